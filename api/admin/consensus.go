@@ -0,0 +1,95 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package admin
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/ava-labs/gecko/api"
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/staking"
+
+	avacon "github.com/ava-labs/gecko/snow/consensus/avalanche"
+)
+
+var (
+	errNoAPIToken     = errors.New("call must carry an API token, generated with --generate-api-token, in an \"Authorization: Bearer <token>\" header")
+	errWrongAPISigner = errors.New("API token isn't signed by this node's staking key")
+)
+
+// Tunable can replace the consensus parameters of the chains it runs
+type Tunable interface {
+	// ConsensusParameters returns the consensus parameters in effect on the
+	// chain [chainID]
+	ConsensusParameters(chainID ids.ID) (avacon.Parameters, error)
+
+	// SetConsensusParameters schedules [params] to replace the consensus
+	// parameters of the chain [chainID] between polls. Returns true if they
+	// were replaced immediately.
+	SetConsensusParameters(chainID ids.ID, params avacon.Parameters) (bool, error)
+}
+
+// Tuning provides helper methods for replacing the consensus parameters of
+// running chains, so that operators can tune liveness without restarting
+type Tuning struct {
+	nodeID ids.ShortID
+	chains Tunable
+}
+
+// Authorize returns an error unless [r] carries an API token, valid at [now],
+// that is signed by this node's staking key. Only the node's operator has the
+// staking key, so the token proves the call is made by the operator.
+func (tn *Tuning) Authorize(r *http.Request, now time.Time) error {
+	token, ok := api.BearerToken(r)
+	if !ok {
+		return errNoAPIToken
+	}
+	signer, _, err := staking.VerifyAPIToken(token, now)
+	if err != nil {
+		return fmt.Errorf("couldn't verify API token: %w", err)
+	}
+	if !signer.Equals(tn.nodeID) {
+		return errWrongAPISigner
+	}
+	return nil
+}
+
+// Set replaces the consensus parameters of the chain [chainID] that are
+// non-zero in [update]. The other parameters keep their current value. Returns
+// the parameters the chain will use and whether they were replaced
+// immediately, rather than once the chain's outstanding polls finish.
+func (tn *Tuning) Set(chainID ids.ID, update avacon.Parameters) (avacon.Parameters, bool, error) {
+	params, err := tn.chains.ConsensusParameters(chainID)
+	if err != nil {
+		return avacon.Parameters{}, false, err
+	}
+
+	if update.K != 0 {
+		params.K = update.K
+	}
+	if update.Alpha != 0 {
+		params.Alpha = update.Alpha
+	}
+	if update.BetaVirtuous != 0 {
+		params.BetaVirtuous = update.BetaVirtuous
+	}
+	if update.BetaRogue != 0 {
+		params.BetaRogue = update.BetaRogue
+	}
+	if update.Parents != 0 {
+		params.Parents = update.Parents
+	}
+	if update.BatchSize != 0 {
+		params.BatchSize = update.BatchSize
+	}
+
+	applied, err := tn.chains.SetConsensusParameters(chainID, params)
+	if err != nil {
+		return avacon.Parameters{}, false, err
+	}
+	return params, applied, nil
+}
@@ -0,0 +1,121 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package admin
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow/consensus/snowball"
+	"github.com/ava-labs/gecko/staking"
+
+	avacon "github.com/ava-labs/gecko/snow/consensus/avalanche"
+)
+
+const testSeedPhrase = "abandon ability able about above absent absorb abstract absurd abuse access accident"
+
+type testTunable struct {
+	params avacon.Parameters
+}
+
+func (tt *testTunable) ConsensusParameters(ids.ID) (avacon.Parameters, error) { return tt.params, nil }
+
+func (tt *testTunable) SetConsensusParameters(_ ids.ID, params avacon.Parameters) (bool, error) {
+	if err := params.Valid(); err != nil {
+		return false, err
+	}
+	tt.params = params
+	return true, nil
+}
+
+func TestTuningSetKeepsOmittedParameters(t *testing.T) {
+	chains := &testTunable{params: avacon.Parameters{
+		Parameters: snowball.Parameters{
+			K: 20, Alpha: 16, BetaVirtuous: 20, BetaRogue: 30,
+		},
+		Parents:   5,
+		BatchSize: 30,
+	}}
+	tn := Tuning{chains: chains}
+
+	update := avacon.Parameters{}
+	update.BetaVirtuous = 15
+	update.BetaRogue = 25
+	params, applied, err := tn.Set(ids.Empty, update)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !applied {
+		t.Fatalf("should have reported the parameters as applied")
+	}
+
+	expected := avacon.Parameters{
+		Parameters: snowball.Parameters{
+			K: 20, Alpha: 16, BetaVirtuous: 15, BetaRogue: 25,
+		},
+		Parents:   5,
+		BatchSize: 30,
+	}
+	if params != expected || chains.params != expected {
+		t.Fatalf("expected parameters %+v but got %+v", expected, params)
+	}
+}
+
+func TestTuningSetInvalid(t *testing.T) {
+	original := avacon.Parameters{
+		Parameters: snowball.Parameters{
+			K: 20, Alpha: 16, BetaVirtuous: 20, BetaRogue: 30,
+		},
+		Parents:   5,
+		BatchSize: 30,
+	}
+	chains := &testTunable{params: original}
+	tn := Tuning{chains: chains}
+
+	update := avacon.Parameters{}
+	update.Alpha = 21
+	if _, _, err := tn.Set(ids.Empty, update); err == nil {
+		t.Fatalf("should have errored due to alpha exceeding k")
+	}
+	if chains.params != original {
+		t.Fatalf("shouldn't have replaced the parameters with invalid parameters")
+	}
+}
+
+func TestTuningAuthorize(t *testing.T) {
+	cert, key, err := staking.NewCertAndKeyFromSeedPhrase(testSeedPhrase)
+	if err != nil {
+		t.Fatal(err)
+	}
+	nodeID, err := staking.NodeID(cert)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Unix(1000000, 0)
+	token, err := staking.NewAPIToken(cert, key, now.Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := httptest.NewRequest("POST", "/ext/admin", nil)
+	tn := Tuning{nodeID: nodeID}
+	if err := tn.Authorize(r, now); err == nil {
+		t.Fatalf("should have rejected a call without an API token")
+	}
+
+	r.Header.Set("Authorization", "Bearer "+token)
+	if err := tn.Authorize(r, now); err != nil {
+		t.Fatal(err)
+	}
+	if err := tn.Authorize(r, now.Add(2*time.Hour)); err == nil {
+		t.Fatalf("should have rejected an expired API token")
+	}
+
+	tn.nodeID = ids.NewShortID([20]byte{1})
+	if err := tn.Authorize(r, now); err != errWrongAPISigner {
+		t.Fatalf("should have rejected an API token signed by another node but got %v", err)
+	}
+}
@@ -0,0 +1,268 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package admin
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"flag"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"runtime/pprof"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ava-labs/gecko/ids"
+)
+
+const (
+	// maxDiagnosticLogSize is the number of bytes included from the end of each
+	// log file
+	maxDiagnosticLogSize = 1 << 20
+
+	// redacted replaces the values of config flags that may hold secrets
+	redacted = "<redacted>"
+)
+
+var (
+	errNoDiagnosticsFile = errors.New("no filename provided for the diagnostics bundle")
+
+	// secretFlagWords mark config flags whose values mustn't leave the node
+	secretFlagWords = []string{"password", "secret", "token", "private"}
+)
+
+// Diagnostics provides helper methods for collecting the state of this node
+// into a single bundle that can be attached to bug reports
+type Diagnostics struct {
+	nodeID    ids.ShortID
+	networkID uint32
+	logDir    string
+	flags     *flag.FlagSet
+	metrics   http.Handler
+	peers     Peerable
+}
+
+// Collect writes a gzipped tarball to [filename] holding:
+//   - version.json: the build and runtime versions of this node
+//   - config.json: the config flags, with secrets redacted
+//   - logs/: the end of each log file
+//   - health.json: the peers this node is connected to
+//   - metrics.txt: a snapshot of the prometheus metrics
+//   - goroutines.txt: the stack of every goroutine, including the consensus
+//     engines
+func (d *Diagnostics) Collect(filename string) error {
+	if filename == "" {
+		return errNoDiagnosticsFile
+	}
+
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	gz := gzip.NewWriter(file)
+	bundle := diagnosticsBundle{
+		writer: tar.NewWriter(gz),
+		now:    time.Now(),
+	}
+
+	bundle.addJSON("version.json", d.version())
+	bundle.addJSON("config.json", d.config())
+	bundle.addLogs(d.logDir)
+	bundle.addJSON("health.json", d.health())
+	bundle.addMetrics("metrics.txt", d.metrics)
+	bundle.addGoroutines("goroutines.txt")
+
+	if bundle.err == nil {
+		bundle.err = bundle.writer.Close()
+	}
+	if bundle.err == nil {
+		bundle.err = gz.Close()
+	}
+	if err := file.Close(); bundle.err == nil {
+		bundle.err = err
+	}
+	return bundle.err
+}
+
+type diagnosticsVersion struct {
+	NodeID    ids.ShortID       `json:"nodeID"`
+	NetworkID uint32            `json:"networkID"`
+	GoVersion string            `json:"goVersion"`
+	OS        string            `json:"os"`
+	Arch      string            `json:"arch"`
+	Path      string            `json:"path,omitempty"`
+	Modules   map[string]string `json:"modules,omitempty"`
+}
+
+func (d *Diagnostics) version() diagnosticsVersion {
+	version := diagnosticsVersion{
+		NodeID:    d.nodeID,
+		NetworkID: d.networkID,
+		GoVersion: runtime.Version(),
+		OS:        runtime.GOOS,
+		Arch:      runtime.GOARCH,
+	}
+	if info, ok := debug.ReadBuildInfo(); ok {
+		version.Path = info.Path
+		version.Modules = make(map[string]string, len(info.Deps)+1)
+		version.Modules[info.Main.Path] = info.Main.Version
+		for _, dep := range info.Deps {
+			version.Modules[dep.Path] = dep.Version
+		}
+	}
+	return version
+}
+
+func (d *Diagnostics) config() map[string]string {
+	config := make(map[string]string)
+	if d.flags == nil {
+		return config
+	}
+	d.flags.VisitAll(func(f *flag.Flag) {
+		config[f.Name] = f.Value.String()
+		name := strings.ToLower(f.Name)
+		for _, word := range secretFlagWords {
+			if strings.Contains(name, word) {
+				config[f.Name] = redacted
+				break
+			}
+		}
+	})
+	return config
+}
+
+type diagnosticsHealth struct {
+	Peers []string `json:"peers"`
+}
+
+func (d *Diagnostics) health() diagnosticsHealth {
+	health := diagnosticsHealth{Peers: []string{}}
+	if d.peers == nil {
+		return health
+	}
+	for _, peer := range d.peers.Peers() {
+		health.Peers = append(health.Peers, peer.String())
+	}
+	sort.Strings(health.Peers)
+	return health
+}
+
+// diagnosticsBundle writes files into a tarball, keeping the first error that
+// occurs so that the bundle can be written without checking every step
+type diagnosticsBundle struct {
+	writer *tar.Writer
+	now    time.Time
+	err    error
+}
+
+func (b *diagnosticsBundle) add(name string, content []byte) {
+	if b.err != nil {
+		return
+	}
+	b.err = b.writer.WriteHeader(&tar.Header{
+		Name:    name,
+		Mode:    0644,
+		Size:    int64(len(content)),
+		ModTime: b.now,
+	})
+	if b.err == nil {
+		_, b.err = b.writer.Write(content)
+	}
+}
+
+func (b *diagnosticsBundle) addJSON(name string, value interface{}) {
+	content, err := json.MarshalIndent(value, "", "\t")
+	if err != nil {
+		b.add(name, []byte(err.Error()))
+		return
+	}
+	b.add(name, content)
+}
+
+// addLogs adds the end of every log file under [dir]. Log files that can't be
+// read are noted in the bundle rather than failing the collection.
+func (b *diagnosticsBundle) addLogs(dir string) {
+	if dir == "" {
+		return
+	}
+	_ = filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() || filepath.Ext(path) != ".log" {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return nil
+		}
+		name := filepath.ToSlash(filepath.Join("logs", rel))
+		content, err := readTail(path, maxDiagnosticLogSize)
+		if err != nil {
+			content = []byte(err.Error())
+		}
+		b.add(name, content)
+		return b.err
+	})
+}
+
+func (b *diagnosticsBundle) addMetrics(name string, handler http.Handler) {
+	if handler == nil {
+		return
+	}
+	request, err := http.NewRequest(http.MethodGet, "/", nil)
+	if err != nil {
+		b.add(name, []byte(err.Error()))
+		return
+	}
+	writer := &bufferedResponseWriter{header: make(http.Header)}
+	handler.ServeHTTP(writer, request)
+	b.add(name, writer.body.Bytes())
+}
+
+func (b *diagnosticsBundle) addGoroutines(name string) {
+	buf := &bytes.Buffer{}
+	if err := pprof.Lookup("goroutine").WriteTo(buf, 2); err != nil {
+		b.add(name, []byte(err.Error()))
+		return
+	}
+	b.add(name, buf.Bytes())
+}
+
+// readTail returns at most the last [size] bytes of the file at [path]
+func readTail(path string, size int64) ([]byte, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if offset := info.Size() - size; offset > 0 {
+		if _, err := file.Seek(offset, io.SeekStart); err != nil {
+			return nil, err
+		}
+	}
+	buf := &bytes.Buffer{}
+	_, err = io.Copy(buf, io.LimitReader(file, size))
+	return buf.Bytes(), err
+}
+
+// bufferedResponseWriter records the body of an HTTP response
+type bufferedResponseWriter struct {
+	header http.Header
+	body   bytes.Buffer
+}
+
+func (w *bufferedResponseWriter) Header() http.Header         { return w.header }
+func (w *bufferedResponseWriter) Write(b []byte) (int, error) { return w.body.Write(b) }
+func (w *bufferedResponseWriter) WriteHeader(int)             {}
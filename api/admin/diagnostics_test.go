@@ -0,0 +1,172 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package admin
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"flag"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ava-labs/gecko/utils"
+)
+
+type testPeerable struct{ peers []utils.IPDesc }
+
+func (p *testPeerable) Peers() []utils.IPDesc { return p.peers }
+
+// readBundle returns the files in the gzipped tarball at [filename]
+func readBundle(t *testing.T, filename string) map[string][]byte {
+	file, err := os.Open(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reader := tar.NewReader(gz)
+
+	files := make(map[string][]byte)
+	for {
+		header, err := reader.Next()
+		if err != nil {
+			break
+		}
+		content, err := ioutil.ReadAll(reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		files[header.Name] = content
+	}
+	return files
+}
+
+func TestDiagnosticsCollect(t *testing.T) {
+	dir, err := ioutil.TempDir("", "diagnostics")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	logDir := filepath.Join(dir, "logs")
+	chainLogDir := filepath.Join(logDir, "chain")
+	if err := os.MkdirAll(chainLogDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(logDir, "0.log"), []byte("node log"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(chainLogDir, "0.log"), []byte("chain log"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(logDir, "notes.txt"), []byte("not a log"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	flags := flag.NewFlagSet("test", flag.ContinueOnError)
+	flags.String("http-port", "9650", "")
+	flags.String("keystore-password", "hunter2", "")
+
+	d := Diagnostics{
+		networkID: 12345,
+		logDir:    logDir,
+		flags:     flags,
+		metrics: http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.Write([]byte("metric 1\n"))
+		}),
+		peers: &testPeerable{peers: []utils.IPDesc{{
+			IP:   []byte{127, 0, 0, 1},
+			Port: 9651,
+		}}},
+	}
+
+	filename := filepath.Join(dir, "bundle.tar.gz")
+	if err := d.Collect(filename); err != nil {
+		t.Fatal(err)
+	}
+	files := readBundle(t, filename)
+
+	for _, name := range []string{"version.json", "config.json", "health.json", "metrics.txt", "goroutines.txt"} {
+		if _, ok := files[name]; !ok {
+			t.Fatalf("bundle is missing %s", name)
+		}
+	}
+
+	if log := string(files["logs/0.log"]); log != "node log" {
+		t.Fatalf("wrong node log: %q", log)
+	}
+	if log := string(files["logs/chain/0.log"]); log != "chain log" {
+		t.Fatalf("wrong chain log: %q", log)
+	}
+	if _, ok := files["logs/notes.txt"]; ok {
+		t.Fatalf("shouldn't have included a file that isn't a log")
+	}
+
+	config := map[string]string{}
+	if err := json.Unmarshal(files["config.json"], &config); err != nil {
+		t.Fatal(err)
+	}
+	if port := config["http-port"]; port != "9650" {
+		t.Fatalf("wrong http port: %s", port)
+	}
+	if password := config["keystore-password"]; password != redacted {
+		t.Fatalf("password should have been redacted, got %s", password)
+	}
+
+	health := diagnosticsHealth{}
+	if err := json.Unmarshal(files["health.json"], &health); err != nil {
+		t.Fatal(err)
+	}
+	if len(health.Peers) != 1 || health.Peers[0] != "127.0.0.1:9651" {
+		t.Fatalf("wrong peers: %v", health.Peers)
+	}
+
+	if metrics := string(files["metrics.txt"]); metrics != "metric 1\n" {
+		t.Fatalf("wrong metrics: %q", metrics)
+	}
+}
+
+func TestDiagnosticsNoFilename(t *testing.T) {
+	d := Diagnostics{}
+	if err := d.Collect(""); err == nil {
+		t.Fatalf("should have failed without a filename")
+	}
+}
+
+func TestReadTail(t *testing.T) {
+	file, err := ioutil.TempFile("", "tail")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(file.Name())
+
+	if _, err := file.Write([]byte("0123456789")); err != nil {
+		t.Fatal(err)
+	}
+	file.Close()
+
+	tail, err := readTail(file.Name(), 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(tail) != "6789" {
+		t.Fatalf("wrong tail: %q", tail)
+	}
+
+	tail, err = readTail(file.Name(), 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(tail) != "0123456789" {
+		t.Fatalf("wrong tail: %q", tail)
+	}
+}
@@ -0,0 +1,50 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package admin
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	// maxMaintenanceDuration is the longest the node may announce that it will
+	// be unavailable for
+	maxMaintenanceDuration = 30 * time.Minute
+)
+
+var (
+	errNonPositiveDuration = errors.New("maintenance duration must be positive")
+	errDurationTooLong     = fmt.Errorf("maintenance duration must be at most %s", maxMaintenanceDuration)
+)
+
+// Maintainable can announce to its peers that it will be briefly unavailable
+type Maintainable interface {
+	// EnterMaintenance announces that this node won't answer queries for
+	// [duration], and stops answering them
+	EnterMaintenance(duration time.Duration) error
+}
+
+// Maintenance provides helper methods for putting the node into maintenance
+// mode before a planned restart
+type Maintenance struct{ network Maintainable }
+
+// Enter maintenance mode for [durationStr], which is parsed as a
+// time.Duration. Returns the time maintenance mode ends.
+func (m *Maintenance) Enter(durationStr string, now time.Time) (time.Time, error) {
+	duration, err := time.ParseDuration(durationStr)
+	switch {
+	case err != nil:
+		return time.Time{}, fmt.Errorf("couldn't parse duration %q: %w", durationStr, err)
+	case duration <= 0:
+		return time.Time{}, errNonPositiveDuration
+	case duration > maxMaintenanceDuration:
+		return time.Time{}, errDurationTooLong
+	}
+	if err := m.network.EnterMaintenance(duration); err != nil {
+		return time.Time{}, err
+	}
+	return now.Add(duration), nil
+}
@@ -0,0 +1,53 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package admin
+
+import (
+	"testing"
+	"time"
+)
+
+type testMaintainable struct {
+	durations []time.Duration
+}
+
+func (n *testMaintainable) EnterMaintenance(duration time.Duration) error {
+	n.durations = append(n.durations, duration)
+	return nil
+}
+
+func TestMaintenanceEnter(t *testing.T) {
+	network := &testMaintainable{}
+	m := Maintenance{network: network}
+
+	now := time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
+	end, err := m.Enter("5m", now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := now.Add(5 * time.Minute); !end.Equal(expected) {
+		t.Fatalf("maintenance should end at %s but ends at %s", expected, end)
+	}
+	if len(network.durations) != 1 || network.durations[0] != 5*time.Minute {
+		t.Fatalf("should have announced 5 minutes of maintenance but announced %v", network.durations)
+	}
+}
+
+func TestMaintenanceEnterInvalidDuration(t *testing.T) {
+	network := &testMaintainable{}
+	m := Maintenance{network: network}
+
+	if _, err := m.Enter("soon", time.Now()); err == nil {
+		t.Fatalf("shouldn't have been able to parse the duration")
+	}
+	if _, err := m.Enter("-1m", time.Now()); err != errNonPositiveDuration {
+		t.Fatalf("expected %s but got %v", errNonPositiveDuration, err)
+	}
+	if _, err := m.Enter("1h", time.Now()); err != errDurationTooLong {
+		t.Fatalf("expected %s but got %v", errDurationTooLong, err)
+	}
+	if len(network.durations) != 0 {
+		t.Fatalf("shouldn't have announced maintenance")
+	}
+}
@@ -4,7 +4,9 @@
 package admin
 
 import (
+	"flag"
 	"net/http"
+	"time"
 
 	"github.com/gorilla/rpc/v2"
 
@@ -14,6 +16,7 @@ import (
 	"github.com/ava-labs/gecko/snow/engine/common"
 	"github.com/ava-labs/gecko/utils/logging"
 
+	avacon "github.com/ava-labs/gecko/snow/consensus/avalanche"
 	cjson "github.com/ava-labs/gecko/utils/json"
 )
 
@@ -24,12 +27,19 @@ type Admin struct {
 	log          logging.Logger
 	networking   Networking
 	performance  Performance
+	shutdown     Shutdown
+	maintenance  Maintenance
+	diagnostics  Diagnostics
+	tuning       Tuning
 	chainManager chains.Manager
 	httpServer   *api.Server
 }
 
-// NewService returns a new admin API service
-func NewService(nodeID ids.ShortID, networkID uint32, log logging.Logger, chainManager chains.Manager, peers Peerable, httpServer *api.Server) *common.HTTPHandler {
+// NewService returns a new admin API service. [logDir] is the directory the
+// node's logs are written to and [metrics] serves the node's metrics, both of
+// which are included in diagnostic bundles. [network] announces maintenance
+// mode to the node's peers.
+func NewService(nodeID ids.ShortID, networkID uint32, log logging.Logger, chainManager chains.Manager, peers Peerable, node Stoppable, network Maintainable, httpServer *api.Server, logDir string, metrics http.Handler) *common.HTTPHandler {
 	newServer := rpc.NewServer()
 	codec := cjson.NewCodec()
 	newServer.RegisterCodec(codec, "application/json")
@@ -42,6 +52,24 @@ func NewService(nodeID ids.ShortID, networkID uint32, log logging.Logger, chainM
 		networking: Networking{
 			peers: peers,
 		},
+		shutdown: Shutdown{
+			node: node,
+		},
+		maintenance: Maintenance{
+			network: network,
+		},
+		diagnostics: Diagnostics{
+			nodeID:    nodeID,
+			networkID: networkID,
+			logDir:    logDir,
+			flags:     flag.CommandLine,
+			metrics:   metrics,
+			peers:     peers,
+		},
+		tuning: Tuning{
+			nodeID: nodeID,
+			chains: chainManager,
+		},
 		httpServer: httpServer,
 	}, "admin")
 	return &common.HTTPHandler{Handler: newServer}
@@ -86,16 +114,42 @@ type GetBlockchainIDArgs struct {
 
 // GetBlockchainIDReply are the results from calling GetBlockchainID
 type GetBlockchainIDReply struct {
-	BlockchainID string `json:"blockchainID"`
+	BlockchainID string   `json:"blockchainID"`
+	Aliases      []string `json:"aliases"`
 }
 
-// GetBlockchainID returns the blockchain ID that resolves the alias that was supplied
+// GetBlockchainID returns the blockchain ID that resolves the alias that was
+// supplied, and every alias of that blockchain
 func (service *Admin) GetBlockchainID(r *http.Request, args *GetBlockchainIDArgs, reply *GetBlockchainIDReply) error {
 	service.log.Debug("Admin: GetBlockchainID called")
 
 	bID, err := service.chainManager.Lookup(args.Alias)
 	reply.BlockchainID = bID.String()
-	return err
+	if err != nil {
+		return err
+	}
+	reply.Aliases = service.chainManager.Aliases(bID)
+	return nil
+}
+
+// ListChainAliasesArgs are the arguments for calling ListChainAliases
+type ListChainAliasesArgs struct{}
+
+// ListChainAliasesReply are the results from calling ListChainAliases
+type ListChainAliasesReply struct {
+	// Aliases maps each alias to the ID of the blockchain it refers to
+	Aliases map[string]string `json:"aliases"`
+}
+
+// ListChainAliases returns every blockchain alias known to this node
+func (service *Admin) ListChainAliases(_ *http.Request, args *ListChainAliasesArgs, reply *ListChainAliasesReply) error {
+	service.log.Debug("Admin: ListChainAliases called")
+
+	reply.Aliases = make(map[string]string)
+	for alias, chainID := range service.chainManager.ListAll() {
+		reply.Aliases[alias] = chainID.String()
+	}
+	return nil
 }
 
 // PeersArgs are the arguments for calling Peers
@@ -226,3 +280,219 @@ func (service *Admin) AliasChain(_ *http.Request, args *AliasChainArgs, reply *A
 	reply.Success = true
 	return service.httpServer.AddAliasesWithReadLock("bc/"+chainID.String(), "bc/"+args.Alias)
 }
+
+// StopNodeArgs are the arguments for calling StopNode
+type StopNodeArgs struct {
+	Confirmation string `json:"confirmation"`
+}
+
+// StopNodeReply are the results from calling StopNode
+type StopNodeReply struct {
+	Confirmation string `json:"confirmation,omitempty"`
+	Success      bool   `json:"success"`
+}
+
+// StopNode gracefully shuts down the node.
+// If no confirmation is provided, a confirmation token is returned and the node
+// keeps running. Calling StopNode again with that token stops the node.
+func (service *Admin) StopNode(_ *http.Request, args *StopNodeArgs, reply *StopNodeReply) error {
+	service.log.Debug("Admin: StopNode called")
+
+	confirmation, err := service.shutdown.Stop(args.Confirmation)
+	if err != nil {
+		return err
+	}
+
+	reply.Confirmation = confirmation
+	reply.Success = confirmation == ""
+	return nil
+}
+
+// RestartNodeArgs are the arguments for calling RestartNode
+type RestartNodeArgs struct {
+	Confirmation string `json:"confirmation"`
+}
+
+// RestartNodeReply are the results from calling RestartNode
+type RestartNodeReply struct {
+	Confirmation string `json:"confirmation,omitempty"`
+	Success      bool   `json:"success"`
+}
+
+// RestartNode gracefully shuts down the node and then starts it again.
+// If no confirmation is provided, a confirmation token is returned and the node
+// keeps running. Calling RestartNode again with that token restarts the node.
+func (service *Admin) RestartNode(_ *http.Request, args *RestartNodeArgs, reply *RestartNodeReply) error {
+	service.log.Debug("Admin: RestartNode called")
+
+	confirmation, err := service.shutdown.Restart(args.Confirmation)
+	if err != nil {
+		return err
+	}
+
+	reply.Confirmation = confirmation
+	reply.Success = confirmation == ""
+	return nil
+}
+
+// EnterMaintenanceArgs are the arguments for calling EnterMaintenance
+type EnterMaintenanceArgs struct {
+	// Duration is how long the node will be unavailable for, such as "5m"
+	Duration string `json:"duration"`
+}
+
+// EnterMaintenanceReply are the results from calling EnterMaintenance
+type EnterMaintenanceReply struct {
+	End     time.Time `json:"end"`
+	Success bool      `json:"success"`
+}
+
+// EnterMaintenance announces to this node's peers that it will be unavailable
+// for the provided duration, so that they stop querying it, and stops
+// answering queries. This should be called before a planned restart.
+func (service *Admin) EnterMaintenance(_ *http.Request, args *EnterMaintenanceArgs, reply *EnterMaintenanceReply) error {
+	service.log.Debug("Admin: EnterMaintenance called with %s", args.Duration)
+
+	end, err := service.maintenance.Enter(args.Duration, time.Now())
+	if err != nil {
+		return err
+	}
+
+	reply.End = end
+	reply.Success = true
+	return nil
+}
+
+// CollectDiagnosticsArgs are the arguments for calling CollectDiagnostics
+type CollectDiagnosticsArgs struct {
+	Filename string `json:"filename"`
+}
+
+// CollectDiagnosticsReply are the results from calling CollectDiagnostics
+type CollectDiagnosticsReply struct {
+	Filename string `json:"filename"`
+	Success  bool   `json:"success"`
+}
+
+// CollectDiagnostics writes a gzipped tarball to the specified file holding
+// this node's version, config, recent logs, peers, metrics, and goroutine
+// stacks, so that a single file can be attached to bug reports
+func (service *Admin) CollectDiagnostics(_ *http.Request, args *CollectDiagnosticsArgs, reply *CollectDiagnosticsReply) error {
+	service.log.Debug("Admin: CollectDiagnostics called with %s", args.Filename)
+
+	if err := service.diagnostics.Collect(args.Filename); err != nil {
+		return err
+	}
+
+	reply.Filename = args.Filename
+	reply.Success = true
+	return nil
+}
+
+// ConsensusParameters are the consensus parameters of a chain. The parents and
+// batch size of Snowman chains are 0.
+type ConsensusParameters struct {
+	K            cjson.Uint32 `json:"k"`
+	Alpha        cjson.Uint32 `json:"alpha"`
+	BetaVirtuous cjson.Uint32 `json:"betaVirtuous"`
+	BetaRogue    cjson.Uint32 `json:"betaRogue"`
+	Parents      cjson.Uint32 `json:"parents"`
+	BatchSize    cjson.Uint32 `json:"batchSize"`
+}
+
+func (p *ConsensusParameters) from(params avacon.Parameters) {
+	p.K = cjson.Uint32(params.K)
+	p.Alpha = cjson.Uint32(params.Alpha)
+	p.BetaVirtuous = cjson.Uint32(params.BetaVirtuous)
+	p.BetaRogue = cjson.Uint32(params.BetaRogue)
+	p.Parents = cjson.Uint32(params.Parents)
+	p.BatchSize = cjson.Uint32(params.BatchSize)
+}
+
+func (p *ConsensusParameters) to() avacon.Parameters {
+	params := avacon.Parameters{
+		Parents:   int(p.Parents),
+		BatchSize: int(p.BatchSize),
+	}
+	params.K = int(p.K)
+	params.Alpha = int(p.Alpha)
+	params.BetaVirtuous = int(p.BetaVirtuous)
+	params.BetaRogue = int(p.BetaRogue)
+	return params
+}
+
+// GetConsensusParametersArgs are the arguments for calling
+// GetConsensusParameters
+type GetConsensusParametersArgs struct {
+	Chain string `json:"chain"`
+}
+
+// GetConsensusParametersReply are the results from calling
+// GetConsensusParameters
+type GetConsensusParametersReply struct {
+	ConsensusParameters
+}
+
+// GetConsensusParameters returns the consensus parameters in effect on a chain
+func (service *Admin) GetConsensusParameters(_ *http.Request, args *GetConsensusParametersArgs, reply *GetConsensusParametersReply) error {
+	service.log.Debug("Admin: GetConsensusParameters called with Chain: %s", args.Chain)
+
+	chainID, err := service.chainManager.Lookup(args.Chain)
+	if err != nil {
+		return err
+	}
+	params, err := service.chainManager.ConsensusParameters(chainID)
+	if err != nil {
+		return err
+	}
+
+	reply.from(params)
+	return nil
+}
+
+// SetConsensusParametersArgs are the arguments for calling
+// SetConsensusParameters. Parameters that are omitted or 0 keep their current
+// value.
+type SetConsensusParametersArgs struct {
+	Chain string `json:"chain"`
+	ConsensusParameters
+}
+
+// SetConsensusParametersReply are the results from calling
+// SetConsensusParameters
+type SetConsensusParametersReply struct {
+	ConsensusParameters
+	// Pending is true if the parameters will be replaced once the chain's next
+	// poll finishes, rather than having been replaced already
+	Pending bool `json:"pending"`
+	Success bool `json:"success"`
+}
+
+// SetConsensusParameters replaces the consensus parameters of a running chain,
+// so that its liveness can be tuned without restarting the node. The
+// parameters are validated, and replaced between polls. The call must carry an
+// API token signed by this node's staking key.
+func (service *Admin) SetConsensusParameters(r *http.Request, args *SetConsensusParametersArgs, reply *SetConsensusParametersReply) error {
+	service.log.Debug("Admin: SetConsensusParameters called with Chain: %s", args.Chain)
+
+	if err := service.tuning.Authorize(r, time.Now()); err != nil {
+		return err
+	}
+
+	chainID, err := service.chainManager.Lookup(args.Chain)
+	if err != nil {
+		return err
+	}
+	params, applied, err := service.tuning.Set(chainID, args.to())
+	if err != nil {
+		return err
+	}
+
+	service.log.Info("Admin: consensus parameters of chain %s set to K = %d, Alpha = %d, BetaVirtuous = %d, BetaRogue = %d, Parents = %d, BatchSize = %d",
+		chainID, params.K, params.Alpha, params.BetaVirtuous, params.BetaRogue, params.Parents, params.BatchSize)
+
+	reply.from(params)
+	reply.Pending = !applied
+	reply.Success = true
+	return nil
+}
@@ -0,0 +1,104 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package admin
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/ava-labs/gecko/utils/formatting"
+	"github.com/ava-labs/gecko/utils/timer"
+)
+
+const (
+	// confirmationTimeout is how long a confirmation token may be used for
+	// after it was issued
+	confirmationTimeout = time.Minute
+
+	// confirmationLen is the number of random bytes in a confirmation token
+	confirmationLen = 16
+)
+
+var (
+	errInvalidConfirmation = errors.New("invalid or expired confirmation token")
+)
+
+// Stoppable can be gracefully stopped or restarted
+type Stoppable interface {
+	// Stop the node
+	Stop() error
+	// Restart the node once it has stopped
+	Restart() error
+}
+
+type shutdownAction byte
+
+const (
+	stopAction shutdownAction = iota
+	restartAction
+)
+
+// Shutdown provides helper methods for stopping or restarting the node.
+//
+// Stopping or restarting the node takes two calls. The first call returns a
+// confirmation token. The second call must provide that token before it
+// expires, at which point the node is shut down. A token may only be used once
+// and only for the action it was issued for.
+type Shutdown struct {
+	node  Stoppable
+	clock timer.Clock
+
+	lock         sync.Mutex
+	action       shutdownAction
+	confirmation []byte
+	expiry       time.Time
+}
+
+// Stop the node if [confirmation] is valid. If [confirmation] is empty, a new
+// confirmation token is returned and the node isn't stopped.
+func (s *Shutdown) Stop(confirmation string) (string, error) {
+	return s.confirm(stopAction, confirmation, s.node.Stop)
+}
+
+// Restart the node if [confirmation] is valid. If [confirmation] is empty, a
+// new confirmation token is returned and the node isn't restarted.
+func (s *Shutdown) Restart(confirmation string) (string, error) {
+	return s.confirm(restartAction, confirmation, s.node.Restart)
+}
+
+func (s *Shutdown) confirm(action shutdownAction, confirmation string, f func() error) (string, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if confirmation == "" {
+		token := make([]byte, confirmationLen)
+		if _, err := rand.Read(token); err != nil {
+			return "", err
+		}
+		s.action = action
+		s.confirmation = token
+		s.expiry = s.clock.Time().Add(confirmationTimeout)
+		return formatting.CB58{Bytes: token}.String(), nil
+	}
+
+	cb58 := formatting.CB58{}
+	if err := cb58.FromString(confirmation); err != nil {
+		return "", errInvalidConfirmation
+	}
+
+	valid := s.confirmation != nil &&
+		s.action == action &&
+		s.clock.Time().Before(s.expiry) &&
+		subtle.ConstantTimeCompare(cb58.Bytes, s.confirmation) == 1
+
+	// Tokens can only be used once, even if they were used incorrectly
+	s.confirmation = nil
+	if !valid {
+		return "", errInvalidConfirmation
+	}
+	return "", f()
+}
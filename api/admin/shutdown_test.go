@@ -0,0 +1,110 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package admin
+
+import (
+	"testing"
+	"time"
+)
+
+type testStoppable struct {
+	stopped, restarted int
+}
+
+func (n *testStoppable) Stop() error    { n.stopped++; return nil }
+func (n *testStoppable) Restart() error { n.restarted++; return nil }
+
+func TestShutdownStop(t *testing.T) {
+	node := &testStoppable{}
+	s := Shutdown{node: node}
+
+	confirmation, err := s.Stop("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if confirmation == "" {
+		t.Fatalf("should have returned a confirmation token")
+	}
+	if node.stopped != 0 {
+		t.Fatalf("shouldn't have stopped the node without confirmation")
+	}
+
+	if _, err := s.Stop(confirmation); err != nil {
+		t.Fatal(err)
+	}
+	if node.stopped != 1 {
+		t.Fatalf("should have stopped the node")
+	}
+
+	if _, err := s.Stop(confirmation); err == nil {
+		t.Fatalf("shouldn't have been able to reuse the confirmation token")
+	}
+	if node.stopped != 1 {
+		t.Fatalf("should only have stopped the node once")
+	}
+}
+
+func TestShutdownWrongAction(t *testing.T) {
+	node := &testStoppable{}
+	s := Shutdown{node: node}
+
+	confirmation, err := s.Stop("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Restart(confirmation); err == nil {
+		t.Fatalf("shouldn't have been able to restart with a stop confirmation")
+	}
+	if node.restarted != 0 || node.stopped != 0 {
+		t.Fatalf("shouldn't have shut down the node")
+	}
+}
+
+func TestShutdownInvalidConfirmation(t *testing.T) {
+	node := &testStoppable{}
+	s := Shutdown{node: node}
+
+	if _, err := s.Restart("not a token"); err == nil {
+		t.Fatalf("shouldn't have been able to restart without requesting a confirmation")
+	}
+
+	confirmation, err := s.Restart("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := s.Restart("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Restart(confirmation); err == nil {
+		t.Fatalf("shouldn't have been able to use a replaced confirmation token")
+	}
+	if _, err := s.Restart(other); err == nil {
+		t.Fatalf("shouldn't have been able to use a token after a failed confirmation")
+	}
+	if node.restarted != 0 {
+		t.Fatalf("shouldn't have restarted the node")
+	}
+}
+
+func TestShutdownExpiredConfirmation(t *testing.T) {
+	node := &testStoppable{}
+	s := Shutdown{node: node}
+
+	now := time.Now()
+	s.clock.Set(now)
+
+	confirmation, err := s.Restart("")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s.clock.Set(now.Add(confirmationTimeout))
+	if _, err := s.Restart(confirmation); err == nil {
+		t.Fatalf("shouldn't have been able to use an expired confirmation token")
+	}
+	if node.restarted != 0 {
+		t.Fatalf("shouldn't have restarted the node")
+	}
+}
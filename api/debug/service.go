@@ -0,0 +1,182 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package debug
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/rpc/v2"
+
+	"github.com/ava-labs/gecko/database"
+	"github.com/ava-labs/gecko/database/prefixdb"
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow/engine/common"
+	"github.com/ava-labs/gecko/utils/logging"
+
+	cjson "github.com/ava-labs/gecko/utils/json"
+)
+
+const (
+	// defaultIterateLimit is the number of entries returned by DBIterate if no
+	// limit is provided
+	defaultIterateLimit = 100
+
+	// maxIterateLimit is the maximum number of entries returned by DBIterate
+	maxIterateLimit = 1024
+)
+
+var (
+	errIterateLimitTooLarge = fmt.Errorf("limit must be at most %d", maxIterateLimit)
+	errNoKey                = errors.New("no key provided")
+)
+
+// ChainLookup resolves the aliases of chains
+type ChainLookup interface {
+	Lookup(alias string) (ids.ID, error)
+}
+
+// Debug is the API service for inspecting the node's database. The service
+// never modifies the database.
+type Debug struct {
+	log    logging.Logger
+	db     database.Database
+	chains ChainLookup
+}
+
+// NewService returns a new debug API service that reads from [db], the node's
+// database
+func NewService(log logging.Logger, db database.Database, chains ChainLookup) *common.HTTPHandler {
+	newServer := rpc.NewServer()
+	codec := cjson.NewCodec()
+	newServer.RegisterCodec(codec, "application/json")
+	newServer.RegisterCodec(codec, "application/json;charset=UTF-8")
+	newServer.RegisterService(&Debug{
+		log:    log,
+		db:     db,
+		chains: chains,
+	}, "debug")
+	return &common.HTTPHandler{Handler: newServer}
+}
+
+// DBGetArgs are the arguments for calling DBGet
+type DBGetArgs struct {
+	// Chain whose VM database the key is in. If empty, the key is read from
+	// the node's database without a prefix.
+	Chain string `json:"chain"`
+	// Hex encoded key
+	Key string `json:"key"`
+}
+
+// DBGetReply are the results from calling DBGet
+type DBGetReply struct {
+	// Hex encoded value
+	Value string `json:"value"`
+}
+
+// DBGet returns the value of a key in a chain's VM database
+func (service *Debug) DBGet(_ *http.Request, args *DBGetArgs, reply *DBGetReply) error {
+	service.log.Debug("Debug: DBGet called with Chain: %s, Key: %s", args.Chain, args.Key)
+
+	if args.Key == "" {
+		return errNoKey
+	}
+	key, err := hex.DecodeString(args.Key)
+	if err != nil {
+		return fmt.Errorf("problem decoding key: %w", err)
+	}
+
+	db, err := service.database(args.Chain)
+	if err != nil {
+		return err
+	}
+	value, err := db.Get(key)
+	if err != nil {
+		return err
+	}
+
+	reply.Value = hex.EncodeToString(value)
+	return nil
+}
+
+// DBIterateArgs are the arguments for calling DBIterate
+type DBIterateArgs struct {
+	// Chain whose VM database is iterated over. If empty, the node's database
+	// is iterated over without a prefix.
+	Chain string `json:"chain"`
+	// Hex encoded prefix of the keys to return
+	Prefix string `json:"prefix"`
+	// Maximum number of entries to return. Defaults to 100.
+	Limit cjson.Uint32 `json:"limit"`
+}
+
+// KeyValue is a hex encoded database entry
+type KeyValue struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// DBIterateReply are the results from calling DBIterate
+type DBIterateReply struct {
+	Entries []KeyValue `json:"entries"`
+	// True if there are more entries with the prefix than were returned
+	More bool `json:"more"`
+}
+
+// DBIterate returns the entries of a chain's VM database whose keys start with
+// the provided prefix, in binary-alphabetical order of their keys
+func (service *Debug) DBIterate(_ *http.Request, args *DBIterateArgs, reply *DBIterateReply) error {
+	service.log.Debug("Debug: DBIterate called with Chain: %s, Prefix: %s, Limit: %d", args.Chain, args.Prefix, args.Limit)
+
+	limit := int(args.Limit)
+	switch {
+	case limit == 0:
+		limit = defaultIterateLimit
+	case limit > maxIterateLimit:
+		return errIterateLimitTooLarge
+	}
+
+	prefix, err := hex.DecodeString(args.Prefix)
+	if err != nil {
+		return fmt.Errorf("problem decoding prefix: %w", err)
+	}
+
+	db, err := service.database(args.Chain)
+	if err != nil {
+		return err
+	}
+
+	it := db.NewIteratorWithPrefix(prefix)
+	defer it.Release()
+
+	reply.Entries = []KeyValue{}
+	for it.Next() {
+		if len(reply.Entries) == limit {
+			reply.More = true
+			break
+		}
+		reply.Entries = append(reply.Entries, KeyValue{
+			Key:   hex.EncodeToString(it.Key()),
+			Value: hex.EncodeToString(it.Value()),
+		})
+	}
+	return it.Error()
+}
+
+// database returns the VM database of the chain with alias [chain], or the
+// node's database if [chain] is empty
+func (service *Debug) database(chain string) (database.Database, error) {
+	if chain == "" {
+		return service.db, nil
+	}
+	chainID, err := service.chains.Lookup(chain)
+	if err != nil {
+		return nil, err
+	}
+	// This mirrors the layout of the databases created by the chain manager
+	chainDB := prefixdb.New(chainID.Bytes(), service.db)
+	return prefixdb.New([]byte("vm"), chainDB), nil
+}
@@ -0,0 +1,103 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package debug
+
+import (
+	"testing"
+
+	"github.com/ava-labs/gecko/database/memdb"
+	"github.com/ava-labs/gecko/database/prefixdb"
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/logging"
+)
+
+func setup(t *testing.T) *Debug {
+	chainID := ids.NewID([32]byte{1})
+	aliaser := &ids.Aliaser{}
+	aliaser.Initialize()
+	if err := aliaser.Alias(chainID, "X"); err != nil {
+		t.Fatal(err)
+	}
+
+	db := memdb.New()
+	vmDB := prefixdb.New([]byte("vm"), prefixdb.New(chainID.Bytes(), db))
+	for _, key := range []string{"a1", "a2", "a3", "b1"} {
+		if err := vmDB.Put([]byte(key), []byte("value "+key)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return &Debug{
+		log:    logging.NoLog{},
+		db:     db,
+		chains: aliaser,
+	}
+}
+
+func TestDBGet(t *testing.T) {
+	service := setup(t)
+
+	reply := DBGetReply{}
+	if err := service.DBGet(nil, &DBGetArgs{Chain: "X", Key: "6131"}, &reply); err != nil {
+		t.Fatal(err)
+	}
+	if expected := "76616c7565206131"; reply.Value != expected { // "value a1"
+		t.Fatalf("Expected value %s, got %s", expected, reply.Value)
+	}
+
+	if err := service.DBGet(nil, &DBGetArgs{Chain: "X", Key: "6134"}, &reply); err == nil {
+		t.Fatalf("Should have failed to get a missing key")
+	}
+	if err := service.DBGet(nil, &DBGetArgs{Chain: "Y", Key: "6131"}, &reply); err == nil {
+		t.Fatalf("Should have failed to get a key of an unknown chain")
+	}
+	if err := service.DBGet(nil, &DBGetArgs{Chain: "X", Key: "not hex"}, &reply); err == nil {
+		t.Fatalf("Should have failed to decode the key")
+	}
+}
+
+func TestDBIterate(t *testing.T) {
+	service := setup(t)
+
+	reply := DBIterateReply{}
+	if err := service.DBIterate(nil, &DBIterateArgs{Chain: "X", Prefix: "61", Limit: 2}, &reply); err != nil {
+		t.Fatal(err)
+	}
+	if len(reply.Entries) != 2 {
+		t.Fatalf("Expected %d entries, got %d", 2, len(reply.Entries))
+	}
+	if reply.Entries[0].Key != "6131" || reply.Entries[1].Key != "6132" {
+		t.Fatalf("Wrong entries returned: %v", reply.Entries)
+	}
+	if !reply.More {
+		t.Fatalf("Should have reported that there are more entries")
+	}
+
+	reply = DBIterateReply{}
+	if err := service.DBIterate(nil, &DBIterateArgs{Chain: "X", Prefix: "61"}, &reply); err != nil {
+		t.Fatal(err)
+	}
+	if len(reply.Entries) != 3 {
+		t.Fatalf("Expected %d entries, got %d", 3, len(reply.Entries))
+	}
+	if reply.More {
+		t.Fatalf("Shouldn't have reported that there are more entries")
+	}
+
+	if err := service.DBIterate(nil, &DBIterateArgs{Chain: "X", Limit: maxIterateLimit + 1}, &reply); err == nil {
+		t.Fatalf("Should have failed with a limit that is too large")
+	}
+}
+
+func TestDBIterateNodeDatabase(t *testing.T) {
+	service := setup(t)
+
+	reply := DBIterateReply{}
+	if err := service.DBIterate(nil, &DBIterateArgs{}, &reply); err != nil {
+		t.Fatal(err)
+	}
+	if len(reply.Entries) != 4 {
+		t.Fatalf("Expected %d entries, got %d", 4, len(reply.Entries))
+	}
+}
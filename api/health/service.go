@@ -0,0 +1,85 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package health
+
+import (
+	"net/http"
+	"sort"
+
+	"github.com/gorilla/rpc/v2"
+
+	"github.com/ava-labs/gecko/snow/engine/common"
+	"github.com/ava-labs/gecko/utils/logging"
+
+	cjson "github.com/ava-labs/gecko/utils/json"
+)
+
+// Check returns details about the health of a component of the node, and an
+// error if the component is unhealthy
+type Check func() (interface{}, error)
+
+// Health is the API service for checking whether the node is healthy
+type Health struct {
+	log    logging.Logger
+	checks map[string]Check
+}
+
+// NewService returns a new health API service that runs [checks]
+func NewService(log logging.Logger, checks map[string]Check) *common.HTTPHandler {
+	newServer := rpc.NewServer()
+	codec := cjson.NewCodec()
+	newServer.RegisterCodec(codec, "application/json")
+	newServer.RegisterCodec(codec, "application/json;charset=UTF-8")
+	newServer.RegisterService(&Health{
+		log:    log,
+		checks: checks,
+	}, "health")
+	return &common.HTTPHandler{Handler: newServer}
+}
+
+// CheckResult is the result of running a health check
+type CheckResult struct {
+	Name    string      `json:"name"`
+	Details interface{} `json:"details,omitempty"`
+	Error   string      `json:"error,omitempty"`
+	Healthy bool        `json:"healthy"`
+}
+
+// GetLivenessArgs are the arguments for calling GetLiveness
+type GetLivenessArgs struct{}
+
+// GetLivenessReply are the results from calling GetLiveness
+type GetLivenessReply struct {
+	Checks  []CheckResult `json:"checks"`
+	Healthy bool          `json:"healthy"`
+}
+
+// GetLiveness runs the node's health checks. The node is healthy iff every
+// check passes.
+func (service *Health) GetLiveness(_ *http.Request, args *GetLivenessArgs, reply *GetLivenessReply) error {
+	service.log.Debug("Health: GetLiveness called")
+
+	names := make([]string, 0, len(service.checks))
+	for name := range service.checks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	reply.Healthy = true
+	for _, name := range names {
+		details, err := service.checks[name]()
+		result := CheckResult{
+			Name:    name,
+			Details: details,
+			Healthy: err == nil,
+		}
+		if err != nil {
+			result.Error = err.Error()
+			reply.Healthy = false
+			service.log.Warn("Health check %s failed: %s", name, err)
+		}
+		reply.Checks = append(reply.Checks, result)
+	}
+	return nil
+}
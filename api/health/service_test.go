@@ -0,0 +1,63 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package health
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ava-labs/gecko/utils/logging"
+)
+
+func TestGetLivenessHealthy(t *testing.T) {
+	service := Health{
+		log: logging.NoLog{},
+		checks: map[string]Check{
+			"a": func() (interface{}, error) { return 1, nil },
+		},
+	}
+
+	reply := GetLivenessReply{}
+	if err := service.GetLiveness(nil, &GetLivenessArgs{}, &reply); err != nil {
+		t.Fatal(err)
+	}
+
+	if !reply.Healthy {
+		t.Fatalf("Should have been healthy")
+	}
+	if len(reply.Checks) != 1 {
+		t.Fatalf("Expected 1 check result but got %d", len(reply.Checks))
+	}
+	if result := reply.Checks[0]; result.Name != "a" || !result.Healthy || result.Details != 1 || result.Error != "" {
+		t.Fatalf("Wrong check result: %+v", result)
+	}
+}
+
+func TestGetLivenessUnhealthy(t *testing.T) {
+	service := Health{
+		log: logging.NoLog{},
+		checks: map[string]Check{
+			"b": func() (interface{}, error) { return nil, errors.New("broken") },
+			"a": func() (interface{}, error) { return nil, nil },
+		},
+	}
+
+	reply := GetLivenessReply{}
+	if err := service.GetLiveness(nil, &GetLivenessArgs{}, &reply); err != nil {
+		t.Fatal(err)
+	}
+
+	if reply.Healthy {
+		t.Fatalf("Shouldn't have been healthy")
+	}
+	if len(reply.Checks) != 2 {
+		t.Fatalf("Expected 2 check results but got %d", len(reply.Checks))
+	}
+	if result := reply.Checks[0]; result.Name != "a" || !result.Healthy {
+		t.Fatalf("Wrong check result: %+v", result)
+	}
+	if result := reply.Checks[1]; result.Name != "b" || result.Healthy || result.Error != "broken" {
+		t.Fatalf("Wrong check result: %+v", result)
+	}
+}
@@ -0,0 +1,236 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package info
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gorilla/rpc/v2"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow/engine/common"
+	"github.com/ava-labs/gecko/snow/networking"
+	"github.com/ava-labs/gecko/utils/logging"
+
+	cjson "github.com/ava-labs/gecko/utils/json"
+)
+
+// Fees are the transaction fees, in nAVA, charged on the network this node is
+// running on
+type Fees struct {
+	// TxFee is the fee charged for each AVA transaction
+	TxFee uint64
+
+	// CreateAssetTxFee is the fee charged for creating an asset on the AVM
+	CreateAssetTxFee uint64
+
+	// PlatformTxFee is the fee charged for each platform chain transaction,
+	// including transactions that create subnets and blockchains
+	PlatformTxFee uint64
+}
+
+// NetworkSizeEstimator estimates the number of nodes in the network
+type NetworkSizeEstimator interface {
+	// NetworkSize returns the estimated number of nodes in the network, and
+	// the number of peers the estimate is based on
+	NetworkSize() (uint64, int)
+}
+
+// ReputationTracker scores peers by their misbehavior
+type ReputationTracker interface {
+	// Scores returns the reputation of the peers that misbehaved recently,
+	// worst first
+	Scores() []networking.PeerScore
+}
+
+// ReplayTracker detects replayed messages
+type ReplayTracker interface {
+	// Replays returns the number of replays detected from each peer that sent
+	// any, most first
+	Replays() []networking.PeerReplays
+}
+
+// NodeIDProver proves that this node controls its staking key
+type NodeIDProver interface {
+	// ProveNodeID returns this node's ID and a proof that its staking key
+	// signed [challenge]
+	ProveNodeID(challenge []byte) (ids.ShortID, string, error)
+}
+
+var errStakingDisabled = errors.New("staking is disabled, so this node has no staking key to prove its ID with")
+
+// Info is the API service for unprivileged information about the node and the
+// network it's running on
+type Info struct {
+	log         logging.Logger
+	fees        Fees
+	networkSize NetworkSizeEstimator
+	reputation  ReputationTracker
+	replays     ReplayTracker
+	prover      NodeIDProver
+}
+
+// NewService returns a new info API service. [prover] is nil if staking is
+// disabled.
+func NewService(log logging.Logger, fees Fees, networkSize NetworkSizeEstimator, reputation ReputationTracker, replays ReplayTracker, prover NodeIDProver) *common.HTTPHandler {
+	newServer := rpc.NewServer()
+	codec := cjson.NewCodec()
+	newServer.RegisterCodec(codec, "application/json")
+	newServer.RegisterCodec(codec, "application/json;charset=UTF-8")
+	newServer.RegisterService(&Info{
+		log:         log,
+		fees:        fees,
+		networkSize: networkSize,
+		reputation:  reputation,
+		replays:     replays,
+		prover:      prover,
+	}, "info")
+	return &common.HTTPHandler{Handler: newServer}
+}
+
+// GetTxFeeArgs are the arguments for calling GetTxFee
+type GetTxFeeArgs struct{}
+
+// GetTxFeeReply are the results from calling GetTxFee
+type GetTxFeeReply struct {
+	TxFee                 cjson.Uint64 `json:"txFee"`
+	CreateAssetTxFee      cjson.Uint64 `json:"createAssetTxFee"`
+	PlatformTxFee         cjson.Uint64 `json:"platformTxFee"`
+	CreateSubnetTxFee     cjson.Uint64 `json:"createSubnetTxFee"`
+	CreateBlockchainTxFee cjson.Uint64 `json:"createBlockchainTxFee"`
+}
+
+// GetTxFee returns the fee schedule of the network this node is running on.
+// The platform chain's fees are the genesis fees; governance proposals may
+// change them.
+func (service *Info) GetTxFee(_ *http.Request, args *GetTxFeeArgs, reply *GetTxFeeReply) error {
+	service.log.Debug("Info: GetTxFee called")
+
+	reply.TxFee = cjson.Uint64(service.fees.TxFee)
+	reply.CreateAssetTxFee = cjson.Uint64(service.fees.CreateAssetTxFee)
+	reply.PlatformTxFee = cjson.Uint64(service.fees.PlatformTxFee)
+	reply.CreateSubnetTxFee = cjson.Uint64(service.fees.PlatformTxFee)
+	reply.CreateBlockchainTxFee = cjson.Uint64(service.fees.PlatformTxFee)
+	return nil
+}
+
+// NetworkSizeArgs are the arguments for calling NetworkSize
+type NetworkSizeArgs struct{}
+
+// NetworkSizeReply are the results from calling NetworkSize
+type NetworkSizeReply struct {
+	NetworkSize cjson.Uint64 `json:"networkSize"`
+	NumPeers    cjson.Uint32 `json:"numPeers"`
+}
+
+// NetworkSize returns the estimated number of nodes in the network, as the
+// median of the estimates reported by this node's peers
+func (service *Info) NetworkSize(_ *http.Request, args *NetworkSizeArgs, reply *NetworkSizeReply) error {
+	service.log.Debug("Info: NetworkSize called")
+
+	networkSize, numPeers := service.networkSize.NetworkSize()
+	reply.NetworkSize = cjson.Uint64(networkSize)
+	reply.NumPeers = cjson.Uint32(numPeers)
+	return nil
+}
+
+// PeerScoresArgs are the arguments for calling PeerScores
+type PeerScoresArgs struct{}
+
+// PeerScore is a peer's misbehavior score
+type PeerScore struct {
+	PeerID ids.ShortID `json:"peerID"`
+	Score  float64     `json:"score"`
+	// Unix time the peer's ban ends. 0 if the peer isn't banned.
+	BannedUntil cjson.Uint64 `json:"bannedUntil"`
+}
+
+// PeerScoresReply are the results from calling PeerScores
+type PeerScoresReply struct {
+	Peers []PeerScore `json:"peers"`
+}
+
+// PeerScores returns the misbehavior score of each peer that sent invalid
+// messages or didn't answer requests recently, worst first. Peers whose score
+// reaches the ban threshold are banned for a while.
+func (service *Info) PeerScores(_ *http.Request, args *PeerScoresArgs, reply *PeerScoresReply) error {
+	service.log.Debug("Info: PeerScores called")
+
+	scores := service.reputation.Scores()
+	reply.Peers = make([]PeerScore, len(scores))
+	for i, score := range scores {
+		reply.Peers[i] = PeerScore{
+			PeerID: score.PeerID,
+			Score:  score.Score,
+		}
+		if !score.BannedUntil.IsZero() {
+			reply.Peers[i].BannedUntil = cjson.Uint64(score.BannedUntil.Unix())
+		}
+	}
+	return nil
+}
+
+// PeerReplaysArgs are the arguments for calling PeerReplays
+type PeerReplaysArgs struct{}
+
+// PeerReplays is the number of replayed messages detected from a peer
+type PeerReplays struct {
+	PeerID  ids.ShortID  `json:"peerID"`
+	Replays cjson.Uint64 `json:"replays"`
+}
+
+// PeerReplaysReply are the results from calling PeerReplays
+type PeerReplaysReply struct {
+	Peers []PeerReplays `json:"peers"`
+}
+
+// PeerReplays returns the number of messages each peer sent this node that had
+// already been received, most first. Replayed messages are dropped.
+func (service *Info) PeerReplays(_ *http.Request, args *PeerReplaysArgs, reply *PeerReplaysReply) error {
+	service.log.Debug("Info: PeerReplays called")
+
+	replays := service.replays.Replays()
+	reply.Peers = make([]PeerReplays, len(replays))
+	for i, peer := range replays {
+		reply.Peers[i] = PeerReplays{
+			PeerID:  peer.PeerID,
+			Replays: cjson.Uint64(peer.Replays),
+		}
+	}
+	return nil
+}
+
+// ProveNodeIDArgs are the arguments for calling ProveNodeID
+type ProveNodeIDArgs struct {
+	// Chosen by the caller, such as a random nonce, so that the proof can't
+	// have been made before the call. The challenge's UTF-8 bytes are signed.
+	Challenge string `json:"challenge"`
+}
+
+// ProveNodeIDReply are the results from calling ProveNodeID
+type ProveNodeIDReply struct {
+	NodeID ids.ShortID `json:"nodeID"`
+	// The node's staking certificate and its key's signature of the
+	// challenge, which staking.VerifyNodeIDProof checks
+	Proof string `json:"proof"`
+}
+
+// ProveNodeID signs the caller's challenge with this node's staking key, so
+// that a staking registration tool can check that its user controls the node
+// they're registering as a validator
+func (service *Info) ProveNodeID(_ *http.Request, args *ProveNodeIDArgs, reply *ProveNodeIDReply) error {
+	service.log.Debug("Info: ProveNodeID called")
+
+	if service.prover == nil {
+		return errStakingDisabled
+	}
+	nodeID, proof, err := service.prover.ProveNodeID([]byte(args.Challenge))
+	if err != nil {
+		return err
+	}
+	reply.NodeID = nodeID
+	reply.Proof = proof
+	return nil
+}
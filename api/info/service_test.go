@@ -0,0 +1,167 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package info
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow/networking"
+	"github.com/ava-labs/gecko/staking"
+	"github.com/ava-labs/gecko/utils/logging"
+)
+
+func TestGetTxFee(t *testing.T) {
+	service := Info{
+		log: logging.NoLog{},
+		fees: Fees{
+			TxFee:            1,
+			CreateAssetTxFee: 2,
+			PlatformTxFee:    3,
+		},
+	}
+
+	reply := GetTxFeeReply{}
+	if err := service.GetTxFee(nil, &GetTxFeeArgs{}, &reply); err != nil {
+		t.Fatal(err)
+	}
+
+	if reply.TxFee != 1 {
+		t.Fatalf("expected tx fee to be 1 but was %d", reply.TxFee)
+	}
+	if reply.CreateAssetTxFee != 2 {
+		t.Fatalf("expected create asset tx fee to be 2 but was %d", reply.CreateAssetTxFee)
+	}
+	if reply.PlatformTxFee != 3 {
+		t.Fatalf("expected platform tx fee to be 3 but was %d", reply.PlatformTxFee)
+	}
+	if reply.CreateSubnetTxFee != 3 {
+		t.Fatalf("expected create subnet tx fee to be 3 but was %d", reply.CreateSubnetTxFee)
+	}
+	if reply.CreateBlockchainTxFee != 3 {
+		t.Fatalf("expected create blockchain tx fee to be 3 but was %d", reply.CreateBlockchainTxFee)
+	}
+}
+
+type networkSizeTest struct {
+	size     uint64
+	numPeers int
+}
+
+func (ns networkSizeTest) NetworkSize() (uint64, int) { return ns.size, ns.numPeers }
+
+func TestNetworkSize(t *testing.T) {
+	service := Info{
+		log:         logging.NoLog{},
+		networkSize: networkSizeTest{size: 1000, numPeers: 7},
+	}
+
+	reply := NetworkSizeReply{}
+	if err := service.NetworkSize(nil, &NetworkSizeArgs{}, &reply); err != nil {
+		t.Fatal(err)
+	}
+
+	if reply.NetworkSize != 1000 {
+		t.Fatalf("expected network size to be 1000 but was %d", reply.NetworkSize)
+	}
+	if reply.NumPeers != 7 {
+		t.Fatalf("expected the estimate to be based on 7 peers but was %d", reply.NumPeers)
+	}
+}
+
+type reputationTest []networking.PeerScore
+
+func (rt reputationTest) Scores() []networking.PeerScore { return rt }
+
+func TestPeerScores(t *testing.T) {
+	bannedID := ids.NewShortID([20]byte{1})
+	peerID := ids.NewShortID([20]byte{2})
+	service := Info{
+		log: logging.NoLog{},
+		reputation: reputationTest{
+			{PeerID: bannedID, BannedUntil: time.Unix(1000000, 0)},
+			{PeerID: peerID, Score: 12},
+		},
+	}
+
+	reply := PeerScoresReply{}
+	if err := service.PeerScores(nil, &PeerScoresArgs{}, &reply); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(reply.Peers) != 2 {
+		t.Fatalf("expected 2 peers but got %d", len(reply.Peers))
+	}
+	if banned := reply.Peers[0]; !banned.PeerID.Equals(bannedID) || banned.BannedUntil != 1000000 {
+		t.Fatalf("expected %s to be banned until 1000000 but got %+v", bannedID, banned)
+	}
+	if peer := reply.Peers[1]; !peer.PeerID.Equals(peerID) || peer.Score != 12 || peer.BannedUntil != 0 {
+		t.Fatalf("expected %s to have a score of 12 and not be banned but got %+v", peerID, peer)
+	}
+}
+
+type replaysTest []networking.PeerReplays
+
+func (rt replaysTest) Replays() []networking.PeerReplays { return rt }
+
+func TestPeerReplays(t *testing.T) {
+	peerID := ids.NewShortID([20]byte{1})
+	service := Info{
+		log:     logging.NoLog{},
+		replays: replaysTest{{PeerID: peerID, Replays: 3}},
+	}
+
+	reply := PeerReplaysReply{}
+	if err := service.PeerReplays(nil, &PeerReplaysArgs{}, &reply); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(reply.Peers) != 1 {
+		t.Fatalf("expected 1 peer but got %d", len(reply.Peers))
+	}
+	if peer := reply.Peers[0]; !peer.PeerID.Equals(peerID) || peer.Replays != 3 {
+		t.Fatalf("expected %s to have sent 3 replays but got %+v", peerID, peer)
+	}
+}
+
+func TestProveNodeID(t *testing.T) {
+	cert, key, err := staking.NewCertAndKeyFromSeedPhrase("abandon ability able about above absent absorb abstract absurd abuse access accident")
+	if err != nil {
+		t.Fatal(err)
+	}
+	nodeID, err := staking.NodeID(cert)
+	if err != nil {
+		t.Fatal(err)
+	}
+	service := Info{
+		log:    logging.NoLog{},
+		prover: &staking.NodeIDProver{CertPEM: cert, KeyPEM: key},
+	}
+
+	challenge := "registration nonce"
+	reply := ProveNodeIDReply{}
+	if err := service.ProveNodeID(nil, &ProveNodeIDArgs{Challenge: challenge}, &reply); err != nil {
+		t.Fatal(err)
+	}
+
+	if !reply.NodeID.Equals(nodeID) {
+		t.Fatalf("expected node ID %s but got %s", nodeID, reply.NodeID)
+	}
+	signerID, err := staking.VerifyNodeIDProof(reply.Proof, []byte(challenge))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !signerID.Equals(nodeID) {
+		t.Fatalf("expected the proof to be signed by %s but it was signed by %s", nodeID, signerID)
+	}
+}
+
+func TestProveNodeIDStakingDisabled(t *testing.T) {
+	service := Info{log: logging.NoLog{}}
+
+	if err := service.ProveNodeID(nil, &ProveNodeIDArgs{Challenge: "nonce"}, &ProveNodeIDReply{}); err != errStakingDisabled {
+		t.Fatalf("expected %s but got %v", errStakingDisabled, err)
+	}
+}
@@ -0,0 +1,148 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package keystore
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ava-labs/gecko/database"
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/hashing"
+	"github.com/ava-labs/gecko/vms/components/codec"
+)
+
+const (
+	// maxAddressNameLen is the longest name an address can be saved under
+	maxAddressNameLen = 64
+)
+
+var (
+	// addressBookPrefix is the prefix, in a user's database, of the user's
+	// address book. The databases of blockchains are prefixed with their IDs,
+	// which are never equal to this prefix.
+	addressBookPrefix = []byte("addressBook")
+
+	errEmptyAddressName   = errors.New("address name can't be the empty string")
+	errAddressNameTooLong = fmt.Errorf("address name can't be longer than %d characters", maxAddressNameLen)
+	errAddressNameSep     = errors.New("address name can't contain '-', which separates a chain from an address")
+	errEmptyAddress       = errors.New("address can't be empty")
+	errUnknownAddressName = errors.New("no address is saved under that name")
+)
+
+// AddressBookEntry is an address saved in a user's address book
+type AddressBookEntry struct {
+	Name    string      `serialize:"true" json:"name"`
+	Address ids.ShortID `serialize:"true" json:"address"`
+}
+
+// AddressBook is the addresses a user saved under names, so that API calls can
+// refer to them by name rather than by address. Addresses are the same on
+// every chain, so the book is shared by the user's blockchains.
+//
+// Entries are stored in the user's database, encrypted with their password.
+// Each entry is keyed by the hash of its name, so names aren't stored in the
+// clear.
+type AddressBook struct {
+	codec codec.Codec
+	db    database.Database
+}
+
+// Set saves [address] under [name], replacing the address previously saved
+// under [name], if any
+func (ab *AddressBook) Set(name string, address ids.ShortID) error {
+	if err := verifyAddressName(name); err != nil {
+		return err
+	}
+	if address.IsZero() {
+		return errEmptyAddress
+	}
+	entryBytes, err := ab.codec.Marshal(&AddressBookEntry{
+		Name:    name,
+		Address: address,
+	})
+	if err != nil {
+		return err
+	}
+	return ab.db.Put(addressNameKey(name), entryBytes)
+}
+
+// Get returns the address saved under [name]
+func (ab *AddressBook) Get(name string) (ids.ShortID, error) {
+	entryBytes, err := ab.db.Get(addressNameKey(name))
+	if err == database.ErrNotFound {
+		return ids.ShortID{}, fmt.Errorf("%w: %q", errUnknownAddressName, name)
+	}
+	if err != nil {
+		return ids.ShortID{}, err
+	}
+	entry := AddressBookEntry{}
+	if err := ab.codec.Unmarshal(entryBytes, &entry); err != nil {
+		return ids.ShortID{}, err
+	}
+	return entry.Address, nil
+}
+
+// Remove the address saved under [name]
+func (ab *AddressBook) Remove(name string) error {
+	key := addressNameKey(name)
+	if has, err := ab.db.Has(key); err != nil {
+		return err
+	} else if !has {
+		return fmt.Errorf("%w: %q", errUnknownAddressName, name)
+	}
+	return ab.db.Delete(key)
+}
+
+// List returns the entries of the address book, sorted by name
+func (ab *AddressBook) List() ([]AddressBookEntry, error) {
+	entries := []AddressBookEntry{}
+
+	it := ab.db.NewIterator()
+	defer it.Release()
+	for it.Next() {
+		entry := AddressBookEntry{}
+		if err := ab.codec.Unmarshal(it.Value(), &entry); err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	if err := it.Error(); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name < entries[j].Name })
+	return entries, nil
+}
+
+// addressNameKey returns the key of the entry named [name]
+func addressNameKey(name string) []byte { return hashing.ComputeHash256([]byte(name)) }
+
+// verifyAddressName returns nil if an address can be saved under [name].
+// Names can't contain the separator of a chain's alias from an address, so
+// they can't be confused with addresses.
+func verifyAddressName(name string) error {
+	switch {
+	case name == "":
+		return errEmptyAddressName
+	case len(name) > maxAddressNameLen:
+		return errAddressNameTooLong
+	case strings.Contains(name, "-"):
+		return errAddressNameSep
+	default:
+		return nil
+	}
+}
+
+// parseAddress parses an address, optionally prefixed with the alias of a
+// chain, as in "X-<address>". The chain is ignored, since addresses are the
+// same on every chain.
+func parseAddress(addrStr string) (ids.ShortID, error) {
+	if sep := strings.LastIndex(addrStr, "-"); sep >= 0 {
+		addrStr = addrStr[sep+1:]
+	}
+	return ids.ShortFromString(addrStr)
+}
@@ -18,3 +18,8 @@ type BlockchainKeystore struct {
 func (bks *BlockchainKeystore) GetDatabase(username, password string) (database.Database, error) {
 	return bks.ks.GetDatabase(bks.blockchainID, username, password)
 }
+
+// ResolveAddress ...
+func (bks *BlockchainKeystore) ResolveAddress(username, password, name string) (ids.ShortID, error) {
+	return bks.ks.ResolveAddress(username, password, name)
+}
@@ -45,6 +45,7 @@ var (
 	errEmptyUsername     = errors.New("username can't be the empty string")
 	errUserPassMaxLength = fmt.Errorf("CreateUser call rejected due to username or password exceeding maximum length of %d chars", maxUserPassLen)
 	errWeakPassword      = errors.New("Failed to create user as the given password is too weak. A stronger password is one of 8 or more characters containing attributes of upper and lowercase letters, numbers, and/or special characters")
+	errKeystoreDisabled  = errors.New("the keystore is disabled on this node, so transactions must be signed externally")
 )
 
 // KeyValuePair ...
@@ -70,6 +71,10 @@ type Keystore struct {
 	// Value: The user with that name
 	users map[string]*User
 
+	// If true, users' databases can't be accessed, so no keys are stored or
+	// used by this node
+	disabled bool
+
 	// Used to persist users and their data
 	userDB database.Database
 	bcDB   database.Database
@@ -91,6 +96,15 @@ func (ks *Keystore) Initialize(log logging.Logger, db database.Database) {
 	ks.bcDB = prefixdb.New([]byte("bcs"), db)
 }
 
+// Disable the keystore. Blockchains can no longer access users' databases, so
+// they can't store or sign with users' keys.
+func (ks *Keystore) Disable() {
+	ks.lock.Lock()
+	defer ks.lock.Unlock()
+
+	ks.disabled = true
+}
+
 // CreateHandler returns a new service object that can send requests to thisAPI.
 func (ks *Keystore) CreateHandler() *common.HTTPHandler {
 	newServer := rpc.NewServer()
@@ -316,6 +330,10 @@ func (ks *Keystore) GetDatabase(bID ids.ID, username, password string) (database
 	ks.lock.Lock()
 	defer ks.lock.Unlock()
 
+	if ks.disabled {
+		return nil, errKeystoreDisabled
+	}
+
 	usr, err := ks.getUser(username)
 	if err != nil {
 		return nil, err
@@ -334,3 +352,168 @@ func (ks *Keystore) GetDatabase(bID ids.ID, username, password string) (database
 
 	return encDB, nil
 }
+
+// addressBook returns the address book of the user [username], after checking
+// [password]. Assumes the lock is held.
+func (ks *Keystore) addressBook(username, password string) (*AddressBook, error) {
+	if ks.disabled {
+		return nil, errKeystoreDisabled
+	}
+
+	usr, err := ks.getUser(username)
+	if err != nil {
+		return nil, err
+	}
+	if !usr.CheckPassword(password) {
+		return nil, fmt.Errorf("incorrect password for user '%s'", username)
+	}
+
+	userDB := prefixdb.New([]byte(username), ks.bcDB)
+	bookDB := prefixdb.NewNested(addressBookPrefix, userDB)
+	encDB, err := encdb.New([]byte(password), bookDB)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AddressBook{
+		codec: ks.codec,
+		db:    encDB,
+	}, nil
+}
+
+// ResolveAddress returns the address saved under [name] in the address book of
+// the user [username]
+func (ks *Keystore) ResolveAddress(username, password, name string) (ids.ShortID, error) {
+	ks.lock.Lock()
+	defer ks.lock.Unlock()
+
+	book, err := ks.addressBook(username, password)
+	if err != nil {
+		return ids.ShortID{}, err
+	}
+	return book.Get(name)
+}
+
+// SetAddressBookEntryArgs are the arguments to SetAddressBookEntry
+type SetAddressBookEntryArgs struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+	Name     string `json:"name"`
+	Address  string `json:"address"`
+}
+
+// SetAddressBookEntryReply is the reply from SetAddressBookEntry
+type SetAddressBookEntryReply struct {
+	Success bool `json:"success"`
+}
+
+// SetAddressBookEntry saves an address in a user's address book under a name.
+// API calls made by the user can then refer to the address by that name.
+func (ks *Keystore) SetAddressBookEntry(_ *http.Request, args *SetAddressBookEntryArgs, reply *SetAddressBookEntryReply) error {
+	ks.lock.Lock()
+	defer ks.lock.Unlock()
+
+	ks.log.Verbo("SetAddressBookEntry called for %s", args.Username)
+
+	address, err := parseAddress(args.Address)
+	if err != nil {
+		return fmt.Errorf("problem parsing address '%s': %w", args.Address, err)
+	}
+
+	book, err := ks.addressBook(args.Username, args.Password)
+	if err != nil {
+		return err
+	}
+	if err := book.Set(args.Name, address); err != nil {
+		return err
+	}
+
+	reply.Success = true
+	return nil
+}
+
+// GetAddressBookEntryArgs are the arguments to GetAddressBookEntry
+type GetAddressBookEntryArgs struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+	Name     string `json:"name"`
+}
+
+// GetAddressBookEntryReply is the reply from GetAddressBookEntry
+type GetAddressBookEntryReply struct {
+	Address ids.ShortID `json:"address"`
+}
+
+// GetAddressBookEntry returns the address saved under a name in a user's
+// address book
+func (ks *Keystore) GetAddressBookEntry(_ *http.Request, args *GetAddressBookEntryArgs, reply *GetAddressBookEntryReply) error {
+	ks.lock.Lock()
+	defer ks.lock.Unlock()
+
+	ks.log.Verbo("GetAddressBookEntry called for %s", args.Username)
+
+	book, err := ks.addressBook(args.Username, args.Password)
+	if err != nil {
+		return err
+	}
+	reply.Address, err = book.Get(args.Name)
+	return err
+}
+
+// ListAddressBookArgs are the arguments to ListAddressBook
+type ListAddressBookArgs struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// ListAddressBookReply is the reply from ListAddressBook
+type ListAddressBookReply struct {
+	Entries []AddressBookEntry `json:"entries"`
+}
+
+// ListAddressBook returns the entries of a user's address book, sorted by name
+func (ks *Keystore) ListAddressBook(_ *http.Request, args *ListAddressBookArgs, reply *ListAddressBookReply) error {
+	ks.lock.Lock()
+	defer ks.lock.Unlock()
+
+	ks.log.Verbo("ListAddressBook called for %s", args.Username)
+
+	book, err := ks.addressBook(args.Username, args.Password)
+	if err != nil {
+		return err
+	}
+	reply.Entries, err = book.List()
+	return err
+}
+
+// DeleteAddressBookEntryArgs are the arguments to DeleteAddressBookEntry
+type DeleteAddressBookEntryArgs struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+	Name     string `json:"name"`
+}
+
+// DeleteAddressBookEntryReply is the reply from DeleteAddressBookEntry
+type DeleteAddressBookEntryReply struct {
+	Success bool `json:"success"`
+}
+
+// DeleteAddressBookEntry removes the address saved under a name from a user's
+// address book
+func (ks *Keystore) DeleteAddressBookEntry(_ *http.Request, args *DeleteAddressBookEntryArgs, reply *DeleteAddressBookEntryReply) error {
+	ks.lock.Lock()
+	defer ks.lock.Unlock()
+
+	ks.log.Verbo("DeleteAddressBookEntry called for %s", args.Username)
+
+	book, err := ks.addressBook(args.Username, args.Password)
+	if err != nil {
+		return err
+	}
+	if err := book.Remove(args.Name); err != nil {
+		return err
+	}
+
+	reply.Success = true
+	return nil
+}
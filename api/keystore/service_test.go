@@ -216,6 +216,28 @@ func TestServiceUseBlockchainDB(t *testing.T) {
 	}
 }
 
+func TestServiceDisabled(t *testing.T) {
+	ks := Keystore{}
+	ks.Initialize(logging.NoLog{}, memdb.New())
+
+	{
+		reply := CreateUserReply{}
+		if err := ks.CreateUser(nil, &CreateUserArgs{
+			Username: "bob",
+			Password: strongPassword,
+		}, &reply); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ks.Disable()
+
+	bks := ks.NewBlockchainKeyStore(ids.Empty)
+	if _, err := bks.GetDatabase("bob", strongPassword); err == nil {
+		t.Fatalf("Shouldn't be able to access a user's database when the keystore is disabled")
+	}
+}
+
 func TestServiceExportImport(t *testing.T) {
 	ks := Keystore{}
 	ks.Initialize(logging.NoLog{}, memdb.New())
@@ -280,3 +302,171 @@ func TestServiceExportImport(t *testing.T) {
 		}
 	}
 }
+
+func TestServiceAddressBook(t *testing.T) {
+	ks := Keystore{}
+	ks.Initialize(logging.NoLog{}, memdb.New())
+
+	if err := ks.CreateUser(nil, &CreateUserArgs{
+		Username: "bob",
+		Password: strongPassword,
+	}, &CreateUserReply{}); err != nil {
+		t.Fatal(err)
+	}
+
+	alice := ids.NewShortID([20]byte{1})
+	carol := ids.NewShortID([20]byte{2})
+
+	{
+		reply := SetAddressBookEntryReply{}
+		if err := ks.SetAddressBookEntry(nil, &SetAddressBookEntryArgs{
+			Username: "bob",
+			Password: strongPassword,
+			Name:     "alice",
+			Address:  "X-" + alice.String(),
+		}, &reply); err != nil {
+			t.Fatal(err)
+		}
+		if !reply.Success {
+			t.Fatalf("Entry should have been saved successfully")
+		}
+	}
+
+	if err := ks.SetAddressBookEntry(nil, &SetAddressBookEntryArgs{
+		Username: "bob",
+		Password: strongPassword,
+		Name:     "carol",
+		Address:  carol.String(),
+	}, &SetAddressBookEntryReply{}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ks.SetAddressBookEntry(nil, &SetAddressBookEntryArgs{
+		Username: "bob",
+		Password: strongPassword,
+		Name:     "X-carol",
+		Address:  carol.String(),
+	}, &SetAddressBookEntryReply{}); err == nil {
+		t.Fatalf("Shouldn't have saved an entry under a name containing '-'")
+	}
+
+	if err := ks.SetAddressBookEntry(nil, &SetAddressBookEntryArgs{
+		Username: "bob",
+		Password: "wrong password",
+		Name:     "dave",
+		Address:  carol.String(),
+	}, &SetAddressBookEntryReply{}); err == nil {
+		t.Fatalf("Shouldn't have saved an entry with the wrong password")
+	}
+
+	{
+		reply := GetAddressBookEntryReply{}
+		if err := ks.GetAddressBookEntry(nil, &GetAddressBookEntryArgs{
+			Username: "bob",
+			Password: strongPassword,
+			Name:     "alice",
+		}, &reply); err != nil {
+			t.Fatal(err)
+		}
+		if !reply.Address.Equals(alice) {
+			t.Fatalf("Should have returned %s but returned %s", alice, reply.Address)
+		}
+	}
+
+	{
+		reply := ListAddressBookReply{}
+		if err := ks.ListAddressBook(nil, &ListAddressBookArgs{
+			Username: "bob",
+			Password: strongPassword,
+		}, &reply); err != nil {
+			t.Fatal(err)
+		}
+		if len(reply.Entries) != 2 {
+			t.Fatalf("Should have listed 2 entries but listed %d", len(reply.Entries))
+		}
+		if entry := reply.Entries[0]; entry.Name != "alice" || !entry.Address.Equals(alice) {
+			t.Fatalf("Wrong first entry: %s %s", entry.Name, entry.Address)
+		}
+		if entry := reply.Entries[1]; entry.Name != "carol" || !entry.Address.Equals(carol) {
+			t.Fatalf("Wrong second entry: %s %s", entry.Name, entry.Address)
+		}
+	}
+
+	{
+		reply := DeleteAddressBookEntryReply{}
+		if err := ks.DeleteAddressBookEntry(nil, &DeleteAddressBookEntryArgs{
+			Username: "bob",
+			Password: strongPassword,
+			Name:     "alice",
+		}, &reply); err != nil {
+			t.Fatal(err)
+		}
+		if !reply.Success {
+			t.Fatalf("Entry should have been deleted successfully")
+		}
+	}
+
+	if _, err := ks.ResolveAddress("bob", strongPassword, "alice"); err == nil {
+		t.Fatalf("Shouldn't have resolved a deleted entry")
+	}
+	if err := ks.DeleteAddressBookEntry(nil, &DeleteAddressBookEntryArgs{
+		Username: "bob",
+		Password: strongPassword,
+		Name:     "alice",
+	}, &DeleteAddressBookEntryReply{}); err == nil {
+		t.Fatalf("Shouldn't have deleted a missing entry")
+	}
+
+	bks := ks.NewBlockchainKeyStore(ids.Empty)
+	if addr, err := bks.ResolveAddress("bob", strongPassword, "carol"); err != nil {
+		t.Fatal(err)
+	} else if !addr.Equals(carol) {
+		t.Fatalf("Should have resolved %s but resolved %s", carol, addr)
+	}
+}
+
+func TestServiceAddressBookExportImport(t *testing.T) {
+	ks := Keystore{}
+	ks.Initialize(logging.NoLog{}, memdb.New())
+
+	if err := ks.CreateUser(nil, &CreateUserArgs{
+		Username: "bob",
+		Password: strongPassword,
+	}, &CreateUserReply{}); err != nil {
+		t.Fatal(err)
+	}
+
+	alice := ids.NewShortID([20]byte{1})
+	if err := ks.SetAddressBookEntry(nil, &SetAddressBookEntryArgs{
+		Username: "bob",
+		Password: strongPassword,
+		Name:     "alice",
+		Address:  alice.String(),
+	}, &SetAddressBookEntryReply{}); err != nil {
+		t.Fatal(err)
+	}
+
+	exportReply := ExportUserReply{}
+	if err := ks.ExportUser(nil, &ExportUserArgs{
+		Username: "bob",
+		Password: strongPassword,
+	}, &exportReply); err != nil {
+		t.Fatal(err)
+	}
+
+	newKS := Keystore{}
+	newKS.Initialize(logging.NoLog{}, memdb.New())
+	if err := newKS.ImportUser(nil, &ImportUserArgs{
+		Username: "bob",
+		Password: strongPassword,
+		User:     exportReply.User,
+	}, &ImportUserReply{}); err != nil {
+		t.Fatal(err)
+	}
+
+	if addr, err := newKS.ResolveAddress("bob", strongPassword, "alice"); err != nil {
+		t.Fatal(err)
+	} else if !addr.Equals(alice) {
+		t.Fatalf("Should have resolved %s but resolved %s", alice, addr)
+	}
+}
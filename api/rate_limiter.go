@@ -0,0 +1,249 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package api
+
+import (
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ava-labs/gecko/cache"
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow/validators"
+	"github.com/ava-labs/gecko/staking"
+	"github.com/ava-labs/gecko/utils/hashing"
+	"github.com/ava-labs/gecko/utils/logging"
+	"github.com/ava-labs/gecko/utils/timer"
+)
+
+const (
+	// maxIdleCallerBuckets is the number of caller buckets that may be tracked
+	// before the full buckets are pruned
+	maxIdleCallerBuckets = 4096
+
+	// verifiedTokensSize is the number of API tokens whose signature is
+	// remembered, so the signature isn't checked on every call
+	verifiedTokensSize = 1024
+
+	// bearerPrefix precedes the API token in the Authorization header
+	bearerPrefix = "Bearer "
+)
+
+// RateLimiter limits the rate of API calls each caller may make.
+//
+// Calls carrying an API token signed by a validator of the default subnet, in
+// an "Authorization: Bearer <token>" header, are charged to the validator and
+// get a quota proportional to the validator's stake: a validator with the
+// average stake may make ValidatorRate calls per second. Validators never get
+// less than the anonymous quota. Other calls are charged to the IP they're
+// made from. The IP is taken from the connection, so a proxy in front of the
+// node shares one anonymous quota between all of its callers.
+type RateLimiter struct {
+	Clock timer.Clock
+
+	log logging.Logger
+
+	anonymousRate, anonymousBurst float64
+	validatorRate, validatorBurst float64
+
+	// Token hash --> *verifiedToken
+	verifiedTokens cache.LRU
+
+	lock       sync.Mutex
+	validators validators.Set
+	buckets    map[string]*callBucket
+}
+
+// Initialize the rate limiter. [anonymousRate] and [validatorRate] are the
+// number of calls per second that may be made anonymously and by a validator
+// with the average stake. [anonymousBurst] and [validatorBurst] are the
+// maximum number of calls that may be made at once.
+func (l *RateLimiter) Initialize(log logging.Logger, anonymousRate, anonymousBurst, validatorRate, validatorBurst float64) {
+	l.log = log
+	l.anonymousRate = anonymousRate
+	l.anonymousBurst = anonymousBurst
+	l.validatorRate = validatorRate
+	l.validatorBurst = validatorBurst
+	l.verifiedTokens.Size = verifiedTokensSize
+	l.buckets = make(map[string]*callBucket)
+}
+
+// SetValidators sets the validators whose tokens get the validator quota.
+// Until it's called, every call is charged the anonymous quota.
+func (l *RateLimiter) SetValidators(vdrs validators.Set) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	l.validators = vdrs
+}
+
+// Wrap returns a handler that serves the calls that fit in their caller's
+// quota with [handler]
+func (l *RateLimiter) Wrap(handler http.Handler) http.Handler {
+	return rateLimitHandler{
+		limiter: l,
+		handler: handler,
+	}
+}
+
+// allow returns true if the call [request] fits in its caller's quota, in
+// which case the call is charged to the caller. An error is returned if the
+// call carries an invalid API token.
+func (l *RateLimiter) allow(request *http.Request) (bool, error) {
+	now := l.Clock.Time()
+
+	var callerID string
+	nodeID, authenticated, err := l.tokenSigner(request, now)
+	if err != nil {
+		return false, err
+	}
+	if authenticated {
+		callerID = "validator:" + nodeID.String()
+	} else {
+		host, _, err := net.SplitHostPort(request.RemoteAddr)
+		if err != nil {
+			host = request.RemoteAddr
+		}
+		callerID = "ip:" + host
+	}
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	rate, burst := l.anonymousRate, l.anonymousBurst
+	if authenticated && l.validators != nil {
+		if vdr, ok := l.validators.Get(nodeID); ok && l.validators.Weight() > 0 {
+			// A validator with the average stake gets the validator quota
+			share := float64(vdr.Weight()) * float64(l.validators.Len()) / float64(l.validators.Weight())
+			if validatorRate := l.validatorRate * share; validatorRate > rate {
+				rate = validatorRate
+			}
+			if validatorBurst := l.validatorBurst * share; validatorBurst > burst {
+				burst = validatorBurst
+			}
+		}
+	}
+
+	bucket, exists := l.buckets[callerID]
+	if !exists {
+		if len(l.buckets) >= maxIdleCallerBuckets {
+			l.prune(now)
+		}
+		bucket = &callBucket{
+			calls:      burst,
+			lastUpdate: now,
+		}
+		l.buckets[callerID] = bucket
+	}
+	bucket.refill(now, rate, burst)
+	if bucket.calls < 1 {
+		return false, nil
+	}
+	bucket.calls--
+	return true, nil
+}
+
+// tokenSigner returns the ID of the node that signed the API token carried by
+// [request]. Returns false if [request] doesn't carry a token.
+func (l *RateLimiter) tokenSigner(request *http.Request, now time.Time) (ids.ShortID, bool, error) {
+	token, ok := BearerToken(request)
+	if !ok {
+		return ids.ShortID{}, false, nil
+	}
+
+	tokenHash := ids.NewID(hashing.ComputeHash256Array([]byte(token)))
+	if verifiedIntf, ok := l.verifiedTokens.Get(tokenHash); ok {
+		verified := verifiedIntf.(*verifiedToken)
+		if now.Before(verified.expiry) {
+			return verified.nodeID, true, nil
+		}
+		l.verifiedTokens.Evict(tokenHash)
+	}
+
+	nodeID, expiry, err := staking.VerifyAPIToken(token, now)
+	if err != nil {
+		l.log.Debug("API call from %s carried an invalid token: %s", request.RemoteAddr, err)
+		return ids.ShortID{}, false, err
+	}
+	l.verifiedTokens.Put(tokenHash, &verifiedToken{
+		nodeID: nodeID,
+		expiry: expiry,
+	})
+	return nodeID, true, nil
+}
+
+// BearerToken returns the API token carried by [request] in an
+// "Authorization: Bearer <token>" header. Returns false if [request] doesn't
+// carry a token.
+func BearerToken(request *http.Request) (string, bool) {
+	auth := request.Header.Get("Authorization")
+	if !strings.HasPrefix(auth, bearerPrefix) {
+		return "", false
+	}
+	return strings.TrimSpace(auth[len(bearerPrefix):]), true
+}
+
+// prune removes the caller buckets that have refilled completely. A full
+// bucket behaves identically to a bucket that isn't tracked.
+func (l *RateLimiter) prune(now time.Time) {
+	for callerID, bucket := range l.buckets {
+		bucket.refill(now, bucket.rate, bucket.burst)
+		if bucket.calls >= bucket.burst {
+			delete(l.buckets, callerID)
+		}
+	}
+}
+
+// verifiedToken is an API token whose signature has been checked
+type verifiedToken struct {
+	nodeID ids.ShortID
+	expiry time.Time
+}
+
+// callBucket is a token bucket whose tokens are API calls
+type callBucket struct {
+	calls      float64
+	lastUpdate time.Time
+
+	// The quota the bucket was last refilled with
+	rate, burst float64
+}
+
+// refill the bucket with the calls earned since the last update, at [rate]
+// calls per second, up to [burst] calls
+func (b *callBucket) refill(now time.Time, rate, burst float64) {
+	b.rate = rate
+	b.burst = burst
+
+	elapsed := now.Sub(b.lastUpdate)
+	if elapsed <= 0 {
+		return
+	}
+	b.lastUpdate = now
+
+	b.calls += elapsed.Seconds() * rate
+	if b.calls > burst {
+		b.calls = burst
+	}
+}
+
+type rateLimitHandler struct {
+	limiter *RateLimiter
+	handler http.Handler
+}
+
+func (h rateLimitHandler) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+	allowed, err := h.limiter.allow(request)
+	switch {
+	case err != nil:
+		http.Error(writer, err.Error(), http.StatusUnauthorized)
+	case !allowed:
+		writer.Header().Set("Retry-After", "1")
+		http.Error(writer, "API rate limit exceeded", http.StatusTooManyRequests)
+	default:
+		h.handler.ServeHTTP(writer, request)
+	}
+}
@@ -0,0 +1,105 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/gecko/snow/validators"
+	"github.com/ava-labs/gecko/staking"
+	"github.com/ava-labs/gecko/utils/logging"
+)
+
+const testSeedPhrase = "abandon ability able about above absent absorb abstract absurd abuse access accident"
+
+func newTestLimiter() (*RateLimiter, http.Handler) {
+	limiter := &RateLimiter{}
+	limiter.Clock.Set(time.Unix(1000000, 0))
+	limiter.Initialize(logging.NoLog{}, 1, 2, 10, 20)
+	handler := limiter.Wrap(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	return limiter, handler
+}
+
+func serveTestCall(handler http.Handler, remoteAddr, token string) int {
+	request := httptest.NewRequest("POST", "/ext/info", nil)
+	request.RemoteAddr = remoteAddr
+	if token != "" {
+		request.Header.Set("Authorization", "Bearer "+token)
+	}
+	writer := httptest.NewRecorder()
+	handler.ServeHTTP(writer, request)
+	return writer.Code
+}
+
+func TestRateLimiterAnonymous(t *testing.T) {
+	limiter, handler := newTestLimiter()
+
+	// The burst is used up, then calls are rejected
+	for i := 0; i < 2; i++ {
+		if code := serveTestCall(handler, "1.2.3.4:5000", ""); code != http.StatusOK {
+			t.Fatalf("Call %d should have been allowed but got status %d", i, code)
+		}
+	}
+	if code := serveTestCall(handler, "1.2.3.4:5001", ""); code != http.StatusTooManyRequests {
+		t.Fatalf("Call should have been rate limited but got status %d", code)
+	}
+
+	// Other IPs have their own quota
+	if code := serveTestCall(handler, "5.6.7.8:5000", ""); code != http.StatusOK {
+		t.Fatalf("Call from another IP should have been allowed but got status %d", code)
+	}
+
+	// The quota refills over time
+	limiter.Clock.Set(limiter.Clock.Time().Add(time.Second))
+	if code := serveTestCall(handler, "1.2.3.4:5000", ""); code != http.StatusOK {
+		t.Fatalf("Call should have been allowed after the quota refilled but got status %d", code)
+	}
+}
+
+func TestRateLimiterValidatorToken(t *testing.T) {
+	limiter, handler := newTestLimiter()
+
+	cert, key, err := staking.NewCertAndKeyFromSeedPhrase(testSeedPhrase)
+	if err != nil {
+		t.Fatal(err)
+	}
+	nodeID, err := staking.NodeID(cert)
+	if err != nil {
+		t.Fatal(err)
+	}
+	token, err := staking.NewAPIToken(cert, key, limiter.Clock.Time().Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	vdrs := validators.NewSet()
+	vdrs.Add(validators.NewValidator(nodeID, 1))
+	limiter.SetValidators(vdrs)
+
+	// A validator with the average stake gets the validator burst
+	for i := 0; i < 20; i++ {
+		if code := serveTestCall(handler, "1.2.3.4:5000", token); code != http.StatusOK {
+			t.Fatalf("Call %d should have been allowed but got status %d", i, code)
+		}
+	}
+	if code := serveTestCall(handler, "1.2.3.4:5000", token); code != http.StatusTooManyRequests {
+		t.Fatalf("Call should have been rate limited but got status %d", code)
+	}
+
+	// The validator's calls aren't charged to the IP they're made from
+	if code := serveTestCall(handler, "1.2.3.4:5000", ""); code != http.StatusOK {
+		t.Fatalf("Anonymous call should have been allowed but got status %d", code)
+	}
+}
+
+func TestRateLimiterInvalidToken(t *testing.T) {
+	_, handler := newTestLimiter()
+
+	if code := serveTestCall(handler, "1.2.3.4:5000", "not a token"); code != http.StatusUnauthorized {
+		t.Fatalf("Call with an invalid token should have been rejected but got status %d", code)
+	}
+}
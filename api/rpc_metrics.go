@@ -0,0 +1,179 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/ava-labs/gecko/utils/logging"
+	"github.com/ava-labs/gecko/utils/metrics"
+)
+
+const (
+	// maxErrorResponseSize is the number of bytes of a response that are kept
+	// to look for a JSON-RPC error. Error responses are small, so a larger
+	// response is a result.
+	maxErrorResponseSize = 4096
+
+	// unknownMethod labels calls to methods the handler doesn't serve, so
+	// arbitrary method names can't blow up the number of series
+	unknownMethod = "unknown"
+
+	// jsonRPCInternalError is the JSON-RPC 2.0 code of an internal error
+	jsonRPCInternalError = -32603
+)
+
+// Error classes of an API call
+const (
+	errorClassNone     = "none"
+	errorClassUser     = "user"
+	errorClassInternal = "internal"
+)
+
+// rpcMetrics tracks the calls made to each JSON-RPC method
+type rpcMetrics struct {
+	calls   *prometheus.CounterVec
+	latency *prometheus.HistogramVec
+}
+
+func (m *rpcMetrics) Initialize(log logging.Logger, registerer prometheus.Registerer) {
+	m.calls = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metrics.Namespace,
+			Subsystem: "api",
+			Name:      "calls",
+			Help:      "Number of API calls by endpoint, method and error class",
+		},
+		[]string{"endpoint", "method", "error_class"},
+	)
+	m.latency = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: metrics.Namespace,
+			Subsystem: "api",
+			Name:      "call_duration_seconds",
+			Help:      "Time spent handling API calls by endpoint and method, in seconds",
+			Buckets:   prometheus.DefBuckets,
+		},
+		[]string{"endpoint", "method"},
+	)
+
+	if err := registerer.Register(m.calls); err != nil {
+		log.Error("Failed to register api calls statistics due to %s", err)
+	}
+	if err := registerer.Register(m.latency); err != nil {
+		log.Error("Failed to register api latency statistics due to %s", err)
+	}
+}
+
+// methodServer is implemented by handlers that can report whether they serve a
+// method, such as gorilla's rpc server
+type methodServer interface {
+	HasMethod(method string) bool
+}
+
+// rpcMetricsHandler records the method, latency and error class of each
+// JSON-RPC call made to [handler]
+type rpcMetricsHandler struct {
+	metrics  *rpcMetrics
+	endpoint string
+	// methods, if non-nil, reports which methods [handler] serves
+	methods methodServer
+	handler http.Handler
+}
+
+func (h rpcMetricsHandler) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+	// Only POSTs are JSON-RPC calls. Other requests, such as websocket
+	// upgrades, need the original writer.
+	if request.Method != http.MethodPost {
+		h.handler.ServeHTTP(writer, request)
+		return
+	}
+
+	body, err := ioutil.ReadAll(request.Body)
+	if err != nil {
+		http.Error(writer, err.Error(), http.StatusBadRequest)
+		return
+	}
+	request.Body = ioutil.NopCloser(bytes.NewReader(body))
+	method := h.method(body)
+
+	recorder := &responseRecorder{ResponseWriter: writer, status: http.StatusOK}
+	start := time.Now()
+	h.handler.ServeHTTP(recorder, request)
+	duration := time.Since(start)
+
+	h.metrics.latency.WithLabelValues(h.endpoint, method).Observe(duration.Seconds())
+	h.metrics.calls.WithLabelValues(h.endpoint, method, errorClass(recorder.status, recorder.body.Bytes(), recorder.truncated)).Inc()
+}
+
+// method returns the label of the method called by the JSON-RPC request [body]
+func (h rpcMetricsHandler) method(body []byte) string {
+	request := struct {
+		Method string `json:"method"`
+	}{}
+	if err := json.Unmarshal(body, &request); err != nil || request.Method == "" {
+		return unknownMethod
+	}
+	if h.methods != nil && !h.methods.HasMethod(request.Method) {
+		return unknownMethod
+	}
+	return request.Method
+}
+
+// errorClass returns the class of error a response reports. Errors returned by
+// a method are treated as user errors, as methods return errors when their
+// arguments or the state they refer to are invalid. A 5xx status or a JSON-RPC
+// internal error is an internal error.
+func errorClass(status int, body []byte, truncated bool) string {
+	if status >= http.StatusInternalServerError {
+		return errorClassInternal
+	}
+
+	response := struct {
+		Error *struct {
+			Code int `json:"code"`
+		} `json:"error"`
+	}{}
+	if !truncated && json.Unmarshal(body, &response) == nil && response.Error != nil {
+		if response.Error.Code == jsonRPCInternalError {
+			return errorClassInternal
+		}
+		return errorClassUser
+	}
+	if status >= http.StatusBadRequest {
+		return errorClassUser
+	}
+	return errorClassNone
+}
+
+// responseRecorder passes a response through to the client while keeping its
+// status and the start of its body
+type responseRecorder struct {
+	http.ResponseWriter
+
+	status    int
+	body      bytes.Buffer
+	truncated bool
+}
+
+func (r *responseRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	if remaining := maxErrorResponseSize - r.body.Len(); len(b) > remaining {
+		r.body.Write(b[:remaining])
+		r.truncated = true
+	} else {
+		r.body.Write(b)
+	}
+	return r.ResponseWriter.Write(b)
+}
@@ -0,0 +1,78 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package api
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gorilla/rpc/v2"
+	"github.com/gorilla/rpc/v2/json2"
+)
+
+func TestErrorClassSuccess(t *testing.T) {
+	body := []byte(`{"jsonrpc":"2.0","result":{},"id":1}`)
+	if class := errorClass(http.StatusOK, body, false); class != errorClassNone {
+		t.Fatalf("Expected %s but got %s", errorClassNone, class)
+	}
+}
+
+func TestErrorClassUser(t *testing.T) {
+	body := []byte(`{"jsonrpc":"2.0","error":{"code":-32000,"message":"insufficient funds"},"id":1}`)
+	if class := errorClass(http.StatusOK, body, false); class != errorClassUser {
+		t.Fatalf("Expected %s but got %s", errorClassUser, class)
+	}
+}
+
+func TestErrorClassInternal(t *testing.T) {
+	body := []byte(`{"jsonrpc":"2.0","error":{"code":-32603,"message":"internal error"},"id":1}`)
+	if class := errorClass(http.StatusOK, body, false); class != errorClassInternal {
+		t.Fatalf("Expected %s but got %s", errorClassInternal, class)
+	}
+	if class := errorClass(http.StatusInternalServerError, nil, false); class != errorClassInternal {
+		t.Fatalf("Expected %s but got %s", errorClassInternal, class)
+	}
+}
+
+func TestErrorClassBadRequest(t *testing.T) {
+	if class := errorClass(http.StatusBadRequest, []byte("bad request"), false); class != errorClassUser {
+		t.Fatalf("Expected %s but got %s", errorClassUser, class)
+	}
+}
+
+func TestErrorClassTruncated(t *testing.T) {
+	// The start of a large result that happens to mention an error
+	body := []byte(`{"jsonrpc":"2.0","result":{"error":{"code":-32603`)
+	if class := errorClass(http.StatusOK, body, true); class != errorClassNone {
+		t.Fatalf("Expected %s but got %s", errorClassNone, class)
+	}
+}
+
+func TestRPCMetricsMethod(t *testing.T) {
+	server := rpc.NewServer()
+	server.RegisterCodec(json2.NewCodec(), "application/json")
+	server.RegisterService(&Service{}, "test")
+
+	h := rpcMetricsHandler{methods: server, handler: server}
+
+	known, err := json2.EncodeClientRequest("test.Call", &Args{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if method := h.method(known); method != "test.Call" {
+		t.Fatalf("Expected %s but got %s", "test.Call", method)
+	}
+
+	unknown, err := json2.EncodeClientRequest("test.Missing", &Args{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if method := h.method(unknown); method != unknownMethod {
+		t.Fatalf("Expected %s but got %s", unknownMethod, method)
+	}
+
+	if method := h.method([]byte("not json")); method != unknownMethod {
+		t.Fatalf("Expected %s but got %s", unknownMethod, method)
+	}
+}
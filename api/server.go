@@ -13,6 +13,8 @@ import (
 
 	"github.com/gorilla/handlers"
 
+	"github.com/prometheus/client_golang/prometheus"
+
 	"github.com/rs/cors"
 
 	"github.com/ava-labs/gecko/snow"
@@ -32,26 +34,41 @@ type Server struct {
 	factory logging.Factory
 	router  *router
 	portURL string
+	metrics rpcMetrics
+	limiter *RateLimiter
 }
 
-// Initialize creates the API server at the provided port
-func (s *Server) Initialize(log logging.Logger, factory logging.Factory, port uint16) {
+// Initialize creates the API server at the provided port. Metrics of the calls
+// made to the server are registered with [registerer].
+func (s *Server) Initialize(log logging.Logger, factory logging.Factory, port uint16, registerer prometheus.Registerer) {
 	s.log = log
 	s.factory = factory
 	s.portURL = fmt.Sprintf(":%d", port)
 	s.router = newRouter()
+	s.metrics.Initialize(log, registerer)
 }
 
+// SetRateLimiter limits the rate of calls made to the server with [limiter].
+// Must be called before the server is dispatched.
+func (s *Server) SetRateLimiter(limiter *RateLimiter) { s.limiter = limiter }
+
 // Dispatch starts the API server
 func (s *Server) Dispatch() error {
-	handler := cors.Default().Handler(s.router)
-	return http.ListenAndServe(s.portURL, handler)
+	return http.ListenAndServe(s.portURL, s.handler())
 }
 
 // DispatchTLS starts the API server with the provided TLS certificate
 func (s *Server) DispatchTLS(certFile, keyFile string) error {
-	handler := cors.Default().Handler(s.router)
-	return http.ListenAndServeTLS(s.portURL, certFile, keyFile, handler)
+	return http.ListenAndServeTLS(s.portURL, certFile, keyFile, s.handler())
+}
+
+// handler returns the handler of every call made to the server
+func (s *Server) handler() http.Handler {
+	var handler http.Handler = s.router
+	if s.limiter != nil {
+		handler = s.limiter.Wrap(handler)
+	}
+	return cors.Default().Handler(handler)
 }
 
 // RegisterChain registers the API endpoints associated with this chain That
@@ -92,25 +109,34 @@ func (s *Server) RegisterChain(ctx *snow.Context, vmIntf interface{}) {
 func (s *Server) AddRoute(handler *common.HTTPHandler, lock *sync.RWMutex, base, endpoint string, log logging.Logger) error {
 	url := fmt.Sprintf("%s/%s", baseURL, base)
 	s.log.Info("adding route %s%s", url, endpoint)
-	h := handlers.CombinedLoggingHandler(log, handler.Handler)
+	var h http.Handler = handlers.CombinedLoggingHandler(log, handler.Handler)
 	switch handler.LockOptions {
 	case common.WriteLock:
-		return s.router.AddRouter(url, endpoint, middlewareHandler{
+		h = middlewareHandler{
 			before:  lock.Lock,
 			after:   lock.Unlock,
 			handler: h,
-		})
+		}
 	case common.ReadLock:
-		return s.router.AddRouter(url, endpoint, middlewareHandler{
+		h = middlewareHandler{
 			before:  lock.RLock,
 			after:   lock.RUnlock,
 			handler: h,
-		})
+		}
 	case common.NoLock:
-		return s.router.AddRouter(url, endpoint, h)
 	default:
 		return errUnknownLockOption
 	}
+
+	// Measure calls from when they arrive, so time spent waiting on the lock
+	// counts towards their latency
+	methods, _ := handler.Handler.(methodServer)
+	return s.router.AddRouter(url, endpoint, rpcMetricsHandler{
+		metrics:  &s.metrics,
+		endpoint: url + endpoint,
+		methods:  methods,
+		handler:  h,
+	})
 }
 
 // AddAliases registers aliases to the server
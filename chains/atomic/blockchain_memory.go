@@ -0,0 +1,25 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package atomic
+
+import (
+	"github.com/ava-labs/gecko/database"
+	"github.com/ava-labs/gecko/ids"
+)
+
+// BlockchainMemory is the view of the shared memory that one chain uses
+type BlockchainMemory struct {
+	blockchainID ids.ID
+	mem          *Memory
+}
+
+// GetDatabase returns the database this chain shares with [id]
+func (bm *BlockchainMemory) GetDatabase(id ids.ID) database.Database {
+	return bm.mem.GetDatabase(bm.blockchainID, id)
+}
+
+// ReleaseDatabase releases the database this chain shares with [id]
+func (bm *BlockchainMemory) ReleaseDatabase(id ids.ID) {
+	bm.mem.ReleaseDatabase(bm.blockchainID, id)
+}
@@ -0,0 +1,100 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package atomic
+
+import (
+	"bytes"
+	"sync"
+
+	"github.com/ava-labs/gecko/database"
+	"github.com/ava-labs/gecko/database/prefixdb"
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/hashing"
+	"github.com/ava-labs/gecko/utils/logging"
+)
+
+type rcLock struct {
+	lock  sync.Mutex
+	count int
+}
+
+// Memory is the memory that chains use to move state between each other. Each
+// pair of chains shares one database, which only one of them may use at a
+// time.
+type Memory struct {
+	lock  sync.Mutex
+	log   logging.Logger
+	locks map[[32]byte]*rcLock
+	db    database.Database
+}
+
+// Initialize the memory
+func (m *Memory) Initialize(log logging.Logger, db database.Database) {
+	m.log = log
+	m.locks = make(map[[32]byte]*rcLock)
+	m.db = db
+}
+
+// NewBlockchainMemory returns the view of the memory that [id] uses
+func (m *Memory) NewBlockchainMemory(id ids.ID) *BlockchainMemory {
+	return &BlockchainMemory{
+		blockchainID: id,
+		mem:          m,
+	}
+}
+
+// GetDatabase returns the database shared by [id1] and [id2]. It blocks until
+// the database is released by anyone else using it. ReleaseDatabase must be
+// called once the database is no longer used.
+func (m *Memory) GetDatabase(id1, id2 ids.ID) database.Database {
+	sharedID := m.sharedID(id1, id2)
+	m.makeLock(sharedID).Lock()
+	return prefixdb.New(sharedID.Bytes(), m.db)
+}
+
+// ReleaseDatabase releases the database shared by [id1] and [id2]
+func (m *Memory) ReleaseDatabase(id1, id2 ids.ID) {
+	sharedID := m.sharedID(id1, id2)
+	m.releaseLock(sharedID)
+}
+
+func (m *Memory) makeLock(id ids.ID) *sync.Mutex {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	key := id.Key()
+	rc, exists := m.locks[key]
+	if !exists {
+		rc = &rcLock{}
+		m.locks[key] = rc
+	}
+	rc.count++
+	return &rc.lock
+}
+
+func (m *Memory) releaseLock(id ids.ID) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	key := id.Key()
+	rc, exists := m.locks[key]
+	if !exists {
+		m.log.Error("attempted to release the unused shared database %s", id)
+		return
+	}
+	rc.count--
+	if rc.count == 0 {
+		delete(m.locks, key)
+	}
+	rc.lock.Unlock()
+}
+
+// sharedID is the same for [id1], [id2] as for [id2], [id1]
+func (m *Memory) sharedID(id1, id2 ids.ID) ids.ID {
+	b1, b2 := id1.Bytes(), id2.Bytes()
+	if bytes.Compare(b1, b2) == 1 {
+		b1, b2 = b2, b1
+	}
+	return ids.NewID(hashing.ComputeHash256Array(append(append([]byte{}, b1...), b2...)))
+}
@@ -0,0 +1,97 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package atomic
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ava-labs/gecko/database/memdb"
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/logging"
+)
+
+var (
+	blockchainID0 = ids.Empty.Prefix(0)
+	blockchainID1 = ids.Empty.Prefix(1)
+	blockchainID2 = ids.Empty.Prefix(2)
+)
+
+func TestMemorySharedDatabase(t *testing.T) {
+	m := Memory{}
+	m.Initialize(logging.NoLog{}, memdb.New())
+
+	bm0 := m.NewBlockchainMemory(blockchainID0)
+	bm1 := m.NewBlockchainMemory(blockchainID1)
+
+	db0 := bm0.GetDatabase(blockchainID1)
+	if err := db0.Put([]byte("key"), []byte("value")); err != nil {
+		t.Fatal(err)
+	}
+	bm0.ReleaseDatabase(blockchainID1)
+
+	db1 := bm1.GetDatabase(blockchainID0)
+	defer bm1.ReleaseDatabase(blockchainID0)
+
+	value, err := db1.Get([]byte("key"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(value, []byte("value")) {
+		t.Fatalf("Shared database returned %s but should have returned %s", value, "value")
+	}
+}
+
+func TestMemoryIsolatedDatabases(t *testing.T) {
+	m := Memory{}
+	m.Initialize(logging.NoLog{}, memdb.New())
+
+	bm0 := m.NewBlockchainMemory(blockchainID0)
+	bm2 := m.NewBlockchainMemory(blockchainID2)
+
+	db0 := bm0.GetDatabase(blockchainID1)
+	if err := db0.Put([]byte("key"), []byte("value")); err != nil {
+		t.Fatal(err)
+	}
+	bm0.ReleaseDatabase(blockchainID1)
+
+	db2 := bm2.GetDatabase(blockchainID0)
+	defer bm2.ReleaseDatabase(blockchainID0)
+
+	if has, err := db2.Has([]byte("key")); err != nil {
+		t.Fatal(err)
+	} else if has {
+		t.Fatalf("A database shared with another chain should be isolated")
+	}
+}
+
+func TestMemoryExclusiveAccess(t *testing.T) {
+	m := Memory{}
+	m.Initialize(logging.NoLog{}, memdb.New())
+
+	bm0 := m.NewBlockchainMemory(blockchainID0)
+	bm1 := m.NewBlockchainMemory(blockchainID1)
+
+	bm0.GetDatabase(blockchainID1)
+
+	acquired := make(chan struct{})
+	go func() {
+		bm1.GetDatabase(blockchainID0)
+		close(acquired)
+	}()
+
+	select {
+	case <-acquired:
+		t.Fatalf("The shared database should be held until it's released")
+	default:
+	}
+
+	bm0.ReleaseDatabase(blockchainID1)
+	<-acquired
+	bm1.ReleaseDatabase(blockchainID0)
+
+	if len(m.locks) != 0 {
+		t.Fatalf("Released databases shouldn't be tracked")
+	}
+}
@@ -0,0 +1,40 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package atomic
+
+import (
+	"github.com/ava-labs/gecko/database"
+)
+
+// innerBatch is a batch, such as a prefixed database's batch, that writes to
+// the batch of the database it's on top of
+type innerBatch interface {
+	Inner() database.Batch
+}
+
+// WriteAll writes [baseBatch] and [batches] to the database they are all on
+// top of as one batch, so either all of their changes are persisted or none of
+// them are. A chain uses it to commit its own state together with the changes
+// it made to the shared memory.
+func WriteAll(baseBatch database.Batch, batches ...database.Batch) error {
+	baseBatch = underlyingBatch(baseBatch)
+	for _, batch := range batches {
+		if err := underlyingBatch(batch).Replay(baseBatch); err != nil {
+			return err
+		}
+	}
+	return baseBatch.Write()
+}
+
+// underlyingBatch returns the batch of the database at the bottom of [batch]'s
+// prefixed databases
+func underlyingBatch(batch database.Batch) database.Batch {
+	for {
+		inner, ok := batch.(innerBatch)
+		if !ok {
+			return batch
+		}
+		batch = inner.Inner()
+	}
+}
@@ -0,0 +1,75 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package atomic
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ava-labs/gecko/database"
+	"github.com/ava-labs/gecko/database/memdb"
+	"github.com/ava-labs/gecko/database/prefixdb"
+	"github.com/ava-labs/gecko/database/versiondb"
+	"github.com/ava-labs/gecko/utils/logging"
+)
+
+// numKeys returns the number of keys in [db]
+func numKeys(db database.Iteratee) int {
+	it := db.NewIterator()
+	defer it.Release()
+
+	n := 0
+	for it.Next() {
+		n++
+	}
+	return n
+}
+
+func TestWriteAll(t *testing.T) {
+	baseDB := memdb.New()
+
+	m := Memory{}
+	m.Initialize(logging.NoLog{}, prefixdb.New([]byte("shared memory"), baseDB))
+	bm0 := m.NewBlockchainMemory(blockchainID0)
+
+	chainDB := versiondb.New(prefixdb.New(blockchainID0.Bytes(), baseDB))
+	if err := chainDB.Put([]byte("chain key"), []byte("chain value")); err != nil {
+		t.Fatal(err)
+	}
+
+	sharedDB := versiondb.New(bm0.GetDatabase(blockchainID1))
+	defer bm0.ReleaseDatabase(blockchainID1)
+	if err := sharedDB.Put([]byte("shared key"), []byte("shared value")); err != nil {
+		t.Fatal(err)
+	}
+
+	chainBatch, err := chainDB.CommitBatch()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sharedBatch, err := sharedDB.CommitBatch()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if numKeys(baseDB) != 0 {
+		t.Fatalf("Shouldn't have written anything before WriteAll")
+	}
+	if err := WriteAll(chainBatch, sharedBatch); err != nil {
+		t.Fatal(err)
+	}
+
+	if n := numKeys(baseDB); n != 2 {
+		t.Fatalf("Should have written both batches, wrote %d keys", n)
+	}
+	if value, err := sharedDB.GetDatabase().Get([]byte("shared key")); err != nil {
+		t.Fatal(err)
+	} else if !bytes.Equal(value, []byte("shared value")) {
+		t.Fatalf("Shared database returned %s but should have returned %s", value, "shared value")
+	}
+	if value, err := chainDB.GetDatabase().Get([]byte("chain key")); err != nil {
+		t.Fatal(err)
+	} else if !bytes.Equal(value, []byte("chain value")) {
+		t.Fatalf("Chain database returned %s but should have returned %s", value, "chain value")
+	}
+}
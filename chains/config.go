@@ -0,0 +1,39 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chains
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// ConfigFileName is the name of the file, in a directory named after one of a
+// chain's aliases, whose contents are passed to the chain's VM
+const ConfigFileName = "config.json"
+
+// readChainConfig returns the contents of [dir]/[alias]/config.json for the
+// first of [aliases] that has such a file, and the path it was read from. If
+// [dir] is empty or none of the aliases have a config file, nil is returned.
+func readChainConfig(dir string, aliases []string) ([]byte, string, error) {
+	if dir == "" {
+		return nil, "", nil
+	}
+	for _, alias := range aliases {
+		path := filepath.Join(dir, alias, ConfigFileName)
+		config, err := ioutil.ReadFile(path)
+		switch {
+		case os.IsNotExist(err):
+			continue
+		case err != nil:
+			return nil, path, err
+		case !json.Valid(config):
+			return nil, path, fmt.Errorf("%s isn't valid JSON", path)
+		}
+		return config, path, nil
+	}
+	return nil, "", nil
+}
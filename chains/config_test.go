@@ -0,0 +1,76 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chains
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeChainConfig(t *testing.T, dir, alias, config string) {
+	if err := os.MkdirAll(filepath.Join(dir, alias), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, alias, ConfigFileName), []byte(config), 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReadChainConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "chain-configs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	writeChainConfig(t, dir, "X", `{"a":1}`)
+	writeChainConfig(t, dir, "avm", `{"b":2}`)
+
+	config, path, err := readChainConfig(dir, []string{"2vrXWHgGxh5n3YsLHMV16YVVJTpT4z45Fmb4y3bL6si8kLCyg9", "X", "avm"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(config) != `{"a":1}` {
+		t.Fatalf("Read the wrong config: %s", config)
+	}
+	if path != filepath.Join(dir, "X", ConfigFileName) {
+		t.Fatalf("Reported the wrong path: %s", path)
+	}
+}
+
+func TestReadChainConfigMissing(t *testing.T) {
+	dir, err := ioutil.TempDir("", "chain-configs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	config, _, err := readChainConfig(dir, []string{"X"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config != nil {
+		t.Fatalf("Shouldn't have read a config")
+	}
+
+	if config, _, err := readChainConfig("", []string{"X"}); err != nil || config != nil {
+		t.Fatalf("Shouldn't have read a config without a directory")
+	}
+}
+
+func TestReadChainConfigInvalid(t *testing.T) {
+	dir, err := ioutil.TempDir("", "chain-configs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	writeChainConfig(t, dir, "X", `{"a":`)
+
+	if _, _, err := readChainConfig(dir, []string{"X"}); err == nil {
+		t.Fatalf("Should have errored because the config isn't valid JSON")
+	}
+}
@@ -0,0 +1,123 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chains
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow"
+
+	avacon "github.com/ava-labs/gecko/snow/consensus/avalanche"
+	avaeng "github.com/ava-labs/gecko/snow/engine/avalanche"
+
+	smeng "github.com/ava-labs/gecko/snow/engine/snowman"
+)
+
+var (
+	errSnowmanDAGParams = errors.New("Snowman chains don't have parents or batch size parameters")
+)
+
+// tunableEngine is a consensus engine whose parameters can be replaced while
+// its chain runs
+type tunableEngine interface {
+	// parameters returns the consensus parameters in effect
+	parameters() avacon.Parameters
+
+	// setParameters schedules [params] to replace the consensus parameters
+	// between polls. Returns true if they were replaced immediately.
+	setParameters(params avacon.Parameters) (bool, error)
+}
+
+// avalancheTunable replaces the parameters of an Avalanche engine
+type avalancheTunable struct {
+	ctx    *snow.Context
+	engine *avaeng.Transitive
+}
+
+func (a *avalancheTunable) parameters() avacon.Parameters {
+	a.ctx.Lock.Lock()
+	defer a.ctx.Lock.Unlock()
+
+	return a.engine.Parameters()
+}
+
+func (a *avalancheTunable) setParameters(params avacon.Parameters) (bool, error) {
+	a.ctx.Lock.Lock()
+	defer a.ctx.Lock.Unlock()
+
+	return a.engine.SetParameters(params)
+}
+
+// snowmanTunable replaces the parameters of a Snowman engine. Snowman chains
+// are linear, so their parents and batch size parameters are always 0.
+type snowmanTunable struct {
+	ctx    *snow.Context
+	engine *smeng.Transitive
+}
+
+func (s *snowmanTunable) parameters() avacon.Parameters {
+	s.ctx.Lock.Lock()
+	defer s.ctx.Lock.Unlock()
+
+	return avacon.Parameters{Parameters: s.engine.Parameters()}
+}
+
+func (s *snowmanTunable) setParameters(params avacon.Parameters) (bool, error) {
+	if params.Parents != 0 || params.BatchSize != 0 {
+		return false, errSnowmanDAGParams
+	}
+
+	s.ctx.Lock.Lock()
+	defer s.ctx.Lock.Unlock()
+
+	return s.engine.SetParameters(params.Parameters)
+}
+
+// ConsensusParameters returns the consensus parameters in effect on the chain
+// [chainID]
+func (m *manager) ConsensusParameters(chainID ids.ID) (avacon.Parameters, error) {
+	engine, err := m.tunableEngine(chainID)
+	if err != nil {
+		return avacon.Parameters{}, err
+	}
+	return engine.parameters(), nil
+}
+
+// SetConsensusParameters schedules [params] to replace the consensus
+// parameters of the chain [chainID] once none of its polls are outstanding.
+// Returns true if the parameters were replaced immediately, rather than once
+// the chain's outstanding polls finish.
+func (m *manager) SetConsensusParameters(chainID ids.ID, params avacon.Parameters) (bool, error) {
+	engine, err := m.tunableEngine(chainID)
+	if err != nil {
+		return false, err
+	}
+	applied, err := engine.setParameters(params)
+	if err != nil {
+		return false, fmt.Errorf("invalid consensus parameters for chain %s: %w", chainID, err)
+	}
+	return applied, nil
+}
+
+func (m *manager) tunableEngine(chainID ids.ID) (tunableEngine, error) {
+	m.enginesLock.Lock()
+	defer m.enginesLock.Unlock()
+
+	engine, exists := m.engines[chainID.Key()]
+	if !exists {
+		return nil, fmt.Errorf("chain %s isn't running", chainID)
+	}
+	return engine, nil
+}
+
+// registerEngine allows the consensus parameters of the chain [chainID] to be
+// replaced through [engine]
+func (m *manager) registerEngine(chainID ids.ID, engine tunableEngine) {
+	m.enginesLock.Lock()
+	defer m.enginesLock.Unlock()
+
+	m.engines[chainID.Key()] = engine
+}
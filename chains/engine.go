@@ -0,0 +1,97 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chains
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ava-labs/gecko/snow/engine/avalanche"
+
+	smeng "github.com/ava-labs/gecko/snow/engine/snowman"
+)
+
+// EngineType is the consensus engine that wraps a chain's VM
+type EngineType byte
+
+// Consensus engines that may run a chain
+const (
+	// DefaultEngine picks the engine from the capabilities the VM declares.
+	// If the VM supports both engines, Avalanche is used.
+	DefaultEngine EngineType = iota
+
+	// AvalancheEngine runs the VM's transactions in a DAG
+	AvalancheEngine
+
+	// SnowmanEngine runs the VM as a linear chain of blocks
+	SnowmanEngine
+)
+
+func (e EngineType) String() string {
+	switch e {
+	case DefaultEngine:
+		return "default"
+	case AvalancheEngine:
+		return "avalanche"
+	case SnowmanEngine:
+		return "snowman"
+	default:
+		return fmt.Sprintf("unknown engine (%d)", e)
+	}
+}
+
+// Verify returns an error if [e] isn't a known engine
+func (e EngineType) Verify() error {
+	switch e {
+	case DefaultEngine, AvalancheEngine, SnowmanEngine:
+		return nil
+	default:
+		return fmt.Errorf("%s requested", e)
+	}
+}
+
+// ParseEngineType returns the engine named [name], as printed by String. The
+// empty name is the default engine.
+func ParseEngineType(name string) (EngineType, error) {
+	switch strings.ToLower(name) {
+	case "", DefaultEngine.String():
+		return DefaultEngine, nil
+	case AvalancheEngine.String():
+		return AvalancheEngine, nil
+	case SnowmanEngine.String():
+		return SnowmanEngine, nil
+	default:
+		return DefaultEngine, fmt.Errorf("unknown engine %q", name)
+	}
+}
+
+// selectEngine returns the engine that should run [vm], given that the chain
+// asked to be run by [requested]. An error is returned if [vm] doesn't
+// implement the interface required by the requested engine.
+func selectEngine(vm interface{}, requested EngineType) (EngineType, error) {
+	_, isDAG := vm.(avalanche.DAGVM)
+	_, isLinear := vm.(smeng.ChainVM)
+
+	switch requested {
+	case DefaultEngine:
+		switch {
+		case isDAG:
+			return AvalancheEngine, nil
+		case isLinear:
+			return SnowmanEngine, nil
+		}
+		return DefaultEngine, fmt.Errorf("the vm should have type avalanche.DAGVM or snowman.ChainVM")
+	case AvalancheEngine:
+		if !isDAG {
+			return DefaultEngine, fmt.Errorf("the %s engine requires the vm to have type avalanche.DAGVM", requested)
+		}
+	case SnowmanEngine:
+		if !isLinear {
+			return DefaultEngine, fmt.Errorf("the %s engine requires the vm to have type snowman.ChainVM", requested)
+		}
+	default:
+		return DefaultEngine, fmt.Errorf("%s requested", requested)
+	}
+	return requested, nil
+}
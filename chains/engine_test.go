@@ -0,0 +1,80 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chains
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow/consensus/snowman"
+
+	avaeng "github.com/ava-labs/gecko/snow/engine/avalanche"
+	smeng "github.com/ava-labs/gecko/snow/engine/snowman"
+)
+
+// dualVM implements both avalanche.DAGVM and snowman.ChainVM
+type dualVM struct{ avaeng.VMTest }
+
+func (*dualVM) BuildBlock(context.Context) (snowman.Block, error)         { return nil, nil }
+func (*dualVM) ParseBlock(context.Context, []byte) (snowman.Block, error) { return nil, nil }
+func (*dualVM) GetBlock(context.Context, ids.ID) (snowman.Block, error)   { return nil, nil }
+func (*dualVM) SetPreference(ids.ID)                                      {}
+func (*dualVM) LastAccepted() ids.ID                                      { return ids.ID{} }
+
+func TestSelectEngine(t *testing.T) {
+	tests := []struct {
+		vm        interface{}
+		requested EngineType
+		expected  EngineType
+		shouldErr bool
+	}{
+		{vm: &avaeng.VMTest{}, requested: DefaultEngine, expected: AvalancheEngine},
+		{vm: &avaeng.VMTest{}, requested: AvalancheEngine, expected: AvalancheEngine},
+		{vm: &avaeng.VMTest{}, requested: SnowmanEngine, shouldErr: true},
+		{vm: &smeng.VMTest{}, requested: DefaultEngine, expected: SnowmanEngine},
+		{vm: &smeng.VMTest{}, requested: SnowmanEngine, expected: SnowmanEngine},
+		{vm: &smeng.VMTest{}, requested: AvalancheEngine, shouldErr: true},
+		{vm: &dualVM{}, requested: DefaultEngine, expected: AvalancheEngine},
+		{vm: &dualVM{}, requested: AvalancheEngine, expected: AvalancheEngine},
+		{vm: &dualVM{}, requested: SnowmanEngine, expected: SnowmanEngine},
+		{vm: struct{}{}, requested: DefaultEngine, shouldErr: true},
+		{vm: &dualVM{}, requested: EngineType(255), shouldErr: true},
+	}
+	for i, test := range tests {
+		engine, err := selectEngine(test.vm, test.requested)
+		switch {
+		case test.shouldErr && err == nil:
+			t.Fatalf("test %d: should have errored", i)
+		case !test.shouldErr && err != nil:
+			t.Fatalf("test %d: unexpected error: %s", i, err)
+		case !test.shouldErr && engine != test.expected:
+			t.Fatalf("test %d: expected %s but got %s", i, test.expected, engine)
+		}
+	}
+}
+
+func TestParseEngineType(t *testing.T) {
+	for _, engine := range []EngineType{DefaultEngine, AvalancheEngine, SnowmanEngine} {
+		parsed, err := ParseEngineType(engine.String())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if parsed != engine {
+			t.Fatalf("expected %s but got %s", engine, parsed)
+		}
+		if err := parsed.Verify(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if engine, err := ParseEngineType(""); err != nil || engine != DefaultEngine {
+		t.Fatalf("the empty name should be the default engine")
+	}
+	if _, err := ParseEngineType("snowball"); err == nil {
+		t.Fatalf("should have errored on an unknown engine")
+	}
+	if err := EngineType(255).Verify(); err == nil {
+		t.Fatalf("should have errored on an unknown engine")
+	}
+}
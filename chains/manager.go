@@ -4,11 +4,14 @@
 package chains
 
 import (
+	"context"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/ava-labs/gecko/api"
 	"github.com/ava-labs/gecko/api/keystore"
+	"github.com/ava-labs/gecko/chains/atomic"
 	"github.com/ava-labs/gecko/database"
 	"github.com/ava-labs/gecko/database/prefixdb"
 	"github.com/ava-labs/gecko/ids"
@@ -73,17 +76,41 @@ type Manager interface {
 	// Add an alias to a chain
 	Alias(ids.ID, string) error
 
+	// Return every alias and the ID of the chain it refers to
+	ListAll() map[string]ids.ID
+
+	// Register [acceptor] under [identifier] to be sent, on its own goroutine,
+	// the containers accepted by the chain with the given ID, in the order they
+	// are accepted. The chain waits for an acceptor that falls behind, so the
+	// acceptor mustn't wait on the chain.
+	OnAccept(chainID ids.ID, identifier string, acceptor triggers.Acceptor) error
+
+	// Set the subnets this node validates. The chains of a subnet other than
+	// the default subnet are only run while this node validates the subnet.
+	SetValidatedSubnets(ids.Set)
+
+	// Return the consensus parameters in effect on the chain with the given ID
+	ConsensusParameters(chainID ids.ID) (avacon.Parameters, error)
+
+	// Schedule the consensus parameters of the chain with the given ID to be
+	// replaced between polls. Returns true if they were replaced immediately.
+	SetConsensusParameters(chainID ids.ID, params avacon.Parameters) (bool, error)
+
 	Shutdown()
 }
 
 // ChainParameters defines the chain being created
 type ChainParameters struct {
 	ID          ids.ID   // The ID of the chain being created
-	SubnetID    ids.ID   // ID of the subnet that validates this chain
+	SubnetID    ids.ID   // ID of the subnet that validates this chain. If empty, the default subnet
 	GenesisData []byte   // The genesis data of this chain's ledger
 	VMAlias     string   // The ID of the vm this chain is running
 	FxAliases   []string // The IDs of the feature extensions this chain is running
 
+	// The consensus engine that runs the VM. If DefaultEngine, the engine is
+	// chosen based on the interfaces the VM implements.
+	Engine EngineType
+
 	CustomBeacons validators.Set // Should only be set if the default beacons can't be used.
 }
 
@@ -109,6 +136,56 @@ type manager struct {
 	awaiter         Awaiter               // Waits for required connections before running bootstrapping
 	server          *api.Server           // Handles HTTP API calls
 	keystore        *keystore.Keystore
+	atomicMemory    *atomic.Memory // Shared by the chains to move state between each other
+
+	// Penalizes the validators that don't answer requests in time
+	reputation *networking.Reputation
+
+	// The number of accepted containers each accept hook remembers having
+	// handled, so that they aren't delivered again after a restart
+	acceptJournalRetention uint64
+
+	// The maximum number of Get messages each chain handles concurrently, if
+	// its VM allows it
+	getWorkers int
+
+	// The time between gossips of each chain's accepted frontier. If 0,
+	// accepted frontiers aren't gossiped.
+	frontierGossipFrequency time.Duration
+
+	// The maximum number of network polls each chain may have outstanding at
+	// once. If 0, the number of outstanding polls isn't limited.
+	maxOutstandingPolls int
+
+	// If true, Snowman chains whose VM supports it sync their state from a
+	// state summary returned by their beacons, rather than replaying every
+	// block from genesis
+	stateSync bool
+
+	// If true, chains only serve their APIs from the state in the database.
+	// They don't run a consensus engine, so they never change.
+	readOnly bool
+
+	// Directory holding a directory per chain alias, each of which may
+	// contain a config file passed to the chain's VM. If empty, chains don't
+	// have config files.
+	chainConfigDir string
+
+	// Chain ID --> the hooks notified of the chain's accepted containers
+	acceptHooksLock sync.Mutex
+	acceptHooks     map[[32]byte]*common.AcceptHooks
+
+	// Chain ID --> the engine whose consensus parameters can be replaced
+	enginesLock sync.Mutex
+	engines     map[[32]byte]tunableEngine
+
+	// The subnets this node validates, the chains waiting for this node to
+	// validate their subnet and the chains running for each subnet other than
+	// the default subnet. Subnet ID --> chains.
+	subnetsLock      sync.Mutex
+	validatedSubnets ids.Set
+	waitingChains    map[[32]byte][]ChainParameters
+	subnetChains     map[[32]byte][]ids.ID
 
 	unblocked     bool
 	blockedChains []ChainParameters
@@ -128,6 +205,7 @@ func New(
 	db database.Database,
 	router router.Router,
 	sender sender.ExternalSender,
+	reputation *networking.Reputation,
 	consensusParams avacon.Parameters,
 	validators validators.Manager,
 	nodeID ids.ShortID,
@@ -135,6 +213,14 @@ func New(
 	awaiter Awaiter,
 	server *api.Server,
 	keystore *keystore.Keystore,
+	atomicMemory *atomic.Memory,
+	acceptJournalRetention uint64,
+	getWorkers int,
+	frontierGossipFrequency time.Duration,
+	maxOutstandingPolls int,
+	stateSync bool,
+	readOnly bool,
+	chainConfigDir string,
 ) Manager {
 	timeoutManager := timeout.Manager{}
 	timeoutManager.Initialize(requestTimeout)
@@ -143,22 +229,39 @@ func New(
 	router.Initialize(log, &timeoutManager)
 
 	m := &manager{
-		log:             log,
-		logFactory:      logFactory,
-		vmManager:       vmManager,
-		decisionEvents:  decisionEvents,
-		consensusEvents: consensusEvents,
-		db:              db,
-		chainRouter:     router,
-		sender:          sender,
-		timeoutManager:  &timeoutManager,
-		consensusParams: consensusParams,
-		validators:      validators,
-		nodeID:          nodeID,
-		networkID:       networkID,
-		awaiter:         awaiter,
-		server:          server,
-		keystore:        keystore,
+		log:                     log,
+		logFactory:              logFactory,
+		vmManager:               vmManager,
+		decisionEvents:          decisionEvents,
+		consensusEvents:         consensusEvents,
+		db:                      db,
+		chainRouter:             router,
+		sender:                  sender,
+		timeoutManager:          &timeoutManager,
+		reputation:              reputation,
+		consensusParams:         consensusParams,
+		validators:              validators,
+		nodeID:                  nodeID,
+		networkID:               networkID,
+		awaiter:                 awaiter,
+		server:                  server,
+		keystore:                keystore,
+		atomicMemory:            atomicMemory,
+		acceptJournalRetention:  acceptJournalRetention,
+		getWorkers:              getWorkers,
+		frontierGossipFrequency: frontierGossipFrequency,
+		maxOutstandingPolls:     maxOutstandingPolls,
+		stateSync:               stateSync,
+		readOnly:                readOnly,
+		chainConfigDir:          chainConfigDir,
+		acceptHooks:             make(map[[32]byte]*common.AcceptHooks),
+		engines:                 make(map[[32]byte]tunableEngine),
+		waitingChains:           make(map[[32]byte][]ChainParameters),
+		subnetChains:            make(map[[32]byte][]ids.ID),
+
+		// Chains are usually created once the Platform Chain is bootstrapped,
+		// which never happens if chains don't run consensus
+		unblocked: readOnly,
 	}
 	m.Initialize()
 	return m
@@ -169,6 +272,10 @@ func (m *manager) Router() router.Router { return m.chainRouter }
 
 // Create a chain
 func (m *manager) CreateChain(chain ChainParameters) {
+	if m.waitForSubnet(chain) {
+		m.log.Info("chain %s will be created once this node validates subnet %s", chain.ID, chain.SubnetID)
+		return
+	}
 	if !m.unblocked {
 		m.blockedChains = append(m.blockedChains, chain)
 	} else {
@@ -180,9 +287,11 @@ func (m *manager) CreateChain(chain ChainParameters) {
 func (m *manager) ForceCreateChain(chain ChainParameters) {
 	m.log.Info("creating chain:\n"+
 		"    ID: %s\n"+
-		"    VMID:%s",
+		"    VMID:%s\n"+
+		"    Engine: %s",
 		chain.ID,
 		chain.VMAlias,
+		chain.Engine,
 	)
 
 	// Assert that there isn't already a chain with an alias in [chain].Aliases
@@ -237,26 +346,53 @@ func (m *manager) ForceCreateChain(chain ChainParameters) {
 		return
 	}
 
+	// The chain's config is looked up by each of its aliases, then its ID
+	aliases := append(m.Aliases(chain.ID), chain.ID.String())
+	chainConfig, configPath, err := readChainConfig(m.chainConfigDir, aliases)
+	if err != nil {
+		m.log.Error("error while reading chain's config: %s", err)
+		return
+	}
+	if chainConfig != nil {
+		m.log.Info("passing %s to chain %s", configPath, chain.ID)
+	}
+
 	ctx := &snow.Context{
 		NetworkID:           m.networkID,
 		ChainID:             chain.ID,
+		Config:              chainConfig,
 		Log:                 chainLog,
 		DecisionDispatcher:  m.decisionEvents,
 		ConsensusDispatcher: m.consensusEvents,
 		NodeID:              m.nodeID,
 		HTTP:                m.server,
 		Keystore:            m.keystore.NewBlockchainKeyStore(chain.ID),
+		SharedMemory:        m.atomicMemory.NewBlockchainMemory(chain.ID),
 		BCLookup:            m,
 	}
+	// Auxiliary components are notified of accepted containers through the
+	// chain's accept hooks rather than through the VM
+	journalDB := prefixdb.New([]byte("acceptJournal"), prefixdb.New(chain.ID.Bytes(), m.db))
+	journal := common.NewAcceptJournal(journalDB, m.acceptJournalRetention)
+	hooks := &common.AcceptHooks{}
+	hooks.Initialize(chainLog, chain.ID, common.DefaultAcceptHookQueueSize, journal)
+	if err := m.consensusEvents.RegisterChain(chain.ID, "acceptHooks", hooks); err != nil {
+		m.log.Error("error while registering the chain's accept hooks %s", err)
+		return
+	}
+	m.acceptHooksLock.Lock()
+	m.acceptHooks[chain.ID.Key()] = hooks
+	m.acceptHooksLock.Unlock()
+
 	consensusParams := m.consensusParams
 	if alias, err := m.PrimaryAlias(ctx.ChainID); err == nil {
-		consensusParams.Namespace = fmt.Sprintf("gecko_%s", alias)
+		consensusParams.Namespace = alias
 	} else {
-		consensusParams.Namespace = fmt.Sprintf("gecko_%s", ctx.ChainID)
+		consensusParams.Namespace = ctx.ChainID.String()
 	}
 
 	// The validators of this blockchain
-	validators, ok := m.validators.GetValidatorSet(ids.Empty) // TODO: Change argument to chain.SubnetID
+	validators, ok := m.validators.GetValidatorSet(chain.SubnetID)
 	if !ok {
 		m.log.Error("couldn't get validator set of subnet with ID %s. The subnet may not exist", chain.SubnetID)
 		return
@@ -267,14 +403,21 @@ func (m *manager) ForceCreateChain(chain ChainParameters) {
 		beacons = chain.CustomBeacons
 	}
 
-	switch vm := vm.(type) {
-	case avalanche.DAGVM:
+	engineType, err := selectEngine(vm, chain.Engine)
+	if err != nil {
+		m.log.Error("%s. Chain not created", err)
+		return
+	}
+
+	switch engineType {
+	case AvalancheEngine:
 		err := m.createAvalancheChain(
 			ctx,
 			chain.GenesisData,
 			validators,
 			beacons,
-			vm,
+			hooks,
+			vm.(avalanche.DAGVM),
 			fxs,
 			consensusParams,
 		)
@@ -282,13 +425,14 @@ func (m *manager) ForceCreateChain(chain ChainParameters) {
 			m.log.Error("error while creating new avalanche vm %s", err)
 			return
 		}
-	case smeng.ChainVM:
+	case SnowmanEngine:
 		err := m.createSnowmanChain(
 			ctx,
 			chain.GenesisData,
 			validators,
 			beacons,
-			vm,
+			hooks,
+			vm.(smeng.ChainVM),
 			fxs,
 			consensusParams.Parameters,
 		)
@@ -296,14 +440,18 @@ func (m *manager) ForceCreateChain(chain ChainParameters) {
 			m.log.Error("error while creating new snowman vm %s", err)
 			return
 		}
-	default:
-		m.log.Error("the vm should have type avalanche.DAGVM or snowman.ChainVM. Chain not created")
-		return
 	}
 
 	// Associate the newly created chain with its default alias
 	m.log.AssertNoError(m.Alias(chain.ID, chain.ID.String()))
 
+	if !chain.SubnetID.IsZero() {
+		m.subnetsLock.Lock()
+		subnetKey := chain.SubnetID.Key()
+		m.subnetChains[subnetKey] = append(m.subnetChains[subnetKey], chain.ID)
+		m.subnetsLock.Unlock()
+	}
+
 	// Notify those that registered to be notified when a new chain is created
 	m.notifyRegistrants(ctx, vm)
 }
@@ -326,6 +474,7 @@ func (m *manager) createAvalancheChain(
 	genesisData []byte,
 	validators,
 	beacons validators.Set,
+	hooks *common.AcceptHooks,
 	vm avalanche.DAGVM,
 	fxs []*common.Fx,
 	consensusParams avacon.Parameters,
@@ -338,6 +487,7 @@ func (m *manager) createAvalancheChain(
 	vertexDB := prefixdb.New([]byte("vertex"), db)
 	vertexBootstrappingDB := prefixdb.New([]byte("vertex_bootstrapping"), db)
 	txBootstrappingDB := prefixdb.New([]byte("tx_bootstrapping"), db)
+	checkpointDB := prefixdb.New([]byte("checkpoint"), db)
 
 	vtxBlocker, err := queue.New(vertexBootstrappingDB)
 	if err != nil {
@@ -355,15 +505,26 @@ func (m *manager) createAvalancheChain(
 	if err := vm.Initialize(ctx, vmDB, genesisData, msgChan, fxs); err != nil {
 		return err
 	}
+	if m.readOnly {
+		return nil
+	}
 
 	// Handles serialization/deserialization of vertices and also the
 	// persistence of vertices
 	vtxState := &state.Serializer{}
 	vtxState.Initialize(ctx, vm, vertexDB)
 
+	// Cancelled when the chain starts shutting down, which stops the engine's
+	// calls into the VM and the messages it sends
+	shutdownCtx, cancel := context.WithCancel(context.Background())
+
 	// Passes messages from the consensus engine to the network
 	sender := sender.Sender{}
-	sender.Initialize(ctx, m.sender, m.chainRouter, m.timeoutManager)
+	sender.Initialize(ctx, m.sender, m.chainRouter, m.timeoutManager, m.reputation, shutdownCtx)
+
+	// Bootstrapping requires a majority of the beacons' stake. This is written
+	// to avoid overflowing when the beacons' stake is near the max uint64
+	beaconWeight := beacons.Weight()
 
 	// The engine handles consensus
 	engine := avaeng.Transitive{
@@ -379,24 +540,35 @@ func (m *manager) createAvalancheChain(
 	engine.Initialize(avaeng.Config{
 		BootstrapConfig: avaeng.BootstrapConfig{
 			Config: common.Config{
-				Context:    ctx,
-				Validators: validators,
-				Beacons:    beacons,
-				Alpha:      (beacons.Len() + 1) / 2,
-				Sender:     &sender,
+				Context:        ctx,
+				Validators:     validators,
+				Beacons:        beacons,
+				Alpha:          beaconWeight/2 + beaconWeight%2,
+				Sender:         &sender,
+				AcceptHooks:    hooks,
+				Ctx:            shutdownCtx,
+				RequestTimeout: requestTimeout,
 			},
 			VtxBlocked: vtxBlocker,
 			TxBlocked:  txBlocker,
 			State:      vtxState,
 			VM:         vm,
 		},
-		Params:    consensusParams,
-		Consensus: &avacon.Topological{},
+		Params:              consensusParams,
+		Consensus:           &avacon.Topological{},
+		Checkpoints:         checkpointDB,
+		MaxOutstandingPolls: m.maxOutstandingPolls,
+	})
+	m.registerEngine(ctx.ChainID, &avalancheTunable{
+		ctx:    ctx,
+		engine: &engine,
 	})
 
 	// Asynchronously passes messages from the network to the consensus engine
 	handler := &handler.Handler{}
-	handler.Initialize(&engine, msgChan, defaultChannelSize)
+	// The avalanche engine's vertex state can't be read concurrently, so every
+	// message is handled in order
+	handler.Initialize(&engine, msgChan, defaultChannelSize, 0, m.frontierGossipFrequency, cancel, consensusParams.Namespace, consensusParams.Metrics)
 
 	// Allows messages to be routed to the new chain
 	m.chainRouter.AddChain(handler)
@@ -425,6 +597,7 @@ func (m *manager) createSnowmanChain(
 	genesisData []byte,
 	validators,
 	beacons validators.Set,
+	hooks *common.AcceptHooks,
 	vm smeng.ChainVM,
 	fxs []*common.Fx,
 	consensusParams snowball.Parameters,
@@ -449,38 +622,64 @@ func (m *manager) createSnowmanChain(
 	if err := vm.Initialize(ctx, vmDB, genesisData, msgChan, fxs); err != nil {
 		return err
 	}
+	if m.readOnly {
+		return nil
+	}
+
+	// Cancelled when the chain starts shutting down, which stops the engine's
+	// calls into the VM and the messages it sends
+	shutdownCtx, cancel := context.WithCancel(context.Background())
 
 	// Passes messages from the consensus engine to the network
 	sender := sender.Sender{}
-	sender.Initialize(ctx, m.sender, m.chainRouter, m.timeoutManager)
+	sender.Initialize(ctx, m.sender, m.chainRouter, m.timeoutManager, m.reputation, shutdownCtx)
+
+	// Bootstrapping requires a majority of the beacons' stake. This is written
+	// to avoid overflowing when the beacons' stake is near the max uint64
+	beaconWeight := beacons.Weight()
 
 	// The engine handles consensus
 	engine := smeng.Transitive{}
 	engine.Initialize(smeng.Config{
 		BootstrapConfig: smeng.BootstrapConfig{
 			Config: common.Config{
-				Context:    ctx,
-				Validators: validators,
-				Beacons:    beacons,
-				Alpha:      (beacons.Len() + 1) / 2,
-				Sender:     &sender,
+				Context:        ctx,
+				Validators:     validators,
+				Beacons:        beacons,
+				Alpha:          beaconWeight/2 + beaconWeight%2,
+				Sender:         &sender,
+				AcceptHooks:    hooks,
+				Ctx:            shutdownCtx,
+				RequestTimeout: requestTimeout,
 			},
 			Blocked:      blocked,
 			VM:           vm,
 			Bootstrapped: m.unblockChains,
+			StateSync:    m.stateSync,
 		},
-		Params:    consensusParams,
-		Consensus: &smcon.Topological{},
+		Params:              consensusParams,
+		Consensus:           &smcon.Topological{},
+		MaxOutstandingPolls: m.maxOutstandingPolls,
+	})
+	m.registerEngine(ctx.ChainID, &snowmanTunable{
+		ctx:    ctx,
+		engine: &engine,
 	})
 
 	// Asynchronously passes messages from the network to the consensus engine
 	handler := &handler.Handler{}
-	handler.Initialize(&engine, msgChan, defaultChannelSize)
+	handler.Initialize(&engine, msgChan, defaultChannelSize, m.getWorkers, m.frontierGossipFrequency, cancel, consensusParams.Namespace, consensusParams.Metrics)
 
 	// Allow incoming messages to be routed to the new chain
 	m.chainRouter.AddChain(handler)
 	go ctx.Log.RecoverAndPanic(handler.Dispatch)
 
+	// Expose the engine's consensus state, such as the confidence in
+	// processing blocks
+	if err := m.server.AddRoute(smeng.NewService(&engine), &ctx.Lock, "bc/"+ctx.ChainID.String(), "/snowman", ctx.Log); err != nil {
+		ctx.Log.Error("failed to add the Snowman API: %s", err)
+	}
+
 	awaiting := &networking.AwaitingConnections{
 		Finish: func() {
 			ctx.Lock.Lock()
@@ -497,12 +696,116 @@ func (m *manager) createSnowmanChain(
 	return nil
 }
 
+// OnAccept registers [acceptor] with the accept hooks of the chain [chainID]
+func (m *manager) OnAccept(chainID ids.ID, identifier string, acceptor triggers.Acceptor) error {
+	m.acceptHooksLock.Lock()
+	hooks, exists := m.acceptHooks[chainID.Key()]
+	m.acceptHooksLock.Unlock()
+
+	if !exists {
+		return fmt.Errorf("chain %s doesn't exist", chainID)
+	}
+	return hooks.OnAccept(identifier, acceptor)
+}
+
 // Shutdown stops all the chains
-func (m *manager) Shutdown() { m.chainRouter.Shutdown() }
+func (m *manager) Shutdown() {
+	m.chainRouter.Shutdown()
+
+	m.acceptHooksLock.Lock()
+	defer m.acceptHooksLock.Unlock()
+
+	for _, hooks := range m.acceptHooks {
+		hooks.Shutdown()
+	}
+}
+
+// Returns true if [chain] belongs to a subnet, other than the default subnet,
+// that this node doesn't validate. If so, [chain] is held until this node
+// validates the subnet.
+func (m *manager) waitForSubnet(chain ChainParameters) bool {
+	if chain.SubnetID.IsZero() {
+		return false
+	}
+
+	m.subnetsLock.Lock()
+	defer m.subnetsLock.Unlock()
+
+	if m.validatedSubnets.Contains(chain.SubnetID) {
+		return false
+	}
+	subnetKey := chain.SubnetID.Key()
+	m.waitingChains[subnetKey] = append(m.waitingChains[subnetKey], chain)
+	return true
+}
+
+// SetValidatedSubnets creates the chains of the subnets this node joined and
+// stops the chains of the subnets this node left
+func (m *manager) SetValidatedSubnets(subnetIDs ids.Set) {
+	m.subnetsLock.Lock()
+	joined := []ChainParameters(nil)
+	for subnetKey, chains := range m.waitingChains {
+		if subnetIDs.Contains(ids.NewID(subnetKey)) {
+			joined = append(joined, chains...)
+			delete(m.waitingChains, subnetKey)
+		}
+	}
+	left := []ids.ID(nil)
+	for subnetKey, chainIDs := range m.subnetChains {
+		if !subnetIDs.Contains(ids.NewID(subnetKey)) {
+			left = append(left, chainIDs...)
+			delete(m.subnetChains, subnetKey)
+		}
+	}
+	m.validatedSubnets = ids.Set{}
+	m.validatedSubnets.Union(subnetIDs)
+	m.subnetsLock.Unlock()
+
+	for _, chainID := range left {
+		// The chain's aliases and API handlers stay registered, so it's only
+		// run again once this node restarts
+		m.log.Info("stopping chain %s because this node stopped validating its subnet", chainID)
+		m.stopChain(chainID)
+	}
+	for _, chain := range joined {
+		m.CreateChain(chain)
+	}
+}
+
+// Stop routing messages to the chain [chainID] and shut it down
+func (m *manager) stopChain(chainID ids.ID) {
+	m.chainRouter.RemoveChain(chainID)
+
+	m.acceptHooksLock.Lock()
+	hooks, exists := m.acceptHooks[chainID.Key()]
+	delete(m.acceptHooks, chainID.Key())
+	m.acceptHooksLock.Unlock()
+
+	m.enginesLock.Lock()
+	delete(m.engines, chainID.Key())
+	m.enginesLock.Unlock()
+
+	if exists {
+		if err := m.consensusEvents.DeregisterChain(chainID, "acceptHooks"); err != nil {
+			m.log.Warn("couldn't deregister the accept hooks of chain %s: %s", chainID, err)
+		}
+		hooks.Shutdown()
+	}
+}
 
 // LookupVM returns the ID of the VM associated with an alias
 func (m *manager) LookupVM(alias string) (ids.ID, error) { return m.vmManager.Lookup(alias) }
 
+// Alias gives the chain [id] the alias [alias]. Conflicting aliases are logged
+// along with the chain that already has the alias.
+func (m *manager) Alias(id ids.ID, alias string) error {
+	if err := m.Aliaser.Alias(id, alias); err != nil {
+		m.log.Warn("couldn't alias chain %s: %s", id, err)
+		return err
+	}
+	return nil
+}
+
 // Notify registrants [those who want to know about the creation of chains]
 // that the specified chain has been created
 func (m *manager) notifyRegistrants(ctx *snow.Context, vm interface{}) {
@@ -0,0 +1,240 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package dbbench measures how a database performs under workloads like the
+// ones a node puts it under: small random reads, batched writes and short
+// iterator scans.
+package dbbench
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/ava-labs/gecko/database"
+	"github.com/ava-labs/gecko/utils/hashing"
+	"github.com/ava-labs/gecko/utils/logging"
+)
+
+const (
+	// loadBatchSize is the number of keys written in each batch while the
+	// database is loaded
+	loadBatchSize = 1000
+
+	// Percentages of the operations of the mixed workload that are reads and
+	// batched writes. The rest are scans.
+	mixedReadPercent  = 80
+	mixedWritePercent = 15
+)
+
+var (
+	errNoKeys       = errors.New("the benchmark needs at least one key")
+	errNoBatchSize  = errors.New("batches must hold at least one write")
+	errNoScanLength = errors.New("scans must read at least one key")
+	errNoDuration   = errors.New("workloads must run for a positive duration")
+)
+
+// Config of a benchmark
+type Config struct {
+	// Number of keys written to the database before the workloads run. Reads,
+	// writes and scans pick keys among them.
+	Keys int
+
+	// Size of each value, in bytes. Keys are 32 byte hashes, as most of a
+	// node's keys are IDs.
+	ValueSize int
+
+	// Number of puts written in each batch
+	BatchSize int
+
+	// Number of keys read by each scan
+	ScanLength int
+
+	// How long each workload runs. Long durations turn the benchmark into a
+	// soak test.
+	Duration time.Duration
+
+	// Seed of the random choice of keys and values, so runs can be repeated
+	Seed int64
+}
+
+// Verify returns an error if this config can't be run
+func (c *Config) Verify() error {
+	switch {
+	case c.Keys <= 0:
+		return errNoKeys
+	case c.BatchSize <= 0:
+		return errNoBatchSize
+	case c.ScanLength <= 0:
+		return errNoScanLength
+	case c.Duration <= 0:
+		return errNoDuration
+	default:
+		return nil
+	}
+}
+
+// Result of running a workload
+type Result struct {
+	Workload string
+	// Number of operations performed. A batched write or a scan is one
+	// operation.
+	Ops      int
+	Duration time.Duration
+
+	P50, P90, P99, Max time.Duration
+}
+
+// OpsPerSecond returns the throughput of the workload
+func (r *Result) OpsPerSecond() float64 {
+	if r.Duration <= 0 {
+		return 0
+	}
+	return float64(r.Ops) / r.Duration.Seconds()
+}
+
+func (r *Result) String() string {
+	return fmt.Sprintf("%s: %d ops in %s (%.0f ops/s), latency p50 %s p90 %s p99 %s max %s",
+		r.Workload, r.Ops, r.Duration, r.OpsPerSecond(), r.P50, r.P90, r.P99, r.Max)
+}
+
+// Bench runs workloads against a database
+type Bench struct {
+	Log    logging.Logger
+	DB     database.Database
+	Config Config
+
+	rng *rand.Rand
+}
+
+// Run loads the database with Config.Keys keys and then runs each workload for
+// Config.Duration. The database is left holding the keys it was loaded with.
+func (b *Bench) Run() ([]*Result, error) {
+	if err := b.Config.Verify(); err != nil {
+		return nil, err
+	}
+	b.rng = rand.New(rand.NewSource(b.Config.Seed))
+
+	b.Log.Info("loading the database with %d keys", b.Config.Keys)
+	if err := b.load(); err != nil {
+		return nil, fmt.Errorf("couldn't load the database: %w", err)
+	}
+
+	workloads := []struct {
+		name string
+		op   func() error
+	}{
+		{"read", b.read},
+		{"write", b.write},
+		{"scan", b.scan},
+		{"mixed", b.mixed},
+	}
+	results := make([]*Result, 0, len(workloads))
+	for _, workload := range workloads {
+		b.Log.Info("running the %s workload for %s", workload.name, b.Config.Duration)
+		result, err := b.run(workload.name, workload.op)
+		if err != nil {
+			return nil, fmt.Errorf("%s workload failed: %w", workload.name, err)
+		}
+		b.Log.Info("%s", result)
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// run [op] until Config.Duration has passed
+func (b *Bench) run(name string, op func() error) (*Result, error) {
+	latencies := newRecorder(b.rng)
+	start := time.Now()
+	end := start.Add(b.Config.Duration)
+	for opStart := start; opStart.Before(end); opStart = time.Now() {
+		if err := op(); err != nil {
+			return nil, err
+		}
+		latencies.Record(time.Since(opStart))
+	}
+	return &Result{
+		Workload: name,
+		Ops:      latencies.count,
+		Duration: time.Since(start),
+		P50:      latencies.Percentile(50),
+		P90:      latencies.Percentile(90),
+		P99:      latencies.Percentile(99),
+		Max:      latencies.max,
+	}, nil
+}
+
+// load writes every key to the database
+func (b *Bench) load() error {
+	batch := b.DB.NewBatch()
+	for i := 0; i < b.Config.Keys; i++ {
+		if err := batch.Put(key(i), b.value()); err != nil {
+			return err
+		}
+		if (i+1)%loadBatchSize != 0 {
+			continue
+		}
+		if err := batch.Write(); err != nil {
+			return err
+		}
+		batch.Reset()
+	}
+	return batch.Write()
+}
+
+// read a random key
+func (b *Bench) read() error {
+	_, err := b.DB.Get(b.randomKey())
+	return err
+}
+
+// write a batch of random keys
+func (b *Bench) write() error {
+	batch := b.DB.NewBatch()
+	for i := 0; i < b.Config.BatchSize; i++ {
+		if err := batch.Put(b.randomKey(), b.value()); err != nil {
+			return err
+		}
+	}
+	return batch.Write()
+}
+
+// scan Config.ScanLength keys, starting at a random key
+func (b *Bench) scan() error {
+	it := b.DB.NewIteratorWithStart(b.randomKey())
+	defer it.Release()
+
+	for i := 0; i < b.Config.ScanLength && it.Next(); i++ {
+		_ = it.Value()
+	}
+	return it.Error()
+}
+
+// mixed performs a read, a batched write or a scan
+func (b *Bench) mixed() error {
+	switch n := b.rng.Intn(100); {
+	case n < mixedReadPercent:
+		return b.read()
+	case n < mixedReadPercent+mixedWritePercent:
+		return b.write()
+	default:
+		return b.scan()
+	}
+}
+
+func (b *Bench) randomKey() []byte { return key(b.rng.Intn(b.Config.Keys)) }
+
+func (b *Bench) value() []byte {
+	value := make([]byte, b.Config.ValueSize)
+	_, _ = b.rng.Read(value)
+	return value
+}
+
+// key returns the [i]th key, which is spread across the key space like an ID
+func key(i int) []byte {
+	index := make([]byte, 8)
+	binary.BigEndian.PutUint64(index, uint64(i))
+	return hashing.ComputeHash256(index)
+}
@@ -0,0 +1,106 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package dbbench
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/gecko/database/memdb"
+	"github.com/ava-labs/gecko/utils/logging"
+)
+
+func TestBenchRun(t *testing.T) {
+	db := memdb.New()
+	b := Bench{
+		Log: logging.NoLog{},
+		DB:  db,
+		Config: Config{
+			Keys:       2500,
+			ValueSize:  64,
+			BatchSize:  10,
+			ScanLength: 20,
+			Duration:   10 * time.Millisecond,
+		},
+	}
+
+	results, err := b.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 4 {
+		t.Fatalf("Expected 4 workloads but got %d", len(results))
+	}
+	for _, result := range results {
+		switch {
+		case result.Ops == 0:
+			t.Fatalf("%s workload didn't perform any operations", result.Workload)
+		case result.Duration < b.Config.Duration:
+			t.Fatalf("%s workload stopped early", result.Workload)
+		case result.P50 > result.P90, result.P90 > result.P99, result.P99 > result.Max:
+			t.Fatalf("%s workload has unordered percentiles: %s", result.Workload, result)
+		}
+	}
+
+	// Every loaded key, including the ones in the last partial batch, should
+	// have been written
+	for _, i := range []int{0, 1999, 2499} {
+		if has, err := db.Has(key(i)); err != nil {
+			t.Fatal(err)
+		} else if !has {
+			t.Fatalf("Key %d wasn't loaded", i)
+		}
+	}
+}
+
+func TestBenchInvalidConfig(t *testing.T) {
+	b := Bench{
+		Log: logging.NoLog{},
+		DB:  memdb.New(),
+		Config: Config{
+			Keys:       1,
+			BatchSize:  1,
+			ScanLength: 1,
+		},
+	}
+	if _, err := b.Run(); err != errNoDuration {
+		t.Fatalf("Expected %s but got %v", errNoDuration, err)
+	}
+}
+
+func TestRecorderPercentile(t *testing.T) {
+	r := newRecorder(rand.New(rand.NewSource(0)))
+	if p := r.Percentile(50); p != 0 {
+		t.Fatalf("Empty recorder should report 0 but got %s", p)
+	}
+
+	for i := 100; i > 0; i-- {
+		r.Record(time.Duration(i))
+	}
+	if p := r.Percentile(50); p != 50 {
+		t.Fatalf("Expected p50 of 50 but got %d", p)
+	}
+	if p := r.Percentile(99); p != 99 {
+		t.Fatalf("Expected p99 of 99 but got %d", p)
+	}
+	if p := r.Percentile(100); p != 100 {
+		t.Fatalf("Expected p100 of 100 but got %d", p)
+	}
+}
+
+func TestRecorderSampling(t *testing.T) {
+	r := newRecorder(rand.New(rand.NewSource(0)))
+	for i := 0; i < 2*maxSamples; i++ {
+		r.Record(time.Duration(i))
+	}
+	switch {
+	case r.count != 2*maxSamples:
+		t.Fatalf("Should have counted every operation")
+	case len(r.samples) != maxSamples:
+		t.Fatalf("Should have kept %d samples but kept %d", maxSamples, len(r.samples))
+	case r.max != time.Duration(2*maxSamples-1):
+		t.Fatalf("Should have kept the exact maximum")
+	}
+}
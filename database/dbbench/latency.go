@@ -0,0 +1,65 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package dbbench
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"time"
+)
+
+// maxSamples is the number of latencies kept by a recorder. Long soak runs
+// perform far more operations than can be kept, so a uniform sample of them is
+// kept instead.
+const maxSamples = 1 << 16
+
+// recorder keeps a uniform sample of the latencies of a workload's operations
+type recorder struct {
+	rng     *rand.Rand
+	count   int
+	max     time.Duration
+	samples []time.Duration
+}
+
+func newRecorder(rng *rand.Rand) *recorder {
+	return &recorder{rng: rng}
+}
+
+// Record the latency of an operation
+func (r *recorder) Record(latency time.Duration) {
+	r.count++
+	if latency > r.max {
+		r.max = latency
+	}
+	if len(r.samples) < maxSamples {
+		r.samples = append(r.samples, latency)
+		return
+	}
+	// Reservoir sampling: the i-th operation replaces a sample with
+	// probability maxSamples/i
+	if i := r.rng.Intn(r.count); i < maxSamples {
+		r.samples[i] = latency
+	}
+}
+
+// Percentile returns the latency that [p] percent of the sampled operations
+// completed within. Returns 0 if no operations were recorded.
+func (r *recorder) Percentile(p float64) time.Duration {
+	if len(r.samples) == 0 {
+		return 0
+	}
+	sorted := make([]time.Duration, len(r.samples))
+	copy(sorted, r.samples)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	index := int(math.Ceil(p/100*float64(len(sorted)))) - 1
+	switch {
+	case index < 0:
+		index = 0
+	case index >= len(sorted):
+		index = len(sorted) - 1
+	}
+	return sorted[index]
+}
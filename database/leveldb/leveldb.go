@@ -64,6 +64,32 @@ func New(file string, blockCacheSize, writeBufferSize, handleCap int) (*Database
 	return &Database{DB: db}, nil
 }
 
+// NewReadOnly returns a wrapped LevelDB object that can only be read from. The
+// database's files aren't modified, so it may be opened from a copy of another
+// node's database directory. Writes to it return an error.
+func NewReadOnly(file string, blockCacheSize, handleCap int) (*Database, error) {
+	// Enforce minimums
+	if blockCacheSize < minBlockCacheSize {
+		blockCacheSize = minBlockCacheSize
+	}
+	if handleCap < minHandleCap {
+		handleCap = minHandleCap
+	}
+
+	// Corruptions can't be recovered from without modifying the files
+	db, err := leveldb.OpenFile(file, &opt.Options{
+		OpenFilesCacheCapacity: handleCap,
+		BlockCacheCapacity:     blockCacheSize,
+		Filter:                 filter.NewBloomFilter(10),
+		ErrorIfMissing:         true,
+		ReadOnly:               true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &Database{DB: db}, nil
+}
+
 // Has returns if the key is set in the database
 func (db *Database) Has(key []byte) (bool, error) {
 	has, err := db.DB.Has(key, nil)
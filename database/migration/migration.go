@@ -0,0 +1,211 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package migration
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ava-labs/gecko/database"
+	"github.com/ava-labs/gecko/database/prefixdb"
+	"github.com/ava-labs/gecko/database/versiondb"
+	"github.com/ava-labs/gecko/utils/logging"
+)
+
+const (
+	// backupBatchSize is the number of bytes written to the backup database in
+	// each batch
+	backupBatchSize = 1 << 20
+
+	// backupProgressInterval is the number of keys copied to the backup
+	// database between progress reports
+	backupProgressInterval = 100000
+)
+
+var (
+	versionPrefix = []byte("migration")
+	versionKey    = []byte("version")
+
+	errMigrationRequired = errors.New("database must be migrated with \"gecko migrate\" before the node can run")
+	errDatabaseTooNew    = errors.New("database was written by a newer version of the node")
+	errBadVersion        = errors.New("database version is malformed")
+	errMigrationOrder    = errors.New("migrations must be ordered by consecutive versions starting at 1")
+)
+
+// Migration upgrades the on-disk format of a database from Version-1 to
+// Version
+type Migration struct {
+	Version     uint32
+	Description string
+
+	// Migrate rewrites [db] into the format of Version. Changes made to [db]
+	// are kept in memory and only written to disk once every pending migration
+	// has succeeded, so Migrate may fail at any point without corrupting the
+	// database.
+	Migrate func(db database.Database, log logging.Logger) error
+}
+
+// Migrations are the migrations between every database version. The database
+// version written by this node is the version of the last migration.
+var Migrations = []Migration{}
+
+// CurrentVersion returns the version of the database written by this node
+func CurrentVersion() uint32 { return uint32(len(Migrations)) }
+
+// GetVersion returns the version of [db]. A database that has never been
+// versioned is at version 0.
+func GetVersion(db database.Database) (uint32, error) {
+	versionDB := prefixdb.New(versionPrefix, db)
+	versionBytes, err := versionDB.Get(versionKey)
+	if err == database.ErrNotFound {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	if len(versionBytes) != 4 {
+		return 0, errBadVersion
+	}
+	return binary.BigEndian.Uint32(versionBytes), nil
+}
+
+// SetVersion sets the version of [db] to [version]
+func SetVersion(db database.Database, version uint32) error {
+	versionBytes := make([]byte, 4)
+	binary.BigEndian.PutUint32(versionBytes, version)
+
+	versionDB := prefixdb.New(versionPrefix, db)
+	return versionDB.Put(versionKey, versionBytes)
+}
+
+// CheckVersion returns nil iff the node can run on [db]. An empty database is
+// marked as being at the current version.
+func CheckVersion(db database.Database) error {
+	it := db.NewIterator()
+	empty := !it.Next()
+	it.Release()
+	if empty {
+		return SetVersion(db, CurrentVersion())
+	}
+
+	version, err := GetVersion(db)
+	if err != nil {
+		return err
+	}
+	switch {
+	case version < CurrentVersion():
+		return fmt.Errorf("%w: database is at version %d but version %d is required", errMigrationRequired, version, CurrentVersion())
+	case version > CurrentVersion():
+		return fmt.Errorf("%w: database is at version %d but at most version %d is supported", errDatabaseTooNew, version, CurrentVersion())
+	}
+	return nil
+}
+
+// Migrator upgrades a database to the latest version in two phases. First,
+// every pending migration is applied to an in-memory view of the database.
+// Then, if they all succeed, the changes are written to the database in a
+// single batch.
+type Migrator struct {
+	Log        logging.Logger
+	Migrations []Migration
+
+	// If DryRun, the migrations are verified but not written to the database
+	DryRun bool
+
+	// If Backup is non-nil, the database is copied to it before the changes
+	// are written
+	Backup database.Database
+}
+
+// Migrate [db] to the latest version. Returns the version [db] is at
+// afterwards.
+func (m *Migrator) Migrate(db database.Database) (uint32, error) {
+	for i, migration := range m.Migrations {
+		if migration.Version != uint32(i+1) {
+			return 0, errMigrationOrder
+		}
+	}
+	latestVersion := uint32(len(m.Migrations))
+
+	version, err := GetVersion(db)
+	if err != nil {
+		return 0, err
+	}
+	if version > latestVersion {
+		return version, fmt.Errorf("%w: database is at version %d but at most version %d is supported", errDatabaseTooNew, version, latestVersion)
+	}
+	if version == latestVersion {
+		m.Log.Info("database is already at version %d", version)
+		return version, nil
+	}
+
+	// Phase 1: apply the migrations in memory
+	pending := m.Migrations[version:]
+	staged := versiondb.New(db)
+	for i, migration := range pending {
+		m.Log.Info("applying migration %d/%d to version %d: %s", i+1, len(pending), migration.Version, migration.Description)
+		start := time.Now()
+		if err := migration.Migrate(staged, m.Log); err != nil {
+			return version, fmt.Errorf("migration to version %d failed: %w", migration.Version, err)
+		}
+		m.Log.Info("applied migration to version %d in %s", migration.Version, time.Since(start))
+	}
+	if err := SetVersion(staged, latestVersion); err != nil {
+		return version, err
+	}
+
+	if m.DryRun {
+		m.Log.Info("dry run: database would be migrated from version %d to version %d", version, latestVersion)
+		return version, nil
+	}
+
+	if m.Backup != nil {
+		m.Log.Info("backing up the database")
+		numKeys, err := copyDatabase(m.Backup, db, m.Log)
+		if err != nil {
+			return version, fmt.Errorf("couldn't back up the database: %w", err)
+		}
+		m.Log.Info("backed up %d keys", numKeys)
+	}
+
+	// Phase 2: write the migrated database
+	m.Log.Info("writing the migrated database")
+	if err := staged.Commit(); err != nil {
+		return version, err
+	}
+	m.Log.Info("migrated database from version %d to version %d", version, latestVersion)
+	return latestVersion, nil
+}
+
+// copyDatabase copies every key in [src] to [dst] and returns the number of
+// keys copied
+func copyDatabase(dst, src database.Database, log logging.Logger) (int, error) {
+	it := src.NewIterator()
+	defer it.Release()
+
+	batch := dst.NewBatch()
+	numKeys := 0
+	for it.Next() {
+		if err := batch.Put(it.Key(), it.Value()); err != nil {
+			return numKeys, err
+		}
+		numKeys++
+
+		if batch.ValueSize() >= backupBatchSize {
+			if err := batch.Write(); err != nil {
+				return numKeys, err
+			}
+			batch.Reset()
+		}
+		if numKeys%backupProgressInterval == 0 {
+			log.Info("copied %d keys", numKeys)
+		}
+	}
+	if err := it.Error(); err != nil {
+		return numKeys, err
+	}
+	return numKeys, batch.Write()
+}
@@ -0,0 +1,237 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package migration
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/ava-labs/gecko/database"
+	"github.com/ava-labs/gecko/database/memdb"
+	"github.com/ava-labs/gecko/utils/logging"
+)
+
+// testMigrations rename the key "a" to "b", then "b" to "c"
+func testMigrations() []Migration {
+	rename := func(from, to []byte) func(database.Database, logging.Logger) error {
+		return func(db database.Database, _ logging.Logger) error {
+			value, err := db.Get(from)
+			if err != nil {
+				return err
+			}
+			if err := db.Delete(from); err != nil {
+				return err
+			}
+			return db.Put(to, value)
+		}
+	}
+	return []Migration{
+		Migration{
+			Version:     1,
+			Description: "rename a to b",
+			Migrate:     rename([]byte("a"), []byte("b")),
+		},
+		Migration{
+			Version:     2,
+			Description: "rename b to c",
+			Migrate:     rename([]byte("b"), []byte("c")),
+		},
+	}
+}
+
+func TestVersion(t *testing.T) {
+	db := memdb.New()
+
+	if version, err := GetVersion(db); err != nil {
+		t.Fatal(err)
+	} else if version != 0 {
+		t.Fatalf("Unversioned database should be at version 0 but was at %d", version)
+	}
+
+	if err := SetVersion(db, 5); err != nil {
+		t.Fatal(err)
+	}
+	if version, err := GetVersion(db); err != nil {
+		t.Fatal(err)
+	} else if version != 5 {
+		t.Fatalf("Database should be at version 5 but was at %d", version)
+	}
+}
+
+func TestCheckVersionEmpty(t *testing.T) {
+	db := memdb.New()
+
+	if err := CheckVersion(db); err != nil {
+		t.Fatal(err)
+	}
+	if version, err := GetVersion(db); err != nil {
+		t.Fatal(err)
+	} else if version != CurrentVersion() {
+		t.Fatalf("Empty database should be marked at version %d but was at %d", CurrentVersion(), version)
+	}
+}
+
+func TestCheckVersionTooNew(t *testing.T) {
+	db := memdb.New()
+	if err := SetVersion(db, CurrentVersion()+1); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := CheckVersion(db); !errors.Is(err, errDatabaseTooNew) {
+		t.Fatalf("Expected %s but got %v", errDatabaseTooNew, err)
+	}
+}
+
+func TestMigrate(t *testing.T) {
+	db := memdb.New()
+	if err := db.Put([]byte("a"), []byte("value")); err != nil {
+		t.Fatal(err)
+	}
+	backup := memdb.New()
+
+	migrator := Migrator{
+		Log:        logging.NoLog{},
+		Migrations: testMigrations(),
+		Backup:     backup,
+	}
+	version, err := migrator.Migrate(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if version != 2 {
+		t.Fatalf("Database should be at version 2 but was at %d", version)
+	}
+
+	if has, err := db.Has([]byte("a")); err != nil {
+		t.Fatal(err)
+	} else if has {
+		t.Fatalf("Migrated key should have been removed")
+	}
+	if value, err := db.Get([]byte("c")); err != nil {
+		t.Fatal(err)
+	} else if !bytes.Equal(value, []byte("value")) {
+		t.Fatalf("Migrated key has the wrong value %s", value)
+	}
+	if version, err := GetVersion(db); err != nil {
+		t.Fatal(err)
+	} else if version != 2 {
+		t.Fatalf("Database should be marked at version 2 but was at %d", version)
+	}
+
+	// The backup should be the database before it was migrated
+	if value, err := backup.Get([]byte("a")); err != nil {
+		t.Fatal(err)
+	} else if !bytes.Equal(value, []byte("value")) {
+		t.Fatalf("Backed up key has the wrong value %s", value)
+	}
+	if version, err := GetVersion(backup); err != nil {
+		t.Fatal(err)
+	} else if version != 0 {
+		t.Fatalf("Backup should be at version 0 but was at %d", version)
+	}
+
+	// Migrating again shouldn't do anything
+	if version, err := migrator.Migrate(db); err != nil {
+		t.Fatal(err)
+	} else if version != 2 {
+		t.Fatalf("Database should be at version 2 but was at %d", version)
+	}
+}
+
+func TestMigratePartial(t *testing.T) {
+	db := memdb.New()
+	if err := db.Put([]byte("b"), []byte("value")); err != nil {
+		t.Fatal(err)
+	}
+	if err := SetVersion(db, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	migrator := Migrator{
+		Log:        logging.NoLog{},
+		Migrations: testMigrations(),
+	}
+	if _, err := migrator.Migrate(db); err != nil {
+		t.Fatal(err)
+	}
+	if has, err := db.Has([]byte("c")); err != nil {
+		t.Fatal(err)
+	} else if !has {
+		t.Fatalf("Only the second migration should have been applied")
+	}
+}
+
+func TestMigrateDryRun(t *testing.T) {
+	db := memdb.New()
+	if err := db.Put([]byte("a"), []byte("value")); err != nil {
+		t.Fatal(err)
+	}
+
+	migrator := Migrator{
+		Log:        logging.NoLog{},
+		Migrations: testMigrations(),
+		DryRun:     true,
+	}
+	version, err := migrator.Migrate(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if version != 0 {
+		t.Fatalf("Dry run shouldn't change the database's version but it's at %d", version)
+	}
+	if has, err := db.Has([]byte("a")); err != nil {
+		t.Fatal(err)
+	} else if !has {
+		t.Fatalf("Dry run shouldn't modify the database")
+	}
+	if version, err := GetVersion(db); err != nil {
+		t.Fatal(err)
+	} else if version != 0 {
+		t.Fatalf("Dry run shouldn't change the database's version but it's at %d", version)
+	}
+}
+
+// If any migration fails, nothing is written
+func TestMigrateFailure(t *testing.T) {
+	db := memdb.New()
+	if err := db.Put([]byte("a"), []byte("value")); err != nil {
+		t.Fatal(err)
+	}
+
+	errFailed := errors.New("failed")
+	migrations := testMigrations()
+	migrations[1].Migrate = func(database.Database, logging.Logger) error { return errFailed }
+
+	migrator := Migrator{
+		Log:        logging.NoLog{},
+		Migrations: migrations,
+	}
+	if _, err := migrator.Migrate(db); !errors.Is(err, errFailed) {
+		t.Fatalf("Expected %s but got %v", errFailed, err)
+	}
+	if has, err := db.Has([]byte("a")); err != nil {
+		t.Fatal(err)
+	} else if !has {
+		t.Fatalf("Failed migration shouldn't modify the database")
+	}
+	if version, err := GetVersion(db); err != nil {
+		t.Fatal(err)
+	} else if version != 0 {
+		t.Fatalf("Failed migration shouldn't change the database's version but it's at %d", version)
+	}
+}
+
+func TestMigrateOutOfOrder(t *testing.T) {
+	migrations := testMigrations()
+	migrations[0], migrations[1] = migrations[1], migrations[0]
+
+	migrator := Migrator{
+		Log:        logging.NoLog{},
+		Migrations: migrations,
+	}
+	if _, err := migrator.Migrate(memdb.New()); err != errMigrationOrder {
+		t.Fatalf("Expected %s but got %v", errMigrationOrder, err)
+	}
+}
@@ -11,12 +11,26 @@ import (
 	"github.com/ava-labs/gecko/utils/hashing"
 )
 
+// Buffers used to hold prefixed keys. Keys passed to the underlying database
+// are never retained by it, so the buffers can be reused once the call returns.
+var bufferPool sync.Pool
+
 // Database partitions a database into a sub-database by prefixing all keys with
 // a unique value.
+//
+// Nested prefixed databases are flattened when they are created, so every
+// operation copies the key at most once, regardless of the nesting depth.
 type Database struct {
-	lock     sync.RWMutex
+	lock sync.RWMutex
+
+	// The hash of this database's prefix
 	dbPrefix []byte
-	db       database.Database
+	// The prefixes of the prefixed databases this database is nested in
+	nestedPrefix []byte
+	// The prefix added to keys in [db]. Equal to [nestedPrefix] + [dbPrefix].
+	keyPrefix []byte
+
+	db database.Database
 }
 
 // New returns a new prefixed database
@@ -26,17 +40,34 @@ func New(prefix []byte, db database.Database) *Database {
 		copy(simplePrefix, prefixDB.dbPrefix)
 		copy(simplePrefix[len(prefixDB.dbPrefix):], prefix)
 
-		return NewNested(simplePrefix, prefixDB.db)
+		return newDatabase(prefixDB.nestedPrefix, simplePrefix, prefixDB.db)
 	}
-	return NewNested(prefix, db)
+	return newDatabase(nil, prefix, db)
 }
 
 // NewNested returns a new prefixed database without attempting to compress
 // prefixes.
 func NewNested(prefix []byte, db database.Database) *Database {
+	if prefixDB, ok := db.(*Database); ok {
+		return newDatabase(prefixDB.keyPrefix, prefix, prefixDB.db)
+	}
+	return newDatabase(nil, prefix, db)
+}
+
+// newDatabase returns a database that prefixes keys in [db] with
+// [nestedPrefix] followed by the hash of [prefix]
+func newDatabase(nestedPrefix, prefix []byte, db database.Database) *Database {
+	dbPrefix := hashing.ComputeHash256(prefix)
+
+	keyPrefix := make([]byte, len(nestedPrefix)+len(dbPrefix))
+	copy(keyPrefix, nestedPrefix)
+	copy(keyPrefix[len(nestedPrefix):], dbPrefix)
+
 	return &Database{
-		dbPrefix: hashing.ComputeHash256(prefix),
-		db:       db,
+		dbPrefix:     dbPrefix,
+		nestedPrefix: keyPrefix[:len(nestedPrefix)],
+		keyPrefix:    keyPrefix,
+		db:           db,
 	}
 }
 
@@ -48,7 +79,10 @@ func (db *Database) Has(key []byte) (bool, error) {
 	if db.db == nil {
 		return false, database.ErrClosed
 	}
-	return db.db.Has(db.prefix(key))
+	prefixedKey := db.prefixPooled(key)
+	defer bufferPool.Put(prefixedKey)
+
+	return db.db.Has(*prefixedKey)
 }
 
 // Get implements the Database interface
@@ -59,7 +93,10 @@ func (db *Database) Get(key []byte) ([]byte, error) {
 	if db.db == nil {
 		return nil, database.ErrClosed
 	}
-	return db.db.Get(db.prefix(key))
+	prefixedKey := db.prefixPooled(key)
+	defer bufferPool.Put(prefixedKey)
+
+	return db.db.Get(*prefixedKey)
 }
 
 // Put implements the Database interface
@@ -70,7 +107,10 @@ func (db *Database) Put(key, value []byte) error {
 	if db.db == nil {
 		return database.ErrClosed
 	}
-	return db.db.Put(db.prefix(key), value)
+	prefixedKey := db.prefixPooled(key)
+	defer bufferPool.Put(prefixedKey)
+
+	return db.db.Put(*prefixedKey, value)
 }
 
 // Delete implements the Database interface
@@ -81,7 +121,10 @@ func (db *Database) Delete(key []byte) error {
 	if db.db == nil {
 		return database.ErrClosed
 	}
-	return db.db.Delete(db.prefix(key))
+	prefixedKey := db.prefixPooled(key)
+	defer bufferPool.Put(prefixedKey)
+
+	return db.db.Delete(*prefixedKey)
 }
 
 // NewBatch implements the Database interface
@@ -154,9 +197,27 @@ func (db *Database) Close() error {
 }
 
 func (db *Database) prefix(key []byte) []byte {
-	prefixedKey := make([]byte, len(db.dbPrefix)+len(key))
-	copy(prefixedKey, db.dbPrefix)
-	copy(prefixedKey[len(db.dbPrefix):], key)
+	prefixedKey := make([]byte, len(db.keyPrefix)+len(key))
+	copy(prefixedKey, db.keyPrefix)
+	copy(prefixedKey[len(db.keyPrefix):], key)
+	return prefixedKey
+}
+
+// prefixPooled returns [key] with this database's prefix in a buffer from the
+// buffer pool. The buffer should be returned to the pool once the prefixed key
+// is no longer referenced.
+func (db *Database) prefixPooled(key []byte) *[]byte {
+	keyLen := len(db.keyPrefix) + len(key)
+
+	prefixedKey, ok := bufferPool.Get().(*[]byte)
+	if !ok || cap(*prefixedKey) < keyLen {
+		newKey := make([]byte, keyLen)
+		prefixedKey = &newKey
+	}
+	*prefixedKey = (*prefixedKey)[:keyLen]
+
+	copy(*prefixedKey, db.keyPrefix)
+	copy((*prefixedKey)[len(db.keyPrefix):], key)
 	return prefixedKey
 }
 
@@ -175,13 +236,21 @@ type batch struct {
 // Put implements the Batch interface
 func (b *batch) Put(key, value []byte) error {
 	b.writes = append(b.writes, keyValue{copyBytes(key), copyBytes(value), false})
-	return b.Batch.Put(b.db.prefix(key), value)
+
+	prefixedKey := b.db.prefixPooled(key)
+	defer bufferPool.Put(prefixedKey)
+
+	return b.Batch.Put(*prefixedKey, value)
 }
 
 // Delete implements the Batch interface
 func (b *batch) Delete(key []byte) error {
 	b.writes = append(b.writes, keyValue{copyBytes(key), nil, true})
-	return b.Batch.Delete(b.db.prefix(key))
+
+	prefixedKey := b.db.prefixPooled(key)
+	defer bufferPool.Put(prefixedKey)
+
+	return b.Batch.Delete(*prefixedKey)
 }
 
 // Write flushes any accumulated data to the memory database.
@@ -202,6 +271,10 @@ func (b *batch) Reset() {
 	b.Batch.Reset()
 }
 
+// Inner returns the batch of the underlying database that the prefixed keys
+// are written to
+func (b *batch) Inner() database.Batch { return b.Batch }
+
 // Replay replays the batch contents.
 func (b *batch) Replay(w database.KeyValueWriter) error {
 	for _, keyvalue := range b.writes {
@@ -224,7 +297,7 @@ type iterator struct {
 // Key calls the inner iterators Key and strips the prefix
 func (it *iterator) Key() []byte {
 	key := it.Iterator.Key()
-	if prefixLen := len(it.db.dbPrefix); len(key) >= prefixLen {
+	if prefixLen := len(it.db.keyPrefix); len(key) >= prefixLen {
 		return key[prefixLen:]
 	}
 	return key
@@ -4,10 +4,12 @@
 package prefixdb
 
 import (
+	"bytes"
 	"testing"
 
 	"github.com/ava-labs/gecko/database"
 	"github.com/ava-labs/gecko/database/memdb"
+	"github.com/ava-labs/gecko/utils/hashing"
 )
 
 func TestInterface(t *testing.T) {
@@ -21,3 +23,113 @@ func TestInterface(t *testing.T) {
 		test(t, NewNested([]byte("ld"), New([]byte("wor"), db)))
 	}
 }
+
+// Ensure the keys written to the underlying database don't depend on whether
+// the prefixed databases were flattened
+func TestNestedKeyLayout(t *testing.T) {
+	key := []byte("key")
+	value := []byte("value")
+
+	hashedPrefix := func(prefix []byte) []byte { return hashing.ComputeHash256(prefix) }
+	concat := func(byteSlices ...[]byte) []byte {
+		result := []byte{}
+		for _, b := range byteSlices {
+			result = append(result, b...)
+		}
+		return result
+	}
+
+	tests := []struct {
+		db          func(database.Database) database.Database
+		expectedKey []byte
+	}{
+		{
+			db:          func(db database.Database) database.Database { return New([]byte("a"), db) },
+			expectedKey: concat(hashedPrefix([]byte("a")), key),
+		},
+		{
+			db: func(db database.Database) database.Database {
+				return New([]byte("b"), New([]byte("a"), db))
+			},
+			expectedKey: concat(hashedPrefix(concat(hashedPrefix([]byte("a")), []byte("b"))), key),
+		},
+		{
+			db: func(db database.Database) database.Database {
+				return NewNested([]byte("b"), New([]byte("a"), db))
+			},
+			expectedKey: concat(hashedPrefix([]byte("a")), hashedPrefix([]byte("b")), key),
+		},
+		{
+			db: func(db database.Database) database.Database {
+				return New([]byte("c"), NewNested([]byte("b"), New([]byte("a"), db)))
+			},
+			expectedKey: concat(
+				hashedPrefix([]byte("a")),
+				hashedPrefix(concat(hashedPrefix([]byte("b")), []byte("c"))),
+				key,
+			),
+		},
+		{
+			db: func(db database.Database) database.Database {
+				return NewNested([]byte("c"), NewNested([]byte("b"), New([]byte("a"), db)))
+			},
+			expectedKey: concat(hashedPrefix([]byte("a")), hashedPrefix([]byte("b")), hashedPrefix([]byte("c")), key),
+		},
+	}
+	for i, test := range tests {
+		baseDB := memdb.New()
+		db := test.db(baseDB)
+
+		if err := db.Put(key, value); err != nil {
+			t.Fatalf("test %d: %s", i, err)
+		}
+		if has, err := baseDB.Has(test.expectedKey); err != nil {
+			t.Fatalf("test %d: %s", i, err)
+		} else if !has {
+			t.Fatalf("test %d: value should have been written to key %x", i, test.expectedKey)
+		}
+
+		it := db.NewIterator()
+		if !it.Next() {
+			t.Fatalf("test %d: iterator should have returned the key", i)
+		}
+		if !bytes.Equal(it.Key(), key) {
+			t.Fatalf("test %d: iterator returned key %x but expected %x", i, it.Key(), key)
+		}
+		it.Release()
+	}
+}
+
+func benchmarkNested(b *testing.B, depth int, op func(database.Database, []byte) error) {
+	var db database.Database = memdb.New()
+	for i := 0; i < depth; i++ {
+		db = NewNested([]byte{byte(i)}, db)
+	}
+
+	key := make([]byte, 32)
+	if err := db.Put(key, key); err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		if err := op(db, key); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func benchmarkGet(db database.Database, key []byte) error {
+	_, err := db.Get(key)
+	return err
+}
+
+func benchmarkPut(db database.Database, key []byte) error { return db.Put(key, key) }
+
+func BenchmarkGetDepth1(b *testing.B) { benchmarkNested(b, 1, benchmarkGet) }
+func BenchmarkGetDepth4(b *testing.B) { benchmarkNested(b, 4, benchmarkGet) }
+func BenchmarkGetDepth8(b *testing.B) { benchmarkNested(b, 8, benchmarkGet) }
+func BenchmarkPutDepth1(b *testing.B) { benchmarkNested(b, 1, benchmarkPut) }
+func BenchmarkPutDepth4(b *testing.B) { benchmarkNested(b, 4, benchmarkPut) }
+func BenchmarkPutDepth8(b *testing.B) { benchmarkNested(b, 8, benchmarkPut) }
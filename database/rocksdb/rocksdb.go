@@ -0,0 +1,460 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+//go:build rocksdb
+// +build rocksdb
+
+package rocksdb
+
+import (
+	"bytes"
+	"runtime"
+	"sync"
+
+	"github.com/ava-labs/gecko/database"
+	"github.com/tecbot/gorocksdb"
+)
+
+const (
+	// minBlockCacheSize is the minimum number of bytes to use for block caching
+	// in rocksdb.
+	minBlockCacheSize = 8 * 1024 * 1024
+
+	// minWriteBufferSize is the minimum number of bytes to use for buffers in
+	// rocksdb.
+	minWriteBufferSize = 8 * 1024 * 1024
+
+	// minHandleCap is the minimum number of files descriptors to cap rocksDB to
+	// use
+	minHandleCap = 16
+
+	// bloomFilterBitsPerKey is the number of bits per key of the bloom filters
+	// that let reads of missing keys skip files
+	bloomFilterBitsPerKey = 10
+)
+
+// Database is a persistent key-value store backed by RocksDB. Apart from basic
+// data storage functionality it also supports batch writes and iterating over
+// the keyspace in binary-alphabetical order.
+//
+// RocksDB's handles can't be used once they're freed, so every operation holds
+// [lock] to make sure the database isn't closed while it runs.
+type Database struct {
+	lock   sync.RWMutex
+	closed bool
+
+	db *gorocksdb.DB
+
+	// Freed when the database is closed
+	opts      *gorocksdb.Options
+	tableOpts *gorocksdb.BlockBasedTableOptions
+	cache     *gorocksdb.Cache
+	readOpts  *gorocksdb.ReadOptions
+	writeOpts *gorocksdb.WriteOptions
+
+	// The iterators that haven't been released. They're released when the
+	// database is closed, as RocksDB can't be closed while they're open.
+	iteratorsLock sync.Mutex
+	iterators     map[*iterator]struct{}
+}
+
+// New returns a wrapped RocksDB object.
+func New(file string, blockCacheSize, writeBufferSize, handleCap int) (*Database, error) {
+	// Enforce minimums
+	if writeBufferSize < minWriteBufferSize {
+		writeBufferSize = minWriteBufferSize
+	}
+
+	db := newDatabase(blockCacheSize, handleCap)
+	db.opts.SetCreateIfMissing(true)
+	// There are two buffers of size WriteBuffer used.
+	db.opts.SetWriteBufferSize(writeBufferSize / 2)
+	db.opts.SetMaxWriteBufferNumber(2)
+	db.opts.IncreaseParallelism(runtime.NumCPU())
+
+	rocksDB, err := gorocksdb.OpenDb(db.opts, file)
+	if err != nil {
+		db.free()
+		return nil, err
+	}
+	db.db = rocksDB
+	return db, nil
+}
+
+// NewReadOnly returns a wrapped RocksDB object that can only be read from. The
+// database's files aren't modified, so it may be opened from a copy of another
+// node's database directory. Writes to it return an error.
+func NewReadOnly(file string, blockCacheSize, handleCap int) (*Database, error) {
+	db := newDatabase(blockCacheSize, handleCap)
+
+	rocksDB, err := gorocksdb.OpenDbForReadOnly(db.opts, file, false)
+	if err != nil {
+		db.free()
+		return nil, err
+	}
+	db.db = rocksDB
+	return db, nil
+}
+
+// newDatabase returns a database with the options shared by writable and read
+// only databases. The caller must open [db.db].
+func newDatabase(blockCacheSize, handleCap int) *Database {
+	// Enforce minimums
+	if blockCacheSize < minBlockCacheSize {
+		blockCacheSize = minBlockCacheSize
+	}
+	if handleCap < minHandleCap {
+		handleCap = minHandleCap
+	}
+
+	db := &Database{
+		opts:      gorocksdb.NewDefaultOptions(),
+		tableOpts: gorocksdb.NewDefaultBlockBasedTableOptions(),
+		cache:     gorocksdb.NewLRUCache(uint64(blockCacheSize)),
+		readOpts:  gorocksdb.NewDefaultReadOptions(),
+		writeOpts: gorocksdb.NewDefaultWriteOptions(),
+		iterators: make(map[*iterator]struct{}),
+	}
+	db.tableOpts.SetBlockCache(db.cache)
+	db.tableOpts.SetFilterPolicy(gorocksdb.NewBloomFilter(bloomFilterBitsPerKey))
+	db.opts.SetBlockBasedTableFactory(db.tableOpts)
+	db.opts.SetMaxOpenFiles(handleCap)
+	return db
+}
+
+// Has returns if the key is set in the database
+func (db *Database) Has(key []byte) (bool, error) {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.closed {
+		return false, database.ErrClosed
+	}
+	value, err := db.db.Get(db.readOpts, key)
+	if err != nil {
+		return false, err
+	}
+	defer value.Free()
+	return value.Exists(), nil
+}
+
+// Get returns the value the key maps to in the database
+func (db *Database) Get(key []byte) ([]byte, error) {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.closed {
+		return nil, database.ErrClosed
+	}
+	value, err := db.db.Get(db.readOpts, key)
+	if err != nil {
+		return nil, err
+	}
+	defer value.Free()
+	if !value.Exists() {
+		return nil, database.ErrNotFound
+	}
+	return copyBytes(value.Data()), nil
+}
+
+// Put sets the value of the provided key to the provided value
+func (db *Database) Put(key []byte, value []byte) error {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.closed {
+		return database.ErrClosed
+	}
+	return db.db.Put(db.writeOpts, key, value)
+}
+
+// Delete removes the key from the database
+func (db *Database) Delete(key []byte) error {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.closed {
+		return database.ErrClosed
+	}
+	return db.db.Delete(db.writeOpts, key)
+}
+
+// NewBatch creates a write/delete-only buffer that is atomically committed to
+// the database when write is called
+func (db *Database) NewBatch() database.Batch { return &batch{db: db} }
+
+// NewIterator creates a lexicographically ordered iterator over the database
+func (db *Database) NewIterator() database.Iterator {
+	return db.NewIteratorWithStartAndPrefix(nil, nil)
+}
+
+// NewIteratorWithStart creates a lexicographically ordered iterator over the
+// database starting at the provided key
+func (db *Database) NewIteratorWithStart(start []byte) database.Iterator {
+	return db.NewIteratorWithStartAndPrefix(start, nil)
+}
+
+// NewIteratorWithPrefix creates a lexicographically ordered iterator over the
+// database ignoring keys that do not start with the provided prefix
+func (db *Database) NewIteratorWithPrefix(prefix []byte) database.Iterator {
+	return db.NewIteratorWithStartAndPrefix(nil, prefix)
+}
+
+// NewIteratorWithStartAndPrefix creates a lexicographically ordered iterator
+// over the database starting at start and ignoring keys that do not start with
+// the provided prefix
+func (db *Database) NewIteratorWithStartAndPrefix(start, prefix []byte) database.Iterator {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.closed {
+		return &iterator{db: db, err: database.ErrClosed}
+	}
+
+	seek := prefix
+	if bytes.Compare(start, prefix) == 1 {
+		seek = start
+	}
+	it := &iterator{
+		db:     db,
+		iter:   db.db.NewIterator(db.readOpts),
+		prefix: copyBytes(prefix),
+	}
+	it.iter.Seek(seek)
+
+	db.iteratorsLock.Lock()
+	db.iterators[it] = struct{}{}
+	db.iteratorsLock.Unlock()
+	return it
+}
+
+// Stat returns a particular internal stat of the database.
+func (db *Database) Stat(property string) (string, error) {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.closed {
+		return "", database.ErrClosed
+	}
+	// RocksDB returns an empty value for unknown properties
+	stat := db.db.GetProperty(property)
+	if stat == "" {
+		return "", database.ErrNotFound
+	}
+	return stat, nil
+}
+
+// Compact the underlying DB for the given key range.
+// Specifically, deleted and overwritten versions are discarded,
+// and the data is rearranged to reduce the cost of operations
+// needed to access the data. This operation should typically only
+// be invoked by users who understand the underlying implementation.
+//
+// A nil start is treated as a key before all keys in the DB.
+// And a nil limit is treated as a key after all keys in the DB.
+// Therefore if both are nil then it will compact entire DB.
+func (db *Database) Compact(start []byte, limit []byte) error {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.closed {
+		return database.ErrClosed
+	}
+	db.db.CompactRange(gorocksdb.Range{Start: start, Limit: limit})
+	return nil
+}
+
+// Close implements the Database interface
+func (db *Database) Close() error {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	if db.closed {
+		return database.ErrClosed
+	}
+	db.closed = true
+
+	db.iteratorsLock.Lock()
+	for it := range db.iterators {
+		it.iter.Close()
+		it.iter = nil
+		it.key = nil
+		it.value = nil
+		it.err = database.ErrClosed
+	}
+	db.iterators = nil
+	db.iteratorsLock.Unlock()
+
+	db.db.Close()
+	db.free()
+	return nil
+}
+
+// free the options of the database, which must not be open
+func (db *Database) free() {
+	db.writeOpts.Destroy()
+	db.readOpts.Destroy()
+	db.opts.Destroy()
+	db.tableOpts.Destroy()
+	db.cache.Destroy()
+}
+
+type keyValue struct {
+	key    []byte
+	value  []byte
+	delete bool
+}
+
+// batch buffers writes in memory, so that no RocksDB handles are held until
+// it's written
+type batch struct {
+	db     *Database
+	writes []keyValue
+	size   int
+}
+
+// Put the value into the batch for later writing
+func (b *batch) Put(key, value []byte) error {
+	b.writes = append(b.writes, keyValue{copyBytes(key), copyBytes(value), false})
+	b.size += len(value)
+	return nil
+}
+
+// Delete the key during writing
+func (b *batch) Delete(key []byte) error {
+	b.writes = append(b.writes, keyValue{copyBytes(key), nil, true})
+	b.size++
+	return nil
+}
+
+// ValueSize retrieves the amount of data queued up for writing.
+func (b *batch) ValueSize() int { return b.size }
+
+// Write flushes any accumulated data to disk.
+func (b *batch) Write() error {
+	b.db.lock.RLock()
+	defer b.db.lock.RUnlock()
+
+	if b.db.closed {
+		return database.ErrClosed
+	}
+
+	rocksBatch := gorocksdb.NewWriteBatch()
+	defer rocksBatch.Destroy()
+
+	for _, kv := range b.writes {
+		if kv.delete {
+			rocksBatch.Delete(kv.key)
+		} else {
+			rocksBatch.Put(kv.key, kv.value)
+		}
+	}
+	return b.db.db.Write(b.db.writeOpts, rocksBatch)
+}
+
+// Reset resets the batch for reuse.
+func (b *batch) Reset() {
+	b.writes = b.writes[:0]
+	b.size = 0
+}
+
+// Replay the batch contents.
+func (b *batch) Replay(w database.KeyValueWriter) error {
+	for _, kv := range b.writes {
+		if kv.delete {
+			if err := w.Delete(kv.key); err != nil {
+				return err
+			}
+		} else if err := w.Put(kv.key, kv.value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// iterator over the keys of a RocksDB database that start with [prefix]. The
+// RocksDB iterator is positioned at the first key before Next is first called.
+type iterator struct {
+	db      *Database
+	iter    *gorocksdb.Iterator
+	prefix  []byte
+	started bool
+
+	key, value []byte
+	err        error
+}
+
+// Next moves the iterator to the next key/value pair. It returns whether the
+// iterator is exhausted.
+func (it *iterator) Next() bool {
+	it.db.lock.RLock()
+	defer it.db.lock.RUnlock()
+
+	// The iterator was exhausted, released, or the database was closed
+	if it.iter == nil {
+		return false
+	}
+
+	if it.started {
+		it.iter.Next()
+	}
+	it.started = true
+
+	if !it.iter.ValidForPrefix(it.prefix) {
+		it.err = it.iter.Err()
+		it.release()
+		return false
+	}
+
+	key := it.iter.Key()
+	it.key = copyBytes(key.Data())
+	key.Free()
+
+	value := it.iter.Value()
+	it.value = copyBytes(value.Data())
+	value.Free()
+	return true
+}
+
+// Error returns any accumulated error. Exhausting all the key/value pairs
+// is not considered to be an error.
+func (it *iterator) Error() error { return it.err }
+
+// Key returns the key of the current key/value pair, or nil if done.
+func (it *iterator) Key() []byte { return it.key }
+
+// Value returns the value of the current key/value pair, or nil if done.
+func (it *iterator) Value() []byte { return it.value }
+
+// Release releases associated resources. Release should always succeed and can
+// be called multiple times without causing error.
+func (it *iterator) Release() {
+	it.db.lock.RLock()
+	defer it.db.lock.RUnlock()
+
+	it.release()
+}
+
+// release the RocksDB iterator. Assumes the database's lock is held.
+func (it *iterator) release() {
+	it.key = nil
+	it.value = nil
+	if it.iter == nil {
+		return
+	}
+	it.iter.Close()
+	it.iter = nil
+
+	it.db.iteratorsLock.Lock()
+	delete(it.db.iterators, it)
+	it.db.iteratorsLock.Unlock()
+}
+
+func copyBytes(b []byte) []byte {
+	if b == nil {
+		return nil
+	}
+	copiedBytes := make([]byte, len(b))
+	copy(copiedBytes, b)
+	return copiedBytes
+}
@@ -0,0 +1,28 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+//go:build !rocksdb
+// +build !rocksdb
+
+package rocksdb
+
+import (
+	"errors"
+
+	"github.com/ava-labs/gecko/database"
+)
+
+// RocksDB needs cgo and librocksdb, so it's only built in with the rocksdb
+// build tag
+
+var errDisabled = errors.New("RocksDB isn't built into this node; build it with the rocksdb tag to use RocksDB")
+
+// New returns an error, as this node was built without RocksDB
+func New(file string, blockCacheSize, writeBufferSize, handleCap int) (database.Database, error) {
+	return nil, errDisabled
+}
+
+// NewReadOnly returns an error, as this node was built without RocksDB
+func NewReadOnly(file string, blockCacheSize, handleCap int) (database.Database, error) {
+	return nil, errDisabled
+}
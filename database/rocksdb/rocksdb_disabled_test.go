@@ -0,0 +1,20 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+//go:build !rocksdb
+// +build !rocksdb
+
+package rocksdb
+
+import (
+	"testing"
+)
+
+func TestDisabled(t *testing.T) {
+	if _, err := New("db", 0, 0, 0); err != errDisabled {
+		t.Fatalf("Expected %s but got %v", errDisabled, err)
+	}
+	if _, err := NewReadOnly("db", 0, 0); err != errDisabled {
+		t.Fatalf("Expected %s but got %v", errDisabled, err)
+	}
+}
@@ -0,0 +1,63 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+//go:build rocksdb
+// +build rocksdb
+
+package rocksdb
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/ava-labs/gecko/database"
+)
+
+func TestInterface(t *testing.T) {
+	for i, test := range database.Tests {
+		folder := fmt.Sprintf("db%d", i)
+
+		db, err := New(folder, 0, 0, 0)
+		if err != nil {
+			t.Fatalf("rocksdb.New(%s, 0, 0, 0) errored with %s", folder, err)
+		}
+		defer os.RemoveAll(folder)
+		defer db.Close()
+
+		test(t, db)
+	}
+}
+
+func TestReadOnly(t *testing.T) {
+	folder := "dbReadOnly"
+	defer os.RemoveAll(folder)
+
+	db, err := New(folder, 0, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key := []byte("hello")
+	value := []byte("world")
+	if err := db.Put(key, value); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	readOnly, err := NewReadOnly(folder, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer readOnly.Close()
+
+	if v, err := readOnly.Get(key); err != nil {
+		t.Fatal(err)
+	} else if string(v) != string(value) {
+		t.Fatalf("Expected %s but got %s", value, v)
+	}
+	if err := readOnly.Put(key, value); err == nil {
+		t.Fatalf("Should have errored writing to a read only database")
+	}
+}
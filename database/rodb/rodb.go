@@ -0,0 +1,99 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rodb
+
+import (
+	"sync"
+
+	"github.com/ava-labs/gecko/database"
+	"github.com/ava-labs/gecko/database/versiondb"
+)
+
+// Database only reads from the database it wraps. Writes are kept in memory,
+// where they shadow the wrapped database's values until the database is
+// closed, so that code that writes as it runs can be run over a database that
+// mustn't be modified.
+type Database struct {
+	lock   sync.Mutex
+	closed bool
+
+	mem *versiondb.Database
+	db  database.Database
+}
+
+// New returns a database that reads from [db] and never writes to it. [db] is
+// closed when the returned database is closed.
+func New(db database.Database) *Database {
+	return &Database{
+		mem: versiondb.New(db),
+		db:  db,
+	}
+}
+
+// Has implements the database.Database interface
+func (db *Database) Has(key []byte) (bool, error) { return db.mem.Has(key) }
+
+// Get implements the database.Database interface
+func (db *Database) Get(key []byte) ([]byte, error) { return db.mem.Get(key) }
+
+// Put implements the database.Database interface. The write is kept in memory.
+func (db *Database) Put(key, value []byte) error { return db.mem.Put(key, value) }
+
+// Delete implements the database.Database interface. The deletion is kept in
+// memory.
+func (db *Database) Delete(key []byte) error { return db.mem.Delete(key) }
+
+// NewBatch implements the database.Database interface. Batches are written to
+// memory.
+func (db *Database) NewBatch() database.Batch { return db.mem.NewBatch() }
+
+// NewIterator implements the database.Database interface
+func (db *Database) NewIterator() database.Iterator { return db.mem.NewIterator() }
+
+// NewIteratorWithStart implements the database.Database interface
+func (db *Database) NewIteratorWithStart(start []byte) database.Iterator {
+	return db.mem.NewIteratorWithStart(start)
+}
+
+// NewIteratorWithPrefix implements the database.Database interface
+func (db *Database) NewIteratorWithPrefix(prefix []byte) database.Iterator {
+	return db.mem.NewIteratorWithPrefix(prefix)
+}
+
+// NewIteratorWithStartAndPrefix implements the database.Database interface
+func (db *Database) NewIteratorWithStartAndPrefix(start, prefix []byte) database.Iterator {
+	return db.mem.NewIteratorWithStartAndPrefix(start, prefix)
+}
+
+// Stat implements the database.Database interface
+func (db *Database) Stat(property string) (string, error) { return db.mem.Stat(property) }
+
+// Compact implements the database.Database interface. Compacting would modify
+// the wrapped database, so it does nothing.
+func (db *Database) Compact(start, limit []byte) error {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	if db.closed {
+		return database.ErrClosed
+	}
+	return nil
+}
+
+// Close implements the database.Database interface. The writes kept in memory
+// are dropped and the wrapped database is closed.
+func (db *Database) Close() error {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	if db.closed {
+		return database.ErrClosed
+	}
+	db.closed = true
+
+	if err := db.mem.Close(); err != nil {
+		return err
+	}
+	return db.db.Close()
+}
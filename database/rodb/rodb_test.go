@@ -0,0 +1,83 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rodb
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ava-labs/gecko/database"
+	"github.com/ava-labs/gecko/database/memdb"
+)
+
+func TestInterface(t *testing.T) {
+	for _, test := range database.Tests {
+		baseDB := memdb.New()
+		test(t, New(baseDB))
+	}
+}
+
+func TestWritesDontReachWrappedDatabase(t *testing.T) {
+	baseDB := memdb.New()
+
+	key1 := []byte("hello1")
+	value1 := []byte("world1")
+	key2 := []byte("hello2")
+	value2 := []byte("world2")
+
+	if err := baseDB.Put(key1, value1); err != nil {
+		t.Fatalf("Unexpected error on baseDB.Put: %s", err)
+	}
+
+	db := New(baseDB)
+
+	if value, err := db.Get(key1); err != nil {
+		t.Fatalf("Unexpected error on db.Get: %s", err)
+	} else if !bytes.Equal(value, value1) {
+		t.Fatalf("db.Get Returned: 0x%x ; Expected: 0x%x", value, value1)
+	}
+
+	if err := db.Put(key2, value2); err != nil {
+		t.Fatalf("Unexpected error on db.Put: %s", err)
+	}
+	if err := db.Delete(key1); err != nil {
+		t.Fatalf("Unexpected error on db.Delete: %s", err)
+	}
+	batch := db.NewBatch()
+	if err := batch.Put(key1, value2); err != nil {
+		t.Fatalf("Unexpected error on batch.Put: %s", err)
+	}
+	if err := batch.Write(); err != nil {
+		t.Fatalf("Unexpected error on batch.Write: %s", err)
+	}
+
+	if value, err := db.Get(key1); err != nil {
+		t.Fatalf("Unexpected error on db.Get: %s", err)
+	} else if !bytes.Equal(value, value2) {
+		t.Fatalf("db.Get Returned: 0x%x ; Expected: 0x%x", value, value2)
+	}
+	if has, err := db.Has(key2); err != nil {
+		t.Fatalf("Unexpected error on db.Has: %s", err)
+	} else if !has {
+		t.Fatalf("db.Has unexpectedly returned false on key %s", key2)
+	}
+
+	if value, err := baseDB.Get(key1); err != nil {
+		t.Fatalf("Unexpected error on baseDB.Get: %s", err)
+	} else if !bytes.Equal(value, value1) {
+		t.Fatalf("baseDB.Get Returned: 0x%x ; Expected: 0x%x", value, value1)
+	}
+	if has, err := baseDB.Has(key2); err != nil {
+		t.Fatalf("Unexpected error on baseDB.Has: %s", err)
+	} else if has {
+		t.Fatalf("baseDB.Has unexpectedly returned true on key %s", key2)
+	}
+
+	if err := db.Close(); err != nil {
+		t.Fatalf("Unexpected error on db.Close: %s", err)
+	}
+	if _, err := baseDB.Get(key1); err != database.ErrClosed {
+		t.Fatalf("Expected %s on baseDB.Get after closing but got %s", database.ErrClosed, err)
+	}
+}
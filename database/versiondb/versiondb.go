@@ -74,7 +74,7 @@ func (db *Database) Put(key, value []byte) error {
 	if db.mem == nil {
 		return database.ErrClosed
 	}
-	db.mem[string(key)] = valueDelete{value: value}
+	db.mem[string(key)] = valueDelete{value: copyBytes(value)}
 	return nil
 }
 
@@ -191,22 +191,59 @@ func (db *Database) Commit() error {
 		return nil
 	}
 
+	batch, err := db.commitBatch()
+	if err != nil {
+		return err
+	}
+	if err := batch.Write(); err != nil {
+		return err
+	}
+	db.abort()
+	return nil
+}
+
+// CommitBatch returns a batch of the underlying database that contains all the
+// operations of this database. The operations are only removed from this
+// database once Abort is called, which should happen after the batch is
+// written.
+func (db *Database) CommitBatch() (database.Batch, error) {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	return db.commitBatch()
+}
+
+func (db *Database) commitBatch() (database.Batch, error) {
+	if db.mem == nil {
+		return nil, database.ErrClosed
+	}
+
 	batch := db.db.NewBatch()
 	for key, value := range db.mem {
 		if value.delete {
 			if err := batch.Delete([]byte(key)); err != nil {
-				return err
+				return nil, err
 			}
 		} else if err := batch.Put([]byte(key), value.value); err != nil {
-			return err
+			return nil, err
 		}
 	}
-	if err := batch.Write(); err != nil {
-		return err
-	}
+	return batch, nil
+}
 
-	db.mem = make(map[string]valueDelete, memdb.DefaultSize)
-	return nil
+// Abort discards all the operations of this database that haven't been
+// committed
+func (db *Database) Abort() {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	db.abort()
+}
+
+func (db *Database) abort() {
+	if db.mem != nil {
+		db.mem = make(map[string]valueDelete, memdb.DefaultSize)
+	}
 }
 
 // Close implements the database.Database interface
@@ -290,7 +327,12 @@ func (b *batch) Replay(w database.KeyValueWriter) error {
 }
 
 // iterator walks over both the in memory database and the underlying database
-// at the same time.
+// at the same time. The pending writes and deletes in memory are overlaid onto
+// the underlying database's keys in sorted order.
+//
+// The underlying iterator is advanced as soon as its current key/value pair is
+// returned, so the pair is copied, as the underlying database may reuse its
+// buffers.
 type iterator struct {
 	database.Iterator
 
@@ -313,6 +355,12 @@ func (it *iterator) Next() bool {
 
 	for {
 		switch {
+		case it.exhausted && it.Iterator.Error() != nil:
+			// The rest of the underlying database can't be merged in, so the
+			// keys in memory aren't returned either
+			it.key = nil
+			it.value = nil
+			return false
 		case it.exhausted && len(it.keys) == 0:
 			it.key = nil
 			it.value = nil
@@ -330,8 +378,8 @@ func (it *iterator) Next() bool {
 				return true
 			}
 		case len(it.keys) == 0:
-			it.key = it.Iterator.Key()
-			it.value = it.Iterator.Value()
+			it.key = copyBytes(it.Iterator.Key())
+			it.value = copyBytes(it.Iterator.Value())
 			it.exhausted = !it.Iterator.Next()
 			return true
 		default:
@@ -352,8 +400,8 @@ func (it *iterator) Next() bool {
 					return true
 				}
 			case dbStringKey < memKey:
-				it.key = dbKey
-				it.value = it.Iterator.Value()
+				it.key = copyBytes(dbKey)
+				it.value = copyBytes(it.Iterator.Value())
 				it.exhausted = !it.Iterator.Next()
 				return true
 			default:
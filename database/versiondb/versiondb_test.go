@@ -5,10 +5,12 @@ package versiondb
 
 import (
 	"bytes"
+	"errors"
 	"testing"
 
 	"github.com/ava-labs/gecko/database"
 	"github.com/ava-labs/gecko/database/memdb"
+	"github.com/ava-labs/gecko/database/nodb"
 )
 
 func TestInterface(t *testing.T) {
@@ -179,6 +181,170 @@ func TestIterate(t *testing.T) {
 	}
 }
 
+// assertIterates asserts that [iterator] returns the [keys] in order, each
+// mapped to its value in [values], and is then exhausted
+func assertIterates(t *testing.T, iterator database.Iterator, keys []string, values map[string]string) {
+	defer iterator.Release()
+
+	for _, key := range keys {
+		if !iterator.Next() {
+			t.Fatalf("iterator.Next Returned: %v ; Expected: %v", false, true)
+		} else if k := iterator.Key(); string(k) != key {
+			t.Fatalf("iterator.Key Returned: %s ; Expected: %s", k, key)
+		} else if v := iterator.Value(); string(v) != values[key] {
+			t.Fatalf("iterator.Value Returned: %s ; Expected: %s", v, values[key])
+		}
+	}
+	if iterator.Next() {
+		t.Fatalf("iterator.Next Returned: %v ; Expected: %v", true, false)
+	} else if err := iterator.Error(); err != nil {
+		t.Fatalf("iterator.Error Returned: %s ; Expected: nil", err)
+	}
+}
+
+func TestIterateOverlay(t *testing.T) {
+	baseDB := memdb.New()
+	db := New(baseDB)
+
+	for _, key := range []string{"a", "b", "c", "d"} {
+		if err := db.Put([]byte(key), []byte("committed "+key)); err != nil {
+			t.Fatalf("Unexpected error on db.Put: %s", err)
+		}
+	}
+	if err := db.Commit(); err != nil {
+		t.Fatalf("Unexpected error on db.Commit: %s", err)
+	}
+
+	// Pending writes and deletes interleaved with the committed keys
+	if err := db.Delete([]byte("b")); err != nil {
+		t.Fatalf("Unexpected error on db.Delete: %s", err)
+	} else if err := db.Put([]byte("bb"), []byte("pending bb")); err != nil {
+		t.Fatalf("Unexpected error on db.Put: %s", err)
+	} else if err := db.Put([]byte("c"), []byte("pending c")); err != nil {
+		t.Fatalf("Unexpected error on db.Put: %s", err)
+	} else if err := db.Delete([]byte("cc")); err != nil {
+		t.Fatalf("Unexpected error on db.Delete: %s", err)
+	} else if err := db.Put([]byte("e"), []byte("pending e")); err != nil {
+		t.Fatalf("Unexpected error on db.Put: %s", err)
+	}
+
+	values := map[string]string{
+		"a":  "committed a",
+		"bb": "pending bb",
+		"c":  "pending c",
+		"d":  "committed d",
+		"e":  "pending e",
+	}
+	assertIterates(t, db.NewIterator(), []string{"a", "bb", "c", "d", "e"}, values)
+	assertIterates(t, db.NewIteratorWithStart([]byte("c")), []string{"c", "d", "e"}, values)
+	assertIterates(t, db.NewIteratorWithPrefix([]byte("b")), []string{"bb"}, values)
+	assertIterates(t, db.NewIteratorWithStartAndPrefix([]byte("bc"), []byte("b")), nil, values)
+
+	// The iterator holds the writes pending when it was created
+	iterator := db.NewIterator()
+	if err := db.Put([]byte("aa"), []byte("pending aa")); err != nil {
+		t.Fatalf("Unexpected error on db.Put: %s", err)
+	}
+	assertIterates(t, iterator, []string{"a", "bb", "c", "d", "e"}, values)
+
+	// Committing doesn't change the merged view
+	values["aa"] = "pending aa"
+	if err := db.Commit(); err != nil {
+		t.Fatalf("Unexpected error on db.Commit: %s", err)
+	}
+	assertIterates(t, db.NewIterator(), []string{"a", "aa", "bb", "c", "d", "e"}, values)
+}
+
+// reusingDB is a database whose iterators, like LevelDB's, return keys and
+// values in buffers that are overwritten when they're advanced
+type reusingDB struct{ database.Database }
+
+func (db *reusingDB) NewIteratorWithStartAndPrefix(start, prefix []byte) database.Iterator {
+	return &reusingIterator{Iterator: db.Database.NewIteratorWithStartAndPrefix(start, prefix)}
+}
+
+type reusingIterator struct {
+	database.Iterator
+	key, value []byte
+}
+
+func (it *reusingIterator) Next() bool {
+	next := it.Iterator.Next()
+	it.key = append(it.key[:0], it.Iterator.Key()...)
+	it.value = append(it.value[:0], it.Iterator.Value()...)
+	return next
+}
+
+func (it *reusingIterator) Key() []byte   { return it.key }
+func (it *reusingIterator) Value() []byte { return it.value }
+
+func TestIterateReusedBuffers(t *testing.T) {
+	baseDB := memdb.New()
+	db := New(&reusingDB{Database: baseDB})
+
+	values := map[string]string{
+		"a": "value a",
+		"b": "value b",
+		"c": "value c",
+	}
+	for key, value := range values {
+		if err := baseDB.Put([]byte(key), []byte(value)); err != nil {
+			t.Fatalf("Unexpected error on db.Put: %s", err)
+		}
+	}
+
+	iterator := db.NewIterator()
+	defer iterator.Release()
+
+	// The pairs are held onto while the iterator is advanced
+	keys := [][]byte(nil)
+	vals := [][]byte(nil)
+	for iterator.Next() {
+		keys = append(keys, iterator.Key())
+		vals = append(vals, iterator.Value())
+	}
+	if err := iterator.Error(); err != nil {
+		t.Fatalf("iterator.Error Returned: %s ; Expected: nil", err)
+	}
+	for i, key := range []string{"a", "b", "c"} {
+		if string(keys[i]) != key {
+			t.Fatalf("Key %d was: %s ; Expected: %s", i, keys[i], key)
+		} else if string(vals[i]) != values[key] {
+			t.Fatalf("Value %d was: %s ; Expected: %s", i, vals[i], values[key])
+		}
+	}
+}
+
+// erroringDB is a database whose iterators fail
+type erroringDB struct {
+	database.Database
+	err error
+}
+
+func (db *erroringDB) NewIteratorWithStartAndPrefix(start, prefix []byte) database.Iterator {
+	return &nodb.Iterator{Err: db.err}
+}
+
+func TestIterateUnderlyingError(t *testing.T) {
+	errIterate := errors.New("iterate failed")
+	db := New(&erroringDB{Database: memdb.New(), err: errIterate})
+
+	if err := db.Put([]byte("pending"), []byte("value")); err != nil {
+		t.Fatalf("Unexpected error on db.Put: %s", err)
+	}
+
+	iterator := db.NewIterator()
+	defer iterator.Release()
+
+	// The pending key isn't returned, as it can't be merged with the
+	// underlying database
+	if iterator.Next() {
+		t.Fatalf("iterator.Next Returned: %v ; Expected: %v", true, false)
+	} else if err := iterator.Error(); err != errIterate {
+		t.Fatalf("iterator.Error Returned: %v ; Expected: %s", err, errIterate)
+	}
+}
+
 func TestCommit(t *testing.T) {
 	baseDB := memdb.New()
 	db := New(baseDB)
@@ -209,6 +375,68 @@ func TestCommit(t *testing.T) {
 	}
 }
 
+func TestCommitBatch(t *testing.T) {
+	baseDB := memdb.New()
+	db := New(baseDB)
+
+	key1 := []byte("hello1")
+	value1 := []byte("world1")
+
+	if err := db.Put(key1, value1); err != nil {
+		t.Fatalf("Unexpected error on db.Put: %s", err)
+	}
+
+	batch, err := db.CommitBatch()
+	if err != nil {
+		t.Fatalf("Unexpected error on db.CommitBatch: %s", err)
+	}
+	if has, err := baseDB.Has(key1); err != nil {
+		t.Fatalf("Unexpected error on db.Has: %s", err)
+	} else if has {
+		t.Fatalf("Shouldn't have written to the underlying database before the batch was written")
+	}
+
+	if err := batch.Write(); err != nil {
+		t.Fatalf("Unexpected error on batch.Write: %s", err)
+	}
+	db.Abort()
+
+	if value, err := baseDB.Get(key1); err != nil {
+		t.Fatalf("Unexpected error on db.Get: %s", err)
+	} else if !bytes.Equal(value, value1) {
+		t.Fatalf("db.Get Returned: 0x%x ; Expected: 0x%x", value, value1)
+	} else if value, err := db.Get(key1); err != nil {
+		t.Fatalf("Unexpected error on db.Get: %s", err)
+	} else if !bytes.Equal(value, value1) {
+		t.Fatalf("db.Get Returned: 0x%x ; Expected: 0x%x", value, value1)
+	}
+}
+
+func TestAbort(t *testing.T) {
+	baseDB := memdb.New()
+	db := New(baseDB)
+
+	key1 := []byte("hello1")
+	value1 := []byte("world1")
+
+	if err := db.Put(key1, value1); err != nil {
+		t.Fatalf("Unexpected error on db.Put: %s", err)
+	}
+	db.Abort()
+
+	if has, err := db.Has(key1); err != nil {
+		t.Fatalf("Unexpected error on db.Has: %s", err)
+	} else if has {
+		t.Fatalf("db.Has Returned: %v ; Expected: %v", has, false)
+	} else if err := db.Commit(); err != nil {
+		t.Fatalf("Unexpected error on db.Commit: %s", err)
+	} else if has, err := baseDB.Has(key1); err != nil {
+		t.Fatalf("Unexpected error on db.Has: %s", err)
+	} else if has {
+		t.Fatalf("Shouldn't have committed an aborted write")
+	}
+}
+
 func TestCommitClosed(t *testing.T) {
 	baseDB := memdb.New()
 	db := New(baseDB)
@@ -0,0 +1,86 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package e2e
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+const (
+	// requestTimeout is how long an API call may take before it fails
+	requestTimeout = 10 * time.Second
+)
+
+// APIError is an error returned by a node's API
+type APIError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *APIError) Error() string { return fmt.Sprintf("API error %d: %s", e.Code, e.Message) }
+
+type rpcRequest struct {
+	JSONRPC string      `json:"jsonrpc"`
+	ID      uint64      `json:"id"`
+	Method  string      `json:"method"`
+	Params  interface{} `json:"params"`
+}
+
+type rpcResponse struct {
+	Result json.RawMessage `json:"result"`
+	Error  *APIError       `json:"error"`
+}
+
+// Client calls the JSON-RPC APIs of a node
+type Client struct {
+	uri        string
+	httpClient http.Client
+	nextID     uint64
+}
+
+// NewClient returns a client of the node whose HTTP server is at [uri], such
+// as "http://127.0.0.1:9650"
+func NewClient(uri string) *Client {
+	return &Client{
+		uri:        uri,
+		httpClient: http.Client{Timeout: requestTimeout},
+	}
+}
+
+// Call [method], such as "avm.getBalance", of the API at [endpoint], such as
+// "/ext/bc/X". [reply] is filled in with the method's result.
+func (c *Client) Call(endpoint, method string, args, reply interface{}) error {
+	c.nextID++
+	body, err := json.Marshal(rpcRequest{
+		JSONRPC: "2.0",
+		ID:      c.nextID,
+		Method:  method,
+		Params:  args,
+	})
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.httpClient.Post(c.uri+endpoint, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	response := rpcResponse{}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return fmt.Errorf("couldn't decode the response to %s: %w", method, err)
+	}
+	if response.Error != nil {
+		return response.Error
+	}
+	if reply == nil {
+		return nil
+	}
+	return json.Unmarshal(response.Result, reply)
+}
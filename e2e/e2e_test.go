@@ -0,0 +1,176 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// +build e2e
+
+package e2e
+
+import (
+	"flag"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/gecko/api/keystore"
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow/choices"
+	"github.com/ava-labs/gecko/utils/json"
+	"github.com/ava-labs/gecko/vms/avm"
+)
+
+const (
+	numNodes     = 5
+	readyTimeout = 2 * time.Minute
+	txTimeout    = 30 * time.Second
+
+	username = "e2e"
+	password = "N_+=_jJ;^(<;{4,:*m6CET}'&N;83FYK.wtNpwp-Jt"
+
+	// fundedKey is the private key of the local network's funded address
+	fundedKey = "ewoqjP7PxY4yr3iLTpLisriqt94hdyDFNgchSxGGztUrTXtNN"
+)
+
+var (
+	binary  = flag.String("binary", "../build/ava", "path of the node's executable")
+	keysDir = flag.String("keys", "../keys", "directory holding the genesis stakers' keys")
+)
+
+// startNetwork starts a network of every genesis staker and waits until it's
+// ready
+func startNetwork(t *testing.T) *Network {
+	net, err := NewNetwork(Config{
+		Binary:   *binary,
+		NumNodes: numNodes,
+		KeysDir:  *keysDir,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := net.WaitReady(readyTimeout); err != nil {
+		stopNetwork(t, net)
+		t.Fatal(err)
+	}
+	return net
+}
+
+// stopNetwork stops [net]. Its logs are kept if the test failed.
+func stopNetwork(t *testing.T, net *Network) {
+	if err := net.Stop(); err != nil {
+		t.Error(err)
+	}
+	if t.Failed() {
+		t.Logf("Node logs are in %s", net.Logs())
+		return
+	}
+	if err := net.Remove(); err != nil {
+		t.Error(err)
+	}
+}
+
+// createUser creates a keystore user on [node]
+func createUser(t *testing.T, node *Node) {
+	reply := keystore.CreateUserReply{}
+	err := node.Call("/ext/keystore", "keystore.createUser", &keystore.CreateUserArgs{
+		Username: username,
+		Password: password,
+	}, &reply)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// waitAccepted waits until every node has accepted [txID]
+func waitAccepted(t *testing.T, net *Network, txID ids.ID) {
+	err := net.WaitAll(txTimeout, func(node *Node) (bool, error) {
+		reply := avm.GetTxStatusReply{}
+		if err := node.Call("/ext/bc/X", "avm.getTxStatus", &avm.GetTxStatusArgs{TxID: txID}, &reply); err != nil {
+			return false, err
+		}
+		switch reply.Status {
+		case choices.Accepted:
+			return true, nil
+		case choices.Rejected:
+			return false, fmt.Errorf("tx %s was rejected", txID)
+		default:
+			return false, nil
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// An asset is created on one node and sent to an address of another node.
+// Every node should agree on the resulting balance.
+func TestSendAsset(t *testing.T) {
+	net := startNetwork(t)
+	defer stopNetwork(t, net)
+
+	sender, receiver := net.Nodes[0], net.Nodes[1]
+
+	createUser(t, sender)
+	importReply := avm.ImportKeyReply{}
+	err := sender.Call("/ext/bc/X", "avm.importKey", &avm.ImportKeyArgs{
+		Username:   username,
+		Password:   password,
+		PrivateKey: fundedKey,
+	}, &importReply)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	createReply := avm.CreateFixedCapAssetReply{}
+	err = sender.Call("/ext/bc/X", "avm.createFixedCapAsset", &avm.CreateFixedCapAssetArgs{
+		Username: username,
+		Password: password,
+		Name:     "End to End",
+		Symbol:   "ETE",
+		InitialHolders: []*avm.Holder{&avm.Holder{
+			Amount:  1000,
+			Address: importReply.Address,
+		}},
+	}, &createReply)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, txID := range createReply.TxIDs {
+		waitAccepted(t, net, txID)
+	}
+
+	createUser(t, receiver)
+	addressReply := avm.CreateAddressReply{}
+	err = receiver.Call("/ext/bc/X", "avm.createAddress", &avm.CreateAddressArgs{
+		Username: username,
+		Password: password,
+	}, &addressReply)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sendReply := avm.SendReply{}
+	err = sender.Call("/ext/bc/X", "avm.send", &avm.SendArgs{
+		Username: username,
+		Password: password,
+		Amount:   json.Uint64(300),
+		AssetID:  createReply.AssetID.String(),
+		To:       addressReply.Address,
+	}, &sendReply)
+	if err != nil {
+		t.Fatal(err)
+	}
+	waitAccepted(t, net, sendReply.TxID)
+
+	for i, node := range net.Nodes {
+		balanceReply := avm.GetBalanceReply{}
+		err := node.Call("/ext/bc/X", "avm.getBalance", &avm.GetBalanceArgs{
+			Address: addressReply.Address,
+			AssetID: createReply.AssetID.String(),
+		}, &balanceReply)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if balanceReply.Balance != 300 {
+			t.Fatalf("Node %d reports a balance of %d but it should be 300", i, balanceReply.Balance)
+		}
+	}
+}
@@ -0,0 +1,261 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package e2e runs networks of nodes on this machine so that tests can drive
+// them through their APIs.
+//
+// Each node runs in its own process, as the networking layer only supports one
+// node per process. The nodes stake with the certificates of the local
+// network's genesis stakers, so a network of every genesis staker reaches
+// consensus on its own.
+package e2e
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ava-labs/gecko/utils/wrappers"
+)
+
+const (
+	// pollInterval is the time between checks of a node's state
+	pollInterval = 250 * time.Millisecond
+)
+
+var (
+	errNoNodes        = errors.New("a network must have at least one node")
+	errNotEnoughKeys  = errors.New("there are fewer staking keys than nodes")
+	errTimedOut       = errors.New("timed out")
+	errNodeNotRunning = errors.New("node exited")
+)
+
+// Config describes a network
+type Config struct {
+	// Binary is the path of the node's executable
+	Binary string
+
+	// NumNodes is the number of nodes in the network. It must be at most the
+	// number of staking keys.
+	NumNodes int
+
+	// KeysDir holds a directory for each genesis staker, in the order of
+	// the nodes, holding staker.key and staker.crt
+	KeysDir string
+
+	// Dir holds the nodes' databases and logs. If empty, a temporary
+	// directory is used.
+	Dir string
+
+	// Args are passed to every node, in addition to the flags set by the
+	// network
+	Args []string
+}
+
+// Network of nodes running on this machine
+type Network struct {
+	Nodes []*Node
+
+	config  Config
+	tempDir bool
+}
+
+// NewNetwork starts a network described by [config]. The first node is the
+// bootstrap node of every other node.
+func NewNetwork(config Config) (*Network, error) {
+	if config.NumNodes <= 0 {
+		return nil, errNoNodes
+	}
+
+	keyDirs, err := stakingKeyDirs(config.KeysDir)
+	if err != nil {
+		return nil, err
+	}
+	if len(keyDirs) < config.NumNodes {
+		return nil, fmt.Errorf("%w: %d keys for %d nodes", errNotEnoughKeys, len(keyDirs), config.NumNodes)
+	}
+
+	n := &Network{config: config}
+	if n.config.Dir == "" {
+		dir, err := ioutil.TempDir("", "gecko-e2e")
+		if err != nil {
+			return nil, err
+		}
+		n.config.Dir = dir
+		n.tempDir = true
+	}
+
+	for i := 0; i < config.NumNodes; i++ {
+		node, err := n.newNode(i, keyDirs[i])
+		if err != nil {
+			n.Remove()
+			return nil, err
+		}
+		n.Nodes = append(n.Nodes, node)
+	}
+
+	// Every node must be able to sample every other node
+	quorum := config.NumNodes/2 + 1
+	args := []string{
+		"--network-id=local",
+		"--public-ip=127.0.0.1",
+		fmt.Sprintf("--snow-sample-size=%d", config.NumNodes),
+		fmt.Sprintf("--snow-quorum-size=%d", quorum),
+	}
+	args = append(args, config.Args...)
+
+	bootstrap := n.Nodes[0]
+	for i, node := range n.Nodes {
+		nodeArgs := args
+		if i != 0 {
+			nodeArgs = append([]string{
+				fmt.Sprintf("--bootstrap-ips=%s", bootstrap.StakingAddr()),
+				fmt.Sprintf("--bootstrap-ids=%s", bootstrap.ID),
+			}, args...)
+		}
+		if err := node.start(config.Binary, nodeArgs); err != nil {
+			n.Stop()
+			n.Remove()
+			return nil, err
+		}
+	}
+	return n, nil
+}
+
+func (n *Network) newNode(index int, keyDir string) (*Node, error) {
+	certFile := filepath.Join(keyDir, "staker.crt")
+	id, err := nodeIDFromCert(certFile)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't read the staking certificate of node %d: %w", index, err)
+	}
+
+	dir := filepath.Join(n.config.Dir, fmt.Sprintf("node%d", index))
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+
+	httpPort, err := freePort()
+	if err != nil {
+		return nil, err
+	}
+	stakingPort, err := freePort()
+	if err != nil {
+		return nil, err
+	}
+
+	node := &Node{
+		ID:          id,
+		HTTPPort:    httpPort,
+		StakingPort: stakingPort,
+		Dir:         dir,
+		keyFile:     filepath.Join(keyDir, "staker.key"),
+		certFile:    certFile,
+	}
+	node.client = NewClient(node.URI())
+	return node, nil
+}
+
+// WaitReady waits until every node is serving its API and is connected to
+// every other node
+func (n *Network) WaitReady(timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for i, node := range n.Nodes {
+		err := poll(deadline, func() (bool, error) {
+			if !node.Running() {
+				return false, fmt.Errorf("%w: %v", errNodeNotRunning, node.exitErr)
+			}
+			reply := struct {
+				Peers []string `json:"peers"`
+			}{}
+			if err := node.Call("/ext/admin", "admin.peers", struct{}{}, &reply); err != nil {
+				return false, nil // The API may not be up yet
+			}
+			return len(reply.Peers) >= len(n.Nodes)-1, nil
+		})
+		if err != nil {
+			return fmt.Errorf("node %d isn't ready: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// WaitAll waits until [f] returns true for every node. [f] is retried until
+// it returns true, returns an error or the timeout passes.
+func (n *Network) WaitAll(timeout time.Duration, f func(node *Node) (bool, error)) error {
+	deadline := time.Now().Add(timeout)
+	for i, node := range n.Nodes {
+		if err := poll(deadline, func() (bool, error) { return f(node) }); err != nil {
+			return fmt.Errorf("node %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// Stop every node
+func (n *Network) Stop() error {
+	errs := wrappers.Errs{}
+	for _, node := range n.Nodes {
+		errs.Add(node.stop())
+	}
+	return errs.Err
+}
+
+// Remove the network's directory if it was temporary. Should only be called
+// after the network is stopped.
+func (n *Network) Remove() error {
+	if !n.tempDir {
+		return nil
+	}
+	return os.RemoveAll(n.config.Dir)
+}
+
+// Logs returns the directories holding the nodes' logs, to be reported when a
+// test fails
+func (n *Network) Logs() string {
+	dirs := []string(nil)
+	for _, node := range n.Nodes {
+		dirs = append(dirs, node.Dir)
+	}
+	return strings.Join(dirs, ", ")
+}
+
+// stakingKeyDirs returns the sorted directories in [keysDir] that hold a
+// staking key and certificate
+func stakingKeyDirs(keysDir string) ([]string, error) {
+	entries, err := ioutil.ReadDir(keysDir)
+	if err != nil {
+		return nil, err
+	}
+	dirs := []string(nil)
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		dir := filepath.Join(keysDir, entry.Name())
+		if _, err := os.Stat(filepath.Join(dir, "staker.crt")); err != nil {
+			continue
+		}
+		dirs = append(dirs, dir)
+	}
+	return dirs, nil
+}
+
+// poll calls [f] until it returns true or an error, or [deadline] passes
+func poll(deadline time.Time, f func() (bool, error)) error {
+	for {
+		done, err := f()
+		switch {
+		case err != nil:
+			return err
+		case done:
+			return nil
+		case time.Now().After(deadline):
+			return errTimedOut
+		}
+		time.Sleep(pollInterval)
+	}
+}
@@ -0,0 +1,153 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package e2e
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/hashing"
+)
+
+const (
+	// stopTimeout is how long a node is given to shut down gracefully before
+	// it's killed
+	stopTimeout = 10 * time.Second
+)
+
+var (
+	errNoPEM = errors.New("staking certificate isn't PEM encoded")
+)
+
+// Node is a node of a Network, running in its own process
+type Node struct {
+	// ID of this node, derived from its staking certificate
+	ID ids.ShortID
+
+	HTTPPort    uint16
+	StakingPort uint16
+
+	// Dir holds this node's database and logs
+	Dir string
+
+	keyFile, certFile string
+
+	cmd     *exec.Cmd
+	done    chan struct{}
+	exitErr error
+
+	client *Client
+}
+
+// URI of this node's HTTP server
+func (n *Node) URI() string { return fmt.Sprintf("http://127.0.0.1:%d", n.HTTPPort) }
+
+// StakingAddr is the address this node accepts staking connections on
+func (n *Node) StakingAddr() string { return fmt.Sprintf("127.0.0.1:%d", n.StakingPort) }
+
+// Call [method] of the API at [endpoint] on this node. See Client.Call.
+func (n *Node) Call(endpoint, method string, args, reply interface{}) error {
+	return n.client.Call(endpoint, method, args, reply)
+}
+
+// Running returns true if this node's process hasn't exited
+func (n *Node) Running() bool {
+	if n.cmd == nil {
+		return false
+	}
+	select {
+	case <-n.done:
+		return false
+	default:
+		return true
+	}
+}
+
+// start this node's process with [args] in addition to the node's own flags
+func (n *Node) start(binary string, args []string) error {
+	logFile, err := os.Create(filepath.Join(n.Dir, "stdout.log"))
+	if err != nil {
+		return err
+	}
+
+	nodeArgs := []string{
+		fmt.Sprintf("--http-port=%d", n.HTTPPort),
+		fmt.Sprintf("--staking-port=%d", n.StakingPort),
+		fmt.Sprintf("--staking-tls-key-file=%s", n.keyFile),
+		fmt.Sprintf("--staking-tls-cert-file=%s", n.certFile),
+		fmt.Sprintf("--db-dir=%s", filepath.Join(n.Dir, "db")),
+		fmt.Sprintf("--log-dir=%s", filepath.Join(n.Dir, "logs")),
+	}
+	n.cmd = exec.Command(binary, append(nodeArgs, args...)...)
+	n.cmd.Stdout = logFile
+	n.cmd.Stderr = logFile
+	if err := n.cmd.Start(); err != nil {
+		logFile.Close()
+		return fmt.Errorf("couldn't start node %s: %w", n.ID, err)
+	}
+
+	n.done = make(chan struct{})
+	go func() {
+		n.exitErr = n.cmd.Wait()
+		logFile.Close()
+		close(n.done)
+	}()
+	return nil
+}
+
+// stop this node's process, killing it if it doesn't shut down in time
+func (n *Node) stop() error {
+	if !n.Running() {
+		return nil
+	}
+	if err := n.cmd.Process.Signal(os.Interrupt); err != nil {
+		return err
+	}
+	select {
+	case <-n.done:
+	case <-time.After(stopTimeout):
+		if err := n.cmd.Process.Kill(); err != nil {
+			return err
+		}
+		<-n.done
+	}
+	return nil
+}
+
+// nodeIDFromCert returns the ID of the node that stakes with the certificate
+// in [certFile]
+func nodeIDFromCert(certFile string) (ids.ShortID, error) {
+	certBytes, err := ioutil.ReadFile(certFile)
+	if err != nil {
+		return ids.ShortID{}, err
+	}
+	block, _ := pem.Decode(certBytes)
+	if block == nil {
+		return ids.ShortID{}, errNoPEM
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return ids.ShortID{}, err
+	}
+	return ids.ToShortID(hashing.PubkeyBytesToAddress(cert.Raw))
+}
+
+// freePort returns a port that is currently unused
+func freePort() (uint16, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return 0, err
+	}
+	defer listener.Close()
+	return uint16(listener.Addr().(*net.TCPAddr).Port), nil
+}
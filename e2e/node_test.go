@@ -0,0 +1,56 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package e2e
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+// The staking keys in the repository belong to the local network's genesis
+// stakers
+func TestStakingKeyDirs(t *testing.T) {
+	expected := []string{
+		"7Xhw2mDxuDS44j42TCB6U5579esbSt3Lg",
+		"MFrZFVCXPv5iCn6M9K6XduxGTYp891xXZ",
+		"NFBbbJ4qCmNaCzeW7sxErhvWqvEQMnYcN",
+		"GWPcbFJZFfZreETSoWjPimr846mXEKCtu",
+		"P7oB2McjBGgW2NXXWVYjV8JEDFoW9xDE5",
+	}
+
+	dirs, err := stakingKeyDirs("../keys")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dirs) != len(expected) {
+		t.Fatalf("Expected %d staking key directories but found %d", len(expected), len(dirs))
+	}
+	for i, dir := range dirs {
+		id, err := nodeIDFromCert(filepath.Join(dir, "staker.crt"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if id.String() != expected[i] {
+			t.Fatalf("Staker %d should have ID %s but has ID %s", i, expected[i], id)
+		}
+	}
+}
+
+func TestNodeIDFromCertNotPEM(t *testing.T) {
+	if _, err := nodeIDFromCert("../keys/keys1/genCA.sh"); err != errNoPEM {
+		t.Fatalf("Expected %s but got %v", errNoPEM, err)
+	}
+}
+
+func TestNewNetworkNotEnoughKeys(t *testing.T) {
+	_, err := NewNetwork(Config{
+		Binary:   "ava",
+		NumNodes: 6,
+		KeysDir:  "../keys",
+	})
+	if !errors.Is(err, errNotEnoughKeys) {
+		t.Fatalf("Expected %s but got %v", errNotEnoughKeys, err)
+	}
+}
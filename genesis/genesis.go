@@ -150,27 +150,25 @@ func Aliases(networkID uint32) (generalAliases map[string][]string, chainAliases
 	}
 
 	genesisBytes := Genesis(networkID)
-	genesis := &platformvm.Genesis{}                  // TODO let's not re-create genesis to do aliasing
-	platformvm.Codec.Unmarshal(genesisBytes, genesis) // TODO check for error
-	genesis.Initialize()
+	genesis, _ := platformvm.ParseGenesis(genesisBytes) // TODO let's not re-create genesis to do aliasing, and check for error
 
 	for _, chain := range genesis.Chains {
 		switch {
 		case avm.ID.Equals(chain.VMID):
-			generalAliases["bc/"+chain.ID().String()] = []string{"X", "avm", "bc/X", "bc/avm"}
-			chainAliases[chain.ID().Key()] = []string{"X", "avm"}
+			generalAliases["bc/"+chain.ChainID().String()] = []string{"X", "avm", "bc/X", "bc/avm"}
+			chainAliases[chain.ChainID().Key()] = []string{"X", "avm"}
 		case evm.ID.Equals(chain.VMID):
-			generalAliases["bc/"+chain.ID().String()] = []string{"C", "evm", "bc/C", "bc/evm"}
-			chainAliases[chain.ID().Key()] = []string{"C", "evm"}
+			generalAliases["bc/"+chain.ChainID().String()] = []string{"C", "evm", "bc/C", "bc/evm"}
+			chainAliases[chain.ChainID().Key()] = []string{"C", "evm"}
 		case spdagvm.ID.Equals(chain.VMID):
-			generalAliases["bc/"+chain.ID().String()] = []string{"bc/spdag"}
-			chainAliases[chain.ID().Key()] = []string{"spdag"}
+			generalAliases["bc/"+chain.ChainID().String()] = []string{"bc/spdag"}
+			chainAliases[chain.ChainID().Key()] = []string{"spdag"}
 		case spchainvm.ID.Equals(chain.VMID):
-			generalAliases["bc/"+chain.ID().String()] = []string{"bc/spchain"}
-			chainAliases[chain.ID().Key()] = []string{"spchain"}
+			generalAliases["bc/"+chain.ChainID().String()] = []string{"bc/spchain"}
+			chainAliases[chain.ChainID().Key()] = []string{"spchain"}
 		case timestampvm.ID.Equals(chain.VMID):
-			generalAliases["bc/"+chain.ID().String()] = []string{"bc/timestamp"}
-			chainAliases[chain.ID().Key()] = []string{"timestamp"}
+			generalAliases["bc/"+chain.ChainID().String()] = []string{"bc/timestamp"}
+			chainAliases[chain.ChainID().Key()] = []string{"timestamp"}
 		}
 	}
 	return
@@ -320,62 +318,62 @@ func Genesis(networkID uint32) []byte {
 		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
 		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
 		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
-		0x00, 0x00, 0x30, 0x39, 0x00, 0x00, 0x00, 0x00,
-		0x00, 0x00, 0x00, 0x00, 0x00, 0x08, 0x41, 0x74,
-		0x68, 0x65, 0x72, 0x65, 0x75, 0x6d, 0x65, 0x76,
-		0x6d, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x30, 0x39, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x08, 0x41,
+		0x74, 0x68, 0x65, 0x72, 0x65, 0x75, 0x6d, 0x65,
+		0x76, 0x6d, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
 		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
 		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
 		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
-		0x00, 0x00, 0x00, 0x00, 0x02, 0xc9, 0x7b, 0x22,
-		0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x22, 0x3a,
-		0x7b, 0x22, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x49,
-		0x64, 0x22, 0x3a, 0x34, 0x33, 0x31, 0x31, 0x30,
-		0x2c, 0x22, 0x68, 0x6f, 0x6d, 0x65, 0x73, 0x74,
-		0x65, 0x61, 0x64, 0x42, 0x6c, 0x6f, 0x63, 0x6b,
-		0x22, 0x3a, 0x30, 0x2c, 0x22, 0x64, 0x61, 0x6f,
-		0x46, 0x6f, 0x72, 0x6b, 0x42, 0x6c, 0x6f, 0x63,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xc9, 0x7b,
+		0x22, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x22,
+		0x3a, 0x7b, 0x22, 0x63, 0x68, 0x61, 0x69, 0x6e,
+		0x49, 0x64, 0x22, 0x3a, 0x34, 0x33, 0x31, 0x31,
+		0x30, 0x2c, 0x22, 0x68, 0x6f, 0x6d, 0x65, 0x73,
+		0x74, 0x65, 0x61, 0x64, 0x42, 0x6c, 0x6f, 0x63,
 		0x6b, 0x22, 0x3a, 0x30, 0x2c, 0x22, 0x64, 0x61,
-		0x6f, 0x46, 0x6f, 0x72, 0x6b, 0x53, 0x75, 0x70,
-		0x70, 0x6f, 0x72, 0x74, 0x22, 0x3a, 0x74, 0x72,
-		0x75, 0x65, 0x2c, 0x22, 0x65, 0x69, 0x70, 0x31,
-		0x35, 0x30, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x22,
-		0x3a, 0x30, 0x2c, 0x22, 0x65, 0x69, 0x70, 0x31,
-		0x35, 0x30, 0x48, 0x61, 0x73, 0x68, 0x22, 0x3a,
-		0x22, 0x30, 0x78, 0x32, 0x30, 0x38, 0x36, 0x37,
-		0x39, 0x39, 0x61, 0x65, 0x65, 0x62, 0x65, 0x61,
-		0x65, 0x31, 0x33, 0x35, 0x63, 0x32, 0x34, 0x36,
-		0x63, 0x36, 0x35, 0x30, 0x32, 0x31, 0x63, 0x38,
-		0x32, 0x62, 0x34, 0x65, 0x31, 0x35, 0x61, 0x32,
-		0x63, 0x34, 0x35, 0x31, 0x33, 0x34, 0x30, 0x39,
-		0x39, 0x33, 0x61, 0x61, 0x63, 0x66, 0x64, 0x32,
-		0x37, 0x35, 0x31, 0x38, 0x38, 0x36, 0x35, 0x31,
-		0x34, 0x66, 0x30, 0x22, 0x2c, 0x22, 0x65, 0x69,
-		0x70, 0x31, 0x35, 0x35, 0x42, 0x6c, 0x6f, 0x63,
-		0x6b, 0x22, 0x3a, 0x30, 0x2c, 0x22, 0x65, 0x69,
-		0x70, 0x31, 0x35, 0x38, 0x42, 0x6c, 0x6f, 0x63,
-		0x6b, 0x22, 0x3a, 0x30, 0x2c, 0x22, 0x62, 0x79,
-		0x7a, 0x61, 0x6e, 0x74, 0x69, 0x75, 0x6d, 0x42,
-		0x6c, 0x6f, 0x63, 0x6b, 0x22, 0x3a, 0x30, 0x2c,
-		0x22, 0x63, 0x6f, 0x6e, 0x73, 0x74, 0x61, 0x6e,
-		0x74, 0x69, 0x6e, 0x6f, 0x70, 0x6c, 0x65, 0x42,
-		0x6c, 0x6f, 0x63, 0x6b, 0x22, 0x3a, 0x30, 0x2c,
-		0x22, 0x70, 0x65, 0x74, 0x65, 0x72, 0x73, 0x62,
-		0x75, 0x72, 0x67, 0x42, 0x6c, 0x6f, 0x63, 0x6b,
-		0x22, 0x3a, 0x30, 0x7d, 0x2c, 0x22, 0x6e, 0x6f,
-		0x6e, 0x63, 0x65, 0x22, 0x3a, 0x22, 0x30, 0x78,
-		0x30, 0x22, 0x2c, 0x22, 0x74, 0x69, 0x6d, 0x65,
-		0x73, 0x74, 0x61, 0x6d, 0x70, 0x22, 0x3a, 0x22,
-		0x30, 0x78, 0x30, 0x22, 0x2c, 0x22, 0x65, 0x78,
-		0x74, 0x72, 0x61, 0x44, 0x61, 0x74, 0x61, 0x22,
-		0x3a, 0x22, 0x30, 0x78, 0x30, 0x30, 0x22, 0x2c,
-		0x22, 0x67, 0x61, 0x73, 0x4c, 0x69, 0x6d, 0x69,
-		0x74, 0x22, 0x3a, 0x22, 0x30, 0x78, 0x35, 0x66,
-		0x35, 0x65, 0x31, 0x30, 0x30, 0x22, 0x2c, 0x22,
-		0x64, 0x69, 0x66, 0x66, 0x69, 0x63, 0x75, 0x6c,
-		0x74, 0x79, 0x22, 0x3a, 0x22, 0x30, 0x78, 0x30,
-		0x22, 0x2c, 0x22, 0x6d, 0x69, 0x78, 0x48, 0x61,
-		0x73, 0x68, 0x22, 0x3a, 0x22, 0x30, 0x78, 0x30,
+		0x6f, 0x46, 0x6f, 0x72, 0x6b, 0x42, 0x6c, 0x6f,
+		0x63, 0x6b, 0x22, 0x3a, 0x30, 0x2c, 0x22, 0x64,
+		0x61, 0x6f, 0x46, 0x6f, 0x72, 0x6b, 0x53, 0x75,
+		0x70, 0x70, 0x6f, 0x72, 0x74, 0x22, 0x3a, 0x74,
+		0x72, 0x75, 0x65, 0x2c, 0x22, 0x65, 0x69, 0x70,
+		0x31, 0x35, 0x30, 0x42, 0x6c, 0x6f, 0x63, 0x6b,
+		0x22, 0x3a, 0x30, 0x2c, 0x22, 0x65, 0x69, 0x70,
+		0x31, 0x35, 0x30, 0x48, 0x61, 0x73, 0x68, 0x22,
+		0x3a, 0x22, 0x30, 0x78, 0x32, 0x30, 0x38, 0x36,
+		0x37, 0x39, 0x39, 0x61, 0x65, 0x65, 0x62, 0x65,
+		0x61, 0x65, 0x31, 0x33, 0x35, 0x63, 0x32, 0x34,
+		0x36, 0x63, 0x36, 0x35, 0x30, 0x32, 0x31, 0x63,
+		0x38, 0x32, 0x62, 0x34, 0x65, 0x31, 0x35, 0x61,
+		0x32, 0x63, 0x34, 0x35, 0x31, 0x33, 0x34, 0x30,
+		0x39, 0x39, 0x33, 0x61, 0x61, 0x63, 0x66, 0x64,
+		0x32, 0x37, 0x35, 0x31, 0x38, 0x38, 0x36, 0x35,
+		0x31, 0x34, 0x66, 0x30, 0x22, 0x2c, 0x22, 0x65,
+		0x69, 0x70, 0x31, 0x35, 0x35, 0x42, 0x6c, 0x6f,
+		0x63, 0x6b, 0x22, 0x3a, 0x30, 0x2c, 0x22, 0x65,
+		0x69, 0x70, 0x31, 0x35, 0x38, 0x42, 0x6c, 0x6f,
+		0x63, 0x6b, 0x22, 0x3a, 0x30, 0x2c, 0x22, 0x62,
+		0x79, 0x7a, 0x61, 0x6e, 0x74, 0x69, 0x75, 0x6d,
+		0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x22, 0x3a, 0x30,
+		0x2c, 0x22, 0x63, 0x6f, 0x6e, 0x73, 0x74, 0x61,
+		0x6e, 0x74, 0x69, 0x6e, 0x6f, 0x70, 0x6c, 0x65,
+		0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x22, 0x3a, 0x30,
+		0x2c, 0x22, 0x70, 0x65, 0x74, 0x65, 0x72, 0x73,
+		0x62, 0x75, 0x72, 0x67, 0x42, 0x6c, 0x6f, 0x63,
+		0x6b, 0x22, 0x3a, 0x30, 0x7d, 0x2c, 0x22, 0x6e,
+		0x6f, 0x6e, 0x63, 0x65, 0x22, 0x3a, 0x22, 0x30,
+		0x78, 0x30, 0x22, 0x2c, 0x22, 0x74, 0x69, 0x6d,
+		0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x22, 0x3a,
+		0x22, 0x30, 0x78, 0x30, 0x22, 0x2c, 0x22, 0x65,
+		0x78, 0x74, 0x72, 0x61, 0x44, 0x61, 0x74, 0x61,
+		0x22, 0x3a, 0x22, 0x30, 0x78, 0x30, 0x30, 0x22,
+		0x2c, 0x22, 0x67, 0x61, 0x73, 0x4c, 0x69, 0x6d,
+		0x69, 0x74, 0x22, 0x3a, 0x22, 0x30, 0x78, 0x35,
+		0x66, 0x35, 0x65, 0x31, 0x30, 0x30, 0x22, 0x2c,
+		0x22, 0x64, 0x69, 0x66, 0x66, 0x69, 0x63, 0x75,
+		0x6c, 0x74, 0x79, 0x22, 0x3a, 0x22, 0x30, 0x78,
+		0x30, 0x22, 0x2c, 0x22, 0x6d, 0x69, 0x78, 0x48,
+		0x61, 0x73, 0x68, 0x22, 0x3a, 0x22, 0x30, 0x78,
 		0x30, 0x30, 0x30, 0x30, 0x30, 0x30, 0x30, 0x30,
 		0x30, 0x30, 0x30, 0x30, 0x30, 0x30, 0x30, 0x30,
 		0x30, 0x30, 0x30, 0x30, 0x30, 0x30, 0x30, 0x30,
@@ -383,69 +381,69 @@ func Genesis(networkID uint32) []byte {
 		0x30, 0x30, 0x30, 0x30, 0x30, 0x30, 0x30, 0x30,
 		0x30, 0x30, 0x30, 0x30, 0x30, 0x30, 0x30, 0x30,
 		0x30, 0x30, 0x30, 0x30, 0x30, 0x30, 0x30, 0x30,
-		0x30, 0x30, 0x30, 0x30, 0x30, 0x30, 0x30, 0x22,
-		0x2c, 0x22, 0x63, 0x6f, 0x69, 0x6e, 0x62, 0x61,
-		0x73, 0x65, 0x22, 0x3a, 0x22, 0x30, 0x78, 0x30,
 		0x30, 0x30, 0x30, 0x30, 0x30, 0x30, 0x30, 0x30,
+		0x22, 0x2c, 0x22, 0x63, 0x6f, 0x69, 0x6e, 0x62,
+		0x61, 0x73, 0x65, 0x22, 0x3a, 0x22, 0x30, 0x78,
 		0x30, 0x30, 0x30, 0x30, 0x30, 0x30, 0x30, 0x30,
 		0x30, 0x30, 0x30, 0x30, 0x30, 0x30, 0x30, 0x30,
 		0x30, 0x30, 0x30, 0x30, 0x30, 0x30, 0x30, 0x30,
-		0x30, 0x30, 0x30, 0x30, 0x30, 0x30, 0x30, 0x22,
-		0x2c, 0x22, 0x61, 0x6c, 0x6c, 0x6f, 0x63, 0x22,
-		0x3a, 0x7b, 0x22, 0x37, 0x35, 0x31, 0x61, 0x30,
-		0x62, 0x39, 0x36, 0x65, 0x31, 0x30, 0x34, 0x32,
-		0x62, 0x65, 0x65, 0x37, 0x38, 0x39, 0x34, 0x35,
-		0x32, 0x65, 0x63, 0x62, 0x32, 0x30, 0x32, 0x35,
-		0x33, 0x66, 0x62, 0x61, 0x34, 0x30, 0x64, 0x62,
-		0x65, 0x38, 0x35, 0x22, 0x3a, 0x7b, 0x22, 0x62,
-		0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x22, 0x3a,
-		0x22, 0x30, 0x78, 0x33, 0x33, 0x62, 0x32, 0x65,
-		0x33, 0x63, 0x39, 0x66, 0x64, 0x30, 0x38, 0x30,
-		0x34, 0x30, 0x30, 0x30, 0x30, 0x30, 0x30, 0x30,
-		0x30, 0x30, 0x22, 0x7d, 0x7d, 0x2c, 0x22, 0x6e,
-		0x75, 0x6d, 0x62, 0x65, 0x72, 0x22, 0x3a, 0x22,
-		0x30, 0x78, 0x30, 0x22, 0x2c, 0x22, 0x67, 0x61,
-		0x73, 0x55, 0x73, 0x65, 0x64, 0x22, 0x3a, 0x22,
-		0x30, 0x78, 0x30, 0x22, 0x2c, 0x22, 0x70, 0x61,
-		0x72, 0x65, 0x6e, 0x74, 0x48, 0x61, 0x73, 0x68,
-		0x22, 0x3a, 0x22, 0x30, 0x78, 0x30, 0x30, 0x30,
 		0x30, 0x30, 0x30, 0x30, 0x30, 0x30, 0x30, 0x30,
 		0x30, 0x30, 0x30, 0x30, 0x30, 0x30, 0x30, 0x30,
+		0x22, 0x2c, 0x22, 0x61, 0x6c, 0x6c, 0x6f, 0x63,
+		0x22, 0x3a, 0x7b, 0x22, 0x37, 0x35, 0x31, 0x61,
+		0x30, 0x62, 0x39, 0x36, 0x65, 0x31, 0x30, 0x34,
+		0x32, 0x62, 0x65, 0x65, 0x37, 0x38, 0x39, 0x34,
+		0x35, 0x32, 0x65, 0x63, 0x62, 0x32, 0x30, 0x32,
+		0x35, 0x33, 0x66, 0x62, 0x61, 0x34, 0x30, 0x64,
+		0x62, 0x65, 0x38, 0x35, 0x22, 0x3a, 0x7b, 0x22,
+		0x62, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x22,
+		0x3a, 0x22, 0x30, 0x78, 0x33, 0x33, 0x62, 0x32,
+		0x65, 0x33, 0x63, 0x39, 0x66, 0x64, 0x30, 0x38,
+		0x30, 0x34, 0x30, 0x30, 0x30, 0x30, 0x30, 0x30,
+		0x30, 0x30, 0x30, 0x22, 0x7d, 0x7d, 0x2c, 0x22,
+		0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x22, 0x3a,
+		0x22, 0x30, 0x78, 0x30, 0x22, 0x2c, 0x22, 0x67,
+		0x61, 0x73, 0x55, 0x73, 0x65, 0x64, 0x22, 0x3a,
+		0x22, 0x30, 0x78, 0x30, 0x22, 0x2c, 0x22, 0x70,
+		0x61, 0x72, 0x65, 0x6e, 0x74, 0x48, 0x61, 0x73,
+		0x68, 0x22, 0x3a, 0x22, 0x30, 0x78, 0x30, 0x30,
 		0x30, 0x30, 0x30, 0x30, 0x30, 0x30, 0x30, 0x30,
 		0x30, 0x30, 0x30, 0x30, 0x30, 0x30, 0x30, 0x30,
 		0x30, 0x30, 0x30, 0x30, 0x30, 0x30, 0x30, 0x30,
 		0x30, 0x30, 0x30, 0x30, 0x30, 0x30, 0x30, 0x30,
 		0x30, 0x30, 0x30, 0x30, 0x30, 0x30, 0x30, 0x30,
-		0x30, 0x30, 0x30, 0x30, 0x30, 0x22, 0x7d, 0x00,
-		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
-		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x30, 0x30, 0x30, 0x30, 0x30, 0x30, 0x30, 0x30,
+		0x30, 0x30, 0x30, 0x30, 0x30, 0x30, 0x30, 0x30,
+		0x30, 0x30, 0x30, 0x30, 0x30, 0x30, 0x22, 0x7d,
 		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
 		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
 		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
 		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
 		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
 		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
-		0x00, 0x00, 0x30, 0x39, 0x00, 0x00, 0x00, 0x00,
-		0x00, 0x00, 0x00, 0x00, 0x00, 0x13, 0x53, 0x69,
-		0x6d, 0x70, 0x6c, 0x65, 0x20, 0x44, 0x41, 0x47,
-		0x20, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74,
-		0x73, 0x73, 0x70, 0x64, 0x61, 0x67, 0x76, 0x6d,
 		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
 		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x30, 0x39, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x13,
+		0x53, 0x69, 0x6d, 0x70, 0x6c, 0x65, 0x20, 0x44,
+		0x41, 0x47, 0x20, 0x50, 0x61, 0x79, 0x6d, 0x65,
+		0x6e, 0x74, 0x73, 0x73, 0x70, 0x64, 0x61, 0x67,
+		0x76, 0x6d, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
 		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
 		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
-		0x60, 0x00, 0x00, 0x00, 0x02, 0x00, 0x00, 0x00,
 		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x60, 0x00, 0x00, 0x00, 0x02, 0x00,
 		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
 		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
 		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
-		0x00, 0x00, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00,
-		0x00, 0x00, 0x00, 0x12, 0x30, 0x9c, 0xe5, 0x40,
 		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
-		0x00, 0x00, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00,
-		0x01, 0x3c, 0xb7, 0xd3, 0x84, 0x2e, 0x8c, 0xee,
-		0x6a, 0x0e, 0xbd, 0x09, 0xf1, 0xfe, 0x88, 0x4f,
-		0x68, 0x61, 0xe1, 0xb2, 0x9c, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x12, 0x30, 0x9c,
+		0xe5, 0x40, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01, 0x00,
+		0x00, 0x00, 0x01, 0x3c, 0xb7, 0xd3, 0x84, 0x2e,
+		0x8c, 0xee, 0x6a, 0x0e, 0xbd, 0x09, 0xf1, 0xfe,
+		0x88, 0x4f, 0x68, 0x61, 0xe1, 0xb2, 0x9c, 0x00,
 		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
 		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
 		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
@@ -454,21 +452,21 @@ func Genesis(networkID uint32) []byte {
 		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
 		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
 		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
-		0x00, 0x00, 0x00, 0x00, 0x30, 0x39, 0x00, 0x00,
-		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x15,
-		0x53, 0x69, 0x6d, 0x70, 0x6c, 0x65, 0x20, 0x43,
-		0x68, 0x61, 0x69, 0x6e, 0x20, 0x50, 0x61, 0x79,
-		0x6d, 0x65, 0x6e, 0x74, 0x73, 0x73, 0x70, 0x63,
-		0x68, 0x61, 0x69, 0x6e, 0x76, 0x6d, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x30,
+		0x39, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x15, 0x53, 0x69, 0x6d, 0x70, 0x6c,
+		0x65, 0x20, 0x43, 0x68, 0x61, 0x69, 0x6e, 0x20,
+		0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x73,
+		0x73, 0x70, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x76,
+		0x6d, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
 		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
 		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x28,
+		0x00, 0x00, 0x00, 0x01, 0x3c, 0xb7, 0xd3, 0x84,
+		0x2e, 0x8c, 0xee, 0x6a, 0x0e, 0xbd, 0x09, 0xf1,
+		0xfe, 0x88, 0x4f, 0x68, 0x61, 0xe1, 0xb2, 0x9c,
 		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
-		0x00, 0x00, 0x00, 0x00, 0x28, 0x00, 0x00, 0x00,
-		0x01, 0x3c, 0xb7, 0xd3, 0x84, 0x2e, 0x8c, 0xee,
-		0x6a, 0x0e, 0xbd, 0x09, 0xf1, 0xfe, 0x88, 0x4f,
-		0x68, 0x61, 0xe1, 0xb2, 0x9c, 0x00, 0x00, 0x00,
-		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x12,
-		0x30, 0x9c, 0xe5, 0x40, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x12, 0x30, 0x9c, 0xe5, 0x40, 0x00,
 		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
 		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
 		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
@@ -477,12 +475,12 @@ func Genesis(networkID uint32) []byte {
 		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
 		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
 		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
-		0x30, 0x39, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
-		0x00, 0x00, 0x00, 0x17, 0x53, 0x69, 0x6d, 0x70,
-		0x6c, 0x65, 0x20, 0x54, 0x69, 0x6d, 0x65, 0x73,
-		0x74, 0x61, 0x6d, 0x70, 0x20, 0x53, 0x65, 0x72,
-		0x76, 0x65, 0x72, 0x74, 0x69, 0x6d, 0x65, 0x73,
-		0x74, 0x61, 0x6d, 0x70, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x30, 0x39, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x17,
+		0x53, 0x69, 0x6d, 0x70, 0x6c, 0x65, 0x20, 0x54,
+		0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70,
+		0x20, 0x53, 0x65, 0x72, 0x76, 0x65, 0x72, 0x74,
+		0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70,
 		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
 		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
 		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
@@ -495,16 +493,16 @@ func Genesis(networkID uint32) []byte {
 		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
 		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
 		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
-		0x5d, 0xbb, 0x75, 0x80,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x5d, 0xbb, 0x75,
+		0x80,
 	}
 }
 
 // VMGenesis ...
 func VMGenesis(networkID uint32, vmID ids.ID) *platformvm.CreateChainTx {
 	genesisBytes := Genesis(networkID)
-	genesis := platformvm.Genesis{}
-	platformvm.Codec.Unmarshal(genesisBytes, &genesis)
-	if err := genesis.Initialize(); err != nil {
+	genesis, err := platformvm.ParseGenesis(genesisBytes)
+	if err != nil {
 		panic(err)
 	}
 	for _, chain := range genesis.Chains {
@@ -514,3 +512,17 @@ func VMGenesis(networkID uint32, vmID ids.ID) *platformvm.CreateChainTx {
 	}
 	return nil
 }
+
+// ComputeGenesisHash returns the canonical hash of a chain's genesis data
+func ComputeGenesisHash(genesisData []byte) ids.ID {
+	return platformvm.ComputeGenesisHash(genesisData)
+}
+
+// ComputeChainID returns the ID of the chain named [chainName] validated by the
+// subnet [subnetID] on the network [networkID] whose genesis data hashes to
+// [genesisHash]. On networks whose
+// genesis state enables deterministic chain IDs, this is the ID the chain
+// manager registers the chain under.
+func ComputeChainID(subnetID ids.ID, networkID uint32, genesisHash ids.ID, chainName string) ids.ID {
+	return platformvm.ComputeChainID(subnetID, networkID, genesisHash, chainName)
+}
@@ -30,7 +30,19 @@ func (a *Aliaser) Lookup(alias string) (ID, error) {
 }
 
 // Aliases returns the aliases of an ID
-func (a Aliaser) Aliases(id ID) []string { return a.aliases[id.Key()] }
+func (a Aliaser) Aliases(id ID) []string {
+	aliases := a.aliases[id.Key()]
+	return append(make([]string, 0, len(aliases)), aliases...)
+}
+
+// ListAll returns every alias and the ID it refers to
+func (a Aliaser) ListAll() map[string]ID {
+	all := make(map[string]ID, len(a.dealias))
+	for alias, id := range a.dealias {
+		all[alias] = id
+	}
+	return all
+}
 
 // PrimaryAlias returns the first alias of [id]
 func (a Aliaser) PrimaryAlias(id ID) (string, error) {
@@ -41,10 +53,11 @@ func (a Aliaser) PrimaryAlias(id ID) (string, error) {
 	return aliases[0], nil
 }
 
-// Alias gives [id] the alias [alias]
+// Alias gives [id] the alias [alias]. If [alias] is already in use, the error
+// names the ID that registered it first.
 func (a Aliaser) Alias(id ID, alias string) error {
-	if _, exists := a.dealias[alias]; exists {
-		return fmt.Errorf("%s is already used as an alias for an ID", alias)
+	if existing, exists := a.dealias[alias]; exists {
+		return fmt.Errorf("%s is already used as an alias of %s", alias, existing)
 	}
 	key := id.Key()
 
@@ -0,0 +1,100 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package ids
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAliaser(t *testing.T) {
+	id1 := NewID([32]byte{1})
+	id2 := NewID([32]byte{2})
+
+	a := Aliaser{}
+	a.Initialize()
+
+	if err := a.Alias(id1, "one"); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.Alias(id1, "uno"); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.Alias(id2, "two"); err != nil {
+		t.Fatal(err)
+	}
+
+	if id, err := a.Lookup("uno"); err != nil {
+		t.Fatal(err)
+	} else if !id.Equals(id1) {
+		t.Fatalf("Lookup returned %s but should have returned %s", id, id1)
+	}
+	if alias, err := a.PrimaryAlias(id1); err != nil {
+		t.Fatal(err)
+	} else if alias != "one" {
+		t.Fatalf("Primary alias should be one but is %s", alias)
+	}
+	if aliases := a.Aliases(id1); len(aliases) != 2 || aliases[0] != "one" || aliases[1] != "uno" {
+		t.Fatalf("Wrong aliases returned: %v", aliases)
+	}
+	if aliases := a.Aliases(NewID([32]byte{3})); len(aliases) != 0 {
+		t.Fatalf("An ID without aliases returned aliases: %v", aliases)
+	}
+
+	all := a.ListAll()
+	if len(all) != 3 {
+		t.Fatalf("Should have listed 3 aliases but listed %d", len(all))
+	}
+	if !all["one"].Equals(id1) || !all["uno"].Equals(id1) || !all["two"].Equals(id2) {
+		t.Fatalf("Wrong aliases listed: %v", all)
+	}
+}
+
+// The returned aliases shouldn't alias the aliaser's state
+func TestAliaserListsAreCopies(t *testing.T) {
+	id := NewID([32]byte{1})
+
+	a := Aliaser{}
+	a.Initialize()
+	if err := a.Alias(id, "one"); err != nil {
+		t.Fatal(err)
+	}
+
+	a.Aliases(id)[0] = "changed"
+	delete(a.ListAll(), "one")
+
+	if alias, err := a.PrimaryAlias(id); err != nil {
+		t.Fatal(err)
+	} else if alias != "one" {
+		t.Fatalf("Primary alias was modified to %s", alias)
+	}
+	if _, err := a.Lookup("one"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// A conflicting alias reports which ID already has the alias
+func TestAliaserConflict(t *testing.T) {
+	id1 := NewID([32]byte{1})
+	id2 := NewID([32]byte{2})
+
+	a := Aliaser{}
+	a.Initialize()
+	if err := a.Alias(id1, "one"); err != nil {
+		t.Fatal(err)
+	}
+
+	err := a.Alias(id2, "one")
+	if err == nil {
+		t.Fatalf("Should have failed to reuse an alias")
+	}
+	if !strings.Contains(err.Error(), id1.String()) {
+		t.Fatalf("Error should name the ID with the alias: %s", err)
+	}
+	if id, err := a.Lookup("one"); err != nil {
+		t.Fatal(err)
+	} else if !id.Equals(id1) {
+		t.Fatalf("Conflicting alias changed the aliased ID to %s", id)
+	}
+}
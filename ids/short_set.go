@@ -3,57 +3,86 @@
 
 package ids
 
-import "strings"
+import (
+	"bytes"
+	"sort"
+	"strings"
+)
 
-// ShortSet is a set of ShortIDs
-type ShortSet map[[20]byte]bool
+// maxSmallShortSetSize is the number of ids a ShortSet holds in a sorted slice
+// before switching to a map. Most sets hold only a few validators or
+// addresses, which are cheaper to search in a slice than to hash.
+const maxSmallShortSetSize = 8
 
-func (ids *ShortSet) init(size int) {
-	if *ids == nil {
-		*ids = make(map[[20]byte]bool, size)
-	}
+// ShortSet is a set of ShortIDs
+type ShortSet struct {
+	// small is the sorted set while it has at most maxSmallShortSetSize ids
+	small [][20]byte
+	// large is the set once it has grown beyond maxSmallShortSetSize ids
+	large map[[20]byte]bool
 }
 
 // Add all the ids to this set, if the id is already in the set, nothing happens
 func (ids *ShortSet) Add(idList ...ShortID) {
-	ids.init(2 * len(idList))
 	for _, id := range idList {
-		(*ids)[id.Key()] = true
+		ids.add(id.Key())
 	}
 }
 
 // Union adds all the ids from the provided sets to this set.
 func (ids *ShortSet) Union(idSet ShortSet) {
-	ids.init(2 * idSet.Len())
-	for id := range idSet {
-		(*ids)[id] = true
+	if idSet.large != nil {
+		for id := range idSet.large {
+			ids.add(id)
+		}
+		return
+	}
+	for _, id := range idSet.small {
+		ids.add(id)
 	}
 }
 
 // Contains returns true if the set contains this id, false otherwise
-func (ids *ShortSet) Contains(id ShortID) bool {
-	ids.init(1)
-	return (*ids)[id.Key()]
-}
+func (ids *ShortSet) Contains(id ShortID) bool { return ids.contains(id.Key()) }
 
 // Len returns the number of ids in this set
-func (ids ShortSet) Len() int { return len(ids) }
+func (ids ShortSet) Len() int {
+	if ids.large != nil {
+		return len(ids.large)
+	}
+	return len(ids.small)
+}
 
 // Remove all the id from this set, if the id isn't in the set, nothing happens
 func (ids *ShortSet) Remove(idList ...ShortID) {
-	ids.init(1)
 	for _, id := range idList {
-		delete(*ids, id.Key())
+		key := id.Key()
+		if ids.large != nil {
+			delete(ids.large, key)
+			continue
+		}
+		if i, found := ids.search(key); found {
+			ids.small = append(ids.small[:i], ids.small[i+1:]...)
+		}
 	}
 }
 
 // Clear empties this set
-func (ids *ShortSet) Clear() { *ids = nil }
+func (ids *ShortSet) Clear() { *ids = ShortSet{} }
 
 // CappedList returns a list of length at most [size]. Size should be >= 0
 func (ids ShortSet) CappedList(size int) []ShortID {
+	if l := ids.Len(); l < size {
+		size = l
+	}
 	idList := make([]ShortID, size)[:0]
-	for id := range ids {
+	if ids.large == nil {
+		for _, id := range ids.small[:size] {
+			idList = append(idList, NewShortID(id))
+		}
+		return idList
+	}
+	for id := range ids.large {
 		if size <= 0 {
 			break
 		}
@@ -64,21 +93,23 @@ func (ids ShortSet) CappedList(size int) []ShortID {
 }
 
 // List converts this set into a list
-func (ids ShortSet) List() []ShortID {
-	idList := make([]ShortID, len(ids))[:0]
-	for id := range ids {
-		idList = append(idList, NewShortID(id))
-	}
-	return idList
-}
+func (ids ShortSet) List() []ShortID { return ids.CappedList(ids.Len()) }
 
 // Equals returns true if the sets contain the same elements
 func (ids ShortSet) Equals(oIDs ShortSet) bool {
 	if ids.Len() != oIDs.Len() {
 		return false
 	}
-	for key := range oIDs {
-		if !ids[key] {
+	if oIDs.large != nil {
+		for key := range oIDs.large {
+			if !ids.contains(key) {
+				return false
+			}
+		}
+		return true
+	}
+	for _, key := range oIDs.small {
+		if !ids.contains(key) {
 			return false
 		}
 	}
@@ -89,14 +120,55 @@ func (ids ShortSet) Equals(oIDs ShortSet) bool {
 func (ids ShortSet) String() string {
 	sb := strings.Builder{}
 	sb.WriteString("{")
-	first := true
-	for idBytes := range ids {
-		if !first {
+	for i, id := range ids.List() {
+		if i != 0 {
 			sb.WriteString(", ")
 		}
-		first = false
-		sb.WriteString(NewShortID(idBytes).String())
+		sb.WriteString(id.String())
 	}
 	sb.WriteString("}")
 	return sb.String()
 }
+
+func (ids *ShortSet) add(key [20]byte) {
+	if ids.large != nil {
+		ids.large[key] = true
+		return
+	}
+
+	i, found := ids.search(key)
+	if found {
+		return
+	}
+	if len(ids.small) < maxSmallShortSetSize {
+		ids.small = append(ids.small, [20]byte{})
+		copy(ids.small[i+1:], ids.small[i:])
+		ids.small[i] = key
+		return
+	}
+
+	// The set is too large to search efficiently, so switch to a map
+	ids.large = make(map[[20]byte]bool, 2*(len(ids.small)+1))
+	for _, id := range ids.small {
+		ids.large[id] = true
+	}
+	ids.large[key] = true
+	ids.small = nil
+}
+
+func (ids *ShortSet) contains(key [20]byte) bool {
+	if ids.large != nil {
+		return ids.large[key]
+	}
+	_, found := ids.search(key)
+	return found
+}
+
+// search returns the index [key] is, or would be inserted at, in the small set
+// and whether [key] is in the small set
+func (ids *ShortSet) search(key [20]byte) (int, bool) {
+	i := sort.Search(len(ids.small), func(i int) bool {
+		return bytes.Compare(ids.small[i][:], key[:]) >= 0
+	})
+	return i, i < len(ids.small) && ids.small[i] == key
+}
@@ -0,0 +1,64 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package ids
+
+import (
+	"fmt"
+	"testing"
+)
+
+var shortSetBenchmarkSizes = []int{1, 3, maxSmallShortSetSize, 4 * maxSmallShortSetSize}
+
+func shortSetBenchmarkIDs(size int) []ShortID {
+	idList := make([]ShortID, size)
+	for i := range idList {
+		idList[i] = NewShortID([20]byte{byte(i), byte(i >> 8), 0xff})
+	}
+	return idList
+}
+
+// BenchmarkShortSetAdd benchmarks building a set of each size
+func BenchmarkShortSetAdd(b *testing.B) {
+	for _, size := range shortSetBenchmarkSizes {
+		idList := shortSetBenchmarkIDs(size)
+		b.Run(fmt.Sprintf("%d", size), func(b *testing.B) {
+			b.ReportAllocs()
+			for n := 0; n < b.N; n++ {
+				set := ShortSet{}
+				set.Add(idList...)
+			}
+		})
+	}
+}
+
+// BenchmarkShortSetContains benchmarks looking up every id of a set of each
+// size
+func BenchmarkShortSetContains(b *testing.B) {
+	for _, size := range shortSetBenchmarkSizes {
+		idList := shortSetBenchmarkIDs(size)
+		set := ShortSet{}
+		set.Add(idList...)
+		b.Run(fmt.Sprintf("%d", size), func(b *testing.B) {
+			for n := 0; n < b.N; n++ {
+				for _, id := range idList {
+					set.Contains(id)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkShortSetList benchmarks listing a set of each size
+func BenchmarkShortSetList(b *testing.B) {
+	for _, size := range shortSetBenchmarkSizes {
+		set := ShortSet{}
+		set.Add(shortSetBenchmarkIDs(size)...)
+		b.Run(fmt.Sprintf("%d", size), func(b *testing.B) {
+			b.ReportAllocs()
+			for n := 0; n < b.N; n++ {
+				set.List()
+			}
+		})
+	}
+}
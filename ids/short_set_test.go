@@ -237,3 +237,62 @@ func TestShortSetString(t *testing.T) {
 		t.Fatalf("Should only have one %s in %s", ",", str)
 	}
 }
+
+func TestShortSetLarge(t *testing.T) {
+	set := ShortSet{}
+
+	idList := []ShortID(nil)
+	for i := 0; i < 2*maxSmallShortSetSize; i++ {
+		idList = append(idList, NewShortID([20]byte{byte(2*maxSmallShortSetSize - i)}))
+	}
+
+	for i, id := range idList {
+		set.Add(id)
+		if set.Len() != i+1 {
+			t.Fatalf("Set should have had length %d but had %d", i+1, set.Len())
+		}
+	}
+	for _, id := range idList {
+		if !set.Contains(id) {
+			t.Fatalf("Set should contain %s", id)
+		}
+	}
+
+	otherSet := ShortSet{}
+	otherSet.Add(idList[:maxSmallShortSetSize]...)
+	if set.Equals(otherSet) {
+		t.Fatal("Sets should be unequal")
+	}
+	otherSet.Union(set)
+	if !set.Equals(otherSet) || !otherSet.Equals(set) {
+		t.Fatal("Sets should be equal")
+	}
+
+	set.Remove(idList...)
+	if set.Len() != 0 {
+		t.Fatalf("Set should have been empty but had length %d", set.Len())
+	}
+}
+
+func TestShortSetSmallSorted(t *testing.T) {
+	set := ShortSet{}
+
+	id0 := NewShortID([20]byte{0})
+	id1 := NewShortID([20]byte{1})
+	id2 := NewShortID([20]byte{2})
+
+	set.Add(id2, id0, id1, id0)
+	if set.Len() != 3 {
+		t.Fatalf("Set should have had length %d but had %d", 3, set.Len())
+	}
+
+	set.Remove(id1)
+	switch {
+	case !set.Contains(id0):
+		t.Fatalf("Set should contain %s", id0)
+	case set.Contains(id1):
+		t.Fatalf("Set shouldn't contain %s", id1)
+	case !set.Contains(id2):
+		t.Fatalf("Set should contain %s", id2)
+	}
+}
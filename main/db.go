@@ -0,0 +1,50 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"path"
+
+	"github.com/ava-labs/gecko/database"
+	"github.com/ava-labs/gecko/database/leveldb"
+	"github.com/ava-labs/gecko/database/rocksdb"
+)
+
+// The database backends the node can store its state in
+const (
+	levelDBType = "leveldb"
+	rocksDBType = "rocksdb"
+)
+
+// openDB opens the database of type [dbType] at [dbPath], creating it if it
+// doesn't exist
+func openDB(dbType, dbPath string) (database.Database, error) {
+	switch dbType {
+	case levelDBType:
+		return leveldb.New(dbPath, 0, 0, 0)
+	case rocksDBType:
+		// Unlike LevelDB, RocksDB only creates the last directory of the path
+		if err := os.MkdirAll(path.Dir(dbPath), 0755); err != nil {
+			return nil, err
+		}
+		return rocksdb.New(dbPath, 0, 0, 0)
+	default:
+		return nil, fmt.Errorf("unknown db-type %q", dbType)
+	}
+}
+
+// openReadOnlyDB opens the existing database of type [dbType] at [dbPath]
+// without modifying its files
+func openReadOnlyDB(dbType, dbPath string) (database.Database, error) {
+	switch dbType {
+	case levelDBType:
+		return leveldb.NewReadOnly(dbPath, 0, 0)
+	case rocksDBType:
+		return rocksdb.NewReadOnly(dbPath, 0, 0)
+	default:
+		return nil, fmt.Errorf("unknown db-type %q", dbType)
+	}
+}
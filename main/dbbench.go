@@ -0,0 +1,53 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/ava-labs/gecko/database"
+	"github.com/ava-labs/gecko/database/dbbench"
+	"github.com/ava-labs/gecko/database/memdb"
+	"github.com/ava-labs/gecko/utils/logging"
+)
+
+// benchDB benchmarks a new database in [DBBenchPath], which is deleted once
+// the benchmark is done, and prints the results. If [DBBenchPath] is empty, an
+// in-memory database is benchmarked.
+func benchDB(log logging.Logger) error {
+	var db database.Database = memdb.New()
+	if DBBenchPath != "" {
+		if _, err := os.Stat(DBBenchPath); !os.IsNotExist(err) {
+			return fmt.Errorf("benchmark directory %s already exists", DBBenchPath)
+		}
+		benchDB, err := openDB(DBType, DBBenchPath)
+		if err != nil {
+			return fmt.Errorf("couldn't create the benchmark database: %w", err)
+		}
+		defer func() {
+			if err := os.RemoveAll(DBBenchPath); err != nil {
+				log.Error("couldn't delete the benchmark database %s: %s", DBBenchPath, err)
+			}
+		}()
+
+		log.Info("benchmarking a database in %s", DBBenchPath)
+		db = benchDB
+	}
+	defer db.Close()
+
+	bench := dbbench.Bench{
+		Log:    log,
+		DB:     db,
+		Config: DBBenchConfig,
+	}
+	results, err := bench.Run()
+	if err != nil {
+		return err
+	}
+	for _, result := range results {
+		fmt.Println(result)
+	}
+	return nil
+}
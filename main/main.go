@@ -5,23 +5,47 @@ package main
 
 import (
 	"fmt"
+	"os"
 	"path"
 
+	"github.com/ava-labs/gecko/database/migration"
 	"github.com/ava-labs/gecko/node"
 	"github.com/ava-labs/gecko/utils/crypto"
 	"github.com/ava-labs/gecko/utils/logging"
-	"github.com/ava-labs/go-ethereum/p2p/nat"
 )
 
 // main is the primary entry point to Ava. This can either create a CLI to an
 //     existing node or create a new node.
 func main() {
+	// If the node was restarted through the admin API, replace this process
+	// with a new instance once the node has shut down and released its
+	// resources
+	defer func() {
+		if node.MainNode.ShouldRestart() {
+			restart()
+		}
+	}()
+
 	// Err is set based on the CLI arguments
 	if Err != nil {
 		fmt.Printf("parsing parameters returned with error %s\n", Err)
 		return
 	}
 
+	if GenerateStakingKey {
+		if err := generateStakingKey(os.Stdin); err != nil {
+			fmt.Printf("generating the staking key failed with: %s\n", err)
+		}
+		return
+	}
+
+	if GenerateAPIToken != 0 {
+		if err := generateAPIToken(GenerateAPIToken); err != nil {
+			fmt.Printf("generating the API token failed with: %s\n", err)
+		}
+		return
+	}
+
 	config := Config.LoggingConfig
 	config.Directory = path.Join(config.Directory, "node")
 	factory := logging.NewFactory(config)
@@ -40,6 +64,25 @@ func main() {
 	defer log.StopOnPanic()
 	defer Config.DB.Close()
 
+	if Migrate {
+		if err := migrate(log); err != nil {
+			log.Fatal("migrating the database failed: %s", err)
+		}
+		return
+	}
+
+	if DBBench {
+		if err := benchDB(log); err != nil {
+			log.Fatal("benchmarking the database failed: %s", err)
+		}
+		return
+	}
+
+	if err := migration.CheckVersion(Config.DB); err != nil {
+		log.Fatal("%s", err)
+		return
+	}
+
 	// Track if sybil control is enforced
 	if !Config.EnableStaking {
 		log.Warn("Staking and p2p encryption are disabled. Packet spoofing is possible.")
@@ -61,27 +104,6 @@ func main() {
 		log.Warn("assertions are enabled. This may slow down execution")
 	}
 
-	natChan := make(chan struct{})
-	defer close(natChan)
-
-	go nat.Map(
-		/*nat=*/ Config.Nat,
-		/*closeChannel=*/ natChan,
-		/*protocol=*/ "TCP",
-		/*internetPort=*/ int(Config.StakingIP.Port),
-		/*localPort=*/ int(Config.StakingIP.Port),
-		/*name=*/ "Gecko Staking Server",
-	)
-
-	go nat.Map(
-		/*nat=*/ Config.Nat,
-		/*closeChannel=*/ natChan,
-		/*protocol=*/ "TCP",
-		/*internetPort=*/ int(Config.HTTPPort),
-		/*localPort=*/ int(Config.HTTPPort),
-		/*name=*/ "Gecko HTTP Server",
-	)
-
 	log.Debug("initializing node state")
 	// MainNode is a global variable in the node.go file
 	if err := node.MainNode.Initialize(&Config, log, factory); err != nil {
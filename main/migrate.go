@@ -0,0 +1,40 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/ava-labs/gecko/database/migration"
+	"github.com/ava-labs/gecko/utils/logging"
+)
+
+// migrate the node's database to the version written by this node. Unless this
+// is a dry run, the database is first backed up to [MigrateBackupPath], which
+// must not exist.
+func migrate(log logging.Logger) error {
+	migrator := migration.Migrator{
+		Log:        log,
+		Migrations: migration.Migrations,
+		DryRun:     MigrateDryRun,
+	}
+
+	if MigrateBackupPath != "" && !MigrateDryRun {
+		if _, err := os.Stat(MigrateBackupPath); !os.IsNotExist(err) {
+			return fmt.Errorf("backup directory %s already exists", MigrateBackupPath)
+		}
+		backup, err := openDB(DBType, MigrateBackupPath)
+		if err != nil {
+			return fmt.Errorf("couldn't create the backup database: %w", err)
+		}
+		defer backup.Close()
+
+		log.Info("the database will be backed up to %s", MigrateBackupPath)
+		migrator.Backup = backup
+	}
+
+	_, err := migrator.Migrate(Config.DB)
+	return err
+}
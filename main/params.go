@@ -8,34 +8,93 @@ import (
 	"flag"
 	"fmt"
 	"net"
+	"os"
 	"path"
 	"strings"
+	"time"
 
 	"github.com/ava-labs/go-ethereum/p2p/nat"
 
-	"github.com/ava-labs/gecko/database/leveldb"
+	"github.com/ava-labs/gecko/database/dbbench"
 	"github.com/ava-labs/gecko/database/memdb"
+	"github.com/ava-labs/gecko/database/rodb"
 	"github.com/ava-labs/gecko/genesis"
 	"github.com/ava-labs/gecko/ids"
+	natmap "github.com/ava-labs/gecko/networking/nat"
 	"github.com/ava-labs/gecko/node"
+	"github.com/ava-labs/gecko/snow/engine/common"
 	"github.com/ava-labs/gecko/snow/networking/router"
 	"github.com/ava-labs/gecko/utils"
 	"github.com/ava-labs/gecko/utils/formatting"
 	"github.com/ava-labs/gecko/utils/hashing"
 	"github.com/ava-labs/gecko/utils/logging"
 	"github.com/ava-labs/gecko/utils/wrappers"
+	"github.com/ava-labs/gecko/vms/platformvm"
 )
 
 // Results of parsing the CLI
 var (
 	Config = node.Config{}
 	Err    error
+
+	// GenerateStakingKey is true if, rather than running a node, a staking key
+	// should be derived from a seed phrase
+	GenerateStakingKey bool
+
+	// GenerateAPIToken is the lifetime of the API token that should be
+	// generated rather than running a node. If 0, no token is generated.
+	GenerateAPIToken time.Duration
+
+	// Migrate is true if, rather than running a node, the database should be
+	// migrated to the version written by this node
+	Migrate bool
+
+	// MigrateDryRun is true if the migrations should be verified without
+	// writing them to the database
+	MigrateDryRun bool
+
+	// MigrateBackupPath is the path of the database that the node's database is
+	// copied to before it's migrated. If empty, the database isn't backed up.
+	MigrateBackupPath string
+
+	// DBType is the backend of the node's database, and of the databases
+	// created by "gecko migrate" and "gecko dbbench"
+	DBType string
+
+	// DBBench is true if, rather than running a node, the database backend
+	// should be benchmarked
+	DBBench bool
+
+	// DBBenchPath is the path of the database the benchmark creates and
+	// deletes once it's done. If empty, an in-memory database is benchmarked.
+	DBBenchPath string
+
+	// DBBenchConfig is the configuration of the database benchmark
+	DBBenchConfig dbbench.Config
 )
 
 var (
 	errBootstrapMismatch = errors.New("more bootstrap IDs provided than bootstrap IPs")
+	errKeepAlivePeriod   = errors.New("network-keepalive-period must be positive")
+	errKeepAliveTimeout  = errors.New("network-keepalive-timeout must be greater than network-keepalive-period")
+	errSocketBufferSize  = errors.New("network-socket-buffer-size must be positive")
+	errMigrateNoDB       = errors.New("db-enabled must be true to migrate the database")
+	errReadOnlyNoDB      = errors.New("db-enabled must be true to open the database read only")
+	errMigrateReadOnly   = errors.New("the database can't be migrated while db-read-only is true")
+	errAPIRateLimit      = errors.New("api rate limits must not be negative")
+	errIPv6Staking       = errors.New("staking connections only support IPv4 addresses")
+	errNATInterval       = errors.New("nat-refresh-interval must be positive and shorter than 20 minutes")
+	errNoPublicIP        = errors.New("public-ip must be set when nat is none")
 )
 
+// migrateCommand is the command that migrates the database rather than running
+// a node. Usage: gecko migrate [flags]
+const migrateCommand = "migrate"
+
+// dbbenchCommand is the command that benchmarks the database backend rather
+// than running a node. Usage: gecko dbbench [flags]
+const dbbenchCommand = "dbbench"
+
 // Parse the CLI arguments
 func init() {
 	errs := &wrappers.Errs{}
@@ -48,7 +107,10 @@ func init() {
 	networkName := flag.String("network-id", genesis.LocalName, "Network ID this node will connect to")
 
 	// Ava fees:
-	flag.Uint64Var(&Config.AvaTxFee, "ava-tx-fee", 0, "Ava transaction fee, in $nAva")
+	flag.Uint64Var(&Config.AvaTxFee, "ava-tx-fee", 0, "Ava transaction fee of the Simple Payments DAG, in $nAva. The X-Chain's fee is set by its genesis")
+
+	// Governance:
+	governanceVotes := flag.String("platform-governance-votes", "", "Comma separated list of the parameter values this node votes for in platform chain governance proposals. Example: txFee=1000,minimumStake=20000")
 
 	// Assertions:
 	flag.BoolVar(&loggingConfig.Assertions, "assertions-enabled", true, "Turn on assertion execution")
@@ -59,25 +121,60 @@ func init() {
 	// Database:
 	db := flag.Bool("db-enabled", true, "Turn on persistent storage")
 	dbDir := flag.String("db-dir", "db", "Database directory for Ava state")
+	flag.StringVar(&DBType, "db-type", levelDBType, fmt.Sprintf("Database backend to store Ava state in. Either %q or %q, which needs a node built with the rocksdb tag. The backends' files are kept apart in db-dir, so switching backends starts from an empty database", levelDBType, rocksDBType))
+	flag.BoolVar(&Config.DBReadOnly, "db-read-only", false, "If true, the node serves API calls from the state already in db-dir, without connecting to peers, participating in consensus or modifying the database")
+
+	// Migration:
+	flag.BoolVar(&MigrateDryRun, "migrate-dry-run", false, "If true, \"gecko migrate\" verifies the pending migrations without modifying the database")
+	migrateBackup := flag.Bool("migrate-backup", true, "If true, \"gecko migrate\" copies the database to a new directory in db-dir before modifying it")
+
+	// Database benchmark:
+	flag.IntVar(&DBBenchConfig.Keys, "dbbench-keys", 1000000, "Number of keys \"gecko dbbench\" writes before running its workloads")
+	flag.IntVar(&DBBenchConfig.ValueSize, "dbbench-value-size", 256, "Size, in bytes, of the values \"gecko dbbench\" writes")
+	flag.IntVar(&DBBenchConfig.BatchSize, "dbbench-batch-size", 100, "Number of keys in each batch \"gecko dbbench\" writes")
+	flag.IntVar(&DBBenchConfig.ScanLength, "dbbench-scan-length", 100, "Number of keys each iterator scan of \"gecko dbbench\" reads")
+	flag.DurationVar(&DBBenchConfig.Duration, "dbbench-duration", 30*time.Second, "How long \"gecko dbbench\" runs each workload. Long durations soak test the database")
 
 	// IP:
-	consensusIP := flag.String("public-ip", "", "Public IP of this node")
+	consensusIP := flag.String("public-ip", "", "Public IP of this node. If empty, it's resolved with the NAT device and re-checked periodically")
+
+	// NAT traversal:
+	natSpec := flag.String("nat", "any", "How ports are mapped on the NAT device in front of this node: \"any\", \"upnp\", \"pmp\", \"pmp:<gateway IP>\", \"extip:<public IP>\" or \"none\"")
+	flag.DurationVar(&Config.NATRefreshInterval, "nat-refresh-interval", 5*time.Minute, "How often port mappings are renewed and the public IP is re-checked. Must be shorter than 20 minutes")
+	flag.BoolVar(&Config.NATMapHTTPPort, "nat-map-http-port", true, "If true, the HTTP port is mapped on the NAT device along with the staking port")
 
 	// HTTP Server:
 	httpPort := flag.Uint("http-port", 9650, "Port of the HTTP server")
 	flag.BoolVar(&Config.EnableHTTPS, "http-tls-enabled", false, "Upgrade the HTTP server to HTTPs")
 	flag.StringVar(&Config.HTTPSKeyFile, "http-tls-key-file", "", "TLS private key file for the HTTPs server")
 	flag.StringVar(&Config.HTTPSCertFile, "http-tls-cert-file", "", "TLS certificate file for the HTTPs server")
+	flag.Float64Var(&Config.APIRate, "api-rate-limit", 0, "API calls per second that each IP may make. If 0, API calls aren't rate limited")
+	flag.Float64Var(&Config.APIBurst, "api-rate-limit-burst", 100, "API calls that each IP may make at once")
+	flag.Float64Var(&Config.ValidatorAPIRate, "api-validator-rate-limit", 100, "API calls per second that may be made with an API token signed by a validator with the average stake. Validators get a quota proportional to their stake")
+	flag.Float64Var(&Config.ValidatorAPIBurst, "api-validator-rate-limit-burst", 1000, "API calls that may be made at once with an API token signed by a validator with the average stake")
+	flag.DurationVar(&GenerateAPIToken, "generate-api-token", 0, "If non-zero, print an API token signed with the staking key that expires after this duration, and exit")
 
 	// Bootstrapping:
 	bootstrapIPs := flag.String("bootstrap-ips", "", "Comma separated list of bootstrap peer ips to connect to. Example: 127.0.0.1:9630,127.0.0.1:9631")
 	bootstrapIDs := flag.String("bootstrap-ids", "", "Comma separated list of bootstrap peer ids to connect to. Example: JR4dVmy6ffUGAKCBDkyCbeZbyHQBeDsET,8CrVPQZ4VSqgL8zTdvL14G8HqAfrBr4z")
 
+	// Peer discovery:
+	dnsSeeds := flag.String("dns-seeds", "", "Comma separated list of host names that resolve to the IPs of peers, each optionally followed by the port the peers listen on. If there's no port, the staking port is used. Example: seed1.example.com,seed2.example.com:9651")
+	knownPeersFile := flag.String("known-peers-file", "", "File the peers this node is connected to are saved to, and dialed from when the node restarts. If empty, defaults to a file in db-dir when the database is enabled")
+
 	// Staking:
 	consensusPort := flag.Uint("staking-port", 9651, "Port of the consensus server")
 	flag.BoolVar(&Config.EnableStaking, "staking-tls-enabled", true, "Require TLS to authenticate staking connections")
 	flag.StringVar(&Config.StakingKeyFile, "staking-tls-key-file", "", "TLS private key file for staking connections")
 	flag.StringVar(&Config.StakingCertFile, "staking-tls-cert-file", "", "TLS certificate file for staking connections")
+	flag.BoolVar(&GenerateStakingKey, "generate-staking-key", false, "If true, read a seed phrase from stdin, write the staking key and certificate derived from it to staking-tls-key-file and staking-tls-cert-file, and exit. If the seed phrase is empty, a random key is generated")
+
+	// Connections:
+	flag.DurationVar(&Config.KeepAlivePeriod, "network-keepalive-period", 20*time.Second, "Time between pings sent to a peer to keep the connection alive")
+	flag.DurationVar(&Config.KeepAliveTimeout, "network-keepalive-timeout", time.Minute, "Time without a response after which a peer is disconnected")
+	flag.IntVar(&Config.SocketBufferSize, "network-socket-buffer-size", 64<<10, "Number of bytes read from a connection at once")
+	flag.IntVar(&Config.MaxPeers, "network-max-peers", 0, "Maximum number of connected peers. Non-validators are disconnected to make room for validators, which are always connected. If 0, the number of peers isn't limited")
+	flag.IntVar(&Config.TargetOutboundPeers, "network-target-outbound-peers", 16, "Number of peers to dial and stay connected to. Validators this node was connected to are redialed even once the target is reached")
 
 	// Logging:
 	logsDir := flag.String("log-dir", "", "Logging directory for Ava")
@@ -90,18 +187,71 @@ func init() {
 	flag.IntVar(&Config.ConsensusParams.BetaRogue, "snow-rogue-commit-threshold", 30, "Beta value to use for rogue transactions")
 	flag.IntVar(&Config.ConsensusParams.Parents, "snow-avalanche-num-parents", 5, "Number of vertexes for reference from each new vertex")
 	flag.IntVar(&Config.ConsensusParams.BatchSize, "snow-avalanche-batch-size", 30, "Number of operations to batch in each new vertex")
+	flag.Uint64Var(&Config.AcceptJournalRetention, "accept-journal-retention", common.DefaultAcceptJournalRetention, "Number of accepted containers each accept hook remembers having handled, so they aren't delivered again after a restart. If 0, nothing is remembered")
+	flag.IntVar(&Config.GetWorkers, "snow-get-workers", 4, "Maximum number of Get messages each chain handles concurrently, if its VM allows it. If 0, Get messages are handled in order with the chain's other messages")
+	flag.IntVar(&Config.MaxOutstandingPolls, "snow-max-outstanding-polls", 256, "Maximum number of network polls each chain may have outstanding at once. Containers issued past the limit are queried once an outstanding poll finishes. If 0, the number of outstanding polls isn't limited")
+	flag.BoolVar(&Config.SnowmanStateSync, "snow-state-sync", false, "If true, Snowman chains whose VM supports it sync their state from a state summary returned by a quorum of beacons, rather than replaying every block from genesis")
+	flag.StringVar(&Config.ChainConfigDir, "chain-config-dir", "chains", "Directory of per-chain config files. The contents of [chain-config-dir]/[chain alias]/config.json are passed to the chain's VM")
+
+	// Gossip:
+	flag.Uint64Var(&Config.GossipBandwidth, "gossip-bandwidth", 4<<20, "Bytes per second that may be gossiped to all peers. If 0, gossip isn't limited")
+	flag.Uint64Var(&Config.GossipBurst, "gossip-burst", 1<<25, "Bytes that may be gossiped to all peers at once")
+	flag.Uint64Var(&Config.GossipPeerBandwidth, "gossip-peer-bandwidth", 512<<10, "Bytes per second that may be gossiped to a single peer. If 0, gossip to a peer isn't limited")
+	flag.Uint64Var(&Config.GossipPeerBurst, "gossip-peer-burst", 1<<25, "Bytes that may be gossiped to a single peer at once")
+	flag.DurationVar(&Config.GossipDedupWindow, "gossip-dedup-window", time.Minute, "A container isn't gossiped to a peer that was sent it within this window. If 0, containers may be gossiped repeatedly")
+	flag.IntVar(&Config.GossipDedupSize, "gossip-dedup-size", 1<<16, "Maximum number of container sends remembered to prevent duplicate gossip")
+	flag.DurationVar(&Config.FrontierGossipFrequency, "gossip-frontier-frequency", 10*time.Second, "Time between gossips of each chain's accepted frontier to a sample of peers, so peers that fell behind fetch the containers they're missing. If 0, accepted frontiers aren't gossiped")
+
+	// Compression:
+	flag.BoolVar(&Config.CompressionEnabled, "network-compression-enabled", true, "If true, this node accepts gzip compressed messages and compresses large messages sent to peers that accept them")
+	flag.IntVar(&Config.CompressionThreshold, "network-compression-threshold", 1<<10, "Messages of at least this many bytes are compressed")
+
+	// Inbound rate limits:
+	flag.Float64Var(&Config.InboundLimits.MsgRate, "network-inbound-msg-rate", 1000, "Messages per second a peer may send this node. Excess requests and gossip are dropped. If 0, messages aren't limited")
+	flag.Float64Var(&Config.InboundLimits.MsgBurst, "network-inbound-msg-burst", 2000, "Messages a peer may send this node at once")
+	flag.Float64Var(&Config.InboundLimits.ByteRate, "network-inbound-byte-rate", 4<<20, "Bytes per second a peer may send this node. If 0, bytes aren't limited")
+	flag.Float64Var(&Config.InboundLimits.ByteBurst, "network-inbound-byte-burst", 1<<25, "Bytes a peer may send this node at once. Should be at least the maximum message size")
+	flag.Float64Var(&Config.InboundLimits.TypeMsgRate, "network-inbound-type-msg-rate", 500, "Messages per second a peer may send this node of each type. If 0, messages of each type aren't limited separately")
+	flag.Float64Var(&Config.InboundLimits.TypeMsgBurst, "network-inbound-type-msg-burst", 1000, "Messages a peer may send this node of each type at once")
+
+	// Replay protection:
+	flag.BoolVar(&Config.ReplayProtectionEnabled, "network-replay-protection-enabled", true, "If true, peers prefix the messages they send this node with sequence numbers, and messages received more than once are dropped")
+
+	// Peer banning:
+	flag.Float64Var(&Config.PeerBanThreshold, "peer-ban-threshold", 100, "Misbehavior score at which a peer is banned. Peers are penalized for invalid messages and unanswered requests, and penalties are halved every 10 minutes. If 0, peers aren't banned")
+	flag.DurationVar(&Config.PeerBanDuration, "peer-ban-duration", 30*time.Minute, "How long a banned peer's messages are dropped and its connections refused")
 
 	// Enable/Disable APIs:
 	flag.BoolVar(&Config.AdminAPIEnabled, "api-admin-enabled", true, "If true, this node exposes the Admin API")
 	flag.BoolVar(&Config.KeystoreAPIEnabled, "api-keystore-enabled", true, "If true, this node exposes the Keystore API")
+	flag.BoolVar(&Config.ExternalSigningOnly, "external-signing-only", false, "If true, this node never holds users' keys. The Keystore API and every API method that signs with a user's keys are disabled, so only externally signed transactions can be issued")
 	flag.BoolVar(&Config.MetricsAPIEnabled, "api-metrics-enabled", true, "If true, this node exposes the Metrics API")
 	flag.BoolVar(&Config.IPCEnabled, "api-ipcs-enabled", false, "If true, IPCs can be opened")
+	flag.BoolVar(&Config.DebugAPIEnabled, "api-debug-enabled", false, "If true, this node exposes the read-only Debug API for inspecting its database")
+	flag.BoolVar(&Config.InfoAPIEnabled, "api-info-enabled", true, "If true, this node exposes the Info API")
+	flag.BoolVar(&Config.HealthAPIEnabled, "api-health-enabled", true, "If true, this node exposes the Health API")
+	flag.StringVar(&Config.IssuanceDenyListFile, "api-issuance-deny-list", "", "JSON file of the assets and addresses that the AVM API refuses to issue transactions for")
+	flag.BoolVar(&Config.IndexTransactions, "index-transactions", false, "If true, the AVM indexes accepted transactions by address so avm.getAddressTxs can serve an address's history")
+	flag.BoolVar(&Config.IndexAssetStats, "index-asset-stats", false, "If true, the AVM counts each asset's daily activity so avm.getAssetStats can serve it")
+	flag.IntVar(&Config.AVMMempoolSize, "avm-mempool-size", 0, "Maximum number of issued AVM transactions waiting to be taken by consensus. If 0, a default of 4096 is used")
+	flag.IntVar(&Config.AVMMempoolAddressCap, "avm-mempool-address-cap", 0, "Maximum number of AVM mempool transactions that each address may be involved in. If 0, a default of 256 is used")
 
 	// Throughput Server
 	throughputPort := flag.Uint("xput-server-port", 9652, "Port of the deprecated throughput test server")
 	flag.BoolVar(&Config.ThroughputServerEnabled, "xput-server-enabled", false, "If true, throughput test server is created")
 
-	flag.Parse()
+	args := os.Args[1:]
+	if len(args) > 0 {
+		switch args[0] {
+		case migrateCommand:
+			Migrate = true
+			args = args[1:]
+		case dbbenchCommand:
+			DBBench = true
+			args = args[1:]
+		}
+	}
+	flag.CommandLine.Parse(args)
 
 	networkID, err := genesis.NetworkID(*networkName)
 	errs.Add(err)
@@ -113,29 +263,79 @@ func init() {
 	Config.NetworkID = networkID
 
 	// DB:
-	if *db && err == nil {
+	if DBType != levelDBType && DBType != rocksDBType {
+		errs.Add(fmt.Errorf("db-type must be %q or %q, not %q", levelDBType, rocksDBType, DBType))
+	}
+	// LevelDB's files are kept where they always have been, so existing
+	// databases are still found
+	dbPath := path.Join(*dbDir, genesis.NetworkName(Config.NetworkID))
+	if DBType == rocksDBType {
+		dbPath = path.Join(*dbDir, rocksDBType, genesis.NetworkName(Config.NetworkID))
+	}
+	switch {
+	case DBBench:
+		// The benchmark writes to a database of its own, next to the node's,
+		// so the node's database is left untouched
+		Config.DB = memdb.New()
+		if *db {
+			DBBenchPath = path.Join(*dbDir, fmt.Sprintf("dbbench-%s", time.Now().UTC().Format("20060102T150405Z")))
+		}
+	case *db && err == nil && Config.DBReadOnly:
+		// Writes made while the node runs are kept in memory and dropped when
+		// it stops
+		db, err := openReadOnlyDB(DBType, dbPath)
+		if err == nil {
+			Config.DB = rodb.New(db)
+		}
+		errs.Add(err)
+
+		if Migrate {
+			errs.Add(errMigrateReadOnly)
+		}
+	case *db && err == nil:
 		// TODO: Add better params here
-		dbPath := path.Join(*dbDir, genesis.NetworkName(Config.NetworkID))
-		db, err := leveldb.New(dbPath, 0, 0, 0)
+		db, err := openDB(DBType, dbPath)
 		Config.DB = db
 		errs.Add(err)
-	} else {
+
+		if *migrateBackup {
+			MigrateBackupPath = fmt.Sprintf("%s-backup-%s", dbPath, time.Now().UTC().Format("20060102T150405Z"))
+		}
+	default:
 		Config.DB = memdb.New()
+		if Migrate {
+			errs.Add(errMigrateNoDB)
+		}
+		if Config.DBReadOnly && !*db {
+			errs.Add(errReadOnlyNoDB)
+		}
 	}
 
-	Config.Nat = nat.Any()
+	Config.Nat, err = nat.Parse(*natSpec)
+	if err != nil {
+		errs.Add(fmt.Errorf("invalid nat %q: %w", *natSpec, err))
+	}
+	if Config.NATRefreshInterval <= 0 || Config.NATRefreshInterval >= natmap.MappingLifetime {
+		errs.Add(errNATInterval)
+	}
 
 	var ip net.IP
-	// If public IP is not specified, get it using shell command dig
-	if *consensusIP == "" {
-		ip, err = Config.Nat.ExternalIP()
-		errs.Add(fmt.Errorf("%s\nIf you are trying to create a local network, try adding --public-ip=127.0.0.1", err))
+	// If public IP is not specified, resolve it with the NAT device
+	Config.ResolvePublicIP = *consensusIP == ""
+	if Config.ResolvePublicIP {
+		if Config.Nat == nil {
+			errs.Add(errNoPublicIP)
+		} else if ip, err = Config.Nat.ExternalIP(); err != nil {
+			errs.Add(fmt.Errorf("%s\nIf you are trying to create a local network, try adding --public-ip=127.0.0.1", err))
+		}
 	} else {
 		ip = net.ParseIP(*consensusIP)
 	}
 
 	if ip == nil {
 		errs.Add(fmt.Errorf("Invalid IP Address %s", *consensusIP))
+	} else if ip.To4() == nil {
+		errs.Add(fmt.Errorf("%s: %w", ip, errIPv6Staking))
 	}
 	Config.StakingIP = utils.IPDesc{
 		IP:   ip,
@@ -147,11 +347,25 @@ func init() {
 		if ip != "" {
 			addr, err := utils.ToIPDesc(ip)
 			errs.Add(err)
+			if err == nil && !addr.IsIPv4() {
+				errs.Add(fmt.Errorf("bootstrap IP %s: %w", addr, errIPv6Staking))
+			}
 			Config.BootstrapPeers = append(Config.BootstrapPeers, &node.Peer{
 				IP: addr,
 			})
 		}
 	}
+	// Peer discovery:
+	for _, seed := range strings.Split(*dnsSeeds, ",") {
+		if seed != "" {
+			Config.DNSSeeds = append(Config.DNSSeeds, seed)
+		}
+	}
+	Config.KnownPeersFile = *knownPeersFile
+	if Config.KnownPeersFile == "" && *db && !DBBench {
+		Config.KnownPeersFile = path.Join(*dbDir, fmt.Sprintf("%s-known-peers", genesis.NetworkName(Config.NetworkID)))
+	}
+
 	if Config.EnableStaking {
 		i := 0
 		cb58 := formatting.CB58{}
@@ -178,8 +392,26 @@ func init() {
 		}
 	}
 
+	// Connections:
+	if Config.KeepAlivePeriod <= 0 {
+		errs.Add(errKeepAlivePeriod)
+	}
+	if Config.KeepAliveTimeout <= Config.KeepAlivePeriod {
+		errs.Add(errKeepAliveTimeout)
+	}
+	if Config.SocketBufferSize <= 0 {
+		errs.Add(errSocketBufferSize)
+	}
+
+	// Governance:
+	Config.GovernanceVotes, err = platformvm.ParseGovernanceVotes(*governanceVotes)
+	errs.Add(err)
+
 	// HTTP:
 	Config.HTTPPort = uint16(*httpPort)
+	if Config.APIRate < 0 || Config.APIBurst < 0 || Config.ValidatorAPIRate < 0 || Config.ValidatorAPIBurst < 0 {
+		errs.Add(errAPIRateLimit)
+	}
 
 	// Logging:
 	if *logsDir != "" {
@@ -0,0 +1,23 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+)
+
+// restart replaces the current process with a new instance of the node that
+// is started with the same arguments and environment
+func restart() {
+	binary, err := os.Executable()
+	if err != nil {
+		fmt.Printf("couldn't find the node binary to restart: %s\n", err)
+		return
+	}
+	if err := syscall.Exec(binary, os.Args, os.Environ()); err != nil {
+		fmt.Printf("restarting the node failed with: %s\n", err)
+	}
+}
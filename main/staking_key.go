@@ -0,0 +1,105 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ava-labs/gecko/staking"
+)
+
+var (
+	errNoStakingFiles = errors.New("staking-tls-key-file and staking-tls-cert-file must be provided")
+)
+
+// generateStakingKey reads a seed phrase from [r] and writes the staking key
+// and certificate derived from it to the configured staking files. If the seed
+// phrase is empty, a random key is generated instead. Existing files are never
+// overwritten.
+func generateStakingKey(r io.Reader) error {
+	if Config.StakingKeyFile == "" || Config.StakingCertFile == "" {
+		return errNoStakingFiles
+	}
+
+	fmt.Println("Enter the seed phrase, or nothing to generate a random key:")
+	phrase, err := bufio.NewReader(r).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return fmt.Errorf("couldn't read the seed phrase: %w", err)
+	}
+
+	var cert, key []byte
+	if strings.TrimSpace(phrase) == "" {
+		cert, key, err = staking.NewCertAndKey()
+	} else {
+		cert, key, err = staking.NewCertAndKeyFromSeedPhrase(phrase)
+	}
+	if err != nil {
+		return err
+	}
+	nodeID, err := staking.NodeID(cert)
+	if err != nil {
+		return err
+	}
+
+	if err := writeNewFile(Config.StakingKeyFile, key, 0400); err != nil {
+		return err
+	}
+	if err := writeNewFile(Config.StakingCertFile, cert, 0444); err != nil {
+		return err
+	}
+
+	fmt.Printf("Wrote the staking key to %s and the certificate to %s\n", Config.StakingKeyFile, Config.StakingCertFile)
+	fmt.Printf("Node ID: %s\n", nodeID)
+	return nil
+}
+
+// generateAPIToken prints an API token, valid for [lifetime], signed with the
+// configured staking key
+func generateAPIToken(lifetime time.Duration) error {
+	if Config.StakingKeyFile == "" || Config.StakingCertFile == "" {
+		return errNoStakingFiles
+	}
+
+	cert, err := ioutil.ReadFile(Config.StakingCertFile)
+	if err != nil {
+		return fmt.Errorf("couldn't read %s: %w", Config.StakingCertFile, err)
+	}
+	key, err := ioutil.ReadFile(Config.StakingKeyFile)
+	if err != nil {
+		return fmt.Errorf("couldn't read %s: %w", Config.StakingKeyFile, err)
+	}
+	nodeID, err := staking.NodeID(cert)
+	if err != nil {
+		return err
+	}
+
+	expiry := time.Now().Add(lifetime)
+	token, err := staking.NewAPIToken(cert, key, expiry)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("API token of node %s, valid until %s:\n%s\n", nodeID, expiry.Format(time.RFC3339), token)
+	return nil
+}
+
+// writeNewFile writes [contents] to [filename], which must not exist
+func writeNewFile(filename string, contents []byte, perm os.FileMode) error {
+	f, err := os.OpenFile(filename, os.O_WRONLY|os.O_CREATE|os.O_EXCL, perm)
+	if err != nil {
+		return fmt.Errorf("couldn't create %s: %w", filename, err)
+	}
+	if _, err := f.Write(contents); err != nil {
+		f.Close()
+		return fmt.Errorf("couldn't write %s: %w", filename, err)
+	}
+	return f.Close()
+}
@@ -6,7 +6,9 @@ package networking
 import (
 	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/snow/choices"
+	"github.com/ava-labs/gecko/snow/networking"
 	"github.com/ava-labs/gecko/utils"
+	"github.com/ava-labs/gecko/utils/compression"
 )
 
 // Builder extends a Codec to build messages safely
@@ -15,12 +17,17 @@ type Builder struct{ Codec }
 // GetVersion message
 func (m Builder) GetVersion() (Msg, error) { return m.Pack(GetVersion, nil) }
 
-// Version message
-func (m Builder) Version(networkID uint32, myTime uint64, myVersion string) (Msg, error) {
+// Version message. [accepted] is the compression the sender accepts. If
+// [sequenced], the sender expects every message sent to it to be prefixed with
+// a sequence number. [features] are the optional features the sender supports.
+func (m Builder) Version(networkID uint32, myTime uint64, myVersion string, accepted compression.Type, sequenced bool, features networking.Features) (Msg, error) {
 	return m.Pack(Version, map[Field]interface{}{
-		NetworkID:  networkID,
-		MyTime:     myTime,
-		VersionStr: myVersion,
+		NetworkID:   networkID,
+		MyTime:      myTime,
+		VersionStr:  myVersion,
+		Compression: uint8(accepted),
+		Sequenced:   sequenced,
+		Features:    uint64(features),
 	})
 }
 
@@ -28,8 +35,11 @@ func (m Builder) Version(networkID uint32, myTime uint64, myVersion string) (Msg
 func (m Builder) GetPeerList() (Msg, error) { return m.Pack(GetPeerList, nil) }
 
 // PeerList message
-func (m Builder) PeerList(ipDescs []utils.IPDesc) (Msg, error) {
-	return m.Pack(PeerList, map[Field]interface{}{Peers: ipDescs})
+func (m Builder) PeerList(ipDescs []utils.IPDesc, networkSize uint64) (Msg, error) {
+	return m.Pack(PeerList, map[Field]interface{}{
+		Peers:       ipDescs,
+		NetworkSize: networkSize,
+	})
 }
 
 // GetAcceptedFrontier message
@@ -79,6 +89,36 @@ func (m Builder) Accepted(chainID ids.ID, requestID uint32, containerIDs ids.Set
 	})
 }
 
+// GetStateSummary message
+func (m Builder) GetStateSummary(chainID ids.ID, requestID uint32) (Msg, error) {
+	return m.Pack(GetStateSummary, map[Field]interface{}{
+		ChainID:   chainID.Bytes(),
+		RequestID: requestID,
+	})
+}
+
+// StateSummary message
+func (m Builder) StateSummary(chainID ids.ID, requestID uint32, summary []byte) (Msg, error) {
+	return m.Pack(StateSummary, map[Field]interface{}{
+		ChainID:        chainID.Bytes(),
+		RequestID:      requestID,
+		ContainerBytes: summary,
+	})
+}
+
+// GossipFrontier message
+func (m Builder) GossipFrontier(chainID ids.ID, containerIDs ids.Set) (Msg, error) {
+	containerIDBytes := make([][]byte, containerIDs.Len())
+	for i, containerID := range containerIDs.List() {
+		containerIDBytes[i] = containerID.Bytes()
+	}
+	return m.Pack(GossipFrontier, map[Field]interface{}{
+		ChainID:      chainID.Bytes(),
+		RequestID:    uint32(0),
+		ContainerIDs: containerIDBytes,
+	})
+}
+
 // Get message
 func (m Builder) Get(chainID ids.ID, requestID uint32, containerID ids.ID) (Msg, error) {
 	return m.Pack(Get, map[Field]interface{}{
@@ -154,3 +194,9 @@ func (m Builder) DecidedTx(txID ids.ID, status choices.Status) (Msg, error) {
 		Status: uint32(status),
 	})
 }
+
+// Maintenance message. [duration] is the number of seconds the sender will be
+// unavailable for.
+func (m Builder) Maintenance(duration uint64) (Msg, error) {
+	return m.Pack(Maintenance, map[Field]interface{}{Duration: duration})
+}
@@ -4,11 +4,13 @@
 package networking
 
 import (
+	"encoding/binary"
 	"errors"
 	"math"
 
 	"github.com/ava-labs/salticidae-go"
 
+	"github.com/ava-labs/gecko/utils/compression"
 	"github.com/ava-labs/gecko/utils/wrappers"
 )
 
@@ -18,6 +20,11 @@ var (
 	errBadOp        = errors.New("input field has invalid operation")
 )
 
+// packers are reused across calls to Pack. Packed bytes are copied into the
+// salticidae datastream, so the packer's byte array is never retained by the
+// message.
+var packers = wrappers.PackerPool{MaxSize: math.MaxInt32}
+
 // Codec defines the serialization and deserialization of network messages
 type Codec struct{}
 
@@ -28,13 +35,15 @@ func (Codec) Pack(op salticidae.Opcode, fields map[Field]interface{}) (Msg, erro
 		return nil, errBadOp
 	}
 
-	p := wrappers.Packer{MaxSize: math.MaxInt32}
+	p := packers.Get()
+	defer packers.Put(p)
+
 	for _, field := range message {
 		data, ok := fields[field]
 		if !ok {
 			return nil, errMissingField
 		}
-		field.Packer()(&p, data)
+		field.Packer()(p, data)
 	}
 
 	if p.Errored() { // Prevent the datastream from leaking
@@ -48,28 +57,138 @@ func (Codec) Pack(op salticidae.Opcode, fields map[Field]interface{}) (Msg, erro
 	}, nil
 }
 
-// Parse attempts to convert a byte stream into a message.
-func (Codec) Parse(op salticidae.Opcode, ds salticidae.DataStream) (Msg, error) {
-	message, ok := Messages[op]
+// Framing is how the payload of a message sent to a peer is framed, as
+// negotiated in the version handshake
+type Framing struct {
+	// If Sequenced, the payload is prefixed with Sequence
+	Sequenced bool
+	Sequence  uint64
+
+	// If Compressed, the rest of the payload is prefixed with the compression
+	// applied to it. The rest is compressed with Compression if it's at least
+	// Threshold bytes long and compressing shrinks it. Otherwise, it isn't
+	// compressed.
+	Compressed  bool
+	Compression compression.Type
+	Threshold   int
+}
+
+// Frame returns [m] with its payload framed as described by [f]
+func (Codec) Frame(m Msg, f Framing) (Msg, error) {
+	message, ok := Messages[m.Op()]
 	if !ok {
 		return nil, errBadOp
 	}
 
+	p := packers.Get()
+	defer packers.Put(p)
+
+	fields := make(map[Field]interface{}, len(message))
+	for _, field := range message {
+		data := m.Get(field)
+		field.Packer()(p, data)
+		fields[field] = data
+	}
+
+	if p.Errored() {
+		return nil, p.Err
+	}
+
+	payload := p.Bytes
+	if f.Compressed {
+		applied := compression.None
+		if f.Compression != compression.None && len(payload) >= f.Threshold {
+			compressed, err := compression.Compress(f.Compression, payload)
+			if err != nil {
+				return nil, err
+			}
+			if len(compressed) < len(payload) {
+				payload = compressed
+				applied = f.Compression
+			}
+		}
+		payload = append([]byte{byte(applied)}, payload...)
+	}
+	if f.Sequenced {
+		sequenced := make([]byte, wrappers.LongLen+len(payload))
+		binary.BigEndian.PutUint64(sequenced, f.Sequence)
+		copy(sequenced[wrappers.LongLen:], payload)
+		payload = sequenced
+	}
+
+	return &msg{
+		op:     m.Op(),
+		ds:     salticidae.NewDataStreamFromBytes(payload, false),
+		fields: fields,
+	}, nil
+}
+
+// Parse attempts to convert a byte stream into a message.
+func (c Codec) Parse(op salticidae.Opcode, ds salticidae.DataStream) (Msg, error) {
+	return c.parse(op, readBytes(ds), ds)
+}
+
+// ParseFramed attempts to convert a byte stream, framed as described by [f],
+// into a message. Returns the message's sequence number, if it's sequenced.
+// The decompressed payload may be at most [maxSize] bytes long.
+func (c Codec) ParseFramed(op salticidae.Opcode, ds salticidae.DataStream, f Framing, maxSize int) (Msg, uint64, error) {
+	payload := readBytes(ds)
+
+	seq := uint64(0)
+	if f.Sequenced {
+		if len(payload) < wrappers.LongLen {
+			return nil, 0, errBadLength
+		}
+		seq = binary.BigEndian.Uint64(payload)
+		payload = payload[wrappers.LongLen:]
+	}
+	if f.Compressed {
+		if len(payload) == 0 {
+			return nil, 0, errBadLength
+		}
+		decompressed, err := compression.Decompress(compression.Type(payload[0]), payload[1:], maxSize)
+		if err != nil {
+			return nil, 0, err
+		}
+		payload = decompressed
+	}
+
+	m, err := c.parse(op, payload, ds)
+	return m, seq, err
+}
+
+// readBytes copies the contents of [ds]
+func readBytes(ds salticidae.DataStream) []byte {
 	// TODO: make this work without copy
 	size := ds.Size()
-	p := wrappers.Packer{Bytes: make([]byte, size)}
+	bytes := make([]byte, size)
 
 	byteHandle := ds.GetDataInPlace(size)
 	defer byteHandle.Release()
 
-	copy(p.Bytes, byteHandle.Get())
+	copy(bytes, byteHandle.Get())
+	return bytes
+}
 
+func (Codec) parse(op salticidae.Opcode, bytes []byte, ds salticidae.DataStream) (Msg, error) {
+	message, ok := Messages[op]
+	if !ok {
+		return nil, errBadOp
+	}
+
+	p := wrappers.Packer{Bytes: bytes}
+
+	optional, extensible := ExtensibleMessages[op]
 	fields := make(map[Field]interface{}, len(message))
-	for _, field := range message {
+	for i, field := range message {
+		// Peers running older versions stop before the fields added since
+		if extensible && i >= len(message)-optional && p.Offset == len(bytes) {
+			break
+		}
 		fields[field] = field.Unpacker()(&p)
 	}
 
-	if p.Offset != size {
+	if p.Offset != len(bytes) && !extensible {
 		return nil, errBadLength
 	}
 
@@ -6,6 +6,7 @@ package networking
 import (
 	"github.com/ava-labs/salticidae-go"
 
+	"github.com/ava-labs/gecko/snow/networking"
 	"github.com/ava-labs/gecko/utils/wrappers"
 )
 
@@ -27,6 +28,11 @@ const (
 	TxID                        // Used for throughput tests
 	Tx                          // Used for throughput tests
 	Status                      // Used for throughput tests
+	Duration                    // Used for maintenance announcements
+	NetworkSize                 // Used in handshake
+	Compression                 // Used in handshake
+	Sequenced                   // Used in handshake
+	Features                    // Used in handshake
 )
 
 // Packer returns the packer function that can be used to pack this field.
@@ -58,6 +64,16 @@ func (f Field) Packer() func(*wrappers.Packer, interface{}) {
 		return wrappers.TryPackBytes
 	case Status:
 		return wrappers.TryPackInt
+	case Duration:
+		return wrappers.TryPackLong
+	case NetworkSize:
+		return wrappers.TryPackLong
+	case Compression:
+		return wrappers.TryPackByte
+	case Sequenced:
+		return wrappers.TryPackBool
+	case Features:
+		return wrappers.TryPackLong
 	default:
 		return nil
 	}
@@ -92,6 +108,16 @@ func (f Field) Unpacker() func(*wrappers.Packer) interface{} {
 		return wrappers.TryUnpackBytes
 	case Status:
 		return wrappers.TryUnpackInt
+	case Duration:
+		return wrappers.TryUnpackLong
+	case NetworkSize:
+		return wrappers.TryUnpackLong
+	case Compression:
+		return wrappers.TryUnpackByte
+	case Sequenced:
+		return wrappers.TryUnpackBool
+	case Features:
+		return wrappers.TryUnpackLong
 	default:
 		return nil
 	}
@@ -123,6 +149,16 @@ func (f Field) String() string {
 		return "Tx"
 	case Status:
 		return "Status"
+	case Duration:
+		return "Duration"
+	case NetworkSize:
+		return "NetworkSize"
+	case Compression:
+		return "Compression"
+	case Sequenced:
+		return "Sequenced"
+	case Features:
+		return "Features"
 	default:
 		return "Unknown Field"
 	}
@@ -154,6 +190,13 @@ const (
 	// Throughput test:
 	IssueTx
 	DecidedTx
+	// Maintenance:
+	Maintenance
+	// State sync:
+	GetStateSummary
+	StateSummary
+	// Frontier gossip:
+	GossipFrontier
 )
 
 // Defines the messages that can be sent/received with this network
@@ -161,9 +204,9 @@ var (
 	Messages = map[salticidae.Opcode][]Field{
 		// Handshake:
 		GetVersion:  []Field{},
-		Version:     []Field{NetworkID, MyTime, VersionStr},
+		Version:     []Field{NetworkID, MyTime, VersionStr, Compression, Sequenced, Features},
 		GetPeerList: []Field{},
-		PeerList:    []Field{Peers},
+		PeerList:    []Field{Peers, NetworkSize},
 		// Bootstrapping:
 		GetAcceptedFrontier: []Field{ChainID, RequestID},
 		AcceptedFrontier:    []Field{ChainID, RequestID, ContainerIDs},
@@ -183,5 +226,35 @@ var (
 		// Throughput test:
 		IssueTx:   []Field{ChainID, Tx},
 		DecidedTx: []Field{TxID, Status},
+		// Maintenance:
+		Maintenance: []Field{Duration},
+		// State sync:
+		GetStateSummary: []Field{ChainID, RequestID},
+		StateSummary:    []Field{ChainID, RequestID, ContainerBytes},
+		// Frontier gossip:
+		GossipFrontier: []Field{ChainID, RequestID, ContainerIDs},
 	}
+
+	// ExtensibleMessages can change between versions without breaking
+	// compatibility. Each maps to the number of fields, at the end of the
+	// message, that peers running older versions may not send. Bytes after the
+	// last field, sent by peers running newer versions, are ignored.
+	ExtensibleMessages = map[salticidae.Opcode]int{
+		Version: 1,
+	}
+)
+
+// Optional protocol features. The features a node supports are announced in
+// its version messages.
+const (
+	// MaintenanceFeature is set if the node understands Maintenance messages
+	MaintenanceFeature networking.Features = 1 << iota
+	// StateSyncFeature is set if the node answers GetStateSummary messages
+	StateSyncFeature
+	// FrontierGossipFeature is set if the node understands GossipFrontier
+	// messages
+	FrontierGossipFeature
+
+	// SupportedFeatures are the features this node supports
+	SupportedFeatures = MaintenanceFeature | StateSyncFeature | FrontierGossipFeature
 )
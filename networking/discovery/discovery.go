@@ -0,0 +1,280 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package discovery
+
+import (
+	"bufio"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ava-labs/gecko/utils"
+	"github.com/ava-labs/gecko/utils/logging"
+	"github.com/ava-labs/gecko/utils/timer"
+)
+
+const (
+	// InitialBackoff is how long to wait before the first attempt to connect
+	// to peers, and before the first retry. Each retry waits twice as long as
+	// the last.
+	InitialBackoff = time.Second
+	// MaxBackoff is the longest to wait between attempts to connect to peers.
+	// It's also how often the known peers are saved while this node is well
+	// connected.
+	MaxBackoff = 5 * time.Minute
+	// MinPeers is the fewest peers this node can be connected to before it
+	// dials the peers it knows of and the peers the DNS seeds resolve to
+	MinPeers = 8
+	// MaxKnownPeers is the most peers saved in the known peers file
+	MaxKnownPeers = 1000
+)
+
+// Config is where Discovery finds peers
+type Config struct {
+	// Bootstrap peers are always dialed until this node connects to them
+	Bootstrap []utils.IPDesc
+
+	// Seeds are host names that resolve to the IPs of peers, optionally
+	// followed by the port the peers listen on. If there's no port,
+	// DefaultPort is used.
+	Seeds       []string
+	DefaultPort uint16
+
+	// KnownPeersFile is where the peers this node was connected to are saved,
+	// to be dialed when the node restarts. If empty, peers aren't saved.
+	KnownPeersFile string
+}
+
+// Discovery finds peers for this node to connect to when it isn't connected
+// to enough of them. The bootstrap peers, the peers the DNS seeds resolve to,
+// and the peers this node was connected to before it restarted are dialed.
+// Attempts are retried with exponential backoff until this node is connected
+// to all the bootstrap peers and at least MinPeers peers.
+type Discovery struct {
+	log    logging.Logger
+	config Config
+
+	// Connects to a peer
+	dial func(utils.IPDesc)
+	// Returns the IPs of the peers this node is connected to
+	peers func() []utils.IPDesc
+	// Returns the IPs a host name resolves to
+	resolve func(string) ([]net.IP, error)
+
+	lock    sync.Mutex
+	known   []utils.IPDesc
+	backoff time.Duration
+
+	timer *timer.Timer
+}
+
+// Initialize the discovery of peers with [config]. [dial] is called with the
+// IPs of the peers to connect to, and [peers] returns the IPs of the peers
+// this node is connected to.
+func (d *Discovery) Initialize(log logging.Logger, config Config, dial func(utils.IPDesc), peers func() []utils.IPDesc) {
+	d.log = log
+	d.config = config
+	d.dial = dial
+	d.peers = peers
+	if d.resolve == nil {
+		d.resolve = net.LookupIP
+	}
+	d.backoff = InitialBackoff
+
+	known, err := loadKnownPeers(config.KnownPeersFile)
+	if err != nil {
+		log.Warn("Failed to load the known peers from %s due to %s", config.KnownPeersFile, err)
+	}
+	d.known = known
+
+	d.timer = timer.NewTimer(d.discover)
+	go log.RecoverAndPanic(d.timer.Dispatch)
+	d.timer.SetTimeoutIn(InitialBackoff)
+}
+
+// Shutdown stops dialing peers, and saves the peers this node is connected to
+func (d *Discovery) Shutdown() {
+	if d.timer == nil {
+		return
+	}
+	d.timer.Stop()
+
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	if peers := d.peers(); len(peers) > 0 {
+		d.saveKnownPeers(peers, connectedSet(peers))
+	}
+}
+
+// discover dials peers if this node isn't connected to enough of them, and
+// schedules the next attempt
+func (d *Discovery) discover() {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	peers := d.peers()
+	connected := connectedSet(peers)
+
+	wellConnected := len(peers) >= MinPeers
+	for _, ip := range d.config.Bootstrap {
+		if !connected[ip.String()] {
+			d.log.Debug("Dialing bootstrap peer %s", ip)
+			d.dial(ip)
+			wellConnected = false
+		}
+	}
+	if len(peers) < MinPeers {
+		for _, ip := range append(d.resolveSeeds(), d.known...) {
+			if !connected[ip.String()] {
+				d.dial(ip)
+			}
+		}
+	}
+
+	// Save the peers in case this node restarts
+	if len(peers) > 0 {
+		d.saveKnownPeers(peers, connected)
+	}
+
+	if wellConnected {
+		// Check again later in case this node loses its peers
+		d.backoff = InitialBackoff
+		d.timer.SetTimeoutIn(MaxBackoff)
+		return
+	}
+
+	d.log.Debug("Connected to %d peers. Retrying in %s", len(peers), d.backoff)
+	d.timer.SetTimeoutIn(d.backoff)
+	d.backoff *= 2
+	if d.backoff > MaxBackoff {
+		d.backoff = MaxBackoff
+	}
+}
+
+// resolveSeeds returns the IPs of the peers the DNS seeds resolve to
+func (d *Discovery) resolveSeeds() []utils.IPDesc {
+	ips := []utils.IPDesc(nil)
+	for _, seed := range d.config.Seeds {
+		host, port, err := splitSeed(seed, d.config.DefaultPort)
+		if err != nil {
+			d.log.Warn("Skipping DNS seed %q due to %s", seed, err)
+			continue
+		}
+		resolved, err := d.resolve(host)
+		if err != nil {
+			d.log.Debug("Failed to resolve DNS seed %s due to %s", host, err)
+			continue
+		}
+		for _, ip := range resolved {
+			// Peers can only be connected to over IPv4
+			if ip.To4() == nil {
+				continue
+			}
+			ips = append(ips, utils.IPDesc{
+				IP:   ip,
+				Port: port,
+			})
+		}
+	}
+	return ips
+}
+
+// saveKnownPeers saves [peers], the peers this node is connected to,
+// followed by the peers it knew of before. Assumes the lock is held.
+func (d *Discovery) saveKnownPeers(peers []utils.IPDesc, connected map[string]bool) {
+	if d.config.KnownPeersFile == "" {
+		return
+	}
+
+	known := append([]utils.IPDesc(nil), peers...)
+	for _, ip := range d.known {
+		if !connected[ip.String()] {
+			known = append(known, ip)
+		}
+	}
+	if len(known) > MaxKnownPeers {
+		known = known[:MaxKnownPeers]
+	}
+	d.known = known
+
+	if err := storeKnownPeers(d.config.KnownPeersFile, known); err != nil {
+		d.log.Warn("Failed to save the known peers to %s due to %s", d.config.KnownPeersFile, err)
+	}
+}
+
+// connectedSet returns the set of the string representations of [peers]
+func connectedSet(peers []utils.IPDesc) map[string]bool {
+	connected := make(map[string]bool, len(peers))
+	for _, ip := range peers {
+		connected[ip.String()] = true
+	}
+	return connected
+}
+
+// splitSeed returns the host name and port of [seed]
+func splitSeed(seed string, defaultPort uint16) (string, uint16, error) {
+	host, portStr, err := net.SplitHostPort(seed)
+	if err != nil {
+		// There's no port
+		return seed, defaultPort, nil
+	}
+	port, err := strconv.ParseUint(portStr, 10, 16)
+	if err != nil {
+		return "", 0, fmt.Errorf("invalid port %q", portStr)
+	}
+	return host, uint16(port), nil
+}
+
+// loadKnownPeers returns the peers saved in [file], one per line. If [file]
+// is empty or doesn't exist, there are no known peers.
+func loadKnownPeers(file string) ([]utils.IPDesc, error) {
+	if file == "" {
+		return nil, nil
+	}
+	f, err := os.Open(file)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	known := []utils.IPDesc(nil)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		ip, err := utils.ToIPDesc(line)
+		if err != nil {
+			return nil, err
+		}
+		known = append(known, ip)
+	}
+	return known, scanner.Err()
+}
+
+// storeKnownPeers saves [known] to [file], one per line. The file is replaced
+// atomically, so a crash can't leave it half written.
+func storeKnownPeers(file string, known []utils.IPDesc) error {
+	builder := strings.Builder{}
+	for _, ip := range known {
+		builder.WriteString(ip.String())
+		builder.WriteString("\n")
+	}
+
+	tmp := file + ".tmp"
+	if err := ioutil.WriteFile(tmp, []byte(builder.String()), 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, file)
+}
@@ -0,0 +1,192 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package discovery
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/ava-labs/gecko/utils"
+	"github.com/ava-labs/gecko/utils/logging"
+)
+
+type testNetwork struct {
+	lock   sync.Mutex
+	dialed map[string]int
+	peers  []utils.IPDesc
+}
+
+func newTestNetwork(peers ...utils.IPDesc) *testNetwork {
+	return &testNetwork{
+		dialed: make(map[string]int),
+		peers:  peers,
+	}
+}
+
+func (n *testNetwork) dial(ip utils.IPDesc) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	n.dialed[ip.String()]++
+}
+
+func (n *testNetwork) connected() []utils.IPDesc {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	return append([]utils.IPDesc(nil), n.peers...)
+}
+
+func (n *testNetwork) timesDialed(ip string) int {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	return n.dialed[ip]
+}
+
+func testIP(i int) utils.IPDesc {
+	return utils.IPDesc{
+		IP:   net.IPv4(10, 0, 0, byte(i)),
+		Port: 9651,
+	}
+}
+
+func TestDiscoveryBackoff(t *testing.T) {
+	bootstrap := testIP(1)
+	network := newTestNetwork()
+
+	d := Discovery{}
+	d.Initialize(logging.NoLog{}, Config{Bootstrap: []utils.IPDesc{bootstrap}}, network.dial, network.connected)
+	defer d.Shutdown()
+
+	for i := 0; i < 3; i++ {
+		d.discover()
+	}
+	if dialed := network.timesDialed(bootstrap.String()); dialed != 3 {
+		t.Fatalf("Expected the bootstrap peer to be dialed 3 times but it was dialed %d times", dialed)
+	}
+	d.lock.Lock()
+	backoff := d.backoff
+	d.lock.Unlock()
+	if backoff != 8*InitialBackoff {
+		t.Fatalf("Expected the backoff to be %s but it was %s", 8*InitialBackoff, backoff)
+	}
+
+	// Once this node is connected to the bootstrap peer, and enough others,
+	// it stops dialing
+	network.lock.Lock()
+	for i := 1; i <= MinPeers; i++ {
+		network.peers = append(network.peers, testIP(i))
+	}
+	network.lock.Unlock()
+
+	d.discover()
+	if dialed := network.timesDialed(bootstrap.String()); dialed != 3 {
+		t.Fatalf("Shouldn't have dialed the bootstrap peer once connected to it")
+	}
+	d.lock.Lock()
+	backoff = d.backoff
+	d.lock.Unlock()
+	if backoff != InitialBackoff {
+		t.Fatalf("Expected the backoff to be reset to %s but it was %s", InitialBackoff, backoff)
+	}
+}
+
+func TestDiscoveryMaxBackoff(t *testing.T) {
+	network := newTestNetwork()
+
+	d := Discovery{}
+	d.Initialize(logging.NoLog{}, Config{}, network.dial, network.connected)
+	defer d.Shutdown()
+
+	for i := 0; i < 20; i++ {
+		d.discover()
+	}
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	if d.backoff != MaxBackoff {
+		t.Fatalf("Expected the backoff to be capped at %s but it was %s", MaxBackoff, d.backoff)
+	}
+}
+
+func TestDiscoverySeeds(t *testing.T) {
+	network := newTestNetwork()
+
+	d := Discovery{
+		resolve: func(host string) ([]net.IP, error) {
+			switch host {
+			case "seed1.example.com":
+				return []net.IP{net.ParseIP("1.2.3.4"), net.ParseIP("::1")}, nil
+			case "seed2.example.com":
+				return []net.IP{net.ParseIP("5.6.7.8")}, nil
+			default:
+				return nil, fmt.Errorf("no such host %s", host)
+			}
+		},
+	}
+	config := Config{
+		Seeds:       []string{"seed1.example.com", "seed2.example.com:9000", "seed3.example.com"},
+		DefaultPort: 9651,
+	}
+	d.Initialize(logging.NoLog{}, config, network.dial, network.connected)
+	defer d.Shutdown()
+
+	d.discover()
+	if dialed := network.timesDialed("1.2.3.4:9651"); dialed != 1 {
+		t.Fatalf("Should have dialed the first seed's peer on the default port")
+	}
+	if dialed := network.timesDialed("5.6.7.8:9000"); dialed != 1 {
+		t.Fatalf("Should have dialed the second seed's peer on the given port")
+	}
+	network.lock.Lock()
+	numDialed := len(network.dialed)
+	network.lock.Unlock()
+	if numDialed != 2 {
+		t.Fatalf("Expected 2 peers to be dialed but %d were", numDialed)
+	}
+}
+
+func TestDiscoveryKnownPeers(t *testing.T) {
+	dir, err := ioutil.TempDir("", "discovery")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "known-peers")
+
+	network := newTestNetwork(testIP(1), testIP(2))
+	d := Discovery{}
+	d.Initialize(logging.NoLog{}, Config{KnownPeersFile: file}, network.dial, network.connected)
+	d.Shutdown()
+
+	// After a restart, the peers this node was connected to are dialed
+	network = newTestNetwork()
+	d = Discovery{}
+	d.Initialize(logging.NoLog{}, Config{KnownPeersFile: file}, network.dial, network.connected)
+	defer d.Shutdown()
+
+	d.discover()
+	for i := 1; i <= 2; i++ {
+		if dialed := network.timesDialed(testIP(i).String()); dialed != 1 {
+			t.Fatalf("Should have dialed known peer %s", testIP(i))
+		}
+	}
+}
+
+func TestSplitSeed(t *testing.T) {
+	if host, port, err := splitSeed("seed.example.com", 9651); err != nil || host != "seed.example.com" || port != 9651 {
+		t.Fatalf("Wrong split: %s %d %v", host, port, err)
+	}
+	if host, port, err := splitSeed("seed.example.com:9000", 9651); err != nil || host != "seed.example.com" || port != 9000 {
+		t.Fatalf("Wrong split: %s %d %v", host, port, err)
+	}
+	if _, _, err := splitSeed("seed.example.com:abc", 9651); err == nil {
+		t.Fatalf("Should have errored due to the invalid port")
+	}
+}
@@ -30,7 +30,9 @@ import (
 	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/snow/networking"
 	"github.com/ava-labs/gecko/snow/validators"
+	"github.com/ava-labs/gecko/staking"
 	"github.com/ava-labs/gecko/utils"
+	"github.com/ava-labs/gecko/utils/compression"
 	"github.com/ava-labs/gecko/utils/hashing"
 	"github.com/ava-labs/gecko/utils/logging"
 	"github.com/ava-labs/gecko/utils/random"
@@ -57,21 +59,54 @@ Periodically gossip peerlists.
 const (
 	// CurrentVersion this avalanche instance is executing.
 	CurrentVersion = "avalanche/0.0.1"
+	// MinCompatibleVersion is the oldest version peers can run and still be
+	// connected to
+	MinCompatibleVersion = "avalanche/0.0.1"
+	// PrevMinCompatibleVersion is the oldest version peers can run and still
+	// be connected to before VersionUpgradeTime, so that nodes can upgrade one
+	// at a time
+	PrevMinCompatibleVersion = "avalanche/0.0.1"
+	// RequiredFeatures are the features peers must support to be connected to
+	RequiredFeatures networking.Features = 0
 	// MaxClockDifference allowed between connected nodes.
 	MaxClockDifference = time.Minute
+	// MaxClockSkew is the furthest this node's clock can be from the median of
+	// its peers' clocks before this node reports itself as unhealthy.
+	MaxClockSkew = 10 * time.Second
 	// PeerListGossipSpacing is the amount of time to wait between pushing this
 	// node's peer list to other nodes.
 	PeerListGossipSpacing = time.Minute
-	// PeerListGossipSize is the number of peers to gossip each period.
+	// PeerListGossipSize is the most peers to gossip to each period.
 	PeerListGossipSize = 100
+	// PeerListMinGossipSize is the fewest peers to gossip to each period. The
+	// number of peers gossiped to grows with the estimated network size.
+	PeerListMinGossipSize = 10
+	// PeerListPageSize is the most IPs sent in one peer list message. Larger
+	// peer lists are split over multiple messages.
+	PeerListPageSize = 256
 	// PeerListStakerGossipFraction calculates the fraction of stakers that are
 	// gossiped to. If set to 1, then only stakers will be gossiped to.
 	PeerListStakerGossipFraction = 2
 	// GetVersionTimeout is the amount of time to wait before sending a
 	// getVersion message to a partially connected peer
 	GetVersionTimeout = 2 * time.Second
+	// DialSpacing is the amount of time to wait between checks for peers to
+	// dial, such as validators this node isn't connected to anymore
+	DialSpacing = 2 * time.Second
+	// DialInitialBackoff is how long to wait before redialing a peer that
+	// couldn't be connected to. Each failed dial doubles the wait.
+	DialInitialBackoff = 10 * time.Second
+	// DialMaxBackoff is the longest to wait before redialing a peer
+	DialMaxBackoff = 10 * time.Minute
+	// ReputationHalfLife is how long it takes for the penalties peers are
+	// given for misbehaving to be halved
+	ReputationHalfLife = 10 * time.Minute
 )
 
+// VersionUpgradeTime is when peers running a version older than
+// MinCompatibleVersion stop being compatible. If zero, they aren't.
+var VersionUpgradeTime = time.Time{}
+
 // Manager is the struct that will be accessed on event calls
 var (
 	HandshakeNet = Handshake{}
@@ -90,17 +125,57 @@ type Handshake struct {
 
 	log           logging.Logger
 	vdrs          validators.Set
-	myAddr        salticidae.NetAddr
 	myID          ids.ShortID
 	net           salticidae.PeerNetwork
 	enableStaking bool // Should only be false for local tests
+	maxPeers      int  // If 0, the number of peers isn't limited
+
+	// This node's public address, which changes if its public IP does
+	myAddrLock sync.Mutex
+	myAddr     salticidae.NetAddr
 
 	clock       timer.Clock
 	pending     AddrCert // Connections that I haven't gotten version messages from
 	connections AddrCert // Connections that I think are connected
 
+	// How far each peer's clock is from mine, as of its version message
+	clockSkew networking.ClockSkew
+
+	// Each peer's estimate of the network size, as of its last peer list
+	networkSizes networking.NetworkSize
+
+	// The compression this node accepts, and that each peer accepts, as
+	// announced in version messages
+	acceptedCompression compression.Type
+	peerCompression     networking.PeerCompression
+
+	// Decides which peers this node can talk to, from their versions and
+	// features. Tracks the features each peer supports, as announced in
+	// version messages.
+	compatibility networking.Compatibility
+	peerFeatures  networking.PeerFeatures
+
+	// Scores peers by their misbehavior, and bans the worst
+	reputation networking.Reputation
+
+	// If sequenced, peers prefix the messages they send this node with
+	// sequence numbers, and replayed messages are dropped. Tracks which peers
+	// expect the same, as announced in version messages.
+	sequenced bool
+	replays   networking.ReplayGuard
+
+	// The node ID the certificate of the peer at each IP must hash to, if
+	// it's known in advance
+	expectedIDsLock sync.Mutex
+	expectedIDs     map[string]ids.ShortID
+
+	// Dials peers until enough outbound connections are made, and redials
+	// validators that disconnect
+	connManager networking.ConnectionManager
+
 	versionTimeout   timer.TimeoutManager
 	peerListGossiper *timer.Repeater
+	dialer           *timer.Repeater
 
 	awaitingLock sync.Mutex
 	awaiting     []*networking.AwaitingConnections
@@ -117,6 +192,12 @@ func (nm *Handshake) Initialize(
 	registerer prometheus.Registerer,
 	enableStaking bool,
 	networkID uint32,
+	maxPeers int,
+	targetOutboundPeers int,
+	acceptedCompression compression.Type,
+	sequenced bool,
+	banThreshold float64,
+	banDuration time.Duration,
 ) {
 	log.AssertTrue(nm.net == nil, "Should only register network handlers once")
 	nm.log = log
@@ -126,6 +207,13 @@ func (nm *Handshake) Initialize(
 	nm.net = peerNet
 	nm.enableStaking = enableStaking
 	nm.networkID = networkID
+	nm.maxPeers = maxPeers
+	nm.acceptedCompression = acceptedCompression
+	nm.sequenced = sequenced
+	nm.expectedIDs = make(map[string]ids.ShortID)
+	nm.reputation.Initialize(banThreshold, ReputationHalfLife, banDuration, nm.disconnect)
+	nm.initCompatibility()
+	nm.connManager.Initialize(targetOutboundPeers, DialInitialBackoff, DialMaxBackoff, nm.dial, vdrs.Contains)
 
 	net := peerNet.AsMsgNetwork()
 
@@ -145,6 +233,27 @@ func (nm *Handshake) Initialize(
 	go nm.log.RecoverAndPanic(nm.versionTimeout.Dispatch)
 	nm.peerListGossiper = timer.NewRepeater(nm.gossipPeerList, PeerListGossipSpacing)
 	go nm.log.RecoverAndPanic(nm.peerListGossiper.Dispatch)
+	nm.dialer = timer.NewRepeater(nm.dialPeers, DialSpacing)
+	go nm.log.RecoverAndPanic(nm.dialer.Dispatch)
+}
+
+// initCompatibility sets which peers this node can talk to
+func (nm *Handshake) initCompatibility() {
+	current, err := networking.ParseVersion(CurrentVersion)
+	nm.log.AssertNoError(err)
+	minCompatible, err := networking.ParseVersion(MinCompatibleVersion)
+	nm.log.AssertNoError(err)
+	prevMinCompatible, err := networking.ParseVersion(PrevMinCompatibleVersion)
+	nm.log.AssertNoError(err)
+
+	nm.compatibility = networking.Compatibility{
+		Current:              current,
+		MinCompatible:        minCompatible,
+		RequiredFeatures:     RequiredFeatures,
+		UpgradeTime:          VersionUpgradeTime,
+		PrevMinCompatible:    prevMinCompatible,
+		PrevRequiredFeatures: RequiredFeatures,
+	}
 }
 
 // AwaitConnections ...
@@ -174,11 +283,15 @@ func (nm *Handshake) gossipPeerList() {
 		}
 	}
 
-	numStakersToSend := (PeerListGossipSize + PeerListStakerGossipFraction - 1) / PeerListStakerGossipFraction
+	networkSize, _ := nm.NetworkSize()
+	nm.networkSize.Set(float64(networkSize))
+	gossipSize := networking.GossipFanOut(networkSize, PeerListMinGossipSize, PeerListGossipSize)
+
+	numStakersToSend := (gossipSize + PeerListStakerGossipFraction - 1) / PeerListStakerGossipFraction
 	if len(stakers) < numStakersToSend {
 		numStakersToSend = len(stakers)
 	}
-	numNonStakersToSend := PeerListGossipSize - numStakersToSend
+	numNonStakersToSend := gossipSize - numStakersToSend
 	if len(nonStakers) < numNonStakersToSend {
 		numNonStakersToSend = len(nonStakers)
 	}
@@ -204,14 +317,180 @@ func (nm *Handshake) gossipPeerList() {
 	nm.SendPeerList(ips...)
 }
 
+// dialPeers dials the validators this node was connected to but isn't
+// anymore, and enough other peers to reach the target number of outbound
+// connections
+func (nm *Handshake) dialPeers() {
+	nm.updateConnectedStake()
+	nm.connManager.Update()
+	nm.numOutbound.Set(float64(nm.connManager.Outbound()))
+}
+
+// makeRoom returns true if the peer [id] may be connected to. If the peer
+// limit has been reached, a non-validator is disconnected to make room for a
+// validator. Validators are connected to even if there is nobody to evict.
+func (nm *Handshake) makeRoom(id ids.ShortID) bool {
+	if nm.maxPeers == 0 || nm.connections.Len() < nm.maxPeers {
+		return true
+	}
+	if !nm.vdrs.Contains(id) {
+		return false
+	}
+
+	ips, ids := nm.connections.RawConns()
+	for i, connectedID := range ids {
+		if !nm.vdrs.Contains(connectedID) {
+			nm.log.Debug("Disconnecting from %s to make room for validator %s", toIPDesc(ips[i]), id)
+			nm.net.DelPeer(ips[i])
+			break
+		}
+	}
+	return true
+}
+
+// updateConnectedStake reports the percent of the validators' stake held by
+// this node and the validators it's connected to
+func (nm *Handshake) updateConnectedStake() {
+	totalWeight := nm.vdrs.Weight()
+	if totalWeight == 0 {
+		nm.connectedStake.Set(0)
+		return
+	}
+
+	connectedWeight := uint64(0)
+	for _, vdr := range nm.vdrs.List() {
+		if vdrID := vdr.ID(); vdrID.Equals(nm.myID) || nm.connections.ContainsID(vdrID) {
+			connectedWeight += vdr.Weight()
+		}
+	}
+	nm.connectedStake.Set(100 * float64(connectedWeight) / float64(totalWeight))
+}
+
+// AcceptedCompression returns the compression this node accepts
+func (nm *Handshake) AcceptedCompression() compression.Type { return nm.acceptedCompression }
+
+// PeerCompression returns the object that tracks the compression each
+// connected peer accepts
+func (nm *Handshake) PeerCompression() *networking.PeerCompression { return &nm.peerCompression }
+
+// PeerFeatures returns the object that tracks the features each connected
+// peer supports
+func (nm *Handshake) PeerFeatures() *networking.PeerFeatures { return &nm.peerFeatures }
+
+// Reputation returns the object that scores peers by their misbehavior. Peers
+// whose score reaches the ban threshold are disconnected.
+func (nm *Handshake) Reputation() *networking.Reputation { return &nm.reputation }
+
+// SetMyIP replaces this node's public IP, such as when the ISP leases the NAT
+// device in front of the node a new one. Peers at this node's IP aren't
+// connected to.
+func (nm *Handshake) SetMyIP(ip utils.IPDesc) error {
+	cErr := salticidae.NewError()
+	addr := salticidae.NewNetAddrFromIPPortString(ip.String(), true, &cErr)
+	if code := cErr.GetCode(); code != 0 {
+		return errors.New(salticidae.StrError(code))
+	}
+
+	nm.myAddrLock.Lock()
+	defer nm.myAddrLock.Unlock()
+
+	nm.myAddr = addr
+	return nil
+}
+
+// isMyAddr returns true if [addr] is this node's public address
+func (nm *Handshake) isMyAddr(addr salticidae.NetAddr) bool {
+	nm.myAddrLock.Lock()
+	defer nm.myAddrLock.Unlock()
+
+	return nm.myAddr.IsEq(addr)
+}
+
+// Sequenced returns true if peers prefix the messages they send this node with
+// sequence numbers
+func (nm *Handshake) Sequenced() bool { return nm.sequenced }
+
+// ReplayGuard returns the object that detects replayed messages, and tracks
+// which connected peers expect sequenced messages
+func (nm *Handshake) ReplayGuard() *networking.ReplayGuard { return &nm.replays }
+
+// disconnect from the peer [id], if connected
+func (nm *Handshake) disconnect(id ids.ShortID) {
+	if ip, ok := nm.connections.GetIP(id); ok {
+		nm.log.Info("Disconnecting from %s because it was banned", id)
+		nm.net.DelPeer(ip)
+	}
+}
+
+// ExpectPeerID requires the peer at [ip] to present a certificate that hashes
+// to the node ID [id], such as a bootstrap peer whose ID is configured. If the
+// peer presents any other certificate, it is disconnected, so that the peer at
+// an IP can't be impersonated.
+func (nm *Handshake) ExpectPeerID(ip utils.IPDesc, id ids.ShortID) {
+	nm.expectedIDsLock.Lock()
+	defer nm.expectedIDsLock.Unlock()
+
+	nm.expectedIDs[ip.String()] = id
+}
+
+func (nm *Handshake) expectedID(ip utils.IPDesc) (ids.ShortID, bool) {
+	nm.expectedIDsLock.Lock()
+	defer nm.expectedIDsLock.Unlock()
+
+	id, ok := nm.expectedIDs[ip.String()]
+	return id, ok
+}
+
+// Dial connects to the peer at [ip] now, even if this node has enough
+// outbound connections. The peer is redialed with backoff until it's
+// connected to.
+func (nm *Handshake) Dial(ip utils.IPDesc) {
+	// The network library only supports IPv4
+	if ip.IsIPv4() {
+		nm.connManager.Dial(ip)
+	}
+}
+
+// dial connects to the peer at [ip], unless this node is already connected, or
+// connecting, to it
+func (nm *Handshake) dial(ip utils.IPDesc) {
+	cErr := salticidae.NewError()
+	addr := salticidae.NewNetAddrFromIPPortString(ip.String(), false, &cErr)
+	if cErr.GetCode() != 0 {
+		return
+	}
+	if !nm.isMyAddr(addr) && !nm.pending.ContainsIP(addr) && !nm.connections.ContainsIP(addr) {
+		nm.log.Debug("Dialing %s", ip)
+		nm.net.AddPeer(addr)
+	}
+	addr.Free()
+}
+
 // Connections returns the object that tracks the nodes that are currently
 // connected to this node.
 func (nm *Handshake) Connections() Connections { return &nm.connections }
 
+// ClockSkewCheck returns an error if this node's clock is more than
+// MaxClockSkew away from the median of its peers' clocks
+func (nm *Handshake) ClockSkewCheck() (interface{}, error) {
+	return nm.clockSkew.Check(MaxClockSkew)
+}
+
+// NetworkSize returns the estimated number of nodes in the network, and the
+// number of peers the estimate is based on
+func (nm *Handshake) NetworkSize() (uint64, int) {
+	known := nm.connections.Len() + 1 // Include this node
+	if numVdrs := nm.vdrs.Len(); numVdrs > known {
+		known = numVdrs
+	}
+	return nm.networkSizes.Estimate(uint64(known))
+}
+
 // Shutdown the network
 func (nm *Handshake) Shutdown() {
 	nm.versionTimeout.Stop()
 	nm.peerListGossiper.Stop()
+	nm.dialer.Stop()
 }
 
 // SendGetVersion to the requested peer
@@ -227,7 +506,7 @@ func (nm *Handshake) SendGetVersion(addr salticidae.NetAddr) {
 // SendVersion to the requested peer
 func (nm *Handshake) SendVersion(addr salticidae.NetAddr) error {
 	build := Builder{}
-	v, err := build.Version(nm.networkID, nm.clock.Unix(), CurrentVersion)
+	v, err := build.Version(nm.networkID, nm.clock.Unix(), CurrentVersion, nm.acceptedCompression, nm.sequenced, SupportedFeatures)
 	if err != nil {
 		return fmt.Errorf("packing Version failed due to %s", err)
 	}
@@ -255,15 +534,24 @@ func (nm *Handshake) SendPeerList(addrs ...salticidae.NetAddr) error {
 		return nil
 	}
 
-	nm.log.Verbo("Sending %d ips to %d peer(s)", len(ipsToSend), len(addrs))
+	networkSize, _ := nm.NetworkSize()
+	nm.log.Verbo("Sending %d ips and a network size of %d to %d peer(s)", len(ipsToSend), networkSize, len(addrs))
 
 	build := Builder{}
-	pl, err := build.PeerList(ipsToSend)
-	if err != nil {
-		return fmt.Errorf("Packing Peerlist failed due to %w", err)
+	for len(ipsToSend) > 0 {
+		page := ipsToSend
+		if len(page) > PeerListPageSize {
+			page = page[:PeerListPageSize]
+		}
+		ipsToSend = ipsToSend[len(page):]
+
+		pl, err := build.PeerList(page, networkSize)
+		if err != nil {
+			return fmt.Errorf("Packing Peerlist failed due to %w", err)
+		}
+		nm.send(pl, addrs...)
+		nm.numPeerlistSent.Add(float64(len(addrs)))
 	}
-	nm.send(pl, addrs...)
-	nm.numPeerlistSent.Add(float64(len(addrs)))
 	return nil
 }
 
@@ -316,8 +604,15 @@ func peerHandler(_conn *C.struct_peernetwork_conn_t, connected C.bool, _ unsafe.
 
 		HandshakeNet.pending.RemoveIP(addr)
 		HandshakeNet.connections.RemoveIP(addr)
+		HandshakeNet.connManager.Disconnected(ip)
+		HandshakeNet.clockSkew.Remove(cert)
+		HandshakeNet.networkSizes.Remove(cert)
+		HandshakeNet.peerCompression.Remove(cert)
+		HandshakeNet.peerFeatures.Remove(cert)
+		HandshakeNet.replays.Remove(cert)
 
 		HandshakeNet.numPeers.Set(float64(HandshakeNet.connections.Len()))
+		HandshakeNet.updateConnectedStake()
 
 		HandshakeNet.log.Warn("Disconnected from %s", ip)
 
@@ -428,8 +723,20 @@ func version(_msg *C.struct_msg_t, _conn *C.struct_msgnetwork_conn_t, _ unsafe.P
 	build := Builder{}
 	pMsg, err := build.Parse(Version, msg.GetPayloadByMove())
 	if err != nil {
+		// A peer whose version message changed incompatibly may still be
+		// identified by its version, which precedes the fields that changed
+		if pMsg != nil {
+			if peerVersion, ok := pMsg.Get(VersionStr).(string); ok && peerVersion != "" {
+				HandshakeNet.log.Info("Disconnecting from %s, which runs %s, because its Version message couldn't be parsed due to %s", toIPDesc(addr), peerVersion, err)
+
+				HandshakeNet.numIncompatible.Inc()
+				HandshakeNet.net.DelPeer(addr)
+				return
+			}
+		}
 		HandshakeNet.log.Warn("Failed to parse Version message")
 
+		HandshakeNet.reputation.Penalize(cert, networking.InvalidMessagePenalty)
 		HandshakeNet.net.DelPeer(addr)
 		return
 	}
@@ -441,16 +748,57 @@ func version(_msg *C.struct_msg_t, _conn *C.struct_msgnetwork_conn_t, _ unsafe.P
 		return
 	}
 
+	// Peers running versions from before features were announced don't send
+	// them
+	features, _ := pMsg.Get(Features).(uint64)
+	peerVersion, err := networking.ParseVersion(pMsg.Get(VersionStr).(string))
+	if err == nil {
+		err = HandshakeNet.compatibility.Compatible(peerVersion, networking.Features(features))
+	}
+	if err != nil {
+		HandshakeNet.log.Info("Disconnecting from %s because its version is incompatible: %s", toIPDesc(addr), err)
+
+		HandshakeNet.numIncompatible.Inc()
+		HandshakeNet.net.DelPeer(addr)
+		return
+	}
+
+	if HandshakeNet.reputation.Banned(cert) {
+		HandshakeNet.log.Debug("Rejecting %s because it's banned", cert)
+
+		HandshakeNet.net.DelPeer(addr)
+		return
+	}
+
+	if HandshakeNet.enableStaking {
+		ip := toIPDesc(addr)
+		if expectedID, ok := HandshakeNet.expectedID(ip); ok && !cert.Equals(expectedID) {
+			HandshakeNet.log.Warn("Peer %s presented the certificate of %s but should be %s", ip, cert, expectedID)
+
+			HandshakeNet.net.DelPeer(addr)
+			return
+		}
+	}
+
 	myTime := float64(HandshakeNet.clock.Unix())
-	if peerTime := float64(pMsg.Get(MyTime).(uint64)); math.Abs(peerTime-myTime) > MaxClockDifference.Seconds() {
+	peerTime := float64(pMsg.Get(MyTime).(uint64))
+
+	// Record the skew even if the peer is rejected below, so that this node
+	// notices if it's the one whose clock is wrong
+	HandshakeNet.clockSkew.Add(cert, time.Duration(peerTime-myTime)*time.Second)
+	if skew, numPeers := HandshakeNet.clockSkew.Local(); skew > MaxClockSkew || -skew > MaxClockSkew {
+		HandshakeNet.log.Warn("My clock is %s away from the median of %d peers' clocks. Staking and locktimes may not be validated correctly", skew, numPeers)
+	}
+
+	if math.Abs(peerTime-myTime) > MaxClockDifference.Seconds() {
 		HandshakeNet.log.Warn("Peer's clock is too far out of sync with mine. His = %d, Mine = %d (seconds)", uint64(peerTime), uint64(myTime))
 
 		HandshakeNet.net.DelPeer(addr)
 		return
 	}
 
-	if peerVersion := pMsg.Get(VersionStr).(string); !checkCompatibility(CurrentVersion, peerVersion) {
-		HandshakeNet.log.Warn("Bad version")
+	if !HandshakeNet.makeRoom(cert) {
+		HandshakeNet.log.Debug("Rejecting %s because the peer limit was reached", toIPDesc(addr))
 
 		HandshakeNet.net.DelPeer(addr)
 		return
@@ -458,6 +806,12 @@ func version(_msg *C.struct_msg_t, _conn *C.struct_msgnetwork_conn_t, _ unsafe.P
 
 	HandshakeNet.log.Debug("Finishing handshake with %s", toIPDesc(addr))
 
+	// Messages sent to the peer are framed for compression and sequenced from
+	// now on, so its framing is recorded before it's connected
+	HandshakeNet.peerCompression.Add(cert, compression.Type(pMsg.Get(Compression).(uint8)))
+	HandshakeNet.peerFeatures.Add(cert, networking.Features(features))
+	HandshakeNet.replays.Add(cert, pMsg.Get(Sequenced).(bool))
+
 	HandshakeNet.SendPeerList(addr)
 	HandshakeNet.connections.Add(addr, cert)
 
@@ -467,7 +821,10 @@ func version(_msg *C.struct_msg_t, _conn *C.struct_msgnetwork_conn_t, _ unsafe.P
 		HandshakeNet.vdrs.Add(validators.NewValidator(cert, 1))
 	}
 
+	HandshakeNet.connManager.Connected(toIPDesc(addr), cert)
+
 	HandshakeNet.numPeers.Set(float64(HandshakeNet.connections.Len()))
+	HandshakeNet.updateConnectedStake()
 
 	HandshakeNet.awaitingLock.Lock()
 	defer HandshakeNet.awaitingLock.Unlock()
@@ -519,19 +876,41 @@ func peerList(_msg *C.struct_msg_t, _conn *C.struct_msgnetwork_conn_t, _ unsafe.
 	}
 
 	ips := pMsg.Get(Peers).([]utils.IPDesc)
-	cErr := salticidae.NewError()
+	if len(ips) > PeerListPageSize {
+		HandshakeNet.log.Debug("Dropping PeerList message with %d IPs, which exceeds the page size of %d", len(ips), PeerListPageSize)
+		return
+	}
+
+	// Only connected peers' estimates are aggregated, so that a peer's
+	// estimate is dropped once it disconnects
+	conn := salticidae.PeerNetworkConnFromC(salticidae.CPeerNetworkConn(_conn))
+	addr := conn.GetPeerAddr(false)
+	if !addr.IsNull() {
+		cert := ids.ShortID{}
+		if HandshakeNet.enableStaking {
+			cert = getMsgCert(_conn)
+		} else {
+			cert = toShortID(toIPDesc(addr))
+		}
+		if HandshakeNet.connections.ContainsID(cert) {
+			HandshakeNet.networkSizes.Add(cert, pMsg.Get(NetworkSize).(uint64))
+			networkSize, _ := HandshakeNet.NetworkSize()
+			HandshakeNet.networkSize.Set(float64(networkSize))
+		}
+	}
+	addr.Free()
+
+	// The peers are dialed by the connection manager if this node needs more
+	// outbound connections
 	for _, ip := range ips {
-		HandshakeNet.log.Verbo("Trying to adding peer %s", ip)
-		addr := salticidae.NewNetAddrFromIPPortString(ip.String(), false, &cErr)
-		if cErr.GetCode() == 0 && !HandshakeNet.myAddr.IsEq(addr) { // Make sure not to connect to myself
-			ip := toIPDesc(addr)
-
-			if !HandshakeNet.pending.ContainsIP(addr) && !HandshakeNet.connections.ContainsIP(addr) {
-				HandshakeNet.log.Debug("Adding peer %s", ip)
-				HandshakeNet.net.AddPeer(addr)
-			}
+		if !ip.IsIPv4() {
+			// Peer lists may carry IPv6 addresses, but salticidae can only
+			// connect to IPv4 addresses
+			HandshakeNet.log.Verbo("Not adding IPv6 peer %s", ip)
+			continue
 		}
-		addr.Free()
+		HandshakeNet.log.Verbo("Adding peer %s", ip)
+		HandshakeNet.connManager.Add(ip)
 	}
 }
 
@@ -553,17 +932,11 @@ func getCert(cert salticidae.X509) ids.ShortID {
 	defer certDS.Free()
 
 	certBytes := certDS.GetDataInPlace(certDS.Size()).Get()
-	certID, err := ids.ToShortID(hashing.PubkeyBytesToAddress(certBytes))
+	certID, err := staking.CertificateNodeID(certBytes)
 	HandshakeNet.log.AssertNoError(err)
 	return certID
 }
 
-// checkCompatibility Check to make sure that the peer and I speak the same language.
-func checkCompatibility(myVersion string, peerVersion string) bool {
-	// At the moment, we are all compatible.
-	return true
-}
-
 func toAddr(ip utils.IPDesc, autoFree bool) salticidae.NetAddr {
 	err := salticidae.NewError()
 	addr := salticidae.NewNetAddrFromIPPortString(ip.String(), autoFree, &err)
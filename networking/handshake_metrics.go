@@ -7,11 +7,25 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 
 	"github.com/ava-labs/gecko/utils/logging"
+	"github.com/ava-labs/gecko/utils/metrics"
 )
 
 type handshakeMetrics struct {
 	numPeers prometheus.Gauge
 
+	// Number of connected peers that this node dialed
+	numOutbound prometheus.Gauge
+
+	// Percent of the validators' stake held by this node and connected
+	// validators
+	connectedStake prometheus.Gauge
+
+	// Estimated number of nodes in the network
+	networkSize prometheus.Gauge
+
+	// Number of peers disconnected because their versions are incompatible
+	numIncompatible prometheus.Counter
+
 	numGetVersionSent, numGetVersionReceived,
 	numVersionSent, numVersionReceived,
 	numGetPeerlistSent, numGetPeerlistReceived,
@@ -19,86 +33,18 @@ type handshakeMetrics struct {
 }
 
 func (hm *handshakeMetrics) Initialize(log logging.Logger, registerer prometheus.Registerer) {
-	hm.numPeers = prometheus.NewGauge(
-		prometheus.GaugeOpts{
-			Namespace: "gecko",
-			Name:      "peers",
-			Help:      "Number of network peers",
-		})
-	hm.numGetVersionSent = prometheus.NewCounter(
-		prometheus.CounterOpts{
-			Namespace: "gecko",
-			Name:      "get_version_sent",
-			Help:      "Number of get_version messages sent",
-		})
-	hm.numGetVersionReceived = prometheus.NewCounter(
-		prometheus.CounterOpts{
-			Namespace: "gecko",
-			Name:      "get_version_received",
-			Help:      "Number of get_version messages received",
-		})
-	hm.numVersionSent = prometheus.NewCounter(
-		prometheus.CounterOpts{
-			Namespace: "gecko",
-			Name:      "version_sent",
-			Help:      "Number of version messages sent",
-		})
-	hm.numVersionReceived = prometheus.NewCounter(
-		prometheus.CounterOpts{
-			Namespace: "gecko",
-			Name:      "version_received",
-			Help:      "Number of version messages received",
-		})
-	hm.numGetPeerlistSent = prometheus.NewCounter(
-		prometheus.CounterOpts{
-			Namespace: "gecko",
-			Name:      "get_peerlist_sent",
-			Help:      "Number of get_peerlist messages sent",
-		})
-	hm.numGetPeerlistReceived = prometheus.NewCounter(
-		prometheus.CounterOpts{
-			Namespace: "gecko",
-			Name:      "get_peerlist_received",
-			Help:      "Number of get_peerlist messages received",
-		})
-	hm.numPeerlistSent = prometheus.NewCounter(
-		prometheus.CounterOpts{
-			Namespace: "gecko",
-			Name:      "peerlist_sent",
-			Help:      "Number of peerlist messages sent",
-		})
-	hm.numPeerlistReceived = prometheus.NewCounter(
-		prometheus.CounterOpts{
-			Namespace: "gecko",
-			Name:      "peerlist_received",
-			Help:      "Number of peerlist messages received",
-		})
-
-	if err := registerer.Register(hm.numPeers); err != nil {
-		log.Error("Failed to register peers statistics due to %s", err)
-	}
-	if err := registerer.Register(hm.numGetVersionSent); err != nil {
-		log.Error("Failed to register get_version_sent statistics due to %s", err)
-	}
-	if err := registerer.Register(hm.numGetVersionReceived); err != nil {
-		log.Error("Failed to register get_version_received statistics due to %s", err)
-	}
-	if err := registerer.Register(hm.numVersionSent); err != nil {
-		log.Error("Failed to register version_sent statistics due to %s", err)
-	}
-	if err := registerer.Register(hm.numVersionReceived); err != nil {
-		log.Error("Failed to register version_received statistics due to %s", err)
-	}
-	if err := registerer.Register(hm.numGetPeerlistSent); err != nil {
-		log.Error("Failed to register get_peerlist_sent statistics due to %s", err)
-	}
-	if err := registerer.Register(hm.numGetPeerlistReceived); err != nil {
-		log.Error("Failed to register get_peerlist_received statistics due to %s", err)
-	}
-	if err := registerer.Register(hm.numPeerlistSent); err != nil {
-		log.Error("Failed to register peerlist_sent statistics due to %s", err)
-	}
-	if err := registerer.Register(hm.numPeerlistReceived); err != nil {
-		log.Error("Failed to register peerlist_received statistics due to %s", err)
-	}
+	r := metrics.NewRegisterer(log, registerer, "network")
+	hm.numPeers = r.NewGauge("peers", "Number of network peers")
+	hm.numOutbound = r.NewGauge("outbound_peers", "Number of connected peers that this node dialed")
+	hm.connectedStake = r.NewGauge("connected_stake_percent", "Percent of the validators' stake held by connected validators, including this node")
+	hm.networkSize = r.NewGauge("size", "Estimated number of nodes in the network, based on the peers' peer lists")
+	hm.numIncompatible = r.NewCounter("incompatible_peers", "Number of peers disconnected because their versions are incompatible")
+	hm.numGetVersionSent = r.NewCounter("get_version_sent", "Number of get_version messages sent")
+	hm.numGetVersionReceived = r.NewCounter("get_version_received", "Number of get_version messages received")
+	hm.numVersionSent = r.NewCounter("version_sent", "Number of version messages sent")
+	hm.numVersionReceived = r.NewCounter("version_received", "Number of version messages received")
+	hm.numGetPeerlistSent = r.NewCounter("get_peerlist_sent", "Number of get_peerlist messages sent")
+	hm.numGetPeerlistReceived = r.NewCounter("get_peerlist_received", "Number of get_peerlist messages received")
+	hm.numPeerlistSent = r.NewCounter("peerlist_sent", "Number of peerlist messages sent")
+	hm.numPeerlistReceived = r.NewCounter("peerlist_received", "Number of peerlist messages received")
 }
@@ -0,0 +1,152 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package nat
+
+import (
+	"net"
+	"sync"
+	"time"
+
+	"github.com/ava-labs/go-ethereum/p2p/nat"
+
+	"github.com/ava-labs/gecko/utils/logging"
+	"github.com/ava-labs/gecko/utils/timer"
+)
+
+const (
+	// MappingLifetime is how long a port mapping lasts on the NAT device
+	// unless it's renewed. Mappings must be renewed more often than this.
+	MappingLifetime = 20 * time.Minute
+)
+
+// Traversal keeps ports mapped on the NAT device in front of this node, with
+// UPnP or NAT-PMP, so that peers can connect to the node from the internet. It
+// also keeps track of the node's public IP, which may change when the ISP
+// leases the NAT device a new one.
+type Traversal struct {
+	log        logging.Logger
+	device     nat.Interface
+	onIPChange func(net.IP)
+
+	lock     sync.Mutex
+	mappings []mapping
+	ip       net.IP
+
+	refresher *timer.Repeater
+}
+
+type mapping struct {
+	protocol string
+	port     uint16
+	name     string
+}
+
+// Initialize the traversal through [device], the NAT device in front of this
+// node. If [device] is nil, the node isn't behind a NAT device, and ports
+// aren't mapped. Mappings are renewed every [interval]. If [resolveIP], the
+// node's public IP is re-checked with [device] every [interval] too, and
+// [onIPChange] is called with the new IP when it changes. [ip] is the node's
+// public IP at start up.
+func (t *Traversal) Initialize(log logging.Logger, device nat.Interface, ip net.IP, interval time.Duration, resolveIP bool, onIPChange func(net.IP)) {
+	t.log = log
+	t.device = device
+	t.ip = ip
+	if resolveIP {
+		t.onIPChange = onIPChange
+	}
+
+	if device != nil {
+		t.refresher = timer.NewRepeater(t.refresh, interval)
+		go log.RecoverAndPanic(t.refresher.Dispatch)
+	}
+}
+
+// Map [port] on the NAT device to the same port on this node, for [protocol],
+// which is "TCP" or "UDP". [name] describes the mapping to the device's users.
+func (t *Traversal) Map(protocol string, port uint16, name string) {
+	if t.device == nil {
+		return
+	}
+
+	m := mapping{
+		protocol: protocol,
+		port:     port,
+		name:     name,
+	}
+
+	t.lock.Lock()
+	t.mappings = append(t.mappings, m)
+	t.lock.Unlock()
+
+	// The device may be slow to answer, so the first mapping isn't awaited
+	go t.log.RecoverAndPanic(func() { t.add(m) })
+}
+
+// PublicIP returns this node's public IP, as of the last check
+func (t *Traversal) PublicIP() net.IP {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	return t.ip
+}
+
+// Shutdown stops renewing the port mappings, and deletes them from the NAT
+// device
+func (t *Traversal) Shutdown() {
+	if t.device == nil {
+		return
+	}
+	t.refresher.Stop()
+
+	t.lock.Lock()
+	mappings := t.mappings
+	t.mappings = nil
+	t.lock.Unlock()
+
+	for _, m := range mappings {
+		if err := t.device.DeleteMapping(m.protocol, int(m.port), int(m.port)); err != nil {
+			t.log.Debug("Failed to delete the %s port mapping of %d from %s due to %s", m.protocol, m.port, t.device, err)
+		}
+	}
+}
+
+// refresh renews the port mappings and re-checks the public IP
+func (t *Traversal) refresh() {
+	t.lock.Lock()
+	mappings := append([]mapping(nil), t.mappings...)
+	t.lock.Unlock()
+
+	for _, m := range mappings {
+		t.add(m)
+	}
+
+	if t.onIPChange == nil {
+		return
+	}
+	ip, err := t.device.ExternalIP()
+	if err != nil {
+		t.log.Debug("Failed to resolve the public IP with %s due to %s", t.device, err)
+		return
+	}
+
+	t.lock.Lock()
+	changed := !ip.Equal(t.ip)
+	old := t.ip
+	t.ip = ip
+	t.lock.Unlock()
+
+	if changed {
+		t.log.Info("Public IP changed from %s to %s", old, ip)
+		t.onIPChange(ip)
+	}
+}
+
+// add the port mapping [m] to the NAT device, or renew it
+func (t *Traversal) add(m mapping) {
+	if err := t.device.AddMapping(m.protocol, int(m.port), int(m.port), m.name, MappingLifetime); err != nil {
+		t.log.Warn("Failed to map %s port %d with %s due to %s. Peers may not be able to connect to this node", m.protocol, m.port, t.device, err)
+		return
+	}
+	t.log.Debug("Mapped %s port %d with %s", m.protocol, m.port, t.device)
+}
@@ -0,0 +1,115 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package nat
+
+import (
+	"errors"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/gecko/utils/logging"
+)
+
+type testDevice struct {
+	lock     sync.Mutex
+	ip       net.IP
+	mappings map[int]int // port -> number of times it was mapped
+}
+
+func (d *testDevice) AddMapping(protocol string, extport, intport int, name string, lifetime time.Duration) error {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	if extport != intport {
+		return errors.New("unexpected port translation")
+	}
+	d.mappings[extport]++
+	return nil
+}
+
+func (d *testDevice) DeleteMapping(protocol string, extport, intport int) error {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	delete(d.mappings, extport)
+	return nil
+}
+
+func (d *testDevice) ExternalIP() (net.IP, error) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	return d.ip, nil
+}
+
+func (d *testDevice) String() string { return "test device" }
+
+func (d *testDevice) timesMapped(port int) int {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	return d.mappings[port]
+}
+
+func TestTraversalRenewsMappings(t *testing.T) {
+	device := &testDevice{
+		ip:       net.ParseIP("1.2.3.4"),
+		mappings: make(map[int]int),
+	}
+	tr := Traversal{}
+	tr.Initialize(logging.NoLog{}, device, device.ip, time.Hour, false, nil)
+
+	tr.lock.Lock()
+	tr.mappings = append(tr.mappings, mapping{protocol: "TCP", port: 9651})
+	tr.lock.Unlock()
+
+	tr.refresh()
+	tr.refresh()
+	if mapped := device.timesMapped(9651); mapped != 2 {
+		t.Fatalf("Expected the port to be mapped twice but it was mapped %d times", mapped)
+	}
+
+	tr.Shutdown()
+	if mapped := device.timesMapped(9651); mapped != 0 {
+		t.Fatalf("Should have deleted the mapping on shutdown")
+	}
+}
+
+func TestTraversalResolvesIP(t *testing.T) {
+	device := &testDevice{
+		ip:       net.ParseIP("1.2.3.4"),
+		mappings: make(map[int]int),
+	}
+	changes := []net.IP(nil)
+	tr := Traversal{}
+	tr.Initialize(logging.NoLog{}, device, device.ip, time.Hour, true, func(ip net.IP) { changes = append(changes, ip) })
+	defer tr.Shutdown()
+
+	tr.refresh()
+	if len(changes) != 0 {
+		t.Fatalf("Shouldn't have reported a change while the IP is the same")
+	}
+
+	newIP := net.ParseIP("5.6.7.8")
+	device.lock.Lock()
+	device.ip = newIP
+	device.lock.Unlock()
+
+	tr.refresh()
+	if len(changes) != 1 || !changes[0].Equal(newIP) {
+		t.Fatalf("Expected the IP to change to %s but got %v", newIP, changes)
+	}
+	if ip := tr.PublicIP(); !ip.Equal(newIP) {
+		t.Fatalf("Expected the public IP to be %s but got %s", newIP, ip)
+	}
+}
+
+func TestTraversalWithoutDevice(t *testing.T) {
+	tr := Traversal{}
+	tr.Initialize(logging.NoLog{}, nil, net.ParseIP("1.2.3.4"), time.Hour, true, nil)
+	tr.Map("TCP", 9651, "test")
+	tr.Shutdown()
+}
@@ -8,16 +8,23 @@ package networking
 // void acceptedFrontier(msg_t *, msgnetwork_conn_t *, void *);
 // void getAccepted(msg_t *, msgnetwork_conn_t *, void *);
 // void accepted(msg_t *, msgnetwork_conn_t *, void *);
+// void getStateSummary(msg_t *, msgnetwork_conn_t *, void *);
+// void stateSummary(msg_t *, msgnetwork_conn_t *, void *);
+// void gossipFrontier(msg_t *, msgnetwork_conn_t *, void *);
 // void get(msg_t *, msgnetwork_conn_t *, void *);
 // void put(msg_t *, msgnetwork_conn_t *, void *);
 // void pushQuery(msg_t *, msgnetwork_conn_t *, void *);
 // void pullQuery(msg_t *, msgnetwork_conn_t *, void *);
 // void chits(msg_t *, msgnetwork_conn_t *, void *);
+// void maintenance(msg_t *, msgnetwork_conn_t *, void *);
 import "C"
 
 import (
 	"errors"
 	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
 	"unsafe"
 
 	"github.com/prometheus/client_golang/prometheus"
@@ -25,11 +32,16 @@ import (
 	"github.com/ava-labs/salticidae-go"
 
 	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow/networking"
 	"github.com/ava-labs/gecko/snow/networking/router"
+	"github.com/ava-labs/gecko/snow/networking/sender"
 	"github.com/ava-labs/gecko/snow/validators"
+	"github.com/ava-labs/gecko/utils/compression"
 	"github.com/ava-labs/gecko/utils/formatting"
 	"github.com/ava-labs/gecko/utils/logging"
+	"github.com/ava-labs/gecko/utils/random"
 	"github.com/ava-labs/gecko/utils/timer"
+	"github.com/ava-labs/gecko/utils/wrappers"
 )
 
 var (
@@ -37,8 +49,42 @@ var (
 	VotingNet = Voting{}
 )
 
+const (
+	// maxMaintenanceDuration is the longest a peer's announced maintenance is
+	// respected for
+	maxMaintenanceDuration = 30 * time.Minute
+
+	// maxDecompressedSize is the largest a compressed message's payload may
+	// decompress to
+	maxDecompressedSize = 1 << 25
+
+	// failureLogInterval is the least time between logs of the same
+	// high frequency failure, such as a peer's message being dropped
+	failureLogInterval = time.Second
+
+	// FrontierGossipSize is the most peers a chain's accepted frontier is
+	// gossiped to each time
+	FrontierGossipSize = 20
+	// FrontierGossipMinSize is the fewest peers a chain's accepted frontier is
+	// gossiped to each time, if enough peers are connected
+	FrontierGossipMinSize = 4
+)
+
 var (
 	errConnectionDropped = errors.New("connection dropped before receiving message")
+	errBannedPeer        = errors.New("message sent by a banned peer")
+	errReplayedMessage   = errors.New("message was already received")
+	errRateLimited       = errors.New("message exceeds the peer's rate limits")
+
+	// responses are the messages sent in response to this node's requests.
+	// They're handled even if they exceed the sender's rate limits, since this
+	// node asked for them.
+	responses = map[salticidae.Opcode]bool{
+		AcceptedFrontier: true,
+		Accepted:         true,
+		StateSummary:     true,
+		Chits:            true,
+	}
 )
 
 // Voting implements the SenderExternal interface with a c++ library.
@@ -52,10 +98,50 @@ type Voting struct {
 
 	router   router.Router
 	executor timer.Executor
+
+	// Limits the bandwidth spent gossiping accepted containers
+	gossipBudget *sender.GossipBudget
+	// Prevents gossiping the same container to a peer repeatedly
+	gossipCache *sender.GossipCache
+
+	// The compression this node accepts. Messages are compressed with it if
+	// they're at least compressionThreshold bytes long.
+	compression          compression.Type
+	compressionThreshold int
+	// The compression each connected peer accepts
+	peerCompression *networking.PeerCompression
+
+	// If sequenced, peers prefix the messages they send this node with
+	// sequence numbers, and replays are dropped. The last sequence number this
+	// node sent is sequence. Accessed atomically.
+	sequenced bool
+	sequence  uint64
+	replays   *networking.ReplayGuard
+
+	// Peers that send invalid messages are penalized, and messages from
+	// banned peers are dropped
+	reputation *networking.Reputation
+
+	// Limits the messages each peer may send this node
+	inboundLimiter *networking.InboundLimiter
+
+	// Sample the logs of messages that are dropped and of skipped gossip, so
+	// that a flood of them can't make logging the bottleneck
+	dropLogs, gossipSkipLogs logging.Sampler
+
+	clock timer.Clock
+
+	// maintenanceLock protects maintenanceEnd and peerMaintenanceEnd
+	maintenanceLock sync.Mutex
+	// maintenanceEnd is when the maintenance this node announced ends
+	maintenanceEnd time.Time
+	// peerMaintenanceEnd maps a peer's ID to when the maintenance it announced
+	// ends. Queries aren't sent to a peer in maintenance.
+	peerMaintenanceEnd map[[20]byte]time.Time
 }
 
 // Initialize to the c networking library. Should only be called once ever.
-func (s *Voting) Initialize(log logging.Logger, vdrs validators.Set, peerNet salticidae.PeerNetwork, conns Connections, router router.Router, registerer prometheus.Registerer) {
+func (s *Voting) Initialize(log logging.Logger, vdrs validators.Set, peerNet salticidae.PeerNetwork, conns Connections, router router.Router, gossipBudget *sender.GossipBudget, gossipCache *sender.GossipCache, accepted compression.Type, compressionThreshold int, peerCompression *networking.PeerCompression, sequenced bool, replays *networking.ReplayGuard, reputation *networking.Reputation, inboundLimiter *networking.InboundLimiter, registerer prometheus.Registerer) {
 	log.AssertTrue(s.net == nil, "Should only register network handlers once")
 	log.AssertTrue(s.conns == nil, "Should only set connections once")
 	log.AssertTrue(s.router == nil, "Should only set the router once")
@@ -65,6 +151,18 @@ func (s *Voting) Initialize(log logging.Logger, vdrs validators.Set, peerNet sal
 	s.net = peerNet
 	s.conns = conns
 	s.router = router
+	s.gossipBudget = gossipBudget
+	s.gossipCache = gossipCache
+	s.compression = accepted
+	s.compressionThreshold = compressionThreshold
+	s.peerCompression = peerCompression
+	s.sequenced = sequenced
+	s.replays = replays
+	s.reputation = reputation
+	s.inboundLimiter = inboundLimiter
+	s.dropLogs.Interval = failureLogInterval
+	s.gossipSkipLogs.Interval = failureLogInterval
+	s.peerMaintenanceEnd = make(map[[20]byte]time.Time)
 
 	s.votingMetrics.Initialize(log, registerer)
 
@@ -74,11 +172,15 @@ func (s *Voting) Initialize(log logging.Logger, vdrs validators.Set, peerNet sal
 	net.RegHandler(AcceptedFrontier, salticidae.MsgNetworkMsgCallback(C.acceptedFrontier), nil)
 	net.RegHandler(GetAccepted, salticidae.MsgNetworkMsgCallback(C.getAccepted), nil)
 	net.RegHandler(Accepted, salticidae.MsgNetworkMsgCallback(C.accepted), nil)
+	net.RegHandler(GetStateSummary, salticidae.MsgNetworkMsgCallback(C.getStateSummary), nil)
+	net.RegHandler(StateSummary, salticidae.MsgNetworkMsgCallback(C.stateSummary), nil)
+	net.RegHandler(GossipFrontier, salticidae.MsgNetworkMsgCallback(C.gossipFrontier), nil)
 	net.RegHandler(Get, salticidae.MsgNetworkMsgCallback(C.get), nil)
 	net.RegHandler(Put, salticidae.MsgNetworkMsgCallback(C.put), nil)
 	net.RegHandler(PushQuery, salticidae.MsgNetworkMsgCallback(C.pushQuery), nil)
 	net.RegHandler(PullQuery, salticidae.MsgNetworkMsgCallback(C.pullQuery), nil)
 	net.RegHandler(Chits, salticidae.MsgNetworkMsgCallback(C.chits), nil)
+	net.RegHandler(Maintenance, salticidae.MsgNetworkMsgCallback(C.maintenance), nil)
 
 	s.executor.Initialize()
 	go log.RecoverAndPanic(s.executor.Dispatch)
@@ -87,15 +189,102 @@ func (s *Voting) Initialize(log logging.Logger, vdrs validators.Set, peerNet sal
 // Shutdown threads
 func (s *Voting) Shutdown() { s.executor.Stop() }
 
-// Accept is called after every consensus decision
+// EnterMaintenance announces to every connected peer that this node will be
+// unavailable for [duration]. The peers stop querying this node, rather than
+// waiting for their queries to time out, and queries that are received anyway
+// are dropped until the maintenance ends.
+func (s *Voting) EnterMaintenance(duration time.Duration) error {
+	// The announcement is rounded up to the nearest second
+	seconds := uint64((duration + time.Second - 1) / time.Second)
+
+	build := Builder{}
+	msg, err := build.Maintenance(seconds)
+	if err != nil {
+		return err
+	}
+
+	s.maintenanceLock.Lock()
+	s.maintenanceEnd = s.clock.Time().Add(duration)
+	s.maintenanceLock.Unlock()
+
+	addrs, _ := s.conns.RawConns()
+	s.log.Info("Entering maintenance for %s. Announcing to %d peers", duration, len(addrs))
+	s.send(msg, addrs...)
+	s.numMaintenanceSent.Add(float64(len(addrs)))
+	return nil
+}
+
+// inMaintenance returns true if this node is in maintenance
+func (s *Voting) inMaintenance() bool {
+	s.maintenanceLock.Lock()
+	defer s.maintenanceLock.Unlock()
+
+	return s.clock.Time().Before(s.maintenanceEnd)
+}
+
+// peerInMaintenance returns true if [validatorID] announced maintenance that
+// hasn't ended yet
+func (s *Voting) peerInMaintenance(validatorID ids.ShortID) bool {
+	s.maintenanceLock.Lock()
+	defer s.maintenanceLock.Unlock()
+
+	key := validatorID.Key()
+	end, exists := s.peerMaintenanceEnd[key]
+	if !exists {
+		return false
+	}
+	if !s.clock.Time().Before(end) {
+		delete(s.peerMaintenanceEnd, key)
+		return false
+	}
+	return true
+}
+
+// peerEnteredMaintenance records that [validatorID] will be unavailable for
+// [duration]
+func (s *Voting) peerEnteredMaintenance(validatorID ids.ShortID, duration time.Duration) {
+	if duration > maxMaintenanceDuration {
+		duration = maxMaintenanceDuration
+	}
+
+	s.maintenanceLock.Lock()
+	defer s.maintenanceLock.Unlock()
+
+	s.peerMaintenanceEnd[validatorID.Key()] = s.clock.Time().Add(duration)
+}
+
+// Accept is called after every consensus decision. The container is gossiped
+// to the connected non-validators whose gossip budget allows it, unless it was
+// recently gossiped to them.
 func (s *Voting) Accept(chainID, containerID ids.ID, container []byte) error {
 	addrs := []salticidae.NetAddr(nil)
+	skipped := 0
+	duplicates := 0
 
 	allAddrs, allIDs := s.conns.RawConns()
 	for i, id := range allIDs {
-		if !s.vdrs.Contains(id) {
-			addrs = append(addrs, allAddrs[i])
+		if s.vdrs.Contains(id) {
+			continue
+		}
+		if s.gossipCache.RecentlySent(id, containerID) {
+			duplicates++
+			continue
 		}
+		if !s.gossipBudget.Spend(id, uint64(len(container))) {
+			skipped++
+			continue
+		}
+		s.gossipCache.Sent(id, containerID)
+		addrs = append(addrs, allAddrs[i])
+	}
+	if skipped > 0 {
+		s.gossipSkipLogs.Log(s.log, logging.Debug, "Skipped gossiping container %s to %d peers due to the gossip budget", containerID, skipped)
+		s.numPutGossipSkipped.Add(float64(skipped))
+	}
+	if duplicates > 0 {
+		s.log.Verbo("Skipped gossiping container %s to %d peers that were recently sent it", containerID, duplicates)
+		s.numPutGossipDuplicates.Add(float64(duplicates))
+		s.numPutGossipBytesSaved.Add(float64(duplicates * len(container)))
 	}
 
 	build := Builder{}
@@ -253,8 +442,111 @@ func (s *Voting) Accepted(validatorID ids.ShortID, chainID ids.ID, requestID uin
 	s.numAcceptedSent.Inc()
 }
 
+// GetStateSummary implements the Sender interface.
+func (s *Voting) GetStateSummary(validatorIDs ids.ShortSet, chainID ids.ID, requestID uint32) {
+	addrs := []salticidae.NetAddr(nil)
+	validatorIDList := validatorIDs.List()
+	for _, validatorID := range validatorIDList {
+		vID := validatorID
+		if addr, exists := s.conns.GetIP(vID); exists {
+			addrs = append(addrs, addr)
+			s.log.Verbo("Sending a GetStateSummary to %s", toIPDesc(addr))
+		} else {
+			s.log.Debug("Attempted to send a GetStateSummary message to a disconnected validator: %s", vID)
+			s.executor.Add(func() { s.router.GetStateSummaryFailed(vID, chainID, requestID) })
+		}
+	}
+
+	build := Builder{}
+	msg, err := build.GetStateSummary(chainID, requestID)
+	s.log.AssertNoError(err)
+
+	s.log.Verbo("Sending a GetStateSummary message."+
+		"\nNumber of Validators: %d"+
+		"\nChain: %s"+
+		"\nRequest ID: %d",
+		len(addrs),
+		chainID,
+		requestID,
+	)
+	s.send(msg, addrs...)
+	s.numGetStateSummarySent.Add(float64(len(addrs)))
+}
+
+// StateSummary implements the Sender interface.
+func (s *Voting) StateSummary(validatorID ids.ShortID, chainID ids.ID, requestID uint32, summary []byte) {
+	addr, exists := s.conns.GetIP(validatorID)
+	if !exists {
+		s.log.Debug("Attempted to send a StateSummary message to a disconnected validator: %s", validatorID)
+		return // Validator is not connected
+	}
+
+	build := Builder{}
+	msg, err := build.StateSummary(chainID, requestID, summary)
+	if err != nil {
+		s.log.Error("Attempted to pack too large of a StateSummary message.\nSummary length: %d", len(summary))
+		return // Packing message failed
+	}
+
+	s.log.Verbo("Sending a StateSummary message."+
+		"\nValidator: %s"+
+		"\nDestination: %s"+
+		"\nChain: %s"+
+		"\nRequest ID: %d"+
+		"\nSummary:\n%s",
+		validatorID,
+		toIPDesc(addr),
+		chainID,
+		requestID,
+		formatting.DumpBytes{Bytes: summary},
+	)
+	s.send(msg, addr)
+	s.numStateSummarySent.Inc()
+}
+
+// GossipFrontier implements the Sender interface. The frontier is sent to a
+// random sample of the connected peers, so that peers that fell behind learn
+// of the containers they're missing.
+func (s *Voting) GossipFrontier(chainID ids.ID, containerIDs ids.Set) {
+	allAddrs, _ := s.conns.RawConns()
+	numToGossip := networking.GossipFanOut(uint64(len(allAddrs)), FrontierGossipMinSize, FrontierGossipSize)
+	if len(allAddrs) < numToGossip {
+		numToGossip = len(allAddrs)
+	}
+
+	addrs := make([]salticidae.NetAddr, numToGossip)
+	sampler := random.Uniform{N: len(allAddrs)}
+	for i := range addrs {
+		addrs[i] = allAddrs[sampler.Sample()]
+	}
+
+	build := Builder{}
+	msg, err := build.GossipFrontier(chainID, containerIDs)
+	if err != nil {
+		s.log.Error("Attempted to pack too large of a GossipFrontier message.\nNumber of containerIDs: %d", containerIDs.Len())
+		return // Packing message failed
+	}
+
+	s.log.Verbo("Sending a GossipFrontier message."+
+		"\nNumber of Peers: %d"+
+		"\nChain: %s"+
+		"\nContainer IDs: %s",
+		len(addrs),
+		chainID,
+		containerIDs,
+	)
+	s.send(msg, addrs...)
+	s.numGossipFrontierSent.Add(float64(len(addrs)))
+}
+
 // Get implements the Sender interface.
 func (s *Voting) Get(validatorID ids.ShortID, chainID ids.ID, requestID uint32, containerID ids.ID) {
+	if s.peerInMaintenance(validatorID) {
+		s.log.Debug("Attempted to send a Get message to a validator in maintenance: %s", validatorID)
+		s.executor.Add(func() { s.router.GetFailed(validatorID, chainID, requestID, containerID) })
+		return
+	}
+
 	addr, exists := s.conns.GetIP(validatorID)
 	if !exists {
 		s.log.Debug("Attempted to send a Get message to a disconnected validator: %s", validatorID)
@@ -321,6 +613,11 @@ func (s *Voting) PushQuery(validatorIDs ids.ShortSet, chainID ids.ID, requestID
 	validatorIDList := validatorIDs.List()
 	for _, validatorID := range validatorIDList {
 		vID := validatorID
+		if s.peerInMaintenance(vID) {
+			s.log.Debug("Attempted to send a query to a validator in maintenance: %s", vID)
+			s.executor.Add(func() { s.router.QueryFailed(vID, chainID, requestID) })
+			continue
+		}
 		if addr, exists := s.conns.GetIP(vID); exists {
 			addrs = append(addrs, addr)
 			s.log.Verbo("Sending a PushQuery to %s", toIPDesc(addr))
@@ -364,6 +661,11 @@ func (s *Voting) PullQuery(validatorIDs ids.ShortSet, chainID ids.ID, requestID
 	validatorIDList := validatorIDs.List()
 	for _, validatorID := range validatorIDList {
 		vID := validatorID
+		if s.peerInMaintenance(vID) {
+			s.log.Debug("Attempted to send a query to a validator in maintenance: %s", vID)
+			s.executor.Add(func() { s.router.QueryFailed(vID, chainID, requestID) })
+			continue
+		}
 		if addr, exists := s.conns.GetIP(vID); exists {
 			addrs = append(addrs, addr)
 			s.log.Verbo("Sending a PushQuery to %s", toIPDesc(addr))
@@ -423,6 +725,66 @@ func (s *Voting) Chits(validatorID ids.ShortID, chainID ids.ID, requestID uint32
 }
 
 func (s *Voting) send(msg Msg, addrs ...salticidae.NetAddr) {
+	// Peers that accept compression expect every message to be framed with
+	// the compression applied to it, and peers that track sequence numbers
+	// expect every message to be prefixed with one
+	framings := make(map[Framing][]salticidae.NetAddr)
+	for _, addr := range addrs {
+		f := Framing{}
+		if id, ok := s.conns.GetID(addr); ok {
+			if s.peerCompression.Accepted(id) != compression.None {
+				f.Compressed = true
+				f.Compression = s.compression
+				f.Threshold = s.compressionThreshold
+			}
+			f.Sequenced = s.replays.Sequenced(id)
+		}
+		framings[f] = append(framings[f], addr)
+	}
+
+	// Every peer is sent the message with the same sequence number, so that
+	// it's only ever used once
+	sequence := atomic.AddUint64(&s.sequence, 1)
+	codec := Codec{}
+	for f, framed := range framings {
+		if f == (Framing{}) {
+			continue
+		}
+		f.Sequence = sequence
+
+		framedMsg, err := codec.Frame(msg, f)
+		if err != nil && f.Compressed {
+			s.log.Warn("Failed to compress %s message due to %s", msg.Op(), err)
+			f.Compression = compression.None
+			framedMsg, err = codec.Frame(msg, f)
+		}
+		if err != nil {
+			s.log.Error("Failed to frame %s message due to %s", msg.Op(), err)
+			continue
+		}
+
+		if f.Compressed {
+			size := msg.DataStream().Size()
+			// The framed message is prefixed with the compression applied,
+			// and its sequence number
+			compressedSize := framedMsg.DataStream().Size() - 1
+			if f.Sequenced {
+				compressedSize -= wrappers.LongLen
+			}
+			if compressedSize < size {
+				s.numCompressedSent.Add(float64(len(framed)))
+				s.numCompressionBytesSaved.Add(float64((size - compressedSize) * len(framed)))
+				s.compressionRatio.Observe(float64(compressedSize) / float64(size))
+			}
+		}
+		s.sendMsg(framedMsg, framed...)
+	}
+
+	s.sendMsg(msg, framings[Framing{}]...)
+}
+
+// sendMsg sends [msg] to [addrs] as is
+func (s *Voting) sendMsg(msg Msg, addrs ...salticidae.NetAddr) {
 	ds := msg.DataStream()
 	defer ds.Free()
 	ba := salticidae.NewByteArrayMovedFromDataStream(ds, false)
@@ -447,7 +809,7 @@ func getAcceptedFrontier(_msg *C.struct_msg_t, _conn *C.struct_msgnetwork_conn_t
 
 	validatorID, chainID, requestID, _, err := VotingNet.sanitize(_msg, _conn, GetAcceptedFrontier)
 	if err != nil {
-		VotingNet.log.Error("Failed to sanitize message due to: %s", err)
+		VotingNet.dropLogs.Log(VotingNet.log, logging.Error, "Failed to sanitize message due to: %s", err)
 		return
 	}
 
@@ -461,7 +823,7 @@ func acceptedFrontier(_msg *C.struct_msg_t, _conn *C.struct_msgnetwork_conn_t, _
 
 	validatorID, chainID, requestID, msg, err := VotingNet.sanitize(_msg, _conn, AcceptedFrontier)
 	if err != nil {
-		VotingNet.log.Error("Failed to sanitize message due to: %s", err)
+		VotingNet.dropLogs.Log(VotingNet.log, logging.Error, "Failed to sanitize message due to: %s", err)
 		return
 	}
 
@@ -470,6 +832,7 @@ func acceptedFrontier(_msg *C.struct_msg_t, _conn *C.struct_msgnetwork_conn_t, _
 		containerID, err := ids.ToID(containerIDBytes)
 		if err != nil {
 			VotingNet.log.Warn("Error parsing ContainerID: %v", containerIDBytes)
+			VotingNet.reputation.Penalize(validatorID, networking.InvalidMessagePenalty)
 			return
 		}
 		containerIDs.Add(containerID)
@@ -485,7 +848,7 @@ func getAccepted(_msg *C.struct_msg_t, _conn *C.struct_msgnetwork_conn_t, _ unsa
 
 	validatorID, chainID, requestID, msg, err := VotingNet.sanitize(_msg, _conn, GetAccepted)
 	if err != nil {
-		VotingNet.log.Error("Failed to sanitize message due to: %s", err)
+		VotingNet.dropLogs.Log(VotingNet.log, logging.Error, "Failed to sanitize message due to: %s", err)
 		return
 	}
 
@@ -494,6 +857,7 @@ func getAccepted(_msg *C.struct_msg_t, _conn *C.struct_msgnetwork_conn_t, _ unsa
 		containerID, err := ids.ToID(containerIDBytes)
 		if err != nil {
 			VotingNet.log.Warn("Error parsing ContainerID: %v", containerIDBytes)
+			VotingNet.reputation.Penalize(validatorID, networking.InvalidMessagePenalty)
 			return
 		}
 		containerIDs.Add(containerID)
@@ -509,7 +873,7 @@ func accepted(_msg *C.struct_msg_t, _conn *C.struct_msgnetwork_conn_t, _ unsafe.
 
 	validatorID, chainID, requestID, msg, err := VotingNet.sanitize(_msg, _conn, Accepted)
 	if err != nil {
-		VotingNet.log.Error("Failed to sanitize message due to: %s", err)
+		VotingNet.dropLogs.Log(VotingNet.log, logging.Error, "Failed to sanitize message due to: %s", err)
 		return
 	}
 
@@ -518,6 +882,7 @@ func accepted(_msg *C.struct_msg_t, _conn *C.struct_msgnetwork_conn_t, _ unsafe.
 		containerID, err := ids.ToID(containerIDBytes)
 		if err != nil {
 			VotingNet.log.Warn("Error parsing ContainerID: %v", containerIDBytes)
+			VotingNet.reputation.Penalize(validatorID, networking.InvalidMessagePenalty)
 			return
 		}
 		containerIDs.Add(containerID)
@@ -526,6 +891,61 @@ func accepted(_msg *C.struct_msg_t, _conn *C.struct_msgnetwork_conn_t, _ unsafe.
 	VotingNet.router.Accepted(validatorID, chainID, requestID, containerIDs)
 }
 
+// getStateSummary handles the recept of a getStateSummary message
+//export getStateSummary
+func getStateSummary(_msg *C.struct_msg_t, _conn *C.struct_msgnetwork_conn_t, _ unsafe.Pointer) {
+	VotingNet.numGetStateSummaryReceived.Inc()
+
+	validatorID, chainID, requestID, _, err := VotingNet.sanitize(_msg, _conn, GetStateSummary)
+	if err != nil {
+		VotingNet.dropLogs.Log(VotingNet.log, logging.Error, "Failed to sanitize message due to: %s", err)
+		return
+	}
+
+	VotingNet.router.GetStateSummary(validatorID, chainID, requestID)
+}
+
+// stateSummary handles the recept of a stateSummary message
+//export stateSummary
+func stateSummary(_msg *C.struct_msg_t, _conn *C.struct_msgnetwork_conn_t, _ unsafe.Pointer) {
+	VotingNet.numStateSummaryReceived.Inc()
+
+	validatorID, chainID, requestID, msg, err := VotingNet.sanitize(_msg, _conn, StateSummary)
+	if err != nil {
+		VotingNet.dropLogs.Log(VotingNet.log, logging.Error, "Failed to sanitize message due to: %s", err)
+		return
+	}
+
+	summary := msg.Get(ContainerBytes).([]byte)
+
+	VotingNet.router.StateSummary(validatorID, chainID, requestID, summary)
+}
+
+// gossipFrontier handles the recept of a gossipFrontier message
+//export gossipFrontier
+func gossipFrontier(_msg *C.struct_msg_t, _conn *C.struct_msgnetwork_conn_t, _ unsafe.Pointer) {
+	VotingNet.numGossipFrontierReceived.Inc()
+
+	validatorID, chainID, _, msg, err := VotingNet.sanitize(_msg, _conn, GossipFrontier)
+	if err != nil {
+		VotingNet.dropLogs.Log(VotingNet.log, logging.Error, "Failed to sanitize message due to: %s", err)
+		return
+	}
+
+	containerIDs := ids.Set{}
+	for _, containerIDBytes := range msg.Get(ContainerIDs).([][]byte) {
+		containerID, err := ids.ToID(containerIDBytes)
+		if err != nil {
+			VotingNet.log.Warn("Error parsing ContainerID: %v", containerIDBytes)
+			VotingNet.reputation.Penalize(validatorID, networking.InvalidMessagePenalty)
+			return
+		}
+		containerIDs.Add(containerID)
+	}
+
+	VotingNet.router.GossipFrontier(validatorID, chainID, containerIDs)
+}
+
 // get handles the recept of a get container message for a chain
 //export get
 func get(_msg *C.struct_msg_t, _conn *C.struct_msgnetwork_conn_t, _ unsafe.Pointer) {
@@ -533,7 +953,7 @@ func get(_msg *C.struct_msg_t, _conn *C.struct_msgnetwork_conn_t, _ unsafe.Point
 
 	validatorID, chainID, requestID, msg, err := VotingNet.sanitize(_msg, _conn, Get)
 	if err != nil {
-		VotingNet.log.Error("Failed to sanitize message due to: %s", err)
+		VotingNet.dropLogs.Log(VotingNet.log, logging.Error, "Failed to sanitize message due to: %s", err)
 		return
 	}
 
@@ -549,7 +969,7 @@ func put(_msg *C.struct_msg_t, _conn *C.struct_msgnetwork_conn_t, _ unsafe.Point
 
 	validatorID, chainID, requestID, msg, err := VotingNet.sanitize(_msg, _conn, Put)
 	if err != nil {
-		VotingNet.log.Error("Failed to sanitize message due to: %s", err)
+		VotingNet.dropLogs.Log(VotingNet.log, logging.Error, "Failed to sanitize message due to: %s", err)
 		return
 	}
 
@@ -567,7 +987,13 @@ func pushQuery(_msg *C.struct_msg_t, _conn *C.struct_msgnetwork_conn_t, _ unsafe
 
 	validatorID, chainID, requestID, msg, err := VotingNet.sanitize(_msg, _conn, PushQuery)
 	if err != nil {
-		VotingNet.log.Error("Failed to sanitize message due to: %s", err)
+		VotingNet.dropLogs.Log(VotingNet.log, logging.Error, "Failed to sanitize message due to: %s", err)
+		return
+	}
+
+	if VotingNet.inMaintenance() {
+		VotingNet.log.Debug("Dropping a PushQuery from %s while in maintenance", validatorID)
+		VotingNet.numQueryDroppedInMaintenance.Inc()
 		return
 	}
 
@@ -585,7 +1011,13 @@ func pullQuery(_msg *C.struct_msg_t, _conn *C.struct_msgnetwork_conn_t, _ unsafe
 
 	validatorID, chainID, requestID, msg, err := VotingNet.sanitize(_msg, _conn, PullQuery)
 	if err != nil {
-		VotingNet.log.Error("Failed to sanitize message due to: %s", err)
+		VotingNet.dropLogs.Log(VotingNet.log, logging.Error, "Failed to sanitize message due to: %s", err)
+		return
+	}
+
+	if VotingNet.inMaintenance() {
+		VotingNet.log.Debug("Dropping a PullQuery from %s while in maintenance", validatorID)
+		VotingNet.numQueryDroppedInMaintenance.Inc()
 		return
 	}
 
@@ -601,7 +1033,7 @@ func chits(_msg *C.struct_msg_t, _conn *C.struct_msgnetwork_conn_t, _ unsafe.Poi
 
 	validatorID, chainID, requestID, msg, err := VotingNet.sanitize(_msg, _conn, Chits)
 	if err != nil {
-		VotingNet.log.Error("Failed to sanitize message due to: %s", err)
+		VotingNet.dropLogs.Log(VotingNet.log, logging.Error, "Failed to sanitize message due to: %s", err)
 		return
 	}
 
@@ -610,6 +1042,7 @@ func chits(_msg *C.struct_msg_t, _conn *C.struct_msgnetwork_conn_t, _ unsafe.Poi
 		vote, err := ids.ToID(voteBytes)
 		if err != nil {
 			VotingNet.log.Warn("Error parsing chit: %v", voteBytes)
+			VotingNet.reputation.Penalize(validatorID, networking.InvalidMessagePenalty)
 			return
 		}
 		votes.Add(vote)
@@ -618,24 +1051,83 @@ func chits(_msg *C.struct_msg_t, _conn *C.struct_msgnetwork_conn_t, _ unsafe.Poi
 	VotingNet.router.Chits(validatorID, chainID, requestID, votes)
 }
 
-func (s *Voting) sanitize(_msg *C.struct_msg_t, _conn *C.struct_msgnetwork_conn_t, op salticidae.Opcode) (ids.ShortID, ids.ID, uint32, Msg, error) {
+// maintenance handles the receipt of a maintenance announcement
+//export maintenance
+func maintenance(_msg *C.struct_msg_t, _conn *C.struct_msgnetwork_conn_t, _ unsafe.Pointer) {
+	VotingNet.numMaintenanceReceived.Inc()
+
+	validatorID, err := VotingNet.sender(_conn)
+	if err != nil {
+		VotingNet.dropLogs.Log(VotingNet.log, logging.Error, "Failed to sanitize message due to: %s", err)
+		return
+	}
+	if VotingNet.reputation.Banned(validatorID) {
+		VotingNet.log.Debug("Dropping Maintenance message from banned peer %s", validatorID)
+		return
+	}
+
+	msg := salticidae.MsgFromC(salticidae.CMsg(_msg))
+	ds := msg.GetPayloadByMove()
+	if err := VotingNet.limit(validatorID, Maintenance, ds); err != nil {
+		VotingNet.dropLogs.Log(VotingNet.log, logging.Debug, "Dropping Maintenance message from %s due to %s", validatorID, err)
+		return
+	}
+	pMsg, err := VotingNet.parse(validatorID, Maintenance, ds)
+	if err == errReplayedMessage {
+		VotingNet.log.Debug("Dropping replayed Maintenance message from %s", validatorID)
+		return
+	}
+	if err != nil {
+		VotingNet.dropLogs.Log(VotingNet.log, logging.Warn, "Failed to parse Maintenance message due to %s", err)
+		VotingNet.reputation.Penalize(validatorID, networking.InvalidMessagePenalty)
+		return
+	}
+
+	duration := time.Duration(pMsg.Get(Duration).(uint64)) * time.Second
+	VotingNet.log.Info("Validator %s announced maintenance for %s", validatorID, duration)
+	VotingNet.peerEnteredMaintenance(validatorID, duration)
+}
+
+// sender returns the ID of the peer on the other end of [_conn]
+func (s *Voting) sender(_conn *C.struct_msgnetwork_conn_t) (ids.ShortID, error) {
 	conn := salticidae.PeerNetworkConnFromC(salticidae.CPeerNetworkConn((*C.peernetwork_conn_t)(_conn)))
 	addr := conn.GetPeerAddr(false)
 	defer addr.Free()
 	if addr.IsNull() {
-		return ids.ShortID{}, ids.ID{}, 0, nil, errConnectionDropped
+		return ids.ShortID{}, errConnectionDropped
 	}
 	s.log.Verbo("Receiving message from %s", toIPDesc(addr))
 
 	validatorID, exists := s.conns.GetID(addr)
 	if !exists {
-		return ids.ShortID{}, ids.ID{}, 0, nil, fmt.Errorf("message received from an un-registered source: %s", toIPDesc(addr))
+		return ids.ShortID{}, fmt.Errorf("message received from an un-registered source: %s", toIPDesc(addr))
+	}
+	return validatorID, nil
+}
+
+func (s *Voting) sanitize(_msg *C.struct_msg_t, _conn *C.struct_msgnetwork_conn_t, op salticidae.Opcode) (ids.ShortID, ids.ID, uint32, Msg, error) {
+	validatorID, err := s.sender(_conn)
+	if err != nil {
+		return ids.ShortID{}, ids.ID{}, 0, nil, err
+	}
+
+	if s.reputation.Banned(validatorID) {
+		return ids.ShortID{}, ids.ID{}, 0, nil, errBannedPeer
 	}
 
 	msg := salticidae.MsgFromC(salticidae.CMsg(_msg))
-	codec := Codec{}
-	pMsg, err := codec.Parse(op, msg.GetPayloadByMove())
+	ds := msg.GetPayloadByMove()
+	if err := s.limit(validatorID, op, ds); err != nil {
+		return ids.ShortID{}, ids.ID{}, 0, nil, err
+	}
+	pMsg, err := s.parse(validatorID, op, ds)
+	if err == errReplayedMessage {
+		// The peer isn't penalized, since whoever replayed the message may
+		// not be the peer
+		return ids.ShortID{}, ids.ID{}, 0, nil, err
+	}
 	if err != nil {
+		s.reputation.Penalize(validatorID, networking.InvalidMessagePenalty)
 		return ids.ShortID{}, ids.ID{}, 0, nil, err // The message couldn't be parsed
 	}
 
@@ -646,3 +1138,42 @@ func (s *Voting) sanitize(_msg *C.struct_msg_t, _conn *C.struct_msgnetwork_conn_
 
 	return validatorID, chainID, requestID, pMsg, nil
 }
+
+// limit charges the message [ds], of type [op] and sent by [validatorID], to
+// the peer's rate limits. Returns an error if the message exceeds them and
+// should be dropped. Responses to this node's requests are never dropped, but
+// still count against the peer's limits.
+func (s *Voting) limit(validatorID ids.ShortID, op salticidae.Opcode, ds salticidae.DataStream) error {
+	size := ds.Size()
+	if s.inboundLimiter.Allow(validatorID, uint8(op), size, responses[op]) {
+		return nil
+	}
+	if responses[op] {
+		s.numInboundOverLimit.Inc()
+		return nil
+	}
+	s.numInboundDropped.Inc()
+	s.numInboundDroppedBytes.Add(float64(size))
+	return errRateLimited
+}
+
+// parse [ds], sent by [validatorID], into a message. If this node accepts
+// compression, peers frame every message they send it with the compression
+// applied. If this node is sequenced, peers prefix every message they send it
+// with a sequence number, and messages whose sequence number was already
+// received from the peer are dropped.
+func (s *Voting) parse(validatorID ids.ShortID, op salticidae.Opcode, ds salticidae.DataStream) (Msg, error) {
+	f := Framing{
+		Sequenced:  s.sequenced,
+		Compressed: s.compression != compression.None,
+	}
+	pMsg, seq, err := Codec{}.ParseFramed(op, ds, f, maxDecompressedSize)
+	if err != nil {
+		return nil, err
+	}
+	if s.sequenced && !s.replays.Check(validatorID, seq) {
+		s.numReplaysDetected.Inc()
+		return nil, errReplayedMessage
+	}
+	return pMsg, nil
+}
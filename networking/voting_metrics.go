@@ -7,6 +7,7 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 
 	"github.com/ava-labs/gecko/utils/logging"
+	"github.com/ava-labs/gecko/utils/metrics"
 )
 
 type votingMetrics struct {
@@ -14,175 +15,61 @@ type votingMetrics struct {
 	numAcceptedFrontierSent, numAcceptedFrontierReceived,
 	numGetAcceptedSent, numGetAcceptedReceived,
 	numAcceptedSent, numAcceptedReceived,
+	numGetStateSummarySent, numGetStateSummaryReceived,
+	numStateSummarySent, numStateSummaryReceived,
+	numGossipFrontierSent, numGossipFrontierReceived,
 	numGetSent, numGetReceived,
 	numPutSent, numPutReceived,
 	numPushQuerySent, numPushQueryReceived,
 	numPullQuerySent, numPullQueryReceived,
-	numChitsSent, numChitsReceived prometheus.Counter
+	numChitsSent, numChitsReceived,
+	numMaintenanceSent, numMaintenanceReceived,
+	numPutGossipSkipped, numQueryDroppedInMaintenance prometheus.Counter
+	numPutGossipDuplicates, numPutGossipBytesSaved prometheus.Counter
+	numCompressedSent, numCompressionBytesSaved    prometheus.Counter
+	compressionRatio                               prometheus.Histogram
+	numReplaysDetected                             prometheus.Counter
+	numInboundDropped, numInboundDroppedBytes      prometheus.Counter
+	numInboundOverLimit                            prometheus.Counter
 }
 
 func (vm *votingMetrics) Initialize(log logging.Logger, registerer prometheus.Registerer) {
-	vm.numGetAcceptedFrontierSent = prometheus.NewCounter(
-		prometheus.CounterOpts{
-			Namespace: "gecko",
-			Name:      "get_accepted_frontier_sent",
-			Help:      "Number of get accepted frontier messages sent",
-		})
-	vm.numGetAcceptedFrontierReceived = prometheus.NewCounter(
-		prometheus.CounterOpts{
-			Namespace: "gecko",
-			Name:      "get_accepted_frontier_received",
-			Help:      "Number of get accepted frontier messages received",
-		})
-	vm.numAcceptedFrontierSent = prometheus.NewCounter(
-		prometheus.CounterOpts{
-			Namespace: "gecko",
-			Name:      "accepted_frontier_sent",
-			Help:      "Number of accepted frontier messages sent",
-		})
-	vm.numAcceptedFrontierReceived = prometheus.NewCounter(
-		prometheus.CounterOpts{
-			Namespace: "gecko",
-			Name:      "accepted_frontier_received",
-			Help:      "Number of accepted frontier messages received",
-		})
-	vm.numGetAcceptedSent = prometheus.NewCounter(
-		prometheus.CounterOpts{
-			Namespace: "gecko",
-			Name:      "get_accepted_sent",
-			Help:      "Number of get accepted messages sent",
-		})
-	vm.numGetAcceptedReceived = prometheus.NewCounter(
-		prometheus.CounterOpts{
-			Namespace: "gecko",
-			Name:      "get_accepted_received",
-			Help:      "Number of get accepted messages received",
-		})
-	vm.numAcceptedSent = prometheus.NewCounter(
-		prometheus.CounterOpts{
-			Namespace: "gecko",
-			Name:      "accepted_sent",
-			Help:      "Number of accepted messages sent",
-		})
-	vm.numAcceptedReceived = prometheus.NewCounter(
-		prometheus.CounterOpts{
-			Namespace: "gecko",
-			Name:      "accepted_received",
-			Help:      "Number of accepted messages received",
-		})
-	vm.numGetSent = prometheus.NewCounter(
-		prometheus.CounterOpts{
-			Namespace: "gecko",
-			Name:      "get_sent",
-			Help:      "Number of get messages sent",
-		})
-	vm.numGetReceived = prometheus.NewCounter(
-		prometheus.CounterOpts{
-			Namespace: "gecko",
-			Name:      "get_received",
-			Help:      "Number of get messages received",
-		})
-	vm.numPutSent = prometheus.NewCounter(
-		prometheus.CounterOpts{
-			Namespace: "gecko",
-			Name:      "put_sent",
-			Help:      "Number of put messages sent",
-		})
-	vm.numPutReceived = prometheus.NewCounter(
-		prometheus.CounterOpts{
-			Namespace: "gecko",
-			Name:      "put_received",
-			Help:      "Number of put messages received",
-		})
-	vm.numPushQuerySent = prometheus.NewCounter(
-		prometheus.CounterOpts{
-			Namespace: "gecko",
-			Name:      "push_query_sent",
-			Help:      "Number of push query messages sent",
-		})
-	vm.numPushQueryReceived = prometheus.NewCounter(
-		prometheus.CounterOpts{
-			Namespace: "gecko",
-			Name:      "push_query_received",
-			Help:      "Number of push query messages received",
-		})
-	vm.numPullQuerySent = prometheus.NewCounter(
-		prometheus.CounterOpts{
-			Namespace: "gecko",
-			Name:      "pull_query_sent",
-			Help:      "Number of pull query messages sent",
-		})
-	vm.numPullQueryReceived = prometheus.NewCounter(
-		prometheus.CounterOpts{
-			Namespace: "gecko",
-			Name:      "pull_query_received",
-			Help:      "Number of pull query messages received",
-		})
-	vm.numChitsSent = prometheus.NewCounter(
-		prometheus.CounterOpts{
-			Namespace: "gecko",
-			Name:      "chits_sent",
-			Help:      "Number of chits messages sent",
-		})
-	vm.numChitsReceived = prometheus.NewCounter(
-		prometheus.CounterOpts{
-			Namespace: "gecko",
-			Name:      "chits_received",
-			Help:      "Number of chits messages received",
-		})
-
-	if err := registerer.Register(vm.numGetAcceptedFrontierSent); err != nil {
-		log.Error("Failed to register get_accepted_frontier_sent statistics due to %s", err)
-	}
-	if err := registerer.Register(vm.numGetAcceptedFrontierReceived); err != nil {
-		log.Error("Failed to register get_accepted_frontier_received statistics due to %s", err)
-	}
-	if err := registerer.Register(vm.numAcceptedFrontierSent); err != nil {
-		log.Error("Failed to register accepted_frontier_sent statistics due to %s", err)
-	}
-	if err := registerer.Register(vm.numAcceptedFrontierReceived); err != nil {
-		log.Error("Failed to register accepted_frontier_received statistics due to %s", err)
-	}
-	if err := registerer.Register(vm.numGetAcceptedSent); err != nil {
-		log.Error("Failed to register get_accepted_sent statistics due to %s", err)
-	}
-	if err := registerer.Register(vm.numGetAcceptedReceived); err != nil {
-		log.Error("Failed to register get_accepted_received statistics due to %s", err)
-	}
-	if err := registerer.Register(vm.numAcceptedSent); err != nil {
-		log.Error("Failed to register accepted_sent statistics due to %s", err)
-	}
-	if err := registerer.Register(vm.numAcceptedReceived); err != nil {
-		log.Error("Failed to register accepted_received statistics due to %s", err)
-	}
-	if err := registerer.Register(vm.numGetSent); err != nil {
-		log.Error("Failed to register get_sent statistics due to %s", err)
-	}
-	if err := registerer.Register(vm.numGetReceived); err != nil {
-		log.Error("Failed to register get_received statistics due to %s", err)
-	}
-	if err := registerer.Register(vm.numPutSent); err != nil {
-		log.Error("Failed to register put_sent statistics due to %s", err)
-	}
-	if err := registerer.Register(vm.numPutReceived); err != nil {
-		log.Error("Failed to register put_received statistics due to %s", err)
-	}
-	if err := registerer.Register(vm.numPushQuerySent); err != nil {
-		log.Error("Failed to register push_query_sent statistics due to %s", err)
-	}
-	if err := registerer.Register(vm.numPushQueryReceived); err != nil {
-		log.Error("Failed to register push_query_received statistics due to %s", err)
-	}
-	if err := registerer.Register(vm.numPullQuerySent); err != nil {
-		log.Error("Failed to register pull_query_sent statistics due to %s", err)
-	}
-	if err := registerer.Register(vm.numPullQueryReceived); err != nil {
-		log.Error("Failed to register pull_query_received statistics due to %s", err)
-	}
-	if err := registerer.Register(vm.numChitsSent); err != nil {
-		log.Error("Failed to register chits_sent statistics due to %s", err)
-	}
-	if err := registerer.Register(vm.numChitsReceived); err != nil {
-		log.Error("Failed to register chits_received statistics due to %s", err)
-	}
+	r := metrics.NewRegisterer(log, registerer, "network")
+	vm.numGetAcceptedFrontierSent = r.NewCounter("get_accepted_frontier_sent", "Number of get accepted frontier messages sent")
+	vm.numGetAcceptedFrontierReceived = r.NewCounter("get_accepted_frontier_received", "Number of get accepted frontier messages received")
+	vm.numAcceptedFrontierSent = r.NewCounter("accepted_frontier_sent", "Number of accepted frontier messages sent")
+	vm.numAcceptedFrontierReceived = r.NewCounter("accepted_frontier_received", "Number of accepted frontier messages received")
+	vm.numGetAcceptedSent = r.NewCounter("get_accepted_sent", "Number of get accepted messages sent")
+	vm.numGetAcceptedReceived = r.NewCounter("get_accepted_received", "Number of get accepted messages received")
+	vm.numAcceptedSent = r.NewCounter("accepted_sent", "Number of accepted messages sent")
+	vm.numAcceptedReceived = r.NewCounter("accepted_received", "Number of accepted messages received")
+	vm.numGetStateSummarySent = r.NewCounter("get_state_summary_sent", "Number of get state summary messages sent")
+	vm.numGetStateSummaryReceived = r.NewCounter("get_state_summary_received", "Number of get state summary messages received")
+	vm.numStateSummarySent = r.NewCounter("state_summary_sent", "Number of state summary messages sent")
+	vm.numStateSummaryReceived = r.NewCounter("state_summary_received", "Number of state summary messages received")
+	vm.numGossipFrontierSent = r.NewCounter("gossip_frontier_sent", "Number of gossip frontier messages sent")
+	vm.numGossipFrontierReceived = r.NewCounter("gossip_frontier_received", "Number of gossip frontier messages received")
+	vm.numGetSent = r.NewCounter("get_sent", "Number of get messages sent")
+	vm.numGetReceived = r.NewCounter("get_received", "Number of get messages received")
+	vm.numPutSent = r.NewCounter("put_sent", "Number of put messages sent")
+	vm.numPutReceived = r.NewCounter("put_received", "Number of put messages received")
+	vm.numPushQuerySent = r.NewCounter("push_query_sent", "Number of push query messages sent")
+	vm.numPushQueryReceived = r.NewCounter("push_query_received", "Number of push query messages received")
+	vm.numPullQuerySent = r.NewCounter("pull_query_sent", "Number of pull query messages sent")
+	vm.numPullQueryReceived = r.NewCounter("pull_query_received", "Number of pull query messages received")
+	vm.numChitsSent = r.NewCounter("chits_sent", "Number of chits messages sent")
+	vm.numChitsReceived = r.NewCounter("chits_received", "Number of chits messages received")
+	vm.numMaintenanceSent = r.NewCounter("maintenance_sent", "Number of maintenance messages sent")
+	vm.numMaintenanceReceived = r.NewCounter("maintenance_received", "Number of maintenance messages received")
+	vm.numPutGossipSkipped = r.NewCounter("put_gossip_skipped", "Number of put messages not gossiped due to the gossip budget")
+	vm.numPutGossipDuplicates = r.NewCounter("put_gossip_duplicates", "Number of put messages not gossiped because the peer was recently sent the container")
+	vm.numPutGossipBytesSaved = r.NewCounter("put_gossip_bytes_saved", "Number of container bytes not gossiped because the peer was recently sent the container")
+	vm.numCompressedSent = r.NewCounter("compressed_sent", "Number of messages sent compressed")
+	vm.numCompressionBytesSaved = r.NewCounter("compression_bytes_saved", "Number of message bytes not sent due to compression")
+	vm.compressionRatio = r.NewHistogram("compression_ratio", "Size of compressed messages relative to their uncompressed size", prometheus.LinearBuckets(0.1, 0.1, 10))
+	vm.numReplaysDetected = r.NewCounter("replays_detected", "Number of replayed messages dropped")
+	vm.numInboundDropped = r.NewCounter("inbound_rate_limited", "Number of messages dropped because they exceeded the sender's rate limits")
+	vm.numInboundDroppedBytes = r.NewCounter("inbound_rate_limited_bytes", "Number of message bytes dropped because they exceeded the sender's rate limits")
+	vm.numInboundOverLimit = r.NewCounter("inbound_over_limit", "Number of responses handled even though they exceeded the sender's rate limits")
+	vm.numQueryDroppedInMaintenance = r.NewCounter("query_dropped_in_maintenance", "Number of queries dropped because this node was in maintenance")
 }
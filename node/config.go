@@ -4,26 +4,43 @@
 package node
 
 import (
+	"time"
+
 	"github.com/ava-labs/go-ethereum/p2p/nat"
 
 	"github.com/ava-labs/gecko/database"
 	"github.com/ava-labs/gecko/snow/consensus/avalanche"
+	"github.com/ava-labs/gecko/snow/networking"
 	"github.com/ava-labs/gecko/snow/networking/router"
 	"github.com/ava-labs/gecko/utils"
 	"github.com/ava-labs/gecko/utils/logging"
+	"github.com/ava-labs/gecko/vms/platformvm"
 )
 
 // Config contains all of the configurations of an Ava node.
 type Config struct {
-	// protocol to use for opening the network interface
+	// protocol to use for opening the network interface. Nil if the node
+	// isn't behind a NAT device.
 	Nat nat.Interface
 
+	// Ports are mapped on [Nat] and the mappings are renewed every
+	// [NATRefreshInterval]. If [ResolvePublicIP], the public IP was resolved
+	// with [Nat], and is re-checked as often.
+	NATRefreshInterval time.Duration
+	NATMapHTTPPort     bool
+	ResolvePublicIP    bool
+
 	// ID of the network this node should connect to
 	NetworkID uint32
 
-	// Transaction fee configuration
+	// Transaction fee of the Simple Payments DAG. The X-Chain's fee is set by
+	// its genesis, so that every node agrees on it.
 	AvaTxFee uint64
 
+	// Parameter values this node votes for in platform chain governance
+	// proposals
+	GovernanceVotes platformvm.GovernanceVotes
+
 	// Assertions configuration
 	EnableAssertions bool
 
@@ -33,6 +50,11 @@ type Config struct {
 	// Database to use for the node
 	DB database.Database
 
+	// If true, the node only serves API calls from the state already in DB. It
+	// doesn't connect to peers or participate in consensus, and DB is never
+	// written to.
+	DBReadOnly bool
+
 	// Staking configuration
 	StakingIP       utils.IPDesc
 	EnableStaking   bool
@@ -42,16 +64,72 @@ type Config struct {
 	// Bootstrapping configuration
 	BootstrapPeers []*Peer
 
+	// Peer discovery configuration. DNSSeeds are host names that resolve to
+	// the IPs of peers. The peers this node is connected to are saved to
+	// KnownPeersFile, if it isn't empty, to be dialed when the node restarts.
+	DNSSeeds       []string
+	KnownPeersFile string
+
+	// Connection configuration, applied to both dialed and accepted
+	// connections. A peer is pinged every KeepAlivePeriod, which keeps NAT
+	// mappings open on idle links, and is disconnected if it hasn't responded
+	// within KeepAliveTimeout. SocketBufferSize is the number of bytes read from
+	// a socket at once. The network library always disables Nagle's algorithm,
+	// so there is no option to enable it.
+	KeepAlivePeriod  time.Duration
+	KeepAliveTimeout time.Duration
+	SocketBufferSize int
+
+	// Maximum number of connected peers. Non-validators are disconnected to
+	// make room for validators, which are always connected. If 0, the number
+	// of peers isn't limited.
+	MaxPeers int
+
+	// Number of peers this node dials and stays connected to. Validators this
+	// node was connected to are redialed even once the target is reached.
+	TargetOutboundPeers int
+
 	// HTTP configuration
 	HTTPPort      uint16
 	EnableHTTPS   bool
 	HTTPSKeyFile  string
 	HTTPSCertFile string
 
+	// Calls per second, and at once, that each IP may make to the HTTP server,
+	// and that a validator with the average stake may make with an API token
+	// it signed. If APIRate is 0, calls aren't rate limited.
+	APIRate, APIBurst                   float64
+	ValidatorAPIRate, ValidatorAPIBurst float64
+
 	// Enable/Disable APIs
 	AdminAPIEnabled    bool
 	KeystoreAPIEnabled bool
 	MetricsAPIEnabled  bool
+	DebugAPIEnabled    bool
+	InfoAPIEnabled     bool
+	HealthAPIEnabled   bool
+
+	// If true, this node never holds users' keys. The Keystore API is
+	// disabled and the chains' APIs can't sign transactions, so transactions
+	// must be signed externally before they're issued.
+	ExternalSigningOnly bool
+
+	// File of the assets and addresses that the AVM's API refuses to issue
+	// transactions for. If empty, all valid transactions are issued.
+	IssuanceDenyListFile string
+
+	// If true, the AVM indexes accepted transactions by the addresses they
+	// touch so their history can be served by avm.getAddressTxs
+	IndexTransactions bool
+
+	// If true, the AVM counts each asset's daily activity so it can be served
+	// by avm.getAssetStats
+	IndexAssetStats bool
+
+	// Maximum number of issued AVM transactions waiting to be taken by
+	// consensus, and the maximum number of them that each address may be
+	// involved in. If 0, the AVM's defaults are used.
+	AVMMempoolSize, AVMMempoolAddressCap int
 
 	// Logging configuration
 	LoggingConfig logging.Config
@@ -59,6 +137,59 @@ type Config struct {
 	// Consensus configuration
 	ConsensusParams avalanche.Parameters
 
+	// Number of accepted containers each accept hook remembers having handled
+	AcceptJournalRetention uint64
+
+	// Maximum number of Get messages each chain handles concurrently, if its
+	// VM allows it
+	GetWorkers int
+
+	// Maximum number of network polls each chain may have outstanding at once
+	MaxOutstandingPolls int
+
+	// If true, Snowman chains whose VM supports it sync their state from a
+	// state summary returned by their beacons, rather than replaying every
+	// block from genesis
+	SnowmanStateSync bool
+
+	// Directory of per-chain config files. The contents of
+	// [ChainConfigDir]/[chain alias]/config.json are passed to the chain's VM.
+	ChainConfigDir string
+
+	// Gossip configuration, in bytes per second and bytes. A bandwidth of 0
+	// disables the corresponding limit.
+	GossipBandwidth     uint64
+	GossipBurst         uint64
+	GossipPeerBandwidth uint64
+	GossipPeerBurst     uint64
+
+	// A container isn't gossiped to a peer that was sent it within the last
+	// [GossipDedupWindow]. At most [GossipDedupSize] sends are remembered.
+	GossipDedupWindow time.Duration
+	GossipDedupSize   int
+
+	// Time between gossips of each chain's accepted frontier to a sample of
+	// peers. If 0, accepted frontiers aren't gossiped.
+	FrontierGossipFrequency time.Duration
+
+	// If [CompressionEnabled], peers may compress the messages they send this
+	// node, and messages of at least [CompressionThreshold] bytes are sent
+	// compressed to peers that accept it
+	CompressionEnabled   bool
+	CompressionThreshold int
+
+	// A peer is banned for [PeerBanDuration] once its misbehavior score
+	// reaches [PeerBanThreshold]. If the threshold is 0, peers aren't banned.
+	PeerBanThreshold float64
+	PeerBanDuration  time.Duration
+
+	// The rates at which each peer may send this node messages
+	InboundLimits networking.InboundLimits
+
+	// If [ReplayProtectionEnabled], peers prefix the messages they send this
+	// node with sequence numbers, and replayed messages are dropped
+	ReplayProtectionEnabled bool
+
 	// Throughput configuration
 	ThroughputPort          uint16
 	ThroughputServerEnabled bool
@@ -9,37 +9,55 @@ package node
 import "C"
 
 import (
-	"crypto/x509"
-	"encoding/pem"
 	"errors"
 	"fmt"
 	"io/ioutil"
+	"net"
+	"os"
 	"sync"
+	"sync/atomic"
+	"syscall"
 	"unsafe"
 
 	"github.com/ava-labs/salticidae-go"
 
 	"github.com/ava-labs/gecko/api"
 	"github.com/ava-labs/gecko/api/admin"
+	"github.com/ava-labs/gecko/api/debug"
+	"github.com/ava-labs/gecko/api/health"
+	"github.com/ava-labs/gecko/api/info"
 	"github.com/ava-labs/gecko/api/ipcs"
 	"github.com/ava-labs/gecko/api/keystore"
 	"github.com/ava-labs/gecko/api/metrics"
 	"github.com/ava-labs/gecko/chains"
+	chainatomic "github.com/ava-labs/gecko/chains/atomic"
 	"github.com/ava-labs/gecko/database"
 	"github.com/ava-labs/gecko/database/prefixdb"
 	"github.com/ava-labs/gecko/genesis"
 	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/networking"
+	"github.com/ava-labs/gecko/networking/discovery"
+	"github.com/ava-labs/gecko/networking/nat"
 	"github.com/ava-labs/gecko/networking/xputtest"
+	"github.com/ava-labs/gecko/snow/engine/common"
+	snownetworking "github.com/ava-labs/gecko/snow/networking"
+	"github.com/ava-labs/gecko/snow/networking/sender"
 	"github.com/ava-labs/gecko/snow/triggers"
 	"github.com/ava-labs/gecko/snow/validators"
+	"github.com/ava-labs/gecko/staking"
+	"github.com/ava-labs/gecko/utils"
+	"github.com/ava-labs/gecko/utils/compression"
 	"github.com/ava-labs/gecko/utils/hashing"
 	"github.com/ava-labs/gecko/utils/logging"
 	"github.com/ava-labs/gecko/vms"
 	"github.com/ava-labs/gecko/vms/avm"
 	"github.com/ava-labs/gecko/vms/evm"
+	"github.com/ava-labs/gecko/vms/managedfx"
+	"github.com/ava-labs/gecko/vms/nftfx"
 	"github.com/ava-labs/gecko/vms/platformvm"
+	"github.com/ava-labs/gecko/vms/royaltyfx"
 	"github.com/ava-labs/gecko/vms/secp256k1fx"
+	"github.com/ava-labs/gecko/vms/sessionfx"
 	"github.com/ava-labs/gecko/vms/spchainvm"
 	"github.com/ava-labs/gecko/vms/spdagvm"
 	"github.com/ava-labs/gecko/vms/timestampvm"
@@ -62,12 +80,28 @@ type Node struct {
 	// (in consensus, for example)
 	ID ids.ShortID
 
+	// Proves this node controls its staking key. Nil if staking is disabled.
+	nodeIDProver info.NodeIDProver
+
 	// Storage for this node
 	DB database.Database
 
 	// Handles calls to Keystore API
 	keystoreServer keystore.Keystore
 
+	// Manages the state the chains share with each other
+	sharedMemory chainatomic.Memory
+
+	// ID of the X-Chain and of the AVA asset it creates at genesis
+	xChainID   ids.ID
+	avaAssetID ids.ID
+
+	// Amount of AVA each X-Chain transaction must burn, as set by its genesis
+	xChainTxFee uint64
+
+	// Serves the metrics gathered by this node
+	metricsHandler *common.HTTPHandler
+
 	// Manages creation of blockchains and routing messages to them
 	chainManager chains.Manager
 
@@ -85,6 +119,13 @@ type Node struct {
 	// Network that manages clients
 	ClientNet salticidae.MsgNetwork // TODO: Remove
 
+	// Keeps this node's ports mapped on the NAT device in front of it
+	natTraversal nat.Traversal
+
+	// Finds peers for this node to connect to, and redials the bootstrap
+	// peers until they're connected to
+	discovery discovery.Discovery
+
 	// API that handles new connections
 	ValidatorAPI *networking.Handshake
 	// API that handles voting messages
@@ -101,8 +142,14 @@ type Node struct {
 	// Handles HTTP API calls
 	APIServer api.Server
 
+	// Limits the rate of HTTP API calls. Nil if calls aren't rate limited.
+	apiLimiter *api.RateLimiter
+
 	// This node's configuration
 	Config *Config
+
+	// 1 if the node should be started again after it shuts down
+	restart uint32
 }
 
 /*
@@ -140,12 +187,15 @@ func (n *Node) initNetlib() error {
 
 	// Create peer network config, may have tls enabled
 	peerConfig := salticidae.NewPeerNetworkConfig()
+	peerConfig.PingPeriod(n.Config.KeepAlivePeriod.Seconds())
+	peerConfig.ConnTimeout(n.Config.KeepAliveTimeout.Seconds())
+	peerMsgConfig := peerConfig.AsMsgNetworkConfig()
+	peerMsgConfig.SegBuffSize(n.Config.SocketBufferSize)
 	if n.Config.EnableStaking {
-		msgConfig := peerConfig.AsMsgNetworkConfig()
-		msgConfig.MaxMsgSize(maxMessageSize)
-		msgConfig.EnableTLS(true)
-		msgConfig.TLSKeyFile(n.Config.StakingKeyFile)
-		msgConfig.TLSCertFile(n.Config.StakingCertFile)
+		peerMsgConfig.MaxMsgSize(maxMessageSize)
+		peerMsgConfig.EnableTLS(true)
+		peerMsgConfig.TLSKeyFile(n.Config.StakingKeyFile)
+		peerMsgConfig.TLSCertFile(n.Config.StakingCertFile)
 	}
 
 	// Create the peer network
@@ -162,6 +212,7 @@ func (n *Node) initNetlib() error {
 		// Create the client network
 		msgConfig := salticidae.NewMsgNetworkConfig()
 		msgConfig.MaxMsgSize(maxMessageSize)
+		msgConfig.SegBuffSize(n.Config.SocketBufferSize)
 		n.ClientNet = salticidae.NewMsgNetwork(n.EC, msgConfig, &err)
 		if code := err.GetCode(); code != 0 {
 			return errors.New(salticidae.StrError(code))
@@ -182,6 +233,11 @@ func (n *Node) initValidatorNet() error {
 	n.vdrs = validators.NewManager()
 	n.vdrs.PutValidatorSet(platformvm.DefaultSubnetID, defaultSubnetValidators)
 
+	// API tokens signed by the default subnet's validators get a larger quota
+	if n.apiLimiter != nil {
+		n.apiLimiter.SetValidators(defaultSubnetValidators)
+	}
+
 	cErr := salticidae.NewError()
 	serverIP := salticidae.NewNetAddrFromIPPortString(n.Config.StakingIP.String(), true, &cErr)
 	if code := cErr.GetCode(); code != 0 {
@@ -198,17 +254,70 @@ func (n *Node) initValidatorNet() error {
 		/*metrics=*/ n.Config.ConsensusParams.Metrics,
 		/*enableStaking=*/ n.Config.EnableStaking,
 		/*networkID=*/ n.Config.NetworkID,
+		/*maxPeers=*/ n.Config.MaxPeers,
+		/*targetOutboundPeers=*/ n.Config.TargetOutboundPeers,
+		/*acceptedCompression=*/ n.acceptedCompression(),
+		/*sequenced=*/ n.Config.ReplayProtectionEnabled,
+		/*banThreshold=*/ n.Config.PeerBanThreshold,
+		/*banDuration=*/ n.Config.PeerBanDuration,
 	)
 
 	return nil
 }
 
+// initNAT maps the staking port, and the HTTP port if configured to, on the
+// NAT device in front of this node, and keeps the node's public IP up to date
+func (n *Node) initNAT() {
+	n.natTraversal.Initialize(
+		n.Log,
+		n.Config.Nat,
+		n.Config.StakingIP.IP,
+		n.Config.NATRefreshInterval,
+		n.Config.ResolvePublicIP,
+		func(ip net.IP) {
+			stakingIP := utils.IPDesc{
+				IP:   ip,
+				Port: n.Config.StakingIP.Port,
+			}
+			if err := n.ValidatorAPI.SetMyIP(stakingIP); err != nil {
+				n.Log.Warn("Failed to update this node's public IP to %s due to %s", stakingIP, err)
+			}
+		},
+	)
+	n.natTraversal.Map("TCP", n.Config.StakingIP.Port, "Gecko Staking Server")
+	if n.Config.NATMapHTTPPort {
+		n.natTraversal.Map("TCP", n.Config.HTTPPort, "Gecko HTTP Server")
+	}
+}
+
+// acceptedCompression returns the compression this node accepts
+func (n *Node) acceptedCompression() compression.Type {
+	if n.Config.CompressionEnabled {
+		return compression.Gzip
+	}
+	return compression.None
+}
+
 func (n *Node) initConsensusNet() {
 	vdrs, ok := n.vdrs.GetValidatorSet(platformvm.DefaultSubnetID)
 	n.Log.AssertTrue(ok, "should have initialize the validator set already")
 
+	gossipBudget := &sender.GossipBudget{}
+	gossipBudget.Initialize(
+		n.Config.GossipBandwidth,
+		n.Config.GossipBurst,
+		n.Config.GossipPeerBandwidth,
+		n.Config.GossipPeerBurst,
+	)
+
+	gossipCache := &sender.GossipCache{}
+	gossipCache.Initialize(n.Config.GossipDedupWindow, n.Config.GossipDedupSize)
+
+	inboundLimiter := &snownetworking.InboundLimiter{}
+	inboundLimiter.Initialize(n.Config.InboundLimits)
+
 	n.ConsensusAPI = &networking.VotingNet
-	n.ConsensusAPI.Initialize(n.Log, vdrs, n.PeerNet, n.ValidatorAPI.Connections(), n.chainManager.Router(), n.Config.ConsensusParams.Metrics)
+	n.ConsensusAPI.Initialize(n.Log, vdrs, n.PeerNet, n.ValidatorAPI.Connections(), n.chainManager.Router(), gossipBudget, gossipCache, n.ValidatorAPI.AcceptedCompression(), n.Config.CompressionThreshold, n.ValidatorAPI.PeerCompression(), n.ValidatorAPI.Sequenced(), n.ValidatorAPI.ReplayGuard(), n.ValidatorAPI.Reputation(), inboundLimiter, n.Config.ConsensusParams.Metrics)
 
 	n.Log.AssertNoError(n.ConsensusDispatcher.Register("gossip", n.ConsensusAPI))
 }
@@ -226,6 +335,11 @@ func (n *Node) initClients() {
 // StartConsensusServer starts the P2P server this node uses to communicate
 // with other nodes
 func (n *Node) StartConsensusServer() error {
+	if n.Config.DBReadOnly {
+		n.Log.Info("not starting the consensus server as the database is read only")
+		return nil
+	}
+
 	n.Log.Verbo("starting the consensus server")
 
 	n.PeerNet.AsMsgNetwork().Start()
@@ -266,15 +380,42 @@ func (n *Node) StartConsensusServer() error {
 			if code := err.GetCode(); code != 0 {
 				return fmt.Errorf("failed to create bootstrap ip addr: %s", salticidae.StrError(code))
 			}
+			if n.Config.EnableStaking {
+				n.ValidatorAPI.ExpectPeerID(peer.IP, peer.ID)
+			}
 			n.PeerNet.AddPeer(bootstrapIP)
 		} else {
 			n.Log.Error("can't add self as a bootstrapper")
 		}
 	}
 
+	n.initDiscovery()
 	return nil
 }
 
+// initDiscovery starts finding peers for this node to connect to, from the
+// DNS seeds and the peers it was connected to before it restarted. Bootstrap
+// peers that couldn't be connected to are retried.
+func (n *Node) initDiscovery() {
+	bootstrap := []utils.IPDesc(nil)
+	for _, peer := range n.Config.BootstrapPeers {
+		if !peer.IP.Equal(n.Config.StakingIP) {
+			bootstrap = append(bootstrap, peer.IP)
+		}
+	}
+	n.discovery.Initialize(
+		n.Log,
+		discovery.Config{
+			Bootstrap:      bootstrap,
+			Seeds:          n.Config.DNSSeeds,
+			DefaultPort:    n.Config.StakingIP.Port,
+			KnownPeersFile: n.Config.KnownPeersFile,
+		},
+		n.ValidatorAPI.Dial,
+		n.ValidatorAPI.Connections().Peers,
+	)
+}
+
 // Dispatch starts the node's servers.
 // Returns when the node exits.
 func (n *Node) Dispatch() { n.EC.Dispatch() }
@@ -287,6 +428,31 @@ func (n *Node) Dispatch() { n.EC.Dispatch() }
 
 func (n *Node) initDatabase() { n.DB = n.Config.DB }
 
+// Initialize the state the chains share with each other, and look up the
+// chain and asset that $AVA moves between
+func (n *Node) initSharedMemory() error {
+	n.Log.Info("initializing SharedMemory")
+	sharedMemoryDB := prefixdb.New([]byte("shared memory"), n.DB)
+	n.sharedMemory.Initialize(n.Log, sharedMemoryDB)
+
+	xChain := genesis.VMGenesis(n.Config.NetworkID, avm.ID)
+	if xChain == nil {
+		return errors.New("genesis doesn't create the X-Chain")
+	}
+	avaAssetID, err := avm.GenesisAssetID(xChain.GenesisData, "AVA")
+	if err != nil {
+		return err
+	}
+	xChainTxFee, err := avm.GenesisTxFee(xChain.GenesisData)
+	if err != nil {
+		return err
+	}
+	n.xChainID = xChain.ChainID()
+	n.avaAssetID = avaAssetID
+	n.xChainTxFee = xChainTxFee
+	return nil
+}
+
 // Initialize this node's ID
 // If staking is disabled, a node's ID is a hash of its IP
 // Otherwise, it is a hash of the TLS certificate that this node
@@ -302,16 +468,17 @@ func (n *Node) initNodeID() error {
 	if err != nil {
 		return fmt.Errorf("problem reading staking certificate: %w", err)
 	}
-
-	block, _ := pem.Decode(stakeCert)
-	cert, err := x509.ParseCertificate(block.Bytes)
+	stakeKey, err := ioutil.ReadFile(n.Config.StakingKeyFile)
 	if err != nil {
-		return fmt.Errorf("problem parsing staking certificate: %w", err)
+		return fmt.Errorf("problem reading staking key: %w", err)
 	}
-	n.ID, err = ids.ToShortID(hashing.PubkeyBytesToAddress(cert.Raw))
+
+	// Check the key pair here, as the TLS library fails less clearly
+	n.ID, err = staking.KeyPairNodeID(stakeCert, stakeKey)
 	if err != nil {
 		return fmt.Errorf("problem deriving staker ID from certificate: %w", err)
 	}
+	n.nodeIDProver = &staking.NodeIDProver{CertPEM: stakeCert, KeyPEM: stakeKey}
 	n.Log.Info("Set node's ID to %s", n.ID)
 	return nil
 }
@@ -320,14 +487,37 @@ func (n *Node) initNodeID() error {
 // AVM, EVM, Simple Payments DAG, Simple Payments Chain
 // The Platform VM is registered in initStaking because
 // its factory needs to reference n.chainManager, which is nil right now
-func (n *Node) initVMManager() {
+func (n *Node) initVMManager() error {
+	avmFactory := &avm.Factory{
+		AVA:      n.avaAssetID,
+		Platform: ids.Empty,
+
+		IndexTransactions: n.Config.IndexTransactions,
+		IndexAssetStats:   n.Config.IndexAssetStats,
+
+		MempoolSize:       n.Config.AVMMempoolSize,
+		MempoolAddressCap: n.Config.AVMMempoolAddressCap,
+	}
+	if n.Config.IssuanceDenyListFile != "" {
+		denyList, err := avm.LoadDenyList(n.Config.IssuanceDenyListFile)
+		if err != nil {
+			return err
+		}
+		avmFactory.IssuanceFilter = denyList
+	}
+
 	n.vmManager = vms.NewManager(&n.APIServer, n.HTTPLog)
-	n.vmManager.RegisterVMFactory(avm.ID, &avm.Factory{})
+	n.vmManager.RegisterVMFactory(avm.ID, avmFactory)
 	n.vmManager.RegisterVMFactory(evm.ID, &evm.Factory{})
 	n.vmManager.RegisterVMFactory(spdagvm.ID, &spdagvm.Factory{TxFee: n.Config.AvaTxFee})
 	n.vmManager.RegisterVMFactory(spchainvm.ID, &spchainvm.Factory{})
 	n.vmManager.RegisterVMFactory(secp256k1fx.ID, &secp256k1fx.Factory{})
+	n.vmManager.RegisterVMFactory(nftfx.ID, &nftfx.Factory{})
+	n.vmManager.RegisterVMFactory(managedfx.ID, &managedfx.Factory{})
+	n.vmManager.RegisterVMFactory(sessionfx.ID, &sessionfx.Factory{})
+	n.vmManager.RegisterVMFactory(royaltyfx.ID, &royaltyfx.Factory{})
 	n.vmManager.RegisterVMFactory(timestampvm.ID, &timestampvm.Factory{})
+	return nil
 }
 
 // Create the EventDispatcher used for hooking events
@@ -356,8 +546,11 @@ func (n *Node) initChains() {
 	n.vmManager.RegisterVMFactory(
 		/*vmID=*/ platformvm.ID,
 		/*vmFactory=*/ &platformvm.Factory{
-			ChainManager: n.chainManager,
-			Validators:   vdrs,
+			ChainManager:    n.chainManager,
+			Validators:      vdrs,
+			GovernanceVotes: n.Config.GovernanceVotes,
+			AVM:             n.xChainID,
+			AVA:             n.avaAssetID,
 		},
 	)
 
@@ -373,6 +566,7 @@ func (n *Node) initChains() {
 		ID:            ids.Empty,
 		GenesisData:   genesisBytes, // Specifies other chains to create
 		VMAlias:       platformvm.ID.String(),
+		Engine:        chains.SnowmanEngine,
 		CustomBeacons: beacons,
 	})
 }
@@ -381,7 +575,14 @@ func (n *Node) initChains() {
 func (n *Node) initAPIServer() {
 	n.Log.Info("Initializing API server")
 
-	n.APIServer.Initialize(n.Log, n.LogFactory, n.Config.HTTPPort)
+	n.APIServer.Initialize(n.Log, n.LogFactory, n.Config.HTTPPort, n.Config.ConsensusParams.Metrics)
+
+	if n.Config.APIRate > 0 {
+		n.Log.Info("Rate limiting API calls")
+		n.apiLimiter = &api.RateLimiter{}
+		n.apiLimiter.Initialize(n.Log, n.Config.APIRate, n.Config.APIBurst, n.Config.ValidatorAPIRate, n.Config.ValidatorAPIBurst)
+		n.APIServer.SetRateLimiter(n.apiLimiter)
+	}
 
 	if n.Config.EnableHTTPS {
 		n.Log.Debug("Initializing API server with TLS Enabled")
@@ -408,6 +609,7 @@ func (n *Node) initChainManager() {
 		n.DB,
 		n.Config.ConsensusRouter,
 		&networking.VotingNet,
+		n.ValidatorAPI.Reputation(),
 		n.Config.ConsensusParams,
 		n.vdrs,
 		n.ID,
@@ -415,6 +617,14 @@ func (n *Node) initChainManager() {
 		n.ValidatorAPI,
 		&n.APIServer,
 		&n.keystoreServer,
+		&n.sharedMemory,
+		n.Config.AcceptJournalRetention,
+		n.Config.GetWorkers,
+		n.Config.FrontierGossipFrequency,
+		n.Config.MaxOutstandingPolls,
+		n.Config.SnowmanStateSync,
+		n.Config.DBReadOnly,
+		n.Config.ChainConfigDir,
 	)
 
 	n.chainManager.AddRegistrant(&n.APIServer)
@@ -426,33 +636,80 @@ func (n *Node) initKeystoreAPI() {
 	n.Log.Info("initializing Keystore API")
 	keystoreDB := prefixdb.New([]byte("keystore"), n.DB)
 	n.keystoreServer.Initialize(n.Log, keystoreDB)
+	if n.Config.ExternalSigningOnly {
+		n.Log.Info("external signing only: the Keystore API and server-side signing are disabled")
+		n.keystoreServer.Disable()
+		return
+	}
 	keystoreHandler := n.keystoreServer.CreateHandler()
 	if n.Config.KeystoreAPIEnabled {
 		n.APIServer.AddRoute(keystoreHandler, &sync.RWMutex{}, "keystore", "", n.HTTPLog)
 	}
 }
 
+// initMetrics creates the registry the node's metrics are registered with
+func (n *Node) initMetrics() {
+	registry, handler := metrics.NewService()
+	n.Config.ConsensusParams.Metrics = registry
+	n.metricsHandler = handler
+}
+
 // initMetricsAPI initializes the Metrics API
-// Assumes n.APIServer is already set
+// Assumes n.APIServer and n.metricsHandler are already set
 func (n *Node) initMetricsAPI() {
 	n.Log.Info("initializing Metrics API")
-	registry, handler := metrics.NewService()
 	if n.Config.MetricsAPIEnabled {
-		n.APIServer.AddRoute(handler, &sync.RWMutex{}, "metrics", "", n.HTTPLog)
+		n.APIServer.AddRoute(n.metricsHandler, &sync.RWMutex{}, "metrics", "", n.HTTPLog)
 	}
-	n.Config.ConsensusParams.Metrics = registry
 }
 
 // initAdminAPI initializes the Admin API service
-// Assumes n.log, n.chainManager, and n.ValidatorAPI already initialized
+// Assumes n.log, n.chainManager, n.ValidatorAPI and n.ConsensusAPI already
+// initialized
 func (n *Node) initAdminAPI() {
 	if n.Config.AdminAPIEnabled {
 		n.Log.Info("initializing Admin API")
-		service := admin.NewService(n.ID, n.Config.NetworkID, n.Log, n.chainManager, n.ValidatorAPI.Connections(), &n.APIServer)
+		service := admin.NewService(n.ID, n.Config.NetworkID, n.Log, n.chainManager, n.ValidatorAPI.Connections(), n, n.ConsensusAPI, &n.APIServer, n.Config.LoggingConfig.Directory, n.metricsHandler.Handler)
 		n.APIServer.AddRoute(service, &sync.RWMutex{}, "admin", "", n.HTTPLog)
 	}
 }
 
+// initDebugAPI initializes the Debug API service
+// Assumes n.DB and n.chainManager already initialized
+func (n *Node) initDebugAPI() {
+	if n.Config.DebugAPIEnabled {
+		n.Log.Info("initializing Debug API")
+		service := debug.NewService(n.Log, n.DB, n.chainManager)
+		n.APIServer.AddRoute(service, &sync.RWMutex{}, "debug", "", n.HTTPLog)
+	}
+}
+
+// initInfoAPI initializes the Info API service
+// Assumes n.Log and the validator handshake already initialized
+func (n *Node) initInfoAPI() {
+	if n.Config.InfoAPIEnabled {
+		n.Log.Info("initializing Info API")
+		service := info.NewService(n.Log, info.Fees{
+			TxFee:            n.xChainTxFee,
+			CreateAssetTxFee: n.xChainTxFee,
+			PlatformTxFee:    platformvm.DefaultGovernanceParameters().TxFee,
+		}, n.ValidatorAPI, n.ValidatorAPI.Reputation(), n.ValidatorAPI.ReplayGuard(), n.nodeIDProver)
+		n.APIServer.AddRoute(service, &sync.RWMutex{}, "info", "", n.HTTPLog)
+	}
+}
+
+// initHealthAPI initializes the Health API service
+// Assumes n.Log and the validator handshake already initialized
+func (n *Node) initHealthAPI() {
+	if n.Config.HealthAPIEnabled {
+		n.Log.Info("initializing Health API")
+		service := health.NewService(n.Log, map[string]health.Check{
+			"clockSkew": networking.HandshakeNet.ClockSkewCheck,
+		})
+		n.APIServer.AddRoute(service, &sync.RWMutex{}, "health", "", n.HTTPLog)
+	}
+}
+
 // initIPCAPI initializes the IPC API service
 // Assumes n.log and n.chainManager already initialized
 func (n *Node) initIPCAPI() {
@@ -498,10 +755,16 @@ func (n *Node) Initialize(Config *Config, logger logging.Logger, logFactory logg
 
 	n.initDatabase() // Set up the node's database
 
+	if err = n.initSharedMemory(); err != nil { // Set up the chains' shared memory
+		return fmt.Errorf("problem initializing shared memory: %w", err)
+	}
+
 	if err = n.initNodeID(); err != nil { // Derive this node's ID
 		return fmt.Errorf("problem initializing staker ID: %w", err)
 	}
 
+	n.initMetrics() // Set up the metrics registry
+
 	// Start HTTP APIs
 	n.initAPIServer()   // Start the API Server
 	n.initKeystoreAPI() // Start the Keystore API
@@ -511,8 +774,11 @@ func (n *Node) Initialize(Config *Config, logger logging.Logger, logFactory logg
 	if err = n.initNetlib(); err != nil { // Set up all networking
 		return fmt.Errorf("problem initializing networking: %w", err)
 	}
+	if err = n.initVMManager(); err != nil { // Set up the vm manager
+		return fmt.Errorf("problem initializing the vm manager: %w", err)
+	}
 	n.initValidatorNet()    // Set up the validator handshake + authentication
-	n.initVMManager()       // Set up the vm manager
+	n.initNAT()             // Map this node's ports on the NAT device
 	n.initEventDispatcher() // Set up the event dipatcher
 	n.initChainManager()    // Set up the chain manager
 	n.initConsensusNet()    // Set up the main consensus network
@@ -522,17 +788,40 @@ func (n *Node) Initialize(Config *Config, logger logging.Logger, logFactory logg
 		n.initClients() // Set up the client servers
 	}
 
-	n.initAdminAPI() // Start the Admin API
-	n.initIPCAPI()   // Start the IPC API
-	n.initAliases()  // Set up aliases
-	n.initChains()   // Start the Platform chain
+	n.initAdminAPI()  // Start the Admin API
+	n.initDebugAPI()  // Start the Debug API
+	n.initInfoAPI()   // Start the Info API
+	n.initHealthAPI() // Start the Health API
+	n.initIPCAPI()    // Start the IPC API
+	n.initAliases()   // Set up aliases
+	n.initChains()    // Start the Platform chain
 
 	return nil
 }
 
+// Stop the node by triggering the same graceful shutdown as a SIGTERM. This
+// causes Dispatch to return.
+func (n *Node) Stop() error {
+	n.Log.Info("stopping the node")
+	return syscall.Kill(os.Getpid(), syscall.SIGTERM)
+}
+
+// Restart stops the node and marks that it should be started again once it
+// has shut down
+func (n *Node) Restart() error {
+	n.Log.Info("restarting the node")
+	atomic.StoreUint32(&n.restart, 1)
+	return n.Stop()
+}
+
+// ShouldRestart returns true if the node was stopped by a call to Restart
+func (n *Node) ShouldRestart() bool { return atomic.LoadUint32(&n.restart) == 1 }
+
 // Shutdown this node
 func (n *Node) Shutdown() {
 	n.Log.Info("shutting down the node")
+	n.discovery.Shutdown()
+	n.natTraversal.Shutdown()
 	n.ValidatorAPI.Shutdown()
 	n.ConsensusAPI.Shutdown()
 	n.chainManager.Shutdown()
@@ -0,0 +1,13 @@
+#!/bin/bash -e
+
+# Builds the node and runs the end to end tests against networks of it
+
+PREFIX="${PREFIX:-$(pwd)/build}"
+
+SRC_DIR="$(dirname "${BASH_SOURCE[0]}")"
+source "$SRC_DIR/env.sh"
+
+GECKO_PATH="$( cd "$SRC_DIR" && cd .. && pwd )"
+
+go build -o "$PREFIX/ava" "$GECKO_PATH/main/"*.go
+go test -v -tags e2e -timeout 10m "$GECKO_PATH/e2e/" -args -binary="$PREFIX/ava" -keys="$GECKO_PATH/keys"
@@ -4,6 +4,8 @@
 package choices
 
 import (
+	"context"
+
 	"github.com/ava-labs/gecko/ids"
 )
 
@@ -22,7 +24,11 @@ type Decidable interface {
 	// Accept this element.
 	//
 	// This element will be accepted by every correct node in the network.
-	Accept()
+	//
+	// [ctx] is the context of the request whose handling decided this element.
+	// The element must be accepted even if [ctx] is done; [ctx] only bounds
+	// work that can be abandoned, such as notifying other components.
+	Accept(ctx context.Context)
 
 	// Reject this element.
 	//
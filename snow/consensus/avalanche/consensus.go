@@ -4,6 +4,8 @@
 package avalanche
 
 import (
+	"context"
+
 	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/snow"
 	"github.com/ava-labs/gecko/snow/choices"
@@ -29,13 +31,19 @@ type Consensus interface {
 	// Returns the parameters that describe this avalanche instance
 	Parameters() Parameters
 
+	// Replaces the parameters of this avalanche instance. The new parameters
+	// apply to the next poll, including to the transactions already
+	// processing. Assumes the parameters are valid.
+	SetParameters(Parameters)
+
 	// Returns true if the transaction is virtuous.
 	// That is, no transaction has been added that conflicts with it
 	IsVirtuous(snowstorm.Tx) bool
 
 	// Adds a new decision. Assumes the dependencies have already been added.
-	// Assumes that mutations don't conflict with themselves.
-	Add(Vertex)
+	// Assumes that mutations don't conflict with themselves. [ctx] is passed
+	// to the vertices and transactions that are accepted immediately.
+	Add(context.Context, Vertex)
 
 	// VertexIssued returns true iff Vertex has been added
 	VertexIssued(Vertex) bool
@@ -54,8 +62,9 @@ type Consensus interface {
 	Preferences() ids.Set
 
 	// RecordPoll collects the results of a network poll. If a result has not
-	// been added, the result is dropped.
-	RecordPoll(ids.UniqueBag)
+	// been added, the result is dropped. [ctx] is passed to the vertices and
+	// transactions the poll accepts.
+	RecordPoll(context.Context, ids.UniqueBag)
 
 	// Quiesce returns true iff all vertices that have been added but not been accepted or rejected are rogue.
 	// Note, it is possible that after returning quiesce, a new decision may be added such
@@ -4,6 +4,7 @@
 package avalanche
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
@@ -27,7 +28,7 @@ func ParamsTest(t *testing.T, factory Factory) {
 	ctx := snow.DefaultContextTest()
 	params := Parameters{
 		Parameters: snowball.Parameters{
-			Namespace:    fmt.Sprintf("gecko_%s", ctx.ChainID.String()),
+			Namespace:    ctx.ChainID.String(),
 			Metrics:      prometheus.NewRegistry(),
 			K:            2,
 			Alpha:        2,
@@ -40,17 +41,17 @@ func ParamsTest(t *testing.T, factory Factory) {
 
 	numProcessing := prometheus.NewGauge(
 		prometheus.GaugeOpts{
-			Namespace: params.Namespace,
+			Namespace: fmt.Sprintf("gecko_%s", params.Namespace),
 			Name:      "vtx_processing",
 		})
 	numAccepted := prometheus.NewCounter(
 		prometheus.CounterOpts{
-			Namespace: params.Namespace,
+			Namespace: fmt.Sprintf("gecko_%s", params.Namespace),
 			Name:      "vtx_accepted",
 		})
 	numRejected := prometheus.NewCounter(
 		prometheus.CounterOpts{
-			Namespace: params.Namespace,
+			Namespace: fmt.Sprintf("gecko_%s", params.Namespace),
 			Name:      "vtx_rejected",
 		})
 
@@ -115,7 +116,7 @@ func AddTest(t *testing.T, factory Factory) {
 		status:       choices.Processing,
 	}
 
-	avl.Add(vtx0)
+	avl.Add(context.Background(), vtx0)
 
 	if avl.Finalized() {
 		t.Fatalf("A non-empty avalanche instance is finalized")
@@ -134,7 +135,7 @@ func AddTest(t *testing.T, factory Factory) {
 		status:       choices.Processing,
 	}
 
-	avl.Add(vtx1)
+	avl.Add(context.Background(), vtx1)
 
 	if avl.Finalized() {
 		t.Fatalf("A non-empty avalanche instance is finalized")
@@ -142,7 +143,7 @@ func AddTest(t *testing.T, factory Factory) {
 		t.Fatalf("Initial frontier failed to be set")
 	}
 
-	avl.Add(vtx1)
+	avl.Add(context.Background(), vtx1)
 
 	if avl.Finalized() {
 		t.Fatalf("A non-empty avalanche instance is finalized")
@@ -150,7 +151,7 @@ func AddTest(t *testing.T, factory Factory) {
 		t.Fatalf("Initial frontier failed to be set")
 	}
 
-	avl.Add(vts[0])
+	avl.Add(context.Background(), vts[0])
 
 	if avl.Finalized() {
 		t.Fatalf("A non-empty avalanche instance is finalized")
@@ -206,7 +207,7 @@ func VertexIssuedTest(t *testing.T, factory Factory) {
 		t.Fatalf("Vertex reported as issued")
 	}
 
-	avl.Add(vtx)
+	avl.Add(context.Background(), vtx)
 
 	if !avl.VertexIssued(vtx) {
 		t.Fatalf("Vertex reported as not issued")
@@ -261,7 +262,7 @@ func TxIssuedTest(t *testing.T, factory Factory) {
 		status:       choices.Processing,
 	}
 
-	avl.Add(vtx)
+	avl.Add(context.Background(), vtx)
 
 	if !avl.TxIssued(tx1) {
 		t.Fatalf("Tx reported as not issued")
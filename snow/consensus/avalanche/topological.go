@@ -4,12 +4,15 @@
 package avalanche
 
 import (
+	"context"
+
 	"github.com/prometheus/client_golang/prometheus"
 
 	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/snow"
 	"github.com/ava-labs/gecko/snow/choices"
 	"github.com/ava-labs/gecko/snow/consensus/snowstorm"
+	"github.com/ava-labs/gecko/utils/metrics"
 )
 
 // TopologicalFactory implements Factory by returning a topological struct
@@ -64,34 +67,10 @@ func (ta *Topological) Initialize(ctx *snow.Context, params Parameters, frontier
 	ta.ctx = ctx
 	ta.params = params
 
-	ta.numProcessing = prometheus.NewGauge(
-		prometheus.GaugeOpts{
-			Namespace: params.Namespace,
-			Name:      "vtx_processing",
-			Help:      "Number of currently processing vertices",
-		})
-	ta.numAccepted = prometheus.NewCounter(
-		prometheus.CounterOpts{
-			Namespace: params.Namespace,
-			Name:      "vtx_accepted",
-			Help:      "Number of vertices accepted",
-		})
-	ta.numRejected = prometheus.NewCounter(
-		prometheus.CounterOpts{
-			Namespace: params.Namespace,
-			Name:      "vtx_rejected",
-			Help:      "Number of vertices rejected",
-		})
-
-	if err := ta.params.Metrics.Register(ta.numProcessing); err != nil {
-		ta.ctx.Log.Error("Failed to register vtx_processing statistics due to %s", err)
-	}
-	if err := ta.params.Metrics.Register(ta.numAccepted); err != nil {
-		ta.ctx.Log.Error("Failed to register vtx_accepted statistics due to %s", err)
-	}
-	if err := ta.params.Metrics.Register(ta.numRejected); err != nil {
-		ta.ctx.Log.Error("Failed to register vtx_rejected statistics due to %s", err)
-	}
+	r := metrics.NewChainRegisterer(ctx.Log, params.Metrics, params.Namespace, "consensus")
+	ta.numProcessing = r.NewGauge("vtx_processing", "Number of currently processing vertices")
+	ta.numAccepted = r.NewCounter("vtx_accepted", "Number of vertices accepted")
+	ta.numRejected = r.NewCounter("vtx_rejected", "Number of vertices rejected")
 
 	ta.nodes = make(map[[32]byte]Vertex)
 
@@ -102,17 +81,24 @@ func (ta *Topological) Initialize(ctx *snow.Context, params Parameters, frontier
 	for _, vtx := range frontier {
 		ta.frontier[vtx.ID().Key()] = vtx
 	}
-	ta.updateFrontiers()
+	// The frontier is accepted, so no vertex is decided while it's updated
+	ta.updateFrontiers(context.Background())
 }
 
 // Parameters implements the Avalanche interface
 func (ta *Topological) Parameters() Parameters { return ta.params }
 
+// SetParameters implements the Avalanche interface
+func (ta *Topological) SetParameters(params Parameters) {
+	ta.params = params
+	ta.cg.SetParameters(params.Parameters)
+}
+
 // IsVirtuous implements the Avalanche interface
 func (ta *Topological) IsVirtuous(tx snowstorm.Tx) bool { return ta.cg.IsVirtuous(tx) }
 
 // Add implements the Avalanche interface
-func (ta *Topological) Add(vtx Vertex) {
+func (ta *Topological) Add(ctx context.Context, vtx Vertex) {
 	ta.ctx.Log.AssertTrue(vtx != nil, "Attempting to insert nil vertex")
 
 	vtxID := vtx.ID()
@@ -128,14 +114,14 @@ func (ta *Topological) Add(vtx Vertex) {
 	for _, tx := range vtx.Txs() {
 		if !tx.Status().Decided() {
 			// Add the consumers to the conflict graph.
-			ta.cg.Add(tx)
+			ta.cg.Add(ctx, tx)
 		}
 	}
 
 	ta.nodes[key] = vtx // Add this vertex to the set of nodes
 	ta.numProcessing.Inc()
 
-	ta.update(vtx) // Update the vertex and it's ancestry
+	ta.update(ctx, vtx) // Update the vertex and it's ancestry
 }
 
 // VertexIssued implements the Avalanche interface
@@ -160,16 +146,16 @@ func (ta *Topological) Virtuous() ids.Set { return ta.virtuous }
 func (ta *Topological) Preferences() ids.Set { return ta.preferred }
 
 // RecordPoll implements the Avalanche interface
-func (ta *Topological) RecordPoll(responses ids.UniqueBag) {
+func (ta *Topological) RecordPoll(ctx context.Context, responses ids.UniqueBag) {
 	// Set up the topological sort: O(|Live Set|)
 	kahns, leaves := ta.calculateInDegree(responses)
 	// Collect the votes for each transaction: O(|Live Set|)
 	votes := ta.pushVotes(kahns, leaves)
 	// Update the conflict graph: O(|Transactions|)
 	ta.ctx.Log.Verbo("Updating consumer confidences based on:\n%s", &votes)
-	ta.cg.RecordPoll(votes)
+	ta.cg.RecordPoll(ctx, votes)
 	// Update the dag: O(|Live Set|)
-	ta.updateFrontiers()
+	ta.updateFrontiers(ctx)
 }
 
 // Quiesce implements the Avalanche interface
@@ -303,7 +289,7 @@ func (ta *Topological) pushVotes(
 // If I'm preferred, remove all my ancestors from the preferred frontier, add
 //     myself to the preferred frontier
 // If all my parents are accepted and I'm acceptable, accept myself
-func (ta *Topological) update(vtx Vertex) {
+func (ta *Topological) update(ctx context.Context, vtx Vertex) {
 	vtxID := vtx.ID()
 	vtxKey := vtxID.Key()
 	if _, cached := ta.preferenceCache[vtxKey]; cached {
@@ -355,7 +341,7 @@ func (ta *Topological) update(vtx Vertex) {
 	deps := vtx.Parents()
 	// Update all of my dependencies
 	for _, dep := range deps {
-		ta.update(dep)
+		ta.update(ctx, dep)
 
 		depID := dep.ID()
 		key := depID.Key()
@@ -419,7 +405,7 @@ func (ta *Topological) update(vtx Vertex) {
 	case acceptable:
 		// I'm acceptable, why not accept?
 		ta.ctx.ConsensusDispatcher.Accept(ta.ctx.ChainID, vtxID, vtx.Bytes())
-		vtx.Accept()
+		vtx.Accept(ctx)
 		ta.numAccepted.Inc()
 		delete(ta.nodes, vtxKey)
 		ta.numProcessing.Dec()
@@ -436,7 +422,7 @@ func (ta *Topological) update(vtx Vertex) {
 }
 
 // Update the frontier sets
-func (ta *Topological) updateFrontiers() {
+func (ta *Topological) updateFrontiers(ctx context.Context) {
 	vts := ta.frontier
 
 	ta.preferred.Clear()
@@ -450,6 +436,6 @@ func (ta *Topological) updateFrontiers() {
 
 	for _, vtx := range vts {
 		// Update all the vertices that were in my previous frontier
-		ta.update(vtx)
+		ta.update(ctx, vtx)
 	}
 }
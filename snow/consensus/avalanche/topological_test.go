@@ -4,6 +4,7 @@
 package avalanche
 
 import (
+	"context"
 	"math"
 	"testing"
 
@@ -76,13 +77,13 @@ func TestAvalancheVoting(t *testing.T) {
 		status:       choices.Processing,
 	}
 
-	ta.Add(vtx0)
-	ta.Add(vtx1)
+	ta.Add(context.Background(), vtx0)
+	ta.Add(context.Background(), vtx1)
 
 	sm := make(ids.UniqueBag)
 	sm.Add(0, vtx1.id)
 	sm.Add(1, vtx1.id)
-	ta.RecordPoll(sm)
+	ta.RecordPoll(context.Background(), sm)
 
 	if ta.Finalized() {
 		t.Fatalf("An avalanche instance finalized too early")
@@ -90,7 +91,7 @@ func TestAvalancheVoting(t *testing.T) {
 		t.Fatalf("Initial frontier failed to be set")
 	}
 
-	ta.RecordPoll(sm)
+	ta.RecordPoll(context.Background(), sm)
 
 	if !ta.Finalized() {
 		t.Fatalf("An avalanche instance finalized too late")
@@ -163,14 +164,14 @@ func TestAvalancheTransitiveVoting(t *testing.T) {
 		status:       choices.Processing,
 	}
 
-	ta.Add(vtx0)
-	ta.Add(vtx1)
-	ta.Add(vtx2)
+	ta.Add(context.Background(), vtx0)
+	ta.Add(context.Background(), vtx1)
+	ta.Add(context.Background(), vtx2)
 
 	sm1 := make(ids.UniqueBag)
 	sm1.Add(0, vtx0.id)
 	sm1.Add(1, vtx2.id)
-	ta.RecordPoll(sm1)
+	ta.RecordPoll(context.Background(), sm1)
 
 	if ta.Finalized() {
 		t.Fatalf("An avalanche instance finalized too early")
@@ -183,7 +184,7 @@ func TestAvalancheTransitiveVoting(t *testing.T) {
 	sm2 := make(ids.UniqueBag)
 	sm2.Add(0, vtx2.id)
 	sm2.Add(1, vtx2.id)
-	ta.RecordPoll(sm2)
+	ta.RecordPoll(context.Background(), sm2)
 
 	if !ta.Finalized() {
 		t.Fatalf("An avalanche instance finalized too late")
@@ -242,13 +243,13 @@ func TestAvalancheSplitVoting(t *testing.T) {
 		status:       choices.Processing,
 	}
 
-	ta.Add(vtx0)
-	ta.Add(vtx1)
+	ta.Add(context.Background(), vtx0)
+	ta.Add(context.Background(), vtx1)
 
 	sm1 := make(ids.UniqueBag)
 	sm1.Add(0, vtx0.id)
 	sm1.Add(1, vtx1.id)
-	ta.RecordPoll(sm1)
+	ta.RecordPoll(context.Background(), sm1)
 
 	if !ta.Finalized() {
 		t.Fatalf("An avalanche instance finalized too late")
@@ -325,14 +326,14 @@ func TestAvalancheTransitiveRejection(t *testing.T) {
 		status:       choices.Processing,
 	}
 
-	ta.Add(vtx0)
-	ta.Add(vtx1)
-	ta.Add(vtx2)
+	ta.Add(context.Background(), vtx0)
+	ta.Add(context.Background(), vtx1)
+	ta.Add(context.Background(), vtx2)
 
 	sm := make(ids.UniqueBag)
 	sm.Add(0, vtx1.id)
 	sm.Add(1, vtx1.id)
-	ta.RecordPoll(sm)
+	ta.RecordPoll(context.Background(), sm)
 
 	if ta.Finalized() {
 		t.Fatalf("An avalanche instance finalized too early")
@@ -340,7 +341,7 @@ func TestAvalancheTransitiveRejection(t *testing.T) {
 		t.Fatalf("Initial frontier failed to be set")
 	}
 
-	ta.RecordPoll(sm)
+	ta.RecordPoll(context.Background(), sm)
 
 	if ta.Finalized() {
 		t.Fatalf("An avalanche instance finalized too early")
@@ -357,7 +358,7 @@ func TestAvalancheTransitiveRejection(t *testing.T) {
 	ta.preferenceCache = make(map[[32]byte]bool)
 	ta.virtuousCache = make(map[[32]byte]bool)
 
-	ta.update(vtx2)
+	ta.update(context.Background(), vtx2)
 }
 
 func TestAvalancheVirtuous(t *testing.T) {
@@ -434,7 +435,7 @@ func TestAvalancheVirtuous(t *testing.T) {
 		status:       choices.Processing,
 	}
 
-	ta.Add(vtx0)
+	ta.Add(context.Background(), vtx0)
 
 	if virtuous := ta.Virtuous(); virtuous.Len() != 1 {
 		t.Fatalf("Wrong number of virtuous.")
@@ -442,7 +443,7 @@ func TestAvalancheVirtuous(t *testing.T) {
 		t.Fatalf("Wrong virtuous")
 	}
 
-	ta.Add(vtx1)
+	ta.Add(context.Background(), vtx1)
 
 	if virtuous := ta.Virtuous(); virtuous.Len() != 1 {
 		t.Fatalf("Wrong number of virtuous.")
@@ -450,7 +451,7 @@ func TestAvalancheVirtuous(t *testing.T) {
 		t.Fatalf("Wrong virtuous")
 	}
 
-	ta.updateFrontiers()
+	ta.updateFrontiers(context.Background())
 
 	if virtuous := ta.Virtuous(); virtuous.Len() != 2 {
 		t.Fatalf("Wrong number of virtuous.")
@@ -460,7 +461,7 @@ func TestAvalancheVirtuous(t *testing.T) {
 		t.Fatalf("Wrong virtuous")
 	}
 
-	ta.Add(vtx2)
+	ta.Add(context.Background(), vtx2)
 
 	if virtuous := ta.Virtuous(); virtuous.Len() != 2 {
 		t.Fatalf("Wrong number of virtuous.")
@@ -470,7 +471,7 @@ func TestAvalancheVirtuous(t *testing.T) {
 		t.Fatalf("Wrong virtuous")
 	}
 
-	ta.updateFrontiers()
+	ta.updateFrontiers(context.Background())
 
 	if virtuous := ta.Virtuous(); virtuous.Len() != 2 {
 		t.Fatalf("Wrong number of virtuous.")
@@ -547,7 +548,7 @@ func TestAvalancheIsVirtuous(t *testing.T) {
 		t.Fatalf("Should be virtuous.")
 	}
 
-	ta.Add(vtx0)
+	ta.Add(context.Background(), vtx0)
 
 	if !ta.IsVirtuous(tx0) {
 		t.Fatalf("Should be virtuous.")
@@ -555,7 +556,7 @@ func TestAvalancheIsVirtuous(t *testing.T) {
 		t.Fatalf("Should not be virtuous.")
 	}
 
-	ta.Add(vtx1)
+	ta.Add(context.Background(), vtx1)
 
 	if ta.IsVirtuous(tx0) {
 		t.Fatalf("Should not be virtuous.")
@@ -630,19 +631,19 @@ func TestAvalancheQuiesce(t *testing.T) {
 		status:       choices.Processing,
 	}
 
-	ta.Add(vtx0)
+	ta.Add(context.Background(), vtx0)
 
 	if ta.Quiesce() {
 		t.Fatalf("Shouldn't quiesce")
 	}
 
-	ta.Add(vtx1)
+	ta.Add(context.Background(), vtx1)
 
 	if !ta.Quiesce() {
 		t.Fatalf("Should quiesce")
 	}
 
-	ta.Add(vtx2)
+	ta.Add(context.Background(), vtx2)
 
 	if ta.Quiesce() {
 		t.Fatalf("Shouldn't quiesce")
@@ -650,7 +651,7 @@ func TestAvalancheQuiesce(t *testing.T) {
 
 	sm := make(ids.UniqueBag)
 	sm.Add(0, vtx2.id)
-	ta.RecordPoll(sm)
+	ta.RecordPoll(context.Background(), sm)
 
 	if !ta.Quiesce() {
 		t.Fatalf("Should quiesce")
@@ -723,19 +724,19 @@ func TestAvalancheOrphans(t *testing.T) {
 		status:       choices.Processing,
 	}
 
-	ta.Add(vtx0)
+	ta.Add(context.Background(), vtx0)
 
 	if orphans := ta.Orphans(); orphans.Len() != 0 {
 		t.Fatalf("Wrong number of orphans")
 	}
 
-	ta.Add(vtx1)
+	ta.Add(context.Background(), vtx1)
 
 	if orphans := ta.Orphans(); orphans.Len() != 0 {
 		t.Fatalf("Wrong number of orphans")
 	}
 
-	ta.Add(vtx2)
+	ta.Add(context.Background(), vtx2)
 
 	if orphans := ta.Orphans(); orphans.Len() != 0 {
 		t.Fatalf("Wrong number of orphans")
@@ -743,7 +744,7 @@ func TestAvalancheOrphans(t *testing.T) {
 
 	sm := make(ids.UniqueBag)
 	sm.Add(0, vtx1.id)
-	ta.RecordPoll(sm)
+	ta.RecordPoll(context.Background(), sm)
 
 	if orphans := ta.Orphans(); orphans.Len() != 1 {
 		t.Fatalf("Wrong number of orphans")
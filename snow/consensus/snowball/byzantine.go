@@ -30,6 +30,9 @@ func (b *Byzantine) Initialize(params Parameters, choice ids.ID) {
 // Parameters implements the Consensus interface
 func (b *Byzantine) Parameters() Parameters { return b.params }
 
+// SetParameters implements the Consensus interface
+func (b *Byzantine) SetParameters(params Parameters) { b.params = params }
+
 // Add implements the Consensus interface
 func (b *Byzantine) Add(choice ids.ID) {}
 
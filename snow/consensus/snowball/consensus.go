@@ -20,6 +20,11 @@ type Consensus interface {
 	// Returns the parameters that describe this snowball instance
 	Parameters() Parameters
 
+	// Replaces the parameters of this snowball instance. Alpha applies to the
+	// next poll. The betas only apply to choices added afterwards. Assumes the
+	// parameters are valid.
+	SetParameters(params Parameters)
+
 	// Adds a new choice to vote on
 	Add(newChoice ids.ID)
 
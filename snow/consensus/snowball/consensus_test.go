@@ -35,4 +35,14 @@ func ParamsTest(t *testing.T, factory Factory) {
 	} else if p.BetaRogue != params.BetaRogue {
 		t.Fatalf("Wrong Beta2 parameter")
 	}
+
+	params.K = 3
+	params.Alpha = 3
+	sb.SetParameters(params)
+
+	if p := sb.Parameters(); p.K != params.K {
+		t.Fatalf("Wrong K parameter after setting the parameters")
+	} else if p.Alpha != params.Alpha {
+		t.Fatalf("Wrong Alpha parameter after setting the parameters")
+	}
 }
@@ -31,6 +31,9 @@ func (f *Flat) Initialize(params Parameters, choice ids.ID) {
 // Parameters implements the Consensus interface
 func (f *Flat) Parameters() Parameters { return f.params }
 
+// SetParameters implements the Consensus interface
+func (f *Flat) SetParameters(params Parameters) { f.params = params }
+
 // Add implements the Consensus interface
 func (f *Flat) Add(choice ids.ID) { f.snowball.Add(choice) }
 
@@ -11,6 +11,8 @@ import (
 
 // Parameters required for snowball consensus
 type Parameters struct {
+	// Namespace is the alias of the chain that the metrics of the consensus
+	// instance are labelled with
 	Namespace                         string
 	Metrics                           prometheus.Registerer
 	K, Alpha, BetaVirtuous, BetaRogue int
@@ -55,6 +55,9 @@ func (t *Tree) Initialize(params Parameters, choice ids.ID) {
 // Parameters implements the Consensus interface
 func (t *Tree) Parameters() Parameters { return t.params }
 
+// SetParameters implements the Consensus interface
+func (t *Tree) SetParameters(params Parameters) { t.params = params }
+
 // Add implements the Consensus interface
 func (t *Tree) Add(choice ids.ID) {
 	prefix := t.root.DecidedPrefix()
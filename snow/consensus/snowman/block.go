@@ -4,6 +4,8 @@
 package snowman
 
 import (
+	"context"
+
 	"github.com/ava-labs/gecko/snow/choices"
 )
 
@@ -31,7 +33,11 @@ type Block interface {
 	// returned.
 	//
 	// It is guaranteed that the Parent has been successfully verified.
-	Verify() error
+	//
+	// [ctx] is the context of the request whose handling caused the
+	// verification. If [ctx] is done, verification may be abandoned by
+	// returning an error.
+	Verify(ctx context.Context) error
 
 	// Bytes returns the binary representation of this block.
 	//
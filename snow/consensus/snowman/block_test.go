@@ -4,6 +4,7 @@
 package snowman
 
 import (
+	"context"
 	"sort"
 
 	"github.com/ava-labs/gecko/ids"
@@ -21,7 +22,7 @@ type Blk struct {
 func (b *Blk) Parent() Block          { return b.parent }
 func (b *Blk) ID() ids.ID             { return b.id }
 func (b *Blk) Status() choices.Status { return b.status }
-func (b *Blk) Accept() {
+func (b *Blk) Accept(context.Context) {
 	if b.status.Decided() && b.status != choices.Accepted {
 		panic("Dis-agreement")
 	}
@@ -33,8 +34,8 @@ func (b *Blk) Reject() {
 	}
 	b.status = choices.Rejected
 }
-func (b *Blk) Verify() error { return nil }
-func (b *Blk) Bytes() []byte { return b.bytes }
+func (b *Blk) Verify(context.Context) error { return nil }
+func (b *Blk) Bytes() []byte                { return b.bytes }
 
 type sortBlks []*Blk
 
@@ -4,6 +4,8 @@
 package snowman
 
 import (
+	"context"
+
 	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/snow"
 	"github.com/ava-labs/gecko/snow/consensus/snowball"
@@ -18,6 +20,11 @@ type Consensus interface {
 	// Returns the parameters that describe this snowman instance
 	Parameters() snowball.Parameters
 
+	// Replaces the parameters of this snowman instance. Alpha applies to the
+	// next poll. The betas only apply to blocks whose siblings are added
+	// afterwards. Assumes the parameters are valid.
+	SetParameters(snowball.Parameters)
+
 	// Adds a new decision. Assumes the dependency has already been added.
 	Add(Block)
 
@@ -29,11 +36,17 @@ type Consensus interface {
 	Preference() ids.ID
 
 	// RecordPoll collects the results of a network poll. Assumes all decisions
-	// have been previously added.
-	RecordPoll(ids.Bag)
+	// have been previously added. [ctx] is passed to the blocks the poll
+	// accepts.
+	RecordPoll(context.Context, ids.Bag)
 
 	// Finalized returns true if all decisions that have been added have been
 	// finalized. Note, it is possible that after returning finalized, a new
 	// decision may be added such that this instance is no longer finalized.
 	Finalized() bool
+
+	// Confidence returns the number of consecutive successful polls for the
+	// processing block [blkID] and the number of consecutive successful polls
+	// that finalize it. Returns false if the block isn't processing.
+	Confidence(blkID ids.ID) (confidence int, beta int, processing bool)
 }
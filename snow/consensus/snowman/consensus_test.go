@@ -4,6 +4,7 @@
 package snowman
 
 import (
+	"context"
 	"fmt"
 	"math/rand"
 	"testing"
@@ -21,24 +22,24 @@ func ParamsTest(t *testing.T, factory Factory) {
 
 	ctx := snow.DefaultContextTest()
 	params := snowball.Parameters{
-		Namespace: fmt.Sprintf("gecko_%s", ctx.ChainID),
+		Namespace: ctx.ChainID.String(),
 		Metrics:   prometheus.NewRegistry(),
 		K:         1, Alpha: 1, BetaVirtuous: 3, BetaRogue: 5,
 	}
 
 	numProcessing := prometheus.NewGauge(
 		prometheus.GaugeOpts{
-			Namespace: params.Namespace,
+			Namespace: fmt.Sprintf("gecko_%s", params.Namespace),
 			Name:      "processing",
 		})
 	numAccepted := prometheus.NewCounter(
 		prometheus.CounterOpts{
-			Namespace: params.Namespace,
+			Namespace: fmt.Sprintf("gecko_%s", params.Namespace),
 			Name:      "accepted",
 		})
 	numRejected := prometheus.NewCounter(
 		prometheus.CounterOpts{
-			Namespace: params.Namespace,
+			Namespace: fmt.Sprintf("gecko_%s", params.Namespace),
 			Name:      "rejected",
 		})
 
@@ -152,7 +153,7 @@ func CollectTest(t *testing.T, factory Factory) {
 
 	dep2_2 := ids.Bag{}
 	dep2_2.AddCount(dep2.id, 2)
-	sm.RecordPoll(dep2_2)
+	sm.RecordPoll(context.Background(), dep2_2)
 
 	// Current graph structure:
 	//       G
@@ -170,7 +171,7 @@ func CollectTest(t *testing.T, factory Factory) {
 
 	dep3_2 := ids.Bag{}
 	dep3_2.AddCount(dep3.id, 2)
-	sm.RecordPoll(dep3_2)
+	sm.RecordPoll(context.Background(), dep3_2)
 
 	// Current graph structure:
 	//     0
@@ -184,7 +185,7 @@ func CollectTest(t *testing.T, factory Factory) {
 		t.Fatalf("Wrong preference listed")
 	}
 
-	sm.RecordPoll(dep2_2)
+	sm.RecordPoll(context.Background(), dep2_2)
 
 	// Current graph structure:
 	//     0
@@ -198,7 +199,7 @@ func CollectTest(t *testing.T, factory Factory) {
 		t.Fatalf("Wrong preference listed")
 	}
 
-	sm.RecordPoll(dep2_2)
+	sm.RecordPoll(context.Background(), dep2_2)
 
 	// Current graph structure:
 	//   2
@@ -236,7 +237,7 @@ func CollectNothingTest(t *testing.T, factory Factory) {
 
 	genesis1 := ids.Bag{}
 	genesis1.AddCount(Genesis.ID(), 1)
-	sm.RecordPoll(genesis1)
+	sm.RecordPoll(context.Background(), genesis1)
 
 	// Current graph structure:
 	//       G
@@ -286,8 +287,8 @@ func CollectTransRejectTest(t *testing.T, factory Factory) {
 
 	dep1_1 := ids.Bag{}
 	dep1_1.AddCount(dep1.id, 1)
-	sm.RecordPoll(dep1_1)
-	sm.RecordPoll(dep1_1)
+	sm.RecordPoll(context.Background(), dep1_1)
+	sm.RecordPoll(context.Background(), dep1_1)
 
 	// Current graph structure:
 	//       1
@@ -348,7 +349,7 @@ func CollectTransResetTest(t *testing.T, factory Factory) {
 
 	dep1_1 := ids.Bag{}
 	dep1_1.AddCount(dep1.id, 1)
-	sm.RecordPoll(dep1_1)
+	sm.RecordPoll(context.Background(), dep1_1)
 
 	// Current graph structure:
 	//       G
@@ -360,7 +361,7 @@ func CollectTransResetTest(t *testing.T, factory Factory) {
 
 	dep2_1 := ids.Bag{}
 	dep2_1.AddCount(dep2.id, 1)
-	sm.RecordPoll(dep2_1)
+	sm.RecordPoll(context.Background(), dep2_1)
 
 	if sm.Finalized() {
 		t.Fatalf("Finalized too early")
@@ -372,8 +373,8 @@ func CollectTransResetTest(t *testing.T, factory Factory) {
 		t.Fatalf("Wrong preference listed")
 	}
 
-	sm.RecordPoll(dep2_1)
-	sm.RecordPoll(dep2_1)
+	sm.RecordPoll(context.Background(), dep2_1)
+	sm.RecordPoll(context.Background(), dep2_1)
 
 	if !sm.Finalized() {
 		t.Fatalf("Finalized too late")
@@ -439,7 +440,7 @@ func CollectTransVoteTest(t *testing.T, factory Factory) {
 	dep0_2_4_1.AddCount(dep0.id, 1)
 	dep0_2_4_1.AddCount(dep2.id, 1)
 	dep0_2_4_1.AddCount(dep4.id, 1)
-	sm.RecordPoll(dep0_2_4_1)
+	sm.RecordPoll(context.Background(), dep0_2_4_1)
 
 	// Current graph structure:
 	//     0
@@ -455,7 +456,7 @@ func CollectTransVoteTest(t *testing.T, factory Factory) {
 
 	dep2_3 := ids.Bag{}
 	dep2_3.AddCount(dep2.id, 3)
-	sm.RecordPoll(dep2_3)
+	sm.RecordPoll(context.Background(), dep2_3)
 
 	// Current graph structure:
 	//   2
@@ -503,7 +504,7 @@ func DivergedVotingTest(t *testing.T, factory Factory) {
 
 	dep0_1 := ids.Bag{}
 	dep0_1.AddCount(dep0.id, 1)
-	sm.RecordPoll(dep0_1)
+	sm.RecordPoll(context.Background(), dep0_1)
 
 	dep2 := &Blk{
 		parent: Genesis,
@@ -527,7 +528,7 @@ func DivergedVotingTest(t *testing.T, factory Factory) {
 	// dep0. Because dep2 is already rejected, this will accept dep0.
 	dep3_1 := ids.Bag{}
 	dep3_1.AddCount(dep3.id, 1)
-	sm.RecordPoll(dep3_1)
+	sm.RecordPoll(context.Background(), dep3_1)
 
 	if !sm.Finalized() {
 		t.Fatalf("Finalized too late")
@@ -578,24 +579,24 @@ func MetricsErrorTest(t *testing.T, factory Factory) {
 
 	ctx := snow.DefaultContextTest()
 	params := snowball.Parameters{
-		Namespace: fmt.Sprintf("gecko_%s", ctx.ChainID),
+		Namespace: ctx.ChainID.String(),
 		Metrics:   prometheus.NewRegistry(),
 		K:         1, Alpha: 1, BetaVirtuous: 1, BetaRogue: 2,
 	}
 
 	numProcessing := prometheus.NewGauge(
 		prometheus.GaugeOpts{
-			Namespace: params.Namespace,
+			Namespace: fmt.Sprintf("gecko_%s", params.Namespace),
 			Name:      "processing",
 		})
 	numAccepted := prometheus.NewCounter(
 		prometheus.CounterOpts{
-			Namespace: params.Namespace,
+			Namespace: fmt.Sprintf("gecko_%s", params.Namespace),
 			Name:      "accepted",
 		})
 	numRejected := prometheus.NewCounter(
 		prometheus.CounterOpts{
-			Namespace: params.Namespace,
+			Namespace: fmt.Sprintf("gecko_%s", params.Namespace),
 			Name:      "rejected",
 		})
 
@@ -641,3 +642,134 @@ func ConsistentTest(t *testing.T, factory Factory) {
 		t.Fatalf("Network agreed on inconsistent values")
 	}
 }
+
+func ConfidenceTest(t *testing.T, factory Factory) {
+	sm := factory.New()
+
+	params := snowball.Parameters{
+		Metrics: prometheus.NewRegistry(),
+		K:       1, Alpha: 1, BetaVirtuous: 3, BetaRogue: 5,
+	}
+	sm.Initialize(snow.DefaultContextTest(), params, Genesis.ID())
+
+	dep0 := &Blk{
+		parent: Genesis,
+		id:     ids.Empty.Prefix(1),
+	}
+	sm.Add(dep0)
+
+	dep1 := &Blk{
+		parent: Genesis,
+		id:     ids.Empty.Prefix(2),
+	}
+	sm.Add(dep1)
+
+	dep2 := &Blk{
+		parent: dep0,
+		id:     ids.Empty.Prefix(3),
+	}
+	sm.Add(dep2)
+
+	// Current graph structure:
+	//       G
+	//      / \
+	//     0   1
+	//     |
+	//     2
+
+	if _, _, processing := sm.Confidence(Genesis.ID()); processing {
+		t.Fatalf("Genesis should not be processing")
+	} else if _, _, processing := sm.Confidence(ids.Empty.Prefix(4)); processing {
+		t.Fatalf("Unknown block should not be processing")
+	}
+
+	if confidence, beta, processing := sm.Confidence(dep0.id); !processing {
+		t.Fatalf("Block should be processing")
+	} else if confidence != 0 {
+		t.Fatalf("Wrong confidence. Expected 0, got %d", confidence)
+	} else if beta != params.BetaRogue {
+		t.Fatalf("Wrong beta. Expected %d, got %d", params.BetaRogue, beta)
+	}
+
+	if confidence, beta, processing := sm.Confidence(dep2.id); !processing {
+		t.Fatalf("Block should be processing")
+	} else if confidence != 0 {
+		t.Fatalf("Wrong confidence. Expected 0, got %d", confidence)
+	} else if beta != params.BetaVirtuous {
+		t.Fatalf("Wrong beta. Expected %d, got %d", params.BetaVirtuous, beta)
+	}
+
+	dep2_1 := ids.Bag{}
+	dep2_1.Add(dep2.id)
+	sm.RecordPoll(context.Background(), dep2_1)
+	sm.RecordPoll(context.Background(), dep2_1)
+
+	if confidence, _, _ := sm.Confidence(dep0.id); confidence != 2 {
+		t.Fatalf("Wrong confidence. Expected 2, got %d", confidence)
+	} else if confidence, _, _ := sm.Confidence(dep2.id); confidence != 2 {
+		t.Fatalf("Wrong confidence. Expected 2, got %d", confidence)
+	} else if confidence, _, _ := sm.Confidence(dep1.id); confidence != 0 {
+		t.Fatalf("Wrong confidence. Expected 0, got %d", confidence)
+	}
+
+	dep1_1 := ids.Bag{}
+	dep1_1.Add(dep1.id)
+	sm.RecordPoll(context.Background(), dep1_1)
+
+	// A poll for a conflicting block resets the confidence of the branch
+
+	if confidence, _, _ := sm.Confidence(dep1.id); confidence != 1 {
+		t.Fatalf("Wrong confidence. Expected 1, got %d", confidence)
+	} else if confidence, _, _ := sm.Confidence(dep0.id); confidence != 0 {
+		t.Fatalf("Wrong confidence. Expected 0, got %d", confidence)
+	} else if confidence, _, _ := sm.Confidence(dep2.id); confidence != 0 {
+		t.Fatalf("Wrong confidence. Expected 0, got %d", confidence)
+	}
+
+	sm.RecordPoll(context.Background(), ids.Bag{})
+
+	// An unsuccessful poll resets the confidence of every block
+
+	if confidence, _, _ := sm.Confidence(dep1.id); confidence != 0 {
+		t.Fatalf("Wrong confidence. Expected 0, got %d", confidence)
+	}
+}
+
+func SetParametersTest(t *testing.T, factory Factory) {
+	sm := factory.New()
+
+	params := snowball.Parameters{
+		Metrics: prometheus.NewRegistry(),
+		K:       2, Alpha: 2, BetaVirtuous: 1, BetaRogue: 1,
+	}
+	sm.Initialize(snow.DefaultContextTest(), params, Genesis.ID())
+
+	dep0 := &Blk{
+		parent: Genesis,
+		id:     ids.Empty.Prefix(1),
+		status: choices.Processing,
+	}
+	sm.Add(dep0)
+
+	dep0_1 := ids.Bag{}
+	dep0_1.Add(dep0.id)
+	sm.RecordPoll(context.Background(), dep0_1)
+
+	if status := dep0.Status(); status != choices.Processing {
+		t.Fatalf("Shouldn't have accepted the block with fewer than alpha votes")
+	}
+
+	params.K = 1
+	params.Alpha = 1
+	sm.SetParameters(params)
+
+	if p := sm.Parameters(); p.K != 1 || p.Alpha != 1 {
+		t.Fatalf("Wrong parameters. Expected K = 1, Alpha = 1, got K = %d, Alpha = %d", p.K, p.Alpha)
+	}
+
+	sm.RecordPoll(context.Background(), dep0_1)
+
+	if status := dep0.Status(); status != choices.Accepted {
+		t.Fatalf("Should have accepted the block with the lowered alpha, but its status is %s", status)
+	}
+}
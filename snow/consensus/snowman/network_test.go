@@ -4,6 +4,7 @@
 package snowman
 
 import (
+	"context"
 	"math"
 
 	"github.com/ava-labs/gecko/ids"
@@ -89,7 +90,7 @@ func (n *Network) Round() {
 			}
 		}
 
-		running.RecordPoll(sampledColors)
+		running.RecordPoll(context.Background(), sampledColors)
 
 		// If this node has been finalized, remove it from the poller
 		if running.Finalized() {
@@ -4,11 +4,14 @@
 package snowman
 
 import (
+	"context"
+
 	"github.com/prometheus/client_golang/prometheus"
 
 	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/snow"
 	"github.com/ava-labs/gecko/snow/consensus/snowball"
+	"github.com/ava-labs/gecko/utils/metrics"
 )
 
 // TopologicalFactory implements Factory by returning a topological struct
@@ -41,6 +44,10 @@ type node struct {
 	shouldFalter bool
 	sb           snowball.Consensus
 	children     map[[32]byte]Block
+
+	// confidence is the number of consecutive successful polls for this block
+	// in its parent's snowball instance
+	confidence int
 }
 
 // Used to track the kahn topological sort status
@@ -62,34 +69,10 @@ func (ts *Topological) Initialize(ctx *snow.Context, params snowball.Parameters,
 	ts.ctx = ctx
 	ts.params = params
 
-	ts.numProcessing = prometheus.NewGauge(
-		prometheus.GaugeOpts{
-			Namespace: params.Namespace,
-			Name:      "processing",
-			Help:      "Number of currently processing blocks",
-		})
-	ts.numAccepted = prometheus.NewCounter(
-		prometheus.CounterOpts{
-			Namespace: params.Namespace,
-			Name:      "accepted",
-			Help:      "Number of blocks accepted",
-		})
-	ts.numRejected = prometheus.NewCounter(
-		prometheus.CounterOpts{
-			Namespace: params.Namespace,
-			Name:      "rejected",
-			Help:      "Number of blocks rejected",
-		})
-
-	if err := ts.params.Metrics.Register(ts.numProcessing); err != nil {
-		ts.ctx.Log.Error("Failed to register processing statistics due to %s", err)
-	}
-	if err := ts.params.Metrics.Register(ts.numAccepted); err != nil {
-		ts.ctx.Log.Error("Failed to register accepted statistics due to %s", err)
-	}
-	if err := ts.params.Metrics.Register(ts.numRejected); err != nil {
-		ts.ctx.Log.Error("Failed to register rejected statistics due to %s", err)
-	}
+	r := metrics.NewChainRegisterer(ctx.Log, params.Metrics, params.Namespace, "consensus")
+	ts.numProcessing = r.NewGauge("processing", "Number of currently processing blocks")
+	ts.numAccepted = r.NewCounter("accepted", "Number of blocks accepted")
+	ts.numRejected = r.NewCounter("rejected", "Number of blocks rejected")
 
 	ts.head = rootID
 	ts.nodes = map[[32]byte]node{
@@ -104,6 +87,16 @@ func (ts *Topological) Initialize(ctx *snow.Context, params snowball.Parameters,
 // Parameters implements the Snowman interface
 func (ts *Topological) Parameters() snowball.Parameters { return ts.params }
 
+// SetParameters implements the Snowman interface
+func (ts *Topological) SetParameters(params snowball.Parameters) {
+	ts.params = params
+	for _, n := range ts.nodes {
+		if n.sb != nil {
+			n.sb.SetParameters(params)
+		}
+	}
+}
+
 // Add implements the Snowman interface
 func (ts *Topological) Add(blk Block) {
 	parent := blk.Parent()
@@ -167,7 +160,7 @@ func (ts *Topological) Preference() ids.ID { return ts.tail }
 // The complexity of this function is:
 // Runtime = 3 * |live set| + |votes|
 // Space = |live set| + |votes|
-func (ts *Topological) RecordPoll(votes ids.Bag) {
+func (ts *Topological) RecordPoll(ctx context.Context, votes ids.Bag) {
 	// Runtime = |live set| + |votes| ; Space = |live set| + |votes|
 	kahnGraph, leaves := ts.calculateInDegree(votes)
 
@@ -175,7 +168,7 @@ func (ts *Topological) RecordPoll(votes ids.Bag) {
 	voteStack := ts.pushVotes(kahnGraph, leaves)
 
 	// Runtime = |live set| ; Space = Constant
-	tail := ts.vote(voteStack)
+	tail := ts.vote(ctx, voteStack)
 	tn := node{}
 	for tn = ts.nodes[tail.Key()]; tn.sb != nil; tn = ts.nodes[tail.Key()] {
 		tail = tn.sb.Preference()
@@ -187,6 +180,22 @@ func (ts *Topological) RecordPoll(votes ids.Bag) {
 // Finalized implements the Snowman interface
 func (ts *Topological) Finalized() bool { return len(ts.nodes) == 1 }
 
+// Confidence implements the Snowman interface
+func (ts *Topological) Confidence(blkID ids.ID) (int, int, bool) {
+	n, ok := ts.nodes[blkID.Key()]
+	if !ok || n.blk == nil || n.blk.Status().Decided() {
+		return 0, 0, false
+	}
+
+	// A block without conflicting siblings is finalized after BetaVirtuous
+	// successful polls, otherwise BetaRogue are needed
+	beta := ts.params.BetaVirtuous
+	if parent, ok := ts.nodes[n.blk.Parent().ID().Key()]; ok && len(parent.children) > 1 {
+		beta = ts.params.BetaRogue
+	}
+	return n.confidence, beta, true
+}
+
 // takes in a list of votes and sets up the topological ordering. Returns the
 // reachable section of the graph annotated with the number of inbound edges and
 // the non-transitively applied votes. Also returns the list of leaf nodes.
@@ -281,7 +290,7 @@ func (ts *Topological) pushVotes(
 	return voteStack
 }
 
-func (ts *Topological) vote(voteStack []votes) ids.ID {
+func (ts *Topological) vote(ctx context.Context, voteStack []votes) ids.ID {
 	if len(voteStack) == 0 {
 		headKey := ts.head.Key()
 		headNode := ts.nodes[headKey]
@@ -290,6 +299,7 @@ func (ts *Topological) vote(voteStack []votes) ids.ID {
 		ts.ctx.Log.Verbo("No progress was made on this vote even though we have %d nodes", len(ts.nodes))
 
 		ts.nodes[headKey] = headNode
+		ts.resetConfidence(headNode)
 		return ts.tail
 	}
 
@@ -313,10 +323,11 @@ func (ts *Topological) vote(voteStack []votes) ids.ID {
 			ts.ctx.Log.Verbo("Reset confidence on %s", parentNode.blkID)
 		}
 		parentNode.sb.RecordPoll(voteGroup.votes)
+		ts.recordConfidence(parentNode, voteGroup.votes)
 
 		// Only accept when you are finalized and the head.
 		if parentNode.sb.Finalized() && ts.head.Equals(voteGroup.id) {
-			ts.accept(parentNode)
+			ts.accept(ctx, parentNode)
 			tail = parentNode.sb.Preference()
 			delete(ts.nodes, voteParentKey)
 			ts.numProcessing.Dec()
@@ -347,6 +358,7 @@ func (ts *Topological) vote(voteStack []votes) ids.ID {
 					ts.ctx.Log.Verbo("Defering confidence reset on %s with %d children. NextID: %s", childID, len(parentNode.children), nextID)
 					childNode.shouldFalter = true
 					ts.nodes[childIDBytes] = childNode
+					ts.resetConfidence(childNode)
 				}
 			}
 		}
@@ -354,7 +366,7 @@ func (ts *Topological) vote(voteStack []votes) ids.ID {
 	return tail
 }
 
-func (ts *Topological) accept(n node) {
+func (ts *Topological) accept(ctx context.Context, n node) {
 	// Accept the preference, reject all transitive rejections
 	pref := n.sb.Preference()
 
@@ -382,7 +394,7 @@ func (ts *Topological) accept(n node) {
 	ts.ctx.DecisionDispatcher.Accept(ts.ctx.ChainID, child.ID(), bytes)
 	ts.ctx.ConsensusDispatcher.Accept(ts.ctx.ChainID, child.ID(), bytes)
 
-	child.Accept()
+	child.Accept(ctx)
 	ts.numAccepted.Inc()
 }
 
@@ -413,6 +425,36 @@ func (ts *Topological) rejectTransitively(rejected ...ids.ID) {
 	}
 }
 
+// recordConfidence updates the confidence of the children of [parent] after a
+// poll that gave them [votes]. The child that received an alpha majority gains
+// confidence, every other child loses all of its confidence.
+func (ts *Topological) recordConfidence(parent node, votes ids.Bag) {
+	choice, count := votes.Mode()
+	for childIDBytes := range parent.children {
+		child, ok := ts.nodes[childIDBytes]
+		if !ok {
+			continue
+		}
+		if count >= ts.params.Alpha && choice.Key() == childIDBytes {
+			child.confidence++
+		} else {
+			child.confidence = 0
+		}
+		ts.nodes[childIDBytes] = child
+	}
+}
+
+// resetConfidence resets the confidence of the children of [parent], whose
+// snowball instance is going to be reset
+func (ts *Topological) resetConfidence(parent node) {
+	for childIDBytes := range parent.children {
+		if child, ok := ts.nodes[childIDBytes]; ok {
+			child.confidence = 0
+			ts.nodes[childIDBytes] = child
+		}
+	}
+}
+
 func (n *node) Add(child Block) {
 	childID := child.ID()
 	if n.sb == nil {
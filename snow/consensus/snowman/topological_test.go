@@ -29,4 +29,8 @@ func TestTopologicalIssuedTest(t *testing.T) { IssuedTest(t, TopologicalFactory{
 
 func TestTopologicalMetricsError(t *testing.T) { MetricsErrorTest(t, TopologicalFactory{}) }
 
+func TestTopologicalSetParameters(t *testing.T) { SetParametersTest(t, TopologicalFactory{}) }
+
 func TestTopologicalConsistent(t *testing.T) { ConsistentTest(t, TopologicalFactory{}) }
+
+func TestTopologicalConfidence(t *testing.T) { ConfidenceTest(t, TopologicalFactory{}) }
@@ -4,8 +4,11 @@
 package snowstorm
 
 import (
+	"context"
 	"fmt"
 
+	"github.com/prometheus/client_golang/prometheus"
+
 	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/snow"
 	"github.com/ava-labs/gecko/snow/choices"
@@ -24,12 +27,18 @@ type Consensus interface {
 	// Returns the parameters that describe this snowstorm instance
 	Parameters() snowball.Parameters
 
+	// Replaces the parameters of this snowstorm instance. The new parameters
+	// apply to the next poll, including to the transactions already
+	// processing. Assumes the parameters are valid.
+	SetParameters(snowball.Parameters)
+
 	// Returns true if transaction <Tx> is virtuous.
 	// That is, no transaction has been added that conflicts with <Tx>
 	IsVirtuous(Tx) bool
 
-	// Adds a new transaction to vote on
-	Add(Tx)
+	// Adds a new transaction to vote on. [ctx] is passed to the transaction if
+	// it's accepted immediately.
+	Add(context.Context, Tx)
 
 	// Returns true iff transaction <Tx> has been added
 	Issued(Tx) bool
@@ -45,8 +54,9 @@ type Consensus interface {
 	Conflicts(Tx) ids.Set
 
 	// Collects the results of a network poll. Assumes all transactions
-	// have been previously added
-	RecordPoll(ids.Bag)
+	// have been previously added. [ctx] is passed to the transactions the poll
+	// accepts.
+	RecordPoll(context.Context, ids.Bag)
 
 	// Returns true iff all remaining transactions are rogue. Note, it is
 	// possible that after returning quiesce, a new decision may be added such
@@ -84,7 +94,11 @@ type Tx interface {
 	//
 	// It is guaranteed that when Verify is called, all the dependencies of
 	// this transaction have already been successfully verified.
-	Verify() error
+	//
+	// [ctx] is the context of the request whose handling caused the
+	// verification. If [ctx] is done, verification may be abandoned by
+	// returning an error.
+	Verify(ctx context.Context) error
 
 	// Bytes returns the binary representation of this transaction.
 	//
@@ -92,3 +106,28 @@ type Tx interface {
 	// able to parse these bytes to the same transaction.
 	Bytes() []byte
 }
+
+// RejectionCauseRecorder is an optional interface a Tx may implement to learn
+// why it was rejected.
+type RejectionCauseRecorder interface {
+	// RejectedBy is called immediately before Reject with the ID of the
+	// transaction that caused the rejection. This is either the accepted
+	// transaction that conflicted with this one, or a rejected transaction
+	// that this one depended on.
+	RejectedBy(causeID ids.ID)
+}
+
+// numConfidenceBuckets is the number of buckets the confidence of transactions
+// is counted in
+const numConfidenceBuckets = 16
+
+// confidenceBuckets returns the buckets the confidence of transactions is
+// counted in. The buckets span at least the confidence required to accept a
+// rogue transaction.
+func confidenceBuckets(params snowball.Parameters) []float64 {
+	if params.BetaRogue <= numConfidenceBuckets {
+		return prometheus.LinearBuckets(1, 1, numConfidenceBuckets)
+	}
+	width := float64(params.BetaRogue) / numConfidenceBuckets
+	return prometheus.LinearBuckets(width, width, numConfidenceBuckets)
+}
@@ -4,6 +4,7 @@
 package snowstorm
 
 import (
+	"context"
 	"testing"
 
 	"github.com/prometheus/client_golang/prometheus"
@@ -83,13 +84,13 @@ func IssuedTest(t *testing.T, factory Factory) {
 		t.Fatalf("Haven't issued anything yet.")
 	}
 
-	graph.Add(Red)
+	graph.Add(context.Background(), Red)
 
 	if issued := graph.Issued(Red); !issued {
 		t.Fatalf("Have already issued.")
 	}
 
-	Blue.Accept()
+	Blue.Accept(context.Background())
 
 	if issued := graph.Issued(Blue); !issued {
 		t.Fatalf("Have already accepted.")
@@ -106,8 +107,8 @@ func LeftoverInputTest(t *testing.T, factory Factory) {
 		K:       2, Alpha: 2, BetaVirtuous: 1, BetaRogue: 1,
 	}
 	graph.Initialize(snow.DefaultContextTest(), params)
-	graph.Add(Red)
-	graph.Add(Green)
+	graph.Add(context.Background(), Red)
+	graph.Add(context.Background(), Green)
 
 	if prefs := graph.Preferences(); prefs.Len() != 1 {
 		t.Fatalf("Wrong number of preferences.")
@@ -120,7 +121,7 @@ func LeftoverInputTest(t *testing.T, factory Factory) {
 	r := ids.Bag{}
 	r.SetThreshold(2)
 	r.AddCount(Red.ID(), 2)
-	graph.RecordPoll(r)
+	graph.RecordPoll(context.Background(), r)
 
 	if prefs := graph.Preferences(); prefs.Len() != 0 {
 		t.Fatalf("Wrong number of preferences.")
@@ -145,9 +146,9 @@ func LowerConfidenceTest(t *testing.T, factory Factory) {
 		K:       2, Alpha: 2, BetaVirtuous: 1, BetaRogue: 1,
 	}
 	graph.Initialize(snow.DefaultContextTest(), params)
-	graph.Add(Red)
-	graph.Add(Green)
-	graph.Add(Blue)
+	graph.Add(context.Background(), Red)
+	graph.Add(context.Background(), Green)
+	graph.Add(context.Background(), Blue)
 
 	if prefs := graph.Preferences(); prefs.Len() != 1 {
 		t.Fatalf("Wrong number of preferences.")
@@ -160,7 +161,7 @@ func LowerConfidenceTest(t *testing.T, factory Factory) {
 	r := ids.Bag{}
 	r.SetThreshold(2)
 	r.AddCount(Red.ID(), 2)
-	graph.RecordPoll(r)
+	graph.RecordPoll(context.Background(), r)
 
 	if prefs := graph.Preferences(); prefs.Len() != 1 {
 		t.Fatalf("Wrong number of preferences.")
@@ -181,10 +182,10 @@ func MiddleConfidenceTest(t *testing.T, factory Factory) {
 		K:       2, Alpha: 2, BetaVirtuous: 1, BetaRogue: 1,
 	}
 	graph.Initialize(snow.DefaultContextTest(), params)
-	graph.Add(Red)
-	graph.Add(Green)
-	graph.Add(Alpha)
-	graph.Add(Blue)
+	graph.Add(context.Background(), Red)
+	graph.Add(context.Background(), Green)
+	graph.Add(context.Background(), Alpha)
+	graph.Add(context.Background(), Blue)
 
 	if prefs := graph.Preferences(); prefs.Len() != 2 {
 		t.Fatalf("Wrong number of preferences.")
@@ -199,7 +200,7 @@ func MiddleConfidenceTest(t *testing.T, factory Factory) {
 	r := ids.Bag{}
 	r.SetThreshold(2)
 	r.AddCount(Red.ID(), 2)
-	graph.RecordPoll(r)
+	graph.RecordPoll(context.Background(), r)
 
 	if prefs := graph.Preferences(); prefs.Len() != 1 {
 		t.Fatalf("Wrong number of preferences.")
@@ -219,8 +220,8 @@ func IndependentTest(t *testing.T, factory Factory) {
 		K:       2, Alpha: 2, BetaVirtuous: 2, BetaRogue: 2,
 	}
 	graph.Initialize(snow.DefaultContextTest(), params)
-	graph.Add(Red)
-	graph.Add(Alpha)
+	graph.Add(context.Background(), Red)
+	graph.Add(context.Background(), Alpha)
 
 	if prefs := graph.Preferences(); prefs.Len() != 2 {
 		t.Fatalf("Wrong number of preferences.")
@@ -236,7 +237,7 @@ func IndependentTest(t *testing.T, factory Factory) {
 	ra.SetThreshold(2)
 	ra.AddCount(Red.ID(), 2)
 	ra.AddCount(Alpha.ID(), 2)
-	graph.RecordPoll(ra)
+	graph.RecordPoll(context.Background(), ra)
 
 	if prefs := graph.Preferences(); prefs.Len() != 2 {
 		t.Fatalf("Wrong number of preferences.")
@@ -248,7 +249,7 @@ func IndependentTest(t *testing.T, factory Factory) {
 		t.Fatalf("Finalized too early")
 	}
 
-	graph.RecordPoll(ra)
+	graph.RecordPoll(context.Background(), ra)
 
 	if prefs := graph.Preferences(); prefs.Len() != 0 {
 		t.Fatalf("Wrong number of preferences.")
@@ -267,7 +268,7 @@ func VirtuousTest(t *testing.T, factory Factory) {
 		K:       2, Alpha: 2, BetaVirtuous: 1, BetaRogue: 1,
 	}
 	graph.Initialize(snow.DefaultContextTest(), params)
-	graph.Add(Red)
+	graph.Add(context.Background(), Red)
 
 	if virtuous := graph.Virtuous(); virtuous.Len() != 1 {
 		t.Fatalf("Wrong number of virtuous.")
@@ -275,7 +276,7 @@ func VirtuousTest(t *testing.T, factory Factory) {
 		t.Fatalf("Wrong virtuous. Expected %s", Red.ID())
 	}
 
-	graph.Add(Alpha)
+	graph.Add(context.Background(), Alpha)
 
 	if virtuous := graph.Virtuous(); virtuous.Len() != 2 {
 		t.Fatalf("Wrong number of virtuous.")
@@ -285,7 +286,7 @@ func VirtuousTest(t *testing.T, factory Factory) {
 		t.Fatalf("Wrong virtuous. Expected %s", Alpha.ID())
 	}
 
-	graph.Add(Green)
+	graph.Add(context.Background(), Green)
 
 	if virtuous := graph.Virtuous(); virtuous.Len() != 1 {
 		t.Fatalf("Wrong number of virtuous.")
@@ -293,7 +294,7 @@ func VirtuousTest(t *testing.T, factory Factory) {
 		t.Fatalf("Wrong virtuous. Expected %s", Alpha.ID())
 	}
 
-	graph.Add(Blue)
+	graph.Add(context.Background(), Blue)
 
 	if virtuous := graph.Virtuous(); virtuous.Len() != 0 {
 		t.Fatalf("Wrong number of virtuous.")
@@ -321,7 +322,7 @@ func IsVirtuousTest(t *testing.T, factory Factory) {
 		t.Fatalf("Should be virtuous")
 	}
 
-	graph.Add(Red)
+	graph.Add(context.Background(), Red)
 
 	if !graph.IsVirtuous(Red) {
 		t.Fatalf("Should be virtuous")
@@ -333,7 +334,7 @@ func IsVirtuousTest(t *testing.T, factory Factory) {
 		t.Fatalf("Should be virtuous")
 	}
 
-	graph.Add(Green)
+	graph.Add(context.Background(), Green)
 
 	if graph.IsVirtuous(Red) {
 		t.Fatalf("Should not be virtuous")
@@ -359,13 +360,13 @@ func QuiesceTest(t *testing.T, factory Factory) {
 		t.Fatalf("Should quiesce")
 	}
 
-	graph.Add(Red)
+	graph.Add(context.Background(), Red)
 
 	if graph.Quiesce() {
 		t.Fatalf("Shouldn't quiesce")
 	}
 
-	graph.Add(Green)
+	graph.Add(context.Background(), Green)
 
 	if !graph.Quiesce() {
 		t.Fatalf("Should quiesce")
@@ -390,9 +391,9 @@ func AcceptingDependencyTest(t *testing.T, factory Factory) {
 	}
 	graph.Initialize(snow.DefaultContextTest(), params)
 
-	graph.Add(Red)
-	graph.Add(Green)
-	graph.Add(purple)
+	graph.Add(context.Background(), Red)
+	graph.Add(context.Background(), Green)
+	graph.Add(context.Background(), purple)
 
 	if prefs := graph.Preferences(); prefs.Len() != 2 {
 		t.Fatalf("Wrong number of preferences.")
@@ -411,7 +412,7 @@ func AcceptingDependencyTest(t *testing.T, factory Factory) {
 	g := ids.Bag{}
 	g.Add(Green.ID())
 
-	graph.RecordPoll(g)
+	graph.RecordPoll(context.Background(), g)
 
 	if prefs := graph.Preferences(); prefs.Len() != 2 {
 		t.Fatalf("Wrong number of preferences.")
@@ -430,7 +431,7 @@ func AcceptingDependencyTest(t *testing.T, factory Factory) {
 	rp := ids.Bag{}
 	rp.Add(Red.ID(), purple.ID())
 
-	graph.RecordPoll(rp)
+	graph.RecordPoll(context.Background(), rp)
 
 	if prefs := graph.Preferences(); prefs.Len() != 2 {
 		t.Fatalf("Wrong number of preferences.")
@@ -449,7 +450,7 @@ func AcceptingDependencyTest(t *testing.T, factory Factory) {
 	r := ids.Bag{}
 	r.Add(Red.ID())
 
-	graph.RecordPoll(r)
+	graph.RecordPoll(context.Background(), r)
 
 	if prefs := graph.Preferences(); prefs.Len() != 0 {
 		t.Fatalf("Wrong number of preferences.")
@@ -480,10 +481,10 @@ func RejectingDependencyTest(t *testing.T, factory Factory) {
 	}
 	graph.Initialize(snow.DefaultContextTest(), params)
 
-	graph.Add(Red)
-	graph.Add(Green)
-	graph.Add(Blue)
-	graph.Add(purple)
+	graph.Add(context.Background(), Red)
+	graph.Add(context.Background(), Green)
+	graph.Add(context.Background(), Blue)
+	graph.Add(context.Background(), purple)
 
 	if prefs := graph.Preferences(); prefs.Len() != 2 {
 		t.Fatalf("Wrong number of preferences.")
@@ -504,7 +505,7 @@ func RejectingDependencyTest(t *testing.T, factory Factory) {
 	gp := ids.Bag{}
 	gp.Add(Green.ID(), purple.ID())
 
-	graph.RecordPoll(gp)
+	graph.RecordPoll(context.Background(), gp)
 
 	if prefs := graph.Preferences(); prefs.Len() != 2 {
 		t.Fatalf("Wrong number of preferences.")
@@ -522,7 +523,7 @@ func RejectingDependencyTest(t *testing.T, factory Factory) {
 		t.Fatalf("Wrong status. %s should be %s", purple.ID(), choices.Processing)
 	}
 
-	graph.RecordPoll(gp)
+	graph.RecordPoll(context.Background(), gp)
 
 	if prefs := graph.Preferences(); prefs.Len() != 0 {
 		t.Fatalf("Wrong number of preferences.")
@@ -537,6 +538,48 @@ func RejectingDependencyTest(t *testing.T, factory Factory) {
 	}
 }
 
+func RejectionCauseTest(t *testing.T, factory Factory) {
+	Setup()
+
+	graph := factory.New()
+
+	purple := &TestTx{
+		Identifier: ids.Empty.Prefix(7),
+		Stat:       choices.Processing,
+	}
+	purple.Ins.Add(ids.Empty.Prefix(8))
+	purple.Deps = []Tx{Red}
+
+	params := snowball.Parameters{
+		Metrics: prometheus.NewRegistry(),
+		K:       1, Alpha: 1, BetaVirtuous: 1, BetaRogue: 1,
+	}
+	graph.Initialize(snow.DefaultContextTest(), params)
+
+	graph.Add(context.Background(), Red)
+	graph.Add(context.Background(), Green)
+	graph.Add(context.Background(), purple)
+
+	g := ids.Bag{}
+	g.Add(Green.ID())
+
+	graph.RecordPoll(context.Background(), g)
+
+	if Green.Status() != choices.Accepted {
+		t.Fatalf("Wrong status. %s should be %s", Green.ID(), choices.Accepted)
+	} else if Red.Status() != choices.Rejected {
+		t.Fatalf("Wrong status. %s should be %s", Red.ID(), choices.Rejected)
+	} else if purple.Status() != choices.Rejected {
+		t.Fatalf("Wrong status. %s should be %s", purple.ID(), choices.Rejected)
+	} else if !Green.RejectionCause.IsZero() {
+		t.Fatalf("Accepted tx shouldn't have a rejection cause")
+	} else if !Red.RejectionCause.Equals(Green.ID()) {
+		t.Fatalf("%s should have been rejected by %s", Red.ID(), Green.ID())
+	} else if !purple.RejectionCause.Equals(Red.ID()) {
+		t.Fatalf("%s should have been rejected by %s", purple.ID(), Red.ID())
+	}
+}
+
 func VacuouslyAcceptedTest(t *testing.T, factory Factory) {
 	Setup()
 
@@ -553,7 +596,7 @@ func VacuouslyAcceptedTest(t *testing.T, factory Factory) {
 	}
 	graph.Initialize(snow.DefaultContextTest(), params)
 
-	graph.Add(purple)
+	graph.Add(context.Background(), purple)
 
 	if prefs := graph.Preferences(); prefs.Len() != 0 {
 		t.Fatalf("Wrong number of preferences.")
@@ -593,7 +636,7 @@ func ConflictsTest(t *testing.T, factory Factory) {
 		Ins:        insPurple,
 	}
 
-	graph.Add(purple)
+	graph.Add(context.Background(), purple)
 
 	if orangeConflicts := graph.Conflicts(orange); orangeConflicts.Len() != 1 {
 		t.Fatalf("Wrong number of conflicts")
@@ -601,7 +644,7 @@ func ConflictsTest(t *testing.T, factory Factory) {
 		t.Fatalf("Conflicts does not contain the right transaction")
 	}
 
-	graph.Add(orange)
+	graph.Add(context.Background(), orange)
 
 	if orangeConflicts := graph.Conflicts(orange); orangeConflicts.Len() != 1 {
 		t.Fatalf("Wrong number of conflicts")
@@ -643,15 +686,15 @@ func VirtuousDependsOnRogueTest(t *testing.T, factory Factory) {
 
 	virtuous.Ins.Add(input2)
 
-	graph.Add(rogue1)
-	graph.Add(rogue2)
-	graph.Add(virtuous)
+	graph.Add(context.Background(), rogue1)
+	graph.Add(context.Background(), rogue2)
+	graph.Add(context.Background(), virtuous)
 
 	votes := ids.Bag{}
 	votes.Add(rogue1.ID())
 	votes.Add(virtuous.ID())
 
-	graph.RecordPoll(votes)
+	graph.RecordPoll(context.Background(), votes)
 
 	if status := rogue1.Status(); status != choices.Processing {
 		t.Fatalf("Rogue Tx is %s expected %s", status, choices.Processing)
@@ -674,10 +717,10 @@ func StringTest(t *testing.T, factory Factory, prefix string) {
 		K:       2, Alpha: 2, BetaVirtuous: 1, BetaRogue: 2,
 	}
 	graph.Initialize(snow.DefaultContextTest(), params)
-	graph.Add(Red)
-	graph.Add(Green)
-	graph.Add(Blue)
-	graph.Add(Alpha)
+	graph.Add(context.Background(), Red)
+	graph.Add(context.Background(), Green)
+	graph.Add(context.Background(), Blue)
+	graph.Add(context.Background(), Alpha)
 
 	if prefs := graph.Preferences(); prefs.Len() != 1 {
 		t.Fatalf("Wrong number of preferences.")
@@ -691,8 +734,8 @@ func StringTest(t *testing.T, factory Factory, prefix string) {
 	rb.SetThreshold(2)
 	rb.AddCount(Red.ID(), 2)
 	rb.AddCount(Blue.ID(), 2)
-	graph.RecordPoll(rb)
-	graph.Add(Blue)
+	graph.RecordPoll(context.Background(), rb)
+	graph.Add(context.Background(), Blue)
 
 	{
 		expected := prefix + "(\n" +
@@ -720,7 +763,7 @@ func StringTest(t *testing.T, factory Factory, prefix string) {
 	ga.SetThreshold(2)
 	ga.AddCount(Green.ID(), 2)
 	ga.AddCount(Alpha.ID(), 2)
-	graph.RecordPoll(ga)
+	graph.RecordPoll(context.Background(), ga)
 
 	{
 		expected := prefix + "(\n" +
@@ -745,7 +788,7 @@ func StringTest(t *testing.T, factory Factory, prefix string) {
 	}
 
 	empty := ids.Bag{}
-	graph.RecordPoll(empty)
+	graph.RecordPoll(context.Background(), empty)
 
 	{
 		expected := prefix + "(\n" +
@@ -769,7 +812,7 @@ func StringTest(t *testing.T, factory Factory, prefix string) {
 		t.Fatalf("Finalized too early")
 	}
 
-	graph.RecordPoll(ga)
+	graph.RecordPoll(context.Background(), ga)
 
 	{
 		expected := prefix + "(\n" +
@@ -793,7 +836,7 @@ func StringTest(t *testing.T, factory Factory, prefix string) {
 		t.Fatalf("Finalized too early")
 	}
 
-	graph.RecordPoll(ga)
+	graph.RecordPoll(context.Background(), ga)
 
 	{
 		expected := prefix + "()"
@@ -818,7 +861,7 @@ func StringTest(t *testing.T, factory Factory, prefix string) {
 		t.Fatalf("%s should have been rejected", Blue.ID())
 	}
 
-	graph.RecordPoll(rb)
+	graph.RecordPoll(context.Background(), rb)
 
 	{
 		expected := prefix + "()"
@@ -5,9 +5,11 @@ package snowstorm
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 
@@ -16,6 +18,14 @@ import (
 	"github.com/ava-labs/gecko/snow/consensus/snowball"
 	"github.com/ava-labs/gecko/snow/events"
 	"github.com/ava-labs/gecko/utils/formatting"
+	"github.com/ava-labs/gecko/utils/metrics"
+	"github.com/ava-labs/gecko/utils/timer"
+)
+
+const (
+	// StuckTxAge is how long a transaction may be processing before it is
+	// reported as stuck
+	StuckTxAge = 2 * time.Minute
 )
 
 // DirectedFactory implements Factory by returning a directed struct
@@ -30,8 +40,17 @@ type Directed struct {
 	ctx    *snow.Context
 	params snowball.Parameters
 
-	numProcessingVirtuous, numProcessingRogue prometheus.Gauge
-	numAccepted, numRejected                  prometheus.Counter
+	// Used to measure how long transactions have been processing
+	clock timer.Clock
+
+	numProcessingVirtuous, numProcessingRogue  prometheus.Gauge
+	numAccepted, numRejected                   prometheus.Counter
+	numRejectedConflict, numRejectedDependency prometheus.Counter
+
+	numConflictSets, maxConflictSetSize prometheus.Gauge
+	oldestProcessing, numStuck          prometheus.Gauge
+
+	confidence prometheus.Histogram
 
 	// Each element of preferences is the ID of a transaction that is preferred.
 	// That is, each transaction has no out edges
@@ -65,6 +84,11 @@ type flatNode struct {
 	pendingAccept, accepted, rogue bool
 	ins, outs                      ids.Set
 
+	// issued is when the transaction was added. stuck is true once the
+	// transaction has been reported as stuck.
+	issued time.Time
+	stuck  bool
+
 	tx Tx
 }
 
@@ -75,43 +99,19 @@ func (dg *Directed) Initialize(ctx *snow.Context, params snowball.Parameters) {
 	dg.ctx = ctx
 	dg.params = params
 
-	dg.numProcessingVirtuous = prometheus.NewGauge(
-		prometheus.GaugeOpts{
-			Namespace: params.Namespace,
-			Name:      "tx_processing_virtuous",
-			Help:      "Number of processing virtuous transactions",
-		})
-	dg.numProcessingRogue = prometheus.NewGauge(
-		prometheus.GaugeOpts{
-			Namespace: params.Namespace,
-			Name:      "tx_processing_rogue",
-			Help:      "Number of processing rogue transactions",
-		})
-	dg.numAccepted = prometheus.NewCounter(
-		prometheus.CounterOpts{
-			Namespace: params.Namespace,
-			Name:      "tx_accepted",
-			Help:      "Number of transactions accepted",
-		})
-	dg.numRejected = prometheus.NewCounter(
-		prometheus.CounterOpts{
-			Namespace: params.Namespace,
-			Name:      "tx_rejected",
-			Help:      "Number of transactions rejected",
-		})
-
-	if err := dg.params.Metrics.Register(dg.numProcessingVirtuous); err != nil {
-		dg.ctx.Log.Error("Failed to register tx_processing_virtuous statistics due to %s", err)
-	}
-	if err := dg.params.Metrics.Register(dg.numProcessingRogue); err != nil {
-		dg.ctx.Log.Error("Failed to register tx_processing_rogue statistics due to %s", err)
-	}
-	if err := dg.params.Metrics.Register(dg.numAccepted); err != nil {
-		dg.ctx.Log.Error("Failed to register tx_accepted statistics due to %s", err)
-	}
-	if err := dg.params.Metrics.Register(dg.numRejected); err != nil {
-		dg.ctx.Log.Error("Failed to register tx_rejected statistics due to %s", err)
-	}
+	r := metrics.NewChainRegisterer(ctx.Log, params.Metrics, params.Namespace, "consensus")
+	dg.numProcessingVirtuous = r.NewGauge("tx_processing_virtuous", "Number of processing virtuous transactions")
+	dg.numProcessingRogue = r.NewGauge("tx_processing_rogue", "Number of processing rogue transactions")
+	dg.numAccepted = r.NewCounter("tx_accepted", "Number of transactions accepted")
+	dg.numRejected = r.NewCounter("tx_rejected", "Number of transactions rejected")
+	dg.numRejectedConflict = r.NewCounter("tx_rejected_conflict", "Number of transactions rejected because a conflicting transaction was accepted")
+	dg.numRejectedDependency = r.NewCounter("tx_rejected_dependency", "Number of transactions rejected because a transaction they depend on was rejected")
+
+	dg.numConflictSets = r.NewGauge("tx_conflict_sets", "Number of inputs consumed by more than one processing transaction")
+	dg.maxConflictSetSize = r.NewGauge("tx_max_conflict_set_size", "Number of processing transactions consuming the most contested input")
+	dg.oldestProcessing = r.NewGauge("tx_oldest_processing_seconds", "Number of seconds the oldest processing transaction has been processing")
+	dg.numStuck = r.NewGauge("tx_stuck", "Number of transactions that have been processing for too long")
+	dg.confidence = r.NewHistogram("tx_confidence", "Confidence of the transactions that received an alpha majority in a poll", confidenceBuckets(params))
 
 	dg.spends = make(map[[32]byte]ids.Set)
 	dg.nodes = make(map[[32]byte]*flatNode)
@@ -120,6 +120,9 @@ func (dg *Directed) Initialize(ctx *snow.Context, params snowball.Parameters) {
 // Parameters implements the Snowstorm interface
 func (dg *Directed) Parameters() snowball.Parameters { return dg.params }
 
+// SetParameters implements the Consensus interface
+func (dg *Directed) SetParameters(params snowball.Parameters) { dg.params = params }
+
 // IsVirtuous implements the Consensus interface
 func (dg *Directed) IsVirtuous(tx Tx) bool {
 	id := tx.ID()
@@ -155,7 +158,7 @@ func (dg *Directed) Conflicts(tx Tx) ids.Set {
 }
 
 // Add implements the Consensus interface
-func (dg *Directed) Add(tx Tx) {
+func (dg *Directed) Add(ctx context.Context, tx Tx) {
 	if dg.Issued(tx) {
 		return // Already inserted
 	}
@@ -167,14 +170,17 @@ func (dg *Directed) Add(tx Tx) {
 	inputs := tx.InputIDs()
 	// If there are no inputs, Tx is vacuously accepted
 	if inputs.Len() == 0 {
-		tx.Accept()
+		tx.Accept(ctx)
 		dg.ctx.DecisionDispatcher.Accept(dg.ctx.ChainID, txID, bytes)
 		dg.numAccepted.Inc()
 		return
 	}
 
 	id := tx.ID()
-	fn := &flatNode{tx: tx}
+	fn := &flatNode{
+		issued: dg.clock.Time(),
+		tx:     tx,
+	}
 
 	// Note: Below, for readability, we sometimes say "transaction" when we actually mean
 	// "the flatNode representing a transaction."
@@ -238,7 +244,7 @@ func (dg *Directed) Add(tx Tx) {
 			toReject.deps.Add(dependency.ID())
 		}
 	}
-	dg.pendingReject.Register(toReject)
+	dg.pendingReject.Register(ctx, toReject)
 }
 
 // Issued implements the Consensus interface
@@ -257,7 +263,7 @@ func (dg *Directed) Virtuous() ids.Set { return dg.virtuous }
 func (dg *Directed) Preferences() ids.Set { return dg.preferences }
 
 // RecordPoll implements the Consensus interface
-func (dg *Directed) RecordPoll(votes ids.Bag) {
+func (dg *Directed) RecordPoll(ctx context.Context, votes ids.Bag) {
 	dg.currentVote++
 
 	votes.SetThreshold(dg.params.Alpha)
@@ -279,16 +285,88 @@ func (dg *Directed) RecordPoll(votes ids.Bag) {
 
 		fn.bias++
 		fn.confidence++
+		dg.confidence.Observe(float64(fn.confidence))
 
 		if !fn.pendingAccept &&
 			((!fn.rogue && fn.confidence >= dg.params.BetaVirtuous) ||
 				fn.confidence >= dg.params.BetaRogue) {
-			dg.deferAcceptance(fn)
+			dg.deferAcceptance(ctx, fn)
 		}
 		if !fn.accepted {
 			dg.redirectEdges(fn)
 		}
 	}
+
+	dg.updateHealth()
+}
+
+// DirectedHealth describes the conflicts between, and the age of, the
+// transactions that are processing
+type DirectedHealth struct {
+	// Number of inputs consumed by more than one processing transaction
+	ConflictSets int
+	// Number of processing transactions consuming the most contested input
+	MaxConflictSetSize int
+	// How long the oldest processing transaction has been processing
+	OldestProcessing time.Duration
+	// Transactions that have been processing for longer than StuckTxAge
+	Stuck ids.Set
+}
+
+// Health returns the current health of the conflict graph
+func (dg *Directed) Health() DirectedHealth {
+	health := DirectedHealth{}
+	for _, spends := range dg.spends {
+		size := 0
+		for _, txID := range spends.List() {
+			if _, processing := dg.nodes[txID.Key()]; processing {
+				size++
+			}
+		}
+		if size > 1 {
+			health.ConflictSets++
+		}
+		if size > health.MaxConflictSetSize {
+			health.MaxConflictSetSize = size
+		}
+	}
+
+	now := dg.clock.Time()
+	for _, fn := range dg.nodes {
+		age := now.Sub(fn.issued)
+		if age > health.OldestProcessing {
+			health.OldestProcessing = age
+		}
+		if age > StuckTxAge {
+			health.Stuck.Add(fn.tx.ID())
+		}
+	}
+	return health
+}
+
+// updateHealth updates the health metrics and logs the transactions that have
+// become stuck since the last update
+func (dg *Directed) updateHealth() {
+	health := dg.Health()
+
+	dg.numConflictSets.Set(float64(health.ConflictSets))
+	dg.maxConflictSetSize.Set(float64(health.MaxConflictSetSize))
+	dg.oldestProcessing.Set(health.OldestProcessing.Seconds())
+	dg.numStuck.Set(float64(health.Stuck.Len()))
+
+	for _, txID := range health.Stuck.List() {
+		fn := dg.nodes[txID.Key()]
+		if fn.stuck {
+			continue
+		}
+		fn.stuck = true
+
+		conflicts := ids.Set{}
+		conflicts.Union(fn.ins)
+		conflicts.Union(fn.outs)
+		dg.ctx.Log.Warn("Transaction %s has been processing since %s with confidence %d and conflicts %s",
+			txID, fn.issued, fn.confidence, conflicts)
+	}
 }
 
 // Quiesce implements the Consensus interface
@@ -338,7 +416,7 @@ func (dg *Directed) String() string {
 	return sb.String()
 }
 
-func (dg *Directed) deferAcceptance(fn *flatNode) {
+func (dg *Directed) deferAcceptance(ctx context.Context, fn *flatNode) {
 	fn.pendingAccept = true
 
 	toAccept := &directedAccepter{
@@ -352,10 +430,11 @@ func (dg *Directed) deferAcceptance(fn *flatNode) {
 	}
 
 	dg.virtuousVoting.Remove(fn.tx.ID())
-	dg.pendingAccept.Register(toAccept)
+	dg.pendingAccept.Register(ctx, toAccept)
 }
 
-func (dg *Directed) reject(ids ...ids.ID) {
+// reject all the ids, recording causeID as the reason they were rejected
+func (dg *Directed) reject(ctx context.Context, causeID ids.ID, ids ...ids.ID) {
 	for _, conflict := range ids {
 		conflictKey := conflict.Key()
 		conf := dg.nodes[conflictKey]
@@ -374,11 +453,14 @@ func (dg *Directed) reject(ids ...ids.ID) {
 		dg.removeConflict(conflict, conf.outs.List()...)
 
 		// Mark it as rejected
+		if recorder, ok := conf.tx.(RejectionCauseRecorder); ok {
+			recorder.RejectedBy(causeID)
+		}
 		conf.tx.Reject()
 		dg.ctx.DecisionDispatcher.Reject(dg.ctx.ChainID, conf.tx.ID(), conf.tx.Bytes())
 		dg.numRejected.Inc()
-		dg.pendingAccept.Abandon(conflict)
-		dg.pendingReject.Fulfill(conflict)
+		dg.pendingAccept.Abandon(ctx, conflict)
+		dg.pendingReject.Fulfill(ctx, conflict)
 	}
 }
 
@@ -436,14 +518,14 @@ type directedAccepter struct {
 
 func (a *directedAccepter) Dependencies() ids.Set { return a.deps }
 
-func (a *directedAccepter) Fulfill(id ids.ID) {
+func (a *directedAccepter) Fulfill(ctx context.Context, id ids.ID) {
 	a.deps.Remove(id)
-	a.Update()
+	a.Update(ctx)
 }
 
-func (a *directedAccepter) Abandon(id ids.ID) { a.rejected = true }
+func (a *directedAccepter) Abandon(context.Context, ids.ID) { a.rejected = true }
 
-func (a *directedAccepter) Update() {
+func (a *directedAccepter) Update(ctx context.Context) {
 	// If I was rejected or I am still waiting on dependencies to finish do nothing.
 	if a.rejected || a.deps.Len() != 0 {
 		return
@@ -459,12 +541,16 @@ func (a *directedAccepter) Update() {
 	a.dg.preferences.Remove(id)
 
 	// Reject the conflicts
-	a.dg.reject(a.fn.ins.List()...)
-	a.dg.reject(a.fn.outs.List()...) // Should normally be empty
+	ins := a.fn.ins.List()
+	a.dg.numRejectedConflict.Add(float64(len(ins)))
+	a.dg.reject(ctx, id, ins...)
+	outs := a.fn.outs.List() // Should normally be empty
+	a.dg.numRejectedConflict.Add(float64(len(outs)))
+	a.dg.reject(ctx, id, outs...)
 
 	// Mark it as accepted
 	a.fn.accepted = true
-	a.fn.tx.Accept()
+	a.fn.tx.Accept(ctx)
 	a.dg.ctx.DecisionDispatcher.Accept(a.dg.ctx.ChainID, id, a.fn.tx.Bytes())
 	a.dg.numAccepted.Inc()
 
@@ -474,8 +560,8 @@ func (a *directedAccepter) Update() {
 		a.dg.numProcessingVirtuous.Dec()
 	}
 
-	a.dg.pendingAccept.Fulfill(id)
-	a.dg.pendingReject.Abandon(id)
+	a.dg.pendingAccept.Fulfill(ctx, id)
+	a.dg.pendingReject.Abandon(ctx, id)
 }
 
 // directedRejector implements Blockable
@@ -488,17 +574,18 @@ type directedRejector struct {
 
 func (r *directedRejector) Dependencies() ids.Set { return r.deps }
 
-func (r *directedRejector) Fulfill(id ids.ID) {
+func (r *directedRejector) Fulfill(ctx context.Context, id ids.ID) {
 	if r.rejected {
 		return
 	}
 	r.rejected = true
-	r.dg.reject(r.fn.tx.ID())
+	r.dg.numRejectedDependency.Inc()
+	r.dg.reject(ctx, id, r.fn.tx.ID())
 }
 
-func (*directedRejector) Abandon(id ids.ID) {}
+func (*directedRejector) Abandon(context.Context, ids.ID) {}
 
-func (*directedRejector) Update() {}
+func (*directedRejector) Update(context.Context) {}
 
 type sortFlatNodeData []*flatNode
 
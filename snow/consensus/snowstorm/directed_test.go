@@ -4,7 +4,15 @@
 package snowstorm
 
 import (
+	"context"
 	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow"
+	"github.com/ava-labs/gecko/snow/consensus/snowball"
 )
 
 func TestDirectedParams(t *testing.T) { ParamsTest(t, DirectedFactory{}) }
@@ -31,6 +39,8 @@ func TestDirectedAcceptingDependency(t *testing.T) { AcceptingDependencyTest(t,
 
 func TestDirectedRejectingDependency(t *testing.T) { RejectingDependencyTest(t, DirectedFactory{}) }
 
+func TestDirectedRejectionCause(t *testing.T) { RejectionCauseTest(t, DirectedFactory{}) }
+
 func TestDirectedVacuouslyAccepted(t *testing.T) { VacuouslyAcceptedTest(t, DirectedFactory{}) }
 
 func TestDirectedVirtuousDependsOnRogue(t *testing.T) {
@@ -38,3 +48,63 @@ func TestDirectedVirtuousDependsOnRogue(t *testing.T) {
 }
 
 func TestDirectedString(t *testing.T) { StringTest(t, DirectedFactory{}, "DG") }
+
+func TestDirectedHealth(t *testing.T) {
+	Setup()
+
+	graph := &Directed{}
+	graph.clock.Set(time.Unix(0, 0))
+
+	params := snowball.Parameters{
+		Metrics: prometheus.NewRegistry(),
+		K:       2, Alpha: 2, BetaVirtuous: 2, BetaRogue: 2,
+	}
+	graph.Initialize(snow.DefaultContextTest(), params)
+
+	graph.Add(context.Background(), Red)
+	graph.Add(context.Background(), Green)
+
+	graph.clock.Set(time.Unix(0, 0).Add(StuckTxAge))
+	graph.Add(context.Background(), Blue)
+
+	health := graph.Health()
+	if health.ConflictSets != 2 {
+		t.Fatalf("Expected %d conflict sets, got %d", 2, health.ConflictSets)
+	}
+	if health.MaxConflictSetSize != 2 {
+		t.Fatalf("Expected the largest conflict set to have %d txs, got %d", 2, health.MaxConflictSetSize)
+	}
+	if health.OldestProcessing != StuckTxAge {
+		t.Fatalf("Expected the oldest tx to be %s old, got %s", StuckTxAge, health.OldestProcessing)
+	}
+	if health.Stuck.Len() != 0 {
+		t.Fatalf("No txs should be stuck yet")
+	}
+
+	graph.clock.Set(time.Unix(0, 0).Add(StuckTxAge + time.Second))
+	graph.RecordPoll(context.Background(), ids.Bag{})
+
+	health = graph.Health()
+	if health.Stuck.Len() != 2 || !health.Stuck.Contains(Red.ID()) || !health.Stuck.Contains(Green.ID()) {
+		t.Fatalf("Expected Red and Green to be stuck, got %s", health.Stuck)
+	}
+	if !graph.nodes[Red.ID().Key()].stuck || !graph.nodes[Green.ID().Key()].stuck {
+		t.Fatalf("Stuck txs should have been reported")
+	}
+	if graph.nodes[Blue.ID().Key()].stuck {
+		t.Fatalf("Blue shouldn't have been reported")
+	}
+
+	votes := ids.Bag{}
+	votes.Add(Red.ID(), Red.ID())
+	graph.RecordPoll(context.Background(), votes)
+	graph.RecordPoll(context.Background(), votes)
+
+	health = graph.Health()
+	if health.ConflictSets != 0 {
+		t.Fatalf("Expected no conflict sets after Red was accepted, got %d", health.ConflictSets)
+	}
+	if health.Stuck.Len() != 0 {
+		t.Fatalf("No txs should be stuck after Red was accepted, got %s", health.Stuck)
+	}
+}
@@ -5,6 +5,7 @@ package snowstorm
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"sort"
 	"strings"
@@ -16,6 +17,7 @@ import (
 	"github.com/ava-labs/gecko/snow/consensus/snowball"
 	"github.com/ava-labs/gecko/snow/events"
 	"github.com/ava-labs/gecko/utils/formatting"
+	"github.com/ava-labs/gecko/utils/metrics"
 )
 
 // InputFactory implements Factory by returning an input struct
@@ -30,8 +32,10 @@ type Input struct {
 	ctx    *snow.Context
 	params snowball.Parameters
 
-	numProcessing            prometheus.Gauge
-	numAccepted, numRejected prometheus.Counter
+	numProcessing                              prometheus.Gauge
+	numAccepted, numRejected                   prometheus.Counter
+	numRejectedConflict, numRejectedDependency prometheus.Counter
+	confidence                                 prometheus.Histogram
 
 	// preferences is the set of consumerIDs that have only in edges
 	// virtuous is the set of consumerIDs that have no edges
@@ -70,36 +74,13 @@ func (ig *Input) Initialize(ctx *snow.Context, params snowball.Parameters) {
 	ig.ctx = ctx
 	ig.params = params
 
-	namespace := fmt.Sprintf("gecko_%s", ig.ctx.ChainID)
-
-	ig.numProcessing = prometheus.NewGauge(
-		prometheus.GaugeOpts{
-			Namespace: namespace,
-			Name:      "tx_processing",
-			Help:      "Number of processing transactions",
-		})
-	ig.numAccepted = prometheus.NewCounter(
-		prometheus.CounterOpts{
-			Namespace: namespace,
-			Name:      "tx_accepted",
-			Help:      "Number of transactions accepted",
-		})
-	ig.numRejected = prometheus.NewCounter(
-		prometheus.CounterOpts{
-			Namespace: namespace,
-			Name:      "tx_rejected",
-			Help:      "Number of transactions rejected",
-		})
-
-	if err := ig.params.Metrics.Register(ig.numProcessing); err != nil {
-		ig.ctx.Log.Error("Failed to register tx_processing statistics due to %s", err)
-	}
-	if err := ig.params.Metrics.Register(ig.numAccepted); err != nil {
-		ig.ctx.Log.Error("Failed to register tx_accepted statistics due to %s", err)
-	}
-	if err := ig.params.Metrics.Register(ig.numRejected); err != nil {
-		ig.ctx.Log.Error("Failed to register tx_rejected statistics due to %s", err)
-	}
+	r := metrics.NewChainRegisterer(ctx.Log, params.Metrics, params.Namespace, "consensus")
+	ig.numProcessing = r.NewGauge("tx_processing", "Number of processing transactions")
+	ig.numAccepted = r.NewCounter("tx_accepted", "Number of transactions accepted")
+	ig.numRejected = r.NewCounter("tx_rejected", "Number of transactions rejected")
+	ig.numRejectedConflict = r.NewCounter("tx_rejected_conflict", "Number of transactions rejected because a conflicting transaction was accepted")
+	ig.numRejectedDependency = r.NewCounter("tx_rejected_dependency", "Number of transactions rejected because a transaction they depend on was rejected")
+	ig.confidence = r.NewHistogram("tx_confidence", "Confidence of the transactions that received an alpha majority in a poll", confidenceBuckets(params))
 
 	ig.txs = make(map[[32]byte]txNode)
 	ig.inputs = make(map[[32]byte]inputNode)
@@ -108,6 +89,9 @@ func (ig *Input) Initialize(ctx *snow.Context, params snowball.Parameters) {
 // Parameters implements the Snowstorm interface
 func (ig *Input) Parameters() snowball.Parameters { return ig.params }
 
+// SetParameters implements the Consensus interface
+func (ig *Input) SetParameters(params snowball.Parameters) { ig.params = params }
+
 // IsVirtuous implements the ConflictGraph interface
 func (ig *Input) IsVirtuous(tx Tx) bool {
 	id := tx.ID()
@@ -122,7 +106,7 @@ func (ig *Input) IsVirtuous(tx Tx) bool {
 }
 
 // Add implements the ConflictGraph interface
-func (ig *Input) Add(tx Tx) {
+func (ig *Input) Add(ctx context.Context, tx Tx) {
 	if ig.Issued(tx) {
 		return // Already inserted
 	}
@@ -134,7 +118,7 @@ func (ig *Input) Add(tx Tx) {
 	inputs := tx.InputIDs()
 	// If there are no inputs, they are vacuously accepted
 	if inputs.Len() == 0 {
-		tx.Accept()
+		tx.Accept(ctx)
 		ig.ctx.DecisionDispatcher.Accept(ig.ctx.ChainID, txID, bytes)
 		ig.numAccepted.Inc()
 		return
@@ -185,7 +169,7 @@ func (ig *Input) Add(tx Tx) {
 			toReject.deps.Add(dependency.ID())
 		}
 	}
-	ig.pendingReject.Register(toReject)
+	ig.pendingReject.Register(ctx, toReject)
 }
 
 // Issued implements the ConflictGraph interface
@@ -218,7 +202,7 @@ func (ig *Input) Conflicts(tx Tx) ids.Set {
 }
 
 // RecordPoll implements the ConflictGraph interface
-func (ig *Input) RecordPoll(votes ids.Bag) {
+func (ig *Input) RecordPoll(ctx context.Context, votes ids.Bag) {
 	ig.currentVote++
 
 	votes.SetThreshold(ig.params.Alpha)
@@ -283,6 +267,8 @@ func (ig *Input) RecordPoll(votes ids.Bag) {
 			}
 		}
 
+		ig.confidence.Observe(float64(confidence))
+
 		// If the node wasn't accepted, but was preferred, make sure it is
 		// marked as preferred
 		if preferred {
@@ -291,7 +277,7 @@ func (ig *Input) RecordPoll(votes ids.Bag) {
 
 		if (!rogue && confidence >= ig.params.BetaVirtuous) ||
 			confidence >= ig.params.BetaRogue {
-			ig.deferAcceptance(tx)
+			ig.deferAcceptance(ctx, tx)
 			continue
 		}
 
@@ -299,7 +285,7 @@ func (ig *Input) RecordPoll(votes ids.Bag) {
 	}
 }
 
-func (ig *Input) deferAcceptance(tn txNode) {
+func (ig *Input) deferAcceptance(ctx context.Context, tn txNode) {
 	toAccept := &inputAccepter{
 		ig: ig,
 		tn: tn,
@@ -312,11 +298,12 @@ func (ig *Input) deferAcceptance(tn txNode) {
 	}
 
 	ig.virtuousVoting.Remove(tn.tx.ID())
-	ig.pendingAccept.Register(toAccept)
+	ig.pendingAccept.Register(ctx, toAccept)
 }
 
-// reject all the ids and remove them from their conflict sets
-func (ig *Input) reject(ids ...ids.ID) {
+// reject all the ids and remove them from their conflict sets, recording
+// causeID as the reason they were rejected
+func (ig *Input) reject(ctx context.Context, causeID ids.ID, ids ...ids.ID) {
 	for _, conflict := range ids {
 		conflictKey := conflict.Key()
 		cn := ig.txs[conflictKey]
@@ -328,11 +315,14 @@ func (ig *Input) reject(ids ...ids.ID) {
 		ig.removeConflict(conflict, cn.tx.InputIDs().List()...)
 
 		// Mark it as rejected
+		if recorder, ok := cn.tx.(RejectionCauseRecorder); ok {
+			recorder.RejectedBy(causeID)
+		}
 		cn.tx.Reject()
 		ig.ctx.DecisionDispatcher.Reject(ig.ctx.ChainID, cn.tx.ID(), cn.tx.Bytes())
 		ig.numRejected.Inc()
-		ig.pendingAccept.Abandon(conflict)
-		ig.pendingReject.Fulfill(conflict)
+		ig.pendingAccept.Abandon(ctx, conflict)
+		ig.pendingReject.Fulfill(ctx, conflict)
 	}
 }
 
@@ -482,14 +472,14 @@ type inputAccepter struct {
 
 func (a *inputAccepter) Dependencies() ids.Set { return a.deps }
 
-func (a *inputAccepter) Fulfill(id ids.ID) {
+func (a *inputAccepter) Fulfill(ctx context.Context, id ids.ID) {
 	a.deps.Remove(id)
-	a.Update()
+	a.Update(ctx)
 }
 
-func (a *inputAccepter) Abandon(id ids.ID) { a.rejected = true }
+func (a *inputAccepter) Abandon(context.Context, ids.ID) { a.rejected = true }
 
-func (a *inputAccepter) Update() {
+func (a *inputAccepter) Update(ctx context.Context) {
 	if a.rejected || a.deps.Len() != 0 {
 		return
 	}
@@ -512,16 +502,17 @@ func (a *inputAccepter) Update() {
 			conflicts.Union(inputNode.conflicts)
 		}
 	}
-	a.ig.reject(conflicts.List()...)
+	a.ig.numRejectedConflict.Add(float64(conflicts.Len()))
+	a.ig.reject(ctx, id, conflicts.List()...)
 
 	// Mark it as accepted
-	a.tn.tx.Accept()
+	a.tn.tx.Accept(ctx)
 	a.ig.ctx.DecisionDispatcher.Accept(a.ig.ctx.ChainID, id, a.tn.tx.Bytes())
 	a.ig.numAccepted.Inc()
 	a.ig.numProcessing.Dec()
 
-	a.ig.pendingAccept.Fulfill(id)
-	a.ig.pendingReject.Abandon(id)
+	a.ig.pendingAccept.Fulfill(ctx, id)
+	a.ig.pendingReject.Abandon(ctx, id)
 }
 
 // inputRejector implements Blockable
@@ -534,17 +525,18 @@ type inputRejector struct {
 
 func (r *inputRejector) Dependencies() ids.Set { return r.deps }
 
-func (r *inputRejector) Fulfill(id ids.ID) {
+func (r *inputRejector) Fulfill(ctx context.Context, id ids.ID) {
 	if r.rejected {
 		return
 	}
 	r.rejected = true
-	r.ig.reject(r.tn.tx.ID())
+	r.ig.numRejectedDependency.Inc()
+	r.ig.reject(ctx, id, r.tn.tx.ID())
 }
 
-func (*inputRejector) Abandon(id ids.ID) {}
+func (*inputRejector) Abandon(context.Context, ids.ID) {}
 
-func (*inputRejector) Update() {}
+func (*inputRejector) Update(context.Context) {}
 
 type tempNode struct {
 	id               ids.ID
@@ -31,6 +31,8 @@ func TestInputAcceptingDependency(t *testing.T) { AcceptingDependencyTest(t, Inp
 
 func TestInputRejectingDependency(t *testing.T) { RejectingDependencyTest(t, InputFactory{}) }
 
+func TestInputRejectionCause(t *testing.T) { RejectionCauseTest(t, InputFactory{}) }
+
 func TestInputVacuouslyAccepted(t *testing.T) { VacuouslyAcceptedTest(t, InputFactory{}) }
 
 func TestInputVirtuousDependsOnRogue(t *testing.T) { VirtuousDependsOnRogueTest(t, InputFactory{}) }
@@ -4,6 +4,8 @@
 package snowstorm
 
 import (
+	"context"
+
 	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/snow"
 	"github.com/ava-labs/gecko/snow/choices"
@@ -90,7 +92,7 @@ func (n *Network) AddNode(cg Consensus) {
 		}
 		txs[newTx.ID().Key()] = newTx
 
-		cg.Add(newTx)
+		cg.Add(context.Background(), newTx)
 	}
 
 	n.nodeTxs = append(n.nodeTxs, txs)
@@ -130,7 +132,7 @@ func (n *Network) Round() {
 			}
 		}
 
-		running.RecordPoll(sampledColors)
+		running.RecordPoll(context.Background(), sampledColors)
 
 		// If this node has been finalized, remove it from the poller
 		if running.Finalized() {
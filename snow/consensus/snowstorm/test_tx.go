@@ -4,6 +4,8 @@
 package snowstorm
 
 import (
+	"context"
+
 	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/snow/choices"
 )
@@ -15,6 +17,8 @@ type TestTx struct {
 	Ins        ids.Set
 	Stat       choices.Status
 	Bits       []byte
+
+	RejectionCause ids.ID
 }
 
 // ID implements the Consumer interface
@@ -30,16 +34,22 @@ func (tx *TestTx) InputIDs() ids.Set { return tx.Ins }
 func (tx *TestTx) Status() choices.Status { return tx.Stat }
 
 // Accept implements the Consumer interface
-func (tx *TestTx) Accept() { tx.Stat = choices.Accepted }
+func (tx *TestTx) Accept(context.Context) { tx.Stat = choices.Accepted }
 
 // Reject implements the Consumer interface
 func (tx *TestTx) Reject() { tx.Stat = choices.Rejected }
 
+// RejectedBy implements the RejectionCauseRecorder interface
+func (tx *TestTx) RejectedBy(causeID ids.ID) { tx.RejectionCause = causeID }
+
 // Reset sets the status to pending
-func (tx *TestTx) Reset() { tx.Stat = choices.Processing }
+func (tx *TestTx) Reset() {
+	tx.Stat = choices.Processing
+	tx.RejectionCause = ids.ID{}
+}
 
 // Verify returns nil
-func (tx *TestTx) Verify() error { return nil }
+func (tx *TestTx) Verify(context.Context) error { return nil }
 
 // Bytes returns the bits
 func (tx *TestTx) Bytes() []byte { return tx.Bits }
@@ -4,13 +4,14 @@
 package snowstorm
 
 import (
+	"context"
 	"testing"
 )
 
 func TestTxVerify(t *testing.T) {
 	Setup()
 
-	if err := Red.Verify(); err != nil {
+	if err := Red.Verify(context.Background()); err != nil {
 		t.Fatal(err)
 	}
 }
@@ -22,6 +22,13 @@ type Callable interface {
 // Keystore ...
 type Keystore interface {
 	GetDatabase(username, password string) (database.Database, error)
+	ResolveAddress(username, password, name string) (ids.ShortID, error)
+}
+
+// SharedMemory ...
+type SharedMemory interface {
+	GetDatabase(id ids.ID) database.Database
+	ReleaseDatabase(id ids.ID)
 }
 
 // AliasLookup ...
@@ -30,20 +37,39 @@ type AliasLookup interface {
 	PrimaryAlias(id ids.ID) (string, error)
 }
 
+// LockStrategy declares which of a chain's messages may be handled
+// concurrently
+type LockStrategy int
+
+const (
+	// ExclusiveLock handles every message while holding the chain's Lock
+	ExclusiveLock LockStrategy = iota
+
+	// SharedGets handles Get messages concurrently, while holding the chain's
+	// Lock for reading. A VM may only declare this if its GetBlock can be
+	// called concurrently with itself.
+	SharedGets
+)
+
 // Context is information about the current execution.
 // [NetworkID] is the ID of the network this context exists within.
 // [ChainID] is the ID of the chain this context exists within.
 // [NodeID] is the ID of this node
+// [Config] is the contents of the chain's config file, or nil if it has none
+// [LockStrategy] may be set by the VM when it's initialized
 type Context struct {
 	NetworkID           uint32
 	ChainID             ids.ID
+	Config              []byte
 	NodeID              ids.ShortID
 	Log                 logging.Logger
 	DecisionDispatcher  *triggers.EventDispatcher
 	ConsensusDispatcher *triggers.EventDispatcher
 	Lock                sync.RWMutex
+	LockStrategy        LockStrategy
 	HTTP                Callable
 	Keystore            Keystore
+	SharedMemory        SharedMemory
 	BCLookup            AliasLookup
 }
 
@@ -43,12 +43,15 @@ func (b *bootstrapper) Initialize(config BootstrapConfig) {
 		numAccepted: b.numBootstrappedVtx,
 		numDropped:  b.numDroppedVtx,
 		state:       b.State,
+		ctx:         b.BootstrapConfig.VMContext(),
+		hooks:       b.BootstrapConfig.AcceptHooks,
 	})
 
 	b.TxBlocked.SetParser(&txParser{
 		numAccepted: b.numBootstrappedTx,
 		numDropped:  b.numDroppedTx,
 		vm:          b.VM,
+		ctx:         b.BootstrapConfig.VMContext(),
 	})
 
 	config.Bootstrapable = b
@@ -94,7 +97,10 @@ func (b *bootstrapper) Put(vdr ids.ShortID, requestID uint32, vtxID ids.ID, vtxB
 		return
 	}
 
-	vtx, err := b.State.ParseVertex(vtxBytes)
+	ctx, cancel := b.BootstrapConfig.RequestContext()
+	defer cancel()
+
+	vtx, err := b.State.ParseVertex(ctx, vtxBytes)
 	if err != nil {
 		b.BootstrapConfig.Context.Log.Warn("ParseVertex failed due to %s for block:\n%s",
 			err,
@@ -206,4 +212,7 @@ func (b *bootstrapper) executeAll(jobs *queue.Jobs, numBlocked prometheus.Gauge)
 			b.BootstrapConfig.Context.Log.Warn("Error executing: %s", err)
 		}
 	}
+	if err := jobs.Commit(); err != nil {
+		b.BootstrapConfig.Context.Log.Warn("Error committing the executed jobs: %s", err)
+	}
 }
@@ -20,7 +20,6 @@ import (
 	"github.com/ava-labs/gecko/snow/consensus/snowstorm"
 	"github.com/ava-labs/gecko/snow/engine/common"
 	"github.com/ava-labs/gecko/snow/engine/common/queue"
-	"github.com/ava-labs/gecko/snow/networking/handler"
 	"github.com/ava-labs/gecko/snow/networking/router"
 	"github.com/ava-labs/gecko/snow/networking/timeout"
 	"github.com/ava-labs/gecko/snow/validators"
@@ -39,8 +38,6 @@ func newConfig(t *testing.T) (BootstrapConfig, ids.ShortID, *common.SenderTest,
 	sender := &common.SenderTest{}
 	state := &stateTest{}
 	vm := &VMTest{}
-	engine := &Transitive{}
-	handler := &handler.Handler{}
 	router := &router.ChainRouter{}
 	timeouts := &timeout.Manager{}
 
@@ -58,7 +55,6 @@ func newConfig(t *testing.T) (BootstrapConfig, ids.ShortID, *common.SenderTest,
 	peerID := peer.ID()
 	peers.Add(peer)
 
-	handler.Initialize(engine, make(chan common.Message), 1)
 	timeouts.Initialize(0)
 	router.Initialize(ctx.Log, timeouts)
 
@@ -69,7 +65,7 @@ func newConfig(t *testing.T) (BootstrapConfig, ids.ShortID, *common.SenderTest,
 		Context:    ctx,
 		Validators: peers,
 		Beacons:    peers,
-		Alpha:      peers.Len()/2 + 1,
+		Alpha:      peers.Weight()/2 + 1,
 		Sender:     sender,
 	}
 	return BootstrapConfig{
@@ -0,0 +1,194 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avalanche
+
+import (
+	"errors"
+	"time"
+
+	"github.com/ava-labs/gecko/database"
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow/choices"
+	"github.com/ava-labs/gecko/utils/hashing"
+	"github.com/ava-labs/gecko/utils/wrappers"
+)
+
+const (
+	// checkpointInterval is the number of polls finished between checkpoints
+	checkpointInterval = 32
+
+	// maxCheckpointAge is the age of the oldest checkpoint a restarted engine
+	// resumes from. After a longer downtime, the network has likely moved on
+	// far enough that bootstrapping is faster than catching up through
+	// consensus.
+	maxCheckpointAge = 10 * time.Minute
+)
+
+var (
+	checkpointKey = []byte("checkpoint")
+
+	errCheckpointTooLarge = errors.New("checkpoint lists more vertices than it has bytes for")
+)
+
+// checkpoint is a snapshot of the engine's consensus state
+type checkpoint struct {
+	// When the checkpoint was taken
+	timestamp time.Time
+	// The accepted frontier
+	frontier []ids.ID
+	// The vertices in consensus that were processing
+	processing []ids.ID
+}
+
+// Bytes returns the binary representation of [c]
+func (c *checkpoint) Bytes() ([]byte, error) {
+	p := wrappers.Packer{MaxSize: wrappers.LongLen + 2*wrappers.IntLen + hashing.HashLen*(len(c.frontier)+len(c.processing))}
+	p.PackLong(uint64(c.timestamp.Unix()))
+	packIDs(&p, c.frontier)
+	packIDs(&p, c.processing)
+	return p.Bytes, p.Err
+}
+
+// parseCheckpoint parses the binary representation of a checkpoint
+func parseCheckpoint(b []byte) (*checkpoint, error) {
+	p := wrappers.Packer{Bytes: b}
+	c := &checkpoint{timestamp: time.Unix(int64(p.UnpackLong()), 0)}
+	c.frontier = unpackIDs(&p)
+	c.processing = unpackIDs(&p)
+	if p.Errored() {
+		return nil, p.Err
+	}
+	return c, nil
+}
+
+func packIDs(p *wrappers.Packer, vtxIDs []ids.ID) {
+	p.PackInt(uint32(len(vtxIDs)))
+	for _, vtxID := range vtxIDs {
+		p.PackFixedBytes(vtxID.Bytes())
+	}
+}
+
+func unpackIDs(p *wrappers.Packer) []ids.ID {
+	numIDs := p.UnpackInt()
+	if p.Errored() {
+		return nil
+	}
+	if uint64(numIDs)*hashing.HashLen > uint64(len(p.Bytes)-p.Offset) {
+		p.Add(errCheckpointTooLarge)
+		return nil
+	}
+	vtxIDs := make([]ids.ID, numIDs)
+	for i := range vtxIDs {
+		vtxID, err := ids.ToID(p.UnpackFixedBytes(hashing.HashLen))
+		if err != nil {
+			p.Add(err)
+			return nil
+		}
+		vtxIDs[i] = vtxID
+	}
+	return vtxIDs
+}
+
+// getCheckpoint returns the checkpoint saved in [db]
+func getCheckpoint(db database.Database) (*checkpoint, error) {
+	b, err := db.Get(checkpointKey)
+	if err != nil {
+		return nil, err
+	}
+	return parseCheckpoint(b)
+}
+
+// putCheckpoint saves [c] in [db], replacing the previous checkpoint
+func putCheckpoint(db database.Database, c *checkpoint) error {
+	b, err := c.Bytes()
+	if err != nil {
+		return err
+	}
+	return db.Put(checkpointKey, b)
+}
+
+// checkpoint saves the accepted frontier and the processing vertices, if
+// checkpoints are enabled
+func (t *Transitive) checkpoint() {
+	if t.Config.Checkpoints == nil || !t.bootstrapped {
+		return
+	}
+
+	// Vertices decided since they were issued are no longer processing
+	for _, vtxID := range t.processing.List() {
+		if vtx, err := t.Config.State.GetVertex(vtxID); err != nil || vtx.Status().Decided() {
+			t.processing.Remove(vtxID)
+		}
+	}
+
+	c := &checkpoint{
+		timestamp:  t.clock.Time(),
+		frontier:   t.Config.State.Edge(),
+		processing: t.processing.List(),
+	}
+	if err := putCheckpoint(t.Config.Checkpoints, c); err != nil {
+		t.Config.Context.Log.Warn("Failed to save a checkpoint due to %s", err)
+		return
+	}
+	t.Config.Context.Log.Verbo("Saved a checkpoint with %d processing vertices", len(c.processing))
+}
+
+// resume consensus from the last checkpoint, rather than bootstrapping. Returns
+// false if there is no recent checkpoint that is consistent with the accepted
+// vertices, in which case the engine should bootstrap.
+func (t *Transitive) resume() bool {
+	if t.Config.Checkpoints == nil {
+		return false
+	}
+
+	c, err := getCheckpoint(t.Config.Checkpoints)
+	switch {
+	case err == database.ErrNotFound:
+		return false
+	case err != nil:
+		t.Config.Context.Log.Warn("Failed to load the checkpoint due to %s", err)
+		return false
+	}
+
+	// Once bootstrapping starts, the checkpoint is stale
+	if err := t.Config.Checkpoints.Delete(checkpointKey); err != nil {
+		t.Config.Context.Log.Warn("Failed to delete the checkpoint due to %s", err)
+		return false
+	}
+
+	if age := t.clock.Time().Sub(c.timestamp); age > maxCheckpointAge {
+		t.Config.Context.Log.Info("Bootstrapping as the last checkpoint was taken %s ago", age)
+		return false
+	}
+	for _, vtxID := range c.frontier {
+		if vtx, err := t.Config.State.GetVertex(vtxID); err != nil || vtx.Status() != choices.Accepted {
+			t.Config.Context.Log.Warn("Bootstrapping as the checkpoint's frontier vertex %s isn't accepted", vtxID)
+			return false
+		}
+	}
+
+	t.Config.Context.Log.Info("Resuming from the checkpoint taken at %s with %d processing vertices", c.timestamp, len(c.processing))
+
+	t.finished = true
+	t.finishBootstrapping()
+
+	vdrs := t.Config.Validators.Sample(1)
+	if len(vdrs) == 0 {
+		t.Config.Context.Log.Warn("Dropping the checkpoint's processing vertices as there are no validators to fetch their ancestors from")
+		return true
+	}
+	vdrID := vdrs[0].ID()
+	// Resuming isn't done on behalf of a request, so it's only cancelled by the
+	// chain shutting down
+	ctx := t.Config.VMContext()
+	for _, vtxID := range c.processing {
+		vtx, err := t.Config.State.GetVertex(vtxID)
+		if err != nil {
+			t.Config.Context.Log.Debug("Dropping the checkpoint's processing vertex %s due to %s", vtxID, err)
+			continue
+		}
+		t.insertFrom(ctx, vdrID, vtx)
+	}
+	return true
+}
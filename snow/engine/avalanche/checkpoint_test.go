@@ -0,0 +1,206 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avalanche
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ava-labs/gecko/database/memdb"
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow/choices"
+	"github.com/ava-labs/gecko/snow/consensus/avalanche"
+	"github.com/ava-labs/gecko/snow/consensus/snowstorm"
+	"github.com/ava-labs/gecko/snow/engine/common"
+	"github.com/ava-labs/gecko/snow/validators"
+	"github.com/ava-labs/gecko/utils/wrappers"
+)
+
+func TestCheckpointRoundTrip(t *testing.T) {
+	c := &checkpoint{
+		timestamp:  time.Unix(1000, 0),
+		frontier:   []ids.ID{GenerateID(), GenerateID()},
+		processing: []ids.ID{GenerateID()},
+	}
+
+	b, err := c.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := parseCheckpoint(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !parsed.timestamp.Equal(c.timestamp) {
+		t.Fatalf("Timestamp should have been %s but was %s", c.timestamp, parsed.timestamp)
+	}
+	if len(parsed.frontier) != len(c.frontier) {
+		t.Fatalf("Frontier should have had %d vertices but had %d", len(c.frontier), len(parsed.frontier))
+	}
+	for i, vtxID := range c.frontier {
+		if !parsed.frontier[i].Equals(vtxID) {
+			t.Fatalf("Frontier vertex %d should have been %s but was %s", i, vtxID, parsed.frontier[i])
+		}
+	}
+	if len(parsed.processing) != 1 || !parsed.processing[0].Equals(c.processing[0]) {
+		t.Fatalf("Processing vertices should have been %v but were %v", c.processing, parsed.processing)
+	}
+}
+
+func TestCheckpointParseTooManyVertices(t *testing.T) {
+	p := wrappers.Packer{MaxSize: wrappers.LongLen + wrappers.IntLen}
+	p.PackLong(1000)
+	p.PackInt(1 << 30)
+	if p.Errored() {
+		t.Fatal(p.Err)
+	}
+
+	if _, err := parseCheckpoint(p.Bytes); err == nil {
+		t.Fatalf("Should have errored due to the number of vertices exceeding the bytes left")
+	}
+}
+
+func TestEngineResumeFromCheckpoint(t *testing.T) {
+	config := DefaultConfig()
+	config.Checkpoints = memdb.New()
+
+	vdr := validators.GenerateRandomValidator(1)
+	vals := validators.NewSet()
+	vals.Add(vdr)
+	config.Validators = vals
+
+	sender := &common.SenderTest{}
+	sender.T = t
+	sender.Default(true)
+	config.Sender = sender
+
+	st := &stateTest{t: t}
+	st.Default(true)
+	config.State = st
+
+	gVtx := &Vtx{
+		id:     GenerateID(),
+		status: choices.Accepted,
+	}
+	// The vertex needs an undecided tx, otherwise consensus accepts it as soon
+	// as it is issued
+	tx := &TestTx{
+		TestTx: snowstorm.TestTx{
+			Identifier: GenerateID(),
+			Stat:       choices.Processing,
+		},
+	}
+	tx.Ins.Add(GenerateID())
+	vtx := &Vtx{
+		parents: []avalanche.Vertex{gVtx},
+		id:      GenerateID(),
+		txs:     []snowstorm.Tx{tx},
+		status:  choices.Processing,
+		bytes:   []byte{1},
+	}
+
+	st.edge = func() []ids.ID { return []ids.ID{gVtx.ID()} }
+	st.getVertex = func(vtxID ids.ID) (avalanche.Vertex, error) {
+		switch {
+		case vtxID.Equals(gVtx.ID()):
+			return gVtx, nil
+		case vtxID.Equals(vtx.ID()):
+			return vtx, nil
+		}
+		t.Fatalf("Unknown vertex")
+		panic("Should have errored")
+	}
+
+	now := time.Unix(1000, 0)
+	if err := putCheckpoint(config.Checkpoints, &checkpoint{
+		timestamp:  now.Add(-time.Minute),
+		frontier:   []ids.ID{gVtx.ID()},
+		processing: []ids.ID{vtx.ID()},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	queried := new(bool)
+	sender.PushQueryF = func(vdrs ids.ShortSet, _ uint32, vtxID ids.ID, _ []byte) {
+		if !vtxID.Equals(vtx.ID()) {
+			t.Fatalf("Queried the wrong vertex")
+		}
+		*queried = true
+	}
+
+	te := &Transitive{}
+	te.Initialize(config)
+	te.clock.Set(now)
+	te.Startup()
+
+	if !te.bootstrapped {
+		t.Fatalf("Should have resumed from the checkpoint without bootstrapping")
+	}
+	if !te.Consensus.VertexIssued(vtx) {
+		t.Fatalf("Should have re-issued the checkpoint's processing vertex")
+	}
+	if !*queried {
+		t.Fatalf("Should have queried the checkpoint's processing vertex")
+	}
+	if _, err := getCheckpoint(config.Checkpoints); err == nil {
+		t.Fatalf("Should have deleted the checkpoint it resumed from")
+	}
+
+	te.checkpoint()
+
+	c, err := getCheckpoint(config.Checkpoints)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(c.processing) != 1 || !c.processing[0].Equals(vtx.ID()) {
+		t.Fatalf("Checkpoint should have included the processing vertex")
+	}
+}
+
+func TestEngineStaleCheckpointBootstraps(t *testing.T) {
+	config := DefaultConfig()
+	config.Checkpoints = memdb.New()
+
+	vdr := validators.GenerateRandomValidator(1)
+	vals := validators.NewSet()
+	vals.Add(vdr)
+	config.Validators = vals
+	config.Beacons = vals
+
+	sender := &common.SenderTest{}
+	sender.T = t
+	sender.Default(true)
+	config.Sender = sender
+
+	st := &stateTest{t: t}
+	st.Default(true)
+	config.State = st
+
+	now := time.Unix(1000, 0)
+	if err := putCheckpoint(config.Checkpoints, &checkpoint{
+		timestamp: now.Add(-2 * maxCheckpointAge),
+		frontier:  []ids.ID{GenerateID()},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	requested := new(bool)
+	sender.GetAcceptedFrontierF = func(ids.ShortSet, uint32) { *requested = true }
+
+	te := &Transitive{}
+	te.Initialize(config)
+	te.clock.Set(now)
+	te.Startup()
+
+	if te.bootstrapped {
+		t.Fatalf("Shouldn't have resumed from a stale checkpoint")
+	}
+	if !*requested {
+		t.Fatalf("Should have started bootstrapping")
+	}
+	if _, err := getCheckpoint(config.Checkpoints); err == nil {
+		t.Fatalf("Should have deleted the stale checkpoint")
+	}
+}
@@ -4,6 +4,7 @@
 package avalanche
 
 import (
+	"github.com/ava-labs/gecko/database"
 	"github.com/ava-labs/gecko/snow/consensus/avalanche"
 )
 
@@ -13,4 +14,14 @@ type Config struct {
 
 	Params    avalanche.Parameters
 	Consensus avalanche.Consensus
+
+	// Checkpoints is where the accepted frontier and processing vertices are
+	// periodically saved, so consensus can resume from them after a restart.
+	// If nil, no checkpoints are saved and the engine always bootstraps.
+	Checkpoints database.Database
+
+	// MaxOutstandingPolls is the most network polls that may be outstanding at
+	// once. Vertices issued past the limit are queried once an outstanding
+	// poll finishes. If 0, the number of outstanding polls isn't limited.
+	MaxOutstandingPolls int
 }
@@ -4,6 +4,8 @@
 package avalanche
 
 import (
+	"context"
+
 	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/snow/consensus/avalanche"
 	"github.com/ava-labs/gecko/snow/engine/common"
@@ -20,14 +22,14 @@ type convincer struct {
 
 func (c *convincer) Dependencies() ids.Set { return c.deps }
 
-func (c *convincer) Fulfill(id ids.ID) {
+func (c *convincer) Fulfill(ctx context.Context, id ids.ID) {
 	c.deps.Remove(id)
-	c.Update()
+	c.Update(ctx)
 }
 
-func (c *convincer) Abandon(ids.ID) { c.abandoned = true }
+func (c *convincer) Abandon(context.Context, ids.ID) { c.abandoned = true }
 
-func (c *convincer) Update() {
+func (c *convincer) Update(context.Context) {
 	if c.abandoned || c.deps.Len() != 0 {
 		return
 	}
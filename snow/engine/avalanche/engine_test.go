@@ -4,6 +4,7 @@
 package avalanche
 
 import (
+	"context"
 	"sort"
 
 	"github.com/ava-labs/gecko/ids"
@@ -38,7 +39,7 @@ func (v *Vtx) DependencyIDs() []ids.ID     { return nil }
 func (v *Vtx) Parents() []avalanche.Vertex { return v.parents }
 func (v *Vtx) Txs() []snowstorm.Tx         { return v.txs }
 func (v *Vtx) Status() choices.Status      { return v.status }
-func (v *Vtx) Accept()                     { v.status = choices.Accepted }
+func (v *Vtx) Accept(context.Context)      { v.status = choices.Accepted }
 func (v *Vtx) Reject()                     { v.status = choices.Rejected }
 func (v *Vtx) Bytes() []byte               { return v.bytes }
 
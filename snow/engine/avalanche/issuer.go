@@ -4,8 +4,11 @@
 package avalanche
 
 import (
+	"context"
+
 	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/snow/consensus/avalanche"
+	"github.com/ava-labs/gecko/utils/logging"
 )
 
 type issuer struct {
@@ -15,27 +18,27 @@ type issuer struct {
 	vtxDeps, txDeps   ids.Set
 }
 
-func (i *issuer) FulfillVtx(id ids.ID) {
+func (i *issuer) FulfillVtx(ctx context.Context, id ids.ID) {
 	i.vtxDeps.Remove(id)
-	i.Update()
+	i.Update(ctx)
 }
 
-func (i *issuer) FulfillTx(id ids.ID) {
+func (i *issuer) FulfillTx(ctx context.Context, id ids.ID) {
 	i.txDeps.Remove(id)
-	i.Update()
+	i.Update(ctx)
 }
 
-func (i *issuer) Abandon() {
+func (i *issuer) Abandon(ctx context.Context) {
 	if !i.abandoned {
 		vtxID := i.vtx.ID()
 		i.t.pending.Remove(vtxID)
 		i.abandoned = true
 
-		i.t.vtxBlocked.Abandon(vtxID)
+		i.t.vtxBlocked.Abandon(ctx, vtxID)
 	}
 }
 
-func (i *issuer) Update() {
+func (i *issuer) Update(ctx context.Context) {
 	if i.abandoned || i.issued || i.vtxDeps.Len() != 0 || i.txDeps.Len() != 0 || i.t.Consensus.VertexIssued(i.vtx) {
 		return
 	}
@@ -45,48 +48,36 @@ func (i *issuer) Update() {
 	i.t.pending.Remove(vtxID)
 
 	for _, tx := range i.vtx.Txs() {
-		if err := tx.Verify(); err != nil {
-			i.t.Config.Context.Log.Debug("Transaction failed verification due to %s, dropping vertex", err)
-			i.t.vtxBlocked.Abandon(vtxID)
+		if err := tx.Verify(ctx); err != nil {
+			i.t.verifyFailureLogs.Log(i.t.Config.Context.Log, logging.Debug, "Transaction failed verification due to %s, dropping vertex", err)
+			i.t.vtxBlocked.Abandon(ctx, vtxID)
 			return
 		}
 	}
 
 	i.t.Config.Context.Log.Verbo("Adding vertex to consensus:\n%s", i.vtx)
 
-	i.t.Consensus.Add(i.vtx)
-
-	p := i.t.Consensus.Parameters()
-	vdrs := i.t.Config.Validators.Sample(p.K) // Validators to sample
+	i.t.Consensus.Add(ctx, i.vtx)
+	i.t.processing.Add(vtxID)
 
-	vdrSet := ids.ShortSet{} // Validators to sample repr. as a set
-	for _, vdr := range vdrs {
-		vdrSet.Add(vdr.ID())
-	}
-
-	i.t.RequestID++
-	if numVdrs := len(vdrs); numVdrs == p.K && i.t.polls.Add(i.t.RequestID, vdrSet.Len()) {
-		i.t.Config.Sender.PushQuery(vdrSet, i.t.RequestID, vtxID, i.vtx.Bytes())
-	} else if numVdrs < p.K {
-		i.t.Config.Context.Log.Error("Query for %s was dropped due to an insufficient number of validators", vtxID)
-	}
+	i.t.query(i.vtx)
 
-	i.t.vtxBlocked.Fulfill(vtxID)
+	i.t.vtxBlocked.Fulfill(ctx, vtxID)
 	for _, tx := range i.vtx.Txs() {
-		i.t.txBlocked.Fulfill(tx.ID())
+		i.t.txBlocked.Fulfill(ctx, tx.ID())
 	}
 }
 
 type vtxIssuer struct{ i *issuer }
 
-func (vi *vtxIssuer) Dependencies() ids.Set { return vi.i.vtxDeps }
-func (vi *vtxIssuer) Fulfill(id ids.ID)     { vi.i.FulfillVtx(id) }
-func (vi *vtxIssuer) Abandon(ids.ID)        { vi.i.Abandon() }
-func (vi *vtxIssuer) Update()               { vi.i.Update() }
+func (vi *vtxIssuer) Dependencies() ids.Set                  { return vi.i.vtxDeps }
+func (vi *vtxIssuer) Fulfill(ctx context.Context, id ids.ID) { vi.i.FulfillVtx(ctx, id) }
+func (vi *vtxIssuer) Abandon(ctx context.Context, _ ids.ID)  { vi.i.Abandon(ctx) }
+func (vi *vtxIssuer) Update(ctx context.Context)             { vi.i.Update(ctx) }
 
 type txIssuer struct{ i *issuer }
 
-func (ti *txIssuer) Dependencies() ids.Set { return ti.i.txDeps }
-func (ti *txIssuer) Fulfill(id ids.ID)     { ti.i.FulfillTx(id) }
-func (ti *txIssuer) Abandon(ids.ID)        { ti.i.Abandon() }
-func (ti *txIssuer) Update()               { ti.i.Update() }
+func (ti *txIssuer) Dependencies() ids.Set                  { return ti.i.txDeps }
+func (ti *txIssuer) Fulfill(ctx context.Context, id ids.ID) { ti.i.FulfillTx(ctx, id) }
+func (ti *txIssuer) Abandon(ctx context.Context, _ ids.ID)  { ti.i.Abandon(ctx) }
+func (ti *txIssuer) Update(ctx context.Context)             { ti.i.Update(ctx) }
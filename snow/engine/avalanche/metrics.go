@@ -7,6 +7,8 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 
 	"github.com/ava-labs/gecko/utils/logging"
+
+	gmetrics "github.com/ava-labs/gecko/utils/metrics"
 )
 
 type metrics struct {
@@ -14,109 +16,28 @@ type metrics struct {
 	numBootstrappedVtx, numDroppedVtx,
 	numBootstrappedTx, numDroppedTx prometheus.Counter
 
-	numPolls, numVtxRequests, numTxRequests, numPendingVtx prometheus.Gauge
-}
+	numPolls, numQueuedPolls, numVtxRequests, numTxRequests, numPendingVtx prometheus.Gauge
 
-// Initialize implements the Engine interface
-func (m *metrics) Initialize(log logging.Logger, namespace string, registerer prometheus.Registerer) {
-	m.numPendingRequests = prometheus.NewGauge(
-		prometheus.GaugeOpts{
-			Namespace: namespace,
-			Name:      "av_bs_vtx_requests",
-			Help:      "Number of pending bootstrap vertex requests",
-		})
-	m.numBlockedVtx = prometheus.NewGauge(
-		prometheus.GaugeOpts{
-			Namespace: namespace,
-			Name:      "av_bs_blocked_vts",
-			Help:      "Number of blocked bootstrap vertices",
-		})
-	m.numBlockedTx = prometheus.NewGauge(
-		prometheus.GaugeOpts{
-			Namespace: namespace,
-			Name:      "av_bs_blocked_txs",
-			Help:      "Number of blocked bootstrap txs",
-		})
-	m.numBootstrappedVtx = prometheus.NewCounter(
-		prometheus.CounterOpts{
-			Namespace: namespace,
-			Name:      "av_bs_accepted_vts",
-			Help:      "Number of accepted vertices",
-		})
-	m.numDroppedVtx = prometheus.NewCounter(
-		prometheus.CounterOpts{
-			Namespace: namespace,
-			Name:      "av_bs_dropped_vts",
-			Help:      "Number of dropped vertices",
-		})
-	m.numBootstrappedTx = prometheus.NewCounter(
-		prometheus.CounterOpts{
-			Namespace: namespace,
-			Name:      "av_bs_accepted_txs",
-			Help:      "Number of accepted txs",
-		})
-	m.numDroppedTx = prometheus.NewCounter(
-		prometheus.CounterOpts{
-			Namespace: namespace,
-			Name:      "av_bs_dropped_txs",
-			Help:      "Number of dropped txs",
-		})
-	m.numPolls = prometheus.NewGauge(
-		prometheus.GaugeOpts{
-			Namespace: namespace,
-			Name:      "av_polls",
-			Help:      "Number of pending network polls",
-		})
-	m.numVtxRequests = prometheus.NewGauge(
-		prometheus.GaugeOpts{
-			Namespace: namespace,
-			Name:      "av_vtx_requests",
-			Help:      "Number of pending vertex requests",
-		})
-	m.numTxRequests = prometheus.NewGauge(
-		prometheus.GaugeOpts{
-			Namespace: namespace,
-			Name:      "av_tx_requests",
-			Help:      "Number of pending transactions",
-		})
-	m.numPendingVtx = prometheus.NewGauge(
-		prometheus.GaugeOpts{
-			Namespace: namespace,
-			Name:      "av_blocked_vts",
-			Help:      "Number of blocked vertices",
-		})
+	bootstrapped prometheus.Gauge
+	pollDuration prometheus.Histogram
+}
 
-	if err := registerer.Register(m.numPendingRequests); err != nil {
-		log.Error("Failed to register av_bs_vtx_requests statistics due to %s", err)
-	}
-	if err := registerer.Register(m.numBlockedVtx); err != nil {
-		log.Error("Failed to register av_bs_blocked_vts statistics due to %s", err)
-	}
-	if err := registerer.Register(m.numBlockedTx); err != nil {
-		log.Error("Failed to register av_bs_blocked_txs statistics due to %s", err)
-	}
-	if err := registerer.Register(m.numBootstrappedVtx); err != nil {
-		log.Error("Failed to register av_bs_accepted_vts statistics due to %s", err)
-	}
-	if err := registerer.Register(m.numDroppedVtx); err != nil {
-		log.Error("Failed to register av_bs_dropped_vts statistics due to %s", err)
-	}
-	if err := registerer.Register(m.numBootstrappedTx); err != nil {
-		log.Error("Failed to register av_bs_accepted_txs statistics due to %s", err)
-	}
-	if err := registerer.Register(m.numDroppedTx); err != nil {
-		log.Error("Failed to register av_bs_dropped_txs statistics due to %s", err)
-	}
-	if err := registerer.Register(m.numPolls); err != nil {
-		log.Error("Failed to register av_polls statistics due to %s", err)
-	}
-	if err := registerer.Register(m.numVtxRequests); err != nil {
-		log.Error("Failed to register av_vtx_requests statistics due to %s", err)
-	}
-	if err := registerer.Register(m.numTxRequests); err != nil {
-		log.Error("Failed to register av_tx_requests statistics due to %s", err)
-	}
-	if err := registerer.Register(m.numPendingVtx); err != nil {
-		log.Error("Failed to register av_blocked_vts statistics due to %s", err)
-	}
+// Initialize implements the Engine interface. The metrics are labelled with
+// [chain].
+func (m *metrics) Initialize(log logging.Logger, chain string, registerer prometheus.Registerer) {
+	r := gmetrics.NewChainRegisterer(log, registerer, chain, "engine")
+	m.numPendingRequests = r.NewGauge("av_bs_vtx_requests", "Number of pending bootstrap vertex requests")
+	m.numBlockedVtx = r.NewGauge("av_bs_blocked_vts", "Number of blocked bootstrap vertices")
+	m.numBlockedTx = r.NewGauge("av_bs_blocked_txs", "Number of blocked bootstrap txs")
+	m.numBootstrappedVtx = r.NewCounter("av_bs_accepted_vts", "Number of accepted vertices")
+	m.numDroppedVtx = r.NewCounter("av_bs_dropped_vts", "Number of dropped vertices")
+	m.numBootstrappedTx = r.NewCounter("av_bs_accepted_txs", "Number of accepted txs")
+	m.numDroppedTx = r.NewCounter("av_bs_dropped_txs", "Number of dropped txs")
+	m.numPolls = r.NewGauge("av_polls", "Number of pending network polls")
+	m.numQueuedPolls = r.NewGauge("av_queued_polls", "Number of network polls waiting for an outstanding poll to finish")
+	m.numVtxRequests = r.NewGauge("av_vtx_requests", "Number of pending vertex requests")
+	m.numTxRequests = r.NewGauge("av_tx_requests", "Number of pending transactions")
+	m.numPendingVtx = r.NewGauge("av_blocked_vts", "Number of blocked vertices")
+	m.bootstrapped = r.NewGauge("av_bs_finished", "1 if bootstrapping has finished, 0 otherwise")
+	m.pollDuration = r.NewHistogram("av_poll_duration_seconds", "Duration of finished network polls", gmetrics.LatencyBuckets)
 }
@@ -0,0 +1,52 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avalanche
+
+import (
+	"github.com/ava-labs/gecko/snow/consensus/avalanche"
+)
+
+// Parameters returns the consensus parameters in effect. Assumes the context
+// lock is held.
+func (t *Transitive) Parameters() avalanche.Parameters { return t.Params }
+
+// SetParameters schedules [params] to replace the consensus parameters, if
+// they're valid. The parameters are replaced once no polls are outstanding, so
+// that every poll is issued and recorded with the same parameters. Until then,
+// new queries are queued. Returns true if the parameters were replaced
+// immediately, rather than once the outstanding polls finish. Assumes the
+// context lock is held.
+func (t *Transitive) SetParameters(params avalanche.Parameters) (bool, error) {
+	// The metrics were registered when the engine was initialized
+	params.Namespace = t.Params.Namespace
+	params.Metrics = t.Params.Metrics
+	if err := params.Valid(); err != nil {
+		return false, err
+	}
+
+	t.pendingParams = &params
+	t.applyParameters()
+	if t.pendingParams != nil {
+		t.Config.Context.Log.Info("Consensus parameters will be replaced once the outstanding polls finish")
+		return false, nil
+	}
+	return true, nil
+}
+
+// applyParameters replaces the consensus parameters with the pending
+// parameters, if there are any and no polls are outstanding
+func (t *Transitive) applyParameters() {
+	if t.pendingParams == nil || len(t.polls.m) != 0 {
+		return
+	}
+
+	t.Params = *t.pendingParams
+	t.pendingParams = nil
+	if t.bootstrapped {
+		t.Consensus.SetParameters(t.Params)
+	}
+
+	t.Config.Context.Log.Info("Replaced the consensus parameters with K = %d, Alpha = %d, BetaVirtuous = %d, BetaRogue = %d, Parents = %d, BatchSize = %d",
+		t.Params.K, t.Params.Alpha, t.Params.BetaVirtuous, t.Params.BetaRogue, t.Params.Parents, t.Params.BatchSize)
+}
@@ -0,0 +1,201 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avalanche
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow/choices"
+	"github.com/ava-labs/gecko/snow/consensus/avalanche"
+	"github.com/ava-labs/gecko/snow/consensus/snowstorm"
+	"github.com/ava-labs/gecko/snow/engine/common"
+	"github.com/ava-labs/gecko/snow/validators"
+)
+
+func TestEngineSetParametersInvalid(t *testing.T) {
+	config := DefaultConfig()
+
+	te := &Transitive{}
+	te.Initialize(config)
+
+	params := te.Parameters()
+	params.Parents = 1
+	if _, err := te.SetParameters(params); err == nil {
+		t.Fatalf("Should have errored due to too few parents")
+	}
+	if p := te.Parameters(); p.Parents != config.Params.Parents {
+		t.Fatalf("Shouldn't have replaced the parameters with invalid parameters")
+	}
+}
+
+func TestEngineSetParametersBetweenPolls(t *testing.T) {
+	config := DefaultConfig()
+
+	sender := &common.SenderTest{}
+	sender.T = t
+	config.Sender = sender
+
+	sender.Default(true)
+
+	vdr := validators.GenerateRandomValidator(1)
+
+	vals := validators.NewSet()
+	config.Validators = vals
+
+	vals.Add(vdr)
+
+	st := &stateTest{t: t}
+	config.State = st
+
+	st.Default(true)
+
+	gVtx := &Vtx{
+		id:     GenerateID(),
+		status: choices.Accepted,
+	}
+
+	st.edge = func() []ids.ID { return []ids.ID{gVtx.ID()} }
+	st.getVertex = func(id ids.ID) (avalanche.Vertex, error) {
+		if id.Equals(gVtx.ID()) {
+			return gVtx, nil
+		}
+		t.Fatalf("Unknown vertex")
+		panic("Should have errored")
+	}
+	st.buildVertex = func(ids.Set, []snowstorm.Tx) (avalanche.Vertex, error) {
+		return &Vtx{
+			parents: []avalanche.Vertex{gVtx},
+			id:      GenerateID(),
+			status:  choices.Processing,
+			bytes:   []byte{1},
+		}, nil
+	}
+
+	te := &Transitive{}
+	te.Initialize(config)
+	te.finishBootstrapping()
+
+	requestID := new(uint32)
+	sender.PushQueryF = func(_ ids.ShortSet, reqID uint32, _ ids.ID, _ []byte) { *requestID = reqID }
+
+	te.repoll(context.Background())
+
+	params := te.Parameters()
+	params.Metrics = nil
+	params.BetaVirtuous = 2
+	params.BetaRogue = 3
+	params.Parents = 3
+	if applied, err := te.SetParameters(params); err != nil {
+		t.Fatal(err)
+	} else if applied {
+		t.Fatalf("Shouldn't have replaced the parameters while a poll is outstanding")
+	}
+	if p := te.Consensus.Parameters(); p.BetaVirtuous != 1 {
+		t.Fatalf("Shouldn't have replaced the consensus parameters while a poll is outstanding")
+	}
+
+	te.QueryFailed(vdr.ID(), *requestID)
+
+	if p := te.Parameters(); p.BetaVirtuous != 2 || p.BetaRogue != 3 || p.Parents != 3 {
+		t.Fatalf("Should have replaced the parameters once the poll finished")
+	}
+	if p := te.Consensus.Parameters(); p.BetaVirtuous != 2 || p.BetaRogue != 3 {
+		t.Fatalf("Should have replaced the consensus parameters once the poll finished")
+	}
+	if p := te.Parameters(); p.Metrics == nil {
+		t.Fatalf("Should have kept the metrics registerer")
+	}
+}
+
+func TestEngineSetParametersWaitsForOutstandingPolls(t *testing.T) {
+	config := DefaultConfig()
+
+	sender := &common.SenderTest{}
+	sender.T = t
+	config.Sender = sender
+
+	sender.Default(true)
+
+	vdr := validators.GenerateRandomValidator(1)
+
+	vals := validators.NewSet()
+	config.Validators = vals
+
+	vals.Add(vdr)
+
+	st := &stateTest{t: t}
+	config.State = st
+
+	st.Default(true)
+
+	gVtx := &Vtx{
+		id:     GenerateID(),
+		status: choices.Accepted,
+	}
+
+	st.edge = func() []ids.ID { return []ids.ID{gVtx.ID()} }
+	st.getVertex = func(id ids.ID) (avalanche.Vertex, error) {
+		if id.Equals(gVtx.ID()) {
+			return gVtx, nil
+		}
+		t.Fatalf("Unknown vertex")
+		panic("Should have errored")
+	}
+	st.buildVertex = func(ids.Set, []snowstorm.Tx) (avalanche.Vertex, error) {
+		return &Vtx{
+			parents: []avalanche.Vertex{gVtx},
+			id:      GenerateID(),
+			status:  choices.Processing,
+			bytes:   []byte{1},
+		}, nil
+	}
+
+	te := &Transitive{}
+	te.Initialize(config)
+	te.finishBootstrapping()
+
+	requestIDs := []uint32(nil)
+	sender.PushQueryF = func(_ ids.ShortSet, reqID uint32, _ ids.ID, _ []byte) { requestIDs = append(requestIDs, reqID) }
+
+	te.repoll(context.Background())
+	te.repoll(context.Background())
+
+	params := te.Parameters()
+	params.BetaVirtuous = 2
+	params.BetaRogue = 3
+	if applied, err := te.SetParameters(params); err != nil {
+		t.Fatal(err)
+	} else if applied {
+		t.Fatalf("Shouldn't have replaced the parameters while polls are outstanding")
+	}
+
+	// Queries issued while the parameters are waiting to be replaced are queued
+	vtx := &Vtx{
+		parents: []avalanche.Vertex{gVtx},
+		id:      GenerateID(),
+		status:  choices.Processing,
+		bytes:   []byte{2},
+	}
+	te.query(vtx)
+	if len(requestIDs) != 2 || len(te.queuedQueries) != 1 {
+		t.Fatalf("Should have queued the query")
+	}
+
+	te.QueryFailed(vdr.ID(), requestIDs[0])
+
+	if p := te.Consensus.Parameters(); p.BetaVirtuous != 1 {
+		t.Fatalf("Shouldn't have replaced the consensus parameters while a poll is outstanding")
+	}
+
+	te.QueryFailed(vdr.ID(), requestIDs[1])
+
+	if p := te.Consensus.Parameters(); p.BetaVirtuous != 2 || p.BetaRogue != 3 {
+		t.Fatalf("Should have replaced the consensus parameters once the polls finished")
+	}
+	if len(requestIDs) != 3 || len(te.queuedQueries) != 0 {
+		t.Fatalf("Should have sent the queued query once the parameters were replaced")
+	}
+}
@@ -6,6 +6,7 @@ package avalanche
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 
@@ -30,9 +31,10 @@ import (
 // nodes.
 
 type polls struct {
-	log      logging.Logger
-	numPolls prometheus.Gauge
-	m        map[uint32]poll
+	log          logging.Logger
+	numPolls     prometheus.Gauge
+	pollDuration prometheus.Histogram
+	m            map[uint32]poll
 }
 
 // Add to the current set of polls
@@ -42,6 +44,7 @@ func (p *polls) Add(requestID uint32, numPolled int) bool {
 	poll, exists := p.m[requestID]
 	if !exists {
 		poll.numPending = numPolled
+		poll.start = time.Now()
 		p.m[requestID] = poll
 
 		p.numPolls.Set(float64(len(p.m))) // Tracks performance statistics
@@ -63,7 +66,8 @@ func (p *polls) Vote(requestID uint32, vdr ids.ShortID, votes []ids.ID) (ids.Uni
 	if poll.Finished() {
 		p.log.Verbo("Poll is finished")
 		delete(p.m, requestID)
-		p.numPolls.Set(float64(len(p.m))) // Tracks performance statistics
+		p.numPolls.Set(float64(len(p.m)))                        // Tracks performance statistics
+		p.pollDuration.Observe(time.Since(poll.start).Seconds()) // Tracks performance statistics
 		return poll.votes, true
 	}
 	p.m[requestID] = poll
@@ -85,6 +89,8 @@ func (p *polls) String() string {
 type poll struct {
 	votes      ids.UniqueBag
 	numPending int
+	// When the poll was sent
+	start time.Time
 }
 
 // Vote registers a vote for this poll
@@ -0,0 +1,66 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avalanche
+
+import (
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow/consensus/avalanche"
+)
+
+// query sends a push query for [vtx] to a sample of validators. If
+// MaxOutstandingPolls polls are outstanding, or the consensus parameters are
+// waiting to be replaced, the query is queued until a poll finishes, so that a
+// flood of vertices can't start an unbounded number of polls.
+func (t *Transitive) query(vtx avalanche.Vertex) {
+	if t.holdQueries() {
+		t.queuedQueries = append(t.queuedQueries, vtx)
+		t.numQueuedPolls.Set(float64(len(t.queuedQueries)))
+		return
+	}
+	t.pushQuery(vtx)
+}
+
+// sendQueuedQueries sends the queued queries, oldest first, until
+// MaxOutstandingPolls polls are outstanding or the consensus parameters are
+// waiting to be replaced. Vertices that were decided while their query was
+// queued aren't queried.
+func (t *Transitive) sendQueuedQueries() {
+	for len(t.queuedQueries) > 0 && !t.holdQueries() {
+		vtx := t.queuedQueries[0]
+		t.queuedQueries = t.queuedQueries[1:]
+		if !vtx.Status().Decided() {
+			t.pushQuery(vtx)
+		}
+	}
+	if len(t.queuedQueries) == 0 {
+		t.queuedQueries = nil
+	}
+	t.numQueuedPolls.Set(float64(len(t.queuedQueries)))
+}
+
+// holdQueries returns true if new queries must be queued rather than sent.
+// While the consensus parameters are waiting to be replaced, no polls are
+// started, so that the outstanding polls finish and the parameters are replaced.
+func (t *Transitive) holdQueries() bool {
+	return t.pendingParams != nil ||
+		(t.MaxOutstandingPolls > 0 && len(t.polls.m) >= t.MaxOutstandingPolls)
+}
+
+func (t *Transitive) pushQuery(vtx avalanche.Vertex) {
+	p := t.Consensus.Parameters()
+	vdrs := t.Config.Validators.Sample(p.K) // Validators to sample
+
+	vdrSet := ids.ShortSet{} // Validators to sample repr. as a set
+	for _, vdr := range vdrs {
+		vdrSet.Add(vdr.ID())
+	}
+
+	vtxID := vtx.ID()
+	t.RequestID++
+	if numVdrs := len(vdrs); numVdrs == p.K && t.polls.Add(t.RequestID, vdrSet.Len()) {
+		t.Config.Sender.PushQuery(vdrSet, t.RequestID, vtxID, vtx.Bytes())
+	} else if numVdrs < p.K {
+		t.Config.Context.Log.Error("Query for %s was dropped due to an insufficient number of validators", vtxID)
+	}
+}
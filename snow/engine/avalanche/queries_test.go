@@ -0,0 +1,94 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avalanche
+
+import (
+	"testing"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow/choices"
+	"github.com/ava-labs/gecko/snow/consensus/avalanche"
+	"github.com/ava-labs/gecko/snow/engine/common"
+	"github.com/ava-labs/gecko/snow/validators"
+)
+
+func TestEngineMaxOutstandingPolls(t *testing.T) {
+	config := DefaultConfig()
+	config.MaxOutstandingPolls = 1
+
+	sender := &common.SenderTest{}
+	sender.T = t
+	config.Sender = sender
+
+	sender.Default(true)
+
+	vdr := validators.GenerateRandomValidator(1)
+
+	vals := validators.NewSet()
+	config.Validators = vals
+
+	vals.Add(vdr)
+
+	st := &stateTest{t: t}
+	config.State = st
+
+	st.Default(true)
+
+	gVtx := &Vtx{
+		id:     GenerateID(),
+		status: choices.Accepted,
+	}
+
+	st.edge = func() []ids.ID { return []ids.ID{gVtx.ID()} }
+	st.getVertex = func(id ids.ID) (avalanche.Vertex, error) {
+		if id.Equals(gVtx.ID()) {
+			return gVtx, nil
+		}
+		t.Fatalf("Unknown vertex")
+		panic("Should have errored")
+	}
+
+	te := &Transitive{}
+	te.Initialize(config)
+	te.finishBootstrapping()
+
+	vtx0 := &Vtx{
+		parents: []avalanche.Vertex{gVtx},
+		id:      GenerateID(),
+		status:  choices.Processing,
+		bytes:   []byte{1},
+	}
+	vtx1 := &Vtx{
+		parents: []avalanche.Vertex{gVtx},
+		id:      GenerateID(),
+		status:  choices.Processing,
+		bytes:   []byte{2},
+	}
+
+	queried := []ids.ID(nil)
+	requestID := new(uint32)
+	sender.PushQueryF = func(_ ids.ShortSet, reqID uint32, vtxID ids.ID, _ []byte) {
+		*requestID = reqID
+		queried = append(queried, vtxID)
+	}
+
+	te.query(vtx0)
+	te.query(vtx1)
+
+	if len(queried) != 1 || !queried[0].Equals(vtx0.ID()) {
+		t.Fatalf("Should have only queried the first vertex")
+	}
+	if len(te.queuedQueries) != 1 {
+		t.Fatalf("Should have queued the query for the second vertex")
+	}
+
+	te.QueryFailed(vdr.ID(), *requestID)
+
+	if len(queried) != 2 || !queried[1].Equals(vtx1.ID()) {
+		t.Fatalf("Should have queried the second vertex once the first poll finished")
+	}
+	if len(te.queuedQueries) != 0 {
+		t.Fatalf("Shouldn't have any queued queries")
+	}
+}
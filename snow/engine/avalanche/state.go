@@ -4,6 +4,8 @@
 package avalanche
 
 import (
+	"context"
+
 	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/snow/consensus/avalanche"
 	"github.com/ava-labs/gecko/snow/consensus/snowstorm"
@@ -14,8 +16,9 @@ type State interface {
 	// Create a new vertex from the contents of a vertex
 	BuildVertex(parentIDs ids.Set, txs []snowstorm.Tx) (avalanche.Vertex, error)
 
-	// Attempt to convert a stream of bytes into a vertex
-	ParseVertex(vertex []byte) (avalanche.Vertex, error)
+	// Attempt to convert a stream of bytes into a vertex. [ctx] is passed to
+	// the VM while the vertex's transactions are parsed.
+	ParseVertex(ctx context.Context, vertex []byte) (avalanche.Vertex, error)
 
 	// GetVertex attempts to load a vertex by hash from storage
 	GetVertex(vtxID ids.ID) (avalanche.Vertex, error)
@@ -6,6 +6,7 @@
 package state
 
 import (
+	"context"
 	"errors"
 
 	"github.com/ava-labs/gecko/cache"
@@ -60,8 +61,8 @@ func (s *Serializer) Initialize(ctx *snow.Context, vm avaeng.DAGVM, db database.
 }
 
 // ParseVertex implements the avalanche.State interface
-func (s *Serializer) ParseVertex(b []byte) (avacon.Vertex, error) {
-	vtx, err := s.parseVertex(b)
+func (s *Serializer) ParseVertex(ctx context.Context, b []byte) (avacon.Vertex, error) {
+	vtx, err := s.parseVertex(ctx, b)
 	if err != nil {
 		return nil, err
 	}
@@ -129,9 +130,9 @@ func (s *Serializer) GetVertex(vtxID ids.ID) (avacon.Vertex, error) { return s.g
 // Edge implements the avalanche.State interface
 func (s *Serializer) Edge() []ids.ID { return s.edge.List() }
 
-func (s *Serializer) parseVertex(b []byte) (*vertex, error) {
+func (s *Serializer) parseVertex(ctx context.Context, b []byte) (*vertex, error) {
 	vtx := &vertex{}
-	if err := vtx.Unmarshal(b, s.vm); err != nil {
+	if err := vtx.Unmarshal(ctx, b, s.vm); err != nil {
 		return nil, err
 	} else if !vtx.chainID.Equals(s.ctx.ChainID) {
 		return nil, errWrongChainID
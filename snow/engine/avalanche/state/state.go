@@ -4,6 +4,8 @@
 package state
 
 import (
+	"context"
+
 	"github.com/ava-labs/gecko/cache"
 	"github.com/ava-labs/gecko/database"
 	"github.com/ava-labs/gecko/ids"
@@ -27,8 +29,9 @@ func (s *state) Vertex(id ids.ID) *vertex {
 	}
 
 	if b, err := s.db.Get(id.Bytes()); err == nil {
-		// The key was in the database
-		if vtx, err := s.serializer.parseVertex(b); err == nil {
+		// The key was in the database. Loading a vertex isn't done on behalf of
+		// any one request, and the result is cached, so it isn't cancelled.
+		if vtx, err := s.serializer.parseVertex(context.Background(), b); err == nil {
 			s.dbCache.Put(id, vtx) // Cache the element
 			return vtx
 		}
@@ -4,6 +4,7 @@
 package state
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
@@ -76,7 +77,7 @@ func (vtx *uniqueVertex) setStatus(status choices.Status) {
 
 func (vtx *uniqueVertex) ID() ids.ID { return vtx.vtxID }
 
-func (vtx *uniqueVertex) Accept() {
+func (vtx *uniqueVertex) Accept(context.Context) {
 	vtx.setStatus(choices.Accepted)
 
 	vtx.serializer.edge.Add(vtx.vtxID)
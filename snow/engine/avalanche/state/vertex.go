@@ -5,6 +5,7 @@ package state
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"sort"
 
@@ -87,8 +88,9 @@ func (vtx *vertex) Marshal() ([]byte, error) {
 }
 
 // Unmarshal attempts to set the contents of this vertex to the value encoded in
-// the stream of bytes.
-func (vtx *vertex) Unmarshal(b []byte, vm avalanche.DAGVM) error {
+// the stream of bytes. [ctx] is passed to [vm] while the transactions are
+// parsed.
+func (vtx *vertex) Unmarshal(ctx context.Context, b []byte, vm avalanche.DAGVM) error {
 	p := wrappers.Packer{Bytes: b}
 
 	if codecID := ID(p.UnpackInt()); codecID != CustomID {
@@ -106,7 +108,7 @@ func (vtx *vertex) Unmarshal(b []byte, vm avalanche.DAGVM) error {
 
 	txs := []snowstorm.Tx(nil)
 	for i := p.UnpackInt(); i > 0 && !p.Errored(); i-- {
-		tx, err := vm.ParseTx(p.UnpackBytes())
+		tx, err := vm.ParseTx(ctx, p.UnpackBytes())
 		p.Add(err)
 		txs = append(txs, tx)
 	}
@@ -4,6 +4,7 @@
 package avalanche
 
 import (
+	"context"
 	"errors"
 	"testing"
 
@@ -39,7 +40,7 @@ func (s *stateTest) Default(cant bool) {
 	s.cantSaveEdge = cant
 }
 
-func (s *stateTest) ParseVertex(b []byte) (avalanche.Vertex, error) {
+func (s *stateTest) ParseVertex(_ context.Context, b []byte) (avalanche.Vertex, error) {
 	if s.parseVertex != nil {
 		return s.parseVertex(b)
 	} else if s.cantParseVertex && s.t != nil {
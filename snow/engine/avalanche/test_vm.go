@@ -4,6 +4,7 @@
 package avalanche
 
 import (
+	"context"
 	"errors"
 
 	"github.com/ava-labs/gecko/ids"
@@ -52,7 +53,7 @@ func (vm *VMTest) PendingTxs() []snowstorm.Tx {
 }
 
 // ParseTx ...
-func (vm *VMTest) ParseTx(b []byte) (snowstorm.Tx, error) {
+func (vm *VMTest) ParseTx(_ context.Context, b []byte) (snowstorm.Tx, error) {
 	if vm.ParseTxF != nil {
 		return vm.ParseTxF(b)
 	}
@@ -74,7 +75,7 @@ func (vm *VMTest) IssueTx(b []byte, issued, finalized func(choices.Status)) (ids
 }
 
 // GetTx ...
-func (vm *VMTest) GetTx(txID ids.ID) (snowstorm.Tx, error) {
+func (vm *VMTest) GetTx(_ context.Context, txID ids.ID) (snowstorm.Tx, error) {
 	if vm.GetTxF != nil {
 		return vm.GetTxF(txID)
 	}
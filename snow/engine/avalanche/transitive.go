@@ -4,6 +4,9 @@
 package avalanche
 
 import (
+	"context"
+	"time"
+
 	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/snow"
 	"github.com/ava-labs/gecko/snow/consensus/avalanche"
@@ -11,7 +14,9 @@ import (
 	"github.com/ava-labs/gecko/snow/engine/common"
 	"github.com/ava-labs/gecko/snow/events"
 	"github.com/ava-labs/gecko/utils/formatting"
+	"github.com/ava-labs/gecko/utils/logging"
 	"github.com/ava-labs/gecko/utils/random"
+	"github.com/ava-labs/gecko/utils/timer"
 )
 
 // Transitive implements the Engine interface by attempting to fetch all
@@ -30,6 +35,28 @@ type Transitive struct {
 	// txBlocked tracks operations that are blocked on transactions
 	vtxBlocked, txBlocked events.Blocker
 
+	// processing tracks the vertices added to consensus since the last
+	// checkpoint, along with those that were processing at the last checkpoint
+	processing ids.Set
+
+	// numPollsSinceCheckpoint is the number of polls finished since the last
+	// checkpoint was saved
+	numPollsSinceCheckpoint int
+
+	// queuedQueries are the vertices, oldest first, waiting to be queried
+	// because MaxOutstandingPolls polls were outstanding when they were issued
+	queuedQueries []avalanche.Vertex
+
+	clock timer.Clock
+
+	// pendingParams, if non-nil, replace the consensus parameters once no
+	// polls are outstanding
+	pendingParams *avalanche.Parameters
+
+	// A peer can flood the engine with unparsable vertices and invalid
+	// transactions, so their failures are logged at most once a second
+	parseFailureLogs, verifyFailureLogs logging.Sampler
+
 	bootstrapped bool
 }
 
@@ -45,7 +72,11 @@ func (t *Transitive) Initialize(config Config) {
 
 	t.polls.log = config.Context.Log
 	t.polls.numPolls = t.numPolls
+	t.polls.pollDuration = t.pollDuration
 	t.polls.m = make(map[uint32]poll)
+
+	t.parseFailureLogs.Interval = time.Second
+	t.verifyFailureLogs.Interval = time.Second
 }
 
 func (t *Transitive) finishBootstrapping() {
@@ -60,11 +91,22 @@ func (t *Transitive) finishBootstrapping() {
 	}
 	t.Consensus.Initialize(t.Config.Context, t.Params, frontier)
 	t.bootstrapped = true
+	t.metrics.bootstrapped.Set(1)
+}
+
+// Startup implements the Engine interface. If a recent checkpoint was saved,
+// consensus resumes from it. Otherwise, the engine bootstraps.
+func (t *Transitive) Startup() {
+	if t.resume() {
+		return
+	}
+	t.bootstrapper.Startup()
 }
 
 // Shutdown implements the Engine interface
 func (t *Transitive) Shutdown() {
 	t.Config.Context.Log.Info("Shutting down Avalanche consensus")
+	t.checkpoint()
 	t.Config.VM.Shutdown()
 }
 
@@ -88,15 +130,22 @@ func (t *Transitive) Put(vdr ids.ShortID, requestID uint32, vtxID ids.ID, vtxByt
 		return
 	}
 
-	vtx, err := t.Config.State.ParseVertex(vtxBytes)
+	ctx, cancel := t.Config.RequestContext()
+	defer cancel()
+
+	t.put(ctx, vdr, requestID, vtxID, vtxBytes)
+}
+
+func (t *Transitive) put(ctx context.Context, vdr ids.ShortID, requestID uint32, vtxID ids.ID, vtxBytes []byte) {
+	vtx, err := t.Config.State.ParseVertex(ctx, vtxBytes)
 	if err != nil {
-		t.Config.Context.Log.Warn("ParseVertex failed due to %s for block:\n%s",
+		t.parseFailureLogs.Log(t.Config.Context.Log, logging.Warn, "ParseVertex failed due to %s for block:\n%s",
 			err,
 			formatting.DumpBytes{Bytes: vtxBytes})
-		t.GetFailed(vdr, requestID, vtxID)
+		t.getFailed(ctx, vtxID)
 		return
 	}
-	t.insertFrom(vdr, vtx)
+	t.insertFrom(ctx, vdr, vtx)
 }
 
 // GetFailed implements the Engine interface
@@ -106,13 +155,20 @@ func (t *Transitive) GetFailed(vdr ids.ShortID, requestID uint32, vtxID ids.ID)
 		return
 	}
 
+	ctx, cancel := t.Config.RequestContext()
+	defer cancel()
+
+	t.getFailed(ctx, vtxID)
+}
+
+func (t *Transitive) getFailed(ctx context.Context, vtxID ids.ID) {
 	t.pending.Remove(vtxID)
-	t.vtxBlocked.Abandon(vtxID)
+	t.vtxBlocked.Abandon(ctx, vtxID)
 	t.vtxReqs.Remove(vtxID)
 
 	if t.vtxReqs.Len() == 0 {
 		for _, txID := range t.missingTxs.List() {
-			t.txBlocked.Abandon(txID)
+			t.txBlocked.Abandon(ctx, txID)
 		}
 		t.missingTxs.Clear()
 	}
@@ -130,6 +186,13 @@ func (t *Transitive) PullQuery(vdr ids.ShortID, requestID uint32, vtxID ids.ID)
 		return
 	}
 
+	ctx, cancel := t.Config.RequestContext()
+	defer cancel()
+
+	t.pullQuery(ctx, vdr, requestID, vtxID)
+}
+
+func (t *Transitive) pullQuery(ctx context.Context, vdr ids.ShortID, requestID uint32, vtxID ids.ID) {
 	c := &convincer{
 		consensus: t.Consensus,
 		sender:    t.Config.Sender,
@@ -137,11 +200,11 @@ func (t *Transitive) PullQuery(vdr ids.ShortID, requestID uint32, vtxID ids.ID)
 		requestID: requestID,
 	}
 
-	if !t.reinsertFrom(vdr, vtxID) {
+	if !t.reinsertFrom(ctx, vdr, vtxID) {
 		c.deps.Add(vtxID)
 	}
 
-	t.vtxBlocked.Register(c)
+	t.vtxBlocked.Register(ctx, c)
 }
 
 // PushQuery implements the Engine interface
@@ -151,8 +214,11 @@ func (t *Transitive) PushQuery(vdr ids.ShortID, requestID uint32, vtxID ids.ID,
 		return
 	}
 
-	t.Put(vdr, requestID, vtxID, vtx)
-	t.PullQuery(vdr, requestID, vtxID)
+	ctx, cancel := t.Config.RequestContext()
+	defer cancel()
+
+	t.put(ctx, vdr, requestID, vtxID, vtx)
+	t.pullQuery(ctx, vdr, requestID, vtxID)
 }
 
 // Chits implements the Engine interface
@@ -162,6 +228,9 @@ func (t *Transitive) Chits(vdr ids.ShortID, requestID uint32, votes ids.Set) {
 		return
 	}
 
+	ctx, cancel := t.Config.RequestContext()
+	defer cancel()
+
 	v := &voter{
 		t:         t,
 		vdr:       vdr,
@@ -170,12 +239,12 @@ func (t *Transitive) Chits(vdr ids.ShortID, requestID uint32, votes ids.Set) {
 	}
 	voteList := votes.List()
 	for _, vote := range voteList {
-		if !t.reinsertFrom(vdr, vote) {
+		if !t.reinsertFrom(ctx, vdr, vote) {
 			v.deps.Add(vote)
 		}
 	}
 
-	t.vtxBlocked.Register(v)
+	t.vtxBlocked.Register(ctx, v)
 }
 
 // QueryFailed implements the Engine interface
@@ -190,28 +259,56 @@ func (t *Transitive) Notify(msg common.Message) {
 		return
 	}
 
+	ctx, cancel := t.Config.RequestContext()
+	defer cancel()
+
 	switch msg {
 	case common.PendingTxs:
 		txs := t.Config.VM.PendingTxs()
-		t.batch(txs, false /*=force*/, false /*=empty*/)
+		t.batch(ctx, txs, false /*=force*/, false /*=empty*/)
+	}
+}
+
+// Gossip implements the Engine interface
+func (t *Transitive) Gossip() {
+	if !t.bootstrapped {
+		return
+	}
+	t.Config.Sender.GossipFrontier(t.CurrentAcceptedFrontier())
+}
+
+// GossipFrontier implements the Engine interface. The vertices in the gossiped
+// frontier that this engine doesn't have are fetched from the peer, so that
+// this engine catches up without waiting to be queried about them.
+func (t *Transitive) GossipFrontier(vdr ids.ShortID, vtxIDs ids.Set) {
+	if !t.bootstrapped {
+		t.bootstrapper.GossipFrontier(vdr, vtxIDs)
+		return
+	}
+
+	ctx, cancel := t.Config.RequestContext()
+	defer cancel()
+
+	for _, vtxID := range vtxIDs.List() {
+		t.reinsertFrom(ctx, vdr, vtxID)
 	}
 }
 
-func (t *Transitive) repoll() {
+func (t *Transitive) repoll(ctx context.Context) {
 	txs := t.Config.VM.PendingTxs()
-	t.batch(txs, false /*=force*/, true /*=empty*/)
+	t.batch(ctx, txs, false /*=force*/, true /*=empty*/)
 }
 
-func (t *Transitive) reinsertFrom(vdr ids.ShortID, vtxID ids.ID) bool {
+func (t *Transitive) reinsertFrom(ctx context.Context, vdr ids.ShortID, vtxID ids.ID) bool {
 	vtx, err := t.Config.State.GetVertex(vtxID)
 	if err != nil {
 		t.sendRequest(vdr, vtxID)
 		return false
 	}
-	return t.insertFrom(vdr, vtx)
+	return t.insertFrom(ctx, vdr, vtx)
 }
 
-func (t *Transitive) insertFrom(vdr ids.ShortID, vtx avalanche.Vertex) bool {
+func (t *Transitive) insertFrom(ctx context.Context, vdr ids.ShortID, vtx avalanche.Vertex) bool {
 	issued := true
 	vts := []avalanche.Vertex{vtx}
 	for len(vts) > 0 {
@@ -235,12 +332,12 @@ func (t *Transitive) insertFrom(vdr ids.ShortID, vtx avalanche.Vertex) bool {
 			}
 		}
 
-		t.insert(vtx)
+		t.insert(ctx, vtx)
 	}
 	return issued
 }
 
-func (t *Transitive) insert(vtx avalanche.Vertex) {
+func (t *Transitive) insert(ctx context.Context, vtx avalanche.Vertex) {
 	vtxID := vtx.ID()
 
 	t.pending.Add(vtxID)
@@ -276,12 +373,12 @@ func (t *Transitive) insert(vtx avalanche.Vertex) {
 
 	t.Config.Context.Log.Verbo("Vertex: %s is blocking on %d vertices and %d transactions", vtxID, i.vtxDeps.Len(), i.txDeps.Len())
 
-	t.vtxBlocked.Register(&vtxIssuer{i: i})
-	t.txBlocked.Register(&txIssuer{i: i})
+	t.vtxBlocked.Register(ctx, &vtxIssuer{i: i})
+	t.txBlocked.Register(ctx, &txIssuer{i: i})
 
 	if t.vtxReqs.Len() == 0 {
 		for _, txID := range t.missingTxs.List() {
-			t.txBlocked.Abandon(txID)
+			t.txBlocked.Abandon(ctx, txID)
 		}
 		t.missingTxs.Clear()
 	}
@@ -292,7 +389,7 @@ func (t *Transitive) insert(vtx avalanche.Vertex) {
 	t.numBlockedVtx.Set(float64(t.pending.Len()))
 }
 
-func (t *Transitive) batch(txs []snowstorm.Tx, force, empty bool) {
+func (t *Transitive) batch(ctx context.Context, txs []snowstorm.Tx, force, empty bool) {
 	batch := []snowstorm.Tx(nil)
 	issuedTxs := ids.Set{}
 	consumed := ids.Set{}
@@ -301,7 +398,7 @@ func (t *Transitive) batch(txs []snowstorm.Tx, force, empty bool) {
 		inputs := tx.InputIDs()
 		overlaps := consumed.Overlaps(inputs)
 		if len(batch) >= t.Params.BatchSize || (force && overlaps) {
-			t.issueBatch(batch)
+			t.issueBatch(ctx, batch)
 			batch = nil
 			consumed.Clear()
 			issued = true
@@ -317,11 +414,11 @@ func (t *Transitive) batch(txs []snowstorm.Tx, force, empty bool) {
 	}
 
 	if len(batch) > 0 || (empty && !issued) {
-		t.issueBatch(batch)
+		t.issueBatch(ctx, batch)
 	}
 }
 
-func (t *Transitive) issueBatch(txs []snowstorm.Tx) {
+func (t *Transitive) issueBatch(ctx context.Context, txs []snowstorm.Tx) {
 	t.Config.Context.Log.Verbo("Batching %d transactions into a new vertex", len(txs))
 
 	virtuousIDs := t.Consensus.Virtuous().List()
@@ -332,7 +429,7 @@ func (t *Transitive) issueBatch(txs []snowstorm.Tx) {
 	}
 
 	if vtx, err := t.Config.State.BuildVertex(parentIDs, txs); err == nil {
-		t.insert(vtx)
+		t.insert(ctx, vtx)
 	} else {
 		t.Config.Context.Log.Warn("Error building new vertex with %d parents and %d transactions", len(parentIDs), len(txs))
 	}
@@ -5,6 +5,7 @@ package avalanche
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"testing"
 
@@ -430,7 +431,7 @@ func TestEngineMultipleQuery(t *testing.T) {
 		}
 	}
 
-	te.insert(vtx0)
+	te.insert(context.Background(), vtx0)
 
 	vtx1 := &Vtx{
 		parents: vts,
@@ -546,10 +547,10 @@ func TestEngineBlockedIssue(t *testing.T) {
 	te.Initialize(config)
 	te.finishBootstrapping()
 
-	te.insert(vtx1)
+	te.insert(context.Background(), vtx1)
 
 	vtx1.parents[0] = vtx0
-	te.insert(vtx0)
+	te.insert(context.Background(), vtx0)
 
 	if !Matches(te.Consensus.Preferences().List(), []ids.ID{vtx1.ID()}) {
 		t.Fatalf("Should have issued vtx1")
@@ -665,7 +666,7 @@ func TestEngineScheduleRepoll(t *testing.T) {
 		*requestID = reqID
 	}
 
-	te.insert(vtx)
+	te.insert(context.Background(), vtx)
 
 	sender.PushQueryF = nil
 
@@ -978,7 +979,7 @@ func TestEngineIssueRepoll(t *testing.T) {
 		}
 	}
 
-	te.repoll()
+	te.repoll(context.Background())
 }
 
 func TestEngineReissue(t *testing.T) {
@@ -1365,7 +1366,7 @@ func TestEngineInsufficientValidators(t *testing.T) {
 		*queried = true
 	}
 
-	te.insert(vtx)
+	te.insert(context.Background(), vtx)
 
 	if *queried {
 		t.Fatalf("Unknown query")
@@ -1511,7 +1512,7 @@ func TestEngineSingleQuery(t *testing.T) {
 	sender.CantPushQuery = false
 	sender.CantPullQuery = false
 
-	te.insert(vtx)
+	te.insert(context.Background(), vtx)
 }
 
 func TestEngineParentBlockingInsert(t *testing.T) {
@@ -1585,8 +1586,8 @@ func TestEngineParentBlockingInsert(t *testing.T) {
 	te.Initialize(config)
 	te.finishBootstrapping()
 
-	te.insert(parentVtx)
-	te.insert(blockingVtx)
+	te.insert(context.Background(), parentVtx)
+	te.insert(context.Background(), blockingVtx)
 
 	if len(te.vtxBlocked) != 2 {
 		t.Fatalf("Both inserts should be blocking")
@@ -1595,7 +1596,7 @@ func TestEngineParentBlockingInsert(t *testing.T) {
 	sender.CantPushQuery = false
 
 	missingVtx.status = choices.Processing
-	te.insert(missingVtx)
+	te.insert(context.Background(), missingVtx)
 
 	if len(te.vtxBlocked) != 0 {
 		t.Fatalf("Both inserts should not longer be blocking")
@@ -1673,7 +1674,7 @@ func TestEngineBlockingChitRequest(t *testing.T) {
 	te.Initialize(config)
 	te.finishBootstrapping()
 
-	te.insert(parentVtx)
+	te.insert(context.Background(), parentVtx)
 
 	st.getVertex = func(vtxID ids.ID) (avalanche.Vertex, error) {
 		switch {
@@ -1702,7 +1703,7 @@ func TestEngineBlockingChitRequest(t *testing.T) {
 	sender.CantChits = false
 
 	missingVtx.status = choices.Processing
-	te.insert(missingVtx)
+	te.insert(context.Background(), missingVtx)
 
 	if len(te.vtxBlocked) != 0 {
 		t.Fatalf("Both inserts should not longer be blocking")
@@ -1780,7 +1781,7 @@ func TestEngineBlockingChitResponse(t *testing.T) {
 	te.Initialize(config)
 	te.finishBootstrapping()
 
-	te.insert(blockingVtx)
+	te.insert(context.Background(), blockingVtx)
 
 	queryRequestID := new(uint32)
 	sender.PushQueryF = func(inVdrs ids.ShortSet, requestID uint32, vtxID ids.ID, vtx []byte) {
@@ -1795,7 +1796,7 @@ func TestEngineBlockingChitResponse(t *testing.T) {
 		}
 	}
 
-	te.insert(issuedVtx)
+	te.insert(context.Background(), issuedVtx)
 
 	st.getVertex = func(id ids.ID) (avalanche.Vertex, error) {
 		switch {
@@ -1819,7 +1820,7 @@ func TestEngineBlockingChitResponse(t *testing.T) {
 	sender.CantChits = false
 
 	missingVtx.status = choices.Processing
-	te.insert(missingVtx)
+	te.insert(context.Background(), missingVtx)
 
 	if len(te.vtxBlocked) != 0 {
 		t.Fatalf("Both inserts should not longer be blocking")
@@ -1897,7 +1898,7 @@ func TestEngineMissingTx(t *testing.T) {
 	te.Initialize(config)
 	te.finishBootstrapping()
 
-	te.insert(blockingVtx)
+	te.insert(context.Background(), blockingVtx)
 
 	queryRequestID := new(uint32)
 	sender.PushQueryF = func(inVdrs ids.ShortSet, requestID uint32, vtxID ids.ID, vtx []byte) {
@@ -1912,7 +1913,7 @@ func TestEngineMissingTx(t *testing.T) {
 		}
 	}
 
-	te.insert(issuedVtx)
+	te.insert(context.Background(), issuedVtx)
 
 	st.getVertex = func(id ids.ID) (avalanche.Vertex, error) {
 		switch {
@@ -1936,7 +1937,7 @@ func TestEngineMissingTx(t *testing.T) {
 	sender.CantChits = false
 
 	missingVtx.status = choices.Processing
-	te.insert(missingVtx)
+	te.insert(context.Background(), missingVtx)
 
 	if len(te.vtxBlocked) != 0 {
 		t.Fatalf("Both inserts should not longer be blocking")
@@ -1993,7 +1994,7 @@ func TestEngineIssueBlockingTx(t *testing.T) {
 	te.Initialize(config)
 	te.finishBootstrapping()
 
-	te.insert(vtx)
+	te.insert(context.Background(), vtx)
 
 	if prefs := te.Consensus.Preferences(); !prefs.Contains(vtx.ID()) {
 		t.Fatalf("Vertex should be preferred")
@@ -2363,3 +2364,113 @@ func TestEngineBootstrappingIntoConsensus(t *testing.T) {
 	sender.PushQueryF = nil
 	st.getVertex = nil
 }
+
+func TestEngineGossip(t *testing.T) {
+	config := DefaultConfig()
+
+	sender := &common.SenderTest{}
+	sender.T = t
+	config.Sender = sender
+
+	sender.Default(true)
+
+	st := &stateTest{t: t}
+	config.State = st
+
+	st.Default(true)
+
+	te := &Transitive{}
+	te.Initialize(config)
+
+	// Nothing is gossiped while bootstrapping
+	te.Gossip()
+
+	st.cantEdge = false
+	te.finishBootstrapping()
+
+	gVtx := &Vtx{
+		id:     GenerateID(),
+		status: choices.Accepted,
+	}
+
+	st.edge = func() []ids.ID { return []ids.ID{gVtx.ID()} }
+
+	called := new(bool)
+	sender.GossipFrontierF = func(vtxIDs ids.Set) {
+		*called = true
+		if vtxIDs.Len() != 1 || !vtxIDs.Contains(gVtx.ID()) {
+			t.Fatalf("Should have gossiped the accepted frontier")
+		}
+	}
+
+	te.Gossip()
+
+	if !*called {
+		t.Fatalf("Should have gossiped the accepted frontier")
+	}
+}
+
+func TestEngineGossipFrontier(t *testing.T) {
+	config := DefaultConfig()
+
+	sender := &common.SenderTest{}
+	sender.T = t
+	config.Sender = sender
+
+	sender.Default(true)
+	sender.CantGetAcceptedFrontier = false
+
+	st := &stateTest{t: t}
+	config.State = st
+
+	st.Default(true)
+
+	st.cantEdge = false
+
+	te := &Transitive{}
+	te.Initialize(config)
+	te.finishBootstrapping()
+
+	// Frontiers may be gossiped by peers that aren't validators
+	peerID := ids.NewShortID([20]byte{0xff})
+
+	gVtx := &Vtx{
+		id:     GenerateID(),
+		status: choices.Accepted,
+	}
+	missingVtxID := GenerateID()
+
+	st.getVertex = func(vtxID ids.ID) (avalanche.Vertex, error) {
+		switch {
+		case vtxID.Equals(gVtx.ID()):
+			return gVtx, nil
+		case vtxID.Equals(missingVtxID):
+			return nil, errMissing
+		}
+		t.Fatalf("Unknown vertex")
+		panic("Should have failed")
+	}
+
+	requested := ids.Set{}
+	sender.GetF = func(inVdr ids.ShortID, _ uint32, vtxID ids.ID) {
+		if !inVdr.Equals(peerID) {
+			t.Fatalf("Should have requested the vertex from the peer that gossiped it")
+		}
+		requested.Add(vtxID)
+	}
+
+	frontier := ids.Set{}
+	frontier.Add(gVtx.ID(), missingVtxID)
+	te.GossipFrontier(peerID, frontier)
+
+	if requested.Len() != 1 || !requested.Contains(missingVtxID) {
+		t.Fatalf("Should have only requested the missing vertex but requested %s", requested)
+	}
+
+	// The vertex is already being fetched
+	te.GossipFrontier(peerID, frontier)
+
+	if requested.Len() != 1 {
+		t.Fatalf("Shouldn't have requested the missing vertex again")
+	}
+}
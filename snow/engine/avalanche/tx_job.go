@@ -4,6 +4,8 @@
 package avalanche
 
 import (
+	"context"
+
 	"github.com/prometheus/client_golang/prometheus"
 
 	"github.com/ava-labs/gecko/ids"
@@ -15,10 +17,11 @@ import (
 type txParser struct {
 	numAccepted, numDropped prometheus.Counter
 	vm                      DAGVM
+	ctx                     context.Context
 }
 
 func (p *txParser) Parse(txBytes []byte) (queue.Job, error) {
-	tx, err := p.vm.ParseTx(txBytes)
+	tx, err := p.vm.ParseTx(p.ctx, txBytes)
 	if err != nil {
 		return nil, err
 	}
@@ -26,12 +29,14 @@ func (p *txParser) Parse(txBytes []byte) (queue.Job, error) {
 		numAccepted: p.numAccepted,
 		numDropped:  p.numDropped,
 		tx:          tx,
+		ctx:         p.ctx,
 	}, nil
 }
 
 type txJob struct {
 	numAccepted, numDropped prometheus.Counter
 	tx                      snowstorm.Tx
+	ctx                     context.Context
 }
 
 func (t *txJob) ID() ids.ID { return t.tx.ID() }
@@ -54,8 +59,8 @@ func (t *txJob) Execute() {
 	case choices.Unknown, choices.Rejected:
 		t.numDropped.Inc()
 	case choices.Processing:
-		if err := t.tx.Verify(); err == nil {
-			t.tx.Accept()
+		if err := t.tx.Verify(t.ctx); err == nil {
+			t.tx.Accept(t.ctx)
 			t.numAccepted.Inc()
 		} else {
 			t.numDropped.Inc()
@@ -4,21 +4,26 @@
 package avalanche
 
 import (
+	"context"
+
 	"github.com/prometheus/client_golang/prometheus"
 
 	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/snow/choices"
 	"github.com/ava-labs/gecko/snow/consensus/avalanche"
+	"github.com/ava-labs/gecko/snow/engine/common"
 	"github.com/ava-labs/gecko/snow/engine/common/queue"
 )
 
 type vtxParser struct {
 	numAccepted, numDropped prometheus.Counter
 	state                   State
+	ctx                     context.Context
+	hooks                   *common.AcceptHooks
 }
 
 func (p *vtxParser) Parse(vtxBytes []byte) (queue.Job, error) {
-	vtx, err := p.state.ParseVertex(vtxBytes)
+	vtx, err := p.state.ParseVertex(p.ctx, vtxBytes)
 	if err != nil {
 		return nil, err
 	}
@@ -26,12 +31,16 @@ func (p *vtxParser) Parse(vtxBytes []byte) (queue.Job, error) {
 		numAccepted: p.numAccepted,
 		numDropped:  p.numDropped,
 		vtx:         vtx,
+		ctx:         p.ctx,
+		hooks:       p.hooks,
 	}, nil
 }
 
 type vertexJob struct {
 	numAccepted, numDropped prometheus.Counter
 	vtx                     avalanche.Vertex
+	ctx                     context.Context
+	hooks                   *common.AcceptHooks
 }
 
 func (v *vertexJob) ID() ids.ID { return v.vtx.ID() }
@@ -59,8 +68,11 @@ func (v *vertexJob) Execute() {
 	case choices.Unknown, choices.Rejected:
 		v.numDropped.Inc()
 	case choices.Processing:
-		v.vtx.Accept()
+		v.vtx.Accept(v.ctx)
 		v.numAccepted.Inc()
+		if v.hooks != nil {
+			v.hooks.Notify(v.vtx.ID(), v.vtx.Bytes())
+		}
 	}
 }
 func (v *vertexJob) Bytes() []byte { return v.vtx.Bytes() }
@@ -4,13 +4,19 @@
 package avalanche
 
 import (
+	"context"
+
 	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/snow/consensus/snowstorm"
 	"github.com/ava-labs/gecko/snow/engine/common"
 )
 
 // DAGVM defines the minimum functionality that an avalanche VM must
-// implement
+// implement.
+//
+// The context passed to ParseTx and GetTx is cancelled when the chain starts
+// shutting down. While the engine handles a message, it's also cancelled once
+// the engine's per-request deadline passes.
 type DAGVM interface {
 	common.VM
 
@@ -18,8 +24,8 @@ type DAGVM interface {
 	PendingTxs() []snowstorm.Tx
 
 	// Convert a stream of bytes to a transaction or return an error
-	ParseTx(tx []byte) (snowstorm.Tx, error)
+	ParseTx(ctx context.Context, tx []byte) (snowstorm.Tx, error)
 
 	// Retrieve a transaction that was submitted previously
-	GetTx(ids.ID) (snowstorm.Tx, error)
+	GetTx(context.Context, ids.ID) (snowstorm.Tx, error)
 }
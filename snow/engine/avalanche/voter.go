@@ -4,6 +4,8 @@
 package avalanche
 
 import (
+	"context"
+
 	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/snow/consensus/snowstorm"
 )
@@ -18,14 +20,14 @@ type voter struct {
 
 func (v *voter) Dependencies() ids.Set { return v.deps }
 
-func (v *voter) Fulfill(id ids.ID) {
+func (v *voter) Fulfill(ctx context.Context, id ids.ID) {
 	v.deps.Remove(id)
-	v.Update()
+	v.Update(ctx)
 }
 
-func (v *voter) Abandon(id ids.ID) { v.Fulfill(id) }
+func (v *voter) Abandon(ctx context.Context, id ids.ID) { v.Fulfill(ctx, id) }
 
-func (v *voter) Update() {
+func (v *voter) Update(ctx context.Context) {
 	if v.deps.Len() != 0 {
 		return
 	}
@@ -36,11 +38,19 @@ func (v *voter) Update() {
 	}
 
 	v.t.Config.Context.Log.Debug("Finishing poll with:\n%s", &results)
-	v.t.Consensus.RecordPoll(results)
+	v.t.Consensus.RecordPoll(ctx, results)
+	v.t.applyParameters()
+	v.t.sendQueuedQueries()
+
+	v.t.numPollsSinceCheckpoint++
+	if v.t.numPollsSinceCheckpoint >= checkpointInterval {
+		v.t.numPollsSinceCheckpoint = 0
+		v.t.checkpoint()
+	}
 
 	txs := []snowstorm.Tx(nil)
 	for _, orphanID := range v.t.Consensus.Orphans().List() {
-		if tx, err := v.t.Config.VM.GetTx(orphanID); err == nil {
+		if tx, err := v.t.Config.VM.GetTx(ctx, orphanID); err == nil {
 			txs = append(txs, tx)
 		} else {
 			v.t.Config.Context.Log.Warn("Failed to fetch %s during attempted re-issuance", orphanID)
@@ -49,7 +59,7 @@ func (v *voter) Update() {
 	if len(txs) > 0 {
 		v.t.Config.Context.Log.Debug("Re-issuing %d transactions", len(txs))
 	}
-	v.t.batch(txs, true /*=force*/, false /*empty*/)
+	v.t.batch(ctx, txs, true /*=force*/, false /*empty*/)
 
 	if v.t.Consensus.Quiesce() {
 		v.t.Config.Context.Log.Verbo("Avalanche engine can quiesce")
@@ -59,6 +69,6 @@ func (v *voter) Update() {
 	v.t.Config.Context.Log.Verbo("Avalanche engine can't quiesce")
 
 	if len(v.t.polls.m) == 0 {
-		v.t.repoll()
+		v.t.repoll(ctx)
 	}
 }
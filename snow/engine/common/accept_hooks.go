@@ -0,0 +1,223 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package common
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow/triggers"
+	"github.com/ava-labs/gecko/utils/logging"
+)
+
+const (
+	// DefaultAcceptHookQueueSize is the number of accepted containers that may
+	// be waiting to be delivered to a single hook before acceptance waits for
+	// that hook
+	DefaultAcceptHookQueueSize = 1024
+)
+
+var (
+	errHooksClosed = errors.New("accept hooks have been shut down")
+)
+
+// AcceptHooks delivers the containers accepted by a chain to auxiliary
+// components, such as indexers, that shouldn't be wired into the VM.
+//
+// Every hook is run on its own goroutine and is given the containers in the
+// order they were accepted. Each hook has a bounded queue; once a hook's queue
+// is full, acceptance waits, and is logged, until the hook makes room, so a
+// hook is never missing a container. A hook therefore mustn't wait on the
+// chain it's registered with.
+//
+// If the hooks have a journal, a container that a hook already handled isn't
+// delivered to that hook again, which can otherwise happen when the node
+// restarts and re-accepts containers while bootstrapping.
+type AcceptHooks struct {
+	log       logging.Logger
+	chainID   ids.ID
+	queueSize int
+	journal   *AcceptJournal
+
+	lock   sync.Mutex
+	hooks  map[string]*acceptHook
+	closed bool
+}
+
+type acceptHook struct {
+	acceptor triggers.Acceptor
+	queue    chan acceptedContainer
+	// quit is closed when the hook is removed. [queue] is never closed, so
+	// that it can be sent to without holding the hooks' lock.
+	quit chan struct{}
+	done chan struct{}
+}
+
+type acceptedContainer struct {
+	containerID ids.ID
+	container   []byte
+}
+
+// Initialize the hooks of the chain [chainID]. If [queueSize] is not positive,
+// DefaultAcceptHookQueueSize is used. [journal] may be nil, in which case
+// containers accepted again after a restart are delivered again.
+func (h *AcceptHooks) Initialize(log logging.Logger, chainID ids.ID, queueSize int, journal *AcceptJournal) {
+	if queueSize <= 0 {
+		queueSize = DefaultAcceptHookQueueSize
+	}
+	h.log = log
+	h.chainID = chainID
+	h.queueSize = queueSize
+	h.journal = journal
+	h.hooks = make(map[string]*acceptHook)
+}
+
+// OnAccept registers [acceptor] under [identifier]. The acceptor is sent every
+// container accepted after this call returns.
+func (h *AcceptHooks) OnAccept(identifier string, acceptor triggers.Acceptor) error {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	if h.closed {
+		return errHooksClosed
+	}
+	if _, exists := h.hooks[identifier]; exists {
+		return fmt.Errorf("accept hook %s already exists on chain %s", identifier, h.chainID)
+	}
+
+	hook := &acceptHook{
+		acceptor: acceptor,
+		queue:    make(chan acceptedContainer, h.queueSize),
+		quit:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	h.hooks[identifier] = hook
+	go h.log.RecoverAndPanic(func() { h.dispatch(identifier, hook) })
+	return nil
+}
+
+// Remove the hook registered under [identifier]. Containers already queued for
+// the hook are delivered before this call returns.
+func (h *AcceptHooks) Remove(identifier string) error {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	hook, exists := h.hooks[identifier]
+	if !exists {
+		return fmt.Errorf("accept hook %s does not exist on chain %s", identifier, h.chainID)
+	}
+	delete(h.hooks, identifier)
+
+	close(hook.quit)
+	<-hook.done
+	return nil
+}
+
+// Accept implements the triggers.Acceptor interface so that the hooks can be
+// registered with a chain's consensus dispatcher
+func (h *AcceptHooks) Accept(_, containerID ids.ID, container []byte) error {
+	return h.Notify(containerID, container)
+}
+
+// Notify queues the accepted container for every registered hook. If a hook's
+// queue is full, Notify blocks until the hook makes room or is removed.
+func (h *AcceptHooks) Notify(containerID ids.ID, container []byte) error {
+	h.lock.Lock()
+	if h.closed {
+		h.lock.Unlock()
+		return errHooksClosed
+	}
+	hooks := make(map[string]*acceptHook, len(h.hooks))
+	for identifier, hook := range h.hooks {
+		hooks[identifier] = hook
+	}
+	h.lock.Unlock()
+
+	accepted := acceptedContainer{
+		containerID: containerID,
+		container:   container,
+	}
+	for identifier, hook := range hooks {
+		select {
+		case hook.queue <- accepted:
+			continue
+		case <-hook.quit:
+			continue
+		default:
+		}
+
+		h.log.Warn("accept hook %s is %d containers behind on chainID %s, waiting to queue %s", identifier, h.queueSize, h.chainID, containerID)
+		select {
+		case hook.queue <- accepted:
+		case <-hook.quit:
+		}
+	}
+	return nil
+}
+
+// Shutdown stops delivering containers. Containers already queued are
+// delivered before this call returns.
+func (h *AcceptHooks) Shutdown() {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	if h.closed {
+		return
+	}
+	h.closed = true
+
+	for _, hook := range h.hooks {
+		close(hook.quit)
+	}
+	for _, hook := range h.hooks {
+		<-hook.done
+	}
+	h.hooks = nil
+}
+
+func (h *AcceptHooks) dispatch(identifier string, hook *acceptHook) {
+	defer close(hook.done)
+
+	for {
+		select {
+		case accepted := <-hook.queue:
+			h.deliver(identifier, hook, accepted)
+		case <-hook.quit:
+			// Deliver the containers queued before the hook was removed
+			for {
+				select {
+				case accepted := <-hook.queue:
+					h.deliver(identifier, hook, accepted)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+func (h *AcceptHooks) deliver(identifier string, hook *acceptHook, accepted acceptedContainer) {
+	if h.journal != nil {
+		completed, err := h.journal.Completed(identifier, accepted.containerID)
+		if err != nil {
+			h.log.Error("accept hook %s couldn't read the journal for %s on chainID %s: %s", identifier, accepted.containerID, h.chainID, err)
+		} else if completed {
+			h.log.Debug("accept hook %s already handled %s on chainID %s", identifier, accepted.containerID, h.chainID)
+			return
+		}
+	}
+
+	if err := hook.acceptor.Accept(h.chainID, accepted.containerID, accepted.container); err != nil {
+		h.log.Error("accept hook %s failed on %s for chainID %s: %s", identifier, accepted.containerID, h.chainID, err)
+		return
+	}
+
+	if h.journal != nil {
+		if err := h.journal.Record(identifier, accepted.containerID); err != nil {
+			h.log.Error("accept hook %s couldn't record %s on chainID %s: %s", identifier, accepted.containerID, h.chainID, err)
+		}
+	}
+}
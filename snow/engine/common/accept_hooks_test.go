@@ -0,0 +1,250 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package common
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ava-labs/gecko/database/memdb"
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/logging"
+)
+
+type acceptorFunc func(chainID, containerID ids.ID, container []byte) error
+
+func (f acceptorFunc) Accept(chainID, containerID ids.ID, container []byte) error {
+	return f(chainID, containerID, container)
+}
+
+func TestAcceptHooksOrder(t *testing.T) {
+	chainID := ids.Empty.Prefix(0)
+
+	hooks := AcceptHooks{}
+	hooks.Initialize(logging.NoLog{}, chainID, 0, nil)
+
+	// The hook runs on its own goroutine, so it can't fail the test itself
+	wrongChain := make(chan ids.ID, 1)
+	accepted := []ids.ID(nil)
+	if err := hooks.OnAccept("indexer", acceptorFunc(func(cID, containerID ids.ID, _ []byte) error {
+		if !cID.Equals(chainID) {
+			select {
+			case wrongChain <- cID:
+			default:
+			}
+		}
+		accepted = append(accepted, containerID)
+		return nil
+	})); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []ids.ID(nil)
+	for i := uint64(1); i <= 100; i++ {
+		containerID := ids.Empty.Prefix(i)
+		expected = append(expected, containerID)
+		if err := hooks.Accept(ids.Empty, containerID, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Shutdown waits for the queued containers to be delivered
+	hooks.Shutdown()
+
+	select {
+	case cID := <-wrongChain:
+		t.Fatalf("Wrong chainID %s", cID)
+	default:
+	}
+	if len(accepted) != len(expected) {
+		t.Fatalf("Should have delivered %d containers, delivered %d", len(expected), len(accepted))
+	}
+	for i, containerID := range expected {
+		if !containerID.Equals(accepted[i]) {
+			t.Fatalf("Container %d should have been %s, was %s", i, containerID, accepted[i])
+		}
+	}
+
+	if err := hooks.Notify(ids.Empty.Prefix(101), nil); err == nil {
+		t.Fatalf("Should have errored after shutdown")
+	}
+}
+
+func TestAcceptHooksBackpressure(t *testing.T) {
+	hooks := AcceptHooks{}
+	hooks.Initialize(logging.NoLog{}, ids.Empty, 1, nil)
+
+	started := make(chan struct{}, 1)
+	release := make(chan struct{})
+	delivered := make(chan ids.ID, 3)
+	if err := hooks.OnAccept("slow", acceptorFunc(func(_, containerID ids.ID, _ []byte) error {
+		select {
+		case started <- struct{}{}:
+		default:
+		}
+		<-release
+		delivered <- containerID
+		return nil
+	})); err != nil {
+		t.Fatal(err)
+	}
+
+	// The first container is taken by the hook's goroutine and the second fills
+	// the queue, so the third must wait for the hook rather than be dropped
+	if err := hooks.Notify(ids.Empty.Prefix(0), nil); err != nil {
+		t.Fatal(err)
+	}
+	<-started
+
+	notified := make(chan struct{})
+	go func() {
+		hooks.Notify(ids.Empty.Prefix(1), nil)
+		hooks.Notify(ids.Empty.Prefix(2), nil)
+		close(notified)
+	}()
+
+	select {
+	case <-notified:
+		t.Fatalf("Notify should have blocked on the full queue")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	close(release)
+	select {
+	case <-notified:
+	case <-time.After(time.Second):
+		t.Fatalf("Notify should have returned once the hook made room")
+	}
+	hooks.Shutdown()
+
+	if len(delivered) != 3 {
+		t.Fatalf("Should have delivered 3 containers, delivered %d", len(delivered))
+	}
+	for i := uint64(0); i < 3; i++ {
+		if containerID := <-delivered; !containerID.Equals(ids.Empty.Prefix(i)) {
+			t.Fatalf("Should have delivered %s, delivered %s", ids.Empty.Prefix(i), containerID)
+		}
+	}
+}
+
+func TestAcceptHooksRemoveUnblocks(t *testing.T) {
+	hooks := AcceptHooks{}
+	hooks.Initialize(logging.NoLog{}, ids.Empty, 1, nil)
+
+	started := make(chan struct{}, 1)
+	release := make(chan struct{})
+	if err := hooks.OnAccept("stuck", acceptorFunc(func(ids.ID, ids.ID, []byte) error {
+		select {
+		case started <- struct{}{}:
+		default:
+		}
+		<-release
+		return nil
+	})); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := hooks.Notify(ids.Empty.Prefix(0), nil); err != nil {
+		t.Fatal(err)
+	}
+	<-started
+
+	notified := make(chan struct{})
+	go func() {
+		hooks.Notify(ids.Empty.Prefix(1), nil)
+		hooks.Notify(ids.Empty.Prefix(2), nil)
+		close(notified)
+	}()
+
+	// Removing the hook waits for its queue to drain, so it has to be let go
+	// while the removal is in progress
+	removed := make(chan error, 1)
+	go func() { removed <- hooks.Remove("stuck") }()
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+
+	if err := <-removed; err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-notified:
+	case <-time.After(time.Second):
+		t.Fatalf("Notify should have returned once the hook was removed")
+	}
+}
+
+func TestAcceptHooksRegistration(t *testing.T) {
+	hooks := AcceptHooks{}
+	hooks.Initialize(logging.NoLog{}, ids.Empty, 0, nil)
+
+	count := 0
+	acceptor := acceptorFunc(func(ids.ID, ids.ID, []byte) error {
+		count++
+		return nil
+	})
+
+	if err := hooks.OnAccept("hook", acceptor); err != nil {
+		t.Fatal(err)
+	}
+	if err := hooks.OnAccept("hook", acceptor); err == nil {
+		t.Fatalf("Should have errored on a duplicated identifier")
+	}
+
+	hooks.Notify(ids.Empty.Prefix(0), nil)
+	if err := hooks.Remove("hook"); err != nil {
+		t.Fatal(err)
+	}
+	if count != 1 {
+		t.Fatalf("Removing a hook should deliver its queued containers")
+	}
+
+	hooks.Notify(ids.Empty.Prefix(1), nil)
+	if count != 1 {
+		t.Fatalf("A removed hook shouldn't be notified")
+	}
+	if err := hooks.Remove("hook"); err == nil {
+		t.Fatalf("Should have errored on removing a missing hook")
+	}
+
+	hooks.Shutdown()
+	if err := hooks.OnAccept("hook", acceptor); err == nil {
+		t.Fatalf("Should have errored after shutdown")
+	}
+}
+
+func TestAcceptHooksJournal(t *testing.T) {
+	db := memdb.New()
+
+	delivered := []ids.ID(nil)
+	acceptor := acceptorFunc(func(_, containerID ids.ID, _ []byte) error {
+		delivered = append(delivered, containerID)
+		return nil
+	})
+
+	hooks := AcceptHooks{}
+	hooks.Initialize(logging.NoLog{}, ids.Empty, 0, NewAcceptJournal(db, 10))
+	if err := hooks.OnAccept("hook", acceptor); err != nil {
+		t.Fatal(err)
+	}
+	hooks.Notify(ids.Empty.Prefix(0), nil)
+	hooks.Notify(ids.Empty.Prefix(1), nil)
+	hooks.Shutdown()
+
+	// Simulate a restart that accepts the containers again
+	hooks = AcceptHooks{}
+	hooks.Initialize(logging.NoLog{}, ids.Empty, 0, NewAcceptJournal(db, 10))
+	if err := hooks.OnAccept("hook", acceptor); err != nil {
+		t.Fatal(err)
+	}
+	hooks.Notify(ids.Empty.Prefix(1), nil)
+	hooks.Notify(ids.Empty.Prefix(2), nil)
+	hooks.Shutdown()
+
+	if len(delivered) != 3 {
+		t.Fatalf("Should have delivered %d containers, delivered %d", 3, len(delivered))
+	}
+	if !delivered[2].Equals(ids.Empty.Prefix(2)) {
+		t.Fatalf("Shouldn't have delivered a container that was already handled")
+	}
+}
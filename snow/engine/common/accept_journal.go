@@ -0,0 +1,155 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package common
+
+import (
+	"sync"
+
+	"github.com/ava-labs/gecko/database"
+	"github.com/ava-labs/gecko/database/prefixdb"
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/wrappers"
+)
+
+const (
+	// DefaultAcceptJournalRetention is the number of containers remembered
+	// for each hook by default
+	DefaultAcceptJournalRetention = 100000
+)
+
+const (
+	containerPrefix byte = iota
+	sequencePrefix
+	nextSeqPrefix
+)
+
+var (
+	nextSeqKey = []byte{nextSeqPrefix}
+)
+
+// AcceptJournal persists which accepted containers each hook has finished
+// handling, so that the side effects of accepting a container, such as
+// external notifications, happen at most once even if the node restarts and
+// accepts the container again while bootstrapping.
+//
+// Only the most recently recorded containers of each hook are remembered. A
+// container that is accepted again after more than [retention] newer containers
+// were recorded for the hook will be delivered again.
+type AcceptJournal struct {
+	lock      sync.Mutex
+	db        database.Database
+	retention uint64
+}
+
+// NewAcceptJournal returns a journal stored in [db] that remembers the last
+// [retention] containers handled by each hook. If [retention] is 0, nothing is
+// remembered.
+func NewAcceptJournal(db database.Database, retention uint64) *AcceptJournal {
+	return &AcceptJournal{
+		db:        db,
+		retention: retention,
+	}
+}
+
+// Completed returns true if the hook [identifier] was recorded as having
+// handled [containerID]
+func (j *AcceptJournal) Completed(identifier string, containerID ids.ID) (bool, error) {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+
+	return j.hookDB(identifier).Has(containerKey(containerID))
+}
+
+// Record that the hook [identifier] handled [containerID]. If this causes
+// more than [retention] containers to be remembered for the hook, the oldest
+// is forgotten.
+func (j *AcceptJournal) Record(identifier string, containerID ids.ID) error {
+	if j.retention == 0 {
+		return nil
+	}
+
+	j.lock.Lock()
+	defer j.lock.Unlock()
+
+	db := j.hookDB(identifier)
+	if recorded, err := db.Has(containerKey(containerID)); err != nil || recorded {
+		return err
+	}
+
+	seq, err := j.nextSeq(db)
+	if err != nil {
+		return err
+	}
+
+	batch := db.NewBatch()
+	if err := batch.Put(containerKey(containerID), packLong(seq)); err != nil {
+		return err
+	}
+	if err := batch.Put(sequenceKey(seq), containerID.Bytes()); err != nil {
+		return err
+	}
+	if err := batch.Put(nextSeqKey, packLong(seq+1)); err != nil {
+		return err
+	}
+
+	// Forget the container that falls out of the retention window
+	if seq >= j.retention {
+		prunedKey := sequenceKey(seq - j.retention)
+		prunedBytes, err := db.Get(prunedKey)
+		switch err {
+		case nil:
+			prunedID, err := ids.ToID(prunedBytes)
+			if err != nil {
+				return err
+			}
+			if err := batch.Delete(prunedKey); err != nil {
+				return err
+			}
+			if err := batch.Delete(containerKey(prunedID)); err != nil {
+				return err
+			}
+		case database.ErrNotFound:
+		default:
+			return err
+		}
+	}
+	return batch.Write()
+}
+
+// hookDB returns the database holding the journal of the hook [identifier]
+func (j *AcceptJournal) hookDB(identifier string) database.Database {
+	return prefixdb.New([]byte(identifier), j.db)
+}
+
+// nextSeq returns the sequence number that the next container recorded in
+// [db] will be given
+func (j *AcceptJournal) nextSeq(db database.Database) (uint64, error) {
+	value, err := db.Get(nextSeqKey)
+	switch err {
+	case nil:
+	case database.ErrNotFound:
+		return 0, nil
+	default:
+		return 0, err
+	}
+	p := wrappers.Packer{Bytes: value}
+	seq := p.UnpackLong()
+	return seq, p.Err
+}
+
+// containerKey is the key mapping [containerID] to its sequence number
+func containerKey(containerID ids.ID) []byte {
+	return append([]byte{containerPrefix}, containerID.Bytes()...)
+}
+
+// sequenceKey is the key mapping [seq] to the ID of its container
+func sequenceKey(seq uint64) []byte {
+	return append([]byte{sequencePrefix}, packLong(seq)...)
+}
+
+func packLong(v uint64) []byte {
+	p := wrappers.Packer{Bytes: make([]byte, wrappers.LongLen)}
+	p.PackLong(v)
+	return p.Bytes
+}
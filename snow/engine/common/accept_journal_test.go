@@ -0,0 +1,72 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package common
+
+import (
+	"testing"
+
+	"github.com/ava-labs/gecko/database/memdb"
+	"github.com/ava-labs/gecko/ids"
+)
+
+func TestAcceptJournalRecord(t *testing.T) {
+	journal := NewAcceptJournal(memdb.New(), 10)
+
+	containerID := ids.Empty.Prefix(0)
+	if completed, err := journal.Completed("hook", containerID); err != nil {
+		t.Fatal(err)
+	} else if completed {
+		t.Fatalf("Shouldn't have completed an unrecorded container")
+	}
+
+	if err := journal.Record("hook", containerID); err != nil {
+		t.Fatal(err)
+	}
+	if completed, err := journal.Completed("hook", containerID); err != nil {
+		t.Fatal(err)
+	} else if !completed {
+		t.Fatalf("Should have completed a recorded container")
+	}
+
+	if completed, err := journal.Completed("other hook", containerID); err != nil {
+		t.Fatal(err)
+	} else if completed {
+		t.Fatalf("Hooks should be journaled separately")
+	}
+}
+
+func TestAcceptJournalPruning(t *testing.T) {
+	journal := NewAcceptJournal(memdb.New(), 3)
+
+	for i := uint64(0); i < 5; i++ {
+		if err := journal.Record("hook", ids.Empty.Prefix(i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for i := uint64(0); i < 5; i++ {
+		completed, err := journal.Completed("hook", ids.Empty.Prefix(i))
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Only the last 3 containers should be remembered
+		if expected := i >= 2; completed != expected {
+			t.Fatalf("Container %d: expected completed to be %v, was %v", i, expected, completed)
+		}
+	}
+}
+
+func TestAcceptJournalNoRetention(t *testing.T) {
+	journal := NewAcceptJournal(memdb.New(), 0)
+
+	containerID := ids.Empty.Prefix(0)
+	if err := journal.Record("hook", containerID); err != nil {
+		t.Fatal(err)
+	}
+	if completed, err := journal.Completed("hook", containerID); err != nil {
+		t.Fatal(err)
+	} else if completed {
+		t.Fatalf("Shouldn't remember containers without retention")
+	}
+}
@@ -4,7 +4,10 @@
 package common
 
 import (
+	stdmath "math"
+
 	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/math"
 )
 
 // Bootstrapper implements the Engine interface.
@@ -15,7 +18,7 @@ type Bootstrapper struct {
 	acceptedFrontier        ids.Set
 
 	pendingAccepted ids.ShortSet
-	accepted        ids.Bag
+	acceptedVotes   map[[32]byte]uint64
 
 	RequestID uint32
 }
@@ -30,7 +33,7 @@ func (b *Bootstrapper) Initialize(config Config) {
 		b.pendingAccepted.Add(vdrID)
 	}
 
-	b.accepted.SetThreshold(config.Alpha)
+	b.acceptedVotes = make(map[[32]byte]uint64)
 }
 
 // Startup implements the Engine interface.
@@ -77,6 +80,17 @@ func (b *Bootstrapper) AcceptedFrontier(validatorID ids.ShortID, requestID uint3
 	}
 }
 
+// GossipFrontier implements the Engine interface. Gossiped frontiers are
+// ignored while bootstrapping, since the containers they reference are fetched
+// once the accepted frontier is agreed upon.
+func (b *Bootstrapper) GossipFrontier(validatorID ids.ShortID, _ ids.Set) {
+	b.Context.Log.Verbo("Dropping a GossipFrontier message from %s while bootstrapping", validatorID)
+}
+
+// Gossip implements the Engine interface. Nothing is gossiped while
+// bootstrapping, since this engine's accepted frontier is likely stale.
+func (b *Bootstrapper) Gossip() {}
+
 // GetAccepted implements the Engine interface.
 func (b *Bootstrapper) GetAccepted(validatorID ids.ShortID, requestID uint32, containerIDs ids.Set) {
 	b.Sender.Accepted(validatorID, requestID, b.Bootstrapable.FilterAccepted(containerIDs))
@@ -95,10 +109,27 @@ func (b *Bootstrapper) Accepted(validatorID ids.ShortID, requestID uint32, conta
 	}
 	b.pendingAccepted.Remove(validatorID)
 
-	b.accepted.Add(containerIDs.List()...)
+	weight := uint64(0)
+	if vdr, ok := b.Beacons.Get(validatorID); ok {
+		weight = vdr.Weight()
+	}
+
+	for _, containerID := range containerIDs.List() {
+		key := containerID.Key()
+		newWeight, err := math.Add64(weight, b.acceptedVotes[key])
+		if err != nil {
+			newWeight = stdmath.MaxUint64
+		}
+		b.acceptedVotes[key] = newWeight
+	}
 
 	if b.pendingAccepted.Len() == 0 {
-		accepted := b.accepted.Threshold()
+		accepted := ids.Set{}
+		for key, weight := range b.acceptedVotes {
+			if weight >= b.Config.Alpha {
+				accepted.Add(ids.NewID(key))
+			}
+		}
 		if size := accepted.Len(); size == 0 && b.Config.Beacons.Len() > 0 {
 			b.Context.Log.Warn("Bootstrapping finished with no accepted frontier. This is likely a result of failing to be able to connect to the specified bootstraps, or no transactions have been issued on this network yet")
 		} else {
@@ -108,3 +139,20 @@ func (b *Bootstrapper) Accepted(validatorID ids.ShortID, requestID uint32, conta
 		b.Bootstrapable.ForceAccepted(accepted)
 	}
 }
+
+// GetStateSummary implements the Engine interface. Engines that can't
+// summarize their state respond with an empty summary, so the requester
+// doesn't wait for the request to time out.
+func (b *Bootstrapper) GetStateSummary(validatorID ids.ShortID, requestID uint32) {
+	b.Sender.StateSummary(validatorID, requestID, nil)
+}
+
+// StateSummary implements the Engine interface.
+func (b *Bootstrapper) StateSummary(validatorID ids.ShortID, _ uint32, _ []byte) {
+	b.Context.Log.Debug("Received a StateSummary message from %s unexpectedly", validatorID)
+}
+
+// GetStateSummaryFailed implements the Engine interface.
+func (b *Bootstrapper) GetStateSummaryFailed(validatorID ids.ShortID, _ uint32) {
+	b.Context.Log.Debug("Received a GetStateSummaryFailed message for %s unexpectedly", validatorID)
+}
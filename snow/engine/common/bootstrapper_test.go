@@ -0,0 +1,61 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package common
+
+import (
+	"testing"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow/validators"
+)
+
+func TestBootstrapperAcceptedWeightedByStake(t *testing.T) {
+	config := DefaultConfigTest()
+
+	sender := &SenderTest{T: t}
+	bootstrapable := &BootstrapableTest{T: t}
+	sender.Default(true)
+	bootstrapable.Default(true)
+	config.Sender = sender
+	config.Bootstrapable = bootstrapable
+
+	heavy := validators.GenerateRandomValidator(10)
+	light0 := validators.GenerateRandomValidator(1)
+	light1 := validators.GenerateRandomValidator(1)
+
+	config.Beacons.Add(heavy)
+	config.Beacons.Add(light0)
+	config.Beacons.Add(light1)
+	config.Alpha = config.Beacons.Weight()/2 + 1
+
+	bs := Bootstrapper{}
+	bs.Initialize(config)
+
+	honestID := ids.Empty.Prefix(0)
+	sybilID := ids.Empty.Prefix(1)
+
+	sender.CantGetAcceptedFrontier = false
+	bs.Startup()
+
+	sender.CantGetAccepted = false
+	bs.AcceptedFrontier(heavy.ID(), bs.RequestID, ids.Set{honestID.Key(): true})
+	bs.AcceptedFrontier(light0.ID(), bs.RequestID, ids.Set{sybilID.Key(): true})
+	bs.AcceptedFrontier(light1.ID(), bs.RequestID, ids.Set{sybilID.Key(): true})
+
+	accepted := ids.Set(nil)
+	bootstrapable.ForceAcceptedF = func(containerIDs ids.Set) { accepted = containerIDs }
+
+	bs.Accepted(heavy.ID(), bs.RequestID, ids.Set{honestID.Key(): true})
+	bs.Accepted(light0.ID(), bs.RequestID, ids.Set{sybilID.Key(): true})
+	bs.Accepted(light1.ID(), bs.RequestID, ids.Set{sybilID.Key(): true})
+
+	switch {
+	case accepted == nil:
+		t.Fatalf("Bootstrapping should have finished")
+	case accepted.Len() != 1:
+		t.Fatalf("Should have accepted exactly one container, accepted %d", accepted.Len())
+	case !accepted.Contains(honestID):
+		t.Fatalf("Should have accepted the container reported by the majority of stake")
+	}
+}
@@ -4,6 +4,9 @@
 package common
 
 import (
+	"context"
+	"time"
+
 	"github.com/ava-labs/gecko/snow"
 	"github.com/ava-labs/gecko/snow/validators"
 )
@@ -15,7 +18,43 @@ type Config struct {
 	Validators validators.Set
 	Beacons    validators.Set
 
-	Alpha         int
+	// Alpha is the amount of beacon stake that must report a container as
+	// accepted for it to be considered accepted while bootstrapping
+	Alpha uint64
+
 	Sender        Sender
 	Bootstrapable Bootstrapable
+
+	// AcceptHooks, if non-nil, is notified of the containers accepted while
+	// bootstrapping. Containers accepted by consensus reach the hooks through
+	// the chain's consensus dispatcher.
+	AcceptHooks *AcceptHooks
+
+	// Ctx is cancelled when the chain starts shutting down. The engine passes
+	// it to the VM so that long running work can be abandoned. If nil, the
+	// engine's calls into the VM are never cancelled.
+	Ctx context.Context
+
+	// RequestTimeout bounds the work the engine does on behalf of a single
+	// message, including the VM calls made while handling it. If 0, the work
+	// is only bounded by [Ctx].
+	RequestTimeout time.Duration
+}
+
+// VMContext returns the context the engine passes to the VM
+func (c *Config) VMContext() context.Context {
+	if c.Ctx == nil {
+		return context.Background()
+	}
+	return c.Ctx
+}
+
+// RequestContext returns the context the engine passes to the VM while it
+// handles a message. The returned cancel function must be called once the
+// message has been handled.
+func (c *Config) RequestContext() (context.Context, context.CancelFunc) {
+	if c.RequestTimeout == 0 {
+		return context.WithCancel(c.VMContext())
+	}
+	return context.WithTimeout(c.VMContext(), c.RequestTimeout)
 }
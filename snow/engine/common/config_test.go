@@ -0,0 +1,58 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package common
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestRequestContextDeadline(t *testing.T) {
+	config := Config{RequestTimeout: time.Minute}
+
+	ctx, cancel := config.RequestContext()
+	defer cancel()
+
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		t.Fatalf("The request context should have a deadline")
+	}
+	if remaining := time.Until(deadline); remaining > time.Minute {
+		t.Fatalf("The request context's deadline is %s away, expected at most %s", remaining, time.Minute)
+	}
+}
+
+func TestRequestContextNoTimeout(t *testing.T) {
+	config := Config{}
+
+	ctx, cancel := config.RequestContext()
+	if _, ok := ctx.Deadline(); ok {
+		t.Fatalf("The request context shouldn't have a deadline")
+	}
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("The request context shouldn't be done: %s", err)
+	}
+
+	cancel()
+	if err := ctx.Err(); err != context.Canceled {
+		t.Fatalf("Expected %s but got %v", context.Canceled, err)
+	}
+}
+
+func TestRequestContextShutdown(t *testing.T) {
+	shutdownCtx, shutdown := context.WithCancel(context.Background())
+	config := Config{
+		Ctx:            shutdownCtx,
+		RequestTimeout: time.Minute,
+	}
+
+	ctx, cancel := config.RequestContext()
+	defer cancel()
+
+	shutdown()
+	if err := ctx.Err(); err != context.Canceled {
+		t.Fatalf("The request context should be cancelled when the chain shuts down, but got %v", err)
+	}
+}
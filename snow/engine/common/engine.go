@@ -27,6 +27,7 @@ type Handler interface {
 type ExternalHandler interface {
 	FrontierHandler
 	AcceptedHandler
+	StateSyncHandler
 	FetchHandler
 	QueryHandler
 }
@@ -46,6 +47,11 @@ type FrontierHandler interface {
 	// requested accepted frontier from the specified validator should be
 	// considered lost
 	GetAcceptedFrontierFailed(validatorID ids.ShortID, requestID uint32)
+
+	// GossipFrontier notifies this consensus engine of the accepted frontier
+	// that the specified peer gossiped. Containers in the frontier that this
+	// engine doesn't have should be fetched from the peer.
+	GossipFrontier(validatorID ids.ShortID, containerIDs ids.Set)
 }
 
 // AcceptedHandler defines how a consensus engine reacts to messages pertaining
@@ -65,6 +71,23 @@ type AcceptedHandler interface {
 	GetAcceptedFailed(validatorID ids.ShortID, requestID uint32)
 }
 
+// StateSyncHandler defines how a consensus engine reacts to state summary
+// messages from other validators
+type StateSyncHandler interface {
+	// GetStateSummary notifies this consensus engine that the specified
+	// validator requested a summary of this engine's last accepted state
+	GetStateSummary(validatorID ids.ShortID, requestID uint32)
+
+	// StateSummary notifies this consensus engine of the specified validator's
+	// summary of its last accepted state. An empty summary means the validator
+	// can't provide one.
+	StateSummary(validatorID ids.ShortID, requestID uint32, summary []byte)
+
+	// GetStateSummaryFailed notifies this consensus engine that the requested
+	// state summary from the specified validator should be considered lost
+	GetStateSummaryFailed(validatorID ids.ShortID, requestID uint32)
+}
+
 // FetchHandler defines how a consensus engine reacts to retrieval messages from
 // other validators
 type FetchHandler interface {
@@ -117,4 +140,8 @@ type InternalHandler interface {
 
 	// Notify this engine that the vm has sent a message to it.
 	Notify(Message)
+
+	// Gossip this engine's accepted frontier to a sample of peers. Called
+	// periodically.
+	Gossip()
 }
@@ -12,26 +12,42 @@ import (
 	"github.com/ava-labs/gecko/utils/wrappers"
 )
 
+const (
+	// DefaultCheckpointInterval is the number of changes made to the queue
+	// between checkpoints
+	DefaultCheckpointInterval = 1024
+)
+
 var (
 	errEmpty     = errors.New("no available containers")
 	errDuplicate = errors.New("duplicated container")
+	errExecuted  = errors.New("container was already executed")
 )
 
-// Jobs ...
+// Jobs is a queue of jobs that are executed once their dependencies are met.
+//
+// The queue is checkpointed to the database every [checkpointInterval]
+// changes, along with the IDs of the executed jobs, so that a node that is
+// stopped while bootstrapping resumes from its last checkpoint rather than
+// refetching and re-executing every container.
 type Jobs struct {
 	parser Parser
 	baseDB database.Database
 	db     *versiondb.Database
 	// Dynamic sized stack of ready to execute items
 	// Map from itemID to list of itemIDs that are blocked on this item
+	// Set of itemIDs that have been executed
 	state prefixedState
+
+	checkpointInterval, uncommitted int
 }
 
 // New ...
 func New(db database.Database) (*Jobs, error) {
 	jobs := &Jobs{
-		baseDB: db,
-		db:     versiondb.New(db),
+		baseDB:             db,
+		db:                 versiondb.New(db),
+		checkpointInterval: DefaultCheckpointInterval,
 	}
 	jobs.state.jobs = jobs
 
@@ -44,14 +60,45 @@ func New(db database.Database) (*Jobs, error) {
 // SetParser ...
 func (j *Jobs) SetParser(parser Parser) { j.parser = parser }
 
+// SetCheckpointInterval sets the number of changes made to the queue between
+// checkpoints. If [interval] is not positive, the queue is only written to the
+// database when Commit is called.
+func (j *Jobs) SetCheckpointInterval(interval int) { j.checkpointInterval = interval }
+
 // Push ...
 func (j *Jobs) Push(job Job) error {
+	if executed, err := j.state.HasExecuted(j.db, job.ID()); err != nil {
+		return err
+	} else if executed {
+		return errExecuted
+	}
+
+	var err error
 	if deps := job.MissingDependencies(); deps.Len() != 0 {
-		return j.block(job, deps)
+		err = j.block(job, deps)
+	} else {
+		err = j.push(job)
+	}
+	if err != nil {
+		return err
+	}
+	return j.changed()
+}
+
+// Has returns true if [jobID] has been pushed and hasn't been executed yet
+func (j *Jobs) Has(jobID ids.ID) (bool, error) {
+	if executed, err := j.state.HasExecuted(j.db, jobID); err != nil || executed {
+		return false, err
 	}
-	return j.push(job)
+	return j.state.HasJob(j.db, jobID)
 }
 
+// Get returns the pushed job [jobID]
+func (j *Jobs) Get(jobID ids.ID) (Job, error) { return j.state.Job(j.db, jobID) }
+
+// Executed returns true if [jobID] has been executed
+func (j *Jobs) Executed(jobID ids.ID) (bool, error) { return j.state.HasExecuted(j.db, jobID) }
+
 // Pop ...
 func (j *Jobs) Pop() (Job, error) {
 	size, err := j.state.StackSize(j.db)
@@ -79,10 +126,17 @@ func (j *Jobs) HasNext() (bool, error) {
 
 // Execute ...
 func (j *Jobs) Execute(job Job) error {
-	job.Execute()
-
 	jobID := job.ID()
 
+	if executed, err := j.state.HasExecuted(j.db, jobID); err != nil {
+		return err
+	} else if !executed {
+		job.Execute()
+		if err := j.state.SetExecuted(j.db, jobID); err != nil {
+			return err
+		}
+	}
+
 	blocking, _ := j.state.Blocking(j.db, jobID)
 	j.state.DeleteBlocking(j.db, jobID)
 
@@ -100,11 +154,24 @@ func (j *Jobs) Execute(job Job) error {
 		}
 	}
 
-	return nil
+	return j.changed()
+}
+
+// Commit writes the queue to the database
+func (j *Jobs) Commit() error {
+	j.uncommitted = 0
+	return j.db.Commit()
 }
 
-// Commit ...
-func (j *Jobs) Commit() error { return j.db.Commit() }
+// changed records a change to the queue and checkpoints the queue once enough
+// changes have been made
+func (j *Jobs) changed() error {
+	j.uncommitted++
+	if j.checkpointInterval <= 0 || j.uncommitted < j.checkpointInterval {
+		return nil
+	}
+	return j.Commit()
+}
 
 func (j *Jobs) push(job Job) error {
 	if has, err := j.state.HasJob(j.db, job.ID()); err != nil {
@@ -250,3 +250,90 @@ func TestDuplicatedPush(t *testing.T) {
 		t.Fatalf("Shouldn't have a container ready to pop")
 	}
 }
+
+func TestCheckpoint(t *testing.T) {
+	parser := &TestParser{T: t}
+	db := memdb.New()
+
+	jobs, err := New(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	jobs.SetParser(parser)
+	jobs.SetCheckpointInterval(1)
+
+	id := ids.Empty.Prefix(0)
+	executed := new(bool)
+	job := &TestJob{
+		T: t,
+
+		IDF:                  func() ids.ID { return id },
+		MissingDependenciesF: func() ids.Set { return ids.Set{} },
+		ExecuteF:             func() { *executed = true },
+		BytesF:               func() []byte { return []byte{0} },
+	}
+
+	if err := jobs.Push(job); err != nil {
+		t.Fatal(err)
+	}
+
+	// The push should have been checkpointed without calling Commit
+	jobs, err = New(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	jobs.SetParser(parser)
+	jobs.SetCheckpointInterval(1)
+
+	if has, err := jobs.Has(id); err != nil {
+		t.Fatal(err)
+	} else if !has {
+		t.Fatalf("Should have restored the pushed job")
+	}
+
+	parser.ParseF = func(b []byte) (Job, error) {
+		if !bytes.Equal(b, []byte{0}) {
+			t.Fatalf("Unknown job")
+		}
+		return job, nil
+	}
+
+	returnedJob, err := jobs.Pop()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := jobs.Execute(returnedJob); err != nil {
+		t.Fatal(err)
+	}
+	if !*executed {
+		t.Fatalf("Should have executed the job")
+	}
+
+	jobs, err = New(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	jobs.SetParser(parser)
+
+	if hasNext, err := jobs.HasNext(); err != nil {
+		t.Fatal(err)
+	} else if hasNext {
+		t.Fatalf("The executed job shouldn't be restored")
+	}
+	if executed, err := jobs.Executed(id); err != nil {
+		t.Fatal(err)
+	} else if !executed {
+		t.Fatalf("Should have recorded the executed job")
+	}
+	if has, err := jobs.Has(id); err != nil {
+		t.Fatal(err)
+	} else if has {
+		t.Fatalf("The executed job shouldn't be pending")
+	}
+	if err := jobs.Push(job); err == nil {
+		t.Fatalf("Should have failed to push an executed job")
+	}
+}
@@ -16,6 +16,7 @@ const (
 	stackID
 	jobID
 	blockingID
+	executedID
 )
 
 var (
@@ -121,3 +122,21 @@ func (ps *prefixedState) Blocking(db database.Database, id ids.ID) (ids.Set, err
 
 	return ps.state.IDs(db, p.Bytes)
 }
+
+func (ps *prefixedState) SetExecuted(db database.Database, id ids.ID) error {
+	p := wrappers.Packer{Bytes: make([]byte, 1+hashing.HashLen)}
+
+	p.PackByte(executedID)
+	p.PackFixedBytes(id.Bytes())
+
+	return db.Put(p.Bytes, nil)
+}
+
+func (ps *prefixedState) HasExecuted(db database.Database, id ids.ID) (bool, error) {
+	p := wrappers.Packer{Bytes: make([]byte, 1+hashing.HashLen)}
+
+	p.PackByte(executedID)
+	p.PackFixedBytes(id.Bytes())
+
+	return db.Has(p.Bytes)
+}
@@ -12,6 +12,7 @@ import (
 type Sender interface {
 	FrontierSender
 	AcceptedSender
+	StateSyncSender
 	FetchSender
 	QuerySender
 }
@@ -26,6 +27,11 @@ type FrontierSender interface {
 	// AcceptedFrontier responds to a AcceptedFrontier message with this
 	// engine's current accepted frontier.
 	AcceptedFrontier(validatorID ids.ShortID, requestID uint32, containerIDs ids.Set)
+
+	// GossipFrontier sends this engine's current accepted frontier to a random
+	// sample of peers, so that peers that fell behind can fetch the
+	// containers they're missing.
+	GossipFrontier(containerIDs ids.Set)
 }
 
 // AcceptedSender defines how a consensus engine sends messages pertaining to
@@ -41,6 +47,19 @@ type AcceptedSender interface {
 	Accepted(validatorID ids.ShortID, requestID uint32, containerIDs ids.Set)
 }
 
+// StateSyncSender defines how a consensus engine sends state summary messages
+// to other validators
+type StateSyncSender interface {
+	// GetStateSummary requests that every validator in [validatorIDs] sends a
+	// StateSummary message.
+	GetStateSummary(validatorIDs ids.ShortSet, requestID uint32)
+
+	// StateSummary responds to a GetStateSummary message with a summary of
+	// this engine's last accepted state. [summary] is empty if this engine
+	// can't provide one.
+	StateSummary(validatorID ids.ShortID, requestID uint32, summary []byte)
+}
+
 // FetchSender defines how a consensus engine sends retrieval messages to other
 // validators
 type FetchSender interface {
@@ -20,15 +20,21 @@ type EngineTest struct {
 	CantContext,
 
 	CantNotify,
+	CantGossip,
 
 	CantGetAcceptedFrontier,
 	CantGetAcceptedFrontierFailed,
 	CantAcceptedFrontier,
+	CantGossipFrontier,
 
 	CantGetAccepted,
 	CantGetAcceptedFailed,
 	CantAccepted,
 
+	CantGetStateSummary,
+	CantGetStateSummaryFailed,
+	CantStateSummary,
+
 	CantGet,
 	CantGetFailed,
 	CantPut,
@@ -38,13 +44,16 @@ type EngineTest struct {
 	CantQueryFailed,
 	CantChits bool
 
-	StartupF, ShutdownF                                                                func()
+	StartupF, ShutdownF, GossipF                                                       func()
 	ContextF                                                                           func() *snow.Context
 	NotifyF                                                                            func(Message)
 	GetF, GetFailedF, PullQueryF                                                       func(validatorID ids.ShortID, requestID uint32, containerID ids.ID)
 	PutF, PushQueryF                                                                   func(validatorID ids.ShortID, requestID uint32, containerID ids.ID, container []byte)
 	GetAcceptedFrontierF, GetAcceptedFrontierFailedF, GetAcceptedFailedF, QueryFailedF func(validatorID ids.ShortID, requestID uint32)
 	AcceptedFrontierF, GetAcceptedF, AcceptedF, ChitsF                                 func(validatorID ids.ShortID, requestID uint32, containerIDs ids.Set)
+	GetStateSummaryF, GetStateSummaryFailedF                                           func(validatorID ids.ShortID, requestID uint32)
+	StateSummaryF                                                                      func(validatorID ids.ShortID, requestID uint32, summary []byte)
+	GossipFrontierF                                                                    func(validatorID ids.ShortID, containerIDs ids.Set)
 }
 
 // Default ...
@@ -55,15 +64,21 @@ func (e *EngineTest) Default(cant bool) {
 	e.CantContext = cant
 
 	e.CantNotify = cant
+	e.CantGossip = cant
 
 	e.CantGetAcceptedFrontier = cant
 	e.CantGetAcceptedFrontierFailed = cant
 	e.CantAcceptedFrontier = cant
+	e.CantGossipFrontier = cant
 
 	e.CantGetAccepted = cant
 	e.CantGetAcceptedFailed = cant
 	e.CantAccepted = cant
 
+	e.CantGetStateSummary = cant
+	e.CantGetStateSummaryFailed = cant
+	e.CantStateSummary = cant
+
 	e.CantGet = cant
 	e.CantGetFailed = cant
 	e.CantPut = cant
@@ -112,6 +127,24 @@ func (e *EngineTest) Notify(msg Message) {
 	}
 }
 
+// Gossip ...
+func (e *EngineTest) Gossip() {
+	if e.GossipF != nil {
+		e.GossipF()
+	} else if e.CantGossip && e.T != nil {
+		e.T.Fatalf("Unexpectedly called Gossip")
+	}
+}
+
+// GossipFrontier ...
+func (e *EngineTest) GossipFrontier(validatorID ids.ShortID, containerIDs ids.Set) {
+	if e.GossipFrontierF != nil {
+		e.GossipFrontierF(validatorID, containerIDs)
+	} else if e.CantGossipFrontier && e.T != nil {
+		e.T.Fatalf("Unexpectedly called GossipFrontier")
+	}
+}
+
 // GetAcceptedFrontier ...
 func (e *EngineTest) GetAcceptedFrontier(validatorID ids.ShortID, requestID uint32) {
 	if e.GetAcceptedFrontierF != nil {
@@ -166,6 +199,33 @@ func (e *EngineTest) Accepted(validatorID ids.ShortID, requestID uint32, contain
 	}
 }
 
+// GetStateSummary ...
+func (e *EngineTest) GetStateSummary(validatorID ids.ShortID, requestID uint32) {
+	if e.GetStateSummaryF != nil {
+		e.GetStateSummaryF(validatorID, requestID)
+	} else if e.CantGetStateSummary && e.T != nil {
+		e.T.Fatalf("Unexpectedly called GetStateSummary")
+	}
+}
+
+// GetStateSummaryFailed ...
+func (e *EngineTest) GetStateSummaryFailed(validatorID ids.ShortID, requestID uint32) {
+	if e.GetStateSummaryFailedF != nil {
+		e.GetStateSummaryFailedF(validatorID, requestID)
+	} else if e.CantGetStateSummaryFailed && e.T != nil {
+		e.T.Fatalf("Unexpectedly called GetStateSummaryFailed")
+	}
+}
+
+// StateSummary ...
+func (e *EngineTest) StateSummary(validatorID ids.ShortID, requestID uint32, summary []byte) {
+	if e.StateSummaryF != nil {
+		e.StateSummaryF(validatorID, requestID, summary)
+	} else if e.CantStateSummary && e.T != nil {
+		e.T.Fatalf("Unexpectedly called StateSummary")
+	}
+}
+
 // Get ...
 func (e *EngineTest) Get(validatorID ids.ShortID, requestID uint32, containerID ids.ID) {
 	if e.GetF != nil {
@@ -15,6 +15,8 @@ type SenderTest struct {
 
 	CantGetAcceptedFrontier, CantAcceptedFrontier,
 	CantGetAccepted, CantAccepted,
+	CantGetStateSummary, CantStateSummary,
+	CantGossipFrontier,
 	CantGet, CantPut,
 	CantPullQuery, CantPushQuery, CantChits bool
 
@@ -22,6 +24,9 @@ type SenderTest struct {
 	AcceptedFrontierF    func(ids.ShortID, uint32, ids.Set)
 	GetAcceptedF         func(ids.ShortSet, uint32, ids.Set)
 	AcceptedF            func(ids.ShortID, uint32, ids.Set)
+	GetStateSummaryF     func(ids.ShortSet, uint32)
+	StateSummaryF        func(ids.ShortID, uint32, []byte)
+	GossipFrontierF      func(ids.Set)
 	GetF                 func(ids.ShortID, uint32, ids.ID)
 	PutF                 func(ids.ShortID, uint32, ids.ID, []byte)
 	PushQueryF           func(ids.ShortSet, uint32, ids.ID, []byte)
@@ -35,6 +40,9 @@ func (s *SenderTest) Default(cant bool) {
 	s.CantAcceptedFrontier = cant
 	s.CantGetAccepted = cant
 	s.CantAccepted = cant
+	s.CantGetStateSummary = cant
+	s.CantStateSummary = cant
+	s.CantGossipFrontier = cant
 	s.CantGet = cant
 	s.CantPut = cant
 	s.CantPullQuery = cant
@@ -86,6 +94,39 @@ func (s *SenderTest) Accepted(validatorID ids.ShortID, requestID uint32, contain
 	}
 }
 
+// GetStateSummary calls GetStateSummaryF if it was initialized. If it wasn't
+// initialized and this function shouldn't be called and testing was
+// initialized, then testing will fail.
+func (s *SenderTest) GetStateSummary(validatorIDs ids.ShortSet, requestID uint32) {
+	if s.GetStateSummaryF != nil {
+		s.GetStateSummaryF(validatorIDs, requestID)
+	} else if s.CantGetStateSummary && s.T != nil {
+		s.T.Fatalf("Unexpectedly called GetStateSummary")
+	}
+}
+
+// StateSummary calls StateSummaryF if it was initialized. If it wasn't
+// initialized and this function shouldn't be called and testing was
+// initialized, then testing will fail.
+func (s *SenderTest) StateSummary(validatorID ids.ShortID, requestID uint32, summary []byte) {
+	if s.StateSummaryF != nil {
+		s.StateSummaryF(validatorID, requestID, summary)
+	} else if s.CantStateSummary && s.T != nil {
+		s.T.Fatalf("Unexpectedly called StateSummary")
+	}
+}
+
+// GossipFrontier calls GossipFrontierF if it was initialized. If it wasn't
+// initialized and this function shouldn't be called and testing was
+// initialized, then testing will fail.
+func (s *SenderTest) GossipFrontier(containerIDs ids.Set) {
+	if s.GossipFrontierF != nil {
+		s.GossipFrontierF(containerIDs)
+	} else if s.CantGossipFrontier && s.T != nil {
+		s.T.Fatalf("Unexpectedly called GossipFrontier")
+	}
+}
+
 // Get calls GetF if it was initialized. If it wasn't initialized and this
 // function shouldn't be called and testing was initialized, then testing will
 // fail.
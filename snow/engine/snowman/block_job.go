@@ -4,21 +4,26 @@
 package snowman
 
 import (
+	"context"
+
 	"github.com/prometheus/client_golang/prometheus"
 
 	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/snow/choices"
 	"github.com/ava-labs/gecko/snow/consensus/snowman"
+	"github.com/ava-labs/gecko/snow/engine/common"
 	"github.com/ava-labs/gecko/snow/engine/common/queue"
 )
 
 type parser struct {
 	numAccepted, numDropped prometheus.Counter
 	vm                      ChainVM
+	ctx                     context.Context
+	hooks                   *common.AcceptHooks
 }
 
 func (p *parser) Parse(blkBytes []byte) (queue.Job, error) {
-	blk, err := p.vm.ParseBlock(blkBytes)
+	blk, err := p.vm.ParseBlock(p.ctx, blkBytes)
 	if err != nil {
 		return nil, err
 	}
@@ -26,12 +31,16 @@ func (p *parser) Parse(blkBytes []byte) (queue.Job, error) {
 		numAccepted: p.numAccepted,
 		numDropped:  p.numDropped,
 		blk:         blk,
+		ctx:         p.ctx,
+		hooks:       p.hooks,
 	}, nil
 }
 
 type blockJob struct {
 	numAccepted, numDropped prometheus.Counter
 	blk                     snowman.Block
+	ctx                     context.Context
+	hooks                   *common.AcceptHooks
 }
 
 func (b *blockJob) ID() ids.ID { return b.blk.ID() }
@@ -51,9 +60,12 @@ func (b *blockJob) Execute() {
 	case choices.Unknown, choices.Rejected:
 		b.numDropped.Inc()
 	case choices.Processing:
-		if err := b.blk.Verify(); err == nil {
-			b.blk.Accept()
+		if err := b.blk.Verify(b.ctx); err == nil {
+			b.blk.Accept(b.ctx)
 			b.numAccepted.Inc()
+			if b.hooks != nil {
+				b.hooks.Notify(b.blk.ID(), b.blk.Bytes())
+			}
 		} else {
 			b.numDropped.Inc()
 		}
@@ -4,12 +4,16 @@
 package snowman
 
 import (
+	stdmath "math"
+
 	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/snow/choices"
 	"github.com/ava-labs/gecko/snow/consensus/snowman"
 	"github.com/ava-labs/gecko/snow/engine/common"
 	"github.com/ava-labs/gecko/snow/engine/common/queue"
 	"github.com/ava-labs/gecko/utils/formatting"
+	"github.com/ava-labs/gecko/utils/hashing"
+	"github.com/ava-labs/gecko/utils/math"
 	"github.com/prometheus/client_golang/prometheus"
 )
 
@@ -23,6 +27,11 @@ type BootstrapConfig struct {
 	VM ChainVM
 
 	Bootstrapped func()
+
+	// StateSync, if true and VM is a StateSyncableVM, syncs VM's state from a
+	// state summary returned by a quorum of beacons before bootstrapping, so
+	// only the blocks after the summary are replayed
+	StateSync bool
 }
 
 type bootstrapper struct {
@@ -33,6 +42,15 @@ type bootstrapper struct {
 	pending    ids.Set
 	finished   bool
 	onFinished func()
+
+	// Beacons whose state summary hasn't been received yet
+	pendingStateSummaries ids.ShortSet
+	// The request ID of the outstanding GetStateSummary request
+	stateSummaryRequestID uint32
+	// Summary hash --> beacon weight that returned the summary
+	stateSummaryVotes map[[32]byte]uint64
+	// Summary hash --> summary
+	stateSummaries map[[32]byte][]byte
 }
 
 // Initialize this engine.
@@ -43,10 +61,113 @@ func (b *bootstrapper) Initialize(config BootstrapConfig) {
 		numAccepted: b.numBootstrapped,
 		numDropped:  b.numDropped,
 		vm:          b.VM,
+		ctx:         b.BootstrapConfig.VMContext(),
+		hooks:       b.BootstrapConfig.AcceptHooks,
 	})
 
 	config.Bootstrapable = b
 	b.Bootstrapper.Initialize(config.Config)
+
+	b.stateSummaryVotes = make(map[[32]byte]uint64)
+	b.stateSummaries = make(map[[32]byte][]byte)
+}
+
+// Startup bootstraps this engine. If state sync is enabled, the VM's state is
+// synced before the blocks are replayed.
+func (b *bootstrapper) Startup() {
+	_, syncable := b.VM.(StateSyncableVM)
+	if !b.StateSync || !syncable || b.BootstrapConfig.Beacons.Len() == 0 {
+		b.Bootstrapper.Startup()
+		return
+	}
+
+	vdrs := ids.ShortSet{}
+	for _, vdr := range b.BootstrapConfig.Beacons.List() {
+		vdrs.Add(vdr.ID())
+	}
+	b.pendingStateSummaries.Union(vdrs)
+
+	b.RequestID++
+	b.stateSummaryRequestID = b.RequestID
+	b.BootstrapConfig.Sender.GetStateSummary(vdrs, b.RequestID)
+}
+
+// GetStateSummary responds with a summary of the VM's state, if the VM can
+// provide one
+func (b *bootstrapper) GetStateSummary(vdr ids.ShortID, requestID uint32) {
+	var summary []byte
+	if vm, ok := b.VM.(StateSyncableVM); ok {
+		var err error
+		summary, err = vm.StateSummary(b.BootstrapConfig.VMContext())
+		if err != nil {
+			b.BootstrapConfig.Context.Log.Debug("Couldn't summarize the state for %s due to %s", vdr, err)
+			summary = nil
+		}
+	}
+	b.BootstrapConfig.Sender.StateSummary(vdr, requestID, summary)
+}
+
+// GetStateSummaryFailed ...
+func (b *bootstrapper) GetStateSummaryFailed(vdr ids.ShortID, requestID uint32) {
+	b.StateSummary(vdr, requestID, nil)
+}
+
+// StateSummary counts [vdr]'s weight towards [summary]. Once every beacon has
+// responded, the state is synced to the summary returned by a quorum of the
+// beacons, if there is one, and the blocks after it are bootstrapped.
+func (b *bootstrapper) StateSummary(vdr ids.ShortID, requestID uint32, summary []byte) {
+	if requestID != b.stateSummaryRequestID || !b.pendingStateSummaries.Contains(vdr) {
+		b.BootstrapConfig.Context.Log.Debug("Received a StateSummary message from %s unexpectedly", vdr)
+		return
+	}
+	b.pendingStateSummaries.Remove(vdr)
+
+	if len(summary) > 0 {
+		weight := uint64(0)
+		if beacon, ok := b.BootstrapConfig.Beacons.Get(vdr); ok {
+			weight = beacon.Weight()
+		}
+
+		key := hashing.ComputeHash256Array(summary)
+		newWeight, err := math.Add64(weight, b.stateSummaryVotes[key])
+		if err != nil {
+			newWeight = stdmath.MaxUint64
+		}
+		b.stateSummaryVotes[key] = newWeight
+		b.stateSummaries[key] = summary
+	}
+
+	if b.pendingStateSummaries.Len() == 0 {
+		b.syncState()
+	}
+}
+
+// syncState syncs the VM's state to the summary returned by the most beacon
+// weight, if that weight is at least alpha, and then bootstraps the blocks
+// after the summary
+func (b *bootstrapper) syncState() {
+	var (
+		summary    []byte
+		bestWeight uint64
+	)
+	for key, weight := range b.stateSummaryVotes {
+		if weight >= b.BootstrapConfig.Alpha && weight > bestWeight {
+			summary = b.stateSummaries[key]
+			bestWeight = weight
+		}
+	}
+	b.stateSummaryVotes = make(map[[32]byte]uint64)
+	b.stateSummaries = make(map[[32]byte][]byte)
+
+	if summary == nil {
+		b.BootstrapConfig.Context.Log.Info("State sync skipped as no state summary was returned by a quorum of the beacons")
+	} else if err := b.VM.(StateSyncableVM).SyncState(b.BootstrapConfig.VMContext(), summary); err != nil {
+		b.BootstrapConfig.Context.Log.Warn("State sync failed due to %s, bootstrapping every block instead", err)
+	} else {
+		b.BootstrapConfig.Context.Log.Info("State synced to block %s", b.VM.LastAccepted())
+	}
+
+	b.Bootstrapper.Startup()
 }
 
 // CurrentAcceptedFrontier ...
@@ -60,7 +181,7 @@ func (b *bootstrapper) CurrentAcceptedFrontier() ids.Set {
 func (b *bootstrapper) FilterAccepted(containerIDs ids.Set) ids.Set {
 	acceptedIDs := ids.Set{}
 	for _, blkID := range containerIDs.List() {
-		if blk, err := b.VM.GetBlock(blkID); err == nil && blk.Status() == choices.Accepted {
+		if blk, err := b.VM.GetBlock(b.BootstrapConfig.VMContext(), blkID); err == nil && blk.Status() == choices.Accepted {
 			acceptedIDs.Add(blkID)
 		}
 	}
@@ -88,7 +209,10 @@ func (b *bootstrapper) Put(vdr ids.ShortID, requestID uint32, blkID ids.ID, blkB
 		return
 	}
 
-	blk, err := b.VM.ParseBlock(blkBytes)
+	ctx, cancel := b.BootstrapConfig.RequestContext()
+	defer cancel()
+
+	blk, err := b.VM.ParseBlock(ctx, blkBytes)
 	if err != nil {
 		b.BootstrapConfig.Context.Log.Warn("ParseBlock failed due to %s for block:\n%s",
 			err,
@@ -108,14 +232,35 @@ func (b *bootstrapper) fetch(blkID ids.ID) {
 		return
 	}
 
-	blk, err := b.VM.GetBlock(blkID)
+	blk, err := b.VM.GetBlock(b.BootstrapConfig.VMContext(), blkID)
 	if err != nil {
-		b.sendRequest(blkID)
-		return
+		queuedBlk, ok := b.queuedBlock(blkID)
+		if !ok {
+			b.sendRequest(blkID)
+			return
+		}
+		blk = queuedBlk
 	}
 	b.addBlock(blk)
 }
 
+// queuedBlock returns the block [blkID] if it was fetched and pushed into the
+// queue, possibly before the node was restarted
+func (b *bootstrapper) queuedBlock(blkID ids.ID) (snowman.Block, bool) {
+	if has, err := b.Blocked.Has(blkID); err != nil || !has {
+		return nil, false
+	}
+	job, err := b.Blocked.Get(blkID)
+	if err != nil {
+		return nil, false
+	}
+	blkJob, ok := job.(*blockJob)
+	if !ok {
+		return nil, false
+	}
+	return blkJob.blk, true
+}
+
 func (b *bootstrapper) sendRequest(blkID ids.ID) {
 	validators := b.BootstrapConfig.Validators.Sample(1)
 	if len(validators) == 0 {
@@ -134,7 +279,17 @@ func (b *bootstrapper) sendRequest(blkID ids.ID) {
 func (b *bootstrapper) addBlock(blk snowman.Block) {
 	status := blk.Status()
 	blkID := blk.ID()
-	for status == choices.Processing {
+	for {
+		if status == choices.Unknown {
+			if queuedBlk, ok := b.queuedBlock(blkID); ok {
+				blk = queuedBlk
+				status = blk.Status()
+			}
+		}
+		if status != choices.Processing {
+			break
+		}
+
 		b.pending.Remove(blkID)
 
 		if err := b.Blocked.Push(&blockJob{
@@ -189,4 +344,7 @@ func (b *bootstrapper) executeAll(jobs *queue.Jobs, numBlocked prometheus.Gauge)
 			b.BootstrapConfig.Context.Log.Warn("Error executing: %s", err)
 		}
 	}
+	if err := jobs.Commit(); err != nil {
+		b.BootstrapConfig.Context.Log.Warn("Error committing the executed jobs: %s", err)
+	}
 }
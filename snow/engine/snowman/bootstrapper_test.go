@@ -18,7 +18,6 @@ import (
 	"github.com/ava-labs/gecko/snow/consensus/snowman"
 	"github.com/ava-labs/gecko/snow/engine/common"
 	"github.com/ava-labs/gecko/snow/engine/common/queue"
-	"github.com/ava-labs/gecko/snow/networking/handler"
 	"github.com/ava-labs/gecko/snow/networking/router"
 	"github.com/ava-labs/gecko/snow/networking/timeout"
 	"github.com/ava-labs/gecko/snow/validators"
@@ -28,6 +27,12 @@ var (
 	errUnknownBlock = errors.New("unknown block")
 )
 
+type acceptorFunc func(chainID, containerID ids.ID, container []byte) error
+
+func (f acceptorFunc) Accept(chainID, containerID ids.ID, container []byte) error {
+	return f(chainID, containerID, container)
+}
+
 func newConfig(t *testing.T) (BootstrapConfig, ids.ShortID, *common.SenderTest, *VMTest) {
 	ctx := snow.DefaultContextTest()
 
@@ -35,8 +40,6 @@ func newConfig(t *testing.T) (BootstrapConfig, ids.ShortID, *common.SenderTest,
 	db := memdb.New()
 	sender := &common.SenderTest{}
 	vm := &VMTest{}
-	engine := &Transitive{}
-	handler := &handler.Handler{}
 	router := &router.ChainRouter{}
 	timeouts := &timeout.Manager{}
 
@@ -52,7 +55,6 @@ func newConfig(t *testing.T) (BootstrapConfig, ids.ShortID, *common.SenderTest,
 	peerID := peer.ID()
 	peers.Add(peer)
 
-	handler.Initialize(engine, make(chan common.Message), 1)
 	timeouts.Initialize(0)
 	router.Initialize(ctx.Log, timeouts)
 
@@ -62,7 +64,7 @@ func newConfig(t *testing.T) (BootstrapConfig, ids.ShortID, *common.SenderTest,
 		Context:    ctx,
 		Validators: peers,
 		Beacons:    peers,
-		Alpha:      peers.Len()/2 + 1,
+		Alpha:      peers.Weight()/2 + 1,
 		Sender:     sender,
 	}
 	return BootstrapConfig{
@@ -349,6 +351,227 @@ func TestBootstrapperDependency(t *testing.T) {
 	}
 }
 
+func TestBootstrapperAcceptHooks(t *testing.T) {
+	config, peerID, sender, vm := newConfig(t)
+
+	accepted := []ids.ID(nil)
+	hooks := &common.AcceptHooks{}
+	hooks.Initialize(config.Context.Log, config.Context.ChainID, 0, nil)
+	hooks.OnAccept("test", acceptorFunc(func(_, blkID ids.ID, _ []byte) error {
+		accepted = append(accepted, blkID)
+		return nil
+	}))
+	config.AcceptHooks = hooks
+
+	blkID0 := ids.Empty.Prefix(0)
+	blkID1 := ids.Empty.Prefix(1)
+	blkID2 := ids.Empty.Prefix(2)
+
+	blkBytes0 := []byte{0}
+	blkBytes1 := []byte{1}
+	blkBytes2 := []byte{2}
+
+	blk0 := &Blk{
+		id:     blkID0,
+		height: 0,
+		status: choices.Accepted,
+		bytes:  blkBytes0,
+	}
+	blk1 := &Blk{
+		parent: blk0,
+		id:     blkID1,
+		height: 1,
+		status: choices.Unknown,
+		bytes:  blkBytes1,
+	}
+	blk2 := &Blk{
+		parent: blk1,
+		id:     blkID2,
+		height: 2,
+		status: choices.Processing,
+		bytes:  blkBytes2,
+	}
+
+	bs := bootstrapper{}
+	bs.metrics.Initialize(config.Context.Log, fmt.Sprintf("gecko_%s", config.Context.ChainID), prometheus.NewRegistry())
+	bs.Initialize(config)
+
+	acceptedIDs := ids.Set{}
+	acceptedIDs.Add(blkID2)
+
+	vm.GetBlockF = func(blkID ids.ID) (snowman.Block, error) {
+		switch {
+		case blkID.Equals(blkID2):
+			return blk2, nil
+		default:
+			t.Fatalf("Requested unknown block")
+			panic("Requested unknown block")
+		}
+	}
+
+	requestID := new(uint32)
+	sender.GetF = func(vdr ids.ShortID, reqID uint32, vtxID ids.ID) {
+		if !vdr.Equals(peerID) {
+			t.Fatalf("Should have requested block from %s, requested from %s", peerID, vdr)
+		}
+		switch {
+		case vtxID.Equals(blkID1):
+		default:
+			t.Fatalf("Requested unknown block")
+		}
+
+		*requestID = reqID
+	}
+
+	bs.ForceAccepted(acceptedIDs)
+
+	vm.GetBlockF = nil
+	sender.GetF = nil
+
+	vm.ParseBlockF = func(blkBytes []byte) (snowman.Block, error) {
+		switch {
+		case bytes.Equal(blkBytes, blkBytes1):
+			return blk1, nil
+		case bytes.Equal(blkBytes, blkBytes2):
+			return blk2, nil
+		}
+		t.Fatal(errUnknownBlock)
+		return nil, errUnknownBlock
+	}
+
+	blk1.status = choices.Processing
+
+	finished := new(bool)
+	bs.onFinished = func() { *finished = true }
+
+	bs.Put(peerID, *requestID, blkID1, blkBytes1)
+
+	if !*finished {
+		t.Fatalf("Bootstrapping should have finished")
+	}
+
+	// Wait for the hook to be sent the bootstrapped blocks
+	hooks.Shutdown()
+
+	switch {
+	case len(accepted) != 2:
+		t.Fatalf("Hook should have been sent 2 blocks, was sent %d", len(accepted))
+	case !accepted[0].Equals(blkID1):
+		t.Fatalf("Hook should have been sent %s first", blkID1)
+	case !accepted[1].Equals(blkID2):
+		t.Fatalf("Hook should have been sent %s second", blkID2)
+	}
+}
+
+func TestBootstrapperResumesFromCheckpoint(t *testing.T) {
+	config, peerID, sender, vm := newConfig(t)
+
+	db := memdb.New()
+	blocked, err := queue.New(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	blocked.SetCheckpointInterval(1)
+	config.Blocked = blocked
+
+	blkID0 := ids.Empty.Prefix(0)
+	blkID1 := ids.Empty.Prefix(1)
+	blkID2 := ids.Empty.Prefix(2)
+
+	blkBytes1 := []byte{1}
+	blkBytes2 := []byte{2}
+
+	blk0 := &Blk{
+		id:     blkID0,
+		height: 0,
+		status: choices.Accepted,
+		bytes:  []byte{0},
+	}
+	blk1 := &Blk{
+		parent: blk0,
+		id:     blkID1,
+		height: 1,
+		status: choices.Unknown,
+		bytes:  blkBytes1,
+	}
+	blk2 := &Blk{
+		parent: blk1,
+		id:     blkID2,
+		height: 2,
+		status: choices.Processing,
+		bytes:  blkBytes2,
+	}
+
+	bs := bootstrapper{}
+	bs.metrics.Initialize(config.Context.Log, fmt.Sprintf("gecko_%s", config.Context.ChainID), prometheus.NewRegistry())
+	bs.Initialize(config)
+
+	acceptedIDs := ids.Set{}
+	acceptedIDs.Add(blkID2)
+
+	requested := ids.Set{}
+	reqID := new(uint32)
+	sender.GetF = func(vdr ids.ShortID, innerReqID uint32, blkID ids.ID) {
+		requested.Add(blkID)
+		*reqID = innerReqID
+	}
+	vm.GetBlockF = func(blkID ids.ID) (snowman.Block, error) { return nil, errUnknownBlock }
+	vm.ParseBlockF = func(blkBytes []byte) (snowman.Block, error) {
+		switch {
+		case bytes.Equal(blkBytes, blkBytes1):
+			return blk1, nil
+		case bytes.Equal(blkBytes, blkBytes2):
+			return blk2, nil
+		}
+		t.Fatal(errUnknownBlock)
+		return nil, errUnknownBlock
+	}
+
+	bs.ForceAccepted(acceptedIDs)
+	bs.Put(peerID, *reqID, blkID2, blkBytes2)
+
+	if !requested.Contains(blkID1) {
+		t.Fatalf("Should have requested the parent block")
+	}
+
+	// Restart bootstrapping before the parent block arrives
+	blocked, err = queue.New(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	config.Blocked = blocked
+
+	bs = bootstrapper{}
+	bs.metrics.Initialize(config.Context.Log, fmt.Sprintf("gecko_%s", config.Context.ChainID), prometheus.NewRegistry())
+	bs.Initialize(config)
+
+	requested.Clear()
+	bs.ForceAccepted(acceptedIDs)
+
+	if requested.Contains(blkID2) {
+		t.Fatalf("Shouldn't have refetched the checkpointed block")
+	}
+	if !requested.Contains(blkID1) {
+		t.Fatalf("Should have requested the parent block")
+	}
+
+	finished := new(bool)
+	bs.onFinished = func() { *finished = true }
+
+	blk1.status = choices.Processing
+	bs.Put(peerID, *reqID, blkID1, blkBytes1)
+
+	if !*finished {
+		t.Fatalf("Bootstrapping should have finished")
+	}
+	if blk1.Status() != choices.Accepted {
+		t.Fatalf("Block should be accepted")
+	}
+	if blk2.Status() != choices.Accepted {
+		t.Fatalf("Block should be accepted")
+	}
+}
+
 func TestBootstrapperAcceptedFrontier(t *testing.T) {
 	config, _, _, vm := newConfig(t)
 
@@ -425,3 +648,118 @@ func TestBootstrapperFilterAccepted(t *testing.T) {
 		t.Fatalf("Blk shouldn't be accepted")
 	}
 }
+
+func newStateSyncConfig(t *testing.T) (BootstrapConfig, ids.ShortID, *common.SenderTest, *StateSyncableVMTest) {
+	config, peerID, sender, _ := newConfig(t)
+
+	vm := &StateSyncableVMTest{}
+	vm.T = t
+	vm.Default(true)
+
+	config.VM = vm
+	config.StateSync = true
+	return config, peerID, sender, vm
+}
+
+func TestBootstrapperStateSync(t *testing.T) {
+	config, peerID, sender, vm := newStateSyncConfig(t)
+
+	summary := []byte{1, 2, 3}
+	syncedID := ids.Empty.Prefix(1)
+
+	bs := bootstrapper{}
+	bs.metrics.Initialize(config.Context.Log, fmt.Sprintf("gecko_%s", config.Context.ChainID), prometheus.NewRegistry())
+	bs.Initialize(config)
+
+	reqID := new(uint32)
+	sender.GetStateSummaryF = func(vdrs ids.ShortSet, innerReqID uint32) {
+		if !vdrs.Contains(peerID) {
+			t.Fatalf("Should have requested the state summary from %s", peerID)
+		}
+		*reqID = innerReqID
+	}
+	frontierRequested := new(bool)
+	sender.GetAcceptedFrontierF = func(ids.ShortSet, uint32) {
+		*frontierRequested = true
+	}
+
+	bs.Startup()
+
+	if *frontierRequested {
+		t.Fatalf("Shouldn't have requested the accepted frontier before the state was synced")
+	}
+
+	synced := new(bool)
+	vm.SyncStateF = func(b []byte) error {
+		if !bytes.Equal(b, summary) {
+			t.Fatalf("Synced to the wrong summary")
+		}
+		*synced = true
+		return nil
+	}
+	vm.LastAcceptedF = func() ids.ID { return syncedID }
+
+	bs.StateSummary(peerID, *reqID, summary)
+
+	if !*synced {
+		t.Fatalf("Should have synced the state")
+	}
+	if !*frontierRequested {
+		t.Fatalf("Should have requested the accepted frontier after the state was synced")
+	}
+}
+
+func TestBootstrapperStateSyncNoQuorum(t *testing.T) {
+	config, peerID, sender, _ := newStateSyncConfig(t)
+
+	bs := bootstrapper{}
+	bs.metrics.Initialize(config.Context.Log, fmt.Sprintf("gecko_%s", config.Context.ChainID), prometheus.NewRegistry())
+	bs.Initialize(config)
+
+	reqID := new(uint32)
+	sender.GetStateSummaryF = func(_ ids.ShortSet, innerReqID uint32) {
+		*reqID = innerReqID
+	}
+	frontierRequested := new(bool)
+	sender.GetAcceptedFrontierF = func(ids.ShortSet, uint32) {
+		*frontierRequested = true
+	}
+
+	bs.Startup()
+	bs.GetStateSummaryFailed(peerID, *reqID)
+
+	if !*frontierRequested {
+		t.Fatalf("Should have bootstrapped every block after failing to get a state summary")
+	}
+}
+
+func TestBootstrapperGetStateSummary(t *testing.T) {
+	config, peerID, sender, vm := newStateSyncConfig(t)
+
+	summary := []byte{1, 2, 3}
+
+	bs := bootstrapper{}
+	bs.metrics.Initialize(config.Context.Log, fmt.Sprintf("gecko_%s", config.Context.ChainID), prometheus.NewRegistry())
+	bs.Initialize(config)
+
+	vm.StateSummaryF = func() ([]byte, error) { return summary, nil }
+
+	sent := new(bool)
+	sender.StateSummaryF = func(vdr ids.ShortID, requestID uint32, b []byte) {
+		switch {
+		case !vdr.Equals(peerID):
+			t.Fatalf("Sent the state summary to the wrong validator")
+		case requestID != 5:
+			t.Fatalf("Sent the state summary with the wrong request ID")
+		case !bytes.Equal(b, summary):
+			t.Fatalf("Sent the wrong state summary")
+		}
+		*sent = true
+	}
+
+	bs.GetStateSummary(peerID, 5)
+
+	if !*sent {
+		t.Fatalf("Should have sent the state summary")
+	}
+}
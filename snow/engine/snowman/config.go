@@ -14,4 +14,9 @@ type Config struct {
 
 	Params    snowball.Parameters
 	Consensus snowman.Consensus
+
+	// MaxOutstandingPolls is the most network polls that may be outstanding at
+	// once. Blocks issued past the limit are queried once an outstanding poll
+	// finishes. If 0, the number of outstanding polls isn't limited.
+	MaxOutstandingPolls int
 }
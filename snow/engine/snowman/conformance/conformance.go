@@ -0,0 +1,313 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package conformance checks that a Snowman VM upholds the invariants the
+// consensus engine relies on. VM authors should run it against their VM before
+// requesting that a chain be created with it:
+//
+//	func TestConformance(t *testing.T) {
+//		conformance.Run(t, conformance.Harness{
+//			NewVM:   func() snowman.ChainVM { return &VM{} },
+//			Genesis: genesisBytes,
+//		})
+//	}
+//
+// The invariants checked are:
+//   - After initialization, the last accepted block can be fetched, is
+//     accepted and parses from its own bytes to a block with the same ID.
+//   - Malformed bytes (nil, empty, truncated or with trailing bytes) are
+//     rejected by ParseBlock with an error, and never cause a panic.
+//   - Fetching a block that doesn't exist returns an error.
+//   - A built block is a child of the preferred block, is processing, passes
+//     verification, parses from its own bytes to the same ID and, once
+//     accepted, becomes the last accepted block.
+//   - After the VM is shut down, a VM initialized with the same database
+//     recovers the last accepted block, its bytes and its status.
+package conformance
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/ava-labs/gecko/database"
+	"github.com/ava-labs/gecko/database/memdb"
+	"github.com/ava-labs/gecko/database/prefixdb"
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow"
+	"github.com/ava-labs/gecko/snow/choices"
+	"github.com/ava-labs/gecko/snow/consensus/snowman"
+	"github.com/ava-labs/gecko/snow/engine/common"
+
+	smeng "github.com/ava-labs/gecko/snow/engine/snowman"
+)
+
+var (
+	// chainID is the ID of the chain the VMs under test run
+	chainID = ids.NewID([32]byte{'c', 'o', 'n', 'f', 'o', 'r', 'm'})
+
+	// vmPrefix is the prefix of the database given to the VMs under test.
+	// VMs may close their database on shutdown, so they're never given the
+	// underlying database, which must outlive them to test restart recovery.
+	vmPrefix = []byte("vm")
+)
+
+// Harness describes how to run a VM under test
+type Harness struct {
+	// NewVM returns a new, uninitialized, instance of the VM
+	NewVM func() smeng.ChainVM
+
+	// Genesis is the genesis data the VM is initialized with
+	Genesis []byte
+
+	// Fxs are the feature extensions the VM is initialized with
+	Fxs []*common.Fx
+
+	// PrepareBlock, if non-nil, is called before the suite asks [vm] to build
+	// a block. It should give the VM the data it needs to build a valid block,
+	// for example by issuing a transaction to it. If nil, blocks are built
+	// without preparation. The context lock is held while it's called.
+	PrepareBlock func(vm smeng.ChainVM) error
+}
+
+// Tests is a list of all the conformance tests, by name
+var Tests = []struct {
+	Name string
+	Test func(t *testing.T, h Harness)
+}{
+	{"Genesis", TestGenesis},
+	{"MalformedBytes", TestMalformedBytes},
+	{"UnknownBlock", TestUnknownBlock},
+	{"BlockLifecycle", TestBlockLifecycle},
+	{"RestartRecovery", TestRestartRecovery},
+}
+
+// Run every conformance test against the VM described by [h]
+func Run(t *testing.T, h Harness) {
+	for _, test := range Tests {
+		test := test
+		t.Run(test.Name, func(t *testing.T) { test.Test(t, h) })
+	}
+}
+
+// TestGenesis checks that the last accepted block of a newly initialized VM
+// can be fetched, is accepted and round trips through its bytes
+func TestGenesis(t *testing.T, h Harness) {
+	vm, ctx := h.initialize(t, memdb.New())
+	defer h.shutdown(vm, ctx)
+
+	ctx.Lock.Lock()
+	defer ctx.Lock.Unlock()
+
+	lastAcceptedID := vm.LastAccepted()
+	if lastAcceptedID.IsZero() {
+		t.Fatal("LastAccepted returned the empty ID")
+	}
+	lastAccepted, err := vm.GetBlock(context.Background(), lastAcceptedID)
+	if err != nil {
+		t.Fatalf("couldn't get the last accepted block: %s", err)
+	}
+	if !lastAccepted.ID().Equals(lastAcceptedID) {
+		t.Fatalf("GetBlock(%s) returned block %s", lastAcceptedID, lastAccepted.ID())
+	}
+	if status := lastAccepted.Status(); status != choices.Accepted {
+		t.Fatalf("last accepted block has status %s", status)
+	}
+	if err := roundTrip(vm, lastAccepted); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestMalformedBytes checks that ParseBlock returns an error, rather than
+// panicking or returning a block, when given malformed bytes
+func TestMalformedBytes(t *testing.T, h Harness) {
+	vm, ctx := h.initialize(t, memdb.New())
+	defer h.shutdown(vm, ctx)
+
+	ctx.Lock.Lock()
+	defer ctx.Lock.Unlock()
+
+	lastAccepted, err := vm.GetBlock(context.Background(), vm.LastAccepted())
+	if err != nil {
+		t.Fatalf("couldn't get the last accepted block: %s", err)
+	}
+	blkBytes := lastAccepted.Bytes()
+
+	malformed := map[string][]byte{
+		"nil":            nil,
+		"empty":          {},
+		"truncated":      blkBytes[:len(blkBytes)-1],
+		"trailing bytes": append(append([]byte{}, blkBytes...), 0),
+	}
+	if len(blkBytes) > 1 {
+		malformed["first byte only"] = blkBytes[:1]
+	}
+	for name, b := range malformed {
+		if panicked, err := parseNoPanic(vm, b); panicked != nil {
+			t.Fatalf("ParseBlock panicked on %s bytes: %v", name, panicked)
+		} else if err == nil {
+			t.Fatalf("ParseBlock should have errored on %s bytes", name)
+		}
+	}
+}
+
+// TestUnknownBlock checks that GetBlock returns an error for a block that
+// doesn't exist
+func TestUnknownBlock(t *testing.T, h Harness) {
+	vm, ctx := h.initialize(t, memdb.New())
+	defer h.shutdown(vm, ctx)
+
+	ctx.Lock.Lock()
+	defer ctx.Lock.Unlock()
+
+	unknownID := ids.NewID([32]byte{'u', 'n', 'k', 'n', 'o', 'w', 'n'})
+	if blk, err := vm.GetBlock(context.Background(), unknownID); err == nil {
+		t.Fatalf("GetBlock(%s) should have errored but returned block %s", unknownID, blk.ID())
+	}
+}
+
+// TestBlockLifecycle checks that a block can be built, verified, parsed and
+// accepted
+func TestBlockLifecycle(t *testing.T, h Harness) {
+	vm, ctx := h.initialize(t, memdb.New())
+	defer h.shutdown(vm, ctx)
+
+	ctx.Lock.Lock()
+	defer ctx.Lock.Unlock()
+
+	blk, err := h.buildAndVerify(vm)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	blk.Accept(context.Background())
+	if status := blk.Status(); status != choices.Accepted {
+		t.Fatalf("accepted block has status %s", status)
+	}
+	if lastAccepted := vm.LastAccepted(); !lastAccepted.Equals(blk.ID()) {
+		t.Fatalf("last accepted block should be %s but is %s", blk.ID(), lastAccepted)
+	}
+	fetched, err := vm.GetBlock(context.Background(), blk.ID())
+	if err != nil {
+		t.Fatalf("couldn't get the accepted block: %s", err)
+	}
+	if status := fetched.Status(); status != choices.Accepted {
+		t.Fatalf("fetched accepted block has status %s", status)
+	}
+}
+
+// TestRestartRecovery checks that a VM recovers its last accepted block after
+// being shut down and initialized again with the same database
+func TestRestartRecovery(t *testing.T, h Harness) {
+	db := memdb.New()
+
+	vm, ctx := h.initialize(t, db)
+	ctx.Lock.Lock()
+	blk, err := h.buildAndVerify(vm)
+	if err != nil {
+		ctx.Lock.Unlock()
+		t.Fatal(err)
+	}
+	blk.Accept(context.Background())
+	blkID := blk.ID()
+	blkBytes := blk.Bytes()
+	ctx.Lock.Unlock()
+	h.shutdown(vm, ctx)
+
+	vm, ctx = h.initialize(t, db)
+	defer h.shutdown(vm, ctx)
+
+	ctx.Lock.Lock()
+	defer ctx.Lock.Unlock()
+
+	if lastAccepted := vm.LastAccepted(); !lastAccepted.Equals(blkID) {
+		t.Fatalf("after restarting, the last accepted block should be %s but is %s", blkID, lastAccepted)
+	}
+	recovered, err := vm.GetBlock(context.Background(), blkID)
+	if err != nil {
+		t.Fatalf("after restarting, couldn't get the last accepted block: %s", err)
+	}
+	if !bytes.Equal(recovered.Bytes(), blkBytes) {
+		t.Fatal("after restarting, the last accepted block has different bytes")
+	}
+	if status := recovered.Status(); status != choices.Accepted {
+		t.Fatalf("after restarting, the last accepted block has status %s", status)
+	}
+}
+
+// initialize a new instance of the VM under test, whose state is kept in [db]
+func (h Harness) initialize(t *testing.T, db database.Database) (smeng.ChainVM, *snow.Context) {
+	ctx := snow.DefaultContextTest()
+	ctx.ChainID = chainID
+
+	vm := h.NewVM()
+	toEngine := make(chan common.Message, 1)
+	if err := vm.Initialize(ctx, prefixdb.New(vmPrefix, db), h.Genesis, toEngine, h.Fxs); err != nil {
+		t.Fatalf("couldn't initialize the VM: %s", err)
+	}
+	return vm, ctx
+}
+
+// shutdown [vm] with the context lock held, as the engine does
+func (h Harness) shutdown(vm smeng.ChainVM, ctx *snow.Context) {
+	ctx.Lock.Lock()
+	defer ctx.Lock.Unlock()
+
+	vm.Shutdown()
+}
+
+// buildAndVerify asks [vm] to build a block on top of its last accepted block
+// and checks the block before it's decided. Assumes the context lock is held.
+func (h Harness) buildAndVerify(vm smeng.ChainVM) (snowman.Block, error) {
+	parentID := vm.LastAccepted()
+	vm.SetPreference(parentID)
+
+	if h.PrepareBlock != nil {
+		if err := h.PrepareBlock(vm); err != nil {
+			return nil, fmt.Errorf("couldn't prepare a block: %w", err)
+		}
+	}
+	blk, err := vm.BuildBlock(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("couldn't build a block: %w", err)
+	}
+
+	if parent := blk.Parent(); parent == nil || !parent.ID().Equals(parentID) {
+		return nil, fmt.Errorf("built block %s isn't a child of the preferred block %s", blk.ID(), parentID)
+	}
+	if status := blk.Status(); status != choices.Processing {
+		return nil, fmt.Errorf("built block has status %s", status)
+	}
+	if err := blk.Verify(context.Background()); err != nil {
+		return nil, fmt.Errorf("built block failed verification: %w", err)
+	}
+	if err := roundTrip(vm, blk); err != nil {
+		return nil, err
+	}
+	return blk, nil
+}
+
+// roundTrip returns nil iff [blk]'s bytes parse to a block with [blk]'s ID and
+// bytes
+func roundTrip(vm smeng.ChainVM, blk snowman.Block) error {
+	parsed, err := vm.ParseBlock(context.Background(), blk.Bytes())
+	if err != nil {
+		return fmt.Errorf("couldn't parse block %s from its own bytes: %w", blk.ID(), err)
+	}
+	if !parsed.ID().Equals(blk.ID()) {
+		return fmt.Errorf("block %s parsed from its own bytes to block %s", blk.ID(), parsed.ID())
+	}
+	if !bytes.Equal(parsed.Bytes(), blk.Bytes()) {
+		return fmt.Errorf("block %s parsed from its own bytes to different bytes", blk.ID())
+	}
+	return nil
+}
+
+// parseNoPanic parses [b] with [vm]. If parsing panicked, the value the
+// parser panicked with is returned.
+func parseNoPanic(vm smeng.ChainVM, b []byte) (panicked interface{}, err error) {
+	defer func() { panicked = recover() }()
+	_, err = vm.ParseBlock(context.Background(), b)
+	return nil, err
+}
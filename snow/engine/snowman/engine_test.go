@@ -4,6 +4,7 @@
 package snowman
 
 import (
+	"context"
 	"sort"
 
 	"github.com/ava-labs/gecko/ids"
@@ -31,13 +32,13 @@ type Blk struct {
 	bytes []byte
 }
 
-func (b *Blk) ID() ids.ID             { return b.id }
-func (b *Blk) Parent() snowman.Block  { return b.parent }
-func (b *Blk) Accept()                { b.status = choices.Accepted }
-func (b *Blk) Reject()                { b.status = choices.Rejected }
-func (b *Blk) Status() choices.Status { return b.status }
-func (b *Blk) Verify() error          { return nil }
-func (b *Blk) Bytes() []byte          { return b.bytes }
+func (b *Blk) ID() ids.ID                   { return b.id }
+func (b *Blk) Parent() snowman.Block        { return b.parent }
+func (b *Blk) Accept(context.Context)       { b.status = choices.Accepted }
+func (b *Blk) Reject()                      { b.status = choices.Rejected }
+func (b *Blk) Status() choices.Status       { return b.status }
+func (b *Blk) Verify(context.Context) error { return nil }
+func (b *Blk) Bytes() []byte                { return b.bytes }
 
 type sortBks []*Blk
 
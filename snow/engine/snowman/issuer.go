@@ -4,6 +4,8 @@
 package snowman
 
 import (
+	"context"
+
 	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/snow/consensus/snowman"
 )
@@ -17,16 +19,16 @@ type issuer struct {
 
 func (i *issuer) Dependencies() ids.Set { return i.deps }
 
-func (i *issuer) Fulfill(id ids.ID) {
+func (i *issuer) Fulfill(ctx context.Context, id ids.ID) {
 	i.deps.Remove(id)
-	i.Update()
+	i.Update(ctx)
 }
 
-func (i *issuer) Abandon(ids.ID) {
+func (i *issuer) Abandon(ctx context.Context, _ ids.ID) {
 	if !i.abandoned {
 		blkID := i.blk.ID()
 		i.t.pending.Remove(blkID)
-		i.t.blocked.Abandon(blkID)
+		i.t.blocked.Abandon(ctx, blkID)
 
 		// Tracks performance statistics
 		i.t.numBlkRequests.Set(float64(i.t.blkReqs.Len()))
@@ -35,10 +37,10 @@ func (i *issuer) Abandon(ids.ID) {
 	i.abandoned = true
 }
 
-func (i *issuer) Update() {
+func (i *issuer) Update(ctx context.Context) {
 	if i.abandoned || i.deps.Len() != 0 {
 		return
 	}
 
-	i.t.deliver(i.blk)
+	i.t.deliver(ctx, i.blk)
 }
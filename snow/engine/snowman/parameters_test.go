@@ -0,0 +1,120 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package snowman
+
+import (
+	"testing"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow/choices"
+)
+
+func TestEngineSetParametersInvalid(t *testing.T) {
+	_, _, _, _, te, _ := setup(t)
+
+	params := te.Parameters()
+	params.Alpha = params.K + 1
+	if _, err := te.SetParameters(params); err == nil {
+		t.Fatalf("Should have errored due to alpha exceeding k")
+	}
+	if p := te.Parameters(); p.Alpha != params.K {
+		t.Fatalf("Shouldn't have replaced the parameters with invalid parameters")
+	}
+}
+
+func TestEngineSetParametersBetweenPolls(t *testing.T) {
+	vdr, _, sender, _, te, _ := setup(t)
+
+	requestID := new(uint32)
+	sender.PullQueryF = func(_ ids.ShortSet, reqID uint32, _ ids.ID) { *requestID = reqID }
+
+	te.repoll()
+
+	params := te.Parameters()
+	params.Metrics = nil
+	params.BetaVirtuous = 2
+	params.BetaRogue = 3
+	if applied, err := te.SetParameters(params); err != nil {
+		t.Fatal(err)
+	} else if applied {
+		t.Fatalf("Shouldn't have replaced the parameters while a poll is outstanding")
+	}
+	if p := te.Consensus.Parameters(); p.BetaVirtuous != 1 {
+		t.Fatalf("Shouldn't have replaced the consensus parameters while a poll is outstanding")
+	}
+
+	te.QueryFailed(vdr.ID(), *requestID)
+
+	if p := te.Parameters(); p.BetaVirtuous != 2 || p.BetaRogue != 3 {
+		t.Fatalf("Should have replaced the parameters once the poll finished")
+	}
+	if p := te.Consensus.Parameters(); p.BetaVirtuous != 2 || p.BetaRogue != 3 {
+		t.Fatalf("Should have replaced the consensus parameters once the poll finished")
+	}
+	if p := te.Parameters(); p.Metrics == nil {
+		t.Fatalf("Should have kept the metrics registerer")
+	}
+
+	params.BetaVirtuous = 4
+	params.BetaRogue = 4
+	if applied, err := te.SetParameters(params); err != nil {
+		t.Fatal(err)
+	} else if !applied {
+		t.Fatalf("Should have replaced the parameters immediately as no poll is outstanding")
+	}
+}
+
+func TestEngineSetParametersWaitsForOutstandingPolls(t *testing.T) {
+	vdr, _, sender, _, te, gBlk := setup(t)
+
+	requestIDs := []uint32(nil)
+	sender.PullQueryF = func(_ ids.ShortSet, reqID uint32, _ ids.ID) { requestIDs = append(requestIDs, reqID) }
+
+	te.repoll()
+	te.repoll()
+
+	params := te.Parameters()
+	params.BetaVirtuous = 2
+	params.BetaRogue = 3
+	if applied, err := te.SetParameters(params); err != nil {
+		t.Fatal(err)
+	} else if applied {
+		t.Fatalf("Shouldn't have replaced the parameters while polls are outstanding")
+	}
+
+	// Queries issued while the parameters are waiting to be replaced are queued
+	blk := &Blk{
+		parent: gBlk,
+		id:     GenerateID(),
+		status: choices.Processing,
+		bytes:  []byte{1},
+	}
+	te.pushSample(blk)
+	if len(te.queuedQueries) != 1 {
+		t.Fatalf("Should have queued the query")
+	}
+
+	te.QueryFailed(vdr.ID(), requestIDs[0])
+
+	if p := te.Consensus.Parameters(); p.BetaVirtuous != 1 {
+		t.Fatalf("Shouldn't have replaced the consensus parameters while a poll is outstanding")
+	}
+
+	queried := new(bool)
+	sender.PushQueryF = func(_ ids.ShortSet, _ uint32, blkID ids.ID, _ []byte) {
+		if !blkID.Equals(blk.ID()) {
+			t.Fatalf("Queried the wrong block")
+		}
+		*queried = true
+	}
+
+	te.QueryFailed(vdr.ID(), requestIDs[1])
+
+	if p := te.Consensus.Parameters(); p.BetaVirtuous != 2 || p.BetaRogue != 3 {
+		t.Fatalf("Should have replaced the consensus parameters once the polls finished")
+	}
+	if !*queried {
+		t.Fatalf("Should have sent the queued query once the parameters were replaced")
+	}
+}
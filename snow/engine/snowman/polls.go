@@ -6,6 +6,7 @@ package snowman
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/utils/logging"
@@ -13,10 +14,11 @@ import (
 )
 
 type polls struct {
-	log      logging.Logger
-	numPolls prometheus.Gauge
-	alpha    int
-	m        map[uint32]poll
+	log          logging.Logger
+	numPolls     prometheus.Gauge
+	pollDuration prometheus.Histogram
+	alpha        int
+	m            map[uint32]poll
 }
 
 // Add to the current set of polls
@@ -27,6 +29,7 @@ func (p *polls) Add(requestID uint32, numPolled int) bool {
 	if !exists {
 		poll.alpha = p.alpha
 		poll.numPolled = numPolled
+		poll.start = time.Now()
 		p.m[requestID] = poll
 
 		p.numPolls.Set(float64(len(p.m))) // Tracks performance statistics
@@ -45,7 +48,8 @@ func (p *polls) Vote(requestID uint32, vdr ids.ShortID, vote ids.ID) (ids.Bag, b
 	poll.Vote(vote)
 	if poll.Finished() {
 		delete(p.m, requestID)
-		p.numPolls.Set(float64(len(p.m))) // Tracks performance statistics
+		p.numPolls.Set(float64(len(p.m)))                        // Tracks performance statistics
+		p.pollDuration.Observe(time.Since(poll.start).Seconds()) // Tracks performance statistics
 		return poll.votes, true
 	}
 	p.m[requestID] = poll
@@ -63,7 +67,8 @@ func (p *polls) CancelVote(requestID uint32, vdr ids.ShortID) (ids.Bag, bool) {
 	poll.CancelVote()
 	if poll.Finished() {
 		delete(p.m, requestID)
-		p.numPolls.Set(float64(len(p.m))) // Tracks performance statistics
+		p.numPolls.Set(float64(len(p.m)))                        // Tracks performance statistics
+		p.pollDuration.Observe(time.Since(poll.start).Seconds()) // Tracks performance statistics
 		return poll.votes, true
 	}
 	p.m[requestID] = poll
@@ -86,6 +91,8 @@ type poll struct {
 	alpha     int
 	votes     ids.Bag
 	numPolled int
+	// When the poll was sent
+	start time.Time
 }
 
 // Vote registers a vote for this poll
@@ -0,0 +1,65 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package snowman
+
+import (
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow/consensus/snowman"
+)
+
+// pushSample sends a push query for [blk] to a sample of validators. If
+// MaxOutstandingPolls polls are outstanding, or the consensus parameters are
+// waiting to be replaced, the query is queued until a poll finishes, so that a
+// flood of blocks can't start an unbounded number of polls.
+func (t *Transitive) pushSample(blk snowman.Block) {
+	if t.holdQueries() {
+		t.queuedQueries = append(t.queuedQueries, blk)
+		t.numQueuedPolls.Set(float64(len(t.queuedQueries)))
+		return
+	}
+	t.pushQuery(blk)
+}
+
+// sendQueuedQueries sends the queued queries, oldest first, until
+// MaxOutstandingPolls polls are outstanding or the consensus parameters are
+// waiting to be replaced. Blocks that were decided while their query was
+// queued aren't queried.
+func (t *Transitive) sendQueuedQueries() {
+	for len(t.queuedQueries) > 0 && !t.holdQueries() {
+		blk := t.queuedQueries[0]
+		t.queuedQueries = t.queuedQueries[1:]
+		if !blk.Status().Decided() {
+			t.pushQuery(blk)
+		}
+	}
+	if len(t.queuedQueries) == 0 {
+		t.queuedQueries = nil
+	}
+	t.numQueuedPolls.Set(float64(len(t.queuedQueries)))
+}
+
+// holdQueries returns true if new queries must be queued rather than sent.
+// While the consensus parameters are waiting to be replaced, no polls are
+// started, so that the outstanding polls finish and the parameters are replaced.
+func (t *Transitive) holdQueries() bool {
+	return t.pendingParams != nil ||
+		(t.MaxOutstandingPolls > 0 && len(t.polls.m) >= t.MaxOutstandingPolls)
+}
+
+func (t *Transitive) pushQuery(blk snowman.Block) {
+	t.Config.Context.Log.Verbo("About to sample from: %s", t.Config.Validators)
+	p := t.Consensus.Parameters()
+	vdrs := t.Config.Validators.Sample(p.K)
+	vdrSet := ids.ShortSet{}
+	for _, vdr := range vdrs {
+		vdrSet.Add(vdr.ID())
+	}
+
+	t.RequestID++
+	if numVdrs := len(vdrs); numVdrs == p.K && t.polls.Add(t.RequestID, vdrSet.Len()) {
+		t.Config.Sender.PushQuery(vdrSet, t.RequestID, blk.ID(), blk.Bytes())
+	} else if numVdrs < p.K {
+		t.Config.Context.Log.Error("Query for %s was dropped due to an insufficient number of validators", blk.ID())
+	}
+}
@@ -0,0 +1,57 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package snowman
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow/choices"
+)
+
+func TestEngineMaxOutstandingPolls(t *testing.T) {
+	vdr, _, sender, _, te, gBlk := setup(t)
+
+	te.MaxOutstandingPolls = 1
+
+	blk0 := &Blk{
+		parent: gBlk,
+		id:     GenerateID(),
+		status: choices.Processing,
+		bytes:  []byte{1},
+	}
+	blk1 := &Blk{
+		parent: blk0,
+		id:     GenerateID(),
+		status: choices.Processing,
+		bytes:  []byte{2},
+	}
+
+	queried := []ids.ID(nil)
+	requestID := new(uint32)
+	sender.PushQueryF = func(_ ids.ShortSet, reqID uint32, blkID ids.ID, _ []byte) {
+		*requestID = reqID
+		queried = append(queried, blkID)
+	}
+
+	te.deliver(context.Background(), blk0)
+	te.deliver(context.Background(), blk1)
+
+	if len(queried) != 1 || !queried[0].Equals(blk0.ID()) {
+		t.Fatalf("Should have only queried the first block")
+	}
+	if len(te.queuedQueries) != 1 {
+		t.Fatalf("Should have queued the query for the second block")
+	}
+
+	te.QueryFailed(vdr.ID(), *requestID)
+
+	if len(queried) != 2 || !queried[1].Equals(blk1.ID()) {
+		t.Fatalf("Should have queried the second block once the first poll finished")
+	}
+	if len(te.queuedQueries) != 0 {
+		t.Fatalf("Shouldn't have any queued queries")
+	}
+}
@@ -0,0 +1,81 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package snowman
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gorilla/rpc/v2"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow/choices"
+	"github.com/ava-labs/gecko/snow/engine/common"
+
+	cjson "github.com/ava-labs/gecko/utils/json"
+)
+
+var (
+	errBootstrapping = errors.New("the chain is still bootstrapping")
+)
+
+// Service is the API service of the Snowman engine. It exposes consensus state
+// that VMs don't have access to.
+type Service struct{ engine *Transitive }
+
+// NewService returns the API service of [engine]. The service reads the
+// engine's state, so it must be served holding the chain's context lock.
+func NewService(engine *Transitive) *common.HTTPHandler {
+	newServer := rpc.NewServer()
+	codec := cjson.NewCodec()
+	newServer.RegisterCodec(codec, "application/json")
+	newServer.RegisterCodec(codec, "application/json;charset=UTF-8")
+	newServer.RegisterService(&Service{engine: engine}, "snowman")
+	return &common.HTTPHandler{LockOptions: common.ReadLock, Handler: newServer}
+}
+
+// GetBlockConfidenceArgs are the arguments for calling GetBlockConfidence
+type GetBlockConfidenceArgs struct {
+	BlockID ids.ID `json:"blockID"`
+}
+
+// GetBlockConfidenceReply are the results from calling GetBlockConfidence
+type GetBlockConfidenceReply struct {
+	Status choices.Status `json:"status"`
+	// Number of consecutive successful polls for the block
+	Confidence cjson.Uint32 `json:"confidence"`
+	// Number of consecutive successful polls that finalize the block. The
+	// block's progress towards finality is confidence / beta.
+	Beta cjson.Uint32 `json:"beta"`
+}
+
+// GetBlockConfidence returns how close a block is to being accepted. Accepted
+// blocks report a confidence equal to beta, rejected and unknown blocks report
+// a confidence of 0.
+func (service *Service) GetBlockConfidence(_ *http.Request, args *GetBlockConfidenceArgs, reply *GetBlockConfidenceReply) error {
+	t := service.engine
+	t.Config.Context.Log.Verbo("Snowman: GetBlockConfidence called with %s", args.BlockID)
+
+	if !t.bootstrapped {
+		return errBootstrapping
+	}
+
+	if confidence, beta, processing := t.Consensus.Confidence(args.BlockID); processing {
+		reply.Status = choices.Processing
+		reply.Confidence = cjson.Uint32(confidence)
+		reply.Beta = cjson.Uint32(beta)
+		return nil
+	}
+
+	params := t.Consensus.Parameters()
+	reply.Status = choices.Unknown
+	reply.Beta = cjson.Uint32(params.BetaVirtuous)
+	if blk, err := t.Config.VM.GetBlock(t.Config.VMContext(), args.BlockID); err == nil {
+		reply.Status = blk.Status()
+	}
+	if reply.Status == choices.Accepted {
+		reply.Confidence = reply.Beta
+	}
+	return nil
+}
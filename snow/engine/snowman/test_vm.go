@@ -4,6 +4,7 @@
 package snowman
 
 import (
+	"context"
 	"errors"
 
 	"github.com/ava-labs/gecko/ids"
@@ -15,6 +16,9 @@ var (
 	errBuildBlock = errors.New("unexpectedly called BuildBlock")
 	errParseBlock = errors.New("unexpectedly called ParseBlock")
 	errGetBlock   = errors.New("unexpectedly called GetBlock")
+
+	errStateSummary = errors.New("unexpectedly called StateSummary")
+	errSyncState    = errors.New("unexpectedly called SyncState")
 )
 
 // VMTest ...
@@ -46,7 +50,7 @@ func (vm *VMTest) Default(cant bool) {
 }
 
 // BuildBlock ...
-func (vm *VMTest) BuildBlock() (snowman.Block, error) {
+func (vm *VMTest) BuildBlock(context.Context) (snowman.Block, error) {
 	if vm.BuildBlockF != nil {
 		return vm.BuildBlockF()
 	}
@@ -57,7 +61,7 @@ func (vm *VMTest) BuildBlock() (snowman.Block, error) {
 }
 
 // ParseBlock ...
-func (vm *VMTest) ParseBlock(b []byte) (snowman.Block, error) {
+func (vm *VMTest) ParseBlock(_ context.Context, b []byte) (snowman.Block, error) {
 	if vm.ParseBlockF != nil {
 		return vm.ParseBlockF(b)
 	}
@@ -68,7 +72,7 @@ func (vm *VMTest) ParseBlock(b []byte) (snowman.Block, error) {
 }
 
 // GetBlock ...
-func (vm *VMTest) GetBlock(id ids.ID) (snowman.Block, error) {
+func (vm *VMTest) GetBlock(_ context.Context, id ids.ID) (snowman.Block, error) {
 	if vm.GetBlockF != nil {
 		return vm.GetBlockF(id)
 	}
@@ -97,3 +101,44 @@ func (vm *VMTest) LastAccepted() ids.ID {
 	}
 	return ids.ID{}
 }
+
+// StateSyncableVMTest ...
+type StateSyncableVMTest struct {
+	VMTest
+
+	CantStateSummary,
+	CantSyncState bool
+
+	StateSummaryF func() ([]byte, error)
+	SyncStateF    func([]byte) error
+}
+
+// Default ...
+func (vm *StateSyncableVMTest) Default(cant bool) {
+	vm.VMTest.Default(cant)
+
+	vm.CantStateSummary = cant
+	vm.CantSyncState = cant
+}
+
+// StateSummary ...
+func (vm *StateSyncableVMTest) StateSummary(context.Context) ([]byte, error) {
+	if vm.StateSummaryF != nil {
+		return vm.StateSummaryF()
+	}
+	if vm.CantStateSummary && vm.T != nil {
+		vm.T.Fatal(errStateSummary)
+	}
+	return nil, errStateSummary
+}
+
+// SyncState ...
+func (vm *StateSyncableVMTest) SyncState(_ context.Context, summary []byte) error {
+	if vm.SyncStateF != nil {
+		return vm.SyncStateF(summary)
+	}
+	if vm.CantSyncState && vm.T != nil {
+		vm.T.Fatal(errSyncState)
+	}
+	return errSyncState
+}
@@ -4,13 +4,18 @@
 package snowman
 
 import (
+	"context"
+	"time"
+
 	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/snow"
 	"github.com/ava-labs/gecko/snow/choices"
+	"github.com/ava-labs/gecko/snow/consensus/snowball"
 	"github.com/ava-labs/gecko/snow/consensus/snowman"
 	"github.com/ava-labs/gecko/snow/engine/common"
 	"github.com/ava-labs/gecko/snow/events"
 	"github.com/ava-labs/gecko/utils/formatting"
+	"github.com/ava-labs/gecko/utils/logging"
 )
 
 // Transitive implements the Engine interface by attempting to fetch all
@@ -25,6 +30,18 @@ type Transitive struct {
 
 	blocked events.Blocker // track operations that are blocked on blocks
 
+	// queuedQueries are the blocks, oldest first, waiting to be queried
+	// because MaxOutstandingPolls polls were outstanding when they were issued
+	queuedQueries []snowman.Block
+
+	// pendingParams, if non-nil, replace the consensus parameters once no
+	// polls are outstanding
+	pendingParams *snowball.Parameters
+
+	// Peers can send any number of unparsable or invalid blocks, so their
+	// failures are logged at most once a second
+	parseFailureLogs, verifyFailureLogs logging.Sampler
+
 	bootstrapped bool
 }
 
@@ -40,8 +57,12 @@ func (t *Transitive) Initialize(config Config) {
 
 	t.polls.log = config.Context.Log
 	t.polls.numPolls = t.numPolls
+	t.polls.pollDuration = t.pollDuration
 	t.polls.alpha = t.Params.Alpha
 	t.polls.m = make(map[uint32]poll)
+
+	t.parseFailureLogs.Interval = time.Second
+	t.verifyFailureLogs.Interval = time.Second
 }
 
 func (t *Transitive) finishBootstrapping() {
@@ -49,6 +70,7 @@ func (t *Transitive) finishBootstrapping() {
 	t.Config.VM.SetPreference(tail)
 	t.Consensus.Initialize(t.Config.Context, t.Params, tail)
 	t.bootstrapped = true
+	t.metrics.bootstrapped.Set(1)
 }
 
 // Shutdown implements the Engine interface
@@ -62,7 +84,10 @@ func (t *Transitive) Context() *snow.Context { return t.Config.Context }
 
 // Get implements the Engine interface
 func (t *Transitive) Get(vdr ids.ShortID, requestID uint32, blkID ids.ID) {
-	if blk, err := t.Config.VM.GetBlock(blkID); err == nil {
+	ctx, cancel := t.Config.RequestContext()
+	defer cancel()
+
+	if blk, err := t.Config.VM.GetBlock(ctx, blkID); err == nil {
 		t.Config.Sender.Put(vdr, requestID, blkID, blk.Bytes())
 	}
 }
@@ -76,16 +101,23 @@ func (t *Transitive) Put(vdr ids.ShortID, requestID uint32, blkID ids.ID, blkByt
 		return
 	}
 
-	blk, err := t.Config.VM.ParseBlock(blkBytes)
+	ctx, cancel := t.Config.RequestContext()
+	defer cancel()
+
+	t.put(ctx, vdr, requestID, blkID, blkBytes)
+}
+
+func (t *Transitive) put(ctx context.Context, vdr ids.ShortID, requestID uint32, blkID ids.ID, blkBytes []byte) {
+	blk, err := t.Config.VM.ParseBlock(ctx, blkBytes)
 	if err != nil {
-		t.Config.Context.Log.Warn("ParseBlock failed due to %s for block:\n%s",
+		t.parseFailureLogs.Log(t.Config.Context.Log, logging.Warn, "ParseBlock failed due to %s for block:\n%s",
 			err,
 			formatting.DumpBytes{Bytes: blkBytes})
-		t.GetFailed(vdr, requestID, blkID)
+		t.getFailed(ctx, blkID)
 		return
 	}
 
-	t.insertFrom(vdr, blk)
+	t.insertFrom(ctx, vdr, blk)
 }
 
 // GetFailed implements the Engine interface
@@ -95,8 +127,15 @@ func (t *Transitive) GetFailed(vdr ids.ShortID, requestID uint32, blkID ids.ID)
 		return
 	}
 
+	ctx, cancel := t.Config.RequestContext()
+	defer cancel()
+
+	t.getFailed(ctx, blkID)
+}
+
+func (t *Transitive) getFailed(ctx context.Context, blkID ids.ID) {
 	t.pending.Remove(blkID)
-	t.blocked.Abandon(blkID)
+	t.blocked.Abandon(ctx, blkID)
 	t.blkReqs.Remove(blkID)
 
 	// Tracks performance statistics
@@ -110,6 +149,13 @@ func (t *Transitive) PullQuery(vdr ids.ShortID, requestID uint32, blkID ids.ID)
 		return
 	}
 
+	ctx, cancel := t.Config.RequestContext()
+	defer cancel()
+
+	t.pullQuery(ctx, vdr, requestID, blkID)
+}
+
+func (t *Transitive) pullQuery(ctx context.Context, vdr ids.ShortID, requestID uint32, blkID ids.ID) {
 	c := &convincer{
 		consensus: t.Consensus,
 		sender:    t.Config.Sender,
@@ -117,11 +163,11 @@ func (t *Transitive) PullQuery(vdr ids.ShortID, requestID uint32, blkID ids.ID)
 		requestID: requestID,
 	}
 
-	if !t.reinsertFrom(vdr, blkID) {
+	if !t.reinsertFrom(ctx, vdr, blkID) {
 		c.deps.Add(blkID)
 	}
 
-	t.blocked.Register(c)
+	t.blocked.Register(ctx, c)
 }
 
 // PushQuery implements the Engine interface
@@ -131,8 +177,11 @@ func (t *Transitive) PushQuery(vdr ids.ShortID, requestID uint32, blkID ids.ID,
 		return
 	}
 
-	t.Put(vdr, requestID, blkID, blk)
-	t.PullQuery(vdr, requestID, blkID)
+	ctx, cancel := t.Config.RequestContext()
+	defer cancel()
+
+	t.put(ctx, vdr, requestID, blkID, blk)
+	t.pullQuery(ctx, vdr, requestID, blkID)
 }
 
 // Chits implements the Engine interface
@@ -148,6 +197,10 @@ func (t *Transitive) Chits(vdr ids.ShortID, requestID uint32, votes ids.Set) {
 		t.QueryFailed(vdr, requestID)
 		return
 	}
+
+	ctx, cancel := t.Config.RequestContext()
+	defer cancel()
+
 	vote := votes.List()[0]
 
 	t.Config.Context.Log.Verbo("Chit was called. RequestID: %v. Vote: %s", requestID, vote)
@@ -159,11 +212,11 @@ func (t *Transitive) Chits(vdr ids.ShortID, requestID uint32, votes ids.Set) {
 		response:  vote,
 	}
 
-	if !t.reinsertFrom(vdr, vote) {
+	if !t.reinsertFrom(ctx, vdr, vote) {
 		v.deps.Add(vote)
 	}
 
-	t.blocked.Register(v)
+	t.blocked.Register(ctx, v)
 }
 
 // QueryFailed implements the Engine interface
@@ -173,7 +226,10 @@ func (t *Transitive) QueryFailed(vdr ids.ShortID, requestID uint32) {
 		return
 	}
 
-	t.blocked.Register(&voter{
+	ctx, cancel := t.Config.RequestContext()
+	defer cancel()
+
+	t.blocked.Register(ctx, &voter{
 		t:         t,
 		vdr:       vdr,
 		requestID: requestID,
@@ -187,10 +243,13 @@ func (t *Transitive) Notify(msg common.Message) {
 		return
 	}
 
+	ctx, cancel := t.Config.RequestContext()
+	defer cancel()
+
 	t.Config.Context.Log.Verbo("Snowman engine notified of %s from the vm", msg)
 	switch msg {
 	case common.PendingTxs:
-		if blk, err := t.Config.VM.BuildBlock(); err == nil {
+		if blk, err := t.Config.VM.BuildBlock(ctx); err == nil {
 			if status := blk.Status(); status != choices.Processing {
 				t.Config.Context.Log.Warn("Attempting to issue a block with status: %s, expected Processing", status)
 			}
@@ -198,7 +257,7 @@ func (t *Transitive) Notify(msg common.Message) {
 			if pref := t.Consensus.Preference(); !parentID.Equals(pref) {
 				t.Config.Context.Log.Warn("Built block with parent: %s, expected %s", parentID, pref)
 			}
-			if t.insertAll(blk) {
+			if t.insertAll(ctx, blk) {
 				t.Config.Context.Log.Verbo("Successfully issued new block from the VM")
 			} else {
 				t.Config.Context.Log.Warn("VM.BuildBlock returned a block that is pending for ancestors")
@@ -211,24 +270,49 @@ func (t *Transitive) Notify(msg common.Message) {
 	}
 }
 
+// Gossip implements the Engine interface
+func (t *Transitive) Gossip() {
+	if !t.bootstrapped {
+		return
+	}
+	t.Config.Sender.GossipFrontier(t.CurrentAcceptedFrontier())
+}
+
+// GossipFrontier implements the Engine interface. The blocks in the gossiped
+// frontier that this engine doesn't have are fetched from the peer, so that
+// this engine catches up without waiting to be queried about them.
+func (t *Transitive) GossipFrontier(vdr ids.ShortID, blkIDs ids.Set) {
+	if !t.bootstrapped {
+		t.bootstrapper.GossipFrontier(vdr, blkIDs)
+		return
+	}
+
+	ctx, cancel := t.Config.RequestContext()
+	defer cancel()
+
+	for _, blkID := range blkIDs.List() {
+		t.reinsertFrom(ctx, vdr, blkID)
+	}
+}
+
 func (t *Transitive) repoll() {
 	prefID := t.Consensus.Preference()
 	t.pullSample(prefID)
 }
 
-func (t *Transitive) reinsertFrom(vdr ids.ShortID, blkID ids.ID) bool {
-	blk, err := t.Config.VM.GetBlock(blkID)
+func (t *Transitive) reinsertFrom(ctx context.Context, vdr ids.ShortID, blkID ids.ID) bool {
+	blk, err := t.Config.VM.GetBlock(ctx, blkID)
 	if err != nil {
 		t.sendRequest(vdr, blkID)
 		return false
 	}
-	return t.insertFrom(vdr, blk)
+	return t.insertFrom(ctx, vdr, blk)
 }
 
-func (t *Transitive) insertFrom(vdr ids.ShortID, blk snowman.Block) bool {
+func (t *Transitive) insertFrom(ctx context.Context, vdr ids.ShortID, blk snowman.Block) bool {
 	blkID := blk.ID()
 	for !t.Consensus.Issued(blk) && !t.pending.Contains(blkID) {
-		t.insert(blk)
+		t.insert(ctx, blk)
 
 		parent := blk.Parent()
 		parentID := parent.ID()
@@ -243,16 +327,16 @@ func (t *Transitive) insertFrom(vdr ids.ShortID, blk snowman.Block) bool {
 	return !t.pending.Contains(blkID)
 }
 
-func (t *Transitive) insertAll(blk snowman.Block) bool {
+func (t *Transitive) insertAll(ctx context.Context, blk snowman.Block) bool {
 	blkID := blk.ID()
 	for blk.Status().Fetched() && !t.Consensus.Issued(blk) && !t.pending.Contains(blkID) {
-		t.insert(blk)
+		t.insert(ctx, blk)
 		blk = blk.Parent()
 	}
 	return !t.pending.Contains(blkID)
 }
 
-func (t *Transitive) insert(blk snowman.Block) {
+func (t *Transitive) insert(ctx context.Context, blk snowman.Block) {
 	blkID := blk.ID()
 
 	t.pending.Add(blkID)
@@ -269,7 +353,7 @@ func (t *Transitive) insert(blk snowman.Block) {
 		i.deps.Add(parentID)
 	}
 
-	t.blocked.Register(i)
+	t.blocked.Register(ctx, i)
 
 	// Tracks performance statistics
 	t.numBlkRequests.Set(float64(t.blkReqs.Len()))
@@ -305,24 +389,7 @@ func (t *Transitive) pullSample(blkID ids.ID) {
 	}
 }
 
-func (t *Transitive) pushSample(blk snowman.Block) {
-	t.Config.Context.Log.Verbo("About to sample from: %s", t.Config.Validators)
-	p := t.Consensus.Parameters()
-	vdrs := t.Config.Validators.Sample(p.K)
-	vdrSet := ids.ShortSet{}
-	for _, vdr := range vdrs {
-		vdrSet.Add(vdr.ID())
-	}
-
-	t.RequestID++
-	if numVdrs := len(vdrs); numVdrs == p.K && t.polls.Add(t.RequestID, vdrSet.Len()) {
-		t.Config.Sender.PushQuery(vdrSet, t.RequestID, blk.ID(), blk.Bytes())
-	} else if numVdrs < p.K {
-		t.Config.Context.Log.Error("Query for %s was dropped due to an insufficient number of validators", blk.ID())
-	}
-}
-
-func (t *Transitive) deliver(blk snowman.Block) {
+func (t *Transitive) deliver(ctx context.Context, blk snowman.Block) {
 	if t.Consensus.Issued(blk) {
 		return
 	}
@@ -330,9 +397,9 @@ func (t *Transitive) deliver(blk snowman.Block) {
 	blkID := blk.ID()
 	t.pending.Remove(blkID)
 
-	if err := blk.Verify(); err != nil {
-		t.Config.Context.Log.Debug("Block failed verification due to %s, dropping block", err)
-		t.blocked.Abandon(blkID)
+	if err := blk.Verify(ctx); err != nil {
+		t.verifyFailureLogs.Log(t.Config.Context.Log, logging.Debug, "Block failed verification due to %s, dropping block", err)
+		t.blocked.Abandon(ctx, blkID)
 		t.numBlockedBlk.Set(float64(t.pending.Len())) // Tracks performance statistics
 		return
 	}
@@ -347,9 +414,9 @@ func (t *Transitive) deliver(blk snowman.Block) {
 	switch blk := blk.(type) {
 	case OracleBlock:
 		for _, blk := range blk.Options() {
-			if err := blk.Verify(); err != nil {
-				t.Config.Context.Log.Debug("Block failed verification due to %s, dropping block", err)
-				t.blocked.Abandon(blk.ID())
+			if err := blk.Verify(ctx); err != nil {
+				t.verifyFailureLogs.Log(t.Config.Context.Log, logging.Debug, "Block failed verification due to %s, dropping block", err)
+				t.blocked.Abandon(ctx, blk.ID())
 				dropped = append(dropped, blk)
 			} else {
 				t.Consensus.Add(blk)
@@ -360,17 +427,17 @@ func (t *Transitive) deliver(blk snowman.Block) {
 	}
 
 	t.Config.VM.SetPreference(t.Consensus.Preference())
-	t.blocked.Fulfill(blkID)
+	t.blocked.Fulfill(ctx, blkID)
 
 	for _, blk := range added {
 		blkID := blk.ID()
 		t.pending.Remove(blkID)
-		t.blocked.Fulfill(blkID)
+		t.blocked.Fulfill(ctx, blkID)
 	}
 	for _, blk := range dropped {
 		blkID := blk.ID()
 		t.pending.Remove(blkID)
-		t.blocked.Abandon(blkID)
+		t.blocked.Abandon(ctx, blkID)
 	}
 
 	// Tracks performance statistics
@@ -5,6 +5,7 @@ package snowman
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"testing"
 
@@ -375,7 +376,7 @@ func TestEngineMultipleQuery(t *testing.T) {
 		}
 	}
 
-	te.insert(blk0)
+	te.insert(context.Background(), blk0)
 
 	blk1 := &Blk{
 		parent: blk0,
@@ -472,10 +473,10 @@ func TestEngineBlockedIssue(t *testing.T) {
 		bytes:  []byte{1},
 	}
 
-	te.insert(blk1)
+	te.insert(context.Background(), blk1)
 
 	blk0.status = choices.Processing
-	te.insert(blk0)
+	te.insert(context.Background(), blk0)
 
 	if !blk1.ID().Equals(te.Consensus.Preference()) {
 		t.Fatalf("Should have issued blk1")
@@ -494,7 +495,7 @@ func TestEngineAbandonResponse(t *testing.T) {
 		bytes:  []byte{1},
 	}
 
-	te.insert(blk)
+	te.insert(context.Background(), blk)
 	te.QueryFailed(vdr.ID(), 1)
 
 	if len(te.blocked) != 0 {
@@ -752,7 +753,7 @@ func TestVoteCanceling(t *testing.T) {
 		}
 	}
 
-	te.insert(blk)
+	te.insert(context.Background(), blk)
 
 	if len(te.polls.m) != 1 {
 		t.Fatalf("Shouldn't have finished blocking issue")
@@ -806,7 +807,7 @@ func TestEngineNoQuery(t *testing.T) {
 		bytes:  []byte{1},
 	}
 
-	te.insert(blk)
+	te.insert(context.Background(), blk)
 }
 
 func TestEngineNoRepollQuery(t *testing.T) {
@@ -881,7 +882,7 @@ func TestEngineAbandonChit(t *testing.T) {
 
 	sender.CantPushQuery = false
 
-	te.insert(blk)
+	te.insert(context.Background(), blk)
 
 	fakeBlkID := GenerateID()
 	vm.GetBlockF = func(id ids.ID) (snowman.Block, error) {
@@ -933,7 +934,7 @@ func TestEngineBlockingChitRequest(t *testing.T) {
 		bytes:  []byte{1},
 	}
 
-	te.insert(parentBlk)
+	te.insert(context.Background(), parentBlk)
 
 	vm.ParseBlockF = func(b []byte) (snowman.Block, error) {
 		switch {
@@ -964,7 +965,7 @@ func TestEngineBlockingChitRequest(t *testing.T) {
 	sender.CantChits = false
 
 	missingBlk.status = choices.Processing
-	te.insert(missingBlk)
+	te.insert(context.Background(), missingBlk)
 
 	if len(te.blocked) != 0 {
 		t.Fatalf("Both inserts should not longer be blocking")
@@ -996,7 +997,7 @@ func TestEngineBlockingChitResponse(t *testing.T) {
 		bytes:  []byte{1},
 	}
 
-	te.insert(blockingBlk)
+	te.insert(context.Background(), blockingBlk)
 
 	queryRequestID := new(uint32)
 	sender.PushQueryF = func(inVdrs ids.ShortSet, requestID uint32, blkID ids.ID, blkBytes []byte) {
@@ -1011,7 +1012,7 @@ func TestEngineBlockingChitResponse(t *testing.T) {
 		}
 	}
 
-	te.insert(issuedBlk)
+	te.insert(context.Background(), issuedBlk)
 
 	vm.GetBlockF = func(blkID ids.ID) (snowman.Block, error) {
 		switch {
@@ -1034,7 +1035,7 @@ func TestEngineBlockingChitResponse(t *testing.T) {
 	sender.CantPushQuery = false
 
 	missingBlk.status = choices.Processing
-	te.insert(missingBlk)
+	te.insert(context.Background(), missingBlk)
 }
 
 func TestEngineRetryFetch(t *testing.T) {
@@ -1076,3 +1077,72 @@ func TestEngineRetryFetch(t *testing.T) {
 		t.Fatalf("Should have requested the block again")
 	}
 }
+
+func TestEngineGossip(t *testing.T) {
+	_, _, sender, vm, te, gBlk := setup(t)
+
+	vm.LastAcceptedF = func() ids.ID { return gBlk.ID() }
+
+	called := new(bool)
+	sender.GossipFrontierF = func(blkIDs ids.Set) {
+		*called = true
+		if blkIDs.Len() != 1 || !blkIDs.Contains(gBlk.ID()) {
+			t.Fatalf("Should have gossiped the last accepted block")
+		}
+	}
+
+	te.Gossip()
+
+	if !*called {
+		t.Fatalf("Should have gossiped the accepted frontier")
+	}
+}
+
+func TestEngineGossipFrontier(t *testing.T) {
+	_, _, sender, vm, te, gBlk := setup(t)
+
+	// Frontiers may be gossiped by peers that aren't validators
+	peerID := ids.NewShortID([20]byte{0xff})
+
+	missingBlk := &Blk{
+		parent: gBlk,
+		id:     GenerateID(),
+		height: 1,
+		status: choices.Unknown,
+		bytes:  []byte{1},
+	}
+
+	vm.GetBlockF = func(blkID ids.ID) (snowman.Block, error) {
+		switch {
+		case blkID.Equals(gBlk.ID()):
+			return gBlk, nil
+		case blkID.Equals(missingBlk.ID()):
+			return nil, errUnknownBlock
+		}
+		t.Fatalf("Unknown block")
+		panic("Should have failed")
+	}
+
+	requested := ids.Set{}
+	sender.GetF = func(inVdr ids.ShortID, _ uint32, blkID ids.ID) {
+		if !inVdr.Equals(peerID) {
+			t.Fatalf("Should have requested the block from the peer that gossiped it")
+		}
+		requested.Add(blkID)
+	}
+
+	frontier := ids.Set{}
+	frontier.Add(gBlk.ID(), missingBlk.ID())
+	te.GossipFrontier(peerID, frontier)
+
+	if requested.Len() != 1 || !requested.Contains(missingBlk.ID()) {
+		t.Fatalf("Should have only requested the missing block but requested %s", requested)
+	}
+
+	// The block is already being fetched
+	te.GossipFrontier(peerID, frontier)
+
+	if requested.Len() != 1 {
+		t.Fatalf("Shouldn't have requested the missing block again")
+	}
+}
@@ -4,6 +4,8 @@
 package snowman
 
 import (
+	"context"
+
 	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/snow/consensus/snowman"
 	"github.com/ava-labs/gecko/snow/engine/common"
@@ -23,6 +25,11 @@ import (
 // Applying the operation will save to the database the new value.
 // The VM can attempt to issue a new number, of larger value, at any time.
 // Consensus will ensure the network agrees on the number at every block height.
+//
+// The context passed to BuildBlock, ParseBlock, and GetBlock is cancelled when
+// the chain starts shutting down. While the engine handles a message, it's also
+// cancelled once the engine's per-request deadline passes. Long running work
+// should be abandoned, and an error returned, once the context is done.
 type ChainVM interface {
 	common.VM
 
@@ -30,18 +37,18 @@ type ChainVM interface {
 	//
 	// If the VM doesn't want to issue a new block, an error should be
 	// returned.
-	BuildBlock() (snowman.Block, error)
+	BuildBlock(context.Context) (snowman.Block, error)
 
 	// Attempt to create a block from a stream of bytes.
 	//
 	// The block should be represented by the full byte array, without extra
 	// bytes.
-	ParseBlock([]byte) (snowman.Block, error)
+	ParseBlock(context.Context, []byte) (snowman.Block, error)
 
 	// Attempt to load a block.
 	//
 	// If the block does not exist, then an error should be returned.
-	GetBlock(ids.ID) (snowman.Block, error)
+	GetBlock(context.Context, ids.ID) (snowman.Block, error)
 
 	// Notify the VM of the currently preferred block.
 	//
@@ -55,3 +62,33 @@ type ChainVM interface {
 	// returned.
 	LastAccepted() ids.ID
 }
+
+// StateSyncableVM defines the functionality a Snowman VM must implement for a
+// node to sync its state from a summary, rather than replaying every block
+// from genesis.
+//
+// A state summary describes the state as of some accepted block, the summary's
+// block. Nodes only sync to a summary that a quorum of beacons return
+// identically, so correct nodes must return identical summaries for the same
+// block. Because beacons keep accepting blocks, a VM should summarize the state
+// as of a deterministic recent checkpoint, such as the last accepted block
+// whose height is a multiple of some interval, rather than as of its last
+// accepted block.
+type StateSyncableVM interface {
+	ChainVM
+
+	// StateSummary returns the summary of the state as of the VM's most recent
+	// checkpoint.
+	//
+	// If the VM can't provide a summary, an error should be returned.
+	StateSummary(context.Context) ([]byte, error)
+
+	// SyncState replaces the VM's state with the state described by
+	// [summary]. Once it returns, the summary's block is the last accepted
+	// block, so only the blocks after it are replayed while bootstrapping.
+	//
+	// If the VM's state is already at least as recent as [summary], the state
+	// should be left unchanged. If an error is returned, the state must be
+	// left unchanged.
+	SyncState(ctx context.Context, summary []byte) error
+}
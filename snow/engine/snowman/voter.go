@@ -4,6 +4,8 @@
 package snowman
 
 import (
+	"context"
+
 	"github.com/ava-labs/gecko/ids"
 )
 
@@ -17,14 +19,14 @@ type voter struct {
 
 func (v *voter) Dependencies() ids.Set { return v.deps }
 
-func (v *voter) Fulfill(id ids.ID) {
+func (v *voter) Fulfill(ctx context.Context, id ids.ID) {
 	v.deps.Remove(id)
-	v.Update()
+	v.Update(ctx)
 }
 
-func (v *voter) Abandon(id ids.ID) { v.Fulfill(id) }
+func (v *voter) Abandon(ctx context.Context, id ids.ID) { v.Fulfill(ctx, id) }
 
-func (v *voter) Update() {
+func (v *voter) Update(ctx context.Context) {
 	if v.deps.Len() != 0 {
 		return
 	}
@@ -42,7 +44,9 @@ func (v *voter) Update() {
 	}
 
 	v.t.Config.Context.Log.Verbo("Finishing poll [%d] with:\n%s", v.requestID, &results)
-	v.t.Consensus.RecordPoll(results)
+	v.t.Consensus.RecordPoll(ctx, results)
+	v.t.applyParameters()
+	v.t.sendQueuedQueries()
 
 	v.t.Config.VM.SetPreference(v.t.Consensus.Preference())
 
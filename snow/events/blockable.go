@@ -4,17 +4,21 @@
 package events
 
 import (
+	"context"
+
 	"github.com/ava-labs/gecko/ids"
 )
 
-// Blockable defines what an object must implement to be able to block on events
+// Blockable defines what an object must implement to be able to block on events.
+// Each call is given the context of the request whose handling caused it, so
+// that work done on the request's behalf can be abandoned with it.
 type Blockable interface {
 	// IDs that this object is blocking on
 	Dependencies() ids.Set
 	// Notify this object that an event has been fulfilled
-	Fulfill(ids.ID)
+	Fulfill(context.Context, ids.ID)
 	// Notify this object that an event has been abandoned
-	Abandon(ids.ID)
+	Abandon(context.Context, ids.ID)
 	// Update the state of this object without changing the status of any events
-	Update()
+	Update(context.Context)
 }
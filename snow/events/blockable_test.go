@@ -4,6 +4,8 @@
 package events
 
 import (
+	"context"
+
 	"github.com/ava-labs/gecko/ids"
 )
 
@@ -32,7 +34,7 @@ func (b *blockable) Default() {
 	}
 }
 
-func (b *blockable) Dependencies() ids.Set { return b.dependencies() }
-func (b *blockable) Fulfill(id ids.ID)     { b.fulfill(id) }
-func (b *blockable) Abandon(id ids.ID)     { b.abandon(id) }
-func (b *blockable) Update()               { b.update() }
+func (b *blockable) Dependencies() ids.Set                { return b.dependencies() }
+func (b *blockable) Fulfill(_ context.Context, id ids.ID) { b.fulfill(id) }
+func (b *blockable) Abandon(_ context.Context, id ids.ID) { b.abandon(id) }
+func (b *blockable) Update(context.Context)               { b.update() }
@@ -4,6 +4,7 @@
 package events
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
@@ -20,8 +21,8 @@ func (b *Blocker) init() {
 }
 
 // Fulfill notifies all objects blocking on the event whose ID is <id> that
-// the event has happened
-func (b *Blocker) Fulfill(id ids.ID) {
+// the event has happened. [ctx] is the context of the request being handled.
+func (b *Blocker) Fulfill(ctx context.Context, id ids.ID) {
 	b.init()
 
 	key := id.Key()
@@ -29,13 +30,14 @@ func (b *Blocker) Fulfill(id ids.ID) {
 	delete(*b, key)
 
 	for _, pending := range blocking {
-		pending.Fulfill(id)
+		pending.Fulfill(ctx, id)
 	}
 }
 
 // Abandon notifies all objects blocking on the event whose ID is <id> that
-// the event has been abandoned
-func (b *Blocker) Abandon(id ids.ID) {
+// the event has been abandoned. [ctx] is the context of the request being
+// handled.
+func (b *Blocker) Abandon(ctx context.Context, id ids.ID) {
 	b.init()
 
 	key := id.Key()
@@ -43,12 +45,13 @@ func (b *Blocker) Abandon(id ids.ID) {
 	delete(*b, key)
 
 	for _, pending := range blocking {
-		pending.Abandon(id)
+		pending.Abandon(ctx, id)
 	}
 }
 
-// Register a new Blockable and its dependencies
-func (b *Blocker) Register(pending Blockable) {
+// Register a new Blockable and its dependencies. [ctx] is the context of the
+// request being handled.
+func (b *Blocker) Register(ctx context.Context, pending Blockable) {
 	b.init()
 
 	for _, pendingID := range pending.Dependencies().List() {
@@ -56,7 +59,7 @@ func (b *Blocker) Register(pending Blockable) {
 		(*b)[key] = append((*b)[key], pending)
 	}
 
-	pending.Update()
+	pending.Update(ctx)
 }
 
 // PrefixedString returns the same value as the String function, with all the
@@ -4,6 +4,7 @@
 package events
 
 import (
+	"context"
 	"testing"
 
 	"github.com/ava-labs/gecko/ids"
@@ -40,36 +41,36 @@ func TestBlocker(t *testing.T) {
 		*calledUpdate = true
 	}
 
-	b.Register(a)
+	b.Register(context.Background(), a)
 
 	switch {
 	case !*calledDep, *calledFill, *calledAbandon, !*calledUpdate:
 		t.Fatalf("Called wrong function")
 	}
 
-	b.Fulfill(id2)
-	b.Abandon(id2)
+	b.Fulfill(context.Background(), id2)
+	b.Abandon(context.Background(), id2)
 
 	switch {
 	case !*calledDep, *calledFill, *calledAbandon, !*calledUpdate:
 		t.Fatalf("Called wrong function")
 	}
 
-	b.Fulfill(id0)
+	b.Fulfill(context.Background(), id0)
 
 	switch {
 	case !*calledDep, !*calledFill, *calledAbandon, !*calledUpdate:
 		t.Fatalf("Called wrong function")
 	}
 
-	b.Abandon(id0)
+	b.Abandon(context.Background(), id0)
 
 	switch {
 	case !*calledDep, !*calledFill, *calledAbandon, !*calledUpdate:
 		t.Fatalf("Called wrong function")
 	}
 
-	b.Abandon(id1)
+	b.Abandon(context.Background(), id1)
 
 	switch {
 	case !*calledDep, !*calledFill, !*calledAbandon, !*calledUpdate:
@@ -0,0 +1,86 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package networking
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ava-labs/gecko/ids"
+)
+
+// ClockSkew tracks how far each peer's clock is from this node's clock, as
+// reported during the version handshake. Any one peer's clock may be wrong, so
+// this node's clock is only considered skewed if the median peer disagrees
+// with it.
+type ClockSkew struct {
+	lock  sync.Mutex
+	skews map[[20]byte]time.Duration
+}
+
+// Add records that [peerID]'s clock is [skew] ahead of this node's clock. A
+// negative skew means the peer's clock is behind this node's clock.
+func (cs *ClockSkew) Add(peerID ids.ShortID, skew time.Duration) {
+	cs.lock.Lock()
+	defer cs.lock.Unlock()
+
+	if cs.skews == nil {
+		cs.skews = make(map[[20]byte]time.Duration)
+	}
+	cs.skews[peerID.Key()] = skew
+}
+
+// Remove the skew recorded for [peerID]
+func (cs *ClockSkew) Remove(peerID ids.ShortID) {
+	cs.lock.Lock()
+	defer cs.lock.Unlock()
+
+	delete(cs.skews, peerID.Key())
+}
+
+// Local returns how far this node's clock is estimated to be ahead of its
+// peers' clocks, and the number of peers the estimate is based on. The
+// estimate is the negation of the median skew of the peers' clocks.
+func (cs *ClockSkew) Local() (time.Duration, int) {
+	cs.lock.Lock()
+	defer cs.lock.Unlock()
+
+	if len(cs.skews) == 0 {
+		return 0, 0
+	}
+
+	skews := make([]time.Duration, 0, len(cs.skews))
+	for _, skew := range cs.skews {
+		skews = append(skews, skew)
+	}
+	sort.Slice(skews, func(i, j int) bool { return skews[i] < skews[j] })
+
+	median := skews[len(skews)/2]
+	if len(skews)%2 == 0 {
+		median = (skews[len(skews)/2-1] + median) / 2
+	}
+	return -median, len(skews)
+}
+
+// ClockSkewStatus is the result of a clock skew health check
+type ClockSkewStatus struct {
+	Skew     string `json:"skew"`
+	NumPeers int    `json:"numPeers"`
+}
+
+// Check returns an error if this node's clock is estimated to be more than
+// [threshold] away from its peers' clocks
+func (cs *ClockSkew) Check(threshold time.Duration) (interface{}, error) {
+	skew, numPeers := cs.Local()
+	status := ClockSkewStatus{
+		Skew:     skew.String(),
+		NumPeers: numPeers,
+	}
+	if skew > threshold || -skew > threshold {
+		return status, fmt.Errorf("local clock is skewed by %s, which exceeds the maximum of %s", skew, threshold)
+	}
+	return status, nil
+}
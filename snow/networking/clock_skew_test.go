@@ -0,0 +1,74 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package networking
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ava-labs/gecko/ids"
+)
+
+func TestClockSkewEmpty(t *testing.T) {
+	cs := ClockSkew{}
+
+	if skew, numPeers := cs.Local(); skew != 0 || numPeers != 0 {
+		t.Fatalf("Expected no skew from no peers but got %s from %d peers", skew, numPeers)
+	}
+	if _, err := cs.Check(time.Second); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestClockSkewMedian(t *testing.T) {
+	cs := ClockSkew{}
+	cs.Add(ids.NewShortID([20]byte{1}), time.Hour)
+	cs.Add(ids.NewShortID([20]byte{2}), -3*time.Second)
+	cs.Add(ids.NewShortID([20]byte{3}), -5*time.Second)
+
+	if skew, numPeers := cs.Local(); skew != 3*time.Second || numPeers != 3 {
+		t.Fatalf("Expected skew of 3s from 3 peers but got %s from %d peers", skew, numPeers)
+	}
+
+	cs.Add(ids.NewShortID([20]byte{4}), -7*time.Second)
+
+	if skew, numPeers := cs.Local(); skew != 4*time.Second || numPeers != 4 {
+		t.Fatalf("Expected skew of 4s from 4 peers but got %s from %d peers", skew, numPeers)
+	}
+}
+
+func TestClockSkewReplaceAndRemove(t *testing.T) {
+	cs := ClockSkew{}
+	peer := ids.NewShortID([20]byte{1})
+	cs.Add(peer, time.Minute)
+	cs.Add(peer, time.Second)
+
+	if skew, numPeers := cs.Local(); skew != -time.Second || numPeers != 1 {
+		t.Fatalf("Expected skew of -1s from 1 peer but got %s from %d peers", skew, numPeers)
+	}
+
+	cs.Remove(peer)
+
+	if skew, numPeers := cs.Local(); skew != 0 || numPeers != 0 {
+		t.Fatalf("Expected no skew from no peers but got %s from %d peers", skew, numPeers)
+	}
+}
+
+func TestClockSkewCheck(t *testing.T) {
+	cs := ClockSkew{}
+	cs.Add(ids.NewShortID([20]byte{1}), 10*time.Second)
+
+	if _, err := cs.Check(10 * time.Second); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cs.Check(9 * time.Second); err == nil {
+		t.Fatalf("Should have reported the clock as skewed")
+	}
+
+	cs.Add(ids.NewShortID([20]byte{1}), -10*time.Second)
+
+	if _, err := cs.Check(9 * time.Second); err == nil {
+		t.Fatalf("Should have reported the clock as skewed")
+	}
+}
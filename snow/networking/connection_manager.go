@@ -0,0 +1,238 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package networking
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils"
+	"github.com/ava-labs/gecko/utils/timer"
+)
+
+const (
+	// maxOutboundCandidates is the most peers tracked as candidates to dial.
+	// Peers learned of once this many are tracked are ignored.
+	maxOutboundCandidates = 1 << 12
+
+	// maxDialAttempts is the number of failed dials after which a peer that
+	// isn't a validator is forgotten
+	maxDialAttempts = 8
+)
+
+// ConnectionManager maintains this node's outbound connections. It dials the
+// peers this node learns of until [target] of them are connected, and redials
+// the validators of the current validator set that it was connected to, even
+// once [target] is reached. Validators are dialed before other peers.
+//
+// A peer that isn't connected to by the time it may be dialed again is
+// considered unreachable. Each failed dial doubles the time until the peer is
+// dialed again, up to a maximum, and a random jitter is applied so that
+// unreachable peers aren't all dialed at once.
+type ConnectionManager struct {
+	lock  sync.Mutex
+	clock timer.Clock
+
+	target         int
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+
+	dial        func(utils.IPDesc)
+	isValidator func(ids.ShortID) bool
+	// Returns the time to wait, given the backoff of a peer
+	jitter func(time.Duration) time.Duration
+
+	// Key: The string representation of the peer's IP
+	peers map[string]*outboundPeer
+}
+
+type outboundPeer struct {
+	ip utils.IPDesc
+	// The ID of the peer, once it was connected to
+	id        ids.ShortID
+	connected bool
+	// True if the connection was made by this node dialing the peer
+	outbound bool
+	// Number of dials since the peer was last connected to
+	attempts int
+	// The peer isn't dialed again before nextAttempt
+	nextAttempt time.Time
+}
+
+// Initialize the manager. [dial] is called with the IP of each peer to
+// connect to. [isValidator] returns true if the peer is in the current
+// validator set. Failed dials are retried after [initialBackoff], doubled
+// after each failure up to [maxBackoff].
+func (cm *ConnectionManager) Initialize(target int, initialBackoff, maxBackoff time.Duration, dial func(utils.IPDesc), isValidator func(ids.ShortID) bool) {
+	cm.lock.Lock()
+	defer cm.lock.Unlock()
+
+	cm.target = target
+	cm.initialBackoff = initialBackoff
+	cm.maxBackoff = maxBackoff
+	cm.dial = dial
+	cm.isValidator = isValidator
+	if cm.jitter == nil {
+		cm.jitter = equalJitter
+	}
+	cm.peers = make(map[string]*outboundPeer)
+}
+
+// Add the peer at [ip] to the peers to dial. It's dialed by the next Update if
+// this node needs more outbound connections.
+func (cm *ConnectionManager) Add(ip utils.IPDesc) {
+	cm.lock.Lock()
+	defer cm.lock.Unlock()
+
+	cm.add(ip)
+}
+
+// Dial the peer at [ip] now, even if this node has enough outbound
+// connections, and keep redialing it with backoff while it isn't connected.
+func (cm *ConnectionManager) Dial(ip utils.IPDesc) {
+	cm.lock.Lock()
+	peer := cm.add(ip)
+	if peer == nil || peer.connected {
+		cm.lock.Unlock()
+		return
+	}
+	cm.attempt(peer, cm.clock.Time())
+	cm.lock.Unlock()
+
+	cm.dial(ip)
+}
+
+// Connected marks the peer at [ip], whose ID is [id], as connected. Validators
+// that connected to this node are tracked so that they're redialed if they
+// disconnect.
+func (cm *ConnectionManager) Connected(ip utils.IPDesc, id ids.ShortID) {
+	cm.lock.Lock()
+	defer cm.lock.Unlock()
+
+	peer, exists := cm.peers[ip.String()]
+	if !exists {
+		if !cm.isValidator(id) {
+			return
+		}
+		if peer = cm.add(ip); peer == nil {
+			return
+		}
+	}
+	peer.id = id
+	peer.connected = true
+	peer.outbound = peer.attempts > 0
+	peer.attempts = 0
+}
+
+// Disconnected marks the peer at [ip] as disconnected. It's redialed once the
+// initial backoff passes.
+func (cm *ConnectionManager) Disconnected(ip utils.IPDesc) {
+	cm.lock.Lock()
+	defer cm.lock.Unlock()
+
+	peer, exists := cm.peers[ip.String()]
+	if !exists || !peer.connected {
+		return
+	}
+	peer.connected = false
+	peer.outbound = false
+	peer.nextAttempt = cm.clock.Time().Add(cm.jitter(cm.initialBackoff))
+}
+
+// Outbound returns the number of connected peers that this node dialed
+func (cm *ConnectionManager) Outbound() int {
+	cm.lock.Lock()
+	defer cm.lock.Unlock()
+
+	outbound := 0
+	for _, peer := range cm.peers {
+		if peer.outbound {
+			outbound++
+		}
+	}
+	return outbound
+}
+
+// Update dials the validators that aren't connected, and enough other peers to
+// reach the target number of outbound connections. Peers whose backoff hasn't
+// passed aren't dialed. Dials that are still in progress count towards the
+// target.
+func (cm *ConnectionManager) Update() {
+	cm.lock.Lock()
+
+	now := cm.clock.Time()
+	slots := cm.target
+	toDial := []utils.IPDesc(nil)
+	others := []*outboundPeer(nil)
+	for key, peer := range cm.peers {
+		switch {
+		case peer.outbound:
+			slots--
+		case peer.connected:
+		case now.Before(peer.nextAttempt):
+			// The peer was dialed, and may still connect
+			if peer.attempts > 0 {
+				slots--
+			}
+		case !peer.id.IsZero() && cm.isValidator(peer.id):
+			slots--
+			cm.attempt(peer, now)
+			toDial = append(toDial, peer.ip)
+		case peer.attempts >= maxDialAttempts:
+			delete(cm.peers, key)
+		default:
+			others = append(others, peer)
+		}
+	}
+
+	// Dial a random subset of the other peers, so that this node's outbound
+	// connections don't depend on the order peers were learned in
+	rand.Shuffle(len(others), func(i, j int) { others[i], others[j] = others[j], others[i] })
+	for i := 0; i < slots && i < len(others); i++ {
+		cm.attempt(others[i], now)
+		toDial = append(toDial, others[i].ip)
+	}
+	cm.lock.Unlock()
+
+	for _, ip := range toDial {
+		cm.dial(ip)
+	}
+}
+
+// add [ip] to the tracked peers, if it isn't already tracked, and returns it.
+// Returns nil if too many peers are tracked. Assumes the lock is held.
+func (cm *ConnectionManager) add(ip utils.IPDesc) *outboundPeer {
+	key := ip.String()
+	if peer, exists := cm.peers[key]; exists {
+		return peer
+	}
+	if len(cm.peers) >= maxOutboundCandidates {
+		return nil
+	}
+	peer := &outboundPeer{ip: ip}
+	cm.peers[key] = peer
+	return peer
+}
+
+// attempt records that [peer] is dialed at [now], and backs off its next dial.
+// Assumes the lock is held.
+func (cm *ConnectionManager) attempt(peer *outboundPeer, now time.Time) {
+	backoff := cm.initialBackoff
+	for i := 0; i < peer.attempts && backoff < cm.maxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > cm.maxBackoff {
+		backoff = cm.maxBackoff
+	}
+	peer.attempts++
+	peer.nextAttempt = now.Add(cm.jitter(backoff))
+}
+
+// equalJitter returns a random duration in [backoff/2, backoff]
+func equalJitter(backoff time.Duration) time.Duration {
+	half := backoff / 2
+	return half + time.Duration(rand.Int63n(int64(backoff-half)+1))
+}
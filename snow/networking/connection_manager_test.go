@@ -0,0 +1,186 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package networking
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils"
+)
+
+func testIP(i byte) utils.IPDesc {
+	return utils.IPDesc{
+		IP:   net.IPv4(10, 0, 0, i),
+		Port: 9651,
+	}
+}
+
+// newTestConnectionManager returns a manager without jitter, and the IPs it
+// dialed, in order
+func newTestConnectionManager(target int, validators ...ids.ShortID) (*ConnectionManager, *[]utils.IPDesc) {
+	vdrs := ids.ShortSet{}
+	vdrs.Add(validators...)
+
+	dialed := []utils.IPDesc{}
+	cm := &ConnectionManager{}
+	cm.jitter = func(backoff time.Duration) time.Duration { return backoff }
+	cm.Initialize(target, time.Second, 8*time.Second, func(ip utils.IPDesc) { dialed = append(dialed, ip) }, vdrs.Contains)
+	cm.clock.Set(time.Unix(1000000, 0))
+	return cm, &dialed
+}
+
+func TestConnectionManagerTarget(t *testing.T) {
+	cm, dialed := newTestConnectionManager(2)
+	for i := byte(0); i < 4; i++ {
+		cm.Add(testIP(i))
+	}
+
+	cm.Update()
+	if len(*dialed) != 2 {
+		t.Fatalf("Should have dialed 2 peers but dialed %d", len(*dialed))
+	}
+
+	// The dials are still in progress
+	cm.Update()
+	if len(*dialed) != 2 {
+		t.Fatalf("Shouldn't have dialed more peers while dials are in progress")
+	}
+
+	cm.Connected((*dialed)[0], ids.NewShortID([20]byte{1}))
+	cm.Connected((*dialed)[1], ids.NewShortID([20]byte{2}))
+	if outbound := cm.Outbound(); outbound != 2 {
+		t.Fatalf("Should have 2 outbound connections but has %d", outbound)
+	}
+
+	cm.clock.Set(cm.clock.Time().Add(time.Hour))
+	cm.Update()
+	if len(*dialed) != 2 {
+		t.Fatalf("Shouldn't have dialed more peers once the target was reached")
+	}
+
+	cm.Disconnected((*dialed)[0])
+	cm.clock.Set(cm.clock.Time().Add(time.Second))
+	cm.Update()
+	if len(*dialed) != 3 {
+		t.Fatalf("Should have dialed a peer to replace the disconnected one")
+	}
+}
+
+func TestConnectionManagerBackoff(t *testing.T) {
+	cm, dialed := newTestConnectionManager(1)
+	ip := testIP(1)
+	cm.Add(ip)
+
+	start := cm.clock.Time()
+	expected := []time.Duration{0, time.Second, 3 * time.Second, 7 * time.Second, 15 * time.Second, 23 * time.Second}
+	for i, at := range expected {
+		if i > 0 {
+			cm.clock.Set(start.Add(at - time.Millisecond))
+			cm.Update()
+			if len(*dialed) != i {
+				t.Fatalf("Shouldn't have redialed before %s", at)
+			}
+		}
+
+		cm.clock.Set(start.Add(at))
+		cm.Update()
+		if len(*dialed) != i+1 {
+			t.Fatalf("Should have dialed %d times by %s but dialed %d times", i+1, at, len(*dialed))
+		}
+	}
+}
+
+func TestConnectionManagerForgetsUnreachable(t *testing.T) {
+	cm, dialed := newTestConnectionManager(1)
+	cm.Add(testIP(1))
+
+	for i := 0; i < 2*maxDialAttempts; i++ {
+		cm.Update()
+		cm.clock.Set(cm.clock.Time().Add(time.Hour))
+	}
+	if len(*dialed) != maxDialAttempts {
+		t.Fatalf("Should have dialed %d times but dialed %d times", maxDialAttempts, len(*dialed))
+	}
+}
+
+func TestConnectionManagerRedialsValidators(t *testing.T) {
+	vdr := ids.NewShortID([20]byte{1})
+	cm, dialed := newTestConnectionManager(1, vdr)
+
+	// The validator connected to this node
+	vdrIP := testIP(1)
+	cm.Connected(vdrIP, vdr)
+	if outbound := cm.Outbound(); outbound != 0 {
+		t.Fatalf("Shouldn't have counted an inbound connection as outbound")
+	}
+
+	// Peers that aren't validators are only tracked once dialed
+	cm.Connected(testIP(2), ids.NewShortID([20]byte{2}))
+
+	cm.Add(testIP(3))
+	cm.Update()
+	cm.Connected(testIP(3), ids.NewShortID([20]byte{3}))
+	if len(*dialed) != 1 {
+		t.Fatalf("Should have dialed 1 peer but dialed %d", len(*dialed))
+	}
+
+	// The validator is redialed even though the target was reached
+	cm.Disconnected(vdrIP)
+	cm.Disconnected(testIP(2))
+	cm.clock.Set(cm.clock.Time().Add(time.Second))
+	cm.Update()
+	if len(*dialed) != 2 || (*dialed)[1].String() != vdrIP.String() {
+		t.Fatalf("Should have redialed the validator but dialed %v", *dialed)
+	}
+
+	// The validator is never forgotten
+	for i := 0; i < 2*maxDialAttempts; i++ {
+		cm.clock.Set(cm.clock.Time().Add(time.Hour))
+		cm.Update()
+	}
+	if len(*dialed) != 2+2*maxDialAttempts {
+		t.Fatalf("Should have kept redialing the validator")
+	}
+}
+
+func TestConnectionManagerPrefersValidators(t *testing.T) {
+	vdr := ids.NewShortID([20]byte{1})
+	cm, dialed := newTestConnectionManager(1, vdr)
+
+	vdrIP := testIP(1)
+	cm.Connected(vdrIP, vdr)
+	cm.Disconnected(vdrIP)
+	for i := byte(2); i < 10; i++ {
+		cm.Add(testIP(i))
+	}
+
+	cm.clock.Set(cm.clock.Time().Add(time.Second))
+	cm.Update()
+	if len(*dialed) != 1 || (*dialed)[0].String() != vdrIP.String() {
+		t.Fatalf("Should have only dialed the validator but dialed %v", *dialed)
+	}
+}
+
+func TestConnectionManagerDial(t *testing.T) {
+	cm, dialed := newTestConnectionManager(0)
+	ip := testIP(1)
+
+	cm.Dial(ip)
+	if len(*dialed) != 1 {
+		t.Fatalf("Should have dialed the peer even though the target is 0")
+	}
+
+	cm.Connected(ip, ids.NewShortID([20]byte{1}))
+	if outbound := cm.Outbound(); outbound != 1 {
+		t.Fatalf("Should have 1 outbound connection but has %d", outbound)
+	}
+
+	cm.Dial(ip)
+	if len(*dialed) != 1 {
+		t.Fatalf("Shouldn't have dialed a connected peer")
+	}
+}
@@ -4,7 +4,11 @@
 package handler
 
 import (
+	"context"
 	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
 
 	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/snow"
@@ -14,19 +18,73 @@ import (
 // Handler passes incoming messages from the network to the consensus engine
 // (Actually, it receives the incoming messages from a ChainRouter, but same difference)
 type Handler struct {
+	metrics
+
 	msgs    chan message
 	wg      sync.WaitGroup
 	engine  common.Engine
 	msgChan <-chan common.Message
+
+	// gets, if non-nil, holds the Get messages that are handled concurrently
+	// by the get workers, rather than by Dispatch. Closed once the engine is
+	// shut down.
+	gets   chan message
+	closed chan struct{}
+
+	// cancel, if non-nil, cancels the context of the engine's calls into the
+	// VM
+	cancel context.CancelFunc
+
+	// The time between the engine's gossips. If 0, the engine isn't told to
+	// gossip.
+	gossipFrequency time.Duration
+
+	// The Get and PullQuery requests that haven't been handled yet. Duplicates
+	// of them are dropped.
+	inflight inflightRequests
 }
 
-// Initialize this consensus handler
-func (h *Handler) Initialize(engine common.Engine, msgChan <-chan common.Message, bufferSize int) {
+// Initialize this consensus handler. [cancel] is called as soon as the handler
+// is told to shut down, before the engine has finished processing its pending
+// messages, so that long running VM work isn't waited on.
+//
+// If the engine's context declares the snow.SharedGets lock strategy, up to
+// [numGetWorkers] Get messages are handled concurrently with each other.
+// Otherwise, or if [numGetWorkers] is 0, every message is handled in order by
+// Dispatch.
+//
+// If [gossipFrequency] is non-zero, the engine is told to gossip its accepted
+// frontier every [gossipFrequency].
+//
+// The handler's metrics are labelled with [chain] and registered with
+// [registerer].
+func (h *Handler) Initialize(
+	engine common.Engine,
+	msgChan <-chan common.Message,
+	bufferSize int,
+	numGetWorkers int,
+	gossipFrequency time.Duration,
+	cancel context.CancelFunc,
+	chain string,
+	registerer prometheus.Registerer,
+) {
+	h.metrics.Initialize(engine.Context().Log, chain, registerer)
 	h.msgs = make(chan message, bufferSize)
 	h.engine = engine
 	h.msgChan = msgChan
+	h.cancel = cancel
+	h.gossipFrequency = gossipFrequency
+	h.closed = make(chan struct{})
 
 	h.wg.Add(1)
+
+	if numGetWorkers > 0 && engine.Context().LockStrategy == snow.SharedGets {
+		h.gets = make(chan message, bufferSize)
+		h.wg.Add(numGetWorkers)
+		for i := 0; i < numGetWorkers; i++ {
+			go engine.Context().Log.RecoverAndPanic(h.getWorker)
+		}
+	}
 }
 
 // Context of this Handler
@@ -37,6 +95,15 @@ func (h *Handler) Context() *snow.Context { return h.engine.Context() }
 func (h *Handler) Dispatch() {
 	defer h.wg.Done()
 
+	// A nil channel is never ready, so the engine isn't told to gossip if
+	// gossip is disabled
+	var gossip <-chan time.Time
+	if h.gossipFrequency > 0 {
+		ticker := time.NewTicker(h.gossipFrequency)
+		defer ticker.Stop()
+		gossip = ticker.C
+	}
+
 	for {
 		select {
 		case msg := <-h.msgs:
@@ -47,10 +114,49 @@ func (h *Handler) Dispatch() {
 			if !h.dispatchMsg(message{messageType: notifyMsg, notification: msg}) {
 				return
 			}
+		case <-gossip:
+			if !h.dispatchMsg(message{messageType: gossipMsg}) {
+				return
+			}
 		}
 	}
 }
 
+// getWorker handles Get messages until the engine is shut down
+func (h *Handler) getWorker() {
+	defer h.wg.Done()
+
+	for {
+		select {
+		case msg := <-h.gets:
+			h.dispatchGet(msg)
+		case <-h.closed:
+			return
+		}
+	}
+}
+
+// dispatchGet passes a Get message to the consensus engine while holding the
+// context's lock for reading
+func (h *Handler) dispatchGet(msg message) {
+	defer h.inflight.Remove(msg)
+
+	ctx := h.engine.Context()
+
+	ctx.Lock.RLock()
+	defer ctx.Lock.RUnlock()
+
+	// The engine may have been shut down while waiting for the lock
+	select {
+	case <-h.closed:
+		return
+	default:
+	}
+
+	ctx.Log.Verbo("Forwarding message to consensus: %s", msg)
+	h.engine.Get(msg.validatorID, msg.requestID, msg.containerID)
+}
+
 // Dispatch a message to the consensus engine.
 // Returns false iff this consensus handler (and its associated engine) should shutdown
 // (due to receipt of a shutdown message)
@@ -75,8 +181,17 @@ func (h *Handler) dispatchMsg(msg message) bool {
 		h.engine.Accepted(msg.validatorID, msg.requestID, msg.containerIDs)
 	case getAcceptedFailedMsg:
 		h.engine.GetAcceptedFailed(msg.validatorID, msg.requestID)
+	case getStateSummaryMsg:
+		h.engine.GetStateSummary(msg.validatorID, msg.requestID)
+	case stateSummaryMsg:
+		h.engine.StateSummary(msg.validatorID, msg.requestID, msg.container)
+	case getStateSummaryFailedMsg:
+		h.engine.GetStateSummaryFailed(msg.validatorID, msg.requestID)
+	case gossipFrontierMsg:
+		h.engine.GossipFrontier(msg.validatorID, msg.containerIDs)
 	case getMsg:
 		h.engine.Get(msg.validatorID, msg.requestID, msg.containerID)
+		h.inflight.Remove(msg)
 	case getFailedMsg:
 		h.engine.GetFailed(msg.validatorID, msg.requestID, msg.containerID)
 	case putMsg:
@@ -85,13 +200,17 @@ func (h *Handler) dispatchMsg(msg message) bool {
 		h.engine.PushQuery(msg.validatorID, msg.requestID, msg.containerID, msg.container)
 	case pullQueryMsg:
 		h.engine.PullQuery(msg.validatorID, msg.requestID, msg.containerID)
+		h.inflight.Remove(msg)
 	case queryFailedMsg:
 		h.engine.QueryFailed(msg.validatorID, msg.requestID)
 	case chitsMsg:
 		h.engine.Chits(msg.validatorID, msg.requestID, msg.containerIDs)
 	case notifyMsg:
 		h.engine.Notify(msg.notification)
+	case gossipMsg:
+		h.engine.Gossip()
 	case shutdownMsg:
+		close(h.closed)
 		h.engine.Shutdown()
 		return false
 	}
@@ -161,14 +280,67 @@ func (h *Handler) GetAcceptedFailed(validatorID ids.ShortID, requestID uint32) {
 	}
 }
 
+// GetStateSummary passes a GetStateSummary message received from the network
+// to the consensus engine.
+func (h *Handler) GetStateSummary(validatorID ids.ShortID, requestID uint32) {
+	h.msgs <- message{
+		messageType: getStateSummaryMsg,
+		validatorID: validatorID,
+		requestID:   requestID,
+	}
+}
+
+// StateSummary passes a StateSummary message received from the network to the
+// consensus engine.
+func (h *Handler) StateSummary(validatorID ids.ShortID, requestID uint32, summary []byte) {
+	h.msgs <- message{
+		messageType: stateSummaryMsg,
+		validatorID: validatorID,
+		requestID:   requestID,
+		container:   summary,
+	}
+}
+
+// GetStateSummaryFailed passes a GetStateSummaryFailed message to the
+// consensus engine.
+func (h *Handler) GetStateSummaryFailed(validatorID ids.ShortID, requestID uint32) {
+	h.msgs <- message{
+		messageType: getStateSummaryFailedMsg,
+		validatorID: validatorID,
+		requestID:   requestID,
+	}
+}
+
+// GossipFrontier passes a GossipFrontier message received from the network to
+// the consensus engine.
+func (h *Handler) GossipFrontier(validatorID ids.ShortID, containerIDs ids.Set) {
+	h.msgs <- message{
+		messageType:  gossipFrontierMsg,
+		validatorID:  validatorID,
+		containerIDs: containerIDs,
+	}
+}
+
 // Get passes a Get message received from the network to the consensus engine.
+// If a Get with the same request ID from the same validator is still being
+// handled, the message is dropped.
 func (h *Handler) Get(validatorID ids.ShortID, requestID uint32, containerID ids.ID) {
-	h.msgs <- message{
+	msg := message{
 		messageType: getMsg,
 		validatorID: validatorID,
 		requestID:   requestID,
 		containerID: containerID,
 	}
+	if !h.inflight.Add(msg) {
+		h.engine.Context().Log.Debug("Dropping duplicate Get request %d from %s", requestID, validatorID)
+		h.numDuplicateGets.Inc()
+		return
+	}
+	if h.gets != nil {
+		h.gets <- msg
+	} else {
+		h.msgs <- msg
+	}
 }
 
 // Put passes a Put message received from the network to the consensus engine.
@@ -204,13 +376,21 @@ func (h *Handler) PushQuery(validatorID ids.ShortID, requestID uint32, blockID i
 }
 
 // PullQuery passes a PullQuery message received from the network to the consensus engine.
+// If a PullQuery with the same request ID from the same validator is still
+// being handled, the message is dropped.
 func (h *Handler) PullQuery(validatorID ids.ShortID, requestID uint32, blockID ids.ID) {
-	h.msgs <- message{
+	msg := message{
 		messageType: pullQueryMsg,
 		validatorID: validatorID,
 		requestID:   requestID,
 		containerID: blockID,
 	}
+	if !h.inflight.Add(msg) {
+		h.engine.Context().Log.Debug("Dropping duplicate PullQuery request %d from %s", requestID, validatorID)
+		h.numDuplicatePullQueries.Inc()
+		return
+	}
+	h.msgs <- msg
 }
 
 // Chits passes a Chits message received from the network to the consensus engine.
@@ -233,7 +413,13 @@ func (h *Handler) QueryFailed(validatorID ids.ShortID, requestID uint32) {
 }
 
 // Shutdown shuts down the dispatcher
-func (h *Handler) Shutdown() { h.msgs <- message{messageType: shutdownMsg}; h.wg.Wait() }
+func (h *Handler) Shutdown() {
+	if h.cancel != nil {
+		h.cancel()
+	}
+	h.msgs <- message{messageType: shutdownMsg}
+	h.wg.Wait()
+}
 
 // Notify ...
 func (h *Handler) Notify(msg common.Message) {
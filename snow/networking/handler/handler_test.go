@@ -0,0 +1,254 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package handler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	dto "github.com/prometheus/client_model/go"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow"
+	"github.com/ava-labs/gecko/snow/engine/common"
+)
+
+func TestHandlerConcurrentGets(t *testing.T) {
+	ctx := snow.DefaultContextTest()
+	ctx.LockStrategy = snow.SharedGets
+
+	engine := &common.EngineTest{T: t}
+	engine.Default(true)
+	engine.ContextF = func() *snow.Context { return ctx }
+
+	started := make(chan uint32, 2)
+	release := make(chan struct{})
+	engine.GetF = func(_ ids.ShortID, requestID uint32, _ ids.ID) {
+		started <- requestID
+		<-release
+	}
+	engine.ShutdownF = func() {}
+
+	handler := &Handler{}
+	handler.Initialize(engine, nil, 2, 2, 0, nil, "", prometheus.NewRegistry())
+	go handler.Dispatch()
+
+	handler.Get(ids.NewShortID([20]byte{1}), 1, ids.Empty)
+	handler.Get(ids.NewShortID([20]byte{2}), 2, ids.Empty)
+
+	// Both Gets must be handled before either returns
+	for i := 0; i < 2; i++ {
+		select {
+		case <-started:
+		case <-time.After(time.Second):
+			t.Fatalf("Get messages weren't handled concurrently")
+		}
+	}
+	close(release)
+
+	handler.Shutdown()
+}
+
+func TestHandlerExclusiveGets(t *testing.T) {
+	ctx := snow.DefaultContextTest()
+
+	engine := &common.EngineTest{T: t}
+	engine.Default(true)
+	engine.ContextF = func() *snow.Context { return ctx }
+
+	handled := []uint32{}
+	engine.GetF = func(_ ids.ShortID, requestID uint32, _ ids.ID) {
+		handled = append(handled, requestID)
+	}
+	engine.PullQueryF = func(_ ids.ShortID, requestID uint32, _ ids.ID) {
+		handled = append(handled, requestID)
+	}
+	engine.ShutdownF = func() {}
+
+	handler := &Handler{}
+	handler.Initialize(engine, nil, 3, 2, 0, nil, "", prometheus.NewRegistry())
+	if handler.gets != nil {
+		t.Fatalf("Get workers shouldn't be used with an exclusive lock")
+	}
+
+	handler.Get(ids.NewShortID([20]byte{1}), 1, ids.Empty)
+	handler.PullQuery(ids.NewShortID([20]byte{1}), 2, ids.Empty)
+	handler.Get(ids.NewShortID([20]byte{1}), 3, ids.Empty)
+
+	go handler.Dispatch()
+	handler.Shutdown()
+
+	if len(handled) != 3 || handled[0] != 1 || handled[1] != 2 || handled[2] != 3 {
+		t.Fatalf("Messages should have been handled in order but were %v", handled)
+	}
+}
+
+func TestHandlerNoGetsAfterShutdown(t *testing.T) {
+	ctx := snow.DefaultContextTest()
+	ctx.LockStrategy = snow.SharedGets
+
+	engine := &common.EngineTest{T: t}
+	engine.Default(true)
+	engine.ContextF = func() *snow.Context { return ctx }
+
+	shutdown := false
+	engine.GetF = func(ids.ShortID, uint32, ids.ID) {
+		if shutdown {
+			t.Fatalf("Get was called after the engine was shut down")
+		}
+	}
+	engine.ShutdownF = func() { shutdown = true }
+
+	handler := &Handler{}
+	handler.Initialize(engine, nil, 1, 1, 0, nil, "", prometheus.NewRegistry())
+
+	// Hold the lock so the Get can't be handled until the engine is shut down
+	ctx.Lock.Lock()
+	handler.Get(ids.NewShortID([20]byte{1}), 1, ids.Empty)
+	go handler.Dispatch()
+	go handler.Shutdown()
+	time.Sleep(10 * time.Millisecond)
+	ctx.Lock.Unlock()
+
+	handler.wg.Wait()
+}
+
+func counterValue(t *testing.T, counter prometheus.Counter) float64 {
+	metric := dto.Metric{}
+	if err := counter.Write(&metric); err != nil {
+		t.Fatal(err)
+	}
+	return metric.GetCounter().GetValue()
+}
+
+func TestHandlerDropsDuplicateRequests(t *testing.T) {
+	ctx := snow.DefaultContextTest()
+
+	engine := &common.EngineTest{T: t}
+	engine.Default(true)
+	engine.ContextF = func() *snow.Context { return ctx }
+
+	gets, pullQueries := 0, 0
+	engine.GetF = func(ids.ShortID, uint32, ids.ID) { gets++ }
+	engine.PullQueryF = func(ids.ShortID, uint32, ids.ID) { pullQueries++ }
+	engine.ShutdownF = func() {}
+
+	handler := &Handler{}
+	handler.Initialize(engine, nil, 4, 0, 0, nil, "", prometheus.NewRegistry())
+
+	vdr := ids.NewShortID([20]byte{1})
+
+	// The originals haven't been handled, so the re-sent requests are dropped
+	handler.Get(vdr, 1, ids.Empty)
+	handler.Get(vdr, 1, ids.Empty)
+	handler.PullQuery(vdr, 1, ids.Empty)
+	handler.PullQuery(vdr, 1, ids.Empty)
+
+	// Requests with different IDs, or from different validators, aren't
+	// duplicates
+	handler.Get(vdr, 2, ids.Empty)
+	handler.Get(ids.NewShortID([20]byte{2}), 1, ids.Empty)
+
+	if dropped := counterValue(t, handler.numDuplicateGets); dropped != 1 {
+		t.Fatalf("Should have dropped 1 Get but dropped %v", dropped)
+	}
+	if dropped := counterValue(t, handler.numDuplicatePullQueries); dropped != 1 {
+		t.Fatalf("Should have dropped 1 PullQuery but dropped %v", dropped)
+	}
+
+	for i := 0; i < 4; i++ {
+		handler.dispatchMsg(<-handler.msgs)
+	}
+	if gets != 3 || pullQueries != 1 {
+		t.Fatalf("Should have handled 3 Gets and 1 PullQuery but handled %d and %d", gets, pullQueries)
+	}
+	if n := handler.inflight.Len(); n != 0 {
+		t.Fatalf("%d requests are still marked as being handled", n)
+	}
+
+	// Once the original was handled, its request ID can be reused
+	handler.Get(vdr, 1, ids.Empty)
+	handler.dispatchMsg(<-handler.msgs)
+	if gets != 4 {
+		t.Fatalf("Should have handled the Get with the reused request ID")
+	}
+	if dropped := counterValue(t, handler.numDuplicateGets); dropped != 1 {
+		t.Fatalf("Shouldn't have dropped the Get with the reused request ID")
+	}
+}
+
+func TestHandlerDropsDuplicateConcurrentGets(t *testing.T) {
+	ctx := snow.DefaultContextTest()
+	ctx.LockStrategy = snow.SharedGets
+
+	engine := &common.EngineTest{T: t}
+	engine.Default(true)
+	engine.ContextF = func() *snow.Context { return ctx }
+
+	started := make(chan struct{}, 2)
+	release := make(chan struct{})
+	engine.GetF = func(ids.ShortID, uint32, ids.ID) {
+		started <- struct{}{}
+		<-release
+	}
+	engine.ShutdownF = func() {}
+
+	handler := &Handler{}
+	handler.Initialize(engine, nil, 2, 1, 0, nil, "", prometheus.NewRegistry())
+	go handler.Dispatch()
+
+	vdr := ids.NewShortID([20]byte{1})
+
+	// The Get is dropped while the original is being handled by a worker
+	handler.Get(vdr, 1, ids.Empty)
+	<-started
+	handler.Get(vdr, 1, ids.Empty)
+	if dropped := counterValue(t, handler.numDuplicateGets); dropped != 1 {
+		t.Fatalf("Should have dropped the Get that was re-sent while being handled")
+	}
+	release <- struct{}{}
+
+	// Once it has been handled, the request ID can be reused
+	for handler.inflight.Len() != 0 {
+		time.Sleep(time.Millisecond)
+	}
+	handler.Get(vdr, 1, ids.Empty)
+	select {
+	case <-started:
+	case <-time.After(time.Second):
+		t.Fatalf("The Get with the reused request ID wasn't handled")
+	}
+	close(release)
+
+	handler.Shutdown()
+}
+
+func TestHandlerGossips(t *testing.T) {
+	engine := &common.EngineTest{T: t}
+	engine.Default(true)
+	engine.ContextF = snow.DefaultContextTest
+
+	gossiped := make(chan struct{}, 1)
+	engine.GossipF = func() {
+		select {
+		case gossiped <- struct{}{}:
+		default:
+		}
+	}
+	engine.ShutdownF = func() {}
+
+	handler := &Handler{}
+	handler.Initialize(engine, nil, 1, 0, time.Millisecond, nil, "", prometheus.NewRegistry())
+	go handler.Dispatch()
+
+	select {
+	case <-gossiped:
+	case <-time.After(20 * time.Second):
+		t.Fatalf("Should have told the engine to gossip")
+	}
+
+	handler.Shutdown()
+}
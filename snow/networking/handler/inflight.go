@@ -0,0 +1,69 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package handler
+
+import (
+	"sync"
+)
+
+// requestKey identifies a request by its type, the peer that sent it, and the
+// ID the peer gave it
+type requestKey struct {
+	messageType msgType
+	validatorID [20]byte
+	requestID   uint32
+}
+
+// inflightRequests tracks the requests that are being handled, from when they
+// are received until the engine returns from handling them.
+//
+// A peer that re-sends a request, for example because it didn't receive the
+// response in time, shouldn't cause the same work to be done twice, so a
+// request with the same key as one still being handled is a duplicate. Once
+// the original has been handled, the peer may reuse its request ID.
+type inflightRequests struct {
+	lock     sync.Mutex
+	requests map[requestKey]struct{}
+}
+
+// Add [msg] to the requests being handled. Returns false if a request with
+// the same key is already being handled, in which case [msg] is a duplicate.
+func (ir *inflightRequests) Add(msg message) bool {
+	ir.lock.Lock()
+	defer ir.lock.Unlock()
+
+	if ir.requests == nil {
+		ir.requests = make(map[requestKey]struct{})
+	}
+	key := keyOf(msg)
+	if _, exists := ir.requests[key]; exists {
+		return false
+	}
+	ir.requests[key] = struct{}{}
+	return true
+}
+
+// Remove [msg] from the requests being handled
+func (ir *inflightRequests) Remove(msg message) {
+	ir.lock.Lock()
+	defer ir.lock.Unlock()
+
+	delete(ir.requests, keyOf(msg))
+}
+
+// Len returns the number of requests being handled
+func (ir *inflightRequests) Len() int {
+	ir.lock.Lock()
+	defer ir.lock.Unlock()
+
+	return len(ir.requests)
+}
+
+func keyOf(msg message) requestKey {
+	return requestKey{
+		messageType: msg.messageType,
+		validatorID: msg.validatorID.Key(),
+		requestID:   msg.requestID,
+	}
+}
@@ -21,6 +21,10 @@ const (
 	getAcceptedMsg
 	acceptedMsg
 	getAcceptedFailedMsg
+	getStateSummaryMsg
+	stateSummaryMsg
+	getStateSummaryFailedMsg
+	gossipFrontierMsg
 	getMsg
 	putMsg
 	getFailedMsg
@@ -29,6 +33,7 @@ const (
 	chitsMsg
 	queryFailedMsg
 	notifyMsg
+	gossipMsg
 	shutdownMsg
 )
 
@@ -71,6 +76,14 @@ func (t msgType) String() string {
 		return "Accepted Message"
 	case getAcceptedFailedMsg:
 		return "Get Accepted Failed Message"
+	case getStateSummaryMsg:
+		return "Get State Summary Message"
+	case stateSummaryMsg:
+		return "State Summary Message"
+	case getStateSummaryFailedMsg:
+		return "Get State Summary Failed Message"
+	case gossipFrontierMsg:
+		return "Gossip Frontier Message"
 	case getMsg:
 		return "Get Message"
 	case putMsg:
@@ -87,6 +100,8 @@ func (t msgType) String() string {
 		return "Query Failed Message"
 	case notifyMsg:
 		return "Notify Message"
+	case gossipMsg:
+		return "Gossip Message"
 	case shutdownMsg:
 		return "Shutdown Message"
 	default:
@@ -0,0 +1,23 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package handler
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/ava-labs/gecko/utils/logging"
+
+	gmetrics "github.com/ava-labs/gecko/utils/metrics"
+)
+
+type metrics struct {
+	numDuplicateGets, numDuplicatePullQueries prometheus.Counter
+}
+
+// Initialize the metrics, labelled with [chain]
+func (m *metrics) Initialize(log logging.Logger, chain string, registerer prometheus.Registerer) {
+	r := gmetrics.NewChainRegisterer(log, registerer, chain, "handler")
+	m.numDuplicateGets = r.NewCounter("duplicate_gets", "Number of Get requests dropped because a request with the same ID from the same peer was still being handled")
+	m.numDuplicatePullQueries = r.NewCounter("duplicate_pull_queries", "Number of PullQuery requests dropped because a request with the same ID from the same peer was still being handled")
+}
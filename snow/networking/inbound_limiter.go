@@ -0,0 +1,171 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package networking
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/timer"
+)
+
+// maxIdleInboundPeers is the number of peers whose limits are tracked before
+// the peers whose buckets refilled completely are pruned
+const maxIdleInboundPeers = 1024
+
+// InboundLimits are the rates at which each peer may send this node messages.
+// A rate of 0 disables the corresponding limit.
+type InboundLimits struct {
+	// Messages per second a peer may send, and messages it may send at once
+	MsgRate, MsgBurst float64
+
+	// Bytes per second a peer may send, and bytes it may send at once. The
+	// burst should be at least the largest message size, or larger messages
+	// never fit.
+	ByteRate, ByteBurst float64
+
+	// Messages per second a peer may send of each type, and messages of each
+	// type it may send at once, so that one type of message can't crowd out
+	// the others
+	TypeMsgRate, TypeMsgBurst float64
+}
+
+// InboundLimiter limits the messages each peer may send this node, in total
+// and of each type, with token buckets. A peer can't flood this node with more
+// messages than it can handle.
+//
+// Messages that don't fit in a peer's limits should be dropped, unless they
+// must be handled, such as responses to this node's requests. Those are
+// charged anyway, putting the peer's buckets in debt so that its later
+// messages are the ones dropped.
+type InboundLimiter struct {
+	lock  sync.Mutex
+	clock timer.Clock
+
+	limits InboundLimits
+	peers  map[[20]byte]*inboundPeer
+}
+
+type inboundPeer struct {
+	msgs, bytes limitBucket
+	types       map[uint8]*limitBucket
+}
+
+// Initialize the limiter to enforce [limits]
+func (l *InboundLimiter) Initialize(limits InboundLimits) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	l.limits = limits
+	l.peers = make(map[[20]byte]*inboundPeer)
+}
+
+// Allow charges a message of type [msgType] and [size] bytes, sent by
+// [peerID], to the peer's limits. Returns true if the message fits within them.
+// If it doesn't, it's only charged if [force].
+func (l *InboundLimiter) Allow(peerID ids.ShortID, msgType uint8, size int, force bool) bool {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if l.peers == nil {
+		l.peers = make(map[[20]byte]*inboundPeer)
+	}
+
+	now := l.clock.Time()
+	key := peerID.Key()
+	peer, exists := l.peers[key]
+	if !exists {
+		if len(l.peers) >= maxIdleInboundPeers {
+			l.prune(now)
+		}
+		peer = &inboundPeer{
+			msgs:  limitBucket{tokens: l.limits.MsgBurst, lastUpdate: now},
+			bytes: limitBucket{tokens: l.limits.ByteBurst, lastUpdate: now},
+			types: make(map[uint8]*limitBucket),
+		}
+		l.peers[key] = peer
+	}
+	typeBucket, exists := peer.types[msgType]
+	if !exists {
+		typeBucket = &limitBucket{tokens: l.limits.TypeMsgBurst, lastUpdate: now}
+		peer.types[msgType] = typeBucket
+	}
+
+	charges := []struct {
+		bucket      *limitBucket
+		rate, burst float64
+		cost        float64
+	}{
+		{&peer.msgs, l.limits.MsgRate, l.limits.MsgBurst, 1},
+		{&peer.bytes, l.limits.ByteRate, l.limits.ByteBurst, float64(size)},
+		{typeBucket, l.limits.TypeMsgRate, l.limits.TypeMsgBurst, 1},
+	}
+
+	fits := true
+	for _, charge := range charges {
+		if charge.rate == 0 {
+			continue
+		}
+		charge.bucket.refill(now, charge.rate, charge.burst)
+		if charge.bucket.tokens < charge.cost {
+			fits = false
+		}
+	}
+	if !fits && !force {
+		return false
+	}
+
+	for _, charge := range charges {
+		if charge.rate == 0 {
+			continue
+		}
+		charge.bucket.tokens -= charge.cost
+		if charge.bucket.tokens < -charge.burst {
+			// The debt is bounded, so that a peer isn't cut off for long after
+			// this node requested a lot from it
+			charge.bucket.tokens = -charge.burst
+		}
+	}
+	return fits
+}
+
+// prune forgets the peers whose buckets refilled completely. A peer with full
+// buckets behaves identically to a peer that isn't tracked. Assumes the lock is
+// held.
+func (l *InboundLimiter) prune(now time.Time) {
+	for key, peer := range l.peers {
+		peer.msgs.refill(now, l.limits.MsgRate, l.limits.MsgBurst)
+		peer.bytes.refill(now, l.limits.ByteRate, l.limits.ByteBurst)
+		full := peer.msgs.tokens >= l.limits.MsgBurst && peer.bytes.tokens >= l.limits.ByteBurst
+		for _, typeBucket := range peer.types {
+			typeBucket.refill(now, l.limits.TypeMsgRate, l.limits.TypeMsgBurst)
+			full = full && typeBucket.tokens >= l.limits.TypeMsgBurst
+		}
+		if full {
+			delete(l.peers, key)
+		}
+	}
+}
+
+// limitBucket is a token bucket that may go into debt
+type limitBucket struct {
+	tokens     float64
+	lastUpdate time.Time
+}
+
+// refill the bucket with the tokens earned since the last update, at [rate]
+// tokens per second, up to [burst] tokens
+func (b *limitBucket) refill(now time.Time, rate, burst float64) {
+	elapsed := now.Sub(b.lastUpdate)
+	if elapsed <= 0 {
+		return
+	}
+	b.lastUpdate = now
+
+	b.tokens += elapsed.Seconds() * rate
+	if b.tokens > burst {
+		b.tokens = burst
+	}
+}
@@ -0,0 +1,113 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package networking
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ava-labs/gecko/ids"
+)
+
+func TestInboundLimiterMsgLimit(t *testing.T) {
+	l := InboundLimiter{}
+	l.Initialize(InboundLimits{MsgRate: 2, MsgBurst: 2})
+	now := time.Unix(1000000, 0)
+	l.clock.Set(now)
+
+	peer1 := ids.NewShortID([20]byte{1})
+	peer2 := ids.NewShortID([20]byte{2})
+	for i := 0; i < 2; i++ {
+		if !l.Allow(peer1, 0, 100, false) {
+			t.Fatalf("Should have allowed message %d within the burst", i)
+		}
+	}
+	if l.Allow(peer1, 0, 100, false) {
+		t.Fatalf("Should have dropped the message exceeding the burst")
+	}
+	if !l.Allow(peer2, 0, 100, false) {
+		t.Fatalf("Should limit each peer separately")
+	}
+
+	l.clock.Set(now.Add(500 * time.Millisecond))
+	if !l.Allow(peer1, 0, 100, false) {
+		t.Fatalf("Should have allowed a message once the bucket refilled")
+	}
+	if l.Allow(peer1, 0, 100, false) {
+		t.Fatalf("Should have dropped the message exceeding the rate")
+	}
+}
+
+func TestInboundLimiterByteLimit(t *testing.T) {
+	l := InboundLimiter{}
+	l.Initialize(InboundLimits{ByteRate: 100, ByteBurst: 100})
+	l.clock.Set(time.Unix(1000000, 0))
+
+	peer := ids.NewShortID([20]byte{1})
+	if !l.Allow(peer, 0, 60, false) {
+		t.Fatalf("Should have allowed the message within the burst")
+	}
+	if l.Allow(peer, 0, 60, false) {
+		t.Fatalf("Should have dropped the message exceeding the burst")
+	}
+	if !l.Allow(peer, 0, 40, false) {
+		t.Fatalf("Shouldn't have charged the dropped message")
+	}
+}
+
+func TestInboundLimiterTypeLimit(t *testing.T) {
+	l := InboundLimiter{}
+	l.Initialize(InboundLimits{TypeMsgRate: 1, TypeMsgBurst: 1})
+	l.clock.Set(time.Unix(1000000, 0))
+
+	peer := ids.NewShortID([20]byte{1})
+	if !l.Allow(peer, 1, 100, false) {
+		t.Fatalf("Should have allowed the first message of the type")
+	}
+	if l.Allow(peer, 1, 100, false) {
+		t.Fatalf("Should have dropped the second message of the type")
+	}
+	if !l.Allow(peer, 2, 100, false) {
+		t.Fatalf("Should limit each type of message separately")
+	}
+}
+
+func TestInboundLimiterForce(t *testing.T) {
+	l := InboundLimiter{}
+	l.Initialize(InboundLimits{MsgRate: 1, MsgBurst: 2})
+	now := time.Unix(1000000, 0)
+	l.clock.Set(now)
+
+	peer := ids.NewShortID([20]byte{1})
+	for i := 0; i < 2; i++ {
+		l.Allow(peer, 0, 100, false)
+	}
+	for i := 0; i < 10; i++ {
+		if l.Allow(peer, 0, 100, true) {
+			t.Fatalf("Should have reported the forced message as exceeding the limit")
+		}
+	}
+
+	// The debt is bounded by the burst, so it's paid off after 3 seconds
+	l.clock.Set(now.Add(2 * time.Second))
+	if l.Allow(peer, 0, 100, false) {
+		t.Fatalf("Should have deprioritized the peer while it's in debt")
+	}
+	l.clock.Set(now.Add(3 * time.Second))
+	if !l.Allow(peer, 0, 100, false) {
+		t.Fatalf("Should have allowed a message once the debt was paid off")
+	}
+}
+
+func TestInboundLimiterUnlimited(t *testing.T) {
+	l := InboundLimiter{}
+	l.Initialize(InboundLimits{})
+
+	peer := ids.NewShortID([20]byte{1})
+	for i := 0; i < 100; i++ {
+		if !l.Allow(peer, 0, 1<<20, false) {
+			t.Fatalf("Shouldn't limit messages without limits")
+		}
+	}
+}
@@ -0,0 +1,84 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package networking
+
+import (
+	"math"
+	"sort"
+	"sync"
+
+	"github.com/ava-labs/gecko/ids"
+)
+
+// NetworkSize tracks the estimates of the total number of nodes in the network
+// that peers report in their peer lists. Each peer only sees part of a large
+// network, and any one peer may report a wrong estimate, so the network size
+// is estimated as the median of the peers' estimates.
+type NetworkSize struct {
+	lock  sync.Mutex
+	sizes map[[20]byte]uint64
+}
+
+// Add records that [peerID] estimates the network to have [size] nodes
+func (ns *NetworkSize) Add(peerID ids.ShortID, size uint64) {
+	ns.lock.Lock()
+	defer ns.lock.Unlock()
+
+	if ns.sizes == nil {
+		ns.sizes = make(map[[20]byte]uint64)
+	}
+	ns.sizes[peerID.Key()] = size
+}
+
+// Remove the estimate recorded for [peerID]
+func (ns *NetworkSize) Remove(peerID ids.ShortID) {
+	ns.lock.Lock()
+	defer ns.lock.Unlock()
+
+	delete(ns.sizes, peerID.Key())
+}
+
+// Estimate returns the estimated number of nodes in the network, and the
+// number of peers the estimate is based on. [known] is the number of nodes this
+// node knows of, which the network is at least as large as.
+func (ns *NetworkSize) Estimate(known uint64) (uint64, int) {
+	ns.lock.Lock()
+	defer ns.lock.Unlock()
+
+	if len(ns.sizes) == 0 {
+		return known, 0
+	}
+
+	sizes := make([]uint64, 0, len(ns.sizes))
+	for _, size := range ns.sizes {
+		sizes = append(sizes, size)
+	}
+	sort.Slice(sizes, func(i, j int) bool { return sizes[i] < sizes[j] })
+
+	median := sizes[len(sizes)/2]
+	if len(sizes)%2 == 0 {
+		// The sizes are sorted, so this can't overflow
+		lower := sizes[len(sizes)/2-1]
+		median = lower + (median-lower)/2
+	}
+	if median < known {
+		median = known
+	}
+	return median, len(sizes)
+}
+
+// GossipFanOut returns the number of peers to gossip to in a network of
+// [networkSize] nodes. Gossiping to about the square root of the network's
+// size reaches every node in a few rounds, without every node messaging a
+// large fraction of the network. The fan-out is kept within [min, max].
+func GossipFanOut(networkSize uint64, min, max int) int {
+	fanOut := int(math.Ceil(math.Sqrt(float64(networkSize))))
+	if fanOut < min {
+		fanOut = min
+	}
+	if fanOut > max {
+		fanOut = max
+	}
+	return fanOut
+}
@@ -0,0 +1,84 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package networking
+
+import (
+	"math"
+	"testing"
+
+	"github.com/ava-labs/gecko/ids"
+)
+
+func TestNetworkSizeEmpty(t *testing.T) {
+	ns := NetworkSize{}
+
+	if size, numPeers := ns.Estimate(5); size != 5 || numPeers != 0 {
+		t.Fatalf("Expected the known size of 5 from no peers but got %d from %d peers", size, numPeers)
+	}
+}
+
+func TestNetworkSizeMedian(t *testing.T) {
+	ns := NetworkSize{}
+	ns.Add(ids.NewShortID([20]byte{1}), 1000000)
+	ns.Add(ids.NewShortID([20]byte{2}), 200)
+	ns.Add(ids.NewShortID([20]byte{3}), 100)
+
+	if size, numPeers := ns.Estimate(1); size != 200 || numPeers != 3 {
+		t.Fatalf("Expected size of 200 from 3 peers but got %d from %d peers", size, numPeers)
+	}
+
+	ns.Add(ids.NewShortID([20]byte{4}), 150)
+
+	if size, numPeers := ns.Estimate(1); size != 175 || numPeers != 4 {
+		t.Fatalf("Expected size of 175 from 4 peers but got %d from %d peers", size, numPeers)
+	}
+	if size, _ := ns.Estimate(300); size != 300 {
+		t.Fatalf("Expected size of at least the 300 known nodes but got %d", size)
+	}
+}
+
+func TestNetworkSizeMedianOverflow(t *testing.T) {
+	ns := NetworkSize{}
+	ns.Add(ids.NewShortID([20]byte{1}), math.MaxUint64)
+	ns.Add(ids.NewShortID([20]byte{2}), math.MaxUint64)
+
+	if size, _ := ns.Estimate(1); size != math.MaxUint64 {
+		t.Fatalf("Expected size of %d but got %d", uint64(math.MaxUint64), size)
+	}
+}
+
+func TestNetworkSizeReplaceAndRemove(t *testing.T) {
+	ns := NetworkSize{}
+	peer := ids.NewShortID([20]byte{1})
+	ns.Add(peer, 10)
+	ns.Add(peer, 20)
+
+	if size, numPeers := ns.Estimate(1); size != 20 || numPeers != 1 {
+		t.Fatalf("Expected size of 20 from 1 peer but got %d from %d peers", size, numPeers)
+	}
+
+	ns.Remove(peer)
+
+	if size, numPeers := ns.Estimate(1); size != 1 || numPeers != 0 {
+		t.Fatalf("Expected size of 1 from no peers but got %d from %d peers", size, numPeers)
+	}
+}
+
+func TestGossipFanOut(t *testing.T) {
+	tests := []struct {
+		networkSize uint64
+		expected    int
+	}{
+		{0, 10},
+		{50, 10},
+		{101, 11},
+		{2500, 50},
+		{1000000, 100},
+	}
+	for _, test := range tests {
+		if fanOut := GossipFanOut(test.networkSize, 10, 100); fanOut != test.expected {
+			t.Fatalf("Expected fan-out of %d for %d nodes but got %d", test.expected, test.networkSize, fanOut)
+		}
+	}
+}
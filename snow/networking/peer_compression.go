@@ -0,0 +1,47 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package networking
+
+import (
+	"sync"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/compression"
+)
+
+// PeerCompression tracks the compression each peer announced it accepts during
+// the version handshake. Every message sent to a peer that accepts compression
+// is prefixed with the compression applied to the rest of the message.
+type PeerCompression struct {
+	lock  sync.Mutex
+	types map[[20]byte]compression.Type
+}
+
+// Add records that [peerID] accepts messages compressed with [t]
+func (pc *PeerCompression) Add(peerID ids.ShortID, t compression.Type) {
+	pc.lock.Lock()
+	defer pc.lock.Unlock()
+
+	if pc.types == nil {
+		pc.types = make(map[[20]byte]compression.Type)
+	}
+	pc.types[peerID.Key()] = t
+}
+
+// Remove the compression recorded for [peerID]
+func (pc *PeerCompression) Remove(peerID ids.ShortID) {
+	pc.lock.Lock()
+	defer pc.lock.Unlock()
+
+	delete(pc.types, peerID.Key())
+}
+
+// Accepted returns the compression [peerID] accepts. If nothing was recorded
+// for [peerID], it doesn't accept compression.
+func (pc *PeerCompression) Accepted(peerID ids.ShortID) compression.Type {
+	pc.lock.Lock()
+	defer pc.lock.Unlock()
+
+	return pc.types[peerID.Key()]
+}
@@ -0,0 +1,30 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package networking
+
+import (
+	"testing"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/compression"
+)
+
+func TestPeerCompression(t *testing.T) {
+	pc := PeerCompression{}
+	peer := ids.NewShortID([20]byte{1})
+
+	if typ := pc.Accepted(peer); typ != compression.None {
+		t.Fatalf("Expected an unknown peer to accept %s but got %s", compression.None, typ)
+	}
+
+	pc.Add(peer, compression.Gzip)
+	if typ := pc.Accepted(peer); typ != compression.Gzip {
+		t.Fatalf("Expected the peer to accept %s but got %s", compression.Gzip, typ)
+	}
+
+	pc.Remove(peer)
+	if typ := pc.Accepted(peer); typ != compression.None {
+		t.Fatalf("Expected a removed peer to accept %s but got %s", compression.None, typ)
+	}
+}
@@ -0,0 +1,148 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package networking
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/ava-labs/gecko/ids"
+)
+
+// ReplayWindowSize is how far behind the highest sequence number received from
+// a peer a sequence number may be and still be accepted, if it wasn't received
+// yet. Older sequence numbers are rejected, since it can no longer be told
+// whether they were received.
+const ReplayWindowSize = 64
+
+// PeerReplays is the number of replayed messages detected from a peer
+type PeerReplays struct {
+	PeerID  ids.ShortID
+	Replays uint64
+}
+
+// ReplayGuard detects messages that are received more than once, such as
+// messages captured and re-injected by a middlebox. Peers that announced
+// during the version handshake that they track sequence numbers expect every
+// message sent to them to be prefixed with a sequence number, which increases
+// with every message the sender sends. A message is a replay if its sequence
+// number was already received from the same peer.
+//
+// Like IPsec's anti-replay window, the sequence numbers received from a peer
+// are tracked in a bitmap that slides with the highest one received, so
+// messages that arrive out of order are still accepted.
+type ReplayGuard struct {
+	lock sync.Mutex
+
+	// Whether each connected peer expects the messages sent to it to be
+	// sequenced
+	sequenced map[[20]byte]bool
+	// The sequence numbers received from each connected peer
+	windows map[[20]byte]*replayWindow
+	// The number of replays detected from each peer, kept once it disconnects
+	replays map[[20]byte]*PeerReplays
+}
+
+type replayWindow struct {
+	// The highest sequence number received
+	highest uint64
+	// Bit i is set if highest-i was received
+	received uint64
+}
+
+// Add records whether [peerID] expects the messages sent to it to be
+// sequenced. The sequence numbers received from [peerID] are forgotten.
+func (rg *ReplayGuard) Add(peerID ids.ShortID, sequenced bool) {
+	rg.lock.Lock()
+	defer rg.lock.Unlock()
+
+	if rg.sequenced == nil {
+		rg.sequenced = make(map[[20]byte]bool)
+	}
+	key := peerID.Key()
+	rg.sequenced[key] = sequenced
+	delete(rg.windows, key)
+}
+
+// Remove forgets [peerID], other than the number of replays it sent. If it
+// reconnects, it may start numbering its messages anew.
+func (rg *ReplayGuard) Remove(peerID ids.ShortID) {
+	rg.lock.Lock()
+	defer rg.lock.Unlock()
+
+	key := peerID.Key()
+	delete(rg.sequenced, key)
+	delete(rg.windows, key)
+}
+
+// Sequenced returns true if [peerID] expects the messages sent to it to be
+// sequenced
+func (rg *ReplayGuard) Sequenced(peerID ids.ShortID) bool {
+	rg.lock.Lock()
+	defer rg.lock.Unlock()
+
+	return rg.sequenced[peerID.Key()]
+}
+
+// Check records that a message with sequence number [seq] was received from
+// [peerID]. Returns false, and counts a replay, if [seq] was already received
+// from [peerID] or is too old to tell.
+func (rg *ReplayGuard) Check(peerID ids.ShortID, seq uint64) bool {
+	rg.lock.Lock()
+	defer rg.lock.Unlock()
+
+	if rg.windows == nil {
+		rg.windows = make(map[[20]byte]*replayWindow)
+	}
+
+	key := peerID.Key()
+	window, exists := rg.windows[key]
+	switch {
+	case !exists:
+		rg.windows[key] = &replayWindow{
+			highest:  seq,
+			received: 1,
+		}
+		return true
+	case seq > window.highest:
+		if shift := seq - window.highest; shift < ReplayWindowSize {
+			window.received = window.received<<shift | 1
+		} else {
+			window.received = 1
+		}
+		window.highest = seq
+		return true
+	}
+
+	age := window.highest - seq
+	if age < ReplayWindowSize && window.received&(1<<age) == 0 {
+		window.received |= 1 << age
+		return true
+	}
+
+	if rg.replays == nil {
+		rg.replays = make(map[[20]byte]*PeerReplays)
+	}
+	replays, exists := rg.replays[key]
+	if !exists {
+		replays = &PeerReplays{PeerID: peerID}
+		rg.replays[key] = replays
+	}
+	replays.Replays++
+	return false
+}
+
+// Replays returns the number of replays detected from each peer that sent
+// any, most first
+func (rg *ReplayGuard) Replays() []PeerReplays {
+	rg.lock.Lock()
+	defer rg.lock.Unlock()
+
+	replays := make([]PeerReplays, 0, len(rg.replays))
+	for _, peer := range rg.replays {
+		replays = append(replays, *peer)
+	}
+	sort.Slice(replays, func(i, j int) bool { return replays[i].Replays > replays[j].Replays })
+	return replays
+}
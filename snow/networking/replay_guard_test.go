@@ -0,0 +1,64 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package networking
+
+import (
+	"testing"
+
+	"github.com/ava-labs/gecko/ids"
+)
+
+func TestReplayGuardCheck(t *testing.T) {
+	rg := ReplayGuard{}
+	peer := ids.NewShortID([20]byte{1})
+
+	for _, seq := range []uint64{5, 6, 8, 7, 100, 40} {
+		if !rg.Check(peer, seq) {
+			t.Fatalf("Should have accepted sequence number %d", seq)
+		}
+	}
+	for _, seq := range []uint64{5, 7, 8, 100, 40, 36} {
+		if rg.Check(peer, seq) {
+			t.Fatalf("Should have rejected sequence number %d", seq)
+		}
+	}
+	if !rg.Check(peer, 37) {
+		t.Fatalf("Should have accepted the oldest sequence number in the window")
+	}
+
+	replays := rg.Replays()
+	if len(replays) != 1 || !replays[0].PeerID.Equals(peer) || replays[0].Replays != 6 {
+		t.Fatalf("Expected 6 replays from the peer but got %v", replays)
+	}
+}
+
+func TestReplayGuardPeers(t *testing.T) {
+	rg := ReplayGuard{}
+	peer1 := ids.NewShortID([20]byte{1})
+	peer2 := ids.NewShortID([20]byte{2})
+
+	rg.Add(peer1, true)
+	rg.Add(peer2, false)
+	if !rg.Sequenced(peer1) || rg.Sequenced(peer2) {
+		t.Fatalf("Should have recorded which peers expect sequenced messages")
+	}
+
+	if !rg.Check(peer1, 1) || !rg.Check(peer2, 1) {
+		t.Fatalf("Should track the sequence numbers of each peer separately")
+	}
+	if rg.Check(peer1, 1) {
+		t.Fatalf("Should have rejected the replay")
+	}
+
+	rg.Remove(peer1)
+	if rg.Sequenced(peer1) {
+		t.Fatalf("Should have forgotten the disconnected peer")
+	}
+	if !rg.Check(peer1, 1) {
+		t.Fatalf("Should have accepted a reconnected peer numbering its messages anew")
+	}
+	if replays := rg.Replays(); len(replays) != 1 || replays[0].Replays != 1 {
+		t.Fatalf("Should have kept the replays of the disconnected peer but got %v", replays)
+	}
+}
@@ -0,0 +1,190 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package networking
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/timer"
+)
+
+// Penalties added to a peer's score when it misbehaves
+const (
+	// InvalidMessagePenalty is added when a peer sends a message that can't be
+	// parsed
+	InvalidMessagePenalty = 10
+
+	// FailedQueryPenalty is added when a peer doesn't answer a query in time
+	FailedQueryPenalty = 2
+
+	// TimeoutPenalty is added when a peer doesn't answer any other request in
+	// time
+	TimeoutPenalty = 1
+)
+
+// maxTrackedPeers is the number of peers whose scores are tracked before the
+// scores that decayed away are pruned
+const maxTrackedPeers = 1 << 14
+
+// minScore is the score below which a peer is forgotten
+const minScore = 0.01
+
+// PeerScore is a peer's reputation
+type PeerScore struct {
+	PeerID ids.ShortID
+	// Score is the sum of the peer's penalties, each halved every half-life
+	// since it was added
+	Score float64
+	// BannedUntil is when the peer's ban ends. Zero if the peer isn't banned.
+	BannedUntil time.Time
+}
+
+// Reputation scores peers by their misbehavior, such as sending invalid
+// messages and not answering requests. A peer whose score reaches the ban
+// threshold is banned for a while: its messages are dropped and it's
+// disconnected. Penalties decay, so a peer that only occasionally times out is
+// never banned.
+type Reputation struct {
+	lock  sync.Mutex
+	clock timer.Clock
+
+	threshold   float64
+	halfLife    time.Duration
+	banDuration time.Duration
+	onBan       func(ids.ShortID)
+
+	peers map[[20]byte]*peerScore
+}
+
+type peerScore struct {
+	id          ids.ShortID
+	score       float64
+	updated     time.Time
+	bannedUntil time.Time
+}
+
+// Initialize the tracker. A peer is banned for [banDuration] once its score
+// reaches [threshold], and [onBan] is called with its ID. Penalties are halved
+// every [halfLife]. If [threshold] is 0, peers are never banned.
+func (r *Reputation) Initialize(threshold float64, halfLife, banDuration time.Duration, onBan func(ids.ShortID)) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.threshold = threshold
+	r.halfLife = halfLife
+	r.banDuration = banDuration
+	r.onBan = onBan
+}
+
+// Penalize adds [penalty] to the score of [peerID]. Returns true if the peer
+// was banned as a result.
+func (r *Reputation) Penalize(peerID ids.ShortID, penalty float64) bool {
+	banned, onBan := r.penalize(peerID, penalty)
+	if banned && onBan != nil {
+		onBan(peerID)
+	}
+	return banned
+}
+
+func (r *Reputation) penalize(peerID ids.ShortID, penalty float64) (bool, func(ids.ShortID)) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	now := r.clock.Time()
+	if r.peers == nil {
+		r.peers = make(map[[20]byte]*peerScore)
+	}
+	if len(r.peers) >= maxTrackedPeers {
+		r.prune(now)
+	}
+
+	key := peerID.Key()
+	peer, exists := r.peers[key]
+	if !exists {
+		peer = &peerScore{
+			id:      peerID,
+			updated: now,
+		}
+		r.peers[key] = peer
+	}
+	if now.Before(peer.bannedUntil) {
+		// The peer is already banned
+		return false, nil
+	}
+
+	r.decay(peer, now)
+	peer.score += penalty
+	if r.threshold <= 0 || peer.score < r.threshold {
+		return false, nil
+	}
+
+	// The ban wipes the slate clean, so that the peer isn't banned again as
+	// soon as the ban ends
+	peer.score = 0
+	peer.bannedUntil = now.Add(r.banDuration)
+	return true, r.onBan
+}
+
+// Banned returns true if [peerID] is banned
+func (r *Reputation) Banned(peerID ids.ShortID) bool {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	peer, exists := r.peers[peerID.Key()]
+	return exists && r.clock.Time().Before(peer.bannedUntil)
+}
+
+// Scores returns the reputation of every peer that was penalized recently
+// enough for its score not to have decayed away, or that is banned. The worst
+// scores are returned first.
+func (r *Reputation) Scores() []PeerScore {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	now := r.clock.Time()
+	r.prune(now)
+
+	scores := make([]PeerScore, 0, len(r.peers))
+	for _, peer := range r.peers {
+		score := PeerScore{
+			PeerID: peer.id,
+			Score:  peer.score,
+		}
+		if now.Before(peer.bannedUntil) {
+			score.BannedUntil = peer.bannedUntil
+		}
+		scores = append(scores, score)
+	}
+	sort.Slice(scores, func(i, j int) bool {
+		if !scores[i].BannedUntil.Equal(scores[j].BannedUntil) {
+			return scores[i].BannedUntil.After(scores[j].BannedUntil)
+		}
+		return scores[i].Score > scores[j].Score
+	})
+	return scores
+}
+
+// prune decays every score, and forgets the peers that aren't banned whose
+// score decayed away. Assumes the lock is held.
+func (r *Reputation) prune(now time.Time) {
+	for key, peer := range r.peers {
+		r.decay(peer, now)
+		if peer.score < minScore && !now.Before(peer.bannedUntil) {
+			delete(r.peers, key)
+		}
+	}
+}
+
+// decay halves [peer]'s score for every half-life since it was last updated.
+// Assumes the lock is held.
+func (r *Reputation) decay(peer *peerScore, now time.Time) {
+	if r.halfLife > 0 && now.After(peer.updated) {
+		peer.score *= math.Exp2(-float64(now.Sub(peer.updated)) / float64(r.halfLife))
+	}
+	peer.updated = now
+}
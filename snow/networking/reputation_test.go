@@ -0,0 +1,112 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package networking
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ava-labs/gecko/ids"
+)
+
+func TestReputationBan(t *testing.T) {
+	banned := []ids.ShortID(nil)
+	r := Reputation{}
+	r.Initialize(20, time.Hour, time.Minute, func(peerID ids.ShortID) { banned = append(banned, peerID) })
+	now := time.Unix(1000000, 0)
+	r.clock.Set(now)
+
+	peer := ids.NewShortID([20]byte{1})
+	if r.Penalize(peer, InvalidMessagePenalty) {
+		t.Fatalf("Shouldn't have banned the peer after one invalid message")
+	}
+	if r.Banned(peer) {
+		t.Fatalf("Shouldn't have reported the peer as banned")
+	}
+	if !r.Penalize(peer, InvalidMessagePenalty) {
+		t.Fatalf("Should have banned the peer after two invalid messages")
+	}
+	if !r.Banned(peer) {
+		t.Fatalf("Should have reported the peer as banned")
+	}
+	if len(banned) != 1 || !banned[0].Equals(peer) {
+		t.Fatalf("Should have notified that the peer was banned but got %v", banned)
+	}
+	if r.Penalize(peer, InvalidMessagePenalty) {
+		t.Fatalf("Shouldn't have banned the peer while it's already banned")
+	}
+
+	r.clock.Set(now.Add(time.Minute))
+	if r.Banned(peer) {
+		t.Fatalf("Should have lifted the ban")
+	}
+	if r.Penalize(peer, InvalidMessagePenalty) {
+		t.Fatalf("Shouldn't have banned the peer again once the ban was lifted")
+	}
+}
+
+func TestReputationDecay(t *testing.T) {
+	r := Reputation{}
+	r.Initialize(20, time.Hour, time.Minute, nil)
+	now := time.Unix(1000000, 0)
+	r.clock.Set(now)
+
+	peer := ids.NewShortID([20]byte{1})
+	r.Penalize(peer, 16)
+
+	r.clock.Set(now.Add(2 * time.Hour))
+	if r.Penalize(peer, 15) {
+		t.Fatalf("Shouldn't have banned the peer once its penalty decayed")
+	}
+
+	scores := r.Scores()
+	if len(scores) != 1 {
+		t.Fatalf("Expected 1 score but got %d", len(scores))
+	}
+	if score := scores[0].Score; score != 19 {
+		t.Fatalf("Expected a score of 19 but got %f", score)
+	}
+
+	r.clock.Set(now.Add(100 * time.Hour))
+	if scores := r.Scores(); len(scores) != 0 {
+		t.Fatalf("Should have forgotten the peer once its score decayed away but got %v", scores)
+	}
+}
+
+func TestReputationNoThreshold(t *testing.T) {
+	r := Reputation{}
+	peer := ids.NewShortID([20]byte{1})
+	for i := 0; i < 100; i++ {
+		if r.Penalize(peer, InvalidMessagePenalty) {
+			t.Fatalf("Shouldn't ban peers without a threshold")
+		}
+	}
+	if r.Banned(peer) {
+		t.Fatalf("Shouldn't ban peers without a threshold")
+	}
+}
+
+func TestReputationScoresOrder(t *testing.T) {
+	r := Reputation{}
+	r.Initialize(20, time.Hour, time.Minute, nil)
+	r.clock.Set(time.Unix(1000000, 0))
+
+	peer1 := ids.NewShortID([20]byte{1})
+	peer2 := ids.NewShortID([20]byte{2})
+	peer3 := ids.NewShortID([20]byte{3})
+	r.Penalize(peer1, TimeoutPenalty)
+	r.Penalize(peer2, FailedQueryPenalty)
+	r.Penalize(peer3, 2*InvalidMessagePenalty)
+
+	scores := r.Scores()
+	if len(scores) != 3 {
+		t.Fatalf("Expected 3 scores but got %d", len(scores))
+	}
+	if !scores[0].PeerID.Equals(peer3) || scores[0].BannedUntil.IsZero() {
+		t.Fatalf("Expected the banned peer first but got %v", scores[0])
+	}
+	if !scores[1].PeerID.Equals(peer2) || !scores[2].PeerID.Equals(peer1) {
+		t.Fatalf("Expected the worst scores first but got %v", scores)
+	}
+}
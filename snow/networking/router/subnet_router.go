@@ -143,6 +143,64 @@ func (sr *ChainRouter) GetAcceptedFailed(validatorID ids.ShortID, chainID ids.ID
 	}
 }
 
+// GetStateSummary routes an incoming GetStateSummary request from the
+// validator with ID [validatorID] to the consensus engine working on the chain
+// with ID [chainID]
+func (sr *ChainRouter) GetStateSummary(validatorID ids.ShortID, chainID ids.ID, requestID uint32) {
+	sr.lock.RLock()
+	defer sr.lock.RUnlock()
+
+	if chain, exists := sr.chains[chainID.Key()]; exists {
+		chain.GetStateSummary(validatorID, requestID)
+	} else {
+		sr.log.Warn("Message referenced a chain, %s, this validator is not validating", chainID)
+	}
+}
+
+// StateSummary routes an incoming StateSummary message from the validator with
+// ID [validatorID] to the consensus engine working on the chain with ID
+// [chainID]
+func (sr *ChainRouter) StateSummary(validatorID ids.ShortID, chainID ids.ID, requestID uint32, summary []byte) {
+	sr.lock.RLock()
+	defer sr.lock.RUnlock()
+
+	sr.timeouts.Cancel(validatorID, chainID, requestID)
+	if chain, exists := sr.chains[chainID.Key()]; exists {
+		chain.StateSummary(validatorID, requestID, summary)
+	} else {
+		sr.log.Warn("Message referenced a chain, %s, this validator is not validating", chainID)
+	}
+}
+
+// GetStateSummaryFailed routes an incoming GetStateSummaryFailed message from
+// the validator with ID [validatorID] to the consensus engine working on the
+// chain with ID [chainID]
+func (sr *ChainRouter) GetStateSummaryFailed(validatorID ids.ShortID, chainID ids.ID, requestID uint32) {
+	sr.lock.RLock()
+	defer sr.lock.RUnlock()
+
+	sr.timeouts.Cancel(validatorID, chainID, requestID)
+	if chain, exists := sr.chains[chainID.Key()]; exists {
+		chain.GetStateSummaryFailed(validatorID, requestID)
+	} else {
+		sr.log.Warn("Message referenced a chain, %s, this validator is not validating", chainID)
+	}
+}
+
+// GossipFrontier routes an incoming GossipFrontier message from the peer with
+// ID [validatorID] to the consensus engine working on the chain with ID
+// [chainID]
+func (sr *ChainRouter) GossipFrontier(validatorID ids.ShortID, chainID ids.ID, containerIDs ids.Set) {
+	sr.lock.RLock()
+	defer sr.lock.RUnlock()
+
+	if chain, exists := sr.chains[chainID.Key()]; exists {
+		chain.GossipFrontier(validatorID, containerIDs)
+	} else {
+		sr.log.Debug("Message referenced a chain, %s, this node is not validating", chainID)
+	}
+}
+
 // Get routes an incoming Get request from the validator with ID [validatorID]
 // to the consensus engine working on the chain with ID [chainID]
 func (sr *ChainRouter) Get(validatorID ids.ShortID, chainID ids.ID, requestID uint32, containerID ids.ID) {
@@ -0,0 +1,137 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package sender
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/timer"
+)
+
+const (
+	// maxIdlePeerBuckets is the number of peer buckets that may be tracked
+	// before the full buckets are pruned
+	maxIdlePeerBuckets = 1024
+)
+
+// GossipBudget limits the outbound bandwidth spent on gossip, both in total and
+// to each peer, so that gossip can't crowd out queries and their responses.
+//
+// The budgets are token buckets measured in bytes. Gossip that doesn't fit in
+// the budget should be skipped; gossip is best effort and peers that miss a
+// container will learn about it when they query for it.
+type GossipBudget struct {
+	Clock timer.Clock
+
+	lock sync.Mutex
+
+	globalRate, globalBurst uint64
+	peerRate, peerBurst     uint64
+
+	global bucket
+	peers  map[[20]byte]*bucket
+}
+
+// Initialize the budget. [globalRate] and [peerRate] are the number of bytes
+// per second that may be gossiped in total and to a single peer. [globalBurst]
+// and [peerBurst] are the maximum number of bytes that may be gossiped at once.
+// A rate of 0 disables the corresponding limit.
+func (b *GossipBudget) Initialize(globalRate, globalBurst, peerRate, peerBurst uint64) {
+	b.globalRate = globalRate
+	b.globalBurst = globalBurst
+	b.peerRate = peerRate
+	b.peerBurst = peerBurst
+
+	now := b.Clock.Time()
+	b.global = bucket{
+		tokens:     globalBurst,
+		lastUpdate: now,
+	}
+	b.peers = make(map[[20]byte]*bucket)
+}
+
+// Spend attempts to charge [size] bytes of gossip to [peerID]. Returns true if
+// the gossip fits in both the global and the peer's budget, in which case the
+// gossip should be sent. Returns false, without charging either budget, if the
+// gossip should be skipped.
+func (b *GossipBudget) Spend(peerID ids.ShortID, size uint64) bool {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	now := b.Clock.Time()
+
+	if b.globalRate != 0 {
+		b.global.refill(now, b.globalRate, b.globalBurst)
+		if b.global.tokens < size {
+			return false
+		}
+	}
+
+	if b.peerRate != 0 {
+		key := peerID.Key()
+		peer, exists := b.peers[key]
+		if !exists {
+			if len(b.peers) >= maxIdlePeerBuckets {
+				b.prune(now)
+			}
+			peer = &bucket{
+				tokens:     b.peerBurst,
+				lastUpdate: now,
+			}
+			b.peers[key] = peer
+		}
+		peer.refill(now, b.peerRate, b.peerBurst)
+		if peer.tokens < size {
+			return false
+		}
+		peer.tokens -= size
+	}
+
+	if b.globalRate != 0 {
+		b.global.tokens -= size
+	}
+	return true
+}
+
+// prune removes the peer buckets that have refilled completely. A full bucket
+// behaves identically to a bucket that isn't tracked.
+func (b *GossipBudget) prune(now time.Time) {
+	for key, peer := range b.peers {
+		peer.refill(now, b.peerRate, b.peerBurst)
+		if peer.tokens == b.peerBurst {
+			delete(b.peers, key)
+		}
+	}
+}
+
+// bucket is a token bucket whose tokens are bytes
+type bucket struct {
+	tokens     uint64
+	lastUpdate time.Time
+}
+
+// refill the bucket with the tokens earned since the last update, at [rate]
+// tokens per second, up to [burst] tokens
+func (b *bucket) refill(now time.Time, rate, burst uint64) {
+	elapsed := now.Sub(b.lastUpdate)
+	if elapsed <= 0 {
+		return
+	}
+
+	earned := uint64(elapsed.Seconds() * float64(rate))
+	if earned == 0 {
+		// Don't advance the last update, so that the fractional tokens aren't
+		// lost when the bucket is refilled frequently
+		return
+	}
+	b.lastUpdate = now
+
+	if burst-b.tokens < earned {
+		b.tokens = burst
+	} else {
+		b.tokens += earned
+	}
+}
@@ -0,0 +1,106 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package sender
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ava-labs/gecko/ids"
+)
+
+func TestGossipBudgetPeerLimit(t *testing.T) {
+	b := GossipBudget{}
+	b.Clock.Set(time.Unix(0, 0))
+	b.Initialize(0, 0, 100, 200)
+
+	peer0 := ids.NewShortID([20]byte{0})
+	peer1 := ids.NewShortID([20]byte{1})
+
+	if !b.Spend(peer0, 150) {
+		t.Fatalf("Gossip within the burst should have been allowed")
+	}
+	if b.Spend(peer0, 100) {
+		t.Fatalf("Gossip beyond the peer's budget should have been skipped")
+	}
+	if !b.Spend(peer0, 50) {
+		t.Fatalf("Skipped gossip shouldn't have been charged to the peer")
+	}
+	if !b.Spend(peer1, 200) {
+		t.Fatalf("Other peers' budgets should be independent")
+	}
+
+	b.Clock.Set(time.Unix(1, 0))
+	if !b.Spend(peer0, 100) {
+		t.Fatalf("The peer's budget should have been refilled")
+	}
+	if b.Spend(peer0, 1) {
+		t.Fatalf("The peer's budget should have been exhausted")
+	}
+
+	b.Clock.Set(time.Unix(60, 0))
+	if b.Spend(peer0, 201) {
+		t.Fatalf("The peer's budget should never exceed the burst")
+	}
+}
+
+func TestGossipBudgetGlobalLimit(t *testing.T) {
+	b := GossipBudget{}
+	b.Clock.Set(time.Unix(0, 0))
+	b.Initialize(100, 100, 100, 100)
+
+	peer0 := ids.NewShortID([20]byte{0})
+	peer1 := ids.NewShortID([20]byte{1})
+
+	if !b.Spend(peer0, 60) {
+		t.Fatalf("Gossip within the burst should have been allowed")
+	}
+	if b.Spend(peer1, 60) {
+		t.Fatalf("Gossip beyond the global budget should have been skipped")
+	}
+	if !b.Spend(peer1, 40) {
+		t.Fatalf("Gossip within the global budget should have been allowed")
+	}
+
+	b.Clock.Set(time.Unix(0, int64(500*time.Millisecond)))
+	if !b.Spend(peer1, 50) {
+		t.Fatalf("The global budget should have been partially refilled")
+	}
+	if b.Spend(peer0, 1) {
+		t.Fatalf("The global budget should have been exhausted")
+	}
+}
+
+func TestGossipBudgetUnlimited(t *testing.T) {
+	b := GossipBudget{}
+	b.Initialize(0, 0, 0, 0)
+
+	for i := 0; i < 10; i++ {
+		if !b.Spend(ids.NewShortID([20]byte{byte(i)}), 1<<30) {
+			t.Fatalf("Gossip should be unlimited when the rates are 0")
+		}
+	}
+}
+
+func TestGossipBudgetPrunesFullBuckets(t *testing.T) {
+	b := GossipBudget{}
+	b.Clock.Set(time.Unix(0, 0))
+	b.Initialize(0, 0, 100, 100)
+
+	peerID := func(i int) ids.ShortID {
+		return ids.NewShortID([20]byte{byte(i), byte(i >> 8)})
+	}
+
+	for i := 0; i < maxIdlePeerBuckets; i++ {
+		b.Spend(peerID(i), 100)
+	}
+
+	b.Clock.Set(time.Unix(1, 0))
+	if !b.Spend(peerID(maxIdlePeerBuckets), 100) {
+		t.Fatalf("Gossip within the burst should have been allowed")
+	}
+	if len(b.peers) != 1 {
+		t.Fatalf("The refilled buckets should have been pruned, %d buckets remain", len(b.peers))
+	}
+}
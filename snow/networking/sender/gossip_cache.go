@@ -0,0 +1,110 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package sender
+
+import (
+	"container/list"
+	"sync"
+	"time"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/timer"
+)
+
+// GossipCache remembers which containers were recently gossiped to which peers,
+// so that a container isn't gossiped to the same peer repeatedly.
+//
+// Entries expire after the cache's window. If more than the cache's maximum
+// number of entries are added within a window, the oldest entries are evicted
+// early.
+type GossipCache struct {
+	Clock timer.Clock
+
+	lock sync.Mutex
+
+	window     time.Duration
+	maxEntries int
+
+	// (peer ID, container ID) --> element in [order] holding a *gossipEntry
+	entries map[[52]byte]*list.Element
+	// The entries, from the oldest to the most recently sent
+	order *list.List
+}
+
+type gossipEntry struct {
+	key  [52]byte
+	sent time.Time
+}
+
+// Initialize the cache to remember gossip for [window], and to hold at most
+// [maxEntries] entries. A window of 0 disables the cache.
+func (c *GossipCache) Initialize(window time.Duration, maxEntries int) {
+	c.window = window
+	c.maxEntries = maxEntries
+	c.entries = make(map[[52]byte]*list.Element)
+	c.order = list.New()
+}
+
+// RecentlySent returns true if [containerID] was gossiped to [peerID] within
+// the window
+func (c *GossipCache) RecentlySent(peerID ids.ShortID, containerID ids.ID) bool {
+	if c.window == 0 {
+		return false
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.expire(c.Clock.Time())
+	_, exists := c.entries[gossipKey(peerID, containerID)]
+	return exists
+}
+
+// Sent records that [containerID] was gossiped to [peerID]
+func (c *GossipCache) Sent(peerID ids.ShortID, containerID ids.ID) {
+	if c.window == 0 {
+		return
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	now := c.Clock.Time()
+	c.expire(now)
+
+	key := gossipKey(peerID, containerID)
+	if elem, exists := c.entries[key]; exists {
+		c.order.Remove(elem)
+	}
+	c.entries[key] = c.order.PushBack(&gossipEntry{
+		key:  key,
+		sent: now,
+	})
+
+	for c.order.Len() > c.maxEntries {
+		c.remove(c.order.Front())
+	}
+}
+
+// expire removes the entries sent before the window
+func (c *GossipCache) expire(now time.Time) {
+	for elem := c.order.Front(); elem != nil; elem = c.order.Front() {
+		if now.Sub(elem.Value.(*gossipEntry).sent) < c.window {
+			return
+		}
+		c.remove(elem)
+	}
+}
+
+func (c *GossipCache) remove(elem *list.Element) {
+	delete(c.entries, elem.Value.(*gossipEntry).key)
+	c.order.Remove(elem)
+}
+
+func gossipKey(peerID ids.ShortID, containerID ids.ID) [52]byte {
+	key := [52]byte{}
+	copy(key[:20], peerID.Bytes())
+	copy(key[20:], containerID.Bytes())
+	return key
+}
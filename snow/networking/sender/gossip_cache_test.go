@@ -0,0 +1,98 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package sender
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ava-labs/gecko/ids"
+)
+
+func TestGossipCacheWindow(t *testing.T) {
+	c := GossipCache{}
+	c.Clock.Set(time.Unix(0, 0))
+	c.Initialize(time.Minute, 10)
+
+	peer0 := ids.NewShortID([20]byte{0})
+	peer1 := ids.NewShortID([20]byte{1})
+	container := ids.NewID([32]byte{1})
+
+	if c.RecentlySent(peer0, container) {
+		t.Fatalf("Nothing has been sent yet")
+	}
+	c.Sent(peer0, container)
+	if !c.RecentlySent(peer0, container) {
+		t.Fatalf("The container was just sent to the peer")
+	}
+	if c.RecentlySent(peer1, container) {
+		t.Fatalf("The container wasn't sent to the other peer")
+	}
+	if c.RecentlySent(peer0, ids.NewID([32]byte{2})) {
+		t.Fatalf("The other container wasn't sent to the peer")
+	}
+
+	c.Clock.Set(time.Unix(59, 0))
+	if !c.RecentlySent(peer0, container) {
+		t.Fatalf("The container was sent within the window")
+	}
+
+	c.Clock.Set(time.Unix(60, 0))
+	if c.RecentlySent(peer0, container) {
+		t.Fatalf("The entry should have expired")
+	}
+}
+
+func TestGossipCacheResend(t *testing.T) {
+	c := GossipCache{}
+	c.Clock.Set(time.Unix(0, 0))
+	c.Initialize(time.Minute, 10)
+
+	peer := ids.NewShortID([20]byte{0})
+	container := ids.NewID([32]byte{1})
+
+	c.Sent(peer, container)
+	c.Clock.Set(time.Unix(30, 0))
+	c.Sent(peer, container)
+
+	c.Clock.Set(time.Unix(60, 0))
+	if !c.RecentlySent(peer, container) {
+		t.Fatalf("Resending should have restarted the window")
+	}
+}
+
+func TestGossipCacheMaxEntries(t *testing.T) {
+	c := GossipCache{}
+	c.Clock.Set(time.Unix(0, 0))
+	c.Initialize(time.Minute, 2)
+
+	peer := ids.NewShortID([20]byte{0})
+	container0 := ids.NewID([32]byte{0})
+	container1 := ids.NewID([32]byte{1})
+	container2 := ids.NewID([32]byte{2})
+
+	c.Sent(peer, container0)
+	c.Sent(peer, container1)
+	c.Sent(peer, container2)
+
+	if c.RecentlySent(peer, container0) {
+		t.Fatalf("The oldest entry should have been evicted")
+	}
+	if !c.RecentlySent(peer, container1) || !c.RecentlySent(peer, container2) {
+		t.Fatalf("The newest entries should have been kept")
+	}
+}
+
+func TestGossipCacheDisabled(t *testing.T) {
+	c := GossipCache{}
+	c.Initialize(0, 10)
+
+	peer := ids.NewShortID([20]byte{0})
+	container := ids.NewID([32]byte{1})
+
+	c.Sent(peer, container)
+	if c.RecentlySent(peer, container) {
+		t.Fatalf("A disabled cache shouldn't remember anything")
+	}
+}
@@ -4,8 +4,11 @@
 package sender
 
 import (
+	"context"
+
 	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/snow"
+	"github.com/ava-labs/gecko/snow/networking"
 	"github.com/ava-labs/gecko/snow/networking/router"
 	"github.com/ava-labs/gecko/snow/networking/timeout"
 )
@@ -16,14 +19,32 @@ type Sender struct {
 	sender   ExternalSender // Actually does the sending over the network
 	router   router.Router
 	timeouts *timeout.Manager
+
+	// Peers that don't answer requests in time are penalized
+	reputation *networking.Reputation
+
+	// shutdown is cancelled when the chain starts shutting down. Once it is,
+	// messages are dropped rather than sent.
+	shutdown context.Context
 }
 
 // Initialize this sender
-func (s *Sender) Initialize(ctx *snow.Context, sender ExternalSender, router router.Router, timeouts *timeout.Manager) {
+func (s *Sender) Initialize(ctx *snow.Context, sender ExternalSender, router router.Router, timeouts *timeout.Manager, reputation *networking.Reputation, shutdown context.Context) {
 	s.ctx = ctx
 	s.sender = sender
 	s.router = router
 	s.timeouts = timeouts
+	s.reputation = reputation
+	s.shutdown = shutdown
+}
+
+// shuttingDown returns true if messages should no longer be sent
+func (s *Sender) shuttingDown() bool {
+	if s.shutdown.Err() == nil {
+		return false
+	}
+	s.ctx.Log.Verbo("Dropping message due to shutdown")
+	return true
 }
 
 // Context of this sender
@@ -31,6 +52,9 @@ func (s *Sender) Context() *snow.Context { return s.ctx }
 
 // GetAcceptedFrontier ...
 func (s *Sender) GetAcceptedFrontier(validatorIDs ids.ShortSet, requestID uint32) {
+	if s.shuttingDown() {
+		return
+	}
 	if validatorIDs.Contains(s.ctx.NodeID) {
 		validatorIDs.Remove(s.ctx.NodeID)
 		go s.router.GetAcceptedFrontier(s.ctx.NodeID, s.ctx.ChainID, requestID)
@@ -39,6 +63,7 @@ func (s *Sender) GetAcceptedFrontier(validatorIDs ids.ShortSet, requestID uint32
 	for _, validatorID := range validatorList {
 		vID := validatorID
 		s.timeouts.Register(validatorID, s.ctx.ChainID, requestID, func() {
+			s.reputation.Penalize(vID, networking.TimeoutPenalty)
 			s.router.GetAcceptedFrontierFailed(vID, s.ctx.ChainID, requestID)
 		})
 	}
@@ -47,6 +72,9 @@ func (s *Sender) GetAcceptedFrontier(validatorIDs ids.ShortSet, requestID uint32
 
 // AcceptedFrontier ...
 func (s *Sender) AcceptedFrontier(validatorID ids.ShortID, requestID uint32, containerIDs ids.Set) {
+	if s.shuttingDown() {
+		return
+	}
 	if validatorID.Equals(s.ctx.NodeID) {
 		go s.router.AcceptedFrontier(validatorID, s.ctx.ChainID, requestID, containerIDs)
 		return
@@ -56,6 +84,9 @@ func (s *Sender) AcceptedFrontier(validatorID ids.ShortID, requestID uint32, con
 
 // GetAccepted ...
 func (s *Sender) GetAccepted(validatorIDs ids.ShortSet, requestID uint32, containerIDs ids.Set) {
+	if s.shuttingDown() {
+		return
+	}
 	if validatorIDs.Contains(s.ctx.NodeID) {
 		validatorIDs.Remove(s.ctx.NodeID)
 		go s.router.GetAccepted(s.ctx.NodeID, s.ctx.ChainID, requestID, containerIDs)
@@ -64,6 +95,7 @@ func (s *Sender) GetAccepted(validatorIDs ids.ShortSet, requestID uint32, contai
 	for _, validatorID := range validatorList {
 		vID := validatorID
 		s.timeouts.Register(validatorID, s.ctx.ChainID, requestID, func() {
+			s.reputation.Penalize(vID, networking.TimeoutPenalty)
 			s.router.GetAcceptedFailed(vID, s.ctx.ChainID, requestID)
 		})
 	}
@@ -72,6 +104,9 @@ func (s *Sender) GetAccepted(validatorIDs ids.ShortSet, requestID uint32, contai
 
 // Accepted ...
 func (s *Sender) Accepted(validatorID ids.ShortID, requestID uint32, containerIDs ids.Set) {
+	if s.shuttingDown() {
+		return
+	}
 	if validatorID.Equals(s.ctx.NodeID) {
 		go s.router.Accepted(validatorID, s.ctx.ChainID, requestID, containerIDs)
 		return
@@ -79,15 +114,60 @@ func (s *Sender) Accepted(validatorID ids.ShortID, requestID uint32, containerID
 	s.sender.Accepted(validatorID, s.ctx.ChainID, requestID, containerIDs)
 }
 
+// GetStateSummary ...
+func (s *Sender) GetStateSummary(validatorIDs ids.ShortSet, requestID uint32) {
+	if s.shuttingDown() {
+		return
+	}
+	if validatorIDs.Contains(s.ctx.NodeID) {
+		validatorIDs.Remove(s.ctx.NodeID)
+		go s.router.GetStateSummary(s.ctx.NodeID, s.ctx.ChainID, requestID)
+	}
+	validatorList := validatorIDs.List()
+	for _, validatorID := range validatorList {
+		vID := validatorID
+		s.timeouts.Register(validatorID, s.ctx.ChainID, requestID, func() {
+			s.reputation.Penalize(vID, networking.TimeoutPenalty)
+			s.router.GetStateSummaryFailed(vID, s.ctx.ChainID, requestID)
+		})
+	}
+	s.sender.GetStateSummary(validatorIDs, s.ctx.ChainID, requestID)
+}
+
+// StateSummary ...
+func (s *Sender) StateSummary(validatorID ids.ShortID, requestID uint32, summary []byte) {
+	if s.shuttingDown() {
+		return
+	}
+	if validatorID.Equals(s.ctx.NodeID) {
+		go s.router.StateSummary(validatorID, s.ctx.ChainID, requestID, summary)
+		return
+	}
+	s.sender.StateSummary(validatorID, s.ctx.ChainID, requestID, summary)
+}
+
+// GossipFrontier sends this chain's accepted frontier to a sample of peers
+func (s *Sender) GossipFrontier(containerIDs ids.Set) {
+	if s.shuttingDown() {
+		return
+	}
+	s.ctx.Log.Verbo("Gossiping accepted frontier %s", containerIDs)
+	s.sender.GossipFrontier(s.ctx.ChainID, containerIDs)
+}
+
 // Get sends a Get message to the consensus engine running on the specified
 // chain to the specified validator. The Get message signifies that this
 // consensus engine would like the recipient to send this consensus engine the
 // specified container.
 func (s *Sender) Get(validatorID ids.ShortID, requestID uint32, containerID ids.ID) {
+	if s.shuttingDown() {
+		return
+	}
 	s.ctx.Log.Verbo("Sending Get to validator %s. RequestID: %d. ContainerID: %s", validatorID, requestID, containerID)
 	// Add a timeout -- if we don't get a response before the timeout expires,
 	// send this consensus engine a GetFailed message
 	s.timeouts.Register(validatorID, s.ctx.ChainID, requestID, func() {
+		s.reputation.Penalize(validatorID, networking.TimeoutPenalty)
 		s.router.GetFailed(validatorID, s.ctx.ChainID, requestID, containerID)
 	})
 	s.sender.Get(validatorID, s.ctx.ChainID, requestID, containerID)
@@ -98,6 +178,9 @@ func (s *Sender) Get(validatorID ids.ShortID, requestID uint32, containerID ids.
 // The Put message signifies that this consensus engine is giving to the recipient
 // the contents of the specified container.
 func (s *Sender) Put(validatorID ids.ShortID, requestID uint32, containerID ids.ID, container []byte) {
+	if s.shuttingDown() {
+		return
+	}
 	s.ctx.Log.Verbo("Sending Put to validator %s. RequestID: %d. ContainerID: %s", validatorID, requestID, containerID)
 	s.sender.Put(validatorID, s.ctx.ChainID, requestID, containerID, container)
 }
@@ -107,6 +190,9 @@ func (s *Sender) Put(validatorID ids.ShortID, requestID uint32, containerID ids.
 // The PushQuery message signifies that this consensus engine would like each validator to send
 // their preferred frontier given the existence of the specified container.
 func (s *Sender) PushQuery(validatorIDs ids.ShortSet, requestID uint32, containerID ids.ID, container []byte) {
+	if s.shuttingDown() {
+		return
+	}
 	s.ctx.Log.Verbo("Sending PushQuery to validators %v. RequestID: %d. ContainerID: %s", validatorIDs, requestID, containerID)
 	// If one of the validators in [validatorIDs] is myself, send this message directly
 	// to my own router rather than sending it over the network
@@ -121,6 +207,7 @@ func (s *Sender) PushQuery(validatorIDs ids.ShortSet, requestID uint32, containe
 	for _, validatorID := range validatorList {
 		vID := validatorID
 		s.timeouts.Register(validatorID, s.ctx.ChainID, requestID, func() {
+			s.reputation.Penalize(vID, networking.FailedQueryPenalty)
 			s.router.QueryFailed(vID, s.ctx.ChainID, requestID)
 		})
 	}
@@ -132,6 +219,9 @@ func (s *Sender) PushQuery(validatorIDs ids.ShortSet, requestID uint32, containe
 // The PullQuery message signifies that this consensus engine would like each validator to send
 // their preferred frontier.
 func (s *Sender) PullQuery(validatorIDs ids.ShortSet, requestID uint32, containerID ids.ID) {
+	if s.shuttingDown() {
+		return
+	}
 	s.ctx.Log.Verbo("Sending PullQuery. RequestID: %d. ContainerID: %s", requestID, containerID)
 	// If one of the validators in [validatorIDs] is myself, send this message directly
 	// to my own router rather than sending it over the network
@@ -146,6 +236,7 @@ func (s *Sender) PullQuery(validatorIDs ids.ShortSet, requestID uint32, containe
 	for _, validatorID := range validatorList {
 		vID := validatorID
 		s.timeouts.Register(validatorID, s.ctx.ChainID, requestID, func() {
+			s.reputation.Penalize(vID, networking.FailedQueryPenalty)
 			s.router.QueryFailed(vID, s.ctx.ChainID, requestID)
 		})
 	}
@@ -154,6 +245,9 @@ func (s *Sender) PullQuery(validatorIDs ids.ShortSet, requestID uint32, containe
 
 // Chits sends chits
 func (s *Sender) Chits(validatorID ids.ShortID, requestID uint32, votes ids.Set) {
+	if s.shuttingDown() {
+		return
+	}
 	s.ctx.Log.Verbo("Sending Chits to validator %s. RequestID: %d. Votes: %s", validatorID, requestID, votes)
 	// If [validatorID] is myself, send this message directly
 	// to my own router rather than sending it over the network
@@ -4,13 +4,17 @@
 package sender
 
 import (
+	"context"
 	"sync"
 	"testing"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
+
 	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/snow"
 	"github.com/ava-labs/gecko/snow/engine/common"
+	"github.com/ava-labs/gecko/snow/networking"
 	"github.com/ava-labs/gecko/snow/networking/handler"
 	"github.com/ava-labs/gecko/snow/networking/router"
 	"github.com/ava-labs/gecko/snow/networking/timeout"
@@ -26,7 +30,7 @@ func TestTimeout(t *testing.T) {
 	router.Initialize(logging.NoLog{}, &tm)
 
 	sender := Sender{}
-	sender.Initialize(snow.DefaultContextTest(), &ExternalSenderTest{}, &router, &tm)
+	sender.Initialize(snow.DefaultContextTest(), &ExternalSenderTest{}, &router, &tm, &networking.Reputation{}, context.Background())
 
 	engine := common.EngineTest{T: t}
 	engine.Default(true)
@@ -43,7 +47,7 @@ func TestTimeout(t *testing.T) {
 	}
 
 	handler := handler.Handler{}
-	handler.Initialize(&engine, nil, 1)
+	handler.Initialize(&engine, nil, 1, 0, 0, nil, "", prometheus.NewRegistry())
 	go handler.Dispatch()
 
 	router.AddChain(&handler)
@@ -60,3 +64,34 @@ func TestTimeout(t *testing.T) {
 		t.Fatalf("Timeouts should have fired")
 	}
 }
+
+func TestSenderDropsMessagesAfterShutdown(t *testing.T) {
+	tm := timeout.Manager{}
+	tm.Initialize(time.Hour)
+
+	router := router.ChainRouter{}
+	router.Initialize(logging.NoLog{}, &tm)
+
+	externalSender := &ExternalSenderTest{T: t}
+	externalSender.Default(true)
+
+	shutdown, cancel := context.WithCancel(context.Background())
+
+	sender := Sender{}
+	sender.Initialize(snow.DefaultContextTest(), externalSender, &router, &tm, &networking.Reputation{}, shutdown)
+
+	vdrID := ids.NewShortID([20]byte{255})
+	sent := false
+	externalSender.GetF = func(ids.ShortID, ids.ID, uint32, ids.ID) { sent = true }
+
+	sender.Get(vdrID, 0, ids.Empty)
+	if !sent {
+		t.Fatalf("Should have sent the message before shutdown")
+	}
+
+	cancel()
+	externalSender.GetF = nil
+
+	// The external sender fails the test if it is called
+	sender.Get(vdrID, 1, ids.Empty)
+}
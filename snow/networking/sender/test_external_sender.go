@@ -16,6 +16,8 @@ type ExternalSenderTest struct {
 
 	CantGetAcceptedFrontier, CantAcceptedFrontier,
 	CantGetAccepted, CantAccepted,
+	CantGetStateSummary, CantStateSummary,
+	CantGossipFrontier,
 	CantGet, CantPut,
 	CantPullQuery, CantPushQuery, CantChits bool
 
@@ -23,6 +25,9 @@ type ExternalSenderTest struct {
 	AcceptedFrontierF    func(validatorID ids.ShortID, chainID ids.ID, requestID uint32, containerIDs ids.Set)
 	GetAcceptedF         func(validatorIDs ids.ShortSet, chainID ids.ID, requestID uint32, containerIDs ids.Set)
 	AcceptedF            func(validatorID ids.ShortID, chainID ids.ID, requestID uint32, containerIDs ids.Set)
+	GetStateSummaryF     func(validatorIDs ids.ShortSet, chainID ids.ID, requestID uint32)
+	StateSummaryF        func(validatorID ids.ShortID, chainID ids.ID, requestID uint32, summary []byte)
+	GossipFrontierF      func(chainID ids.ID, containerIDs ids.Set)
 	GetF                 func(validatorID ids.ShortID, chainID ids.ID, requestID uint32, containerID ids.ID)
 	PutF                 func(validatorID ids.ShortID, chainID ids.ID, requestID uint32, containerID ids.ID, container []byte)
 	PushQueryF           func(validatorIDs ids.ShortSet, chainID ids.ID, requestID uint32, containerID ids.ID, container []byte)
@@ -36,6 +41,9 @@ func (s *ExternalSenderTest) Default(cant bool) {
 	s.CantAcceptedFrontier = cant
 	s.CantGetAccepted = cant
 	s.CantAccepted = cant
+	s.CantGetStateSummary = cant
+	s.CantStateSummary = cant
+	s.CantGossipFrontier = cant
 	s.CantGet = cant
 	s.CantPut = cant
 	s.CantPullQuery = cant
@@ -95,6 +103,45 @@ func (s *ExternalSenderTest) Accepted(validatorID ids.ShortID, chainID ids.ID, r
 	}
 }
 
+// GetStateSummary calls GetStateSummaryF if it was initialized. If it wasn't
+// initialized and this function shouldn't be called and testing was
+// initialized, then testing will fail.
+func (s *ExternalSenderTest) GetStateSummary(validatorIDs ids.ShortSet, chainID ids.ID, requestID uint32) {
+	if s.GetStateSummaryF != nil {
+		s.GetStateSummaryF(validatorIDs, chainID, requestID)
+	} else if s.CantGetStateSummary && s.T != nil {
+		s.T.Fatalf("Unexpectedly called GetStateSummary")
+	} else if s.CantGetStateSummary && s.B != nil {
+		s.B.Fatalf("Unexpectedly called GetStateSummary")
+	}
+}
+
+// StateSummary calls StateSummaryF if it was initialized. If it wasn't
+// initialized and this function shouldn't be called and testing was
+// initialized, then testing will fail.
+func (s *ExternalSenderTest) StateSummary(validatorID ids.ShortID, chainID ids.ID, requestID uint32, summary []byte) {
+	if s.StateSummaryF != nil {
+		s.StateSummaryF(validatorID, chainID, requestID, summary)
+	} else if s.CantStateSummary && s.T != nil {
+		s.T.Fatalf("Unexpectedly called StateSummary")
+	} else if s.CantStateSummary && s.B != nil {
+		s.B.Fatalf("Unexpectedly called StateSummary")
+	}
+}
+
+// GossipFrontier calls GossipFrontierF if it was initialized. If it wasn't
+// initialized and this function shouldn't be called and testing was
+// initialized, then testing will fail.
+func (s *ExternalSenderTest) GossipFrontier(chainID ids.ID, containerIDs ids.Set) {
+	if s.GossipFrontierF != nil {
+		s.GossipFrontierF(chainID, containerIDs)
+	} else if s.CantGossipFrontier && s.T != nil {
+		s.T.Fatalf("Unexpectedly called GossipFrontier")
+	} else if s.CantGossipFrontier && s.B != nil {
+		s.B.Fatalf("Unexpectedly called GossipFrontier")
+	}
+}
+
 // Get calls GetF if it was initialized. If it wasn't initialized and this
 // function shouldn't be called and testing was initialized, then testing will
 // fail.
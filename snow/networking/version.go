@@ -0,0 +1,171 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package networking
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/timer"
+)
+
+var (
+	errMalformedVersion    = errors.New("version must be of the form <app>/<major>.<minor>.<patch>")
+	errDifferentApp        = errors.New("peer runs a different application")
+	errVersionTooOld       = errors.New("peer's version is too old")
+	errVersionTooNew       = errors.New("peer's major version is newer than this node's")
+	errMissingFeatures     = errors.New("peer doesn't support the required features")
+	errUpgradeWindowClosed = errors.New("the upgrade window closed")
+)
+
+// Version of the node software, announced in version messages
+type Version struct {
+	App                 string
+	Major, Minor, Patch int
+}
+
+// ParseVersion parses a version of the form "avalanche/1.2.3"
+func ParseVersion(s string) (Version, error) {
+	slash := strings.Index(s, "/")
+	if slash <= 0 {
+		return Version{}, fmt.Errorf("%w: %q", errMalformedVersion, s)
+	}
+	numbers := strings.Split(s[slash+1:], ".")
+	if len(numbers) != 3 {
+		return Version{}, fmt.Errorf("%w: %q", errMalformedVersion, s)
+	}
+	parsed := [3]int{}
+	for i, number := range numbers {
+		n, err := strconv.Atoi(number)
+		if err != nil || n < 0 {
+			return Version{}, fmt.Errorf("%w: %q", errMalformedVersion, s)
+		}
+		parsed[i] = n
+	}
+	return Version{
+		App:   s[:slash],
+		Major: parsed[0],
+		Minor: parsed[1],
+		Patch: parsed[2],
+	}, nil
+}
+
+func (v Version) String() string {
+	return fmt.Sprintf("%s/%d.%d.%d", v.App, v.Major, v.Minor, v.Patch)
+}
+
+// Before returns true if [v] is an older release than [o]. The apps aren't
+// compared.
+func (v Version) Before(o Version) bool {
+	switch {
+	case v.Major != o.Major:
+		return v.Major < o.Major
+	case v.Minor != o.Minor:
+		return v.Minor < o.Minor
+	default:
+		return v.Patch < o.Patch
+	}
+}
+
+// Features is a set of optional protocol features, as bits
+type Features uint64
+
+// Has returns true if [f] includes every feature in [o]
+func (f Features) Has(o Features) bool { return f&o == o }
+
+// Compatibility decides which peers this node can talk to, from the versions
+// and features they announce in version messages.
+//
+// When a release stops being compatible with the one before it, nodes
+// upgrade one at a time. Peers that run the previous release stay compatible
+// until UpgradeTime, so the network isn't split while nodes upgrade.
+type Compatibility struct {
+	// Current is the version of this node
+	Current Version
+
+	// Peers older than MinCompatible, or without all of RequiredFeatures, are
+	// incompatible
+	MinCompatible    Version
+	RequiredFeatures Features
+
+	// Before UpgradeTime, peers that are at least PrevMinCompatible, and have
+	// all of PrevRequiredFeatures, are also compatible. If UpgradeTime is
+	// zero, there is no upgrade window.
+	UpgradeTime          time.Time
+	PrevMinCompatible    Version
+	PrevRequiredFeatures Features
+
+	clock timer.Clock
+}
+
+// Compatible returns nil if a peer running [peer] and supporting [features]
+// is compatible with this node. Otherwise, it returns why not.
+func (c *Compatibility) Compatible(peer Version, features Features) error {
+	switch {
+	case peer.App != c.Current.App:
+		return fmt.Errorf("%w: %s", errDifferentApp, peer.App)
+	case peer.Major > c.Current.Major:
+		return fmt.Errorf("%w: %s", errVersionTooNew, peer)
+	}
+
+	err := compatible(peer, features, c.MinCompatible, c.RequiredFeatures)
+	if err == nil || c.UpgradeTime.IsZero() {
+		return err
+	}
+	if !c.clock.Time().Before(c.UpgradeTime) {
+		return fmt.Errorf("%w at %s: %s", errUpgradeWindowClosed, c.UpgradeTime, err)
+	}
+	return compatible(peer, features, c.PrevMinCompatible, c.PrevRequiredFeatures)
+}
+
+func compatible(peer Version, features Features, min Version, required Features) error {
+	switch {
+	case peer.Before(min):
+		return fmt.Errorf("%w: %s is older than %s", errVersionTooOld, peer, min)
+	case !features.Has(required):
+		return fmt.Errorf("%w: %b", errMissingFeatures, required&^features)
+	default:
+		return nil
+	}
+}
+
+// PeerFeatures tracks the features each peer announced it supports during the
+// version handshake
+type PeerFeatures struct {
+	lock     sync.Mutex
+	features map[[20]byte]Features
+}
+
+// Add records that [peerID] supports [features]
+func (pf *PeerFeatures) Add(peerID ids.ShortID, features Features) {
+	pf.lock.Lock()
+	defer pf.lock.Unlock()
+
+	if pf.features == nil {
+		pf.features = make(map[[20]byte]Features)
+	}
+	pf.features[peerID.Key()] = features
+}
+
+// Remove the features recorded for [peerID]
+func (pf *PeerFeatures) Remove(peerID ids.ShortID) {
+	pf.lock.Lock()
+	defer pf.lock.Unlock()
+
+	delete(pf.features, peerID.Key())
+}
+
+// Supports returns true if [peerID] supports every feature in [features]. If
+// nothing was recorded for [peerID], it supports no features.
+func (pf *PeerFeatures) Supports(peerID ids.ShortID, features Features) bool {
+	pf.lock.Lock()
+	defer pf.lock.Unlock()
+
+	return pf.features[peerID.Key()].Has(features)
+}
@@ -0,0 +1,130 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package networking
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/gecko/ids"
+)
+
+func TestParseVersion(t *testing.T) {
+	v, err := ParseVersion("avalanche/1.2.3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := (Version{App: "avalanche", Major: 1, Minor: 2, Patch: 3}); v != expected {
+		t.Fatalf("Expected %s but got %s", expected, v)
+	}
+	if s := v.String(); s != "avalanche/1.2.3" {
+		t.Fatalf("Expected avalanche/1.2.3 but got %s", s)
+	}
+
+	for _, s := range []string{"", "avalanche", "/1.2.3", "avalanche/1.2", "avalanche/1.2.x", "avalanche/1.-2.3"} {
+		if _, err := ParseVersion(s); !errors.Is(err, errMalformedVersion) {
+			t.Fatalf("Expected %q to be malformed but got %v", s, err)
+		}
+	}
+}
+
+func TestVersionBefore(t *testing.T) {
+	older := Version{App: "avalanche", Major: 1, Minor: 2, Patch: 3}
+	for _, newer := range []Version{
+		{App: "avalanche", Major: 2},
+		{App: "avalanche", Major: 1, Minor: 3},
+		{App: "avalanche", Major: 1, Minor: 2, Patch: 4},
+	} {
+		if !older.Before(newer) {
+			t.Fatalf("%s should be before %s", older, newer)
+		}
+		if newer.Before(older) {
+			t.Fatalf("%s shouldn't be before %s", newer, older)
+		}
+	}
+	if older.Before(older) {
+		t.Fatalf("A version shouldn't be before itself")
+	}
+}
+
+func TestCompatibility(t *testing.T) {
+	c := Compatibility{
+		Current:          Version{App: "avalanche", Major: 1, Minor: 2},
+		MinCompatible:    Version{App: "avalanche", Major: 1, Minor: 1},
+		RequiredFeatures: 1,
+	}
+
+	if err := c.Compatible(Version{App: "avalanche", Major: 1, Minor: 1}, 3); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Compatible(Version{App: "avalanche", Major: 1, Minor: 5}, 1); err != nil {
+		t.Fatalf("Newer minor versions should be compatible but got %s", err)
+	}
+	if err := c.Compatible(Version{App: "other", Major: 1, Minor: 2}, 1); !errors.Is(err, errDifferentApp) {
+		t.Fatalf("Expected %s but got %v", errDifferentApp, err)
+	}
+	if err := c.Compatible(Version{App: "avalanche", Major: 2}, 1); !errors.Is(err, errVersionTooNew) {
+		t.Fatalf("Expected %s but got %v", errVersionTooNew, err)
+	}
+	if err := c.Compatible(Version{App: "avalanche", Major: 1}, 1); !errors.Is(err, errVersionTooOld) {
+		t.Fatalf("Expected %s but got %v", errVersionTooOld, err)
+	}
+	if err := c.Compatible(Version{App: "avalanche", Major: 1, Minor: 2}, 2); !errors.Is(err, errMissingFeatures) {
+		t.Fatalf("Expected %s but got %v", errMissingFeatures, err)
+	}
+}
+
+func TestCompatibilityUpgradeWindow(t *testing.T) {
+	upgradeTime := time.Unix(1000000, 0)
+	c := Compatibility{
+		Current:           Version{App: "avalanche", Major: 1, Minor: 2},
+		MinCompatible:     Version{App: "avalanche", Major: 1, Minor: 2},
+		RequiredFeatures:  1,
+		UpgradeTime:       upgradeTime,
+		PrevMinCompatible: Version{App: "avalanche", Major: 1, Minor: 1},
+	}
+	previous := Version{App: "avalanche", Major: 1, Minor: 1}
+
+	c.clock.Set(upgradeTime.Add(-time.Second))
+	if err := c.Compatible(previous, 0); err != nil {
+		t.Fatalf("The previous release should be compatible during the upgrade window but got %s", err)
+	}
+	if err := c.Compatible(Version{App: "avalanche", Major: 1}, 0); !errors.Is(err, errVersionTooOld) {
+		t.Fatalf("Expected %s but got %v", errVersionTooOld, err)
+	}
+
+	c.clock.Set(upgradeTime)
+	if err := c.Compatible(previous, 0); !errors.Is(err, errUpgradeWindowClosed) {
+		t.Fatalf("Expected %s but got %v", errUpgradeWindowClosed, err)
+	}
+	if err := c.Compatible(c.Current, 1); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPeerFeatures(t *testing.T) {
+	pf := PeerFeatures{}
+	peer := ids.NewShortID([20]byte{1})
+
+	if pf.Supports(peer, 1) {
+		t.Fatalf("An unknown peer shouldn't support any features")
+	}
+	if !pf.Supports(peer, 0) {
+		t.Fatalf("Every peer supports no features")
+	}
+
+	pf.Add(peer, 3)
+	if !pf.Supports(peer, 1) || !pf.Supports(peer, 3) {
+		t.Fatalf("The peer should support the features it announced")
+	}
+	if pf.Supports(peer, 4) {
+		t.Fatalf("The peer shouldn't support features it didn't announce")
+	}
+
+	pf.Remove(peer)
+	if pf.Supports(peer, 1) {
+		t.Fatalf("A removed peer shouldn't support any features")
+	}
+}
@@ -5,11 +5,13 @@ package validators
 
 import (
 	"fmt"
+	stdmath "math"
 	"strings"
 	"sync"
 
 	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/utils/formatting"
+	"github.com/ava-labs/gecko/utils/math"
 	"github.com/ava-labs/gecko/utils/random"
 )
 
@@ -27,6 +29,10 @@ type Set interface {
 	// Remove the validator with the specified ID.
 	Remove(ids.ShortID)
 
+	// Get returns the validator with the specified ID, if it is currently in
+	// the set.
+	Get(ids.ShortID) (Validator, bool)
+
 	// Contains returns true if there is a validator with the specified ID
 	// currently in the set.
 	Contains(ids.ShortID) bool
@@ -34,6 +40,10 @@ type Set interface {
 	// Len returns the number of validators currently in the set.
 	Len() int
 
+	// Weight returns the cumulative weight of all validators in the set. If
+	// the sum overflows, the max uint64 is returned.
+	Weight() uint64
+
 	// List all the ids of validators in this group
 	List() []Validator
 
@@ -134,6 +144,22 @@ func (s *set) remove(vdrID ids.ShortID) {
 	s.sampler.Weights = s.sampler.Weights[:e]
 }
 
+// Get implements the Set interface.
+func (s *set) Get(vdrID ids.ShortID) (Validator, bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.get(vdrID)
+}
+
+func (s *set) get(vdrID ids.ShortID) (Validator, bool) {
+	i, contains := s.vdrMap[vdrID.Key()]
+	if !contains {
+		return nil, false
+	}
+	return s.vdrSlice[i], true
+}
+
 // Contains implements the Set interface.
 func (s *set) Contains(vdrID ids.ShortID) bool {
 	s.lock.Lock()
@@ -157,6 +183,26 @@ func (s *set) Len() int {
 
 func (s *set) len() int { return len(s.vdrSlice) }
 
+// Weight implements the Set interface.
+func (s *set) Weight() uint64 {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.weight()
+}
+
+func (s *set) weight() uint64 {
+	weight := uint64(0)
+	for _, w := range s.sampler.Weights {
+		newWeight, err := math.Add64(weight, w)
+		if err != nil {
+			return stdmath.MaxUint64
+		}
+		weight = newWeight
+	}
+	return weight
+}
+
 // List implements the Group interface.
 func (s *set) List() []Validator {
 	s.lock.Lock()
@@ -126,6 +126,59 @@ func TestSamplerContains(t *testing.T) {
 	}
 }
 
+func TestSamplerGet(t *testing.T) {
+	vdr := GenerateRandomValidator(3)
+
+	s := NewSet()
+	if _, ok := s.Get(vdr.ID()); ok {
+		t.Fatalf("Shouldn't have found validator")
+	}
+
+	s.Add(vdr)
+
+	if got, ok := s.Get(vdr.ID()); !ok {
+		t.Fatalf("Should have found validator")
+	} else if !got.ID().Equals(vdr.ID()) || got.Weight() != 3 {
+		t.Fatalf("Returned the wrong validator")
+	}
+}
+
+func TestSamplerWeight(t *testing.T) {
+	vdr0 := GenerateRandomValidator(1)
+	vdr1 := GenerateRandomValidator(5)
+
+	s := NewSet()
+	if weight := s.Weight(); weight != 0 {
+		t.Fatalf("Empty set should have weight 0, has %d", weight)
+	}
+
+	s.Add(vdr0)
+	s.Add(vdr1)
+
+	if weight := s.Weight(); weight != 6 {
+		t.Fatalf("Set should have weight 6, has %d", weight)
+	}
+
+	s.Remove(vdr0.ID())
+
+	if weight := s.Weight(); weight != 5 {
+		t.Fatalf("Set should have weight 5, has %d", weight)
+	}
+}
+
+func TestSamplerWeightOverflow(t *testing.T) {
+	vdr0 := GenerateRandomValidator(1)
+	vdr1 := GenerateRandomValidator(math.MaxUint64)
+
+	s := NewSet()
+	s.Add(vdr0)
+	s.Add(vdr1)
+
+	if weight := s.Weight(); weight != math.MaxUint64 {
+		t.Fatalf("Set's weight should have saturated at %d, has %d", uint64(math.MaxUint64), weight)
+	}
+}
+
 func TestSamplerString(t *testing.T) {
 	vdr0 := NewValidator(ids.ShortEmpty, 1)
 	vdr1 := NewValidator(
@@ -0,0 +1,176 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package staking
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/formatting"
+	"github.com/ava-labs/gecko/utils/wrappers"
+)
+
+const (
+	// maxAPITokenSize is the largest API token, in bytes, that is parsed
+	maxAPITokenSize = 16 * 1024
+)
+
+var (
+	// apiTokenPrefix domain separates API tokens from other signatures made
+	// with a staking key
+	apiTokenPrefix = []byte("gecko api token")
+
+	errNoPrivateKey        = errors.New("no PEM encoded private key found")
+	errNotSigner           = errors.New("private key can't sign")
+	errUnsupportedKey      = errors.New("unsupported public key type")
+	errAPITokenExpired     = errors.New("API token expired")
+	errAPITokenTooLarge    = fmt.Errorf("API token is larger than %d bytes", maxAPITokenSize)
+	errAPITokenExtraSpace  = errors.New("API token has trailing bytes")
+	errAPITokenNotSignedBy = errors.New("API token isn't signed by its certificate's key")
+	errNotSignedBy         = errors.New("not signed by the certificate's key")
+)
+
+// NewAPIToken returns a token that authenticates API calls as being made on
+// behalf of the node that stakes with the PEM encoded certificate [certPEM],
+// whose PEM encoded private key is [keyPEM]. The token is valid until [expiry].
+func NewAPIToken(certPEM, keyPEM []byte, expiry time.Time) (string, error) {
+	expiryUnix := uint64(expiry.Unix())
+	certDER, sig, err := signWithStakingKey(certPEM, keyPEM, apiTokenMessage(expiryUnix))
+	if err != nil {
+		return "", err
+	}
+
+	p := wrappers.Packer{MaxSize: maxAPITokenSize}
+	p.PackBytes(certDER)
+	p.PackLong(expiryUnix)
+	p.PackBytes(sig)
+	if p.Errored() {
+		return "", p.Err
+	}
+	return formatting.CB58{Bytes: p.Bytes}.String(), nil
+}
+
+// VerifyAPIToken returns the ID of the node that signed [token], and when the
+// token expires. An error is returned if [token] is malformed, isn't signed by
+// its certificate's key or has expired by [now].
+func VerifyAPIToken(token string, now time.Time) (ids.ShortID, time.Time, error) {
+	if len(token) > 2*maxAPITokenSize {
+		return ids.ShortID{}, time.Time{}, errAPITokenTooLarge
+	}
+	tokenBytes := formatting.CB58{}
+	if err := tokenBytes.FromString(token); err != nil {
+		return ids.ShortID{}, time.Time{}, fmt.Errorf("problem parsing API token: %w", err)
+	}
+
+	p := wrappers.Packer{Bytes: tokenBytes.Bytes}
+	certBytes := p.UnpackBytes()
+	expiryUnix := p.UnpackLong()
+	sig := p.UnpackBytes()
+	switch {
+	case p.Errored():
+		return ids.ShortID{}, time.Time{}, fmt.Errorf("problem parsing API token: %w", p.Err)
+	case p.Offset != len(p.Bytes):
+		return ids.ShortID{}, time.Time{}, errAPITokenExtraSpace
+	}
+
+	expiry := time.Unix(int64(expiryUnix), 0)
+	if !now.Before(expiry) {
+		return ids.ShortID{}, time.Time{}, errAPITokenExpired
+	}
+
+	nodeID, err := verifyStakingSignature(certBytes, apiTokenMessage(expiryUnix), sig)
+	switch {
+	case err == errNotSignedBy:
+		return ids.ShortID{}, time.Time{}, errAPITokenNotSignedBy
+	case err != nil:
+		return ids.ShortID{}, time.Time{}, err
+	}
+	return nodeID, expiry, nil
+}
+
+// apiTokenMessage returns the message signed by an API token that expires at
+// [expiry], in seconds since the Unix epoch
+func apiTokenMessage(expiry uint64) []byte {
+	p := wrappers.Packer{MaxSize: len(apiTokenPrefix) + wrappers.LongLen}
+	p.PackFixedBytes(apiTokenPrefix)
+	p.PackLong(expiry)
+	return p.Bytes
+}
+
+// signWithStakingKey signs [msg] with the PEM encoded staking key [keyPEM]. It
+// returns the DER encoding of the PEM encoded certificate [certPEM], which the
+// signature is verified with, and the signature.
+func signWithStakingKey(certPEM, keyPEM, msg []byte) ([]byte, []byte, error) {
+	certBlock, _ := pem.Decode(certPEM)
+	if certBlock == nil {
+		return nil, nil, errNoCertificate
+	}
+	keyBlock, _ := pem.Decode(keyPEM)
+	if keyBlock == nil {
+		return nil, nil, errNoPrivateKey
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(keyBlock.Bytes)
+	if err != nil {
+		// Keys generated by openssl may be PKCS #1 encoded
+		key, err = x509.ParsePKCS1PrivateKey(keyBlock.Bytes)
+		if err != nil {
+			return nil, nil, fmt.Errorf("problem parsing staking key: %w", err)
+		}
+	}
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, nil, errNotSigner
+	}
+
+	var sig []byte
+	switch signer.Public().(type) {
+	case ed25519.PublicKey:
+		sig, err = signer.Sign(rand.Reader, msg, crypto.Hash(0))
+	case *rsa.PublicKey, *ecdsa.PublicKey:
+		digest := sha256.Sum256(msg)
+		sig, err = signer.Sign(rand.Reader, digest[:], crypto.SHA256)
+	default:
+		return nil, nil, errUnsupportedKey
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("couldn't sign with staking key: %w", err)
+	}
+	return certBlock.Bytes, sig, nil
+}
+
+// verifyStakingSignature returns the ID of the node that stakes with the DER
+// encoded certificate [certDER]. Returns errNotSignedBy if [sig] isn't the
+// certificate's key's signature of [msg].
+func verifyStakingSignature(certDER, msg, sig []byte) (ids.ShortID, error) {
+	cert, err := x509.ParseCertificate(certDER)
+	if err != nil {
+		return ids.ShortID{}, fmt.Errorf("problem parsing certificate: %w", err)
+	}
+	var algorithm x509.SignatureAlgorithm
+	switch cert.PublicKey.(type) {
+	case ed25519.PublicKey:
+		algorithm = x509.PureEd25519
+	case *rsa.PublicKey:
+		algorithm = x509.SHA256WithRSA
+	case *ecdsa.PublicKey:
+		algorithm = x509.ECDSAWithSHA256
+	default:
+		return ids.ShortID{}, errUnsupportedKey
+	}
+	if err := cert.CheckSignature(algorithm, msg, sig); err != nil {
+		return ids.ShortID{}, errNotSignedBy
+	}
+	return CertificateNodeID(cert.Raw)
+}
@@ -0,0 +1,68 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package staking
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAPIToken(t *testing.T) {
+	cert, key, err := NewCertAndKeyFromSeedPhrase(testPhrase)
+	if err != nil {
+		t.Fatal(err)
+	}
+	nodeID, err := NodeID(cert)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Unix(1000000, 0)
+	expiry := now.Add(time.Hour)
+	token, err := NewAPIToken(cert, key, expiry)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	signerID, tokenExpiry, err := VerifyAPIToken(token, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !signerID.Equals(nodeID) {
+		t.Fatalf("Token should have been signed by %s but was signed by %s", nodeID, signerID)
+	}
+	if !tokenExpiry.Equal(expiry) {
+		t.Fatalf("Token should expire at %s but expires at %s", expiry, tokenExpiry)
+	}
+
+	if _, _, err := VerifyAPIToken(token, expiry); err != errAPITokenExpired {
+		t.Fatalf("Should have errored with %s, errored with %v", errAPITokenExpired, err)
+	}
+}
+
+func TestAPITokenWrongSigner(t *testing.T) {
+	cert0, _, err := NewCertAndKeyFromSeedPhrase(testPhrase)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, key1, err := NewCertAndKeyFromSeedPhrase(testPhrase + " actor")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Unix(1000000, 0)
+	token, err := NewAPIToken(cert0, key1, now.Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := VerifyAPIToken(token, now); err != errAPITokenNotSignedBy {
+		t.Fatalf("Should have errored with %s, errored with %v", errAPITokenNotSignedBy, err)
+	}
+}
+
+func TestAPITokenMalformed(t *testing.T) {
+	if _, _, err := VerifyAPIToken("not a token", time.Unix(0, 0)); err == nil {
+		t.Fatal("Should have errored on a malformed token")
+	}
+}
@@ -0,0 +1,123 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package staking
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/formatting"
+	"github.com/ava-labs/gecko/utils/wrappers"
+)
+
+const (
+	// MaxChallengeSize is the largest challenge, in bytes, that a node ID
+	// proof signs
+	MaxChallengeSize = 1024
+
+	// maxNodeIDProofSize is the largest node ID proof, in bytes, that is
+	// parsed
+	maxNodeIDProofSize = 16 * 1024
+)
+
+var (
+	// nodeIDProofPrefix domain separates node ID proofs from other signatures
+	// made with a staking key, so a challenge can't be chosen to produce an
+	// API token
+	nodeIDProofPrefix = []byte("gecko node ID proof")
+
+	errNoChallenge            = errors.New("challenge must not be empty")
+	errChallengeTooLarge      = fmt.Errorf("challenge is larger than %d bytes", MaxChallengeSize)
+	errNodeIDProofTooLarge    = fmt.Errorf("node ID proof is larger than %d bytes", maxNodeIDProofSize)
+	errNodeIDProofExtraSpace  = errors.New("node ID proof has trailing bytes")
+	errNodeIDProofNotSignedBy = errors.New("node ID proof isn't signed by its certificate's key")
+)
+
+// ProveNodeID returns the ID of the node that stakes with the PEM encoded
+// certificate [certPEM], whose PEM encoded private key is [keyPEM], and a proof
+// that the holder of the key signed [challenge]. A verifier that chose the
+// challenge learns that whoever produced the proof controls the node.
+func ProveNodeID(certPEM, keyPEM, challenge []byte) (ids.ShortID, string, error) {
+	msg, err := nodeIDProofMessage(challenge)
+	if err != nil {
+		return ids.ShortID{}, "", err
+	}
+	certDER, sig, err := signWithStakingKey(certPEM, keyPEM, msg)
+	if err != nil {
+		return ids.ShortID{}, "", err
+	}
+	nodeID, err := CertificateNodeID(certDER)
+	if err != nil {
+		return ids.ShortID{}, "", err
+	}
+
+	p := wrappers.Packer{MaxSize: maxNodeIDProofSize}
+	p.PackBytes(certDER)
+	p.PackBytes(sig)
+	if p.Errored() {
+		return ids.ShortID{}, "", p.Err
+	}
+	return nodeID, formatting.CB58{Bytes: p.Bytes}.String(), nil
+}
+
+// VerifyNodeIDProof returns the ID of the node whose staking key signed
+// [challenge] to produce [proof]. An error is returned if [proof] is malformed
+// or isn't its certificate's key's signature of [challenge].
+func VerifyNodeIDProof(proof string, challenge []byte) (ids.ShortID, error) {
+	if len(proof) > 2*maxNodeIDProofSize {
+		return ids.ShortID{}, errNodeIDProofTooLarge
+	}
+	msg, err := nodeIDProofMessage(challenge)
+	if err != nil {
+		return ids.ShortID{}, err
+	}
+	proofBytes := formatting.CB58{}
+	if err := proofBytes.FromString(proof); err != nil {
+		return ids.ShortID{}, fmt.Errorf("problem parsing node ID proof: %w", err)
+	}
+
+	p := wrappers.Packer{Bytes: proofBytes.Bytes}
+	certBytes := p.UnpackBytes()
+	sig := p.UnpackBytes()
+	switch {
+	case p.Errored():
+		return ids.ShortID{}, fmt.Errorf("problem parsing node ID proof: %w", p.Err)
+	case p.Offset != len(p.Bytes):
+		return ids.ShortID{}, errNodeIDProofExtraSpace
+	}
+
+	nodeID, err := verifyStakingSignature(certBytes, msg, sig)
+	switch {
+	case err == errNotSignedBy:
+		return ids.ShortID{}, errNodeIDProofNotSignedBy
+	case err != nil:
+		return ids.ShortID{}, err
+	}
+	return nodeID, nil
+}
+
+// nodeIDProofMessage returns the message signed by a node ID proof of
+// [challenge]
+func nodeIDProofMessage(challenge []byte) ([]byte, error) {
+	switch {
+	case len(challenge) == 0:
+		return nil, errNoChallenge
+	case len(challenge) > MaxChallengeSize:
+		return nil, errChallengeTooLarge
+	}
+	p := wrappers.Packer{MaxSize: len(nodeIDProofPrefix) + wrappers.IntLen + len(challenge)}
+	p.PackFixedBytes(nodeIDProofPrefix)
+	p.PackBytes(challenge)
+	return p.Bytes, p.Err
+}
+
+// NodeIDProver proves that this node stakes with the PEM encoded certificate
+// [CertPEM], whose PEM encoded private key is [KeyPEM]
+type NodeIDProver struct{ CertPEM, KeyPEM []byte }
+
+// ProveNodeID returns this node's ID and a proof that it signed [challenge]
+func (p *NodeIDProver) ProveNodeID(challenge []byte) (ids.ShortID, string, error) {
+	return ProveNodeID(p.CertPEM, p.KeyPEM, challenge)
+}
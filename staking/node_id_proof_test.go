@@ -0,0 +1,80 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package staking
+
+import (
+	"testing"
+)
+
+func TestNodeIDProof(t *testing.T) {
+	cert, key, err := NewCertAndKeyFromSeedPhrase(testPhrase)
+	if err != nil {
+		t.Fatal(err)
+	}
+	nodeID, err := NodeID(cert)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	challenge := []byte("register node 1")
+	provenID, proof, err := ProveNodeID(cert, key, challenge)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !provenID.Equals(nodeID) {
+		t.Fatalf("Should have proven %s but proved %s", nodeID, provenID)
+	}
+
+	signerID, err := VerifyNodeIDProof(proof, challenge)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !signerID.Equals(nodeID) {
+		t.Fatalf("Proof should have been signed by %s but was signed by %s", nodeID, signerID)
+	}
+
+	// The proof is only valid for the challenge it signed
+	if _, err := VerifyNodeIDProof(proof, []byte("register node 2")); err != errNodeIDProofNotSignedBy {
+		t.Fatalf("Should have errored with %s, errored with %v", errNodeIDProofNotSignedBy, err)
+	}
+}
+
+func TestNodeIDProofWrongSigner(t *testing.T) {
+	cert0, _, err := NewCertAndKeyFromSeedPhrase(testPhrase)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, key1, err := NewCertAndKeyFromSeedPhrase(testPhrase + " actor")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	challenge := []byte("register node")
+	_, proof, err := ProveNodeID(cert0, key1, challenge)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := VerifyNodeIDProof(proof, challenge); err != errNodeIDProofNotSignedBy {
+		t.Fatalf("Should have errored with %s, errored with %v", errNodeIDProofNotSignedBy, err)
+	}
+}
+
+func TestNodeIDProofChallengeSize(t *testing.T) {
+	cert, key, err := NewCertAndKeyFromSeedPhrase(testPhrase)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := ProveNodeID(cert, key, nil); err != errNoChallenge {
+		t.Fatalf("Should have errored with %s, errored with %v", errNoChallenge, err)
+	}
+	if _, _, err := ProveNodeID(cert, key, make([]byte, MaxChallengeSize+1)); err != errChallengeTooLarge {
+		t.Fatalf("Should have errored with %s, errored with %v", errChallengeTooLarge, err)
+	}
+}
+
+func TestNodeIDProofMalformed(t *testing.T) {
+	if _, err := VerifyNodeIDProof("not a proof", []byte("challenge")); err == nil {
+		t.Fatal("Should have errored on a malformed proof")
+	}
+}
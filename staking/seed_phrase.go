@@ -0,0 +1,50 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package staking
+
+import (
+	"crypto/ed25519"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+
+	"github.com/ava-labs/gecko/utils/hashing"
+)
+
+const (
+	// MinSeedPhraseWords is the minimum number of words in a seed phrase
+	MinSeedPhraseWords = 12
+)
+
+var (
+	// seedPhraseSalt domain separates staking keys from other uses of a seed
+	// phrase. Changing it changes every derived node ID.
+	seedPhraseSalt = []byte("gecko staking key")
+
+	errShortSeedPhrase = fmt.Errorf("seed phrase must have at least %d words", MinSeedPhraseWords)
+)
+
+// NewCertAndKeyFromSeedPhrase deterministically derives a staking key and a
+// self-signed certificate from [phrase], and returns them PEM encoded.
+//
+// Words are compared case-insensitively and the whitespace between them is
+// ignored, so the same phrase always derives the same node ID.
+//
+// The key is an Ed25519 key because, unlike RSA and ECDSA key generation and
+// ECDSA signing, Ed25519 doesn't consume randomness, which makes the derived
+// certificate byte-for-byte reproducible.
+func NewCertAndKeyFromSeedPhrase(phrase string) ([]byte, []byte, error) {
+	words := strings.Fields(strings.ToLower(phrase))
+	if len(words) < MinSeedPhraseWords {
+		return nil, nil, errShortSeedPhrase
+	}
+	normalized := strings.Join(words, " ")
+
+	seed := argon2.IDKey([]byte(normalized), seedPhraseSalt, 1, 64*1024, 4, ed25519.SeedSize)
+	key := ed25519.NewKeyFromSeed(seed)
+
+	return newCertAndKey(key, new(big.Int).SetBytes(hashing.ComputeHash160(seed)))
+}
@@ -0,0 +1,72 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package staking
+
+import (
+	"bytes"
+	"crypto/tls"
+	"testing"
+)
+
+const testPhrase = "abandon ability able about above absent absorb abstract absurd abuse access accident"
+
+func TestNewCertAndKeyFromSeedPhraseDeterministic(t *testing.T) {
+	cert0, key0, err := NewCertAndKeyFromSeedPhrase(testPhrase)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Case and whitespace shouldn't matter
+	cert1, key1, err := NewCertAndKeyFromSeedPhrase("  ABANDON ability able about above absent\nabsorb abstract absurd abuse access accident ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(cert0, cert1) || !bytes.Equal(key0, key1) {
+		t.Fatalf("The same seed phrase should derive the same certificate and key")
+	}
+
+	id0, err := NodeID(cert0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	id1, err := NodeID(cert1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !id0.Equals(id1) {
+		t.Fatalf("The same seed phrase should derive the same node ID")
+	}
+
+	if _, err := tls.X509KeyPair(cert0, key0); err != nil {
+		t.Fatalf("The derived certificate and key should be a TLS key pair: %s", err)
+	}
+}
+
+func TestNewCertAndKeyFromSeedPhraseDifferentPhrases(t *testing.T) {
+	cert0, _, err := NewCertAndKeyFromSeedPhrase(testPhrase)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert1, _, err := NewCertAndKeyFromSeedPhrase(testPhrase + " actor")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	id0, err := NodeID(cert0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	id1, err := NodeID(cert1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id0.Equals(id1) {
+		t.Fatalf("Different seed phrases should derive different node IDs")
+	}
+}
+
+func TestNewCertAndKeyFromSeedPhraseTooShort(t *testing.T) {
+	if _, _, err := NewCertAndKeyFromSeedPhrase("abandon ability able"); err == nil {
+		t.Fatalf("Should have rejected a short seed phrase")
+	}
+}
@@ -0,0 +1,101 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package staking
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/hashing"
+)
+
+var (
+	// The validity period of staking certificates is fixed so that the same
+	// certificate, and therefore the same node ID, is derived every time from
+	// a seed phrase. A node's ID must never expire.
+	certNotBefore = time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
+	certNotAfter  = time.Date(9999, time.December, 31, 23, 59, 59, 0, time.UTC)
+
+	errNoCertificate = errors.New("no PEM encoded certificate found")
+)
+
+// NewCertAndKey generates a random staking key and a self-signed certificate,
+// and returns them PEM encoded
+func NewCertAndKey() ([]byte, []byte, error) {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, nil, fmt.Errorf("couldn't generate key: %w", err)
+	}
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, nil, fmt.Errorf("couldn't generate serial number: %w", err)
+	}
+	return newCertAndKey(key, serial)
+}
+
+// newCertAndKey returns a certificate, self-signed by [key], and [key], PEM
+// encoded
+func newCertAndKey(key crypto.Signer, serial *big.Int) ([]byte, []byte, error) {
+	template := &x509.Certificate{
+		SerialNumber:          serial,
+		NotBefore:             certNotBefore,
+		NotAfter:              certNotAfter,
+		KeyUsage:              x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+	}
+	certBytes, err := x509.CreateCertificate(nil, template, template, key.Public(), key)
+	if err != nil {
+		return nil, nil, fmt.Errorf("couldn't create certificate: %w", err)
+	}
+	keyBytes, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, nil, fmt.Errorf("couldn't marshal private key: %w", err)
+	}
+
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certBytes})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyBytes})
+	return certPEM, keyPEM, nil
+}
+
+// CertificateNodeID returns the ID of the node that stakes with the DER
+// encoded certificate [certDER]. A node's ID is the hash of the certificate it
+// presents when peers connect to it over TLS, so a peer can only claim a node
+// ID if it has the certificate's key.
+func CertificateNodeID(certDER []byte) (ids.ShortID, error) {
+	return ids.ToShortID(hashing.PubkeyBytesToAddress(certDER))
+}
+
+// NodeID returns the ID of the node that stakes with the PEM encoded
+// certificate [certPEM]
+func NodeID(certPEM []byte) (ids.ShortID, error) {
+	block, _ := pem.Decode(certPEM)
+	if block == nil {
+		return ids.ShortID{}, errNoCertificate
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return ids.ShortID{}, fmt.Errorf("problem parsing staking certificate: %w", err)
+	}
+	return CertificateNodeID(cert.Raw)
+}
+
+// KeyPairNodeID returns the ID of the node that stakes with the PEM encoded
+// certificate [certPEM] and key [keyPEM]. Errors if they can't be used to
+// authenticate TLS connections, such as if the key isn't the certificate's.
+func KeyPairNodeID(certPEM, keyPEM []byte) (ids.ShortID, error) {
+	pair, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return ids.ShortID{}, fmt.Errorf("staking certificate and key aren't a TLS key pair: %w", err)
+	}
+	return CertificateNodeID(pair.Certificate[0])
+}
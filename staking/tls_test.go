@@ -0,0 +1,58 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package staking
+
+import (
+	"testing"
+)
+
+func TestNewCertAndKey(t *testing.T) {
+	cert0, key0, err := NewCertAndKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert1, _, err := NewCertAndKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	id0, err := KeyPairNodeID(cert0, key0)
+	if err != nil {
+		t.Fatalf("The generated certificate and key should be a TLS key pair: %s", err)
+	}
+	if id, err := NodeID(cert0); err != nil {
+		t.Fatal(err)
+	} else if !id.Equals(id0) {
+		t.Fatalf("The key pair's node ID should be the certificate's node ID")
+	}
+
+	id1, err := NodeID(cert1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id0.Equals(id1) {
+		t.Fatalf("Generated certificates should have different node IDs")
+	}
+}
+
+func TestKeyPairNodeIDMismatch(t *testing.T) {
+	cert, _, err := NewCertAndKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, key, err := NewCertAndKeyFromSeedPhrase(testPhrase)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := KeyPairNodeID(cert, key); err == nil {
+		t.Fatalf("Should have rejected a key that isn't the certificate's")
+	}
+}
+
+func TestNodeIDInvalidCert(t *testing.T) {
+	if _, err := NodeID([]byte("not a certificate")); err == nil {
+		t.Fatalf("Should have failed to parse the certificate")
+	}
+}
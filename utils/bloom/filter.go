@@ -0,0 +1,182 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package bloom
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+	"math/bits"
+
+	"github.com/ava-labs/gecko/utils/hashing"
+)
+
+const (
+	// MaxHashes is the maximum number of hash functions a filter may use
+	MaxHashes = 16
+
+	// MaxBytes is the maximum size, in bytes, of a filter's bit array
+	MaxBytes = 4 * 1024
+)
+
+var (
+	errNoHashes      = errors.New("filter must use at least one hash function")
+	errTooManyHashes = errors.New("filter uses too many hash functions")
+	errNoBits        = errors.New("filter must have a non-empty bit array")
+	errTooManyBits   = errors.New("filter bit array is too large")
+	errNoEntries     = errors.New("filter must be sized for at least one entry")
+	errInvalidRate   = errors.New("false positive rate must be in (0, 1)")
+	errIncompatible  = errors.New("filters have different sizes or hash functions")
+	errNoBytes       = errors.New("no bytes provided")
+)
+
+// Filter is a bloom filter over byte slices.
+//
+// The i'th hash function of a key is the first 8 bytes, interpreted as a
+// big-endian uint64, of sha256([i] || key) modulo the number of bits in the
+// filter. Bit n of the filter is the (n % 8)'th least significant bit of the
+// (n / 8)'th byte. This allows a client to build a filter without access to
+// this package.
+type Filter struct {
+	numHashes int
+	bits      []byte
+}
+
+// New returns a filter using [numHashes] hash functions that is backed by
+// [bits]. [bits] is not copied.
+func New(numHashes int, bits []byte) (*Filter, error) {
+	switch {
+	case numHashes <= 0:
+		return nil, errNoHashes
+	case numHashes > MaxHashes:
+		return nil, errTooManyHashes
+	case len(bits) == 0:
+		return nil, errNoBits
+	case len(bits) > MaxBytes:
+		return nil, errTooManyBits
+	}
+	return &Filter{
+		numHashes: numHashes,
+		bits:      bits,
+	}, nil
+}
+
+// OptimalParameters returns the number of hash functions and the size, in
+// bytes, of the bit array that minimize the size of a filter that contains
+// [numEntries] entries with a false positive rate of at most [falsePositiveRate].
+// The parameters are capped at MaxHashes and MaxBytes, so the false positive
+// rate of a filter sized for many entries may be higher than requested.
+func OptimalParameters(numEntries int, falsePositiveRate float64) (int, int, error) {
+	switch {
+	case numEntries <= 0:
+		return 0, 0, errNoEntries
+	case falsePositiveRate <= 0 || falsePositiveRate >= 1:
+		return 0, 0, errInvalidRate
+	}
+
+	// m = -n * ln(p) / ln(2)^2
+	numBits := math.Ceil(-float64(numEntries) * math.Log(falsePositiveRate) / (math.Ln2 * math.Ln2))
+	numBytes := int(math.Min(math.Ceil(numBits/8), MaxBytes))
+	if numBytes < 1 {
+		numBytes = 1
+	}
+
+	// k = m / n * ln(2)
+	numHashes := int(math.Round(float64(8*numBytes) / float64(numEntries) * math.Ln2))
+	switch {
+	case numHashes < 1:
+		numHashes = 1
+	case numHashes > MaxHashes:
+		numHashes = MaxHashes
+	}
+	return numHashes, numBytes, nil
+}
+
+// NewOptimal returns an empty filter sized to contain [numEntries] entries
+// with a false positive rate of at most [falsePositiveRate]. See
+// OptimalParameters.
+func NewOptimal(numEntries int, falsePositiveRate float64) (*Filter, error) {
+	numHashes, numBytes, err := OptimalParameters(numEntries, falsePositiveRate)
+	if err != nil {
+		return nil, err
+	}
+	return New(numHashes, make([]byte, numBytes))
+}
+
+// Parse returns the filter that [b], the output of Marshal, represents. [b] is
+// copied.
+func Parse(b []byte) (*Filter, error) {
+	if len(b) == 0 {
+		return nil, errNoBytes
+	}
+	return New(int(b[0]), append([]byte(nil), b[1:]...))
+}
+
+// Add [key] to the filter
+func (f *Filter) Add(key []byte) {
+	for i := 0; i < f.numHashes; i++ {
+		index := f.index(i, key)
+		f.bits[index/8] |= 1 << (index % 8)
+	}
+}
+
+// Check returns true if [key] may have been added to the filter. False
+// positives are possible, false negatives aren't.
+func (f *Filter) Check(key []byte) bool {
+	for i := 0; i < f.numHashes; i++ {
+		index := f.index(i, key)
+		if f.bits[index/8]&(1<<(index%8)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Merge adds to this filter every key that was added to [other]. [other] must
+// use the same number of hash functions and the same size bit array as this
+// filter.
+func (f *Filter) Merge(other *Filter) error {
+	if f.numHashes != other.numHashes || len(f.bits) != len(other.bits) {
+		return errIncompatible
+	}
+	for i, b := range other.bits {
+		f.bits[i] |= b
+	}
+	return nil
+}
+
+// EstimatedFalsePositiveRate returns the probability that Check returns true
+// for a key that wasn't added, estimated from the fraction of set bits
+func (f *Filter) EstimatedFalsePositiveRate() float64 {
+	numSet := 0
+	for _, b := range f.bits {
+		numSet += bits.OnesCount8(b)
+	}
+	fractionSet := float64(numSet) / float64(8*len(f.bits))
+	return math.Pow(fractionSet, float64(f.numHashes))
+}
+
+// Marshal returns the byte representation of this filter: the number of hash
+// functions as a single byte, followed by the bit array
+func (f *Filter) Marshal() []byte {
+	b := make([]byte, len(f.bits)+1)
+	b[0] = byte(f.numHashes)
+	copy(b[1:], f.bits)
+	return b
+}
+
+// NumHashes returns the number of hash functions this filter uses
+func (f *Filter) NumHashes() int { return f.numHashes }
+
+// Bytes returns the bit array of this filter
+func (f *Filter) Bytes() []byte { return f.bits }
+
+func (f *Filter) index(i int, key []byte) uint64 {
+	preimage := make([]byte, len(key)+1)
+	preimage[0] = byte(i)
+	copy(preimage[1:], key)
+
+	hash := hashing.ComputeHash256(preimage)
+	return binary.BigEndian.Uint64(hash) % uint64(8*len(f.bits))
+}
@@ -0,0 +1,233 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package bloom
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+)
+
+func TestNewInvalid(t *testing.T) {
+	if _, err := New(0, make([]byte, 1)); err == nil {
+		t.Fatalf("Should have errored due to no hash functions")
+	}
+	if _, err := New(MaxHashes+1, make([]byte, 1)); err == nil {
+		t.Fatalf("Should have errored due to too many hash functions")
+	}
+	if _, err := New(1, nil); err == nil {
+		t.Fatalf("Should have errored due to an empty bit array")
+	}
+	if _, err := New(1, make([]byte, MaxBytes+1)); err == nil {
+		t.Fatalf("Should have errored due to a too large bit array")
+	}
+}
+
+func TestFilter(t *testing.T) {
+	f, err := New(3, make([]byte, 256))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	added := [][]byte{
+		[]byte("hello"),
+		[]byte("world"),
+		[]byte{},
+	}
+	for _, key := range added {
+		f.Add(key)
+	}
+	for _, key := range added {
+		if !f.Check(key) {
+			t.Fatalf("Filter should contain %v", key)
+		}
+	}
+
+	if f.Check([]byte("goodbye")) {
+		t.Fatalf("Filter shouldn't contain an unadded key")
+	}
+}
+
+func TestFilterEncoding(t *testing.T) {
+	f, err := New(1, make([]byte, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// sha256([0]) = 6e340b9cffb37a98...
+	// 0x6e340b9cffb37a98 % 8 = 0
+	f.Add(nil)
+
+	if b := f.Bytes()[0]; b != 0x01 {
+		t.Fatalf("Filter set bits 0x%02x but expected 0x%02x", b, 0x01)
+	}
+}
+
+func TestOptimalParameters(t *testing.T) {
+	numHashes, numBytes, err := OptimalParameters(1000, .01)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// m = -1000 * ln(.01) / ln(2)^2 = 9586 bits = 1199 bytes
+	// k = 9592 / 1000 * ln(2) = 7
+	if numBytes != 1199 {
+		t.Fatalf("Expected %d bytes but got %d", 1199, numBytes)
+	}
+	if numHashes != 7 {
+		t.Fatalf("Expected %d hash functions but got %d", 7, numHashes)
+	}
+
+	numHashes, numBytes, err = OptimalParameters(1000000, .0001)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if numBytes != MaxBytes {
+		t.Fatalf("Expected the bit array to be capped at %d bytes but was %d", MaxBytes, numBytes)
+	}
+	if numHashes != 1 {
+		t.Fatalf("Expected %d hash function but got %d", 1, numHashes)
+	}
+}
+
+func TestOptimalParametersInvalid(t *testing.T) {
+	if _, _, err := OptimalParameters(0, .01); err == nil {
+		t.Fatalf("Should have errored due to no entries")
+	}
+	if _, _, err := OptimalParameters(10, 0); err == nil {
+		t.Fatalf("Should have errored due to a false positive rate of 0")
+	}
+	if _, _, err := OptimalParameters(10, 1); err == nil {
+		t.Fatalf("Should have errored due to a false positive rate of 1")
+	}
+}
+
+func TestNewOptimalFalsePositiveRate(t *testing.T) {
+	numEntries := 1000
+	falsePositiveRate := .01
+	f, err := NewOptimal(numEntries, falsePositiveRate)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	key := make([]byte, 8)
+	for i := 0; i < numEntries; i++ {
+		binary.BigEndian.PutUint64(key, uint64(i))
+		f.Add(key)
+	}
+
+	if rate := f.EstimatedFalsePositiveRate(); rate > 2*falsePositiveRate {
+		t.Fatalf("Estimated false positive rate %f is much higher than %f", rate, falsePositiveRate)
+	}
+
+	numFalsePositives := 0
+	numChecks := 10000
+	for i := numEntries; i < numEntries+numChecks; i++ {
+		binary.BigEndian.PutUint64(key, uint64(i))
+		if f.Check(key) {
+			numFalsePositives++
+		}
+	}
+	if rate := float64(numFalsePositives) / float64(numChecks); rate > 2*falsePositiveRate {
+		t.Fatalf("Observed false positive rate %f is much higher than %f", rate, falsePositiveRate)
+	}
+}
+
+func TestEstimatedFalsePositiveRate(t *testing.T) {
+	f, err := New(2, []byte{0x0f, 0x00})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 4 of 16 bits are set, so (4/16)^2 = 1/16
+	if rate := f.EstimatedFalsePositiveRate(); rate != 1./16 {
+		t.Fatalf("Expected false positive rate %f but got %f", 1./16, rate)
+	}
+}
+
+func TestMerge(t *testing.T) {
+	f0, err := New(3, make([]byte, 64))
+	if err != nil {
+		t.Fatal(err)
+	}
+	f1, err := New(3, make([]byte, 64))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f0.Add([]byte("hello"))
+	f1.Add([]byte("world"))
+
+	if err := f0.Merge(f1); err != nil {
+		t.Fatal(err)
+	}
+	if !f0.Check([]byte("hello")) || !f0.Check([]byte("world")) {
+		t.Fatalf("Merged filter should contain the keys of both filters")
+	}
+	if f1.Check([]byte("hello")) {
+		t.Fatalf("Merging shouldn't modify the merged in filter")
+	}
+}
+
+func TestMergeIncompatible(t *testing.T) {
+	f, err := New(3, make([]byte, 64))
+	if err != nil {
+		t.Fatal(err)
+	}
+	fewerHashes, err := New(2, make([]byte, 64))
+	if err != nil {
+		t.Fatal(err)
+	}
+	fewerBytes, err := New(3, make([]byte, 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := f.Merge(fewerHashes); err == nil {
+		t.Fatalf("Should have errored due to a different number of hash functions")
+	}
+	if err := f.Merge(fewerBytes); err == nil {
+		t.Fatalf("Should have errored due to a different size bit array")
+	}
+}
+
+func TestMarshalParse(t *testing.T) {
+	f, err := New(3, make([]byte, 64))
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Add([]byte("hello"))
+
+	b := f.Marshal()
+	parsed, err := Parse(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsed.NumHashes() != f.NumHashes() {
+		t.Fatalf("Parsed filter uses %d hash functions but expected %d", parsed.NumHashes(), f.NumHashes())
+	}
+	if !bytes.Equal(parsed.Bytes(), f.Bytes()) {
+		t.Fatalf("Parsed filter has the wrong bit array")
+	}
+	if !parsed.Check([]byte("hello")) {
+		t.Fatalf("Parsed filter should contain the added key")
+	}
+
+	// The parsed filter shouldn't share memory with [b]
+	parsed.Add([]byte("world"))
+	if !bytes.Equal(b[1:], f.Bytes()) {
+		t.Fatalf("Parsing should copy the bit array")
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	if _, err := Parse(nil); err == nil {
+		t.Fatalf("Should have errored due to no bytes")
+	}
+	if _, err := Parse([]byte{0, 1}); err == nil {
+		t.Fatalf("Should have errored due to no hash functions")
+	}
+	if _, err := Parse([]byte{1}); err == nil {
+		t.Fatalf("Should have errored due to an empty bit array")
+	}
+}
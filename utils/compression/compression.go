@@ -0,0 +1,93 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package compression
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+)
+
+// Type is an algorithm a message's payload may be compressed with
+type Type uint8
+
+// Compression types. These values are sent over the wire.
+const (
+	// None means the payload isn't compressed
+	None Type = iota
+	// Gzip means the payload is compressed with gzip
+	Gzip
+)
+
+var (
+	errUnknownType = errors.New("unknown compression type")
+)
+
+func (t Type) String() string {
+	switch t {
+	case None:
+		return "none"
+	case Gzip:
+		return "gzip"
+	default:
+		return "unknown"
+	}
+}
+
+// Compress [b] with [t]
+func Compress(t Type, b []byte) ([]byte, error) {
+	switch t {
+	case None:
+		return b, nil
+	case Gzip:
+		buf := bytes.Buffer{}
+		// Messages are compressed on the send path, so speed is preferred to
+		// the best ratio
+		w, err := gzip.NewWriterLevel(&buf, gzip.BestSpeed)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := w.Write(b); err != nil {
+			return nil, err
+		}
+		if err := w.Close(); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	default:
+		return nil, errUnknownType
+	}
+}
+
+// Decompress [b], which was compressed with [t]. Errors if the decompressed
+// bytes would be longer than [maxSize], so that a small payload can't expand to
+// exhaust memory.
+func Decompress(t Type, b []byte, maxSize int) ([]byte, error) {
+	var r io.Reader
+	switch t {
+	case None:
+		r = bytes.NewReader(b)
+	case Gzip:
+		gr, err := gzip.NewReader(bytes.NewReader(b))
+		if err != nil {
+			return nil, err
+		}
+		defer gr.Close()
+		r = gr
+	default:
+		return nil, errUnknownType
+	}
+
+	decompressed, err := ioutil.ReadAll(io.LimitReader(r, int64(maxSize)+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(decompressed) > maxSize {
+		return nil, fmt.Errorf("decompressed payload is longer than %d bytes", maxSize)
+	}
+	return decompressed, nil
+}
@@ -0,0 +1,58 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package compression
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestCompressRoundTrip(t *testing.T) {
+	payload := bytes.Repeat([]byte("container"), 1000)
+
+	for _, typ := range []Type{None, Gzip} {
+		compressed, err := Compress(typ, payload)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if typ != None && len(compressed) >= len(payload) {
+			t.Fatalf("%s should have shrunk a repetitive payload", typ)
+		}
+
+		decompressed, err := Decompress(typ, compressed, len(payload))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(decompressed, payload) {
+			t.Fatalf("%s didn't round trip the payload", typ)
+		}
+	}
+}
+
+func TestDecompressTooLarge(t *testing.T) {
+	payload := make([]byte, 1<<20)
+
+	compressed, err := Compress(Gzip, payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Decompress(Gzip, compressed, len(payload)-1); err == nil {
+		t.Fatalf("Should have rejected a payload that decompresses past the maximum size")
+	}
+	if _, err := Decompress(None, payload, len(payload)-1); err == nil {
+		t.Fatalf("Should have rejected an uncompressed payload past the maximum size")
+	}
+}
+
+func TestDecompressInvalid(t *testing.T) {
+	if _, err := Decompress(Gzip, []byte("not gzip"), 1024); err == nil {
+		t.Fatalf("Should have failed to decompress an invalid payload")
+	}
+	if _, err := Decompress(Type(255), nil, 1024); err == nil {
+		t.Fatalf("Should have rejected an unknown compression type")
+	}
+	if _, err := Compress(Type(255), nil); err == nil {
+		t.Fatalf("Should have rejected an unknown compression type")
+	}
+}
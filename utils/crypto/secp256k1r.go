@@ -66,6 +66,10 @@ func (f *FactorySECP256K1R) RecoverPublicKey(msg, sig []byte) (PublicKey, error)
 
 // RecoverHashPublicKey returns the public key from a 65 byte signature
 func (f *FactorySECP256K1R) RecoverHashPublicKey(hash, sig []byte) (PublicKey, error) {
+	if err := VerifySECP256K1RSignatureFormat(sig); err != nil {
+		return nil, err
+	}
+
 	cacheBytes := make([]byte, len(hash)+len(sig))
 	copy(cacheBytes, hash)
 	copy(cacheBytes[len(hash):], sig)
@@ -74,10 +78,6 @@ func (f *FactorySECP256K1R) RecoverHashPublicKey(hash, sig []byte) (PublicKey, e
 		return cachedPublicKey.(*PublicKeySECP256K1), nil
 	}
 
-	if err := verifySECP256K1RSignatureFormat(sig); err != nil {
-		return nil, err
-	}
-
 	rawPubkey, err := crypto.SigToPub(hash, sig)
 	if err != nil {
 		return nil, err
@@ -101,7 +101,7 @@ func (k *PublicKeySECP256K1R) Verify(msg, sig []byte) bool {
 
 // VerifyHash implements the PublicKey interface
 func (k *PublicKeySECP256K1R) VerifyHash(hash, sig []byte) bool {
-	if verifySECP256K1RSignatureFormat(sig) != nil {
+	if VerifySECP256K1RSignatureFormat(sig) != nil {
 		return false
 	}
 	return crypto.VerifySignature(k.Bytes(), hash, sig[:SECP256K1RSigLen-1])
@@ -162,7 +162,13 @@ func (k *PrivateKeySECP256K1R) Bytes() []byte {
 	return k.bytes
 }
 
-func verifySECP256K1RSignatureFormat(sig []byte) error {
+// VerifySECP256K1RSignatureFormat returns nil if [sig] is the canonical
+// encoding of a recoverable signature. That is, [sig] is 65 bytes, r and s are
+// in [1, n), s is in the lower half of the curve order, and the recovery ID is
+// 0 or 1. Every signature has exactly one canonical encoding, so a third party
+// can't modify a signature, and therefore the ID of the transaction containing
+// it, without invalidating it.
+func VerifySECP256K1RSignatureFormat(sig []byte) error {
 	if len(sig) != SECP256K1RSigLen {
 		return errInvalidSigLen
 	}
@@ -5,8 +5,11 @@ package crypto
 
 import (
 	"bytes"
+	"math/big"
 	"testing"
 
+	"github.com/ava-labs/go-ethereum/crypto/secp256k1"
+
 	"github.com/ava-labs/gecko/cache"
 	"github.com/ava-labs/gecko/utils/formatting"
 	"github.com/ava-labs/gecko/utils/hashing"
@@ -73,3 +76,96 @@ func TestGenRecreate(t *testing.T) {
 		}
 	}
 }
+
+// malleate returns the other valid encoding of [sig], which has s replaced by
+// n - s and the recovery ID flipped
+func malleate(sig []byte) []byte {
+	var s big.Int
+	s.SetBytes(sig[32:64])
+	s.Sub(secp256k1.S256().Params().N, &s)
+
+	malleated := make([]byte, SECP256K1RSigLen)
+	copy(malleated, sig[:32])
+	sBytes := s.Bytes()
+	copy(malleated[64-len(sBytes):64], sBytes)
+	malleated[64] = sig[64] ^ 1
+	return malleated
+}
+
+func TestSignaturesAreCanonical(t *testing.T) {
+	f := FactorySECP256K1R{}
+
+	msg := []byte{1, 2, 3}
+	for i := 0; i < 100; i++ {
+		key, err := f.NewPrivateKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		sig, err := key.Sign(msg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := VerifySECP256K1RSignatureFormat(sig); err != nil {
+			t.Fatalf("Sign produced a non-canonical signature: %s", err)
+		}
+	}
+}
+
+func TestMalleatedSignatureRejected(t *testing.T) {
+	f := FactorySECP256K1R{Cache: cache.LRU{Size: 2}}
+	key, err := f.NewPrivateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	msg := []byte{1, 2, 3}
+	sig, err := key.Sign(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.RecoverPublicKey(msg, sig); err != nil {
+		t.Fatal(err)
+	}
+
+	malleated := malleate(sig)
+	if err := VerifySECP256K1RSignatureFormat(malleated); err == nil {
+		t.Fatalf("Should have rejected a high-S signature")
+	}
+	if _, err := f.RecoverPublicKey(msg, malleated); err == nil {
+		t.Fatalf("Should have failed to recover a public key from a high-S signature")
+	}
+	if key.PublicKey().Verify(msg, malleated) {
+		t.Fatalf("Should have failed to verify a high-S signature")
+	}
+}
+
+func TestNonCanonicalSignatureEncodings(t *testing.T) {
+	f := FactorySECP256K1R{}
+	key, err := f.NewPrivateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig, err := key.Sign([]byte{1, 2, 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	badRecoveryID := make([]byte, SECP256K1RSigLen)
+	copy(badRecoveryID, sig)
+	badRecoveryID[64] += 2
+
+	zeroR := make([]byte, SECP256K1RSigLen)
+	copy(zeroR[32:], sig[32:])
+
+	tests := [][]byte{
+		sig[:SECP256K1RSigLen-1],
+		append(append([]byte{}, sig...), 0),
+		badRecoveryID,
+		zeroR,
+	}
+	for i, test := range tests {
+		if err := VerifySECP256K1RSignatureFormat(test); err == nil {
+			t.Fatalf("test %d: should have rejected the signature", i)
+		}
+	}
+}
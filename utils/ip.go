@@ -8,7 +8,6 @@ import (
 	"fmt"
 	"net"
 	"strconv"
-	"strings"
 )
 
 var (
@@ -32,22 +31,28 @@ func (ipDesc IPDesc) PortString() string {
 	return fmt.Sprintf(":%d", ipDesc.Port)
 }
 
+// String returns the IP and port as "host:port". IPv6 addresses are enclosed
+// in square brackets, as in "[::1]:9651".
 func (ipDesc IPDesc) String() string {
-	return fmt.Sprintf("%s%s", ipDesc.IP, ipDesc.PortString())
+	return net.JoinHostPort(ipDesc.IP.String(), strconv.FormatUint(uint64(ipDesc.Port), 10))
 }
 
-// ToIPDesc ...
-// TODO: this was kinda hacked together, it should be verified.
+// IsIPv4 returns true if the IP is an IPv4 address, including an IPv4 address
+// in its IPv6 form
+func (ipDesc IPDesc) IsIPv4() bool { return ipDesc.IP.To4() != nil }
+
+// ToIPDesc parses an IP and port formatted as "host:port", where an IPv6 host
+// is enclosed in square brackets
 func ToIPDesc(str string) (IPDesc, error) {
-	parts := strings.Split(str, ":")
-	if len(parts) != 2 {
+	host, portStr, err := net.SplitHostPort(str)
+	if err != nil {
 		return IPDesc{}, errBadIP
 	}
-	port, err := strconv.ParseUint(parts[1], 10 /*=base*/, 16 /*=size*/)
+	port, err := strconv.ParseUint(portStr, 10 /*=base*/, 16 /*=size*/)
 	if err != nil {
 		return IPDesc{}, err
 	}
-	ip := net.ParseIP(parts[0])
+	ip := net.ParseIP(host)
 	if ip == nil {
 		return IPDesc{}, errBadIP
 	}
@@ -0,0 +1,51 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package utils
+
+import (
+	"net"
+	"testing"
+)
+
+func TestToIPDesc(t *testing.T) {
+	tests := []struct {
+		str  string
+		ip   IPDesc
+		ipv4 bool
+	}{
+		{"127.0.0.1:9651", IPDesc{IP: net.ParseIP("127.0.0.1"), Port: 9651}, true},
+		{"[::1]:9651", IPDesc{IP: net.ParseIP("::1"), Port: 9651}, false},
+		{"[2001:db8::1]:80", IPDesc{IP: net.ParseIP("2001:db8::1"), Port: 80}, false},
+		{"[::ffff:1.2.3.4]:80", IPDesc{IP: net.ParseIP("1.2.3.4"), Port: 80}, true},
+	}
+	for _, test := range tests {
+		ip, err := ToIPDesc(test.str)
+		if err != nil {
+			t.Fatalf("Failed to parse %s: %s", test.str, err)
+		}
+		if !ip.Equal(test.ip) {
+			t.Fatalf("Expected %s but got %s", test.ip, ip)
+		}
+		if ip.IsIPv4() != test.ipv4 {
+			t.Fatalf("Expected IsIPv4 of %s to be %t", ip, test.ipv4)
+		}
+	}
+}
+
+func TestToIPDescInvalid(t *testing.T) {
+	for _, str := range []string{"", "127.0.0.1", "::1:9651", "localhost:9651", "127.0.0.1:65536"} {
+		if _, err := ToIPDesc(str); err == nil {
+			t.Fatalf("Should have failed to parse %q", str)
+		}
+	}
+}
+
+func TestIPDescString(t *testing.T) {
+	if str := (IPDesc{IP: net.ParseIP("127.0.0.1"), Port: 9651}).String(); str != "127.0.0.1:9651" {
+		t.Fatalf("Expected 127.0.0.1:9651 but got %s", str)
+	}
+	if str := (IPDesc{IP: net.ParseIP("::1"), Port: 9651}).String(); str != "[::1]:9651" {
+		t.Fatalf("Expected [::1]:9651 but got %s", str)
+	}
+}
@@ -31,8 +31,27 @@ func (lc lowercase) NewRequest(r *http.Request) rpc.CodecRequest {
 	return &request{lc.Codec.NewRequest(r).(*json2.CodecRequest)}
 }
 
+// CodedError is an error that carries a stable code. When a service returns a
+// CodedError, or an error wrapping one, its code is reported as the code of the
+// JSON-RPC error.
+type CodedError interface {
+	error
+	ErrorCode() int
+}
+
 type request struct{ *json2.CodecRequest }
 
+func (r *request) WriteError(w http.ResponseWriter, status int, err error) {
+	var coded CodedError
+	if errors.As(err, &coded) {
+		err = &json2.Error{
+			Code:    json2.ErrorCode(coded.ErrorCode()),
+			Message: err.Error(),
+		}
+	}
+	r.CodecRequest.WriteError(w, status, err)
+}
+
 func (r *request) Method() (string, error) {
 	method, err := r.CodecRequest.Method()
 	methodSections := strings.SplitN(method, ".", 2)
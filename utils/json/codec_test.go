@@ -0,0 +1,61 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package json
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type codedError struct{ code int }
+
+func (e codedError) Error() string  { return "coded error" }
+func (e codedError) ErrorCode() int { return e.code }
+
+func writeError(t *testing.T, err error) (int, string) {
+	body := `{"jsonrpc":"2.0","method":"test.method","params":[{}],"id":1}`
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	r.Header.Set("Content-Type", "application/json")
+
+	w := httptest.NewRecorder()
+	NewCodec().NewRequest(r).WriteError(w, http.StatusBadRequest, err)
+
+	reply := struct {
+		Error struct {
+			Code    int    `json:"code"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}{}
+	if err := json.Unmarshal(w.Body.Bytes(), &reply); err != nil {
+		t.Fatal(err)
+	}
+	return reply.Error.Code, reply.Error.Message
+}
+
+func TestWriteCodedError(t *testing.T) {
+	err := fmt.Errorf("problem issuing transaction: %w", codedError{code: 1005})
+
+	code, message := writeError(t, err)
+	if code != 1005 {
+		t.Fatalf("Should have reported code %d, reported %d", 1005, code)
+	}
+	if message != err.Error() {
+		t.Fatalf("Should have reported message %q, reported %q", err.Error(), message)
+	}
+}
+
+func TestWriteUncodedError(t *testing.T) {
+	code, message := writeError(t, errors.New("uncoded error"))
+	if code != -32000 {
+		t.Fatalf("Should have reported the server error code, reported %d", code)
+	}
+	if message != "uncoded error" {
+		t.Fatalf("Wrong message %q", message)
+	}
+}
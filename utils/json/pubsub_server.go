@@ -12,6 +12,8 @@ import (
 	"github.com/gorilla/websocket"
 
 	"github.com/ava-labs/gecko/snow"
+	"github.com/ava-labs/gecko/utils/bloom"
+	"github.com/ava-labs/gecko/utils/formatting"
 )
 
 const (
@@ -30,8 +32,9 @@ const (
 	// Send pings to peer with this period. Must be less than pongWait.
 	pingPeriod = (pongWait * 9) / 10
 
-	// Maximum message size allowed from peer.
-	maxMessageSize = 512 // bytes
+	// Maximum message size allowed from peer. Must be large enough to hold a
+	// CB58 encoded bloom filter.
+	maxMessageSize = 8 * 1024 // bytes
 
 	// Maximum number of pending messages to send to a peer.
 	maxPendingMessages = 256 // messages
@@ -48,11 +51,16 @@ var (
 )
 
 // PubSubServer maintains the set of active clients and sends messages to the clients.
+//
+// A client may subscribe to a channel with a bloom filter. A filtered
+// subscription only receives the messages published to the channel with
+// PublishFiltered that have a key contained in the filter.
 type PubSubServer struct {
 	ctx *snow.Context
 
-	lock     sync.Mutex
-	conns    map[*Connection]map[string]struct{}
+	lock sync.Mutex
+	// conn -> channel -> filter of the subscription, nil if unfiltered
+	conns    map[*Connection]map[string]*bloom.Filter
 	channels map[string]map[*Connection]struct{}
 }
 
@@ -60,7 +68,7 @@ type PubSubServer struct {
 func NewPubSubServer(ctx *snow.Context) *PubSubServer {
 	return &PubSubServer{
 		ctx:      ctx,
-		conns:    make(map[*Connection]map[string]struct{}),
+		conns:    make(map[*Connection]map[string]*bloom.Filter),
 		channels: make(map[string]map[*Connection]struct{}),
 	}
 }
@@ -75,8 +83,14 @@ func (s *PubSubServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	s.addConnection(conn)
 }
 
-// Publish ...
+// Publish [msg] to the unfiltered subscribers of [channel]
 func (s *PubSubServer) Publish(channel string, msg interface{}) {
+	s.PublishFiltered(channel, msg, nil)
+}
+
+// PublishFiltered publishes [msg] to the unfiltered subscribers of [channel]
+// and to the filtered subscribers whose filter contains any of [keys]
+func (s *PubSubServer) PublishFiltered(channel string, msg interface{}, keys [][]byte) {
 	s.lock.Lock()
 	defer s.lock.Unlock()
 
@@ -92,6 +106,10 @@ func (s *PubSubServer) Publish(channel string, msg interface{}) {
 	}
 
 	for conn := range conns {
+		if filter := s.conns[conn][channel]; filter != nil && !matches(filter, keys) {
+			continue
+		}
+
 		select {
 		case conn.send <- pubMsg:
 		default:
@@ -116,7 +134,7 @@ func (s *PubSubServer) Register(channel string) error {
 func (s *PubSubServer) addConnection(conn *Connection) {
 	s.lock.Lock()
 	defer s.lock.Unlock()
-	s.conns[conn] = make(map[string]struct{})
+	s.conns[conn] = make(map[string]*bloom.Filter)
 
 	go conn.writePump()
 	go conn.readPump()
@@ -135,9 +153,13 @@ func (s *PubSubServer) removeConnection(conn *Connection) {
 	for channel := range channels {
 		delete(s.channels[channel], conn)
 	}
+	delete(s.conns, conn)
 }
 
-func (s *PubSubServer) addChannel(conn *Connection, channel string) {
+// addChannel subscribes [conn] to [channel]. If [filter] is non-nil, only
+// messages matching the filter will be sent. Resubscribing to a channel
+// replaces the previous filter.
+func (s *PubSubServer) addChannel(conn *Connection, channel string, filter *bloom.Filter) {
 	s.lock.Lock()
 	defer s.lock.Unlock()
 
@@ -151,7 +173,7 @@ func (s *PubSubServer) addChannel(conn *Connection, channel string) {
 		return
 	}
 
-	channels[channel] = struct{}{}
+	channels[channel] = filter
 	conns[conn] = struct{}{}
 }
 
@@ -173,14 +195,32 @@ func (s *PubSubServer) removeChannel(conn *Connection, channel string) {
 	delete(conns, conn)
 }
 
+// matches returns true if any of [keys] is contained in [filter]
+func matches(filter *bloom.Filter, keys [][]byte) bool {
+	for _, key := range keys {
+		if filter.Check(key) {
+			return true
+		}
+	}
+	return false
+}
+
 type publish struct {
 	Channel string      `json:"channel"`
 	Value   interface{} `json:"value"`
 }
 
 type subscribe struct {
-	Channel     string `json:"channel"`
-	Unsubscribe bool   `json:"unsubscribe"`
+	Channel     string       `json:"channel"`
+	Unsubscribe bool         `json:"unsubscribe"`
+	Filter      *filterParam `json:"filter"`
+}
+
+// filterParam is a bloom filter uploaded by a client. See bloom.Filter for
+// the hash functions the client must use to populate the bit array.
+type filterParam struct {
+	NumHashes int             `json:"numHashes"`
+	Bits      formatting.CB58 `json:"bits"`
 }
 
 // Connection is a representation of the websocket connection.
@@ -220,9 +260,18 @@ func (c *Connection) readPump() {
 		}
 		if msg.Unsubscribe {
 			c.s.removeChannel(c, msg.Channel)
-		} else {
-			c.s.addChannel(c, msg.Channel)
+			continue
+		}
+
+		var filter *bloom.Filter
+		if msg.Filter != nil {
+			filter, err = bloom.New(msg.Filter.NumHashes, msg.Filter.Bits.Bytes)
+			if err != nil {
+				c.s.ctx.Log.Debug("Dropping subscription with an invalid filter: %s", err)
+				continue
+			}
 		}
+		c.s.addChannel(c, msg.Channel, filter)
 	}
 }
 
@@ -0,0 +1,76 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package json
+
+import (
+	"testing"
+
+	"github.com/ava-labs/gecko/snow"
+	"github.com/ava-labs/gecko/utils/bloom"
+)
+
+// newTestConnection registers a connection with [s] that isn't backed by a
+// websocket
+func newTestConnection(s *PubSubServer) *Connection {
+	conn := &Connection{s: s, send: make(chan interface{}, maxPendingMessages)}
+	s.conns[conn] = make(map[string]*bloom.Filter)
+	return conn
+}
+
+func TestPubSubServerPublishFiltered(t *testing.T) {
+	s := NewPubSubServer(snow.DefaultContextTest())
+	if err := s.Register("accepted"); err != nil {
+		t.Fatal(err)
+	}
+
+	filter, err := bloom.New(3, make([]byte, 64))
+	if err != nil {
+		t.Fatal(err)
+	}
+	filter.Add([]byte("mine"))
+
+	unfiltered := newTestConnection(s)
+	filtered := newTestConnection(s)
+	s.addChannel(unfiltered, "accepted", nil)
+	s.addChannel(filtered, "accepted", filter)
+
+	s.PublishFiltered("accepted", "theirs", [][]byte{[]byte("theirs")})
+	s.PublishFiltered("accepted", "mine", [][]byte{[]byte("theirs"), []byte("mine")})
+	s.Publish("accepted", "unkeyed")
+
+	if pending := len(unfiltered.send); pending != 3 {
+		t.Fatalf("Unfiltered subscriber should have received 3 messages, received %d", pending)
+	}
+	if pending := len(filtered.send); pending != 1 {
+		t.Fatalf("Filtered subscriber should have received 1 message, received %d", pending)
+	}
+	if msg := (<-filtered.send).(*publish); msg.Value != "mine" {
+		t.Fatalf("Filtered subscriber received the wrong message: %v", msg.Value)
+	}
+}
+
+func TestPubSubServerResubscribeReplacesFilter(t *testing.T) {
+	s := NewPubSubServer(snow.DefaultContextTest())
+	if err := s.Register("accepted"); err != nil {
+		t.Fatal(err)
+	}
+
+	filter, err := bloom.New(3, make([]byte, 64))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	conn := newTestConnection(s)
+	s.addChannel(conn, "accepted", filter)
+	s.Publish("accepted", "dropped")
+	if pending := len(conn.send); pending != 0 {
+		t.Fatalf("Filtered subscriber shouldn't have received any messages, received %d", pending)
+	}
+
+	s.addChannel(conn, "accepted", nil)
+	s.Publish("accepted", "delivered")
+	if pending := len(conn.send); pending != 1 {
+		t.Fatalf("Resubscribed subscriber should have received 1 message, received %d", pending)
+	}
+}
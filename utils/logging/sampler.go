@@ -0,0 +1,81 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package logging
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ava-labs/gecko/utils/timer"
+)
+
+// Sampler limits how often a high frequency event is logged, so that a flood of
+// the event, such as invalid messages sent during an attack, can't make logging
+// the bottleneck. The occurrences that aren't logged are counted, and the count
+// is reported with the next occurrence that is.
+//
+// The zero value logs every occurrence.
+type Sampler struct {
+	// Every is the number of occurrences per occurrence logged. If 0, every
+	// occurrence may be logged.
+	Every uint64
+	// Interval is the least time between occurrences logged. If 0, occurrences
+	// may be logged at any time.
+	Interval time.Duration
+
+	lock       sync.Mutex
+	clock      timer.Clock
+	logged     bool
+	lastLogged time.Time
+	suppressed uint64
+}
+
+// Sample records an occurrence of the event. Returns true if it should be
+// logged, along with the number of occurrences suppressed since the last one
+// logged.
+func (s *Sampler) Sample() (bool, uint64) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	now := s.clock.Time()
+	if s.logged && (s.suppressed+1 < s.Every || now.Sub(s.lastLogged) < s.Interval) {
+		s.suppressed++
+		return false, 0
+	}
+
+	suppressed := s.suppressed
+	s.logged = true
+	s.lastLogged = now
+	s.suppressed = 0
+	return true, suppressed
+}
+
+// Log [format], formatted with [args], at [level] to [log] if this occurrence
+// of the event is sampled. The number of occurrences suppressed since the last
+// one logged is appended.
+func (s *Sampler) Log(log Logger, level Level, format string, args ...interface{}) {
+	sampled, suppressed := s.Sample()
+	if !sampled {
+		return
+	}
+	if suppressed > 0 {
+		format += " (%d similar messages suppressed)"
+		args = append(args, suppressed)
+	}
+
+	switch level {
+	case Fatal:
+		log.Fatal(format, args...)
+	case Error:
+		log.Error(format, args...)
+	case Warn:
+		log.Warn(format, args...)
+	case Info:
+		log.Info(format, args...)
+	case Debug:
+		log.Debug(format, args...)
+	case Verbo:
+		log.Verbo(format, args...)
+	}
+}
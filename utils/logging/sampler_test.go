@@ -0,0 +1,57 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package logging
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSamplerEvery(t *testing.T) {
+	s := Sampler{Every: 3}
+
+	expected := []bool{true, false, false, true, false, false, true}
+	for i, shouldLog := range expected {
+		sampled, suppressed := s.Sample()
+		if sampled != shouldLog {
+			t.Fatalf("Occurrence %d: expected sampled to be %t", i, shouldLog)
+		}
+		if sampled && i > 0 && suppressed != 2 {
+			t.Fatalf("Occurrence %d: expected 2 suppressed occurrences but got %d", i, suppressed)
+		}
+	}
+}
+
+func TestSamplerInterval(t *testing.T) {
+	s := Sampler{Interval: time.Second}
+	now := time.Unix(1000000, 0)
+	s.clock.Set(now)
+
+	if sampled, _ := s.Sample(); !sampled {
+		t.Fatalf("Should have sampled the first occurrence")
+	}
+	for i := 0; i < 5; i++ {
+		if sampled, _ := s.Sample(); sampled {
+			t.Fatalf("Shouldn't have sampled an occurrence within the interval")
+		}
+	}
+
+	s.clock.Set(now.Add(time.Second))
+	sampled, suppressed := s.Sample()
+	if !sampled {
+		t.Fatalf("Should have sampled an occurrence once the interval passed")
+	}
+	if suppressed != 5 {
+		t.Fatalf("Expected 5 suppressed occurrences but got %d", suppressed)
+	}
+}
+
+func TestSamplerZero(t *testing.T) {
+	s := Sampler{}
+	for i := 0; i < 10; i++ {
+		if sampled, suppressed := s.Sample(); !sampled || suppressed != 0 {
+			t.Fatalf("Should have sampled every occurrence")
+		}
+	}
+}
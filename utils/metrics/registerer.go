@@ -0,0 +1,150 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package metrics
+
+import (
+	"fmt"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/ava-labs/gecko/utils/logging"
+)
+
+const (
+	// Namespace prefixes the name of every metric
+	Namespace = "gecko"
+
+	// ChainLabel is the label holding the alias of the chain a metric
+	// describes
+	ChainLabel = "chain"
+)
+
+// LatencyBuckets are the buckets, in seconds, of histograms of network round
+// trip durations
+var LatencyBuckets = prometheus.ExponentialBuckets(0.01, 2, 10)
+
+// Registerer creates the metrics of a subsystem and registers them. Metrics
+// are named gecko_<subsystem>_<name>. Metrics of a chain are labelled with the
+// chain's alias, so the same metric can be aggregated across chains.
+//
+// For compatibility with existing dashboards, each metric is also exposed
+// under the name it had before metrics were standardized: gecko_<name> for
+// metrics of the node and gecko_<chain>_<name> for metrics of a chain.
+type Registerer struct {
+	log        logging.Logger
+	registerer prometheus.Registerer
+
+	subsystem string
+	labels    prometheus.Labels
+
+	legacyNamespace string
+}
+
+// NewRegisterer returns a registerer of the node's metrics of [subsystem]
+func NewRegisterer(log logging.Logger, registerer prometheus.Registerer, subsystem string) *Registerer {
+	return &Registerer{
+		log:             log,
+		registerer:      registerer,
+		subsystem:       subsystem,
+		legacyNamespace: Namespace,
+	}
+}
+
+// NewChainRegisterer returns a registerer of the metrics of [subsystem] of
+// the chain [chain], which should be the chain's primary alias
+func NewChainRegisterer(log logging.Logger, registerer prometheus.Registerer, chain, subsystem string) *Registerer {
+	return &Registerer{
+		log:             log,
+		registerer:      registerer,
+		subsystem:       subsystem,
+		labels:          prometheus.Labels{ChainLabel: chain},
+		legacyNamespace: fmt.Sprintf("%s_%s", Namespace, chain),
+	}
+}
+
+// NewGauge returns a registered gauge named [name]
+func (r *Registerer) NewGauge(name, help string) prometheus.Gauge {
+	gauge := &legacyGauge{
+		Gauge: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace:   Namespace,
+			Subsystem:   r.subsystem,
+			Name:        name,
+			Help:        help,
+			ConstLabels: r.labels,
+		}),
+		legacy: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: r.legacyNamespace,
+			Name:      name,
+			Help:      help,
+		}),
+	}
+	r.register(name, gauge.Gauge, gauge.legacy)
+	return gauge
+}
+
+// NewCounter returns a registered counter named [name]
+func (r *Registerer) NewCounter(name, help string) prometheus.Counter {
+	counter := &legacyCounter{
+		Counter: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace:   Namespace,
+			Subsystem:   r.subsystem,
+			Name:        name,
+			Help:        help,
+			ConstLabels: r.labels,
+		}),
+		legacy: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: r.legacyNamespace,
+			Name:      name,
+			Help:      help,
+		}),
+	}
+	r.register(name, counter.Counter, counter.legacy)
+	return counter
+}
+
+// NewHistogram returns a registered histogram named [name] whose observations
+// are counted in [buckets]. Histograms were added after metrics were
+// standardized, so they aren't exposed under a legacy name.
+func (r *Registerer) NewHistogram(name, help string, buckets []float64) prometheus.Histogram {
+	histogram := prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace:   Namespace,
+		Subsystem:   r.subsystem,
+		Name:        name,
+		Help:        help,
+		ConstLabels: r.labels,
+		Buckets:     buckets,
+	})
+	r.register(name, histogram)
+	return histogram
+}
+
+func (r *Registerer) register(name string, collectors ...prometheus.Collector) {
+	for _, collector := range collectors {
+		if err := r.registerer.Register(collector); err != nil {
+			r.log.Error("Failed to register %s statistics due to %s", name, err)
+		}
+	}
+}
+
+// legacyGauge is a gauge that is also exposed under its legacy name
+type legacyGauge struct {
+	prometheus.Gauge
+	legacy prometheus.Gauge
+}
+
+func (g *legacyGauge) Set(v float64)     { g.Gauge.Set(v); g.legacy.Set(v) }
+func (g *legacyGauge) Inc()              { g.Gauge.Inc(); g.legacy.Inc() }
+func (g *legacyGauge) Dec()              { g.Gauge.Dec(); g.legacy.Dec() }
+func (g *legacyGauge) Add(v float64)     { g.Gauge.Add(v); g.legacy.Add(v) }
+func (g *legacyGauge) Sub(v float64)     { g.Gauge.Sub(v); g.legacy.Sub(v) }
+func (g *legacyGauge) SetToCurrentTime() { g.Gauge.SetToCurrentTime(); g.legacy.SetToCurrentTime() }
+
+// legacyCounter is a counter that is also exposed under its legacy name
+type legacyCounter struct {
+	prometheus.Counter
+	legacy prometheus.Counter
+}
+
+func (c *legacyCounter) Inc()          { c.Counter.Inc(); c.legacy.Inc() }
+func (c *legacyCounter) Add(v float64) { c.Counter.Add(v); c.legacy.Add(v) }
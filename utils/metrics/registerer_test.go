@@ -0,0 +1,81 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package metrics
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/ava-labs/gecko/utils/logging"
+)
+
+func TestChainRegistererNames(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	r := NewChainRegisterer(logging.NoLog{}, registry, "X", "consensus")
+	r.NewGauge("processing", "Number of processing blocks")
+
+	// The standardized name is registered with the chain label
+	if err := registry.Register(prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace:   "gecko",
+		Subsystem:   "consensus",
+		Name:        "processing",
+		ConstLabels: prometheus.Labels{ChainLabel: "X"},
+	})); err == nil {
+		t.Fatalf("Should have registered gecko_consensus_processing")
+	}
+
+	// The legacy name is registered without labels
+	if err := registry.Register(prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: "gecko_X",
+		Name:      "processing",
+	})); err == nil {
+		t.Fatalf("Should have registered gecko_X_processing")
+	}
+}
+
+func TestRegistererNames(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	r := NewRegisterer(logging.NoLog{}, registry, "network")
+	r.NewCounter("peerlist_sent", "Number of peerlist messages sent")
+
+	if err := registry.Register(prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "gecko",
+		Subsystem: "network",
+		Name:      "peerlist_sent",
+	})); err == nil {
+		t.Fatalf("Should have registered gecko_network_peerlist_sent")
+	}
+	if err := registry.Register(prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "gecko",
+		Name:      "peerlist_sent",
+	})); err == nil {
+		t.Fatalf("Should have registered gecko_peerlist_sent")
+	}
+}
+
+func TestChainRegistererHistogram(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	r := NewChainRegisterer(logging.NoLog{}, registry, "X", "engine")
+	r.NewHistogram("poll_duration_seconds", "Duration of finished polls", LatencyBuckets).Observe(0.5)
+
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(families) != 1 {
+		t.Fatalf("Should have registered only the standardized histogram but registered %d metrics", len(families))
+	}
+	family := families[0]
+	if name := family.GetName(); name != "gecko_engine_poll_duration_seconds" {
+		t.Fatalf("Registered %s instead of gecko_engine_poll_duration_seconds", name)
+	}
+	metric := family.GetMetric()[0]
+	if labels := metric.GetLabel(); len(labels) != 1 || labels[0].GetName() != ChainLabel || labels[0].GetValue() != "X" {
+		t.Fatalf("Histogram should have been labelled with the chain")
+	}
+	if count := metric.GetHistogram().GetSampleCount(); count != 1 {
+		t.Fatalf("Histogram should have had 1 observation but had %d", count)
+	}
+}
@@ -0,0 +1,58 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package wrappers
+
+import (
+	"sync"
+)
+
+const (
+	// DefaultMaxPooledSize is the largest capacity, in bytes, of a byte array
+	// that will be kept in a PackerPool by default
+	DefaultMaxPooledSize = 1 << 16
+)
+
+// PackerPool is a pool of Packers whose byte arrays are reused, which removes
+// the need to allocate a new byte array every time a value is packed.
+//
+// After a packer has been returned to the pool, neither the packer nor its
+// byte array may be referenced by the caller. Any bytes that must outlive the
+// packer must be copied out before it is returned.
+type PackerPool struct {
+	// The largest allowed size of expanding the byte array of a packer
+	// returned by this pool
+	MaxSize int
+	// Packers whose byte arrays have grown beyond this capacity are dropped
+	// rather than returned to the pool. If 0, DefaultMaxPooledSize is used.
+	MaxPooledSize int
+
+	pool sync.Pool
+}
+
+// Get returns an empty packer from the pool, or a new packer if the pool is
+// empty
+func (pp *PackerPool) Get() *Packer {
+	if p, ok := pp.pool.Get().(*Packer); ok {
+		return p
+	}
+	return &Packer{
+		MaxSize: pp.MaxSize,
+		Bytes:   []byte{},
+	}
+}
+
+// Put resets [p] and returns it to the pool
+func (pp *PackerPool) Put(p *Packer) {
+	maxPooledSize := pp.MaxPooledSize
+	if maxPooledSize == 0 {
+		maxPooledSize = DefaultMaxPooledSize
+	}
+	if cap(p.Bytes) > maxPooledSize {
+		return
+	}
+
+	p.Reset()
+	p.MaxSize = pp.MaxSize
+	pp.pool.Put(p)
+}
@@ -0,0 +1,85 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package wrappers
+
+import (
+	"testing"
+)
+
+func TestPackerReset(t *testing.T) {
+	p := Packer{MaxSize: 4}
+	p.PackLong(1)
+	if !p.Errored() {
+		t.Fatalf("Packer.PackLong should have exceeded the max size")
+	}
+
+	p.Reset()
+	if p.Errored() {
+		t.Fatalf("Packer.Reset should have cleared the error")
+	}
+	if p.Offset != 0 {
+		t.Fatalf("Packer.Reset should have cleared the offset, got %d", p.Offset)
+	}
+	if len(p.Bytes) != 0 {
+		t.Fatalf("Packer.Reset should have emptied the byte array, got %d byte(s)", len(p.Bytes))
+	}
+
+	p.PackInt(1)
+	if p.Errored() {
+		t.Fatal(p.Err)
+	}
+}
+
+func TestPackerPoolReturnsEmptyPacker(t *testing.T) {
+	pool := PackerPool{MaxSize: 8}
+
+	for i := 0; i < 16; i++ {
+		p := pool.Get()
+		if p.Errored() || p.Offset != 0 || len(p.Bytes) != 0 {
+			t.Fatalf("PackerPool.Get returned a packer that wasn't reset")
+		}
+		if p.MaxSize != 8 {
+			t.Fatalf("PackerPool.Get returned a packer with max size %d but expected %d", p.MaxSize, 8)
+		}
+		p.PackLong(uint64(i))
+		p.PackByte(1) // Exceeds the max size
+		pool.Put(p)
+	}
+}
+
+func TestPackerPoolDropsLargePackers(t *testing.T) {
+	pool := PackerPool{MaxSize: 1024, MaxPooledSize: 16}
+
+	p := pool.Get()
+	p.PackFixedBytes(make([]byte, 32))
+	pool.Put(p)
+
+	// The pool may be emptied at any time, so this only verifies that a large
+	// byte array is never handed out again
+	if p := pool.Get(); cap(p.Bytes) > 16 {
+		t.Fatalf("PackerPool.Put should have dropped a packer with capacity %d", cap(p.Bytes))
+	}
+}
+
+func BenchmarkPackerNoPool(b *testing.B) {
+	payload := make([]byte, 1024)
+	b.ReportAllocs()
+	for n := 0; n < b.N; n++ {
+		p := Packer{MaxSize: 2048}
+		p.PackInt(uint32(n))
+		p.PackBytes(payload)
+	}
+}
+
+func BenchmarkPackerPool(b *testing.B) {
+	payload := make([]byte, 1024)
+	pool := PackerPool{MaxSize: 2048}
+	b.ReportAllocs()
+	for n := 0; n < b.N; n++ {
+		p := pool.Get()
+		p.PackInt(uint32(n))
+		p.PackBytes(payload)
+		pool.Put(p)
+	}
+}
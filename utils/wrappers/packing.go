@@ -7,6 +7,7 @@ import (
 	"encoding/binary"
 	"errors"
 	"math"
+	"net"
 
 	"github.com/ava-labs/gecko/utils"
 	"github.com/ava-labs/gecko/utils/hashing"
@@ -46,6 +47,14 @@ type Packer struct {
 	Offset int
 }
 
+// Reset clears the packer so that it can be reused. The capacity of the byte
+// array is kept, but its contents will be overwritten by future packing.
+func (p *Packer) Reset() {
+	p.Err = nil
+	p.Bytes = p.Bytes[:0]
+	p.Offset = 0
+}
+
 // CheckSpace requires that there is at least [bytes] of write space left in the
 // byte array. If this is not true, an error is added to the packer
 func (p *Packer) CheckSpace(bytes int) {
@@ -268,15 +277,21 @@ func (p *Packer) UnpackStr() string {
 	return string(p.UnpackFixedBytes(int(strSize)))
 }
 
-// PackIP unpacks an ip port pair from the byte array
+// PackIP packs an ip port pair to the byte array. The IP is packed in its 16
+// byte form, so IPv4 and IPv6 addresses are packed alike.
 func (p *Packer) PackIP(ip utils.IPDesc) {
-	p.PackFixedBytes(ip.IP.To16())
+	ipBytes := ip.IP.To16()
+	if ipBytes == nil {
+		p.Add(errInvalidInput)
+		return
+	}
+	p.PackFixedBytes(ipBytes)
 	p.PackShort(ip.Port)
 }
 
 // UnpackIP unpacks an ip port pair from the byte array
 func (p *Packer) UnpackIP() utils.IPDesc {
-	ip := p.UnpackFixedBytes(16)
+	ip := p.UnpackFixedBytes(net.IPv6len)
 	port := p.UnpackShort()
 	return utils.IPDesc{
 		IP:   ip,
@@ -316,6 +331,20 @@ func TryUnpackByte(packer *Packer) interface{} {
 	return packer.UnpackByte()
 }
 
+// TryPackBool attempts to pack the value as a bool
+func TryPackBool(packer *Packer, valIntf interface{}) {
+	if val, ok := valIntf.(bool); ok {
+		packer.PackBool(val)
+	} else {
+		packer.Add(errBadType)
+	}
+}
+
+// TryUnpackBool attempts to unpack a value as a bool
+func TryUnpackBool(packer *Packer) interface{} {
+	return packer.UnpackBool()
+}
+
 // TryPackShort attempts to pack the value as a short
 func TryPackShort(packer *Packer, valIntf interface{}) {
 	if val, ok := valIntf.(uint16); ok {
@@ -5,7 +5,10 @@ package wrappers
 
 import (
 	"bytes"
+	"net"
 	"testing"
+
+	"github.com/ava-labs/gecko/utils"
 )
 
 func TestPackerByte(t *testing.T) {
@@ -151,3 +154,38 @@ func TestPackBool(t *testing.T) {
 		t.Fatal("got back wrong values")
 	}
 }
+
+func TestPackIP(t *testing.T) {
+	ips := []utils.IPDesc{
+		{IP: net.ParseIP("1.2.3.4"), Port: 9651},
+		{IP: net.ParseIP("2001:db8::1"), Port: 9651},
+	}
+
+	p := Packer{MaxSize: 1024}
+	p.PackIPs(ips)
+	if p.Errored() {
+		t.Fatal(p.Err)
+	}
+
+	p = Packer{Bytes: p.Bytes}
+	unpacked := p.UnpackIPs()
+	if p.Errored() {
+		t.Fatal(p.Err)
+	}
+	if len(unpacked) != len(ips) {
+		t.Fatalf("Expected %d IPs but got %d", len(ips), len(unpacked))
+	}
+	for i, ip := range ips {
+		if !unpacked[i].Equal(ip) {
+			t.Fatalf("Expected %s but got %s", ip, unpacked[i])
+		}
+	}
+}
+
+func TestPackInvalidIP(t *testing.T) {
+	p := Packer{MaxSize: 1024}
+	p.PackIP(utils.IPDesc{IP: net.IP{1, 2, 3}, Port: 9651})
+	if !p.Errored() {
+		t.Fatalf("Should have errored due to the invalid IP")
+	}
+}
@@ -4,14 +4,13 @@
 package avm
 
 import (
-	"errors"
-
 	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/vms/components/verify"
 )
 
 var (
-	errNilAssetID   = errors.New("nil asset ID is not valid")
-	errEmptyAssetID = errors.New("empty asset ID is not valid")
+	errNilAssetID   = verify.NewError(CodeNilAssetID, "nil asset ID is not valid")
+	errEmptyAssetID = verify.NewError(CodeEmptyAssetID, "empty asset ID is not valid")
 )
 
 // Asset ...
@@ -0,0 +1,103 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avm
+
+import (
+	stdmath "math"
+	"time"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/hashing"
+	"github.com/ava-labs/gecko/utils/math"
+	"github.com/ava-labs/gecko/utils/wrappers"
+)
+
+const (
+	// assetStatsPeriod is the length of the periods that the activity of each
+	// asset is rolled up into
+	assetStatsPeriod = 24 * time.Hour
+)
+
+// assetStats are the counters of an asset's activity during a period
+type assetStats struct {
+	// Number of accepted txs that spent, produced or operated on the asset
+	TxCount uint64 `serialize:"true"`
+	// Amount of the asset output by the txs, including change returned to the
+	// spenders. Saturates rather than overflowing.
+	Transferred uint64 `serialize:"true"`
+	// Number of distinct addresses that owned a utxo of the asset that the
+	// txs spent or produced
+	Addresses uint64 `serialize:"true"`
+}
+
+// assetActivity is the activity of an asset in an accepted tx
+type assetActivity struct {
+	// Amount of the asset output by the tx
+	transferred uint64
+	// IDs of the addresses that owned a utxo of the asset the tx spent or
+	// produced
+	addrs ids.Set
+}
+
+// assetActivities maps the ID of each asset a tx touched to its activity
+type assetActivities map[[32]byte]*assetActivity
+
+// get returns the activity of [assetID], adding it if it isn't there yet
+func (a assetActivities) get(assetID ids.ID) *assetActivity {
+	key := assetID.Key()
+	activity, exists := a[key]
+	if !exists {
+		activity = &assetActivity{addrs: ids.Set{}}
+		a[key] = activity
+	}
+	return activity
+}
+
+// spent records that the tx spent [utxo]
+func (a assetActivities) spent(utxo *UTXO) {
+	addUTXOAddresses(a.get(utxo.AssetID()).addrs, utxo)
+}
+
+// produced records that the tx produced [utxo]
+func (a assetActivities) produced(utxo *UTXO) {
+	activity := a.get(utxo.AssetID())
+	addUTXOAddresses(activity.addrs, utxo)
+	if transferable, ok := utxo.Out.(FxTransferable); ok {
+		activity.transferred = addSaturating(activity.transferred, transferable.Amount())
+	}
+}
+
+// assetStatsPeriodAt returns the index of the period, since the unix epoch,
+// that [timestamp], in unix seconds, is in
+func assetStatsPeriodAt(timestamp uint64) uint64 {
+	return timestamp / uint64(assetStatsPeriod/time.Second)
+}
+
+// addSaturating returns [a] + [b], or the maximum uint64 if the sum overflows
+func addSaturating(a, b uint64) uint64 {
+	sum, err := math.Add64(a, b)
+	if err != nil {
+		return stdmath.MaxUint64
+	}
+	return sum
+}
+
+// assetStatsKey returns the ID the stats of [assetID] during [period] are
+// stored under
+func assetStatsKey(assetID ids.ID, period uint64) ids.ID {
+	p := wrappers.Packer{Bytes: make([]byte, hashing.HashLen+wrappers.LongLen)}
+	p.PackFixedBytes(assetID.Bytes())
+	p.PackLong(period)
+	return ids.NewID(hashing.ComputeHash256Array(p.Bytes))
+}
+
+// assetStatsAddressKey returns the ID under which it's recorded that [addr]
+// was counted in the stats of [assetID] during [period]
+func assetStatsAddressKey(assetID ids.ID, period uint64, addr ids.ID) ids.ID {
+	p := wrappers.Packer{Bytes: make([]byte, 2*hashing.HashLen+wrappers.LongLen)}
+	p.PackFixedBytes(assetID.Bytes())
+	p.PackLong(period)
+	p.PackFixedBytes(addr.Bytes())
+	return ids.NewID(hashing.ComputeHash256Array(p.Bytes))
+}
@@ -0,0 +1,139 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avm
+
+import (
+	"github.com/ava-labs/gecko/database"
+	"github.com/ava-labs/gecko/database/prefixdb"
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/vms/components/codec"
+)
+
+// AtomicUTXOs are the UTXOs that chains send each other through the database
+// they share. Each UTXO is stored under the ID of the chain it was sent to, so
+// that chain can consume it.
+//
+// Every chain must agree on how the UTXOs are encoded, so they're encoded with
+// a codec that doesn't depend on the fxs of any one VM.
+type AtomicUTXOs struct {
+	db    database.Database
+	codec codec.Codec
+}
+
+// NewAtomicUTXOs returns the UTXOs stored in [db], which is the database that
+// two chains share
+func NewAtomicUTXOs(db database.Database) *AtomicUTXOs {
+	return &AtomicUTXOs{
+		db:    db,
+		codec: newStaticCodec(),
+	}
+}
+
+// Put sends [utxo] to the chain [chainID]
+func (a *AtomicUTXOs) Put(chainID ids.ID, utxo *UTXO) error {
+	b, err := a.codec.Marshal(utxo)
+	if err != nil {
+		return err
+	}
+	return a.chainDB(chainID).Put(utxo.InputID().Bytes(), b)
+}
+
+// Get returns the UTXO [utxoID] that was sent to the chain [chainID]
+func (a *AtomicUTXOs) Get(chainID, utxoID ids.ID) (*UTXO, error) {
+	b, err := a.chainDB(chainID).Get(utxoID.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	utxo := &UTXO{}
+	if err := a.codec.Unmarshal(b, utxo); err != nil {
+		return nil, err
+	}
+	return utxo, nil
+}
+
+// Remove the UTXO [utxoID] that was sent to the chain [chainID]
+func (a *AtomicUTXOs) Remove(chainID, utxoID ids.ID) error {
+	return a.chainDB(chainID).Delete(utxoID.Bytes())
+}
+
+// List returns the UTXOs that were sent to the chain [chainID] and that
+// reference at least one address in [addrs]
+func (a *AtomicUTXOs) List(chainID ids.ID, addrs ids.ShortSet) ([]*UTXO, error) {
+	it := a.chainDB(chainID).NewIterator()
+	defer it.Release()
+
+	utxos := []*UTXO(nil)
+	for it.Next() {
+		utxo := &UTXO{}
+		if err := a.codec.Unmarshal(it.Value(), utxo); err != nil {
+			return nil, err
+		}
+		addressable, ok := utxo.Out.(FxAddressable)
+		if !ok {
+			continue
+		}
+		for _, addr := range addressable.Addresses() {
+			shortID, err := ids.ToShortID(addr)
+			if err == nil && addrs.Contains(shortID) {
+				utxos = append(utxos, utxo)
+				break
+			}
+		}
+	}
+	return utxos, it.Error()
+}
+
+func (a *AtomicUTXOs) chainDB(chainID ids.ID) database.Database {
+	return prefixdb.New(chainID.Bytes(), a.db)
+}
+
+// AtomicUTXOsBatch records changes to the UTXOs that chains send each other
+// without writing them. A chain writes them, with atomic.WriteAll, in the same
+// batch as its own state, so either both or neither are persisted.
+type AtomicUTXOsBatch struct {
+	utxos   *AtomicUTXOs
+	batches map[[32]byte]database.Batch
+}
+
+// NewBatch returns a batch of changes to these UTXOs
+func (a *AtomicUTXOs) NewBatch() *AtomicUTXOsBatch {
+	return &AtomicUTXOsBatch{
+		utxos:   a,
+		batches: make(map[[32]byte]database.Batch),
+	}
+}
+
+// Put sends [utxo] to the chain [chainID] once the batch is written
+func (b *AtomicUTXOsBatch) Put(chainID ids.ID, utxo *UTXO) error {
+	bytes, err := b.utxos.codec.Marshal(utxo)
+	if err != nil {
+		return err
+	}
+	return b.chainBatch(chainID).Put(utxo.InputID().Bytes(), bytes)
+}
+
+// Remove the UTXO [utxoID] that was sent to the chain [chainID] once the batch
+// is written
+func (b *AtomicUTXOsBatch) Remove(chainID, utxoID ids.ID) error {
+	return b.chainBatch(chainID).Delete(utxoID.Bytes())
+}
+
+// Batches returns the batches of the shared database that hold the changes
+func (b *AtomicUTXOsBatch) Batches() []database.Batch {
+	batches := make([]database.Batch, 0, len(b.batches))
+	for _, batch := range b.batches {
+		batches = append(batches, batch)
+	}
+	return batches
+}
+
+func (b *AtomicUTXOsBatch) chainBatch(chainID ids.ID) database.Batch {
+	key := chainID.Key()
+	batch, exists := b.batches[key]
+	if !exists {
+		batch = b.utxos.chainDB(chainID).NewBatch()
+		b.batches[key] = batch
+	}
+	return batch
+}
@@ -0,0 +1,123 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avm
+
+import (
+	"testing"
+
+	"github.com/ava-labs/gecko/database/memdb"
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/vms/secp256k1fx"
+)
+
+func TestAtomicUTXOs(t *testing.T) {
+	state := NewAtomicUTXOs(memdb.New())
+
+	destChainID := ids.Empty.Prefix(0)
+	otherChainID := ids.Empty.Prefix(1)
+	addr := keys[0].PublicKey().Address()
+
+	utxo := &UTXO{
+		UTXOID: UTXOID{
+			TxID:        ids.Empty.Prefix(2),
+			OutputIndex: 1,
+		},
+		Asset: Asset{ID: asset},
+		Out: &secp256k1fx.TransferOutput{
+			Amt: 12345,
+			OutputOwners: secp256k1fx.OutputOwners{
+				Threshold: 1,
+				Addrs:     []ids.ShortID{addr},
+			},
+		},
+	}
+	if err := state.Put(destChainID, utxo); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := state.Get(otherChainID, utxo.InputID()); err == nil {
+		t.Fatalf("UTXO should only have been sent to %s", destChainID)
+	}
+	result, err := state.Get(destChainID, utxo.InputID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.InputID().Equals(utxo.InputID()) {
+		t.Fatalf("Wrong UTXO returned")
+	}
+
+	addrs := ids.ShortSet{}
+	addrs.Add(addr)
+	utxos, err := state.List(destChainID, addrs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(utxos) != 1 {
+		t.Fatalf("Should have listed 1 UTXO but listed %d", len(utxos))
+	}
+
+	otherAddrs := ids.ShortSet{}
+	otherAddrs.Add(keys[1].PublicKey().Address())
+	if utxos, err := state.List(destChainID, otherAddrs); err != nil {
+		t.Fatal(err)
+	} else if len(utxos) != 0 {
+		t.Fatalf("Shouldn't have listed UTXOs of other addresses")
+	}
+
+	if err := state.Remove(destChainID, utxo.InputID()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := state.Get(destChainID, utxo.InputID()); err == nil {
+		t.Fatalf("UTXO should have been removed")
+	}
+}
+
+func TestAtomicUTXOsBatch(t *testing.T) {
+	state := NewAtomicUTXOs(memdb.New())
+
+	destChainID := ids.Empty.Prefix(0)
+	utxo := &UTXO{
+		UTXOID: UTXOID{
+			TxID:        ids.Empty.Prefix(2),
+			OutputIndex: 1,
+		},
+		Asset: Asset{ID: asset},
+		Out: &secp256k1fx.TransferOutput{
+			Amt: 12345,
+			OutputOwners: secp256k1fx.OutputOwners{
+				Threshold: 1,
+				Addrs:     []ids.ShortID{keys[0].PublicKey().Address()},
+			},
+		},
+	}
+
+	batch := state.NewBatch()
+	if err := batch.Put(destChainID, utxo); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := state.Get(destChainID, utxo.InputID()); err == nil {
+		t.Fatalf("UTXO shouldn't have been sent before the batch was written")
+	}
+	for _, b := range batch.Batches() {
+		if err := b.Write(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := state.Get(destChainID, utxo.InputID()); err != nil {
+		t.Fatal(err)
+	}
+
+	batch = state.NewBatch()
+	if err := batch.Remove(destChainID, utxo.InputID()); err != nil {
+		t.Fatal(err)
+	}
+	for _, b := range batch.Batches() {
+		if err := b.Write(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := state.Get(destChainID, utxo.InputID()); err == nil {
+		t.Fatalf("UTXO should have been removed")
+	}
+}
@@ -4,25 +4,25 @@
 package avm
 
 import (
-	"errors"
-
 	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/snow"
 	"github.com/ava-labs/gecko/utils/math"
 	"github.com/ava-labs/gecko/vms/components/codec"
+	"github.com/ava-labs/gecko/vms/components/verify"
 )
 
 var (
-	errNilTx          = errors.New("nil tx is not valid")
-	errWrongNetworkID = errors.New("tx has wrong network ID")
-	errWrongChainID   = errors.New("tx has wrong chain ID")
+	errNilTx          = verify.NewError(CodeNilTx, "nil tx is not valid")
+	errWrongNetworkID = verify.NewError(CodeWrongNetworkID, "tx has wrong network ID")
+	errWrongChainID   = verify.NewError(CodeWrongChainID, "tx has wrong chain ID")
 
-	errOutputsNotSorted      = errors.New("outputs not sorted")
-	errInputsNotSortedUnique = errors.New("inputs not sorted and unique")
+	errOutputsNotSorted      = verify.NewError(CodeOutputsNotSorted, "outputs not sorted")
+	errInputsNotSortedUnique = verify.NewError(CodeInputsNotSortedUnique, "inputs not sorted and unique")
 
-	errInputOverflow     = errors.New("inputs overflowed uint64")
-	errOutputOverflow    = errors.New("outputs overflowed uint64")
-	errInsufficientFunds = errors.New("insufficient funds")
+	errInputOverflow     = verify.NewError(CodeInputOverflow, "inputs overflowed uint64")
+	errOutputOverflow    = verify.NewError(CodeOutputOverflow, "outputs overflowed uint64")
+	errInsufficientFunds = verify.NewError(CodeInsufficientFunds, "insufficient funds")
+	errInsufficientFee   = verify.NewError(CodeInsufficientFee, "tx doesn't burn enough AVA to pay the tx fee")
 )
 
 // BaseTx is the basis of all transactions.
@@ -49,6 +49,10 @@ func (t *BaseTx) Outputs() []*TransferableOutput { return t.Outs }
 // should not be modified.
 func (t *BaseTx) Inputs() []*TransferableInput { return t.Ins }
 
+// MemoBytes is the memo attached to this transaction. A memo is attached by
+// wrapping the transaction in a MemoTx, so this is always empty.
+func (t *BaseTx) MemoBytes() []byte { return nil }
+
 // InputUTXOs track which UTXOs this transaction is consuming.
 func (t *BaseTx) InputUTXOs() []*UTXOID {
 	utxos := []*UTXOID(nil)
@@ -88,6 +92,18 @@ func (t *BaseTx) UTXOs() []*UTXO {
 
 // SyntacticVerify that this transaction is well-formed.
 func (t *BaseTx) SyntacticVerify(ctx *snow.Context, c codec.Codec, _ int) error {
+	if err := t.verifyFields(ctx, c); err != nil {
+		return err
+	}
+	if err := verifyFlow(t.Ins, t.Outs); err != nil {
+		return err
+	}
+	return t.metadata.Verify()
+}
+
+// verifyFields verifies that every field of this transaction is well-formed,
+// without checking that the inputs cover the outputs
+func (t *BaseTx) verifyFields(ctx *snow.Context, c codec.Codec) error {
 	switch {
 	case t == nil:
 		return errNilTx
@@ -111,12 +127,17 @@ func (t *BaseTx) SyntacticVerify(ctx *snow.Context, c codec.Codec, _ int) error
 			return err
 		}
 	}
-	if !isSortedAndUniqueTransferableInputs(t.Ins) {
+	if !IsSortedAndUniqueTransferableInputs(t.Ins) {
 		return errInputsNotSortedUnique
 	}
+	return nil
+}
 
+// verifyFlow verifies that, for every asset, [ins] consume at least as much as
+// [outs] produce
+func verifyFlow(ins []*TransferableInput, outs []*TransferableOutput) error {
 	consumedFunds := map[[32]byte]uint64{}
-	for _, in := range t.Ins {
+	for _, in := range ins {
 		assetID := in.AssetID()
 		amount := in.Input().Amount()
 
@@ -129,7 +150,7 @@ func (t *BaseTx) SyntacticVerify(ctx *snow.Context, c codec.Codec, _ int) error
 		}
 	}
 	producedFunds := map[[32]byte]uint64{}
-	for _, out := range t.Outs {
+	for _, out := range outs {
 		assetID := out.AssetID()
 		amount := out.Output().Amount()
 
@@ -142,82 +163,128 @@ func (t *BaseTx) SyntacticVerify(ctx *snow.Context, c codec.Codec, _ int) error
 		}
 	}
 
-	// TODO: Add the Tx fee to the producedFunds
-
 	for assetID, producedAssetAmount := range producedFunds {
 		consumedAssetAmount := consumedFunds[assetID]
 		if producedAssetAmount > consumedAssetAmount {
 			return errInsufficientFunds
 		}
 	}
+	return nil
+}
+
+// verifyFee verifies that [ins] consume at least the tx fee more AVA than
+// [outs] produce. The difference is burned.
+func (vm *VM) verifyFee(ins []*TransferableInput, outs []*TransferableOutput) error {
+	if vm.txFee == 0 {
+		return nil
+	}
 
-	return t.metadata.Verify()
+	consumed := uint64(0)
+	for _, in := range ins {
+		if !in.AssetID().Equals(vm.ava) {
+			continue
+		}
+		amount, err := math.Add64(consumed, in.Input().Amount())
+		if err != nil {
+			return errInputOverflow
+		}
+		consumed = amount
+	}
+	produced := vm.txFee
+	for _, out := range outs {
+		if !out.AssetID().Equals(vm.ava) {
+			continue
+		}
+		amount, err := math.Add64(produced, out.Output().Amount())
+		if err != nil {
+			return errOutputOverflow
+		}
+		produced = amount
+	}
+	if consumed < produced {
+		return errInsufficientFee
+	}
+	return nil
 }
 
 // SemanticVerify that this transaction is valid to be spent.
 func (t *BaseTx) SemanticVerify(vm *VM, uTx *UniqueTx, creds []*Credential) error {
+	if err := vm.verifyFee(t.Ins, t.Outs); err != nil {
+		return err
+	}
+	return t.verifyInputs(vm, uTx, creds)
+}
+
+// verifyInputs verifies that [creds] authorize spending the UTXOs consumed by
+// [t.Ins]
+func (t *BaseTx) verifyInputs(vm *VM, uTx *UniqueTx, creds []*Credential) error {
+	checks, err := t.inputChecks(vm, newSignedTx(uTx), creds)
+	if err != nil {
+		return err
+	}
+	return vm.verifyAll(checks)
+}
+
+// inputChecks looks up the UTXOs consumed by [t.Ins] and returns the checks
+// that [creds] authorize spending them. The lookups touch the VM's state, so
+// they are done here rather than in the checks.
+func (t *BaseTx) inputChecks(vm *VM, tx *signedTx, creds []*Credential) ([]func() error, error) {
+	checks := make([]func() error, 0, len(t.Ins))
 	for i, in := range t.Ins {
 		cred := creds[i]
 
 		fxIndex, err := vm.getFx(cred.Cred)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		fx := vm.fxs[fxIndex].Fx
 
 		utxoID := in.InputID()
 		utxo, err := vm.state.UTXO(utxoID)
-		if err == nil {
-			utxoAssetID := utxo.AssetID()
-			inAssetID := in.AssetID()
-			if !utxoAssetID.Equals(inAssetID) {
-				return errAssetIDMismatch
+		if err != nil {
+			inputTx, inputIndex := in.InputSource()
+			parent := UniqueTx{
+				vm:   vm,
+				txID: inputTx,
 			}
 
-			if !vm.verifyFxUsage(fxIndex, inAssetID) {
-				return errIncompatibleFx
+			if err := parent.verify(); err != nil {
+				return nil, errMissingUTXO
+			} else if status := parent.Status(); status.Decided() {
+				return nil, errMissingUTXO
 			}
 
-			err = fx.VerifyTransfer(uTx, utxo.Out, in.In, cred.Cred)
-			if err == nil {
-				continue
-			}
-			return err
-		}
-
-		inputTx, inputIndex := in.InputSource()
-		parent := UniqueTx{
-			vm:   vm,
-			txID: inputTx,
-		}
+			utxos := parent.UTXOs()
 
-		if err := parent.Verify(); err != nil {
-			return errMissingUTXO
-		} else if status := parent.Status(); status.Decided() {
-			return errMissingUTXO
-		}
-
-		utxos := parent.UTXOs()
+			if uint32(len(utxos)) <= inputIndex || int(inputIndex) < 0 {
+				return nil, errInvalidUTXO
+			}
 
-		if uint32(len(utxos)) <= inputIndex || int(inputIndex) < 0 {
-			return errInvalidUTXO
+			utxo = utxos[int(inputIndex)]
 		}
 
-		utxo = utxos[int(inputIndex)]
-
 		utxoAssetID := utxo.AssetID()
 		inAssetID := in.AssetID()
 		if !utxoAssetID.Equals(inAssetID) {
-			return errAssetIDMismatch
+			return nil, errAssetIDMismatch
 		}
 
 		if !vm.verifyFxUsage(fxIndex, inAssetID) {
-			return errIncompatibleFx
+			return nil, errIncompatibleFx
 		}
 
-		if err := fx.VerifyTransfer(uTx, utxo.Out, in.In, cred.Cred); err != nil {
-			return err
+		checks = append(checks, transferCheck(fx, tx, utxo, in, cred))
+	}
+	return checks, nil
+}
+
+// transferCheck returns a check that [cred] authorizes [in] to spend [utxo]
+func transferCheck(fx Fx, tx *signedTx, utxo *UTXO, in *TransferableInput, cred *Credential) func() error {
+	tx.spend(in.In, utxo)
+	return func() error {
+		if err := fx.VerifyTransfer(tx, utxo.Out, in.In, cred.Cred); err != nil {
+			return verify.WrapError(CodeFxVerificationFailed, err)
 		}
+		return nil
 	}
-	return nil
 }
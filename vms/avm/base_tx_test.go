@@ -5,6 +5,7 @@ package avm
 
 import (
 	"bytes"
+	"context"
 	"math"
 	"testing"
 
@@ -2049,3 +2050,131 @@ func TestBaseTxSemanticVerifyPendingInvalidSignature(t *testing.T) {
 		t.Fatalf("Invalid signature should have failed verification")
 	}
 }
+
+func TestBaseTxSemanticVerifyFee(t *testing.T) {
+	vm, genesisTx, _ := sharedMemoryVM(t)
+	vm.txFee = 1000
+	vm.ctx.Lock.Lock()
+	defer vm.ctx.Lock.Unlock()
+
+	key := keys[0]
+	newTx := func(amount uint64) *UniqueTx {
+		tx := &Tx{UnsignedTx: &BaseTx{
+			NetID: networkID,
+			BCID:  chainID,
+			Outs: []*TransferableOutput{&TransferableOutput{
+				Asset: Asset{ID: genesisTx.ID()},
+				Out: &secp256k1fx.TransferOutput{
+					Amt: amount,
+					OutputOwners: secp256k1fx.OutputOwners{
+						Threshold: 1,
+						Addrs:     []ids.ShortID{key.PublicKey().Address()},
+					},
+				},
+			}},
+			Ins: []*TransferableInput{&TransferableInput{
+				UTXOID: UTXOID{
+					TxID:        genesisTx.ID(),
+					OutputIndex: 1,
+				},
+				Asset: Asset{ID: genesisTx.ID()},
+				In: &secp256k1fx.TransferInput{
+					Amt:   50000,
+					Input: secp256k1fx.Input{SigIndices: []uint32{0}},
+				},
+			}},
+		}}
+		uTx, err := vm.parseTx(context.Background(), signTestTx(vm, tx, key, 1, t))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return uTx
+	}
+
+	if err := newTx(50000 - vm.txFee + 1).Verify(context.Background()); err == nil {
+		t.Fatalf("Should have errored because the tx doesn't burn the tx fee")
+	}
+	if err := newTx(50000 - vm.txFee).Verify(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// benchmarkBaseTx returns a codec that can marshal transactions, and a BaseTx
+// with [numIns] inputs and [numOuts] outputs
+func benchmarkBaseTx(numIns, numOuts int) (codec.Codec, UnsignedTx) {
+	c := codec.NewDefault()
+	c.RegisterType(&BaseTx{})
+	c.RegisterType(&CreateAssetTx{})
+	c.RegisterType(&OperationTx{})
+	c.RegisterType(&secp256k1fx.MintOutput{})
+	c.RegisterType(&secp256k1fx.TransferOutput{})
+	c.RegisterType(&secp256k1fx.MintInput{})
+	c.RegisterType(&secp256k1fx.TransferInput{})
+	c.RegisterType(&secp256k1fx.Credential{})
+
+	tx := &BaseTx{
+		NetID: networkID,
+		BCID:  chainID,
+	}
+	for i := 0; i < numIns; i++ {
+		tx.Ins = append(tx.Ins, &TransferableInput{
+			UTXOID: UTXOID{
+				TxID:        ids.Empty.Prefix(uint64(i)),
+				OutputIndex: uint32(i),
+			},
+			Asset: Asset{ID: asset},
+			In: &secp256k1fx.TransferInput{
+				Amt: 54321,
+				Input: secp256k1fx.Input{
+					SigIndices: []uint32{0},
+				},
+			},
+		})
+	}
+	for i := 0; i < numOuts; i++ {
+		tx.Outs = append(tx.Outs, &TransferableOutput{
+			Asset: Asset{ID: asset},
+			Out: &secp256k1fx.TransferOutput{
+				Amt: 12345,
+				OutputOwners: secp256k1fx.OutputOwners{
+					Threshold: 1,
+					Addrs:     []ids.ShortID{keys[i%len(keys)].PublicKey().Address()},
+				},
+			},
+		})
+	}
+	return c, tx
+}
+
+// BenchmarkBaseTxMarshal benchmarks encoding a BaseTx with 8 inputs and 8
+// outputs
+func BenchmarkBaseTxMarshal(b *testing.B) {
+	c, tx := benchmarkBaseTx(8, 8)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		if _, err := c.Marshal(&tx); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkBaseTxUnmarshal benchmarks decoding a BaseTx with 8 inputs and 8
+// outputs
+func BenchmarkBaseTxUnmarshal(b *testing.B) {
+	c, tx := benchmarkBaseTx(8, 8)
+	txBytes, err := c.Marshal(&tx)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		parsed := UnsignedTx(nil)
+		if err := c.Unmarshal(txBytes, &parsed); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -4,13 +4,13 @@
 package avm
 
 import (
-	"errors"
 	"fmt"
 	"strings"
 	"unicode"
 
 	"github.com/ava-labs/gecko/snow"
 	"github.com/ava-labs/gecko/vms/components/codec"
+	"github.com/ava-labs/gecko/vms/components/verify"
 )
 
 const (
@@ -20,13 +20,13 @@ const (
 )
 
 var (
-	errInitialStatesNotSortedUnique = errors.New("initial states not sorted and unique")
-	errNameTooLong                  = fmt.Errorf("name is too long, maximum size is %d", maxNameLen)
-	errSymbolTooLong                = fmt.Errorf("symbol is too long, maximum size is %d", maxSymbolLen)
-	errNoFxs                        = errors.New("assets must support at least one Fx")
-	errUnprintableASCIICharacter    = errors.New("unprintable ascii character was provided")
-	errUnexpectedWhitespace         = errors.New("unexpected whitespace provided")
-	errDenominationTooLarge         = errors.New("denomination is too large")
+	errInitialStatesNotSortedUnique = verify.NewError(CodeInitialStatesNotSortedUnique, "initial states not sorted and unique")
+	errNameTooLong                  = verify.NewError(CodeNameTooLong, fmt.Sprintf("name is too long, maximum size is %d", maxNameLen))
+	errSymbolTooLong                = verify.NewError(CodeSymbolTooLong, fmt.Sprintf("symbol is too long, maximum size is %d", maxSymbolLen))
+	errNoFxs                        = verify.NewError(CodeNoFxs, "assets must support at least one Fx")
+	errUnprintableASCIICharacter    = verify.NewError(CodeUnprintableASCIICharacter, "unprintable ascii character was provided")
+	errUnexpectedWhitespace         = verify.NewError(CodeUnexpectedWhitespace, "unexpected whitespace provided")
+	errDenominationTooLarge         = verify.NewError(CodeDenominationTooLarge, "denomination is too large")
 )
 
 // CreateAssetTx is a transaction that creates a new asset.
@@ -4,14 +4,12 @@
 package avm
 
 import (
-	"errors"
-
 	"github.com/ava-labs/gecko/vms/components/verify"
 )
 
 var (
-	errNilCredential   = errors.New("nil credential is not valid")
-	errNilFxCredential = errors.New("nil feature extension credential is not valid")
+	errNilCredential   = verify.NewError(CodeNilCredential, "nil credential is not valid")
+	errNilFxCredential = verify.NewError(CodeNilFxCredential, "nil feature extension credential is not valid")
 )
 
 // Credential ...
@@ -31,6 +29,9 @@ func (cred *Credential) Verify() error {
 	case cred.Cred == nil:
 		return errNilFxCredential
 	default:
-		return cred.Cred.Verify()
+		if err := cred.Cred.Verify(); err != nil {
+			return verify.WrapError(CodeInvalidFxCredential, err)
+		}
+		return nil
 	}
 }
@@ -0,0 +1,91 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avm
+
+import (
+	"github.com/ava-labs/gecko/vms/components/verify"
+)
+
+// Codes of the errors returned when a transaction fails verification. These
+// codes are reported to API clients, so they must never be changed or reused.
+const (
+	// Malformed transactions
+	CodeNilTx                        verify.ErrorCode = 1000
+	CodeWrongNetworkID               verify.ErrorCode = 1001
+	CodeWrongChainID                 verify.ErrorCode = 1002
+	CodeOutputsNotSorted             verify.ErrorCode = 1003
+	CodeInputsNotSortedUnique        verify.ErrorCode = 1004
+	CodeInputOverflow                verify.ErrorCode = 1005
+	CodeOutputOverflow               verify.ErrorCode = 1006
+	CodeInsufficientFunds            verify.ErrorCode = 1007
+	CodeNilAssetID                   verify.ErrorCode = 1008
+	CodeEmptyAssetID                 verify.ErrorCode = 1009
+	CodeInitialStatesNotSortedUnique verify.ErrorCode = 1010
+	CodeNameTooLong                  verify.ErrorCode = 1011
+	CodeSymbolTooLong                verify.ErrorCode = 1012
+	CodeNoFxs                        verify.ErrorCode = 1013
+	CodeUnprintableASCIICharacter    verify.ErrorCode = 1014
+	CodeUnexpectedWhitespace         verify.ErrorCode = 1015
+	CodeDenominationTooLarge         verify.ErrorCode = 1016
+	CodeNilCredential                verify.ErrorCode = 1017
+	CodeNilFxCredential              verify.ErrorCode = 1018
+	CodeNilInitialState              verify.ErrorCode = 1019
+	CodeNilFxOutput                  verify.ErrorCode = 1020
+	CodeNilMetadata                  verify.ErrorCode = 1021
+	CodeMetadataNotInitialize        verify.ErrorCode = 1022
+	CodeNilOperableOutput            verify.ErrorCode = 1023
+	CodeNilOperableFxOutput          verify.ErrorCode = 1024
+	CodeNilOperableInput             verify.ErrorCode = 1025
+	CodeNilOperableFxInput           verify.ErrorCode = 1026
+	CodeNilOperation                 verify.ErrorCode = 1027
+	CodeEmptyOperation               verify.ErrorCode = 1028
+	CodeOperationsNotSortedUnique    verify.ErrorCode = 1029
+	CodeDoubleSpend                  verify.ErrorCode = 1030
+	CodeNilTransferableOutput        verify.ErrorCode = 1031
+	CodeNilTransferableFxOutput      verify.ErrorCode = 1032
+	CodeNilTransferableInput         verify.ErrorCode = 1033
+	CodeNilTransferableFxInput       verify.ErrorCode = 1034
+	CodeWrongNumberOfCredentials     verify.ErrorCode = 1035
+	CodeNilUTXO                      verify.ErrorCode = 1036
+	CodeEmptyUTXO                    verify.ErrorCode = 1037
+	CodeNilUTXOID                    verify.ErrorCode = 1038
+	CodeNilTxID                      verify.ErrorCode = 1039
+	CodeInvalidFxCredential          verify.ErrorCode = 1040
+	CodeMemoTooLarge                 verify.ErrorCode = 1041
+	CodeNoImportInputs               verify.ErrorCode = 1042
+	CodeNoExportOutputs              verify.ErrorCode = 1043
+	CodeEmptyMemo                    verify.ErrorCode = 1044
+	CodeNestedMemo                   verify.ErrorCode = 1045
+
+	// Transactions that are inconsistent with the current state
+	CodeAssetIDMismatch verify.ErrorCode = 1100
+	CodeMissingUTXO     verify.ErrorCode = 1101
+	CodeUnknownTx       verify.ErrorCode = 1102
+	CodeRejectedTx      verify.ErrorCode = 1103
+	CodeInvalidUTXO     verify.ErrorCode = 1104
+	CodeIncompatibleFx  verify.ErrorCode = 1105
+	CodeUnknownFx       verify.ErrorCode = 1106
+
+	// The feature extension rejected the transaction, for example because of
+	// a missing or invalid signature
+	CodeFxVerificationFailed verify.ErrorCode = 1107
+
+	// Only AVA may be moved to the Platform Chain
+	CodeWrongAssetID verify.ErrorCode = 1108
+
+	// The transaction doesn't burn enough AVA to pay the transaction fee
+	CodeInsufficientFee verify.ErrorCode = 1109
+
+	// The UTXO was already imported from the Platform Chain
+	CodeUTXOAlreadyImported verify.ErrorCode = 1110
+
+	// Valid transactions that this node's issuance filter refuses to issue
+	CodeDeniedAsset   verify.ErrorCode = 1200
+	CodeDeniedAddress verify.ErrorCode = 1201
+
+	// Valid transactions that this node's mempool can't hold
+	CodeConflictingTx     verify.ErrorCode = 1300
+	CodeAddressCapReached verify.ErrorCode = 1301
+	CodeMempoolFull       verify.ErrorCode = 1302
+)
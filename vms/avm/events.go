@@ -0,0 +1,120 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avm
+
+import (
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow/choices"
+	"github.com/ava-labs/gecko/vms/components/events"
+
+	cjson "github.com/ava-labs/gecko/utils/json"
+)
+
+// eventParser describes the txs this VM decides to the events server. The
+// container of each decision is a single tx.
+type eventParser struct{ vm *VM }
+
+// ParseAccepted implements the events.Parser interface
+func (p *eventParser) ParseAccepted(txID ids.ID, _ []byte) ([]*events.Tx, []*events.Tx, error) {
+	tx, err := p.decidedTx(txID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	event := &events.Tx{ID: txID}
+	// Imported utxos aren't in this chain's utxo set, so they aren't reported
+	// as spent
+	for _, utxoID := range tx.InputUTXOs() {
+		if utxoID.Imported() {
+			continue
+		}
+		utxo, err := p.vm.inputUTXO(utxoID)
+		if err != nil {
+			return nil, nil, err
+		}
+		event.Consumed = append(event.Consumed, p.utxo(utxo, true))
+	}
+	for _, utxo := range tx.UTXOs() {
+		event.Produced = append(event.Produced, p.utxo(utxo, false))
+	}
+	p.addAddresses(event, tx, append(event.Consumed, event.Produced...))
+	return []*events.Tx{event}, nil, nil
+}
+
+// ParseRejected implements the events.Parser interface
+func (p *eventParser) ParseRejected(txID ids.ID, _ []byte) ([]*events.Tx, error) {
+	tx, err := p.decidedTx(txID)
+	if err != nil {
+		return nil, err
+	}
+
+	// A rejected tx doesn't change any utxos, but its subscribers are still
+	// found by the utxos it would have spent and produced
+	involved := []*events.UTXO(nil)
+	for _, utxoID := range tx.InputUTXOs() {
+		if utxoID.Imported() {
+			continue
+		}
+		if utxo, err := p.vm.inputUTXO(utxoID); err == nil {
+			involved = append(involved, p.utxo(utxo, false))
+		}
+	}
+	for _, utxo := range tx.UTXOs() {
+		involved = append(involved, p.utxo(utxo, false))
+	}
+
+	event := &events.Tx{ID: txID}
+	p.addAddresses(event, tx, involved)
+	return []*events.Tx{event}, nil
+}
+
+func (p *eventParser) decidedTx(txID ids.ID) (*UniqueTx, error) {
+	tx := &UniqueTx{
+		vm:   p.vm,
+		txID: txID,
+	}
+	if tx.Status() == choices.Unknown || tx.t.tx == nil {
+		return nil, errUnknownTx
+	}
+	return tx, nil
+}
+
+// utxo describes [utxo] to the subscribers of its asset and owners
+func (p *eventParser) utxo(utxo *UTXO, spent bool) *events.UTXO {
+	event := &events.UTXO{
+		ID:         utxo.InputID(),
+		TxID:       utxo.TxID,
+		AssetID:    utxo.AssetID(),
+		Spent:      spent,
+		FilterKeys: utxoFilterKeys(utxo),
+	}
+	if transferable, ok := utxo.Out.(FxTransferable); ok {
+		event.Amount = cjson.Uint64(transferable.Amount())
+	}
+	if addressable, ok := utxo.Out.(FxAddressable); ok {
+		for _, addr := range addressable.Addresses() {
+			event.Addresses = append(event.Addresses, p.vm.Format(addr))
+		}
+	}
+	return event
+}
+
+// addAddresses sets the addresses and filter keys of [event], which are the
+// assets [tx] moves and the owners of [utxos]
+func (p *eventParser) addAddresses(event *events.Tx, tx *UniqueTx, utxos []*events.UTXO) {
+	for _, assetID := range tx.t.tx.AssetIDs().List() {
+		event.FilterKeys = append(event.FilterKeys, assetID.Bytes())
+	}
+
+	addrs := map[string]bool{}
+	for _, utxo := range utxos {
+		event.FilterKeys = append(event.FilterKeys, utxo.FilterKeys...)
+		for _, addr := range utxo.Addresses {
+			if !addrs[addr] {
+				addrs[addr] = true
+				event.Addresses = append(event.Addresses, addr)
+			}
+		}
+	}
+}
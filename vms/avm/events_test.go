@@ -0,0 +1,102 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avm
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ava-labs/gecko/ids"
+)
+
+func TestEventParserAccepted(t *testing.T) {
+	genesisBytes := BuildGenesisTest(t)
+	genesisTx := GetFirstTxFromGenesisTest(genesisBytes, t)
+	vm := GenesisVM(t)
+	defer vm.Shutdown()
+	parser := &eventParser{vm: vm}
+
+	ctx.Lock.Lock()
+	defer ctx.Lock.Unlock()
+
+	txID, err := vm.IssueTx(newTestMemoTx(vm, genesisTx, 1, t), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	txs := vm.PendingTxs()
+	if len(txs) != 1 {
+		t.Fatalf("Should have issued the tx")
+	}
+	txs[0].Accept(context.Background())
+
+	accepted, rejected, err := parser.ParseAccepted(txID, nil)
+	switch {
+	case err != nil:
+		t.Fatal(err)
+	case len(accepted) != 1 || len(rejected) != 0:
+		t.Fatalf("Expected 1 accepted tx but got %d accepted and %d rejected", len(accepted), len(rejected))
+	}
+
+	event := accepted[0]
+	addr := vm.Format(keys[0].PublicKey().Address().Bytes())
+	switch {
+	case !event.ID.Equals(txID):
+		t.Fatalf("Expected tx %s but got %s", txID, event.ID)
+	case len(event.Addresses) != 1 || event.Addresses[0] != addr:
+		t.Fatalf("Expected the tx to involve only %s but got %v", addr, event.Addresses)
+	case len(event.Consumed) != 1 || !event.Consumed[0].Spent:
+		t.Fatalf("Expected the genesis utxo to be reported as spent")
+	case !event.Consumed[0].TxID.Equals(genesisTx.ID()):
+		t.Fatalf("Expected the spent utxo to be produced by %s but got %s", genesisTx.ID(), event.Consumed[0].TxID)
+	case len(event.Produced) != 1 || event.Produced[0].Spent:
+		t.Fatalf("Expected the new utxo to be reported as produced")
+	case event.Produced[0].Amount != 50000:
+		t.Fatalf("Expected the new utxo to hold 50000 but got %d", event.Produced[0].Amount)
+	}
+}
+
+func TestEventParserRejected(t *testing.T) {
+	genesisBytes := BuildGenesisTest(t)
+	genesisTx := GetFirstTxFromGenesisTest(genesisBytes, t)
+	vm := GenesisVM(t)
+	defer vm.Shutdown()
+	parser := &eventParser{vm: vm}
+
+	ctx.Lock.Lock()
+	defer ctx.Lock.Unlock()
+
+	// The replacement rejects the first tx
+	firstID, err := vm.IssueTx(newTestMemoTx(vm, genesisTx, 1, t), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := vm.IssueTx(newTestMemoTx(vm, genesisTx, 2, t), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	rejected, err := parser.ParseRejected(firstID, nil)
+	switch {
+	case err != nil:
+		t.Fatal(err)
+	case len(rejected) != 1 || !rejected[0].ID.Equals(firstID):
+		t.Fatalf("Expected tx %s to be rejected", firstID)
+	case len(rejected[0].Consumed) != 0 || len(rejected[0].Produced) != 0:
+		t.Fatalf("A rejected tx shouldn't change any utxos")
+	case len(rejected[0].Addresses) == 0 || len(rejected[0].FilterKeys) == 0:
+		t.Fatalf("A rejected tx should still be published to its addresses")
+	}
+}
+
+func TestEventParserUnknownTx(t *testing.T) {
+	vm := GenesisVM(t)
+	defer vm.Shutdown()
+	parser := &eventParser{vm: vm}
+
+	ctx.Lock.Lock()
+	defer ctx.Lock.Unlock()
+
+	if _, _, err := parser.ParseAccepted(ids.Empty.Prefix(1), nil); err != errUnknownTx {
+		t.Fatalf("Expected %s but got %v", errUnknownTx, err)
+	}
+}
@@ -0,0 +1,108 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avm
+
+import (
+	"github.com/ava-labs/gecko/snow"
+	"github.com/ava-labs/gecko/vms/components/codec"
+	"github.com/ava-labs/gecko/vms/components/verify"
+)
+
+var (
+	errNoExportOutputs = verify.NewError(CodeNoExportOutputs, "no export outputs")
+	errWrongAssetID    = verify.NewError(CodeWrongAssetID, "only AVA may be exported")
+)
+
+// ExportTx is a transaction that sends UTXOs to the Platform Chain
+type ExportTx struct {
+	BaseTx `serialize:"true"`
+
+	ExportedOuts []*TransferableOutput `serialize:"true"` // The outputs this tx sends to the Platform Chain
+}
+
+// ExportedUTXOs returns the UTXOs this transaction sends to the Platform Chain.
+// Their output indices follow those of the UTXOs it produces on this chain.
+func (t *ExportTx) ExportedUTXOs() []*UTXO {
+	txID := t.ID()
+	offset := len(t.Outs)
+	utxos := make([]*UTXO, len(t.ExportedOuts))
+	for i, out := range t.ExportedOuts {
+		utxos[i] = &UTXO{
+			UTXOID: UTXOID{
+				TxID:        txID,
+				OutputIndex: uint32(offset + i),
+			},
+			Asset: Asset{
+				ID: out.AssetID(),
+			},
+			Out: out.Out,
+		}
+	}
+	return utxos
+}
+
+// SyntacticVerify that this transaction is well-formed.
+func (t *ExportTx) SyntacticVerify(ctx *snow.Context, c codec.Codec, _ int) error {
+	switch {
+	case t == nil:
+		return errNilTx
+	case len(t.ExportedOuts) == 0:
+		return errNoExportOutputs
+	}
+
+	if err := t.verifyFields(ctx, c); err != nil {
+		return err
+	}
+
+	for _, out := range t.ExportedOuts {
+		if err := out.Verify(); err != nil {
+			return err
+		}
+	}
+	if !IsSortedTransferableOutputs(t.ExportedOuts, c) {
+		return errOutputsNotSorted
+	}
+
+	outs := make([]*TransferableOutput, 0, len(t.Outs)+len(t.ExportedOuts))
+	outs = append(outs, t.Outs...)
+	outs = append(outs, t.ExportedOuts...)
+	if err := verifyFlow(t.Ins, outs); err != nil {
+		return err
+	}
+	return t.metadata.Verify()
+}
+
+// SemanticVerify that this transaction is valid to be spent.
+func (t *ExportTx) SemanticVerify(vm *VM, uTx *UniqueTx, creds []*Credential) error {
+	for _, out := range t.ExportedOuts {
+		if !out.AssetID().Equals(vm.ava) {
+			return errWrongAssetID
+		}
+		fxIndex, err := vm.getFx(out.Out)
+		if err != nil {
+			return err
+		}
+		if !vm.verifyFxUsage(fxIndex, vm.ava) {
+			return errIncompatibleFx
+		}
+	}
+
+	outs := make([]*TransferableOutput, 0, len(t.Outs)+len(t.ExportedOuts))
+	outs = append(outs, t.Outs...)
+	outs = append(outs, t.ExportedOuts...)
+	if err := vm.verifyFee(t.Ins, outs); err != nil {
+		return err
+	}
+	return t.verifyInputs(vm, uTx, creds)
+}
+
+// acceptShared sends the exported UTXOs to the Platform Chain
+func (t *ExportTx) acceptShared(vm *VM, batch *AtomicUTXOsBatch) error {
+	for _, utxo := range t.ExportedUTXOs() {
+		if err := batch.Put(vm.platform, utxo); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,198 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avm
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ava-labs/gecko/chains/atomic"
+	"github.com/ava-labs/gecko/database/memdb"
+	"github.com/ava-labs/gecko/database/prefixdb"
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow"
+	"github.com/ava-labs/gecko/snow/engine/common"
+	"github.com/ava-labs/gecko/utils/crypto"
+	"github.com/ava-labs/gecko/utils/logging"
+	"github.com/ava-labs/gecko/vms/secp256k1fx"
+)
+
+var platformChainID = ids.Empty.Prefix(0)
+
+// sharedMemoryVM returns a VM, whose AVA asset is the first asset of its
+// genesis, that shares memory with the chain [platformChainID]. As in a node,
+// the VM's database and the shared memory are on top of the same database.
+func sharedMemoryVM(t *testing.T) (*VM, *Tx, *atomic.Memory) {
+	genesisBytes := BuildGenesisTest(t)
+	genesisTx := GetFirstTxFromGenesisTest(genesisBytes, t)
+
+	baseDB := memdb.New()
+	sm := &atomic.Memory{}
+	sm.Initialize(logging.NoLog{}, prefixdb.New([]byte("shared memory"), baseDB))
+
+	vmCtx := snow.DefaultContextTest()
+	vmCtx.NetworkID = networkID
+	vmCtx.ChainID = chainID
+	vmCtx.SharedMemory = sm.NewBlockchainMemory(chainID)
+
+	vm := &VM{
+		ava:      genesisTx.ID(),
+		platform: platformChainID,
+	}
+	err := vm.Initialize(
+		vmCtx,
+		prefixdb.New(chainID.Bytes(), baseDB),
+		genesisBytes,
+		make(chan common.Message, 1),
+		[]*common.Fx{&common.Fx{
+			ID: ids.Empty,
+			Fx: &secp256k1fx.Fx{},
+		}},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return vm, genesisTx, sm
+}
+
+// signTestTx adds a credential to [tx], signed by [key], for each of
+// [numInputs] inputs and returns the bytes of the signed transaction
+func signTestTx(vm *VM, tx *Tx, key *crypto.PrivateKeySECP256K1R, numInputs int, t *testing.T) []byte {
+	unsignedBytes, err := vm.codec.Marshal(&tx.UnsignedTx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig, err := key.Sign(unsignedBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fixedSig := [crypto.SECP256K1RSigLen]byte{}
+	copy(fixedSig[:], sig)
+
+	for i := 0; i < numInputs; i++ {
+		tx.Creds = append(tx.Creds, &Credential{
+			Cred: &secp256k1fx.Credential{
+				Sigs: [][crypto.SECP256K1RSigLen]byte{fixedSig},
+			},
+		})
+	}
+
+	b, err := vm.codec.Marshal(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func TestExportTxNoOutputs(t *testing.T) {
+	tx := &ExportTx{BaseTx: BaseTx{
+		NetID: networkID,
+		BCID:  chainID,
+	}}
+	if err := tx.SyntacticVerify(ctx, newStaticCodec(), 0); err == nil {
+		t.Fatalf("Should have errored due to no exported outputs")
+	}
+}
+
+func TestIssueExportTx(t *testing.T) {
+	vm, genesisTx, sm := sharedMemoryVM(t)
+	vm.ctx.Lock.Lock()
+	defer vm.ctx.Lock.Unlock()
+
+	key := keys[0]
+	tx := &Tx{UnsignedTx: &ExportTx{
+		BaseTx: BaseTx{
+			NetID: networkID,
+			BCID:  chainID,
+			Ins: []*TransferableInput{&TransferableInput{
+				UTXOID: UTXOID{
+					TxID:        genesisTx.ID(),
+					OutputIndex: 1,
+				},
+				Asset: Asset{ID: genesisTx.ID()},
+				In: &secp256k1fx.TransferInput{
+					Amt:   50000,
+					Input: secp256k1fx.Input{SigIndices: []uint32{0}},
+				},
+			}},
+		},
+		ExportedOuts: []*TransferableOutput{&TransferableOutput{
+			Asset: Asset{ID: genesisTx.ID()},
+			Out: &secp256k1fx.TransferOutput{
+				Amt: 50000,
+				OutputOwners: secp256k1fx.OutputOwners{
+					Threshold: 1,
+					Addrs:     []ids.ShortID{key.PublicKey().Address()},
+				},
+			},
+		}},
+	}}
+
+	uTx, err := vm.parseTx(context.Background(), signTestTx(vm, tx, key, 1, t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := uTx.Verify(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	uTx.Accept(context.Background())
+
+	exportTx := uTx.t.tx.UnsignedTx.(*ExportTx)
+	utxoID := exportTx.ExportedUTXOs()[0].InputID()
+
+	state := NewAtomicUTXOs(sm.GetDatabase(chainID, platformChainID))
+	defer sm.ReleaseDatabase(chainID, platformChainID)
+
+	utxo, err := state.Get(platformChainID, utxoID)
+	if err != nil {
+		t.Fatalf("Exported UTXO should have been sent to the Platform Chain: %s", err)
+	}
+	if amount := utxo.Out.(*secp256k1fx.TransferOutput).Amt; amount != 50000 {
+		t.Fatalf("Exported UTXO should have had amount %d but had %d", 50000, amount)
+	}
+}
+
+func TestIssueExportTxWrongAsset(t *testing.T) {
+	vm, genesisTx, _ := sharedMemoryVM(t)
+	vm.ava = ids.Empty.Prefix(1)
+	vm.ctx.Lock.Lock()
+	defer vm.ctx.Lock.Unlock()
+
+	key := keys[0]
+	tx := &Tx{UnsignedTx: &ExportTx{
+		BaseTx: BaseTx{
+			NetID: networkID,
+			BCID:  chainID,
+			Ins: []*TransferableInput{&TransferableInput{
+				UTXOID: UTXOID{
+					TxID:        genesisTx.ID(),
+					OutputIndex: 1,
+				},
+				Asset: Asset{ID: genesisTx.ID()},
+				In: &secp256k1fx.TransferInput{
+					Amt:   50000,
+					Input: secp256k1fx.Input{SigIndices: []uint32{0}},
+				},
+			}},
+		},
+		ExportedOuts: []*TransferableOutput{&TransferableOutput{
+			Asset: Asset{ID: genesisTx.ID()},
+			Out: &secp256k1fx.TransferOutput{
+				Amt: 50000,
+				OutputOwners: secp256k1fx.OutputOwners{
+					Threshold: 1,
+					Addrs:     []ids.ShortID{key.PublicKey().Address()},
+				},
+			},
+		}},
+	}}
+
+	uTx, err := vm.parseTx(context.Background(), signTestTx(vm, tx, key, 1, t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := uTx.Verify(context.Background()); err == nil {
+		t.Fatalf("Should have errored because only AVA may be exported")
+	}
+}
@@ -13,7 +13,46 @@ var (
 )
 
 // Factory ...
-type Factory struct{}
+type Factory struct {
+	// IssuanceFilter, if non-nil, is consulted before the API issues a
+	// transaction
+	IssuanceFilter IssuanceFilter
+
+	// AVA is the ID of the AVA asset, the only asset that may be moved to the
+	// Platform Chain
+	AVA ids.ID
+
+	// Platform is the ID of the Platform Chain
+	Platform ids.ID
+
+	// IndexTransactions enables the index of accepted transactions by the
+	// addresses they touch, which is served by avm.getAddressTxs
+	IndexTransactions bool
+
+	// IndexAssetStats enables the daily stats of each asset's activity, which
+	// are served by avm.getAssetStats
+	IndexAssetStats bool
+
+	// MempoolSize is the maximum number of issued transactions waiting to be
+	// taken by consensus. If 0, a default is used.
+	MempoolSize int
+
+	// MempoolAddressCap is the maximum number of transactions in the mempool
+	// that each address may be involved in. If 0, a default is used.
+	MempoolAddressCap int
+}
 
 // New ...
-func (f *Factory) New() interface{} { return &VM{} }
+func (f *Factory) New() interface{} {
+	return &VM{
+		issuanceFilter: f.IssuanceFilter,
+		ava:            f.AVA,
+		platform:       f.Platform,
+		indexTxs:       f.IndexTransactions,
+
+		indexAssetStats: f.IndexAssetStats,
+
+		mempoolSize:       f.MempoolSize,
+		mempoolAddressCap: f.MempoolAddressCap,
+	}
+}
@@ -47,3 +47,11 @@ type FxTransferable interface {
 type FxAddressable interface {
 	Addresses() [][]byte
 }
+
+// FxFreezable is the interface a feature extension's output must provide to be
+// able to freeze transfers of its asset. Once a tx producing the output is
+// accepted, the asset can only be spent using that feature extension until an
+// output that isn't frozen is accepted.
+type FxFreezable interface {
+	IsFrozen() bool
+}
@@ -4,10 +4,18 @@
 package avm
 
 import (
+	"errors"
 	"sort"
 	"strings"
 
+	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/utils"
+	"github.com/ava-labs/gecko/vms/components/codec"
+	"github.com/ava-labs/gecko/vms/secp256k1fx"
+)
+
+var (
+	errUnknownGenesisAsset = errors.New("unknown genesis asset")
 )
 
 // Genesis ...
@@ -15,6 +23,30 @@ type Genesis struct {
 	Txs []*GenesisAsset `serialize:"true"`
 }
 
+// FeeGenesis is the genesis of an AVM whose transactions must burn [TxFee] AVA.
+// A genesis without a fee is encoded as a Genesis instead, so that the genesis
+// data, and therefore the IDs, of chains created before fees existed don't
+// change.
+type FeeGenesis struct {
+	Genesis `serialize:"true"`
+	TxFee   uint64 `serialize:"true"`
+}
+
+// parseGenesis parses [genesisBytes], which are either a Genesis or a
+// FeeGenesis, and returns the genesis along with the tx fee it sets
+func parseGenesis(c codec.Codec, genesisBytes []byte) (*Genesis, uint64, error) {
+	genesis := Genesis{}
+	if err := c.Unmarshal(genesisBytes, &genesis); err == nil {
+		return &genesis, 0, nil
+	}
+
+	feeGenesis := FeeGenesis{}
+	if err := c.Unmarshal(genesisBytes, &feeGenesis); err != nil {
+		return nil, 0, err
+	}
+	return &feeGenesis.Genesis, feeGenesis.TxFee, nil
+}
+
 // Less ...
 func (g *Genesis) Less(i, j int) bool { return strings.Compare(g.Txs[i].Alias, g.Txs[j].Alias) == -1 }
 
@@ -35,3 +67,52 @@ type GenesisAsset struct {
 	Alias         string `serialize:"true"`
 	CreateAssetTx `serialize:"true"`
 }
+
+// GenesisAssetID returns the ID of the asset with alias [alias] that
+// [genesisBytes] creates
+func GenesisAssetID(genesisBytes []byte, alias string) (ids.ID, error) {
+	c := newStaticCodec()
+
+	genesis, _, err := parseGenesis(c, genesisBytes)
+	if err != nil {
+		return ids.ID{}, err
+	}
+	for _, genesisTx := range genesis.Txs {
+		if genesisTx.Alias != alias {
+			continue
+		}
+		tx := Tx{
+			UnsignedTx: &genesisTx.CreateAssetTx,
+		}
+		txBytes, err := c.Marshal(&tx)
+		if err != nil {
+			return ids.ID{}, err
+		}
+		tx.Initialize(txBytes)
+		return tx.ID(), nil
+	}
+	return ids.ID{}, errUnknownGenesisAsset
+}
+
+// GenesisTxFee returns the amount of AVA each transaction must burn on the
+// chain created with [genesisBytes]
+func GenesisTxFee(genesisBytes []byte) (uint64, error) {
+	_, txFee, err := parseGenesis(newStaticCodec(), genesisBytes)
+	return txFee, err
+}
+
+// newStaticCodec returns a codec that registers the same types, in the same
+// order, as a VM whose only fx is the secp256k1fx. It's used where there is no
+// VM, such as when building genesis data.
+func newStaticCodec() codec.Codec {
+	c := codec.NewDefault()
+	c.RegisterType(&BaseTx{})
+	c.RegisterType(&CreateAssetTx{})
+	c.RegisterType(&OperationTx{})
+	c.RegisterType(&secp256k1fx.MintOutput{})
+	c.RegisterType(&secp256k1fx.TransferOutput{})
+	c.RegisterType(&secp256k1fx.MintInput{})
+	c.RegisterType(&secp256k1fx.TransferInput{})
+	c.RegisterType(&secp256k1fx.Credential{})
+	return c
+}
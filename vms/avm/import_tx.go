@@ -0,0 +1,156 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avm
+
+import (
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow"
+	"github.com/ava-labs/gecko/snow/choices"
+	"github.com/ava-labs/gecko/vms/components/codec"
+	"github.com/ava-labs/gecko/vms/components/verify"
+)
+
+var (
+	errNoImportInputs      = verify.NewError(CodeNoImportInputs, "no import inputs")
+	errUTXOAlreadyImported = verify.NewError(CodeUTXOAlreadyImported, "utxo was already imported")
+)
+
+// ImportTx is a transaction that imports the UTXOs the Platform Chain sent to
+// this chain
+type ImportTx struct {
+	BaseTx `serialize:"true"`
+
+	ImportedIns []*TransferableInput `serialize:"true"` // The UTXOs this tx imports
+}
+
+// InputUTXOs track which UTXOs this transaction is consuming.
+func (t *ImportTx) InputUTXOs() []*UTXOID {
+	utxos := t.BaseTx.InputUTXOs()
+	for _, in := range t.ImportedIns {
+		utxoID := in.UTXOID
+		utxoID.imported = true
+		utxos = append(utxos, &utxoID)
+	}
+	return utxos
+}
+
+// AssetIDs returns the IDs of the assets this transaction depends on
+func (t *ImportTx) AssetIDs() ids.Set {
+	assets := t.BaseTx.AssetIDs()
+	for _, in := range t.ImportedIns {
+		assets.Add(in.AssetID())
+	}
+	return assets
+}
+
+// SyntacticVerify that this transaction is well-formed.
+func (t *ImportTx) SyntacticVerify(ctx *snow.Context, c codec.Codec, _ int) error {
+	switch {
+	case t == nil:
+		return errNilTx
+	case len(t.ImportedIns) == 0:
+		return errNoImportInputs
+	}
+
+	if err := t.verifyFields(ctx, c); err != nil {
+		return err
+	}
+
+	inputs := ids.Set{}
+	for _, in := range t.Ins {
+		inputs.Add(in.InputID())
+	}
+	for _, in := range t.ImportedIns {
+		if err := in.Verify(); err != nil {
+			return err
+		}
+		inputID := in.InputID()
+		if inputs.Contains(inputID) {
+			return errDoubleSpend
+		}
+		inputs.Add(inputID)
+	}
+	if !IsSortedAndUniqueTransferableInputs(t.ImportedIns) {
+		return errInputsNotSortedUnique
+	}
+
+	ins := make([]*TransferableInput, 0, len(t.Ins)+len(t.ImportedIns))
+	ins = append(ins, t.Ins...)
+	ins = append(ins, t.ImportedIns...)
+	if err := verifyFlow(ins, t.Outs); err != nil {
+		return err
+	}
+	return t.metadata.Verify()
+}
+
+// SemanticVerify that this transaction is valid to be spent.
+func (t *ImportTx) SemanticVerify(vm *VM, uTx *UniqueTx, creds []*Credential) error {
+	ins := make([]*TransferableInput, 0, len(t.Ins)+len(t.ImportedIns))
+	ins = append(ins, t.Ins...)
+	ins = append(ins, t.ImportedIns...)
+	if err := vm.verifyFee(ins, t.Outs); err != nil {
+		return err
+	}
+	tx := newSignedTx(uTx)
+	checks, err := t.inputChecks(vm, tx, creds)
+	if err != nil {
+		return err
+	}
+
+	smDB := vm.ctx.SharedMemory.GetDatabase(vm.platform)
+	defer vm.ctx.SharedMemory.ReleaseDatabase(vm.platform)
+
+	state := NewAtomicUTXOs(smDB)
+
+	offset := len(t.Ins)
+	for i, in := range t.ImportedIns {
+		cred := creds[i+offset]
+
+		fxIndex, err := vm.getFx(cred.Cred)
+		if err != nil {
+			return err
+		}
+		fx := vm.fxs[fxIndex].Fx
+
+		// The UTXO is removed from the shared memory in the same batch that
+		// marks it as imported, but a UTXO that the Platform Chain exports
+		// again must not be imported twice
+		utxoID := in.InputID()
+		if status, err := vm.state.ImportedUTXO(utxoID); err == nil && status == choices.Accepted {
+			return errUTXOAlreadyImported
+		}
+		utxo, err := state.Get(vm.ctx.ChainID, utxoID)
+		if err != nil {
+			return errMissingUTXO
+		}
+
+		utxoAssetID := utxo.AssetID()
+		inAssetID := in.AssetID()
+		if !utxoAssetID.Equals(inAssetID) {
+			return errAssetIDMismatch
+		}
+
+		if !vm.verifyFxUsage(fxIndex, inAssetID) {
+			return errIncompatibleFx
+		}
+
+		checks = append(checks, transferCheck(fx, tx, utxo, in, cred))
+	}
+	return vm.verifyAll(checks)
+}
+
+// acceptShared removes the imported UTXOs from the shared memory and marks
+// them as imported
+func (t *ImportTx) acceptShared(vm *VM, batch *AtomicUTXOsBatch) error {
+	for _, in := range t.ImportedIns {
+		utxoID := in.InputID()
+		if err := batch.Remove(vm.ctx.ChainID, utxoID); err != nil {
+			return err
+		}
+		if err := vm.state.SetImportedUTXO(utxoID, choices.Accepted); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,153 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avm
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/vms/secp256k1fx"
+)
+
+func TestImportTxNoInputs(t *testing.T) {
+	tx := &ImportTx{BaseTx: BaseTx{
+		NetID: networkID,
+		BCID:  chainID,
+	}}
+	if err := tx.SyntacticVerify(ctx, newStaticCodec(), 0); err == nil {
+		t.Fatalf("Should have errored due to no imported inputs")
+	}
+}
+
+func TestIssueImportTx(t *testing.T) {
+	vm, genesisTx, sm := sharedMemoryVM(t)
+	vm.ctx.Lock.Lock()
+	defer vm.ctx.Lock.Unlock()
+
+	key := keys[0]
+	addr := key.PublicKey().Address()
+
+	// The Platform Chain sends a UTXO to this chain
+	utxoID := UTXOID{
+		TxID:        ids.Empty.Prefix(1),
+		OutputIndex: 0,
+	}
+	utxo := &UTXO{
+		UTXOID: utxoID,
+		Asset:  Asset{ID: genesisTx.ID()},
+		Out: &secp256k1fx.TransferOutput{
+			Amt: 1000,
+			OutputOwners: secp256k1fx.OutputOwners{
+				Threshold: 1,
+				Addrs:     []ids.ShortID{addr},
+			},
+		},
+	}
+	state := NewAtomicUTXOs(sm.GetDatabase(chainID, platformChainID))
+	err := state.Put(chainID, utxo)
+	sm.ReleaseDatabase(chainID, platformChainID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tx := &Tx{UnsignedTx: &ImportTx{
+		BaseTx: BaseTx{
+			NetID: networkID,
+			BCID:  chainID,
+			Outs: []*TransferableOutput{&TransferableOutput{
+				Asset: Asset{ID: genesisTx.ID()},
+				Out: &secp256k1fx.TransferOutput{
+					Amt: 1000,
+					OutputOwners: secp256k1fx.OutputOwners{
+						Threshold: 1,
+						Addrs:     []ids.ShortID{addr},
+					},
+				},
+			}},
+		},
+		ImportedIns: []*TransferableInput{&TransferableInput{
+			UTXOID: utxoID,
+			Asset:  Asset{ID: genesisTx.ID()},
+			In: &secp256k1fx.TransferInput{
+				Amt:   1000,
+				Input: secp256k1fx.Input{SigIndices: []uint32{0}},
+			},
+		}},
+	}}
+
+	uTx, err := vm.parseTx(context.Background(), signTestTx(vm, tx, key, 1, t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, dep := range uTx.Dependencies() {
+		if dep.ID().Equals(utxoID.TxID) {
+			t.Fatalf("The tx that produced an imported UTXO shouldn't be a dependency")
+		}
+	}
+	if err := uTx.Verify(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	uTx.Accept(context.Background())
+
+	state = NewAtomicUTXOs(sm.GetDatabase(chainID, platformChainID))
+	_, err = state.Get(chainID, utxo.InputID())
+	sm.ReleaseDatabase(chainID, platformChainID)
+	if err == nil {
+		t.Fatalf("Imported UTXO should have been removed from the shared memory")
+	}
+	if _, err := vm.state.UTXO(uTx.UTXOs()[0].InputID()); err != nil {
+		t.Fatalf("Imported AVA should have been sent to %s: %s", addr, err)
+	}
+
+	// The UTXO can't be imported again, even if it's sent to this chain again
+	state = NewAtomicUTXOs(sm.GetDatabase(chainID, platformChainID))
+	err = state.Put(chainID, utxo)
+	sm.ReleaseDatabase(chainID, platformChainID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx.UnsignedTx.(*ImportTx).Outs[0].Out.(*secp256k1fx.TransferOutput).Amt = 999
+	tx.Creds = nil
+	uTx, err = vm.parseTx(context.Background(), signTestTx(vm, tx, key, 1, t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := uTx.Verify(context.Background()); err != errUTXOAlreadyImported {
+		t.Fatalf("Should have errored with %s, errored with %v", errUTXOAlreadyImported, err)
+	}
+}
+
+func TestIssueImportTxMissingUTXO(t *testing.T) {
+	vm, genesisTx, _ := sharedMemoryVM(t)
+	vm.ctx.Lock.Lock()
+	defer vm.ctx.Lock.Unlock()
+
+	key := keys[0]
+	tx := &Tx{UnsignedTx: &ImportTx{
+		BaseTx: BaseTx{
+			NetID: networkID,
+			BCID:  chainID,
+		},
+		ImportedIns: []*TransferableInput{&TransferableInput{
+			UTXOID: UTXOID{
+				TxID:        ids.Empty.Prefix(1),
+				OutputIndex: 0,
+			},
+			Asset: Asset{ID: genesisTx.ID()},
+			In: &secp256k1fx.TransferInput{
+				Amt:   1000,
+				Input: secp256k1fx.Input{SigIndices: []uint32{0}},
+			},
+		}},
+	}}
+
+	uTx, err := vm.parseTx(context.Background(), signTestTx(vm, tx, key, 1, t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := uTx.Verify(context.Background()); err == nil {
+		t.Fatalf("Should have errored because the imported UTXO doesn't exist")
+	}
+}
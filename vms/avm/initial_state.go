@@ -5,7 +5,6 @@ package avm
 
 import (
 	"bytes"
-	"errors"
 	"sort"
 
 	"github.com/ava-labs/gecko/vms/components/codec"
@@ -13,8 +12,8 @@ import (
 )
 
 var (
-	errNilInitialState = errors.New("nil initial state is not valid")
-	errNilFxOutput     = errors.New("nil feature extension output is not valid")
+	errNilInitialState = verify.NewError(CodeNilInitialState, "nil initial state is not valid")
+	errNilFxOutput     = verify.NewError(CodeNilFxOutput, "nil feature extension output is not valid")
 )
 
 // InitialState ...
@@ -0,0 +1,121 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avm
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/vms/components/verify"
+)
+
+// IssuanceFilter decides whether this node will originate a transaction. It is
+// only consulted when a transaction is issued through this node's API, never
+// when a transaction is received from a peer, so filtering transactions doesn't
+// change which transactions this node considers valid.
+type IssuanceFilter interface {
+	// Filter returns an error if [tx], which consumes [consumed], mustn't be
+	// issued by this node
+	Filter(tx *Tx, consumed []*UTXO) error
+}
+
+// DenyList is an IssuanceFilter that refuses transactions that use a denied
+// asset or that send funds to or from a denied address
+type DenyList struct {
+	assets    ids.Set
+	addresses ids.ShortSet
+}
+
+// denyListFile is the format of a deny list file
+type denyListFile struct {
+	Assets    []ids.ID      `json:"assets"`
+	Addresses []ids.ShortID `json:"addresses"`
+}
+
+// NewDenyList returns a deny list that refuses the provided assets and
+// addresses
+func NewDenyList(assets []ids.ID, addresses []ids.ShortID) *DenyList {
+	dl := &DenyList{}
+	dl.assets.Add(assets...)
+	dl.addresses.Add(addresses...)
+	return dl
+}
+
+// LoadDenyList returns the deny list in the JSON file [filename], which holds
+// the cb58 encoded IDs of the denied assets and addresses. For example:
+// {"assets": ["2YNP..."], "addresses": ["6Y3k..."]}
+func LoadDenyList(filename string) (*DenyList, error) {
+	bytes, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	file := denyListFile{}
+	if err := json.Unmarshal(bytes, &file); err != nil {
+		return nil, fmt.Errorf("problem parsing deny list %s: %w", filename, err)
+	}
+	return NewDenyList(file.Assets, file.Addresses), nil
+}
+
+// Filter implements the IssuanceFilter interface
+func (dl *DenyList) Filter(tx *Tx, consumed []*UTXO) error {
+	for _, assetID := range tx.AssetIDs().List() {
+		if dl.assets.Contains(assetID) {
+			return verify.NewError(CodeDeniedAsset, fmt.Sprintf("asset %s is denied by this node", assetID))
+		}
+	}
+	for _, utxos := range [][]*UTXO{consumed, tx.UTXOs()} {
+		for _, utxo := range utxos {
+			addressable, ok := utxo.Out.(FxAddressable)
+			if !ok {
+				continue
+			}
+			for _, addrBytes := range addressable.Addresses() {
+				addr, err := ids.ToShortID(addrBytes)
+				if err != nil {
+					continue
+				}
+				if dl.addresses.Contains(addr) {
+					return verify.NewError(CodeDeniedAddress, fmt.Sprintf("address %s is denied by this node", addr))
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// consumedUTXOs returns the UTXOs that [tx] spends. The UTXOs may have been
+// produced by transactions that are still processing.
+func (vm *VM) consumedUTXOs(tx *UniqueTx) ([]*UTXO, error) {
+	utxos := []*UTXO(nil)
+	for _, utxoID := range tx.InputUTXOs() {
+		utxo, err := vm.inputUTXO(utxoID)
+		if err != nil {
+			return nil, err
+		}
+		utxos = append(utxos, utxo)
+	}
+	return utxos, nil
+}
+
+// inputUTXO returns the UTXO [utxoID] refers to. If the UTXO isn't in the UTXO
+// set, either because it was already spent or because the transaction that
+// produces it is still processing, it's looked up in that transaction.
+func (vm *VM) inputUTXO(utxoID *UTXOID) (*UTXO, error) {
+	if utxo, err := vm.state.UTXO(utxoID.InputID()); err == nil {
+		return utxo, nil
+	}
+
+	inputTx, inputIndex := utxoID.InputSource()
+	parent := UniqueTx{
+		vm:   vm,
+		txID: inputTx,
+	}
+	parentUTXOs := parent.UTXOs()
+	if uint32(len(parentUTXOs)) <= inputIndex {
+		return nil, errMissingUTXO
+	}
+	return parentUTXOs[int(inputIndex)], nil
+}
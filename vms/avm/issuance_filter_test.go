@@ -0,0 +1,145 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avm
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/crypto"
+	"github.com/ava-labs/gecko/vms/components/verify"
+	"github.com/ava-labs/gecko/vms/secp256k1fx"
+)
+
+// spendGenesisTx returns a signed tx that burns a genesis UTXO owned by keys[0]
+func spendGenesisTx(t *testing.T, vm *VM, genesisTx *Tx) *Tx {
+	tx := &Tx{UnsignedTx: &BaseTx{
+		NetID: networkID,
+		BCID:  chainID,
+		Ins: []*TransferableInput{&TransferableInput{
+			UTXOID: UTXOID{
+				TxID:        genesisTx.ID(),
+				OutputIndex: 1,
+			},
+			Asset: Asset{ID: genesisTx.ID()},
+			In: &secp256k1fx.TransferInput{
+				Amt: 50000,
+				Input: secp256k1fx.Input{
+					SigIndices: []uint32{0},
+				},
+			},
+		}},
+	}}
+
+	unsignedBytes, err := vm.codec.Marshal(&tx.UnsignedTx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig, err := keys[0].Sign(unsignedBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fixedSig := [crypto.SECP256K1RSigLen]byte{}
+	copy(fixedSig[:], sig)
+	tx.Creds = append(tx.Creds, &Credential{
+		Cred: &secp256k1fx.Credential{
+			Sigs: [][crypto.SECP256K1RSigLen]byte{fixedSig},
+		},
+	})
+
+	b, err := vm.codec.Marshal(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx.Initialize(b)
+	return tx
+}
+
+// issueWithDenyList issues a tx spending a genesis UTXO of keys[0] to a VM
+// that refuses the transactions denied by [denyList]
+func issueWithDenyList(t *testing.T, denyList func(genesisTx *Tx) *DenyList) error {
+	genesisBytes := BuildGenesisTest(t)
+	genesisTx := GetFirstTxFromGenesisTest(genesisBytes, t)
+
+	vm := GenesisVM(t)
+	vm.issuanceFilter = denyList(genesisTx)
+
+	tx := spendGenesisTx(t, vm, genesisTx)
+
+	ctx.Lock.Lock()
+	defer ctx.Lock.Unlock()
+
+	_, err := vm.IssueTx(tx.Bytes(), nil)
+	return err
+}
+
+func TestIssueTxDeniedAsset(t *testing.T) {
+	err := issueWithDenyList(t, func(genesisTx *Tx) *DenyList {
+		return NewDenyList([]ids.ID{genesisTx.ID()}, nil)
+	})
+	if err == nil {
+		t.Fatalf("should have refused to issue the tx")
+	}
+	if code := verify.Code(err); code != CodeDeniedAsset {
+		t.Fatalf("wrong error code, expected %d got %d", CodeDeniedAsset, code)
+	}
+}
+
+func TestIssueTxDeniedAddress(t *testing.T) {
+	err := issueWithDenyList(t, func(*Tx) *DenyList {
+		return NewDenyList(nil, []ids.ShortID{keys[0].PublicKey().Address()})
+	})
+	if err == nil {
+		t.Fatalf("should have refused to issue the tx")
+	}
+	if code := verify.Code(err); code != CodeDeniedAddress {
+		t.Fatalf("wrong error code, expected %d got %d", CodeDeniedAddress, code)
+	}
+}
+
+func TestIssueTxNotDenied(t *testing.T) {
+	err := issueWithDenyList(t, func(*Tx) *DenyList {
+		return NewDenyList([]ids.ID{ids.Empty}, []ids.ShortID{keys[1].PublicKey().Address()})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadDenyList(t *testing.T) {
+	addr := keys[0].PublicKey().Address()
+	assetID := ids.NewID([32]byte{1})
+
+	file, err := ioutil.TempFile("", "deny_list")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(file.Name())
+
+	contents := `{"assets": ["` + assetID.String() + `"], "addresses": ["` + addr.String() + `"]}`
+	if _, err := file.WriteString(contents); err != nil {
+		t.Fatal(err)
+	}
+	file.Close()
+
+	denyList, err := LoadDenyList(file.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !denyList.assets.Contains(assetID) {
+		t.Fatalf("should have denied the asset")
+	}
+	if !denyList.addresses.Contains(addr) {
+		t.Fatalf("should have denied the address")
+	}
+
+	if err := ioutil.WriteFile(file.Name(), []byte("not json"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadDenyList(file.Name()); err == nil {
+		t.Fatalf("should have failed to parse the deny list")
+	}
+}
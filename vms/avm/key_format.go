@@ -0,0 +1,213 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avm
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/asn1"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/mr-tron/base58/base58"
+
+	"github.com/ava-labs/gecko/utils/crypto"
+	"github.com/ava-labs/gecko/utils/formatting"
+)
+
+const (
+	// wifMainnetVersion and wifTestnetVersion are the version bytes that
+	// prefix a private key in Wallet Import Format
+	wifMainnetVersion = 0x80
+	wifTestnetVersion = 0xef
+
+	// wifCompressed follows the private key in Wallet Import Format if the
+	// key's public key is compressed
+	wifCompressed = 0x01
+
+	// wifChecksumLen is the number of bytes of the double SHA-256 hash that
+	// checksum a key in Wallet Import Format
+	wifChecksumLen = 4
+)
+
+var (
+	// oidECPublicKey identifies an elliptic curve key in a PKCS #8 structure
+	oidECPublicKey = asn1.ObjectIdentifier{1, 2, 840, 10045, 2, 1}
+	// oidSECP256K1 identifies the secp256k1 curve
+	oidSECP256K1 = asn1.ObjectIdentifier{1, 3, 132, 0, 10}
+
+	errUnknownKeyFormat = errors.New("private key isn't hex, Wallet Import Format, PEM or CB58 encoded")
+	errWrongKeyLength   = fmt.Errorf("private key should be %d bytes", crypto.SECP256K1RSKLen)
+	errWrongKeyCurve    = errors.New("private key isn't a secp256k1 key")
+	errWrongPEMType     = errors.New("PEM block isn't an EC PRIVATE KEY or PRIVATE KEY")
+	errPEMExtraData     = errors.New("PEM encoded private key has trailing data")
+)
+
+// parsePrivateKey returns the bytes of the secp256k1 private key [str],
+// detecting which format it's encoded in. The supported formats are:
+//   - CB58, as returned by exportKey
+//   - Hex, optionally prefixed by "0x"
+//   - Wallet Import Format (WIF), which is base58 with a double SHA-256
+//     checksum, as used by Bitcoin wallets
+//   - PEM encoded SEC 1 ("EC PRIVATE KEY") or PKCS #8 ("PRIVATE KEY")
+//     structures, as output by openssl
+func parsePrivateKey(str string) ([]byte, error) {
+	str = strings.TrimSpace(str)
+
+	var (
+		keyBytes []byte
+		err      error
+	)
+	switch {
+	case strings.HasPrefix(str, "-----BEGIN"):
+		keyBytes, err = parsePEMKey(str)
+	case isHexKey(str):
+		keyBytes, err = hex.DecodeString(trimHexPrefix(str))
+	default:
+		var ok bool
+		if keyBytes, ok = parseWIFKey(str); !ok {
+			cb58 := formatting.CB58{}
+			if cb58Err := cb58.FromString(str); cb58Err != nil {
+				return nil, errUnknownKeyFormat
+			}
+			keyBytes = cb58.Bytes
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(keyBytes) != crypto.SECP256K1RSKLen {
+		return nil, errWrongKeyLength
+	}
+	return keyBytes, nil
+}
+
+func trimHexPrefix(str string) string {
+	if strings.HasPrefix(str, "0x") || strings.HasPrefix(str, "0X") {
+		return str[2:]
+	}
+	return str
+}
+
+// isHexKey returns true if [str] is the hex encoding of a private key
+func isHexKey(str string) bool {
+	str = trimHexPrefix(str)
+	if len(str) != 2*crypto.SECP256K1RSKLen {
+		return false
+	}
+	for _, c := range str {
+		isHex := ('0' <= c && c <= '9') || ('a' <= c && c <= 'f') || ('A' <= c && c <= 'F')
+		if !isHex {
+			return false
+		}
+	}
+	return true
+}
+
+// parseWIFKey returns the private key encoded in Wallet Import Format by
+// [str]. Returns false if [str] isn't a valid WIF key.
+func parseWIFKey(str string) ([]byte, bool) {
+	b, err := base58.Decode(str)
+	if err != nil {
+		return nil, false
+	}
+
+	// version || key || optional compression flag || checksum
+	switch len(b) {
+	case 1 + crypto.SECP256K1RSKLen + wifChecksumLen:
+	case 1 + crypto.SECP256K1RSKLen + 1 + wifChecksumLen:
+		if b[1+crypto.SECP256K1RSKLen] != wifCompressed {
+			return nil, false
+		}
+	default:
+		return nil, false
+	}
+	if b[0] != wifMainnetVersion && b[0] != wifTestnetVersion {
+		return nil, false
+	}
+
+	payload := b[:len(b)-wifChecksumLen]
+	first := sha256.Sum256(payload)
+	second := sha256.Sum256(first[:])
+	if !bytes.Equal(b[len(b)-wifChecksumLen:], second[:wifChecksumLen]) {
+		return nil, false
+	}
+	return payload[1 : 1+crypto.SECP256K1RSKLen], true
+}
+
+// ecPrivateKey is the SEC 1 structure of an elliptic curve private key
+type ecPrivateKey struct {
+	Version       int
+	PrivateKey    []byte
+	NamedCurveOID asn1.ObjectIdentifier `asn1:"optional,explicit,tag:0"`
+	PublicKey     asn1.BitString        `asn1:"optional,explicit,tag:1"`
+}
+
+// pkcs8 is the PKCS #8 structure of an unencrypted private key
+type pkcs8 struct {
+	Version    int
+	Algorithm  pkcs8Algorithm
+	PrivateKey []byte
+}
+
+type pkcs8Algorithm struct {
+	Algorithm  asn1.ObjectIdentifier
+	Parameters asn1.ObjectIdentifier
+}
+
+// parsePEMKey returns the private key of the PEM encoded secp256k1 key [str].
+// The standard library can't be used as it doesn't support secp256k1.
+func parsePEMKey(str string) ([]byte, error) {
+	// openssl may output the curve's parameters before the key
+	rest := []byte(str)
+	for {
+		block, remaining := pem.Decode(rest)
+		if block == nil {
+			return nil, errWrongPEMType
+		}
+		rest = remaining
+
+		switch block.Type {
+		case "EC PARAMETERS":
+			continue
+		case "EC PRIVATE KEY":
+			return parseSEC1Key(block.Bytes, nil)
+		case "PRIVATE KEY":
+			key := pkcs8{}
+			if extra, err := asn1.Unmarshal(block.Bytes, &key); err != nil {
+				return nil, fmt.Errorf("problem parsing PKCS #8 private key: %w", err)
+			} else if len(extra) != 0 {
+				return nil, errPEMExtraData
+			}
+			if !key.Algorithm.Algorithm.Equal(oidECPublicKey) {
+				return nil, errWrongKeyCurve
+			}
+			return parseSEC1Key(key.PrivateKey, key.Algorithm.Parameters)
+		default:
+			return nil, errWrongPEMType
+		}
+	}
+}
+
+// parseSEC1Key returns the private key of the DER encoded SEC 1 structure
+// [der]. [curve], if non-nil, is the curve the key was specified to be on by an
+// enclosing structure.
+func parseSEC1Key(der []byte, curve asn1.ObjectIdentifier) ([]byte, error) {
+	key := ecPrivateKey{}
+	if extra, err := asn1.Unmarshal(der, &key); err != nil {
+		return nil, fmt.Errorf("problem parsing EC private key: %w", err)
+	} else if len(extra) != 0 {
+		return nil, errPEMExtraData
+	}
+	if len(key.NamedCurveOID) != 0 {
+		curve = key.NamedCurveOID
+	}
+	if !curve.Equal(oidSECP256K1) {
+		return nil, errWrongKeyCurve
+	}
+	return key.PrivateKey, nil
+}
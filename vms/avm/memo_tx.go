@@ -0,0 +1,72 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avm
+
+import (
+	"github.com/ava-labs/gecko/snow"
+	"github.com/ava-labs/gecko/vms/components/codec"
+	"github.com/ava-labs/gecko/vms/components/verify"
+)
+
+const (
+	// maxMemoSize is the maximum number of bytes in a transaction's memo
+	maxMemoSize = 256
+)
+
+var (
+	errMemoTooLarge = verify.NewError(CodeMemoTooLarge, "memo is too large")
+	errEmptyMemo    = verify.NewError(CodeEmptyMemo, "memo is empty")
+	errNestedMemo   = verify.NewError(CodeNestedMemo, "memo tx can't attach a memo to another memo tx")
+)
+
+// MemoTx attaches a memo, such as an exchange's identifier for a transfer, to
+// another transaction. Transactions without a memo aren't wrapped, so the
+// encoding, and therefore the ID, of every transaction created before memos
+// existed is unchanged.
+type MemoTx struct {
+	UnsignedTx `serialize:"true"`
+
+	Memo []byte `serialize:"true"` // Arbitrary data
+}
+
+// WithMemo returns [tx] with the memo [memo] attached. If [memo] is empty,
+// [tx] is returned as is.
+func WithMemo(tx UnsignedTx, memo []byte) UnsignedTx {
+	if len(memo) == 0 {
+		return tx
+	}
+	return &MemoTx{
+		UnsignedTx: tx,
+		Memo:       memo,
+	}
+}
+
+// innerTx returns the transaction [tx] attaches a memo to, or [tx] if it isn't
+// a MemoTx
+func innerTx(tx UnsignedTx) UnsignedTx {
+	if memoTx, ok := tx.(*MemoTx); ok {
+		return memoTx.UnsignedTx
+	}
+	return tx
+}
+
+// MemoBytes is the memo attached to this transaction. The returned array should
+// not be modified.
+func (t *MemoTx) MemoBytes() []byte { return t.Memo }
+
+// SyntacticVerify that this transaction is well-formed.
+func (t *MemoTx) SyntacticVerify(ctx *snow.Context, c codec.Codec, numFxs int) error {
+	switch {
+	case t == nil || t.UnsignedTx == nil:
+		return errNilTx
+	case len(t.Memo) == 0:
+		return errEmptyMemo
+	case len(t.Memo) > maxMemoSize:
+		return errMemoTooLarge
+	}
+	if _, ok := t.UnsignedTx.(*MemoTx); ok {
+		return errNestedMemo
+	}
+	return t.UnsignedTx.SyntacticVerify(ctx, c, numFxs)
+}
@@ -0,0 +1,183 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avm
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/vms/components/codec"
+	"github.com/ava-labs/gecko/vms/secp256k1fx"
+)
+
+// memoTestCodec returns a codec with the types registered in the order the VM
+// registers them when the secp256k1fx is its only feature extension
+func memoTestCodec() codec.Codec {
+	c := codec.NewDefault()
+	c.RegisterType(&BaseTx{})
+	c.RegisterType(&CreateAssetTx{})
+	c.RegisterType(&OperationTx{})
+	c.RegisterType(&secp256k1fx.MintOutput{})
+	c.RegisterType(&secp256k1fx.TransferOutput{})
+	c.RegisterType(&secp256k1fx.MintInput{})
+	c.RegisterType(&secp256k1fx.TransferInput{})
+	c.RegisterType(&secp256k1fx.Credential{})
+	c.RegisterType(&ImportTx{})
+	c.RegisterType(&ExportTx{})
+	c.RegisterType(&MemoTx{})
+	return c
+}
+
+func memoTestBaseTx() *BaseTx {
+	return &BaseTx{
+		NetID: networkID,
+		BCID:  chainID,
+		Outs: []*TransferableOutput{
+			&TransferableOutput{
+				Asset: Asset{ID: asset},
+				Out: &secp256k1fx.TransferOutput{
+					Amt: 12345,
+					OutputOwners: secp256k1fx.OutputOwners{
+						Threshold: 1,
+						Addrs:     []ids.ShortID{keys[0].PublicKey().Address()},
+					},
+				},
+			},
+		},
+		Ins: []*TransferableInput{
+			&TransferableInput{
+				UTXOID: UTXOID{
+					TxID:        ids.Empty.Prefix(0),
+					OutputIndex: 1,
+				},
+				Asset: Asset{ID: asset},
+				In: &secp256k1fx.TransferInput{
+					Amt: 54321,
+					Input: secp256k1fx.Input{
+						SigIndices: []uint32{2},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestWithMemoEmpty(t *testing.T) {
+	c := memoTestCodec()
+
+	baseTx := memoTestBaseTx()
+	expected, err := c.Marshal(&Tx{UnsignedTx: baseTx})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tx := &Tx{UnsignedTx: WithMemo(baseTx, nil)}
+	if _, ok := tx.UnsignedTx.(*BaseTx); !ok {
+		t.Fatalf("A tx without a memo shouldn't have been wrapped")
+	}
+	result, err := c.Marshal(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(expected, result) {
+		t.Fatalf("\nExpected: 0x%x\nResult:   0x%x", expected, result)
+	}
+}
+
+func TestMemoTxSerialization(t *testing.T) {
+	c := memoTestCodec()
+
+	baseTx := memoTestBaseTx()
+	baseBytes, err := c.Marshal(&Tx{UnsignedTx: baseTx})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	memo := []byte{0x00, 0x01, 0x02, 0x03}
+	txBytes, err := c.Marshal(&Tx{UnsignedTx: WithMemo(baseTx, memo)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []byte{
+		// txID:
+		0x00, 0x00, 0x00, 0x0a,
+	}
+	// The wrapped tx is encoded as it would be on its own, without its
+	// credentials
+	expected = append(expected, baseBytes[:len(baseBytes)-4]...)
+	expected = append(expected,
+		// memo length:
+		0x00, 0x00, 0x00, 0x04,
+		// memo:
+		0x00, 0x01, 0x02, 0x03,
+		// number of credentials:
+		0x00, 0x00, 0x00, 0x00,
+	)
+	if !bytes.Equal(expected, txBytes) {
+		t.Fatalf("\nExpected: 0x%x\nResult:   0x%x", expected, txBytes)
+	}
+
+	tx := &Tx{}
+	if err := c.Unmarshal(txBytes, tx); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(tx.MemoBytes(), memo) {
+		t.Fatalf("Wrong memo: 0x%x", tx.MemoBytes())
+	}
+	if _, ok := innerTx(tx.UnsignedTx).(*BaseTx); !ok {
+		t.Fatalf("Wrong inner tx type: %T", innerTx(tx.UnsignedTx))
+	}
+	tx.Initialize(txBytes)
+
+	if err := tx.UnsignedTx.SyntacticVerify(ctx, c, 1); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMemoTxSyntacticVerifyNil(t *testing.T) {
+	c := memoTestCodec()
+
+	tx := (*MemoTx)(nil)
+	if err := tx.SyntacticVerify(ctx, c, 1); err != errNilTx {
+		t.Fatalf("Expected %s but got %v", errNilTx, err)
+	}
+}
+
+func TestMemoTxSyntacticVerifyEmpty(t *testing.T) {
+	c := memoTestCodec()
+
+	tx := &MemoTx{UnsignedTx: memoTestBaseTx()}
+	if err := tx.SyntacticVerify(ctx, c, 1); err != errEmptyMemo {
+		t.Fatalf("Expected %s but got %v", errEmptyMemo, err)
+	}
+}
+
+func TestMemoTxSyntacticVerifyMemoTooLarge(t *testing.T) {
+	c := memoTestCodec()
+
+	tx := &MemoTx{
+		UnsignedTx: memoTestBaseTx(),
+		Memo:       make([]byte, maxMemoSize+1),
+	}
+	if err := tx.SyntacticVerify(ctx, c, 1); err != errMemoTooLarge {
+		t.Fatalf("Expected %s but got %v", errMemoTooLarge, err)
+	}
+}
+
+func TestMemoTxSyntacticVerifyNested(t *testing.T) {
+	c := memoTestCodec()
+
+	tx := &MemoTx{
+		UnsignedTx: &MemoTx{
+			UnsignedTx: memoTestBaseTx(),
+			Memo:       []byte{0x01},
+		},
+		Memo: []byte{0x02},
+	}
+	if err := tx.SyntacticVerify(ctx, c, 1); err != errNestedMemo {
+		t.Fatalf("Expected %s but got %v", errNestedMemo, err)
+	}
+}
@@ -0,0 +1,273 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avm
+
+import (
+	"container/list"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow/consensus/snowstorm"
+	"github.com/ava-labs/gecko/vms/components/verify"
+)
+
+const (
+	defaultMempoolSize       = 4096
+	defaultMempoolAddressCap = 256
+)
+
+var (
+	errConflictingTx     = verify.NewError(CodeConflictingTx, "tx conflicts with a pending tx that it doesn't replace")
+	errAddressCapReached = verify.NewError(CodeAddressCapReached, "an address of the tx has too many pending txs")
+	errParentEvicted     = verify.NewError(CodeMempoolFull, "mempool is full and the tx's pending parent was evicted")
+)
+
+// mempool holds the transactions issued through the API until consensus takes
+// them. A tx is only added if it doesn't conflict with a pending tx, unless it
+// replaces the txs it conflicts with, and if none of its addresses already has
+// too many pending txs. When the mempool is full, the oldest pending tx is
+// evicted.
+//
+// A tx replaces the pending txs it conflicts with only if it spends every
+// input of each of them. Spending an input requires the input's credential, so
+// only the owners of a pending tx's inputs can replace it.
+type mempool struct {
+	// maximum number of pending txs
+	maxSize int
+	// maximum number of pending txs that each address may be involved in
+	addressCap int
+
+	// pending txs, oldest first
+	order *list.List
+	txs   map[[32]byte]*list.Element
+	// ID of the pending tx that spends each input
+	spenders map[[32]byte]ids.ID
+	// IDs of the pending txs that spend each pending tx's outputs
+	children map[[32]byte]ids.Set
+	// number of pending txs each address is involved in
+	addressTxs map[[32]byte]int
+}
+
+// pendingTx is a tx held by the mempool
+type pendingTx struct {
+	tx *UniqueTx
+	// IDs of the inputs the tx spends
+	inputs ids.Set
+	// IDs of the pending txs whose outputs the tx spends
+	parents ids.Set
+	// IDs of the addresses the tx consumes from or produces to
+	addrs ids.Set
+}
+
+// droppedTx is a tx that was removed from the mempool without being taken by
+// consensus. Its status is left as it was, as it wasn't decided.
+type droppedTx struct {
+	tx *UniqueTx
+	// ID of the tx that replaced [tx], or whose removal made [tx] invalid. Zero
+	// if [tx] was evicted.
+	causeID ids.ID
+}
+
+func newMempool(maxSize, addressCap int) *mempool {
+	if maxSize <= 0 {
+		maxSize = defaultMempoolSize
+	}
+	if addressCap <= 0 {
+		addressCap = defaultMempoolAddressCap
+	}
+	return &mempool{
+		maxSize:    maxSize,
+		addressCap: addressCap,
+		order:      list.New(),
+		txs:        make(map[[32]byte]*list.Element),
+		spenders:   make(map[[32]byte]ids.ID),
+		children:   make(map[[32]byte]ids.Set),
+		addressTxs: make(map[[32]byte]int),
+	}
+}
+
+// Len returns the number of pending txs
+func (m *mempool) Len() int { return m.order.Len() }
+
+// Has returns true if [txID] is pending
+func (m *mempool) Has(txID ids.ID) bool {
+	_, ok := m.txs[txID.Key()]
+	return ok
+}
+
+// txAddresses returns the IDs of the addresses that own the UTXOs [tx]
+// consumes, which are [consumed], or that it produces
+func txAddresses(tx *UniqueTx, consumed []*UTXO) ids.Set {
+	addrs := ids.Set{}
+	for _, utxos := range [][]*UTXO{consumed, tx.UTXOs()} {
+		for _, utxo := range utxos {
+			addUTXOAddresses(addrs, utxo)
+		}
+	}
+	return addrs
+}
+
+// newPendingTx returns [tx], which involves [addrs], as it would be held by
+// the mempool
+func (m *mempool) newPendingTx(tx *UniqueTx, addrs ids.Set) *pendingTx {
+	ptx := &pendingTx{
+		tx:     tx,
+		inputs: tx.InputIDs(),
+		addrs:  addrs,
+	}
+	for _, utxoID := range tx.InputUTXOs() {
+		if parentID, _ := utxoID.InputSource(); m.Has(parentID) {
+			ptx.parents.Add(parentID)
+		}
+	}
+	return ptx
+}
+
+// Check returns the IDs of the pending txs that [ptx] would replace, or an
+// error if [ptx] can't be added. It doesn't look at [ptx]'s credentials, so
+// it can be called before [ptx] is verified.
+func (m *mempool) Check(ptx *pendingTx) (ids.Set, error) {
+	replaced := ids.Set{}
+	for _, inputID := range ptx.inputs.List() {
+		spenderID, ok := m.spenders[inputID.Key()]
+		if !ok || replaced.Contains(spenderID) {
+			continue
+		}
+		if ptx.parents.Contains(spenderID) {
+			return nil, errConflictingTx
+		}
+		spender := m.txs[spenderID.Key()].Value.(*pendingTx)
+		for _, spentID := range spender.inputs.List() {
+			if !ptx.inputs.Contains(spentID) {
+				return nil, errConflictingTx
+			}
+		}
+		replaced.Add(spenderID)
+	}
+
+	for _, addr := range ptx.addrs.List() {
+		count := m.addressTxs[addr.Key()]
+		for _, txID := range replaced.List() {
+			if m.txs[txID.Key()].Value.(*pendingTx).addrs.Contains(addr) {
+				count--
+			}
+		}
+		if count >= m.addressCap {
+			return nil, errAddressCapReached
+		}
+	}
+	return replaced, nil
+}
+
+// Add [ptx] to the mempool, in place of the pending txs it replaces. The txs
+// that are dropped to make room for [ptx] are returned, even if [ptx] couldn't
+// be added.
+func (m *mempool) Add(ptx *pendingTx) ([]droppedTx, error) {
+	replaced, err := m.Check(ptx)
+	if err != nil {
+		return nil, err
+	}
+
+	txID := ptx.tx.ID()
+	dropped := []droppedTx(nil)
+	for _, replacedID := range replaced.List() {
+		dropped = m.remove(replacedID, txID, dropped)
+	}
+	for m.Len() >= m.maxSize {
+		oldest := m.order.Front().Value.(*pendingTx)
+		dropped = m.remove(oldest.tx.ID(), ids.ID{}, dropped)
+	}
+	for _, parentID := range ptx.parents.List() {
+		if !m.Has(parentID) {
+			return dropped, errParentEvicted
+		}
+	}
+
+	m.txs[txID.Key()] = m.order.PushBack(ptx)
+	for _, inputID := range ptx.inputs.List() {
+		m.spenders[inputID.Key()] = txID
+	}
+	for _, parentID := range ptx.parents.List() {
+		children := m.children[parentID.Key()]
+		children.Add(txID)
+		m.children[parentID.Key()] = children
+	}
+	for _, addr := range ptx.addrs.List() {
+		m.addressTxs[addr.Key()]++
+	}
+	return dropped, nil
+}
+
+// remove [txID], and the pending txs that spend its outputs, from the mempool.
+// The removed txs are appended to [dropped] with the cause [causeID].
+func (m *mempool) remove(txID, causeID ids.ID, dropped []droppedTx) []droppedTx {
+	ptx := m.release(txID)
+	if ptx == nil {
+		return dropped
+	}
+	dropped = append(dropped, droppedTx{
+		tx:      ptx.tx,
+		causeID: causeID,
+	})
+
+	children := m.children[txID.Key()]
+	delete(m.children, txID.Key())
+	for _, childID := range children.List() {
+		dropped = m.remove(childID, txID, dropped)
+	}
+	return dropped
+}
+
+// release removes [txID] from the mempool without removing the txs that spend
+// its outputs, and returns it. Returns nil if [txID] isn't pending.
+func (m *mempool) release(txID ids.ID) *pendingTx {
+	elem, ok := m.txs[txID.Key()]
+	if !ok {
+		return nil
+	}
+	ptx := m.order.Remove(elem).(*pendingTx)
+	delete(m.txs, txID.Key())
+
+	for _, inputID := range ptx.inputs.List() {
+		delete(m.spenders, inputID.Key())
+	}
+	for _, parentID := range ptx.parents.List() {
+		if children, ok := m.children[parentID.Key()]; ok {
+			children.Remove(txID)
+		}
+	}
+	for _, addr := range ptx.addrs.List() {
+		if m.addressTxs[addr.Key()]--; m.addressTxs[addr.Key()] == 0 {
+			delete(m.addressTxs, addr.Key())
+		}
+	}
+	return ptx
+}
+
+// PopAll removes every pending tx from the mempool and returns them, oldest
+// first. Parents are always returned before the txs that spend their outputs.
+func (m *mempool) PopAll() []snowstorm.Tx {
+	txs := make([]snowstorm.Tx, 0, m.order.Len())
+	for elem := m.order.Front(); elem != nil; elem = elem.Next() {
+		txs = append(txs, elem.Value.(*pendingTx).tx)
+	}
+	m.order.Init()
+	m.txs = make(map[[32]byte]*list.Element)
+	m.spenders = make(map[[32]byte]ids.ID)
+	m.children = make(map[[32]byte]ids.Set)
+	m.addressTxs = make(map[[32]byte]int)
+	return txs
+}
+
+// PendingTxIDs returns the IDs of the pending txs, oldest first. If [addr] is
+// non-nil, only the txs that involve [addr] are returned.
+func (m *mempool) PendingTxIDs(addr *ids.ID) []ids.ID {
+	txIDs := []ids.ID{}
+	for elem := m.order.Front(); elem != nil; elem = elem.Next() {
+		ptx := elem.Value.(*pendingTx)
+		if addr == nil || ptx.addrs.Contains(*addr) {
+			txIDs = append(txIDs, ptx.tx.ID())
+		}
+	}
+	return txIDs
+}
@@ -0,0 +1,277 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avm
+
+import (
+	"testing"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow/choices"
+	"github.com/ava-labs/gecko/vms/components/verify"
+	"github.com/ava-labs/gecko/vms/secp256k1fx"
+)
+
+// newTestPendingTx returns a pending tx with the ID [txID] that spends the
+// inputs [inputs], spends the outputs of [parents] and involves [addrs]
+func newTestPendingTx(txID byte, inputs, parents, addrs []byte) *pendingTx {
+	ptx := &pendingTx{tx: &UniqueTx{txID: ids.Empty.Prefix(uint64(txID))}}
+	for _, input := range inputs {
+		ptx.inputs.Add(ids.NewID([32]byte{input}))
+	}
+	for _, parent := range parents {
+		ptx.parents.Add(ids.Empty.Prefix(uint64(parent)))
+	}
+	for _, addr := range addrs {
+		ptx.addrs.Add(ids.NewID([32]byte{addr}))
+	}
+	return ptx
+}
+
+func TestMempoolAdd(t *testing.T) {
+	m := newMempool(0, 0)
+
+	a := newTestPendingTx(1, []byte{1}, nil, []byte{1})
+	b := newTestPendingTx(2, []byte{2}, nil, []byte{1})
+	for _, ptx := range []*pendingTx{a, b} {
+		if dropped, err := m.Add(ptx); err != nil {
+			t.Fatal(err)
+		} else if len(dropped) != 0 {
+			t.Fatalf("Shouldn't have dropped any txs")
+		}
+	}
+	if !m.Has(a.tx.ID()) || !m.Has(b.tx.ID()) {
+		t.Fatalf("Should have held both txs")
+	}
+
+	txs := m.PopAll()
+	switch {
+	case len(txs) != 2:
+		t.Fatalf("Should have popped both txs")
+	case !txs[0].ID().Equals(a.tx.ID()), !txs[1].ID().Equals(b.tx.ID()):
+		t.Fatalf("Should have popped the txs in the order they were added")
+	case m.Len() != 0:
+		t.Fatalf("Should have emptied the mempool")
+	}
+
+	// The inputs and addresses of popped txs aren't pending anymore
+	if _, err := m.Add(newTestPendingTx(3, []byte{1}, nil, []byte{1})); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMempoolConflict(t *testing.T) {
+	m := newMempool(0, 0)
+
+	if _, err := m.Add(newTestPendingTx(1, []byte{1, 2}, nil, nil)); err != nil {
+		t.Fatal(err)
+	}
+	_, err := m.Add(newTestPendingTx(2, []byte{2, 3}, nil, nil))
+	if code := verify.Code(err); code != CodeConflictingTx {
+		t.Fatalf("Expected error code %d but got %d", CodeConflictingTx, code)
+	}
+	if m.Len() != 1 {
+		t.Fatalf("Shouldn't have changed the mempool")
+	}
+}
+
+func TestMempoolReplace(t *testing.T) {
+	m := newMempool(0, 0)
+
+	a := newTestPendingTx(1, []byte{1}, nil, []byte{1})
+	child := newTestPendingTx(2, []byte{2}, []byte{1}, []byte{1})
+	other := newTestPendingTx(3, []byte{3}, nil, []byte{1})
+	for _, ptx := range []*pendingTx{a, child, other} {
+		if _, err := m.Add(ptx); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	replacement := newTestPendingTx(4, []byte{1, 4}, nil, []byte{1})
+	dropped, err := m.Add(replacement)
+	switch {
+	case err != nil:
+		t.Fatal(err)
+	case len(dropped) != 2:
+		t.Fatalf("Should have dropped the replaced tx and its child")
+	case !dropped[0].tx.ID().Equals(a.tx.ID()), !dropped[0].causeID.Equals(replacement.tx.ID()):
+		t.Fatalf("Should have dropped the replaced tx because of the replacement")
+	case !dropped[1].tx.ID().Equals(child.tx.ID()), !dropped[1].causeID.Equals(a.tx.ID()):
+		t.Fatalf("Should have dropped the child because of its parent")
+	}
+
+	txIDs := m.PendingTxIDs(nil)
+	if len(txIDs) != 2 || !txIDs[0].Equals(other.tx.ID()) || !txIDs[1].Equals(replacement.tx.ID()) {
+		t.Fatalf("Wrong pending txs: %v", txIDs)
+	}
+}
+
+func TestMempoolReplaceParent(t *testing.T) {
+	m := newMempool(0, 0)
+
+	if _, err := m.Add(newTestPendingTx(1, []byte{1}, nil, nil)); err != nil {
+		t.Fatal(err)
+	}
+	// Spending both the parent's input and its output is a double spend
+	_, err := m.Add(newTestPendingTx(2, []byte{1, 2}, []byte{1}, nil))
+	if code := verify.Code(err); code != CodeConflictingTx {
+		t.Fatalf("Expected error code %d but got %d", CodeConflictingTx, code)
+	}
+}
+
+func TestMempoolAddressCap(t *testing.T) {
+	m := newMempool(0, 1)
+
+	if _, err := m.Add(newTestPendingTx(1, []byte{1}, nil, []byte{1})); err != nil {
+		t.Fatal(err)
+	}
+	_, err := m.Add(newTestPendingTx(2, []byte{2}, nil, []byte{2, 1}))
+	if code := verify.Code(err); code != CodeAddressCapReached {
+		t.Fatalf("Expected error code %d but got %d", CodeAddressCapReached, code)
+	}
+	if _, err := m.Add(newTestPendingTx(3, []byte{3}, nil, []byte{2})); err != nil {
+		t.Fatal(err)
+	}
+	// Replacing a tx frees its slots
+	if _, err := m.Add(newTestPendingTx(4, []byte{1}, nil, []byte{1})); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMempoolEvictOldest(t *testing.T) {
+	m := newMempool(2, 0)
+
+	a := newTestPendingTx(1, []byte{1}, nil, nil)
+	child := newTestPendingTx(2, []byte{2}, []byte{1}, nil)
+	b := newTestPendingTx(3, []byte{3}, nil, nil)
+	for _, ptx := range []*pendingTx{a, child} {
+		if _, err := m.Add(ptx); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	dropped, err := m.Add(b)
+	switch {
+	case err != nil:
+		t.Fatal(err)
+	case len(dropped) != 2:
+		t.Fatalf("Should have evicted the oldest tx and its child")
+	case !dropped[0].tx.ID().Equals(a.tx.ID()), !dropped[0].causeID.IsZero():
+		t.Fatalf("Should have evicted the oldest tx")
+	case m.Len() != 1 || !m.Has(b.tx.ID()):
+		t.Fatalf("Should have held the new tx")
+	}
+}
+
+func TestMempoolEvictParent(t *testing.T) {
+	m := newMempool(1, 0)
+
+	if _, err := m.Add(newTestPendingTx(1, []byte{1}, nil, nil)); err != nil {
+		t.Fatal(err)
+	}
+	_, err := m.Add(newTestPendingTx(2, []byte{2}, []byte{1}, nil))
+	if code := verify.Code(err); code != CodeMempoolFull {
+		t.Fatalf("Expected error code %d but got %d", CodeMempoolFull, code)
+	}
+	if m.Len() != 0 {
+		t.Fatalf("Should have evicted the parent")
+	}
+}
+
+func TestMempoolPendingTxIDs(t *testing.T) {
+	m := newMempool(0, 0)
+
+	for _, ptx := range []*pendingTx{
+		newTestPendingTx(1, []byte{1}, nil, []byte{1}),
+		newTestPendingTx(2, []byte{2}, nil, []byte{2}),
+		newTestPendingTx(3, []byte{3}, nil, []byte{1, 2}),
+	} {
+		if _, err := m.Add(ptx); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	addr := ids.NewID([32]byte{2})
+	txIDs := m.PendingTxIDs(&addr)
+	if len(txIDs) != 2 || !txIDs[0].Equals(ids.Empty.Prefix(2)) || !txIDs[1].Equals(ids.Empty.Prefix(3)) {
+		t.Fatalf("Wrong pending txs: %v", txIDs)
+	}
+}
+
+// newTestMemoTx returns the bytes of a tx that sends the genesis utxo owned by
+// keys[0] back to keys[0] with the memo [memo]
+func newTestMemoTx(vm *VM, genesisTx *Tx, memo byte, t *testing.T) []byte {
+	key := keys[0]
+	tx := &Tx{UnsignedTx: WithMemo(&BaseTx{
+		NetID: networkID,
+		BCID:  chainID,
+		Outs: []*TransferableOutput{&TransferableOutput{
+			Asset: Asset{ID: genesisTx.ID()},
+			Out: &secp256k1fx.TransferOutput{
+				Amt: 50000,
+				OutputOwners: secp256k1fx.OutputOwners{
+					Threshold: 1,
+					Addrs:     []ids.ShortID{key.PublicKey().Address()},
+				},
+			},
+		}},
+		Ins: []*TransferableInput{&TransferableInput{
+			UTXOID: UTXOID{
+				TxID:        genesisTx.ID(),
+				OutputIndex: 1,
+			},
+			Asset: Asset{ID: genesisTx.ID()},
+			In: &secp256k1fx.TransferInput{
+				Amt:   50000,
+				Input: secp256k1fx.Input{SigIndices: []uint32{0}},
+			},
+		}},
+	}, []byte{memo})}
+	return signTestTx(vm, tx, key, 1, t)
+}
+
+func TestIssueTxReplacesPendingTx(t *testing.T) {
+	genesisBytes := BuildGenesisTest(t)
+	genesisTx := GetFirstTxFromGenesisTest(genesisBytes, t)
+	vm := GenesisVM(t)
+
+	ctx.Lock.Lock()
+	defer ctx.Lock.Unlock()
+
+	firstStatus := choices.Unknown
+	firstID, err := vm.IssueTx(newTestMemoTx(vm, genesisTx, 1, t), func(status choices.Status) {
+		firstStatus = status
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Issuing a pending tx again does nothing
+	if _, err := vm.IssueTx(newTestMemoTx(vm, genesisTx, 1, t), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	secondID, err := vm.IssueTx(newTestMemoTx(vm, genesisTx, 2, t), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Consensus never decided the replaced tx, so it isn't rejected
+	first := &UniqueTx{vm: vm, txID: firstID}
+	if status := first.Status(); status != choices.Processing {
+		t.Fatalf("The replaced tx should still have been processing, but is %s", status)
+	}
+	if firstStatus != choices.Unknown {
+		t.Fatalf("The replaced tx's issuer shouldn't have been told it was decided")
+	}
+	if _, err := vm.state.RejectionCause(firstID); err == nil {
+		t.Fatalf("The replaced tx shouldn't have a rejection cause")
+	}
+	if vm.persisted.txIDs.Contains(firstID) {
+		t.Fatalf("The replaced tx shouldn't be re-issued after a restart")
+	}
+
+	txs := vm.PendingTxs()
+	if len(txs) != 1 || !txs[0].ID().Equals(secondID) {
+		t.Fatalf("Only the replacement should have been issued")
+	}
+}
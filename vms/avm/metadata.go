@@ -4,15 +4,14 @@
 package avm
 
 import (
-	"errors"
-
 	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/utils/hashing"
+	"github.com/ava-labs/gecko/vms/components/verify"
 )
 
 var (
-	errNilMetadata           = errors.New("nil metadata is not valid")
-	errMetadataNotInitialize = errors.New("metadata was never initialized and is not valid")
+	errNilMetadata           = verify.NewError(CodeNilMetadata, "nil metadata is not valid")
+	errMetadataNotInitialize = verify.NewError(CodeMetadataNotInitialize, "metadata was never initialized and is not valid")
 )
 
 type metadata struct {
@@ -5,7 +5,6 @@ package avm
 
 import (
 	"bytes"
-	"errors"
 	"sort"
 
 	"github.com/ava-labs/gecko/utils"
@@ -14,11 +13,11 @@ import (
 )
 
 var (
-	errNilOperableOutput   = errors.New("nil operable output is not valid")
-	errNilOperableFxOutput = errors.New("nil operable feature extension output is not valid")
+	errNilOperableOutput   = verify.NewError(CodeNilOperableOutput, "nil operable output is not valid")
+	errNilOperableFxOutput = verify.NewError(CodeNilOperableFxOutput, "nil operable feature extension output is not valid")
 
-	errNilOperableInput   = errors.New("nil operable input is not valid")
-	errNilOperableFxInput = errors.New("nil operable feature extension input is not valid")
+	errNilOperableInput   = verify.NewError(CodeNilOperableInput, "nil operable input is not valid")
+	errNilOperableFxInput = verify.NewError(CodeNilOperableFxInput, "nil operable feature extension input is not valid")
 )
 
 // OperableOutput ...
@@ -63,10 +62,13 @@ func (outs *innerSortOperableOutputs) Less(i, j int) bool {
 func (outs *innerSortOperableOutputs) Len() int      { return len(outs.outs) }
 func (outs *innerSortOperableOutputs) Swap(i, j int) { o := outs.outs; o[j], o[i] = o[i], o[j] }
 
-func sortOperableOutputs(outs []*OperableOutput, c codec.Codec) {
+// SortOperableOutputs sorts output objects
+func SortOperableOutputs(outs []*OperableOutput, c codec.Codec) {
 	sort.Sort(&innerSortOperableOutputs{outs: outs, codec: c})
 }
-func isSortedOperableOutputs(outs []*OperableOutput, c codec.Codec) bool {
+
+// IsSortedOperableOutputs returns true if output objects are sorted
+func IsSortedOperableOutputs(outs []*OperableOutput, c codec.Codec) bool {
 	return sort.IsSorted(&innerSortOperableOutputs{outs: outs, codec: c})
 }
 
@@ -56,11 +56,11 @@ func TestOperableOutputSorting(t *testing.T) {
 		},
 	}
 
-	if isSortedOperableOutputs(outs, c) {
+	if IsSortedOperableOutputs(outs, c) {
 		t.Fatalf("Shouldn't be sorted")
 	}
-	sortOperableOutputs(outs, c)
-	if !isSortedOperableOutputs(outs, c) {
+	SortOperableOutputs(outs, c)
+	if !IsSortedOperableOutputs(outs, c) {
 		t.Fatalf("Should be sorted")
 	}
 	if result := outs[0].Out.(*TestTransferable).Val; result != 0 {
@@ -5,16 +5,16 @@ package avm
 
 import (
 	"bytes"
-	"errors"
 	"sort"
 
 	"github.com/ava-labs/gecko/utils"
 	"github.com/ava-labs/gecko/vms/components/codec"
+	"github.com/ava-labs/gecko/vms/components/verify"
 )
 
 var (
-	errNilOperation   = errors.New("nil operation is not valid")
-	errEmptyOperation = errors.New("empty operation is not valid")
+	errNilOperation   = verify.NewError(CodeNilOperation, "nil operation is not valid")
+	errEmptyOperation = verify.NewError(CodeEmptyOperation, "empty operation is not valid")
 )
 
 // Operation ...
@@ -48,7 +48,7 @@ func (op *Operation) Verify(c codec.Codec) error {
 			return err
 		}
 	}
-	if !isSortedOperableOutputs(op.Outs, c) {
+	if !IsSortedOperableOutputs(op.Outs, c) {
 		return errOutputsNotSorted
 	}
 
@@ -4,17 +4,16 @@
 package avm
 
 import (
-	"errors"
-
 	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/snow"
 	"github.com/ava-labs/gecko/vms/components/codec"
+	"github.com/ava-labs/gecko/vms/components/verify"
 )
 
 var (
-	errOperationsNotSortedUnique = errors.New("operations not sorted and unique")
+	errOperationsNotSortedUnique = verify.NewError(CodeOperationsNotSortedUnique, "operations not sorted and unique")
 
-	errDoubleSpend = errors.New("inputs attempt to double spend an input")
+	errDoubleSpend = verify.NewError(CodeDoubleSpend, "inputs attempt to double spend an input")
 )
 
 // OperationTx is a transaction with no credentials.
@@ -107,9 +106,18 @@ func (t *OperationTx) SyntacticVerify(ctx *snow.Context, c codec.Codec, numFxs i
 
 // SemanticVerify that this transaction is well-formed.
 func (t *OperationTx) SemanticVerify(vm *VM, uTx *UniqueTx, creds []*Credential) error {
-	if err := t.BaseTx.SemanticVerify(vm, uTx, creds); err != nil {
+	if err := vm.verifyFee(t.Ins, t.Outs); err != nil {
+		return err
+	}
+
+	// Every input is looked up before any credential is verified, so that the
+	// credentials can be verified concurrently
+	tx := newSignedTx(uTx)
+	checks, err := t.BaseTx.inputChecks(vm, tx, creds)
+	if err != nil {
 		return err
 	}
+
 	offset := len(t.BaseTx.Ins)
 	for _, op := range t.Ops {
 		opAssetID := op.AssetID()
@@ -143,7 +151,7 @@ func (t *OperationTx) SemanticVerify(vm *VM, uTx *UniqueTx, creds []*Credential)
 				txID: inputTx,
 			}
 
-			if err := parent.Verify(); err != nil {
+			if err := parent.verify(); err != nil {
 				return errMissingUTXO
 			} else if status := parent.Status(); status.Decided() {
 				return errMissingUTXO
@@ -186,10 +194,18 @@ func (t *OperationTx) SemanticVerify(vm *VM, uTx *UniqueTx, creds []*Credential)
 			return errIncompatibleFx
 		}
 
-		err = fx.VerifyOperation(uTx, utxos, ins, credIntfs, outs)
-		if err != nil {
-			return err
+		checks = append(checks, operationCheck(fx, tx, utxos, ins, credIntfs, outs))
+	}
+	return vm.verifyAll(checks)
+}
+
+// operationCheck returns a check that [creds] authorize the operation that
+// consumes [utxos] to produce [outs]
+func operationCheck(fx Fx, tx *signedTx, utxos, ins, creds, outs []interface{}) func() error {
+	return func() error {
+		if err := fx.VerifyOperation(tx, utxos, ins, creds, outs); err != nil {
+			return verify.WrapError(CodeFxVerificationFailed, err)
 		}
+		return nil
 	}
-	return nil
 }
@@ -0,0 +1,126 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avm
+
+import (
+	"time"
+
+	"github.com/ava-labs/gecko/database"
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow/choices"
+)
+
+const (
+	// maxPersistedTxs is the number of issued but undecided txs that are
+	// saved to be re-issued after a restart. When more are undecided, the
+	// oldest of them aren't re-issued.
+	maxPersistedTxs = 1024
+
+	// maxPersistedTxAge is the age of the oldest tx that's re-issued after a
+	// restart. Older txs were likely dropped by the rest of the network, or
+	// their issuers have since given up on them.
+	maxPersistedTxAge = 10 * time.Minute
+)
+
+// persistedTx is a tx that was issued through the API and hasn't been decided
+type persistedTx struct {
+	TxID ids.ID `serialize:"true"`
+	// Unix time the tx was issued at
+	IssuedAt uint64 `serialize:"true"`
+}
+
+// persistedTxs are the txs issued through the API that haven't been decided,
+// oldest first. They're saved so they can be re-issued after a restart.
+type persistedTxs struct {
+	txs   []persistedTx
+	txIDs ids.Set
+}
+
+// persistTx saves [txID], issued through the API, to be re-issued if the node
+// restarts before it's decided. The change isn't committed.
+func (vm *VM) persistTx(txID ids.ID) error {
+	vm.persisted.txs = append(vm.persisted.txs, persistedTx{
+		TxID:     txID,
+		IssuedAt: vm.clock.Unix(),
+	})
+	vm.persisted.txIDs.Add(txID)
+	if excess := len(vm.persisted.txs) - maxPersistedTxs; excess > 0 {
+		for _, ptx := range vm.persisted.txs[:excess] {
+			vm.persisted.txIDs.Remove(ptx.TxID)
+		}
+		vm.persisted.txs = vm.persisted.txs[excess:]
+	}
+	return vm.savePersistedTxs()
+}
+
+// unpersistTx stops [txID] from being re-issued after a restart, as it has
+// been decided. The change isn't committed.
+func (vm *VM) unpersistTx(txID ids.ID) error {
+	if !vm.persisted.txIDs.Contains(txID) {
+		return nil
+	}
+	vm.persisted.txIDs.Remove(txID)
+	for i, ptx := range vm.persisted.txs {
+		if ptx.TxID.Equals(txID) {
+			vm.persisted.txs = append(vm.persisted.txs[:i], vm.persisted.txs[i+1:]...)
+			break
+		}
+	}
+	return vm.savePersistedTxs()
+}
+
+func (vm *VM) savePersistedTxs() error {
+	if len(vm.persisted.txs) == 0 {
+		return vm.db.Delete(persistedTxsKey.Bytes())
+	}
+	bytes, err := vm.codec.Marshal(vm.persisted.txs)
+	if err != nil {
+		return err
+	}
+	return vm.db.Put(persistedTxsKey.Bytes(), bytes)
+}
+
+// reissuePersistedTxs adds the txs that were issued through the API before
+// the last shutdown, and haven't been decided, back to the mempool. Txs older
+// than [maxPersistedTxAge], and txs that are no longer valid, are dropped. The
+// change isn't committed.
+func (vm *VM) reissuePersistedTxs() error {
+	bytes, err := vm.db.Get(persistedTxsKey.Bytes())
+	if err == database.ErrNotFound {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	saved := []persistedTx(nil)
+	if err := vm.codec.Unmarshal(bytes, &saved); err != nil {
+		return err
+	}
+
+	now := vm.clock.Time()
+	for _, ptx := range saved {
+		if now.Sub(time.Unix(int64(ptx.IssuedAt), 0)) > maxPersistedTxAge {
+			continue
+		}
+		tx := &UniqueTx{
+			vm:   vm,
+			txID: ptx.TxID,
+		}
+		if tx.Status() != choices.Processing {
+			continue
+		}
+		if err := vm.issue(tx); err != nil {
+			vm.ctx.Log.Debug("Dropping tx %s issued before the restart due to %s", ptx.TxID, err)
+			continue
+		}
+		vm.persisted.txs = append(vm.persisted.txs, ptx)
+		vm.persisted.txIDs.Add(ptx.TxID)
+	}
+	if n := len(vm.persisted.txs); n > 0 {
+		vm.ctx.Log.Info("Re-issued %d txs that were issued before the restart", n)
+		// The engine drops the txs while it's bootstrapping, so they're
+		// offered to it until it takes them
+		vm.awaitingEngine = true
+	}
+	return vm.savePersistedTxs()
+}
@@ -0,0 +1,137 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avm
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/gecko/database"
+	"github.com/ava-labs/gecko/database/memdb"
+	"github.com/ava-labs/gecko/database/prefixdb"
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow/engine/common"
+	"github.com/ava-labs/gecko/vms/secp256k1fx"
+)
+
+// restartedVM returns a VM initialized at [now] with the state in [db]. The
+// VM is given a prefixed database as shutting it down closes its database.
+func restartedVM(t *testing.T, db database.Database, now time.Time) *VM {
+	genesisBytes := BuildGenesisTest(t)
+
+	ctx.Lock.Lock()
+	defer ctx.Lock.Unlock()
+
+	vm := &VM{}
+	vm.clock.Set(now)
+	err := vm.Initialize(
+		ctx,
+		prefixdb.New([]byte("vm"), db),
+		genesisBytes,
+		make(chan common.Message, 1),
+		[]*common.Fx{&common.Fx{
+			ID: ids.Empty,
+			Fx: &secp256k1fx.Fx{},
+		}},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return vm
+}
+
+// issueBeforeRestart issues a tx at [now] to a VM with the state in [db], and
+// shuts the VM down. If [decide] is non-nil, it's called with the issued tx
+// before the VM is shut down.
+func issueBeforeRestart(t *testing.T, db database.Database, now time.Time, decide func(*UniqueTx)) ids.ID {
+	genesisTx := GetFirstTxFromGenesisTest(BuildGenesisTest(t), t)
+
+	vm := restartedVM(t, db, now)
+	ctx.Lock.Lock()
+	defer func() {
+		ctx.Lock.Unlock()
+		vm.Shutdown()
+	}()
+
+	tx := spendGenesisTx(t, vm, genesisTx)
+	txID, err := vm.IssueTx(tx.Bytes(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decide != nil {
+		txs := vm.PendingTxs()
+		if len(txs) != 1 {
+			t.Fatalf("Should have issued 1 tx")
+		}
+		decide(txs[0].(*UniqueTx))
+	}
+	return txID
+}
+
+func TestReissuePersistedTxs(t *testing.T) {
+	db := memdb.New()
+	now := time.Unix(1000000, 0)
+	txID := issueBeforeRestart(t, db, now, nil)
+
+	vm := restartedVM(t, db, now.Add(time.Minute))
+	ctx.Lock.Lock()
+	defer func() {
+		ctx.Lock.Unlock()
+		vm.Shutdown()
+	}()
+
+	if !vm.mempool.Has(txID) {
+		t.Fatalf("Should have re-issued the undecided tx")
+	}
+	if !vm.awaitingEngine {
+		t.Fatalf("Should have offered the re-issued tx to the engine until it's taken")
+	}
+	if txs := vm.PendingTxs(); len(txs) != 1 || !txs[0].ID().Equals(txID) {
+		t.Fatalf("Should have returned the re-issued tx")
+	}
+	if vm.awaitingEngine {
+		t.Fatalf("Shouldn't offer the mempool once the engine has taken it")
+	}
+}
+
+func TestReissuePersistedTxsTooOld(t *testing.T) {
+	db := memdb.New()
+	now := time.Unix(1000000, 0)
+	txID := issueBeforeRestart(t, db, now, nil)
+
+	vm := restartedVM(t, db, now.Add(maxPersistedTxAge+time.Second))
+	ctx.Lock.Lock()
+	defer func() {
+		ctx.Lock.Unlock()
+		vm.Shutdown()
+	}()
+
+	if vm.mempool.Has(txID) {
+		t.Fatalf("Shouldn't have re-issued a tx older than %s", maxPersistedTxAge)
+	}
+	if vm.persisted.txIDs.Len() != 0 {
+		t.Fatalf("Should have stopped saving the old tx")
+	}
+}
+
+func TestReissuePersistedTxsDecided(t *testing.T) {
+	db := memdb.New()
+	now := time.Unix(1000000, 0)
+	txID := issueBeforeRestart(t, db, now, func(tx *UniqueTx) { tx.Accept(context.Background()) })
+
+	vm := restartedVM(t, db, now.Add(time.Minute))
+	ctx.Lock.Lock()
+	defer func() {
+		ctx.Lock.Unlock()
+		vm.Shutdown()
+	}()
+
+	if vm.mempool.Has(txID) {
+		t.Fatalf("Shouldn't have re-issued an accepted tx")
+	}
+	if vm.persisted.txIDs.Len() != 0 {
+		t.Fatalf("Should have stopped saving the accepted tx")
+	}
+}
@@ -4,10 +4,14 @@
 package avm
 
 import (
+	"errors"
+
 	"github.com/ava-labs/gecko/cache"
+	"github.com/ava-labs/gecko/database"
 	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/snow/choices"
 	"github.com/ava-labs/gecko/utils/hashing"
+	"github.com/ava-labs/gecko/utils/wrappers"
 )
 
 const (
@@ -16,10 +20,39 @@ const (
 	txStatusID
 	fundsID
 	dbInitializedID
+	assetFundsID
+	assetIndexInitializedID
+	rejectionCauseID
+	addressTxCountID
+	addressTxID
+	addressTxIndexInitializedID
+	assetFreezerID
+	persistedTxsID
+	utxoSetHashID
+	utxoSetHashInitializedID
+	assetStatsID
+	assetStatsAddressID
+	assetStatsInitializedID
+	importedUTXOID
+)
+
+var (
+	errWrongNumberOfCauses = errors.New("expected exactly one rejection cause")
+	errWrongNumberOfTxs    = errors.New("expected exactly one transaction")
 )
 
 var (
-	dbInitialized = ids.Empty.Prefix(dbInitializedID)
+	dbInitialized         = ids.Empty.Prefix(dbInitializedID)
+	assetIndexInitialized = ids.Empty.Prefix(assetIndexInitializedID)
+
+	addressTxIndexInitialized = ids.Empty.Prefix(addressTxIndexInitializedID)
+
+	persistedTxsKey = ids.Empty.Prefix(persistedTxsID)
+
+	utxoSetHashKey         = ids.Empty.Prefix(utxoSetHashID)
+	utxoSetHashInitialized = ids.Empty.Prefix(utxoSetHashInitializedID)
+
+	assetStatsInitialized = ids.Empty.Prefix(assetStatsInitializedID)
 )
 
 // prefixedState wraps a state object. By prefixing the state, there will be no
@@ -27,8 +60,10 @@ var (
 type prefixedState struct {
 	state *state
 
-	tx, utxo, txStatus, funds cache.Cacher
-	uniqueTx                  cache.Deduplicator
+	tx, utxo, txStatus, funds, assetFunds, rejectionCause cache.Cacher
+	addressTxCount, addressTx, assetFreezer               cache.Cacher
+	assetStats, assetStatsAddress                         cache.Cacher
+	uniqueTx                                              cache.Deduplicator
 }
 
 // UniqueTx de-duplicates the transaction.
@@ -64,6 +99,37 @@ func (s *prefixedState) SetStatus(id ids.ID, status choices.Status) error {
 	return s.state.SetStatus(s.uniqueID(id, txStatusID, s.txStatus), status)
 }
 
+// ImportedUTXO returns Accepted if the UTXO [id] was imported from the
+// Platform Chain
+func (s *prefixedState) ImportedUTXO(id ids.ID) (choices.Status, error) {
+	return s.state.Status(id.Prefix(importedUTXOID))
+}
+
+// SetImportedUTXO saves whether the UTXO [id] was imported from the Platform
+// Chain
+func (s *prefixedState) SetImportedUTXO(id ids.ID, status choices.Status) error {
+	return s.state.SetStatus(id.Prefix(importedUTXOID), status)
+}
+
+// RejectionCause returns the ID of the transaction that caused the provided
+// transaction id to be rejected.
+func (s *prefixedState) RejectionCause(id ids.ID) (ids.ID, error) {
+	causes, err := s.state.IDs(s.uniqueID(id, rejectionCauseID, s.rejectionCause))
+	if err != nil {
+		return ids.ID{}, err
+	}
+	if len(causes) != 1 {
+		return ids.ID{}, errWrongNumberOfCauses
+	}
+	return causes[0], nil
+}
+
+// SetRejectionCause saves the ID of the transaction that caused the provided
+// transaction id to be rejected.
+func (s *prefixedState) SetRejectionCause(id ids.ID, causeID ids.ID) error {
+	return s.state.SetIDs(s.uniqueID(id, rejectionCauseID, s.rejectionCause), []ids.ID{causeID})
+}
+
 // DBInitialized returns the status of this database. If the database is
 // uninitialized, the status will be unknown.
 func (s *prefixedState) DBInitialized() (choices.Status, error) { return s.state.Status(dbInitialized) }
@@ -84,6 +150,199 @@ func (s *prefixedState) SetFunds(id ids.ID, idSlice []ids.ID) error {
 	return s.state.SetIDs(s.uniqueID(id, fundsID, s.funds), idSlice)
 }
 
+// AssetFunds returns the IDs of the utxos of [assetID] that reference the
+// address whose 32 byte representation is [addr].
+func (s *prefixedState) AssetFunds(addr, assetID ids.ID) ([]ids.ID, error) {
+	return s.state.IDs(s.uniqueID(assetFundsKey(addr, assetID), assetFundsID, s.assetFunds))
+}
+
+// SetAssetFunds saves the mapping from address and asset to utxo IDs to
+// storage.
+func (s *prefixedState) SetAssetFunds(addr, assetID ids.ID, idSlice []ids.ID) error {
+	return s.state.SetIDs(s.uniqueID(assetFundsKey(addr, assetID), assetFundsID, s.assetFunds), idSlice)
+}
+
+// AssetIndexInitialized returns the status of the address/asset index. If the
+// database was initialized before the index existed, the index may be
+// incomplete and the status will be unknown.
+func (s *prefixedState) AssetIndexInitialized() (choices.Status, error) {
+	return s.state.Status(assetIndexInitialized)
+}
+
+// SetAssetIndexInitialized saves the provided status of the address/asset
+// index.
+func (s *prefixedState) SetAssetIndexInitialized(status choices.Status) error {
+	return s.state.SetStatus(assetIndexInitialized, status)
+}
+
+// AddressTxCount returns the number of accepted transactions that have been
+// indexed as touching the address.
+func (s *prefixedState) AddressTxCount(addr ids.ID) (uint64, error) {
+	return s.state.Int(s.uniqueID(addr, addressTxCountID, s.addressTxCount))
+}
+
+// SetAddressTxCount saves the number of accepted transactions that have been
+// indexed as touching the address.
+func (s *prefixedState) SetAddressTxCount(addr ids.ID, count uint64) error {
+	return s.state.SetInt(s.uniqueID(addr, addressTxCountID, s.addressTxCount), count)
+}
+
+// AddressTx returns the ID of the [index]th accepted transaction that touched
+// the address.
+func (s *prefixedState) AddressTx(addr ids.ID, index uint64) (ids.ID, error) {
+	txIDs, err := s.state.IDs(s.uniqueID(addressTxKey(addr, index), addressTxID, s.addressTx))
+	if err != nil {
+		return ids.ID{}, err
+	}
+	if len(txIDs) != 1 {
+		return ids.ID{}, errWrongNumberOfTxs
+	}
+	return txIDs[0], nil
+}
+
+// SetAddressTx saves the ID of the [index]th accepted transaction that touched
+// the address.
+func (s *prefixedState) SetAddressTx(addr ids.ID, index uint64, txID ids.ID) error {
+	return s.state.SetIDs(s.uniqueID(addressTxKey(addr, index), addressTxID, s.addressTx), []ids.ID{txID})
+}
+
+// IndexAddressTx appends the transaction to the history of each address.
+func (s *prefixedState) IndexAddressTx(txID ids.ID, addrs ids.Set) error {
+	for _, addr := range addrs.List() {
+		count, err := s.AddressTxCount(addr)
+		if err == database.ErrNotFound {
+			count = 0
+		} else if err != nil {
+			return err
+		}
+		if err := s.SetAddressTx(addr, count, txID); err != nil {
+			return err
+		}
+		if err := s.SetAddressTxCount(addr, count+1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AssetFreezer returns the index, plus one, of the fx whose output froze
+// transfers of the asset. If the asset isn't frozen, 0 is returned.
+func (s *prefixedState) AssetFreezer(assetID ids.ID) (uint64, error) {
+	freezer, err := s.state.Int(s.uniqueID(assetID, assetFreezerID, s.assetFreezer))
+	if err == database.ErrNotFound {
+		return 0, nil
+	}
+	return freezer, err
+}
+
+// SetAssetFreezer saves the index, plus one, of the fx whose output froze
+// transfers of the asset. 0 unfreezes the asset.
+func (s *prefixedState) SetAssetFreezer(assetID ids.ID, freezer uint64) error {
+	return s.state.SetInt(s.uniqueID(assetID, assetFreezerID, s.assetFreezer), freezer)
+}
+
+// AddressTxIndexInitialized returns the status of the address tx index. The
+// status is accepted if every accepted transaction has been indexed, and
+// processing if transactions were accepted while indexing was disabled.
+func (s *prefixedState) AddressTxIndexInitialized() (choices.Status, error) {
+	return s.state.Status(addressTxIndexInitialized)
+}
+
+// SetAddressTxIndexInitialized saves the provided status of the address tx
+// index.
+func (s *prefixedState) SetAddressTxIndexInitialized(status choices.Status) error {
+	return s.state.SetStatus(addressTxIndexInitialized, status)
+}
+
+// UTXOSetHash returns the hash of the set of unspent utxos. If no utxo has been
+// added, the hash is zero.
+func (s *prefixedState) UTXOSetHash() (*utxoSetHash, error) {
+	hash, err := s.state.UTXOSetHash(utxoSetHashKey)
+	if err == database.ErrNotFound {
+		return &utxoSetHash{}, nil
+	}
+	return hash, err
+}
+
+// SetUTXOSetHash saves the hash of the set of unspent utxos.
+func (s *prefixedState) SetUTXOSetHash(hash *utxoSetHash) error {
+	return s.state.SetUTXOSetHash(utxoSetHashKey, hash)
+}
+
+// UTXOSetHashInitialized returns the status of the utxo set hash. The status
+// is accepted if every utxo has been hashed. If the database was initialized
+// before the hash existed, the status will be unknown.
+func (s *prefixedState) UTXOSetHashInitialized() (choices.Status, error) {
+	return s.state.Status(utxoSetHashInitialized)
+}
+
+// SetUTXOSetHashInitialized saves the provided status of the utxo set hash.
+func (s *prefixedState) SetUTXOSetHashInitialized(status choices.Status) error {
+	return s.state.SetStatus(utxoSetHashInitialized, status)
+}
+
+// AssetStats returns the stats of the asset during the [period]th period since
+// the unix epoch. If the asset wasn't active during the period, the stats are
+// zero.
+func (s *prefixedState) AssetStats(assetID ids.ID, period uint64) (assetStats, error) {
+	stats, err := s.state.AssetStats(s.uniqueID(assetStatsKey(assetID, period), assetStatsID, s.assetStats))
+	if err == database.ErrNotFound {
+		return assetStats{}, nil
+	}
+	return stats, err
+}
+
+// SetAssetStats saves the stats of the asset during the [period]th period
+// since the unix epoch.
+func (s *prefixedState) SetAssetStats(assetID ids.ID, period uint64, stats assetStats) error {
+	return s.state.SetAssetStats(s.uniqueID(assetStatsKey(assetID, period), assetStatsID, s.assetStats), stats)
+}
+
+// IndexAssetStats adds the activity of an accepted transaction to the stats of
+// each asset it touched during [period]. An address is only counted once per
+// asset and period.
+func (s *prefixedState) IndexAssetStats(period uint64, activities assetActivities) error {
+	for assetKey, activity := range activities {
+		assetID := ids.NewID(assetKey)
+		stats, err := s.AssetStats(assetID, period)
+		if err != nil {
+			return err
+		}
+		stats.TxCount++
+		stats.Transferred = addSaturating(stats.Transferred, activity.transferred)
+
+		for _, addr := range activity.addrs.List() {
+			key := s.uniqueID(assetStatsAddressKey(assetID, period, addr), assetStatsAddressID, s.assetStatsAddress)
+			if _, err := s.state.Int(key); err == nil {
+				continue // The address was already counted
+			} else if err != database.ErrNotFound {
+				return err
+			}
+			if err := s.state.SetInt(key, 1); err != nil {
+				return err
+			}
+			stats.Addresses++
+		}
+
+		if err := s.SetAssetStats(assetID, period, stats); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AssetStatsInitialized returns the status of the asset stats index. The
+// status is accepted if every accepted transaction has been counted, and
+// processing if transactions were accepted while the index was disabled.
+func (s *prefixedState) AssetStatsInitialized() (choices.Status, error) {
+	return s.state.Status(assetStatsInitialized)
+}
+
+// SetAssetStatsInitialized saves the provided status of the asset stats index.
+func (s *prefixedState) SetAssetStatsInitialized(status choices.Status) error {
+	return s.state.SetStatus(assetStatsInitialized, status)
+}
+
 func (s *prefixedState) uniqueID(id ids.ID, prefix uint64, cacher cache.Cacher) ids.ID {
 	if cachedIDIntf, found := cacher.Get(id); found {
 		return cachedIDIntf.(ids.ID)
@@ -102,16 +361,19 @@ func (s *prefixedState) SpendUTXO(utxoID ids.ID) error {
 	if err := s.SetUTXO(utxoID, nil); err != nil {
 		return err
 	}
+	if err := s.hashUTXO(utxo, false); err != nil {
+		return err
+	}
 
 	addressable, ok := utxo.Out.(FxAddressable)
 	if !ok {
 		return nil
 	}
 
-	return s.removeUTXO(addressable.Addresses(), utxoID)
+	return s.removeUTXO(addressable.Addresses(), utxo.AssetID(), utxoID)
 }
 
-func (s *prefixedState) removeUTXO(addrs [][]byte, utxoID ids.ID) error {
+func (s *prefixedState) removeUTXO(addrs [][]byte, assetID, utxoID ids.ID) error {
 	for _, addr := range addrs {
 		addrID := ids.NewID(hashing.ComputeHash256Array(addr))
 		utxos := ids.Set{}
@@ -121,6 +383,14 @@ func (s *prefixedState) removeUTXO(addrs [][]byte, utxoID ids.ID) error {
 		if err := s.SetFunds(addrID, utxos.List()); err != nil {
 			return err
 		}
+
+		assetUTXOs := ids.Set{}
+		assetFunds, _ := s.AssetFunds(addrID, assetID)
+		assetUTXOs.Add(assetFunds...)
+		assetUTXOs.Remove(utxoID)
+		if err := s.SetAssetFunds(addrID, assetID, assetUTXOs.List()); err != nil {
+			return err
+		}
 	}
 	return nil
 }
@@ -131,16 +401,19 @@ func (s *prefixedState) FundUTXO(utxo *UTXO) error {
 	if err := s.SetUTXO(utxoID, utxo); err != nil {
 		return err
 	}
+	if err := s.hashUTXO(utxo, true); err != nil {
+		return err
+	}
 
 	addressable, ok := utxo.Out.(FxAddressable)
 	if !ok {
 		return nil
 	}
 
-	return s.addUTXO(addressable.Addresses(), utxoID)
+	return s.addUTXO(addressable.Addresses(), utxo.AssetID(), utxoID)
 }
 
-func (s *prefixedState) addUTXO(addrs [][]byte, utxoID ids.ID) error {
+func (s *prefixedState) addUTXO(addrs [][]byte, assetID, utxoID ids.ID) error {
 	for _, addr := range addrs {
 		addrID := ids.NewID(hashing.ComputeHash256Array(addr))
 		utxos := ids.Set{}
@@ -150,6 +423,49 @@ func (s *prefixedState) addUTXO(addrs [][]byte, utxoID ids.ID) error {
 		if err := s.SetFunds(addrID, utxos.List()); err != nil {
 			return err
 		}
+
+		assetUTXOs := ids.Set{}
+		assetFunds, _ := s.AssetFunds(addrID, assetID)
+		assetUTXOs.Add(assetFunds...)
+		assetUTXOs.Add(utxoID)
+		if err := s.SetAssetFunds(addrID, assetID, assetUTXOs.List()); err != nil {
+			return err
+		}
 	}
 	return nil
 }
+
+// hashUTXO adds [utxo] to, or if not [funded] removes it from, the utxo set
+// hash
+func (s *prefixedState) hashUTXO(utxo *UTXO, funded bool) error {
+	bytes, err := s.state.vm.codec.Marshal(utxo)
+	if err != nil {
+		return err
+	}
+	hash, err := s.UTXOSetHash()
+	if err != nil {
+		return err
+	}
+	if funded {
+		hash.Add(bytes)
+	} else {
+		hash.Remove(bytes)
+	}
+	return s.SetUTXOSetHash(hash)
+}
+
+// assetFundsKey returns the ID the utxos of [assetID] that reference [addr] are
+// stored under
+func assetFundsKey(addr, assetID ids.ID) ids.ID {
+	key := make([]byte, 2*hashing.HashLen)
+	copy(key, addr.Bytes())
+	copy(key[hashing.HashLen:], assetID.Bytes())
+	return ids.NewID(hashing.ComputeHash256Array(key))
+}
+
+func addressTxKey(addr ids.ID, index uint64) ids.ID {
+	p := wrappers.Packer{Bytes: make([]byte, hashing.HashLen+wrappers.LongLen)}
+	p.PackFixedBytes(addr.Bytes())
+	p.PackLong(index)
+	return ids.NewID(hashing.ComputeHash256Array(p.Bytes))
+}
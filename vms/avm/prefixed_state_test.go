@@ -177,3 +177,46 @@ func TestPrefixedFundingAddresses(t *testing.T) {
 		t.Fatalf("Should have returned no utxoIDs")
 	}
 }
+
+func TestPrefixedFundingAssetIndex(t *testing.T) {
+	vm := GenesisVM(t)
+	state := vm.state
+
+	vm.codec.RegisterType(&testAddressable{})
+
+	assetID := ids.NewID([32]byte{1})
+	utxo := &UTXO{
+		UTXOID: UTXOID{
+			TxID:        ids.Empty,
+			OutputIndex: 1,
+		},
+		Asset: Asset{ID: assetID},
+		Out: &testAddressable{
+			Addrs: [][]byte{
+				[]byte{0},
+			},
+		},
+	}
+	addr := ids.NewID(hashing.ComputeHash256Array([]byte{0}))
+
+	if err := state.FundUTXO(utxo); err != nil {
+		t.Fatal(err)
+	}
+	funds, err := state.AssetFunds(addr, assetID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(funds) != 1 || !funds[0].Equals(utxo.InputID()) {
+		t.Fatalf("Should have returned the funded utxoID")
+	}
+	if _, err := state.AssetFunds(addr, ids.Empty); err == nil {
+		t.Fatalf("Shouldn't have returned utxoIDs of another asset")
+	}
+
+	if err := state.SpendUTXO(utxo.InputID()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := state.AssetFunds(addr, assetID); err == nil {
+		t.Fatalf("Should have returned no utxoIDs")
+	}
+}
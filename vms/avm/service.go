@@ -9,7 +9,9 @@ import (
 	"fmt"
 	"net/http"
 	"sort"
+	"time"
 
+	"github.com/ava-labs/gecko/database"
 	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/snow/choices"
 	"github.com/ava-labs/gecko/utils"
@@ -19,9 +21,38 @@ import (
 	"github.com/ava-labs/gecko/utils/json"
 	"github.com/ava-labs/gecko/utils/math"
 	"github.com/ava-labs/gecko/vms/components/verify"
+	"github.com/ava-labs/gecko/vms/managedfx"
+	"github.com/ava-labs/gecko/vms/nftfx"
 	"github.com/ava-labs/gecko/vms/secp256k1fx"
 )
 
+const (
+	// maxOutputsPerTx is the number of outputs put into a transaction built by
+	// this service, which keeps the transaction well under the codec's size
+	// limit
+	maxOutputsPerTx = 1024
+
+	// defaultUTXOsLimit is the number of utxos returned by GetUTXOs and
+	// GetUTXOsByAssetID if no limit is provided
+	defaultUTXOsLimit = 1024
+
+	// maxUTXOsLimit is the maximum number of utxos returned by GetUTXOs and
+	// GetUTXOsByAssetID
+	maxUTXOsLimit = 4096
+
+	// defaultAddressTxsLimit is the number of tx IDs returned by
+	// GetAddressTxs if no limit is provided
+	defaultAddressTxsLimit = 1024
+
+	// maxAddressTxsLimit is the maximum number of tx IDs returned by
+	// GetAddressTxs
+	maxAddressTxsLimit = 4096
+
+	// maxAssetStatsPeriods is the maximum number of periods GetAssetStats
+	// returns the stats of
+	maxAssetStatsPeriods = 366
+)
+
 var (
 	errUnknownAssetID            = errors.New("unknown asset ID")
 	errTxNotCreateAsset          = errors.New("transaction doesn't create an asset")
@@ -33,10 +64,29 @@ var (
 	errAddressesCantMintAsset    = errors.New("provided addresses don't have the authority to mint the provided asset")
 	errCanOnlySignSingleInputTxs = errors.New("can only sign transactions with one input")
 	errUnknownUTXO               = errors.New("unknown utxo")
-	errInvalidUTXO               = errors.New("invalid utxo")
+	errInvalidUTXO               = verify.NewError(CodeInvalidUTXO, "invalid utxo")
 	errUnknownOutputType         = errors.New("unknown output type")
 	errUnneededAddress           = errors.New("address not required to sign")
 	errUnknownCredentialType     = errors.New("unknown credential type")
+	errTooManyMinterSets         = fmt.Errorf("at most %d minter sets may be provided", maxOutputsPerTx-1)
+	errNoDistributionAddress     = errors.New("user must have an address to distribute the asset from")
+	errAddressNotOwned           = errors.New("user doesn't control the provided address")
+	errMissingSignatures         = errors.New("transaction is missing signatures")
+	errUTXOsLimitTooLarge        = fmt.Errorf("limit must be at most %d", maxUTXOsLimit)
+	errAddressTxsLimitTooLarge   = fmt.Errorf("limit must be at most %d", maxAddressTxsLimit)
+	errAddressTxIndexDisabled    = errors.New("the address tx index isn't enabled on this node")
+	errAssetStatsDisabled        = errors.New("the asset stats aren't enabled on this node")
+	errAssetStatsRange           = errors.New("endTime must not be before startTime")
+	errAssetStatsRangeTooLarge   = fmt.Errorf("the range must span at most %d days", maxAssetStatsPeriods)
+	errUTXOSetHashIncomplete     = errors.New("this node's database predates the utxo set commitment, so the commitment is incomplete")
+	errVestingLocktime           = errors.New("a holder with a vesting schedule can't also have a locktime")
+	errVestingAmount             = errors.New("vesting period amounts must be positive")
+	errVestingUnsorted           = errors.New("vesting periods must be sorted by locktime, with no duplicates")
+	errVestingSum                = errors.New("vesting period amounts must sum to the holder's amount")
+	errNFTFxNotEnabled           = errors.New("the nft feature extension isn't enabled on this chain")
+	errManagedFxNotEnabled       = errors.New("the managed asset feature extension isn't enabled on this chain")
+	errPayloadTooLarge           = fmt.Errorf("payload must be at most %d bytes", nftfx.MaxPayloadSize)
+	errAddressesCantSendNFT      = errors.New("provided addresses don't own an NFT of the provided asset and group")
 )
 
 // Service defines the base service for the asset vm
@@ -73,6 +123,17 @@ type GetTxStatusArgs struct {
 // GetTxStatusReply defines the GetTxStatus replies returned from the API
 type GetTxStatusReply struct {
 	Status choices.Status `json:"status"`
+
+	// If the transaction is processing but no longer passes verification, the
+	// code of the verification error and its description. The transaction
+	// won't be accepted.
+	ErrorCode verify.ErrorCode `json:"errorCode,omitempty"`
+	Reason    string           `json:"reason,omitempty"`
+
+	// If the transaction was rejected, the ID of the transaction that caused
+	// the rejection. This is either an accepted transaction that conflicted
+	// with it, or a rejected transaction that it depended on.
+	RejectedBy ids.ID `json:"rejectedBy"`
 }
 
 // GetTxStatus returns the status of the specified transaction
@@ -89,33 +150,128 @@ func (service *Service) GetTxStatus(r *http.Request, args *GetTxStatusArgs, repl
 	}
 
 	reply.Status = tx.Status()
+	switch reply.Status {
+	case choices.Processing:
+		// Report the result of the engine's last verification. Verifying the
+		// transaction here would record and publish the result on the
+		// engine's behalf.
+		if verified, err := tx.verified(); verified && err != nil {
+			reply.ErrorCode = verify.Code(err)
+			reply.Reason = err.Error()
+		}
+	case choices.Rejected:
+		if causeID, err := service.vm.state.RejectionCause(args.TxID); err == nil {
+			reply.RejectedBy = causeID
+		}
+	}
+	return nil
+}
+
+// GetTxArgs are arguments for passing into GetTx requests
+type GetTxArgs struct {
+	TxID ids.ID `json:"txID"`
+}
+
+// GetTxReply defines the GetTx replies returned from the API
+type GetTxReply struct {
+	Tx   formatting.CB58 `json:"tx"`
+	Memo formatting.CB58 `json:"memo"`
+}
+
+// GetTx returns the specified transaction and its memo
+func (service *Service) GetTx(r *http.Request, args *GetTxArgs, reply *GetTxReply) error {
+	service.vm.ctx.Log.Verbo("GetTx called with %s", args.TxID)
+
+	if args.TxID.IsZero() {
+		return errNilTxID
+	}
+
+	tx := UniqueTx{
+		vm:   service.vm,
+		txID: args.TxID,
+	}
+	if status := tx.Status(); status == choices.Unknown || tx.t.tx == nil {
+		return errUnknownTx
+	}
+
+	reply.Tx.Bytes = tx.Bytes()
+	reply.Memo.Bytes = tx.t.tx.MemoBytes()
 	return nil
 }
 
+// Index is a cursor into the utxos of a set of addresses. It identifies a utxo
+// by the address it was listed under and its ID.
+type Index struct {
+	Address string `json:"address"`
+	UTXO    string `json:"utxo"`
+}
+
 // GetUTXOsArgs are arguments for passing into GetUTXOs requests
 type GetUTXOsArgs struct {
 	Addresses []string `json:"addresses"`
+	// If the address is non-empty, only utxos after this index are returned.
+	// Used to get the page that follows a previous reply's EndIndex.
+	StartIndex Index `json:"startIndex"`
+	// Maximum number of utxos to return. Defaults to 1024.
+	Limit json.Uint32 `json:"limit"`
 }
 
 // GetUTXOsReply defines the GetUTXOs replies returned from the API
 type GetUTXOsReply struct {
 	UTXOs []formatting.CB58 `json:"utxos"`
+	// The index of the last utxo visited. Pass it as the StartIndex of the
+	// next request to get the next page. A page with fewer than Limit utxos
+	// is the last page.
+	EndIndex Index `json:"endIndex"`
 }
 
-// GetUTXOs creates an empty account with the name passed in
+// GetUTXOs returns the utxos that reference any of the provided addresses,
+// ordered by address and then by utxo ID
 func (service *Service) GetUTXOs(r *http.Request, args *GetUTXOsArgs, reply *GetUTXOsReply) error {
 	service.vm.ctx.Log.Verbo("GetUTXOs called with %s", args.Addresses)
 
-	addrSet := ids.Set{}
+	limit := int(args.Limit)
+	switch {
+	case limit == 0:
+		limit = defaultUTXOsLimit
+	case limit > maxUTXOsLimit:
+		return errUTXOsLimitTooLarge
+	}
+
+	// Maps the ID of an address back to the address it was parsed from
+	addrNames := map[[32]byte]string{}
+	addrs := []ids.ID{}
 	for _, addr := range args.Addresses {
 		addrBytes, err := service.vm.Parse(addr)
 		if err != nil {
 			return err
 		}
-		addrSet.Add(ids.NewID(hashing.ComputeHash256Array(addrBytes)))
+		addrID := ids.NewID(hashing.ComputeHash256Array(addrBytes))
+		if _, ok := addrNames[addrID.Key()]; !ok {
+			addrNames[addrID.Key()] = addr
+			addrs = append(addrs, addrID)
+		}
 	}
 
-	utxos, err := service.vm.GetUTXOs(addrSet)
+	startAddr := ids.ID{}
+	startUTXOID := ids.ID{}
+	if args.StartIndex.Address != "" {
+		addrBytes, err := service.vm.Parse(args.StartIndex.Address)
+		if err != nil {
+			return fmt.Errorf("problem parsing startIndex address: %w", err)
+		}
+		startAddr = ids.NewID(hashing.ComputeHash256Array(addrBytes))
+		addrNames[startAddr.Key()] = args.StartIndex.Address
+
+		if args.StartIndex.UTXO != "" {
+			startUTXOID, err = ids.FromString(args.StartIndex.UTXO)
+			if err != nil {
+				return fmt.Errorf("problem parsing startIndex utxo: %w", err)
+			}
+		}
+	}
+
+	utxos, endAddr, endUTXOID, err := service.vm.GetPaginatedUTXOs(addrs, startAddr, startUTXOID, limit)
 	if err != nil {
 		return err
 	}
@@ -128,6 +284,312 @@ func (service *Service) GetUTXOs(r *http.Request, args *GetUTXOsArgs, reply *Get
 		}
 		reply.UTXOs = append(reply.UTXOs, formatting.CB58{Bytes: b})
 	}
+	if !endAddr.IsZero() {
+		reply.EndIndex.Address = addrNames[endAddr.Key()]
+	}
+	if !endUTXOID.IsZero() {
+		reply.EndIndex.UTXO = endUTXOID.String()
+	}
+	return nil
+}
+
+// GetAddressTxsArgs are arguments for passing into GetAddressTxs requests
+type GetAddressTxsArgs struct {
+	Address string `json:"address"`
+	// Index of the first tx to return. Used to get the page that starts at a
+	// previous reply's NextIndex.
+	StartIndex json.Uint64 `json:"startIndex"`
+	// Maximum number of txs to return. Defaults to 1024.
+	Limit json.Uint32 `json:"limit"`
+}
+
+// GetAddressTxsReply defines the GetAddressTxs replies returned from the API
+type GetAddressTxsReply struct {
+	TxIDs []ids.ID `json:"txIDs"`
+	// Index of the first tx of the next page. A page with fewer than Limit
+	// txs is the last page.
+	NextIndex json.Uint64 `json:"nextIndex"`
+	// False if txs were accepted while the index was disabled, in which case
+	// the history may be missing txs
+	Complete bool `json:"complete"`
+}
+
+// GetAddressTxs returns the IDs of the accepted txs that spent or produced a
+// utxo owned by the address, in the order they were accepted
+func (service *Service) GetAddressTxs(r *http.Request, args *GetAddressTxsArgs, reply *GetAddressTxsReply) error {
+	service.vm.ctx.Log.Verbo("GetAddressTxs called with address: %s startIndex: %d", args.Address, args.StartIndex)
+
+	if !service.vm.indexTxs {
+		return errAddressTxIndexDisabled
+	}
+
+	limit := uint64(args.Limit)
+	switch {
+	case limit == 0:
+		limit = defaultAddressTxsLimit
+	case limit > maxAddressTxsLimit:
+		return errAddressTxsLimitTooLarge
+	}
+
+	addrBytes, err := service.vm.Parse(args.Address)
+	if err != nil {
+		return fmt.Errorf("problem parsing address: %w", err)
+	}
+	addr := ids.NewID(hashing.ComputeHash256Array(addrBytes))
+
+	count, err := service.vm.state.AddressTxCount(addr)
+	if err == database.ErrNotFound {
+		count = 0
+	} else if err != nil {
+		return fmt.Errorf("problem retrieving the number of txs: %w", err)
+	}
+
+	reply.TxIDs = []ids.ID{}
+	index := uint64(args.StartIndex)
+	for ; index < count && uint64(len(reply.TxIDs)) < limit; index++ {
+		txID, err := service.vm.state.AddressTx(addr, index)
+		if err != nil {
+			return fmt.Errorf("problem retrieving tx %d: %w", index, err)
+		}
+		reply.TxIDs = append(reply.TxIDs, txID)
+	}
+	reply.NextIndex = json.Uint64(index)
+
+	status, _ := service.vm.state.AddressTxIndexInitialized()
+	reply.Complete = status == choices.Accepted
+	return nil
+}
+
+// GetAssetStatsArgs are arguments for passing into GetAssetStats requests
+type GetAssetStatsArgs struct {
+	AssetID string `json:"assetID"`
+	// Unix times, in seconds, of the start and end of the range. The stats of
+	// every day, in UTC, that overlaps the range are returned.
+	StartTime json.Uint64 `json:"startTime"`
+	EndTime   json.Uint64 `json:"endTime"`
+}
+
+// AssetStats are the counters of an asset's activity during a day
+type AssetStats struct {
+	// Unix time, in seconds, that the day starts at
+	StartTime json.Uint64 `json:"startTime"`
+	// Number of accepted txs that touched the asset
+	TxCount json.Uint64 `json:"txCount"`
+	// Amount of the asset output by the txs, including change
+	Transferred json.Uint64 `json:"transferred"`
+	// Number of distinct addresses that owned a utxo of the asset that the
+	// txs spent or produced
+	Addresses json.Uint64 `json:"addresses"`
+}
+
+// GetAssetStatsReply defines the GetAssetStats replies returned from the API
+type GetAssetStatsReply struct {
+	// The stats of each day in the range, in order
+	Days []AssetStats `json:"days"`
+	// Sums of the days' TxCount and Transferred. Addresses aren't summed, as
+	// an address active on several days would be counted more than once.
+	TxCount     json.Uint64 `json:"txCount"`
+	Transferred json.Uint64 `json:"transferred"`
+	// False if txs were accepted while the stats were disabled, in which case
+	// the stats may be missing txs
+	Complete bool `json:"complete"`
+}
+
+// GetAssetStats returns the daily activity of the asset during the range
+func (service *Service) GetAssetStats(r *http.Request, args *GetAssetStatsArgs, reply *GetAssetStatsReply) error {
+	service.vm.ctx.Log.Verbo("GetAssetStats called with assetID: %s startTime: %d endTime: %d", args.AssetID, args.StartTime, args.EndTime)
+
+	if !service.vm.indexAssetStats {
+		return errAssetStatsDisabled
+	}
+
+	assetID, err := service.vm.Lookup(args.AssetID)
+	if err != nil {
+		assetID, err = ids.FromString(args.AssetID)
+		if err != nil {
+			return fmt.Errorf("asset '%s' not found", args.AssetID)
+		}
+	}
+
+	if args.EndTime < args.StartTime {
+		return errAssetStatsRange
+	}
+	startPeriod := assetStatsPeriodAt(uint64(args.StartTime))
+	endPeriod := assetStatsPeriodAt(uint64(args.EndTime))
+	if endPeriod-startPeriod >= maxAssetStatsPeriods {
+		return errAssetStatsRangeTooLarge
+	}
+
+	periodLength := uint64(assetStatsPeriod / time.Second)
+	reply.Days = []AssetStats{}
+	txCount, transferred := uint64(0), uint64(0)
+	for period := startPeriod; period <= endPeriod; period++ {
+		stats, err := service.vm.state.AssetStats(assetID, period)
+		if err != nil {
+			return fmt.Errorf("problem retrieving the stats of day %d: %w", period, err)
+		}
+		reply.Days = append(reply.Days, AssetStats{
+			StartTime:   json.Uint64(period * periodLength),
+			TxCount:     json.Uint64(stats.TxCount),
+			Transferred: json.Uint64(stats.Transferred),
+			Addresses:   json.Uint64(stats.Addresses),
+		})
+		txCount += stats.TxCount
+		transferred = addSaturating(transferred, stats.Transferred)
+	}
+	reply.TxCount = json.Uint64(txCount)
+	reply.Transferred = json.Uint64(transferred)
+
+	status, _ := service.vm.state.AssetStatsInitialized()
+	reply.Complete = status == choices.Accepted
+	return nil
+}
+
+// GetPendingTxsArgs are arguments for passing into GetPendingTxs requests
+type GetPendingTxsArgs struct {
+	// If non-empty, only the txs that spend or produce a utxo owned by this
+	// address are returned
+	Address string `json:"address"`
+}
+
+// GetPendingTxsReply defines the GetPendingTxs replies returned from the API
+type GetPendingTxsReply struct {
+	TxIDs []ids.ID `json:"txIDs"`
+}
+
+// GetPendingTxs returns the IDs of the txs issued to this node that are
+// waiting in its mempool to be taken by consensus, oldest first
+func (service *Service) GetPendingTxs(r *http.Request, args *GetPendingTxsArgs, reply *GetPendingTxsReply) error {
+	service.vm.ctx.Log.Verbo("GetPendingTxs called with address: %s", args.Address)
+
+	var addr *ids.ID
+	if args.Address != "" {
+		addrBytes, err := service.vm.Parse(args.Address)
+		if err != nil {
+			return fmt.Errorf("problem parsing address: %w", err)
+		}
+		addrID := ids.NewID(hashing.ComputeHash256Array(addrBytes))
+		addr = &addrID
+	}
+
+	reply.TxIDs = service.vm.mempool.PendingTxIDs(addr)
+	return nil
+}
+
+// GetUTXOSetCommitmentArgs are arguments for passing into GetUTXOSetCommitment
+// requests
+type GetUTXOSetCommitmentArgs struct{}
+
+// GetUTXOSetCommitmentReply defines the GetUTXOSetCommitment replies returned
+// from the API
+type GetUTXOSetCommitmentReply struct {
+	Commitment ids.ID `json:"commitment"`
+}
+
+// GetUTXOSetCommitment returns a commitment to the set of unspent utxos, as of
+// the last accepted tx. Nodes that have accepted the same txs return the same
+// commitment, no matter the order they accepted them in.
+func (service *Service) GetUTXOSetCommitment(_ *http.Request, _ *GetUTXOSetCommitmentArgs, reply *GetUTXOSetCommitmentReply) error {
+	service.vm.ctx.Log.Verbo("GetUTXOSetCommitment called")
+
+	if status, err := service.vm.state.UTXOSetHashInitialized(); err != nil || status != choices.Accepted {
+		return errUTXOSetHashIncomplete
+	}
+	hash, err := service.vm.state.UTXOSetHash()
+	if err != nil {
+		return fmt.Errorf("problem getting the utxo set hash: %w", err)
+	}
+	reply.Commitment = hash.Commitment()
+	return nil
+}
+
+// GetUTXOsByAssetIDArgs are arguments for passing into GetUTXOsByAssetID
+// requests
+type GetUTXOsByAssetIDArgs struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+	AssetID  string `json:"assetID"`
+	// If non-empty, only utxos whose IDs are at least StartUTXOID are
+	// returned. Used to get the page that starts at a previous reply's
+	// NextUTXOID.
+	StartUTXOID string `json:"startUTXOID"`
+	// Maximum number of utxos to return. Defaults to 1024.
+	Limit json.Uint32 `json:"limit"`
+}
+
+// GetUTXOsByAssetIDReply defines the GetUTXOsByAssetID replies returned from
+// the API
+type GetUTXOsByAssetIDReply struct {
+	UTXOs []formatting.CB58 `json:"utxos"`
+	// The ID of the first utxo of the next page, or empty if this is the last
+	// page
+	NextUTXOID string `json:"nextUTXOID"`
+}
+
+// GetUTXOsByAssetID returns the utxos of an asset that reference any of the
+// addresses controlled by a user, in order of their IDs
+func (service *Service) GetUTXOsByAssetID(_ *http.Request, args *GetUTXOsByAssetIDArgs, reply *GetUTXOsByAssetIDReply) error {
+	service.vm.ctx.Log.Verbo("GetUTXOsByAssetID called with username: %s assetID: %s", args.Username, args.AssetID)
+
+	assetID, err := service.vm.Lookup(args.AssetID)
+	if err != nil {
+		assetID, err = ids.FromString(args.AssetID)
+		if err != nil {
+			return fmt.Errorf("asset '%s' not found", args.AssetID)
+		}
+	}
+
+	var start []byte
+	if args.StartUTXOID != "" {
+		startID, err := ids.FromString(args.StartUTXOID)
+		if err != nil {
+			return fmt.Errorf("problem parsing startUTXOID: %w", err)
+		}
+		start = startID.Bytes()
+	}
+
+	limit := int(args.Limit)
+	switch {
+	case limit == 0:
+		limit = defaultUTXOsLimit
+	case limit > maxUTXOsLimit:
+		return errUTXOsLimitTooLarge
+	}
+
+	db, err := service.vm.ctx.Keystore.GetDatabase(args.Username, args.Password)
+	if err != nil {
+		return fmt.Errorf("problem retrieving user: %w", err)
+	}
+	user := userState{vm: service.vm}
+	addresses, _ := user.Addresses(db)
+
+	addrs := ids.Set{}
+	addrs.Add(addresses...)
+	utxos, err := service.vm.GetUTXOsByAsset(addrs, assetID)
+	if err != nil {
+		return fmt.Errorf("problem retrieving UTXOs: %w", err)
+	}
+	sort.Slice(utxos, func(i, j int) bool {
+		return bytes.Compare(utxos[i].InputID().Bytes(), utxos[j].InputID().Bytes()) < 0
+	})
+
+	reply.UTXOs = []formatting.CB58{}
+	for _, utxo := range utxos {
+		utxoID := utxo.InputID()
+		if start != nil && bytes.Compare(utxoID.Bytes(), start) < 0 {
+			continue
+		}
+		if len(reply.UTXOs) == limit {
+			reply.NextUTXOID = utxoID.String()
+			break
+		}
+
+		b, err := service.vm.codec.Marshal(utxo)
+		if err != nil {
+			return err
+		}
+		reply.UTXOs = append(reply.UTXOs, formatting.CB58{Bytes: b})
+	}
 	return nil
 }
 
@@ -163,7 +625,7 @@ func (service *Service) GetAssetDescription(_ *http.Request, args *GetAssetDescr
 	if status := tx.Status(); !status.Fetched() {
 		return errUnknownAssetID
 	}
-	createAssetTx, ok := tx.t.tx.UnsignedTx.(*CreateAssetTx)
+	createAssetTx, ok := innerTx(tx.t.tx.UnsignedTx).(*CreateAssetTx)
 	if !ok {
 		return errTxNotCreateAsset
 	}
@@ -236,20 +698,70 @@ type CreateFixedCapAssetArgs struct {
 	Symbol         string    `json:"symbol"`
 	Denomination   byte      `json:"denomination"`
 	InitialHolders []*Holder `json:"initialHolders"`
+	// Manager, if given, can freeze transfers of the asset and claw it back.
+	// An asset can only be managed if it's created with a manager.
+	Manager *Owners `json:"manager"`
 }
 
-// Holder describes how much an address owns of an asset
+// Holder describes how much an address owns of an asset. The amount can't be
+// spent until [Locktime]. If [Vesting] is provided, the amount instead unlocks
+// in parts at each of the vesting periods' locktimes, and the periods' amounts
+// must sum to the amount.
 type Holder struct {
-	Amount  json.Uint64 `json:"amount"`
-	Address string      `json:"address"`
+	Amount   json.Uint64     `json:"amount"`
+	Address  string          `json:"address"`
+	Locktime json.Uint64     `json:"locktime"`
+	Vesting  []VestingPeriod `json:"vesting"`
+}
+
+// VestingPeriod is part of a holder's amount that unlocks at [Locktime]
+type VestingPeriod struct {
+	Amount   json.Uint64 `json:"amount"`
+	Locktime json.Uint64 `json:"locktime"`
+}
+
+// schedule returns the amounts the holder is given and when each unlocks. Each
+// period is given to the holder as a separate output.
+func (h *Holder) schedule() ([]VestingPeriod, error) {
+	if len(h.Vesting) == 0 {
+		return []VestingPeriod{{Amount: h.Amount, Locktime: h.Locktime}}, nil
+	}
+	if h.Locktime != 0 {
+		return nil, errVestingLocktime
+	}
+
+	total := uint64(0)
+	for i, period := range h.Vesting {
+		if period.Amount == 0 {
+			return nil, errVestingAmount
+		}
+		if i > 0 && period.Locktime <= h.Vesting[i-1].Locktime {
+			return nil, errVestingUnsorted
+		}
+		var err error
+		total, err = math.Add64(total, uint64(period.Amount))
+		if err != nil {
+			return nil, errVestingSum
+		}
+	}
+	if total != uint64(h.Amount) {
+		return nil, fmt.Errorf("%w: the periods unlock %d but the amount is %d", errVestingSum, total, h.Amount)
+	}
+	return h.Vesting, nil
 }
 
 // CreateFixedCapAssetReply defines the CreateFixedCapAsset replies returned from the API
 type CreateFixedCapAssetReply struct {
 	AssetID ids.ID `json:"assetID"`
+	// TxIDs are the transactions that created and distributed the asset. If
+	// the holders fit in the asset's creation, this is only the asset's ID.
+	TxIDs []ids.ID `json:"txIDs"`
 }
 
-// CreateFixedCapAsset returns ID of the newly created asset
+// CreateFixedCapAsset returns ID of the newly created asset. If there are too
+// many initial holders to fit in a single transaction, the user's first address
+// receives the rest of the supply, which is then sent to the remaining holders
+// in as many transactions as needed.
 func (service *Service) CreateFixedCapAsset(r *http.Request, args *CreateFixedCapAssetArgs, reply *CreateFixedCapAssetReply) error {
 	service.vm.ctx.Log.Verbo("CreateFixedCapAsset called with name: %s symbol: %s number of holders: %d",
 		args.Name,
@@ -261,66 +773,38 @@ func (service *Service) CreateFixedCapAsset(r *http.Request, args *CreateFixedCa
 		return errNoHolders
 	}
 
-	initialState := &InitialState{
-		FxID: 0, // TODO: Should lookup secp256k1fx FxID
-		Outs: []verify.Verifiable{},
-	}
-
-	tx := &Tx{UnsignedTx: &CreateAssetTx{
-		BaseTx: BaseTx{
-			NetID: service.vm.ctx.NetworkID,
-			BCID:  service.vm.ctx.ChainID,
-		},
-		Name:         args.Name,
-		Symbol:       args.Symbol,
-		Denomination: args.Denomination,
-		States: []*InitialState{
-			initialState,
-		},
-	}}
-
-	for _, holder := range args.InitialHolders {
-		address, err := service.vm.Parse(holder.Address)
-		if err != nil {
-			return err
-		}
-		addr, err := ids.ToShortID(address)
-		if err != nil {
-			return err
-		}
-		initialState.Outs = append(initialState.Outs, &secp256k1fx.TransferOutput{
-			Amt: uint64(holder.Amount),
-			OutputOwners: secp256k1fx.OutputOwners{
-				Threshold: 1,
-				Addrs:     []ids.ShortID{addr},
-			},
-		})
-	}
-	initialState.Sort(service.vm.codec)
-
-	b, err := service.vm.codec.Marshal(tx)
-	if err != nil {
-		return fmt.Errorf("problem creating transaction: %w", err)
-	}
-
-	assetID, err := service.vm.IssueTx(b, nil)
+	assetID, txIDs, err := service.createAsset(
+		args.Username,
+		args.Password,
+		args.Name,
+		args.Symbol,
+		args.Denomination,
+		args.InitialHolders,
+		nil,
+		args.Manager,
+	)
 	if err != nil {
-		return fmt.Errorf("problem issuing transaction: %w", err)
+		return err
 	}
 
 	reply.AssetID = assetID
+	reply.TxIDs = txIDs
 
 	return nil
 }
 
 // CreateVariableCapAssetArgs are arguments for passing into CreateVariableCapAsset requests
 type CreateVariableCapAssetArgs struct {
-	Username     string   `json:"username"`
-	Password     string   `json:"password"`
-	Name         string   `json:"name"`
-	Symbol       string   `json:"symbol"`
-	Denomination byte     `json:"denomination"`
-	MinterSets   []Owners `json:"minterSets"`
+	Username       string    `json:"username"`
+	Password       string    `json:"password"`
+	Name           string    `json:"name"`
+	Symbol         string    `json:"symbol"`
+	Denomination   byte      `json:"denomination"`
+	MinterSets     []Owners  `json:"minterSets"`
+	InitialHolders []*Holder `json:"initialHolders"`
+	// Manager, if given, can freeze transfers of the asset and claw it back.
+	// An asset can only be managed if it's created with a manager.
+	Manager *Owners `json:"manager"`
 }
 
 // Owners describes who can perform an action
@@ -332,9 +816,14 @@ type Owners struct {
 // CreateVariableCapAssetReply defines the CreateVariableCapAsset replies returned from the API
 type CreateVariableCapAssetReply struct {
 	AssetID ids.ID `json:"assetID"`
+	// TxIDs are the transactions that created and distributed the asset. If
+	// the holders fit in the asset's creation, this is only the asset's ID.
+	TxIDs []ids.ID `json:"txIDs"`
 }
 
-// CreateVariableCapAsset returns ID of the newly created asset
+// CreateVariableCapAsset returns ID of the newly created asset. The initial
+// holders are optional and are distributed the same way as in
+// CreateFixedCapAsset.
 func (service *Service) CreateVariableCapAsset(r *http.Request, args *CreateVariableCapAssetArgs, reply *CreateVariableCapAssetReply) error {
 	service.vm.ctx.Log.Verbo("CreateFixedCapAsset called with name: %s symbol: %s number of minters: %d",
 		args.Name,
@@ -346,24 +835,7 @@ func (service *Service) CreateVariableCapAsset(r *http.Request, args *CreateVari
 		return errNoMinters
 	}
 
-	initialState := &InitialState{
-		FxID: 0, // TODO: Should lookup secp256k1fx FxID
-		Outs: []verify.Verifiable{},
-	}
-
-	tx := &Tx{UnsignedTx: &CreateAssetTx{
-		BaseTx: BaseTx{
-			NetID: service.vm.ctx.NetworkID,
-			BCID:  service.vm.ctx.ChainID,
-		},
-		Name:         args.Name,
-		Symbol:       args.Symbol,
-		Denomination: args.Denomination,
-		States: []*InitialState{
-			initialState,
-		},
-	}}
-
+	minters := []verify.Verifiable{}
 	for _, owner := range args.MinterSets {
 		minter := &secp256k1fx.MintOutput{
 			OutputOwners: secp256k1fx.OutputOwners{
@@ -382,25 +854,308 @@ func (service *Service) CreateVariableCapAsset(r *http.Request, args *CreateVari
 			minter.Addrs = append(minter.Addrs, addr)
 		}
 		ids.SortShortIDs(minter.Addrs)
-		initialState.Outs = append(initialState.Outs, minter)
+		minters = append(minters, minter)
 	}
-	initialState.Sort(service.vm.codec)
 
-	b, err := service.vm.codec.Marshal(tx)
+	assetID, txIDs, err := service.createAsset(
+		args.Username,
+		args.Password,
+		args.Name,
+		args.Symbol,
+		args.Denomination,
+		args.InitialHolders,
+		minters,
+		args.Manager,
+	)
 	if err != nil {
-		return fmt.Errorf("problem creating transaction: %w", err)
-	}
-
-	assetID, err := service.vm.IssueTx(b, nil)
-	if err != nil {
-		return fmt.Errorf("problem issuing transaction: %w", err)
+		return err
 	}
 
 	reply.AssetID = assetID
+	reply.TxIDs = txIDs
 
 	return nil
 }
 
+// createAsset issues the transaction that creates an asset with the [minters],
+// the outputs of the [holders] and, if given, the [manager]. If there are more than maxOutputsPerTx
+// outputs, the rest of the [holders] are sent their outputs by a chain of
+// transactions that spend the remaining supply from the user's first address.
+// Every transaction is built before any is issued, and the user pays each
+// one's fee.
+func (service *Service) createAsset(
+	username, password, name, symbol string,
+	denomination byte,
+	holders []*Holder,
+	minters []verify.Verifiable,
+	manager *Owners,
+) (ids.ID, []ids.ID, error) {
+	outs := []*secp256k1fx.TransferOutput{}
+	for _, holder := range holders {
+		address, err := service.vm.Parse(holder.Address)
+		if err != nil {
+			return ids.ID{}, nil, err
+		}
+		addr, err := ids.ToShortID(address)
+		if err != nil {
+			return ids.ID{}, nil, err
+		}
+		periods, err := holder.schedule()
+		if err != nil {
+			return ids.ID{}, nil, fmt.Errorf("invalid holder %s: %w", holder.Address, err)
+		}
+		for _, period := range periods {
+			outs = append(outs, &secp256k1fx.TransferOutput{
+				Amt:      uint64(period.Amount),
+				Locktime: uint64(period.Locktime),
+				OutputOwners: secp256k1fx.OutputOwners{
+					Threshold: 1,
+					Addrs:     []ids.ShortID{addr},
+				},
+			})
+		}
+	}
+
+	initialState := &InitialState{
+		FxID: 0, // TODO: Should lookup secp256k1fx FxID
+		Outs: append([]verify.Verifiable{}, minters...),
+	}
+	states := []*InitialState{initialState}
+	if manager != nil {
+		managerState, err := service.managerState(manager)
+		if err != nil {
+			return ids.ID{}, nil, err
+		}
+		states = append(states, managerState)
+		sortInitialStates(states)
+	}
+
+	createTx := &CreateAssetTx{
+		BaseTx: BaseTx{
+			NetID: service.vm.ctx.NetworkID,
+			BCID:  service.vm.ctx.ChainID,
+		},
+		Name:         name,
+		Symbol:       symbol,
+		Denomination: denomination,
+		States:       states,
+	}
+	tx := &Tx{UnsignedTx: createTx}
+
+	numInitialOuts := maxOutputsPerTx - len(minters)
+	if len(outs) <= numInitialOuts {
+		for _, out := range outs {
+			initialState.Outs = append(initialState.Outs, out)
+		}
+		initialState.Sort(service.vm.codec)
+
+		fees, err := service.userFeeChain(username, password, nil, 1)
+		if err != nil {
+			return ids.ID{}, nil, err
+		}
+		b, err := service.signTx(tx, payFee(&createTx.BaseTx, fees))
+		if err != nil {
+			return ids.ID{}, nil, fmt.Errorf("problem creating transaction: %w", err)
+		}
+
+		assetID, err := service.vm.IssueTx(b, nil)
+		if err != nil {
+			return ids.ID{}, nil, fmt.Errorf("problem issuing transaction: %w", err)
+		}
+		return assetID, []ids.ID{assetID}, nil
+	}
+
+	// Leave room in the creation for the output holding the remaining supply
+	numInitialOuts--
+	if numInitialOuts < 0 {
+		return ids.ID{}, nil, errTooManyMinterSets
+	}
+
+	db, err := service.vm.ctx.Keystore.GetDatabase(username, password)
+	if err != nil {
+		return ids.ID{}, nil, fmt.Errorf("problem retrieving user: %w", err)
+	}
+	user := userState{vm: service.vm}
+	addresses, _ := user.Addresses(db)
+	if len(addresses) == 0 {
+		return ids.ID{}, nil, errNoDistributionAddress
+	}
+	key, err := user.Key(db, addresses[0])
+	if err != nil {
+		return ids.ID{}, nil, fmt.Errorf("problem retrieving private key: %w", err)
+	}
+
+	for _, out := range outs[:numInitialOuts] {
+		initialState.Outs = append(initialState.Outs, out)
+	}
+	remaining := outs[numInitialOuts:]
+	supply, err := totalAmount(remaining)
+	if err != nil {
+		return ids.ID{}, nil, err
+	}
+	distributionOut := &secp256k1fx.TransferOutput{
+		Amt: supply,
+		OutputOwners: secp256k1fx.OutputOwners{
+			Threshold: 1,
+			Addrs:     []ids.ShortID{key.PublicKey().Address()},
+		},
+	}
+	initialState.Outs = append(initialState.Outs, distributionOut)
+	initialState.Sort(service.vm.codec)
+
+	numOutsPerTx := maxOutputsPerTx - 1
+	numTxs := 1 + (len(remaining)+numOutsPerTx-1)/numOutsPerTx
+	fees, err := service.userFeeChain(username, password, key, numTxs)
+	if err != nil {
+		return ids.ID{}, nil, err
+	}
+	b, err := service.signTx(tx, payFee(&createTx.BaseTx, fees))
+	if err != nil {
+		return ids.ID{}, nil, fmt.Errorf("problem creating transaction: %w", err)
+	}
+	fees.built(b, createTx.Outs)
+	assetID := ids.NewID(hashing.ComputeHash256Array(b))
+
+	// The initial state's outputs are indexed after the creation's outputs
+	utxoID := UTXOID{TxID: assetID}
+	for i, out := range initialState.Outs {
+		if out == distributionOut {
+			utxoID.OutputIndex = uint32(len(createTx.Outs) + i)
+		}
+	}
+
+	txs := [][]byte{b}
+	for len(remaining) > 0 {
+		numOuts := numOutsPerTx
+		if numOuts > len(remaining) {
+			numOuts = len(remaining)
+		}
+		b, changeUTXOID, err := service.distribute(assetID, utxoID, supply, remaining[:numOuts], key, fees)
+		if err != nil {
+			return ids.ID{}, nil, err
+		}
+		txs = append(txs, b)
+
+		sent, _ := totalAmount(remaining[:numOuts])
+		supply -= sent
+		remaining = remaining[numOuts:]
+		utxoID = changeUTXOID
+	}
+
+	txIDs := []ids.ID(nil)
+	for _, b := range txs {
+		txID, err := service.vm.IssueTx(b, nil)
+		if err != nil {
+			return ids.ID{}, nil, fmt.Errorf("problem issuing transaction: %w", err)
+		}
+		txIDs = append(txIDs, txID)
+	}
+	return assetID, txIDs, nil
+}
+
+// payFee adds the next inputs and outputs of [fees] to [base], which must have
+// no other inputs or outputs, and returns the keys that sign the added inputs
+func payFee(base *BaseTx, fees *feeChain) [][]*crypto.PrivateKeySECP256K1R {
+	ins, keys, outs := fees.next()
+	base.Ins = ins
+	base.Outs = outs
+	return keys
+}
+
+// distribute returns a signed transaction that spends the [supply] of
+// [assetID] held by [key] in [utxoID] to create [outs] and pays its fee with
+// [fees]. The change is sent back to [key] in the returned UTXO.
+func (service *Service) distribute(
+	assetID ids.ID,
+	utxoID UTXOID,
+	supply uint64,
+	outs []*secp256k1fx.TransferOutput,
+	key *crypto.PrivateKeySECP256K1R,
+	fees *feeChain,
+) ([]byte, UTXOID, error) {
+	transferableOuts := []*TransferableOutput{}
+	for _, out := range outs {
+		transferableOuts = append(transferableOuts, &TransferableOutput{
+			Asset: Asset{ID: assetID},
+			Out:   out,
+		})
+	}
+
+	sent, err := totalAmount(outs)
+	if err != nil {
+		return nil, UTXOID{}, err
+	}
+	changeOut := &TransferableOutput{
+		Asset: Asset{ID: assetID},
+		Out: &secp256k1fx.TransferOutput{
+			Amt: supply - sent,
+			OutputOwners: secp256k1fx.OutputOwners{
+				Threshold: 1,
+				Addrs:     []ids.ShortID{key.PublicKey().Address()},
+			},
+		},
+	}
+	if supply > sent {
+		transferableOuts = append(transferableOuts, changeOut)
+	}
+
+	feeIns, feeKeys, feeOuts := fees.next()
+	transferableOuts = append(transferableOuts, feeOuts...)
+	SortTransferableOutputs(transferableOuts, service.vm.codec)
+
+	ins := []*TransferableInput{
+		&TransferableInput{
+			UTXOID: utxoID,
+			Asset:  Asset{ID: assetID},
+			In: &secp256k1fx.TransferInput{
+				Amt: supply,
+				Input: secp256k1fx.Input{
+					SigIndices: []uint32{0},
+				},
+			},
+		},
+	}
+	keys := [][]*crypto.PrivateKeySECP256K1R{{key}}
+	ins = append(ins, feeIns...)
+	keys = append(keys, feeKeys...)
+	SortTransferableInputsWithSigners(ins, keys)
+
+	tx := &Tx{
+		UnsignedTx: &BaseTx{
+			NetID: service.vm.ctx.NetworkID,
+			BCID:  service.vm.ctx.ChainID,
+			Outs:  transferableOuts,
+			Ins:   ins,
+		},
+	}
+	b, err := service.signTx(tx, keys)
+	if err != nil {
+		return nil, UTXOID{}, fmt.Errorf("problem creating transaction: %w", err)
+	}
+	fees.built(b, transferableOuts)
+
+	changeUTXOID := UTXOID{TxID: ids.NewID(hashing.ComputeHash256Array(b))}
+	for i, out := range transferableOuts {
+		if out == changeOut {
+			changeUTXOID.OutputIndex = uint32(i)
+		}
+	}
+	return b, changeUTXOID, nil
+}
+
+// totalAmount returns the sum of the amounts of [outs]
+func totalAmount(outs []*secp256k1fx.TransferOutput) (uint64, error) {
+	total := uint64(0)
+	for _, out := range outs {
+		amount, err := math.Add64(total, out.Amt)
+		if err != nil {
+			return 0, errSpendOverflow
+		}
+		total = amount
+	}
+	return total, nil
+}
+
 // CreateAddressArgs are arguments for calling CreateAddress
 type CreateAddressArgs struct {
 	Username string `json:"username"`
@@ -485,9 +1240,12 @@ func (service *Service) ExportKey(r *http.Request, args *ExportKeyArgs, reply *E
 
 // ImportKeyArgs are arguments for ImportKey
 type ImportKeyArgs struct {
-	Username   string          `json:"username"`
-	Password   string          `json:"password"`
-	PrivateKey formatting.CB58 `json:"privateKey"`
+	Username string `json:"username"`
+	Password string `json:"password"`
+
+	// The secp256k1 private key to import. May be CB58, hex, Wallet Import
+	// Format or PEM encoded. The format is detected automatically.
+	PrivateKey string `json:"privateKey"`
 }
 
 // ImportKeyReply is the response for ImportKey
@@ -507,10 +1265,14 @@ func (service *Service) ImportKey(r *http.Request, args *ImportKeyArgs, reply *I
 
 	user := userState{vm: service.vm}
 
+	skBytes, err := parsePrivateKey(args.PrivateKey)
+	if err != nil {
+		return fmt.Errorf("problem parsing private key: %w", err)
+	}
 	factory := crypto.FactorySECP256K1R{}
-	skIntf, err := factory.ToPrivateKey(args.PrivateKey.Bytes)
+	skIntf, err := factory.ToPrivateKey(skBytes)
 	if err != nil {
-		return fmt.Errorf("problem parsing private key %s: %w", args.PrivateKey, err)
+		return fmt.Errorf("problem parsing private key: %w", err)
 	}
 	sk := skIntf.(*crypto.PrivateKeySECP256K1R)
 
@@ -531,11 +1293,12 @@ func (service *Service) ImportKey(r *http.Request, args *ImportKeyArgs, reply *I
 
 // SendArgs are arguments for passing into Send requests
 type SendArgs struct {
-	Username string      `json:"username"`
-	Password string      `json:"password"`
-	Amount   json.Uint64 `json:"amount"`
-	AssetID  string      `json:"assetID"`
-	To       string      `json:"to"`
+	Username string          `json:"username"`
+	Password string          `json:"password"`
+	Amount   json.Uint64     `json:"amount"`
+	AssetID  string          `json:"assetID"`
+	To       string          `json:"to"`
+	Memo     formatting.CB58 `json:"memo"`
 }
 
 // SendReply defines the Send replies returned from the API
@@ -543,13 +1306,17 @@ type SendReply struct {
 	TxID ids.ID `json:"txID"`
 }
 
-// Send returns the ID of the newly created transaction
+// Send returns the ID of the newly created transaction. The tx fee is paid
+// from the user's AVA.
 func (service *Service) Send(r *http.Request, args *SendArgs, reply *SendReply) error {
 	service.vm.ctx.Log.Verbo("Send called with username: %s", args.Username)
 
 	if args.Amount == 0 {
 		return errInvalidAmount
 	}
+	if len(args.Memo.Bytes) > maxMemoSize {
+		return errMemoTooLarge
+	}
 
 	assetID, err := service.vm.Lookup(args.AssetID)
 	if err != nil {
@@ -559,13 +1326,9 @@ func (service *Service) Send(r *http.Request, args *SendArgs, reply *SendReply)
 		}
 	}
 
-	toBytes, err := service.vm.Parse(args.To)
+	to, err := service.parseToAddress(args.Username, args.Password, args.To)
 	if err != nil {
-		return fmt.Errorf("problem parsing to address: %w", err)
-	}
-	to, err := ids.ToShortID(toBytes)
-	if err != nil {
-		return fmt.Errorf("problem parsing to address: %w", err)
+		return err
 	}
 
 	db, err := service.vm.ctx.Keystore.GetDatabase(args.Username, args.Password)
@@ -593,49 +1356,15 @@ func (service *Service) Send(r *http.Request, args *SendArgs, reply *SendReply)
 		kc.Add(sk)
 	}
 
-	amountSpent := uint64(0)
-	time := service.vm.clock.Unix()
-
-	ins := []*TransferableInput{}
-	keys := [][]*crypto.PrivateKeySECP256K1R{}
-	for _, utxo := range utxos {
-		if !utxo.AssetID().Equals(assetID) {
-			continue
-		}
-		inputIntf, signers, err := kc.Spend(utxo.Out, time)
-		if err != nil {
-			continue
-		}
-		input, ok := inputIntf.(FxTransferable)
-		if !ok {
-			continue
-		}
-		spent, err := math.Add64(amountSpent, input.Amount())
-		if err != nil {
-			return errSpendOverflow
-		}
-		amountSpent = spent
-
-		in := &TransferableInput{
-			UTXOID: utxo.UTXOID,
-			Asset:  Asset{ID: assetID},
-			In:     input,
-		}
-
-		ins = append(ins, in)
-		keys = append(keys, signers)
-
-		if amountSpent >= uint64(args.Amount) {
-			break
-		}
+	amounts := map[[32]byte]uint64{assetID.Key(): uint64(args.Amount)}
+	if err := service.addFee(amounts); err != nil {
+		return err
 	}
-
-	if amountSpent < uint64(args.Amount) {
-		return errInsufficientFunds
+	ins, keys, amountsSpent, err := service.spend(utxos, kc, amounts)
+	if err != nil {
+		return err
 	}
 
-	SortTransferableInputsWithSigners(ins, keys)
-
 	outs := []*TransferableOutput{
 		&TransferableOutput{
 			Asset: Asset{
@@ -651,59 +1380,19 @@ func (service *Service) Send(r *http.Request, args *SendArgs, reply *SendReply)
 			},
 		},
 	}
-
-	if amountSpent > uint64(args.Amount) {
-		changeAddr := kc.Keys[0].PublicKey().Address()
-		outs = append(outs,
-			&TransferableOutput{
-				Asset: Asset{
-					ID: assetID,
-				},
-				Out: &secp256k1fx.TransferOutput{
-					Amt:      amountSpent - uint64(args.Amount),
-					Locktime: 0,
-					OutputOwners: secp256k1fx.OutputOwners{
-						Threshold: 1,
-						Addrs:     []ids.ShortID{changeAddr},
-					},
-				},
-			},
-		)
-	}
-
+	outs = append(outs, changeOutputs(amountsSpent, amounts, kc.Keys[0].PublicKey().Address())...)
 	SortTransferableOutputs(outs, service.vm.codec)
 
 	tx := Tx{
-		UnsignedTx: &BaseTx{
+		UnsignedTx: WithMemo(&BaseTx{
 			NetID: service.vm.ctx.NetworkID,
 			BCID:  service.vm.ctx.ChainID,
 			Outs:  outs,
 			Ins:   ins,
-		},
-	}
-
-	unsignedBytes, err := service.vm.codec.Marshal(&tx.UnsignedTx)
-	if err != nil {
-		return fmt.Errorf("problem creating transaction: %w", err)
-	}
-	hash := hashing.ComputeHash256(unsignedBytes)
-
-	for _, credKeys := range keys {
-		cred := &secp256k1fx.Credential{}
-		for _, key := range credKeys {
-			sig, err := key.SignHash(hash)
-			if err != nil {
-				return fmt.Errorf("problem creating transaction: %w", err)
-			}
-			fixedSig := [crypto.SECP256K1RSigLen]byte{}
-			copy(fixedSig[:], sig)
-
-			cred.Sigs = append(cred.Sigs, fixedSig)
-		}
-		tx.Creds = append(tx.Creds, &Credential{Cred: cred})
+		}, args.Memo.Bytes),
 	}
 
-	b, err := service.vm.codec.Marshal(tx)
+	b, err := service.signTx(&tx, keys)
 	if err != nil {
 		return fmt.Errorf("problem creating transaction: %w", err)
 	}
@@ -717,62 +1406,36 @@ func (service *Service) Send(r *http.Request, args *SendArgs, reply *SendReply)
 	return nil
 }
 
-type innerSortTransferableInputsWithSigners struct {
-	ins     []*TransferableInput
-	signers [][]*crypto.PrivateKeySECP256K1R
-}
-
-func (ins *innerSortTransferableInputsWithSigners) Less(i, j int) bool {
-	iID, iIndex := ins.ins[i].InputSource()
-	jID, jIndex := ins.ins[j].InputSource()
-
-	switch bytes.Compare(iID.Bytes(), jID.Bytes()) {
-	case -1:
-		return true
-	case 0:
-		return iIndex < jIndex
-	default:
-		return false
-	}
-}
-func (ins *innerSortTransferableInputsWithSigners) Len() int { return len(ins.ins) }
-func (ins *innerSortTransferableInputsWithSigners) Swap(i, j int) {
-	ins.ins[j], ins.ins[i] = ins.ins[i], ins.ins[j]
-	ins.signers[j], ins.signers[i] = ins.signers[i], ins.signers[j]
-}
-
-// SortTransferableInputsWithSigners sorts the inputs and signers based on the
-// input's utxo ID
-func SortTransferableInputsWithSigners(ins []*TransferableInput, signers [][]*crypto.PrivateKeySECP256K1R) {
-	sort.Sort(&innerSortTransferableInputsWithSigners{ins: ins, signers: signers})
-}
+// ConsolidateUTXOsArgs are arguments for passing into ConsolidateUTXOs requests
+type ConsolidateUTXOsArgs struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+	AssetID  string `json:"assetID"`
 
-// IsSortedAndUniqueTransferableInputsWithSigners returns true if the inputs are
-// sorted and unique
-func IsSortedAndUniqueTransferableInputsWithSigners(ins []*TransferableInput, signers [][]*crypto.PrivateKeySECP256K1R) bool {
-	return utils.IsSortedAndUnique(&innerSortTransferableInputsWithSigners{ins: ins, signers: signers})
-}
+	// Address whose UTXOs are consolidated. If empty, the UTXOs of each of the
+	// user's addresses are consolidated.
+	Address string `json:"address"`
 
-// CreateMintTxArgs are arguments for passing into CreateMintTx requests
-type CreateMintTxArgs struct {
-	Amount  json.Uint64 `json:"amount"`
-	AssetID string      `json:"assetID"`
-	To      string      `json:"to"`
-	Minters []string    `json:"minters"`
+	// Number of UTXOs of the asset each address should hold afterwards.
+	// Defaults to 1.
+	Target json.Uint32 `json:"target"`
 }
 
-// CreateMintTxReply defines the CreateMintTx replies returned from the API
-type CreateMintTxReply struct {
-	Tx formatting.CB58 `json:"tx"`
+// ConsolidateUTXOsReply defines the ConsolidateUTXOs replies returned from the
+// API
+type ConsolidateUTXOsReply struct {
+	TxIDs []ids.ID `json:"txIDs"`
 }
 
-// CreateMintTx returns the newly created unsigned transaction
-func (service *Service) CreateMintTx(r *http.Request, args *CreateMintTxArgs, reply *CreateMintTxReply) error {
-	service.vm.ctx.Log.Verbo("CreateMintTx called")
-
-	if args.Amount == 0 {
-		return errInvalidMintAmount
-	}
+// ConsolidateUTXOs merges the smallest UTXOs of an asset held by the user's
+// addresses, so that each address holds at most [Target] UTXOs of the asset.
+// Only unlocked UTXOs owned solely by one address are merged, and each address
+// keeps its own funds. If the asset is AVA, each transaction's fee is paid out
+// of the AVA it merges, and otherwise with the user's AVA. Returns the IDs of
+// the issued transactions, which is empty if no address holds more than
+// [Target] UTXOs.
+func (service *Service) ConsolidateUTXOs(_ *http.Request, args *ConsolidateUTXOsArgs, reply *ConsolidateUTXOsReply) error {
+	service.vm.ctx.Log.Verbo("ConsolidateUTXOs called with username: %s", args.Username)
 
 	assetID, err := service.vm.Lookup(args.AssetID)
 	if err != nil {
@@ -782,12 +1445,273 @@ func (service *Service) CreateMintTx(r *http.Request, args *CreateMintTxArgs, re
 		}
 	}
 
-	toBytes, err := service.vm.Parse(args.To)
-	if err != nil {
-		return fmt.Errorf("problem parsing to address '%s': %w", args.To, err)
+	target := int(args.Target)
+	if target == 0 {
+		target = 1
 	}
-	to, err := ids.ToShortID(toBytes)
-	if err != nil {
+
+	db, err := service.vm.ctx.Keystore.GetDatabase(args.Username, args.Password)
+	if err != nil {
+		return fmt.Errorf("problem retrieving user: %w", err)
+	}
+	user := userState{vm: service.vm}
+	addresses, _ := user.Addresses(db)
+
+	if args.Address != "" {
+		addrBytes, err := service.vm.Parse(args.Address)
+		if err != nil {
+			return fmt.Errorf("problem parsing address: %w", err)
+		}
+		addr := ids.NewID(hashing.ComputeHash256Array(addrBytes))
+		owned := ids.Set{}
+		owned.Add(addresses...)
+		if !owned.Contains(addr) {
+			return errAddressNotOwned
+		}
+		addresses = []ids.ID{addr}
+	}
+
+	batches := [][]*TransferableInput{}
+	keys := []*crypto.PrivateKeySECP256K1R{}
+	for _, addr := range addresses {
+		key, err := user.Key(db, addr)
+		if err != nil {
+			return fmt.Errorf("problem retrieving private key: %w", err)
+		}
+		addrBatches, err := service.consolidate(assetID, key, target)
+		if err != nil {
+			return err
+		}
+		for _, batch := range addrBatches {
+			batches = append(batches, batch)
+			keys = append(keys, key)
+		}
+	}
+
+	// AVA pays its own fees out of the merged amount
+	numFees := len(batches)
+	if assetID.Equals(service.vm.ava) {
+		numFees = 0
+	}
+	fees, err := service.userFeeChain(args.Username, args.Password, nil, numFees)
+	if err != nil {
+		return err
+	}
+
+	txs := [][]byte{}
+	for i, batch := range batches {
+		b, err := service.merge(assetID, batch, keys[i], fees)
+		if err != nil {
+			return err
+		}
+		txs = append(txs, b)
+	}
+
+	reply.TxIDs = []ids.ID{}
+	for _, b := range txs {
+		txID, err := service.vm.IssueTx(b, nil)
+		if err != nil {
+			return fmt.Errorf("problem issuing transaction: %w", err)
+		}
+		reply.TxIDs = append(reply.TxIDs, txID)
+	}
+	return nil
+}
+
+// consolidate returns the batches of inputs that merge the smallest UTXOs of
+// [assetID] held solely by [key] until it holds at most [target] of them. Each
+// batch is spent by one transaction.
+func (service *Service) consolidate(assetID ids.ID, key *crypto.PrivateKeySECP256K1R, target int) ([][]*TransferableInput, error) {
+	addr := key.PublicKey().Address()
+	addrs := ids.Set{}
+	addrs.Add(ids.NewID(hashing.ComputeHash256Array(addr.Bytes())))
+	utxos, err := service.vm.GetUTXOs(addrs)
+	if err != nil {
+		return nil, fmt.Errorf("problem retrieving UTXOs: %w", err)
+	}
+
+	now := service.vm.clock.Unix()
+	ins := []*TransferableInput{}
+	for _, utxo := range utxos {
+		out, ok := utxo.Out.(*secp256k1fx.TransferOutput)
+		if !ok ||
+			!utxo.AssetID().Equals(assetID) ||
+			out.Locktime > now ||
+			out.Threshold != 1 ||
+			len(out.Addrs) != 1 ||
+			!out.Addrs[0].Equals(addr) {
+			continue
+		}
+		ins = append(ins, &TransferableInput{
+			UTXOID: utxo.UTXOID,
+			Asset:  Asset{ID: assetID},
+			In: &secp256k1fx.TransferInput{
+				Amt: out.Amt,
+				Input: secp256k1fx.Input{
+					SigIndices: []uint32{0},
+				},
+			},
+		})
+	}
+	if len(ins) <= target {
+		return nil, nil
+	}
+
+	// Merge the smallest UTXOs, so that the largest UTXOs are left untouched
+	sort.Slice(ins, func(i, j int) bool {
+		return ins[i].Input().Amount() < ins[j].Input().Amount()
+	})
+	toMerge := ins[:len(ins)-target+1]
+
+	batches := [][]*TransferableInput{}
+	for len(toMerge) > 0 {
+		numIns := maxOutputsPerTx
+		if numIns > len(toMerge) {
+			numIns = len(toMerge)
+		}
+		batches = append(batches, toMerge[:numIns])
+		toMerge = toMerge[numIns:]
+	}
+	return batches, nil
+}
+
+// merge returns a signed transaction that spends [ins], which are all held by
+// [key], to a single output held by [key]. If [assetID] is AVA, the tx fee is
+// paid out of the merged amount, and otherwise by [fees].
+func (service *Service) merge(
+	assetID ids.ID,
+	ins []*TransferableInput,
+	key *crypto.PrivateKeySECP256K1R,
+	fees *feeChain,
+) ([]byte, error) {
+	amount := uint64(0)
+	for _, in := range ins {
+		sum, err := math.Add64(amount, in.Input().Amount())
+		if err != nil {
+			return nil, errSpendOverflow
+		}
+		amount = sum
+	}
+	if assetID.Equals(service.vm.ava) {
+		if amount <= service.vm.txFee {
+			return nil, errInsufficientFee
+		}
+		amount -= service.vm.txFee
+	}
+
+	outs := []*TransferableOutput{&TransferableOutput{
+		Asset: Asset{ID: assetID},
+		Out: &secp256k1fx.TransferOutput{
+			Amt: amount,
+			OutputOwners: secp256k1fx.OutputOwners{
+				Threshold: 1,
+				Addrs:     []ids.ShortID{key.PublicKey().Address()},
+			},
+		},
+	}}
+	keys := [][]*crypto.PrivateKeySECP256K1R{}
+	for range ins {
+		keys = append(keys, []*crypto.PrivateKeySECP256K1R{key})
+	}
+
+	feeIns, feeKeys, feeOuts := fees.next()
+	ins = append(append([]*TransferableInput{}, ins...), feeIns...)
+	keys = append(keys, feeKeys...)
+	outs = append(outs, feeOuts...)
+	SortTransferableInputsWithSigners(ins, keys)
+	SortTransferableOutputs(outs, service.vm.codec)
+
+	tx := &Tx{
+		UnsignedTx: &BaseTx{
+			NetID: service.vm.ctx.NetworkID,
+			BCID:  service.vm.ctx.ChainID,
+			Outs:  outs,
+			Ins:   ins,
+		},
+	}
+	b, err := service.signTx(tx, keys)
+	if err != nil {
+		return nil, fmt.Errorf("problem creating transaction: %w", err)
+	}
+	fees.built(b, outs)
+	return b, nil
+}
+
+type innerSortTransferableInputsWithSigners struct {
+	ins     []*TransferableInput
+	signers [][]*crypto.PrivateKeySECP256K1R
+}
+
+func (ins *innerSortTransferableInputsWithSigners) Less(i, j int) bool {
+	iID, iIndex := ins.ins[i].InputSource()
+	jID, jIndex := ins.ins[j].InputSource()
+
+	switch bytes.Compare(iID.Bytes(), jID.Bytes()) {
+	case -1:
+		return true
+	case 0:
+		return iIndex < jIndex
+	default:
+		return false
+	}
+}
+func (ins *innerSortTransferableInputsWithSigners) Len() int { return len(ins.ins) }
+func (ins *innerSortTransferableInputsWithSigners) Swap(i, j int) {
+	ins.ins[j], ins.ins[i] = ins.ins[i], ins.ins[j]
+	ins.signers[j], ins.signers[i] = ins.signers[i], ins.signers[j]
+}
+
+// SortTransferableInputsWithSigners sorts the inputs and signers based on the
+// input's utxo ID
+func SortTransferableInputsWithSigners(ins []*TransferableInput, signers [][]*crypto.PrivateKeySECP256K1R) {
+	sort.Sort(&innerSortTransferableInputsWithSigners{ins: ins, signers: signers})
+}
+
+// IsSortedAndUniqueTransferableInputsWithSigners returns true if the inputs are
+// sorted and unique
+func IsSortedAndUniqueTransferableInputsWithSigners(ins []*TransferableInput, signers [][]*crypto.PrivateKeySECP256K1R) bool {
+	return utils.IsSortedAndUnique(&innerSortTransferableInputsWithSigners{ins: ins, signers: signers})
+}
+
+// CreateMintTxArgs are arguments for passing into CreateMintTx requests
+type CreateMintTxArgs struct {
+	Amount  json.Uint64     `json:"amount"`
+	AssetID string          `json:"assetID"`
+	To      string          `json:"to"`
+	Minters []string        `json:"minters"`
+	Memo    formatting.CB58 `json:"memo"`
+}
+
+// CreateMintTxReply defines the CreateMintTx replies returned from the API
+type CreateMintTxReply struct {
+	Tx formatting.CB58 `json:"tx"`
+}
+
+// CreateMintTx returns the newly created unsigned transaction
+func (service *Service) CreateMintTx(r *http.Request, args *CreateMintTxArgs, reply *CreateMintTxReply) error {
+	service.vm.ctx.Log.Verbo("CreateMintTx called")
+
+	if args.Amount == 0 {
+		return errInvalidMintAmount
+	}
+	if len(args.Memo.Bytes) > maxMemoSize {
+		return errMemoTooLarge
+	}
+
+	assetID, err := service.vm.Lookup(args.AssetID)
+	if err != nil {
+		assetID, err = ids.FromString(args.AssetID)
+		if err != nil {
+			return fmt.Errorf("asset '%s' not found", args.AssetID)
+		}
+	}
+
+	toBytes, err := service.vm.Parse(args.To)
+	if err != nil {
+		return fmt.Errorf("problem parsing to address '%s': %w", args.To, err)
+	}
+	to, err := ids.ToShortID(toBytes)
+	if err != nil {
 		return fmt.Errorf("problem parsing to address '%s': %w", args.To, err)
 	}
 
@@ -829,7 +1753,7 @@ func (service *Service) CreateMintTx(r *http.Request, args *CreateMintTxArgs, re
 			}
 
 			tx := Tx{
-				UnsignedTx: &OperationTx{
+				UnsignedTx: WithMemo(&OperationTx{
 					BaseTx: BaseTx{
 						NetID: service.vm.ctx.NetworkID,
 						BCID:  service.vm.ctx.ChainID,
@@ -867,7 +1791,7 @@ func (service *Service) CreateMintTx(r *http.Request, args *CreateMintTxArgs, re
 							},
 						},
 					},
-				},
+				}, args.Memo.Bytes),
 			}
 
 			txBytes, err := service.vm.codec.Marshal(&tx)
@@ -994,3 +1918,1420 @@ func (service *Service) SignMintTx(r *http.Request, args *SignMintTxArgs, reply
 	reply.Tx.Bytes = txBytes
 	return nil
 }
+
+// MintArgs are arguments for passing into Mint requests
+type MintArgs struct {
+	Username string          `json:"username"`
+	Password string          `json:"password"`
+	Amount   json.Uint64     `json:"amount"`
+	AssetID  string          `json:"assetID"`
+	To       string          `json:"to"`
+	Memo     formatting.CB58 `json:"memo"`
+}
+
+// MintReply defines the Mint replies returned from the API
+type MintReply struct {
+	TxID ids.ID `json:"txID"`
+}
+
+// Mint issues a transaction that mints [Amount] of the asset to the [To]
+// address, using one of the user's mint outputs of the asset
+func (service *Service) Mint(r *http.Request, args *MintArgs, reply *MintReply) error {
+	service.vm.ctx.Log.Verbo("Mint called with username: %s", args.Username)
+
+	if args.Amount == 0 {
+		return errInvalidMintAmount
+	}
+	if len(args.Memo.Bytes) > maxMemoSize {
+		return errMemoTooLarge
+	}
+
+	assetID, err := service.vm.Lookup(args.AssetID)
+	if err != nil {
+		assetID, err = ids.FromString(args.AssetID)
+		if err != nil {
+			return fmt.Errorf("asset '%s' not found", args.AssetID)
+		}
+	}
+
+	to, err := service.parseToAddress(args.Username, args.Password, args.To)
+	if err != nil {
+		return err
+	}
+
+	db, err := service.vm.ctx.Keystore.GetDatabase(args.Username, args.Password)
+	if err != nil {
+		return fmt.Errorf("problem retrieving user: %w", err)
+	}
+
+	user := userState{vm: service.vm}
+
+	addresses, _ := user.Addresses(db)
+
+	addrs := ids.Set{}
+	addrs.Add(addresses...)
+	utxos, err := service.vm.GetUTXOs(addrs)
+	if err != nil {
+		return fmt.Errorf("problem retrieving user's UTXOs: %w", err)
+	}
+
+	kc := secp256k1fx.NewKeychain()
+	for _, addr := range addresses {
+		sk, err := user.Key(db, addr)
+		if err != nil {
+			return fmt.Errorf("problem retrieving private key: %w", err)
+		}
+		kc.Add(sk)
+	}
+
+	for _, utxo := range utxos {
+		out, ok := utxo.Out.(*secp256k1fx.MintOutput)
+		if !ok || !utxo.AssetID().Equals(assetID) {
+			continue
+		}
+		input, signers, err := kc.Spend(out, service.vm.clock.Unix())
+		if err != nil {
+			continue
+		}
+
+		// The mint output is recreated so that the asset can be minted again
+		outs := []*OperableOutput{
+			&OperableOutput{
+				&secp256k1fx.MintOutput{
+					OutputOwners: out.OutputOwners,
+				},
+			},
+			&OperableOutput{
+				&secp256k1fx.TransferOutput{
+					Amt: uint64(args.Amount),
+					OutputOwners: secp256k1fx.OutputOwners{
+						Threshold: 1,
+						Addrs:     []ids.ShortID{to},
+					},
+				},
+			},
+		}
+		SortOperableOutputs(outs, service.vm.codec)
+
+		fees, err := service.newFeeChain(utxos, kc, kc.Keys[0], 1)
+		if err != nil {
+			return err
+		}
+
+		opTx := &OperationTx{
+			BaseTx: BaseTx{
+				NetID: service.vm.ctx.NetworkID,
+				BCID:  service.vm.ctx.ChainID,
+			},
+			Ops: []*Operation{
+				&Operation{
+					Asset: Asset{
+						ID: assetID,
+					},
+					Ins: []*OperableInput{
+						&OperableInput{
+							UTXOID: utxo.UTXOID,
+							In:     input,
+						},
+					},
+					Outs: outs,
+				},
+			},
+		}
+		feeKeys := payFee(&opTx.BaseTx, fees)
+		tx := Tx{UnsignedTx: WithMemo(opTx, args.Memo.Bytes)}
+
+		unsignedBytes, err := service.vm.codec.Marshal(&tx.UnsignedTx)
+		if err != nil {
+			return fmt.Errorf("problem creating transaction: %w", err)
+		}
+		hash := hashing.ComputeHash256(unsignedBytes)
+
+		// The operation's input follows the inputs that pay the fee, so its
+		// credential is the last credential
+		for _, keys := range feeKeys {
+			sigs, err := signatures(hash, keys)
+			if err != nil {
+				return fmt.Errorf("problem creating transaction: %w", err)
+			}
+			tx.Creds = append(tx.Creds, &Credential{Cred: &secp256k1fx.Credential{Sigs: sigs}})
+		}
+		sigs, err := signatures(hash, signers)
+		if err != nil {
+			return fmt.Errorf("problem creating transaction: %w", err)
+		}
+		tx.Creds = append(tx.Creds, &Credential{Cred: &secp256k1fx.Credential{Sigs: sigs}})
+
+		b, err := service.vm.codec.Marshal(tx)
+		if err != nil {
+			return fmt.Errorf("problem creating transaction: %w", err)
+		}
+
+		txID, err := service.vm.IssueTx(b, nil)
+		if err != nil {
+			return fmt.Errorf("problem issuing transaction: %w", err)
+		}
+
+		reply.TxID = txID
+		return nil
+	}
+
+	return errAddressesCantMintAsset
+}
+
+// CreateNFTAssetArgs are arguments for passing into CreateNFTAsset requests
+type CreateNFTAssetArgs struct {
+	Username   string   `json:"username"`
+	Password   string   `json:"password"`
+	Name       string   `json:"name"`
+	Symbol     string   `json:"symbol"`
+	MinterSets []Owners `json:"minterSets"`
+}
+
+// CreateNFTAssetReply defines the CreateNFTAsset replies returned from the API
+type CreateNFTAssetReply struct {
+	AssetID ids.ID `json:"assetID"`
+}
+
+// CreateNFTAsset issues a transaction that creates a family of NFTs. The i-th
+// minter set is given the authority to mint NFTs into group i of the family.
+func (service *Service) CreateNFTAsset(_ *http.Request, args *CreateNFTAssetArgs, reply *CreateNFTAssetReply) error {
+	service.vm.ctx.Log.Verbo("CreateNFTAsset called with name: %s symbol: %s number of minters: %d",
+		args.Name,
+		args.Symbol,
+		len(args.MinterSets),
+	)
+
+	fxID, err := service.nftFxID()
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case len(args.MinterSets) == 0:
+		return errNoMinters
+	case len(args.MinterSets) > maxOutputsPerTx-1:
+		return errTooManyMinterSets
+	}
+
+	initialState := &InitialState{FxID: fxID}
+	for i, owner := range args.MinterSets {
+		minter := &nftfx.MintOutput{
+			GroupID: uint32(i),
+			OutputOwners: secp256k1fx.OutputOwners{
+				Threshold: uint32(owner.Threshold),
+			},
+		}
+		for _, address := range owner.Minters {
+			addrBytes, err := service.vm.Parse(address)
+			if err != nil {
+				return err
+			}
+			addr, err := ids.ToShortID(addrBytes)
+			if err != nil {
+				return err
+			}
+			minter.Addrs = append(minter.Addrs, addr)
+		}
+		ids.SortShortIDs(minter.Addrs)
+		initialState.Outs = append(initialState.Outs, minter)
+	}
+	initialState.Sort(service.vm.codec)
+
+	fees, err := service.userFeeChain(args.Username, args.Password, nil, 1)
+	if err != nil {
+		return err
+	}
+
+	createTx := &CreateAssetTx{
+		BaseTx: BaseTx{
+			NetID: service.vm.ctx.NetworkID,
+			BCID:  service.vm.ctx.ChainID,
+		},
+		Name:   args.Name,
+		Symbol: args.Symbol,
+		States: []*InitialState{
+			initialState,
+		},
+	}
+	tx := &Tx{UnsignedTx: createTx}
+
+	b, err := service.signTx(tx, payFee(&createTx.BaseTx, fees))
+	if err != nil {
+		return fmt.Errorf("problem creating transaction: %w", err)
+	}
+
+	assetID, err := service.vm.IssueTx(b, nil)
+	if err != nil {
+		return fmt.Errorf("problem issuing transaction: %w", err)
+	}
+
+	reply.AssetID = assetID
+	return nil
+}
+
+// MintNFTArgs are arguments for passing into MintNFT requests
+type MintNFTArgs struct {
+	Username string          `json:"username"`
+	Password string          `json:"password"`
+	AssetID  string          `json:"assetID"`
+	GroupID  json.Uint32     `json:"groupID"`
+	Payload  formatting.CB58 `json:"payload"`
+	To       string          `json:"to"`
+}
+
+// MintNFTReply defines the MintNFT replies returned from the API
+type MintNFTReply struct {
+	TxID ids.ID `json:"txID"`
+}
+
+// MintNFT issues a transaction that mints an NFT with [Payload] into group
+// [GroupID] of the asset and sends it to the [To] address
+func (service *Service) MintNFT(_ *http.Request, args *MintNFTArgs, reply *MintNFTReply) error {
+	service.vm.ctx.Log.Verbo("MintNFT called with username: %s", args.Username)
+
+	if len(args.Payload.Bytes) > nftfx.MaxPayloadSize {
+		return errPayloadTooLarge
+	}
+
+	assetID, err := service.vm.Lookup(args.AssetID)
+	if err != nil {
+		assetID, err = ids.FromString(args.AssetID)
+		if err != nil {
+			return fmt.Errorf("asset '%s' not found", args.AssetID)
+		}
+	}
+
+	to, err := service.parseToAddress(args.Username, args.Password, args.To)
+	if err != nil {
+		return err
+	}
+
+	kc, utxos, err := service.userAssetUTXOs(args.Username, args.Password, assetID)
+	if err != nil {
+		return err
+	}
+
+	for _, utxo := range utxos {
+		out, ok := utxo.Out.(*nftfx.MintOutput)
+		if !ok || out.GroupID != uint32(args.GroupID) {
+			continue
+		}
+		sigIndices, signers, able := kc.Match(&out.OutputOwners)
+		if !able {
+			continue
+		}
+
+		// The mint output is recreated so that the group can be minted into
+		// again
+		outs := []*OperableOutput{
+			&OperableOutput{
+				&nftfx.MintOutput{
+					GroupID:      out.GroupID,
+					OutputOwners: out.OutputOwners,
+				},
+			},
+			&OperableOutput{
+				&nftfx.TransferOutput{
+					GroupID: out.GroupID,
+					Payload: args.Payload.Bytes,
+					OutputOwners: secp256k1fx.OutputOwners{
+						Threshold: 1,
+						Addrs:     []ids.ShortID{to},
+					},
+				},
+			},
+		}
+		SortOperableOutputs(outs, service.vm.codec)
+
+		txID, err := service.issueNFTOperation(args.Username, args.Password, &Operation{
+			Asset: Asset{
+				ID: assetID,
+			},
+			Ins: []*OperableInput{
+				&OperableInput{
+					UTXOID: utxo.UTXOID,
+					In: &nftfx.MintInput{
+						Input: secp256k1fx.Input{
+							SigIndices: sigIndices,
+						},
+					},
+				},
+			},
+			Outs: outs,
+		}, signers)
+		if err != nil {
+			return err
+		}
+
+		reply.TxID = txID
+		return nil
+	}
+
+	return errAddressesCantMintAsset
+}
+
+// SendNFTArgs are arguments for passing into SendNFT requests
+type SendNFTArgs struct {
+	Username string      `json:"username"`
+	Password string      `json:"password"`
+	AssetID  string      `json:"assetID"`
+	GroupID  json.Uint32 `json:"groupID"`
+	To       string      `json:"to"`
+}
+
+// SendNFTReply defines the SendNFT replies returned from the API
+type SendNFTReply struct {
+	TxID ids.ID `json:"txID"`
+}
+
+// SendNFT issues a transaction that sends one of the user's NFTs in group
+// [GroupID] of the asset to the [To] address
+func (service *Service) SendNFT(_ *http.Request, args *SendNFTArgs, reply *SendNFTReply) error {
+	service.vm.ctx.Log.Verbo("SendNFT called with username: %s", args.Username)
+
+	assetID, err := service.vm.Lookup(args.AssetID)
+	if err != nil {
+		assetID, err = ids.FromString(args.AssetID)
+		if err != nil {
+			return fmt.Errorf("asset '%s' not found", args.AssetID)
+		}
+	}
+
+	to, err := service.parseToAddress(args.Username, args.Password, args.To)
+	if err != nil {
+		return err
+	}
+
+	kc, utxos, err := service.userAssetUTXOs(args.Username, args.Password, assetID)
+	if err != nil {
+		return err
+	}
+
+	for _, utxo := range utxos {
+		out, ok := utxo.Out.(*nftfx.TransferOutput)
+		if !ok || out.GroupID != uint32(args.GroupID) {
+			continue
+		}
+		sigIndices, signers, able := kc.Match(&out.OutputOwners)
+		if !able {
+			continue
+		}
+
+		txID, err := service.issueNFTOperation(args.Username, args.Password, &Operation{
+			Asset: Asset{
+				ID: assetID,
+			},
+			Ins: []*OperableInput{
+				&OperableInput{
+					UTXOID: utxo.UTXOID,
+					In: &nftfx.TransferInput{
+						Input: secp256k1fx.Input{
+							SigIndices: sigIndices,
+						},
+					},
+				},
+			},
+			Outs: []*OperableOutput{
+				&OperableOutput{
+					&nftfx.TransferOutput{
+						GroupID: out.GroupID,
+						Payload: out.Payload,
+						OutputOwners: secp256k1fx.OutputOwners{
+							Threshold: 1,
+							Addrs:     []ids.ShortID{to},
+						},
+					},
+				},
+			},
+		}, signers)
+		if err != nil {
+			return err
+		}
+
+		reply.TxID = txID
+		return nil
+	}
+
+	return errAddressesCantSendNFT
+}
+
+// nftFxID returns the index of the nftfx in this chain's feature extensions
+// managerState returns the initial state that makes [owners] the manager of an
+// asset
+func (service *Service) managerState(owners *Owners) (*InitialState, error) {
+	fxID, err := service.managedFxID()
+	if err != nil {
+		return nil, err
+	}
+
+	manager := &managedfx.ManagerOutput{
+		OutputOwners: secp256k1fx.OutputOwners{
+			Threshold: uint32(owners.Threshold),
+		},
+	}
+	for _, address := range owners.Minters {
+		addrBytes, err := service.vm.Parse(address)
+		if err != nil {
+			return nil, err
+		}
+		addr, err := ids.ToShortID(addrBytes)
+		if err != nil {
+			return nil, err
+		}
+		manager.Addrs = append(manager.Addrs, addr)
+	}
+	ids.SortShortIDs(manager.Addrs)
+
+	return &InitialState{
+		FxID: fxID,
+		Outs: []verify.Verifiable{manager},
+	}, nil
+}
+
+// managedFxID returns the index of the managedfx in this chain's fxs
+func (service *Service) managedFxID() (uint32, error) {
+	for i, fx := range service.vm.fxs {
+		if _, ok := fx.Fx.(*managedfx.Fx); ok {
+			return uint32(i), nil
+		}
+	}
+	return 0, errManagedFxNotEnabled
+}
+
+func (service *Service) nftFxID() (uint32, error) {
+	for i, fx := range service.vm.fxs {
+		if _, ok := fx.Fx.(*nftfx.Fx); ok {
+			return uint32(i), nil
+		}
+	}
+	return 0, errNFTFxNotEnabled
+}
+
+// parseToAddress parses [addr], the address funds are sent to. If [addr]
+// isn't an address, it's the name of an address in the address book of the
+// user [username].
+func (service *Service) parseToAddress(username, password, addr string) (ids.ShortID, error) {
+	if addrBytes, err := service.vm.Parse(addr); err == nil {
+		if to, err := ids.ToShortID(addrBytes); err == nil {
+			return to, nil
+		}
+	}
+	to, err := service.vm.ctx.Keystore.ResolveAddress(username, password, addr)
+	if err != nil {
+		return ids.ShortID{}, fmt.Errorf("problem parsing to address '%s': %w", addr, err)
+	}
+	return to, nil
+}
+
+// userAssetUTXOs returns a keychain of the user's keys and the UTXOs of
+// [assetID] that the user's addresses reference
+func (service *Service) userAssetUTXOs(username, password string, assetID ids.ID) (*secp256k1fx.Keychain, []*UTXO, error) {
+	db, err := service.vm.ctx.Keystore.GetDatabase(username, password)
+	if err != nil {
+		return nil, nil, fmt.Errorf("problem retrieving user: %w", err)
+	}
+
+	user := userState{vm: service.vm}
+
+	addresses, _ := user.Addresses(db)
+
+	kc := secp256k1fx.NewKeychain()
+	for _, addr := range addresses {
+		sk, err := user.Key(db, addr)
+		if err != nil {
+			return nil, nil, fmt.Errorf("problem retrieving private key: %w", err)
+		}
+		kc.Add(sk)
+	}
+
+	addrs := ids.Set{}
+	addrs.Add(addresses...)
+	utxos, err := service.vm.GetUTXOsByAsset(addrs, assetID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("problem retrieving user's UTXOs: %w", err)
+	}
+	return kc, utxos, nil
+}
+
+// issueNFTOperation issues a transaction containing only [op], which has a
+// single input that is spent by [signers], and pays its fee with the AVA of
+// the user [username]
+func (service *Service) issueNFTOperation(username, password string, op *Operation, signers []*crypto.PrivateKeySECP256K1R) (ids.ID, error) {
+	fees, err := service.userFeeChain(username, password, nil, 1)
+	if err != nil {
+		return ids.ID{}, err
+	}
+
+	opTx := &OperationTx{
+		BaseTx: BaseTx{
+			NetID: service.vm.ctx.NetworkID,
+			BCID:  service.vm.ctx.ChainID,
+		},
+		Ops: []*Operation{op},
+	}
+	feeKeys := payFee(&opTx.BaseTx, fees)
+	tx := Tx{UnsignedTx: opTx}
+
+	unsignedBytes, err := service.vm.codec.Marshal(&tx.UnsignedTx)
+	if err != nil {
+		return ids.ID{}, fmt.Errorf("problem creating transaction: %w", err)
+	}
+	hash := hashing.ComputeHash256(unsignedBytes)
+
+	// The operation's input follows the inputs that pay the fee, so its
+	// credential is the last credential
+	for _, keys := range feeKeys {
+		sigs, err := signatures(hash, keys)
+		if err != nil {
+			return ids.ID{}, fmt.Errorf("problem creating transaction: %w", err)
+		}
+		tx.Creds = append(tx.Creds, &Credential{Cred: &secp256k1fx.Credential{Sigs: sigs}})
+	}
+	sigs, err := signatures(hash, signers)
+	if err != nil {
+		return ids.ID{}, fmt.Errorf("problem creating transaction: %w", err)
+	}
+	tx.Creds = append(tx.Creds, &Credential{Cred: &nftfx.Credential{
+		Credential: secp256k1fx.Credential{Sigs: sigs},
+	}})
+
+	b, err := service.vm.codec.Marshal(tx)
+	if err != nil {
+		return ids.ID{}, fmt.Errorf("problem creating transaction: %w", err)
+	}
+
+	txID, err := service.vm.IssueTx(b, nil)
+	if err != nil {
+		return ids.ID{}, fmt.Errorf("problem issuing transaction: %w", err)
+	}
+	return txID, nil
+}
+
+// CreateUnsignedTxArgs are arguments for passing into CreateUnsignedTx requests
+type CreateUnsignedTxArgs struct {
+	Amount  json.Uint64     `json:"amount"`
+	AssetID string          `json:"assetID"`
+	To      string          `json:"to"`
+	Memo    formatting.CB58 `json:"memo"`
+
+	// Addresses that will sign the transaction. Only UTXOs that these
+	// addresses can spend together are consumed.
+	Signers []string `json:"signers"`
+}
+
+// CreateUnsignedTxReply defines the CreateUnsignedTx replies returned from the
+// API
+type CreateUnsignedTxReply struct {
+	Tx formatting.CB58 `json:"tx"`
+}
+
+// CreateUnsignedTx returns a transaction that sends [Amount] of the asset to
+// the [To] address, spending UTXOs that require signatures from [Signers].
+// The tx fee is paid from AVA that [Signers] can spend. Change is returned to
+// the owners of the first UTXO spent of each asset, so funds held by a multisig
+// keep being held by it.
+//
+// The transaction's credentials contain an empty signature for each signature
+// the inputs require. The transaction is passed to each signer, who fills in
+// their signatures with AddSignature, and issued with IssueSignedTx once
+// every signature is present.
+func (service *Service) CreateUnsignedTx(_ *http.Request, args *CreateUnsignedTxArgs, reply *CreateUnsignedTxReply) error {
+	service.vm.ctx.Log.Verbo("CreateUnsignedTx called")
+
+	if args.Amount == 0 {
+		return errInvalidAmount
+	}
+	if len(args.Memo.Bytes) > maxMemoSize {
+		return errMemoTooLarge
+	}
+
+	assetID, err := service.vm.Lookup(args.AssetID)
+	if err != nil {
+		assetID, err = ids.FromString(args.AssetID)
+		if err != nil {
+			return fmt.Errorf("asset '%s' not found", args.AssetID)
+		}
+	}
+
+	toBytes, err := service.vm.Parse(args.To)
+	if err != nil {
+		return fmt.Errorf("problem parsing to address '%s': %w", args.To, err)
+	}
+	to, err := ids.ToShortID(toBytes)
+	if err != nil {
+		return fmt.Errorf("problem parsing to address '%s': %w", args.To, err)
+	}
+
+	addrs := ids.Set{}
+	signers := ids.ShortSet{}
+	for _, signer := range args.Signers {
+		addrBytes, err := service.vm.Parse(signer)
+		if err != nil {
+			return fmt.Errorf("problem parsing signer address '%s': %w", signer, err)
+		}
+		addr, err := ids.ToShortID(addrBytes)
+		if err != nil {
+			return fmt.Errorf("problem parsing signer address '%s': %w", signer, err)
+		}
+		addrs.Add(ids.NewID(hashing.ComputeHash256Array(addrBytes)))
+		signers.Add(addr)
+	}
+
+	utxos, err := service.vm.GetUTXOs(addrs)
+	if err != nil {
+		return fmt.Errorf("problem getting signers' UTXOs: %w", err)
+	}
+
+	amounts := map[[32]byte]uint64{assetID.Key(): uint64(args.Amount)}
+	if err := service.addFee(amounts); err != nil {
+		return err
+	}
+	amountsSpent := map[[32]byte]uint64{}
+	time := service.vm.clock.Unix()
+
+	ins := []*TransferableInput{}
+	changeOwners := map[[32]byte]*secp256k1fx.OutputOwners{}
+	for _, utxo := range utxos {
+		utxoAssetID := utxo.AssetID()
+		assetKey := utxoAssetID.Key()
+		amount, ok := amounts[assetKey]
+		if !ok || amountsSpent[assetKey] >= amount {
+			continue
+		}
+		out, ok := utxo.Out.(*secp256k1fx.TransferOutput)
+		if !ok || out.Locktime > time {
+			continue
+		}
+		sigs := []uint32{}
+		for i := uint32(0); i < uint32(len(out.Addrs)) && uint32(len(sigs)) < out.Threshold; i++ {
+			if signers.Contains(out.Addrs[i]) {
+				sigs = append(sigs, i)
+			}
+		}
+		if uint32(len(sigs)) != out.Threshold {
+			continue
+		}
+
+		spent, err := math.Add64(amountsSpent[assetKey], out.Amt)
+		if err != nil {
+			return errSpendOverflow
+		}
+		amountsSpent[assetKey] = spent
+
+		ins = append(ins, &TransferableInput{
+			UTXOID: utxo.UTXOID,
+			Asset:  Asset{ID: utxoAssetID},
+			In: &secp256k1fx.TransferInput{
+				Amt: out.Amt,
+				Input: secp256k1fx.Input{
+					SigIndices: sigs,
+				},
+			},
+		})
+		if _, ok := changeOwners[assetKey]; !ok {
+			changeOwners[assetKey] = &out.OutputOwners
+		}
+	}
+
+	for assetKey, amount := range amounts {
+		if amountsSpent[assetKey] < amount {
+			return errInsufficientFunds
+		}
+	}
+
+	SortTransferableInputs(ins)
+
+	outs := []*TransferableOutput{
+		&TransferableOutput{
+			Asset: Asset{
+				ID: assetID,
+			},
+			Out: &secp256k1fx.TransferOutput{
+				Amt:      uint64(args.Amount),
+				Locktime: 0,
+				OutputOwners: secp256k1fx.OutputOwners{
+					Threshold: 1,
+					Addrs:     []ids.ShortID{to},
+				},
+			},
+		},
+	}
+
+	// Change is sent back to the owners of the first UTXO spent of each asset
+	for assetKey, spent := range amountsSpent {
+		if spent <= amounts[assetKey] {
+			continue
+		}
+		owners := changeOwners[assetKey]
+		outs = append(outs,
+			&TransferableOutput{
+				Asset: Asset{
+					ID: ids.NewID(assetKey),
+				},
+				Out: &secp256k1fx.TransferOutput{
+					Amt:      spent - amounts[assetKey],
+					Locktime: 0,
+					OutputOwners: secp256k1fx.OutputOwners{
+						Threshold: owners.Threshold,
+						Addrs:     owners.Addrs,
+					},
+				},
+			},
+		)
+	}
+
+	SortTransferableOutputs(outs, service.vm.codec)
+
+	tx := Tx{
+		UnsignedTx: WithMemo(&BaseTx{
+			NetID: service.vm.ctx.NetworkID,
+			BCID:  service.vm.ctx.ChainID,
+			Outs:  outs,
+			Ins:   ins,
+		}, args.Memo.Bytes),
+	}
+	for _, in := range ins {
+		input := in.In.(*secp256k1fx.TransferInput)
+		tx.Creds = append(tx.Creds, &Credential{
+			Cred: &secp256k1fx.Credential{
+				Sigs: make([][crypto.SECP256K1RSigLen]byte, len(input.SigIndices)),
+			},
+		})
+	}
+
+	txBytes, err := service.vm.codec.Marshal(&tx)
+	if err != nil {
+		return fmt.Errorf("problem creating transaction: %w", err)
+	}
+	reply.Tx.Bytes = txBytes
+	return nil
+}
+
+// AddSignatureArgs are arguments for passing into AddSignature requests
+type AddSignatureArgs struct {
+	Username string          `json:"username"`
+	Password string          `json:"password"`
+	Signer   string          `json:"signer"`
+	Tx       formatting.CB58 `json:"tx"`
+}
+
+// AddSignatureReply defines the AddSignature replies returned from the API
+type AddSignatureReply struct {
+	Tx formatting.CB58 `json:"tx"`
+
+	// Number of signatures the transaction still needs before it can be issued
+	MissingSignatures json.Uint32 `json:"missingSignatures"`
+}
+
+// AddSignature signs each input of the transaction that requires a signature
+// from the [Signer] address, using the user's key for the address, and returns
+// the transaction with the signatures added
+func (service *Service) AddSignature(_ *http.Request, args *AddSignatureArgs, reply *AddSignatureReply) error {
+	service.vm.ctx.Log.Verbo("AddSignature called with username: %s", args.Username)
+
+	signer, err := service.vm.Parse(args.Signer)
+	if err != nil {
+		return fmt.Errorf("problem parsing address '%s': %w", args.Signer, err)
+	}
+
+	db, err := service.vm.ctx.Keystore.GetDatabase(args.Username, args.Password)
+	if err != nil {
+		return fmt.Errorf("problem retrieving user: %w", err)
+	}
+
+	user := userState{vm: service.vm}
+
+	addr := ids.NewID(hashing.ComputeHash256Array(signer))
+	sk, err := user.Key(db, addr)
+	if err != nil {
+		return fmt.Errorf("problem retriving private key: %w", err)
+	}
+
+	tx := Tx{}
+	if err := service.vm.codec.Unmarshal(args.Tx.Bytes, &tx); err != nil {
+		return fmt.Errorf("problem parsing transaction: %w", err)
+	}
+	if tx.UnsignedTx == nil {
+		return errNilTx
+	}
+	if len(tx.Creds) != len(tx.InputUTXOs()) {
+		return errWrongNumberOfCredentials
+	}
+
+	unsignedBytes, err := service.vm.codec.Marshal(&tx.UnsignedTx)
+	if err != nil {
+		return fmt.Errorf("problem creating transaction: %w", err)
+	}
+	sig, err := sk.Sign(unsignedBytes)
+	if err != nil {
+		return fmt.Errorf("problem signing transaction: %w", err)
+	}
+
+	signed := false
+	for i, in := range tx.Inputs() {
+		input, ok := in.In.(*secp256k1fx.TransferInput)
+		if !ok {
+			continue
+		}
+		utxo, err := service.vm.state.UTXO(in.InputID())
+		if err != nil {
+			return errUnknownUTXO
+		}
+		out, ok := utxo.Out.(*secp256k1fx.TransferOutput)
+		if !ok {
+			continue
+		}
+		cred, ok := tx.Creds[i].Cred.(*secp256k1fx.Credential)
+		if !ok {
+			return errUnknownCredentialType
+		}
+		if len(cred.Sigs) != len(input.SigIndices) {
+			cred.Sigs = make([][crypto.SECP256K1RSigLen]byte, len(input.SigIndices))
+		}
+		for j, sigIndex := range input.SigIndices {
+			if sigIndex < uint32(len(out.Addrs)) && bytes.Equal(out.Addrs[sigIndex].Bytes(), signer) {
+				copy(cred.Sigs[j][:], sig)
+				signed = true
+			}
+		}
+	}
+	if !signed {
+		return errUnneededAddress
+	}
+
+	txBytes, err := service.vm.codec.Marshal(&tx)
+	if err != nil {
+		return fmt.Errorf("problem creating transaction: %w", err)
+	}
+	reply.Tx.Bytes = txBytes
+	reply.MissingSignatures = json.Uint32(missingSignatures(&tx))
+	return nil
+}
+
+// IssueSignedTxArgs are arguments for passing into IssueSignedTx requests
+type IssueSignedTxArgs struct {
+	Tx formatting.CB58 `json:"tx"`
+}
+
+// IssueSignedTxReply defines the IssueSignedTx replies returned from the API
+type IssueSignedTxReply struct {
+	TxID ids.ID `json:"txID"`
+}
+
+// IssueSignedTx issues a transaction signed with AddSignature. Unlike IssueTx,
+// it reports how many signatures are missing if the transaction isn't fully
+// signed yet.
+func (service *Service) IssueSignedTx(_ *http.Request, args *IssueSignedTxArgs, reply *IssueSignedTxReply) error {
+	service.vm.ctx.Log.Verbo("IssueSignedTx called with %s", args.Tx)
+
+	tx := Tx{}
+	if err := service.vm.codec.Unmarshal(args.Tx.Bytes, &tx); err != nil {
+		return fmt.Errorf("problem parsing transaction: %w", err)
+	}
+	if missing := missingSignatures(&tx); missing != 0 {
+		return fmt.Errorf("%w: %d missing", errMissingSignatures, missing)
+	}
+
+	txID, err := service.vm.IssueTx(args.Tx.Bytes, nil)
+	if err != nil {
+		return fmt.Errorf("problem issuing transaction: %w", err)
+	}
+
+	reply.TxID = txID
+	return nil
+}
+
+// missingSignatures returns the number of empty signatures in the credentials
+// of [tx]
+func missingSignatures(tx *Tx) int {
+	missing := 0
+	for _, cred := range tx.Creds {
+		cred, ok := cred.Cred.(*secp256k1fx.Credential)
+		if !ok {
+			continue
+		}
+		for _, sig := range cred.Sigs {
+			if sig == [crypto.SECP256K1RSigLen]byte{} {
+				missing++
+			}
+		}
+	}
+	return missing
+}
+
+// ExportAVAArgs are arguments for passing into ExportAVA requests
+type ExportAVAArgs struct {
+	Username string      `json:"username"`
+	Password string      `json:"password"`
+	Amount   json.Uint64 `json:"amount"`
+
+	// Platform Chain account the exported AVA is sent to
+	To ids.ShortID `json:"to"`
+}
+
+// ExportAVAReply defines the ExportAVA replies returned from the API
+type ExportAVAReply struct {
+	TxID ids.ID `json:"txID"`
+}
+
+// ExportAVA sends AVA from the user's addresses to the Platform Chain account
+// [To]. Once this transaction is accepted, the AVA must be imported on the
+// Platform Chain.
+func (service *Service) ExportAVA(_ *http.Request, args *ExportAVAArgs, reply *ExportAVAReply) error {
+	service.vm.ctx.Log.Verbo("ExportAVA called with username: %s", args.Username)
+
+	if args.Amount == 0 {
+		return errInvalidAmount
+	}
+
+	db, err := service.vm.ctx.Keystore.GetDatabase(args.Username, args.Password)
+	if err != nil {
+		return fmt.Errorf("problem retrieving user: %w", err)
+	}
+
+	user := userState{vm: service.vm}
+
+	addresses, _ := user.Addresses(db)
+
+	addrs := ids.Set{}
+	addrs.Add(addresses...)
+	utxos, err := service.vm.GetUTXOs(addrs)
+	if err != nil {
+		return fmt.Errorf("problem retrieving user's UTXOs: %w", err)
+	}
+
+	kc := secp256k1fx.NewKeychain()
+	for _, addr := range addresses {
+		sk, err := user.Key(db, addr)
+		if err != nil {
+			return fmt.Errorf("problem retrieving private key: %w", err)
+		}
+		kc.Add(sk)
+	}
+
+	amounts := map[[32]byte]uint64{service.vm.ava.Key(): uint64(args.Amount)}
+	if err := service.addFee(amounts); err != nil {
+		return err
+	}
+	ins, keys, amountsSpent, err := service.spend(utxos, kc, amounts)
+	if err != nil {
+		return err
+	}
+
+	exportOuts := []*TransferableOutput{&TransferableOutput{
+		Asset: Asset{ID: service.vm.ava},
+		Out: &secp256k1fx.TransferOutput{
+			Amt: uint64(args.Amount),
+			OutputOwners: secp256k1fx.OutputOwners{
+				Threshold: 1,
+				Addrs:     []ids.ShortID{args.To},
+			},
+		},
+	}}
+
+	outs := changeOutputs(amountsSpent, amounts, kc.Keys[0].PublicKey().Address())
+
+	tx := Tx{UnsignedTx: &ExportTx{
+		BaseTx: BaseTx{
+			NetID: service.vm.ctx.NetworkID,
+			BCID:  service.vm.ctx.ChainID,
+			Outs:  outs,
+			Ins:   ins,
+		},
+		ExportedOuts: exportOuts,
+	}}
+
+	b, err := service.signTx(&tx, keys)
+	if err != nil {
+		return fmt.Errorf("problem creating transaction: %w", err)
+	}
+
+	txID, err := service.vm.IssueTx(b, nil)
+	if err != nil {
+		return fmt.Errorf("problem issuing transaction: %w", err)
+	}
+
+	reply.TxID = txID
+	return nil
+}
+
+// ImportAVAArgs are arguments for passing into ImportAVA requests
+type ImportAVAArgs struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+
+	// Address the imported AVA is sent to
+	To string `json:"to"`
+}
+
+// ImportAVAReply defines the ImportAVA replies returned from the API
+type ImportAVAReply struct {
+	TxID ids.ID `json:"txID"`
+}
+
+// ImportAVA imports all the AVA the Platform Chain sent to the user's
+// addresses, and sends it, less the tx fee, to [To]
+func (service *Service) ImportAVA(_ *http.Request, args *ImportAVAArgs, reply *ImportAVAReply) error {
+	service.vm.ctx.Log.Verbo("ImportAVA called with username: %s", args.Username)
+
+	to, err := service.parseToAddress(args.Username, args.Password, args.To)
+	if err != nil {
+		return err
+	}
+
+	db, err := service.vm.ctx.Keystore.GetDatabase(args.Username, args.Password)
+	if err != nil {
+		return fmt.Errorf("problem retrieving user: %w", err)
+	}
+
+	user := userState{vm: service.vm}
+
+	addresses, _ := user.Addresses(db)
+
+	kc := secp256k1fx.NewKeychain()
+	addrs := ids.ShortSet{}
+	for _, addr := range addresses {
+		sk, err := user.Key(db, addr)
+		if err != nil {
+			return fmt.Errorf("problem retrieving private key: %w", err)
+		}
+		kc.Add(sk)
+		addrs.Add(sk.PublicKey().Address())
+	}
+
+	smDB := service.vm.ctx.SharedMemory.GetDatabase(service.vm.platform)
+	utxos, err := NewAtomicUTXOs(smDB).List(service.vm.ctx.ChainID, addrs)
+	service.vm.ctx.SharedMemory.ReleaseDatabase(service.vm.platform)
+	if err != nil {
+		return fmt.Errorf("problem retrieving user's imported UTXOs: %w", err)
+	}
+
+	amount := uint64(0)
+	time := service.vm.clock.Unix()
+
+	ins := []*TransferableInput{}
+	keys := [][]*crypto.PrivateKeySECP256K1R{}
+	for _, utxo := range utxos {
+		if !utxo.AssetID().Equals(service.vm.ava) {
+			continue
+		}
+		inputIntf, signers, err := kc.Spend(utxo.Out, time)
+		if err != nil {
+			continue
+		}
+		input, ok := inputIntf.(FxTransferable)
+		if !ok {
+			continue
+		}
+		newAmount, err := math.Add64(amount, input.Amount())
+		if err != nil {
+			return errSpendOverflow
+		}
+		amount = newAmount
+
+		ins = append(ins, &TransferableInput{
+			UTXOID: utxo.UTXOID,
+			Asset:  Asset{ID: service.vm.ava},
+			In:     input,
+		})
+		keys = append(keys, signers)
+	}
+
+	if amount == 0 {
+		return errNoImportInputs
+	}
+	if amount <= service.vm.txFee {
+		return errInsufficientFee
+	}
+
+	SortTransferableInputsWithSigners(ins, keys)
+
+	tx := Tx{UnsignedTx: &ImportTx{
+		BaseTx: BaseTx{
+			NetID: service.vm.ctx.NetworkID,
+			BCID:  service.vm.ctx.ChainID,
+			Outs: []*TransferableOutput{&TransferableOutput{
+				Asset: Asset{ID: service.vm.ava},
+				Out: &secp256k1fx.TransferOutput{
+					Amt: amount - service.vm.txFee,
+					OutputOwners: secp256k1fx.OutputOwners{
+						Threshold: 1,
+						Addrs:     []ids.ShortID{to},
+					},
+				},
+			}},
+		},
+		ImportedIns: ins,
+	}}
+
+	b, err := service.signTx(&tx, keys)
+	if err != nil {
+		return fmt.Errorf("problem creating transaction: %w", err)
+	}
+
+	txID, err := service.vm.IssueTx(b, nil)
+	if err != nil {
+		return fmt.Errorf("problem issuing transaction: %w", err)
+	}
+
+	reply.TxID = txID
+	return nil
+}
+
+// signTx adds a credential to [tx] for each set of [keys], in order, and
+// returns the bytes of the signed transaction
+func (service *Service) signTx(tx *Tx, keys [][]*crypto.PrivateKeySECP256K1R) ([]byte, error) {
+	unsignedBytes, err := service.vm.codec.Marshal(&tx.UnsignedTx)
+	if err != nil {
+		return nil, err
+	}
+	hash := hashing.ComputeHash256(unsignedBytes)
+
+	for _, credKeys := range keys {
+		sigs, err := signatures(hash, credKeys)
+		if err != nil {
+			return nil, err
+		}
+		tx.Creds = append(tx.Creds, &Credential{Cred: &secp256k1fx.Credential{Sigs: sigs}})
+	}
+
+	return service.vm.codec.Marshal(tx)
+}
+
+// signatures returns the signatures of [hash] by each of [keys]
+func signatures(hash []byte, keys []*crypto.PrivateKeySECP256K1R) ([][crypto.SECP256K1RSigLen]byte, error) {
+	sigs := [][crypto.SECP256K1RSigLen]byte{}
+	for _, key := range keys {
+		sig, err := key.SignHash(hash)
+		if err != nil {
+			return nil, err
+		}
+		fixedSig := [crypto.SECP256K1RSigLen]byte{}
+		copy(fixedSig[:], sig)
+
+		sigs = append(sigs, fixedSig)
+	}
+	return sigs, nil
+}
+
+// addFee adds the tx fee to the amount of AVA in [amounts]
+func (service *Service) addFee(amounts map[[32]byte]uint64) error {
+	if service.vm.txFee == 0 {
+		return nil
+	}
+	avaKey := service.vm.ava.Key()
+	amount, err := math.Add64(amounts[avaKey], service.vm.txFee)
+	if err != nil {
+		return errSpendOverflow
+	}
+	amounts[avaKey] = amount
+	return nil
+}
+
+// spend returns inputs that consume at least [amounts] of each asset from the
+// [utxos] that [kc] can spend, sorted along with the keys that sign each of
+// them. It also returns the amount of each asset the inputs consume.
+func (service *Service) spend(
+	utxos []*UTXO,
+	kc *secp256k1fx.Keychain,
+	amounts map[[32]byte]uint64,
+) ([]*TransferableInput, [][]*crypto.PrivateKeySECP256K1R, map[[32]byte]uint64, error) {
+	amountsSpent := make(map[[32]byte]uint64, len(amounts))
+	time := service.vm.clock.Unix()
+
+	ins := []*TransferableInput{}
+	keys := [][]*crypto.PrivateKeySECP256K1R{}
+	for _, utxo := range utxos {
+		assetID := utxo.AssetID()
+		assetKey := assetID.Key()
+		amount, ok := amounts[assetKey]
+		if !ok || amountsSpent[assetKey] >= amount {
+			continue
+		}
+		inputIntf, signers, err := kc.Spend(utxo.Out, time)
+		if err != nil {
+			continue
+		}
+		input, ok := inputIntf.(FxTransferable)
+		if !ok {
+			continue
+		}
+		spent, err := math.Add64(amountsSpent[assetKey], input.Amount())
+		if err != nil {
+			return nil, nil, nil, errSpendOverflow
+		}
+		amountsSpent[assetKey] = spent
+
+		ins = append(ins, &TransferableInput{
+			UTXOID: utxo.UTXOID,
+			Asset:  Asset{ID: assetID},
+			In:     input,
+		})
+		keys = append(keys, signers)
+	}
+
+	for assetKey, amount := range amounts {
+		if amountsSpent[assetKey] < amount {
+			return nil, nil, nil, errInsufficientFunds
+		}
+	}
+
+	SortTransferableInputsWithSigners(ins, keys)
+	return ins, keys, amountsSpent, nil
+}
+
+// changeOutputs returns outputs that send [addr] the amount of each asset in
+// [amountsSpent] that exceeds the amount in [amounts]
+func changeOutputs(amountsSpent, amounts map[[32]byte]uint64, addr ids.ShortID) []*TransferableOutput {
+	outs := []*TransferableOutput{}
+	for assetKey, spent := range amountsSpent {
+		if spent <= amounts[assetKey] {
+			continue
+		}
+		outs = append(outs, &TransferableOutput{
+			Asset: Asset{ID: ids.NewID(assetKey)},
+			Out: &secp256k1fx.TransferOutput{
+				Amt: spent - amounts[assetKey],
+				OutputOwners: secp256k1fx.OutputOwners{
+					Threshold: 1,
+					Addrs:     []ids.ShortID{addr},
+				},
+			},
+		})
+	}
+	return outs
+}
+
+// feeChain pays the tx fees of a sequence of transactions that are all built
+// before any of them is issued, so they can't spend the same AVA. The first
+// transaction spends enough of the user's AVA to pay every fee, and sends what
+// is left over to [key]. Each later transaction spends the AVA that the one
+// before it sent to [key].
+type feeChain struct {
+	vm  *VM
+	key *crypto.PrivateKeySECP256K1R
+
+	// The inputs that pay the next transaction's fee, the keys that sign each
+	// of them, and the amount of AVA they consume
+	ins    []*TransferableInput
+	keys   [][]*crypto.PrivateKeySECP256K1R
+	amount uint64
+
+	// The output of the last transaction that the next one spends, if any
+	change *TransferableOutput
+}
+
+// newFeeChain returns a feeChain that pays the fees of [numTxs] transactions
+// with the AVA in [utxos] that [kc] can spend, sending the AVA left over to
+// [key]. If there is no tx fee, the chain adds nothing to the transactions.
+func (service *Service) newFeeChain(
+	utxos []*UTXO,
+	kc *secp256k1fx.Keychain,
+	key *crypto.PrivateKeySECP256K1R,
+	numTxs int,
+) (*feeChain, error) {
+	fees := &feeChain{
+		vm:  service.vm,
+		key: key,
+	}
+	if service.vm.txFee == 0 || numTxs == 0 {
+		return fees, nil
+	}
+
+	amount, err := math.Mul64(service.vm.txFee, uint64(numTxs))
+	if err != nil {
+		return nil, errSpendOverflow
+	}
+	avaKey := service.vm.ava.Key()
+	ins, keys, amountsSpent, err := service.spend(utxos, kc, map[[32]byte]uint64{avaKey: amount})
+	if err != nil {
+		return nil, err
+	}
+	fees.ins = ins
+	fees.keys = keys
+	fees.amount = amountsSpent[avaKey]
+	return fees, nil
+}
+
+// userFeeChain returns a feeChain that pays the fees of [numTxs] transactions
+// with the AVA of the user [username]. If [key] is nil, the AVA left over is
+// sent to the user's first address. The user is only looked up if there is a
+// tx fee.
+func (service *Service) userFeeChain(username, password string, key *crypto.PrivateKeySECP256K1R, numTxs int) (*feeChain, error) {
+	if service.vm.txFee == 0 || numTxs == 0 {
+		return service.newFeeChain(nil, nil, key, numTxs)
+	}
+
+	kc, utxos, err := service.userAssetUTXOs(username, password, service.vm.ava)
+	if err != nil {
+		return nil, err
+	}
+	if key == nil {
+		if len(kc.Keys) == 0 {
+			return nil, errInsufficientFunds
+		}
+		key = kc.Keys[0]
+	}
+	return service.newFeeChain(utxos, kc, key, numTxs)
+}
+
+// next returns the inputs that pay the next transaction's fee, sorted along
+// with the keys that sign each of them, and the outputs that return the AVA
+// left over. Once the transaction is signed, built must be called before next
+// is called again.
+func (fees *feeChain) next() ([]*TransferableInput, [][]*crypto.PrivateKeySECP256K1R, []*TransferableOutput) {
+	fees.change = nil
+	if len(fees.ins) == 0 {
+		return nil, nil, nil
+	}
+
+	outs := []*TransferableOutput{}
+	if fees.amount > fees.vm.txFee {
+		fees.change = &TransferableOutput{
+			Asset: Asset{ID: fees.vm.ava},
+			Out: &secp256k1fx.TransferOutput{
+				Amt: fees.amount - fees.vm.txFee,
+				OutputOwners: secp256k1fx.OutputOwners{
+					Threshold: 1,
+					Addrs:     []ids.ShortID{fees.key.PublicKey().Address()},
+				},
+			},
+		}
+		outs = append(outs, fees.change)
+	}
+	return fees.ins, fees.keys, outs
+}
+
+// built records that the signed transaction [b], whose base outputs are
+// [outs], pays its fee with the inputs last returned by next
+func (fees *feeChain) built(b []byte, outs []*TransferableOutput) {
+	fees.ins = nil
+	fees.keys = nil
+	fees.amount = 0
+	if fees.change == nil {
+		return
+	}
+
+	txID := ids.NewID(hashing.ComputeHash256Array(b))
+	for i, out := range outs {
+		if out != fees.change {
+			continue
+		}
+		fees.amount = out.Output().Amount()
+		fees.ins = []*TransferableInput{&TransferableInput{
+			UTXOID: UTXOID{
+				TxID:        txID,
+				OutputIndex: uint32(i),
+			},
+			Asset: Asset{ID: fees.vm.ava},
+			In: &secp256k1fx.TransferInput{
+				Amt: fees.amount,
+				Input: secp256k1fx.Input{
+					SigIndices: []uint32{0},
+				},
+			},
+		}}
+		fees.keys = [][]*crypto.PrivateKeySECP256K1R{{fees.key}}
+	}
+}
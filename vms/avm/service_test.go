@@ -4,14 +4,57 @@
 package avm
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"testing"
+	"time"
 
+	"github.com/ava-labs/gecko/api/keystore"
 	"github.com/ava-labs/gecko/database/memdb"
 	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow/choices"
 	"github.com/ava-labs/gecko/snow/engine/common"
+	"github.com/ava-labs/gecko/utils/crypto"
+	"github.com/ava-labs/gecko/utils/formatting"
+	"github.com/ava-labs/gecko/utils/hashing"
+	"github.com/ava-labs/gecko/utils/json"
+	"github.com/ava-labs/gecko/utils/logging"
+	"github.com/ava-labs/gecko/vms/components/verify"
+	"github.com/ava-labs/gecko/vms/managedfx"
+	"github.com/ava-labs/gecko/vms/nftfx"
 	"github.com/ava-labs/gecko/vms/secp256k1fx"
 )
 
+var (
+	// strongPassword scores high enough to pass the keystore's password
+	// strength check
+	strongPassword = "N_+=_jJ;^(<;{4,:*m6CET}'&N;83FYK.wtNpwp-Jt"
+)
+
+// setupUser creates a user named [username] in a new keystore assigned to the
+// vm, and imports keys[keyIndex] into the user. Returns the keystore.
+func setupUser(t *testing.T, s *Service, username string, keyIndex int) *keystore.Keystore {
+	ks := &keystore.Keystore{}
+	ks.Initialize(logging.NoLog{}, memdb.New())
+	if err := ks.CreateUser(nil, &keystore.CreateUserArgs{
+		Username: username,
+		Password: strongPassword,
+	}, &keystore.CreateUserReply{}); err != nil {
+		t.Fatal(err)
+	}
+	s.vm.ctx.Keystore = ks.NewBlockchainKeyStore(s.vm.ctx.ChainID)
+
+	if err := s.ImportKey(nil, &ImportKeyArgs{
+		Username:   username,
+		Password:   strongPassword,
+		PrivateKey: formatting.CB58{Bytes: keys[keyIndex].Bytes()}.String(),
+	}, &ImportKeyReply{}); err != nil {
+		t.Fatal(err)
+	}
+	return ks
+}
+
 func TestGetAssetDescription(t *testing.T) {
 	genesisBytes := BuildGenesisTest(t)
 
@@ -141,6 +184,77 @@ func TestCreateFixedCapAsset(t *testing.T) {
 	}
 }
 
+func TestCreateFixedCapAssetVesting(t *testing.T) {
+	vm := GenesisVM(t)
+	ctx.Lock.Lock()
+	defer func() {
+		ctx.Lock.Unlock()
+		vm.Shutdown()
+	}()
+
+	s := Service{vm: vm}
+
+	reply := CreateFixedCapAssetReply{}
+	if err := s.CreateFixedCapAsset(nil, &CreateFixedCapAssetArgs{
+		Name:   "vesting asset",
+		Symbol: "vest",
+		InitialHolders: []*Holder{&Holder{
+			Amount:  300,
+			Address: vm.Format(keys[0].PublicKey().Address().Bytes()),
+			Vesting: []VestingPeriod{
+				{Amount: 100, Locktime: 1000},
+				{Amount: 200, Locktime: 2000},
+			},
+		}},
+	}, &reply); err != nil {
+		t.Fatal(err)
+	}
+
+	tx := UniqueTx{vm: vm, txID: reply.AssetID}
+	utxos := tx.UTXOs()
+	if len(utxos) != 2 {
+		t.Fatalf("Should have created %d UTXOs, created %d", 2, len(utxos))
+	}
+	unlocked := map[uint64]uint64{}
+	for _, utxo := range utxos {
+		out, ok := utxo.Out.(*secp256k1fx.TransferOutput)
+		if !ok {
+			t.Fatalf("Wrong output type %T", utxo.Out)
+		}
+		unlocked[out.Locktime] += out.Amt
+	}
+	if unlocked[1000] != 100 || unlocked[2000] != 200 {
+		t.Fatalf("Wrong vesting schedule: %v", unlocked)
+	}
+}
+
+func TestCreateFixedCapAssetVestingUnsorted(t *testing.T) {
+	vm := GenesisVM(t)
+	ctx.Lock.Lock()
+	defer func() {
+		ctx.Lock.Unlock()
+		vm.Shutdown()
+	}()
+
+	s := Service{vm: vm}
+
+	err := s.CreateFixedCapAsset(nil, &CreateFixedCapAssetArgs{
+		Name:   "vesting asset",
+		Symbol: "vest",
+		InitialHolders: []*Holder{&Holder{
+			Amount:  300,
+			Address: vm.Format(keys[0].PublicKey().Address().Bytes()),
+			Vesting: []VestingPeriod{
+				{Amount: 200, Locktime: 2000},
+				{Amount: 100, Locktime: 1000},
+			},
+		}},
+	}, &CreateFixedCapAssetReply{})
+	if !errors.Is(err, errVestingUnsorted) {
+		t.Fatalf("Expected %s but got %v", errVestingUnsorted, err)
+	}
+}
+
 func TestCreateVariableCapAsset(t *testing.T) {
 	genesisBytes := BuildGenesisTest(t)
 
@@ -186,3 +300,1956 @@ func TestCreateVariableCapAsset(t *testing.T) {
 		t.Fatalf("Wrong assetID returned from CreateFixedCapAsset %s", reply.AssetID)
 	}
 }
+
+func TestCreateFixedCapAssetManyHolders(t *testing.T) {
+	vm := GenesisVM(t)
+	ctx.Lock.Lock()
+	defer func() {
+		// Building the transactions may outlast the batch timeout, in which
+		// case the VM's timer is waiting on the lock
+		ctx.Lock.Unlock()
+		vm.Shutdown()
+		ctx.Keystore = nil
+	}()
+
+	s := Service{vm: vm}
+	setupUser(t, &s, "bob", 0)
+
+	holders := []*Holder(nil)
+	for i := 0; i < 2*maxOutputsPerTx+1; i++ {
+		addr := ids.NewShortID([20]byte{byte(i), byte(i >> 8)})
+		holders = append(holders, &Holder{
+			Amount:   json.Uint64(i + 1),
+			Address:  vm.Format(addr.Bytes()),
+			Locktime: json.Uint64(i % 3),
+		})
+	}
+
+	reply := CreateFixedCapAssetReply{}
+	if err := s.CreateFixedCapAsset(nil, &CreateFixedCapAssetArgs{
+		Username:       "bob",
+		Password:       strongPassword,
+		Name:           "test asset",
+		Symbol:         "test",
+		Denomination:   1,
+		InitialHolders: holders,
+	}, &reply); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(reply.TxIDs) != 3 {
+		t.Fatalf("The holders should have been split across %d transactions, were split across %d", 3, len(reply.TxIDs))
+	}
+	if !reply.AssetID.Equals(reply.TxIDs[0]) {
+		t.Fatalf("The first transaction should have created the asset")
+	}
+
+	// Every holder should receive exactly one output
+	received := map[[20]byte]uint64{}
+	for _, txID := range reply.TxIDs {
+		tx := UniqueTx{
+			vm:   vm,
+			txID: txID,
+		}
+		if err := tx.Verify(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		for _, utxo := range tx.UTXOs() {
+			out, ok := utxo.Out.(*secp256k1fx.TransferOutput)
+			if !ok || !utxo.AssetID().Equals(reply.AssetID) {
+				continue
+			}
+			received[out.Addrs[0].Key()] += out.Amt
+		}
+	}
+	for i, holder := range holders {
+		addr := ids.NewShortID([20]byte{byte(i), byte(i >> 8)})
+		if amount := received[addr.Key()]; amount != uint64(holder.Amount) {
+			t.Fatalf("Holder %d should have received %d, received %d", i, holder.Amount, amount)
+		}
+	}
+
+	lastTx := UniqueTx{
+		vm:   vm,
+		txID: reply.TxIDs[len(reply.TxIDs)-1],
+	}
+	for _, utxo := range lastTx.UTXOs() {
+		if out, ok := utxo.Out.(*secp256k1fx.TransferOutput); ok && out.Addrs[0].Equals(keys[0].PublicKey().Address()) {
+			t.Fatalf("The whole supply should have been distributed, %d remains", out.Amt)
+		}
+	}
+}
+
+func TestMint(t *testing.T) {
+	vm := GenesisVM(t)
+	ctx.Lock.Lock()
+	defer func() {
+		ctx.Lock.Unlock()
+		vm.Shutdown()
+		ctx.Keystore = nil
+	}()
+
+	s := Service{vm: vm}
+	setupUser(t, &s, "bob", 0)
+
+	to := vm.Format(keys[2].PublicKey().Address().Bytes())
+
+	reply := MintReply{}
+	if err := s.Mint(nil, &MintArgs{
+		Username: "bob",
+		Password: strongPassword,
+		Amount:   1000,
+		AssetID:  "asset3",
+		To:       to,
+	}, &reply); err != nil {
+		t.Fatal(err)
+	}
+
+	statusReply := GetTxStatusReply{}
+	if err := s.GetTxStatus(nil, &GetTxStatusArgs{TxID: reply.TxID}, &statusReply); err != nil {
+		t.Fatal(err)
+	}
+	if statusReply.Status != choices.Processing {
+		t.Fatalf("Minting transaction should have been issued, status: %s", statusReply.Status)
+	}
+
+	tx := UniqueTx{
+		vm:   vm,
+		txID: reply.TxID,
+	}
+	utxos := tx.UTXOs()
+	if len(utxos) != 2 {
+		t.Fatalf("Minting transaction should have produced 2 UTXOs, produced %d", len(utxos))
+	}
+	minted := false
+	for _, utxo := range utxos {
+		out, ok := utxo.Out.(*secp256k1fx.TransferOutput)
+		if !ok {
+			continue
+		}
+		if out.Amt != 1000 || !out.Addrs[0].Equals(keys[2].PublicKey().Address()) {
+			t.Fatalf("Wrong minted output")
+		}
+		minted = true
+	}
+	if !minted {
+		t.Fatalf("Minting transaction should have produced a transfer output")
+	}
+}
+
+func TestGetTx(t *testing.T) {
+	vm := GenesisVM(t)
+	ctx.Lock.Lock()
+	defer func() {
+		ctx.Lock.Unlock()
+		vm.Shutdown()
+		ctx.Keystore = nil
+	}()
+
+	s := Service{vm: vm}
+	setupUser(t, &s, "bob", 0)
+
+	memo := []byte("exchange deposit 1234")
+	reply := MintReply{}
+	if err := s.Mint(nil, &MintArgs{
+		Username: "bob",
+		Password: strongPassword,
+		Amount:   1000,
+		AssetID:  "asset3",
+		To:       vm.Format(keys[2].PublicKey().Address().Bytes()),
+		Memo:     formatting.CB58{Bytes: memo},
+	}, &reply); err != nil {
+		t.Fatal(err)
+	}
+
+	txReply := GetTxReply{}
+	if err := s.GetTx(nil, &GetTxArgs{TxID: reply.TxID}, &txReply); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(txReply.Memo.Bytes, memo) {
+		t.Fatalf("Wrong memo returned: %s", txReply.Memo.Bytes)
+	}
+
+	tx, err := vm.parseTx(context.Background(), txReply.Tx.Bytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !tx.ID().Equals(reply.TxID) {
+		t.Fatalf("Returned tx has ID %s but should have ID %s", tx.ID(), reply.TxID)
+	}
+}
+
+func TestGetTxUnknown(t *testing.T) {
+	vm := GenesisVM(t)
+	ctx.Lock.Lock()
+	defer func() {
+		ctx.Lock.Unlock()
+		vm.Shutdown()
+	}()
+
+	s := Service{vm: vm}
+	txReply := GetTxReply{}
+	if err := s.GetTx(nil, &GetTxArgs{TxID: ids.Empty.Prefix(1)}, &txReply); err != errUnknownTx {
+		t.Fatalf("Expected %s but got %v", errUnknownTx, err)
+	}
+}
+
+func TestGetTxStatusRejectedBy(t *testing.T) {
+	vm := GenesisVM(t)
+	ctx.Lock.Lock()
+	defer func() {
+		ctx.Lock.Unlock()
+		vm.Shutdown()
+		ctx.Keystore = nil
+	}()
+
+	s := Service{vm: vm}
+	setupUser(t, &s, "bob", 0)
+
+	reply := MintReply{}
+	if err := s.Mint(nil, &MintArgs{
+		Username: "bob",
+		Password: strongPassword,
+		Amount:   1000,
+		AssetID:  "asset3",
+		To:       vm.Format(keys[2].PublicKey().Address().Bytes()),
+	}, &reply); err != nil {
+		t.Fatal(err)
+	}
+
+	causeID := ids.Empty.Prefix(1)
+	tx := UniqueTx{
+		vm:   vm,
+		txID: reply.TxID,
+	}
+	tx.RejectedBy(causeID)
+	tx.Reject()
+
+	statusReply := GetTxStatusReply{}
+	if err := s.GetTxStatus(nil, &GetTxStatusArgs{TxID: reply.TxID}, &statusReply); err != nil {
+		t.Fatal(err)
+	}
+	if statusReply.Status != choices.Rejected {
+		t.Fatalf("Transaction should have been rejected, status: %s", statusReply.Status)
+	}
+	if !statusReply.RejectedBy.Equals(causeID) {
+		t.Fatalf("Expected rejection cause %s but got %s", causeID, statusReply.RejectedBy)
+	}
+}
+
+func TestGetTxStatusDoesntVerify(t *testing.T) {
+	vm := GenesisVM(t)
+	ctx.Lock.Lock()
+	defer func() {
+		ctx.Lock.Unlock()
+		vm.Shutdown()
+		ctx.Keystore = nil
+	}()
+
+	s := Service{vm: vm}
+	setupUser(t, &s, "bob", 0)
+
+	reply := MintReply{}
+	if err := s.Mint(nil, &MintArgs{
+		Username: "bob",
+		Password: strongPassword,
+		Amount:   1000,
+		AssetID:  "asset3",
+		To:       vm.Format(keys[2].PublicKey().Address().Bytes()),
+	}, &reply); err != nil {
+		t.Fatal(err)
+	}
+
+	// Forget that issuing the transaction verified it
+	tx := UniqueTx{
+		vm:   vm,
+		txID: reply.TxID,
+	}
+	tx.refresh()
+	tx.t.verifiedTx = false
+	tx.t.verifiedState = false
+	tx.t.validity = nil
+
+	statusReply := GetTxStatusReply{}
+	if err := s.GetTxStatus(nil, &GetTxStatusArgs{TxID: reply.TxID}, &statusReply); err != nil {
+		t.Fatal(err)
+	}
+	if statusReply.Reason != "" {
+		t.Fatalf("Unverified transaction shouldn't have a failure reason, has %q", statusReply.Reason)
+	}
+	if verified, _ := tx.verified(); verified {
+		t.Fatalf("GetTxStatus shouldn't have verified the transaction")
+	}
+
+	// Simulate the engine finding the transaction invalid
+	tx.t.verifiedState = true
+	tx.t.validity = errUnknownTx
+
+	statusReply = GetTxStatusReply{}
+	if err := s.GetTxStatus(nil, &GetTxStatusArgs{TxID: reply.TxID}, &statusReply); err != nil {
+		t.Fatal(err)
+	}
+	if statusReply.Reason != errUnknownTx.Error() {
+		t.Fatalf("Should have reported the recorded failure %q, reported %q", errUnknownTx, statusReply.Reason)
+	}
+}
+
+func TestMintMemoTooLarge(t *testing.T) {
+	vm := GenesisVM(t)
+	ctx.Lock.Lock()
+	defer func() {
+		ctx.Lock.Unlock()
+		vm.Shutdown()
+		ctx.Keystore = nil
+	}()
+
+	s := Service{vm: vm}
+	setupUser(t, &s, "bob", 0)
+
+	reply := MintReply{}
+	err := s.Mint(nil, &MintArgs{
+		Username: "bob",
+		Password: strongPassword,
+		Amount:   1000,
+		AssetID:  "asset3",
+		To:       vm.Format(keys[2].PublicKey().Address().Bytes()),
+		Memo:     formatting.CB58{Bytes: make([]byte, maxMemoSize+1)},
+	}, &reply)
+	if err != errMemoTooLarge {
+		t.Fatalf("Expected %s but got %v", errMemoTooLarge, err)
+	}
+}
+
+func TestMintNotMinter(t *testing.T) {
+	vm := GenesisVM(t)
+	ctx.Lock.Lock()
+	defer func() {
+		ctx.Lock.Unlock()
+		vm.Shutdown()
+		ctx.Keystore = nil
+	}()
+
+	s := Service{vm: vm}
+	setupUser(t, &s, "bob", 2)
+
+	// The asset's only minter is keys[0]
+	err := s.Mint(nil, &MintArgs{
+		Username: "bob",
+		Password: strongPassword,
+		Amount:   1000,
+		AssetID:  "asset3",
+		To:       vm.Format(keys[2].PublicKey().Address().Bytes()),
+	}, &MintReply{})
+	if err != errAddressesCantMintAsset {
+		t.Fatalf("Should have errored with %s, errored with %v", errAddressesCantMintAsset, err)
+	}
+}
+
+func TestConsolidateUTXOs(t *testing.T) {
+	vm := GenesisVM(t)
+	ctx.Lock.Lock()
+	defer func() {
+		// Issuing the transaction starts the VM's timer, which waits on the
+		// lock
+		ctx.Lock.Unlock()
+		vm.Shutdown()
+		ctx.Keystore = nil
+	}()
+
+	s := Service{vm: vm}
+	setupUser(t, &s, "bob", 0)
+
+	// keys[0] holds 4 UTXOs of asset1 at genesis
+	reply := ConsolidateUTXOsReply{}
+	if err := s.ConsolidateUTXOs(nil, &ConsolidateUTXOsArgs{
+		Username: "bob",
+		Password: strongPassword,
+		AssetID:  "asset1",
+		Target:   4,
+	}, &reply); err != nil {
+		t.Fatal(err)
+	}
+	if len(reply.TxIDs) != 0 {
+		t.Fatalf("Shouldn't have consolidated UTXOs that are already at the target")
+	}
+
+	if err := s.ConsolidateUTXOs(nil, &ConsolidateUTXOsArgs{
+		Username: "bob",
+		Password: strongPassword,
+		AssetID:  "asset1",
+		Address:  vm.Format(keys[0].PublicKey().Address().Bytes()),
+		Target:   2,
+	}, &reply); err != nil {
+		t.Fatal(err)
+	}
+	if len(reply.TxIDs) != 1 {
+		t.Fatalf("Should have issued %d transaction, issued %d", 1, len(reply.TxIDs))
+	}
+
+	tx := UniqueTx{
+		vm:   vm,
+		txID: reply.TxIDs[0],
+	}
+	if err := tx.Verify(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if numIns := len(tx.InputUTXOs()); numIns != 3 {
+		t.Fatalf("Should have merged %d UTXOs, merged %d", 3, numIns)
+	}
+	utxos := tx.UTXOs()
+	if len(utxos) != 1 {
+		t.Fatalf("Should have produced %d UTXO, produced %d", 1, len(utxos))
+	}
+	out, ok := utxos[0].Out.(*secp256k1fx.TransferOutput)
+	if !ok {
+		t.Fatalf("Wrong output type")
+	}
+	// The smallest UTXOs hold 50000, 50000, and 100000
+	if out.Amt != 200000 {
+		t.Fatalf("Merged UTXO should hold %d, holds %d", 200000, out.Amt)
+	}
+	if len(out.Addrs) != 1 || !out.Addrs[0].Equals(keys[0].PublicKey().Address()) {
+		t.Fatalf("Merged UTXO should be held by the same address")
+	}
+}
+
+func TestConsolidateUTXOsPaysFee(t *testing.T) {
+	vm := GenesisVM(t)
+	ctx.Lock.Lock()
+	defer func() {
+		ctx.Lock.Unlock()
+		vm.Shutdown()
+		ctx.Keystore = nil
+	}()
+
+	avaID, err := vm.Lookup("asset1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	vm.ava = avaID
+	vm.txFee = 1000
+
+	s := Service{vm: vm}
+	setupUser(t, &s, "bob", 0)
+
+	reply := ConsolidateUTXOsReply{}
+	if err := s.ConsolidateUTXOs(nil, &ConsolidateUTXOsArgs{
+		Username: "bob",
+		Password: strongPassword,
+		AssetID:  avaID.String(),
+		Target:   2,
+	}, &reply); err != nil {
+		t.Fatal(err)
+	}
+	if len(reply.TxIDs) != 1 {
+		t.Fatalf("Should have issued %d transaction, issued %d", 1, len(reply.TxIDs))
+	}
+
+	tx := UniqueTx{
+		vm:   vm,
+		txID: reply.TxIDs[0],
+	}
+	if err := tx.Verify(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	utxos := tx.UTXOs()
+	if len(utxos) != 1 {
+		t.Fatalf("Should have produced %d UTXO, produced %d", 1, len(utxos))
+	}
+	out, ok := utxos[0].Out.(*secp256k1fx.TransferOutput)
+	if !ok {
+		t.Fatalf("Wrong output type")
+	}
+	// The smallest UTXOs hold 50000, 50000, and 100000, less the fee
+	if out.Amt != 200000-vm.txFee {
+		t.Fatalf("Merged UTXO should hold %d, holds %d", 200000-vm.txFee, out.Amt)
+	}
+
+	// Merging can't pay the fee if the merged UTXOs don't exceed it
+	vm.txFee = 300000
+	if err := s.ConsolidateUTXOs(nil, &ConsolidateUTXOsArgs{
+		Username: "bob",
+		Password: strongPassword,
+		AssetID:  avaID.String(),
+		Target:   1,
+	}, &reply); err == nil {
+		t.Fatalf("Should have errored because the merged UTXOs don't cover the fee")
+	}
+}
+
+func TestConsolidateUTXOsAddressNotOwned(t *testing.T) {
+	vm := GenesisVM(t)
+	ctx.Lock.Lock()
+	defer func() {
+		ctx.Lock.Unlock()
+		vm.Shutdown()
+		ctx.Keystore = nil
+	}()
+
+	s := Service{vm: vm}
+	setupUser(t, &s, "bob", 0)
+
+	if err := s.ConsolidateUTXOs(nil, &ConsolidateUTXOsArgs{
+		Username: "bob",
+		Password: strongPassword,
+		AssetID:  "asset1",
+		Address:  vm.Format(keys[1].PublicKey().Address().Bytes()),
+	}, &ConsolidateUTXOsReply{}); err == nil {
+		t.Fatalf("Shouldn't have consolidated the UTXOs of an address the user doesn't control")
+	}
+}
+
+func TestGetUTXOsPaginated(t *testing.T) {
+	vm := GenesisVM(t)
+	ctx.Lock.Lock()
+	defer func() {
+		ctx.Lock.Unlock()
+		vm.Shutdown()
+	}()
+
+	s := Service{vm: vm}
+	addrs := []string{
+		vm.Format(keys[0].PublicKey().Address().Bytes()),
+		vm.Format(keys[1].PublicKey().Address().Bytes()),
+	}
+
+	allReply := GetUTXOsReply{}
+	if err := s.GetUTXOs(nil, &GetUTXOsArgs{Addresses: addrs}, &allReply); err != nil {
+		t.Fatal(err)
+	}
+	if len(allReply.UTXOs) < 3 {
+		t.Fatalf("Genesis should have given the addresses at least 3 UTXOs, has %d", len(allReply.UTXOs))
+	}
+
+	pagedUTXOs := ids.Set{}
+	startIndex := Index{}
+	for {
+		reply := GetUTXOsReply{}
+		if err := s.GetUTXOs(nil, &GetUTXOsArgs{
+			Addresses:  addrs,
+			StartIndex: startIndex,
+			Limit:      2,
+		}, &reply); err != nil {
+			t.Fatal(err)
+		}
+		if len(reply.UTXOs) > 2 {
+			t.Fatalf("Should have returned at most %d UTXOs, returned %d", 2, len(reply.UTXOs))
+		}
+		for _, b := range reply.UTXOs {
+			utxo := &UTXO{}
+			if err := vm.codec.Unmarshal(b.Bytes, utxo); err != nil {
+				t.Fatal(err)
+			}
+			if pagedUTXOs.Contains(utxo.InputID()) {
+				t.Fatalf("UTXO %s was returned twice", utxo.InputID())
+			}
+			pagedUTXOs.Add(utxo.InputID())
+		}
+		if len(reply.UTXOs) < 2 {
+			break
+		}
+		startIndex = reply.EndIndex
+	}
+	if pagedUTXOs.Len() != len(allReply.UTXOs) {
+		t.Fatalf("Pages should have contained %d UTXOs, contained %d", len(allReply.UTXOs), pagedUTXOs.Len())
+	}
+}
+
+func TestGetUTXOsLimitTooLarge(t *testing.T) {
+	vm := GenesisVM(t)
+	ctx.Lock.Lock()
+	defer func() {
+		ctx.Lock.Unlock()
+		vm.Shutdown()
+	}()
+
+	s := Service{vm: vm}
+	if err := s.GetUTXOs(nil, &GetUTXOsArgs{
+		Addresses: []string{vm.Format(keys[0].PublicKey().Address().Bytes())},
+		Limit:     maxUTXOsLimit + 1,
+	}, &GetUTXOsReply{}); err != errUTXOsLimitTooLarge {
+		t.Fatalf("Expected %s but got %v", errUTXOsLimitTooLarge, err)
+	}
+}
+
+func TestGetAddressTxs(t *testing.T) {
+	genesisBytes := BuildGenesisTest(t)
+
+	ctx.Lock.Lock()
+
+	vm := &VM{indexTxs: true}
+	err := vm.Initialize(
+		ctx,
+		memdb.New(),
+		genesisBytes,
+		make(chan common.Message, 1),
+		[]*common.Fx{&common.Fx{
+			ID: ids.Empty,
+			Fx: &secp256k1fx.Fx{},
+		}},
+	)
+	if err != nil {
+		ctx.Lock.Unlock()
+		t.Fatal(err)
+	}
+	vm.batchTimeout = 0
+	defer func() {
+		ctx.Lock.Unlock()
+		vm.Shutdown()
+		ctx.Keystore = nil
+	}()
+
+	s := Service{vm: vm}
+	setupUser(t, &s, "alice", 0)
+
+	from := vm.Format(keys[0].PublicKey().Address().Bytes())
+	to := vm.Format(keys[2].PublicKey().Address().Bytes())
+
+	genesisReply := GetAddressTxsReply{}
+	if err := s.GetAddressTxs(nil, &GetAddressTxsArgs{Address: from}, &genesisReply); err != nil {
+		t.Fatal(err)
+	}
+	if len(genesisReply.TxIDs) == 0 {
+		t.Fatalf("The genesis txs should have been indexed")
+	}
+	if !genesisReply.Complete {
+		t.Fatalf("The index should be complete")
+	}
+
+	sendReply := SendReply{}
+	if err := s.Send(nil, &SendArgs{
+		Username: "alice",
+		Password: strongPassword,
+		Amount:   500,
+		AssetID:  "asset1",
+		To:       to,
+	}, &sendReply); err != nil {
+		t.Fatal(err)
+	}
+	tx := UniqueTx{vm: vm, txID: sendReply.TxID}
+	if err := tx.Verify(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	tx.Accept(context.Background())
+
+	// The sender's history has the send after the genesis txs
+	pageReply := GetAddressTxsReply{}
+	if err := s.GetAddressTxs(nil, &GetAddressTxsArgs{
+		Address:    from,
+		StartIndex: genesisReply.NextIndex,
+		Limit:      1,
+	}, &pageReply); err != nil {
+		t.Fatal(err)
+	}
+	if len(pageReply.TxIDs) != 1 || !pageReply.TxIDs[0].Equals(sendReply.TxID) {
+		t.Fatalf("Expected the page to hold %s but got %v", sendReply.TxID, pageReply.TxIDs)
+	}
+	if uint64(pageReply.NextIndex) != uint64(genesisReply.NextIndex)+1 {
+		t.Fatalf("Expected the next index to be %d but got %d", genesisReply.NextIndex+1, pageReply.NextIndex)
+	}
+
+	// The recipient's history has the send
+	toReply := GetAddressTxsReply{}
+	if err := s.GetAddressTxs(nil, &GetAddressTxsArgs{Address: to}, &toReply); err != nil {
+		t.Fatal(err)
+	}
+	if len(toReply.TxIDs) == 0 || !toReply.TxIDs[len(toReply.TxIDs)-1].Equals(sendReply.TxID) {
+		t.Fatalf("Expected the recipient's history to end with %s but got %v", sendReply.TxID, toReply.TxIDs)
+	}
+}
+
+func TestGetAddressTxsDisabled(t *testing.T) {
+	vm := GenesisVM(t)
+	ctx.Lock.Lock()
+	defer func() {
+		ctx.Lock.Unlock()
+		vm.Shutdown()
+	}()
+
+	s := Service{vm: vm}
+	if err := s.GetAddressTxs(nil, &GetAddressTxsArgs{
+		Address: vm.Format(keys[0].PublicKey().Address().Bytes()),
+	}, &GetAddressTxsReply{}); err != errAddressTxIndexDisabled {
+		t.Fatalf("Expected %s but got %v", errAddressTxIndexDisabled, err)
+	}
+}
+
+func TestGetAssetStats(t *testing.T) {
+	genesisBytes := BuildGenesisTest(t)
+
+	ctx.Lock.Lock()
+
+	vm := &VM{indexAssetStats: true}
+	err := vm.Initialize(
+		ctx,
+		memdb.New(),
+		genesisBytes,
+		make(chan common.Message, 1),
+		[]*common.Fx{&common.Fx{
+			ID: ids.Empty,
+			Fx: &secp256k1fx.Fx{},
+		}},
+	)
+	if err != nil {
+		ctx.Lock.Unlock()
+		t.Fatal(err)
+	}
+	vm.batchTimeout = 0
+	defer func() {
+		ctx.Lock.Unlock()
+		vm.Shutdown()
+		ctx.Keystore = nil
+	}()
+
+	now := time.Unix(1600000000, 0)
+	vm.clock.Set(now)
+
+	s := Service{vm: vm}
+	setupUser(t, &s, "alice", 0)
+
+	sendReply := SendReply{}
+	if err := s.Send(nil, &SendArgs{
+		Username: "alice",
+		Password: strongPassword,
+		Amount:   500,
+		AssetID:  "asset1",
+		To:       vm.Format(keys[2].PublicKey().Address().Bytes()),
+	}, &sendReply); err != nil {
+		t.Fatal(err)
+	}
+	tx := UniqueTx{vm: vm, txID: sendReply.TxID}
+	if err := tx.Verify(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	tx.Accept(context.Background())
+
+	// The range spans the day before the send and the day of the send
+	reply := GetAssetStatsReply{}
+	if err := s.GetAssetStats(nil, &GetAssetStatsArgs{
+		AssetID:   "asset1",
+		StartTime: json.Uint64(now.Add(-assetStatsPeriod).Unix()),
+		EndTime:   json.Uint64(now.Unix()),
+	}, &reply); err != nil {
+		t.Fatal(err)
+	}
+	if len(reply.Days) != 2 {
+		t.Fatalf("Expected the stats of 2 days but got %d", len(reply.Days))
+	}
+	if before := reply.Days[0]; before.TxCount != 0 || before.Transferred != 0 || before.Addresses != 0 {
+		t.Fatalf("Expected no activity before the send but got %+v", before)
+	}
+	day := reply.Days[1]
+	if uint64(day.StartTime) > uint64(now.Unix()) || uint64(now.Unix())-uint64(day.StartTime) >= uint64(assetStatsPeriod/time.Second) {
+		t.Fatalf("Expected the day starting at %d to contain %d", day.StartTime, now.Unix())
+	}
+	if day.TxCount != 1 {
+		t.Fatalf("Expected 1 tx but got %d", day.TxCount)
+	}
+	if day.Transferred < 500 {
+		t.Fatalf("Expected at least 500 to be transferred but got %d", day.Transferred)
+	}
+	if day.Addresses != 2 {
+		t.Fatalf("Expected the sender and recipient to be counted but got %d addresses", day.Addresses)
+	}
+	if reply.TxCount != day.TxCount || reply.Transferred != day.Transferred {
+		t.Fatalf("Expected the totals to match the day's stats")
+	}
+	if !reply.Complete {
+		t.Fatalf("The stats should be complete")
+	}
+
+	// Ranges longer than the maximum are rejected
+	if err := s.GetAssetStats(nil, &GetAssetStatsArgs{
+		AssetID: "asset1",
+		EndTime: json.Uint64(maxAssetStatsPeriods * uint64(assetStatsPeriod/time.Second)),
+	}, &GetAssetStatsReply{}); err != errAssetStatsRangeTooLarge {
+		t.Fatalf("Expected %s but got %v", errAssetStatsRangeTooLarge, err)
+	}
+}
+
+func TestGetAssetStatsDisabled(t *testing.T) {
+	vm := GenesisVM(t)
+	ctx.Lock.Lock()
+	defer func() {
+		ctx.Lock.Unlock()
+		vm.Shutdown()
+	}()
+
+	s := Service{vm: vm}
+	if err := s.GetAssetStats(nil, &GetAssetStatsArgs{AssetID: "asset1"}, &GetAssetStatsReply{}); err != errAssetStatsDisabled {
+		t.Fatalf("Expected %s but got %v", errAssetStatsDisabled, err)
+	}
+}
+
+func TestGetUTXOsByAssetID(t *testing.T) {
+	vm := GenesisVM(t)
+	ctx.Lock.Lock()
+	defer func() {
+		ctx.Lock.Unlock()
+		vm.Shutdown()
+		ctx.Keystore = nil
+	}()
+
+	s := Service{vm: vm}
+	setupUser(t, &s, "bob", 0)
+
+	assetID, err := vm.Lookup("asset1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// keys[0] holds 4 UTXOs of asset1 at genesis
+	reply := GetUTXOsByAssetIDReply{}
+	if err := s.GetUTXOsByAssetID(nil, &GetUTXOsByAssetIDArgs{
+		Username: "bob",
+		Password: strongPassword,
+		AssetID:  "asset1",
+		Limit:    3,
+	}, &reply); err != nil {
+		t.Fatal(err)
+	}
+	if len(reply.UTXOs) != 3 {
+		t.Fatalf("Should have returned %d UTXOs, returned %d", 3, len(reply.UTXOs))
+	}
+	if reply.NextUTXOID == "" {
+		t.Fatalf("Should have returned the start of the next page")
+	}
+	utxoIDs := []ids.ID{}
+	for _, b := range reply.UTXOs {
+		utxo := &UTXO{}
+		if err := vm.codec.Unmarshal(b.Bytes, utxo); err != nil {
+			t.Fatal(err)
+		}
+		if !utxo.AssetID().Equals(assetID) {
+			t.Fatalf("Returned a UTXO of asset %s", utxo.AssetID())
+		}
+		utxoIDs = append(utxoIDs, utxo.InputID())
+	}
+	if !ids.IsSortedAndUniqueIDs(utxoIDs) {
+		t.Fatalf("UTXOs should be sorted by ID")
+	}
+
+	nextReply := GetUTXOsByAssetIDReply{}
+	if err := s.GetUTXOsByAssetID(nil, &GetUTXOsByAssetIDArgs{
+		Username:    "bob",
+		Password:    strongPassword,
+		AssetID:     "asset1",
+		StartUTXOID: reply.NextUTXOID,
+		Limit:       3,
+	}, &nextReply); err != nil {
+		t.Fatal(err)
+	}
+	if len(nextReply.UTXOs) != 1 {
+		t.Fatalf("Should have returned %d UTXO, returned %d", 1, len(nextReply.UTXOs))
+	}
+	if nextReply.NextUTXOID != "" {
+		t.Fatalf("Shouldn't have returned another page")
+	}
+}
+
+// A database initialized before the address/asset index existed should still
+// return all of a user's UTXOs
+func TestGetUTXOsByAssetIDUnindexed(t *testing.T) {
+	vm := GenesisVM(t)
+	ctx.Lock.Lock()
+	defer func() {
+		ctx.Lock.Unlock()
+		vm.Shutdown()
+		ctx.Keystore = nil
+	}()
+
+	s := Service{vm: vm}
+	setupUser(t, &s, "bob", 0)
+
+	assetID, err := vm.Lookup("asset1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := ids.NewID(hashing.ComputeHash256Array(keys[0].PublicKey().Address().Bytes()))
+	if err := vm.state.SetAssetFunds(addr, assetID, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := vm.state.SetAssetIndexInitialized(choices.Unknown); err != nil {
+		t.Fatal(err)
+	}
+
+	reply := GetUTXOsByAssetIDReply{}
+	if err := s.GetUTXOsByAssetID(nil, &GetUTXOsByAssetIDArgs{
+		Username: "bob",
+		Password: strongPassword,
+		AssetID:  "asset1",
+	}, &reply); err != nil {
+		t.Fatal(err)
+	}
+	if len(reply.UTXOs) != 4 {
+		t.Fatalf("Should have returned %d UTXOs, returned %d", 4, len(reply.UTXOs))
+	}
+}
+
+func TestGetUTXOsByAssetIDLimitTooLarge(t *testing.T) {
+	vm := GenesisVM(t)
+	ctx.Lock.Lock()
+	defer func() {
+		ctx.Lock.Unlock()
+		vm.Shutdown()
+		ctx.Keystore = nil
+	}()
+
+	s := Service{vm: vm}
+	setupUser(t, &s, "bob", 0)
+
+	if err := s.GetUTXOsByAssetID(nil, &GetUTXOsByAssetIDArgs{
+		Username: "bob",
+		Password: strongPassword,
+		AssetID:  "asset1",
+		Limit:    maxUTXOsLimit + 1,
+	}, &GetUTXOsByAssetIDReply{}); err != errUTXOsLimitTooLarge {
+		t.Fatalf("Expected %s but got %v", errUTXOsLimitTooLarge, err)
+	}
+}
+
+func TestPartiallySignedTx(t *testing.T) {
+	vm := GenesisVM(t)
+	ctx.Lock.Lock()
+	defer func() {
+		// Issuing the transaction starts the VM's timer, which waits on the
+		// lock
+		ctx.Lock.Unlock()
+		vm.Shutdown()
+		ctx.Keystore = nil
+	}()
+
+	assetID, err := vm.Lookup("asset2")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Fund a UTXO that requires signatures from both keys[0] and keys[1]
+	owners := secp256k1fx.OutputOwners{
+		Threshold: 2,
+		Addrs: []ids.ShortID{
+			keys[0].PublicKey().Address(),
+			keys[1].PublicKey().Address(),
+		},
+	}
+	owners.Sort()
+	if err := vm.state.FundUTXO(&UTXO{
+		UTXOID: UTXOID{TxID: ids.Empty.Prefix(1)},
+		Asset:  Asset{ID: assetID},
+		Out: &secp256k1fx.TransferOutput{
+			Amt:          5000,
+			OutputOwners: owners,
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	s := Service{vm: vm}
+
+	createReply := CreateUnsignedTxReply{}
+	if err := s.CreateUnsignedTx(nil, &CreateUnsignedTxArgs{
+		Amount:  1000,
+		AssetID: assetID.String(),
+		To:      vm.Format(keys[2].PublicKey().Address().Bytes()),
+		Signers: []string{
+			vm.Format(keys[0].PublicKey().Address().Bytes()),
+			vm.Format(keys[1].PublicKey().Address().Bytes()),
+		},
+	}, &createReply); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.IssueSignedTx(nil, &IssueSignedTxArgs{Tx: createReply.Tx}, &IssueSignedTxReply{}); err == nil {
+		t.Fatalf("Shouldn't have issued an unsigned transaction")
+	}
+
+	setupUser(t, &s, "alice", 0)
+	aliceReply := AddSignatureReply{}
+	if err := s.AddSignature(nil, &AddSignatureArgs{
+		Username: "alice",
+		Password: strongPassword,
+		Signer:   vm.Format(keys[0].PublicKey().Address().Bytes()),
+		Tx:       createReply.Tx,
+	}, &aliceReply); err != nil {
+		t.Fatal(err)
+	}
+	if aliceReply.MissingSignatures != 1 {
+		t.Fatalf("Should be missing %d signature, missing %d", 1, aliceReply.MissingSignatures)
+	}
+
+	if err := s.IssueSignedTx(nil, &IssueSignedTxArgs{Tx: aliceReply.Tx}, &IssueSignedTxReply{}); err == nil {
+		t.Fatalf("Shouldn't have issued a partially signed transaction")
+	}
+
+	setupUser(t, &s, "bob", 1)
+	bobReply := AddSignatureReply{}
+	if err := s.AddSignature(nil, &AddSignatureArgs{
+		Username: "bob",
+		Password: strongPassword,
+		Signer:   vm.Format(keys[1].PublicKey().Address().Bytes()),
+		Tx:       aliceReply.Tx,
+	}, &bobReply); err != nil {
+		t.Fatal(err)
+	}
+	if bobReply.MissingSignatures != 0 {
+		t.Fatalf("Should be missing %d signatures, missing %d", 0, bobReply.MissingSignatures)
+	}
+
+	issueReply := IssueSignedTxReply{}
+	if err := s.IssueSignedTx(nil, &IssueSignedTxArgs{Tx: bobReply.Tx}, &issueReply); err != nil {
+		t.Fatal(err)
+	}
+
+	tx := UniqueTx{
+		vm:   vm,
+		txID: issueReply.TxID,
+	}
+	if err := tx.Verify(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	utxos := tx.UTXOs()
+	if len(utxos) != 2 {
+		t.Fatalf("Should have produced %d UTXOs, produced %d", 2, len(utxos))
+	}
+	for _, utxo := range utxos {
+		out, ok := utxo.Out.(*secp256k1fx.TransferOutput)
+		if !ok {
+			t.Fatalf("Wrong output type")
+		}
+		// The change should still be held by both keys
+		if out.Amt == 4000 && !out.OutputOwners.Equals(&owners) {
+			t.Fatalf("Change should be held by the spent UTXO's owners")
+		}
+	}
+}
+
+func TestAddSignatureUnneededAddress(t *testing.T) {
+	vm := GenesisVM(t)
+	ctx.Lock.Lock()
+	defer func() {
+		ctx.Lock.Unlock()
+		vm.Shutdown()
+		ctx.Keystore = nil
+	}()
+
+	s := Service{vm: vm}
+
+	createReply := CreateUnsignedTxReply{}
+	if err := s.CreateUnsignedTx(nil, &CreateUnsignedTxArgs{
+		Amount:  1000,
+		AssetID: "asset1",
+		To:      vm.Format(keys[2].PublicKey().Address().Bytes()),
+		Signers: []string{vm.Format(keys[0].PublicKey().Address().Bytes())},
+	}, &createReply); err != nil {
+		t.Fatal(err)
+	}
+
+	setupUser(t, &s, "bob", 1)
+	if err := s.AddSignature(nil, &AddSignatureArgs{
+		Username: "bob",
+		Password: strongPassword,
+		Signer:   vm.Format(keys[1].PublicKey().Address().Bytes()),
+		Tx:       createReply.Tx,
+	}, &AddSignatureReply{}); err != errUnneededAddress {
+		t.Fatalf("Should have failed with %s, failed with %v", errUnneededAddress, err)
+	}
+}
+
+// nftVM returns a VM running the secp256k1fx and the nftfx
+func nftVM(t *testing.T) *VM {
+	genesisBytes := BuildGenesisTest(t)
+
+	ctx.Lock.Lock()
+	defer ctx.Lock.Unlock()
+
+	vm := &VM{}
+	err := vm.Initialize(
+		ctx,
+		memdb.New(),
+		genesisBytes,
+		make(chan common.Message, 1),
+		[]*common.Fx{
+			&common.Fx{
+				ID: ids.Empty,
+				Fx: &secp256k1fx.Fx{},
+			},
+			&common.Fx{
+				ID: nftfx.ID,
+				Fx: &nftfx.Fx{},
+			},
+		},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	vm.batchTimeout = 0
+	return vm
+}
+
+func TestNFT(t *testing.T) {
+	vm := nftVM(t)
+	ctx.Lock.Lock()
+	defer func() {
+		// Issuing the transaction starts the VM's timer, which waits on the
+		// lock
+		ctx.Lock.Unlock()
+		vm.Shutdown()
+		ctx.Keystore = nil
+	}()
+
+	s := Service{vm: vm}
+	setupUser(t, &s, "bob", 0)
+
+	createReply := CreateNFTAssetReply{}
+	if err := s.CreateNFTAsset(nil, &CreateNFTAssetArgs{
+		Name:   "Family",
+		Symbol: "FAM",
+		MinterSets: []Owners{
+			Owners{
+				Threshold: 1,
+				Minters:   []string{vm.Format(keys[0].PublicKey().Address().Bytes())},
+			},
+		},
+	}, &createReply); err != nil {
+		t.Fatal(err)
+	}
+	createTx := UniqueTx{vm: vm, txID: createReply.AssetID}
+	createTx.Accept(context.Background())
+
+	payload := []byte{1, 2, 3}
+	mintReply := MintNFTReply{}
+	if err := s.MintNFT(nil, &MintNFTArgs{
+		Username: "bob",
+		Password: strongPassword,
+		AssetID:  createReply.AssetID.String(),
+		Payload:  formatting.CB58{Bytes: payload},
+		To:       vm.Format(keys[0].PublicKey().Address().Bytes()),
+	}, &mintReply); err != nil {
+		t.Fatal(err)
+	}
+	mintTx := UniqueTx{vm: vm, txID: mintReply.TxID}
+	if status := mintTx.Status(); status != choices.Processing {
+		t.Fatalf("Minting transaction should have been issued, status: %s", status)
+	}
+	mintTx.Accept(context.Background())
+
+	sendReply := SendNFTReply{}
+	if err := s.SendNFT(nil, &SendNFTArgs{
+		Username: "bob",
+		Password: strongPassword,
+		AssetID:  createReply.AssetID.String(),
+		To:       vm.Format(keys[2].PublicKey().Address().Bytes()),
+	}, &sendReply); err != nil {
+		t.Fatal(err)
+	}
+	sendTx := UniqueTx{vm: vm, txID: sendReply.TxID}
+	if status := sendTx.Status(); status != choices.Processing {
+		t.Fatalf("Sending transaction should have been issued, status: %s", status)
+	}
+
+	utxos := sendTx.UTXOs()
+	if len(utxos) != 1 {
+		t.Fatalf("Sending transaction should have produced 1 UTXO, produced %d", len(utxos))
+	}
+	out, ok := utxos[0].Out.(*nftfx.TransferOutput)
+	switch {
+	case !ok:
+		t.Fatalf("Sending transaction should have produced an NFT")
+	case !bytes.Equal(out.Payload, payload):
+		t.Fatalf("Sent NFT has the wrong payload")
+	case !out.Addrs[0].Equals(keys[2].PublicKey().Address()):
+		t.Fatalf("Sent NFT has the wrong owner")
+	}
+}
+
+func TestMintNFTWrongGroup(t *testing.T) {
+	vm := nftVM(t)
+	ctx.Lock.Lock()
+	defer func() {
+		// Issuing the transaction starts the VM's timer, which waits on the
+		// lock
+		ctx.Lock.Unlock()
+		vm.Shutdown()
+		ctx.Keystore = nil
+	}()
+
+	s := Service{vm: vm}
+	setupUser(t, &s, "bob", 0)
+
+	createReply := CreateNFTAssetReply{}
+	if err := s.CreateNFTAsset(nil, &CreateNFTAssetArgs{
+		Name:   "Family",
+		Symbol: "FAM",
+		MinterSets: []Owners{
+			Owners{
+				Threshold: 1,
+				Minters:   []string{vm.Format(keys[0].PublicKey().Address().Bytes())},
+			},
+		},
+	}, &createReply); err != nil {
+		t.Fatal(err)
+	}
+	createTx := UniqueTx{vm: vm, txID: createReply.AssetID}
+	createTx.Accept(context.Background())
+
+	// The family only has group 0
+	err := s.MintNFT(nil, &MintNFTArgs{
+		Username: "bob",
+		Password: strongPassword,
+		AssetID:  createReply.AssetID.String(),
+		GroupID:  1,
+		To:       vm.Format(keys[0].PublicKey().Address().Bytes()),
+	}, &MintNFTReply{})
+	if err != errAddressesCantMintAsset {
+		t.Fatalf("Should have errored with %s, errored with %v", errAddressesCantMintAsset, err)
+	}
+}
+
+func TestCreateNFTAssetNotEnabled(t *testing.T) {
+	vm := GenesisVM(t)
+	ctx.Lock.Lock()
+	defer func() {
+		ctx.Lock.Unlock()
+		vm.Shutdown()
+	}()
+
+	s := Service{vm: vm}
+	err := s.CreateNFTAsset(nil, &CreateNFTAssetArgs{
+		Name:   "Family",
+		Symbol: "FAM",
+		MinterSets: []Owners{
+			Owners{
+				Threshold: 1,
+				Minters:   []string{vm.Format(keys[0].PublicKey().Address().Bytes())},
+			},
+		},
+	}, &CreateNFTAssetReply{})
+	if err != errNFTFxNotEnabled {
+		t.Fatalf("Should have errored with %s, errored with %v", errNFTFxNotEnabled, err)
+	}
+}
+
+func TestSendPaysFee(t *testing.T) {
+	vm := GenesisVM(t)
+	ctx.Lock.Lock()
+	defer func() {
+		ctx.Lock.Unlock()
+		vm.Shutdown()
+		ctx.Keystore = nil
+	}()
+
+	avaID, err := vm.Lookup("asset1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	vm.ava = avaID
+	vm.txFee = 1000
+
+	s := Service{vm: vm}
+	setupUser(t, &s, "alice", 0)
+
+	reply := SendReply{}
+	if err := s.Send(nil, &SendArgs{
+		Username: "alice",
+		Password: strongPassword,
+		Amount:   500,
+		AssetID:  avaID.String(),
+		To:       vm.Format(keys[2].PublicKey().Address().Bytes()),
+	}, &reply); err != nil {
+		t.Fatal(err)
+	}
+
+	tx := UniqueTx{vm: vm, txID: reply.TxID}
+	if err := tx.Verify(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	consumed := uint64(0)
+	for _, in := range tx.t.tx.UnsignedTx.(*BaseTx).Ins {
+		consumed += in.Input().Amount()
+	}
+	produced := uint64(0)
+	for _, out := range tx.t.tx.UnsignedTx.(*BaseTx).Outs {
+		produced += out.Output().Amount()
+	}
+	if burned := consumed - produced; burned != vm.txFee {
+		t.Fatalf("Should have burned %d but burned %d", vm.txFee, burned)
+	}
+}
+
+func TestSendNoAVAForFee(t *testing.T) {
+	vm := GenesisVM(t)
+	ctx.Lock.Lock()
+	defer func() {
+		ctx.Lock.Unlock()
+		vm.Shutdown()
+		ctx.Keystore = nil
+	}()
+
+	avaID, err := vm.Lookup("asset1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	vm.ava = avaID
+	vm.txFee = 1000
+
+	assetID, err := vm.Lookup("asset2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := vm.state.FundUTXO(&UTXO{
+		UTXOID: UTXOID{TxID: ids.Empty.Prefix(1)},
+		Asset:  Asset{ID: assetID},
+		Out: &secp256k1fx.TransferOutput{
+			Amt: 5000,
+			OutputOwners: secp256k1fx.OutputOwners{
+				Threshold: 1,
+				Addrs:     []ids.ShortID{keys[1].PublicKey().Address()},
+			},
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// keys[1] holds the asset but no AVA to pay the fee with
+	s := Service{vm: vm}
+	setupUser(t, &s, "bob", 1)
+
+	if err := s.Send(nil, &SendArgs{
+		Username: "bob",
+		Password: strongPassword,
+		Amount:   1000,
+		AssetID:  assetID.String(),
+		To:       vm.Format(keys[2].PublicKey().Address().Bytes()),
+	}, &SendReply{}); err == nil {
+		t.Fatalf("Should have errored because the user can't pay the tx fee")
+	}
+}
+
+// burnedAVA returns the amount of AVA [tx] consumes but doesn't produce
+func burnedAVA(t *testing.T, tx *UniqueTx) uint64 {
+	base := (*BaseTx)(nil)
+	switch unsignedTx := tx.t.tx.UnsignedTx.(type) {
+	case *BaseTx:
+		base = unsignedTx
+	case *CreateAssetTx:
+		base = &unsignedTx.BaseTx
+	case *OperationTx:
+		base = &unsignedTx.BaseTx
+	default:
+		t.Fatalf("Unknown transaction type %T", unsignedTx)
+	}
+
+	burned := uint64(0)
+	for _, in := range base.Ins {
+		if in.AssetID().Equals(tx.vm.ava) {
+			burned += in.Input().Amount()
+		}
+	}
+	for _, out := range base.Outs {
+		if out.AssetID().Equals(tx.vm.ava) {
+			burned -= out.Output().Amount()
+		}
+	}
+	return burned
+}
+
+func TestCreateFixedCapAssetManyHoldersPaysFee(t *testing.T) {
+	vm := GenesisVM(t)
+	ctx.Lock.Lock()
+	defer func() {
+		ctx.Lock.Unlock()
+		vm.Shutdown()
+		ctx.Keystore = nil
+	}()
+
+	avaID, err := vm.Lookup("asset1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	vm.ava = avaID
+	vm.txFee = 1000
+
+	s := Service{vm: vm}
+	setupUser(t, &s, "bob", 0)
+
+	holders := []*Holder(nil)
+	for i := 0; i < maxOutputsPerTx+1; i++ {
+		addr := ids.NewShortID([20]byte{byte(i), byte(i >> 8)})
+		holders = append(holders, &Holder{
+			Amount:  json.Uint64(i + 1),
+			Address: vm.Format(addr.Bytes()),
+		})
+	}
+
+	reply := CreateFixedCapAssetReply{}
+	if err := s.CreateFixedCapAsset(nil, &CreateFixedCapAssetArgs{
+		Username:       "bob",
+		Password:       strongPassword,
+		Name:           "test asset",
+		Symbol:         "test",
+		Denomination:   1,
+		InitialHolders: holders,
+	}, &reply); err != nil {
+		t.Fatal(err)
+	}
+	if len(reply.TxIDs) != 2 {
+		t.Fatalf("The holders should have been split across %d transactions, were split across %d", 2, len(reply.TxIDs))
+	}
+
+	// The distribution spends the AVA left over from paying the creation's fee
+	for _, txID := range reply.TxIDs {
+		tx := UniqueTx{
+			vm:   vm,
+			txID: txID,
+		}
+		if err := tx.Verify(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		if burned := burnedAVA(t, &tx); burned != vm.txFee {
+			t.Fatalf("Should have burned %d but burned %d", vm.txFee, burned)
+		}
+	}
+}
+
+func TestMintPaysFee(t *testing.T) {
+	vm := GenesisVM(t)
+	ctx.Lock.Lock()
+	defer func() {
+		ctx.Lock.Unlock()
+		vm.Shutdown()
+		ctx.Keystore = nil
+	}()
+
+	avaID, err := vm.Lookup("asset1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	vm.ava = avaID
+	vm.txFee = 1000
+
+	s := Service{vm: vm}
+	setupUser(t, &s, "bob", 0)
+
+	reply := MintReply{}
+	if err := s.Mint(nil, &MintArgs{
+		Username: "bob",
+		Password: strongPassword,
+		Amount:   1000,
+		AssetID:  "asset3",
+		To:       vm.Format(keys[2].PublicKey().Address().Bytes()),
+	}, &reply); err != nil {
+		t.Fatal(err)
+	}
+
+	tx := UniqueTx{
+		vm:   vm,
+		txID: reply.TxID,
+	}
+	if err := tx.Verify(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if burned := burnedAVA(t, &tx); burned != vm.txFee {
+		t.Fatalf("Should have burned %d but burned %d", vm.txFee, burned)
+	}
+}
+
+func TestNFTPaysFee(t *testing.T) {
+	vm := nftVM(t)
+	ctx.Lock.Lock()
+	defer func() {
+		ctx.Lock.Unlock()
+		vm.Shutdown()
+		ctx.Keystore = nil
+	}()
+
+	avaID, err := vm.Lookup("asset1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	vm.ava = avaID
+	vm.txFee = 1000
+
+	s := Service{vm: vm}
+	setupUser(t, &s, "bob", 0)
+
+	createReply := CreateNFTAssetReply{}
+	if err := s.CreateNFTAsset(nil, &CreateNFTAssetArgs{
+		Username: "bob",
+		Password: strongPassword,
+		Name:     "Family",
+		Symbol:   "FAM",
+		MinterSets: []Owners{
+			Owners{
+				Threshold: 1,
+				Minters:   []string{vm.Format(keys[0].PublicKey().Address().Bytes())},
+			},
+		},
+	}, &createReply); err != nil {
+		t.Fatal(err)
+	}
+	createTx := UniqueTx{vm: vm, txID: createReply.AssetID}
+	if err := createTx.Verify(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if burned := burnedAVA(t, &createTx); burned != vm.txFee {
+		t.Fatalf("Creation should have burned %d but burned %d", vm.txFee, burned)
+	}
+	createTx.Accept(context.Background())
+
+	mintReply := MintNFTReply{}
+	if err := s.MintNFT(nil, &MintNFTArgs{
+		Username: "bob",
+		Password: strongPassword,
+		AssetID:  createReply.AssetID.String(),
+		Payload:  formatting.CB58{Bytes: []byte{1, 2, 3}},
+		To:       vm.Format(keys[0].PublicKey().Address().Bytes()),
+	}, &mintReply); err != nil {
+		t.Fatal(err)
+	}
+	mintTx := UniqueTx{vm: vm, txID: mintReply.TxID}
+	if err := mintTx.Verify(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if burned := burnedAVA(t, &mintTx); burned != vm.txFee {
+		t.Fatalf("Minting should have burned %d but burned %d", vm.txFee, burned)
+	}
+	mintTx.Accept(context.Background())
+
+	sendReply := SendNFTReply{}
+	if err := s.SendNFT(nil, &SendNFTArgs{
+		Username: "bob",
+		Password: strongPassword,
+		AssetID:  createReply.AssetID.String(),
+		To:       vm.Format(keys[2].PublicKey().Address().Bytes()),
+	}, &sendReply); err != nil {
+		t.Fatal(err)
+	}
+	sendTx := UniqueTx{vm: vm, txID: sendReply.TxID}
+	if err := sendTx.Verify(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if burned := burnedAVA(t, &sendTx); burned != vm.txFee {
+		t.Fatalf("Sending should have burned %d but burned %d", vm.txFee, burned)
+	}
+}
+
+func TestConsolidateUTXOsPaysFeeWithAVA(t *testing.T) {
+	vm := GenesisVM(t)
+	ctx.Lock.Lock()
+	defer func() {
+		ctx.Lock.Unlock()
+		vm.Shutdown()
+		ctx.Keystore = nil
+	}()
+
+	s := Service{vm: vm}
+	setupUser(t, &s, "bob", 0)
+
+	// Give keys[0] two UTXOs of asset3 to merge
+	for i := 0; i < 2; i++ {
+		reply := MintReply{}
+		if err := s.Mint(nil, &MintArgs{
+			Username: "bob",
+			Password: strongPassword,
+			Amount:   1000,
+			AssetID:  "asset3",
+			To:       vm.Format(keys[0].PublicKey().Address().Bytes()),
+		}, &reply); err != nil {
+			t.Fatal(err)
+		}
+		mintTx := UniqueTx{vm: vm, txID: reply.TxID}
+		mintTx.Accept(context.Background())
+	}
+
+	avaID, err := vm.Lookup("asset1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	vm.ava = avaID
+	vm.txFee = 1000
+
+	reply := ConsolidateUTXOsReply{}
+	if err := s.ConsolidateUTXOs(nil, &ConsolidateUTXOsArgs{
+		Username: "bob",
+		Password: strongPassword,
+		AssetID:  "asset3",
+	}, &reply); err != nil {
+		t.Fatal(err)
+	}
+	if len(reply.TxIDs) != 1 {
+		t.Fatalf("Should have issued %d transaction, issued %d", 1, len(reply.TxIDs))
+	}
+
+	tx := UniqueTx{
+		vm:   vm,
+		txID: reply.TxIDs[0],
+	}
+	if err := tx.Verify(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if burned := burnedAVA(t, &tx); burned != vm.txFee {
+		t.Fatalf("Should have burned %d but burned %d", vm.txFee, burned)
+	}
+}
+
+func TestSendToAddressBookName(t *testing.T) {
+	vm := GenesisVM(t)
+	ctx.Lock.Lock()
+	defer func() {
+		ctx.Lock.Unlock()
+		vm.Shutdown()
+		ctx.Keystore = nil
+	}()
+
+	avaID, err := vm.Lookup("asset1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	vm.ava = avaID
+
+	s := Service{vm: vm}
+	ks := setupUser(t, &s, "alice", 0)
+
+	to := keys[2].PublicKey().Address()
+	if err := ks.SetAddressBookEntry(nil, &keystore.SetAddressBookEntryArgs{
+		Username: "alice",
+		Password: strongPassword,
+		Name:     "carol",
+		Address:  vm.Format(to.Bytes()),
+	}, &keystore.SetAddressBookEntryReply{}); err != nil {
+		t.Fatal(err)
+	}
+
+	reply := SendReply{}
+	if err := s.Send(nil, &SendArgs{
+		Username: "alice",
+		Password: strongPassword,
+		Amount:   500,
+		AssetID:  avaID.String(),
+		To:       "carol",
+	}, &reply); err != nil {
+		t.Fatal(err)
+	}
+
+	tx := UniqueTx{vm: vm, txID: reply.TxID}
+	if err := tx.Verify(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	sent := false
+	for _, out := range tx.t.tx.UnsignedTx.(*BaseTx).Outs {
+		transferOut, ok := out.Output().(*secp256k1fx.TransferOutput)
+		if ok && transferOut.Amt == 500 && len(transferOut.Addrs) == 1 && transferOut.Addrs[0].Equals(to) {
+			sent = true
+		}
+	}
+	if !sent {
+		t.Fatalf("Should have sent to the address saved as 'carol'")
+	}
+
+	if err := s.Send(nil, &SendArgs{
+		Username: "alice",
+		Password: strongPassword,
+		Amount:   500,
+		AssetID:  avaID.String(),
+		To:       "dave",
+	}, &SendReply{}); err == nil {
+		t.Fatalf("Should have errored because 'dave' isn't in the address book")
+	}
+}
+
+func TestGetPendingTxs(t *testing.T) {
+	genesisBytes := BuildGenesisTest(t)
+	genesisTx := GetFirstTxFromGenesisTest(genesisBytes, t)
+	vm := GenesisVM(t)
+
+	ctx.Lock.Lock()
+	defer ctx.Lock.Unlock()
+
+	s := Service{vm: vm}
+
+	txID, err := vm.IssueTx(newTestMemoTx(vm, genesisTx, 1, t), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reply := GetPendingTxsReply{}
+	if err := s.GetPendingTxs(nil, &GetPendingTxsArgs{}, &reply); err != nil {
+		t.Fatal(err)
+	}
+	if len(reply.TxIDs) != 1 || !reply.TxIDs[0].Equals(txID) {
+		t.Fatalf("Wrong pending txs: %v", reply.TxIDs)
+	}
+
+	otherReply := GetPendingTxsReply{}
+	otherArgs := &GetPendingTxsArgs{Address: vm.Format(keys[2].PublicKey().Address().Bytes())}
+	if err := s.GetPendingTxs(nil, otherArgs, &otherReply); err != nil {
+		t.Fatal(err)
+	}
+	if len(otherReply.TxIDs) != 0 {
+		t.Fatalf("The tx doesn't involve the address")
+	}
+
+	vm.PendingTxs()
+
+	emptyReply := GetPendingTxsReply{}
+	if err := s.GetPendingTxs(nil, &GetPendingTxsArgs{}, &emptyReply); err != nil {
+		t.Fatal(err)
+	}
+	if len(emptyReply.TxIDs) != 0 {
+		t.Fatalf("Txs taken by consensus aren't pending")
+	}
+}
+
+// managedVM returns a VM running the secp256k1fx and the managedfx
+func managedVM(t *testing.T) *VM {
+	genesisBytes := BuildGenesisTest(t)
+
+	ctx.Lock.Lock()
+	defer ctx.Lock.Unlock()
+
+	vm := &VM{}
+	err := vm.Initialize(
+		ctx,
+		memdb.New(),
+		genesisBytes,
+		make(chan common.Message, 1),
+		[]*common.Fx{
+			&common.Fx{
+				ID: ids.Empty,
+				Fx: &secp256k1fx.Fx{},
+			},
+			&common.Fx{
+				ID: managedfx.ID,
+				Fx: &managedfx.Fx{},
+			},
+		},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	vm.batchTimeout = 0
+	return vm
+}
+
+// issueManagerOp issues and accepts an operation on [assetID] that consumes
+// [utxos], signing the manager's input with keys[0], and produces [outs]
+func issueManagerOp(t *testing.T, vm *VM, assetID ids.ID, utxos []*UTXO, outs []verify.Verifiable) *UniqueTx {
+	op := &Operation{Asset: Asset{ID: assetID}}
+	for _, utxo := range utxos {
+		in := &OperableInput{UTXOID: utxo.UTXOID}
+		if _, ok := utxo.Out.(*managedfx.ManagerOutput); ok {
+			in.In = &managedfx.ManagerInput{Input: secp256k1fx.Input{SigIndices: []uint32{0}}}
+		} else {
+			in.In = &managedfx.ClawbackInput{}
+		}
+		op.Ins = append(op.Ins, in)
+	}
+	sortOperableInputs(op.Ins)
+	for _, out := range outs {
+		op.Outs = append(op.Outs, &OperableOutput{Out: out})
+	}
+	SortOperableOutputs(op.Outs, vm.codec)
+
+	tx := &Tx{UnsignedTx: &OperationTx{
+		BaseTx: BaseTx{
+			NetID: networkID,
+			BCID:  chainID,
+		},
+		Ops: []*Operation{op},
+	}}
+	unsignedBytes, err := vm.codec.Marshal(&tx.UnsignedTx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig, err := keys[0].Sign(unsignedBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fixedSig := [crypto.SECP256K1RSigLen]byte{}
+	copy(fixedSig[:], sig)
+
+	for _, in := range op.Ins {
+		cred := &managedfx.Credential{}
+		if _, ok := in.In.(*managedfx.ManagerInput); ok {
+			cred.Sigs = append(cred.Sigs, fixedSig)
+		}
+		tx.Creds = append(tx.Creds, &Credential{Cred: cred})
+	}
+
+	b, err := vm.codec.Marshal(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	txID, err := vm.IssueTx(b, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	uniqueTx := &UniqueTx{vm: vm, txID: txID}
+	uniqueTx.Accept(context.Background())
+	return uniqueTx
+}
+
+func TestManagedAsset(t *testing.T) {
+	vm := managedVM(t)
+	ctx.Lock.Lock()
+	defer func() {
+		// Issuing the transaction starts the VM's timer, which waits on the
+		// lock
+		ctx.Lock.Unlock()
+		vm.Shutdown()
+		ctx.Keystore = nil
+	}()
+
+	s := Service{vm: vm}
+	setupUser(t, &s, "bob", 0)
+
+	manager := keys[0].PublicKey().Address()
+	holder := keys[1].PublicKey().Address()
+	createReply := CreateFixedCapAssetReply{}
+	if err := s.CreateFixedCapAsset(nil, &CreateFixedCapAssetArgs{
+		Username: "bob",
+		Password: strongPassword,
+		Name:     "Regulated",
+		Symbol:   "REG",
+		InitialHolders: []*Holder{&Holder{
+			Amount:  100,
+			Address: vm.Format(holder.Bytes()),
+		}},
+		Manager: &Owners{
+			Threshold: 1,
+			Minters:   []string{vm.Format(manager.Bytes())},
+		},
+	}, &createReply); err != nil {
+		t.Fatal(err)
+	}
+	assetID := createReply.AssetID
+	createTx := UniqueTx{vm: vm, txID: assetID}
+	createTx.Accept(context.Background())
+
+	var managerUTXO, holderUTXO *UTXO
+	for _, utxo := range createTx.UTXOs() {
+		switch utxo.Out.(type) {
+		case *managedfx.ManagerOutput:
+			managerUTXO = utxo
+		case *secp256k1fx.TransferOutput:
+			holderUTXO = utxo
+		}
+	}
+	switch {
+	case managerUTXO == nil || holderUTXO == nil:
+		t.Fatalf("The asset should have been created with a manager and a holder")
+	case !vm.verifyFxUsage(0, assetID):
+		t.Fatalf("Transfers of the asset shouldn't be frozen yet")
+	}
+
+	// Freeze transfers of the asset
+	freezeTx := issueManagerOp(t, vm, assetID, []*UTXO{managerUTXO}, []verify.Verifiable{
+		&managedfx.ManagerOutput{
+			Frozen:       true,
+			OutputOwners: secp256k1fx.OutputOwners{Threshold: 1, Addrs: []ids.ShortID{manager}},
+		},
+	})
+	switch {
+	case vm.verifyFxUsage(0, assetID):
+		t.Fatalf("Transfers of the asset should be frozen")
+	case !vm.verifyFxUsage(1, assetID):
+		t.Fatalf("The manager should still be able to operate on the asset")
+	}
+
+	// Claw back the holder's output and unfreeze the asset
+	clawbackTx := issueManagerOp(t, vm, assetID, []*UTXO{freezeTx.UTXOs()[0], holderUTXO}, []verify.Verifiable{
+		&managedfx.ManagerOutput{
+			OutputOwners: secp256k1fx.OutputOwners{Threshold: 1, Addrs: []ids.ShortID{manager}},
+		},
+		&secp256k1fx.TransferOutput{
+			Amt:          100,
+			OutputOwners: secp256k1fx.OutputOwners{Threshold: 1, Addrs: []ids.ShortID{manager}},
+		},
+	})
+	if !vm.verifyFxUsage(0, assetID) {
+		t.Fatalf("Transfers of the asset should have been unfrozen")
+	}
+	if _, err := vm.state.UTXO(holderUTXO.InputID()); err == nil {
+		t.Fatalf("The holder's output should have been clawed back")
+	}
+	clawedBack := false
+	for _, utxo := range clawbackTx.UTXOs() {
+		if out, ok := utxo.Out.(*secp256k1fx.TransferOutput); ok && out.Amt == 100 && out.Addrs[0].Equals(manager) {
+			clawedBack = true
+		}
+	}
+	if !clawedBack {
+		t.Fatalf("The clawed back amount should have been sent to the manager")
+	}
+}
+
+func TestCreateManagedAssetNotEnabled(t *testing.T) {
+	vm := GenesisVM(t)
+	defer vm.Shutdown()
+
+	s := Service{vm: vm}
+	if _, err := s.managerState(&Owners{Threshold: 1}); err != errManagedFxNotEnabled {
+		t.Fatalf("Expected %s but got %v", errManagedFxNotEnabled, err)
+	}
+}
+
+func TestGetUTXOSetCommitment(t *testing.T) {
+	genesisBytes := BuildGenesisTest(t)
+	genesisTx := GetFirstTxFromGenesisTest(genesisBytes, t)
+	vm := GenesisVM(t)
+	defer vm.Shutdown()
+	otherVM := GenesisVM(t)
+	defer otherVM.Shutdown()
+
+	ctx.Lock.Lock()
+	defer ctx.Lock.Unlock()
+
+	s := Service{vm: vm}
+	genesisReply := GetUTXOSetCommitmentReply{}
+	if err := s.GetUTXOSetCommitment(nil, &GetUTXOSetCommitmentArgs{}, &genesisReply); err != nil {
+		t.Fatal(err)
+	}
+
+	otherReply := GetUTXOSetCommitmentReply{}
+	if err := (&Service{vm: otherVM}).GetUTXOSetCommitment(nil, &GetUTXOSetCommitmentArgs{}, &otherReply); err != nil {
+		t.Fatal(err)
+	}
+	if !genesisReply.Commitment.Equals(otherReply.Commitment) {
+		t.Fatalf("Nodes with the same utxos should have the same commitment")
+	}
+
+	if _, err := vm.IssueTx(newTestMemoTx(vm, genesisTx, 1, t), nil); err != nil {
+		t.Fatal(err)
+	}
+	txs := vm.PendingTxs()
+	if len(txs) != 1 {
+		t.Fatalf("Should have issued the tx")
+	}
+	txs[0].Accept(context.Background())
+
+	acceptedReply := GetUTXOSetCommitmentReply{}
+	if err := s.GetUTXOSetCommitment(nil, &GetUTXOSetCommitmentArgs{}, &acceptedReply); err != nil {
+		t.Fatal(err)
+	}
+	if acceptedReply.Commitment.Equals(genesisReply.Commitment) {
+		t.Fatalf("Accepting a tx should have changed the commitment")
+	}
+}
+
+func TestGetUTXOSetCommitmentIncomplete(t *testing.T) {
+	vm := GenesisVM(t)
+	defer vm.Shutdown()
+
+	ctx.Lock.Lock()
+	defer ctx.Lock.Unlock()
+
+	// Simulate a database that was initialized before the commitment existed
+	if err := vm.state.SetUTXOSetHashInitialized(choices.Unknown); err != nil {
+		t.Fatal(err)
+	}
+
+	s := Service{vm: vm}
+	if err := s.GetUTXOSetCommitment(nil, &GetUTXOSetCommitmentArgs{}, &GetUTXOSetCommitmentReply{}); err != errUTXOSetHashIncomplete {
+		t.Fatalf("Should have errored with %s but got %v", errUTXOSetHashIncomplete, err)
+	}
+}
@@ -98,6 +98,70 @@ func (s *state) SetUTXO(id ids.ID, utxo *UTXO) error {
 	return s.vm.db.Put(id.Bytes(), bytes)
 }
 
+// UTXOSetHash returns a utxo set hash from storage.
+func (s *state) UTXOSetHash(id ids.ID) (*utxoSetHash, error) {
+	if hashIntf, found := s.c.Get(id); found {
+		if hash, ok := hashIntf.(utxoSetHash); ok {
+			return &hash, nil
+		}
+		return nil, errCacheTypeMismatch
+	}
+
+	bytes, err := s.vm.db.Get(id.Bytes())
+	if err != nil {
+		return nil, err
+	}
+
+	hash, err := parseUTXOSetHash(bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	s.c.Put(id, *hash)
+	return hash, nil
+}
+
+// SetUTXOSetHash saves a utxo set hash to storage.
+func (s *state) SetUTXOSetHash(id ids.ID, hash *utxoSetHash) error {
+	// The hash is cached by value so that callers can't modify the cached copy
+	s.c.Put(id, *hash)
+	return s.vm.db.Put(id.Bytes(), hash.Bytes())
+}
+
+// AssetStats returns the stats of an asset during a period from storage.
+func (s *state) AssetStats(id ids.ID) (assetStats, error) {
+	if statsIntf, found := s.c.Get(id); found {
+		if stats, ok := statsIntf.(assetStats); ok {
+			return stats, nil
+		}
+		return assetStats{}, errCacheTypeMismatch
+	}
+
+	bytes, err := s.vm.db.Get(id.Bytes())
+	if err != nil {
+		return assetStats{}, err
+	}
+
+	stats := assetStats{}
+	if err := s.vm.codec.Unmarshal(bytes, &stats); err != nil {
+		return assetStats{}, err
+	}
+
+	s.c.Put(id, stats)
+	return stats, nil
+}
+
+// SetAssetStats saves the stats of an asset during a period to storage.
+func (s *state) SetAssetStats(id ids.ID, stats assetStats) error {
+	s.c.Put(id, stats)
+
+	bytes, err := s.vm.codec.Marshal(&stats)
+	if err != nil {
+		return err
+	}
+	return s.vm.db.Put(id.Bytes(), bytes)
+}
+
 // Status returns a status from storage.
 func (s *state) Status(id ids.ID) (choices.Status, error) {
 	if statusIntf, found := s.c.Get(id); found {
@@ -174,3 +238,37 @@ func (s *state) SetIDs(id ids.ID, idSlice []ids.ID) error {
 
 	return s.vm.db.Put(id.Bytes(), bytes)
 }
+
+// Int returns an int from storage
+func (s *state) Int(id ids.ID) (uint64, error) {
+	if intIntf, found := s.c.Get(id); found {
+		if i, ok := intIntf.(uint64); ok {
+			return i, nil
+		}
+		return 0, errCacheTypeMismatch
+	}
+
+	bytes, err := s.vm.db.Get(id.Bytes())
+	if err != nil {
+		return 0, err
+	}
+
+	var i uint64
+	if err := s.vm.codec.Unmarshal(bytes, &i); err != nil {
+		return 0, err
+	}
+
+	s.c.Put(id, i)
+	return i, nil
+}
+
+// SetInt saves an int to storage
+func (s *state) SetInt(id ids.ID, i uint64) error {
+	s.c.Put(id, i)
+
+	bytes, err := s.vm.codec.Marshal(i)
+	if err != nil {
+		return err
+	}
+	return s.vm.db.Put(id.Bytes(), bytes)
+}
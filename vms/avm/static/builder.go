@@ -0,0 +1,367 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package static builds and signs AVM transactions without a node. A builder
+// turns a list of UTXOs into an unsigned transaction, whose bytes can be
+// carried to an air-gapped machine and signed there with SignTx.
+package static
+
+import (
+	"bytes"
+	"errors"
+	"sort"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow"
+	"github.com/ava-labs/gecko/utils/math"
+	"github.com/ava-labs/gecko/utils/timer"
+	"github.com/ava-labs/gecko/utils/wrappers"
+	"github.com/ava-labs/gecko/vms/avm"
+	"github.com/ava-labs/gecko/vms/components/codec"
+	"github.com/ava-labs/gecko/vms/secp256k1fx"
+)
+
+// numFxs is the number of feature extensions the builder's txs use. Only the
+// secp256k1fx is supported.
+const numFxs = 1
+
+var (
+	errNoAmount          = errors.New("amount must be positive")
+	errInsufficientFunds = errors.New("insufficient funds")
+	errNoMintOutput      = errors.New("no mint output of the asset can be spent by the signers")
+)
+
+// Codec serializes the txs the builder makes. Its types are registered in the
+// order the AVM registers them, so the txs it serializes can be issued to a
+// chain that uses the secp256k1fx as its only feature extension.
+var Codec codec.Codec
+
+func init() {
+	Codec = codec.NewDefault()
+	errs := wrappers.Errs{}
+	errs.Add(
+		Codec.RegisterType(&avm.BaseTx{}),
+		Codec.RegisterType(&avm.CreateAssetTx{}),
+		Codec.RegisterType(&avm.OperationTx{}),
+		Codec.RegisterType(&secp256k1fx.MintOutput{}),
+		Codec.RegisterType(&secp256k1fx.TransferOutput{}),
+		Codec.RegisterType(&secp256k1fx.MintInput{}),
+		Codec.RegisterType(&secp256k1fx.TransferInput{}),
+		Codec.RegisterType(&secp256k1fx.Credential{}),
+		Codec.RegisterType(&avm.ImportTx{}),
+		Codec.RegisterType(&avm.ExportTx{}),
+		Codec.RegisterType(&avm.MemoTx{}),
+	)
+	if errs.Errored() {
+		panic(errs.Err)
+	}
+}
+
+// UnsignedTx is a tx that's ready to be signed
+type UnsignedTx struct {
+	Tx *avm.Tx
+
+	// Signers[i] are the addresses whose keys must sign the tx's i-th
+	// credential, in the order their signatures must be given
+	Signers [][]ids.ShortID
+}
+
+// Bytes returns the serialized unsigned tx, which is what SignTx signs
+func (tx *UnsignedTx) Bytes() ([]byte, error) { return Codec.Marshal(&tx.Tx.UnsignedTx) }
+
+// Builder makes the txs of a chain
+type Builder struct {
+	NetworkID uint32
+	ChainID   ids.ID
+
+	// The asset that fees are paid in, and the fee paid by each tx
+	FeeAssetID ids.ID
+	TxFee      uint64
+
+	// Used to check that the UTXOs spent are no longer locked
+	clock timer.Clock
+}
+
+// NewBuilder returns a builder of txs that pay no fee for the chain [chainID]
+func NewBuilder(networkID uint32, chainID ids.ID) *Builder {
+	return &Builder{
+		NetworkID: networkID,
+		ChainID:   chainID,
+	}
+}
+
+// BaseTx returns a tx that sends [amount] of [assetID] to [to]. The UTXOs of
+// [utxos] that [signers] can spend pay for it, and the change is sent to
+// [changeAddr].
+func (b *Builder) BaseTx(
+	utxos []*avm.UTXO,
+	signers ids.ShortSet,
+	assetID ids.ID,
+	amount uint64,
+	to ids.ShortID,
+	changeAddr ids.ShortID,
+	memo []byte,
+) (*UnsignedTx, error) {
+	if amount == 0 {
+		return nil, errNoAmount
+	}
+
+	amounts := map[[32]byte]uint64{assetID.Key(): amount}
+	ins, inSigners, changes, err := b.spend(utxos, signers, amounts)
+	if err != nil {
+		return nil, err
+	}
+
+	outs := []*avm.TransferableOutput{transferOutput(assetID, amount, to)}
+	for _, changeOut := range changes {
+		outs = append(outs, transferOutput(changeOut.assetID, changeOut.amount, changeAddr))
+	}
+	avm.SortTransferableOutputs(outs, Codec)
+
+	tx := &avm.Tx{UnsignedTx: avm.WithMemo(&avm.BaseTx{
+		NetID: b.NetworkID,
+		BCID:  b.ChainID,
+		Outs:  outs,
+		Ins:   ins,
+	}, memo)}
+	return b.unsignedTx(tx, inSigners)
+}
+
+// MintTx returns a tx that mints [amount] of [assetID] to [to]. One of the
+// mint outputs of [utxos] that [signers] can spend is spent, and recreated
+// so the asset can be minted again. If a fee must be paid, the UTXOs of
+// [utxos] that [signers] can spend pay for it, and the change is sent to
+// [changeAddr].
+func (b *Builder) MintTx(
+	utxos []*avm.UTXO,
+	signers ids.ShortSet,
+	assetID ids.ID,
+	amount uint64,
+	to ids.ShortID,
+	changeAddr ids.ShortID,
+	memo []byte,
+) (*UnsignedTx, error) {
+	if amount == 0 {
+		return nil, errNoAmount
+	}
+
+	var (
+		op         *avm.Operation
+		opSigners  []ids.ShortID
+		mintOutput *secp256k1fx.MintOutput
+	)
+	for _, utxo := range utxos {
+		out, ok := utxo.Out.(*secp256k1fx.MintOutput)
+		if !ok || !utxo.AssetID().Equals(assetID) {
+			continue
+		}
+		sigIndices, addrs, ok := match(&out.OutputOwners, signers)
+		if !ok {
+			continue
+		}
+		op = &avm.Operation{
+			Asset: avm.Asset{ID: assetID},
+			Ins: []*avm.OperableInput{&avm.OperableInput{
+				UTXOID: utxo.UTXOID,
+				In: &secp256k1fx.MintInput{
+					Input: secp256k1fx.Input{SigIndices: sigIndices},
+				},
+			}},
+		}
+		opSigners, mintOutput = addrs, out
+		break
+	}
+	if op == nil {
+		return nil, errNoMintOutput
+	}
+	op.Outs = []*avm.OperableOutput{
+		&avm.OperableOutput{
+			Out: &secp256k1fx.MintOutput{OutputOwners: mintOutput.OutputOwners},
+		},
+		&avm.OperableOutput{
+			Out: &secp256k1fx.TransferOutput{
+				Amt: amount,
+				OutputOwners: secp256k1fx.OutputOwners{
+					Threshold: 1,
+					Addrs:     []ids.ShortID{to},
+				},
+			},
+		},
+	}
+	avm.SortOperableOutputs(op.Outs, Codec)
+
+	ins, inSigners, changes, err := b.spend(utxos, signers, map[[32]byte]uint64{})
+	if err != nil {
+		return nil, err
+	}
+	outs := []*avm.TransferableOutput(nil)
+	for _, changeOut := range changes {
+		outs = append(outs, transferOutput(changeOut.assetID, changeOut.amount, changeAddr))
+	}
+	avm.SortTransferableOutputs(outs, Codec)
+
+	tx := &avm.Tx{UnsignedTx: avm.WithMemo(&avm.OperationTx{
+		BaseTx: avm.BaseTx{
+			NetID: b.NetworkID,
+			BCID:  b.ChainID,
+			Outs:  outs,
+			Ins:   ins,
+		},
+		Ops: []*avm.Operation{op},
+	}, memo)}
+	// The credentials of the operation's inputs follow the ones of the
+	// transferable inputs
+	return b.unsignedTx(tx, append(inSigners, opSigners))
+}
+
+// change is the part of the UTXOs spent that is returned to the spender
+type change struct {
+	assetID ids.ID
+	amount  uint64
+}
+
+// spend returns the sorted inputs that consume enough of [utxos] to pay
+// [amounts], and the tx fee, along with the addresses that sign each input
+// and the change of each asset
+func (b *Builder) spend(
+	utxos []*avm.UTXO,
+	signers ids.ShortSet,
+	amounts map[[32]byte]uint64,
+) ([]*avm.TransferableInput, [][]ids.ShortID, []change, error) {
+	if b.TxFee != 0 {
+		fee, err := math.Add64(amounts[b.FeeAssetID.Key()], b.TxFee)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		amounts[b.FeeAssetID.Key()] = fee
+	}
+
+	now := b.clock.Unix()
+	spent := map[[32]byte]uint64{}
+	ins := []*avm.TransferableInput(nil)
+	inSigners := [][]ids.ShortID(nil)
+	for _, utxo := range utxos {
+		assetKey := utxo.AssetID().Key()
+		if spent[assetKey] >= amounts[assetKey] {
+			continue
+		}
+		out, ok := utxo.Out.(*secp256k1fx.TransferOutput)
+		if !ok || now < out.Locktime {
+			continue
+		}
+		sigIndices, addrs, ok := match(&out.OutputOwners, signers)
+		if !ok {
+			continue
+		}
+		amount, err := math.Add64(spent[assetKey], out.Amt)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		spent[assetKey] = amount
+
+		ins = append(ins, &avm.TransferableInput{
+			UTXOID: utxo.UTXOID,
+			Asset:  utxo.Asset,
+			In: &secp256k1fx.TransferInput{
+				Amt:   out.Amt,
+				Input: secp256k1fx.Input{SigIndices: sigIndices},
+			},
+		})
+		inSigners = append(inSigners, addrs)
+	}
+
+	changes := []change(nil)
+	for assetKey, amount := range amounts {
+		switch {
+		case spent[assetKey] < amount:
+			return nil, nil, nil, errInsufficientFunds
+		case spent[assetKey] > amount:
+			changes = append(changes, change{
+				assetID: ids.NewID(assetKey),
+				amount:  spent[assetKey] - amount,
+			})
+		}
+	}
+	sortTransferableInputsWithSigners(ins, inSigners)
+	return ins, inSigners, changes, nil
+}
+
+// unsignedTx checks that [tx] is well-formed before it's handed out
+func (b *Builder) unsignedTx(tx *avm.Tx, signers [][]ids.ShortID) (*UnsignedTx, error) {
+	// The tx's ID changes once it's signed
+	txBytes, err := Codec.Marshal(tx)
+	if err != nil {
+		return nil, err
+	}
+	tx.Initialize(txBytes)
+
+	ctx := &snow.Context{
+		NetworkID: b.NetworkID,
+		ChainID:   b.ChainID,
+	}
+	if err := tx.UnsignedTx.SyntacticVerify(ctx, Codec, numFxs); err != nil {
+		return nil, err
+	}
+	return &UnsignedTx{
+		Tx:      tx,
+		Signers: signers,
+	}, nil
+}
+
+// match returns the indices of the signatures that [owners] requires from
+// [signers], along with the addresses that give them. Returns false if
+// [signers] can't meet the threshold of [owners].
+func match(owners *secp256k1fx.OutputOwners, signers ids.ShortSet) ([]uint32, []ids.ShortID, bool) {
+	sigIndices := []uint32{}
+	addrs := []ids.ShortID{}
+	for i := uint32(0); i < uint32(len(owners.Addrs)) && uint32(len(addrs)) < owners.Threshold; i++ {
+		if signers.Contains(owners.Addrs[i]) {
+			sigIndices = append(sigIndices, i)
+			addrs = append(addrs, owners.Addrs[i])
+		}
+	}
+	return sigIndices, addrs, uint32(len(addrs)) == owners.Threshold
+}
+
+func transferOutput(assetID ids.ID, amount uint64, to ids.ShortID) *avm.TransferableOutput {
+	return &avm.TransferableOutput{
+		Asset: avm.Asset{ID: assetID},
+		Out: &secp256k1fx.TransferOutput{
+			Amt: amount,
+			OutputOwners: secp256k1fx.OutputOwners{
+				Threshold: 1,
+				Addrs:     []ids.ShortID{to},
+			},
+		},
+	}
+}
+
+type innerSortInputsWithSigners struct {
+	ins     []*avm.TransferableInput
+	signers [][]ids.ShortID
+}
+
+func (ins *innerSortInputsWithSigners) Less(i, j int) bool {
+	iID, iIndex := ins.ins[i].InputSource()
+	jID, jIndex := ins.ins[j].InputSource()
+
+	switch bytes.Compare(iID.Bytes(), jID.Bytes()) {
+	case -1:
+		return true
+	case 0:
+		return iIndex < jIndex
+	default:
+		return false
+	}
+}
+func (ins *innerSortInputsWithSigners) Len() int { return len(ins.ins) }
+func (ins *innerSortInputsWithSigners) Swap(i, j int) {
+	ins.ins[j], ins.ins[i] = ins.ins[i], ins.ins[j]
+	ins.signers[j], ins.signers[i] = ins.signers[i], ins.signers[j]
+}
+
+// sortTransferableInputsWithSigners sorts the inputs and the addresses that
+// sign them based on the input's utxo ID
+func sortTransferableInputsWithSigners(ins []*avm.TransferableInput, signers [][]ids.ShortID) {
+	sort.Sort(&innerSortInputsWithSigners{ins: ins, signers: signers})
+}
@@ -0,0 +1,228 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package static
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/crypto"
+	"github.com/ava-labs/gecko/utils/hashing"
+	"github.com/ava-labs/gecko/vms/avm"
+	"github.com/ava-labs/gecko/vms/secp256k1fx"
+)
+
+var (
+	networkID uint32 = 10
+	chainID          = ids.NewID([32]byte{5, 4, 3, 2, 1})
+	assetID          = ids.NewID([32]byte{1, 2, 3})
+	feeAssetID       = ids.NewID([32]byte{3, 2, 1})
+	to               = ids.NewShortID([20]byte{1})
+	changeAddr       = ids.NewShortID([20]byte{2})
+)
+
+func newKey(t *testing.T) *crypto.PrivateKeySECP256K1R {
+	factory := crypto.FactorySECP256K1R{}
+	key, err := factory.NewPrivateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key.(*crypto.PrivateKeySECP256K1R)
+}
+
+func newUTXO(txID byte, assetID ids.ID, out *secp256k1fx.TransferOutput) *avm.UTXO {
+	return &avm.UTXO{
+		UTXOID: avm.UTXOID{TxID: ids.NewID([32]byte{txID})},
+		Asset:  avm.Asset{ID: assetID},
+		Out:    out,
+	}
+}
+
+func ownedBy(amount uint64, addr ids.ShortID) *secp256k1fx.TransferOutput {
+	return &secp256k1fx.TransferOutput{
+		Amt: amount,
+		OutputOwners: secp256k1fx.OutputOwners{
+			Threshold: 1,
+			Addrs:     []ids.ShortID{addr},
+		},
+	}
+}
+
+func TestBuilderBaseTx(t *testing.T) {
+	addr := newKey(t).PublicKey().Address()
+	signers := ids.ShortSet{}
+	signers.Add(addr)
+	utxos := []*avm.UTXO{
+		newUTXO(2, assetID, ownedBy(30, addr)),
+		newUTXO(1, assetID, ownedBy(50, addr)),
+		newUTXO(3, assetID, ownedBy(10, addr)),
+	}
+
+	b := NewBuilder(networkID, chainID)
+	utx, err := b.BaseTx(utxos, signers, assetID, 60, to, changeAddr, []byte{1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	memoTx, ok := utx.Tx.UnsignedTx.(*avm.MemoTx)
+	if !ok || !bytes.Equal(memoTx.Memo, []byte{1}) {
+		t.Fatalf("The memo should be attached to the tx")
+	}
+	tx := memoTx.UnsignedTx.(*avm.BaseTx)
+	switch {
+	case len(tx.Ins) != 2:
+		t.Fatalf("Expected the first 2 utxos to be spent but %d were", len(tx.Ins))
+	case !tx.Ins[0].TxID.Equals(utxos[1].TxID):
+		t.Fatalf("Inputs should be sorted")
+	case len(utx.Signers) != 2 || !utx.Signers[0][0].Equals(addr):
+		t.Fatalf("Each input should be signed by %s", addr)
+	case len(tx.Outs) != 2:
+		t.Fatalf("Expected a payment and its change but got %d outputs", len(tx.Outs))
+	}
+
+	paid := map[[20]byte]uint64{}
+	for _, out := range tx.Outs {
+		out := out.Out.(*secp256k1fx.TransferOutput)
+		paid[out.Addrs[0].Key()] += out.Amt
+	}
+	if paid[to.Key()] != 60 || paid[changeAddr.Key()] != 20 {
+		t.Fatalf("Expected 60 to be paid and 20 to be returned but got %d and %d", paid[to.Key()], paid[changeAddr.Key()])
+	}
+}
+
+func TestBuilderBaseTxFee(t *testing.T) {
+	addr := newKey(t).PublicKey().Address()
+	signers := ids.ShortSet{}
+	signers.Add(addr)
+	utxos := []*avm.UTXO{
+		newUTXO(1, assetID, ownedBy(50, addr)),
+		newUTXO(2, feeAssetID, ownedBy(5, addr)),
+	}
+
+	b := NewBuilder(networkID, chainID)
+	b.FeeAssetID = feeAssetID
+	b.TxFee = 2
+	utx, err := b.BaseTx(utxos, signers, assetID, 50, to, changeAddr, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tx := utx.Tx.UnsignedTx.(*avm.BaseTx)
+	if len(tx.Ins) != 2 {
+		t.Fatalf("The fee should be paid from the fee asset's utxo")
+	}
+	for _, out := range tx.Outs {
+		if out.AssetID().Equals(feeAssetID) && out.Output().Amount() != 3 {
+			t.Fatalf("Expected 3 of the fee asset to be returned but got %d", out.Output().Amount())
+		}
+	}
+
+	b.TxFee = 6
+	if _, err := b.BaseTx(utxos, signers, assetID, 50, to, changeAddr, nil); err != errInsufficientFunds {
+		t.Fatalf("Expected %s but got %v", errInsufficientFunds, err)
+	}
+}
+
+func TestBuilderBaseTxUnspendable(t *testing.T) {
+	addr := newKey(t).PublicKey().Address()
+	signers := ids.ShortSet{}
+	signers.Add(addr)
+
+	locked := ownedBy(50, addr)
+	locked.Locktime = ^uint64(0)
+	utxos := []*avm.UTXO{
+		newUTXO(1, assetID, locked),
+		newUTXO(2, assetID, ownedBy(50, changeAddr)),
+	}
+
+	b := NewBuilder(networkID, chainID)
+	if _, err := b.BaseTx(utxos, signers, assetID, 1, to, changeAddr, nil); err != errInsufficientFunds {
+		t.Fatalf("Expected %s but got %v", errInsufficientFunds, err)
+	}
+}
+
+func TestBuilderMintTx(t *testing.T) {
+	addr := newKey(t).PublicKey().Address()
+	signers := ids.ShortSet{}
+	signers.Add(addr)
+	utxos := []*avm.UTXO{&avm.UTXO{
+		UTXOID: avm.UTXOID{TxID: assetID},
+		Asset:  avm.Asset{ID: assetID},
+		Out: &secp256k1fx.MintOutput{
+			OutputOwners: secp256k1fx.OutputOwners{
+				Threshold: 1,
+				Addrs:     []ids.ShortID{changeAddr, addr},
+			},
+		},
+	}}
+
+	b := NewBuilder(networkID, chainID)
+	utx, err := b.MintTx(utxos, signers, assetID, 100, to, changeAddr, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tx := utx.Tx.UnsignedTx.(*avm.OperationTx)
+	switch {
+	case len(tx.Ins) != 0 || len(tx.Ops) != 1:
+		t.Fatalf("Expected only a mint operation")
+	case len(tx.Ops[0].Outs) != 2:
+		t.Fatalf("The mint output should be recreated along with the minted output")
+	case len(utx.Signers) != 1 || !utx.Signers[0][0].Equals(addr):
+		t.Fatalf("The mint should be signed by %s", addr)
+	}
+	in := tx.Ops[0].Ins[0].In.(*secp256k1fx.MintInput)
+	if len(in.SigIndices) != 1 || in.SigIndices[0] != 1 {
+		t.Fatalf("The mint should be signed by the second minter")
+	}
+
+	if _, err := b.MintTx(utxos, ids.ShortSet{}, assetID, 100, to, changeAddr, nil); err != errNoMintOutput {
+		t.Fatalf("Expected %s but got %v", errNoMintOutput, err)
+	}
+}
+
+func TestSignTx(t *testing.T) {
+	key := newKey(t)
+	addr := key.PublicKey().Address()
+	signers := ids.ShortSet{}
+	signers.Add(addr)
+	utxos := []*avm.UTXO{newUTXO(1, assetID, ownedBy(50, addr))}
+
+	b := NewBuilder(networkID, chainID)
+	utx, err := b.BaseTx(utxos, signers, assetID, 50, to, changeAddr, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	unsignedBytes, err := utx.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := SignTx(unsignedBytes, nil); err != errWrongNumberOfSigners {
+		t.Fatalf("Expected %s but got %v", errWrongNumberOfSigners, err)
+	}
+
+	signedBytes, err := SignTx(unsignedBytes, [][]*crypto.PrivateKeySECP256K1R{{key}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx := &avm.Tx{}
+	if err := Codec.Unmarshal(signedBytes, tx); err != nil {
+		t.Fatal(err)
+	}
+	if len(tx.Creds) != 1 {
+		t.Fatalf("Expected 1 credential but got %d", len(tx.Creds))
+	}
+
+	factory := crypto.FactorySECP256K1R{}
+	sig := tx.Creds[0].Cred.(*secp256k1fx.Credential).Sigs[0]
+	pk, err := factory.RecoverHashPublicKey(hashing.ComputeHash256(unsignedBytes), sig[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !pk.Address().Equals(addr) {
+		t.Fatalf("The tx should have been signed by %s", addr)
+	}
+}
@@ -0,0 +1,71 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package static
+
+import (
+	"errors"
+
+	"github.com/ava-labs/gecko/snow"
+	"github.com/ava-labs/gecko/utils/crypto"
+	"github.com/ava-labs/gecko/utils/hashing"
+	"github.com/ava-labs/gecko/vms/avm"
+	"github.com/ava-labs/gecko/vms/secp256k1fx"
+)
+
+var (
+	errWrongNumberOfSigners = errors.New("a list of keys must be given for each input of the tx")
+)
+
+// ParseUnsignedTx returns the unsigned tx serialized in [unsignedBytes]
+func ParseUnsignedTx(unsignedBytes []byte) (*avm.Tx, error) {
+	tx := &avm.Tx{}
+	if err := Codec.Unmarshal(unsignedBytes, &tx.UnsignedTx); err != nil {
+		return nil, err
+	}
+	return tx, nil
+}
+
+// SignTx signs the unsigned tx serialized in [unsignedBytes] and returns the
+// signed tx, ready to be issued. [keys][i] are the keys that sign the tx's
+// i-th input, in the order of the input's signature indices.
+func SignTx(unsignedBytes []byte, keys [][]*crypto.PrivateKeySECP256K1R) ([]byte, error) {
+	tx, err := ParseUnsignedTx(unsignedBytes)
+	if err != nil {
+		return nil, err
+	}
+	if len(keys) != len(tx.InputUTXOs()) {
+		return nil, errWrongNumberOfSigners
+	}
+
+	hash := hashing.ComputeHash256(unsignedBytes)
+	for _, credKeys := range keys {
+		cred := &secp256k1fx.Credential{}
+		for _, key := range credKeys {
+			sig, err := key.SignHash(hash)
+			if err != nil {
+				return nil, err
+			}
+			fixedSig := [crypto.SECP256K1RSigLen]byte{}
+			copy(fixedSig[:], sig)
+
+			cred.Sigs = append(cred.Sigs, fixedSig)
+		}
+		tx.Creds = append(tx.Creds, &avm.Credential{Cred: cred})
+	}
+
+	b, err := Codec.Marshal(tx)
+	if err != nil {
+		return nil, err
+	}
+	tx.Initialize(b)
+
+	ctx := &snow.Context{
+		NetworkID: tx.NetworkID(),
+		ChainID:   tx.ChainID(),
+	}
+	if err := tx.SyntacticVerify(ctx, Codec, numFxs); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
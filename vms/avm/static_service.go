@@ -6,11 +6,11 @@ package avm
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 
 	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/utils/formatting"
-	"github.com/ava-labs/gecko/vms/components/codec"
 	"github.com/ava-labs/gecko/vms/secp256k1fx"
 
 	cjson "github.com/ava-labs/gecko/utils/json"
@@ -26,6 +26,10 @@ type StaticService struct{}
 // BuildGenesisArgs are arguments for BuildGenesis
 type BuildGenesisArgs struct {
 	GenesisData map[string]AssetDefinition `json:"genesisData"`
+
+	// TxFee is the amount of AVA each transaction must burn. If zero,
+	// transactions don't pay a fee.
+	TxFee cjson.Uint64 `json:"txFee"`
 }
 
 // AssetDefinition ...
@@ -44,15 +48,7 @@ type BuildGenesisReply struct {
 // BuildGenesis returns the UTXOs such that at least one address in [args.Addresses] is
 // referenced in the UTXO.
 func (*StaticService) BuildGenesis(_ *http.Request, args *BuildGenesisArgs, reply *BuildGenesisReply) error {
-	c := codec.NewDefault()
-	c.RegisterType(&BaseTx{})
-	c.RegisterType(&CreateAssetTx{})
-	c.RegisterType(&OperationTx{})
-	c.RegisterType(&secp256k1fx.MintOutput{})
-	c.RegisterType(&secp256k1fx.TransferOutput{})
-	c.RegisterType(&secp256k1fx.MintInput{})
-	c.RegisterType(&secp256k1fx.TransferInput{})
-	c.RegisterType(&secp256k1fx.Credential{})
+	c := newStaticCodec()
 
 	g := Genesis{}
 	for assetAlias, assetDefinition := range args.GenesisData {
@@ -90,13 +86,20 @@ func (*StaticService) BuildGenesis(_ *http.Request, args *BuildGenesisArgs, repl
 					if err != nil {
 						return err
 					}
-					initialState.Outs = append(initialState.Outs, &secp256k1fx.TransferOutput{
-						Amt: uint64(holder.Amount),
-						OutputOwners: secp256k1fx.OutputOwners{
-							Threshold: 1,
-							Addrs:     []ids.ShortID{addr},
-						},
-					})
+					periods, err := holder.schedule()
+					if err != nil {
+						return fmt.Errorf("invalid holder %s of %s: %w", holder.Address, assetAlias, err)
+					}
+					for _, period := range periods {
+						initialState.Outs = append(initialState.Outs, &secp256k1fx.TransferOutput{
+							Amt:      uint64(period.Amount),
+							Locktime: uint64(period.Locktime),
+							OutputOwners: secp256k1fx.OutputOwners{
+								Threshold: 1,
+								Addrs:     []ids.ShortID{addr},
+							},
+						})
+					}
 				}
 				initialState.Sort(c)
 				asset.States = append(asset.States, initialState)
@@ -145,7 +148,18 @@ func (*StaticService) BuildGenesis(_ *http.Request, args *BuildGenesisArgs, repl
 	}
 	g.Sort()
 
-	b, err := c.Marshal(&g)
+	var (
+		b   []byte
+		err error
+	)
+	if args.TxFee == 0 {
+		b, err = c.Marshal(&g)
+	} else {
+		b, err = c.Marshal(&FeeGenesis{
+			Genesis: g,
+			TxFee:   uint64(args.TxFee),
+		})
+	}
 	if err != nil {
 		return err
 	}
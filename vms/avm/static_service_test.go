@@ -4,9 +4,11 @@
 package avm
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/ava-labs/gecko/utils/formatting"
+	"github.com/ava-labs/gecko/vms/secp256k1fx"
 )
 
 func TestBuildGenesis(t *testing.T) {
@@ -96,3 +98,135 @@ func TestBuildGenesis(t *testing.T) {
 		)
 	}
 }
+
+func TestBuildGenesisVesting(t *testing.T) {
+	ss := StaticService{}
+
+	args := BuildGenesisArgs{GenesisData: map[string]AssetDefinition{
+		"asset1": AssetDefinition{
+			Name:   "myVestingAsset",
+			Symbol: "MVA",
+			InitialState: map[string][]interface{}{
+				"fixedCap": []interface{}{
+					Holder{
+						Amount:  100000,
+						Address: "A9bTQjfYGBFK3JPRJqF2eh3JYL7cHocvy",
+						Vesting: []VestingPeriod{
+							{Amount: 25000, Locktime: 1000},
+							{Amount: 25000, Locktime: 2000},
+							{Amount: 50000, Locktime: 3000},
+						},
+					},
+				},
+			},
+		},
+	}}
+	reply := BuildGenesisReply{}
+	if err := ss.BuildGenesis(nil, &args, &reply); err != nil {
+		t.Fatal(err)
+	}
+
+	genesis := Genesis{}
+	if err := newStaticCodec().Unmarshal(reply.Bytes.Bytes, &genesis); err != nil {
+		t.Fatal(err)
+	}
+	if len(genesis.Txs) != 1 || len(genesis.Txs[0].States) != 1 {
+		t.Fatalf("Genesis should have a single asset with a single initial state")
+	}
+	outs := genesis.Txs[0].States[0].Outs
+	if len(outs) != 3 {
+		t.Fatalf("Should have created %d outputs, created %d", 3, len(outs))
+	}
+	unlocked := map[uint64]uint64{}
+	for _, outIntf := range outs {
+		out, ok := outIntf.(*secp256k1fx.TransferOutput)
+		if !ok {
+			t.Fatalf("Wrong output type %T", outIntf)
+		}
+		unlocked[out.Locktime] += out.Amt
+	}
+	if unlocked[1000] != 25000 || unlocked[2000] != 25000 || unlocked[3000] != 50000 {
+		t.Fatalf("Wrong vesting schedule: %v", unlocked)
+	}
+}
+
+func TestBuildGenesisTxFee(t *testing.T) {
+	ss := StaticService{}
+
+	args := BuildGenesisArgs{GenesisData: map[string]AssetDefinition{
+		"AVA": AssetDefinition{
+			Name:   "AVA",
+			Symbol: "AVA",
+			InitialState: map[string][]interface{}{
+				"fixedCap": []interface{}{
+					Holder{
+						Amount:  100000,
+						Address: "A9bTQjfYGBFK3JPRJqF2eh3JYL7cHocvy",
+					},
+				},
+			},
+		},
+	}}
+	noFeeReply := BuildGenesisReply{}
+	if err := ss.BuildGenesis(nil, &args, &noFeeReply); err != nil {
+		t.Fatal(err)
+	}
+	args.TxFee = 1000
+	feeReply := BuildGenesisReply{}
+	if err := ss.BuildGenesis(nil, &args, &feeReply); err != nil {
+		t.Fatal(err)
+	}
+
+	// A genesis without a fee keeps the format it had before fees existed
+	if err := newStaticCodec().Unmarshal(noFeeReply.Bytes.Bytes, &Genesis{}); err != nil {
+		t.Fatal(err)
+	}
+	if txFee, err := GenesisTxFee(noFeeReply.Bytes.Bytes); err != nil {
+		t.Fatal(err)
+	} else if txFee != 0 {
+		t.Fatalf("Genesis shouldn't have set a fee, set %d", txFee)
+	}
+	if txFee, err := GenesisTxFee(feeReply.Bytes.Bytes); err != nil {
+		t.Fatal(err)
+	} else if txFee != 1000 {
+		t.Fatalf("Genesis should have set a fee of %d, set %d", 1000, txFee)
+	}
+
+	noFeeAssetID, err := GenesisAssetID(noFeeReply.Bytes.Bytes, "AVA")
+	if err != nil {
+		t.Fatal(err)
+	}
+	feeAssetID, err := GenesisAssetID(feeReply.Bytes.Bytes, "AVA")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !noFeeAssetID.Equals(feeAssetID) {
+		t.Fatalf("The fee shouldn't change the genesis asset's ID")
+	}
+}
+
+func TestBuildGenesisVestingSumMismatch(t *testing.T) {
+	ss := StaticService{}
+
+	args := BuildGenesisArgs{GenesisData: map[string]AssetDefinition{
+		"asset1": AssetDefinition{
+			Name:   "myVestingAsset",
+			Symbol: "MVA",
+			InitialState: map[string][]interface{}{
+				"fixedCap": []interface{}{
+					Holder{
+						Amount:  100000,
+						Address: "A9bTQjfYGBFK3JPRJqF2eh3JYL7cHocvy",
+						Vesting: []VestingPeriod{
+							{Amount: 25000, Locktime: 1000},
+							{Amount: 25000, Locktime: 2000},
+						},
+					},
+				},
+			},
+		},
+	}}
+	if err := ss.BuildGenesis(nil, &args, &BuildGenesisReply{}); !errors.Is(err, errVestingSum) {
+		t.Fatalf("Expected %s but got %v", errVestingSum, err)
+	}
+}
@@ -5,7 +5,6 @@ package avm
 
 import (
 	"bytes"
-	"errors"
 	"sort"
 
 	"github.com/ava-labs/gecko/utils"
@@ -14,11 +13,11 @@ import (
 )
 
 var (
-	errNilTransferableOutput   = errors.New("nil transferable output is not valid")
-	errNilTransferableFxOutput = errors.New("nil transferable feature extension output is not valid")
+	errNilTransferableOutput   = verify.NewError(CodeNilTransferableOutput, "nil transferable output is not valid")
+	errNilTransferableFxOutput = verify.NewError(CodeNilTransferableFxOutput, "nil transferable feature extension output is not valid")
 
-	errNilTransferableInput   = errors.New("nil transferable input is not valid")
-	errNilTransferableFxInput = errors.New("nil transferable feature extension input is not valid")
+	errNilTransferableInput   = verify.NewError(CodeNilTransferableInput, "nil transferable input is not valid")
+	errNilTransferableFxInput = verify.NewError(CodeNilTransferableFxInput, "nil transferable feature extension input is not valid")
 )
 
 // TransferableOutput ...
@@ -126,7 +125,11 @@ func (ins innerSortTransferableInputs) Less(i, j int) bool {
 func (ins innerSortTransferableInputs) Len() int      { return len(ins) }
 func (ins innerSortTransferableInputs) Swap(i, j int) { ins[j], ins[i] = ins[i], ins[j] }
 
-func sortTransferableInputs(ins []*TransferableInput) { sort.Sort(innerSortTransferableInputs(ins)) }
-func isSortedAndUniqueTransferableInputs(ins []*TransferableInput) bool {
+// SortTransferableInputs sorts input objects
+func SortTransferableInputs(ins []*TransferableInput) { sort.Sort(innerSortTransferableInputs(ins)) }
+
+// IsSortedAndUniqueTransferableInputs returns true if input objects are sorted
+// and unique
+func IsSortedAndUniqueTransferableInputs(ins []*TransferableInput) bool {
 	return utils.IsSortedAndUnique(innerSortTransferableInputs(ins))
 }
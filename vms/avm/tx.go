@@ -4,16 +4,15 @@
 package avm
 
 import (
-	"errors"
-
 	"github.com/ava-labs/gecko/ids"
 
 	"github.com/ava-labs/gecko/snow"
 	"github.com/ava-labs/gecko/vms/components/codec"
+	"github.com/ava-labs/gecko/vms/components/verify"
 )
 
 var (
-	errWrongNumberOfCredentials = errors.New("should have the same number of credentials as inputs")
+	errWrongNumberOfCredentials = verify.NewError(CodeWrongNumberOfCredentials, "should have the same number of credentials as inputs")
 )
 
 // UnsignedTx ...
@@ -26,6 +25,7 @@ type UnsignedTx interface {
 	ChainID() ids.ID
 	Outputs() []*TransferableOutput
 	Inputs() []*TransferableInput
+	MemoBytes() []byte
 
 	AssetIDs() ids.Set
 	InputUTXOs() []*UTXOID
@@ -34,6 +34,14 @@ type UnsignedTx interface {
 	SemanticVerify(vm *VM, uTx *UniqueTx, creds []*Credential) error
 }
 
+// atomicTx is a transaction that moves UTXOs between this chain and the
+// Platform Chain through the memory they share
+type atomicTx interface {
+	// acceptShared records in [batch] the changes to the memory this chain
+	// shares with the Platform Chain once the tx is accepted
+	acceptShared(vm *VM, batch *AtomicUTXOsBatch) error
+}
+
 // Tx is the core operation that can be performed. The tx uses the UTXO model.
 // Specifically, a txs inputs will consume previous txs outputs. A tx will be
 // valid if the inputs have the authority to consume the outputs they are
@@ -4,18 +4,20 @@
 package avm
 
 import (
-	"errors"
+	"context"
 
 	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/snow/choices"
 	"github.com/ava-labs/gecko/snow/consensus/snowstorm"
+	"github.com/ava-labs/gecko/utils/hashing"
+	"github.com/ava-labs/gecko/vms/components/verify"
 )
 
 var (
-	errAssetIDMismatch = errors.New("asset IDs in the input don't match the utxo")
-	errMissingUTXO     = errors.New("missing utxo")
-	errUnknownTx       = errors.New("transaction is unknown")
-	errRejectedTx      = errors.New("transaction is rejected")
+	errAssetIDMismatch = verify.NewError(CodeAssetIDMismatch, "asset IDs in the input don't match the utxo")
+	errMissingUTXO     = verify.NewError(CodeMissingUTXO, "missing utxo")
+	errUnknownTx       = verify.NewError(CodeUnknownTx, "transaction is unknown")
+	errRejectedTx      = verify.NewError(CodeRejectedTx, "transaction is rejected")
 )
 
 // UniqueTx provides a de-duplication service for txs. This only provides a
@@ -36,6 +38,10 @@ type txState struct {
 	utxos      []*UTXO
 	deps       []snowstorm.Tx
 
+	// The unsigned bytes of the tx and their hash, which every credential
+	// signs. They're computed once rather than for every credential.
+	unsignedBytes, unsignedHash []byte
+
 	status choices.Status
 
 	onDecide func(choices.Status)
@@ -94,14 +100,41 @@ func (tx *UniqueTx) setStatus(status choices.Status) error {
 func (tx *UniqueTx) ID() ids.ID { return tx.txID }
 
 // Accept is called when the transaction was finalized as accepted by consensus
-func (tx *UniqueTx) Accept() {
+func (tx *UniqueTx) Accept(context.Context) {
 	if err := tx.setStatus(choices.Accepted); err != nil {
 		tx.vm.ctx.Log.Error("Failed to accept tx %s due to %s", tx.txID, err)
 		return
 	}
 
-	// Remove spent utxos
-	for _, utxoID := range tx.InputIDs().List() {
+	// The addresses and assets this tx touches. Used by pubsub subscribers to
+	// filter the accepted txs.
+	filterKeys := [][]byte(nil)
+	// The addresses of the utxos this tx spends and produces on this chain or
+	// exports. Used to index the tx by address.
+	addrs := ids.Set{}
+	// The activity of each asset this tx touches. Used to update the asset
+	// stats.
+	activities := assetActivities{}
+	for _, assetID := range tx.t.tx.AssetIDs().List() {
+		filterKeys = append(filterKeys, assetID.Bytes())
+		activities.get(assetID)
+	}
+
+	// Remove spent utxos. Imported utxos aren't in this chain's utxo set.
+	for _, input := range tx.InputUTXOs() {
+		if input.Imported() {
+			continue
+		}
+		utxoID := input.InputID()
+		utxo, err := tx.vm.state.UTXO(utxoID)
+		if err != nil {
+			tx.vm.ctx.Log.Error("Failed to fetch utxo %s due to %s", utxoID, err)
+			return
+		}
+		filterKeys = append(filterKeys, utxoFilterKeys(utxo)...)
+		addUTXOAddresses(addrs, utxo)
+		activities.spent(utxo)
+
 		if err := tx.vm.state.SpendUTXO(utxoID); err != nil {
 			tx.vm.ctx.Log.Error("Failed to spend utxo %s due to %s", utxoID, err)
 			return
@@ -110,20 +143,82 @@ func (tx *UniqueTx) Accept() {
 
 	// Add new utxos
 	for _, utxo := range tx.UTXOs() {
+		filterKeys = append(filterKeys, utxoFilterKeys(utxo)...)
+		addUTXOAddresses(addrs, utxo)
+		activities.produced(utxo)
+
 		if err := tx.vm.state.FundUTXO(utxo); err != nil {
 			tx.vm.ctx.Log.Error("Failed to fund utxo %s due to %s", utxoID, err)
 			return
 		}
+
+		// Freeze or unfreeze transfers of the utxo's asset
+		if freezable, ok := utxo.Out.(FxFreezable); ok {
+			freezer := uint64(0)
+			if freezable.IsFrozen() {
+				fxIndex, err := tx.vm.getFx(utxo.Out)
+				if err != nil {
+					tx.vm.ctx.Log.Error("Failed to find the fx of %s due to %s", utxo.InputID(), err)
+					return
+				}
+				freezer = uint64(fxIndex) + 1
+			}
+			if err := tx.vm.state.SetAssetFreezer(utxo.AssetID(), freezer); err != nil {
+				tx.vm.ctx.Log.Error("Failed to freeze asset %s due to %s", utxo.AssetID(), err)
+				return
+			}
+		}
+	}
+
+	// Move utxos to or from the Platform Chain. The shared memory is only
+	// written to when this chain's state is committed, so that either both or
+	// neither are persisted.
+	sharedBatch := (*AtomicUTXOsBatch)(nil)
+	if shared, ok := innerTx(tx.t.tx.UnsignedTx).(atomicTx); ok {
+		smDB := tx.vm.ctx.SharedMemory.GetDatabase(tx.vm.platform)
+		defer tx.vm.ctx.SharedMemory.ReleaseDatabase(tx.vm.platform)
+
+		sharedBatch = NewAtomicUTXOs(smDB).NewBatch()
+		if err := shared.acceptShared(tx.vm, sharedBatch); err != nil {
+			tx.vm.ctx.Log.Error("Failed to update the shared memory for %s due to %s", tx.txID, err)
+			return
+		}
 	}
 
 	txID := tx.ID()
 	tx.vm.ctx.Log.Verbo("Accepting Tx: %s", txID)
 
-	if err := tx.vm.db.Commit(); err != nil {
+	if exportTx, ok := innerTx(tx.t.tx.UnsignedTx).(*ExportTx); ok {
+		for _, utxo := range exportTx.ExportedUTXOs() {
+			addUTXOAddresses(addrs, utxo)
+			activities.produced(utxo)
+		}
+	}
+
+	if tx.vm.indexTxs {
+		if err := tx.vm.state.IndexAddressTx(txID, addrs); err != nil {
+			tx.vm.ctx.Log.Error("Failed to index %s by address due to %s", tx.txID, err)
+			return
+		}
+	}
+
+	if tx.vm.indexAssetStats {
+		period := assetStatsPeriodAt(tx.vm.clock.Unix())
+		if err := tx.vm.state.IndexAssetStats(period, activities); err != nil {
+			tx.vm.ctx.Log.Error("Failed to update the asset stats with %s due to %s", tx.txID, err)
+			return
+		}
+	}
+
+	if err := tx.vm.unpersistTx(txID); err != nil {
+		tx.vm.ctx.Log.Error("Failed to stop re-issuing tx %s due to %s", txID, err)
+	}
+
+	if err := tx.vm.commit(sharedBatch); err != nil {
 		tx.vm.ctx.Log.Error("Failed to commit accept %s due to %s", tx.txID, err)
 	}
 
-	tx.vm.pubsub.Publish("accepted", txID)
+	tx.vm.pubsub.PublishFiltered("accepted", txID, filterKeys)
 
 	tx.t.deps = nil // Needed to prevent a memory leak
 
@@ -132,6 +227,32 @@ func (tx *UniqueTx) Accept() {
 	}
 }
 
+// utxoFilterKeys returns the asset ID and the owners' addresses of [utxo]
+func utxoFilterKeys(utxo *UTXO) [][]byte {
+	keys := [][]byte{utxo.AssetID().Bytes()}
+	if addressable, ok := utxo.Out.(FxAddressable); ok {
+		keys = append(keys, addressable.Addresses()...)
+	}
+	return keys
+}
+
+// RejectedBy is called by consensus before Reject with the ID of the
+// transaction that caused this transaction to be rejected
+func (tx *UniqueTx) RejectedBy(causeID ids.ID) {
+	if err := tx.vm.state.SetRejectionCause(tx.ID(), causeID); err != nil {
+		tx.vm.ctx.Log.Error("Failed to record rejection cause of tx %s due to %s", tx.txID, err)
+	}
+}
+
+// addUTXOAddresses adds the IDs of the addresses that own [utxo] to [addrs]
+func addUTXOAddresses(addrs ids.Set, utxo *UTXO) {
+	if addressable, ok := utxo.Out.(FxAddressable); ok {
+		for _, addr := range addressable.Addresses() {
+			addrs.Add(ids.NewID(hashing.ComputeHash256Array(addr)))
+		}
+	}
+}
+
 // Reject is called when the transaction was finalized as rejected by consensus
 func (tx *UniqueTx) Reject() {
 	if err := tx.setStatus(choices.Rejected); err != nil {
@@ -142,6 +263,9 @@ func (tx *UniqueTx) Reject() {
 	txID := tx.ID()
 	tx.vm.ctx.Log.Debug("Rejecting Tx: %s", txID)
 
+	if err := tx.vm.unpersistTx(txID); err != nil {
+		tx.vm.ctx.Log.Error("Failed to stop re-issuing tx %s due to %s", txID, err)
+	}
 	if err := tx.vm.db.Commit(); err != nil {
 		tx.vm.ctx.Log.Error("Failed to commit reject %s due to %s", tx.txID, err)
 	}
@@ -170,6 +294,10 @@ func (tx *UniqueTx) Dependencies() []snowstorm.Tx {
 
 	txIDs := ids.Set{}
 	for _, in := range tx.InputUTXOs() {
+		if in.Imported() {
+			// Imported utxos were produced by another chain's txs
+			continue
+		}
 		txID, _ := in.InputSource()
 		if !txIDs.Contains(txID) {
 			txIDs.Add(txID)
@@ -230,8 +358,16 @@ func (tx *UniqueTx) Bytes() []byte {
 	return tx.t.tx.Bytes()
 }
 
-// Verify the validity of this transaction
-func (tx *UniqueTx) Verify() error {
+// Verify the validity of this transaction. Verification is abandoned once
+// [ctx] is done.
+func (tx *UniqueTx) Verify(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return tx.verify()
+}
+
+func (tx *UniqueTx) verify() error {
 	switch status := tx.Status(); status {
 	case choices.Unknown:
 		return errUnknownTx
@@ -278,9 +414,31 @@ func (tx *UniqueTx) SemanticVerify() error {
 	return tx.t.validity
 }
 
+// verified returns true, along with the result, if this transaction has already
+// been verified. Unlike Verify, it never verifies the transaction itself.
+func (tx *UniqueTx) verified() (bool, error) {
+	tx.refresh()
+
+	if tx.t.verifiedState || (tx.t.verifiedTx && tx.t.validity != nil) {
+		return true, tx.t.validity
+	}
+	return false, nil
+}
+
 // UnsignedBytes returns the unsigned bytes of the transaction
 func (tx *UniqueTx) UnsignedBytes() []byte {
-	b, err := tx.vm.codec.Marshal(&tx.t.tx.UnsignedTx)
-	tx.vm.ctx.Log.AssertNoError(err)
-	return b
+	if tx.t.unsignedBytes == nil {
+		b, err := tx.vm.codec.Marshal(&tx.t.tx.UnsignedTx)
+		tx.vm.ctx.Log.AssertNoError(err)
+		tx.t.unsignedBytes = b
+	}
+	return tx.t.unsignedBytes
+}
+
+// UnsignedHash returns the hash of the unsigned bytes of the transaction
+func (tx *UniqueTx) UnsignedHash() []byte {
+	if tx.t.unsignedHash == nil {
+		tx.t.unsignedHash = hashing.ComputeHash256(tx.UnsignedBytes())
+	}
+	return tx.t.unsignedHash
 }
@@ -4,14 +4,12 @@
 package avm
 
 import (
-	"errors"
-
 	"github.com/ava-labs/gecko/vms/components/verify"
 )
 
 var (
-	errNilUTXO   = errors.New("nil utxo is not valid")
-	errEmptyUTXO = errors.New("empty utxo is not valid")
+	errNilUTXO   = verify.NewError(CodeNilUTXO, "nil utxo is not valid")
+	errEmptyUTXO = verify.NewError(CodeEmptyUTXO, "empty utxo is not valid")
 )
 
 // UTXO ...
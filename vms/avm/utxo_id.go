@@ -4,14 +4,13 @@
 package avm
 
 import (
-	"errors"
-
 	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/vms/components/verify"
 )
 
 var (
-	errNilUTXOID = errors.New("nil utxo ID is not valid")
-	errNilTxID   = errors.New("nil tx ID is not valid")
+	errNilUTXOID = verify.NewError(CodeNilUTXOID, "nil utxo ID is not valid")
+	errNilTxID   = verify.NewError(CodeNilTxID, "nil tx ID is not valid")
 )
 
 // UTXOID ...
@@ -22,6 +21,10 @@ type UTXOID struct {
 
 	// Cached:
 	id ids.ID
+
+	// imported is true if the UTXO was sent to this chain by another chain,
+	// so it isn't in this chain's UTXO set
+	imported bool
 }
 
 // InputSource returns the source of the UTXO that this input is spending
@@ -35,6 +38,9 @@ func (utxo *UTXOID) InputID() ids.ID {
 	return utxo.id
 }
 
+// Imported returns true if the UTXO was sent to this chain by another chain
+func (utxo *UTXOID) Imported() bool { return utxo.imported }
+
 // Verify implements the verify.Verifiable interface
 func (utxo *UTXOID) Verify() error {
 	switch {
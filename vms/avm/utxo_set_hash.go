@@ -0,0 +1,89 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avm
+
+import (
+	"encoding/binary"
+	"errors"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/hashing"
+)
+
+const (
+	// utxoSetHashLanes is the number of 16 bit lanes of a utxo set hash
+	utxoSetHashLanes = 1024
+
+	// utxoSetHashLen is the number of bytes of a utxo set hash
+	utxoSetHashLen = 2 * utxoSetHashLanes
+
+	// lanesPerBlock is the number of lanes filled by each hash of an element
+	lanesPerBlock = hashing.HashLen / 2
+)
+
+var (
+	errWrongUTXOSetHashLen = errors.New("wrong utxo set hash length")
+)
+
+// utxoSetHash is a homomorphic hash of a set of utxos. Each utxo is expanded
+// into 1024 16 bit lanes, and the hash of the set is the lane by lane sum,
+// modulo 2^16, of the expansions of its utxos. Adding or removing a utxo
+// updates the hash without rehashing the set, and two nodes with the same
+// utxos have the same hash no matter the order the utxos were added in.
+//
+// This is the lattice based LtHash construction. Its security rests on the
+// hardness of finding a short vector that sums to zero, which is why the hash
+// is much longer than the 32 byte commitment reported from it.
+type utxoSetHash [utxoSetHashLanes]uint16
+
+// Add the element whose bytes are [b] to the set
+func (h *utxoSetHash) Add(b []byte) {
+	expandUTXOSetElement(b, func(i int, lane uint16) { h[i] += lane })
+}
+
+// Remove the element whose bytes are [b] from the set
+func (h *utxoSetHash) Remove(b []byte) {
+	expandUTXOSetElement(b, func(i int, lane uint16) { h[i] -= lane })
+}
+
+// Bytes returns the binary representation of this hash
+func (h *utxoSetHash) Bytes() []byte {
+	b := make([]byte, utxoSetHashLen)
+	for i, lane := range h {
+		binary.BigEndian.PutUint16(b[2*i:], lane)
+	}
+	return b
+}
+
+// Commitment returns a 32 byte commitment to the set
+func (h *utxoSetHash) Commitment() ids.ID {
+	return ids.NewID(hashing.ComputeHash256Array(h.Bytes()))
+}
+
+// parseUTXOSetHash parses the binary representation of a utxo set hash
+func parseUTXOSetHash(b []byte) (*utxoSetHash, error) {
+	if len(b) != utxoSetHashLen {
+		return nil, errWrongUTXOSetHashLen
+	}
+	h := &utxoSetHash{}
+	for i := range h {
+		h[i] = binary.BigEndian.Uint16(b[2*i:])
+	}
+	return h, nil
+}
+
+// expandUTXOSetElement calls [f] with each lane of the expansion of the
+// element whose bytes are [b]. The expansion is the concatenation of the
+// hashes of [b] prefixed with a counter.
+func expandUTXOSetElement(b []byte, f func(int, uint16)) {
+	input := make([]byte, 4+len(b))
+	copy(input[4:], b)
+	for block := 0; block < utxoSetHashLanes/lanesPerBlock; block++ {
+		binary.BigEndian.PutUint32(input, uint32(block))
+		hash := hashing.ComputeHash256(input)
+		for i := 0; i < lanesPerBlock; i++ {
+			f(block*lanesPerBlock+i, binary.BigEndian.Uint16(hash[2*i:]))
+		}
+	}
+}
@@ -0,0 +1,68 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avm
+
+import (
+	"testing"
+)
+
+func TestUTXOSetHashOrderIndependent(t *testing.T) {
+	a, b, c := []byte{1}, []byte{2}, []byte{3}
+
+	first := utxoSetHash{}
+	first.Add(a)
+	first.Add(b)
+	first.Add(c)
+
+	second := utxoSetHash{}
+	second.Add(c)
+	second.Add(a)
+	second.Add(b)
+
+	if first != second {
+		t.Fatalf("The hash shouldn't depend on the order elements are added in")
+	}
+	if !first.Commitment().Equals(second.Commitment()) {
+		t.Fatalf("The commitments should be equal")
+	}
+}
+
+func TestUTXOSetHashRemove(t *testing.T) {
+	a, b := []byte{1}, []byte{2}
+
+	withB := utxoSetHash{}
+	withB.Add(b)
+
+	h := utxoSetHash{}
+	h.Add(a)
+	h.Add(b)
+	if h == withB {
+		t.Fatalf("Adding an element should have changed the hash")
+	}
+	h.Remove(a)
+	if h != withB {
+		t.Fatalf("Removing an element should undo adding it")
+	}
+	h.Remove(b)
+	if h != (utxoSetHash{}) {
+		t.Fatalf("The hash of the empty set should be zero")
+	}
+}
+
+func TestUTXOSetHashBytes(t *testing.T) {
+	h := utxoSetHash{}
+	h.Add([]byte{1, 2, 3})
+
+	parsed, err := parseUTXOSetHash(h.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *parsed != h {
+		t.Fatalf("Parsed the wrong hash")
+	}
+
+	if _, err := parseUTXOSetHash(h.Bytes()[1:]); err != errWrongUTXOSetHashLen {
+		t.Fatalf("Should have errored with %s but got %v", errWrongUTXOSetHashLen, err)
+	}
+}
@@ -0,0 +1,142 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avm
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/ava-labs/gecko/ids"
+)
+
+// signedTx is the transaction handed to feature extensions while credentials
+// are verified concurrently. Its unsigned bytes and their hash, and the UTXOs
+// it produces, are computed before the checks run, so that the checks don't
+// race to cache them.
+type signedTx struct {
+	*UniqueTx
+	unsignedBytes, unsignedHash []byte
+	utxos                       []*UTXO
+
+	// The inputs whose checks were created, and the UTXOs they spend
+	spentIns   []interface{}
+	spentUTXOs []*UTXO
+}
+
+func newSignedTx(tx *UniqueTx) *signedTx {
+	return &signedTx{
+		UniqueTx:      tx,
+		unsignedBytes: tx.UnsignedBytes(),
+		unsignedHash:  tx.UnsignedHash(),
+		utxos:         tx.UTXOs(),
+	}
+}
+
+// spend records that [in] spends [utxo]. Must be called before the checks run.
+func (tx *signedTx) spend(in interface{}, utxo *UTXO) {
+	tx.spentIns = append(tx.spentIns, in)
+	tx.spentUTXOs = append(tx.spentUTXOs, utxo)
+}
+
+// spentAsset returns the asset [in] spends
+func (tx *signedTx) spentAsset(in interface{}) (ids.ID, bool) {
+	for i, spentIn := range tx.spentIns {
+		if spentIn == in {
+			return tx.spentUTXOs[i].AssetID(), true
+		}
+	}
+	return ids.ID{}, false
+}
+
+// SpentOutputs returns the outputs the transaction consumes of the asset that
+// [in] spends
+func (tx *signedTx) SpentOutputs(in interface{}) []interface{} {
+	assetID, ok := tx.spentAsset(in)
+	if !ok {
+		return nil
+	}
+	outs := []interface{}(nil)
+	for _, utxo := range tx.spentUTXOs {
+		if utxo.AssetID().Equals(assetID) {
+			outs = append(outs, utxo.Out)
+		}
+	}
+	return outs
+}
+
+// ProducedOutputs returns the outputs the transaction produces of the asset
+// that [in] spends
+func (tx *signedTx) ProducedOutputs(in interface{}) []interface{} {
+	assetID, ok := tx.spentAsset(in)
+	if !ok {
+		return nil
+	}
+	outs := []interface{}(nil)
+	for _, utxo := range tx.utxos {
+		if utxo.AssetID().Equals(assetID) {
+			outs = append(outs, utxo.Out)
+		}
+	}
+	return outs
+}
+
+// UnsignedBytes returns the unsigned bytes of the transaction
+func (tx *signedTx) UnsignedBytes() []byte { return tx.unsignedBytes }
+
+// UnsignedHash returns the hash of the unsigned bytes of the transaction
+func (tx *signedTx) UnsignedHash() []byte { return tx.unsignedHash }
+
+// verifyAll runs [checks] on up to [vm.verifyWorkers] goroutines. The checks
+// must be independent of each other and must not touch the VM's state. The
+// error returned is the one of the first failing check, so the result doesn't
+// depend on how the checks were scheduled.
+func (vm *VM) verifyAll(checks []func() error) error {
+	workers := vm.verifyWorkers
+	if workers > len(checks) {
+		workers = len(checks)
+	}
+	if workers <= 1 {
+		for _, check := range checks {
+			if err := check(); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	var (
+		next   int64 = -1
+		failed int64 = int64(len(checks))
+		errs         = make([]error, len(checks))
+		wg     sync.WaitGroup
+	)
+	wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer wg.Done()
+			for {
+				index := atomic.AddInt64(&next, 1)
+				// Checks after a failure can't change the result
+				if index >= atomic.LoadInt64(&failed) {
+					return
+				}
+				if errs[index] = checks[index](); errs[index] == nil {
+					continue
+				}
+				for {
+					current := atomic.LoadInt64(&failed)
+					if index >= current || atomic.CompareAndSwapInt64(&failed, current, index) {
+						break
+					}
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if failed < int64(len(checks)) {
+		return errs[failed]
+	}
+	return nil
+}
@@ -0,0 +1,198 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avm
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/crypto"
+	"github.com/ava-labs/gecko/vms/secp256k1fx"
+)
+
+func TestVerifyAllFirstError(t *testing.T) {
+	vm := &VM{verifyWorkers: 4}
+
+	errFirst := errors.New("first")
+	errSecond := errors.New("second")
+
+	checks := make([]func() error, 16)
+	for i := range checks {
+		checks[i] = func() error { return nil }
+	}
+	checks[11] = func() error { return errSecond }
+	checks[5] = func() error { return errFirst }
+
+	for i := 0; i < 100; i++ {
+		if err := vm.verifyAll(checks); err != errFirst {
+			t.Fatalf("Expected %s but got %v", errFirst, err)
+		}
+	}
+}
+
+func TestVerifyAllSequential(t *testing.T) {
+	vm := &VM{verifyWorkers: 1}
+
+	errFailed := errors.New("failed")
+
+	ran := 0
+	checks := []func() error{
+		func() error { ran++; return nil },
+		func() error { ran++; return errFailed },
+		func() error { ran++; return nil },
+	}
+	if err := vm.verifyAll(checks); err != errFailed {
+		t.Fatalf("Expected %s but got %v", errFailed, err)
+	}
+	if ran != 2 {
+		t.Fatalf("Checks after the failure shouldn't have run")
+	}
+
+	if err := vm.verifyAll(nil); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// newMultiInputTest returns a VM and a tx that spends [numInputs] UTXOs, each
+// owned by a different key, so that each credential needs its own public key
+// recovery
+func newMultiInputTest(numInputs int, t testing.TB) (*VM, *UniqueTx) {
+	genesisBytes := BuildGenesisTest(t)
+	genesisTx := GetFirstTxFromGenesisTest(genesisBytes, t)
+	vm := GenesisVM(t)
+
+	factory := crypto.FactorySECP256K1R{}
+	signers := make([]*crypto.PrivateKeySECP256K1R, numInputs)
+
+	baseTx := &BaseTx{
+		NetID: networkID,
+		BCID:  chainID,
+	}
+	for i := range signers {
+		skIntf, err := factory.NewPrivateKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		signers[i] = skIntf.(*crypto.PrivateKeySECP256K1R)
+
+		utxo := &UTXO{
+			UTXOID: UTXOID{
+				TxID:        ids.Empty.Prefix(uint64(i)),
+				OutputIndex: 0,
+			},
+			Asset: Asset{ID: genesisTx.ID()},
+			Out: &secp256k1fx.TransferOutput{
+				Amt: 1000,
+				OutputOwners: secp256k1fx.OutputOwners{
+					Threshold: 1,
+					Addrs:     []ids.ShortID{signers[i].PublicKey().Address()},
+				},
+			},
+		}
+		if err := vm.state.SetUTXO(utxo.InputID(), utxo); err != nil {
+			t.Fatal(err)
+		}
+
+		baseTx.Ins = append(baseTx.Ins, &TransferableInput{
+			UTXOID: utxo.UTXOID,
+			Asset:  utxo.Asset,
+			In: &secp256k1fx.TransferInput{
+				Amt:   1000,
+				Input: secp256k1fx.Input{SigIndices: []uint32{0}},
+			},
+		})
+	}
+
+	tx := &Tx{UnsignedTx: baseTx}
+	unsignedBytes, err := vm.codec.Marshal(&tx.UnsignedTx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, signer := range signers {
+		sig, err := signer.Sign(unsignedBytes)
+		if err != nil {
+			t.Fatal(err)
+		}
+		fixedSig := [crypto.SECP256K1RSigLen]byte{}
+		copy(fixedSig[:], sig)
+
+		tx.Creds = append(tx.Creds, &Credential{
+			Cred: &secp256k1fx.Credential{
+				Sigs: [][crypto.SECP256K1RSigLen]byte{fixedSig},
+			},
+		})
+	}
+
+	b, err := vm.codec.Marshal(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx.Initialize(b)
+
+	return vm, &UniqueTx{
+		vm:   vm,
+		txID: tx.ID(),
+		t: &txState{
+			tx: tx,
+		},
+	}
+}
+
+func TestBaseTxSemanticVerifyManyInputs(t *testing.T) {
+	vm, uTx := newMultiInputTest(32, t)
+	tx := uTx.t.tx
+
+	ctx.Lock.Lock()
+	defer ctx.Lock.Unlock()
+
+	for _, workers := range []int{1, 8} {
+		vm.verifyWorkers = workers
+		if err := tx.UnsignedTx.SemanticVerify(vm, uTx, tx.Creds); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestBaseTxSemanticVerifyManyInputsInvalidSignature(t *testing.T) {
+	vm, uTx := newMultiInputTest(32, t)
+	tx := uTx.t.tx
+
+	// Credentials in the middle of the tx sign for the wrong inputs
+	tx.Creds[13], tx.Creds[19] = tx.Creds[19], tx.Creds[13]
+
+	ctx.Lock.Lock()
+	defer ctx.Lock.Unlock()
+
+	for _, workers := range []int{1, 8} {
+		vm.verifyWorkers = workers
+		if err := tx.UnsignedTx.SemanticVerify(vm, uTx, tx.Creds); err == nil {
+			t.Fatalf("Should have errored with %d workers due to mismatched credentials", workers)
+		}
+	}
+}
+
+func benchmarkSemanticVerify(b *testing.B, numInputs, workers int) {
+	vm, uTx := newMultiInputTest(numInputs, b)
+	vm.verifyWorkers = workers
+	tx := uTx.t.tx
+
+	ctx.Lock.Lock()
+	defer ctx.Lock.Unlock()
+
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		if err := tx.UnsignedTx.SemanticVerify(vm, uTx, tx.Creds); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkSemanticVerify64Inputs measures verifying the credentials of a tx
+// with 64 inputs on 8 goroutines
+func BenchmarkSemanticVerify64Inputs(b *testing.B) { benchmarkSemanticVerify(b, 64, 8) }
+
+// BenchmarkSemanticVerify64InputsSequential is BenchmarkSemanticVerify64Inputs
+// without verifying credentials concurrently
+func BenchmarkSemanticVerify64InputsSequential(b *testing.B) { benchmarkSemanticVerify(b, 64, 1) }
@@ -4,15 +4,19 @@
 package avm
 
 import (
+	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"reflect"
+	"runtime"
 	"strings"
 	"time"
 
 	"github.com/gorilla/rpc/v2"
 
 	"github.com/ava-labs/gecko/cache"
+	"github.com/ava-labs/gecko/chains/atomic"
 	"github.com/ava-labs/gecko/database"
 	"github.com/ava-labs/gecko/database/versiondb"
 	"github.com/ava-labs/gecko/ids"
@@ -21,9 +25,12 @@ import (
 	"github.com/ava-labs/gecko/snow/consensus/snowstorm"
 	"github.com/ava-labs/gecko/snow/engine/common"
 	"github.com/ava-labs/gecko/utils/formatting"
+	"github.com/ava-labs/gecko/utils/hashing"
 	"github.com/ava-labs/gecko/utils/timer"
 	"github.com/ava-labs/gecko/utils/wrappers"
 	"github.com/ava-labs/gecko/vms/components/codec"
+	"github.com/ava-labs/gecko/vms/components/events"
+	"github.com/ava-labs/gecko/vms/components/verify"
 
 	cjson "github.com/ava-labs/gecko/utils/json"
 )
@@ -38,8 +45,8 @@ const (
 )
 
 var (
-	errIncompatibleFx            = errors.New("incompatible feature extension")
-	errUnknownFx                 = errors.New("unknown feature extension")
+	errIncompatibleFx            = verify.NewError(CodeIncompatibleFx, "incompatible feature extension")
+	errUnknownFx                 = verify.NewError(CodeUnknownFx, "unknown feature extension")
 	errGenesisAssetMustHaveState = errors.New("genesis asset must have non-empty state")
 	errInvalidAddress            = errors.New("invalid address")
 	errWrongBlockchainID         = errors.New("wrong blockchain ID")
@@ -57,22 +64,60 @@ type VM struct {
 
 	codec codec.Codec
 
+	// A tx that parsed bytes are decoded into when it isn't referenced by
+	// anything else. Txs that are gossiped repeatedly are decoded into it
+	// rather than into newly allocated txs.
+	parseBuffer *Tx
+
 	pubsub *cjson.PubSubServer
 
+	// Pushes the decisions of txs to the subscribers of their addresses
+	eventServer *events.Server
+
 	// State management
 	state *prefixedState
 
 	// Transaction issuing
 	timer        *timer.Timer
 	batchTimeout time.Duration
-	txs          []snowstorm.Tx
+	mempool      *mempool
 	toEngine     chan<- common.Message
 
+	// The txs issued through the API that haven't been decided
+	persisted persistedTxs
+
+	// If true, the mempool is offered to the engine until the engine takes
+	// it, as the engine may have dropped the offer while bootstrapping
+	awaitingEngine bool
+
 	baseDB database.Database
 	db     *versiondb.Database
 
 	typeToFxIndex map[reflect.Type]int
 	fxs           []*parsedFx
+
+	// If non-nil, consulted before issuing transactions received by the API
+	issuanceFilter IssuanceFilter
+
+	// The AVA asset and the Platform Chain, which AVA can be exported to and
+	// imported from
+	ava, platform ids.ID
+
+	// Amount of AVA each transaction must burn, as set by the genesis
+	txFee uint64
+
+	// If true, accepted transactions are indexed by the addresses they touch
+	indexTxs bool
+
+	// If true, the activity of each asset is counted per period
+	indexAssetStats bool
+
+	// Maximum number of transactions the mempool holds, and the maximum
+	// number of them that each address may be involved in
+	mempoolSize, mempoolAddressCap int
+
+	// Number of goroutines the credentials of a transaction are verified with
+	verifyWorkers int
 }
 
 type codecRegistry struct {
@@ -86,8 +131,9 @@ func (cr *codecRegistry) RegisterType(val interface{}) error {
 	cr.typeToFxIndex[valType] = cr.index
 	return cr.codec.RegisterType(val)
 }
-func (cr *codecRegistry) Marshal(val interface{}) ([]byte, error)   { return cr.codec.Marshal(val) }
-func (cr *codecRegistry) Unmarshal(b []byte, val interface{}) error { return cr.codec.Unmarshal(b, val) }
+func (cr *codecRegistry) Marshal(val interface{}) ([]byte, error)       { return cr.codec.Marshal(val) }
+func (cr *codecRegistry) Unmarshal(b []byte, val interface{}) error     { return cr.codec.Unmarshal(b, val) }
+func (cr *codecRegistry) UnmarshalInto(b []byte, val interface{}) error { return cr.codec.UnmarshalInto(b, val) }
 
 /*
  ******************************************************************************
@@ -108,6 +154,7 @@ func (vm *VM) Initialize(
 	vm.baseDB = db
 	vm.db = versiondb.New(db)
 	vm.typeToFxIndex = map[reflect.Type]int{}
+	vm.verifyWorkers = runtime.GOMAXPROCS(0)
 	vm.Aliaser.Initialize()
 
 	vm.pubsub = cjson.NewPubSubServer(ctx)
@@ -133,6 +180,15 @@ func (vm *VM) Initialize(
 		txStatus: &cache.LRU{Size: idCacheSize},
 		funds:    &cache.LRU{Size: idCacheSize},
 
+		assetFunds:     &cache.LRU{Size: idCacheSize},
+		rejectionCause: &cache.LRU{Size: idCacheSize},
+		addressTxCount: &cache.LRU{Size: idCacheSize},
+		addressTx:      &cache.LRU{Size: idCacheSize},
+		assetFreezer:   &cache.LRU{Size: idCacheSize},
+
+		assetStats:        &cache.LRU{Size: idCacheSize},
+		assetStatsAddress: &cache.LRU{Size: idCacheSize},
+
 		uniqueTx: &cache.EvictableLRU{Size: txCacheSize},
 	}
 
@@ -164,18 +220,38 @@ func (vm *VM) Initialize(
 		}
 	}
 
+	// The atomic txs and the memo tx are registered after the fxs' types so
+	// that adding them didn't change the type IDs of txs that were already
+	// encoded
+	c.RegisterType(&ImportTx{})
+	c.RegisterType(&ExportTx{})
+	c.RegisterType(&MemoTx{})
+
 	vm.codec = c
 
-	if err := vm.initAliases(genesisBytes); err != nil {
+	genesis, txFee, err := parseGenesis(vm.codec, genesisBytes)
+	if err != nil {
+		return err
+	}
+	vm.txFee = txFee
+
+	if err := vm.initAliases(genesis); err != nil {
 		return err
 	}
 
 	if dbStatus, err := vm.state.DBInitialized(); err != nil || dbStatus == choices.Unknown {
-		if err := vm.initState(genesisBytes); err != nil {
+		if err := vm.initState(genesis); err != nil {
 			return err
 		}
 	}
+	if err := vm.initAddressTxIndex(); err != nil {
+		return err
+	}
+	if err := vm.initAssetStatsIndex(); err != nil {
+		return err
+	}
 
+	vm.mempool = newMempool(vm.mempoolSize, vm.mempoolAddressCap)
 	vm.timer = timer.NewTimer(func() {
 		ctx.Lock.Lock()
 		defer ctx.Lock.Unlock()
@@ -185,12 +261,20 @@ func (vm *VM) Initialize(
 	go ctx.Log.RecoverAndPanic(vm.timer.Dispatch)
 	vm.batchTimeout = batchTimeout
 
+	if err := vm.reissuePersistedTxs(); err != nil {
+		return err
+	}
 	return vm.db.Commit()
 }
 
 // Shutdown implements the avalanche.DAGVM interface
 func (vm *VM) Shutdown() {
 	vm.timer.Stop()
+	if vm.eventServer != nil {
+		if err := vm.eventServer.Shutdown(); err != nil {
+			vm.ctx.Log.Error("Stopping the events server failed with %s", err)
+		}
+	}
 	if err := vm.baseDB.Close(); err != nil {
 		vm.ctx.Log.Error("Closing the database failed with %s", err)
 	}
@@ -204,10 +288,19 @@ func (vm *VM) CreateHandlers() map[string]*common.HTTPHandler {
 	rpcServer.RegisterCodec(codec, "application/json;charset=UTF-8")
 	rpcServer.RegisterService(&Service{vm: vm}, "avm") // name this service "avm"
 
-	return map[string]*common.HTTPHandler{
+	handlers := map[string]*common.HTTPHandler{
 		"":        &common.HTTPHandler{Handler: rpcServer},
 		"/pubsub": &common.HTTPHandler{LockOptions: common.NoLock, Handler: vm.pubsub},
 	}
+
+	// The events server is only fed decisions once its endpoint is served
+	if eventServer, err := events.NewServer(vm.ctx, &eventParser{vm: vm}); err == nil {
+		vm.eventServer = eventServer
+		handlers["/events"] = &common.HTTPHandler{LockOptions: common.NoLock, Handler: eventServer}
+	} else {
+		vm.ctx.Log.Error("Failed to create the events server due to %s", err)
+	}
+	return handlers
 }
 
 // CreateStaticHandlers implements the avalanche.DAGVM interface
@@ -225,24 +318,23 @@ func (vm *VM) CreateStaticHandlers() map[string]*common.HTTPHandler {
 // PendingTxs implements the avalanche.DAGVM interface
 func (vm *VM) PendingTxs() []snowstorm.Tx {
 	vm.timer.Cancel()
+	vm.awaitingEngine = false
 
-	txs := vm.txs
-	vm.txs = nil
-	return txs
+	return vm.mempool.PopAll()
 }
 
 // ParseTx implements the avalanche.DAGVM interface
-func (vm *VM) ParseTx(b []byte) (snowstorm.Tx, error) { return vm.parseTx(b) }
+func (vm *VM) ParseTx(ctx context.Context, b []byte) (snowstorm.Tx, error) { return vm.parseTx(ctx, b) }
 
 // GetTx implements the avalanche.DAGVM interface
-func (vm *VM) GetTx(txID ids.ID) (snowstorm.Tx, error) {
+func (vm *VM) GetTx(ctx context.Context, txID ids.ID) (snowstorm.Tx, error) {
 	tx := &UniqueTx{
 		vm:   vm,
 		txID: txID,
 	}
 	// Verify must be called in the case the that tx was flushed from the unique
 	// cache.
-	return tx, tx.Verify()
+	return tx, tx.Verify(ctx)
 }
 
 /*
@@ -255,16 +347,28 @@ func (vm *VM) GetTx(txID ids.ID) (snowstorm.Tx, error) {
 // If onDecide is specified, the function will be called when the transaction is
 // either accepted or rejected with the appropriate status. This function will
 // go out of scope when the transaction is removed from memory.
+// Transactions refused by the mempool or by the issuance filter aren't issued.
+// Issuing a transaction that is already in the mempool does nothing.
+// Issued transactions are saved, so they're issued again if the node restarts
+// before they're decided.
 func (vm *VM) IssueTx(b []byte, onDecide func(choices.Status)) (ids.ID, error) {
-	tx, err := vm.parseTx(b)
+	tx, err := vm.parseTx(context.Background(), b)
 	if err != nil {
 		return ids.ID{}, err
 	}
-	if err := tx.Verify(); err != nil {
+	if vm.mempool.Has(tx.ID()) {
+		return tx.ID(), nil
+	}
+	if err := vm.issue(tx); err != nil {
 		return ids.ID{}, err
 	}
-	vm.issueTx(tx)
 	tx.t.onDecide = onDecide
+
+	if err := vm.persistTx(tx.ID()); err != nil {
+		vm.ctx.Log.Error("Failed to save issued tx %s due to %s", tx.ID(), err)
+	} else if err := vm.db.Commit(); err != nil {
+		vm.ctx.Log.Error("Failed to commit issued tx %s due to %s", tx.ID(), err)
+	}
 	return tx.ID(), nil
 }
 
@@ -288,6 +392,117 @@ func (vm *VM) GetUTXOs(addrs ids.Set) ([]*UTXO, error) {
 	return utxos, nil
 }
 
+// GetPaginatedUTXOs returns at most [limit] utxos that reference any of the
+// provided addresses, ordered by address and then by utxo ID. If [startAddr] is
+// non-empty, only utxos that come after the utxo [startUTXOID] of [startAddr]
+// are returned. The address and utxo ID of the last utxo visited are returned
+// so they can be passed back in to fetch the next page.
+//
+// A utxo that references several of the addresses is only listed under the
+// first of them, so it is returned once across all pages.
+func (vm *VM) GetPaginatedUTXOs(addrs []ids.ID, startAddr, startUTXOID ids.ID, limit int) ([]*UTXO, ids.ID, ids.ID, error) {
+	sortedAddrs := make([]ids.ID, len(addrs))
+	copy(sortedAddrs, addrs)
+	ids.SortIDs(sortedAddrs)
+
+	requested := ids.Set{}
+	requested.Add(addrs...)
+
+	lastAddr, lastUTXOID := startAddr, startUTXOID
+	utxos := []*UTXO{}
+	for _, addr := range sortedAddrs {
+		if !startAddr.IsZero() && bytes.Compare(addr.Bytes(), startAddr.Bytes()) < 0 {
+			continue
+		}
+		skipThrough := ids.ID{}
+		if addr.Equals(startAddr) {
+			skipThrough = startUTXOID
+		}
+
+		funds, _ := vm.state.Funds(addr)
+		// Funds may be cached, so it must not be sorted in place
+		utxoIDs := make([]ids.ID, len(funds))
+		copy(utxoIDs, funds)
+		ids.SortIDs(utxoIDs)
+
+		for _, utxoID := range utxoIDs {
+			if !skipThrough.IsZero() && bytes.Compare(utxoID.Bytes(), skipThrough.Bytes()) <= 0 {
+				continue
+			}
+			if len(utxos) == limit {
+				return utxos, lastAddr, lastUTXOID, nil
+			}
+			lastAddr, lastUTXOID = addr, utxoID
+
+			utxo, err := vm.state.UTXO(utxoID)
+			if err != nil {
+				return nil, ids.ID{}, ids.ID{}, err
+			}
+			if listedUnder := firstReferencedAddr(utxo, requested); !listedUnder.Equals(addr) {
+				continue
+			}
+			utxos = append(utxos, utxo)
+		}
+	}
+	return utxos, lastAddr, lastUTXOID, nil
+}
+
+// firstReferencedAddr returns the smallest address in [addrs] that [utxo]
+// references
+func firstReferencedAddr(utxo *UTXO, addrs ids.Set) ids.ID {
+	addressable, ok := utxo.Out.(FxAddressable)
+	if !ok {
+		return ids.ID{}
+	}
+	first := ids.ID{}
+	for _, addrBytes := range addressable.Addresses() {
+		addr := ids.NewID(hashing.ComputeHash256Array(addrBytes))
+		if !addrs.Contains(addr) {
+			continue
+		}
+		if first.IsZero() || bytes.Compare(addr.Bytes(), first.Bytes()) < 0 {
+			first = addr
+		}
+	}
+	return first
+}
+
+// GetUTXOsByAsset returns the utxos of [assetID] that at least one of the
+// provided addresses is referenced in.
+func (vm *VM) GetUTXOsByAsset(addrs ids.Set, assetID ids.ID) ([]*UTXO, error) {
+	if status, err := vm.state.AssetIndexInitialized(); err != nil || status == choices.Unknown {
+		// This database was initialized before the address/asset index
+		// existed, so the index may be missing utxos
+		utxos, err := vm.GetUTXOs(addrs)
+		if err != nil {
+			return nil, err
+		}
+		assetUTXOs := []*UTXO{}
+		for _, utxo := range utxos {
+			if utxo.AssetID().Equals(assetID) {
+				assetUTXOs = append(assetUTXOs, utxo)
+			}
+		}
+		return assetUTXOs, nil
+	}
+
+	utxoIDs := ids.Set{}
+	for _, addr := range addrs.List() {
+		utxos, _ := vm.state.AssetFunds(addr, assetID)
+		utxoIDs.Add(utxos...)
+	}
+
+	utxos := []*UTXO{}
+	for _, utxoID := range utxoIDs.List() {
+		utxo, err := vm.state.UTXO(utxoID)
+		if err != nil {
+			return nil, err
+		}
+		utxos = append(utxos, utxo)
+	}
+	return utxos, nil
+}
+
 /*
  ******************************************************************************
  *********************************** Fx API ***********************************
@@ -309,9 +524,12 @@ func (vm *VM) Codec() codec.Codec { return vm.codec }
 // FlushTxs into consensus
 func (vm *VM) FlushTxs() {
 	vm.timer.Cancel()
-	if len(vm.txs) != 0 {
+	if vm.mempool.Len() != 0 {
 		select {
 		case vm.toEngine <- common.PendingTxs:
+			if vm.awaitingEngine {
+				vm.timer.SetTimeoutIn(vm.batchTimeout)
+			}
 		default:
 			vm.ctx.Log.Warn("Delaying issuance of transactions due to contention")
 			vm.timer.SetTimeoutIn(vm.batchTimeout)
@@ -325,11 +543,7 @@ func (vm *VM) FlushTxs() {
  ******************************************************************************
  */
 
-func (vm *VM) initAliases(genesisBytes []byte) error {
-	genesis := Genesis{}
-	if err := vm.codec.Unmarshal(genesisBytes, &genesis); err != nil {
-		return err
-	}
+func (vm *VM) initAliases(genesis *Genesis) error {
 
 	for _, genesisTx := range genesis.Txs {
 		if len(genesisTx.Outs) != 0 {
@@ -353,11 +567,7 @@ func (vm *VM) initAliases(genesisBytes []byte) error {
 	return nil
 }
 
-func (vm *VM) initState(genesisBytes []byte) error {
-	genesis := Genesis{}
-	if err := vm.codec.Unmarshal(genesisBytes, &genesis); err != nil {
-		return err
-	}
+func (vm *VM) initState(genesis *Genesis) error {
 
 	for _, genesisTx := range genesis.Txs {
 		if len(genesisTx.Outs) != 0 {
@@ -383,20 +593,98 @@ func (vm *VM) initState(genesisBytes []byte) error {
 		if err := vm.state.SetStatus(txID, choices.Accepted); err != nil {
 			return err
 		}
+		addrs := ids.Set{}
 		for _, utxo := range tx.UTXOs() {
 			if err := vm.state.FundUTXO(utxo); err != nil {
 				return err
 			}
+			addUTXOAddresses(addrs, utxo)
+		}
+		if vm.indexTxs {
+			if err := vm.state.IndexAddressTx(txID, addrs); err != nil {
+				return err
+			}
 		}
 	}
 
+	// Every utxo is added to the address/asset index, so the index is complete
+	if err := vm.state.SetAssetIndexInitialized(choices.Accepted); err != nil {
+		return err
+	}
+	// Every utxo is added to the utxo set hash, so the hash is complete
+	if err := vm.state.SetUTXOSetHashInitialized(choices.Accepted); err != nil {
+		return err
+	}
+	// Every genesis tx is added to the address tx index, so the index is
+	// complete
+	if vm.indexTxs {
+		if err := vm.state.SetAddressTxIndexInitialized(choices.Accepted); err != nil {
+			return err
+		}
+	}
+	// The genesis txs aren't counted in any period, so the asset stats are
+	// complete
+	if vm.indexAssetStats {
+		if err := vm.state.SetAssetStatsInitialized(choices.Accepted); err != nil {
+			return err
+		}
+	}
 	return vm.state.SetDBInitialized(choices.Processing)
 }
 
-func (vm *VM) parseTx(b []byte) (*UniqueTx, error) {
-	rawTx := &Tx{}
-	err := vm.codec.Unmarshal(b, rawTx)
-	if err != nil {
+// initAddressTxIndex records whether the address tx index holds every accepted
+// transaction. The index is incomplete if it was enabled after transactions
+// had been accepted, or if transactions are accepted while it is disabled.
+func (vm *VM) initAddressTxIndex() error {
+	status, err := vm.state.AddressTxIndexInitialized()
+	if err != nil && err != database.ErrNotFound {
+		return err
+	}
+	switch {
+	case vm.indexTxs && status != choices.Accepted:
+		vm.ctx.Log.Warn("The address tx index is missing transactions that were accepted while indexing was disabled")
+		return vm.state.SetAddressTxIndexInitialized(choices.Processing)
+	case !vm.indexTxs && status == choices.Accepted:
+		return vm.state.SetAddressTxIndexInitialized(choices.Processing)
+	default:
+		return nil
+	}
+}
+
+// initAssetStatsIndex records whether the asset stats count every accepted
+// transaction. The stats are incomplete if they were enabled after
+// transactions had been accepted, or if transactions are accepted while they
+// are disabled.
+func (vm *VM) initAssetStatsIndex() error {
+	status, err := vm.state.AssetStatsInitialized()
+	if err != nil && err != database.ErrNotFound {
+		return err
+	}
+	switch {
+	case vm.indexAssetStats && status != choices.Accepted:
+		vm.ctx.Log.Warn("The asset stats are missing transactions that were accepted while they were disabled")
+		return vm.state.SetAssetStatsInitialized(choices.Processing)
+	case !vm.indexAssetStats && status == choices.Accepted:
+		return vm.state.SetAssetStatsInitialized(choices.Processing)
+	default:
+		return nil
+	}
+}
+
+// parseTx parses and syntactically verifies [b]. Parsing is abandoned, and the
+// tx isn't stored, once [ctx] is done.
+func (vm *VM) parseTx(ctx context.Context, b []byte) (*UniqueTx, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	rawTx := vm.parseBuffer
+	if rawTx == nil {
+		rawTx = &Tx{}
+	}
+	// Until it's known whether [rawTx] is kept, it can't be reused
+	vm.parseBuffer = nil
+	if err := vm.codec.UnmarshalInto(b, rawTx); err != nil {
+		vm.parseBuffer = rawTx
 		return nil, err
 	}
 	rawTx.Initialize(b)
@@ -408,7 +696,16 @@ func (vm *VM) parseTx(b []byte) (*UniqueTx, error) {
 			tx: rawTx,
 		},
 	}
-	if err := tx.SyntacticVerify(); err != nil {
+	err := tx.SyntacticVerify()
+	if tx.t.tx != rawTx {
+		// This tx was already known, so the state of the known tx is used
+		// and [rawTx] can be reused
+		vm.parseBuffer = rawTx
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := ctx.Err(); err != nil {
 		return nil, err
 	}
 
@@ -422,14 +719,80 @@ func (vm *VM) parseTx(b []byte) (*UniqueTx, error) {
 	return tx, nil
 }
 
-func (vm *VM) issueTx(tx snowstorm.Tx) {
-	vm.txs = append(vm.txs, tx)
+// issue [tx] to the mempool, if the mempool can hold it and it's valid
+func (vm *VM) issue(tx *UniqueTx) error {
+	// Transactions the mempool can't hold are refused before their
+	// credentials are verified, as that is the expensive part of verification
+	consumed, err := vm.consumedUTXOs(tx)
+	if err != nil {
+		return err
+	}
+	ptx := vm.mempool.newPendingTx(tx, txAddresses(tx, consumed))
+	if _, err := vm.mempool.Check(ptx); err != nil {
+		return err
+	}
+
+	if err := tx.verify(); err != nil {
+		return err
+	}
+	if vm.issuanceFilter != nil {
+		if err := vm.issuanceFilter.Filter(tx.t.tx, consumed); err != nil {
+			return err
+		}
+	}
+	return vm.issueTx(ptx)
+}
+
+// issueTx adds [ptx] to the mempool. The txs dropped from the mempool to make
+// room for [ptx] aren't rejected, as consensus never decided them, so another
+// node may still issue them. They're only no longer re-issued after a restart.
+func (vm *VM) issueTx(ptx *pendingTx) error {
+	dropped, err := vm.mempool.Add(ptx)
+	for _, d := range dropped {
+		txID := d.tx.ID()
+		if d.causeID.IsZero() {
+			vm.ctx.Log.Debug("Evicting pending tx %s from the mempool", txID)
+		} else {
+			vm.ctx.Log.Debug("Dropping pending tx %s from the mempool because of %s", txID, d.causeID)
+		}
+		if err := vm.unpersistTx(txID); err != nil {
+			vm.ctx.Log.Error("Failed to stop re-issuing tx %s due to %s", txID, err)
+		}
+	}
+	if len(dropped) != 0 {
+		if err := vm.db.Commit(); err != nil {
+			vm.ctx.Log.Error("Failed to commit dropping txs from the mempool due to %s", err)
+		}
+	}
+	if err != nil {
+		return err
+	}
+
 	switch {
-	case len(vm.txs) == batchSize:
+	case vm.mempool.Len() >= batchSize:
 		vm.FlushTxs()
-	case len(vm.txs) == 1:
+	case vm.mempool.Len() == 1:
 		vm.timer.SetTimeoutIn(vm.batchTimeout)
 	}
+	return nil
+}
+
+// commit this chain's state. If [sharedBatch] isn't nil, its changes to the
+// shared memory are written in the same batch.
+func (vm *VM) commit(sharedBatch *AtomicUTXOsBatch) error {
+	if sharedBatch == nil {
+		return vm.db.Commit()
+	}
+
+	batch, err := vm.db.CommitBatch()
+	if err != nil {
+		return err
+	}
+	if err := atomic.WriteAll(batch, sharedBatch.Batches()...); err != nil {
+		return err
+	}
+	vm.db.Abort()
+	return nil
 }
 
 func (vm *VM) getFx(val interface{}) (int, error) {
@@ -449,10 +812,15 @@ func (vm *VM) verifyFxUsage(fxID int, assetID ids.ID) bool {
 	if status := tx.Status(); !status.Fetched() {
 		return false
 	}
-	createAssetTx, ok := tx.t.tx.UnsignedTx.(*CreateAssetTx)
+	createAssetTx, ok := innerTx(tx.t.tx.UnsignedTx).(*CreateAssetTx)
 	if !ok {
 		return false
 	}
+	// Transfers of a frozen asset can only use the fx that froze them
+	freezer, err := vm.state.AssetFreezer(assetID)
+	if err != nil || (freezer != 0 && freezer != uint64(fxID)+1) {
+		return false
+	}
 	// TODO: This could be a binary search to import performance... Or perhaps
 	// make a map
 	for _, state := range createAssetTx.States {
@@ -5,11 +5,13 @@ package avm
 
 import (
 	"bytes"
+	"context"
 	"testing"
 
 	"github.com/ava-labs/gecko/database/memdb"
 	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/snow"
+	"github.com/ava-labs/gecko/snow/choices"
 	"github.com/ava-labs/gecko/snow/engine/common"
 	"github.com/ava-labs/gecko/utils/crypto"
 	"github.com/ava-labs/gecko/utils/formatting"
@@ -45,7 +47,7 @@ func init() {
 	}
 }
 
-func GetFirstTxFromGenesisTest(genesisBytes []byte, t *testing.T) *Tx {
+func GetFirstTxFromGenesisTest(genesisBytes []byte, t testing.TB) *Tx {
 	c := codec.NewDefault()
 	c.RegisterType(&BaseTx{})
 	c.RegisterType(&CreateAssetTx{})
@@ -82,7 +84,7 @@ func GetFirstTxFromGenesisTest(genesisBytes []byte, t *testing.T) *Tx {
 	return nil
 }
 
-func BuildGenesisTest(t *testing.T) []byte {
+func BuildGenesisTest(t testing.TB) []byte {
 	ss := StaticService{}
 
 	addr0 := keys[0].PublicKey().Address()
@@ -160,7 +162,7 @@ func BuildGenesisTest(t *testing.T) []byte {
 	return reply.Bytes.Bytes
 }
 
-func GenesisVM(t *testing.T) *VM {
+func GenesisVM(t testing.TB) *VM {
 	genesisBytes := BuildGenesisTest(t)
 
 	ctx.Lock.Lock()
@@ -185,6 +187,58 @@ func GenesisVM(t *testing.T) *VM {
 	return vm
 }
 
+func TestGenesisTxFee(t *testing.T) {
+	addr := keys[0].PublicKey().Address()
+
+	ss := StaticService{}
+	args := BuildGenesisArgs{
+		GenesisData: map[string]AssetDefinition{
+			"AVA": AssetDefinition{
+				Name:   "AVA",
+				Symbol: "AVA",
+				InitialState: map[string][]interface{}{
+					"fixedCap": []interface{}{
+						Holder{
+							Amount:  100000,
+							Address: addr.String(),
+						},
+					},
+				},
+			},
+		},
+		TxFee: 1000,
+	}
+	reply := BuildGenesisReply{}
+	if err := ss.BuildGenesis(nil, &args, &reply); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx.Lock.Lock()
+
+	vm := &VM{}
+	if err := vm.Initialize(
+		ctx,
+		memdb.New(),
+		reply.Bytes.Bytes,
+		make(chan common.Message, 1),
+		[]*common.Fx{&common.Fx{
+			ID: ids.Empty,
+			Fx: &secp256k1fx.Fx{},
+		}},
+	); err != nil {
+		ctx.Lock.Unlock()
+		t.Fatal(err)
+	}
+	defer func() {
+		ctx.Lock.Unlock()
+		vm.Shutdown()
+	}()
+
+	if vm.txFee != 1000 {
+		t.Fatalf("VM should have taken the fee %d from its genesis, took %d", 1000, vm.txFee)
+	}
+}
+
 func TestTxSerialization(t *testing.T) {
 	expected := []byte{
 		// txID:
@@ -705,3 +759,183 @@ func TestIssueDependentTx(t *testing.T) {
 		t.Fatalf("Should have returned %d tx(s)", 2)
 	}
 }
+
+// newTestOperationTx returns an OperationTx that spends a UTXO of [genesisTx]
+func newTestOperationTx(vm *VM, genesisTx *Tx, t testing.TB) *Tx {
+	newTx := &Tx{UnsignedTx: &OperationTx{BaseTx: BaseTx{
+		NetID: networkID,
+		BCID:  chainID,
+		Ins: []*TransferableInput{
+			&TransferableInput{
+				UTXOID: UTXOID{
+					TxID:        genesisTx.ID(),
+					OutputIndex: 1,
+				},
+				Asset: Asset{
+					ID: genesisTx.ID(),
+				},
+				In: &secp256k1fx.TransferInput{
+					Amt: 50000,
+					Input: secp256k1fx.Input{
+						SigIndices: []uint32{
+							0,
+						},
+					},
+				},
+			},
+		},
+	}}}
+
+	unsignedBytes, err := vm.codec.Marshal(&newTx.UnsignedTx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sig, err := keys[0].Sign(unsignedBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fixedSig := [crypto.SECP256K1RSigLen]byte{}
+	copy(fixedSig[:], sig)
+
+	newTx.Creds = append(newTx.Creds, &Credential{
+		Cred: &secp256k1fx.Credential{
+			Sigs: [][crypto.SECP256K1RSigLen]byte{
+				fixedSig,
+			},
+		},
+	})
+
+	b, err := vm.codec.Marshal(newTx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	newTx.Initialize(b)
+	return newTx
+}
+
+// The cached unsigned bytes of a tx, and their hash, should match a fresh
+// marshaling of the tx
+func TestUniqueTxUnsignedBytes(t *testing.T) {
+	genesisBytes := BuildGenesisTest(t)
+	vm := GenesisVM(t)
+	newTx := newTestOperationTx(vm, GetFirstTxFromGenesisTest(genesisBytes, t), t)
+
+	ctx.Lock.Lock()
+	defer ctx.Lock.Unlock()
+
+	tx, err := vm.parseTx(context.Background(), newTx.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected, err := vm.codec.Marshal(&newTx.UnsignedTx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		if unsignedBytes := tx.UnsignedBytes(); !bytes.Equal(unsignedBytes, expected) {
+			t.Fatalf("Unsigned bytes should have been %v but were %v", expected, unsignedBytes)
+		}
+		if unsignedHash := tx.UnsignedHash(); !bytes.Equal(unsignedHash, hashing.ComputeHash256(expected)) {
+			t.Fatalf("Unsigned hash doesn't match the unsigned bytes")
+		}
+	}
+
+	// Every UniqueTx of the tx shares the cached bytes
+	other := &UniqueTx{
+		vm:   vm,
+		txID: tx.ID(),
+	}
+	other.refresh()
+	if &other.UnsignedBytes()[0] != &tx.UnsignedBytes()[0] {
+		t.Fatalf("Unsigned bytes should have been cached once per tx")
+	}
+}
+
+// Parsing a tx that is already known shouldn't retain the decoded tx, so its
+// buffer should be reused by the next parse
+func TestParseTxReusesBuffer(t *testing.T) {
+	genesisBytes := BuildGenesisTest(t)
+	vm := GenesisVM(t)
+	newTx := newTestOperationTx(vm, GetFirstTxFromGenesisTest(genesisBytes, t), t)
+
+	ctx.Lock.Lock()
+	defer ctx.Lock.Unlock()
+
+	firstTx, err := vm.parseTx(context.Background(), newTx.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vm.parseBuffer != nil {
+		t.Fatalf("parse buffer shouldn't be reused after being retained")
+	}
+
+	secondTx, err := vm.parseTx(context.Background(), newTx.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vm.parseBuffer == nil {
+		t.Fatalf("parse buffer should be reused after parsing a known tx")
+	}
+	if vm.parseBuffer == secondTx.t.tx {
+		t.Fatalf("parse buffer shouldn't be referenced by the parsed tx")
+	}
+	if !firstTx.ID().Equals(secondTx.ID()) {
+		t.Fatalf("parsed the wrong tx")
+	}
+}
+
+// Parsing should stop, without storing the tx, once the chain shuts down
+func TestParseTxCancelled(t *testing.T) {
+	genesisBytes := BuildGenesisTest(t)
+	vm := GenesisVM(t)
+	newTx := newTestOperationTx(vm, GetFirstTxFromGenesisTest(genesisBytes, t), t)
+
+	ctx.Lock.Lock()
+	defer ctx.Lock.Unlock()
+
+	parseCtx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := vm.ParseTx(parseCtx, newTx.Bytes()); err != context.Canceled {
+		t.Fatalf("Should have errored with %s, errored with %v", context.Canceled, err)
+	}
+	if _, err := vm.state.Tx(newTx.ID()); err == nil {
+		t.Fatalf("Shouldn't have stored a tx whose parsing was cancelled")
+	}
+
+	tx, err := vm.parseTx(context.Background(), newTx.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status := tx.Status(); status != choices.Processing {
+		t.Fatalf("Tx should have been %s but was %s", choices.Processing, status)
+	}
+}
+
+func benchmarkParseTx(b *testing.B, reuse bool) {
+	genesisBytes := BuildGenesisTest(b)
+	vm := GenesisVM(b)
+	txBytes := newTestOperationTx(vm, GetFirstTxFromGenesisTest(genesisBytes, b), b).Bytes()
+
+	ctx.Lock.Lock()
+	defer ctx.Lock.Unlock()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		if !reuse {
+			vm.parseBuffer = nil
+		}
+		if _, err := vm.parseTx(context.Background(), txBytes); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkParseKnownTx measures parsing a gossiped tx that is already known
+func BenchmarkParseKnownTx(b *testing.B) { benchmarkParseTx(b, true) }
+
+// BenchmarkParseKnownTxNoReuse is BenchmarkParseKnownTx without reusing the
+// parse buffer
+func BenchmarkParseKnownTxNoReuse(b *testing.B) { benchmarkParseTx(b, false) }
@@ -7,7 +7,8 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
-	"unicode"
+	"sync"
+	"unsafe"
 
 	"github.com/ava-labs/gecko/utils/wrappers"
 )
@@ -74,8 +75,18 @@ type codec struct {
 	maxSize     int
 	maxSliceLen int
 
+	packers *wrappers.PackerPool
+
+	// Packers used to read the bytes being unmarshaled. These are kept
+	// separately from [packers] because their byte arrays belong to the caller.
+	unpackers *sync.Pool
+
 	typeIDToType map[uint32]reflect.Type
 	typeToTypeID map[reflect.Type]uint32
+
+	// Key: A struct type
+	// Value: The *structPlan of the type
+	plans *sync.Map
 }
 
 // Codec marshals and unmarshals
@@ -83,6 +94,7 @@ type Codec interface {
 	RegisterType(interface{}) error
 	Marshal(interface{}) ([]byte, error)
 	Unmarshal([]byte, interface{}) error
+	UnmarshalInto([]byte, interface{}) error
 }
 
 // New returns a new codec
@@ -90,8 +102,11 @@ func New(maxSize, maxSliceLen int) Codec {
 	return codec{
 		maxSize:      maxSize,
 		maxSliceLen:  maxSliceLen,
+		packers:      &wrappers.PackerPool{MaxSize: maxSize},
+		unpackers:    &sync.Pool{New: func() interface{} { return &wrappers.Packer{} }},
 		typeIDToType: map[uint32]reflect.Type{},
 		typeToTypeID: map[reflect.Type]uint32{},
+		plans:        &sync.Map{},
 	}
 }
 
@@ -107,6 +122,7 @@ func (c codec) RegisterType(val interface{}) error {
 	}
 	c.typeIDToType[uint32(len(c.typeIDToType))] = reflect.TypeOf(val)
 	c.typeToTypeID[valType] = uint32(len(c.typeIDToType) - 1)
+	c.precompute(valType)
 	return nil
 }
 
@@ -123,6 +139,8 @@ func (c codec) RegisterType(val interface{}) error {
 //    you must call codec.RegisterType([instance of the type that fulfills the interface]).
 // 7) nil slices will be unmarshaled as an empty slice of the appropriate type
 // 8) Serialized fields must be exported
+// 9) UnmarshalInto reuses the slices, pointers and interface values already
+//    held by the destination rather than allocating new ones
 
 // Marshal returns the byte representation of [value]
 // If you want to marshal an interface, [value] must be a pointer
@@ -137,65 +155,75 @@ func (c codec) Marshal(value interface{}) ([]byte, error) {
 
 // Marshal [value] to bytes
 func (c codec) marshal(value reflect.Value) ([]byte, error) {
-	p := wrappers.Packer{MaxSize: c.maxSize, Bytes: []byte{}}
+	p := c.packers.Get()
+	defer c.packers.Put(p)
+
+	if err := c.pack(value, p); err != nil {
+		return nil, err
+	}
+
+	// The packer's byte array is returned to the pool, so the result must be
+	// copied out of it
+	bytes := make([]byte, len(p.Bytes))
+	copy(bytes, p.Bytes)
+	return bytes, nil
+}
+
+// Pack [value] into [p]
+func (c codec) pack(value reflect.Value, p *wrappers.Packer) error {
 	t := value.Type()
 
 	valueKind := value.Kind()
 	switch valueKind {
 	case reflect.Interface, reflect.Ptr, reflect.Slice:
 		if value.IsNil() {
-			return nil, errNil
+			return errNil
 		}
 	}
 
 	switch valueKind {
 	case reflect.Uint8:
 		p.PackByte(uint8(value.Uint()))
-		return p.Bytes, p.Err
+		return p.Err
 	case reflect.Int8:
 		p.PackByte(uint8(value.Int()))
-		return p.Bytes, p.Err
+		return p.Err
 	case reflect.Uint16:
 		p.PackShort(uint16(value.Uint()))
-		return p.Bytes, p.Err
+		return p.Err
 	case reflect.Int16:
 		p.PackShort(uint16(value.Int()))
-		return p.Bytes, p.Err
+		return p.Err
 	case reflect.Uint32:
 		p.PackInt(uint32(value.Uint()))
-		return p.Bytes, p.Err
+		return p.Err
 	case reflect.Int32:
 		p.PackInt(uint32(value.Int()))
-		return p.Bytes, p.Err
+		return p.Err
 	case reflect.Uint64:
 		p.PackLong(value.Uint())
-		return p.Bytes, p.Err
+		return p.Err
 	case reflect.Int64:
 		p.PackLong(uint64(value.Int()))
-		return p.Bytes, p.Err
+		return p.Err
 	case reflect.Uintptr, reflect.Ptr:
-		return c.marshal(value.Elem())
+		return c.pack(value.Elem(), p)
 	case reflect.String:
 		p.PackStr(value.String())
-		return p.Bytes, p.Err
+		return p.Err
 	case reflect.Bool:
 		p.PackBool(value.Bool())
-		return p.Bytes, p.Err
+		return p.Err
 	case reflect.Interface:
 		typeID, ok := c.typeToTypeID[reflect.TypeOf(value.Interface())] // Get the type ID of the value being marshaled
 		if !ok {
-			return nil, fmt.Errorf("can't marshal unregistered type '%v'", reflect.TypeOf(value.Interface()).String())
+			return fmt.Errorf("can't marshal unregistered type '%v'", reflect.TypeOf(value.Interface()).String())
 		}
 		p.PackInt(typeID)
-		bytes, err := c.Marshal(value.Interface())
-		if err != nil {
-			return nil, err
-		}
-		p.PackFixedBytes(bytes)
 		if p.Errored() {
-			return nil, p.Err
+			return p.Err
 		}
-		return p.Bytes, err
+		return c.pack(reflect.ValueOf(value.Interface()), p)
 	case reflect.Array, reflect.Slice:
 		numElts := value.Len() // # elements in the slice/array (assumed to be <= 2^31 - 1)
 		// If this is a slice, pack the number of elements in the slice
@@ -203,46 +231,55 @@ func (c codec) marshal(value reflect.Value) ([]byte, error) {
 			p.PackInt(uint32(numElts))
 		}
 		for i := 0; i < numElts; i++ { // Pack each element in the slice/array
-			eltBytes, err := c.marshal(value.Index(i))
-			if err != nil {
-				return nil, err
+			if err := c.pack(value.Index(i), p); err != nil {
+				return err
 			}
-			p.PackFixedBytes(eltBytes)
 		}
-		return p.Bytes, p.Err
+		return p.Err
 	case reflect.Struct:
-		for i := 0; i < t.NumField(); i++ { // Go through all fields of this struct
-			field := t.Field(i)
-			if !shouldSerialize(field) { // Skip fields we don't need to serialize
-				continue
-			}
-			if unicode.IsLower(rune(field.Name[0])) { // Can only marshal exported fields
-				return nil, errMarshalUnexportedField
-			}
-			fieldVal := value.Field(i) // The field we're serializing
-			if fieldVal.Kind() == reflect.Slice && fieldVal.IsNil() {
+		plan := c.plan(t)
+		if plan.unexported { // Can only marshal exported fields
+			return errMarshalUnexportedField
+		}
+		for _, field := range plan.serialized { // Go through the fields to serialize
+			fieldVal := value.Field(field.index) // The field we're serializing
+			if field.kind == reflect.Slice && fieldVal.IsNil() {
 				p.PackInt(0)
 				continue
 			}
-			fieldBytes, err := c.marshal(fieldVal) // Serialize the field
-			if err != nil {
-				return nil, err
+			if err := c.pack(fieldVal, p); err != nil { // Serialize the field
+				return err
 			}
-			p.PackFixedBytes(fieldBytes)
 		}
-		return p.Bytes, p.Err
+		return p.Err
 	case reflect.Invalid:
-		return nil, errUnmarshalNil
+		return errUnmarshalNil
 	default:
-		return nil, errUnknownType
+		return errUnknownType
 	}
 }
 
 // Unmarshal unmarshals [bytes] into [dest], where
 // [dest] must be a pointer or interface
 func (c codec) Unmarshal(bytes []byte, dest interface{}) error {
-	p := &wrappers.Packer{Bytes: bytes}
+	return c.unmarshalBytes(bytes, dest, false)
+}
+
+// UnmarshalInto unmarshals [bytes] into [dest], where [dest] must be a pointer.
+// Unlike Unmarshal, the slices, pointers and interface values [dest] already
+// holds are reused where possible, so decoding repeatedly into the same object
+// allocates much less. Fields that aren't serialized are zeroed, so state
+// cached from the previous contents isn't kept.
+//
+// Nothing other than [dest] may reference the values reachable from [dest], as
+// they may be overwritten.
+func (c codec) UnmarshalInto(bytes []byte, dest interface{}) error {
+	return c.unmarshalBytes(bytes, dest, true)
+}
 
+// Unmarshal [bytes] into [dest]. If [reuse], the values held by [dest] are
+// reused.
+func (c codec) unmarshalBytes(bytes []byte, dest interface{}, reuse bool) error {
 	if len(bytes) > c.maxSize {
 		return errSliceTooLarge
 	}
@@ -259,8 +296,14 @@ func (c codec) Unmarshal(bytes []byte, dest interface{}) error {
 
 	destVal := destPtr.Elem()
 
-	err := c.unmarshal(p, destVal)
-	if err != nil {
+	p := c.unpackers.Get().(*wrappers.Packer)
+	p.Bytes = bytes
+	defer func() {
+		*p = wrappers.Packer{} // Don't keep a reference to [bytes]
+		c.unpackers.Put(p)
+	}()
+
+	if err := c.unmarshal(p, destVal, reuse); err != nil {
 		return err
 	}
 
@@ -272,7 +315,9 @@ func (c codec) Unmarshal(bytes []byte, dest interface{}) error {
 
 // Unmarshal bytes from [p] into [field]
 // [field] must be addressable
-func (c codec) unmarshal(p *wrappers.Packer, field reflect.Value) error {
+// If [reuse], the slices, pointers and interface values held by [field] are
+// reused
+func (c codec) unmarshal(p *wrappers.Packer, field reflect.Value, reuse bool) error {
 	kind := field.Kind()
 	switch kind {
 	case reflect.Uint8:
@@ -299,18 +344,23 @@ func (c codec) unmarshal(p *wrappers.Packer, field reflect.Value) error {
 			return errSliceTooLarge
 		}
 
-		// First set [field] to be a slice of the appropriate type/capacity (right now [field] is nil)
-		slice := reflect.MakeSlice(field.Type(), sliceLen, sliceLen)
-		field.Set(slice)
+		if reuse && !field.IsNil() && field.Cap() >= sliceLen {
+			// Reuse the slice's array
+			field.SetLen(sliceLen)
+		} else {
+			// Set [field] to be a slice of the appropriate type/capacity
+			slice := reflect.MakeSlice(field.Type(), sliceLen, sliceLen)
+			field.Set(slice)
+		}
 		// Unmarshal each element into the appropriate index of the slice
 		for i := 0; i < sliceLen; i++ {
-			if err := c.unmarshal(p, field.Index(i)); err != nil {
+			if err := c.unmarshal(p, field.Index(i), reuse); err != nil {
 				return err
 			}
 		}
 	case reflect.Array:
 		for i := 0; i < field.Len(); i++ {
-			if err := c.unmarshal(p, field.Index(i)); err != nil {
+			if err := c.unmarshal(p, field.Index(i), reuse); err != nil {
 				return err
 			}
 		}
@@ -324,27 +374,46 @@ func (c codec) unmarshal(p *wrappers.Packer, field reflect.Value) error {
 		if !ok {
 			return errUnmarshalUnregisteredType
 		}
+		if typ.Kind() == reflect.Ptr {
+			current := field.Elem()
+			if reuse && current.IsValid() && current.Type() == typ && !current.IsNil() {
+				// Reuse the value [field] already points to
+				return c.unmarshal(p, current.Elem(), reuse)
+			}
+			// Unmarshal into a new value of the type pointed to
+			concreteInstancePtr := reflect.New(typ.Elem())
+			if err := c.unmarshal(p, concreteInstancePtr.Elem(), reuse); err != nil {
+				return err
+			}
+			field.Set(concreteInstancePtr)
+			return p.Err
+		}
 		concreteInstancePtr := reflect.New(typ) // instance of the proper type
 		// Unmarshal into the struct
-		if err := c.unmarshal(p, concreteInstancePtr.Elem()); err != nil {
+		if err := c.unmarshal(p, concreteInstancePtr.Elem(), reuse); err != nil {
 			return err
 		}
 		// And assign the filled struct to the field
 		field.Set(concreteInstancePtr.Elem())
 	case reflect.Struct:
-		// Type of this struct
-		structType := reflect.TypeOf(field.Interface())
-		// Go through all the fields and umarshal into each
-		for i := 0; i < structType.NumField(); i++ {
-			structField := structType.Field(i)
-			if !shouldSerialize(structField) { // Skip fields we don't need to unmarshal
-				continue
-			}
-			if unicode.IsLower(rune(structField.Name[0])) { // Only unmarshal into exported field
-				return errUnmarshalUnexportedField
+		plan := c.plan(field.Type())
+		if plan.unexported { // Only unmarshal into exported fields
+			return errUnmarshalUnexportedField
+		}
+		if reuse {
+			// Clear anything cached from the previous contents of the fields
+			// that aren't unmarshaled. They may be unexported, so they're set
+			// through their addresses.
+			structAddr := unsafe.Pointer(field.UnsafeAddr())
+			for _, skipped := range plan.skipped {
+				fieldAddr := unsafe.Pointer(uintptr(structAddr) + skipped.offset)
+				reflect.NewAt(skipped.typ, fieldAddr).Elem().Set(skipped.zero)
 			}
-			field := field.Field(i)                       // Get the field
-			if err := c.unmarshal(p, field); err != nil { // Unmarshal into the field
+		}
+		// Go through the fields to unmarshal and umarshal into each
+		for _, structField := range plan.serialized {
+			field := field.Field(structField.index)              // Get the field
+			if err := c.unmarshal(p, field, reuse); err != nil { // Unmarshal into the field
 				return err
 			}
 			if p.Errored() { // If there was an error just return immediately
@@ -352,12 +421,16 @@ func (c codec) unmarshal(p *wrappers.Packer, field reflect.Value) error {
 			}
 		}
 	case reflect.Ptr:
+		if reuse && !field.IsNil() {
+			// Reuse the value [field] already points to
+			return c.unmarshal(p, field.Elem(), reuse)
+		}
 		// Get the type this pointer points to
 		underlyingType := field.Type().Elem()
 		// Create a new pointer to a new value of the underlying type
 		underlyingValue := reflect.New(underlyingType)
 		// Fill the value
-		if err := c.unmarshal(p, underlyingValue.Elem()); err != nil {
+		if err := c.unmarshal(p, underlyingValue.Elem(), reuse); err != nil {
 			return err
 		}
 		// Assign to the top-level struct's member
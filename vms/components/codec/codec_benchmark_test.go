@@ -53,3 +53,55 @@ func BenchmarkMarshalNonCodec(b *testing.B) {
 		}
 	}
 }
+
+func benchmarkUnmarshal(b *testing.B, reuse bool) {
+	temp := Foo(&MyInnerStruct{})
+	myStructInstance := myStruct{
+		InnerStruct:  MyInnerStruct{"hello"},
+		InnerStruct2: &MyInnerStruct{"yello"},
+		Member1:      1,
+		MySlice:      []byte{1, 2, 3, 4},
+		MySlice2:     []string{"one", "two", "three"},
+		MySlice3:     []MyInnerStruct{MyInnerStruct{"a"}, MyInnerStruct{"b"}, MyInnerStruct{"c"}},
+		MySlice4:     []*MyInnerStruct2{&MyInnerStruct2{true}, &MyInnerStruct2{}},
+		MySlice5:     []Foo{&MyInnerStruct2{true}, &MyInnerStruct2{}},
+		MyArray:      [4]byte{5, 6, 7, 8},
+		MyArray2:     [5]string{"four", "five", "six", "seven"},
+		MyArray3:     [3]MyInnerStruct{MyInnerStruct{"d"}, MyInnerStruct{"e"}, MyInnerStruct{"f"}},
+		MyArray4:     [2]*MyInnerStruct2{&MyInnerStruct2{}, &MyInnerStruct2{true}},
+		MyInterface:  &MyInnerStruct{"yeet"},
+		InnerStruct3: MyInnerStruct3{
+			Str: "str",
+			M1: MyInnerStruct{
+				Str: "other str",
+			},
+			F: &MyInnerStruct2{},
+		},
+		MyPointer: &temp,
+	}
+
+	codec := NewDefault()
+	codec.RegisterType(&MyInnerStruct{}) // Register the types that may be unmarshaled into interfaces
+	codec.RegisterType(&MyInnerStruct2{})
+	bytes, err := codec.Marshal(myStructInstance)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	dest := myStruct{}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		if reuse {
+			codec.UnmarshalInto(bytes, &dest)
+		} else {
+			codec.Unmarshal(bytes, &myStruct{})
+		}
+	}
+}
+
+// BenchmarkUnmarshal benchmarks decoding into a new value
+func BenchmarkUnmarshal(b *testing.B) { benchmarkUnmarshal(b, false) }
+
+// BenchmarkUnmarshalInto benchmarks decoding into a previously decoded value
+func BenchmarkUnmarshalInto(b *testing.B) { benchmarkUnmarshal(b, true) }
@@ -538,3 +538,179 @@ func TestTooLargeUnmarshal(t *testing.T) {
 		t.Fatalf("Should have errored due to too many bytes provided")
 	}
 }
+
+// Ensure marshaled bytes aren't overwritten when the codec reuses its buffers
+func TestMarshalDoesNotAlias(t *testing.T) {
+	codec := NewDefault()
+
+	first, err := codec.Marshal(uint64(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []byte{0, 0, 0, 0, 0, 0, 0, 1}
+	if !bytes.Equal(first, expected) {
+		t.Fatalf("Marshal returned %v but expected %v", first, expected)
+	}
+
+	for i := 0; i < 16; i++ {
+		if _, err := codec.Marshal(uint64(0xffffffffffffffff)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if !bytes.Equal(first, expected) {
+		t.Fatalf("Previously marshaled bytes were modified to %v", first)
+	}
+}
+
+type cachingStruct struct {
+	Nums []uint32 `serialize:"true"`
+	F    Foo      `serialize:"true"`
+
+	cached int
+}
+
+// Test that UnmarshalInto decodes the same values as Unmarshal
+func TestUnmarshalInto(t *testing.T) {
+	codec := NewDefault()
+	codec.RegisterType(&MyInnerStruct{})
+	codec.RegisterType(&MyInnerStruct2{})
+
+	temp := Foo(&MyInnerStruct{"pointed to"})
+	myStructInstance := myStruct{
+		InnerStruct:  MyInnerStruct{"hello"},
+		InnerStruct2: &MyInnerStruct{"yello"},
+		Member1:      1,
+		MySlice:      []byte{1, 2, 3, 4},
+		MySlice2:     []string{"one", "two", "three"},
+		MySlice3:     []MyInnerStruct{MyInnerStruct{"a"}, MyInnerStruct{"b"}},
+		MySlice4:     []*MyInnerStruct2{&MyInnerStruct2{true}, &MyInnerStruct2{}},
+		MySlice5:     []Foo{&MyInnerStruct2{true}, &MyInnerStruct{"in a slice"}},
+		MyArray4:     [2]*MyInnerStruct2{&MyInnerStruct2{}, &MyInnerStruct2{true}},
+		MyInterface:  &MyInnerStruct{"yeet"},
+		InnerStruct3: MyInnerStruct3{F: &MyInnerStruct2{}},
+		MyPointer:    &temp,
+	}
+	myStructBytes, err := codec.Marshal(myStructInstance)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := myStruct{}
+	if err := codec.Unmarshal(myStructBytes, &expected); err != nil {
+		t.Fatal(err)
+	}
+
+	// Decode into an empty struct, then decode again into the now populated
+	// struct
+	reused := myStruct{}
+	for i := 0; i < 2; i++ {
+		if err := codec.UnmarshalInto(myStructBytes, &reused); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(reused, expected) {
+			t.Fatalf("expected %+v but got %+v", expected, reused)
+		}
+	}
+}
+
+// Test that UnmarshalInto reuses the slices and values held by the destination
+func TestUnmarshalIntoReuses(t *testing.T) {
+	codec := NewDefault()
+	codec.RegisterType(&MyInnerStruct{})
+
+	bytes, err := codec.Marshal(&cachingStruct{
+		Nums: []uint32{1, 2},
+		F:    &MyInnerStruct{"new"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	inner := &MyInnerStruct{"old"}
+	dest := cachingStruct{
+		Nums:   make([]uint32, 3, 4),
+		F:      inner,
+		cached: 5,
+	}
+	nums := dest.Nums
+	if err := codec.UnmarshalInto(bytes, &dest); err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(dest.Nums, []uint32{1, 2}) {
+		t.Fatalf("unexpected slice %v", dest.Nums)
+	}
+	if &dest.Nums[0] != &nums[0] {
+		t.Fatal("should have reused the slice's array")
+	}
+	if dest.F != inner {
+		t.Fatal("should have reused the interface's value")
+	}
+	if inner.Str != "new" {
+		t.Fatalf("expected the reused value to be overwritten but was %q", inner.Str)
+	}
+	if dest.cached != 0 {
+		t.Fatal("should have cleared the unserialized field")
+	}
+}
+
+// Test that UnmarshalInto replaces an interface's value of a different type
+func TestUnmarshalIntoDifferentType(t *testing.T) {
+	codec := NewDefault()
+	codec.RegisterType(&MyInnerStruct{})
+	codec.RegisterType(&MyInnerStruct2{})
+
+	bytes, err := codec.Marshal(&cachingStruct{F: &MyInnerStruct2{true}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dest := cachingStruct{F: &MyInnerStruct{"old"}}
+	if err := codec.UnmarshalInto(bytes, &dest); err != nil {
+		t.Fatal(err)
+	}
+	if inner, ok := dest.F.(*MyInnerStruct2); !ok || !inner.Bool {
+		t.Fatalf("unexpected value %+v", dest.F)
+	}
+}
+
+type treeNode struct {
+	Value    uint32      `serialize:"true"`
+	Children []*treeNode `serialize:"true"`
+}
+
+// Test that registering a type that contains itself terminates, and that the
+// type can then be marshaled and unmarshaled
+func TestRecursiveType(t *testing.T) {
+	codec := NewDefault()
+	if err := codec.RegisterType(&treeNode{}); err != nil {
+		t.Fatal(err)
+	}
+
+	tree := &treeNode{
+		Value: 1,
+		Children: []*treeNode{
+			&treeNode{Value: 2},
+			&treeNode{
+				Value:    3,
+				Children: []*treeNode{&treeNode{Value: 4}},
+			},
+		},
+	}
+	treeBytes, err := codec.Marshal(tree)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parsed := &treeNode{}
+	if err := codec.Unmarshal(treeBytes, parsed); err != nil {
+		t.Fatal(err)
+	}
+	// nil slices are unmarshaled as empty slices
+	tree.Children[0].Children = []*treeNode{}
+	tree.Children[1].Children[0].Children = []*treeNode{}
+	if !reflect.DeepEqual(tree, parsed) {
+		t.Fatalf("expected %+v but got %+v", tree, parsed)
+	}
+}
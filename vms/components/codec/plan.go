@@ -0,0 +1,82 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package codec
+
+import (
+	"reflect"
+	"unicode"
+)
+
+// structPlan is how a struct type is traversed when it's marshaled or
+// unmarshaled. Reading the fields and tags of a type through reflection is
+// slow, so each type's plan is computed once and reused.
+type structPlan struct {
+	// The fields that are serialized, in order
+	serialized []fieldPlan
+	// The fields that aren't serialized, which UnmarshalInto zeroes
+	skipped []fieldPlan
+	// True if a serialized field is unexported, so the type can't be
+	// marshaled or unmarshaled
+	unexported bool
+}
+
+// fieldPlan is a field of a struct type
+type fieldPlan struct {
+	index  int
+	offset uintptr
+	typ    reflect.Type
+	kind   reflect.Kind
+	// The zero value of the field's type
+	zero reflect.Value
+}
+
+// newStructPlan returns the plan of the struct type [t]
+func newStructPlan(t reflect.Type) *structPlan {
+	plan := &structPlan{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		fp := fieldPlan{
+			index:  i,
+			offset: field.Offset,
+			typ:    field.Type,
+			kind:   field.Type.Kind(),
+		}
+		if !shouldSerialize(field) {
+			fp.zero = reflect.Zero(field.Type)
+			plan.skipped = append(plan.skipped, fp)
+			continue
+		}
+		if unicode.IsLower(rune(field.Name[0])) {
+			plan.unexported = true
+		}
+		plan.serialized = append(plan.serialized, fp)
+	}
+	return plan
+}
+
+// plan returns the plan of the struct type [t], computing it if this is the
+// first time [t] is traversed
+func (c codec) plan(t reflect.Type) *structPlan {
+	if plan, ok := c.plans.Load(t); ok {
+		return plan.(*structPlan)
+	}
+	plan, _ := c.plans.LoadOrStore(t, newStructPlan(t))
+	return plan.(*structPlan)
+}
+
+// precompute the plans of the struct types that values of type [t] may
+// contain, other than through interfaces
+func (c codec) precompute(t reflect.Type) {
+	switch t.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Array:
+		c.precompute(t.Elem())
+	case reflect.Struct:
+		if _, ok := c.plans.Load(t); ok {
+			return
+		}
+		for _, field := range c.plan(t).serialized {
+			c.precompute(field.typ)
+		}
+	}
+}
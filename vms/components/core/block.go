@@ -4,6 +4,7 @@
 package core
 
 import (
+	"context"
 	"errors"
 
 	"github.com/ava-labs/gecko/ids"
@@ -43,7 +44,7 @@ func (b *Block) ParentID() ids.ID { return b.PrntID }
 
 // Parent returns [b]'s parent
 func (b *Block) Parent() snowman.Block {
-	parent, err := b.VM.GetBlock(b.ParentID())
+	parent, err := b.VM.GetBlock(context.Background(), b.ParentID())
 	if err != nil {
 		return &missing.Block{BlkID: b.ParentID()}
 	}
@@ -53,7 +54,7 @@ func (b *Block) Parent() snowman.Block {
 // Accept sets this block's status to Accepted and sets lastAccepted to this
 // block's ID and saves this info to b.vm.DB
 // Recall that b.vm.DB.Commit() must be called to persist to the DB
-func (b *Block) Accept() {
+func (b *Block) Accept(context.Context) {
 	b.SetStatus(choices.Accepted)                           // Change state of this block
 	b.VM.State.PutStatus(b.VM.DB, b.ID(), choices.Accepted) // Persist data
 	b.VM.State.PutLastAccepted(b.VM.DB, b.ID())
@@ -4,6 +4,7 @@
 package core
 
 import (
+	"context"
 	"errors"
 
 	"github.com/gorilla/rpc/v2"
@@ -64,12 +65,12 @@ func (svm *SnowmanVM) Preferred() ids.ID { return svm.preferred }
 func (svm *SnowmanVM) LastAccepted() ids.ID { return svm.lastAccepted }
 
 // ParseBlock parses [bytes] to a block
-func (svm *SnowmanVM) ParseBlock(bytes []byte) (snowman.Block, error) {
+func (svm *SnowmanVM) ParseBlock(_ context.Context, bytes []byte) (snowman.Block, error) {
 	return svm.unmarshalBlockFunc(bytes)
 }
 
 // GetBlock returns the block with ID [ID]
-func (svm *SnowmanVM) GetBlock(ID ids.ID) (snowman.Block, error) {
+func (svm *SnowmanVM) GetBlock(_ context.Context, ID ids.ID) (snowman.Block, error) {
 	block, err := svm.State.Get(svm.DB, state.BlockTypeID, ID)
 	if err != nil {
 		return nil, err
@@ -0,0 +1,146 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package events pushes the decisions of a chain's transactions, and the UTXOs
+// they change, to websocket subscribers as consensus makes them. Clients
+// subscribe to a channel with a bloom filter of the addresses they follow
+// rather than polling for the status of each transaction.
+package events
+
+import (
+	"net/http"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow"
+	"github.com/ava-labs/gecko/utils/wrappers"
+
+	cjson "github.com/ava-labs/gecko/utils/json"
+)
+
+const (
+	// AcceptedChannel receives the transactions that were accepted
+	AcceptedChannel = "accepted"
+	// RejectedChannel receives the transactions that were rejected
+	RejectedChannel = "rejected"
+	// UTXOChannel receives the UTXOs that accepted transactions spent and
+	// produced
+	UTXOChannel = "utxos"
+
+	// dispatcherID identifies the server in the chain's decision dispatcher
+	dispatcherID = "events"
+)
+
+// Tx is a transaction that was decided
+type Tx struct {
+	ID        ids.ID   `json:"txID"`
+	Addresses []string `json:"addresses"`
+	Consumed  []*UTXO  `json:"consumed,omitempty"`
+	Produced  []*UTXO  `json:"produced,omitempty"`
+
+	// FilterKeys are matched against the subscribers' filters. They're
+	// usually the raw bytes of the transaction's addresses.
+	FilterKeys [][]byte `json:"-"`
+}
+
+// UTXO is an unspent output that a transaction spent or produced
+type UTXO struct {
+	ID        ids.ID       `json:"utxoID"`
+	TxID      ids.ID       `json:"txID"`
+	AssetID   ids.ID       `json:"assetID"`
+	Amount    cjson.Uint64 `json:"amount"`
+	Addresses []string     `json:"addresses"`
+	// Spent is true if the UTXO was consumed rather than produced
+	Spent bool `json:"spent"`
+
+	// FilterKeys are matched against the subscribers' filters
+	FilterKeys [][]byte `json:"-"`
+}
+
+// Parser returns the transactions that were decided with a container
+type Parser interface {
+	// ParseAccepted returns the transactions that were accepted and rejected
+	// when the container [containerID] was accepted
+	ParseAccepted(containerID ids.ID, container []byte) (accepted []*Tx, rejected []*Tx, err error)
+
+	// ParseRejected returns the transactions that were rejected when the
+	// container [containerID] was rejected
+	ParseRejected(containerID ids.ID, container []byte) ([]*Tx, error)
+}
+
+// Server publishes the transactions of a chain as they're decided. It's fed by
+// the chain's decision dispatcher, which calls it while the chain's lock is
+// held, so the parser may read the VM's state.
+type Server struct {
+	ctx    *snow.Context
+	parser Parser
+	pubsub *cjson.PubSubServer
+}
+
+// NewServer returns a server of the decisions of the chain [ctx] is the
+// context of, and registers it with the chain's decision dispatcher
+func NewServer(ctx *snow.Context, parser Parser) (*Server, error) {
+	s := &Server{
+		ctx:    ctx,
+		parser: parser,
+		pubsub: cjson.NewPubSubServer(ctx),
+	}
+
+	errs := wrappers.Errs{}
+	errs.Add(
+		s.pubsub.Register(AcceptedChannel),
+		s.pubsub.Register(RejectedChannel),
+		s.pubsub.Register(UTXOChannel),
+	)
+	if errs.Errored() {
+		return nil, errs.Err
+	}
+	if err := ctx.DecisionDispatcher.RegisterChain(ctx.ChainID, dispatcherID, s); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Accept implements the triggers.Acceptor interface
+func (s *Server) Accept(_, containerID ids.ID, container []byte) error {
+	accepted, rejected, err := s.parser.ParseAccepted(containerID, container)
+	if err != nil {
+		return err
+	}
+	for _, tx := range accepted {
+		s.pubsub.PublishFiltered(AcceptedChannel, tx, tx.FilterKeys)
+		for _, utxo := range tx.Consumed {
+			s.pubsub.PublishFiltered(UTXOChannel, utxo, utxo.FilterKeys)
+		}
+		for _, utxo := range tx.Produced {
+			s.pubsub.PublishFiltered(UTXOChannel, utxo, utxo.FilterKeys)
+		}
+	}
+	s.publishRejected(rejected)
+	return nil
+}
+
+// Reject implements the triggers.Rejector interface
+func (s *Server) Reject(_, containerID ids.ID, container []byte) error {
+	rejected, err := s.parser.ParseRejected(containerID, container)
+	if err != nil {
+		return err
+	}
+	s.publishRejected(rejected)
+	return nil
+}
+
+func (s *Server) publishRejected(txs []*Tx) {
+	for _, tx := range txs {
+		s.pubsub.PublishFiltered(RejectedChannel, tx, tx.FilterKeys)
+	}
+}
+
+// ServeHTTP upgrades the request to a websocket subscription
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.pubsub.ServeHTTP(w, r)
+}
+
+// Shutdown stops the server from being fed by the decision dispatcher
+func (s *Server) Shutdown() error {
+	return s.ctx.DecisionDispatcher.DeregisterChain(s.ctx.ChainID, dispatcherID)
+}
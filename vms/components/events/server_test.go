@@ -0,0 +1,68 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package events
+
+import (
+	"testing"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow"
+)
+
+// testParser records the containers it's asked to parse
+type testParser struct {
+	accepted, rejected []ids.ID
+}
+
+func (p *testParser) ParseAccepted(containerID ids.ID, _ []byte) ([]*Tx, []*Tx, error) {
+	p.accepted = append(p.accepted, containerID)
+	return []*Tx{&Tx{ID: containerID}}, nil, nil
+}
+
+func (p *testParser) ParseRejected(containerID ids.ID, _ []byte) ([]*Tx, error) {
+	p.rejected = append(p.rejected, containerID)
+	return []*Tx{&Tx{ID: containerID}}, nil
+}
+
+func TestServerParsesDecisions(t *testing.T) {
+	ctx := snow.DefaultContextTest()
+	parser := &testParser{}
+	s, err := NewServer(ctx, parser)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Shutdown()
+
+	acceptedID := ids.Empty.Prefix(1)
+	rejectedID := ids.Empty.Prefix(2)
+	ctx.DecisionDispatcher.Accept(ctx.ChainID, acceptedID, nil)
+	ctx.DecisionDispatcher.Reject(ctx.ChainID, rejectedID, nil)
+	// Decisions of other chains aren't published
+	ctx.DecisionDispatcher.Accept(ids.Empty.Prefix(3), acceptedID, nil)
+
+	switch {
+	case len(parser.accepted) != 1 || !parser.accepted[0].Equals(acceptedID):
+		t.Fatalf("Expected the server to parse accepted container %s but got %v", acceptedID, parser.accepted)
+	case len(parser.rejected) != 1 || !parser.rejected[0].Equals(rejectedID):
+		t.Fatalf("Expected the server to parse rejected container %s but got %v", rejectedID, parser.rejected)
+	}
+}
+
+func TestServerShutdown(t *testing.T) {
+	ctx := snow.DefaultContextTest()
+	s, err := NewServer(ctx, &testParser{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewServer(ctx, &testParser{}); err == nil {
+		t.Fatalf("Only one server should be fed the decisions of a chain")
+	}
+
+	if err := s.Shutdown(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewServer(ctx, &testParser{}); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -4,6 +4,7 @@
 package missing
 
 import (
+	"context"
 	"errors"
 
 	"github.com/ava-labs/gecko/ids"
@@ -22,7 +23,7 @@ type Block struct{ BlkID ids.ID }
 func (mb *Block) ID() ids.ID { return mb.BlkID }
 
 // Accept ...
-func (*Block) Accept() { panic(errMissingBlock) }
+func (*Block) Accept(context.Context) { panic(errMissingBlock) }
 
 // Reject ...
 func (*Block) Reject() { panic(errMissingBlock) }
@@ -34,7 +35,7 @@ func (*Block) Status() choices.Status { return choices.Unknown }
 func (*Block) Parent() snowman.Block { return nil }
 
 // Verify ...
-func (*Block) Verify() error { return errMissingBlock }
+func (*Block) Verify(context.Context) error { return errMissingBlock }
 
 // Bytes ...
 func (*Block) Bytes() []byte { return nil }
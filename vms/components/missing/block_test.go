@@ -4,6 +4,7 @@
 package missing
 
 import (
+	"context"
 	"testing"
 
 	"github.com/ava-labs/gecko/ids"
@@ -20,7 +21,7 @@ func TestMissingBlock(t *testing.T) {
 		t.Fatalf("missingBlock.Status returned %s, expected %s", status, choices.Unknown)
 	} else if parent := mb.Parent(); parent != nil {
 		t.Fatalf("missingBlock.Parent returned %v, expected %v", parent, nil)
-	} else if err := mb.Verify(); err == nil {
+	} else if err := mb.Verify(context.Background()); err == nil {
 		t.Fatalf("missingBlock.Verify returned nil, expected an error")
 	} else if bytes := mb.Bytes(); bytes != nil {
 		t.Fatalf("missingBlock.Bytes returned %v, expected %v", bytes, nil)
@@ -32,7 +33,7 @@ func TestMissingBlock(t *testing.T) {
 				t.Fatalf("Should have panicked on accept")
 			}
 		}()
-		mb.Accept()
+		mb.Accept(context.Background())
 	}()
 	func() {
 		defer func() {
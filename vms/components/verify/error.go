@@ -0,0 +1,59 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package verify
+
+import (
+	"errors"
+)
+
+// ErrorCode identifies the reason that verification failed. Clients may branch
+// on these codes, so a code must never be reassigned to a different reason.
+type ErrorCode int
+
+// CodeUnknown is the code of errors that don't carry a code
+const CodeUnknown ErrorCode = 0
+
+// Error is a verification error with a stable code
+type Error struct {
+	code    ErrorCode
+	message string
+	err     error
+}
+
+// NewError returns a new verification error
+func NewError(code ErrorCode, message string) *Error {
+	return &Error{
+		code:    code,
+		message: message,
+	}
+}
+
+// WrapError returns a verification error with [code] that wraps [err], which
+// doesn't need to carry a code
+func WrapError(code ErrorCode, err error) *Error {
+	return &Error{
+		code:    code,
+		message: err.Error(),
+		err:     err,
+	}
+}
+
+// Error implements the error interface
+func (e *Error) Error() string { return e.message }
+
+// Unwrap returns the error wrapped by this error, if any
+func (e *Error) Unwrap() error { return e.err }
+
+// ErrorCode returns the code of this error
+func (e *Error) ErrorCode() int { return int(e.code) }
+
+// Code returns the code of the first verification error in [err]'s chain, or
+// CodeUnknown if there isn't one
+func Code(err error) ErrorCode {
+	var verr *Error
+	if errors.As(err, &verr) {
+		return verr.code
+	}
+	return CodeUnknown
+}
@@ -5,6 +5,7 @@ package verify
 
 import (
 	"errors"
+	"fmt"
 	"testing"
 )
 
@@ -35,3 +36,21 @@ func TestAllError(t *testing.T) {
 		t.Fatalf("Should have returned an error")
 	}
 }
+
+func TestErrorCode(t *testing.T) {
+	verr := NewError(5, "test error")
+	if verr.Error() != "test error" {
+		t.Fatalf("Wrong error message: %s", verr)
+	}
+
+	switch {
+	case Code(verr) != 5:
+		t.Fatalf("Wrong code %d", Code(verr))
+	case Code(fmt.Errorf("wrapped: %w", verr)) != 5:
+		t.Fatalf("Should have found the code of a wrapped error")
+	case Code(errTest) != CodeUnknown:
+		t.Fatalf("Errors without a code should have the unknown code")
+	case Code(nil) != CodeUnknown:
+		t.Fatalf("nil should have the unknown code")
+	}
+}
@@ -4,6 +4,7 @@
 package evm
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/ava-labs/go-ethereum/core/types"
@@ -25,7 +26,7 @@ type Block struct {
 func (b *Block) ID() ids.ID { return b.id }
 
 // Accept implements the snowman.Block interface
-func (b *Block) Accept() {
+func (b *Block) Accept(context.Context) {
 	b.vm.ctx.Log.Verbo("Block %s is accepted", b.ID())
 	b.vm.updateStatus(b.ID(), choices.Accepted)
 }
@@ -58,7 +59,7 @@ func (b *Block) Parent() snowman.Block {
 }
 
 // Verify implements the snowman.Block interface
-func (b *Block) Verify() error {
+func (b *Block) Verify(context.Context) error {
 	_, err := b.vm.chain.InsertChain([]*types.Block{b.ethBlock})
 	return err
 }
@@ -4,6 +4,7 @@
 package evm
 
 import (
+	"context"
 	"crypto/rand"
 	"encoding/json"
 	"errors"
@@ -115,6 +116,8 @@ func (vm *VM) Initialize(
 	}
 
 	vm.ctx = ctx
+	// GetBlock is guarded by the metalock, so it can be called concurrently
+	ctx.LockStrategy = snow.SharedGets
 	vm.chaindb = Database{db}
 	g := new(core.Genesis)
 	err := json.Unmarshal(b, g)
@@ -258,7 +261,7 @@ func (vm *VM) Shutdown() {
 }
 
 // BuildBlock implements the snowman.ChainVM interface
-func (vm *VM) BuildBlock() (snowman.Block, error) {
+func (vm *VM) BuildBlock(context.Context) (snowman.Block, error) {
 	vm.chain.GenBlock()
 	block := <-vm.newBlockChan
 	if block == nil {
@@ -279,7 +282,7 @@ func (vm *VM) BuildBlock() (snowman.Block, error) {
 }
 
 // ParseBlock implements the snowman.ChainVM interface
-func (vm *VM) ParseBlock(b []byte) (snowman.Block, error) {
+func (vm *VM) ParseBlock(_ context.Context, b []byte) (snowman.Block, error) {
 	vm.metalock.Lock()
 	defer vm.metalock.Unlock()
 
@@ -297,7 +300,7 @@ func (vm *VM) ParseBlock(b []byte) (snowman.Block, error) {
 }
 
 // GetBlock implements the snowman.ChainVM interface
-func (vm *VM) GetBlock(id ids.ID) (snowman.Block, error) {
+func (vm *VM) GetBlock(_ context.Context, id ids.ID) (snowman.Block, error) {
 	vm.metalock.Lock()
 	defer vm.metalock.Unlock()
 
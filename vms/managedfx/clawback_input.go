@@ -0,0 +1,17 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package managedfx
+
+// ClawbackInput consumes a secp256k1fx.TransferOutput of a managed asset
+// without its owners' consent. It's authorized by the ManagerInput of the same
+// operation, so its credential must have no signatures.
+type ClawbackInput struct{}
+
+// Verify ...
+func (in *ClawbackInput) Verify() error {
+	if in == nil {
+		return errNilInput
+	}
+	return nil
+}
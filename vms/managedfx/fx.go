@@ -0,0 +1,152 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package managedfx
+
+import (
+	"errors"
+
+	"github.com/ava-labs/gecko/utils/math"
+	"github.com/ava-labs/gecko/vms/components/verify"
+	"github.com/ava-labs/gecko/vms/secp256k1fx"
+)
+
+var (
+	errWrongTxType         = errors.New("wrong tx type")
+	errWrongUTXOType       = errors.New("wrong utxo type")
+	errWrongOutputType     = errors.New("wrong output type")
+	errWrongInputType      = errors.New("wrong input type")
+	errWrongCredentialType = errors.New("wrong credential type")
+	errNilInput            = errors.New("nil input")
+
+	errWrongNumberOfInputs      = errors.New("wrong number of inputs for an operation")
+	errWrongNumberOfCredentials = errors.New("wrong number of credentials for an operation")
+
+	errNoManager            = errors.New("operation must consume exactly one manager output")
+	errWrongManagerCreated  = errors.New("operation must create exactly one manager output")
+	errClawbackSigned       = errors.New("clawed back outputs can't be signed for")
+	errWrongClawbackAmount  = errors.New("operation must send exactly the clawed back amount")
+	errCantTransferManagers = errors.New("manager outputs can only be transferred with an operation")
+	errClawbackOverflow     = errors.New("clawed back amount overflows")
+)
+
+// Fx manages regulated assets. An asset opts in by being created with a
+// ManagerOutput. The manager output's owners can freeze and unfreeze transfers
+// of the asset, hand the manager output to new owners, and claw back the
+// asset's secp256k1fx transfer outputs. Only the manager output's owners sign a
+// clawback; the owners of the clawed back outputs don't.
+type Fx struct{ secp256k1fx.Fx }
+
+// Initialize ...
+func (fx *Fx) Initialize(vmIntf interface{}) error {
+	if err := fx.InitializeVM(vmIntf); err != nil {
+		return err
+	}
+
+	vm := vmIntf.(secp256k1fx.VM)
+	c := vm.Codec()
+	c.RegisterType(&ManagerOutput{})
+	c.RegisterType(&ManagerInput{})
+	c.RegisterType(&ClawbackInput{})
+	c.RegisterType(&Credential{})
+	return nil
+}
+
+// VerifyOperation verifies that the operation consumes exactly one manager
+// output, authorized by its owners, and recreates it, possibly with new owners
+// or a new freeze state. Every other input must claw back a transfer output,
+// and the clawed back amount must be sent to the operation's transfer outputs.
+func (fx *Fx) VerifyOperation(txIntf interface{}, utxosIntf, insIntf, credsIntf, outsIntf []interface{}) error {
+	tx, ok := txIntf.(secp256k1fx.Tx)
+	if !ok {
+		return errWrongTxType
+	}
+	if len(utxosIntf) != len(insIntf) {
+		return errWrongNumberOfInputs
+	}
+	if len(credsIntf) != len(insIntf) {
+		return errWrongNumberOfCredentials
+	}
+
+	var (
+		manager     *ManagerOutput
+		managerIn   *ManagerInput
+		managerCred *Credential
+		clawedBack  uint64
+	)
+	for i, inIntf := range insIntf {
+		cred, ok := credsIntf[i].(*Credential)
+		if !ok {
+			return errWrongCredentialType
+		}
+
+		switch in := inIntf.(type) {
+		case *ManagerInput:
+			utxo, ok := utxosIntf[i].(*ManagerOutput)
+			if !ok {
+				return errWrongUTXOType
+			}
+			if manager != nil {
+				return errNoManager
+			}
+			if err := verify.All(utxo, in, cred); err != nil {
+				return err
+			}
+			manager, managerIn, managerCred = utxo, in, cred
+		case *ClawbackInput:
+			utxo, ok := utxosIntf[i].(*secp256k1fx.TransferOutput)
+			if !ok {
+				return errWrongUTXOType
+			}
+			if err := verify.All(utxo, in, cred); err != nil {
+				return err
+			}
+			if len(cred.Sigs) != 0 {
+				return errClawbackSigned
+			}
+			amount, err := math.Add64(clawedBack, utxo.Amount())
+			if err != nil {
+				return errClawbackOverflow
+			}
+			clawedBack = amount
+		default:
+			return errWrongInputType
+		}
+	}
+	if manager == nil {
+		return errNoManager
+	}
+
+	numManagers := 0
+	sent := uint64(0)
+	for _, outIntf := range outsIntf {
+		switch out := outIntf.(type) {
+		case *ManagerOutput:
+			if err := out.Verify(); err != nil {
+				return err
+			}
+			numManagers++
+		case *secp256k1fx.TransferOutput:
+			if err := out.Verify(); err != nil {
+				return err
+			}
+			amount, err := math.Add64(sent, out.Amount())
+			if err != nil {
+				return errClawbackOverflow
+			}
+			sent = amount
+		default:
+			return errWrongOutputType
+		}
+	}
+	switch {
+	case numManagers != 1:
+		return errWrongManagerCreated
+	case sent != clawedBack:
+		return errWrongClawbackAmount
+	}
+	return fx.VerifyCredentials(tx, &manager.OutputOwners, &managerIn.Input, &managerCred.Credential)
+}
+
+// VerifyTransfer ...
+func (fx *Fx) VerifyTransfer(_, _, _, _ interface{}) error { return errCantTransferManagers }
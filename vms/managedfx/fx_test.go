@@ -0,0 +1,176 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package managedfx
+
+import (
+	"testing"
+
+	"github.com/ava-labs/gecko/vms/secp256k1fx"
+)
+
+func testTransferOutput(amount uint64) *secp256k1fx.TransferOutput {
+	return &secp256k1fx.TransferOutput{
+		Amt:          amount,
+		OutputOwners: secp256k1fx.TestOwners(),
+	}
+}
+
+func TestFxInitialize(t *testing.T) {
+	fx := Fx{}
+	if err := fx.Initialize(&secp256k1fx.TestVM{}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFxInitializeInvalid(t *testing.T) {
+	fx := Fx{}
+	if err := fx.Initialize(nil); err == nil {
+		t.Fatalf("Should have returned an error")
+	}
+}
+
+func TestFxVerifyFreezeOperation(t *testing.T) {
+	fx := Fx{}
+	if err := fx.Initialize(&secp256k1fx.TestVM{}); err != nil {
+		t.Fatal(err)
+	}
+	tx := &secp256k1fx.TestTx{Bytes: secp256k1fx.TestTxBytes}
+	utxo := &ManagerOutput{OutputOwners: secp256k1fx.TestOwners()}
+	in := &ManagerInput{Input: secp256k1fx.TestInput()}
+	manager := &ManagerOutput{
+		Frozen:       true,
+		OutputOwners: secp256k1fx.TestOwners(),
+	}
+
+	utxos := []interface{}{utxo}
+	ins := []interface{}{in}
+	creds := []interface{}{&Credential{Credential: secp256k1fx.TestCredential()}}
+	outs := []interface{}{manager}
+	if err := fx.VerifyOperation(tx, utxos, ins, creds, outs); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFxVerifyFreezeOperationWrongSigner(t *testing.T) {
+	fx := Fx{}
+	if err := fx.Initialize(&secp256k1fx.TestVM{}); err != nil {
+		t.Fatal(err)
+	}
+	tx := &secp256k1fx.TestTx{Bytes: []byte{6}}
+	utxo := &ManagerOutput{OutputOwners: secp256k1fx.TestOwners()}
+	in := &ManagerInput{Input: secp256k1fx.TestInput()}
+	manager := &ManagerOutput{
+		Frozen:       true,
+		OutputOwners: secp256k1fx.TestOwners(),
+	}
+
+	utxos := []interface{}{utxo}
+	ins := []interface{}{in}
+	creds := []interface{}{&Credential{Credential: secp256k1fx.TestCredential()}}
+	outs := []interface{}{manager}
+	if err := fx.VerifyOperation(tx, utxos, ins, creds, outs); err == nil {
+		t.Fatalf("Should have errored due to the signature being over different bytes")
+	}
+}
+
+func TestFxVerifyOperationManagerNotRecreated(t *testing.T) {
+	fx := Fx{}
+	if err := fx.Initialize(&secp256k1fx.TestVM{}); err != nil {
+		t.Fatal(err)
+	}
+	tx := &secp256k1fx.TestTx{Bytes: secp256k1fx.TestTxBytes}
+	utxo := &ManagerOutput{OutputOwners: secp256k1fx.TestOwners()}
+	in := &ManagerInput{Input: secp256k1fx.TestInput()}
+
+	utxos := []interface{}{utxo}
+	ins := []interface{}{in}
+	creds := []interface{}{&Credential{Credential: secp256k1fx.TestCredential()}}
+	outs := []interface{}{}
+	if err := fx.VerifyOperation(tx, utxos, ins, creds, outs); err != errWrongManagerCreated {
+		t.Fatalf("Should have errored with %s, errored with %v", errWrongManagerCreated, err)
+	}
+}
+
+func TestFxVerifyClawbackOperation(t *testing.T) {
+	fx := Fx{}
+	if err := fx.Initialize(&secp256k1fx.TestVM{}); err != nil {
+		t.Fatal(err)
+	}
+	tx := &secp256k1fx.TestTx{Bytes: secp256k1fx.TestTxBytes}
+	managerUTXO := &ManagerOutput{OutputOwners: secp256k1fx.TestOwners()}
+	managerIn := &ManagerInput{Input: secp256k1fx.TestInput()}
+	manager := &ManagerOutput{OutputOwners: secp256k1fx.TestOwners()}
+
+	utxos := []interface{}{managerUTXO, testTransferOutput(30), testTransferOutput(20)}
+	ins := []interface{}{managerIn, &ClawbackInput{}, &ClawbackInput{}}
+	creds := []interface{}{&Credential{Credential: secp256k1fx.TestCredential()}, &Credential{}, &Credential{}}
+	outs := []interface{}{manager, testTransferOutput(50)}
+	if err := fx.VerifyOperation(tx, utxos, ins, creds, outs); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFxVerifyClawbackOperationWrongAmount(t *testing.T) {
+	fx := Fx{}
+	if err := fx.Initialize(&secp256k1fx.TestVM{}); err != nil {
+		t.Fatal(err)
+	}
+	tx := &secp256k1fx.TestTx{Bytes: secp256k1fx.TestTxBytes}
+	managerUTXO := &ManagerOutput{OutputOwners: secp256k1fx.TestOwners()}
+	managerIn := &ManagerInput{Input: secp256k1fx.TestInput()}
+	manager := &ManagerOutput{OutputOwners: secp256k1fx.TestOwners()}
+
+	utxos := []interface{}{managerUTXO, testTransferOutput(30)}
+	ins := []interface{}{managerIn, &ClawbackInput{}}
+	creds := []interface{}{&Credential{Credential: secp256k1fx.TestCredential()}, &Credential{}}
+	outs := []interface{}{manager, testTransferOutput(31)}
+	if err := fx.VerifyOperation(tx, utxos, ins, creds, outs); err != errWrongClawbackAmount {
+		t.Fatalf("Should have errored with %s, errored with %v", errWrongClawbackAmount, err)
+	}
+}
+
+func TestFxVerifyClawbackOperationNoManager(t *testing.T) {
+	fx := Fx{}
+	if err := fx.Initialize(&secp256k1fx.TestVM{}); err != nil {
+		t.Fatal(err)
+	}
+	tx := &secp256k1fx.TestTx{Bytes: secp256k1fx.TestTxBytes}
+
+	utxos := []interface{}{testTransferOutput(30)}
+	ins := []interface{}{&ClawbackInput{}}
+	creds := []interface{}{&Credential{}}
+	outs := []interface{}{testTransferOutput(30)}
+	if err := fx.VerifyOperation(tx, utxos, ins, creds, outs); err != errNoManager {
+		t.Fatalf("Should have errored with %s, errored with %v", errNoManager, err)
+	}
+}
+
+func TestFxVerifyClawbackOperationSigned(t *testing.T) {
+	fx := Fx{}
+	if err := fx.Initialize(&secp256k1fx.TestVM{}); err != nil {
+		t.Fatal(err)
+	}
+	tx := &secp256k1fx.TestTx{Bytes: secp256k1fx.TestTxBytes}
+	managerUTXO := &ManagerOutput{OutputOwners: secp256k1fx.TestOwners()}
+	managerIn := &ManagerInput{Input: secp256k1fx.TestInput()}
+	manager := &ManagerOutput{OutputOwners: secp256k1fx.TestOwners()}
+
+	utxos := []interface{}{managerUTXO, testTransferOutput(30)}
+	ins := []interface{}{managerIn, &ClawbackInput{}}
+	creds := []interface{}{&Credential{Credential: secp256k1fx.TestCredential()}, &Credential{Credential: secp256k1fx.TestCredential()}}
+	outs := []interface{}{manager, testTransferOutput(30)}
+	if err := fx.VerifyOperation(tx, utxos, ins, creds, outs); err != errClawbackSigned {
+		t.Fatalf("Should have errored with %s, errored with %v", errClawbackSigned, err)
+	}
+}
+
+func TestFxVerifyTransfer(t *testing.T) {
+	fx := Fx{}
+	if err := fx.Initialize(&secp256k1fx.TestVM{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := fx.VerifyTransfer(nil, nil, nil, nil); err != errCantTransferManagers {
+		t.Fatalf("Should have errored with %s, errored with %v", errCantTransferManagers, err)
+	}
+}
@@ -0,0 +1,13 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package managedfx
+
+import (
+	"github.com/ava-labs/gecko/vms/secp256k1fx"
+)
+
+// ManagerInput consumes a ManagerOutput to manage its asset
+type ManagerInput struct {
+	secp256k1fx.Input `serialize:"true"`
+}
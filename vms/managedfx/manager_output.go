@@ -0,0 +1,34 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package managedfx
+
+import (
+	"errors"
+
+	"github.com/ava-labs/gecko/vms/secp256k1fx"
+)
+
+var (
+	errNilOutput = errors.New("nil output")
+)
+
+// ManagerOutput allows its owners to manage its asset. While [Frozen] is set,
+// the asset can only be spent by operations of this fx.
+type ManagerOutput struct {
+	Frozen                   bool `serialize:"true"`
+	secp256k1fx.OutputOwners `serialize:"true"`
+}
+
+// IsFrozen returns true if transfers of the asset are frozen
+func (out *ManagerOutput) IsFrozen() bool { return out.Frozen }
+
+// Verify ...
+func (out *ManagerOutput) Verify() error {
+	switch {
+	case out == nil:
+		return errNilOutput
+	default:
+		return out.OutputOwners.Verify()
+	}
+}
@@ -0,0 +1,154 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package nftfx
+
+import (
+	"bytes"
+	"errors"
+
+	"github.com/ava-labs/gecko/vms/components/verify"
+	"github.com/ava-labs/gecko/vms/secp256k1fx"
+)
+
+var (
+	errWrongTxType         = errors.New("wrong tx type")
+	errWrongUTXOType       = errors.New("wrong utxo type")
+	errWrongOutputType     = errors.New("wrong output type")
+	errWrongInputType      = errors.New("wrong input type")
+	errWrongCredentialType = errors.New("wrong credential type")
+
+	errWrongNumberOfInputs      = errors.New("wrong number of inputs for an operation")
+	errWrongNumberOfCredentials = errors.New("wrong number of credentials for an operation")
+
+	errWrongMintCreated   = errors.New("wrong mint output created from the operation")
+	errNoNFTsMinted       = errors.New("mint operation must create at least one NFT")
+	errWrongGroupID       = errors.New("NFT has a different group ID than expected")
+	errWrongPayload       = errors.New("NFT has a different payload than expected")
+	errCantTransferNFTs   = errors.New("NFTs can only be transferred with an operation")
+	errWrongTransferCount = errors.New("transfer operation must create exactly one NFT")
+)
+
+// Fx manages non-fungible tokens. A MintOutput allows its owners to mint NFTs
+// into its group. Each NFT is a TransferOutput that can be sent to new owners
+// by a transfer operation. An NFT's group and payload never change once it's
+// minted.
+type Fx struct{ secp256k1fx.Fx }
+
+// Initialize ...
+func (fx *Fx) Initialize(vmIntf interface{}) error {
+	if err := fx.InitializeVM(vmIntf); err != nil {
+		return err
+	}
+
+	vm := vmIntf.(secp256k1fx.VM)
+	c := vm.Codec()
+	c.RegisterType(&MintOutput{})
+	c.RegisterType(&TransferOutput{})
+	c.RegisterType(&MintInput{})
+	c.RegisterType(&TransferInput{})
+	c.RegisterType(&Credential{})
+	return nil
+}
+
+// VerifyOperation ...
+func (fx *Fx) VerifyOperation(txIntf interface{}, utxosIntf, insIntf, credsIntf, outsIntf []interface{}) error {
+	tx, ok := txIntf.(secp256k1fx.Tx)
+	if !ok {
+		return errWrongTxType
+	}
+
+	if len(utxosIntf) != 1 || len(insIntf) != 1 {
+		return errWrongNumberOfInputs
+	}
+	if len(credsIntf) != 1 {
+		return errWrongNumberOfCredentials
+	}
+	cred, ok := credsIntf[0].(*Credential)
+	if !ok {
+		return errWrongCredentialType
+	}
+
+	switch in := insIntf[0].(type) {
+	case *MintInput:
+		utxo, ok := utxosIntf[0].(*MintOutput)
+		if !ok {
+			return errWrongUTXOType
+		}
+		return fx.verifyMintOperation(tx, utxo, in, cred, outsIntf)
+	case *TransferInput:
+		utxo, ok := utxosIntf[0].(*TransferOutput)
+		if !ok {
+			return errWrongUTXOType
+		}
+		return fx.verifyTransferOperation(tx, utxo, in, cred, outsIntf)
+	default:
+		return errWrongInputType
+	}
+}
+
+// verifyMintOperation verifies that the mint output is recreated and that
+// every other output is an NFT of the mint output's group
+func (fx *Fx) verifyMintOperation(tx secp256k1fx.Tx, utxo *MintOutput, in *MintInput, cred *Credential, outsIntf []interface{}) error {
+	if err := verify.All(utxo, in, cred); err != nil {
+		return err
+	}
+
+	numMints := 0
+	for _, outIntf := range outsIntf {
+		switch out := outIntf.(type) {
+		case *MintOutput:
+			if err := out.Verify(); err != nil {
+				return err
+			}
+			if out.GroupID != utxo.GroupID || !out.OutputOwners.Equals(&utxo.OutputOwners) {
+				return errWrongMintCreated
+			}
+			numMints++
+		case *TransferOutput:
+			if err := out.Verify(); err != nil {
+				return err
+			}
+			if out.GroupID != utxo.GroupID {
+				return errWrongGroupID
+			}
+		default:
+			return errWrongOutputType
+		}
+	}
+	switch {
+	case numMints != 1:
+		return errWrongMintCreated
+	case len(outsIntf) < 2:
+		return errNoNFTsMinted
+	}
+
+	return fx.VerifyCredentials(tx, &utxo.OutputOwners, &in.Input, &cred.Credential)
+}
+
+// verifyTransferOperation verifies that the NFT is sent, unchanged, to its new
+// owners
+func (fx *Fx) verifyTransferOperation(tx secp256k1fx.Tx, utxo *TransferOutput, in *TransferInput, cred *Credential, outsIntf []interface{}) error {
+	if len(outsIntf) != 1 {
+		return errWrongTransferCount
+	}
+	out, ok := outsIntf[0].(*TransferOutput)
+	if !ok {
+		return errWrongOutputType
+	}
+	if err := verify.All(utxo, in, cred, out); err != nil {
+		return err
+	}
+
+	switch {
+	case out.GroupID != utxo.GroupID:
+		return errWrongGroupID
+	case !bytes.Equal(out.Payload, utxo.Payload):
+		return errWrongPayload
+	}
+
+	return fx.VerifyCredentials(tx, &utxo.OutputOwners, &in.Input, &cred.Credential)
+}
+
+// VerifyTransfer ...
+func (fx *Fx) VerifyTransfer(_, _, _, _ interface{}) error { return errCantTransferNFTs }
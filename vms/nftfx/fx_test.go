@@ -0,0 +1,303 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package nftfx
+
+import (
+	"testing"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/vms/secp256k1fx"
+)
+
+func TestFxInitialize(t *testing.T) {
+	fx := Fx{}
+	if err := fx.Initialize(&secp256k1fx.TestVM{}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFxInitializeInvalid(t *testing.T) {
+	fx := Fx{}
+	if err := fx.Initialize(nil); err == nil {
+		t.Fatalf("Should have returned an error")
+	}
+}
+
+func TestFxVerifyMintOperation(t *testing.T) {
+	fx := Fx{}
+	if err := fx.Initialize(&secp256k1fx.TestVM{}); err != nil {
+		t.Fatal(err)
+	}
+	tx := &secp256k1fx.TestTx{Bytes: secp256k1fx.TestTxBytes}
+	utxo := &MintOutput{
+		GroupID:      1,
+		OutputOwners: secp256k1fx.TestOwners(),
+	}
+	in := &MintInput{Input: secp256k1fx.TestInput()}
+	mintOutput := &MintOutput{
+		GroupID:      1,
+		OutputOwners: secp256k1fx.TestOwners(),
+	}
+	nft1 := &TransferOutput{
+		GroupID:      1,
+		Payload:      []byte{1},
+		OutputOwners: secp256k1fx.TestOwners(),
+	}
+	nft2 := &TransferOutput{
+		GroupID:      1,
+		Payload:      []byte{2},
+		OutputOwners: secp256k1fx.TestOwners(),
+	}
+
+	utxos := []interface{}{utxo}
+	ins := []interface{}{in}
+	creds := []interface{}{&Credential{Credential: secp256k1fx.TestCredential()}}
+	outs := []interface{}{nft1, mintOutput, nft2}
+	if err := fx.VerifyOperation(tx, utxos, ins, creds, outs); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFxVerifyMintOperationWrongGroupID(t *testing.T) {
+	fx := Fx{}
+	if err := fx.Initialize(&secp256k1fx.TestVM{}); err != nil {
+		t.Fatal(err)
+	}
+	tx := &secp256k1fx.TestTx{Bytes: secp256k1fx.TestTxBytes}
+	utxo := &MintOutput{
+		GroupID:      1,
+		OutputOwners: secp256k1fx.TestOwners(),
+	}
+	in := &MintInput{Input: secp256k1fx.TestInput()}
+	mintOutput := &MintOutput{
+		GroupID:      1,
+		OutputOwners: secp256k1fx.TestOwners(),
+	}
+	nft := &TransferOutput{
+		GroupID:      2,
+		OutputOwners: secp256k1fx.TestOwners(),
+	}
+
+	utxos := []interface{}{utxo}
+	ins := []interface{}{in}
+	creds := []interface{}{&Credential{Credential: secp256k1fx.TestCredential()}}
+	outs := []interface{}{mintOutput, nft}
+	if err := fx.VerifyOperation(tx, utxos, ins, creds, outs); err != errWrongGroupID {
+		t.Fatalf("Should have errored with %s, errored with %v", errWrongGroupID, err)
+	}
+}
+
+func TestFxVerifyMintOperationMintNotRecreated(t *testing.T) {
+	fx := Fx{}
+	if err := fx.Initialize(&secp256k1fx.TestVM{}); err != nil {
+		t.Fatal(err)
+	}
+	tx := &secp256k1fx.TestTx{Bytes: secp256k1fx.TestTxBytes}
+	utxo := &MintOutput{
+		GroupID:      1,
+		OutputOwners: secp256k1fx.TestOwners(),
+	}
+	in := &MintInput{Input: secp256k1fx.TestInput()}
+	nft := &TransferOutput{
+		GroupID:      1,
+		OutputOwners: secp256k1fx.TestOwners(),
+	}
+
+	utxos := []interface{}{utxo}
+	ins := []interface{}{in}
+	creds := []interface{}{&Credential{Credential: secp256k1fx.TestCredential()}}
+	outs := []interface{}{nft}
+	if err := fx.VerifyOperation(tx, utxos, ins, creds, outs); err != errWrongMintCreated {
+		t.Fatalf("Should have errored with %s, errored with %v", errWrongMintCreated, err)
+	}
+}
+
+func TestFxVerifyMintOperationNoNFTs(t *testing.T) {
+	fx := Fx{}
+	if err := fx.Initialize(&secp256k1fx.TestVM{}); err != nil {
+		t.Fatal(err)
+	}
+	tx := &secp256k1fx.TestTx{Bytes: secp256k1fx.TestTxBytes}
+	utxo := &MintOutput{
+		GroupID:      1,
+		OutputOwners: secp256k1fx.TestOwners(),
+	}
+	in := &MintInput{Input: secp256k1fx.TestInput()}
+	mintOutput := &MintOutput{
+		GroupID:      1,
+		OutputOwners: secp256k1fx.TestOwners(),
+	}
+
+	utxos := []interface{}{utxo}
+	ins := []interface{}{in}
+	creds := []interface{}{&Credential{Credential: secp256k1fx.TestCredential()}}
+	outs := []interface{}{mintOutput}
+	if err := fx.VerifyOperation(tx, utxos, ins, creds, outs); err != errNoNFTsMinted {
+		t.Fatalf("Should have errored with %s, errored with %v", errNoNFTsMinted, err)
+	}
+}
+
+func TestFxVerifyMintOperationWrongSigner(t *testing.T) {
+	fx := Fx{}
+	if err := fx.Initialize(&secp256k1fx.TestVM{}); err != nil {
+		t.Fatal(err)
+	}
+	tx := &secp256k1fx.TestTx{Bytes: []byte{6}}
+	utxo := &MintOutput{
+		GroupID:      1,
+		OutputOwners: secp256k1fx.TestOwners(),
+	}
+	in := &MintInput{Input: secp256k1fx.TestInput()}
+	mintOutput := &MintOutput{
+		GroupID:      1,
+		OutputOwners: secp256k1fx.TestOwners(),
+	}
+	nft := &TransferOutput{
+		GroupID:      1,
+		OutputOwners: secp256k1fx.TestOwners(),
+	}
+
+	utxos := []interface{}{utxo}
+	ins := []interface{}{in}
+	creds := []interface{}{&Credential{Credential: secp256k1fx.TestCredential()}}
+	outs := []interface{}{mintOutput, nft}
+	if err := fx.VerifyOperation(tx, utxos, ins, creds, outs); err == nil {
+		t.Fatalf("Should have errored due to the signature being over different bytes")
+	}
+}
+
+func TestFxVerifyTransferOperation(t *testing.T) {
+	fx := Fx{}
+	if err := fx.Initialize(&secp256k1fx.TestVM{}); err != nil {
+		t.Fatal(err)
+	}
+	tx := &secp256k1fx.TestTx{Bytes: secp256k1fx.TestTxBytes}
+	utxo := &TransferOutput{
+		GroupID:      1,
+		Payload:      []byte{1, 2, 3},
+		OutputOwners: secp256k1fx.TestOwners(),
+	}
+	in := &TransferInput{Input: secp256k1fx.TestInput()}
+	nft := &TransferOutput{
+		GroupID: 1,
+		Payload: []byte{1, 2, 3},
+		OutputOwners: secp256k1fx.OutputOwners{
+			Threshold: 1,
+			Addrs: []ids.ShortID{
+				ids.NewShortID([20]byte{1}),
+			},
+		},
+	}
+
+	utxos := []interface{}{utxo}
+	ins := []interface{}{in}
+	creds := []interface{}{&Credential{Credential: secp256k1fx.TestCredential()}}
+	outs := []interface{}{nft}
+	if err := fx.VerifyOperation(tx, utxos, ins, creds, outs); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFxVerifyTransferOperationWrongPayload(t *testing.T) {
+	fx := Fx{}
+	if err := fx.Initialize(&secp256k1fx.TestVM{}); err != nil {
+		t.Fatal(err)
+	}
+	tx := &secp256k1fx.TestTx{Bytes: secp256k1fx.TestTxBytes}
+	utxo := &TransferOutput{
+		GroupID:      1,
+		Payload:      []byte{1, 2, 3},
+		OutputOwners: secp256k1fx.TestOwners(),
+	}
+	in := &TransferInput{Input: secp256k1fx.TestInput()}
+	nft := &TransferOutput{
+		GroupID:      1,
+		Payload:      []byte{3, 2, 1},
+		OutputOwners: secp256k1fx.TestOwners(),
+	}
+
+	utxos := []interface{}{utxo}
+	ins := []interface{}{in}
+	creds := []interface{}{&Credential{Credential: secp256k1fx.TestCredential()}}
+	outs := []interface{}{nft}
+	if err := fx.VerifyOperation(tx, utxos, ins, creds, outs); err != errWrongPayload {
+		t.Fatalf("Should have errored with %s, errored with %v", errWrongPayload, err)
+	}
+}
+
+func TestFxVerifyTransferOperationTooManyOutputs(t *testing.T) {
+	fx := Fx{}
+	if err := fx.Initialize(&secp256k1fx.TestVM{}); err != nil {
+		t.Fatal(err)
+	}
+	tx := &secp256k1fx.TestTx{Bytes: secp256k1fx.TestTxBytes}
+	utxo := &TransferOutput{
+		GroupID:      1,
+		OutputOwners: secp256k1fx.TestOwners(),
+	}
+	in := &TransferInput{Input: secp256k1fx.TestInput()}
+	nft := &TransferOutput{
+		GroupID:      1,
+		OutputOwners: secp256k1fx.TestOwners(),
+	}
+
+	utxos := []interface{}{utxo}
+	ins := []interface{}{in}
+	creds := []interface{}{&Credential{Credential: secp256k1fx.TestCredential()}}
+	outs := []interface{}{nft, nft}
+	if err := fx.VerifyOperation(tx, utxos, ins, creds, outs); err != errWrongTransferCount {
+		t.Fatalf("Should have errored with %s, errored with %v", errWrongTransferCount, err)
+	}
+}
+
+func TestFxVerifyOperationWrongUTXOType(t *testing.T) {
+	fx := Fx{}
+	if err := fx.Initialize(&secp256k1fx.TestVM{}); err != nil {
+		t.Fatal(err)
+	}
+	tx := &secp256k1fx.TestTx{Bytes: secp256k1fx.TestTxBytes}
+	utxo := &TransferOutput{
+		GroupID:      1,
+		OutputOwners: secp256k1fx.TestOwners(),
+	}
+	in := &MintInput{Input: secp256k1fx.TestInput()}
+	mintOutput := &MintOutput{
+		GroupID:      1,
+		OutputOwners: secp256k1fx.TestOwners(),
+	}
+
+	utxos := []interface{}{utxo}
+	ins := []interface{}{in}
+	creds := []interface{}{&Credential{Credential: secp256k1fx.TestCredential()}}
+	outs := []interface{}{mintOutput}
+	if err := fx.VerifyOperation(tx, utxos, ins, creds, outs); err != errWrongUTXOType {
+		t.Fatalf("Should have errored with %s, errored with %v", errWrongUTXOType, err)
+	}
+}
+
+func TestFxVerifyTransfer(t *testing.T) {
+	fx := Fx{}
+	if err := fx.Initialize(&secp256k1fx.TestVM{}); err != nil {
+		t.Fatal(err)
+	}
+	tx := &secp256k1fx.TestTx{Bytes: secp256k1fx.TestTxBytes}
+	utxo := &TransferOutput{
+		GroupID:      1,
+		OutputOwners: secp256k1fx.TestOwners(),
+	}
+	in := &TransferInput{Input: secp256k1fx.TestInput()}
+	if err := fx.VerifyTransfer(tx, utxo, in, &Credential{Credential: secp256k1fx.TestCredential()}); err != errCantTransferNFTs {
+		t.Fatalf("Should have errored with %s, errored with %v", errCantTransferNFTs, err)
+	}
+}
+
+func TestTransferOutputPayloadTooLarge(t *testing.T) {
+	out := &TransferOutput{
+		Payload:      make([]byte, MaxPayloadSize+1),
+		OutputOwners: secp256k1fx.TestOwners(),
+	}
+	if err := out.Verify(); err != errPayloadTooLarge {
+		t.Fatalf("Should have errored with %s, errored with %v", errPayloadTooLarge, err)
+	}
+}
@@ -0,0 +1,39 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package nftfx
+
+import (
+	"errors"
+
+	"github.com/ava-labs/gecko/vms/secp256k1fx"
+)
+
+const (
+	// MaxPayloadSize is the maximum size of an NFT's payload in bytes
+	MaxPayloadSize = 1 << 10
+)
+
+var (
+	errPayloadTooLarge = errors.New("payload too large")
+)
+
+// TransferOutput is a single NFT of the group [GroupID]. The NFT is described
+// by [Payload].
+type TransferOutput struct {
+	GroupID                  uint32 `serialize:"true"`
+	Payload                  []byte `serialize:"true"`
+	secp256k1fx.OutputOwners `serialize:"true"`
+}
+
+// Verify ...
+func (out *TransferOutput) Verify() error {
+	switch {
+	case out == nil:
+		return errNilOutput
+	case len(out.Payload) > MaxPayloadSize:
+		return errPayloadTooLarge
+	default:
+		return out.OutputOwners.Verify()
+	}
+}
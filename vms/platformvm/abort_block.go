@@ -4,6 +4,8 @@
 package platformvm
 
 import (
+	"context"
+
 	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/vms/components/core"
 )
@@ -19,7 +21,7 @@ type Abort struct {
 // The parent block must be a proposal
 //
 // This function also sets onAcceptDB database if the verification passes.
-func (a *Abort) Verify() error {
+func (a *Abort) Verify(context.Context) error {
 	// Abort is a decision, so its parent must be a proposal
 	if parent, ok := a.parentBlock().(*ProposalBlock); ok {
 		a.onAcceptDB, a.onAcceptFunc = parent.onAbort()
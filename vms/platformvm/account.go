@@ -4,7 +4,6 @@
 package platformvm
 
 import (
-	"errors"
 	"fmt"
 
 	stdmath "math"
@@ -12,6 +11,7 @@ import (
 	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/utils/math"
 	"github.com/ava-labs/gecko/utils/units"
+	"github.com/ava-labs/gecko/vms/components/verify"
 )
 
 var (
@@ -19,8 +19,8 @@ var (
 )
 
 var (
-	errOutOfSpends = errors.New("ran out of spends")
-	errInvalidID   = errors.New("invalid ID")
+	errOutOfSpends = verify.NewError(CodeOutOfSpends, "ran out of spends")
+	errInvalidID   = verify.NewError(CodeInvalidID, "invalid ID")
 )
 
 // Account represents the Balance and nonce of a user's funds
@@ -42,6 +42,13 @@ type Account struct {
 // Remove generates a new account state from removing [amount + txFee] from [a]'s balance.
 // [nonce] is [a]'s next unused nonce
 func (a Account) Remove(amount, nonce uint64) (Account, error) {
+	return a.RemoveWithFee(amount, txFee, nonce)
+}
+
+// RemoveWithFee generates a new account state from removing [amount + fee]
+// from [a]'s balance.
+// [nonce] is [a]'s next unused nonce
+func (a Account) RemoveWithFee(amount, fee, nonce uint64) (Account, error) {
 	// Ensure account is in a valid state
 	if err := a.Verify(); err != nil {
 		return Account{}, err
@@ -56,22 +63,22 @@ func (a Account) Remove(amount, nonce uint64) (Account, error) {
 	}
 
 	if newNonce != nonce {
-		return Account{}, fmt.Errorf("account's last nonce is %d so expected tx nonce to be %d but was %d", a.Nonce, newNonce, nonce)
+		return Account{}, verify.WrapError(CodeWrongNonce, fmt.Errorf("account's last nonce is %d so expected tx nonce to be %d but was %d", a.Nonce, newNonce, nonce))
 	}
 
-	amountWithFee, err := math.Add64(amount, txFee)
+	amountWithFee, err := math.Add64(amount, fee)
 	if err != nil {
-		return Account{}, fmt.Errorf("send amount overflowed: tx fee (%d) + send amount (%d) > maximum value", txFee, amount)
+		return Account{}, verify.WrapError(CodeSpendOverflow, fmt.Errorf("send amount overflowed: tx fee (%d) + send amount (%d) > maximum value", fee, amount))
 	}
 
 	newBalance, err := math.Sub64(a.Balance, amountWithFee)
 	if err != nil {
-		return Account{}, fmt.Errorf("insufficient funds: account balance %d < tx fee (%d) + send amount (%d)", a.Balance, txFee, amount)
+		return Account{}, verify.WrapError(CodeInsufficientFunds, fmt.Errorf("insufficient funds: account balance %d < tx fee (%d) + send amount (%d)", a.Balance, fee, amount))
 	}
 
 	// Ensure this tx wouldn't lock funds
 	if newNonce == stdmath.MaxUint64 && newBalance != 0 {
-		return Account{}, fmt.Errorf("transaction would lock %d funds", newBalance)
+		return Account{}, verify.WrapError(CodeFundsLocked, fmt.Errorf("transaction would lock %d funds", newBalance))
 	}
 
 	return Account{
@@ -98,7 +105,7 @@ func (a Account) Add(amount uint64) (Account, error) {
 	// account's balance after receipt of staked $AVA
 	newBalance, err := math.Add64(a.Balance, amount)
 	if err != nil {
-		return a, fmt.Errorf("account balance (%d) + staked $AVA (%d) exceeds maximum uint64", a.Balance, amount)
+		return a, verify.WrapError(CodeBalanceOverflow, fmt.Errorf("account balance (%d) + staked $AVA (%d) exceeds maximum uint64", a.Balance, amount))
 	}
 
 	return Account{
@@ -124,9 +124,22 @@ func (tx *addDefaultSubnetDelegatorTx) SemanticVerify(db database.Database) (*ve
 		return nil, nil, nil, nil, errDBAccount
 	}
 
-	// The account if this block's proposal is committed and the validator is added
+	// The delegator stakes its own $AVA, which is returned (with its share of
+	// the reward, if applicable) when it's done delegating
+	amount := tx.Weight()
+
+	// Ensure the delegator stakes at least the minimum amount set by governance
+	params, err := tx.vm.getGovernanceParameters(db)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	if amount < params.MinimumStake {
+		return nil, nil, nil, nil, errWeightTooSmall
+	}
+
+	// The account if this block's proposal is committed and the delegator is added
 	// to the pending validator set. (Increase the account's nonce; decrease its balance.)
-	newAccount, err := account.Remove(0, tx.Nonce) // Remove also removes the fee
+	newAccount, err := account.RemoveWithFee(amount, params.TxFee, tx.Nonce)
 	if err != nil {
 		return nil, nil, nil, nil, err
 	}
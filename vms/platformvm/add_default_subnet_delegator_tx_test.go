@@ -377,3 +377,59 @@ func TestAddDefaultSubnetDelegatorTxSemanticVerify(t *testing.T) {
 	}
 	txFee = txFeeSaved // Reset tx fee
 }
+
+func TestAddDefaultSubnetDelegatorTxStakes(t *testing.T) {
+	vm := defaultVM()
+
+	tx, err := vm.newAddDefaultSubnetDelegatorTx(
+		defaultNonce+1,                          // nonce
+		MinimumStakeAmount,                      // weight
+		uint64(defaultValidateStartTime.Unix()), // start time
+		uint64(defaultValidateEndTime.Unix()),   // end time
+		keys[1].PublicKey().Address(),           // node ID
+		defaultKey.PublicKey().Address(),        // destination
+		testNetworkID,                           // network ID
+		defaultKey,                              // tx fee payer and staker
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	onCommitDB, onAbortDB, _, _, err := tx.SemanticVerify(vm.DB)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	account, err := vm.getAccount(onCommitDB, defaultKey.PublicKey().Address())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := defaultBalance - MinimumStakeAmount - txFee; account.Balance != expected {
+		t.Fatalf("Expected the delegator's balance to be %d but was %d", expected, account.Balance)
+	}
+
+	account, err = vm.getAccount(onAbortDB, defaultKey.PublicKey().Address())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if account.Balance != defaultBalance {
+		t.Fatalf("Expected the delegator's balance to be %d but was %d", defaultBalance, account.Balance)
+	}
+
+	// The delegator can't stake more than its balance
+	tx, err = vm.newAddDefaultSubnetDelegatorTx(
+		defaultNonce+1,                          // nonce
+		defaultBalance+1,                        // weight
+		uint64(defaultValidateStartTime.Unix()), // start time
+		uint64(defaultValidateEndTime.Unix()),   // end time
+		keys[1].PublicKey().Address(),           // node ID
+		defaultKey.PublicKey().Address(),        // destination
+		testNetworkID,                           // network ID
+		defaultKey,                              // tx fee payer and staker
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, _, _, err := tx.SemanticVerify(vm.DB); err == nil {
+		t.Fatal("should have failed because the delegator can't afford its stake")
+	}
+}
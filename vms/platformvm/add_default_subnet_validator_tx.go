@@ -4,7 +4,6 @@
 package platformvm
 
 import (
-	"errors"
 	"fmt"
 
 	"github.com/ava-labs/gecko/database"
@@ -13,15 +12,18 @@ import (
 	"github.com/ava-labs/gecko/snow/validators"
 	"github.com/ava-labs/gecko/utils/crypto"
 	"github.com/ava-labs/gecko/utils/hashing"
+	"github.com/ava-labs/gecko/vms/components/verify"
 )
 
 var (
-	errNilTx          = errors.New("nil tx is invalid")
-	errWrongNetworkID = errors.New("tx was issued with a different network ID")
-	errWeightTooSmall = errors.New("weight of this validator is too low")
-	errStakeTooShort  = errors.New("staking period is too short")
-	errStakeTooLong   = errors.New("staking period is too long")
-	errTooManyShares  = fmt.Errorf("a staker can only require at most %d shares from delegators", NumberOfShares)
+	errNilTx          = verify.NewError(CodeNilTx, "nil tx is invalid")
+	errWrongNetworkID = verify.NewError(CodeWrongNetworkID, "tx was issued with a different network ID")
+	errWeightTooSmall = verify.NewError(CodeWeightTooSmall, "weight of this validator is too low")
+	errStakeTooShort  = verify.NewError(CodeStakeTooShort, "staking period is too short")
+	errStakeTooLong   = verify.NewError(CodeStakeTooLong, "staking period is too long")
+	errTooManyShares  = verify.NewError(CodeTooManyShares, fmt.Sprintf("a staker can only require at most %d shares from delegators", NumberOfShares))
+
+	errDelegationFeeBounds = verify.NewError(CodeDelegationFeeBounds, "delegation fee is outside of the bounds set by governance")
 )
 
 // UnsignedAddDefaultSubnetValidatorTx is an unsigned addDefaultSubnetValidatorTx
@@ -138,9 +140,22 @@ func (tx *addDefaultSubnetValidatorTx) SemanticVerify(db database.Database) (*ve
 	// staked $AVA
 	amount := tx.Weight()
 
+	// Ensure the validator stakes at least the minimum amount set by governance
+	params, err := tx.vm.getGovernanceParameters(db)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	if amount < params.MinimumStake {
+		return nil, nil, nil, nil, errWeightTooSmall
+	}
+	// Ensure the validator's delegation fee is within the bounds set by governance
+	if shares := uint64(tx.Shares); shares < params.MinDelegationFee || shares > params.MaxDelegationFee {
+		return nil, nil, nil, nil, errDelegationFeeBounds
+	}
+
 	// The account if this block's proposal is committed and the validator is added
 	// to the pending validator set. (Increase the account's nonce; decrease its balance.)
-	newAccount, err := account.Remove(amount, tx.Nonce)
+	newAccount, err := account.RemoveWithFee(amount, params.TxFee, tx.Nonce)
 	if err != nil {
 		return nil, nil, nil, nil, err
 	}
@@ -311,3 +311,46 @@ func TestAddDefaultSubnetValidatorTxSemanticVerify(t *testing.T) {
 		t.Fatal("should have failed because validator in pending validator set")
 	}
 }
+
+func TestAddDefaultSubnetValidatorTxDelegationFeeBounds(t *testing.T) {
+	vm := defaultVM()
+
+	params := DefaultGovernanceParameters()
+	params.MinDelegationFee = NumberOfShares / 100
+	params.MaxDelegationFee = NumberOfShares / 10
+	if err := vm.putGovernanceParameters(vm.DB, params); err != nil {
+		t.Fatal(err)
+	}
+
+	key, err := vm.factory.NewPrivateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, test := range []struct {
+		shares uint32
+		err    error
+	}{
+		{shares: NumberOfShares/100 - 1, err: errDelegationFeeBounds},
+		{shares: NumberOfShares / 100},
+		{shares: NumberOfShares / 10},
+		{shares: NumberOfShares/10 + 1, err: errDelegationFeeBounds},
+	} {
+		tx, err := vm.newAddDefaultSubnetValidatorTx(
+			defaultNonce+1,
+			defaultStakeAmount,
+			uint64(defaultValidateStartTime.Unix()),
+			uint64(defaultValidateEndTime.Unix()),
+			key.PublicKey().Address(),
+			defaultKey.PublicKey().Address(),
+			test.shares,
+			testNetworkID,
+			defaultKey,
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, _, _, _, err := tx.SemanticVerify(vm.DB); err != test.err {
+			t.Fatalf("with %d shares expected %v but got %v", test.shares, test.err, err)
+		}
+	}
+}
@@ -13,12 +13,13 @@ import (
 	"github.com/ava-labs/gecko/snow/validators"
 	"github.com/ava-labs/gecko/utils/crypto"
 	"github.com/ava-labs/gecko/utils/hashing"
+	"github.com/ava-labs/gecko/vms/components/verify"
 )
 
 var (
-	errSigsNotSorted           = errors.New("control signatures not sorted")
-	errWrongNumberOfSignatures = errors.New("wrong number of signatures")
-	errDSValidatorSubset       = errors.New("all subnets must be a subset of the default subnet")
+	errSigsNotSorted           = verify.NewError(CodeSigsNotSorted, "control signatures not sorted")
+	errWrongNumberOfSignatures = verify.NewError(CodeWrongNumberOfSignatures, "wrong number of signatures")
+	errDSValidatorSubset       = verify.NewError(CodeDSValidatorSubset, "all subnets must be a subset of the default subnet")
 )
 
 // UnsignedAddNonDefaultSubnetValidatorTx is an unsigned addNonDefaultSubnetValidatorTx
@@ -242,9 +243,14 @@ func (tx *addNonDefaultSubnetValidatorTx) SemanticVerify(db database.Database) (
 		return nil, nil, nil, nil, errDBAccount
 	}
 
+	params, err := tx.vm.getGovernanceParameters(db)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+
 	// The account if this block's proposal is committed and the validator is added
 	// to the pending validator set. (Increase the account's nonce; decrease its balance.)
-	newAccount, err := account.Remove(0, tx.Nonce) // Remove also removes the fee
+	newAccount, err := account.RemoveWithFee(0, params.TxFee, tx.Nonce)
 	if err != nil {
 		return nil, nil, nil, nil, err
 	}
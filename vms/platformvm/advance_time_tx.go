@@ -9,11 +9,15 @@ import (
 
 	"github.com/ava-labs/gecko/database"
 	"github.com/ava-labs/gecko/database/versiondb"
+	"github.com/ava-labs/gecko/ids"
 )
 
 // advanceTimeTx is a transaction to increase the chain's timestamp.
 // When the chain's timestamp is updated (a AdvanceTimeTx is accepted and
-// followed by a commit block) the staker set is also updated accordingly.
+// followed by a commit block) the staker set is also updated accordingly, and
+// governance proposals whose activation time has been reached take effect.
+// If the new timestamp is in a later epoch than the current timestamp, the
+// validator manager is updated to the staker set as of the start of that epoch.
 // It must be that:
 //   * proposed timestamp > [current chain time]
 //   * proposed timestamp <= [time for next staker to be removed]
@@ -86,59 +90,68 @@ func (tx *advanceTimeTx) SemanticVerify(db database.Database) (*versiondb.Databa
 		return nil, nil, nil, nil, err
 	}
 
-	current, pending, err := tx.vm.calculateValidators(db, tx.Timestamp(), DefaultSubnetID)
-	if err != nil {
+	// Activate the governance proposals whose activation time has been reached
+	if err := tx.vm.activateProposals(onCommitDB, tx.Timestamp()); err != nil {
 		return nil, nil, nil, nil, err
 	}
 
-	if err := tx.vm.putCurrentValidators(onCommitDB, current, DefaultSubnetID); err != nil {
-		return nil, nil, nil, nil, err
-	}
-	if err := tx.vm.putPendingValidators(onCommitDB, pending, DefaultSubnetID); err != nil {
-		return nil, nil, nil, nil, err
-	}
+	// Whether this tx moves the chain into a new epoch
+	newEpoch := epoch(tx.Timestamp()) > epoch(currentTimestamp)
 
-	// For each subnet, calculate what current and pending validator sets should be
-	// given new timestamp
+	// For the default subnet and each other subnet, calculate what current and
+	// pending validator sets should be given new timestamp
 	subnets, err := tx.vm.getSubnets(db)
 	if err != nil {
 		return nil, nil, nil, nil, err
 	}
+	subnetIDs := []ids.ID{DefaultSubnetID}
 	for _, subnet := range subnets {
-		current, pending, err := tx.vm.calculateValidators(db, tx.Timestamp(), subnet.ID)
+		subnetIDs = append(subnetIDs, subnet.ID)
+	}
+	for _, subnetID := range subnetIDs {
+		current, pending, err := tx.vm.calculateValidators(db, tx.Timestamp(), subnetID)
 		if err != nil {
 			return nil, nil, nil, nil, err
 		}
 
-		if err := tx.vm.putCurrentValidators(onCommitDB, current, subnet.ID); err != nil {
+		if err := tx.vm.putCurrentValidators(onCommitDB, current, subnetID); err != nil {
 			return nil, nil, nil, nil, err
 		}
-		if err := tx.vm.putPendingValidators(onCommitDB, pending, subnet.ID); err != nil {
+		if err := tx.vm.putPendingValidators(onCommitDB, pending, subnetID); err != nil {
 			return nil, nil, nil, nil, err
 		}
-	}
 
-	// If this block is committed, update the validator sets
-	// onAbortDB or onCommitDB should commit (flush to vm.DB) before this is called
-	updateValidators := func() {
-		subnets, err := tx.vm.getSubnets(tx.vm.DB)
-		if err != nil {
-			tx.vm.Ctx.Log.Error("failed to get subnets: %s", err)
-			return
+		if !newEpoch {
+			continue
 		}
-		for _, subnet := range subnets {
-			if err := tx.vm.updateValidators(subnet.ID); err != nil {
-				tx.vm.Ctx.Log.Debug("failed to update validators on the default subnet: %s", err)
-			}
+
+		// The validators of the new epoch are the validators at the time
+		// the epoch started
+		epochValidators, _, err := tx.vm.calculateValidators(db, epochStartTime(tx.Timestamp()), subnetID)
+		if err != nil {
+			return nil, nil, nil, nil, err
 		}
-		if err := tx.vm.updateValidators(DefaultSubnetID); err != nil {
-			tx.vm.Ctx.Log.Fatal("failed to update validators on the default subnet: %s", err)
+		if err := tx.vm.putEpochValidators(onCommitDB, epochValidators, subnetID); err != nil {
+			return nil, nil, nil, nil, err
 		}
 	}
 
 	// Specify what the state of the chain will be if this proposal is aborted
 	onAbortDB := versiondb.New(db) // state doesn't change
 
+	// The validator sets only change at the start of an epoch
+	if !newEpoch {
+		return onCommitDB, onAbortDB, nil, nil, nil
+	}
+
+	// If this block is committed, update the validator sets
+	// onAbortDB or onCommitDB should commit (flush to vm.DB) before this is called
+	updateValidators := func() {
+		if err := tx.vm.updateAllValidators(); err != nil {
+			tx.vm.Ctx.Log.Fatal("failed to update validators: %s", err)
+		}
+	}
+
 	return onCommitDB, onAbortDB, updateValidators, nil, nil
 }
 
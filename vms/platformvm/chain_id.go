@@ -0,0 +1,32 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/hashing"
+	"github.com/ava-labs/gecko/utils/wrappers"
+)
+
+// ComputeGenesisHash returns the canonical hash of a chain's genesis data
+func ComputeGenesisHash(genesisData []byte) ids.ID {
+	return ids.NewID(hashing.ComputeHash256Array(genesisData))
+}
+
+// ComputeChainID returns the ID of the chain named [chainName] validated by the
+// subnet [subnetID] on the network [networkID] whose genesis data hashes to
+// [genesisHash].
+//
+// The ID only depends on these values, so it can be computed by tooling before
+// the chain is created, and no two chains of a subnet may share both a name
+// and genesis data. Chains are only identified this way on networks whose
+// genesis state enables deterministic chain IDs.
+func ComputeChainID(subnetID ids.ID, networkID uint32, genesisHash ids.ID, chainName string) ids.ID {
+	p := wrappers.Packer{MaxSize: 2*wrappers.IntLen + 2*hashing.HashLen + len(chainName)}
+	p.PackFixedBytes(subnetID.Bytes())
+	p.PackInt(networkID)
+	p.PackFixedBytes(genesisHash.Bytes())
+	p.PackBytes([]byte(chainName))
+	return ids.NewID(hashing.ComputeHash256Array(p.Bytes))
+}
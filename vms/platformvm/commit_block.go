@@ -4,6 +4,8 @@
 package platformvm
 
 import (
+	"context"
+
 	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/vms/components/core"
 )
@@ -19,7 +21,7 @@ type Commit struct {
 // The parent block must either be a proposal
 //
 // This function also sets the onCommit databases if the verification passes.
-func (c *Commit) Verify() error {
+func (c *Commit) Verify(context.Context) error {
 	// the parent of an Commit block should always be a proposal
 	if parent, ok := c.parentBlock().(*ProposalBlock); ok {
 		c.onAcceptDB, c.onAcceptFunc = parent.onCommit()
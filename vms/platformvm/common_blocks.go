@@ -4,6 +4,7 @@
 package platformvm
 
 import (
+	"context"
 	"errors"
 
 	"github.com/ava-labs/gecko/vms/components/missing"
@@ -209,10 +210,16 @@ func (cdb *CommonDecisionBlock) onAccept() database.Database {
 }
 
 // Accept implements the snowman.Block interface
-func (cdb *CommonDecisionBlock) Accept() {
+func (cdb *CommonDecisionBlock) Accept(ctx context.Context) {
 	cdb.VM.Ctx.Log.Verbo("Accepting block with ID %s", cdb.ID())
 
-	cdb.CommonBlock.Accept()
+	cdb.CommonBlock.Accept(ctx)
+
+	// The chain time before this block was accepted
+	prevTimestamp, err := cdb.vm.getTimestamp(cdb.vm.DB)
+	if err != nil {
+		cdb.vm.Ctx.Log.Warn("unable to get the chain time before block %s", cdb.ID())
+	}
 
 	// Update the state of the chain in the database
 	if err := cdb.onAcceptDB.Commit(); err != nil {
@@ -221,6 +228,11 @@ func (cdb *CommonDecisionBlock) Accept() {
 	if err := cdb.vm.DB.Commit(); err != nil {
 		cdb.vm.Ctx.Log.Warn("unable to commit vm's DB")
 	}
+	if err == nil {
+		// Summarize the chain's state if this block moved the chain time into
+		// a new epoch
+		cdb.vm.checkpoint(cdb.ID(), cdb.Bytes(), prevTimestamp)
+	}
 
 	for _, child := range cdb.children {
 		child.setBaseDatabase(cdb.vm.DB)
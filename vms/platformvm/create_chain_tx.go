@@ -4,7 +4,6 @@
 package platformvm
 
 import (
-	"errors"
 	"fmt"
 
 	"github.com/ava-labs/gecko/chains"
@@ -12,11 +11,13 @@ import (
 	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/utils/crypto"
 	"github.com/ava-labs/gecko/utils/hashing"
+	"github.com/ava-labs/gecko/vms/components/verify"
 )
 
 var (
-	errInvalidVMID             = errors.New("invalid VM ID")
-	errFxIDsNotSortedAndUnique = errors.New("feature extensions IDs must be sorted and unique")
+	errInvalidVMID             = verify.NewError(CodeInvalidVMID, "invalid VM ID")
+	errFxIDsNotSortedAndUnique = verify.NewError(CodeFxIDsNotSortedAndUnique, "feature extensions IDs must be sorted and unique")
+	errInvalidEngine           = verify.NewError(CodeInvalidEngine, "invalid consensus engine")
 )
 
 // UnsignedCreateChainTx is an unsigned CreateChainTx
@@ -28,7 +29,8 @@ type UnsignedCreateChainTx struct {
 	// Currently unused, as there are no tx fees.
 	Nonce uint64 `serialize:"true"`
 
-	// A human readable name for the chain; need not be unique
+	// A human readable name for the chain. Chains with the same genesis data
+	// must have different names.
 	ChainName string `serialize:"true"`
 
 	// ID of the VM running on the new chain
@@ -39,6 +41,10 @@ type UnsignedCreateChainTx struct {
 
 	// Byte representation of state of the new chain
 	GenesisData []byte `serialize:"true"`
+
+	// The consensus engine that runs the new chain. If DefaultEngine, the
+	// engine is picked from the capabilities of the VM.
+	Engine chains.EngineType `serialize:"true"`
 }
 
 // CreateChainTx is a proposal to create a chain
@@ -47,23 +53,88 @@ type CreateChainTx struct {
 
 	Sig [crypto.SECP256K1RSigLen]byte `serialize:"true"`
 
-	vm    *VM
-	id    ids.ID
-	key   crypto.PublicKey // public key of transaction signer
-	bytes []byte
+	vm      *VM
+	id      ids.ID
+	chainID ids.ID
+	key     crypto.PublicKey // public key of transaction signer
+	bytes   []byte
+}
+
+// preEngineCreateChainTx is the encoding of a CreateChainTx before it named
+// its consensus engine
+type preEngineCreateChainTx struct {
+	NetworkID   uint32                        `serialize:"true"`
+	Nonce       uint64                        `serialize:"true"`
+	ChainName   string                        `serialize:"true"`
+	VMID        ids.ID                        `serialize:"true"`
+	FxIDs       []ids.ID                      `serialize:"true"`
+	GenesisData []byte                        `serialize:"true"`
+	Sig         [crypto.SECP256K1RSigLen]byte `serialize:"true"`
+}
+
+// createChainTx returns the transaction [tx] encodes. It runs the default
+// engine.
+func (tx *preEngineCreateChainTx) createChainTx() *CreateChainTx {
+	return &CreateChainTx{
+		UnsignedCreateChainTx: UnsignedCreateChainTx{
+			NetworkID:   tx.NetworkID,
+			Nonce:       tx.Nonce,
+			ChainName:   tx.ChainName,
+			VMID:        tx.VMID,
+			FxIDs:       tx.FxIDs,
+			GenesisData: tx.GenesisData,
+		},
+		Sig: tx.Sig,
+	}
 }
 
 func (tx *CreateChainTx) initialize(vm *VM) error {
 	tx.vm = vm
 	txBytes, err := Codec.Marshal(tx) // byte repr. of the signed tx
+	if err != nil {
+		return err
+	}
 	tx.bytes = txBytes
-	tx.id = ids.NewID(hashing.ComputeHash256Array(txBytes))
-	return err
+
+	// Transactions that run the default engine keep the ID they had before
+	// the engine was part of the transaction, so existing chains keep their IDs
+	idBytes := txBytes
+	if tx.Engine == chains.DefaultEngine {
+		idBytes, err = Codec.Marshal(&preEngineCreateChainTx{
+			NetworkID:   tx.NetworkID,
+			Nonce:       tx.Nonce,
+			ChainName:   tx.ChainName,
+			VMID:        tx.VMID,
+			FxIDs:       tx.FxIDs,
+			GenesisData: tx.GenesisData,
+			Sig:         tx.Sig,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	tx.id = ids.NewID(hashing.ComputeHash256Array(idBytes))
+	tx.chainID = tx.id
+	if vm != nil && vm.deterministicChainIDs {
+		tx.chainID = tx.computeChainID()
+	}
+	return nil
+}
+
+// computeChainID returns the ID ComputeChainID gives the chain this transaction
+// creates. Chains are validated by the default subnet.
+func (tx *CreateChainTx) computeChainID() ids.ID {
+	return ComputeChainID(DefaultSubnetID, tx.NetworkID, ComputeGenesisHash(tx.GenesisData), tx.ChainName)
 }
 
 // ID of this transaction
 func (tx *CreateChainTx) ID() ids.ID { return tx.id }
 
+// ChainID returns the ID of the chain this transaction creates. Unless the
+// network's genesis state enables deterministic chain IDs, this is the ID of
+// the transaction.
+func (tx *CreateChainTx) ChainID() ids.ID { return tx.chainID }
+
 // Key returns the public key of the signer of this transaction
 // Precondition: tx.Verify() has been called and returned nil
 func (tx *CreateChainTx) Key() crypto.PublicKey { return tx.key }
@@ -87,6 +158,8 @@ func (tx *CreateChainTx) SyntacticVerify() error {
 		return errInvalidVMID
 	case !ids.IsSortedAndUniqueIDs(tx.FxIDs):
 		return errFxIDsNotSortedAndUnique
+	case tx.Engine.Verify() != nil:
+		return errInvalidEngine
 	}
 
 	unsignedIntf := interface{}(&tx.UnsignedCreateChainTx)
@@ -115,8 +188,8 @@ func (tx *CreateChainTx) SemanticVerify(db database.Database) (func(), error) {
 		return nil, errDBChains
 	}
 	for _, chain := range currentChains {
-		if chain.ID().Equals(tx.ID()) {
-			return nil, fmt.Errorf("chain with ID %s already exists", chain.ID())
+		if chain.ChainID().Equals(tx.ChainID()) {
+			return nil, fmt.Errorf("chain with ID %s already exists", chain.ChainID())
 		}
 	}
 	currentChains = append(currentChains, tx) // add this new chain
@@ -125,11 +198,15 @@ func (tx *CreateChainTx) SemanticVerify(db database.Database) (func(), error) {
 	}
 
 	// Deduct tx fee from payer's account
+	params, err := tx.vm.getGovernanceParameters(db)
+	if err != nil {
+		return nil, err
+	}
 	account, err := tx.vm.getAccount(db, tx.Key().Address())
 	if err != nil {
 		return nil, err
 	}
-	account, err = account.Remove(0, tx.Nonce)
+	account, err = account.RemoveWithFee(0, params.TxFee, tx.Nonce)
 	if err != nil {
 		return nil, err
 	}
@@ -140,9 +217,10 @@ func (tx *CreateChainTx) SemanticVerify(db database.Database) (func(), error) {
 	// If this proposal is committed, create the new blockchain using the chain manager
 	onAccept := func() {
 		chainParams := chains.ChainParameters{
-			ID:          tx.ID(),
+			ID:          tx.ChainID(),
 			GenesisData: tx.GenesisData,
 			VMAlias:     tx.VMID.String(),
+			Engine:      tx.Engine,
 		}
 		for _, fxID := range tx.FxIDs {
 			chainParams.FxAliases = append(chainParams.FxAliases, fxID.String())
@@ -166,7 +244,7 @@ func (chains createChainList) Bytes() []byte {
 	return bytes
 }
 
-func (vm *VM) newCreateChainTx(nonce uint64, genesisData []byte, vmID ids.ID, fxIDs []ids.ID, chainName string, networkID uint32, key *crypto.PrivateKeySECP256K1R) (*CreateChainTx, error) {
+func (vm *VM) newCreateChainTx(nonce uint64, genesisData []byte, vmID ids.ID, fxIDs []ids.ID, engine chains.EngineType, chainName string, networkID uint32, key *crypto.PrivateKeySECP256K1R) (*CreateChainTx, error) {
 	tx := &CreateChainTx{
 		UnsignedCreateChainTx: UnsignedCreateChainTx{
 			NetworkID:   networkID,
@@ -175,6 +253,7 @@ func (vm *VM) newCreateChainTx(nonce uint64, genesisData []byte, vmID ids.ID, fx
 			VMID:        vmID,
 			FxIDs:       fxIDs,
 			ChainName:   chainName,
+			Engine:      engine,
 		},
 	}
 
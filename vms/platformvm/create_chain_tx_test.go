@@ -6,8 +6,10 @@ package platformvm
 import (
 	"testing"
 
+	"github.com/ava-labs/gecko/chains"
 	"github.com/ava-labs/gecko/database/versiondb"
 	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/hashing"
 	"github.com/ava-labs/gecko/vms/avm"
 )
 
@@ -27,6 +29,7 @@ func TestCreateChainTxSyntacticVerify(t *testing.T) {
 		nil,
 		avm.ID,
 		nil,
+		chains.DefaultEngine,
 		"chain name",
 		testNetworkID+1,
 		defaultKey,
@@ -46,6 +49,7 @@ func TestCreateChainTxSyntacticVerify(t *testing.T) {
 		nil,
 		avm.ID,
 		nil,
+		chains.DefaultEngine,
 		"chain name",
 		testNetworkID,
 		defaultKey,
@@ -64,6 +68,7 @@ func TestCreateChainTxSyntacticVerify(t *testing.T) {
 		nil,
 		avm.ID,
 		nil,
+		chains.DefaultEngine,
 		"chain name",
 		testNetworkID,
 		defaultKey,
@@ -75,6 +80,24 @@ func TestCreateChainTxSyntacticVerify(t *testing.T) {
 	if err := tx.SyntacticVerify(); err == nil {
 		t.Fatal("should've errored because tx ID is empty")
 	}
+
+	// Case 5: engine is unknown
+	tx, err = vm.newCreateChainTx(
+		defaultNonce+1,
+		nil,
+		avm.ID,
+		nil,
+		chains.EngineType(255),
+		"chain name",
+		testNetworkID,
+		defaultKey,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.SyntacticVerify(); err != errInvalidEngine {
+		t.Fatalf("should've errored because the engine is unknown but got %v", err)
+	}
 }
 
 func TestSemanticVerify(t *testing.T) {
@@ -86,6 +109,7 @@ func TestSemanticVerify(t *testing.T) {
 		nil,
 		avm.ID,
 		nil,
+		chains.DefaultEngine,
 		"chain name",
 		testNetworkID,
 		defaultKey,
@@ -122,6 +146,7 @@ func TestSemanticVerifyAlreadyExisting(t *testing.T) {
 		nil,
 		avm.ID,
 		nil,
+		chains.DefaultEngine,
 		"chain name",
 		testNetworkID,
 		defaultKey,
@@ -140,3 +165,206 @@ func TestSemanticVerifyAlreadyExisting(t *testing.T) {
 		t.Fatalf("should have failed because there is already a chain with ID %s", tx.id)
 	}
 }
+
+func TestCreateChainTxChainID(t *testing.T) {
+	vm := defaultVM()
+	vm.deterministicChainIDs = true
+
+	tx, err := vm.newCreateChainTx(
+		defaultNonce+1,
+		[]byte{1, 2, 3},
+		avm.ID,
+		nil,
+		chains.DefaultEngine,
+		"chain name",
+		testNetworkID,
+		defaultKey,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedID := ComputeChainID(DefaultSubnetID, testNetworkID, ComputeGenesisHash([]byte{1, 2, 3}), "chain name")
+	if !tx.ChainID().Equals(expectedID) {
+		t.Fatalf("wrong chain ID, expected %s got %s", expectedID, tx.ChainID())
+	}
+	if tx.ChainID().Equals(tx.ID()) {
+		t.Fatalf("chain ID shouldn't be the transaction ID")
+	}
+
+	// The chain ID shouldn't depend on who paid for the chain
+	otherTx, err := vm.newCreateChainTx(
+		defaultNonce+1,
+		[]byte{1, 2, 3},
+		avm.ID,
+		nil,
+		chains.DefaultEngine,
+		"chain name",
+		testNetworkID,
+		keys[1],
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !otherTx.ChainID().Equals(tx.ChainID()) {
+		t.Fatalf("chain IDs should have been equal")
+	}
+
+	if err := vm.putChains(vm.DB, []*CreateChainTx{tx}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := otherTx.SemanticVerify(vm.DB); err == nil {
+		t.Fatalf("should have failed because there is already a chain with ID %s", tx.ChainID())
+	}
+}
+
+func TestCreateChainTxLegacyChainID(t *testing.T) {
+	vm := defaultVM()
+
+	tx, err := vm.newCreateChainTx(
+		defaultNonce+1,
+		[]byte{1, 2, 3},
+		avm.ID,
+		nil,
+		chains.DefaultEngine,
+		"chain name",
+		testNetworkID,
+		defaultKey,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Unless the genesis state enables deterministic chain IDs, chains keep
+	// being identified by the transactions that create them
+	if !tx.ChainID().Equals(tx.ID()) {
+		t.Fatalf("chain ID should be the transaction ID")
+	}
+}
+
+func TestCreateChainTxEngine(t *testing.T) {
+	vm := defaultVM()
+
+	tx, err := vm.newCreateChainTx(
+		defaultNonce+1,
+		[]byte{1, 2, 3},
+		avm.ID,
+		nil,
+		chains.SnowmanEngine,
+		"chain name",
+		testNetworkID,
+		defaultKey,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.SyntacticVerify(); err != nil {
+		t.Fatal(err)
+	}
+
+	parsed := &CreateChainTx{}
+	if err := Codec.Unmarshal(tx.Bytes(), parsed); err != nil {
+		t.Fatal(err)
+	}
+	if err := parsed.initialize(vm); err != nil {
+		t.Fatal(err)
+	}
+	if parsed.Engine != chains.SnowmanEngine {
+		t.Fatalf("expected engine %s but got %s", chains.SnowmanEngine, parsed.Engine)
+	}
+	if !parsed.ID().Equals(tx.ID()) {
+		t.Fatalf("parsed tx should have ID %s but has %s", tx.ID(), parsed.ID())
+	}
+
+	// The engine is part of the ID of the transaction
+	defaultTx := *tx
+	defaultTx.Engine = chains.DefaultEngine
+	if err := defaultTx.initialize(vm); err != nil {
+		t.Fatal(err)
+	}
+	if defaultTx.ID().Equals(tx.ID()) {
+		t.Fatalf("transactions with different engines should have different IDs")
+	}
+}
+
+func TestCreateChainTxPreEngineID(t *testing.T) {
+	vm := defaultVM()
+
+	tx, err := vm.newCreateChainTx(
+		defaultNonce+1,
+		[]byte{1, 2, 3},
+		avm.ID,
+		nil,
+		chains.DefaultEngine,
+		"chain name",
+		testNetworkID,
+		defaultKey,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Chains stored before they named their engine are read with the ID they
+	// had before
+	preEngineTx := &preEngineCreateChainTx{
+		NetworkID:   tx.NetworkID,
+		Nonce:       tx.Nonce,
+		ChainName:   tx.ChainName,
+		VMID:        tx.VMID,
+		FxIDs:       tx.FxIDs,
+		GenesisData: tx.GenesisData,
+		Sig:         tx.Sig,
+	}
+	preEngineBytes, err := Codec.Marshal(preEngineTx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expectedID := ids.NewID(hashing.ComputeHash256Array(preEngineBytes)); !tx.ID().Equals(expectedID) {
+		t.Fatalf("tx should have ID %s but has %s", expectedID, tx.ID())
+	}
+
+	db := versiondb.New(vm.DB)
+	if err := vm.State.Put(db, chainsTypeID, chainsKey, preEngineChainList{preEngineTx}); err != nil {
+		t.Fatal(err)
+	}
+	storedChains, err := vm.getChains(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(storedChains) != 1 {
+		t.Fatalf("expected 1 chain but got %d", len(storedChains))
+	}
+	if storedChain := storedChains[0]; !storedChain.ID().Equals(tx.ID()) || storedChain.Engine != chains.DefaultEngine {
+		t.Fatalf("stored chain should have ID %s and the default engine", tx.ID())
+	}
+}
+
+// preEngineChainList is a list of chains as they were stored before they named
+// their engine
+type preEngineChainList []*preEngineCreateChainTx
+
+func (chains preEngineChainList) Bytes() []byte {
+	bytes, _ := Codec.Marshal(chains)
+	return bytes
+}
+
+func TestComputeChainID(t *testing.T) {
+	genesisHash := ComputeGenesisHash([]byte{1, 2, 3})
+	chainID := ComputeChainID(DefaultSubnetID, testNetworkID, genesisHash, "chain name")
+
+	if !chainID.Equals(ComputeChainID(DefaultSubnetID, testNetworkID, genesisHash, "chain name")) {
+		t.Fatalf("chain IDs should be deterministic")
+	}
+	if chainID.Equals(ComputeChainID(DefaultSubnetID, testNetworkID+1, genesisHash, "chain name")) {
+		t.Fatalf("chain IDs should depend on the network ID")
+	}
+	if chainID.Equals(ComputeChainID(DefaultSubnetID, testNetworkID, ComputeGenesisHash([]byte{1, 2}), "chain name")) {
+		t.Fatalf("chain IDs should depend on the genesis data")
+	}
+	if chainID.Equals(ComputeChainID(DefaultSubnetID, testNetworkID, genesisHash, "other name")) {
+		t.Fatalf("chain IDs should depend on the chain name")
+	}
+	if chainID.Equals(ComputeChainID(ids.NewID([32]byte{1}), testNetworkID, genesisHash, "chain name")) {
+		t.Fatalf("chain IDs should depend on the subnet ID")
+	}
+}
@@ -4,7 +4,6 @@
 package platformvm
 
 import (
-	"errors"
 	"fmt"
 
 	"github.com/ava-labs/gecko/database"
@@ -12,13 +11,14 @@ import (
 	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/utils/crypto"
 	"github.com/ava-labs/gecko/utils/hashing"
+	"github.com/ava-labs/gecko/vms/components/verify"
 )
 
 const maxThreshold = 25
 
 var (
-	errThresholdExceedsKeysLen = errors.New("threshold must be no more than number of control keys")
-	errThresholdTooHigh        = fmt.Errorf("threshold can't be greater than %d", maxThreshold)
+	errThresholdExceedsKeysLen = verify.NewError(CodeThresholdExceedsKeysLen, "threshold must be no more than number of control keys")
+	errThresholdTooHigh        = verify.NewError(CodeThresholdTooHigh, fmt.Sprintf("threshold can't be greater than %d", maxThreshold))
 )
 
 // UnsignedCreateSubnetTx is an unsigned proposal to create a new subnet
@@ -116,11 +116,15 @@ func (tx *CreateSubnetTx) SemanticVerify(db database.Database) (func(), error) {
 	}
 
 	// Deduct tx fee from payer's account
+	params, err := tx.vm.getGovernanceParameters(db)
+	if err != nil {
+		return nil, err
+	}
 	account, err := tx.vm.getAccount(db, tx.key.Address())
 	if err != nil {
 		return nil, err
 	}
-	account, err = account.Remove(0, tx.Nonce)
+	account, err = account.RemoveWithFee(0, params.TxFee, tx.Nonce)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,94 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"time"
+
+	"github.com/ava-labs/gecko/database"
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow/validators"
+)
+
+// Changes to a subnet's current validators are applied to the chain's state
+// as soon as they are accepted. However, the validator set that is reported to
+// the validator manager (and therefore to the networking layer) only changes at
+// epoch boundaries. When the chain time crosses into a new epoch, the validator
+// set of each subnet at the start of that epoch is persisted and the validator
+// manager is notified once.
+
+// epoch returns the index of the epoch containing [timestamp]
+func epoch(timestamp time.Time) int64 {
+	return timestamp.Unix() / int64(EpochDuration/time.Second)
+}
+
+// epochStartTime returns the time the epoch containing [timestamp] started
+func epochStartTime(timestamp time.Time) time.Time {
+	return time.Unix(epoch(timestamp)*int64(EpochDuration/time.Second), 0)
+}
+
+// nextEpochStartTime returns the time the epoch after the one containing
+// [timestamp] starts
+func nextEpochStartTime(timestamp time.Time) time.Time {
+	return epochStartTime(timestamp).Add(EpochDuration)
+}
+
+// nextEpochChangeTime returns the start of the epoch after the one containing
+// [timestamp] if the validator set of any subnet will change at that time.
+// Otherwise, returns maxTime.
+func (vm *VM) nextEpochChangeTime(db database.Database, timestamp time.Time) time.Time {
+	subnetIDs := []ids.ID{DefaultSubnetID}
+	subnets, err := vm.getSubnets(db)
+	if err != nil {
+		vm.Ctx.Log.Error("couldn't get subnets: %s", err)
+	}
+	for _, subnet := range subnets {
+		subnetIDs = append(subnetIDs, subnet.ID)
+	}
+
+	for _, subnetID := range subnetIDs {
+		changed, err := vm.epochValidatorsChanged(db, subnetID)
+		if err != nil {
+			vm.Ctx.Log.Error("couldn't compare the validators of subnet %s: %s", subnetID, err)
+			continue
+		}
+		if changed {
+			return nextEpochStartTime(timestamp)
+		}
+	}
+	return maxTime
+}
+
+// epochValidatorsChanged returns true if the current validators of subnet
+// [subnetID] differ from its validators as of the start of the current epoch
+func (vm *VM) epochValidatorsChanged(db database.Database, subnetID ids.ID) (bool, error) {
+	epochValidators, err := vm.getEpochValidators(db, subnetID)
+	if err != nil {
+		return false, err
+	}
+	currentValidators, err := vm.getCurrentValidators(db, subnetID)
+	if err != nil {
+		return false, err
+	}
+	return !sameValidators(vm.getValidators(epochValidators), vm.getValidators(currentValidators)), nil
+}
+
+// sameValidators returns true if [a] and [b] contain the same validators with
+// the same weights
+func sameValidators(a, b []validators.Validator) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	weights := make(map[[20]byte]uint64, len(a))
+	for _, vdr := range a {
+		weights[vdr.ID().Key()] = vdr.Weight()
+	}
+	for _, vdr := range b {
+		weight, exists := weights[vdr.ID().Key()]
+		if !exists || weight != vdr.Weight() {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,164 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"container/heap"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/gecko/utils/crypto"
+)
+
+func TestEpochStartTime(t *testing.T) {
+	start := time.Unix(0, 0).Add(10 * EpochDuration)
+
+	if s := epochStartTime(start); !s.Equal(start) {
+		t.Fatalf("epoch should start at %s but starts at %s", start, s)
+	}
+	if s := epochStartTime(start.Add(EpochDuration - time.Second)); !s.Equal(start) {
+		t.Fatalf("epoch should start at %s but starts at %s", start, s)
+	}
+	if s := nextEpochStartTime(start); !s.Equal(start.Add(EpochDuration)) {
+		t.Fatalf("next epoch should start at %s but starts at %s", start.Add(EpochDuration), s)
+	}
+	if e := epoch(start.Add(EpochDuration)); e != epoch(start)+1 {
+		t.Fatalf("expected epoch %d but got %d", epoch(start)+1, e)
+	}
+}
+
+// Ensure the validator manager is only updated at the start of an epoch
+func TestValidatorSetChangesAtEpochBoundary(t *testing.T) {
+	vm := defaultVM()
+	vm.Ctx.Lock.Lock()
+	defer func() {
+		vm.Shutdown()
+		vm.Ctx.Lock.Unlock()
+	}()
+
+	vdrSet, ok := vm.Validators.GetValidatorSet(DefaultSubnetID)
+	if !ok {
+		t.Fatalf("default subnet validator set should exist")
+	}
+	if vdrSet.Len() != len(keys) {
+		t.Fatalf("expected %d validators but got %d", len(keys), vdrSet.Len())
+	}
+
+	// Remove a validator from the current validator set without notifying the
+	// validator manager
+	currentValidators, err := vm.getCurrentValidators(vm.DB, DefaultSubnetID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	heap.Pop(currentValidators)
+	if err := vm.putCurrentValidators(vm.DB, currentValidators, DefaultSubnetID); err != nil {
+		t.Fatal(err)
+	}
+
+	timestamp, err := vm.getTimestamp(vm.DB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	epochChangeTime := vm.nextEpochChangeTime(vm.DB, timestamp)
+	if expected := nextEpochStartTime(timestamp); !epochChangeTime.Equal(expected) {
+		t.Fatalf("validator set should change at %s but changes at %s", expected, epochChangeTime)
+	}
+
+	// Before the epoch starts, no block should be built
+	vm.clock.Set(epochChangeTime.Add(-time.Second))
+	if _, err := vm.BuildBlock(context.Background()); err == nil {
+		t.Fatalf("shouldn't have built a block before the epoch started")
+	}
+
+	vm.clock.Set(epochChangeTime)
+	blk, err := vm.BuildBlock(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	block := blk.(*ProposalBlock)
+	tx, ok := block.Tx.(*advanceTimeTx)
+	if !ok {
+		t.Fatalf("should have proposed to advance the chain time")
+	}
+	if !tx.Timestamp().Equal(epochChangeTime) {
+		t.Fatalf("should have proposed advancing the chain time to %s but proposed %s", epochChangeTime, tx.Timestamp())
+	}
+
+	if err := block.Verify(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	block.Accept(context.Background())
+	commit, ok := block.Options()[0].(*Commit)
+	if !ok {
+		t.Fatal(errShouldPrefCommit)
+	}
+	if err := commit.Verify(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if vdrSet.Len() != len(keys) {
+		t.Fatalf("validator set shouldn't change before the epoch is committed")
+	}
+	commit.Accept(context.Background())
+
+	if vdrSet.Len() != len(keys)-1 {
+		t.Fatalf("expected %d validators but got %d", len(keys)-1, vdrSet.Len())
+	}
+	if changeTime := vm.nextEpochChangeTime(vm.DB, epochChangeTime); !changeTime.Equal(maxTime) {
+		t.Fatalf("validator set shouldn't change again but changes at %s", changeTime)
+	}
+}
+
+// Ensure subnets that existed before epochs were introduced are given an epoch
+// validator set
+func TestInitEpochValidatorsBackfillsSubnets(t *testing.T) {
+	vm := defaultVM()
+	vm.Ctx.Lock.Lock()
+	defer func() {
+		vm.Shutdown()
+		vm.Ctx.Lock.Unlock()
+	}()
+
+	tx, err := vm.newAddNonDefaultSubnetValidatorTx(
+		defaultNonce+1,
+		defaultWeight,
+		uint64(defaultValidateStartTime.Unix()),
+		uint64(defaultValidateEndTime.Unix()),
+		keys[0].PublicKey().Address(),
+		testSubnet1.ID,
+		testNetworkID,
+		[]*crypto.PrivateKeySECP256K1R{testSubnet1ControlKeys[0], testSubnet1ControlKeys[1]},
+		keys[0],
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	currentValidators := &EventHeap{SortByStartTime: false}
+	heap.Push(currentValidators, tx)
+	if err := vm.putCurrentValidators(vm.DB, currentValidators, testSubnet1.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	key := testSubnet1.ID.Prefix(epochValidatorsPrefix)
+	if has, err := vm.State.Has(vm.DB, validatorsTypeID, key); err != nil {
+		t.Fatal(err)
+	} else if has {
+		t.Fatalf("subnet shouldn't have an epoch validator set yet")
+	}
+
+	if err := vm.initEpochValidators(); err != nil {
+		t.Fatal(err)
+	}
+
+	epochValidators, err := vm.getEpochValidators(vm.DB, testSubnet1.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if epochValidators.Len() != 1 {
+		t.Fatalf("expected %d epoch validators but got %d", 1, epochValidators.Len())
+	}
+	if !epochValidators.Peek().ID().Equals(tx.ID()) {
+		t.Fatalf("epoch validator should be the subnet's current validator")
+	}
+}
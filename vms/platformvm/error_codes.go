@@ -0,0 +1,57 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"github.com/ava-labs/gecko/vms/components/verify"
+)
+
+// Codes of the errors returned when a transaction fails verification. These
+// codes are reported to API clients, so they must never be changed or reused.
+const (
+	// Malformed transactions
+	CodeNilTx                   verify.ErrorCode = 2000
+	CodeWrongNetworkID          verify.ErrorCode = 2001
+	CodeInvalidID               verify.ErrorCode = 2002
+	CodeWeightTooSmall          verify.ErrorCode = 2003
+	CodeStakeTooShort           verify.ErrorCode = 2004
+	CodeStakeTooLong            verify.ErrorCode = 2005
+	CodeTooManyShares           verify.ErrorCode = 2006
+	CodeSigsNotSorted           verify.ErrorCode = 2007
+	CodeWrongNumberOfSignatures verify.ErrorCode = 2008
+	CodeInvalidVMID             verify.ErrorCode = 2009
+	CodeFxIDsNotSortedAndUnique verify.ErrorCode = 2010
+	CodeThresholdExceedsKeysLen verify.ErrorCode = 2011
+	CodeThresholdTooHigh        verify.ErrorCode = 2012
+	CodeTimeTooAdvanced         verify.ErrorCode = 2013
+	CodeUnknownParameter        verify.ErrorCode = 2014
+	CodeParameterTooSmall       verify.ErrorCode = 2015
+	CodeNoExportOutputs         verify.ErrorCode = 2016
+	CodeNoImportInputs          verify.ErrorCode = 2017
+	CodeWrongAssetID            verify.ErrorCode = 2018
+	CodeUnsupportedUTXO         verify.ErrorCode = 2019
+	CodeOutputsNotSorted        verify.ErrorCode = 2020
+	CodeInputsNotSortedUnique   verify.ErrorCode = 2021
+	CodeUnknownPenalty          verify.ErrorCode = 2022
+	CodeNilEvidence             verify.ErrorCode = 2023
+	CodeParameterTooLarge       verify.ErrorCode = 2026
+	CodeInvalidEngine           verify.ErrorCode = 2027
+
+	// Transactions that conflict with the current state
+	CodeDSValidatorSubset   verify.ErrorCode = 2100
+	CodeShouldBeDSValidator verify.ErrorCode = 2101
+	CodeOutOfSpends         verify.ErrorCode = 2102
+	CodeWrongNonce          verify.ErrorCode = 2103
+	CodeSpendOverflow       verify.ErrorCode = 2104
+	CodeInsufficientFunds   verify.ErrorCode = 2105
+	CodeFundsLocked         verify.ErrorCode = 2106
+	CodeBalanceOverflow     verify.ErrorCode = 2107
+	CodeActivationTooEarly  verify.ErrorCode = 2108
+	CodeMissingUTXO         verify.ErrorCode = 2109
+	CodeUTXOAlreadyImported verify.ErrorCode = 2110
+	CodeUnauthorizedImport  verify.ErrorCode = 2111
+	CodeAlreadySlashed      verify.ErrorCode = 2112
+	CodeNotSubnetValidator  verify.ErrorCode = 2113
+	CodeDelegationFeeBounds verify.ErrorCode = 2114
+)
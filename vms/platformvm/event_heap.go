@@ -16,6 +16,9 @@ import (
 type TimedTx interface {
 	ProposalTx
 
+	// SyntacticVerify returns nil iff this transaction is well formed
+	SyntacticVerify() error
+
 	Vdr() validators.Validator
 
 	ID() ids.ID
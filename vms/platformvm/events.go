@@ -0,0 +1,126 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"errors"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/vms/components/events"
+)
+
+var (
+	errNoProposal = errors.New("the parent of a commit or abort block must be a proposal block")
+)
+
+// eventParser describes the txs this VM decides to the events server. The
+// container of each decision is a block.
+//
+// The txs of a standard block are accepted with it. The tx of a proposal block
+// is accepted when the commit block that follows it is accepted, and rejected
+// when the abort block that follows it is accepted. The txs of rejected blocks
+// may still be issued in a later block, so they aren't reported as rejected.
+type eventParser struct{ vm *VM }
+
+// ParseAccepted implements the events.Parser interface
+func (p *eventParser) ParseAccepted(blkID ids.ID, _ []byte) ([]*events.Tx, []*events.Tx, error) {
+	blk, err := p.vm.getBlock(blkID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	switch blk := blk.(type) {
+	case *StandardBlock:
+		accepted := []*events.Tx(nil)
+		for _, tx := range blk.Txs {
+			event, err := p.tx(tx)
+			if err != nil {
+				return nil, nil, err
+			}
+			if event != nil {
+				accepted = append(accepted, event)
+			}
+		}
+		return accepted, nil, nil
+	case *Commit:
+		event, err := p.proposal(blk.parentBlock())
+		if err != nil || event == nil {
+			return nil, nil, err
+		}
+		return []*events.Tx{event}, nil, nil
+	case *Abort:
+		event, err := p.proposal(blk.parentBlock())
+		if err != nil || event == nil {
+			return nil, nil, err
+		}
+		return nil, []*events.Tx{event}, nil
+	default:
+		return nil, nil, nil
+	}
+}
+
+// ParseRejected implements the events.Parser interface
+func (p *eventParser) ParseRejected(ids.ID, []byte) ([]*events.Tx, error) { return nil, nil }
+
+// proposal describes the tx of the proposal block [blk]
+func (p *eventParser) proposal(blk Block) (*events.Tx, error) {
+	proposal, ok := blk.(*ProposalBlock)
+	if !ok {
+		return nil, errNoProposal
+	}
+	return p.tx(proposal.Tx)
+}
+
+// tx describes [tx] to the subscribers of the accounts it involves. Returns nil
+// if [tx] isn't issued by a user, like the txs that advance the time and
+// reward validators.
+func (p *eventParser) tx(tx interface{}) (*events.Tx, error) {
+	// The signers of txs read from the database are unknown until they're
+	// verified again. Txs are only verified once, so this is free for txs
+	// that are still in memory.
+	if tx, ok := tx.(interface{ SyntacticVerify() error }); ok {
+		if err := tx.SyntacticVerify(); err != nil {
+			return nil, err
+		}
+	}
+
+	var (
+		txID  ids.ID
+		addrs []ids.ShortID
+	)
+	switch tx := tx.(type) {
+	case *CreateChainTx:
+		txID, addrs = tx.ID(), []ids.ShortID{tx.Key().Address()}
+	case *CreateSubnetTx:
+		txID, addrs = tx.ID, append([]ids.ShortID{tx.key.Address()}, tx.ControlKeys...)
+	case *ImportTx:
+		txID, addrs = tx.ID(), []ids.ShortID{tx.Key().Address()}
+	case *ExportTx:
+		txID, addrs = tx.ID(), []ids.ShortID{tx.Key().Address()}
+	case *addDefaultSubnetValidatorTx:
+		txID, addrs = tx.ID(), []ids.ShortID{tx.senderID, tx.Destination}
+	case *addDefaultSubnetDelegatorTx:
+		txID, addrs = tx.ID(), []ids.ShortID{tx.senderID, tx.Destination}
+	case *addNonDefaultSubnetValidatorTx:
+		txID, addrs = tx.ID(), append([]ids.ShortID{tx.senderID}, tx.controlIDs...)
+	case *governanceProposalTx:
+		txID, addrs = tx.ID(), []ids.ShortID{tx.senderID}
+	case *slashTx:
+		txID, addrs = tx.ID(), []ids.ShortID{tx.senderID}
+	default:
+		return nil, nil
+	}
+
+	event := &events.Tx{ID: txID}
+	seen := ids.ShortSet{}
+	for _, addr := range addrs {
+		if seen.Contains(addr) {
+			continue
+		}
+		seen.Add(addr)
+		event.Addresses = append(event.Addresses, addr.String())
+		event.FilterKeys = append(event.FilterKeys, addr.Bytes())
+	}
+	return event, nil
+}
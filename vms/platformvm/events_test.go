@@ -0,0 +1,123 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/gecko/chains"
+	"github.com/ava-labs/gecko/vms/timestampvm"
+)
+
+func TestEventParserStandardBlock(t *testing.T) {
+	vm := defaultVM()
+	parser := &eventParser{vm: vm}
+
+	tx, err := vm.newCreateChainTx(
+		defaultNonce+1,
+		nil,
+		timestampvm.ID,
+		nil,
+		chains.DefaultEngine,
+		"name",
+		testNetworkID,
+		keys[0],
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	vm.Ctx.Lock.Lock()
+	vm.unissuedDecisionTxs = append(vm.unissuedDecisionTxs, tx)
+	blk, err := vm.BuildBlock(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	vm.Ctx.Lock.Unlock()
+
+	if err := blk.Verify(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	blk.Accept(context.Background())
+
+	accepted, rejected, err := parser.ParseAccepted(blk.ID(), blk.Bytes())
+	switch {
+	case err != nil:
+		t.Fatal(err)
+	case len(accepted) != 1 || len(rejected) != 0:
+		t.Fatalf("Expected 1 accepted tx but got %d accepted and %d rejected", len(accepted), len(rejected))
+	case !accepted[0].ID.Equals(tx.ID()):
+		t.Fatalf("Expected tx %s but got %s", tx.ID(), accepted[0].ID)
+	case len(accepted[0].Addresses) != 1 || accepted[0].Addresses[0] != keys[0].PublicKey().Address().String():
+		t.Fatalf("Expected the tx to involve only its signer but got %v", accepted[0].Addresses)
+	}
+}
+
+func TestEventParserAbortBlock(t *testing.T) {
+	vm := defaultVM()
+	parser := &eventParser{vm: vm}
+	startTime := defaultGenesisTime.Add(Delta).Add(1 * time.Second)
+	endTime := startTime.Add(MinimumStakingDuration)
+	key, _ := vm.factory.NewPrivateKey()
+	ID := key.PublicKey().Address()
+
+	tx, err := vm.newAddDefaultSubnetValidatorTx(
+		defaultNonce+1,
+		defaultStakeAmount,
+		uint64(startTime.Unix()),
+		uint64(endTime.Unix()),
+		ID,
+		ID,
+		NumberOfShares,
+		testNetworkID,
+		defaultKey,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	vm.unissuedEvents.Add(tx)
+	vm.Ctx.Lock.Lock()
+	blk, err := vm.BuildBlock(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	vm.Ctx.Lock.Unlock()
+
+	block := blk.(*ProposalBlock)
+	abort, ok := block.Options()[1].(*Abort)
+	if !ok {
+		t.Fatal(errShouldPrefAbort)
+	}
+	if err := block.Verify(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	block.Accept(context.Background())
+
+	// Accepting the proposal block doesn't decide its tx
+	if accepted, rejected, err := parser.ParseAccepted(block.ID(), block.Bytes()); err != nil {
+		t.Fatal(err)
+	} else if len(accepted) != 0 || len(rejected) != 0 {
+		t.Fatalf("The proposal block shouldn't decide any txs")
+	}
+
+	if err := abort.Verify(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	abort.Accept(context.Background())
+
+	accepted, rejected, err := parser.ParseAccepted(abort.ID(), abort.Bytes())
+	switch {
+	case err != nil:
+		t.Fatal(err)
+	case len(accepted) != 0 || len(rejected) != 1:
+		t.Fatalf("Expected 1 rejected tx but got %d accepted and %d rejected", len(accepted), len(rejected))
+	case !rejected[0].ID.Equals(tx.ID()):
+		t.Fatalf("Expected tx %s but got %s", tx.ID(), rejected[0].ID)
+	case len(rejected[0].Addresses) != 2:
+		t.Fatalf("Expected the tx to involve its sender and destination but got %v", rejected[0].Addresses)
+	}
+}
@@ -0,0 +1,27 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/vms/components/verify"
+)
+
+var (
+	errNilEvidence = verify.NewError(CodeNilEvidence, "evidence is nil")
+)
+
+// Evidence is a proof that a default subnet validator misbehaved.
+//
+// To add a new kind of misbehavior, implement Evidence and register the type
+// with Codec. Evidence is submitted to the chain in a slashTx.
+//
+// No kind of evidence is registered yet. Evidence must be built from messages
+// the misbehaving node signed while participating in consensus, and nodes
+// don't sign their consensus messages yet.
+type Evidence interface {
+	// Verify returns the ID of the node that misbehaved, or an error if the
+	// evidence doesn't prove any node misbehaved
+	Verify(vm *VM) (ids.ShortID, error)
+}
@@ -0,0 +1,202 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"github.com/ava-labs/gecko/database"
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/crypto"
+	"github.com/ava-labs/gecko/utils/hashing"
+	"github.com/ava-labs/gecko/utils/math"
+	"github.com/ava-labs/gecko/vms/avm"
+	"github.com/ava-labs/gecko/vms/components/verify"
+	"github.com/ava-labs/gecko/vms/secp256k1fx"
+)
+
+var (
+	errNoExportOutputs  = verify.NewError(CodeNoExportOutputs, "no export outputs")
+	errWrongAssetID     = verify.NewError(CodeWrongAssetID, "only AVA may be moved between chains")
+	errUnsupportedUTXO  = verify.NewError(CodeUnsupportedUTXO, "only secp256k1fx transfers may be moved between chains")
+	errOutputsNotSorted = verify.NewError(CodeOutputsNotSorted, "outputs not sorted")
+	errSpendOverflow    = verify.NewError(CodeSpendOverflow, "amount overflowed uint64")
+)
+
+// UnsignedExportTx is an unsigned ExportTx
+type UnsignedExportTx struct {
+	// ID of the network this transaction exists on
+	NetworkID uint32 `serialize:"true"`
+
+	// Next unused nonce of the account paying for the exported $AVA and the
+	// transaction fee
+	Nonce uint64 `serialize:"true"`
+
+	// The UTXOs this transaction sends to the X-Chain
+	Outs []*avm.TransferableOutput `serialize:"true"`
+}
+
+// ExportTx sends $AVA from an account to the X-Chain
+type ExportTx struct {
+	UnsignedExportTx `serialize:"true"`
+
+	Sig [crypto.SECP256K1RSigLen]byte `serialize:"true"`
+
+	vm    *VM
+	id    ids.ID
+	key   crypto.PublicKey // public key of transaction signer
+	bytes []byte
+}
+
+func (tx *ExportTx) initialize(vm *VM) error {
+	tx.vm = vm
+	txBytes, err := Codec.Marshal(tx) // byte repr. of the signed tx
+	tx.bytes = txBytes
+	tx.id = ids.NewID(hashing.ComputeHash256Array(txBytes))
+	return err
+}
+
+// ID of this transaction
+func (tx *ExportTx) ID() ids.ID { return tx.id }
+
+// Key returns the public key of the signer of this transaction
+// Precondition: tx.Verify() has been called and returned nil
+func (tx *ExportTx) Key() crypto.PublicKey { return tx.key }
+
+// Bytes returns the byte representation of an ExportTx
+func (tx *ExportTx) Bytes() []byte { return tx.bytes }
+
+// UTXOs returns the UTXOs this transaction sends to the X-Chain
+func (tx *ExportTx) UTXOs() []*avm.UTXO {
+	utxos := make([]*avm.UTXO, len(tx.Outs))
+	for i, out := range tx.Outs {
+		utxos[i] = &avm.UTXO{
+			UTXOID: avm.UTXOID{
+				TxID:        tx.id,
+				OutputIndex: uint32(i),
+			},
+			Asset: avm.Asset{ID: out.AssetID()},
+			Out:   out.Out,
+		}
+	}
+	return utxos
+}
+
+// SyntacticVerify this transaction is well-formed
+// Also populates [tx.Key] with the public key that signed this transaction
+func (tx *ExportTx) SyntacticVerify() error {
+	switch {
+	case tx == nil:
+		return errNilTx
+	case tx.key != nil:
+		return nil // Only verify the transaction once
+	case tx.NetworkID != tx.vm.Ctx.NetworkID: // verify the transaction is on this network
+		return errWrongNetworkID
+	case tx.id.IsZero():
+		return errInvalidID
+	case len(tx.Outs) == 0:
+		return errNoExportOutputs
+	}
+
+	for _, out := range tx.Outs {
+		if err := out.Verify(); err != nil {
+			return err
+		}
+		if !out.AssetID().Equals(tx.vm.AVA) {
+			return errWrongAssetID
+		}
+		if _, ok := out.Out.(*secp256k1fx.TransferOutput); !ok {
+			return errUnsupportedUTXO
+		}
+	}
+	if !avm.IsSortedTransferableOutputs(tx.Outs, Codec) {
+		return errOutputsNotSorted
+	}
+
+	unsignedIntf := interface{}(&tx.UnsignedExportTx)
+	unsignedBytes, err := Codec.Marshal(&unsignedIntf) // byte repr of unsigned tx
+	if err != nil {
+		return err
+	}
+
+	key, err := tx.vm.factory.RecoverPublicKey(unsignedBytes, tx.Sig[:])
+	if err != nil {
+		return err
+	}
+	tx.key = key
+
+	return nil
+}
+
+// SemanticVerify this transaction is valid.
+func (tx *ExportTx) SemanticVerify(db database.Database) (func(), error) {
+	if err := tx.SyntacticVerify(); err != nil {
+		return nil, err
+	}
+
+	amount := uint64(0)
+	for _, out := range tx.Outs {
+		newAmount, err := math.Add64(amount, out.Output().Amount())
+		if err != nil {
+			return nil, errSpendOverflow
+		}
+		amount = newAmount
+	}
+
+	// Deduct the exported $AVA and the tx fee from the payer's account
+	params, err := tx.vm.getGovernanceParameters(db)
+	if err != nil {
+		return nil, err
+	}
+	account, err := tx.vm.getAccount(db, tx.Key().Address())
+	if err != nil {
+		return nil, err
+	}
+	account, err = account.RemoveWithFee(amount, params.TxFee, tx.Nonce)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.vm.putAccount(db, account); err != nil {
+		return nil, err
+	}
+
+	// If this tx is accepted, send the UTXOs to the X-Chain
+	onAccept := func() {
+		smDB := tx.vm.Ctx.SharedMemory.GetDatabase(tx.vm.AVM)
+		defer tx.vm.Ctx.SharedMemory.ReleaseDatabase(tx.vm.AVM)
+
+		state := avm.NewAtomicUTXOs(smDB)
+		for _, utxo := range tx.UTXOs() {
+			if err := state.Put(tx.vm.AVM, utxo); err != nil {
+				tx.vm.Ctx.Log.Error("failed to export utxo %s: %s", utxo.InputID(), err)
+			}
+		}
+	}
+
+	return onAccept, nil
+}
+
+func (vm *VM) newExportTx(nonce uint64, networkID uint32, outs []*avm.TransferableOutput, key *crypto.PrivateKeySECP256K1R) (*ExportTx, error) {
+	avm.SortTransferableOutputs(outs, Codec)
+
+	tx := &ExportTx{
+		UnsignedExportTx: UnsignedExportTx{
+			NetworkID: networkID,
+			Nonce:     nonce,
+			Outs:      outs,
+		},
+	}
+
+	unsignedIntf := interface{}(&tx.UnsignedExportTx)
+	unsignedBytes, err := Codec.Marshal(&unsignedIntf) // Byte repr. of unsigned transaction
+	if err != nil {
+		return nil, err
+	}
+
+	sig, err := key.Sign(unsignedBytes)
+	if err != nil {
+		return nil, err
+	}
+	copy(tx.Sig[:], sig)
+
+	return tx, tx.initialize(vm)
+}
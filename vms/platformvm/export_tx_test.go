@@ -0,0 +1,181 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"testing"
+
+	"github.com/ava-labs/gecko/chains/atomic"
+	"github.com/ava-labs/gecko/database/memdb"
+	"github.com/ava-labs/gecko/database/versiondb"
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/logging"
+	"github.com/ava-labs/gecko/vms/avm"
+	"github.com/ava-labs/gecko/vms/secp256k1fx"
+)
+
+var (
+	// ID of the X-Chain in tests
+	testAVMID = ids.Empty.Prefix(100)
+
+	// ID of the AVA asset in tests
+	testAVAID = ids.Empty.Prefix(101)
+)
+
+// sharedMemoryVM returns defaultVM() with memory it shares with the X-Chain
+func sharedMemoryVM() (*VM, *atomic.Memory) {
+	vm := defaultVM()
+	vm.AVM = testAVMID
+	vm.AVA = testAVAID
+
+	sm := &atomic.Memory{}
+	sm.Initialize(logging.NoLog{}, memdb.New())
+	vm.Ctx.SharedMemory = sm.NewBlockchainMemory(vm.Ctx.ChainID)
+	return vm, sm
+}
+
+// avaOutput returns a transferable output that sends [amount] $AVA to [addr]
+func avaOutput(assetID ids.ID, amount uint64, addr ids.ShortID) *avm.TransferableOutput {
+	return &avm.TransferableOutput{
+		Asset: avm.Asset{ID: assetID},
+		Out: &secp256k1fx.TransferOutput{
+			Amt: amount,
+			OutputOwners: secp256k1fx.OutputOwners{
+				Threshold: 1,
+				Addrs:     []ids.ShortID{addr},
+			},
+		},
+	}
+}
+
+func TestExportTxSyntacticVerify(t *testing.T) {
+	vm, _ := sharedMemoryVM()
+	to := keys[1].PublicKey().Address()
+
+	// Case 1: tx is nil
+	var tx *ExportTx
+	if err := tx.SyntacticVerify(); err == nil {
+		t.Fatal("should have failed because tx is nil")
+	}
+
+	// Case 2: network ID is wrong
+	tx, err := vm.newExportTx(
+		defaultNonce+1,
+		testNetworkID+1,
+		[]*avm.TransferableOutput{avaOutput(testAVAID, 1, to)},
+		defaultKey,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.SyntacticVerify(); err == nil {
+		t.Fatal("should have errored because network ID is wrong")
+	}
+
+	// Case 3: no outputs
+	tx, err = vm.newExportTx(
+		defaultNonce+1,
+		testNetworkID,
+		nil,
+		defaultKey,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.SyntacticVerify(); err == nil {
+		t.Fatal("should have errored because there are no outputs")
+	}
+
+	// Case 4: the asset isn't AVA
+	tx, err = vm.newExportTx(
+		defaultNonce+1,
+		testNetworkID,
+		[]*avm.TransferableOutput{avaOutput(ids.Empty.Prefix(102), 1, to)},
+		defaultKey,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.SyntacticVerify(); err == nil {
+		t.Fatal("should have errored because only AVA may be exported")
+	}
+
+	// Case 5: valid
+	tx, err = vm.newExportTx(
+		defaultNonce+1,
+		testNetworkID,
+		[]*avm.TransferableOutput{avaOutput(testAVAID, 1, to)},
+		defaultKey,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.SyntacticVerify(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestExportTxSemanticVerify(t *testing.T) {
+	vm, sm := sharedMemoryVM()
+	to := keys[1].PublicKey().Address()
+	amount := uint64(1000)
+
+	tx, err := vm.newExportTx(
+		defaultNonce+1,
+		testNetworkID,
+		[]*avm.TransferableOutput{avaOutput(testAVAID, amount, to)},
+		defaultKey,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	db := versiondb.New(vm.DB)
+	onAccept, err := tx.SemanticVerify(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Verify the exported $AVA and the tx fee were deducted
+	account, err := vm.getAccount(db, defaultKey.PublicKey().Address())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if account.Balance != defaultBalance-amount-txFee {
+		t.Fatalf("balance should be %d but is %d", defaultBalance-amount-txFee, account.Balance)
+	}
+
+	onAccept()
+
+	// Verify the UTXO was sent to the X-Chain
+	state := avm.NewAtomicUTXOs(sm.GetDatabase(vm.Ctx.ChainID, testAVMID))
+	defer sm.ReleaseDatabase(vm.Ctx.ChainID, testAVMID)
+
+	utxo, err := state.Get(testAVMID, tx.UTXOs()[0].InputID())
+	if err != nil {
+		t.Fatalf("exported UTXO should have been sent to the X-Chain: %s", err)
+	}
+	if utxo.Out.(*secp256k1fx.TransferOutput).Amt != amount {
+		t.Fatalf("exported UTXO should have amount %d", amount)
+	}
+}
+
+func TestExportTxSemanticVerifyInsufficientFunds(t *testing.T) {
+	vm, _ := sharedMemoryVM()
+	to := keys[1].PublicKey().Address()
+
+	tx, err := vm.newExportTx(
+		defaultNonce+1,
+		testNetworkID,
+		[]*avm.TransferableOutput{avaOutput(testAVAID, defaultBalance+1, to)},
+		defaultKey,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := tx.SemanticVerify(versiondb.New(vm.DB)); err == nil {
+		t.Fatal("should have errored because the account doesn't have enough $AVA")
+	}
+}
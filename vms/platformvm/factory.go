@@ -16,14 +16,20 @@ var (
 
 // Factory can create new instances of the Platform Chain
 type Factory struct {
-	ChainManager chains.Manager
-	Validators   validators.Manager
+	ChainManager    chains.Manager
+	Validators      validators.Manager
+	GovernanceVotes GovernanceVotes
+	AVM             ids.ID // The ID of the X-Chain
+	AVA             ids.ID // The ID of the AVA asset
 }
 
 // New returns a new instance of the Platform Chain
 func (f *Factory) New() interface{} {
 	return &VM{
-		ChainManager: f.ChainManager,
-		Validators:   f.Validators,
+		ChainManager:    f.ChainManager,
+		Validators:      f.Validators,
+		GovernanceVotes: f.GovernanceVotes,
+		AVM:             f.AVM,
+		AVA:             f.AVA,
 	}
 }
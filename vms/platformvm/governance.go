@@ -0,0 +1,211 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/ava-labs/gecko/vms/components/verify"
+)
+
+var (
+	errUnknownParameter  = verify.NewError(CodeUnknownParameter, "unknown governance parameter")
+	errParameterTooSmall = verify.NewError(CodeParameterTooSmall, fmt.Sprintf("minimum stake can't be less than %d", MinimumStakeAmount))
+	errUnknownPenalty    = verify.NewError(CodeUnknownPenalty, "unknown slashing penalty")
+	errParameterTooLarge = verify.NewError(CodeParameterTooLarge, fmt.Sprintf("delegation fee can't be more than %d shares", NumberOfShares))
+)
+
+// GovernanceParameter identifies a chain parameter that can be changed by a
+// governance proposal
+type GovernanceParameter uint32
+
+// The chain parameters that can be changed by governance proposals
+const (
+	// TxFeeParameter is the fee, in nAVA, charged for each transaction
+	TxFeeParameter GovernanceParameter = iota
+
+	// MinimumStakeParameter is the minimum amount of nAVA one must bond to be
+	// a staker. It can't be set below MinimumStakeAmount.
+	MinimumStakeParameter
+
+	// SlashingPenaltyParameter is the SlashingPenalty of validators that are
+	// proven to have misbehaved
+	SlashingPenaltyParameter
+
+	// MinDelegationFeeParameter is the least delegation fee, in shares out of
+	// NumberOfShares, a default subnet validator may charge its delegators
+	MinDelegationFeeParameter
+
+	// MaxDelegationFeeParameter is the greatest delegation fee, in shares out
+	// of NumberOfShares, a default subnet validator may charge its delegators
+	MaxDelegationFeeParameter
+)
+
+var governanceParameterNames = map[GovernanceParameter]string{
+	TxFeeParameter:            "txFee",
+	MinimumStakeParameter:     "minimumStake",
+	SlashingPenaltyParameter:  "slashingPenalty",
+	MinDelegationFeeParameter: "minDelegationFee",
+	MaxDelegationFeeParameter: "maxDelegationFee",
+}
+
+func (p GovernanceParameter) String() string {
+	if name, ok := governanceParameterNames[p]; ok {
+		return name
+	}
+	return fmt.Sprintf("unknown(%d)", uint32(p))
+}
+
+// Verify returns nil iff [value] is a valid value for [p]
+func (p GovernanceParameter) Verify(value uint64) error {
+	switch p {
+	case TxFeeParameter:
+		return nil
+	case MinimumStakeParameter:
+		if value < MinimumStakeAmount {
+			return errParameterTooSmall
+		}
+		return nil
+	case SlashingPenaltyParameter:
+		return SlashingPenalty(value).Verify()
+	case MinDelegationFeeParameter, MaxDelegationFeeParameter:
+		if value > NumberOfShares {
+			return errParameterTooLarge
+		}
+		return nil
+	default:
+		return errUnknownParameter
+	}
+}
+
+// ParseGovernanceParameter returns the parameter whose name is [name]
+func ParseGovernanceParameter(name string) (GovernanceParameter, error) {
+	for param, paramName := range governanceParameterNames {
+		if paramName == name {
+			return param, nil
+		}
+	}
+	return 0, fmt.Errorf("%w: %q", errUnknownParameter, name)
+}
+
+// GovernanceParameters are the values of the chain parameters that can be
+// changed by governance proposals
+type GovernanceParameters struct {
+	TxFee            uint64 `serialize:"true"`
+	MinimumStake     uint64 `serialize:"true"`
+	SlashingPenalty  uint64 `serialize:"true"`
+	MinDelegationFee uint64 `serialize:"true"`
+	MaxDelegationFee uint64 `serialize:"true"`
+}
+
+// DefaultGovernanceParameters returns the parameters of a chain that no proposal
+// has changed yet
+func DefaultGovernanceParameters() *GovernanceParameters {
+	return &GovernanceParameters{
+		TxFee:            txFee,
+		MinimumStake:     MinimumStakeAmount,
+		SlashingPenalty:  uint64(ForfeitRewardPenalty),
+		MinDelegationFee: 0,
+		MaxDelegationFee: NumberOfShares,
+	}
+}
+
+// Get returns the value of [param]
+func (p *GovernanceParameters) Get(param GovernanceParameter) uint64 {
+	switch param {
+	case TxFeeParameter:
+		return p.TxFee
+	case MinimumStakeParameter:
+		return p.MinimumStake
+	case SlashingPenaltyParameter:
+		return p.SlashingPenalty
+	case MinDelegationFeeParameter:
+		return p.MinDelegationFee
+	case MaxDelegationFeeParameter:
+		return p.MaxDelegationFee
+	default:
+		return 0
+	}
+}
+
+// set [param] to [value]
+func (p *GovernanceParameters) set(param GovernanceParameter, value uint64) {
+	switch param {
+	case TxFeeParameter:
+		p.TxFee = value
+	case MinimumStakeParameter:
+		p.MinimumStake = value
+	case SlashingPenaltyParameter:
+		p.SlashingPenalty = value
+	case MinDelegationFeeParameter:
+		p.MinDelegationFee = value
+	case MaxDelegationFeeParameter:
+		p.MaxDelegationFee = value
+	}
+}
+
+// Bytes returns the byte representation of [p]
+func (p *GovernanceParameters) Bytes() []byte {
+	bytes, _ := Codec.Marshal(p)
+	return bytes
+}
+
+// SlashingPenalty is what a validator loses when it's proven to have misbehaved
+type SlashingPenalty uint64
+
+// The penalties that can be imposed on a misbehaving validator. The penalty is
+// fixed when the evidence of misbehavior is accepted, and applied when the
+// validator stops validating.
+const (
+	// ForfeitRewardPenalty returns the validator's stake but no reward
+	ForfeitRewardPenalty SlashingPenalty = iota
+
+	// BurnStakePenalty returns neither the validator's stake nor a reward
+	BurnStakePenalty
+)
+
+// Verify returns nil iff [p] is a known penalty
+func (p SlashingPenalty) Verify() error {
+	switch p {
+	case ForfeitRewardPenalty, BurnStakePenalty:
+		return nil
+	default:
+		return errUnknownPenalty
+	}
+}
+
+// GovernanceVotes are the values this node wants the chain parameters to be
+// changed to. A proposal is initially preferred to be committed iff it sets a
+// parameter to the value this node votes for. Proposals for parameters this
+// node hasn't voted on are initially preferred to be aborted.
+type GovernanceVotes map[GovernanceParameter]uint64
+
+// ParseGovernanceVotes parses votes of the form "txFee=1000,minimumStake=20000"
+func ParseGovernanceVotes(s string) (GovernanceVotes, error) {
+	votes := GovernanceVotes{}
+	for _, vote := range strings.Split(s, ",") {
+		if vote == "" {
+			continue
+		}
+		parts := strings.SplitN(vote, "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("governance vote %q should be of the form parameter=value", vote)
+		}
+		param, err := ParseGovernanceParameter(parts[0])
+		if err != nil {
+			return nil, err
+		}
+		value, err := strconv.ParseUint(parts[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't parse value of governance vote %q: %w", vote, err)
+		}
+		if err := param.Verify(value); err != nil {
+			return nil, err
+		}
+		votes[param] = value
+	}
+	return votes, nil
+}
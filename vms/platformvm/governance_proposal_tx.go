@@ -0,0 +1,252 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"sort"
+	"time"
+
+	"github.com/ava-labs/gecko/database"
+	"github.com/ava-labs/gecko/database/versiondb"
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/crypto"
+	"github.com/ava-labs/gecko/utils/hashing"
+	"github.com/ava-labs/gecko/vms/components/verify"
+)
+
+var (
+	errActivationTooEarly = verify.NewError(CodeActivationTooEarly, "proposal's activation time must be after the current chain timestamp")
+)
+
+// UnsignedGovernanceProposalTx is an unsigned governanceProposalTx
+type UnsignedGovernanceProposalTx struct {
+	// NetworkID is the ID of the network this tx was issued on
+	NetworkID uint32 `serialize:"true"`
+
+	// Next unused nonce of account paying the transaction fee for this transaction.
+	Nonce uint64 `serialize:"true"`
+
+	// The parameter this proposal changes, and the value it's changed to
+	Parameter GovernanceParameter `serialize:"true"`
+	Value     uint64              `serialize:"true"`
+
+	// Unix time at which the parameter changes. The change takes effect when
+	// the chain's timestamp is first advanced to or past this time.
+	ActivationTime uint64 `serialize:"true"`
+}
+
+// governanceProposalTx is a transaction that, if it is in a ProposalBlock that
+// is accepted and followed by a Commit block, schedules a change to a chain
+// parameter. Validators vote on the proposal by preferring the Commit or Abort
+// block; see InitiallyPrefersCommit. The transaction fee will be paid from the
+// account who signed the transaction.
+type governanceProposalTx struct {
+	UnsignedGovernanceProposalTx `serialize:"true"`
+
+	// Sig is the signature of the public key whose corresponding account pays
+	// the tx fee for this tx. ie the account with ID == [public key].Address()
+	// pays the tx fee
+	Sig [crypto.SECP256K1RSigLen]byte `serialize:"true"`
+
+	vm       *VM
+	id       ids.ID
+	senderID ids.ShortID
+
+	// Byte representation of the signed transaction
+	bytes []byte
+}
+
+// initialize [tx]
+func (tx *governanceProposalTx) initialize(vm *VM) error {
+	tx.vm = vm
+	bytes, err := Codec.Marshal(tx) // byte representation of the signed transaction
+	tx.bytes = bytes
+	tx.id = ids.NewID(hashing.ComputeHash256Array(bytes))
+	return err
+}
+
+func (tx *governanceProposalTx) ID() ids.ID { return tx.id }
+
+// ActivationTimestamp returns the time at which this proposal's change takes
+// effect
+func (tx *governanceProposalTx) ActivationTimestamp() time.Time {
+	return time.Unix(int64(tx.ActivationTime), 0)
+}
+
+// SyntacticVerify return nil iff [tx] is valid
+// If [tx] is valid, sets [tx.senderID]
+func (tx *governanceProposalTx) SyntacticVerify() error {
+	switch {
+	case tx == nil:
+		return errNilTx
+	case !tx.senderID.IsZero():
+		return nil // Only verify the transaction once
+	case tx.id.IsZero():
+		return errInvalidID
+	case tx.NetworkID != tx.vm.Ctx.NetworkID:
+		return errWrongNetworkID
+	}
+	if err := tx.Parameter.Verify(tx.Value); err != nil {
+		return err
+	}
+
+	unsignedIntf := interface{}(&tx.UnsignedGovernanceProposalTx)
+	// Byte representation of the unsigned transaction
+	unsignedBytes, err := Codec.Marshal(&unsignedIntf)
+	if err != nil {
+		return err
+	}
+
+	// get account to pay tx fee from
+	key, err := tx.vm.factory.RecoverPublicKey(unsignedBytes, tx.Sig[:])
+	if err != nil {
+		return err
+	}
+	tx.senderID = key.Address()
+
+	return nil
+}
+
+// SemanticVerify this transaction is valid.
+func (tx *governanceProposalTx) SemanticVerify(db database.Database) (*versiondb.Database, *versiondb.Database, func(), func(), error) {
+	if err := tx.SyntacticVerify(); err != nil {
+		return nil, nil, nil, nil, err
+	}
+
+	// Ensure the proposal activates after the current timestamp
+	currentTimestamp, err := tx.vm.getTimestamp(db)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	if !currentTimestamp.Before(tx.ActivationTimestamp()) {
+		return nil, nil, nil, nil, errActivationTooEarly
+	}
+
+	params, err := tx.vm.getGovernanceParameters(db)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+
+	// The account that is paying the transaction fee
+	account, err := tx.vm.getAccount(db, tx.senderID)
+	if err != nil {
+		return nil, nil, nil, nil, errDBAccount
+	}
+	newAccount, err := account.RemoveWithFee(0, params.TxFee, tx.Nonce)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+
+	proposals, err := tx.vm.getPendingProposals(db)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	proposals = append(proposals, tx)
+	// Proposals are applied in order of activation time. Proposals with the
+	// same activation time are applied in the order they were committed.
+	sort.SliceStable(proposals, func(i, j int) bool {
+		return proposals[i].ActivationTime < proposals[j].ActivationTime
+	})
+
+	// If this proposal is committed, schedule the parameter change and charge
+	// the proposer's account
+	onCommitDB := versiondb.New(db)
+	if err := tx.vm.putPendingProposals(onCommitDB, proposals); err != nil {
+		return nil, nil, nil, nil, err
+	}
+	if err := tx.vm.putAccount(onCommitDB, newAccount); err != nil {
+		return nil, nil, nil, nil, err
+	}
+
+	// If this proposal is aborted, the parameter isn't changed, but the
+	// proposer is still charged. Otherwise, a proposal that's expected to be
+	// aborted could be reissued with the same nonce at no cost.
+	onAbortDB := versiondb.New(db)
+	if err := tx.vm.putAccount(onAbortDB, newAccount); err != nil {
+		return nil, nil, nil, nil, err
+	}
+
+	return onCommitDB, onAbortDB, nil, nil, nil
+}
+
+// InitiallyPrefersCommit returns true if this node votes for the proposed
+// parameter value
+func (tx *governanceProposalTx) InitiallyPrefersCommit() bool {
+	vote, ok := tx.vm.GovernanceVotes[tx.Parameter]
+	return ok && vote == tx.Value
+}
+
+func (vm *VM) newGovernanceProposalTx(
+	nonce uint64,
+	parameter GovernanceParameter,
+	value,
+	activationTime uint64,
+	networkID uint32,
+	key *crypto.PrivateKeySECP256K1R,
+) (*governanceProposalTx, error) {
+	tx := &governanceProposalTx{
+		UnsignedGovernanceProposalTx: UnsignedGovernanceProposalTx{
+			NetworkID:      networkID,
+			Nonce:          nonce,
+			Parameter:      parameter,
+			Value:          value,
+			ActivationTime: activationTime,
+		},
+	}
+
+	unsignedIntf := interface{}(&tx.UnsignedGovernanceProposalTx)
+	unsignedBytes, err := Codec.Marshal(&unsignedIntf) // byte repr. of unsigned tx
+	if err != nil {
+		return nil, err
+	}
+
+	sig, err := key.Sign(unsignedBytes)
+	if err != nil {
+		return nil, err
+	}
+	copy(tx.Sig[:], sig)
+
+	return tx, tx.initialize(vm)
+}
+
+// activateProposals applies to [db] the pending proposals whose activation
+// time is at or before [timestamp], and removes them from the pending
+// proposals
+func (vm *VM) activateProposals(db database.Database, timestamp time.Time) error {
+	proposals, err := vm.getPendingProposals(db)
+	if err != nil {
+		return err
+	}
+	numActivated := 0
+	for _, proposal := range proposals {
+		if proposal.ActivationTimestamp().After(timestamp) {
+			break
+		}
+		numActivated++
+	}
+	if numActivated == 0 {
+		return nil
+	}
+
+	params, err := vm.getGovernanceParameters(db)
+	if err != nil {
+		return err
+	}
+	for _, proposal := range proposals[:numActivated] {
+		params.set(proposal.Parameter, proposal.Value)
+	}
+	if err := vm.putGovernanceParameters(db, params); err != nil {
+		return err
+	}
+	return vm.putPendingProposals(db, proposals[numActivated:])
+}
+
+// governanceProposalList is a list of *governanceProposalTx
+type governanceProposalList []*governanceProposalTx
+
+// Bytes returns the binary representation of [lst]
+func (lst governanceProposalList) Bytes() []byte {
+	bytes, _ := Codec.Marshal(lst)
+	return bytes
+}
@@ -0,0 +1,348 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/gecko/ids"
+)
+
+func TestGovernanceProposalTxSyntacticVerify(t *testing.T) {
+	vm := defaultVM()
+	activationTime := uint64(defaultGenesisTime.Add(time.Hour).Unix())
+
+	// Case 1: tx is nil
+	var tx *governanceProposalTx
+	if err := tx.SyntacticVerify(); err == nil {
+		t.Fatal("should have failed because tx is nil")
+	}
+
+	// Case 2: network ID is wrong
+	tx, err := vm.newGovernanceProposalTx(
+		defaultNonce+1,
+		TxFeeParameter,
+		1,
+		activationTime,
+		testNetworkID+1,
+		defaultKey,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.SyntacticVerify(); err == nil {
+		t.Fatal("should have failed because network ID is wrong")
+	}
+
+	// Case 3: tx ID is empty
+	tx, err = vm.newGovernanceProposalTx(
+		defaultNonce+1,
+		TxFeeParameter,
+		1,
+		activationTime,
+		testNetworkID,
+		defaultKey,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx.id = ids.ID{}
+	if err := tx.SyntacticVerify(); err == nil {
+		t.Fatal("should have failed because tx ID is empty")
+	}
+
+	// Case 4: parameter is unknown
+	tx, err = vm.newGovernanceProposalTx(
+		defaultNonce+1,
+		GovernanceParameter(100),
+		1,
+		activationTime,
+		testNetworkID,
+		defaultKey,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.SyntacticVerify(); !errors.Is(err, errUnknownParameter) {
+		t.Fatalf("expected %s but got %v", errUnknownParameter, err)
+	}
+
+	// Case 5: minimum stake is lowered below the floor
+	tx, err = vm.newGovernanceProposalTx(
+		defaultNonce+1,
+		MinimumStakeParameter,
+		MinimumStakeAmount-1,
+		activationTime,
+		testNetworkID,
+		defaultKey,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.SyntacticVerify(); !errors.Is(err, errParameterTooSmall) {
+		t.Fatalf("expected %s but got %v", errParameterTooSmall, err)
+	}
+
+	// Case 6: valid
+	tx, err = vm.newGovernanceProposalTx(
+		defaultNonce+1,
+		MinimumStakeParameter,
+		2*MinimumStakeAmount,
+		activationTime,
+		testNetworkID,
+		defaultKey,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.SyntacticVerify(); err != nil {
+		t.Fatal(err)
+	}
+	if !tx.senderID.Equals(defaultKey.PublicKey().Address()) {
+		t.Fatal("should have recovered the key that signed the tx")
+	}
+}
+
+// Ensure semantic verification fails when the proposal activates at or before
+// the current timestamp
+func TestGovernanceProposalTxActivationTooEarly(t *testing.T) {
+	vm := defaultVM()
+
+	tx, err := vm.newGovernanceProposalTx(
+		defaultNonce+1,
+		TxFeeParameter,
+		1,
+		uint64(defaultGenesisTime.Unix()),
+		testNetworkID,
+		defaultKey,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, _, _, err := tx.SemanticVerify(vm.DB); !errors.Is(err, errActivationTooEarly) {
+		t.Fatalf("expected %s but got %v", errActivationTooEarly, err)
+	}
+}
+
+// Commit a proposal this node votes for, then activate it by advancing time
+func TestGovernanceProposalCommit(t *testing.T) {
+	vm := defaultVM()
+	newMinimumStake := 2 * MinimumStakeAmount
+	vm.GovernanceVotes = GovernanceVotes{MinimumStakeParameter: newMinimumStake}
+	activationTime := defaultGenesisTime.Add(time.Second)
+
+	tx, err := vm.newGovernanceProposalTx(
+		defaultNonce+1,
+		MinimumStakeParameter,
+		newMinimumStake,
+		uint64(activationTime.Unix()),
+		testNetworkID,
+		defaultKey,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	vm.unissuedProposals = append(vm.unissuedProposals, tx)
+	vm.Ctx.Lock.Lock()
+	blk, err := vm.BuildBlock(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	vm.Ctx.Lock.Unlock()
+
+	// This node voted for the proposed value, so it should prefer to commit
+	block := blk.(*ProposalBlock)
+	options := block.Options()
+	commit, ok := options[0].(*Commit)
+	if !ok {
+		t.Fatal(errShouldPrefCommit)
+	}
+	if _, ok := options[1].(*Abort); !ok {
+		t.Fatal(errShouldPrefAbort)
+	}
+
+	if err := block.Verify(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	block.Accept(context.Background())
+	if err := commit.Verify(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	commit.Accept(context.Background())
+
+	// The proposal should be pending until the chain time reaches its
+	// activation time
+	proposals, err := vm.getPendingProposals(vm.DB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(proposals) != 1 || !proposals[0].ID().Equals(tx.ID()) {
+		t.Fatalf("expected the proposal to be pending but got %v", proposals)
+	}
+	params, err := vm.getGovernanceParameters(vm.DB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if params.MinimumStake != MinimumStakeAmount {
+		t.Fatalf("minimum stake changed to %d before activation", params.MinimumStake)
+	}
+
+	account, err := vm.getAccount(vm.DB, defaultKey.PublicKey().Address())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if account.Nonce != defaultNonce+1 {
+		t.Fatalf("expected the proposer's nonce to be %d but was %d", defaultNonce+1, account.Nonce)
+	}
+
+	// Advancing the chain time to the activation time activates the proposal
+	advanceTimeTx, err := vm.newAdvanceTimeTx(activationTime)
+	if err != nil {
+		t.Fatal(err)
+	}
+	onCommitDB, onAbortDB, _, _, err := advanceTimeTx.SemanticVerify(vm.DB)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	params, err = vm.getGovernanceParameters(onCommitDB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if params.MinimumStake != newMinimumStake {
+		t.Fatalf("expected minimum stake to be %d but was %d", newMinimumStake, params.MinimumStake)
+	}
+	proposals, err = vm.getPendingProposals(onCommitDB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(proposals) != 0 {
+		t.Fatalf("activated proposal should no longer be pending")
+	}
+
+	params, err = vm.getGovernanceParameters(onAbortDB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if params.MinimumStake != MinimumStakeAmount {
+		t.Fatal("minimum stake shouldn't change if time isn't advanced")
+	}
+}
+
+// A node that hasn't voted for the proposed value prefers to abort it
+func TestGovernanceProposalPrefersAbort(t *testing.T) {
+	vm := defaultVM()
+	vm.GovernanceVotes = GovernanceVotes{TxFeeParameter: 5}
+
+	tx, err := vm.newGovernanceProposalTx(
+		defaultNonce+1,
+		TxFeeParameter,
+		10,
+		uint64(defaultGenesisTime.Add(time.Hour).Unix()),
+		testNetworkID,
+		defaultKey,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	vm.unissuedProposals = append(vm.unissuedProposals, tx)
+	vm.Ctx.Lock.Lock()
+	blk, err := vm.BuildBlock(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	vm.Ctx.Lock.Unlock()
+
+	block := blk.(*ProposalBlock)
+	options := block.Options()
+	abort, ok := options[0].(*Abort)
+	if !ok {
+		t.Fatal(errShouldPrefAbort)
+	}
+
+	if err := block.Verify(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	block.Accept(context.Background())
+	if err := abort.Verify(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	abort.Accept(context.Background())
+
+	proposals, err := vm.getPendingProposals(vm.DB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(proposals) != 0 {
+		t.Fatal("aborted proposal shouldn't be pending")
+	}
+
+	// The proposer pays for the proposal even though it was aborted
+	account, err := vm.getAccount(vm.DB, defaultKey.PublicKey().Address())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if account.Nonce != defaultNonce+1 {
+		t.Fatalf("expected the proposer's nonce to be %d but was %d", defaultNonce+1, account.Nonce)
+	}
+	if expected := defaultBalance - txFee; account.Balance != expected {
+		t.Fatalf("expected the proposer's balance to be %d but was %d", expected, account.Balance)
+	}
+}
+
+// Ensure the governed minimum stake and tx fee are enforced
+func TestGovernanceParametersEnforced(t *testing.T) {
+	vm := defaultVM()
+	startTime := defaultGenesisTime.Add(Delta).Add(time.Second)
+	endTime := startTime.Add(MinimumStakingDuration)
+	key, _ := vm.factory.NewPrivateKey()
+	ID := key.PublicKey().Address()
+
+	tx, err := vm.newAddDefaultSubnetValidatorTx(
+		defaultNonce+1,
+		2*MinimumStakeAmount,
+		uint64(startTime.Unix()),
+		uint64(endTime.Unix()),
+		ID,
+		ID,
+		NumberOfShares,
+		testNetworkID,
+		defaultKey,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, _, _, err := tx.SemanticVerify(vm.DB); err != nil {
+		t.Fatal(err)
+	}
+
+	// Raise the minimum stake above the validator's stake
+	err = vm.putGovernanceParameters(vm.DB, &GovernanceParameters{
+		TxFee:        txFee,
+		MinimumStake: 3 * MinimumStakeAmount,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, _, _, err := tx.SemanticVerify(vm.DB); !errors.Is(err, errWeightTooSmall) {
+		t.Fatalf("expected %s but got %v", errWeightTooSmall, err)
+	}
+
+	// Raise the tx fee so the account can't pay both the stake and the fee
+	err = vm.putGovernanceParameters(vm.DB, &GovernanceParameters{
+		TxFee:        defaultBalance,
+		MinimumStake: MinimumStakeAmount,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, _, _, err := tx.SemanticVerify(vm.DB); err == nil {
+		t.Fatal("should have failed because the account can't pay the tx fee")
+	}
+}
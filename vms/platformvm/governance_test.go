@@ -0,0 +1,71 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseGovernanceVotes(t *testing.T) {
+	votes, err := ParseGovernanceVotes("txFee=1000,minimumStake=20000")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(votes) != 2 {
+		t.Fatalf("expected 2 votes but got %d", len(votes))
+	}
+	if vote := votes[TxFeeParameter]; vote != 1000 {
+		t.Fatalf("expected tx fee vote to be 1000 but was %d", vote)
+	}
+	if vote := votes[MinimumStakeParameter]; vote != 20000 {
+		t.Fatalf("expected minimum stake vote to be 20000 but was %d", vote)
+	}
+}
+
+func TestParseGovernanceVotesEmpty(t *testing.T) {
+	votes, err := ParseGovernanceVotes("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(votes) != 0 {
+		t.Fatalf("expected no votes but got %d", len(votes))
+	}
+}
+
+func TestParseGovernanceVotesInvalid(t *testing.T) {
+	if _, err := ParseGovernanceVotes("txFee"); err == nil {
+		t.Fatal("should have failed because the vote has no value")
+	}
+	if _, err := ParseGovernanceVotes("txFee=abc"); err == nil {
+		t.Fatal("should have failed because the value isn't a number")
+	}
+	if _, err := ParseGovernanceVotes("blockSize=10"); !errors.Is(err, errUnknownParameter) {
+		t.Fatalf("expected %s but got %v", errUnknownParameter, err)
+	}
+	if _, err := ParseGovernanceVotes("minimumStake=1"); !errors.Is(err, errParameterTooSmall) {
+		t.Fatalf("expected %s but got %v", errParameterTooSmall, err)
+	}
+	if _, err := ParseGovernanceVotes("slashingPenalty=2"); !errors.Is(err, errUnknownPenalty) {
+		t.Fatalf("expected %s but got %v", errUnknownPenalty, err)
+	}
+	if _, err := ParseGovernanceVotes("maxDelegationFee=1000001"); !errors.Is(err, errParameterTooLarge) {
+		t.Fatalf("expected %s but got %v", errParameterTooLarge, err)
+	}
+}
+
+func TestGovernanceParameterString(t *testing.T) {
+	for param, name := range governanceParameterNames {
+		parsed, err := ParseGovernanceParameter(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if parsed != param {
+			t.Fatalf("expected %s but got %s", param, parsed)
+		}
+		if param.String() != name {
+			t.Fatalf("expected %s but got %s", name, param.String())
+		}
+	}
+}
@@ -0,0 +1,241 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"github.com/ava-labs/gecko/database"
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow/choices"
+	"github.com/ava-labs/gecko/utils/crypto"
+	"github.com/ava-labs/gecko/utils/hashing"
+	"github.com/ava-labs/gecko/utils/math"
+	"github.com/ava-labs/gecko/vms/avm"
+	"github.com/ava-labs/gecko/vms/components/verify"
+	"github.com/ava-labs/gecko/vms/secp256k1fx"
+)
+
+var (
+	errNoImportInputs        = verify.NewError(CodeNoImportInputs, "no import inputs")
+	errInputsNotSortedUnique = verify.NewError(CodeInputsNotSortedUnique, "inputs not sorted and unique")
+	errMissingUTXO           = verify.NewError(CodeMissingUTXO, "missing utxo")
+	errUTXOAlreadyImported   = verify.NewError(CodeUTXOAlreadyImported, "utxo was already imported")
+	errUnauthorizedImport    = verify.NewError(CodeUnauthorizedImport, "the signer can't spend the imported utxo")
+)
+
+// UnsignedImportTx is an unsigned ImportTx
+type UnsignedImportTx struct {
+	// ID of the network this transaction exists on
+	NetworkID uint32 `serialize:"true"`
+
+	// Next unused nonce of the account receiving the imported $AVA, which pays
+	// the transaction fee
+	Nonce uint64 `serialize:"true"`
+
+	// The UTXOs the X-Chain sent to this chain that this transaction consumes
+	Ins []*avm.TransferableInput `serialize:"true"`
+}
+
+// ImportTx moves $AVA that the X-Chain sent to this chain into an account.
+// Each imported UTXO must be spendable by the signer alone, and the imported
+// $AVA goes to the signer's account.
+type ImportTx struct {
+	UnsignedImportTx `serialize:"true"`
+
+	Sig [crypto.SECP256K1RSigLen]byte `serialize:"true"`
+
+	vm    *VM
+	id    ids.ID
+	key   crypto.PublicKey // public key of transaction signer
+	bytes []byte
+}
+
+func (tx *ImportTx) initialize(vm *VM) error {
+	tx.vm = vm
+	txBytes, err := Codec.Marshal(tx) // byte repr. of the signed tx
+	tx.bytes = txBytes
+	tx.id = ids.NewID(hashing.ComputeHash256Array(txBytes))
+	return err
+}
+
+// ID of this transaction
+func (tx *ImportTx) ID() ids.ID { return tx.id }
+
+// Key returns the public key of the signer of this transaction
+// Precondition: tx.Verify() has been called and returned nil
+func (tx *ImportTx) Key() crypto.PublicKey { return tx.key }
+
+// Bytes returns the byte representation of an ImportTx
+func (tx *ImportTx) Bytes() []byte { return tx.bytes }
+
+// SyntacticVerify this transaction is well-formed
+// Also populates [tx.Key] with the public key that signed this transaction
+func (tx *ImportTx) SyntacticVerify() error {
+	switch {
+	case tx == nil:
+		return errNilTx
+	case tx.key != nil:
+		return nil // Only verify the transaction once
+	case tx.NetworkID != tx.vm.Ctx.NetworkID: // verify the transaction is on this network
+		return errWrongNetworkID
+	case tx.id.IsZero():
+		return errInvalidID
+	case len(tx.Ins) == 0:
+		return errNoImportInputs
+	}
+
+	for _, in := range tx.Ins {
+		if err := in.Verify(); err != nil {
+			return err
+		}
+		if !in.AssetID().Equals(tx.vm.AVA) {
+			return errWrongAssetID
+		}
+		if _, ok := in.In.(*secp256k1fx.TransferInput); !ok {
+			return errUnsupportedUTXO
+		}
+	}
+	if !avm.IsSortedAndUniqueTransferableInputs(tx.Ins) {
+		return errInputsNotSortedUnique
+	}
+
+	unsignedIntf := interface{}(&tx.UnsignedImportTx)
+	unsignedBytes, err := Codec.Marshal(&unsignedIntf) // byte repr of unsigned tx
+	if err != nil {
+		return err
+	}
+
+	key, err := tx.vm.factory.RecoverPublicKey(unsignedBytes, tx.Sig[:])
+	if err != nil {
+		return err
+	}
+	tx.key = key
+
+	return nil
+}
+
+// SemanticVerify this transaction is valid.
+func (tx *ImportTx) SemanticVerify(db database.Database) (func(), error) {
+	if err := tx.SyntacticVerify(); err != nil {
+		return nil, err
+	}
+
+	address := tx.Key().Address()
+	now := tx.vm.clock.Unix()
+
+	smDB := tx.vm.Ctx.SharedMemory.GetDatabase(tx.vm.AVM)
+	defer tx.vm.Ctx.SharedMemory.ReleaseDatabase(tx.vm.AVM)
+
+	state := avm.NewAtomicUTXOs(smDB)
+
+	amount := uint64(0)
+	for _, in := range tx.Ins {
+		utxoID := in.InputID()
+
+		// The UTXO stays in the shared memory until the block importing it is
+		// accepted, so blocks that are still processing mark it as imported
+		if tx.vm.State.GetStatus(db, utxoID.Prefix(importedUTXOsPrefix)) == choices.Accepted {
+			return nil, errUTXOAlreadyImported
+		}
+		utxo, err := state.Get(tx.vm.Ctx.ChainID, utxoID)
+		if err != nil {
+			return nil, errMissingUTXO
+		}
+		if !utxo.AssetID().Equals(tx.vm.AVA) {
+			return nil, errWrongAssetID
+		}
+		out, ok := utxo.Out.(*secp256k1fx.TransferOutput)
+		if !ok {
+			return nil, errUnsupportedUTXO
+		}
+		if !canSpend(out, in.In.Amount(), address, now) {
+			return nil, errUnauthorizedImport
+		}
+
+		newAmount, err := math.Add64(amount, out.Amt)
+		if err != nil {
+			return nil, errSpendOverflow
+		}
+		amount = newAmount
+
+		if err := tx.vm.State.PutStatus(db, utxoID.Prefix(importedUTXOsPrefix), choices.Accepted); err != nil {
+			return nil, err
+		}
+	}
+
+	// Credit the imported $AVA to the signer's account, which pays the tx fee
+	params, err := tx.vm.getGovernanceParameters(db)
+	if err != nil {
+		return nil, err
+	}
+	account, err := tx.vm.getAccount(db, address)
+	if err != nil {
+		return nil, err
+	}
+	account, err = account.Add(amount)
+	if err != nil {
+		return nil, err
+	}
+	account, err = account.RemoveWithFee(0, params.TxFee, tx.Nonce)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.vm.putAccount(db, account); err != nil {
+		return nil, err
+	}
+
+	// If this tx is accepted, remove the imported UTXOs from the shared memory
+	onAccept := func() {
+		smDB := tx.vm.Ctx.SharedMemory.GetDatabase(tx.vm.AVM)
+		defer tx.vm.Ctx.SharedMemory.ReleaseDatabase(tx.vm.AVM)
+
+		state := avm.NewAtomicUTXOs(smDB)
+		for _, in := range tx.Ins {
+			if err := state.Remove(tx.vm.Ctx.ChainID, in.InputID()); err != nil {
+				tx.vm.Ctx.Log.Error("failed to remove imported utxo %s: %s", in.InputID(), err)
+			}
+		}
+	}
+
+	return onAccept, nil
+}
+
+// canSpend returns true if [address] alone may spend [amount] from [out] at
+// time [now]
+func canSpend(out *secp256k1fx.TransferOutput, amount uint64, address ids.ShortID, now uint64) bool {
+	if out.Amt != amount || out.Locktime > now || out.Threshold != 1 {
+		return false
+	}
+	for _, addr := range out.Addrs {
+		if addr.Equals(address) {
+			return true
+		}
+	}
+	return false
+}
+
+func (vm *VM) newImportTx(nonce uint64, networkID uint32, ins []*avm.TransferableInput, key *crypto.PrivateKeySECP256K1R) (*ImportTx, error) {
+	avm.SortTransferableInputs(ins)
+
+	tx := &ImportTx{
+		UnsignedImportTx: UnsignedImportTx{
+			NetworkID: networkID,
+			Nonce:     nonce,
+			Ins:       ins,
+		},
+	}
+
+	unsignedIntf := interface{}(&tx.UnsignedImportTx)
+	unsignedBytes, err := Codec.Marshal(&unsignedIntf) // Byte repr. of unsigned transaction
+	if err != nil {
+		return nil, err
+	}
+
+	sig, err := key.Sign(unsignedBytes)
+	if err != nil {
+		return nil, err
+	}
+	copy(tx.Sig[:], sig)
+
+	return tx, tx.initialize(vm)
+}
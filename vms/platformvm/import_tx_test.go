@@ -0,0 +1,159 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"testing"
+
+	"github.com/ava-labs/gecko/chains/atomic"
+	"github.com/ava-labs/gecko/database/versiondb"
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/vms/avm"
+	"github.com/ava-labs/gecko/vms/secp256k1fx"
+)
+
+// exportTestUTXO sends a UTXO of [amount] $AVA owned by [addr] from the X-Chain
+// to [vm] and returns the input that spends it
+func exportTestUTXO(vm *VM, sm *atomic.Memory, amount uint64, addr ids.ShortID, t *testing.T) *avm.TransferableInput {
+	utxo := &avm.UTXO{
+		UTXOID: avm.UTXOID{
+			TxID:        ids.Empty.Prefix(103),
+			OutputIndex: 0,
+		},
+		Asset: avm.Asset{ID: testAVAID},
+		Out:   avaOutput(testAVAID, amount, addr).Out,
+	}
+
+	state := avm.NewAtomicUTXOs(sm.GetDatabase(vm.Ctx.ChainID, testAVMID))
+	defer sm.ReleaseDatabase(vm.Ctx.ChainID, testAVMID)
+
+	if err := state.Put(vm.Ctx.ChainID, utxo); err != nil {
+		t.Fatal(err)
+	}
+
+	return &avm.TransferableInput{
+		UTXOID: utxo.UTXOID,
+		Asset:  avm.Asset{ID: testAVAID},
+		In: &secp256k1fx.TransferInput{
+			Amt:   amount,
+			Input: secp256k1fx.Input{SigIndices: []uint32{0}},
+		},
+	}
+}
+
+func TestImportTxSyntacticVerify(t *testing.T) {
+	vm, sm := sharedMemoryVM()
+	in := exportTestUTXO(vm, sm, 1000, defaultKey.PublicKey().Address(), t)
+
+	// Case 1: tx is nil
+	var tx *ImportTx
+	if err := tx.SyntacticVerify(); err == nil {
+		t.Fatal("should have failed because tx is nil")
+	}
+
+	// Case 2: network ID is wrong
+	tx, err := vm.newImportTx(defaultNonce+1, testNetworkID+1, []*avm.TransferableInput{in}, defaultKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.SyntacticVerify(); err == nil {
+		t.Fatal("should have errored because network ID is wrong")
+	}
+
+	// Case 3: no inputs
+	tx, err = vm.newImportTx(defaultNonce+1, testNetworkID, nil, defaultKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.SyntacticVerify(); err == nil {
+		t.Fatal("should have errored because there are no inputs")
+	}
+
+	// Case 4: valid
+	tx, err = vm.newImportTx(defaultNonce+1, testNetworkID, []*avm.TransferableInput{in}, defaultKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.SyntacticVerify(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestImportTxSemanticVerify(t *testing.T) {
+	vm, sm := sharedMemoryVM()
+	amount := uint64(1000)
+	in := exportTestUTXO(vm, sm, amount, defaultKey.PublicKey().Address(), t)
+
+	tx, err := vm.newImportTx(defaultNonce+1, testNetworkID, []*avm.TransferableInput{in}, defaultKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	db := versiondb.New(vm.DB)
+	onAccept, err := tx.SemanticVerify(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Verify the imported $AVA was credited and the tx fee was deducted
+	account, err := vm.getAccount(db, defaultKey.PublicKey().Address())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if account.Balance != defaultBalance+amount-txFee {
+		t.Fatalf("balance should be %d but is %d", defaultBalance+amount-txFee, account.Balance)
+	}
+
+	// The UTXO can't be imported again while it's still in the shared memory
+	tx, err = vm.newImportTx(defaultNonce+2, testNetworkID, []*avm.TransferableInput{in}, defaultKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tx.SemanticVerify(db); err == nil {
+		t.Fatal("should have errored because the UTXO was already imported")
+	}
+
+	onAccept()
+
+	// Verify the UTXO was removed from the shared memory
+	state := avm.NewAtomicUTXOs(sm.GetDatabase(vm.Ctx.ChainID, testAVMID))
+	defer sm.ReleaseDatabase(vm.Ctx.ChainID, testAVMID)
+
+	if _, err := state.Get(vm.Ctx.ChainID, in.InputID()); err == nil {
+		t.Fatal("imported UTXO should have been removed from the shared memory")
+	}
+}
+
+func TestImportTxSemanticVerifyUnauthorized(t *testing.T) {
+	vm, sm := sharedMemoryVM()
+	in := exportTestUTXO(vm, sm, 1000, keys[1].PublicKey().Address(), t)
+
+	tx, err := vm.newImportTx(defaultNonce+1, testNetworkID, []*avm.TransferableInput{in}, defaultKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tx.SemanticVerify(versiondb.New(vm.DB)); err == nil {
+		t.Fatal("should have errored because the signer can't spend the UTXO")
+	}
+}
+
+func TestImportTxSemanticVerifyMissingUTXO(t *testing.T) {
+	vm, _ := sharedMemoryVM()
+	in := &avm.TransferableInput{
+		UTXOID: avm.UTXOID{TxID: ids.Empty.Prefix(104)},
+		Asset:  avm.Asset{ID: testAVAID},
+		In: &secp256k1fx.TransferInput{
+			Amt:   1000,
+			Input: secp256k1fx.Input{SigIndices: []uint32{0}},
+		},
+	}
+
+	tx, err := vm.newImportTx(defaultNonce+1, testNetworkID, []*avm.TransferableInput{in}, defaultKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tx.SemanticVerify(versiondb.New(vm.DB)); err == nil {
+		t.Fatal("should have errored because the UTXO doesn't exist")
+	}
+}
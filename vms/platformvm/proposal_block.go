@@ -4,6 +4,8 @@
 package platformvm
 
 import (
+	"context"
+
 	"github.com/ava-labs/gecko/database"
 	"github.com/ava-labs/gecko/database/versiondb"
 	"github.com/ava-labs/gecko/ids"
@@ -84,7 +86,12 @@ func (pb *ProposalBlock) onAbort() (*versiondb.Database, func()) {
 // The parent block must either be a Commit or an Abort block.
 //
 // If this block is valid, this function also sets pas.onCommit and pas.onAbort.
-func (pb *ProposalBlock) Verify() error {
+// Verification is abandoned once [ctx] is done.
+func (pb *ProposalBlock) Verify(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
 	// pdb is the database if this block's parent is accepted
 	var pdb database.Database
 	parent := pb.parentBlock()
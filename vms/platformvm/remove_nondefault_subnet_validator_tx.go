@@ -0,0 +1,251 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"container/heap"
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/gecko/database"
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/crypto"
+	"github.com/ava-labs/gecko/utils/hashing"
+	"github.com/ava-labs/gecko/vms/components/verify"
+)
+
+var (
+	errNotSubnetValidator = verify.NewError(CodeNotSubnetValidator, "node isn't a current or pending validator of the subnet")
+)
+
+// UnsignedRemoveNonDefaultSubnetValidatorTx is an unsigned removeNonDefaultSubnetValidatorTx
+type UnsignedRemoveNonDefaultSubnetValidatorTx struct {
+	// ID of the node to remove
+	NodeID ids.ShortID `serialize:"true"`
+
+	// ID of the subnet the node stops validating
+	Subnet ids.ID `serialize:"true"`
+
+	// ID of the network
+	NetworkID uint32 `serialize:"true"`
+
+	// Next unused nonce of the account paying the tx fee
+	Nonce uint64 `serialize:"true"`
+}
+
+// removeNonDefaultSubnetValidatorTx is a transaction that, if it is accepted,
+// removes a validator from the current and pending validator sets of a subnet
+// other than the default subnet. The node stops validating the subnet at the
+// start of the next epoch.
+// The transaction fee will be paid from the account whose ID is [PayerSig.Address()]
+type removeNonDefaultSubnetValidatorTx struct {
+	UnsignedRemoveNonDefaultSubnetValidatorTx `serialize:"true"`
+
+	// Signatures of a threshold of the subnet's control keys, as when a
+	// validator is added to the subnet
+	ControlSigs [][crypto.SECP256K1RSigLen]byte `serialize:"true"`
+
+	// PayerSig is the signature of the public key whose corresponding account pays
+	// the tx fee for this tx
+	PayerSig [crypto.SECP256K1RSigLen]byte `serialize:"true"`
+
+	vm         *VM
+	id         ids.ID
+	controlIDs []ids.ShortID
+	senderID   ids.ShortID
+
+	// Byte representation of the signed transaction
+	bytes []byte
+}
+
+// initialize [tx]
+func (tx *removeNonDefaultSubnetValidatorTx) initialize(vm *VM) error {
+	bytes, err := Codec.Marshal(tx) // byte representation of the signed transaction
+	if err != nil {
+		return err
+	}
+	tx.vm = vm
+	tx.bytes = bytes
+	tx.id = ids.NewID(hashing.ComputeHash256Array(bytes))
+	return nil
+}
+
+func (tx *removeNonDefaultSubnetValidatorTx) ID() ids.ID { return tx.id }
+
+// SubnetID returns the ID of the subnet the validator is removed from
+func (tx *removeNonDefaultSubnetValidatorTx) SubnetID() ids.ID { return tx.Subnet }
+
+// SyntacticVerify return nil iff [tx] is valid
+// If [tx] is valid, sets [tx.senderID] and [tx.controlIDs]
+func (tx *removeNonDefaultSubnetValidatorTx) SyntacticVerify() error {
+	switch {
+	case tx == nil:
+		return errNilTx
+	case !tx.senderID.IsZero():
+		return nil // Only verify the transaction once
+	case tx.id.IsZero():
+		return errInvalidID
+	case tx.NetworkID != tx.vm.Ctx.NetworkID:
+		return errWrongNetworkID
+	case tx.NodeID.IsZero():
+		return errInvalidID
+	case tx.Subnet.IsZero():
+		return errInvalidID
+	case !crypto.IsSortedAndUniqueSECP2561RSigs(tx.ControlSigs):
+		return errSigsNotSorted
+	}
+
+	// Byte representation of the unsigned transaction
+	unsignedIntf := interface{}(&tx.UnsignedRemoveNonDefaultSubnetValidatorTx)
+	unsignedBytes, err := Codec.Marshal(&unsignedIntf)
+	if err != nil {
+		return err
+	}
+	unsignedBytesHash := hashing.ComputeHash256(unsignedBytes)
+
+	controlIDs := make([]ids.ShortID, len(tx.ControlSigs))
+	// recover control signatures
+	for i, sig := range tx.ControlSigs {
+		key, err := tx.vm.factory.RecoverHashPublicKey(unsignedBytesHash, sig[:])
+		if err != nil {
+			return err
+		}
+		controlIDs[i] = key.Address()
+	}
+
+	// get account to pay tx fee from
+	key, err := tx.vm.factory.RecoverHashPublicKey(unsignedBytesHash, tx.PayerSig[:])
+	if err != nil {
+		return err
+	}
+	tx.controlIDs = controlIDs
+	tx.senderID = key.Address()
+
+	return nil
+}
+
+// SemanticVerify this transaction is valid.
+// The node must be a current or pending validator of the subnet, and the tx
+// must be signed by a threshold of the subnet's control keys.
+func (tx *removeNonDefaultSubnetValidatorTx) SemanticVerify(db database.Database) (func(), error) {
+	if err := tx.SyntacticVerify(); err != nil {
+		return nil, err
+	}
+
+	subnet, err := tx.vm.getSubnet(db, tx.Subnet)
+	if err != nil {
+		return nil, err
+	}
+
+	// Ensure the sigs on [tx] are valid
+	if len(tx.ControlSigs) != int(subnet.Threshold) {
+		return nil, fmt.Errorf("expected tx to have %d control sigs but has %d", subnet.Threshold, len(tx.ControlSigs))
+	}
+	controlKeys := ids.ShortSet{}
+	controlKeys.Add(subnet.ControlKeys...)
+	for _, controlID := range tx.controlIDs {
+		if !controlKeys.Contains(controlID) {
+			return nil, errors.New("tx has control signature from key not in subnet's ControlKeys")
+		}
+	}
+
+	currentValidators, err := tx.vm.getCurrentValidators(db, tx.Subnet)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't get current validators of subnet %s: %v", tx.Subnet, err)
+	}
+	pendingValidators, err := tx.vm.getPendingValidators(db, tx.Subnet)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't get pending validators of subnet %s: %v", tx.Subnet, err)
+	}
+	removedCurrent := currentValidators.removeValidator(tx.NodeID)
+	removedPending := pendingValidators.removeValidator(tx.NodeID)
+	if !removedCurrent && !removedPending {
+		return nil, errNotSubnetValidator
+	}
+	if err := tx.vm.putCurrentValidators(db, currentValidators, tx.Subnet); err != nil {
+		return nil, fmt.Errorf("couldn't put current validators: %v", err)
+	}
+	if err := tx.vm.putPendingValidators(db, pendingValidators, tx.Subnet); err != nil {
+		return nil, fmt.Errorf("couldn't put pending validators: %v", err)
+	}
+
+	// Deduct tx fee from payer's account
+	params, err := tx.vm.getGovernanceParameters(db)
+	if err != nil {
+		return nil, err
+	}
+	account, err := tx.vm.getAccount(db, tx.senderID)
+	if err != nil {
+		return nil, errDBAccount
+	}
+	account, err = account.RemoveWithFee(0, params.TxFee, tx.Nonce)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.vm.putAccount(db, account); err != nil {
+		return nil, errDBPutAccount
+	}
+
+	return nil, nil
+}
+
+// removeValidator removes every tx in [h] that makes [nodeID] a validator.
+// Returns true if any were removed.
+func (h *EventHeap) removeValidator(nodeID ids.ShortID) bool {
+	removed := false
+	for i := 0; i < len(h.Txs); {
+		if h.Txs[i].Vdr().ID().Equals(nodeID) {
+			heap.Remove(h, i)
+			removed = true
+			continue
+		}
+		i++
+	}
+	return removed
+}
+
+func (vm *VM) newRemoveNonDefaultSubnetValidatorTx(
+	nonce uint64,
+	nodeID ids.ShortID,
+	subnetID ids.ID,
+	networkID uint32,
+	controlKeys []*crypto.PrivateKeySECP256K1R,
+	payerKey *crypto.PrivateKeySECP256K1R,
+) (*removeNonDefaultSubnetValidatorTx, error) {
+	tx := &removeNonDefaultSubnetValidatorTx{
+		UnsignedRemoveNonDefaultSubnetValidatorTx: UnsignedRemoveNonDefaultSubnetValidatorTx{
+			NodeID:    nodeID,
+			Subnet:    subnetID,
+			NetworkID: networkID,
+			Nonce:     nonce,
+		},
+	}
+
+	unsignedIntf := interface{}(&tx.UnsignedRemoveNonDefaultSubnetValidatorTx)
+	unsignedBytes, err := Codec.Marshal(&unsignedIntf) // byte repr. of unsigned tx
+	if err != nil {
+		return nil, err
+	}
+	unsignedHash := hashing.ComputeHash256(unsignedBytes)
+
+	// Sign this tx with each control key
+	tx.ControlSigs = make([][crypto.SECP256K1RSigLen]byte, len(controlKeys))
+	for i, key := range controlKeys {
+		sig, err := key.SignHash(unsignedHash)
+		if err != nil {
+			return nil, err
+		}
+		copy(tx.ControlSigs[i][:], sig)
+	}
+	crypto.SortSECP2561RSigs(tx.ControlSigs)
+
+	// Sign this tx with the key of the tx fee payer
+	sig, err := payerKey.SignHash(unsignedHash)
+	if err != nil {
+		return nil, err
+	}
+	copy(tx.PayerSig[:], sig)
+
+	return tx, tx.initialize(vm)
+}
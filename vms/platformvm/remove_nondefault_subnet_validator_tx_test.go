@@ -0,0 +1,174 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"testing"
+
+	"github.com/ava-labs/gecko/database/versiondb"
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/crypto"
+)
+
+func TestRemoveNonDefaultSubnetValidatorTxSyntacticVerify(t *testing.T) {
+	vm := defaultVM()
+
+	// Case 1: tx is nil
+	var tx *removeNonDefaultSubnetValidatorTx
+	if err := tx.SyntacticVerify(); err == nil {
+		t.Fatal("should have errored because tx is nil")
+	}
+
+	// Case 2: Wrong network ID
+	tx, err := vm.newRemoveNonDefaultSubnetValidatorTx(
+		defaultNonce+1,
+		defaultKey.PublicKey().Address(),
+		testSubnet1.ID,
+		testNetworkID+1,
+		[]*crypto.PrivateKeySECP256K1R{testSubnet1ControlKeys[0], testSubnet1ControlKeys[1]},
+		defaultKey,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.SyntacticVerify(); err == nil {
+		t.Fatal("should have errored because the wrong network ID was used")
+	}
+
+	// Case 3: Missing subnet ID
+	tx, err = vm.newRemoveNonDefaultSubnetValidatorTx(
+		defaultNonce+1,
+		defaultKey.PublicKey().Address(),
+		testSubnet1.ID,
+		testNetworkID,
+		[]*crypto.PrivateKeySECP256K1R{testSubnet1ControlKeys[0], testSubnet1ControlKeys[1]},
+		defaultKey,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx.Subnet = ids.ID{}
+	if err := tx.SyntacticVerify(); err == nil {
+		t.Fatal("should have errored because subnet ID is nil")
+	}
+
+	// Case 4: Valid
+	tx, err = vm.newRemoveNonDefaultSubnetValidatorTx(
+		defaultNonce+1,
+		defaultKey.PublicKey().Address(),
+		testSubnet1.ID,
+		testNetworkID,
+		[]*crypto.PrivateKeySECP256K1R{testSubnet1ControlKeys[0], testSubnet1ControlKeys[1]},
+		defaultKey,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.SyntacticVerify(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRemoveNonDefaultSubnetValidatorTxSemanticVerify(t *testing.T) {
+	vm := defaultVM()
+	nodeID := defaultKey.PublicKey().Address()
+
+	// Make the node a pending validator of testSubnet1
+	addTx, err := vm.newAddNonDefaultSubnetValidatorTx(
+		defaultNonce+1,
+		defaultWeight,
+		uint64(defaultValidateStartTime.Unix()),
+		uint64(defaultValidateEndTime.Unix()),
+		nodeID,
+		testSubnet1.ID,
+		testNetworkID,
+		[]*crypto.PrivateKeySECP256K1R{testSubnet1ControlKeys[0], testSubnet1ControlKeys[1]},
+		defaultKey,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := vm.putPendingValidators(vm.DB, &EventHeap{SortByStartTime: true, Txs: []TimedTx{addTx}}, testSubnet1.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	// Case 1: Signed by a key that isn't a control key of the subnet
+	tx, err := vm.newRemoveNonDefaultSubnetValidatorTx(
+		defaultNonce+1,
+		nodeID,
+		testSubnet1.ID,
+		testNetworkID,
+		[]*crypto.PrivateKeySECP256K1R{testSubnet1ControlKeys[0], keys[3]},
+		defaultKey,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tx.SemanticVerify(versiondb.New(vm.DB)); err == nil {
+		t.Fatal("should have failed because the tx is signed by a key that isn't a control key")
+	}
+
+	// Case 2: The node isn't a validator of the subnet
+	tx, err = vm.newRemoveNonDefaultSubnetValidatorTx(
+		defaultNonce+1,
+		keys[3].PublicKey().Address(),
+		testSubnet1.ID,
+		testNetworkID,
+		[]*crypto.PrivateKeySECP256K1R{testSubnet1ControlKeys[0], testSubnet1ControlKeys[1]},
+		defaultKey,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tx.SemanticVerify(versiondb.New(vm.DB)); err == nil {
+		t.Fatal("should have failed because the node isn't a validator of the subnet")
+	}
+
+	// Case 3: Valid
+	tx, err = vm.newRemoveNonDefaultSubnetValidatorTx(
+		defaultNonce+1,
+		nodeID,
+		testSubnet1.ID,
+		testNetworkID,
+		[]*crypto.PrivateKeySECP256K1R{testSubnet1ControlKeys[0], testSubnet1ControlKeys[1]},
+		defaultKey,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	db := versiondb.New(vm.DB)
+	if _, err := tx.SemanticVerify(db); err != nil {
+		t.Fatal(err)
+	}
+	pendingValidators, err := vm.getPendingValidators(db, testSubnet1.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pendingValidators.Len() != 0 {
+		t.Fatal("should have removed the validator from the pending validator set")
+	}
+}
+
+func TestEventHeapRemoveValidator(t *testing.T) {
+	vm := defaultVM()
+
+	current, err := vm.getCurrentValidators(vm.DB, DefaultSubnetID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	numValidators := current.Len()
+
+	if current.removeValidator(ids.NewShortID([20]byte{1})) {
+		t.Fatal("shouldn't have removed a validator that isn't in the heap")
+	}
+	if !current.removeValidator(defaultKey.PublicKey().Address()) {
+		t.Fatal("should have removed the validator")
+	}
+	if current.Len() != numValidators-1 {
+		t.Fatalf("expected %d validators but have %d", numValidators-1, current.Len())
+	}
+	if _, err := current.getDefaultSubnetStaker(defaultKey.PublicKey().Address()); err == nil {
+		t.Fatal("validator should no longer be in the heap")
+	}
+}
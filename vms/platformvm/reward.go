@@ -6,6 +6,10 @@ package platformvm
 import (
 	"math"
 	"time"
+
+	"github.com/ava-labs/gecko/ids"
+
+	safemath "github.com/ava-labs/gecko/utils/math"
 )
 
 // reward returns the amount of $AVA to reward the staker with
@@ -23,3 +27,41 @@ func reward(duration time.Duration, amount uint64, inflationRate float64) uint64
 
 	return uint64(reward)
 }
+
+// splitReward splits a delegator's [reward] between the delegator and the
+// validator it delegated to. The validator takes [shares] out of every
+// NumberOfShares units of the reward. Assumes [shares] <= NumberOfShares.
+func splitReward(reward uint64, shares uint32) (delegatorReward uint64, validatorReward uint64) {
+	// Because shares <= NumberOfShares this will never underflow
+	delegatorShares := NumberOfShares - uint64(shares)
+	// Because delegatorShares <= NumberOfShares this will never overflow
+	delegatorReward = delegatorShares * (reward / NumberOfShares)
+	// Delay rounding as long as possible for small numbers
+	if optimisticReward, err := safemath.Mul64(delegatorShares, reward); err == nil {
+		delegatorReward = optimisticReward / NumberOfShares
+	}
+
+	// Because delegatorReward <= reward this will never underflow
+	return delegatorReward, reward - delegatorReward
+}
+
+// Payout is the $AVA sent to an account when a staker stops staking
+type Payout struct {
+	// Account the $AVA was sent to
+	Account ids.ShortID `serialize:"true"`
+
+	// Staked $AVA returned to the account
+	Stake uint64 `serialize:"true"`
+
+	// Staking reward paid to the account
+	Reward uint64 `serialize:"true"`
+}
+
+// payouts are the payouts made when a staker stopped staking
+type payouts []Payout
+
+// Bytes returns the byte representation of [p]
+func (p payouts) Bytes() []byte {
+	bytes, _ := Codec.Marshal(p)
+	return bytes
+}
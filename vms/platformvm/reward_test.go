@@ -0,0 +1,33 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSplitReward(t *testing.T) {
+	delegatorReward, validatorReward := splitReward(1000, NumberOfShares/4)
+	if delegatorReward != 750 || validatorReward != 250 {
+		t.Fatalf("Expected a 750/250 split but got %d/%d", delegatorReward, validatorReward)
+	}
+}
+
+func TestSplitRewardAllShares(t *testing.T) {
+	delegatorReward, validatorReward := splitReward(1000, NumberOfShares)
+	if delegatorReward != 0 || validatorReward != 1000 {
+		t.Fatalf("Expected a 0/1000 split but got %d/%d", delegatorReward, validatorReward)
+	}
+}
+
+func TestSplitRewardLarge(t *testing.T) {
+	delegatorReward, validatorReward := splitReward(math.MaxUint64, NumberOfShares/2)
+	if delegatorReward+validatorReward != math.MaxUint64 {
+		t.Fatalf("The split should add up to the whole reward")
+	}
+	if delegatorReward != NumberOfShares/2*(math.MaxUint64/NumberOfShares) {
+		t.Fatalf("Wrong delegator reward: %d", delegatorReward)
+	}
+}
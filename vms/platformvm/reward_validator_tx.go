@@ -5,17 +5,17 @@ package platformvm
 
 import (
 	"container/heap"
-	"errors"
 	"fmt"
 
 	"github.com/ava-labs/gecko/database"
 	"github.com/ava-labs/gecko/database/versiondb"
 	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/utils/math"
+	"github.com/ava-labs/gecko/vms/components/verify"
 )
 
 var (
-	errShouldBeDSValidator = errors.New("expected validator to be in the default subnet")
+	errShouldBeDSValidator = verify.NewError(CodeShouldBeDSValidator, "expected validator to be in the default subnet")
 )
 
 // rewardValidatorTx is a transaction that represents a proposal to remove a
@@ -28,6 +28,9 @@ var (
 // If this transaction is accepted and the next block accepted is an *Abort
 // block, the validator is removed and the account that the validator specified
 // receives the staked $AVA but no reward.
+//
+// If the validator was slashed, it's penalized regardless of which block is
+// accepted; see SlashingPenalty.
 type rewardValidatorTx struct {
 	// ID of the tx that created the delegator/validator being removed/rewarded
 	TxID ids.ID `serialize:"true"`
@@ -132,23 +135,48 @@ func (tx *rewardValidatorTx) SemanticVerify(db database.Database) (*versiondb.Da
 
 		accountWithReward := account // The state of the account if the validator earned a validating reward
 		accountNoReward := account   // The state of the account if the validator didn't earn a validating reward
+		payoutWithReward := Payout{Account: accountID, Stake: amount, Reward: amountWithReward - amount}
+		payoutNoReward := Payout{Account: accountID, Stake: amount}
 		if newAccount, err := account.Add(amountWithReward); err == nil {
 			accountWithReward = newAccount
 		} else {
+			payoutWithReward = Payout{Account: accountID}
 			tx.vm.Ctx.Log.Error("error while calculating account balance: %v", err)
 		}
 		if newAccount, err := account.Add(amount); err == nil {
 			accountNoReward = newAccount
 		} else {
+			payoutNoReward = Payout{Account: accountID}
 			tx.vm.Ctx.Log.Error("error while calculating account balance: %v", err)
 		}
 
+		penalty, slashed, err := tx.vm.getSlashing(db, vdrTx.ID())
+		if err != nil {
+			return nil, nil, nil, nil, err
+		}
+		if slashed {
+			switch penalty {
+			case BurnStakePenalty:
+				accountWithReward, accountNoReward = account, account
+				payoutWithReward, payoutNoReward = Payout{Account: accountID}, Payout{Account: accountID}
+			default:
+				accountWithReward = accountNoReward
+				payoutWithReward = payoutNoReward
+			}
+		}
+
 		if err := tx.vm.putAccount(onCommitDB, accountWithReward); err != nil {
 			return nil, nil, nil, nil, errDBPutAccount
 		}
 		if err := tx.vm.putAccount(onAbortDB, accountNoReward); err != nil {
 			return nil, nil, nil, nil, errDBPutAccount
 		}
+		if err := tx.vm.putPayouts(onCommitDB, tx.TxID, payouts{payoutWithReward}); err != nil {
+			return nil, nil, nil, nil, err
+		}
+		if err := tx.vm.putPayouts(onAbortDB, tx.TxID, payouts{payoutNoReward}); err != nil {
+			return nil, nil, nil, nil, err
+		}
 	case *addDefaultSubnetDelegatorTx:
 		parentTx, err := currentEvents.getDefaultSubnetStaker(vdrTx.NodeID)
 		if err != nil {
@@ -159,18 +187,14 @@ func (tx *rewardValidatorTx) SemanticVerify(db database.Database) (*versiondb.Da
 		amount := vdrTx.Wght
 		reward := reward(duration, amount, InflationRate)
 
-		// Because parentTx.Shares <= NumberOfShares this will never underflow
-		delegatorShares := NumberOfShares - uint64(parentTx.Shares)
-		// Because delegatorShares <= NumberOfShares this will never overflow
-		delegatorReward := delegatorShares * (reward / NumberOfShares)
-		// Delay rounding as long as possible for small numbers
-		if optimisticReward, err := math.Mul64(delegatorShares, reward); err == nil {
-			delegatorReward = optimisticReward / NumberOfShares
+		delegatorReward, validatorReward := splitReward(reward, parentTx.Shares)
+		// A slashed validator doesn't earn a share of its delegators' rewards
+		if _, slashed, err := tx.vm.getSlashing(db, parentTx.ID()); err != nil {
+			return nil, nil, nil, nil, err
+		} else if slashed {
+			validatorReward = 0
 		}
 
-		// Because delegatorReward <= reward this will never underflow
-		validatorReward := reward - delegatorReward
-
 		delegatorAmountWithReward, err := math.Add64(amount, delegatorReward)
 		if err != nil {
 			delegatorAmountWithReward = amount
@@ -189,14 +213,18 @@ func (tx *rewardValidatorTx) SemanticVerify(db database.Database) (*versiondb.Da
 
 		delegatorAccountWithReward := delegatorAccount // The state of the account if the validator earned a validating reward
 		delegatorAccountNoReward := delegatorAccount   // The state of the account if the validator didn't earn a validating reward
+		delegatorPayoutWithReward := Payout{Account: delegatorAccountID, Stake: amount, Reward: delegatorAmountWithReward - amount}
+		delegatorPayoutNoReward := Payout{Account: delegatorAccountID, Stake: amount}
 		if newAccount, err := delegatorAccount.Add(delegatorAmountWithReward); err == nil {
 			delegatorAccountWithReward = newAccount
 		} else {
+			delegatorPayoutWithReward = Payout{Account: delegatorAccountID}
 			tx.vm.Ctx.Log.Error("error while calculating account balance: %v", err)
 		}
 		if newAccount, err := delegatorAccount.Add(amount); err == nil {
 			delegatorAccountNoReward = newAccount
 		} else {
+			delegatorPayoutNoReward = Payout{Account: delegatorAccountID}
 			tx.vm.Ctx.Log.Error("error while calculating account balance: %v", err)
 		}
 
@@ -218,29 +246,30 @@ func (tx *rewardValidatorTx) SemanticVerify(db database.Database) (*versiondb.Da
 		}
 
 		validatorAccountWithReward := validatorAccount // The state of the account if the validator earned a validating reward
+		validatorPayout := Payout{Account: validatorAccountID, Reward: validatorReward}
 		if newAccount, err := validatorAccount.Add(validatorReward); err == nil {
 			validatorAccountWithReward = newAccount
 		} else {
+			validatorPayout = Payout{Account: validatorAccountID}
 			tx.vm.Ctx.Log.Error("error while calculating account balance: %v", err)
 		}
 
 		if err := tx.vm.putAccount(onCommitDB, validatorAccountWithReward); err != nil {
 			return nil, nil, nil, nil, errDBPutAccount
 		}
+		if err := tx.vm.putPayouts(onCommitDB, tx.TxID, payouts{delegatorPayoutWithReward, validatorPayout}); err != nil {
+			return nil, nil, nil, nil, err
+		}
+		if err := tx.vm.putPayouts(onAbortDB, tx.TxID, payouts{delegatorPayoutNoReward}); err != nil {
+			return nil, nil, nil, nil, err
+		}
 	default:
 		return nil, nil, nil, nil, errShouldBeDSValidator
 	}
 
-	// Regardless of whether this tx is committed or aborted, update the
-	// validator set to remove the staker. onAbortDB or onCommitDB should commit
-	// (flush to vm.DB) before this is called
-	updateValidators := func() {
-		if err := tx.vm.updateValidators(DefaultSubnetID); err != nil {
-			tx.vm.Ctx.Log.Fatal("failed to update validators on the default subnet: %s", err)
-		}
-	}
-
-	return onCommitDB, onAbortDB, updateValidators, updateValidators, nil
+	// Regardless of whether this tx is committed or aborted, the staker is
+	// removed from the validator set at the start of the next epoch
+	return onCommitDB, onAbortDB, nil, nil, nil
 }
 
 // InitiallyPrefersCommit returns true.
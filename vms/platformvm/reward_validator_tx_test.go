@@ -231,3 +231,57 @@ func TestRewardDelegatorTxSemanticVerify(t *testing.T) {
 		t.Fatalf("expected account balance to be %d was %d", expectedBalance, account.Balance)
 	}
 }
+
+func TestRewardValidatorTxPayouts(t *testing.T) {
+	vm := defaultVM()
+	currentValidators, err := vm.getCurrentValidators(vm.DB, DefaultSubnetID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	nextToRemove := currentValidators.Peek().(*addDefaultSubnetValidatorTx)
+
+	if err := vm.putTimestamp(vm.DB, defaultValidateEndTime); err != nil {
+		t.Fatal(err)
+	}
+	tx, err := vm.newRewardValidatorTx(nextToRemove.ID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	onCommitDB, onAbortDB, _, _, err := tx.SemanticVerify(vm.DB)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, paid, err := vm.getPayouts(vm.DB, nextToRemove.ID()); err != nil {
+		t.Fatal(err)
+	} else if paid {
+		t.Fatalf("Shouldn't have paid out before the proposal is decided")
+	}
+
+	expectedReward := reward(nextToRemove.Duration(), nextToRemove.Wght, InflationRate)
+	payouts, paid, err := vm.getPayouts(onCommitDB, nextToRemove.ID())
+	switch {
+	case err != nil:
+		t.Fatal(err)
+	case !paid || len(payouts) != 1:
+		t.Fatalf("Expected 1 payout but got %d", len(payouts))
+	case !payouts[0].Account.Equals(nextToRemove.Destination):
+		t.Fatalf("Paid out to the wrong account")
+	case payouts[0].Stake != nextToRemove.Wght:
+		t.Fatalf("Expected stake of %d but got %d", nextToRemove.Wght, payouts[0].Stake)
+	case payouts[0].Reward != expectedReward:
+		t.Fatalf("Expected reward of %d but got %d", expectedReward, payouts[0].Reward)
+	}
+
+	payouts, paid, err = vm.getPayouts(onAbortDB, nextToRemove.ID())
+	switch {
+	case err != nil:
+		t.Fatal(err)
+	case !paid || len(payouts) != 1:
+		t.Fatalf("Expected 1 payout but got %d", len(payouts))
+	case payouts[0].Stake != nextToRemove.Wght:
+		t.Fatalf("Expected stake of %d but got %d", nextToRemove.Wght, payouts[0].Stake)
+	case payouts[0].Reward != 0:
+		t.Fatalf("Shouldn't have been rewarded")
+	}
+}
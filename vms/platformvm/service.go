@@ -12,11 +12,14 @@ import (
 
 	"github.com/gorilla/rpc/v2/json2"
 
+	"github.com/ava-labs/gecko/chains"
 	"github.com/ava-labs/gecko/database"
 	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/utils/crypto"
 	"github.com/ava-labs/gecko/utils/formatting"
 	"github.com/ava-labs/gecko/utils/json"
+	"github.com/ava-labs/gecko/vms/avm"
+	"github.com/ava-labs/gecko/vms/secp256k1fx"
 )
 
 var (
@@ -121,58 +124,109 @@ func (service *Service) GetSubnets(_ *http.Request, args *GetSubnetsArgs, respon
 	return nil
 }
 
+// GetValidatedSubnetsArgs are the arguments to GetValidatedSubnets
+type GetValidatedSubnetsArgs struct {
+	// ID of the node. If omitted, this node.
+	NodeID ids.ShortID `json:"nodeID"`
+}
+
+// GetValidatedSubnetsReply is the response from calling GetValidatedSubnets
+type GetValidatedSubnetsReply struct {
+	// IDs of the subnets the node is a current validator of, including the
+	// default subnet
+	SubnetIDs []ids.ID `json:"subnetIDs"`
+}
+
+// GetValidatedSubnets returns the subnets the node [args.NodeID] currently validates
+func (service *Service) GetValidatedSubnets(_ *http.Request, args *GetValidatedSubnetsArgs, reply *GetValidatedSubnetsReply) error {
+	service.vm.Ctx.Log.Debug("platform.getValidatedSubnets called")
+
+	nodeID := args.NodeID
+	if nodeID.IsZero() {
+		nodeID = service.vm.Ctx.NodeID
+	}
+
+	subnets, err := service.vm.getSubnets(service.vm.DB)
+	if err != nil {
+		return fmt.Errorf("error getting subnets from database: %v", err)
+	}
+	subnetIDs := []ids.ID{DefaultSubnetID}
+	for _, subnet := range subnets {
+		subnetIDs = append(subnetIDs, subnet.ID)
+	}
+
+	reply.SubnetIDs = []ids.ID{}
+	for _, subnetID := range subnetIDs {
+		current, err := service.vm.getCurrentValidators(service.vm.DB, subnetID)
+		if err != nil {
+			return fmt.Errorf("couldn't get current validators of subnet %s: %v", subnetID, err)
+		}
+		for _, tx := range current.Txs {
+			if tx.Vdr().ID().Equals(nodeID) {
+				reply.SubnetIDs = append(reply.SubnetIDs, subnetID)
+				break
+			}
+		}
+	}
+	return nil
+}
+
 /*
  ******************************************************
  **************** Get/Sample Validators ***************
  ******************************************************
  */
 
+const (
+	// defaultValidatorsLimit is the number of validators returned by
+	// GetCurrentValidators and GetPendingValidators if no limit is given
+	defaultValidatorsLimit = 1024
+
+	// maxValidatorsLimit is the maximum number of validators returned by
+	// GetCurrentValidators and GetPendingValidators
+	maxValidatorsLimit = 4096
+)
+
+var (
+	errValidatorsLimitTooLarge = fmt.Errorf("limit can be at most %d", maxValidatorsLimit)
+)
+
 // GetCurrentValidatorsArgs are the arguments for calling GetCurrentValidators
 type GetCurrentValidatorsArgs struct {
 	// Subnet we're listing the validators of
 	// If omitted, defaults to default subnet
 	SubnetID ids.ID `json:"subnetID"`
+
+	// If non-empty, only validators added by txs with IDs after this index
+	// are returned. Used to get the page that follows a previous reply's
+	// EndIndex.
+	StartIndex ids.ID `json:"startIndex"`
+
+	// Maximum number of validators to return. Defaults to 1024.
+	Limit json.Uint32 `json:"limit"`
 }
 
 // GetCurrentValidatorsReply are the results from calling GetCurrentValidators
 type GetCurrentValidatorsReply struct {
 	Validators []APIValidator `json:"validators"`
+
+	// ID of the tx that added the last validator returned. Pass it as the
+	// StartIndex of the next request to get the next page. A page with fewer
+	// than Limit validators is the last page.
+	EndIndex ids.ID `json:"endIndex"`
 }
 
-// GetCurrentValidators returns the list of current validators
+// GetCurrentValidators returns the list of current validators, ordered by the
+// IDs of the txs that added them
 func (service *Service) GetCurrentValidators(_ *http.Request, args *GetCurrentValidatorsArgs, reply *GetCurrentValidatorsReply) error {
 	service.vm.Ctx.Log.Debug("GetCurrentValidators called")
 
-	if args.SubnetID.IsZero() {
-		args.SubnetID = DefaultSubnetID
-	}
-
-	validators, err := service.vm.getCurrentValidators(service.vm.DB, args.SubnetID)
+	validators, endIndex, err := service.pageValidators(args.SubnetID, false, args.StartIndex, args.Limit)
 	if err != nil {
-		return fmt.Errorf("couldn't get validators of subnet with ID %s. Does it exist?", args.SubnetID)
-	}
-
-	reply.Validators = make([]APIValidator, validators.Len())
-	for i, tx := range validators.Txs {
-		vdr := tx.Vdr()
-		weight := json.Uint64(vdr.Weight())
-		if args.SubnetID.Equals(DefaultSubnetID) {
-			reply.Validators[i] = APIValidator{
-				ID:          vdr.ID(),
-				StartTime:   json.Uint64(tx.StartTime().Unix()),
-				EndTime:     json.Uint64(tx.EndTime().Unix()),
-				StakeAmount: &weight,
-			}
-		} else {
-			reply.Validators[i] = APIValidator{
-				ID:        vdr.ID(),
-				StartTime: json.Uint64(tx.StartTime().Unix()),
-				EndTime:   json.Uint64(tx.EndTime().Unix()),
-				Weight:    &weight,
-			}
-		}
+		return err
 	}
-
+	reply.Validators = validators
+	reply.EndIndex = endIndex
 	return nil
 }
 
@@ -181,39 +235,79 @@ type GetPendingValidatorsArgs struct {
 	// Subnet we're getting the pending validators of
 	// If omitted, defaults to default subnet
 	SubnetID ids.ID `json:"subnetID"`
+
+	// If non-empty, only validators added by txs with IDs after this index
+	// are returned. Used to get the page that follows a previous reply's
+	// EndIndex.
+	StartIndex ids.ID `json:"startIndex"`
+
+	// Maximum number of validators to return. Defaults to 1024.
+	Limit json.Uint32 `json:"limit"`
 }
 
 // GetPendingValidatorsReply are the results from calling GetPendingValidators
 type GetPendingValidatorsReply struct {
 	Validators []APIValidator `json:"validators"`
+
+	// ID of the tx that added the last validator returned. Pass it as the
+	// StartIndex of the next request to get the next page. A page with fewer
+	// than Limit validators is the last page.
+	EndIndex ids.ID `json:"endIndex"`
 }
 
-// GetPendingValidators returns the list of current validators
+// GetPendingValidators returns the list of pending validators, ordered by the
+// IDs of the txs that added them
 func (service *Service) GetPendingValidators(_ *http.Request, args *GetPendingValidatorsArgs, reply *GetPendingValidatorsReply) error {
 	service.vm.Ctx.Log.Debug("GetPendingValidators called")
 
-	if args.SubnetID.IsZero() {
-		args.SubnetID = DefaultSubnetID
+	validators, endIndex, err := service.pageValidators(args.SubnetID, true, args.StartIndex, args.Limit)
+	if err != nil {
+		return err
+	}
+	reply.Validators = validators
+	reply.EndIndex = endIndex
+	return nil
+}
+
+// pageValidators returns a page of the current, or if [pending] the pending,
+// validators of [subnetID] and the ID of the tx that added the last of them
+func (service *Service) pageValidators(subnetID ids.ID, pending bool, start ids.ID, limitArg json.Uint32) ([]APIValidator, ids.ID, error) {
+	limit := int(limitArg)
+	switch {
+	case limit == 0:
+		limit = defaultValidatorsLimit
+	case limit > maxValidatorsLimit:
+		return nil, ids.ID{}, errValidatorsLimitTooLarge
 	}
 
-	validators, err := service.vm.getPendingValidators(service.vm.DB, args.SubnetID)
+	if subnetID.IsZero() {
+		subnetID = DefaultSubnetID
+	}
+
+	txs, err := service.vm.indexedValidators(subnetID, pending)
 	if err != nil {
-		return fmt.Errorf("couldn't get validators of subnet with ID %s. Does it exist?", args.SubnetID)
+		return nil, ids.ID{}, fmt.Errorf("couldn't get validators of subnet with ID %s. Does it exist?", subnetID)
 	}
+	txs = pageValidators(txs, start, limit)
 
-	reply.Validators = make([]APIValidator, validators.Len())
-	for i, tx := range validators.Txs {
+	validators := make([]APIValidator, len(txs))
+	for i, tx := range txs {
 		vdr := tx.Vdr()
 		weight := json.Uint64(vdr.Weight())
-		if args.SubnetID.Equals(DefaultSubnetID) {
-			reply.Validators[i] = APIValidator{
+		if subnetID.Equals(DefaultSubnetID) {
+			validators[i] = APIValidator{
 				ID:          vdr.ID(),
 				StartTime:   json.Uint64(tx.StartTime().Unix()),
 				EndTime:     json.Uint64(tx.EndTime().Unix()),
 				StakeAmount: &weight,
 			}
+			// Delegators can compare the fees validators charge them
+			if vdrTx, ok := tx.(*addDefaultSubnetValidatorTx); ok {
+				feeRate := json.Uint32(vdrTx.Shares)
+				validators[i].DelegationFeeRate = &feeRate
+			}
 		} else {
-			reply.Validators[i] = APIValidator{
+			validators[i] = APIValidator{
 				ID:        vdr.ID(),
 				StartTime: json.Uint64(tx.StartTime().Unix()),
 				EndTime:   json.Uint64(tx.EndTime().Unix()),
@@ -222,6 +316,118 @@ func (service *Service) GetPendingValidators(_ *http.Request, args *GetPendingVa
 		}
 	}
 
+	endIndex := ids.ID{}
+	if len(txs) > 0 {
+		endIndex = txs[len(txs)-1].ID()
+	}
+	return validators, endIndex, nil
+}
+
+// APIDelegator is a delegation of stake to a default subnet validator.
+// [ID] is the node ID of the validator being delegated to.
+type APIDelegator struct {
+	APIValidator
+
+	TxID        ids.ID      `json:"txID"`
+	Destination ids.ShortID `json:"destination"`
+}
+
+// GetDelegatorsArgs are the arguments for calling GetDelegators
+type GetDelegatorsArgs struct {
+	// Validator whose delegators are listed
+	// If omitted, the delegators of all default subnet validators are listed
+	NodeID ids.ShortID `json:"nodeID"`
+}
+
+// GetDelegatorsReply are the results from calling GetDelegators
+type GetDelegatorsReply struct {
+	Current []APIDelegator `json:"current"`
+	Pending []APIDelegator `json:"pending"`
+}
+
+// GetDelegators returns the current and pending delegators of the default
+// subnet
+func (service *Service) GetDelegators(_ *http.Request, args *GetDelegatorsArgs, reply *GetDelegatorsReply) error {
+	service.vm.Ctx.Log.Debug("GetDelegators called")
+
+	current, err := service.vm.getCurrentValidators(service.vm.DB, DefaultSubnetID)
+	if err != nil {
+		return fmt.Errorf("couldn't get current validators of the default subnet: %w", err)
+	}
+	pending, err := service.vm.getPendingValidators(service.vm.DB, DefaultSubnetID)
+	if err != nil {
+		return fmt.Errorf("couldn't get pending validators of the default subnet: %w", err)
+	}
+
+	reply.Current = apiDelegators(current, args.NodeID)
+	reply.Pending = apiDelegators(pending, args.NodeID)
+	return nil
+}
+
+// apiDelegators returns the delegators in [events]. If [nodeID] is non-zero,
+// only the delegators of that validator are returned.
+func apiDelegators(events *EventHeap, nodeID ids.ShortID) []APIDelegator {
+	delegators := []APIDelegator{}
+	for _, tx := range events.Txs {
+		delegator, ok := tx.(*addDefaultSubnetDelegatorTx)
+		if !ok || (!nodeID.IsZero() && !nodeID.Equals(delegator.NodeID)) {
+			continue
+		}
+		stakeAmount := json.Uint64(delegator.Wght)
+		delegators = append(delegators, APIDelegator{
+			APIValidator: APIValidator{
+				ID:          delegator.NodeID,
+				StartTime:   json.Uint64(delegator.StartTime().Unix()),
+				EndTime:     json.Uint64(delegator.EndTime().Unix()),
+				StakeAmount: &stakeAmount,
+			},
+			TxID:        delegator.ID(),
+			Destination: delegator.Destination,
+		})
+	}
+	return delegators
+}
+
+// APIPayout is the $AVA sent to an account when a staker stopped staking
+type APIPayout struct {
+	Address ids.ShortID `json:"address"`
+	Stake   json.Uint64 `json:"stake"`
+	Reward  json.Uint64 `json:"reward"`
+}
+
+// GetRewardsArgs are the arguments for calling GetRewards
+type GetRewardsArgs struct {
+	// ID of the tx that added the validator or delegator
+	TxID ids.ID `json:"txID"`
+}
+
+// GetRewardsReply are the results from calling GetRewards
+type GetRewardsReply struct {
+	Payouts []APIPayout `json:"payouts"`
+}
+
+// GetRewards returns the staked $AVA and rewards that were paid out when the
+// default subnet validator or delegator added by [args.TxID] stopped staking.
+// A delegator's payouts include the validator's share of its reward.
+func (service *Service) GetRewards(_ *http.Request, args *GetRewardsArgs, reply *GetRewardsReply) error {
+	service.vm.Ctx.Log.Debug("GetRewards called")
+
+	payouts, paid, err := service.vm.getPayouts(service.vm.DB, args.TxID)
+	if err != nil {
+		return fmt.Errorf("couldn't get payouts: %w", err)
+	}
+	if !paid {
+		return fmt.Errorf("staker added by tx %s hasn't been paid out", args.TxID)
+	}
+
+	reply.Payouts = make([]APIPayout, len(payouts))
+	for i, payout := range payouts {
+		reply.Payouts[i] = APIPayout{
+			Address: payout.Account,
+			Stake:   json.Uint64(payout.Stake),
+			Reward:  json.Uint64(payout.Reward),
+		}
+	}
 	return nil
 }
 
@@ -583,6 +789,47 @@ func (service *Service) AddNonDefaultSubnetValidator(_ *http.Request, args *AddN
 	return nil
 }
 
+// RemoveNonDefaultSubnetValidatorArgs are the arguments to RemoveNonDefaultSubnetValidator
+type RemoveNonDefaultSubnetValidatorArgs struct {
+	// ID of the node to remove
+	NodeID ids.ShortID `json:"nodeID"`
+
+	// ID of the subnet the node stops validating
+	SubnetID ids.ID `json:"subnetID"`
+
+	// Next unused nonce of the account the tx fee is paid from
+	PayerNonce json.Uint64 `json:"payerNonce"`
+}
+
+// RemoveNonDefaultSubnetValidatorResponse is the response from a call to RemoveNonDefaultSubnetValidator
+type RemoveNonDefaultSubnetValidatorResponse struct {
+	// The unsigned transaction
+	UnsignedTx formatting.CB58 `json:"unsignedTx"`
+}
+
+// RemoveNonDefaultSubnetValidator removes a validator from a subnet other than the default subnet
+// Returns the unsigned transaction, which must be signed using Sign
+func (service *Service) RemoveNonDefaultSubnetValidator(_ *http.Request, args *RemoveNonDefaultSubnetValidatorArgs, response *RemoveNonDefaultSubnetValidatorResponse) error {
+	service.vm.Ctx.Log.Debug("platform.removeNonDefaultSubnetValidator called")
+
+	tx := removeNonDefaultSubnetValidatorTx{
+		UnsignedRemoveNonDefaultSubnetValidatorTx: UnsignedRemoveNonDefaultSubnetValidatorTx{
+			NodeID:    args.NodeID,
+			Subnet:    args.SubnetID,
+			NetworkID: service.vm.Ctx.NetworkID,
+			Nonce:     uint64(args.PayerNonce),
+		},
+	}
+
+	txBytes, err := Codec.Marshal(genericTx{Tx: &tx})
+	if err != nil {
+		return errCreatingTransaction
+	}
+
+	response.UnsignedTx.Bytes = txBytes
+	return nil
+}
+
 /*
  ******************************************************
  **************** Sign/Issue Txs **********************
@@ -642,8 +889,16 @@ func (service *Service) Sign(_ *http.Request, args *SignArgs, reply *SignRespons
 		genTx.Tx, err = service.signAddNonDefaultSubnetValidatorTx(tx, key)
 	case *CreateSubnetTx:
 		genTx.Tx, err = service.signCreateSubnetTx(tx, key)
+	case *governanceProposalTx:
+		genTx.Tx, err = service.signGovernanceProposalTx(tx, key)
+	case *ExportTx:
+		genTx.Tx, err = service.signExportTx(tx, key)
+	case *slashTx:
+		genTx.Tx, err = service.signSlashTx(tx, key)
+	case *removeNonDefaultSubnetValidatorTx:
+		genTx.Tx, err = service.signRemoveNonDefaultSubnetValidatorTx(tx, key)
 	default:
-		err = errors.New("Could not parse given tx. Must be one of: addDefaultSubnetValidatorTx, addNonDefaultSubnetValidatorTx, createSubnetTx")
+		err = errors.New("Could not parse given tx. Must be one of: addDefaultSubnetValidatorTx, addNonDefaultSubnetValidatorTx, createSubnetTx, governanceProposalTx, exportTx, slashTx, removeNonDefaultSubnetValidatorTx")
 	}
 	if err != nil {
 		return err
@@ -722,6 +977,72 @@ func (service *Service) signCreateSubnetTx(tx *CreateSubnetTx, key *crypto.Priva
 	return tx, nil
 }
 
+// Sign [tx] with [key]
+func (service *Service) signGovernanceProposalTx(tx *governanceProposalTx, key *crypto.PrivateKeySECP256K1R) (*governanceProposalTx, error) {
+	service.vm.Ctx.Log.Debug("platform.signGovernanceProposalTx called")
+
+	unsignedIntf := interface{}(&tx.UnsignedGovernanceProposalTx)
+	unsignedTxBytes, err := Codec.Marshal(&unsignedIntf)
+	if err != nil {
+		return nil, fmt.Errorf("error serializing unsigned tx: %v", err)
+	}
+
+	sig, err := key.Sign(unsignedTxBytes)
+	if err != nil {
+		return nil, errors.New("error while signing")
+	}
+	if len(sig) != crypto.SECP256K1RSigLen {
+		return nil, fmt.Errorf("expected signature to be length %d but was length %d", crypto.SECP256K1RSigLen, len(sig))
+	}
+	copy(tx.Sig[:], sig)
+
+	return tx, nil
+}
+
+// Sign [tx] with [key]
+func (service *Service) signSlashTx(tx *slashTx, key *crypto.PrivateKeySECP256K1R) (*slashTx, error) {
+	service.vm.Ctx.Log.Debug("platform.signSlashTx called")
+
+	unsignedIntf := interface{}(&tx.UnsignedSlashTx)
+	unsignedTxBytes, err := Codec.Marshal(&unsignedIntf)
+	if err != nil {
+		return nil, fmt.Errorf("error serializing unsigned tx: %v", err)
+	}
+
+	sig, err := key.Sign(unsignedTxBytes)
+	if err != nil {
+		return nil, errors.New("error while signing")
+	}
+	if len(sig) != crypto.SECP256K1RSigLen {
+		return nil, fmt.Errorf("expected signature to be length %d but was length %d", crypto.SECP256K1RSigLen, len(sig))
+	}
+	copy(tx.Sig[:], sig)
+
+	return tx, nil
+}
+
+// Sign [tx] with [key]
+func (service *Service) signExportTx(tx *ExportTx, key *crypto.PrivateKeySECP256K1R) (*ExportTx, error) {
+	service.vm.Ctx.Log.Debug("platform.signExportTx called")
+
+	unsignedIntf := interface{}(&tx.UnsignedExportTx)
+	unsignedTxBytes, err := Codec.Marshal(&unsignedIntf)
+	if err != nil {
+		return nil, fmt.Errorf("error serializing unsigned tx: %v", err)
+	}
+
+	sig, err := key.Sign(unsignedTxBytes)
+	if err != nil {
+		return nil, errors.New("error while signing")
+	}
+	if len(sig) != crypto.SECP256K1RSigLen {
+		return nil, fmt.Errorf("expected signature to be length %d but was length %d", crypto.SECP256K1RSigLen, len(sig))
+	}
+	copy(tx.Sig[:], sig)
+
+	return tx, nil
+}
+
 // Signs an unsigned or partially signed addNonDefaultSubnetValidatorTx with [key]
 // If [key] is a control key for the subnet and there is an empty spot in tx.ControlSigs, signs there
 // If [key] is a control key for the subnet and there is no empty spot in tx.ControlSigs, signs as payer
@@ -775,6 +1096,49 @@ func (service *Service) signAddNonDefaultSubnetValidatorTx(tx *addNonDefaultSubn
 	return tx, nil
 }
 
+// Signs an unsigned or partially signed removeNonDefaultSubnetValidatorTx with
+// [key], placing the signature as signAddNonDefaultSubnetValidatorTx does
+func (service *Service) signRemoveNonDefaultSubnetValidatorTx(tx *removeNonDefaultSubnetValidatorTx, key *crypto.PrivateKeySECP256K1R) (*removeNonDefaultSubnetValidatorTx, error) {
+	service.vm.Ctx.Log.Debug("platform.signRemoveNonDefaultSubnetValidatorTx called")
+
+	unsignedIntf := interface{}(&tx.UnsignedRemoveNonDefaultSubnetValidatorTx)
+	unsignedTxBytes, err := Codec.Marshal(&unsignedIntf)
+	if err != nil {
+		return nil, fmt.Errorf("error serializing unsigned tx: %v", err)
+	}
+	sig, err := key.Sign(unsignedTxBytes)
+	if err != nil {
+		return nil, errors.New("error while signing")
+	}
+	if len(sig) != crypto.SECP256K1RSigLen {
+		return nil, fmt.Errorf("expected signature to be length %d but was length %d", crypto.SECP256K1RSigLen, len(sig))
+	}
+
+	subnet, err := service.vm.getSubnet(service.vm.DB, tx.SubnetID())
+	if err != nil {
+		return nil, fmt.Errorf("problem getting subnet information: %v", err)
+	}
+
+	controlKeySet := ids.ShortSet{}
+	controlKeySet.Add(subnet.ControlKeys...)
+	isControlKey := controlKeySet.Contains(key.PublicKey().Address())
+
+	payerSigEmpty := tx.PayerSig == [crypto.SECP256K1RSigLen]byte{} // true if no key has signed to pay the tx fee
+
+	if isControlKey && len(tx.ControlSigs) != int(subnet.Threshold) { // Sign as controlSig
+		tx.ControlSigs = append(tx.ControlSigs, [crypto.SECP256K1RSigLen]byte{})
+		copy(tx.ControlSigs[len(tx.ControlSigs)-1][:], sig)
+	} else if payerSigEmpty { // sign as payer
+		copy(tx.PayerSig[:], sig)
+	} else {
+		return nil, errors.New("no place for key to sign")
+	}
+
+	crypto.SortSECP2561RSigs(tx.ControlSigs)
+
+	return tx, nil
+}
+
 // IssueTxArgs are the arguments to IssueTx
 type IssueTxArgs struct {
 	// Tx being sent to the network
@@ -787,7 +1151,8 @@ type IssueTxResponse struct {
 	TxID ids.ID `json:"txID"`
 }
 
-// IssueTx issues the transaction [args.Tx] to the network
+// IssueTx issues the transaction [args.Tx] to the network. If [args.Tx] is
+// malformed, the returned error carries the reason's verification code.
 func (service *Service) IssueTx(_ *http.Request, args *IssueTxArgs, response *IssueTxResponse) error {
 	genTx := genericTx{}
 	if err := Codec.Unmarshal(args.Tx.Bytes, &genTx); err != nil {
@@ -797,7 +1162,10 @@ func (service *Service) IssueTx(_ *http.Request, args *IssueTxArgs, response *Is
 	switch tx := genTx.Tx.(type) {
 	case TimedTx:
 		if err := tx.initialize(service.vm); err != nil {
-			return fmt.Errorf("error initializing tx: %s", err)
+			return fmt.Errorf("error initializing tx: %w", err)
+		}
+		if err := tx.SyntacticVerify(); err != nil {
+			return err
 		}
 		service.vm.unissuedEvents.Push(tx)
 		defer service.vm.resetTimer()
@@ -805,15 +1173,232 @@ func (service *Service) IssueTx(_ *http.Request, args *IssueTxArgs, response *Is
 		return nil
 	case *CreateSubnetTx:
 		if err := tx.initialize(service.vm); err != nil {
-			return fmt.Errorf("error initializing tx: %s", err)
+			return fmt.Errorf("error initializing tx: %w", err)
+		}
+		if err := tx.SyntacticVerify(); err != nil {
+			return err
 		}
 		service.vm.unissuedDecisionTxs = append(service.vm.unissuedDecisionTxs, tx)
 		defer service.vm.resetTimer()
 		response.TxID = tx.ID
 		return nil
+	case *governanceProposalTx:
+		if err := tx.initialize(service.vm); err != nil {
+			return fmt.Errorf("error initializing tx: %w", err)
+		}
+		if err := tx.SyntacticVerify(); err != nil {
+			return err
+		}
+		service.vm.unissuedProposals = append(service.vm.unissuedProposals, tx)
+		defer service.vm.resetTimer()
+		response.TxID = tx.ID()
+		return nil
+	case *ExportTx:
+		if err := tx.initialize(service.vm); err != nil {
+			return fmt.Errorf("error initializing tx: %w", err)
+		}
+		if err := tx.SyntacticVerify(); err != nil {
+			return err
+		}
+		service.vm.unissuedDecisionTxs = append(service.vm.unissuedDecisionTxs, tx)
+		defer service.vm.resetTimer()
+		response.TxID = tx.ID()
+		return nil
+	case *ImportTx:
+		if err := tx.initialize(service.vm); err != nil {
+			return fmt.Errorf("error initializing tx: %w", err)
+		}
+		if err := tx.SyntacticVerify(); err != nil {
+			return err
+		}
+		service.vm.unissuedDecisionTxs = append(service.vm.unissuedDecisionTxs, tx)
+		defer service.vm.resetTimer()
+		response.TxID = tx.ID()
+		return nil
+	case *slashTx:
+		if err := tx.initialize(service.vm); err != nil {
+			return fmt.Errorf("error initializing tx: %w", err)
+		}
+		if err := tx.SyntacticVerify(); err != nil {
+			return err
+		}
+		service.vm.unissuedDecisionTxs = append(service.vm.unissuedDecisionTxs, tx)
+		defer service.vm.resetTimer()
+		response.TxID = tx.ID()
+		return nil
+	case *removeNonDefaultSubnetValidatorTx:
+		if err := tx.initialize(service.vm); err != nil {
+			return fmt.Errorf("error initializing tx: %w", err)
+		}
+		if err := tx.SyntacticVerify(); err != nil {
+			return err
+		}
+		service.vm.unissuedDecisionTxs = append(service.vm.unissuedDecisionTxs, tx)
+		defer service.vm.resetTimer()
+		response.TxID = tx.ID()
+		return nil
 	default:
-		return errors.New("Could not parse given tx. Must be one of: addDefaultSubnetValidatorTx, addDefaultSubnetDelegatorTx, addNonDefaultSubnetValidatorTx, createSubnetTx")
+		return errors.New("Could not parse given tx. Must be one of: addDefaultSubnetValidatorTx, addDefaultSubnetDelegatorTx, addNonDefaultSubnetValidatorTx, createSubnetTx, governanceProposalTx, exportTx, importTx, slashTx, removeNonDefaultSubnetValidatorTx")
+	}
+}
+
+/*
+ ******************************************************
+ ********** Move $AVA to/from the X-Chain *************
+ ******************************************************
+ */
+
+// ExportAVAArgs are the arguments to ExportAVA
+type ExportAVAArgs struct {
+	// X-Chain address the exported $AVA is sent to
+	To ids.ShortID `json:"to"`
+
+	// Amount of $AVA to export
+	Amount json.Uint64 `json:"amount"`
+
+	// Next unused nonce of the account the $AVA and the tx fee are paid from
+	PayerNonce json.Uint64 `json:"payerNonce"`
+}
+
+// ExportAVAResponse is the response from a call to ExportAVA
+type ExportAVAResponse struct {
+	// The unsigned transaction
+	UnsignedTx formatting.CB58 `json:"unsignedTx"`
+}
+
+// ExportAVA returns an unsigned transaction that sends $AVA from an account to
+// [args.To] on the X-Chain. The $AVA must then be imported on the X-Chain.
+// The returned unsigned transaction should be signed using Sign()
+func (service *Service) ExportAVA(_ *http.Request, args *ExportAVAArgs, response *ExportAVAResponse) error {
+	service.vm.Ctx.Log.Debug("platform.exportAVA called")
+
+	if args.Amount == 0 {
+		return errors.New("amount must be positive")
+	}
+
+	tx := ExportTx{UnsignedExportTx: UnsignedExportTx{
+		NetworkID: service.vm.Ctx.NetworkID,
+		Nonce:     uint64(args.PayerNonce),
+		Outs: []*avm.TransferableOutput{&avm.TransferableOutput{
+			Asset: avm.Asset{ID: service.vm.AVA},
+			Out: &secp256k1fx.TransferOutput{
+				Amt: uint64(args.Amount),
+				OutputOwners: secp256k1fx.OutputOwners{
+					Threshold: 1,
+					Addrs:     []ids.ShortID{args.To},
+				},
+			},
+		}},
+	}}
+
+	txBytes, err := Codec.Marshal(genericTx{Tx: &tx})
+	if err != nil {
+		return fmt.Errorf("problem while creating transaction: %w", err)
+	}
+
+	response.UnsignedTx.Bytes = txBytes
+	return nil
+}
+
+// ImportAVAArgs are the arguments to ImportAVA
+type ImportAVAArgs struct {
+	// The account that receives the imported $AVA and pays the tx fee. Either
+	// an address or the name of an address in the user's address book.
+	To string `json:"to"`
+
+	// Next unused nonce of [To]
+	PayerNonce json.Uint64 `json:"payerNonce"`
+
+	// User that controls [To]
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// ImportAVAResponse is the response from a call to ImportAVA
+type ImportAVAResponse struct {
+	// The signed transaction
+	Tx formatting.CB58 `json:"tx"`
+}
+
+// ImportAVA returns a signed transaction that moves all the $AVA the X-Chain
+// sent to [args.To] into the account [args.To].
+// The returned transaction should be issued using IssueTx()
+func (service *Service) ImportAVA(_ *http.Request, args *ImportAVAArgs, response *ImportAVAResponse) error {
+	service.vm.Ctx.Log.Debug("platform.importAVA called")
+
+	to, err := service.parseAddress(args.Username, args.Password, args.To)
+	if err != nil {
+		return err
+	}
+
+	db, err := service.vm.Ctx.Keystore.GetDatabase(args.Username, args.Password)
+	if err != nil {
+		return errGetUser
+	}
+	user := user{db: db}
+
+	key, err := user.getKey(to)
+	if err != nil {
+		return errDB
 	}
+
+	addrs := ids.ShortSet{}
+	addrs.Add(to)
+
+	smDB := service.vm.Ctx.SharedMemory.GetDatabase(service.vm.AVM)
+	utxos, err := avm.NewAtomicUTXOs(smDB).List(service.vm.Ctx.ChainID, addrs)
+	service.vm.Ctx.SharedMemory.ReleaseDatabase(service.vm.AVM)
+	if err != nil {
+		return fmt.Errorf("problem retrieving the exported utxos: %w", err)
+	}
+
+	now := service.vm.clock.Unix()
+	ins := []*avm.TransferableInput{}
+	for _, utxo := range utxos {
+		out, ok := utxo.Out.(*secp256k1fx.TransferOutput)
+		if !ok || !utxo.AssetID().Equals(service.vm.AVA) || !canSpend(out, out.Amt, to, now) {
+			continue
+		}
+		ins = append(ins, &avm.TransferableInput{
+			UTXOID: utxo.UTXOID,
+			Asset:  avm.Asset{ID: service.vm.AVA},
+			In: &secp256k1fx.TransferInput{
+				Amt: out.Amt,
+				Input: secp256k1fx.Input{
+					SigIndices: []uint32{0},
+				},
+			},
+		})
+	}
+	if len(ins) == 0 {
+		return errors.New("no $AVA to import")
+	}
+
+	tx, err := service.vm.newImportTx(uint64(args.PayerNonce), service.vm.Ctx.NetworkID, ins, key)
+	if err != nil {
+		return fmt.Errorf("problem while creating transaction: %w", err)
+	}
+
+	txBytes, err := Codec.Marshal(genericTx{Tx: tx})
+	if err != nil {
+		return fmt.Errorf("problem while creating transaction: %w", err)
+	}
+
+	response.Tx.Bytes = txBytes
+	return nil
+}
+
+// parseAddress parses [addr]. If [addr] isn't an address, it's the name of an
+// address in the address book of the user [username].
+func (service *Service) parseAddress(username, password, addr string) (ids.ShortID, error) {
+	if address, err := ids.ShortFromString(addr); err == nil {
+		return address, nil
+	}
+	address, err := service.vm.Ctx.Keystore.ResolveAddress(username, password, addr)
+	if err != nil {
+		return ids.ShortID{}, fmt.Errorf("problem parsing address '%s': %w", addr, err)
+	}
+	return address, nil
 }
 
 /*
@@ -882,6 +1467,10 @@ type CreateBlockchainArgs struct {
 	// Human-readable name for the new blockchain, not necessarily unique
 	Name string `json:"name"`
 
+	// Consensus engine that runs the new blockchain: "avalanche", "snowman"
+	// or, if empty, the engine picked from the capabilities of the VM
+	Engine string `json:"engine"`
+
 	// To generate the byte representation of the genesis data for this blockchain,
 	// a POST request with body [GenesisData] is made to the API method whose name is [Method], whose
 	// endpoint is [Endpoint]. See Platform Chain documentation for more info and examples.
@@ -916,6 +1505,11 @@ func (service *Service) CreateBlockchain(_ *http.Request, args *CreateBlockchain
 		fxIDs = append(fxIDs, fxID)
 	}
 
+	engine, err := chains.ParseEngineType(args.Engine)
+	if err != nil {
+		return fmt.Errorf("problem parsing engine: %w", err)
+	}
+
 	genesisBytes := []byte(nil)
 	if args.Method != "" {
 		buf, err := json2.EncodeClientRequest(args.Method, args.GenesisData)
@@ -946,7 +1540,7 @@ func (service *Service) CreateBlockchain(_ *http.Request, args *CreateBlockchain
 
 	// TODO: Should use the key store to sign this transaction.
 	// TODO: Nonce shouldn't always be 0
-	tx, err := service.vm.newCreateChainTx(0, genesisBytes, vmID, fxIDs, args.Name, service.vm.Ctx.NetworkID, key)
+	tx, err := service.vm.newCreateChainTx(0, genesisBytes, vmID, fxIDs, engine, args.Name, service.vm.Ctx.NetworkID, key)
 	if err != nil {
 		return fmt.Errorf("problem creating transaction: %w", err)
 	}
@@ -955,7 +1549,7 @@ func (service *Service) CreateBlockchain(_ *http.Request, args *CreateBlockchain
 	service.vm.unissuedDecisionTxs = append(service.vm.unissuedDecisionTxs, tx)
 	service.vm.resetTimer()
 
-	reply.BlockchainID = tx.ID()
+	reply.BlockchainID = tx.ChainID()
 
 	return nil
 }
@@ -1021,10 +1615,117 @@ func (service *Service) chainExists(blockID ids.ID, chainID ids.ID) (bool, error
 
 	chains, err := service.vm.getChains(db)
 	for _, chain := range chains {
-		if chain.ID().Equals(chainID) {
+		if chain.ChainID().Equals(chainID) {
 			return true, nil
 		}
 	}
 
 	return false, nil
 }
+
+/*
+ ******************************************************
+ ******************** Governance **********************
+ ******************************************************
+ */
+
+// CreateGovernanceProposalArgs are the arguments to CreateGovernanceProposal
+type CreateGovernanceProposalArgs struct {
+	// Name of the parameter to change. One of: txFee, minimumStake, slashingPenalty
+	Parameter string `json:"parameter"`
+
+	// Value to change the parameter to
+	Value json.Uint64 `json:"value"`
+
+	// Unix time at which the parameter changes
+	ActivationTime json.Uint64 `json:"activationTime"`
+
+	// Next unused nonce of the account the tx fee is paid from
+	PayerNonce json.Uint64 `json:"payerNonce"`
+}
+
+// CreateGovernanceProposalResponse is the response from a call to CreateGovernanceProposal
+type CreateGovernanceProposalResponse struct {
+	// The unsigned transaction
+	UnsignedTx formatting.CB58 `json:"unsignedTx"`
+}
+
+// CreateGovernanceProposal returns an unsigned transaction that proposes
+// changing a chain parameter. Validators vote on the proposal; if it's
+// committed, the parameter changes once the chain's timestamp reaches
+// [args.ActivationTime].
+// The returned unsigned transaction should be signed using Sign()
+func (service *Service) CreateGovernanceProposal(_ *http.Request, args *CreateGovernanceProposalArgs, response *CreateGovernanceProposalResponse) error {
+	service.vm.Ctx.Log.Debug("platform.createGovernanceProposal called")
+
+	parameter, err := ParseGovernanceParameter(args.Parameter)
+	if err != nil {
+		return err
+	}
+	if err := parameter.Verify(uint64(args.Value)); err != nil {
+		return err
+	}
+
+	tx := governanceProposalTx{UnsignedGovernanceProposalTx: UnsignedGovernanceProposalTx{
+		NetworkID:      service.vm.Ctx.NetworkID,
+		Nonce:          uint64(args.PayerNonce),
+		Parameter:      parameter,
+		Value:          uint64(args.Value),
+		ActivationTime: uint64(args.ActivationTime),
+	}}
+
+	txBytes, err := Codec.Marshal(genericTx{Tx: &tx})
+	if err != nil {
+		return fmt.Errorf("problem while creating transaction: %w", err)
+	}
+
+	response.UnsignedTx.Bytes = txBytes
+	return nil
+}
+
+// APIProposal is a representation of a governance proposal used in API calls
+type APIProposal struct {
+	// ID of the transaction that made the proposal
+	ID ids.ID `json:"id"`
+
+	// Name of the parameter the proposal changes
+	Parameter string `json:"parameter"`
+
+	// Value the parameter changes to
+	Value json.Uint64 `json:"value"`
+
+	// Unix time at which the parameter changes
+	ActivationTime json.Uint64 `json:"activationTime"`
+}
+
+// GetPendingProposalsArgs are the arguments to GetPendingProposals
+type GetPendingProposalsArgs struct{}
+
+// GetPendingProposalsReply is the response from a call to GetPendingProposals
+type GetPendingProposalsReply struct {
+	// The committed proposals that haven't taken effect yet, sorted by
+	// activation time
+	Proposals []APIProposal `json:"proposals"`
+}
+
+// GetPendingProposals returns the governance proposals that have been
+// committed but haven't taken effect yet
+func (service *Service) GetPendingProposals(_ *http.Request, args *GetPendingProposalsArgs, reply *GetPendingProposalsReply) error {
+	service.vm.Ctx.Log.Debug("platform.getPendingProposals called")
+
+	proposals, err := service.vm.getPendingProposals(service.vm.DB)
+	if err != nil {
+		return fmt.Errorf("couldn't get pending proposals: %w", err)
+	}
+
+	reply.Proposals = make([]APIProposal, len(proposals))
+	for i, proposal := range proposals {
+		reply.Proposals[i] = APIProposal{
+			ID:             proposal.ID(),
+			Parameter:      proposal.Parameter.String(),
+			Value:          json.Uint64(proposal.Value),
+			ActivationTime: json.Uint64(proposal.ActivationTime),
+		}
+	}
+	return nil
+}
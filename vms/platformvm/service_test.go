@@ -6,6 +6,11 @@ package platformvm
 import (
 	"encoding/json"
 	"testing"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/crypto"
+	"github.com/ava-labs/gecko/utils/formatting"
+	"github.com/ava-labs/gecko/vms/components/verify"
 )
 
 func TestAddDefaultSubnetValidator(t *testing.T) {
@@ -32,3 +37,205 @@ func TestCreateBlockchainArgsParsing(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestIssueTxReportsErrorCode(t *testing.T) {
+	vm := defaultVM()
+	service := Service{vm: vm}
+
+	tx, err := vm.newCreateSubnetTx(
+		testNetworkID+1,
+		defaultNonce+1,
+		[]ids.ShortID{keys[0].PublicKey().Address()},
+		1,
+		keys[0],
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	txBytes, err := Codec.Marshal(genericTx{Tx: tx})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	args := IssueTxArgs{Tx: formatting.CB58{Bytes: txBytes}}
+	err = service.IssueTx(nil, &args, &IssueTxResponse{})
+	if err == nil {
+		t.Fatal("should have errored because the wrong network ID was used")
+	}
+	if code := verify.Code(err); code != CodeWrongNetworkID {
+		t.Fatalf("Expected error code %d, got %d", CodeWrongNetworkID, code)
+	}
+	if len(vm.unissuedDecisionTxs) != 0 {
+		t.Fatal("The malformed tx shouldn't have been queued")
+	}
+}
+
+func TestGetDelegators(t *testing.T) {
+	vm := defaultVM()
+	service := Service{vm: vm}
+
+	pending, err := vm.getPendingValidators(vm.DB, DefaultSubnetID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, key := range keys[1:3] {
+		tx, err := vm.newAddDefaultSubnetDelegatorTx(
+			defaultNonce+1,
+			MinimumStakeAmount,
+			uint64(defaultValidateStartTime.Unix())+uint64(i),
+			uint64(defaultValidateEndTime.Unix()),
+			key.PublicKey().Address(),
+			defaultKey.PublicKey().Address(),
+			testNetworkID,
+			defaultKey,
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pending.Add(tx)
+	}
+	if err := vm.putPendingValidators(vm.DB, pending, DefaultSubnetID); err != nil {
+		t.Fatal(err)
+	}
+
+	reply := GetDelegatorsReply{}
+	if err := service.GetDelegators(nil, &GetDelegatorsArgs{}, &reply); err != nil {
+		t.Fatal(err)
+	}
+	if len(reply.Current) != 0 {
+		t.Fatalf("Genesis validators shouldn't be reported as delegators")
+	}
+	if len(reply.Pending) != 2 {
+		t.Fatalf("Expected 2 pending delegators but got %d", len(reply.Pending))
+	}
+
+	nodeID := keys[2].PublicKey().Address()
+	reply = GetDelegatorsReply{}
+	if err := service.GetDelegators(nil, &GetDelegatorsArgs{NodeID: nodeID}, &reply); err != nil {
+		t.Fatal(err)
+	}
+	if len(reply.Pending) != 1 {
+		t.Fatalf("Expected 1 pending delegator but got %d", len(reply.Pending))
+	}
+	delegator := reply.Pending[0]
+	switch {
+	case !delegator.ID.Equals(nodeID):
+		t.Fatalf("Wrong node ID")
+	case !delegator.Destination.Equals(defaultKey.PublicKey().Address()):
+		t.Fatalf("Wrong destination")
+	case delegator.StakeAmount == nil || uint64(*delegator.StakeAmount) != MinimumStakeAmount:
+		t.Fatalf("Wrong stake amount")
+	case delegator.TxID.IsZero():
+		t.Fatalf("Missing tx ID")
+	}
+}
+
+func TestGetRewards(t *testing.T) {
+	vm := defaultVM()
+	service := Service{vm: vm}
+	txID := ids.NewID([32]byte{1})
+
+	if err := service.GetRewards(nil, &GetRewardsArgs{TxID: txID}, &GetRewardsReply{}); err == nil {
+		t.Fatalf("Should have errored because the staker hasn't been paid out")
+	}
+
+	account := keys[0].PublicKey().Address()
+	if err := vm.putPayouts(vm.DB, txID, payouts{{Account: account, Stake: 1, Reward: 2}}); err != nil {
+		t.Fatal(err)
+	}
+
+	reply := GetRewardsReply{}
+	if err := service.GetRewards(nil, &GetRewardsArgs{TxID: txID}, &reply); err != nil {
+		t.Fatal(err)
+	}
+	if len(reply.Payouts) != 1 {
+		t.Fatalf("Expected 1 payout but got %d", len(reply.Payouts))
+	}
+	if payout := reply.Payouts[0]; !payout.Address.Equals(account) || payout.Stake != 1 || payout.Reward != 2 {
+		t.Fatalf("Wrong payout: %+v", payout)
+	}
+}
+
+func TestGetCurrentValidatorsPagination(t *testing.T) {
+	vm := defaultVM()
+	service := Service{vm: vm}
+
+	seen := ids.ShortSet{}
+	args := GetCurrentValidatorsArgs{Limit: 2}
+	for {
+		reply := GetCurrentValidatorsReply{}
+		if err := service.GetCurrentValidators(nil, &args, &reply); err != nil {
+			t.Fatal(err)
+		}
+		for _, vdr := range reply.Validators {
+			if seen.Contains(vdr.ID) {
+				t.Fatalf("Validator %s was returned twice", vdr.ID)
+			}
+			seen.Add(vdr.ID)
+			if vdr.StakeAmount == nil || uint64(*vdr.StakeAmount) != defaultStakeAmount {
+				t.Fatalf("Wrong stake amount")
+			}
+			if vdr.DelegationFeeRate == nil {
+				t.Fatalf("Should have returned the delegation fee rate")
+			}
+		}
+		if len(reply.Validators) < 2 {
+			break
+		}
+		args.StartIndex = reply.EndIndex
+	}
+	if seen.Len() != len(keys) {
+		t.Fatalf("Expected %d validators but got %d", len(keys), seen.Len())
+	}
+}
+
+func TestGetPendingValidatorsLimitTooLarge(t *testing.T) {
+	vm := defaultVM()
+	service := Service{vm: vm}
+
+	args := GetPendingValidatorsArgs{Limit: maxValidatorsLimit + 1}
+	if err := service.GetPendingValidators(nil, &args, &GetPendingValidatorsReply{}); err != errValidatorsLimitTooLarge {
+		t.Fatalf("Should have errored with %s, errored with %v", errValidatorsLimitTooLarge, err)
+	}
+}
+
+func TestGetValidatedSubnets(t *testing.T) {
+	vm := defaultVM()
+	service := Service{vm: vm}
+	nodeID := keys[0].PublicKey().Address()
+
+	addTx, err := vm.newAddNonDefaultSubnetValidatorTx(
+		defaultNonce+1,
+		defaultWeight,
+		uint64(defaultValidateStartTime.Unix()),
+		uint64(defaultValidateEndTime.Unix()),
+		nodeID,
+		testSubnet1.ID,
+		testNetworkID,
+		[]*crypto.PrivateKeySECP256K1R{testSubnet1ControlKeys[0], testSubnet1ControlKeys[1]},
+		defaultKey,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := vm.putCurrentValidators(vm.DB, &EventHeap{Txs: []TimedTx{addTx}}, testSubnet1.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	reply := GetValidatedSubnetsReply{}
+	if err := service.GetValidatedSubnets(nil, &GetValidatedSubnetsArgs{NodeID: nodeID}, &reply); err != nil {
+		t.Fatal(err)
+	}
+	if len(reply.SubnetIDs) != 2 || !reply.SubnetIDs[0].Equals(DefaultSubnetID) || !reply.SubnetIDs[1].Equals(testSubnet1.ID) {
+		t.Fatalf("Expected the default subnet and %s but got %v", testSubnet1.ID, reply.SubnetIDs)
+	}
+
+	reply = GetValidatedSubnetsReply{}
+	args := GetValidatedSubnetsArgs{NodeID: keys[1].PublicKey().Address()}
+	if err := service.GetValidatedSubnets(nil, &args, &reply); err != nil {
+		t.Fatal(err)
+	}
+	if len(reply.SubnetIDs) != 1 || !reply.SubnetIDs[0].Equals(DefaultSubnetID) {
+		t.Fatalf("Expected only the default subnet but got %v", reply.SubnetIDs)
+	}
+}
@@ -0,0 +1,192 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"github.com/ava-labs/gecko/database"
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/crypto"
+	"github.com/ava-labs/gecko/utils/hashing"
+	"github.com/ava-labs/gecko/vms/components/verify"
+)
+
+var (
+	errAlreadySlashed = verify.NewError(CodeAlreadySlashed, "validator has already been slashed")
+)
+
+// UnsignedSlashTx is an unsigned slashTx
+type UnsignedSlashTx struct {
+	// NetworkID is the ID of the network this tx was issued on
+	NetworkID uint32 `serialize:"true"`
+
+	// Next unused nonce of account paying the transaction fee for this transaction.
+	Nonce uint64 `serialize:"true"`
+
+	// Proof that a default subnet validator misbehaved
+	Evidence Evidence `serialize:"true"`
+}
+
+// slashTx is a transaction that submits evidence that a default subnet
+// validator misbehaved. If it's accepted, the validator is slashed: when it
+// stops validating, it's penalized according to the slashing penalty in effect
+// when this tx was accepted. The transaction fee will be paid from the account
+// who signed the transaction.
+type slashTx struct {
+	UnsignedSlashTx `serialize:"true"`
+
+	// Sig is the signature of the public key whose corresponding account pays
+	// the tx fee for this tx. ie the account with ID == [public key].Address()
+	// pays the tx fee
+	Sig [crypto.SECP256K1RSigLen]byte `serialize:"true"`
+
+	vm       *VM
+	id       ids.ID
+	senderID ids.ShortID
+
+	// ID of the node the evidence proves misbehaved
+	nodeID ids.ShortID
+
+	// Byte representation of the signed transaction
+	bytes []byte
+}
+
+// initialize [tx]
+func (tx *slashTx) initialize(vm *VM) error {
+	tx.vm = vm
+	bytes, err := Codec.Marshal(tx) // byte representation of the signed transaction
+	tx.bytes = bytes
+	tx.id = ids.NewID(hashing.ComputeHash256Array(bytes))
+	return err
+}
+
+func (tx *slashTx) ID() ids.ID { return tx.id }
+
+// NodeID returns the ID of the node this tx slashes
+func (tx *slashTx) NodeID() ids.ShortID { return tx.nodeID }
+
+// SyntacticVerify return nil iff [tx] is valid
+// If [tx] is valid, sets [tx.senderID] and [tx.nodeID]
+func (tx *slashTx) SyntacticVerify() error {
+	switch {
+	case tx == nil:
+		return errNilTx
+	case !tx.senderID.IsZero():
+		return nil // Only verify the transaction once
+	case tx.id.IsZero():
+		return errInvalidID
+	case tx.NetworkID != tx.vm.Ctx.NetworkID:
+		return errWrongNetworkID
+	case tx.Evidence == nil:
+		return errNilEvidence
+	}
+
+	nodeID, err := tx.Evidence.Verify(tx.vm)
+	if err != nil {
+		return err
+	}
+
+	unsignedIntf := interface{}(&tx.UnsignedSlashTx)
+	// Byte representation of the unsigned transaction
+	unsignedBytes, err := Codec.Marshal(&unsignedIntf)
+	if err != nil {
+		return err
+	}
+
+	// get account to pay tx fee from
+	key, err := tx.vm.factory.RecoverPublicKey(unsignedBytes, tx.Sig[:])
+	if err != nil {
+		return err
+	}
+	tx.senderID = key.Address()
+	tx.nodeID = nodeID
+
+	return nil
+}
+
+// SemanticVerify this transaction is valid.
+// The misbehaving node must currently be validating the default subnet and
+// must not have been slashed already.
+func (tx *slashTx) SemanticVerify(db database.Database) (func(), error) {
+	if err := tx.SyntacticVerify(); err != nil {
+		return nil, err
+	}
+
+	currentValidators, err := tx.vm.getCurrentValidators(db, DefaultSubnetID)
+	if err != nil {
+		return nil, errDBCurrentValidators
+	}
+	validator, err := currentValidators.getDefaultSubnetStaker(tx.nodeID)
+	if err != nil {
+		return nil, errShouldBeDSValidator
+	}
+	if _, slashed, err := tx.vm.getSlashing(db, validator.ID()); err != nil {
+		return nil, err
+	} else if slashed {
+		return nil, errAlreadySlashed
+	}
+
+	params, err := tx.vm.getGovernanceParameters(db)
+	if err != nil {
+		return nil, err
+	}
+
+	// Deduct tx fee from payer's account
+	account, err := tx.vm.getAccount(db, tx.senderID)
+	if err != nil {
+		return nil, errDBAccount
+	}
+	account, err = account.RemoveWithFee(0, params.TxFee, tx.Nonce)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.vm.putAccount(db, account); err != nil {
+		return nil, errDBPutAccount
+	}
+
+	if err := tx.vm.putSlashing(db, validator.ID(), SlashingPenalty(params.SlashingPenalty)); err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
+
+func (vm *VM) newSlashTx(
+	nonce uint64,
+	evidence Evidence,
+	networkID uint32,
+	key *crypto.PrivateKeySECP256K1R,
+) (*slashTx, error) {
+	tx := &slashTx{
+		UnsignedSlashTx: UnsignedSlashTx{
+			NetworkID: networkID,
+			Nonce:     nonce,
+			Evidence:  evidence,
+		},
+	}
+
+	unsignedIntf := interface{}(&tx.UnsignedSlashTx)
+	unsignedBytes, err := Codec.Marshal(&unsignedIntf) // byte repr. of unsigned tx
+	if err != nil {
+		return nil, err
+	}
+
+	sig, err := key.Sign(unsignedBytes)
+	if err != nil {
+		return nil, err
+	}
+	copy(tx.Sig[:], sig)
+
+	return tx, tx.initialize(vm)
+}
+
+// slashing is the penalty imposed on a validator that was proven to have
+// misbehaved
+type slashing struct {
+	Penalty SlashingPenalty `serialize:"true"`
+}
+
+// Bytes returns the byte representation of [s]
+func (s *slashing) Bytes() []byte {
+	bytes, _ := Codec.Marshal(s)
+	return bytes
+}
@@ -0,0 +1,264 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"container/heap"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/ava-labs/gecko/database/versiondb"
+	"github.com/ava-labs/gecko/ids"
+)
+
+var (
+	errTestEvidenceInvalid = errors.New("evidence doesn't prove misbehavior")
+
+	registerTestEvidence sync.Once
+)
+
+// testEvidence proves that [NodeID] misbehaved iff [Valid] is true
+type testEvidence struct {
+	NodeID ids.ShortID `serialize:"true"`
+	Valid  bool        `serialize:"true"`
+}
+
+func (e *testEvidence) Verify(*VM) (ids.ShortID, error) {
+	switch {
+	case e == nil:
+		return ids.ShortID{}, errNilEvidence
+	case !e.Valid:
+		return ids.ShortID{}, errTestEvidenceInvalid
+	}
+	return e.NodeID, nil
+}
+
+// newTestEvidence returns evidence that [nodeID] misbehaved
+func newTestEvidence(t *testing.T, nodeID ids.ShortID) *testEvidence {
+	// No kind of evidence is registered with Codec, so the test's own kind is
+	// registered after all of the VM's types
+	registerTestEvidence.Do(func() {
+		if err := Codec.RegisterType(&testEvidence{}); err != nil {
+			t.Fatal(err)
+		}
+	})
+	return &testEvidence{NodeID: nodeID, Valid: true}
+}
+
+// addStaker makes [nodeID] a validator of the default subnet of [vm]
+func addStaker(t *testing.T, vm *VM, nodeID ids.ShortID) {
+	validator, err := vm.newAddDefaultSubnetValidatorTx(
+		defaultNonce,
+		defaultStakeAmount,
+		uint64(defaultValidateStartTime.Unix()),
+		uint64(defaultValidateEndTime.Unix()),
+		nodeID,
+		keys[0].PublicKey().Address(),
+		NumberOfShares,
+		testNetworkID,
+		keys[0],
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	currentValidators, err := vm.getCurrentValidators(vm.DB, DefaultSubnetID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	heap.Push(currentValidators, validator)
+	if err := vm.putCurrentValidators(vm.DB, currentValidators, DefaultSubnetID); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSlashTxSyntacticVerify(t *testing.T) {
+	vm := defaultVM()
+
+	nodeID := ids.NewShortID([20]byte{1})
+
+	var tx *slashTx
+	if err := tx.SyntacticVerify(); err != errNilTx {
+		t.Fatalf("Expected %s but got %v", errNilTx, err)
+	}
+
+	tx, err := vm.newSlashTx(defaultNonce+1, newTestEvidence(t, nodeID), testNetworkID+1, keys[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.SyntacticVerify(); err != errWrongNetworkID {
+		t.Fatalf("Expected %s but got %v", errWrongNetworkID, err)
+	}
+
+	evidence := newTestEvidence(t, nodeID)
+	evidence.Valid = false
+	tx, err = vm.newSlashTx(defaultNonce+1, evidence, testNetworkID, keys[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.SyntacticVerify(); err != errTestEvidenceInvalid {
+		t.Fatalf("Expected %s but got %v", errTestEvidenceInvalid, err)
+	}
+
+	tx, err = vm.newSlashTx(defaultNonce+1, newTestEvidence(t, nodeID), testNetworkID, keys[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.SyntacticVerify(); err != nil {
+		t.Fatal(err)
+	}
+	switch {
+	case !tx.senderID.Equals(keys[1].PublicKey().Address()):
+		t.Fatalf("The tx fee should be paid by %s", keys[1].PublicKey().Address())
+	case !tx.NodeID().Equals(nodeID):
+		t.Fatalf("The tx should slash %s", nodeID)
+	}
+}
+
+func TestSlashTxSerialization(t *testing.T) {
+	vm := defaultVM()
+	nodeID := ids.NewShortID([20]byte{1})
+	tx, err := vm.newSlashTx(defaultNonce+1, newTestEvidence(t, nodeID), testNetworkID, keys[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	txBytes, err := Codec.Marshal(genericTx{Tx: tx})
+	if err != nil {
+		t.Fatal(err)
+	}
+	genTx := genericTx{}
+	if err := Codec.Unmarshal(txBytes, &genTx); err != nil {
+		t.Fatal(err)
+	}
+	parsedTx, ok := genTx.Tx.(*slashTx)
+	if !ok {
+		t.Fatalf("Expected *slashTx but got %T", genTx.Tx)
+	}
+	if err := parsedTx.initialize(vm); err != nil {
+		t.Fatal(err)
+	}
+	if !parsedTx.ID().Equals(tx.ID()) {
+		t.Fatalf("Expected tx %s but got %s", tx.ID(), parsedTx.ID())
+	}
+}
+
+func TestSlashTxSemanticVerify(t *testing.T) {
+	vm := defaultVM()
+	nodeID := ids.NewShortID([20]byte{1})
+	addStaker(t, vm, nodeID)
+	currentValidators, err := vm.getCurrentValidators(vm.DB, DefaultSubnetID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	validator, err := currentValidators.getDefaultSubnetStaker(nodeID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tx, err := vm.newSlashTx(defaultNonce+1, newTestEvidence(t, nodeID), testNetworkID, keys[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	db := versiondb.New(vm.DB)
+	if _, err := tx.SemanticVerify(db); err != nil {
+		t.Fatal(err)
+	}
+
+	penalty, slashed, err := vm.getSlashing(db, validator.ID())
+	switch {
+	case err != nil:
+		t.Fatal(err)
+	case !slashed:
+		t.Fatalf("The validator should have been slashed")
+	case penalty != ForfeitRewardPenalty:
+		t.Fatalf("Expected the default penalty but got %d", penalty)
+	}
+
+	account, err := vm.getAccount(db, keys[1].PublicKey().Address())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if account.Balance != defaultBalance-txFee {
+		t.Fatalf("The reporter should have paid the tx fee")
+	}
+
+	tx, err = vm.newSlashTx(defaultNonce+2, newTestEvidence(t, nodeID), testNetworkID, keys[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tx.SemanticVerify(db); err != errAlreadySlashed {
+		t.Fatalf("Expected %s but got %v", errAlreadySlashed, err)
+	}
+}
+
+func TestSlashTxSemanticVerifyNotValidator(t *testing.T) {
+	vm := defaultVM()
+	nodeID := ids.NewShortID([20]byte{1})
+
+	tx, err := vm.newSlashTx(defaultNonce+1, newTestEvidence(t, nodeID), testNetworkID, keys[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tx.SemanticVerify(vm.DB); err != errShouldBeDSValidator {
+		t.Fatalf("Expected %s but got %v", errShouldBeDSValidator, err)
+	}
+}
+
+func TestRewardSlashedValidator(t *testing.T) {
+	vm := defaultVM()
+	currentValidators, err := vm.getCurrentValidators(vm.DB, DefaultSubnetID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	nextToRemove := currentValidators.Peek().(*addDefaultSubnetValidatorTx)
+	if err := vm.putTimestamp(vm.DB, defaultValidateEndTime); err != nil {
+		t.Fatal(err)
+	}
+	account, err := vm.getAccount(vm.DB, nextToRemove.Destination)
+	if err != nil {
+		t.Fatal(err)
+	}
+	balance := account.Balance
+
+	// The validator forfeits its reward but gets its stake back
+	if err := vm.putSlashing(vm.DB, nextToRemove.ID(), ForfeitRewardPenalty); err != nil {
+		t.Fatal(err)
+	}
+	tx, err := vm.newRewardValidatorTx(nextToRemove.ID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	onCommitDB, onAbortDB, _, _, err := tx.SemanticVerify(vm.DB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, db := range []*versiondb.Database{onCommitDB, onAbortDB} {
+		account, err := vm.getAccount(db, nextToRemove.Destination)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if account.Balance != balance+nextToRemove.Wght {
+			t.Fatalf("Expected balance %d but got %d", balance+nextToRemove.Wght, account.Balance)
+		}
+	}
+
+	// The validator loses its stake
+	if err := vm.putSlashing(vm.DB, nextToRemove.ID(), BurnStakePenalty); err != nil {
+		t.Fatal(err)
+	}
+	onCommitDB, onAbortDB, _, _, err = tx.SemanticVerify(vm.DB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, db := range []*versiondb.Database{onCommitDB, onAbortDB} {
+		account, err := vm.getAccount(db, nextToRemove.Destination)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if account.Balance != balance {
+			t.Fatalf("Expected balance %d but got %d", balance, account.Balance)
+		}
+	}
+}
@@ -4,6 +4,8 @@
 package platformvm
 
 import (
+	"context"
+
 	"github.com/ava-labs/gecko/database"
 	"github.com/ava-labs/gecko/database/versiondb"
 	"github.com/ava-labs/gecko/ids"
@@ -46,7 +48,10 @@ func (sb *StandardBlock) initialize(vm *VM, bytes []byte) error {
 // The parent block must be a proposal
 //
 // This function also sets onAcceptDB database if the verification passes.
-func (sb *StandardBlock) Verify() error {
+func (sb *StandardBlock) Verify(ctx context.Context) error { return sb.verify(ctx) }
+
+// verify this block, giving up once [ctx] is done
+func (sb *StandardBlock) verify(ctx context.Context) error {
 	// StandardBlock is not a modifier on a proposal block, so its parent must
 	// be a decision.
 	parent, ok := sb.parentBlock().(decision)
@@ -59,6 +64,9 @@ func (sb *StandardBlock) Verify() error {
 	sb.onAcceptDB = versiondb.New(pdb)
 	funcs := []func(){}
 	for _, tx := range sb.Txs {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 		onAccept, err := tx.SemanticVerify(sb.onAcceptDB)
 		if err != nil {
 			return err
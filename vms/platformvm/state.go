@@ -23,6 +23,10 @@ var (
 const (
 	currentValidatorsPrefix uint64 = iota
 	pendingValidatorsPrefix
+	epochValidatorsPrefix
+	importedUTXOsPrefix
+	slashingsPrefix
+	payoutsPrefix
 )
 
 // get the validators currently validating the specified subnet
@@ -103,6 +107,48 @@ func (vm *VM) putPendingValidators(db database.Database, validators *EventHeap,
 	return nil
 }
 
+// get the validators of the specified subnet as of the start of the current
+// epoch
+func (vm *VM) getEpochValidators(db database.Database, subnetID ids.ID) (*EventHeap, error) {
+	// if epoch validators aren't specified in database, return empty validator set
+	key := subnetID.Prefix(epochValidatorsPrefix)
+	has, err := vm.State.Has(db, validatorsTypeID, key)
+	if err != nil {
+		return nil, err
+	}
+	if !has {
+		return &EventHeap{
+			SortByStartTime: false,
+			Txs:             make([]TimedTx, 0),
+		}, nil
+	}
+	epochValidatorsInterface, err := vm.State.Get(db, validatorsTypeID, key)
+	if err != nil {
+		return nil, errDBEpochValidators
+	}
+	epochValidators, ok := epochValidatorsInterface.(*EventHeap)
+	if !ok {
+		vm.Ctx.Log.Error("expected to retrieve *EventHeap from database but got different type")
+		return nil, errDBEpochValidators
+	}
+	for _, validator := range epochValidators.Txs {
+		if err := validator.initialize(vm); err != nil {
+			return nil, err
+		}
+	}
+	return epochValidators, nil
+}
+
+// put the validators of the specified subnet as of the start of the current
+// epoch
+func (vm *VM) putEpochValidators(db database.Database, validators *EventHeap, subnetID ids.ID) error {
+	err := vm.State.Put(db, validatorsTypeID, subnetID.Prefix(epochValidatorsPrefix), validators)
+	if err != nil {
+		return errDBPutEpochValidators
+	}
+	return nil
+}
+
 // get the account with the specified Address
 // If account does not exist in database, return new account
 func (vm *VM) getAccount(db database.Database, address ids.ShortID) (Account, error) {
@@ -210,6 +256,111 @@ func (vm *VM) getSubnets(db database.Database) ([]*CreateSubnetTx, error) {
 	return subnets, nil
 }
 
+// get the chain parameters set by governance proposals from [db]
+// If no proposal has ever been activated, returns the default parameters
+func (vm *VM) getGovernanceParameters(db database.Database) (*GovernanceParameters, error) {
+	has, err := vm.State.Has(db, governanceParametersTypeID, governanceParametersKey)
+	if err != nil {
+		return nil, err
+	}
+	if !has {
+		return DefaultGovernanceParameters(), nil
+	}
+	paramsIntf, err := vm.State.Get(db, governanceParametersTypeID, governanceParametersKey)
+	if err != nil {
+		return nil, err
+	}
+	params, ok := paramsIntf.(*GovernanceParameters)
+	if !ok {
+		vm.Ctx.Log.Warn("expected to retrieve *GovernanceParameters from database but got different type")
+		return nil, errDB
+	}
+	return params, nil
+}
+
+// put the chain parameters set by governance proposals in [db]
+func (vm *VM) putGovernanceParameters(db database.Database, params *GovernanceParameters) error {
+	return vm.State.Put(db, governanceParametersTypeID, governanceParametersKey, params)
+}
+
+// get the committed governance proposals that haven't been activated yet,
+// sorted by activation time
+func (vm *VM) getPendingProposals(db database.Database) ([]*governanceProposalTx, error) {
+	has, err := vm.State.Has(db, proposalsTypeID, proposalsKey)
+	if err != nil {
+		return nil, err
+	}
+	if !has {
+		return nil, nil
+	}
+	proposalsIntf, err := vm.State.Get(db, proposalsTypeID, proposalsKey)
+	if err != nil {
+		return nil, err
+	}
+	proposals, ok := proposalsIntf.([]*governanceProposalTx)
+	if !ok {
+		vm.Ctx.Log.Warn("expected to retrieve []*governanceProposalTx from database but got different type")
+		return nil, errDB
+	}
+	return proposals, nil
+}
+
+// put the committed governance proposals that haven't been activated yet in [db]
+func (vm *VM) putPendingProposals(db database.Database, proposals governanceProposalList) error {
+	return vm.State.Put(db, proposalsTypeID, proposalsKey, proposals)
+}
+
+// get the penalty imposed on the staker added by tx [stakerTxID]
+// Returns false if the staker hasn't been slashed
+func (vm *VM) getSlashing(db database.Database, stakerTxID ids.ID) (SlashingPenalty, bool, error) {
+	key := stakerTxID.Prefix(slashingsPrefix)
+	has, err := vm.State.Has(db, slashingTypeID, key)
+	if err != nil || !has {
+		return 0, false, err
+	}
+	slashingIntf, err := vm.State.Get(db, slashingTypeID, key)
+	if err != nil {
+		return 0, false, err
+	}
+	slashing, ok := slashingIntf.(*slashing)
+	if !ok {
+		vm.Ctx.Log.Warn("expected to retrieve *slashing from database but got different type")
+		return 0, false, errDB
+	}
+	return slashing.Penalty, true, nil
+}
+
+// put the penalty imposed on the staker added by tx [stakerTxID] in [db]
+func (vm *VM) putSlashing(db database.Database, stakerTxID ids.ID, penalty SlashingPenalty) error {
+	return vm.State.Put(db, slashingTypeID, stakerTxID.Prefix(slashingsPrefix), &slashing{Penalty: penalty})
+}
+
+// get the payouts made when the staker added by tx [stakerTxID] stopped staking
+// Returns false if the staker hasn't stopped staking
+func (vm *VM) getPayouts(db database.Database, stakerTxID ids.ID) (payouts, bool, error) {
+	key := stakerTxID.Prefix(payoutsPrefix)
+	has, err := vm.State.Has(db, payoutsTypeID, key)
+	if err != nil || !has {
+		return nil, false, err
+	}
+	payoutsIntf, err := vm.State.Get(db, payoutsTypeID, key)
+	if err != nil {
+		return nil, false, err
+	}
+	p, ok := payoutsIntf.(payouts)
+	if !ok {
+		vm.Ctx.Log.Warn("expected to retrieve payouts from database but got different type")
+		return nil, false, errDB
+	}
+	return p, true, nil
+}
+
+// put the payouts made when the staker added by tx [stakerTxID] stopped staking
+// in [db]
+func (vm *VM) putPayouts(db database.Database, stakerTxID ids.ID, p payouts) error {
+	return vm.State.Put(db, payoutsTypeID, stakerTxID.Prefix(payoutsPrefix), p)
+}
+
 // get the subnet with the specified ID
 func (vm *VM) getSubnet(db database.Database, ID ids.ID) (*CreateSubnetTx, error) {
 	subnets, err := vm.getSubnets(db)
@@ -258,7 +409,15 @@ func (vm *VM) registerDBTypes() {
 	unmarshalChainsFunc := func(bytes []byte) (interface{}, error) {
 		var chains []*CreateChainTx
 		if err := Codec.Unmarshal(bytes, &chains); err != nil {
-			return nil, err
+			// The chains may have been stored before they named their engine
+			var preEngineChains []*preEngineCreateChainTx
+			if Codec.Unmarshal(bytes, &preEngineChains) != nil {
+				return nil, err
+			}
+			chains = make([]*CreateChainTx, len(preEngineChains))
+			for i, chain := range preEngineChains {
+				chains[i] = chain.createChainTx()
+			}
 		}
 		for _, chain := range chains {
 			if err := chain.initialize(vm); err != nil {
@@ -286,6 +445,66 @@ func (vm *VM) registerDBTypes() {
 	if err := vm.State.RegisterType(subnetsTypeID, unmarshalSubnetsFunc); err != nil {
 		vm.Ctx.Log.Warn(errRegisteringType.Error())
 	}
+
+	unmarshalGovernanceParametersFunc := func(bytes []byte) (interface{}, error) {
+		params := &GovernanceParameters{}
+		if err := Codec.Unmarshal(bytes, params); err != nil {
+			return nil, err
+		}
+		return params, nil
+	}
+	if err := vm.State.RegisterType(governanceParametersTypeID, unmarshalGovernanceParametersFunc); err != nil {
+		vm.Ctx.Log.Warn("%s: %s", errRegisteringType, err)
+	}
+
+	unmarshalProposalsFunc := func(bytes []byte) (interface{}, error) {
+		var proposals []*governanceProposalTx
+		if err := Codec.Unmarshal(bytes, &proposals); err != nil {
+			return nil, err
+		}
+		for _, proposal := range proposals {
+			if err := proposal.initialize(vm); err != nil {
+				return nil, err
+			}
+		}
+		return proposals, nil
+	}
+	if err := vm.State.RegisterType(proposalsTypeID, unmarshalProposalsFunc); err != nil {
+		vm.Ctx.Log.Warn("%s: %s", errRegisteringType, err)
+	}
+
+	unmarshalSlashingFunc := func(bytes []byte) (interface{}, error) {
+		slashing := &slashing{}
+		if err := Codec.Unmarshal(bytes, slashing); err != nil {
+			return nil, err
+		}
+		return slashing, nil
+	}
+	if err := vm.State.RegisterType(slashingTypeID, unmarshalSlashingFunc); err != nil {
+		vm.Ctx.Log.Warn("%s: %s", errRegisteringType, err)
+	}
+
+	unmarshalPayoutsFunc := func(bytes []byte) (interface{}, error) {
+		p := payouts{}
+		if err := Codec.Unmarshal(bytes, &p); err != nil {
+			return nil, err
+		}
+		return p, nil
+	}
+	if err := vm.State.RegisterType(payoutsTypeID, unmarshalPayoutsFunc); err != nil {
+		vm.Ctx.Log.Warn("%s: %s", errRegisteringType, err)
+	}
+
+	unmarshalStateSummaryFunc := func(bytes []byte) (interface{}, error) {
+		summary := &stateSummary{}
+		if err := Codec.Unmarshal(bytes, summary); err != nil {
+			return nil, err
+		}
+		return summary, nil
+	}
+	if err := vm.State.RegisterType(stateSummaryTypeID, unmarshalStateSummaryFunc); err != nil {
+		vm.Ctx.Log.Warn("%s: %s", errRegisteringType, err)
+	}
 }
 
 // Unmarshal a Block from bytes and initialize it
@@ -0,0 +1,269 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ava-labs/gecko/database"
+	"github.com/ava-labs/gecko/database/versiondb"
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow/choices"
+	"github.com/ava-labs/gecko/utils/hashing"
+	"github.com/ava-labs/gecko/vms/components/state"
+)
+
+// A new node can sync the state of the Platform Chain from a state summary,
+// rather than replaying every block since genesis. The state is summarized
+// when a decision block that moves the chain time into a new epoch is
+// accepted. Every node accepts the same such blocks, so the nodes that are at
+// least as recent as a summary return identical summaries.
+//
+// A summary holds every key/value pair of the chain's state and the block the
+// state is as of. The blocks in the database, and their statuses, depend on
+// which blocks the node has seen, so they aren't part of the chain's state.
+
+var (
+	errNoStateSummary     = errors.New("the state hasn't been summarized yet")
+	errSummaryNotDecision = errors.New("the block of a state summary must be a decision block")
+	errSummaryBadState    = errors.New("the state of a state summary must have unique keys and no blocks")
+)
+
+// stateEntry is a key/value pair of the chain's state
+type stateEntry struct {
+	Key   []byte `serialize:"true"`
+	Value []byte `serialize:"true"`
+}
+
+// stateSummary is the state of the chain as of an accepted decision block
+type stateSummary struct {
+	// The block the state is as of
+	Block []byte `serialize:"true"`
+
+	// The chain's state, sorted by key
+	State []stateEntry `serialize:"true"`
+}
+
+// Bytes returns the byte representation of [s]
+func (s *stateSummary) Bytes() []byte {
+	bytes, _ := Codec.Marshal(s)
+	return bytes
+}
+
+// StateSummary returns the summary of the state as of the last decision block
+// that moved the chain time into a new epoch
+func (vm *VM) StateSummary(ctx context.Context) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	summaryIntf, err := vm.State.Get(vm.DB, stateSummaryTypeID, stateSummaryKey)
+	if err == database.ErrNotFound {
+		return nil, errNoStateSummary
+	} else if err != nil {
+		return nil, err
+	}
+	summary, ok := summaryIntf.(*stateSummary)
+	if !ok {
+		return nil, errDB
+	}
+	return Codec.Marshal(summary)
+}
+
+// SyncState replaces the chain's state with the state in [summaryBytes] and
+// makes the summary's block the last accepted block. If this node's chain time
+// is already in the summary's epoch, this node has already accepted the
+// summary's block, so the state isn't changed.
+func (vm *VM) SyncState(ctx context.Context, summaryBytes []byte) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	summary := &stateSummary{}
+	if err := Codec.Unmarshal(summaryBytes, summary); err != nil {
+		return err
+	}
+	blk, err := vm.unmarshalBlockFunc(summary.Block)
+	if err != nil {
+		return err
+	}
+	var commonBlk *CommonBlock
+	switch blk := blk.(type) {
+	case *Commit:
+		commonBlk = &blk.CommonBlock
+	case *Abort:
+		commonBlk = &blk.CommonBlock
+	case *StandardBlock:
+		commonBlk = &blk.CommonBlock
+	default:
+		return errSummaryNotDecision
+	}
+
+	prevTimestamp, err := vm.getTimestamp(vm.DB)
+	if err != nil {
+		return err
+	}
+	prevChains, err := vm.getChains(vm.DB)
+	if err != nil {
+		return err
+	}
+
+	// Replace the chain's state with the summarized state
+	db := versiondb.New(vm.DB)
+	prevState, err := vm.stateEntries(db)
+	if err != nil {
+		return err
+	}
+	for _, entry := range prevState {
+		if err := db.Delete(entry.Key); err != nil {
+			return err
+		}
+	}
+	for _, entry := range summary.State {
+		if err := db.Put(entry.Key, entry.Value); err != nil {
+			return err
+		}
+	}
+
+	// Summarizing the state drops the chain's blocks, so a summary that holds a
+	// block, or holds a key twice, wasn't taken by a correct node
+	if entries, err := vm.stateEntries(db); err != nil {
+		return err
+	} else if len(entries) != len(summary.State) {
+		return errSummaryBadState
+	}
+
+	timestamp, err := vm.getTimestamp(db)
+	if err != nil {
+		return err
+	}
+	if epoch(timestamp) <= epoch(prevTimestamp) {
+		vm.Ctx.Log.Info("State sync skipped as the chain time is already in the epoch of block %s", blk.ID())
+		return nil
+	}
+
+	if err := vm.State.PutBlock(db, blk); err != nil {
+		return err
+	}
+	if err := vm.State.PutStatus(db, blk.ID(), choices.Accepted); err != nil {
+		return err
+	}
+	if err := vm.State.PutLastAccepted(db, blk.ID()); err != nil {
+		return err
+	}
+	// This node can now serve the summary to other nodes
+	if err := vm.State.Put(db, stateSummaryTypeID, stateSummaryKey, summary); err != nil {
+		return err
+	}
+	if err := db.Commit(); err != nil {
+		return err
+	}
+	if err := vm.DB.Commit(); err != nil {
+		vm.DB.Abort()
+		return err
+	}
+
+	// Build off of the summary's block
+	commonBlk.Block.Accept(ctx)
+	if err := vm.DB.Commit(); err != nil {
+		return err
+	}
+	vm.SetPreference(blk.ID())
+
+	if err := vm.updateAllValidators(); err != nil {
+		return err
+	}
+
+	// Create the chains that were created before the summary's block
+	chains, err := vm.getChains(vm.DB)
+	if err != nil {
+		return err
+	}
+	prevChainIDs := ids.Set{}
+	for _, chain := range prevChains {
+		prevChainIDs.Add(chain.ChainID())
+	}
+	for _, chain := range chains {
+		if !prevChainIDs.Contains(chain.ChainID()) {
+			vm.createChain(chain)
+		}
+	}
+	return nil
+}
+
+// checkpoint summarizes the chain's state as of block [blkID], the last
+// accepted block, if the block moved the chain time from [prevTimestamp] into
+// a new epoch. [blkBytes] is the block's byte representation.
+func (vm *VM) checkpoint(blkID ids.ID, blkBytes []byte, prevTimestamp time.Time) {
+	timestamp, err := vm.getTimestamp(vm.DB)
+	if err != nil {
+		vm.Ctx.Log.Warn("couldn't get the chain time after block %s due to %s", blkID, err)
+		return
+	}
+	if epoch(timestamp) <= epoch(prevTimestamp) {
+		return
+	}
+
+	entries, err := vm.stateEntries(vm.DB)
+	if err != nil {
+		vm.Ctx.Log.Warn("couldn't summarize the state as of block %s due to %s", blkID, err)
+		return
+	}
+	summary := &stateSummary{
+		Block: blkBytes,
+		State: entries,
+	}
+	// The summary of the previous epoch is kept if the state is too large to
+	// summarize
+	if _, err := Codec.Marshal(summary); err != nil {
+		vm.Ctx.Log.Warn("couldn't summarize the state as of block %s due to %s", blkID, err)
+		return
+	}
+	if err := vm.State.Put(vm.DB, stateSummaryTypeID, stateSummaryKey, summary); err != nil {
+		vm.Ctx.Log.Warn("couldn't put the state summary as of block %s due to %s", blkID, err)
+		return
+	}
+	if err := vm.DB.Commit(); err != nil {
+		vm.Ctx.Log.Warn("unable to commit vm's DB")
+	}
+}
+
+// stateEntries returns the key/value pairs of the chain's state in [db],
+// sorted by key. The blocks in [db], their statuses and the state summary
+// aren't part of the chain's state. Blocks are stored when they're built or
+// parsed, so every block whose status is in [db] is in [db] too.
+func (vm *VM) stateEntries(db database.Iteratee) ([]stateEntry, error) {
+	excluded := ids.Set{}
+	excluded.Add(stateSummaryKey.Prefix(stateSummaryTypeID))
+
+	// A block's ID is the hash of its bytes
+	it := db.NewIterator()
+	for it.Next() {
+		blkID := ids.NewID(hashing.ComputeHash256Array(it.Value()))
+		if blkKey := blkID.Prefix(state.BlockTypeID); bytes.Equal(blkKey.Bytes(), it.Key()) {
+			excluded.Add(blkKey, blkID.Prefix(state.StatusTypeID))
+		}
+	}
+	it.Release()
+	if err := it.Error(); err != nil {
+		return nil, err
+	}
+
+	entries := []stateEntry(nil)
+	it = db.NewIterator()
+	defer it.Release()
+	for it.Next() {
+		if key, err := ids.ToID(it.Key()); err == nil && excluded.Contains(key) {
+			continue
+		}
+		entries = append(entries, stateEntry{
+			Key:   append([]byte(nil), it.Key()...),
+			Value: append([]byte(nil), it.Value()...),
+		})
+	}
+	return entries, it.Error()
+}
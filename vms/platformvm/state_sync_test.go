@@ -0,0 +1,180 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/gecko/snow/choices"
+
+	smeng "github.com/ava-labs/gecko/snow/engine/snowman"
+)
+
+// advanceTime accepts a proposal to move the chain time of [vm] to [timestamp]
+// and returns the proposal block and the commit block that accepted it
+func advanceTime(t *testing.T, vm *VM, timestamp time.Time) (*ProposalBlock, *Commit) {
+	vm.clock.Set(timestamp)
+	tx, err := vm.newAdvanceTimeTx(timestamp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	block, err := vm.newProposalBlock(vm.LastAccepted(), tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Like a built block, the block is stored before it's decided
+	if err := vm.State.PutBlock(vm.DB, block); err != nil {
+		t.Fatal(err)
+	}
+	return block, acceptProposal(t, block)
+}
+
+// acceptProposal verifies and accepts [block] and the commit block that
+// accepts its proposal, and returns the commit block
+func acceptProposal(t *testing.T, block *ProposalBlock) *Commit {
+	if err := block.Verify(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	block.Accept(context.Background())
+
+	for _, option := range block.Options() {
+		if commit, ok := option.(*Commit); ok {
+			if err := commit.Verify(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+			commit.Accept(context.Background())
+			return commit
+		}
+	}
+	t.Fatal(errShouldPrefCommit)
+	return nil
+}
+
+func TestStateSummaryNotTaken(t *testing.T) {
+	vm := defaultVM()
+
+	if _, err := vm.StateSummary(context.Background()); err != errNoStateSummary {
+		t.Fatalf("Expected %s but got %v", errNoStateSummary, err)
+	}
+
+	// Advancing the chain time within the genesis epoch doesn't summarize the
+	// state
+	timestamp, err := vm.getTimestamp(vm.DB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if epoch(timestamp.Add(time.Second)) == epoch(timestamp) {
+		advanceTime(t, vm, timestamp.Add(time.Second))
+		if _, err := vm.StateSummary(context.Background()); err != errNoStateSummary {
+			t.Fatalf("Expected %s but got %v", errNoStateSummary, err)
+		}
+	}
+}
+
+func TestStateSync(t *testing.T) {
+	vm := defaultVM()
+	var _ smeng.StateSyncableVM = vm
+
+	timestamp, err := vm.getTimestamp(vm.DB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	epochStart := nextEpochStartTime(timestamp)
+	_, checkpoint := advanceTime(t, vm, epochStart)
+
+	summary, err := vm.StateSummary(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The state isn't summarized again until the chain time reaches the next
+	// epoch
+	proposal, _ := advanceTime(t, vm, epochStart.Add(time.Second))
+	if newSummary, err := vm.StateSummary(context.Background()); err != nil {
+		t.Fatal(err)
+	} else if !bytes.Equal(newSummary, summary) {
+		t.Fatalf("The state shouldn't have been summarized within the epoch")
+	}
+
+	syncedVM := defaultVM()
+	if err := syncedVM.SyncState(context.Background(), summary); err != nil {
+		t.Fatal(err)
+	}
+	if lastAccepted := syncedVM.LastAccepted(); !lastAccepted.Equals(checkpoint.ID()) {
+		t.Fatalf("Expected the last accepted block to be %s but was %s", checkpoint.ID(), lastAccepted)
+	}
+	if status := syncedVM.State.GetStatus(syncedVM.DB, checkpoint.ID()); status != choices.Accepted {
+		t.Fatalf("Expected the summary's block to be accepted but was %s", status)
+	}
+	if syncedTimestamp, err := syncedVM.getTimestamp(syncedVM.DB); err != nil {
+		t.Fatal(err)
+	} else if !syncedTimestamp.Equal(epochStart) {
+		t.Fatalf("Expected the chain time to be %s but was %s", epochStart, syncedTimestamp)
+	}
+
+	// The synced node serves the same summary
+	if syncedSummary, err := syncedVM.StateSummary(context.Background()); err != nil {
+		t.Fatal(err)
+	} else if !bytes.Equal(syncedSummary, summary) {
+		t.Fatalf("The synced node should serve the summary it synced to")
+	}
+
+	// Syncing to the same summary again doesn't change the state
+	if err := syncedVM.SyncState(context.Background(), summary); err != nil {
+		t.Fatal(err)
+	}
+	if lastAccepted := syncedVM.LastAccepted(); !lastAccepted.Equals(checkpoint.ID()) {
+		t.Fatalf("Expected the last accepted block to be %s but was %s", checkpoint.ID(), lastAccepted)
+	}
+
+	// The blocks after the summary's block are replayed on the synced state
+	syncedVM.clock.Set(epochStart.Add(time.Second))
+	blk, err := syncedVM.ParseBlock(context.Background(), proposal.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	acceptProposal(t, blk.(*ProposalBlock))
+	if syncedTimestamp, err := syncedVM.getTimestamp(syncedVM.DB); err != nil {
+		t.Fatal(err)
+	} else if !syncedTimestamp.Equal(epochStart.Add(time.Second)) {
+		t.Fatalf("Expected the chain time to be %s but was %s", epochStart.Add(time.Second), syncedTimestamp)
+	}
+
+	entries, err := vm.stateEntries(vm.DB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	syncedEntries, err := syncedVM.stateEntries(syncedVM.DB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != len(syncedEntries) {
+		t.Fatalf("Expected %d state entries but got %d", len(entries), len(syncedEntries))
+	}
+	for i, entry := range entries {
+		if syncedEntry := syncedEntries[i]; !bytes.Equal(entry.Key, syncedEntry.Key) || !bytes.Equal(entry.Value, syncedEntry.Value) {
+			t.Fatalf("State entry 0x%x differs after syncing", entry.Key)
+		}
+	}
+}
+
+func TestSyncStateNotDecision(t *testing.T) {
+	vm := defaultVM()
+
+	tx, err := vm.newAdvanceTimeTx(defaultGenesisTime.Add(time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	block, err := vm.newProposalBlock(vm.LastAccepted(), tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	summary := &stateSummary{Block: block.Bytes()}
+	if err := vm.SyncState(context.Background(), summary.Bytes()); err != errSummaryNotDecision {
+		t.Fatalf("Expected %s but got %v", errSummaryNotDecision, err)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"errors"
 	"net/http"
 
+	"github.com/ava-labs/gecko/chains"
 	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/utils/formatting"
 	"github.com/ava-labs/gecko/utils/json"
@@ -47,6 +48,10 @@ type APIValidator struct {
 	Weight      *json.Uint64 `json:"weight,omitempty"`
 	StakeAmount *json.Uint64 `json:"stakeAmount,omitempty"`
 	ID          ids.ShortID  `json:"id"`
+
+	// The fee, in shares out of NumberOfShares, a default subnet validator
+	// charges its delegators
+	DelegationFeeRate *json.Uint32 `json:"delegationFeeRate,omitempty"`
 }
 
 func (v *APIValidator) weight() uint64 {
@@ -74,11 +79,14 @@ type APIDefaultSubnetValidator struct {
 // [VMID] is the ID of the VM this chain runs.
 // [FxIDs] are the IDs of the Fxs the chain supports.
 // [Name] is a human-readable, non-unique name for the chain.
+// [Engine] is the consensus engine that runs the chain. If empty, the engine
+// is picked from the capabilities of the VM.
 type APIChain struct {
 	GenesisData formatting.CB58 `json:"genesisData"`
 	VMID        ids.ID          `json:"vmID"`
 	FxIDs       []ids.ID        `json:"fxIDs"`
 	Name        string          `json:"name"`
+	Engine      string          `json:"engine"`
 }
 
 // BuildGenesisArgs are the arguments used to create
@@ -89,12 +97,15 @@ type APIChain struct {
 // [Validators] are the validators of the default subnet at genesis.
 // [Chains] are the chains that exist at genesis.
 // [Time] is the Platform Chain's time at network genesis.
+// [DeterministicChainIDs] identifies the network's chains by ComputeChainID,
+// rather than by the IDs of the transactions that create them.
 type BuildGenesisArgs struct {
-	NetworkID  json.Uint32                 `json:"address"`
-	Accounts   []APIAccount                `json:"accounts"`
-	Validators []APIDefaultSubnetValidator `json:"defaultSubnetValidators"`
-	Chains     []APIChain                  `json:"chains"`
-	Time       json.Uint64                 `json:"time"`
+	NetworkID             json.Uint32                 `json:"address"`
+	Accounts              []APIAccount                `json:"accounts"`
+	Validators            []APIDefaultSubnetValidator `json:"defaultSubnetValidators"`
+	Chains                []APIChain                  `json:"chains"`
+	Time                  json.Uint64                 `json:"time"`
+	DeterministicChainIDs bool                        `json:"deterministicChainIDs"`
 }
 
 // BuildGenesisReply is the reply from BuildGenesis
@@ -108,6 +119,33 @@ type Genesis struct {
 	Validators *EventHeap       `serialize:"true"`
 	Chains     []*CreateChainTx `serialize:"true"`
 	Timestamp  uint64           `serialize:"true"`
+
+	// True if the network's chains are identified by ComputeChainID
+	deterministicChainIDs bool
+}
+
+// ChainIDGenesis is the genesis state of a network whose chains are identified
+// by ComputeChainID. The chains of a network whose genesis state is a Genesis
+// are identified by the IDs of the transactions that create them, so that the
+// IDs of existing chains don't change.
+type ChainIDGenesis struct {
+	Genesis               `serialize:"true"`
+	DeterministicChainIDs bool `serialize:"true"`
+}
+
+// ParseGenesis parses and initializes the genesis state of the platform chain,
+// which may be either a Genesis or a ChainIDGenesis
+func ParseGenesis(genesisBytes []byte) (*Genesis, error) {
+	genesis := &Genesis{}
+	if err := Codec.Unmarshal(genesisBytes, genesis); err != nil {
+		chainIDGenesis := ChainIDGenesis{}
+		if err := Codec.Unmarshal(genesisBytes, &chainIDGenesis); err != nil {
+			return nil, err
+		}
+		genesis = &chainIDGenesis.Genesis
+		genesis.deterministicChainIDs = chainIDGenesis.DeterministicChainIDs
+	}
+	return genesis, genesis.Initialize()
 }
 
 // Initialize ...
@@ -121,6 +159,9 @@ func (g *Genesis) Initialize() error {
 		if err := chain.initialize(nil); err != nil {
 			return err
 		}
+		if g.deterministicChainIDs {
+			chain.chainID = chain.computeChainID()
+		}
 	}
 	return nil
 }
@@ -174,8 +215,13 @@ func (*StaticService) BuildGenesis(_ *http.Request, args *BuildGenesisArgs, repl
 	}
 
 	// Specify the chains that exist at genesis.
-	chains := []*CreateChainTx{}
+	genesisChains := []*CreateChainTx{}
 	for _, chain := range args.Chains {
+		engine, err := chains.ParseEngineType(chain.Engine)
+		if err != nil {
+			return err
+		}
+
 		// Ordinarily we sign a createChainTx. For genesis, there is no key.
 		// We generate the ID of this tx by hashing the bytes of the unsigned transaction
 		// TODO: Should we just sign this tx with a private key that we share publicly?
@@ -187,24 +233,38 @@ func (*StaticService) BuildGenesis(_ *http.Request, args *BuildGenesisArgs, repl
 				VMID:        chain.VMID,
 				FxIDs:       chain.FxIDs,
 				GenesisData: chain.GenesisData.Bytes,
+				Engine:      engine,
 			},
 		}
 		if err := tx.initialize(nil); err != nil {
 			return err
 		}
 
-		chains = append(chains, tx)
+		genesisChains = append(genesisChains, tx)
 	}
 
 	// genesis holds the genesis state
 	genesis := Genesis{
 		Accounts:   accounts,
 		Validators: validators,
-		Chains:     chains,
+		Chains:     genesisChains,
 		Timestamp:  uint64(args.Time),
 	}
-	// Marshal genesis to bytes
-	bytes, err := Codec.Marshal(genesis)
+	// Marshal genesis to bytes. Networks with deterministic chain IDs mark
+	// their genesis state as such, so the genesis state of other networks
+	// is unchanged.
+	var (
+		bytes []byte
+		err   error
+	)
+	if args.DeterministicChainIDs {
+		bytes, err = Codec.Marshal(ChainIDGenesis{
+			Genesis:               genesis,
+			DeterministicChainIDs: true,
+		})
+	} else {
+		bytes, err = Codec.Marshal(genesis)
+	}
 	reply.Bytes.Bytes = bytes
 	return err
 }
@@ -59,7 +59,7 @@ func TestBuildGenesis(t *testing.T) {
 		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
 		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
 		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
-		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x05,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x05,
 	}
 
 	addr, _ := ids.ShortFromString("8CrVPQZ4VSqgL8zTdvL14G8HqAfrBr4z")
@@ -112,6 +112,59 @@ func TestBuildGenesis(t *testing.T) {
 	}
 }
 
+func TestBuildGenesisDeterministicChainIDs(t *testing.T) {
+	addr, _ := ids.ShortFromString("8CrVPQZ4VSqgL8zTdvL14G8HqAfrBr4z")
+	weight := json.Uint64(987654321)
+	args := BuildGenesisArgs{
+		Accounts: []APIAccount{APIAccount{
+			Address: addr,
+			Balance: 123456789,
+		}},
+		Validators: []APIDefaultSubnetValidator{APIDefaultSubnetValidator{
+			APIValidator: APIValidator{
+				EndTime: 15,
+				Weight:  &weight,
+				ID:      addr,
+			},
+			Destination: addr,
+		}},
+		Chains: []APIChain{APIChain{
+			GenesisData: formatting.CB58{Bytes: []byte{1, 2, 3}},
+			VMID:        ids.NewID([32]byte{1}),
+			Name:        "My Favorite Episode",
+		}},
+		Time: 5,
+	}
+
+	ss := StaticService{}
+	reply := BuildGenesisReply{}
+	if err := ss.BuildGenesis(nil, &args, &reply); err != nil {
+		t.Fatal(err)
+	}
+	genesis, err := ParseGenesis(reply.Bytes.Bytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	chain := genesis.Chains[0]
+	if !chain.ChainID().Equals(chain.ID()) {
+		t.Fatalf("Chains should be identified by the transactions that create them by default")
+	}
+
+	args.DeterministicChainIDs = true
+	if err := ss.BuildGenesis(nil, &args, &reply); err != nil {
+		t.Fatal(err)
+	}
+	genesis, err = ParseGenesis(reply.Bytes.Bytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	chain = genesis.Chains[0]
+	expectedID := ComputeChainID(DefaultSubnetID, chain.NetworkID, ComputeGenesisHash(chain.GenesisData), chain.ChainName)
+	if !chain.ChainID().Equals(expectedID) {
+		t.Fatalf("Wrong chain ID, expected %s got %s", expectedID, chain.ChainID())
+	}
+}
+
 func TestBuildGenesisInvalidAccountBalance(t *testing.T) {
 	id, _ := ids.ShortFromString("8CrVPQZ4VSqgL8zTdvL14G8HqAfrBr4z")
 	account := APIAccount{
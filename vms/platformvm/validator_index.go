@@ -0,0 +1,75 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"bytes"
+	"sort"
+
+	"github.com/ava-labs/gecko/ids"
+)
+
+// validatorIndex holds the validator sets served by the API sorted by the IDs
+// of the txs that added the validators. Paging through a large validator set
+// reads and sorts it once, rather than once per page. The index is rebuilt
+// once a new block is accepted.
+type validatorIndex struct {
+	// The last accepted block when the index was built
+	lastAccepted ids.ID
+
+	// Key of the validator set in the database --> the sorted set
+	sets map[[32]byte][]TimedTx
+}
+
+// indexedValidators returns the current validators of [subnetID], or the
+// pending validators if [pending] is true, sorted by the IDs of the txs that
+// added them
+func (vm *VM) indexedValidators(subnetID ids.ID, pending bool) ([]TimedTx, error) {
+	index := &vm.validatorIndex
+	if lastAccepted := vm.LastAccepted(); index.sets == nil || !lastAccepted.Equals(index.lastAccepted) {
+		index.lastAccepted = lastAccepted
+		index.sets = make(map[[32]byte][]TimedTx)
+	}
+
+	prefix := currentValidatorsPrefix
+	getValidators := vm.getCurrentValidators
+	if pending {
+		prefix = pendingValidatorsPrefix
+		getValidators = vm.getPendingValidators
+	}
+	key := subnetID.Prefix(prefix).Key()
+	if txs, exists := index.sets[key]; exists {
+		return txs, nil
+	}
+
+	validators, err := getValidators(vm.DB, subnetID)
+	if err != nil {
+		return nil, err
+	}
+	txs := append([]TimedTx(nil), validators.Txs...)
+	sort.Slice(txs, func(i, j int) bool {
+		iID, jID := txs[i].ID(), txs[j].ID()
+		return bytes.Compare(iID.Bytes(), jID.Bytes()) == -1
+	})
+	index.sets[key] = txs
+	return txs, nil
+}
+
+// pageValidators returns at most [limit] of [txs], which are sorted by ID,
+// starting after the tx with ID [start]. If [start] is empty, the page starts
+// at the first tx.
+func pageValidators(txs []TimedTx, start ids.ID, limit int) []TimedTx {
+	first := 0
+	if !start.IsZero() {
+		first = sort.Search(len(txs), func(i int) bool {
+			txID := txs[i].ID()
+			return bytes.Compare(txID.Bytes(), start.Bytes()) == 1
+		})
+	}
+	last := first + limit
+	if last > len(txs) {
+		last = len(txs)
+	}
+	return txs[first:last]
+}
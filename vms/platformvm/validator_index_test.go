@@ -0,0 +1,76 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ava-labs/gecko/ids"
+)
+
+func TestIndexedValidatorsSorted(t *testing.T) {
+	vm := defaultVM()
+
+	txs, err := vm.indexedValidators(DefaultSubnetID, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(txs) != len(keys) {
+		t.Fatalf("Expected %d validators but got %d", len(keys), len(txs))
+	}
+	for i := 1; i < len(txs); i++ {
+		prev, next := txs[i-1].ID(), txs[i].ID()
+		if bytes.Compare(prev.Bytes(), next.Bytes()) != -1 {
+			t.Fatalf("Validators should be sorted by tx ID")
+		}
+	}
+}
+
+func TestIndexedValidatorsRebuiltOnAccept(t *testing.T) {
+	vm := defaultVM()
+
+	if txs, err := vm.indexedValidators(DefaultSubnetID, true); err != nil {
+		t.Fatal(err)
+	} else if len(txs) != 0 {
+		t.Fatalf("Expected no pending validators but got %d", len(txs))
+	}
+
+	tx, err := vm.newAddDefaultSubnetValidatorTx(
+		defaultNonce+1,
+		defaultStakeAmount,
+		uint64(defaultValidateStartTime.Unix()),
+		uint64(defaultValidateEndTime.Unix()),
+		keys[0].PublicKey().Address(),
+		keys[0].PublicKey().Address(),
+		NumberOfShares,
+		testNetworkID,
+		keys[0],
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pending, err := vm.getPendingValidators(vm.DB, DefaultSubnetID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pending.Add(tx)
+	if err := vm.putPendingValidators(vm.DB, pending, DefaultSubnetID); err != nil {
+		t.Fatal(err)
+	}
+
+	if txs, err := vm.indexedValidators(DefaultSubnetID, true); err != nil {
+		t.Fatal(err)
+	} else if len(txs) != 0 {
+		t.Fatalf("The index shouldn't change until a block is accepted")
+	}
+
+	// Simulate a block being accepted
+	vm.validatorIndex.lastAccepted = ids.Empty
+	if txs, err := vm.indexedValidators(DefaultSubnetID, true); err != nil {
+		t.Fatal(err)
+	} else if len(txs) != 1 {
+		t.Fatalf("Expected 1 pending validator but got %d", len(txs))
+	}
+}
@@ -5,6 +5,7 @@ package platformvm
 
 import (
 	"container/heap"
+	"context"
 	"errors"
 	"fmt"
 	"time"
@@ -26,6 +27,9 @@ import (
 	"github.com/ava-labs/gecko/utils/wrappers"
 	"github.com/ava-labs/gecko/vms/components/codec"
 	"github.com/ava-labs/gecko/vms/components/core"
+	"github.com/ava-labs/gecko/vms/components/events"
+	"github.com/ava-labs/gecko/vms/components/verify"
+	"github.com/ava-labs/gecko/vms/secp256k1fx"
 )
 
 const (
@@ -35,10 +39,19 @@ const (
 	chainsTypeID
 	blockTypeID
 	subnetsTypeID
+	governanceParametersTypeID
+	proposalsTypeID
+	slashingTypeID
+	payoutsTypeID
+	stateSummaryTypeID
 
 	// Delta is the synchrony bound used for safe decision making
 	Delta = 10 * time.Second // TODO change to longer period (2 minutes?) before release
 
+	// EpochDuration is the length of an epoch. Changes to a subnet's validator
+	// set take effect at the start of the first epoch after they're accepted.
+	EpochDuration = 5 * time.Minute
+
 	// InflationRate is the maximum inflation rate of AVA from staking
 	InflationRate = 1.04
 
@@ -47,7 +60,8 @@ const (
 
 	// TODO: Incorporate these constants + turn them into governable parameters
 
-	// MinimumStakeAmount is the minimum amount of $AVA one must bond to be a staker
+	// MinimumStakeAmount is the minimum amount of $AVA one must bond to be a
+	// staker. Governance proposals may raise, but not lower, the minimum stake.
 	MinimumStakeAmount = 10 * units.MicroAva
 
 	// MinimumStakingDuration is the shortest amount of time a staker can bond
@@ -75,11 +89,16 @@ var (
 	pendingValidatorsKey = ids.NewID([32]byte{'p', 'e', 'n', 'd', 'i', 'n', 'g'})
 	chainsKey            = ids.NewID([32]byte{'c', 'h', 'a', 'i', 'n', 's'})
 	subnetsKey           = ids.NewID([32]byte{'s', 'u', 'b', 'n', 'e', 't', 's'})
+
+	governanceParametersKey = ids.NewID([32]byte{'p', 'a', 'r', 'a', 'm', 's'})
+	proposalsKey            = ids.NewID([32]byte{'p', 'r', 'o', 'p', 'o', 's', 'a', 'l', 's'})
+
+	stateSummaryKey = ids.NewID([32]byte{'s', 'u', 'm', 'm', 'a', 'r', 'y'})
 )
 
 var (
 	errEndOfTime              = errors.New("program time is suspiciously far in the future. Either this codebase was way more successful than expected, or a critical error has occurred")
-	errTimeTooAdvanced        = errors.New("this is proposing a time too far in the future")
+	errTimeTooAdvanced        = verify.NewError(CodeTimeTooAdvanced, "this is proposing a time too far in the future")
 	errNoPendingBlocks        = errors.New("no pending blocks")
 	errUnsupportedFXs         = errors.New("unsupported feature extensions")
 	errDB                     = errors.New("problem retrieving/putting value from/in database")
@@ -87,6 +106,8 @@ var (
 	errDBPutCurrentValidators = errors.New("couldn't put current validators in database")
 	errDBPendingValidators    = errors.New("couldn't retrieve pending validators from database")
 	errDBPutPendingValidators = errors.New("couldn't put pending validators in database")
+	errDBEpochValidators      = errors.New("couldn't retrieve epoch validators from database")
+	errDBPutEpochValidators   = errors.New("couldn't put epoch validators in database")
 	errDBAccount              = errors.New("couldn't retrieve account from database")
 	errDBPutAccount           = errors.New("couldn't put account in database")
 	errDBChains               = errors.New("couldn't retrieve chain list from database")
@@ -126,6 +147,24 @@ func init() {
 
 		Codec.RegisterType(&advanceTimeTx{}),
 		Codec.RegisterType(&rewardValidatorTx{}),
+
+		Codec.RegisterType(&UnsignedGovernanceProposalTx{}),
+		Codec.RegisterType(&governanceProposalTx{}),
+
+		Codec.RegisterType(&UnsignedExportTx{}),
+		Codec.RegisterType(&ExportTx{}),
+
+		Codec.RegisterType(&UnsignedImportTx{}),
+		Codec.RegisterType(&ImportTx{}),
+
+		Codec.RegisterType(&secp256k1fx.TransferOutput{}),
+		Codec.RegisterType(&secp256k1fx.TransferInput{}),
+
+		Codec.RegisterType(&UnsignedSlashTx{}),
+		Codec.RegisterType(&slashTx{}),
+
+		Codec.RegisterType(&UnsignedRemoveNonDefaultSubnetValidatorTx{}),
+		Codec.RegisterType(&removeNonDefaultSubnetValidatorTx{}),
 	)
 	if errs.Errored() {
 		panic(errs.Err)
@@ -141,6 +180,18 @@ type VM struct {
 	// The node's chain manager
 	ChainManager chains.Manager
 
+	// The parameter values this node votes for in governance proposals
+	GovernanceVotes GovernanceVotes
+
+	// The X-Chain and the AVA asset, which can be moved between the X-Chain
+	// and this chain
+	AVM ids.ID
+	AVA ids.ID
+
+	// True if chains are identified by ComputeChainID rather than by the IDs
+	// of the transactions that create them. Set by the genesis state.
+	deterministicChainIDs bool
+
 	// Used to create and use keys.
 	factory crypto.FactorySECP256K1R
 
@@ -154,6 +205,13 @@ type VM struct {
 	// Transactions that have not been put into blocks yet
 	unissuedEvents      *EventHeap
 	unissuedDecisionTxs []DecisionTx
+	unissuedProposals   []*governanceProposalTx
+
+	// Pushes the decisions of txs to the subscribers of their accounts
+	eventServer *events.Server
+
+	// The validator sets served by the API, sorted for pagination
+	validatorIndex validatorIndex
 
 	// This timer goes off when it is time for the next validator to add/leave the validator set
 	// When it goes off resetTimer() is called, triggering creation of a new block
@@ -181,19 +239,20 @@ func (vm *VM) Initialize(
 		return err
 	}
 
+	// The genesis state decides how chains are identified, so it is parsed
+	// even if the database has already been initialized
+	genesis, err := ParseGenesis(genesisBytes)
+	if err != nil {
+		return err
+	}
+	vm.deterministicChainIDs = genesis.deterministicChainIDs
+
 	// Register this VM's types with the database so we can get/put structs to/from it
 	vm.registerDBTypes()
 
 	// If the database is empty, create the platform chain anew using
 	// the provided genesis state
 	if !vm.DBInitialized() {
-		genesis := &Genesis{}
-		if err := Codec.Unmarshal(genesisBytes, genesis); err != nil {
-			return err
-		}
-		if err := genesis.Initialize(); err != nil {
-			return err
-		}
 
 		// Persist accounts that exist at genesis
 		for _, account := range genesis.Accounts {
@@ -207,6 +266,11 @@ func (vm *VM) Initialize(
 			return errDBPutCurrentValidators
 		}
 
+		// The genesis validators are the validators of the first epoch
+		if err := vm.putEpochValidators(vm.DB, genesis.Validators, DefaultSubnetID); err != nil {
+			return errDBPutEpochValidators
+		}
+
 		// Persist the subnets that exist at genesis (none do)
 		if err := vm.putSubnets(vm.DB, []*CreateSubnetTx{}); err != nil {
 			return fmt.Errorf("error putting genesis subnets: %v", err)
@@ -247,7 +311,7 @@ func (vm *VM) Initialize(
 			return errDB
 		}
 		genesisBlock.onAcceptDB = versiondb.New(vm.DB)
-		genesisBlock.CommonBlock.Accept()
+		genesisBlock.CommonBlock.Accept(context.Background())
 
 		vm.SetDBInitialized()
 	}
@@ -265,8 +329,15 @@ func (vm *VM) Initialize(
 	})
 	go ctx.Log.RecoverAndPanic(vm.timer.Dispatch)
 
-	if err := vm.updateValidators(DefaultSubnetID); err != nil {
-		ctx.Log.Error("failed to initialize the current validator set: %s", err)
+	// Subnets created before epochs were introduced don't have an epoch
+	// validator set, so their current validators are used instead
+	if err := vm.initEpochValidators(); err != nil {
+		ctx.Log.Error("failed to initialize the epoch validator set: %s", err)
+		return err
+	}
+
+	if err := vm.updateAllValidators(); err != nil {
+		ctx.Log.Error("failed to initialize the current validator sets: %s", err)
 		return err
 	}
 
@@ -282,6 +353,37 @@ func (vm *VM) Initialize(
 	return nil
 }
 
+// Persist the current validators of each subnet as its epoch validators if the
+// database doesn't have an epoch validator set for the subnet
+func (vm *VM) initEpochValidators() error {
+	subnetIDs := []ids.ID{DefaultSubnetID}
+	subnets, err := vm.getSubnets(vm.DB)
+	if err != nil {
+		return err
+	}
+	for _, subnet := range subnets {
+		subnetIDs = append(subnetIDs, subnet.ID)
+	}
+
+	for _, subnetID := range subnetIDs {
+		has, err := vm.State.Has(vm.DB, validatorsTypeID, subnetID.Prefix(epochValidatorsPrefix))
+		if err != nil {
+			return err
+		}
+		if has {
+			continue
+		}
+		currentValidators, err := vm.getCurrentValidators(vm.DB, subnetID)
+		if err != nil {
+			return err
+		}
+		if err := vm.putEpochValidators(vm.DB, currentValidators, subnetID); err != nil {
+			return err
+		}
+	}
+	return vm.DB.Commit()
+}
+
 // Create all of the chains that the database says should exist
 func (vm *VM) initBlockchains() error {
 	vm.Ctx.Log.Verbo("platform chain initializing existing blockchains")
@@ -290,30 +392,44 @@ func (vm *VM) initBlockchains() error {
 		return err
 	}
 	for _, chain := range existingChains { // Create each blockchain
-		chainParams := chains.ChainParameters{
-			ID:          chain.ID(),
-			GenesisData: chain.GenesisData,
-			VMAlias:     chain.VMID.String(),
-		}
-		for _, fxID := range chain.FxIDs {
-			chainParams.FxAliases = append(chainParams.FxAliases, fxID.String())
-		}
-		vm.ChainManager.CreateChain(chainParams)
+		vm.createChain(chain)
 	}
 	return nil
 }
 
+// Create the blockchain [chain] describes
+func (vm *VM) createChain(chain *CreateChainTx) {
+	chainParams := chains.ChainParameters{
+		ID:          chain.ChainID(),
+		GenesisData: chain.GenesisData,
+		VMAlias:     chain.VMID.String(),
+		Engine:      chain.Engine,
+	}
+	for _, fxID := range chain.FxIDs {
+		chainParams.FxAliases = append(chainParams.FxAliases, fxID.String())
+	}
+	vm.ChainManager.CreateChain(chainParams)
+}
+
 // Shutdown this blockchain
 func (vm *VM) Shutdown() {
 	vm.timer.Stop()
+	if vm.eventServer != nil {
+		if err := vm.eventServer.Shutdown(); err != nil {
+			vm.Ctx.Log.Error("Stopping the events server failed with %s", err)
+		}
+	}
 	if err := vm.DB.Close(); err != nil {
 		vm.Ctx.Log.Error("Closing the database failed with %s", err)
 	}
 }
 
 // BuildBlock builds a block to be added to consensus
-func (vm *VM) BuildBlock() (snowman.Block, error) {
+func (vm *VM) BuildBlock(ctx context.Context) (snowman.Block, error) {
 	vm.Ctx.Log.Debug("in BuildBlock")
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 	preferredID := vm.Preferred()
 
 	// If there are pending decision txs, build a block with a batch of them
@@ -328,7 +444,12 @@ func (vm *VM) BuildBlock() (snowman.Block, error) {
 		if err != nil {
 			return nil, err
 		}
-		if err := blk.Verify(); err != nil {
+		if err := blk.verify(ctx); err != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				// The txs weren't found to be invalid, so they're kept
+				vm.unissuedDecisionTxs = append(txs, vm.unissuedDecisionTxs...)
+				return nil, ctxErr
+			}
 			vm.resetTimer()
 			return nil, err
 		}
@@ -386,17 +507,25 @@ func (vm *VM) BuildBlock() (snowman.Block, error) {
 		return blk, vm.DB.Commit()
 	}
 
-	// If local time is >= time of the next validator set change,
-	// propose moving the chain time forward
+	// If local time is >= time of the next validator set change, or the start
+	// of an epoch that changes the validator set, propose moving the chain time
+	// forward
 	nextValidatorStartTime := vm.nextValidatorChangeTime(db /*start=*/, true)
 	nextValidatorEndTime := vm.nextValidatorChangeTime(db /*start=*/, false)
+	nextEpochChangeTime := vm.nextEpochChangeTime(db, currentChainTimestamp)
 
 	nextValidatorSetChangeTime := nextValidatorStartTime
 	if nextValidatorEndTime.Before(nextValidatorStartTime) {
 		nextValidatorSetChangeTime = nextValidatorEndTime
 	}
 
+	// The chain time can't skip past a change to a validator set, but it can
+	// skip past the start of an epoch
 	localTime := vm.clock.Time()
+	if localTime.Before(nextValidatorSetChangeTime) && nextEpochChangeTime.Before(nextValidatorSetChangeTime) {
+		nextValidatorSetChangeTime = nextEpochChangeTime
+	}
+
 	if !localTime.Before(nextValidatorSetChangeTime) { // time is at or after the time for the next validator to join/leave
 		advanceTimeTx, err := vm.newAdvanceTimeTx(nextValidatorSetChangeTime)
 		if err != nil {
@@ -412,10 +541,27 @@ func (vm *VM) BuildBlock() (snowman.Block, error) {
 		return blk, vm.DB.Commit()
 	}
 
+	// Propose changing a chain parameter
+	if len(vm.unissuedProposals) > 0 {
+		tx := vm.unissuedProposals[0]
+		vm.unissuedProposals = vm.unissuedProposals[1:]
+		blk, err := vm.newProposalBlock(preferredID, tx)
+		if err != nil {
+			return nil, err
+		}
+		if err := vm.State.PutBlock(vm.DB, blk); err != nil {
+			return nil, err
+		}
+		return blk, vm.DB.Commit()
+	}
+
 	// Propose adding a new validator but only if their start time is in the
 	// future relative to local time (plus Delta)
 	syncTime := localTime.Add(Delta)
 	for vm.unissuedEvents.Len() > 0 {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		tx := vm.unissuedEvents.Remove()
 		if !syncTime.After(tx.StartTime()) {
 			blk, err := vm.newProposalBlock(preferredID, tx)
@@ -435,7 +581,10 @@ func (vm *VM) BuildBlock() (snowman.Block, error) {
 }
 
 // ParseBlock implements the snowman.ChainVM interface
-func (vm *VM) ParseBlock(bytes []byte) (snowman.Block, error) {
+func (vm *VM) ParseBlock(ctx context.Context, bytes []byte) (snowman.Block, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 	blockInterface, err := vm.unmarshalBlockFunc(bytes)
 	if err != nil {
 		return nil, errors.New("problem parsing block")
@@ -445,16 +594,22 @@ func (vm *VM) ParseBlock(bytes []byte) (snowman.Block, error) {
 		return nil, errors.New("problem parsing block")
 	}
 	// If we have seen this block before, return it with the most up-to-date info
-	if block, err := vm.GetBlock(block.ID()); err == nil {
+	if block, err := vm.GetBlock(ctx, block.ID()); err == nil {
 		return block, nil
 	}
+	// Don't store a block whose parsing was abandoned
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 	vm.State.PutBlock(vm.DB, block)
 	vm.DB.Commit()
 	return block, nil
 }
 
 // GetBlock implements the snowman.ChainVM interface
-func (vm *VM) GetBlock(blkID ids.ID) (snowman.Block, error) { return vm.getBlock(blkID) }
+func (vm *VM) GetBlock(_ context.Context, blkID ids.ID) (snowman.Block, error) {
+	return vm.getBlock(blkID)
+}
 
 func (vm *VM) getBlock(blkID ids.ID) (Block, error) {
 	// If block is in memory, return it.
@@ -487,7 +642,16 @@ func (vm *VM) SetPreference(blkID ids.ID) {
 func (vm *VM) CreateHandlers() map[string]*common.HTTPHandler {
 	// Create a service with name "platform"
 	handler := vm.SnowmanVM.NewHandler("platform", &Service{vm: vm})
-	return map[string]*common.HTTPHandler{"": handler}
+	handlers := map[string]*common.HTTPHandler{"": handler}
+
+	// The events server is only fed decisions once its endpoint is served
+	if eventServer, err := events.NewServer(vm.Ctx, &eventParser{vm: vm}); err == nil {
+		vm.eventServer = eventServer
+		handlers["/events"] = &common.HTTPHandler{LockOptions: common.NoLock, Handler: eventServer}
+	} else {
+		vm.Ctx.Log.Error("Failed to create the events server due to %s", err)
+	}
+	return handlers
 }
 
 // CreateStaticHandlers implements the snowman.ChainVM interface
@@ -540,22 +704,35 @@ func (vm *VM) resetTimer() {
 		return
 	}
 
-	// If local time is >= time of the next change in the validator set,
-	// propose moving forward the chain timestamp
+	// If local time is >= time of the next change in the validator set, or
+	// the start of an epoch that changes the validator set, propose moving
+	// forward the chain timestamp
 	nextValidatorStartTime := vm.nextValidatorChangeTime(db, true)
 	nextValidatorEndTime := vm.nextValidatorChangeTime(db, false)
+	nextEpochChangeTime := vm.nextEpochChangeTime(db, timestamp)
 
 	nextValidatorSetChangeTime := nextValidatorStartTime
 	if nextValidatorEndTime.Before(nextValidatorStartTime) {
 		nextValidatorSetChangeTime = nextValidatorEndTime
 	}
 
+	// The chain time can't skip past a change to a validator set, but it can
+	// skip past the start of an epoch
 	localTime := vm.clock.Time()
+	if localTime.Before(nextValidatorSetChangeTime) && nextEpochChangeTime.Before(nextValidatorSetChangeTime) {
+		nextValidatorSetChangeTime = nextEpochChangeTime
+	}
+
 	if !localTime.Before(nextValidatorSetChangeTime) { // time is at or after the time for the next validator to join/leave
 		vm.SnowmanVM.NotifyBlockReady() // Should issue a ProposeTimestamp
 		return
 	}
 
+	if len(vm.unissuedProposals) > 0 {
+		vm.SnowmanVM.NotifyBlockReady() // Should issue a governance proposal
+		return
+	}
+
 	syncTime := localTime.Add(Delta)
 	for vm.unissuedEvents.Len() > 0 {
 		if !syncTime.After(vm.unissuedEvents.Peek().StartTime()) {
@@ -671,18 +848,57 @@ func (vm *VM) getValidators(validatorEvents *EventHeap) []validators.Validator {
 	return vdrList
 }
 
+// updateValidators sets the validators of [subnetID] in the validator manager
+// to the validators of the subnet as of the start of the current epoch
 func (vm *VM) updateValidators(subnetID ids.ID) error {
 	validatorSet, ok := vm.Validators.GetValidatorSet(subnetID)
 	if !ok {
-		return fmt.Errorf("couldn't get the validator sampler of the %s subnet", subnetID)
+		if subnetID.Equals(DefaultSubnetID) {
+			return fmt.Errorf("couldn't get the validator sampler of the %s subnet", subnetID)
+		}
+		// The validator sets of other subnets are created as the subnets
+		// are found
+		validatorSet = validators.NewSet()
+		vm.Validators.PutValidatorSet(subnetID, validatorSet)
 	}
 
-	currentValidators, err := vm.getCurrentValidators(vm.DB, subnetID)
+	epochValidators, err := vm.getEpochValidators(vm.DB, subnetID)
 	if err != nil {
 		return err
 	}
 
-	validators := vm.getValidators(currentValidators)
+	validators := vm.getValidators(epochValidators)
 	validatorSet.Set(validators)
 	return nil
 }
+
+// updateAllValidators updates the validators of every subnet in the validator
+// manager, then tells the chain manager which subnets this node validates so
+// that it can start or stop the chains of subnets this node joined or left
+func (vm *VM) updateAllValidators() error {
+	if err := vm.updateValidators(DefaultSubnetID); err != nil {
+		return err
+	}
+	subnets, err := vm.getSubnets(vm.DB)
+	if err != nil {
+		return err
+	}
+
+	validatedSubnets := ids.Set{}
+	validatedSubnets.Add(DefaultSubnetID)
+	for _, subnet := range subnets {
+		if err := vm.updateValidators(subnet.ID); err != nil {
+			return err
+		}
+		validatorSet, _ := vm.Validators.GetValidatorSet(subnet.ID)
+		if validatorSet.Contains(vm.Ctx.NodeID) {
+			validatedSubnets.Add(subnet.ID)
+		}
+	}
+
+	// TODO: Not sure how else to make this not nil pointer error during tests
+	if vm.ChainManager != nil {
+		vm.ChainManager.SetValidatedSubnets(validatedSubnets)
+	}
+	return nil
+}
@@ -6,10 +6,12 @@ package platformvm
 import (
 	"bytes"
 	"container/heap"
+	"context"
 	"errors"
 	"testing"
 	"time"
 
+	"github.com/ava-labs/gecko/chains"
 	"github.com/ava-labs/gecko/database/memdb"
 	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/snow"
@@ -307,7 +309,7 @@ func TestAddDefaultSubnetValidatorCommit(t *testing.T) {
 	// trigger block creation
 	vm.unissuedEvents.Add(tx)
 	vm.Ctx.Lock.Lock()
-	blk, err := vm.BuildBlock()
+	blk, err := vm.BuildBlock(context.Background())
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -325,15 +327,15 @@ func TestAddDefaultSubnetValidatorCommit(t *testing.T) {
 		t.Fatal(errShouldPrefAbort)
 	}
 
-	if err := block.Verify(); err != nil {
+	if err := block.Verify(context.Background()); err != nil {
 		t.Fatal(err)
 	}
-	block.Accept()
+	block.Accept(context.Background())
 
-	if err := commit.Verify(); err != nil {
+	if err := commit.Verify(context.Background()); err != nil {
 		t.Fatal(err)
 	}
-	commit.Accept() // commit the proposal
+	commit.Accept(context.Background()) // commit the proposal
 
 	// Verify that new validator now in pending validator set
 	pendingValidators, err := vm.getPendingValidators(vm.DB, DefaultSubnetID)
@@ -374,7 +376,7 @@ func TestAddDefaultSubnetValidatorReject(t *testing.T) {
 	// trigger block creation
 	vm.unissuedEvents.Add(tx)
 	vm.Ctx.Lock.Lock()
-	blk, err := vm.BuildBlock()
+	blk, err := vm.BuildBlock(context.Background())
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -392,19 +394,19 @@ func TestAddDefaultSubnetValidatorReject(t *testing.T) {
 		t.Fatal(errShouldPrefAbort)
 	}
 
-	if err := block.Verify(); err != nil {
+	if err := block.Verify(context.Background()); err != nil {
 		t.Fatal(err)
 	}
-	block.Accept()
+	block.Accept(context.Background())
 
-	if err := commit.Verify(); err != nil { // should pass verification
+	if err := commit.Verify(context.Background()); err != nil { // should pass verification
 		t.Fatal(err)
 	}
-	if err := abort.Verify(); err != nil { // should pass verification
+	if err := abort.Verify(context.Background()); err != nil { // should pass verification
 		t.Fatal(err)
 	}
 
-	abort.Accept() // reject the proposal
+	abort.Accept(context.Background()) // reject the proposal
 
 	// Verify that new validator NOT in pending validator set
 	pendingValidators, err := vm.getPendingValidators(vm.DB, DefaultSubnetID)
@@ -445,7 +447,7 @@ func TestAddNonDefaultSubnetValidatorAccept(t *testing.T) {
 	// trigger block creation
 	vm.unissuedEvents.Add(tx)
 	vm.Ctx.Lock.Lock()
-	blk, err := vm.BuildBlock()
+	blk, err := vm.BuildBlock(context.Background())
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -463,19 +465,19 @@ func TestAddNonDefaultSubnetValidatorAccept(t *testing.T) {
 		t.Fatal(errShouldPrefAbort)
 	}
 
-	if err := block.Verify(); err != nil {
+	if err := block.Verify(context.Background()); err != nil {
 		t.Fatal(err)
 	}
-	block.Accept()
+	block.Accept(context.Background())
 
-	if err := commit.Verify(); err != nil {
+	if err := commit.Verify(context.Background()); err != nil {
 		t.Fatal(err)
 	}
-	if err := abort.Verify(); err != nil {
+	if err := abort.Verify(context.Background()); err != nil {
 		t.Fatal(err)
 	}
 
-	commit.Accept() // accept the proposal
+	commit.Accept(context.Background()) // accept the proposal
 
 	// Verify that new validator is in pending validator set
 	pendingValidators, err := vm.getPendingValidators(vm.DB, testSubnet1.ID)
@@ -518,7 +520,7 @@ func TestAddNonDefaultSubnetValidatorReject(t *testing.T) {
 	// trigger block creation
 	vm.unissuedEvents.Add(tx)
 	vm.Ctx.Lock.Lock()
-	blk, err := vm.BuildBlock()
+	blk, err := vm.BuildBlock(context.Background())
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -536,19 +538,19 @@ func TestAddNonDefaultSubnetValidatorReject(t *testing.T) {
 		t.Fatal(errShouldPrefAbort)
 	}
 
-	if err := block.Verify(); err != nil {
+	if err := block.Verify(context.Background()); err != nil {
 		t.Fatal(err)
 	}
-	block.Accept()
+	block.Accept(context.Background())
 
-	if err := commit.Verify(); err != nil {
+	if err := commit.Verify(context.Background()); err != nil {
 		t.Fatal(err)
 	}
-	if err := abort.Verify(); err != nil {
+	if err := abort.Verify(context.Background()); err != nil {
 		t.Fatal(err)
 	}
 
-	abort.Accept() // reject the proposal
+	abort.Accept(context.Background()) // reject the proposal
 
 	// Verify that new validator NOT in pending validator set
 	pendingValidators, err := vm.getPendingValidators(vm.DB, testSubnet1.ID)
@@ -570,7 +572,7 @@ func TestRewardValidatorAccept(t *testing.T) {
 	vm.clock.Set(defaultValidateEndTime)
 
 	vm.Ctx.Lock.Lock()
-	blk, err := vm.BuildBlock() // should contain proposal to advance time
+	blk, err := vm.BuildBlock(context.Background()) // should contain proposal to advance time
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -588,19 +590,19 @@ func TestRewardValidatorAccept(t *testing.T) {
 		t.Fatal(errShouldPrefAbort)
 	}
 
-	if err := block.Verify(); err != nil {
+	if err := block.Verify(context.Background()); err != nil {
 		t.Fatal(err)
 	}
-	block.Accept()
+	block.Accept(context.Background())
 
-	if err := commit.Verify(); err != nil {
+	if err := commit.Verify(context.Background()); err != nil {
 		t.Fatal(err)
 	}
-	if err := abort.Verify(); err != nil {
+	if err := abort.Verify(context.Background()); err != nil {
 		t.Fatal(err)
 	}
 
-	commit.Accept() // advance the timestamp
+	commit.Accept(context.Background()) // advance the timestamp
 
 	// Verify that chain's timestamp has advanced
 	timestamp, err := vm.getTimestamp(vm.DB)
@@ -612,7 +614,7 @@ func TestRewardValidatorAccept(t *testing.T) {
 	}
 
 	vm.Ctx.Lock.Lock()
-	blk, err = vm.BuildBlock() // should contain proposal to reward genesis validator
+	blk, err = vm.BuildBlock(context.Background()) // should contain proposal to reward genesis validator
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -630,19 +632,19 @@ func TestRewardValidatorAccept(t *testing.T) {
 		t.Fatal(errShouldPrefAbort)
 	}
 
-	if err := block.Verify(); err != nil {
+	if err := block.Verify(context.Background()); err != nil {
 		t.Fatal(err)
 	}
-	block.Accept()
+	block.Accept(context.Background())
 
-	if err := commit.Verify(); err != nil {
+	if err := commit.Verify(context.Background()); err != nil {
 		t.Fatal(err)
 	}
-	if err := abort.Verify(); err != nil {
+	if err := abort.Verify(context.Background()); err != nil {
 		t.Fatal(err)
 	}
 
-	commit.Accept() // reward the genesis validator
+	commit.Accept(context.Background()) // reward the genesis validator
 
 	// Verify that genesis validator was rewarded and removed from current validator set
 	currentValidators, err := vm.getCurrentValidators(vm.DB, DefaultSubnetID)
@@ -662,7 +664,7 @@ func TestRewardValidatorReject(t *testing.T) {
 	vm.clock.Set(defaultValidateEndTime)
 
 	vm.Ctx.Lock.Lock()
-	blk, err := vm.BuildBlock() // should contain proposal to advance time
+	blk, err := vm.BuildBlock(context.Background()) // should contain proposal to advance time
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -680,19 +682,19 @@ func TestRewardValidatorReject(t *testing.T) {
 		t.Fatal(errShouldPrefAbort)
 	}
 
-	if err := block.Verify(); err != nil {
+	if err := block.Verify(context.Background()); err != nil {
 		t.Fatal(err)
 	}
-	block.Accept()
+	block.Accept(context.Background())
 
-	if err := commit.Verify(); err != nil {
+	if err := commit.Verify(context.Background()); err != nil {
 		t.Fatal(err)
 	}
-	if err := abort.Verify(); err != nil {
+	if err := abort.Verify(context.Background()); err != nil {
 		t.Fatal(err)
 	}
 
-	commit.Accept() // advance the timestamp
+	commit.Accept(context.Background()) // advance the timestamp
 
 	// Verify that chain's timestamp has advanced
 	timestamp, err := vm.getTimestamp(vm.DB)
@@ -704,7 +706,7 @@ func TestRewardValidatorReject(t *testing.T) {
 	}
 
 	vm.Ctx.Lock.Lock()
-	blk, err = vm.BuildBlock() // should contain proposal to reward genesis validator
+	blk, err = vm.BuildBlock(context.Background()) // should contain proposal to reward genesis validator
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -722,19 +724,19 @@ func TestRewardValidatorReject(t *testing.T) {
 		t.Fatal(errShouldPrefAbort)
 	}
 
-	if err := block.Verify(); err != nil {
+	if err := block.Verify(context.Background()); err != nil {
 		t.Fatal(err)
 	}
-	block.Accept()
+	block.Accept(context.Background())
 
-	if err := commit.Verify(); err != nil {
+	if err := commit.Verify(context.Background()); err != nil {
 		t.Fatal(err)
 	}
-	if err := abort.Verify(); err != nil {
+	if err := abort.Verify(context.Background()); err != nil {
 		t.Fatal(err)
 	}
 
-	abort.Accept() // do not reward the genesis validator
+	abort.Accept(context.Background()) // do not reward the genesis validator
 
 	// Verify that genesis validator was removed from current validator set
 	currentValidators, err := vm.getCurrentValidators(vm.DB, DefaultSubnetID)
@@ -750,11 +752,93 @@ func TestRewardValidatorReject(t *testing.T) {
 func TestUnneededBuildBlock(t *testing.T) {
 	vm := defaultVM()
 
-	if _, err := vm.BuildBlock(); err == nil {
+	if _, err := vm.BuildBlock(context.Background()); err == nil {
 		t.Fatalf("Should have errored on BuildBlock")
 	}
 }
 
+// test that building a block stops once the chain shuts down, and that the
+// txs that would have been in it are kept
+func TestBuildBlockCancelled(t *testing.T) {
+	vm := defaultVM()
+
+	tx, err := vm.newCreateChainTx(
+		defaultNonce+1,
+		nil,
+		timestampvm.ID,
+		nil,
+		chains.DefaultEngine,
+		"name",
+		testNetworkID,
+		keys[0],
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	vm.Ctx.Lock.Lock()
+	defer vm.Ctx.Lock.Unlock()
+
+	vm.unissuedDecisionTxs = append(vm.unissuedDecisionTxs, tx)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := vm.BuildBlock(ctx); err != context.Canceled {
+		t.Fatalf("Should have errored with %s, errored with %v", context.Canceled, err)
+	}
+	if len(vm.unissuedDecisionTxs) != 1 {
+		t.Fatalf("Should have kept the unissued tx")
+	}
+
+	if _, err := vm.BuildBlock(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(vm.unissuedDecisionTxs) != 0 {
+		t.Fatalf("Should have issued the tx")
+	}
+}
+
+// test that a block whose parsing was cancelled isn't stored
+func TestParseBlockCancelled(t *testing.T) {
+	vm := defaultVM()
+
+	tx, err := vm.newCreateChainTx(
+		defaultNonce+1,
+		nil,
+		timestampvm.ID,
+		nil,
+		chains.DefaultEngine,
+		"name",
+		testNetworkID,
+		keys[0],
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	blk, err := vm.newStandardBlock(vm.LastAccepted(), []DecisionTx{tx})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	vm.Ctx.Lock.Lock()
+	defer vm.Ctx.Lock.Unlock()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := vm.ParseBlock(ctx, blk.Bytes()); err != context.Canceled {
+		t.Fatalf("Should have errored with %s, errored with %v", context.Canceled, err)
+	}
+	if _, err := vm.getBlock(blk.ID()); err == nil {
+		t.Fatalf("Shouldn't have stored a block whose parsing was cancelled")
+	}
+
+	if _, err := vm.ParseBlock(context.Background(), blk.Bytes()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := vm.getBlock(blk.ID()); err != nil {
+		t.Fatal(err)
+	}
+}
+
 // test acceptance of proposal to create a new chain
 func TestCreateChain(t *testing.T) {
 	vm := defaultVM()
@@ -764,6 +848,7 @@ func TestCreateChain(t *testing.T) {
 		nil,
 		timestampvm.ID,
 		nil,
+		chains.DefaultEngine,
 		"name ",
 		testNetworkID,
 		keys[0],
@@ -774,17 +859,17 @@ func TestCreateChain(t *testing.T) {
 
 	vm.Ctx.Lock.Lock()
 	vm.unissuedDecisionTxs = append(vm.unissuedDecisionTxs, tx)
-	blk, err := vm.BuildBlock() // should contain proposal to create chain
+	blk, err := vm.BuildBlock(context.Background()) // should contain proposal to create chain
 	if err != nil {
 		t.Fatal(err)
 	}
 	vm.Ctx.Lock.Unlock()
 
-	if err := blk.Verify(); err != nil {
+	if err := blk.Verify(context.Background()); err != nil {
 		t.Fatal(err)
 	}
 
-	blk.Accept()
+	blk.Accept(context.Background())
 
 	// Verify chain was created
 	chains, err := vm.getChains(vm.DB)
@@ -835,17 +920,17 @@ func TestCreateSubnet(t *testing.T) {
 
 	vm.Ctx.Lock.Lock()
 	vm.unissuedDecisionTxs = append(vm.unissuedDecisionTxs, createSubnetTx)
-	blk, err := vm.BuildBlock() // should contain proposal to create subnet
+	blk, err := vm.BuildBlock(context.Background()) // should contain proposal to create subnet
 	if err != nil {
 		t.Fatal(err)
 	}
 	vm.Ctx.Lock.Unlock()
 
-	if err := blk.Verify(); err != nil {
+	if err := blk.Verify(context.Background()); err != nil {
 		t.Fatal(err)
 	}
 
-	blk.Accept()
+	blk.Accept(context.Background())
 
 	// Verify new subnet was created
 	subnets, err := vm.getSubnets(vm.DB)
@@ -898,7 +983,7 @@ func TestCreateSubnet(t *testing.T) {
 
 	vm.Ctx.Lock.Lock()
 	vm.unissuedEvents.Push(addValidatorTx)
-	blk, err = vm.BuildBlock() // should add validator to the new subnet
+	blk, err = vm.BuildBlock(context.Background()) // should add validator to the new subnet
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -918,17 +1003,17 @@ func TestCreateSubnet(t *testing.T) {
 	}
 
 	// Accept the block
-	if err := block.Verify(); err != nil {
+	if err := block.Verify(context.Background()); err != nil {
 		t.Fatal(err)
 	}
-	block.Accept()
-	if err := commit.Verify(); err != nil {
+	block.Accept(context.Background())
+	if err := commit.Verify(context.Background()); err != nil {
 		t.Fatal(err)
 	}
-	if err := abort.Verify(); err != nil {
+	if err := abort.Verify(context.Background()); err != nil {
 		t.Fatal(err)
 	}
-	commit.Accept() // add the validator to pending validator set
+	commit.Accept(context.Background()) // add the validator to pending validator set
 
 	// Verify validator is in pending validator set
 	pendingValidators, err := vm.getPendingValidators(vm.DB, createSubnetTx.ID)
@@ -951,7 +1036,7 @@ func TestCreateSubnet(t *testing.T) {
 	vm.clock.Set(startTime)
 
 	vm.Ctx.Lock.Lock()
-	blk, err = vm.BuildBlock() // should be advance time tx
+	blk, err = vm.BuildBlock(context.Background()) // should be advance time tx
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -971,17 +1056,17 @@ func TestCreateSubnet(t *testing.T) {
 	}
 
 	// Accept the block
-	if err := block.Verify(); err != nil {
+	if err := block.Verify(context.Background()); err != nil {
 		t.Fatal(err)
 	}
-	block.Accept()
-	if err := commit.Verify(); err != nil {
+	block.Accept(context.Background())
+	if err := commit.Verify(context.Background()); err != nil {
 		t.Fatal(err)
 	}
-	if err := abort.Verify(); err != nil {
+	if err := abort.Verify(context.Background()); err != nil {
 		t.Fatal(err)
 	}
-	commit.Accept() // move validator addValidatorTx from pending to current
+	commit.Accept(context.Background()) // move validator addValidatorTx from pending to current
 
 	// Verify validator no longer in pending validator set
 	// Verify validator is in pending validator set
@@ -1011,7 +1096,7 @@ func TestCreateSubnet(t *testing.T) {
 	// fast forward clock to time validator should stop validating
 	vm.clock.Set(endTime)
 	vm.Ctx.Lock.Lock()
-	blk, err = vm.BuildBlock() // should be advance time tx
+	blk, err = vm.BuildBlock(context.Background()) // should be advance time tx
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -1031,17 +1116,17 @@ func TestCreateSubnet(t *testing.T) {
 	}
 
 	// Accept the block
-	if err := block.Verify(); err != nil {
+	if err := block.Verify(context.Background()); err != nil {
 		t.Fatal(err)
 	}
-	block.Accept()
-	if err := commit.Verify(); err != nil {
+	block.Accept(context.Background())
+	if err := commit.Verify(context.Background()); err != nil {
 		t.Fatal(err)
 	}
-	if err := abort.Verify(); err != nil {
+	if err := abort.Verify(context.Background()); err != nil {
 		t.Fatal(err)
 	}
-	commit.Accept() // remove validator from current validator set
+	commit.Accept(context.Background()) // remove validator from current validator set
 
 	// pending validators and current validator should be empty
 	pendingValidators, err = vm.getPendingValidators(vm.DB, createSubnetTx.ID)
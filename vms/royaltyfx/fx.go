@@ -0,0 +1,154 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package royaltyfx
+
+import (
+	"errors"
+
+	"github.com/ava-labs/gecko/utils/math"
+	"github.com/ava-labs/gecko/vms/components/verify"
+	"github.com/ava-labs/gecko/vms/secp256k1fx"
+)
+
+var (
+	errWrongTxType         = errors.New("wrong tx type")
+	errWrongUTXOType       = errors.New("wrong utxo type")
+	errWrongInputType      = errors.New("wrong input type")
+	errWrongCredentialType = errors.New("wrong credential type")
+
+	errWrongAmounts      = errors.New("input is consuming a different amount than expected")
+	errTimelocked        = errors.New("output is time locked")
+	errRoyaltyMismatch   = errors.New("outputs of the asset charge different royalties")
+	errRoyaltyNotCharged = errors.New("outputs produced of the asset must charge its royalty")
+	errRoyaltyUnpaid     = errors.New("transfer doesn't pay the royalty due")
+	errPaidOverflow      = errors.New("royalty paid overflows")
+	errCantOperate       = errors.New("this fx doesn't support operations")
+)
+
+// Tx is a transaction whose transfers of each asset can be inspected
+type Tx interface {
+	secp256k1fx.Tx
+
+	// SpentOutputs returns the outputs the tx consumes of the asset that [in]
+	// spends, including the one [in] spends
+	SpentOutputs(in interface{}) []interface{}
+
+	// ProducedOutputs returns the outputs the tx produces of the asset that
+	// [in] spends
+	ProducedOutputs(in interface{}) []interface{}
+}
+
+// Fx lets an asset's creator charge a royalty on every transfer of the asset.
+// An asset opts in by being created with TransferOutputs. Every transfer must
+// pay the royalty on the amount it spends to the royalty's recipient, in the
+// asset itself, and the outputs it produces of the asset must charge the same
+// royalty. Outputs the recipient owns alone are spent royalty free.
+type Fx struct {
+	secp256k1fx.Fx
+
+	vm secp256k1fx.VM
+}
+
+// Initialize ...
+func (fx *Fx) Initialize(vmIntf interface{}) error {
+	if err := fx.InitializeVM(vmIntf); err != nil {
+		return err
+	}
+
+	fx.vm = vmIntf.(secp256k1fx.VM)
+	c := fx.vm.Codec()
+	c.RegisterType(&TransferOutput{})
+	c.RegisterType(&TransferInput{})
+	c.RegisterType(&Credential{})
+	return nil
+}
+
+// VerifyOperation ...
+func (fx *Fx) VerifyOperation(_ interface{}, _, _, _, _ []interface{}) error {
+	return errCantOperate
+}
+
+// VerifyTransfer verifies that [inIntf] spends [utxoIntf] with the signatures
+// of its owners, and that the tx pays the royalty due on its transfer of the
+// asset
+func (fx *Fx) VerifyTransfer(txIntf, utxoIntf, inIntf, credIntf interface{}) error {
+	tx, ok := txIntf.(Tx)
+	if !ok {
+		return errWrongTxType
+	}
+	utxo, ok := utxoIntf.(*TransferOutput)
+	if !ok {
+		return errWrongUTXOType
+	}
+	in, ok := inIntf.(*TransferInput)
+	if !ok {
+		return errWrongInputType
+	}
+	cred, ok := credIntf.(*Credential)
+	if !ok {
+		return errWrongCredentialType
+	}
+	if err := verify.All(utxo, in, cred); err != nil {
+		return err
+	}
+
+	switch {
+	case utxo.Amt != in.Amt:
+		return errWrongAmounts
+	case utxo.Locktime > fx.vm.Clock().Unix():
+		return errTimelocked
+	}
+	if err := fx.VerifyCredentials(tx, &utxo.OutputOwners, &in.Input, &cred.Credential); err != nil {
+		return err
+	}
+	return verifyRoyalty(&utxo.Royalty, tx.SpentOutputs(in), tx.ProducedOutputs(in))
+}
+
+// verifyRoyalty verifies that a transfer that consumes [spent] and produces
+// [produced] of an asset that charges [royalty] pays it. Every input of the
+// asset checks the whole transfer, so the royalty is due once per transfer
+// rather than once per input.
+func verifyRoyalty(royalty *Royalty, spent, produced []interface{}) error {
+	due := uint64(0)
+	for _, outIntf := range spent {
+		out, ok := outIntf.(*TransferOutput)
+		if !ok {
+			// Outputs of other fxs don't charge a royalty
+			continue
+		}
+		if !out.Royalty.Equals(royalty) {
+			return errRoyaltyMismatch
+		}
+		if royalty.PaidBy(&out.OutputOwners) {
+			continue
+		}
+		outDue, err := royalty.Due(out.Amt)
+		if err != nil {
+			return err
+		}
+		if due, err = math.Add64(due, outDue); err != nil {
+			return errDueOverflow
+		}
+	}
+
+	paid := uint64(0)
+	for _, outIntf := range produced {
+		out, ok := outIntf.(*TransferOutput)
+		if !ok || !out.Royalty.Equals(royalty) {
+			return errRoyaltyNotCharged
+		}
+		if out.Locktime != 0 || !royalty.PaidBy(&out.OutputOwners) {
+			continue
+		}
+		amount, err := math.Add64(paid, out.Amt)
+		if err != nil {
+			return errPaidOverflow
+		}
+		paid = amount
+	}
+	if paid < due {
+		return errRoyaltyUnpaid
+	}
+	return nil
+}
@@ -0,0 +1,285 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package royaltyfx
+
+import (
+	"math"
+	"testing"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/vms/secp256k1fx"
+)
+
+type testTx struct {
+	secp256k1fx.TestTx
+	spent, produced []interface{}
+}
+
+func (tx *testTx) SpentOutputs(interface{}) []interface{} { return tx.spent }
+
+func (tx *testTx) ProducedOutputs(interface{}) []interface{} { return tx.produced }
+
+var recipient = ids.NewShortID([20]byte{9})
+
+// testRoyalty charges 5% plus 10 on every transfer
+func testRoyalty() Royalty {
+	return Royalty{
+		Recipient: recipient,
+		Rate:      500,
+		Fixed:     10,
+	}
+}
+
+func testOutput(amount uint64, addr ids.ShortID) *TransferOutput {
+	return &TransferOutput{
+		TransferOutput: secp256k1fx.TransferOutput{
+			Amt: amount,
+			OutputOwners: secp256k1fx.OutputOwners{
+				Threshold: 1,
+				Addrs:     []ids.ShortID{addr},
+			},
+		},
+		Royalty: testRoyalty(),
+	}
+}
+
+func testInput(amount uint64) *TransferInput {
+	return &TransferInput{
+		TransferInput: secp256k1fx.TransferInput{
+			Amt: amount,
+			Input: secp256k1fx.Input{
+				SigIndices: []uint32{0},
+			},
+		},
+	}
+}
+
+// verifyTransfer spends 1000 of the asset, owned by the signer, in a tx that
+// also spends [spent] and produces [produced]
+func verifyTransfer(t *testing.T, spent, produced []interface{}) error {
+	fx := Fx{}
+	if err := fx.Initialize(&secp256k1fx.TestVM{}); err != nil {
+		t.Fatal(err)
+	}
+	utxo := testOutput(1000, ids.NewShortID(secp256k1fx.TestAddrBytes))
+	tx := &testTx{
+		TestTx:   secp256k1fx.TestTx{Bytes: secp256k1fx.TestTxBytes},
+		spent:    append([]interface{}{utxo}, spent...),
+		produced: produced,
+	}
+	return fx.VerifyTransfer(tx, utxo, testInput(1000), &Credential{Credential: secp256k1fx.TestCredential()})
+}
+
+func TestFxInitialize(t *testing.T) {
+	fx := Fx{}
+	if err := fx.Initialize(&secp256k1fx.TestVM{}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFxInitializeInvalid(t *testing.T) {
+	fx := Fx{}
+	if err := fx.Initialize(nil); err == nil {
+		t.Fatalf("Should have returned an error")
+	}
+}
+
+func TestFxVerifyTransferPaysRoyalty(t *testing.T) {
+	err := verifyTransfer(t, nil, []interface{}{
+		testOutput(940, ids.NewShortID([20]byte{1})),
+		testOutput(60, recipient),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFxVerifyTransferUnpaid(t *testing.T) {
+	err := verifyTransfer(t, nil, []interface{}{
+		testOutput(941, ids.NewShortID([20]byte{1})),
+		testOutput(59, recipient),
+	})
+	if err != errRoyaltyUnpaid {
+		t.Fatalf("Should have errored with %s, errored with %v", errRoyaltyUnpaid, err)
+	}
+}
+
+func TestFxVerifyTransferLockedPayment(t *testing.T) {
+	payment := testOutput(60, recipient)
+	payment.Locktime = 1
+	err := verifyTransfer(t, nil, []interface{}{
+		testOutput(940, ids.NewShortID([20]byte{1})),
+		payment,
+	})
+	if err != errRoyaltyUnpaid {
+		t.Fatalf("Should have errored with %s, errored with %v", errRoyaltyUnpaid, err)
+	}
+}
+
+func TestFxVerifyTransferRoyaltyPerTransfer(t *testing.T) {
+	// Spending 2000 in two outputs costs 2 * (50 + 10)
+	err := verifyTransfer(t, []interface{}{testOutput(1000, ids.NewShortID([20]byte{1}))}, []interface{}{
+		testOutput(1880, ids.NewShortID([20]byte{1})),
+		testOutput(60, recipient),
+		testOutput(60, recipient),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = verifyTransfer(t, []interface{}{testOutput(1000, ids.NewShortID([20]byte{1}))}, []interface{}{
+		testOutput(1940, ids.NewShortID([20]byte{1})),
+		testOutput(60, recipient),
+	})
+	if err != errRoyaltyUnpaid {
+		t.Fatalf("Should have errored with %s, errored with %v", errRoyaltyUnpaid, err)
+	}
+}
+
+func TestFxVerifyTransferRecipientExempt(t *testing.T) {
+	// The recipient's outputs, and outputs of other fxs, are spent royalty
+	// free
+	err := verifyTransfer(t, []interface{}{
+		testOutput(1000, recipient),
+		&secp256k1fx.TransferOutput{Amt: 1000},
+	}, []interface{}{
+		testOutput(2940, ids.NewShortID([20]byte{1})),
+		testOutput(60, recipient),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFxVerifyTransferShedRoyalty(t *testing.T) {
+	err := verifyTransfer(t, nil, []interface{}{
+		&secp256k1fx.TransferOutput{
+			Amt: 940,
+			OutputOwners: secp256k1fx.OutputOwners{
+				Threshold: 1,
+				Addrs:     []ids.ShortID{ids.NewShortID([20]byte{1})},
+			},
+		},
+		testOutput(60, recipient),
+	})
+	if err != errRoyaltyNotCharged {
+		t.Fatalf("Should have errored with %s, errored with %v", errRoyaltyNotCharged, err)
+	}
+
+	cheaper := testOutput(940, ids.NewShortID([20]byte{1}))
+	cheaper.Royalty.Rate = 0
+	err = verifyTransfer(t, nil, []interface{}{
+		cheaper,
+		testOutput(60, recipient),
+	})
+	if err != errRoyaltyNotCharged {
+		t.Fatalf("Should have errored with %s, errored with %v", errRoyaltyNotCharged, err)
+	}
+}
+
+func TestFxVerifyTransferRoyaltyMismatch(t *testing.T) {
+	other := testOutput(1000, ids.NewShortID([20]byte{1}))
+	other.Royalty.Fixed = 0
+	err := verifyTransfer(t, []interface{}{other}, []interface{}{
+		testOutput(2000, recipient),
+	})
+	if err != errRoyaltyMismatch {
+		t.Fatalf("Should have errored with %s, errored with %v", errRoyaltyMismatch, err)
+	}
+}
+
+func TestFxVerifyTransferWrongSigner(t *testing.T) {
+	fx := Fx{}
+	if err := fx.Initialize(&secp256k1fx.TestVM{}); err != nil {
+		t.Fatal(err)
+	}
+	utxo := testOutput(1000, ids.NewShortID([20]byte{1}))
+	tx := &testTx{
+		TestTx:   secp256k1fx.TestTx{Bytes: secp256k1fx.TestTxBytes},
+		spent:    []interface{}{utxo},
+		produced: []interface{}{testOutput(940, recipient)},
+	}
+	if err := fx.VerifyTransfer(tx, utxo, testInput(1000), &Credential{Credential: secp256k1fx.TestCredential()}); err == nil {
+		t.Fatalf("Should have errored due to the wrong signer")
+	}
+}
+
+func TestFxVerifyTransferWrongAmounts(t *testing.T) {
+	fx := Fx{}
+	if err := fx.Initialize(&secp256k1fx.TestVM{}); err != nil {
+		t.Fatal(err)
+	}
+	utxo := testOutput(1000, ids.NewShortID(secp256k1fx.TestAddrBytes))
+	tx := &testTx{
+		TestTx: secp256k1fx.TestTx{Bytes: secp256k1fx.TestTxBytes},
+		spent:  []interface{}{utxo},
+	}
+	if err := fx.VerifyTransfer(tx, utxo, testInput(999), &Credential{Credential: secp256k1fx.TestCredential()}); err != errWrongAmounts {
+		t.Fatalf("Should have errored with %s, errored with %v", errWrongAmounts, err)
+	}
+}
+
+func TestFxVerifyTransferWrongTxType(t *testing.T) {
+	fx := Fx{}
+	if err := fx.Initialize(&secp256k1fx.TestVM{}); err != nil {
+		t.Fatal(err)
+	}
+	utxo := testOutput(1000, ids.NewShortID(secp256k1fx.TestAddrBytes))
+	tx := &secp256k1fx.TestTx{Bytes: secp256k1fx.TestTxBytes}
+	if err := fx.VerifyTransfer(tx, utxo, testInput(1000), &Credential{Credential: secp256k1fx.TestCredential()}); err != errWrongTxType {
+		t.Fatalf("Should have errored with %s, errored with %v", errWrongTxType, err)
+	}
+}
+
+func TestFxVerifyOperation(t *testing.T) {
+	fx := Fx{}
+	if err := fx.Initialize(&secp256k1fx.TestVM{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := fx.VerifyOperation(nil, nil, nil, nil, nil); err != errCantOperate {
+		t.Fatalf("Should have errored with %s, errored with %v", errCantOperate, err)
+	}
+}
+
+func TestRoyaltyVerify(t *testing.T) {
+	royalty := testRoyalty()
+	if err := royalty.Verify(); err != nil {
+		t.Fatal(err)
+	}
+
+	noRecipient := testRoyalty()
+	noRecipient.Recipient = ids.ShortEmpty
+	if err := noRecipient.Verify(); err != errNoRecipient {
+		t.Fatalf("Should have errored with %s, errored with %v", errNoRecipient, err)
+	}
+
+	tooHigh := testRoyalty()
+	tooHigh.Rate = RateDenominator + 1
+	if err := tooHigh.Verify(); err != errRateTooHigh {
+		t.Fatalf("Should have errored with %s, errored with %v", errRateTooHigh, err)
+	}
+
+	free := Royalty{Recipient: recipient}
+	if err := free.Verify(); err != errNoRoyalty {
+		t.Fatalf("Should have errored with %s, errored with %v", errNoRoyalty, err)
+	}
+}
+
+func TestRoyaltyDue(t *testing.T) {
+	royalty := testRoyalty()
+	if due, err := royalty.Due(1019); err != nil || due != 60 {
+		t.Fatalf("Expected 60 due but got %d, %v", due, err)
+	}
+
+	royalty.Rate = RateDenominator
+	royalty.Fixed = 0
+	if due, err := royalty.Due(math.MaxUint64); err != nil || due != math.MaxUint64 {
+		t.Fatalf("Expected %d due but got %d, %v", uint64(math.MaxUint64), due, err)
+	}
+
+	royalty.Fixed = 1
+	if _, err := royalty.Due(math.MaxUint64); err != errDueOverflow {
+		t.Fatalf("Should have errored with %s, errored with %v", errDueOverflow, err)
+	}
+}
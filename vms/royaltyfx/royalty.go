@@ -0,0 +1,70 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package royaltyfx
+
+import (
+	"errors"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/math"
+	"github.com/ava-labs/gecko/vms/secp256k1fx"
+)
+
+// RateDenominator is the denominator of royalty rates, so rates are in
+// hundredths of a percent
+const RateDenominator = 10000
+
+var (
+	errNoRecipient = errors.New("royalty must have a recipient")
+	errRateTooHigh = errors.New("royalty rate can't exceed 100%")
+	errNoRoyalty   = errors.New("royalty must have a rate or a fixed amount")
+	errDueOverflow = errors.New("royalty due overflows")
+)
+
+// Royalty is paid to [Recipient] every time its asset is transferred. Spending
+// X of the asset costs [Fixed] + X * [Rate] / RateDenominator, paid in the
+// asset itself.
+type Royalty struct {
+	Recipient ids.ShortID `serialize:"true"`
+	Rate      uint32      `serialize:"true"`
+	Fixed     uint64      `serialize:"true"`
+}
+
+// Equals returns true if [r] and [other] are the same royalty
+func (r *Royalty) Equals(other *Royalty) bool {
+	return r.Recipient.Equals(other.Recipient) && r.Rate == other.Rate && r.Fixed == other.Fixed
+}
+
+// Due returns the royalty due for spending [amount] of the asset. Rounds
+// down.
+func (r *Royalty) Due(amount uint64) (uint64, error) {
+	// Split [amount] so that the multiplication can't overflow
+	rate := uint64(r.Rate)
+	due := amount/RateDenominator*rate + amount%RateDenominator*rate/RateDenominator
+	due, err := math.Add64(due, r.Fixed)
+	if err != nil {
+		return 0, errDueOverflow
+	}
+	return due, nil
+}
+
+// PaidBy returns true if [owners] pays the royalty: the output is immediately
+// spendable by the recipient alone
+func (r *Royalty) PaidBy(owners *secp256k1fx.OutputOwners) bool {
+	return owners.Threshold == 1 && len(owners.Addrs) == 1 && owners.Addrs[0].Equals(r.Recipient)
+}
+
+// Verify ...
+func (r *Royalty) Verify() error {
+	switch {
+	case r.Recipient.IsZero() || r.Recipient.Equals(ids.ShortEmpty):
+		return errNoRecipient
+	case r.Rate > RateDenominator:
+		return errRateTooHigh
+	case r.Rate == 0 && r.Fixed == 0:
+		return errNoRoyalty
+	default:
+		return nil
+	}
+}
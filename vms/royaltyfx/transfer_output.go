@@ -0,0 +1,33 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package royaltyfx
+
+import (
+	"errors"
+
+	"github.com/ava-labs/gecko/vms/secp256k1fx"
+)
+
+var (
+	errNilOutput = errors.New("nil output")
+)
+
+// TransferOutput is a secp256k1fx transfer output of an asset that charges a
+// royalty on every transfer. The outputs a transfer produces of the asset must
+// charge the same royalty, so the royalty can't be shed.
+type TransferOutput struct {
+	secp256k1fx.TransferOutput `serialize:"true"`
+	Royalty                    Royalty `serialize:"true"`
+}
+
+// Verify ...
+func (out *TransferOutput) Verify() error {
+	if out == nil {
+		return errNilOutput
+	}
+	if err := out.TransferOutput.Verify(); err != nil {
+		return err
+	}
+	return out.Royalty.Verify()
+}
@@ -23,7 +23,16 @@ func (cr *Credential) Verify() error {
 	switch {
 	case cr == nil:
 		return errNilCredential
-	default:
+	case !crypto.EnableCrypto:
 		return nil
 	}
+
+	// Reject signatures that aren't canonically encoded, so that the ID of the
+	// transaction can't be changed by re-encoding its signatures
+	for _, sig := range cr.Sigs {
+		if err := crypto.VerifySECP256K1RSignatureFormat(sig[:]); err != nil {
+			return err
+		}
+	}
+	return nil
 }
@@ -92,3 +92,29 @@ func TestCredentialSerialize(t *testing.T) {
 		t.Fatalf("\nExpected: 0x%x\nResult:   0x%x", expected, result)
 	}
 }
+
+func TestCredentialVerifyNonCanonicalSig(t *testing.T) {
+	factory := crypto.FactorySECP256K1R{}
+	key, err := factory.NewPrivateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sigBytes, err := key.Sign([]byte{1, 2, 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sig := [crypto.SECP256K1RSigLen]byte{}
+	copy(sig[:], sigBytes)
+	cred := Credential{Sigs: [][crypto.SECP256K1RSigLen]byte{sig}}
+	if err := cred.Verify(); err != nil {
+		t.Fatal(err)
+	}
+
+	// An invalid recovery ID
+	sig[crypto.SECP256K1RSigLen-1] = 2
+	cred = Credential{Sigs: [][crypto.SECP256K1RSigLen]byte{sig}}
+	if err := cred.Verify(); err == nil {
+		t.Fatalf("Should have errored due to a non-canonical signature")
+	}
+}
@@ -41,18 +41,26 @@ type Fx struct {
 
 // Initialize ...
 func (fx *Fx) Initialize(vmIntf interface{}) error {
-	vm, ok := vmIntf.(VM)
-	if !ok {
-		return errWrongVMType
+	if err := fx.InitializeVM(vmIntf); err != nil {
+		return err
 	}
 
-	c := vm.Codec()
+	c := fx.vm.Codec()
 	c.RegisterType(&MintOutput{})
 	c.RegisterType(&TransferOutput{})
 	c.RegisterType(&MintInput{})
 	c.RegisterType(&TransferInput{})
 	c.RegisterType(&Credential{})
+	return nil
+}
 
+// InitializeVM sets the VM this Fx runs under without registering this Fx's
+// types. This allows other Fxs to reuse this Fx's verification.
+func (fx *Fx) InitializeVM(vmIntf interface{}) error {
+	vm, ok := vmIntf.(VM)
+	if !ok {
+		return errWrongVMType
+	}
 	fx.vm = vm
 	return nil
 }
@@ -107,7 +115,7 @@ func (fx *Fx) verifyOperation(tx Tx, utxo *MintOutput, in *MintInput, cred *Cred
 		return errWrongMintCreated
 	}
 
-	return fx.verifyCredentials(tx, &utxo.OutputOwners, &in.Input, cred)
+	return fx.VerifyCredentials(tx, &utxo.OutputOwners, &in.Input, cred)
 }
 
 // VerifyTransfer ...
@@ -144,10 +152,12 @@ func (fx *Fx) verifyTransfer(tx Tx, utxo *TransferOutput, in *TransferInput, cre
 		return errTimelocked
 	}
 
-	return fx.verifyCredentials(tx, &utxo.OutputOwners, &in.Input, cred)
+	return fx.VerifyCredentials(tx, &utxo.OutputOwners, &in.Input, cred)
 }
 
-func (fx *Fx) verifyCredentials(tx Tx, out *OutputOwners, in *Input, cred *Credential) error {
+// VerifyCredentials returns nil iff [cred] holds the signatures of [out]'s
+// owners that [in] claims over [tx]'s unsigned bytes
+func (fx *Fx) VerifyCredentials(tx Tx, out *OutputOwners, in *Input, cred *Credential) error {
 	numSigs := len(in.SigIndices)
 	switch {
 	case out.Threshold < uint32(numSigs):
@@ -158,8 +168,12 @@ func (fx *Fx) verifyCredentials(tx Tx, out *OutputOwners, in *Input, cred *Crede
 		return errInputCredentialSignersMismatch
 	}
 
-	txBytes := tx.UnsignedBytes()
-	txHash := hashing.ComputeHash256(txBytes)
+	var txHash []byte
+	if hashedTx, ok := tx.(HashedTx); ok {
+		txHash = hashedTx.UnsignedHash()
+	} else {
+		txHash = hashing.ComputeHash256(tx.UnsignedBytes())
+	}
 
 	for i, index := range in.SigIndices {
 		sig := cred.Sigs[i]
@@ -0,0 +1,78 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package secp256k1fx
+
+import (
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/crypto"
+	"github.com/ava-labs/gecko/utils/hashing"
+	"github.com/ava-labs/gecko/utils/timer"
+	"github.com/ava-labs/gecko/vms/components/codec"
+)
+
+var (
+	// TestTxBytes are the unsigned bytes of a tx signed with TestSigBytes
+	TestTxBytes = []byte{0, 1, 2, 3, 4, 5}
+
+	// TestSigBytes is the signature of TestTxBytes by the key of TestAddrBytes
+	TestSigBytes = [crypto.SECP256K1RSigLen]byte{
+		0x0e, 0x33, 0x4e, 0xbc, 0x67, 0xa7, 0x3f, 0xe8,
+		0x24, 0x33, 0xac, 0xa3, 0x47, 0x88, 0xa6, 0x3d,
+		0x58, 0xe5, 0x8e, 0xf0, 0x3a, 0xd5, 0x84, 0xf1,
+		0xbc, 0xa3, 0xb2, 0xd2, 0x5d, 0x51, 0xd6, 0x9b,
+		0x0f, 0x28, 0x5d, 0xcd, 0x3f, 0x71, 0x17, 0x0a,
+		0xf9, 0xbf, 0x2d, 0xb1, 0x10, 0x26, 0x5c, 0xe9,
+		0xdc, 0xc3, 0x9d, 0x7a, 0x01, 0x50, 0x9d, 0xe8,
+		0x35, 0xbd, 0xcb, 0x29, 0x3a, 0xd1, 0x49, 0x32,
+		0x00,
+	}
+
+	// TestAddrBytes is the address of the key that signed TestTxBytes
+	TestAddrBytes = [hashing.AddrLen]byte{
+		0x01, 0x5c, 0xce, 0x6c, 0x55, 0xd6, 0xb5, 0x09,
+		0x84, 0x5c, 0x8c, 0x4e, 0x30, 0xbe, 0xd9, 0x8d,
+		0x39, 0x1a, 0xe7, 0xf0,
+	}
+)
+
+// TestVM is a VM that fxs can be initialized with in tests
+type TestVM struct{ clock timer.Clock }
+
+// Codec ...
+func (vm *TestVM) Codec() codec.Codec { return codec.NewDefault() }
+
+// Clock ...
+func (vm *TestVM) Clock() *timer.Clock { return &vm.clock }
+
+// TestTx is a tx whose unsigned bytes are [Bytes]
+type TestTx struct{ Bytes []byte }
+
+// UnsignedBytes ...
+func (tx *TestTx) UnsignedBytes() []byte { return tx.Bytes }
+
+// TestOwners returns owners that the key of TestAddrBytes controls alone
+func TestOwners() OutputOwners {
+	return OutputOwners{
+		Threshold: 1,
+		Addrs: []ids.ShortID{
+			ids.NewShortID(TestAddrBytes),
+		},
+	}
+}
+
+// TestInput returns an input signed by the first of the spent output's owners
+func TestInput() Input {
+	return Input{
+		SigIndices: []uint32{0},
+	}
+}
+
+// TestCredential returns a credential that signs TestTxBytes for TestOwners
+func TestCredential() Credential {
+	return Credential{
+		Sigs: [][crypto.SECP256K1RSigLen]byte{
+			TestSigBytes,
+		},
+	}
+}
@@ -7,3 +7,10 @@ package secp256k1fx
 type Tx interface {
 	UnsignedBytes() []byte
 }
+
+// HashedTx is a Tx that caches the hash of its unsigned bytes, so that the hash
+// isn't recomputed for every credential that signs it
+type HashedTx interface {
+	Tx
+	UnsignedHash() []byte
+}
@@ -0,0 +1,38 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package sessionfx
+
+import (
+	"errors"
+
+	"github.com/ava-labs/gecko/vms/secp256k1fx"
+)
+
+var (
+	errNilInput     = errors.New("nil input")
+	errNoValueInput = errors.New("input has no value")
+)
+
+// DelegationInput spends a DelegationOutput. If [Session] is set, the
+// signatures are from the output's session owners rather than its owners.
+type DelegationInput struct {
+	Amt               uint64 `serialize:"true"`
+	Session           bool   `serialize:"true"`
+	secp256k1fx.Input `serialize:"true"`
+}
+
+// Amount returns the quantity of the asset this input produces
+func (in *DelegationInput) Amount() uint64 { return in.Amt }
+
+// Verify this input is syntactically valid
+func (in *DelegationInput) Verify() error {
+	switch {
+	case in == nil:
+		return errNilInput
+	case in.Amt == 0:
+		return errNoValueInput
+	default:
+		return in.Input.Verify()
+	}
+}
@@ -0,0 +1,56 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package sessionfx
+
+import (
+	"errors"
+
+	"github.com/ava-labs/gecko/vms/secp256k1fx"
+)
+
+var (
+	errNilOutput     = errors.New("nil output")
+	errNoValueOutput = errors.New("output has no value")
+	errNoSessionKeys = errors.New("output must require at least one session key signature")
+)
+
+// DelegationOutput can be spent by its owners at any time after [Locktime].
+// Until [Expiry], it can also be spent by the [Session] owners. This lets a
+// cold key hand an ephemeral key the right to spend at most [Amt] for a
+// limited time without giving up control of the funds.
+type DelegationOutput struct {
+	Amt      uint64 `serialize:"true"`
+	Locktime uint64 `serialize:"true"`
+
+	secp256k1fx.OutputOwners `serialize:"true"`
+
+	// Unix time at which the session owners can no longer spend this output
+	Expiry  uint64                   `serialize:"true"`
+	Session secp256k1fx.OutputOwners `serialize:"true"`
+}
+
+// Amount returns the quantity of the asset this output consumes
+func (out *DelegationOutput) Amount() uint64 { return out.Amt }
+
+// Addresses returns the addresses that can spend this output, including the
+// session addresses
+func (out *DelegationOutput) Addresses() [][]byte {
+	return append(out.OutputOwners.Addresses(), out.Session.Addresses()...)
+}
+
+// Verify ...
+func (out *DelegationOutput) Verify() error {
+	switch {
+	case out == nil:
+		return errNilOutput
+	case out.Amt == 0:
+		return errNoValueOutput
+	case out.Session.Threshold == 0:
+		return errNoSessionKeys
+	}
+	if err := out.OutputOwners.Verify(); err != nil {
+		return err
+	}
+	return out.Session.Verify()
+}
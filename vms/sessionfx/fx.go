@@ -0,0 +1,92 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package sessionfx
+
+import (
+	"errors"
+
+	"github.com/ava-labs/gecko/vms/components/verify"
+	"github.com/ava-labs/gecko/vms/secp256k1fx"
+)
+
+var (
+	errWrongTxType         = errors.New("wrong tx type")
+	errWrongUTXOType       = errors.New("wrong utxo type")
+	errWrongInputType      = errors.New("wrong input type")
+	errWrongCredentialType = errors.New("wrong credential type")
+
+	errWrongAmounts = errors.New("input is consuming a different amount than expected")
+	errTimelocked   = errors.New("output is time locked")
+	errSessionOver  = errors.New("session keys can no longer spend this output")
+	errCantOperate  = errors.New("this fx doesn't support operations")
+)
+
+// Fx lets owners of funds delegate spending of them to ephemeral session keys.
+// A DelegationOutput can be spent by its session keys until it expires, and by
+// its owners at any time. Each input says whether it's signed by the session
+// keys or the owners, so the two are never mixed in one spend.
+type Fx struct {
+	secp256k1fx.Fx
+
+	vm secp256k1fx.VM
+}
+
+// Initialize ...
+func (fx *Fx) Initialize(vmIntf interface{}) error {
+	if err := fx.InitializeVM(vmIntf); err != nil {
+		return err
+	}
+
+	fx.vm = vmIntf.(secp256k1fx.VM)
+	c := fx.vm.Codec()
+	c.RegisterType(&DelegationOutput{})
+	c.RegisterType(&DelegationInput{})
+	c.RegisterType(&Credential{})
+	return nil
+}
+
+// VerifyOperation ...
+func (fx *Fx) VerifyOperation(_ interface{}, _, _, _, _ []interface{}) error {
+	return errCantOperate
+}
+
+// VerifyTransfer verifies that [inIntf] spends [utxoIntf] with the signatures
+// of either its owners or, if it hasn't expired, its session owners
+func (fx *Fx) VerifyTransfer(txIntf, utxoIntf, inIntf, credIntf interface{}) error {
+	tx, ok := txIntf.(secp256k1fx.Tx)
+	if !ok {
+		return errWrongTxType
+	}
+	utxo, ok := utxoIntf.(*DelegationOutput)
+	if !ok {
+		return errWrongUTXOType
+	}
+	in, ok := inIntf.(*DelegationInput)
+	if !ok {
+		return errWrongInputType
+	}
+	cred, ok := credIntf.(*Credential)
+	if !ok {
+		return errWrongCredentialType
+	}
+	if err := verify.All(utxo, in, cred); err != nil {
+		return err
+	}
+
+	now := fx.vm.Clock().Unix()
+	switch {
+	case utxo.Amt != in.Amt:
+		return errWrongAmounts
+	case utxo.Locktime > now:
+		return errTimelocked
+	case in.Session && utxo.Expiry <= now:
+		return errSessionOver
+	}
+
+	owners := &utxo.OutputOwners
+	if in.Session {
+		owners = &utxo.Session
+	}
+	return fx.VerifyCredentials(tx, owners, &in.Input, &cred.Credential)
+}
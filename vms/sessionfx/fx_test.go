@@ -0,0 +1,206 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package sessionfx
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/vms/secp256k1fx"
+)
+
+func testSessionOwners() secp256k1fx.OutputOwners {
+	return secp256k1fx.OutputOwners{
+		Threshold: 1,
+		Addrs: []ids.ShortID{
+			ids.NewShortID([20]byte{1}),
+			ids.NewShortID(secp256k1fx.TestAddrBytes),
+		},
+	}
+}
+
+func testDelegationOutput() *DelegationOutput {
+	return &DelegationOutput{
+		Amt: 1,
+		OutputOwners: secp256k1fx.OutputOwners{
+			Threshold: 1,
+			Addrs: []ids.ShortID{
+				ids.NewShortID([20]byte{2}),
+			},
+		},
+		Expiry:  2,
+		Session: testSessionOwners(),
+	}
+}
+
+func testSessionInput() *DelegationInput {
+	return &DelegationInput{
+		Amt:     1,
+		Session: true,
+		Input: secp256k1fx.Input{
+			SigIndices: []uint32{1},
+		},
+	}
+}
+
+func TestFxInitialize(t *testing.T) {
+	fx := Fx{}
+	if err := fx.Initialize(&secp256k1fx.TestVM{}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFxInitializeInvalid(t *testing.T) {
+	fx := Fx{}
+	if err := fx.Initialize(nil); err == nil {
+		t.Fatalf("Should have returned an error")
+	}
+}
+
+func TestFxVerifyTransferSession(t *testing.T) {
+	vm := &secp256k1fx.TestVM{}
+	vm.Clock().Set(time.Unix(1, 0))
+	fx := &Fx{}
+	if err := fx.Initialize(vm); err != nil {
+		t.Fatal(err)
+	}
+	tx := &secp256k1fx.TestTx{Bytes: secp256k1fx.TestTxBytes}
+
+	if err := fx.VerifyTransfer(tx, testDelegationOutput(), testSessionInput(), &Credential{Credential: secp256k1fx.TestCredential()}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFxVerifyTransferSessionExpired(t *testing.T) {
+	vm := &secp256k1fx.TestVM{}
+	vm.Clock().Set(time.Unix(2, 0))
+	fx := &Fx{}
+	if err := fx.Initialize(vm); err != nil {
+		t.Fatal(err)
+	}
+	tx := &secp256k1fx.TestTx{Bytes: secp256k1fx.TestTxBytes}
+
+	if err := fx.VerifyTransfer(tx, testDelegationOutput(), testSessionInput(), &Credential{Credential: secp256k1fx.TestCredential()}); err != errSessionOver {
+		t.Fatalf("Should have errored with %s, errored with %v", errSessionOver, err)
+	}
+}
+
+func TestFxVerifyTransferOwnerAfterExpiry(t *testing.T) {
+	vm := &secp256k1fx.TestVM{}
+	vm.Clock().Set(time.Unix(3, 0))
+	fx := &Fx{}
+	if err := fx.Initialize(vm); err != nil {
+		t.Fatal(err)
+	}
+	tx := &secp256k1fx.TestTx{Bytes: secp256k1fx.TestTxBytes}
+	utxo := testDelegationOutput()
+	utxo.OutputOwners = secp256k1fx.TestOwners()
+	in := &DelegationInput{
+		Amt:   1,
+		Input: secp256k1fx.TestInput(),
+	}
+
+	if err := fx.VerifyTransfer(tx, utxo, in, &Credential{Credential: secp256k1fx.TestCredential()}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFxVerifyTransferOwnerWithSessionKey(t *testing.T) {
+	fx := Fx{}
+	if err := fx.Initialize(&secp256k1fx.TestVM{}); err != nil {
+		t.Fatal(err)
+	}
+	tx := &secp256k1fx.TestTx{Bytes: secp256k1fx.TestTxBytes}
+	in := testSessionInput()
+	in.Session = false
+	in.SigIndices = []uint32{0}
+
+	if err := fx.VerifyTransfer(tx, testDelegationOutput(), in, &Credential{Credential: secp256k1fx.TestCredential()}); err == nil {
+		t.Fatalf("Should have errored because the session key isn't an owner")
+	}
+}
+
+func TestFxVerifyTransferWrongAmount(t *testing.T) {
+	fx := Fx{}
+	if err := fx.Initialize(&secp256k1fx.TestVM{}); err != nil {
+		t.Fatal(err)
+	}
+	tx := &secp256k1fx.TestTx{Bytes: secp256k1fx.TestTxBytes}
+	in := testSessionInput()
+	in.Amt = 2
+
+	if err := fx.VerifyTransfer(tx, testDelegationOutput(), in, &Credential{Credential: secp256k1fx.TestCredential()}); err != errWrongAmounts {
+		t.Fatalf("Should have errored with %s, errored with %v", errWrongAmounts, err)
+	}
+}
+
+func TestFxVerifyTransferTimelocked(t *testing.T) {
+	fx := Fx{}
+	if err := fx.Initialize(&secp256k1fx.TestVM{}); err != nil {
+		t.Fatal(err)
+	}
+	tx := &secp256k1fx.TestTx{Bytes: secp256k1fx.TestTxBytes}
+	utxo := testDelegationOutput()
+	utxo.Locktime = uint64(time.Now().Add(time.Hour).Unix())
+
+	if err := fx.VerifyTransfer(tx, utxo, testSessionInput(), &Credential{Credential: secp256k1fx.TestCredential()}); err != errTimelocked {
+		t.Fatalf("Should have errored with %s, errored with %v", errTimelocked, err)
+	}
+}
+
+func TestFxVerifyTransferWrongTypes(t *testing.T) {
+	fx := Fx{}
+	if err := fx.Initialize(&secp256k1fx.TestVM{}); err != nil {
+		t.Fatal(err)
+	}
+	tx := &secp256k1fx.TestTx{Bytes: secp256k1fx.TestTxBytes}
+
+	if err := fx.VerifyTransfer(nil, testDelegationOutput(), testSessionInput(), &Credential{Credential: secp256k1fx.TestCredential()}); err != errWrongTxType {
+		t.Fatalf("Should have errored with %s, errored with %v", errWrongTxType, err)
+	}
+	if err := fx.VerifyTransfer(tx, nil, testSessionInput(), &Credential{Credential: secp256k1fx.TestCredential()}); err != errWrongUTXOType {
+		t.Fatalf("Should have errored with %s, errored with %v", errWrongUTXOType, err)
+	}
+	if err := fx.VerifyTransfer(tx, testDelegationOutput(), nil, &Credential{Credential: secp256k1fx.TestCredential()}); err != errWrongInputType {
+		t.Fatalf("Should have errored with %s, errored with %v", errWrongInputType, err)
+	}
+	if err := fx.VerifyTransfer(tx, testDelegationOutput(), testSessionInput(), nil); err != errWrongCredentialType {
+		t.Fatalf("Should have errored with %s, errored with %v", errWrongCredentialType, err)
+	}
+}
+
+func TestDelegationOutputVerify(t *testing.T) {
+	out := testDelegationOutput()
+	if err := out.Verify(); err != nil {
+		t.Fatal(err)
+	}
+
+	out.Session = secp256k1fx.OutputOwners{}
+	if err := out.Verify(); err != errNoSessionKeys {
+		t.Fatalf("Should have errored with %s, errored with %v", errNoSessionKeys, err)
+	}
+
+	out = testDelegationOutput()
+	out.Amt = 0
+	if err := out.Verify(); err != errNoValueOutput {
+		t.Fatalf("Should have errored with %s, errored with %v", errNoValueOutput, err)
+	}
+}
+
+func TestDelegationOutputAddresses(t *testing.T) {
+	if addrs := testDelegationOutput().Addresses(); len(addrs) != 3 {
+		t.Fatalf("Expected the owner and session addresses but got %d addresses", len(addrs))
+	}
+}
+
+func TestFxVerifyOperation(t *testing.T) {
+	fx := Fx{}
+	if err := fx.Initialize(&secp256k1fx.TestVM{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := fx.VerifyOperation(nil, nil, nil, nil, nil); err != errCantOperate {
+		t.Fatalf("Should have errored with %s, errored with %v", errCantOperate, err)
+	}
+}
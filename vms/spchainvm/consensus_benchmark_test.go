@@ -1,6 +1,7 @@
 package spchainvm
 
 import (
+	"context"
 	"sync"
 	"testing"
 	"time"
@@ -15,6 +16,7 @@ import (
 	"github.com/ava-labs/gecko/snow/consensus/snowball"
 	"github.com/ava-labs/gecko/snow/engine/common"
 	"github.com/ava-labs/gecko/snow/engine/common/queue"
+	"github.com/ava-labs/gecko/snow/networking"
 	"github.com/ava-labs/gecko/snow/networking/handler"
 	"github.com/ava-labs/gecko/snow/networking/router"
 	"github.com/ava-labs/gecko/snow/networking/sender"
@@ -76,7 +78,7 @@ func ConsensusLeader(numBlocks, numTxsPerBlock int, b *testing.B) {
 		// Passes messages from the consensus engine to the network
 		sender := sender.Sender{}
 
-		sender.Initialize(ctx, externalSender, router, &timeoutManager)
+		sender.Initialize(ctx, externalSender, router, &timeoutManager, &networking.Reputation{}, context.Background())
 
 		// The engine handles consensus
 		engine := smeng.Transitive{}
@@ -86,7 +88,7 @@ func ConsensusLeader(numBlocks, numTxsPerBlock int, b *testing.B) {
 					Context:    ctx,
 					Validators: vdrs,
 					Beacons:    beacons,
-					Alpha:      (beacons.Len() + 1) / 2,
+					Alpha:      (beacons.Weight() + 1) / 2,
 					Sender:     &sender,
 				},
 				Blocked: blocked,
@@ -104,7 +106,7 @@ func ConsensusLeader(numBlocks, numTxsPerBlock int, b *testing.B) {
 
 		// Asynchronously passes messages from the network to the consensus engine
 		handler := &handler.Handler{}
-		handler.Initialize(&engine, msgChan, 1000)
+		handler.Initialize(&engine, msgChan, 1000, 0, 0, nil, "", prometheus.NewRegistry())
 
 		// Allow incoming messages to be routed to the new chain
 		router.AddChain(handler)
@@ -207,7 +209,7 @@ func ConsensusFollower(numBlocks, numTxsPerBlock int, b *testing.B) {
 		// Passes messages from the consensus engine to the network
 		sender := sender.Sender{}
 
-		sender.Initialize(ctx, externalSender, router, &timeoutManager)
+		sender.Initialize(ctx, externalSender, router, &timeoutManager, &networking.Reputation{}, context.Background())
 
 		// The engine handles consensus
 		engine := smeng.Transitive{}
@@ -217,7 +219,7 @@ func ConsensusFollower(numBlocks, numTxsPerBlock int, b *testing.B) {
 					Context:    ctx,
 					Validators: vdrs,
 					Beacons:    beacons,
-					Alpha:      (beacons.Len() + 1) / 2,
+					Alpha:      (beacons.Weight() + 1) / 2,
 					Sender:     &sender,
 				},
 				Blocked: blocked,
@@ -235,7 +237,7 @@ func ConsensusFollower(numBlocks, numTxsPerBlock int, b *testing.B) {
 
 		// Asynchronously passes messages from the network to the consensus engine
 		handler := &handler.Handler{}
-		handler.Initialize(&engine, msgChan, 1000)
+		handler.Initialize(&engine, msgChan, 1000, 0, 0, nil, "", prometheus.NewRegistry())
 
 		// Allow incoming messages to be routed to the new chain
 		router.AddChain(handler)
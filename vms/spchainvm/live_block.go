@@ -4,6 +4,7 @@
 package spchainvm
 
 import (
+	"context"
 	"errors"
 
 	"github.com/ava-labs/gecko/database"
@@ -46,7 +47,7 @@ type LiveBlock struct {
 func (lb *LiveBlock) ID() ids.ID { return lb.block.id }
 
 // Accept is called when this block is finalized as accepted by consensus
-func (lb *LiveBlock) Accept() {
+func (lb *LiveBlock) Accept(context.Context) {
 	bID := lb.ID()
 	lb.vm.ctx.Log.Debug("Accepted block %s", bID)
 
@@ -142,8 +143,16 @@ func (lb *LiveBlock) parentBlock() *LiveBlock {
 // Bytes returns the binary representation of this transaction
 func (lb *LiveBlock) Bytes() []byte { return lb.block.Bytes() }
 
-// Verify the validity of this block
-func (lb *LiveBlock) Verify() error {
+// Verify the validity of this block. Verification is abandoned once [ctx] is
+// done.
+func (lb *LiveBlock) Verify(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return lb.verify()
+}
+
+func (lb *LiveBlock) verify() error {
 	switch status := lb.Status(); status {
 	case choices.Accepted:
 		return nil
@@ -176,7 +185,7 @@ func (lb *LiveBlock) VerifyState() error {
 		return errMissingBlock
 	}
 
-	if err := parent.Verify(); err != nil {
+	if err := parent.verify(); err != nil {
 		return err
 	}
 
@@ -4,6 +4,7 @@
 package spchainvm
 
 import (
+	"context"
 	"errors"
 	"time"
 
@@ -125,7 +126,7 @@ func (vm *VM) Shutdown() {
 }
 
 // BuildBlock implements the snowman.ChainVM interface
-func (vm *VM) BuildBlock() (snowman.Block, error) {
+func (vm *VM) BuildBlock(context.Context) (snowman.Block, error) {
 	vm.timer.Cancel()
 
 	if len(vm.txs) == 0 {
@@ -158,7 +159,7 @@ func (vm *VM) BuildBlock() (snowman.Block, error) {
 }
 
 // ParseBlock implements the snowman.ChainVM interface
-func (vm *VM) ParseBlock(b []byte) (snowman.Block, error) {
+func (vm *VM) ParseBlock(_ context.Context, b []byte) (snowman.Block, error) {
 	c := Codec{}
 	rawBlock, err := c.UnmarshalBlock(b)
 	if err != nil {
@@ -173,7 +174,7 @@ func (vm *VM) ParseBlock(b []byte) (snowman.Block, error) {
 }
 
 // GetBlock implements the snowman.ChainVM interface
-func (vm *VM) GetBlock(id ids.ID) (snowman.Block, error) {
+func (vm *VM) GetBlock(_ context.Context, id ids.ID) (snowman.Block, error) {
 	blk, err := vm.state.Block(vm.baseDB, id)
 	if err != nil {
 		return nil, err
@@ -1,6 +1,7 @@
 package spchainvm
 
 import (
+	"context"
 	"testing"
 
 	"github.com/ava-labs/gecko/database/memdb"
@@ -85,7 +86,7 @@ func BenchmarkParseBlock(b *testing.B) {
 			vm.state.block.Flush()
 
 			b.StartTimer()
-			if _, err := vm.ParseBlock(blockBytes); err != nil {
+			if _, err := vm.ParseBlock(context.Background(), blockBytes); err != nil {
 				b.Fatal(err)
 			}
 			b.StopTimer()
@@ -116,11 +117,11 @@ func BenchmarkParseAndVerify(b *testing.B) {
 
 		b.StartTimer()
 		for _, blockBytes := range blocks {
-			blk, err := vm.ParseBlock(blockBytes)
+			blk, err := vm.ParseBlock(context.Background(), blockBytes)
 			if err != nil {
 				b.Fatal(err)
 			}
-			if err := blk.Verify(); err != nil {
+			if err := blk.Verify(context.Background()); err != nil {
 				b.Fatal(err)
 			}
 		}
@@ -150,16 +151,16 @@ func BenchmarkAccept(b *testing.B) {
 		)
 
 		for _, blockBytes := range blocks {
-			blk, err := vm.ParseBlock(blockBytes)
+			blk, err := vm.ParseBlock(context.Background(), blockBytes)
 			if err != nil {
 				b.Fatal(err)
 			}
-			if err := blk.Verify(); err != nil {
+			if err := blk.Verify(context.Background()); err != nil {
 				b.Fatal(err)
 			}
 
 			b.StartTimer()
-			blk.Accept()
+			blk.Accept(context.Background())
 			b.StopTimer()
 		}
 	}
@@ -188,14 +189,14 @@ func ParseAndVerifyAndAccept(numBlocks, numTxsPerBlock int, b *testing.B) {
 
 		b.StartTimer()
 		for _, blockBytes := range blocks {
-			blk, err := vm.ParseBlock(blockBytes)
+			blk, err := vm.ParseBlock(context.Background(), blockBytes)
 			if err != nil {
 				b.Fatal(err)
 			}
-			if err := blk.Verify(); err != nil {
+			if err := blk.Verify(context.Background()); err != nil {
 				b.Fatal(err)
 			}
-			blk.Accept()
+			blk.Accept(context.Background())
 		}
 		b.StopTimer()
 	}
@@ -243,19 +244,19 @@ func ParseThenVerifyThenAccept(numBlocks, numTxsPerBlock int, b *testing.B) {
 		b.StartTimer()
 		parsedBlocks := make([]snowman.Block, len(blocks))
 		for i, blockBytes := range blocks {
-			blk, err := vm.ParseBlock(blockBytes)
+			blk, err := vm.ParseBlock(context.Background(), blockBytes)
 			if err != nil {
 				b.Fatal(err)
 			}
 			parsedBlocks[i] = blk
 		}
 		for _, blk := range parsedBlocks {
-			if err := blk.Verify(); err != nil {
+			if err := blk.Verify(context.Background()); err != nil {
 				b.Fatal(err)
 			}
 		}
 		for _, blk := range parsedBlocks {
-			blk.Accept()
+			blk.Accept(context.Background())
 		}
 		b.StopTimer()
 	}
@@ -309,15 +310,15 @@ func IssueAndVerifyAndAccept(numBlocks, numTxsPerBlock int, b *testing.B) {
 				}
 			}
 
-			blk, err := vm.BuildBlock()
+			blk, err := vm.BuildBlock(context.Background())
 			if err != nil {
 				b.Fatal(err)
 			}
-			if err := blk.Verify(); err != nil {
+			if err := blk.Verify(context.Background()); err != nil {
 				b.Fatal(err)
 			}
 			vm.SetPreference(blk.ID())
-			blk.Accept()
+			blk.Accept(context.Background())
 		}
 		b.StopTimer()
 	}
@@ -4,6 +4,7 @@
 package spdagvm
 
 import (
+	"context"
 	"errors"
 
 	"github.com/ava-labs/gecko/ids"
@@ -80,7 +81,7 @@ func (tx *UniqueTx) addEvents(finalized func(choices.Status)) {
 func (tx *UniqueTx) ID() ids.ID { return tx.txID }
 
 // Accept is called when the transaction was finalized as accepted by consensus
-func (tx *UniqueTx) Accept() {
+func (tx *UniqueTx) Accept(context.Context) {
 	if err := tx.setStatus(choices.Accepted); err != nil {
 		tx.vm.ctx.Log.Error("Failed to accept tx %s due to %s", tx.txID, err)
 		return
@@ -187,8 +188,16 @@ func (tx *UniqueTx) Bytes() []byte {
 	return tx.t.tx.Bytes()
 }
 
-// Verify the validity of this transaction
-func (tx *UniqueTx) Verify() error {
+// Verify the validity of this transaction. Verification is abandoned once
+// [ctx] is done.
+func (tx *UniqueTx) Verify(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return tx.verify()
+}
+
+func (tx *UniqueTx) verify() error {
 	switch status := tx.Status(); status {
 	case choices.Unknown:
 		return errUnknownTx
@@ -242,7 +251,7 @@ func (tx *UniqueTx) VerifyState() error {
 		}
 
 		// TODO: Replace with a switch?
-		if err := parent.Verify(); err != nil {
+		if err := parent.verify(); err != nil {
 			tx.t.validity = errMissingUTXO
 		} else if status := parent.Status(); status.Decided() {
 			tx.t.validity = errMissingUTXO
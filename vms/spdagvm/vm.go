@@ -4,6 +4,7 @@
 package spdagvm
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"strconv"
@@ -174,10 +175,10 @@ func (vm *VM) PendingTxs() []snowstorm.Tx {
 }
 
 // ParseTx parses bytes to a *UniqueTx
-func (vm *VM) ParseTx(b []byte) (snowstorm.Tx, error) { return vm.parseTx(b, nil) }
+func (vm *VM) ParseTx(_ context.Context, b []byte) (snowstorm.Tx, error) { return vm.parseTx(b, nil) }
 
 // GetTx returns the transaction whose ID is [txID]
-func (vm *VM) GetTx(txID ids.ID) (snowstorm.Tx, error) {
+func (vm *VM) GetTx(_ context.Context, txID ids.ID) (snowstorm.Tx, error) {
 	rawTx, err := vm.state.Tx(txID)
 	if err != nil {
 		return nil, err
@@ -245,8 +246,9 @@ func (vm *VM) GetBalance(address, assetID string) (uint64, error) {
 		return 0, err
 	}
 
-	addrSet := ids.ShortSet{addr.Key(): true} // Note this set contains only [addr]
-	utxos, err := vm.GetUTXOs(addrSet)        // The UTXOs that reference [addr]
+	addrSet := ids.ShortSet{}
+	addrSet.Add(addr)                  // Note this set contains only [addr]
+	utxos, err := vm.GetUTXOs(addrSet) // The UTXOs that reference [addr]
 	if err != nil {
 		return 0, err
 	}
@@ -382,7 +384,8 @@ func (vm *VM) GetTxHistory(address string) (string, []string, map[string]string,
 	if err != nil {
 		return "", nil, nil, nil, err
 	}
-	addrSet := ids.ShortSet{addr.Key(): true}
+	addrSet := ids.ShortSet{}
+	addrSet.Add(addr)
 	utxos, err := vm.GetUTXOs(addrSet)
 	if err != nil {
 		return "", nil, nil, nil, err
@@ -469,7 +472,7 @@ func (vm *VM) IssueTx(b []byte, finalized func(choices.Status)) (ids.ID, error)
 	if err != nil {
 		return ids.ID{}, err
 	}
-	if err := tx.Verify(); err != nil {
+	if err := tx.verify(); err != nil {
 		return ids.ID{}, err
 	}
 	vm.issueTx(tx)
@@ -4,6 +4,7 @@
 package spdagvm
 
 import (
+	"context"
 	"math"
 	"testing"
 
@@ -234,18 +235,18 @@ func TestInvalidSpentTx(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	if err := wrappedTx1.Verify(); err != nil {
+	if err := wrappedTx1.Verify(context.Background()); err != nil {
 		t.Fatal(err)
 	}
 
-	wrappedTx1.Accept()
+	wrappedTx1.Accept(context.Background())
 
 	wrappedTx2, err := vm.wrapTx(tx2, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
 
-	if err := wrappedTx2.Verify(); err == nil {
+	if err := wrappedTx2.Verify(context.Background()); err == nil {
 		t.Fatalf("Should have failed verification")
 	}
 	ctx.Lock.Unlock()
@@ -297,7 +298,7 @@ func TestInvalidTxVerification(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	if err := wrappedTx.Verify(); err == nil {
+	if err := wrappedTx.Verify(context.Background()); err == nil {
 		t.Fatalf("Should have failed verification")
 	}
 
@@ -308,7 +309,7 @@ func TestInvalidTxVerification(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	if err := wrappedTx2.Verify(); err == nil {
+	if err := wrappedTx2.Verify(context.Background()); err == nil {
 		t.Fatalf("Should have failed verification")
 	}
 	ctx.Lock.Unlock()
@@ -406,7 +407,7 @@ func TestRPCAPI(t *testing.T) {
 	if txs := vm.PendingTxs(); len(txs) != 1 {
 		t.Fatalf("PendingTxs(): returned wrong number of transactions - expected: %d ; returned: %d", 1, len(txs))
 	} else {
-		txs[0].Accept()
+		txs[0].Accept(context.Background())
 	}
 	if txs := vm.PendingTxs(); len(txs) != 0 {
 		t.Fatalf("PendingTxs(): there should not have been any pending transactions")
@@ -434,7 +435,7 @@ func TestRPCAPI(t *testing.T) {
 	if txs := vm.PendingTxs(); len(txs) != 1 {
 		t.Fatalf("PendingTxs: returned wrong number of transactions - expected: %d ; returned: %d", 1, len(txs))
 	} else {
-		txs[0].Accept()
+		txs[0].Accept(context.Background())
 	}
 	if txs := vm.PendingTxs(); len(txs) != 0 {
 		t.Fatalf("PendingTxs(): there should not have been any pending transactions")
@@ -468,7 +469,7 @@ func TestRPCAPI(t *testing.T) {
 	if txs := vm.PendingTxs(); len(txs) != 1 {
 		t.Fatalf("PendingTxs(): returned wrong number of transactions - expected: %d; returned: %d", 1, len(txs))
 	} else {
-		txs[0].Accept()
+		txs[0].Accept(context.Background())
 	}
 	if txs := vm.PendingTxs(); len(txs) != 0 {
 		t.Fatalf("PendingTxs(): there should not have been any pending transactions")
@@ -495,7 +496,7 @@ func TestRPCAPI(t *testing.T) {
 	if txs := vm.PendingTxs(); len(txs) != 1 {
 		t.Fatalf("<-txChan: returned wrong number of transactions - expected: %d ; returned: %d", 1, len(txs))
 	} else {
-		txs[0].Accept()
+		txs[0].Accept(context.Background())
 	}
 	if txs := vm.PendingTxs(); len(txs) != 0 {
 		t.Fatalf("PendingTxs(): there should not have been any pending transactions")
@@ -715,10 +716,10 @@ func TestIssuePendingDependency(t *testing.T) {
 		avlTx2 = txs[0]
 	}
 
-	if err := avlTx1.Verify(); err != nil {
+	if err := avlTx1.Verify(context.Background()); err != nil {
 		t.Fatal(err)
 	}
-	if err := avlTx2.Verify(); err != nil {
+	if err := avlTx2.Verify(context.Background()); err != nil {
 		t.Fatal(err)
 	}
 
@@ -776,7 +777,7 @@ func TestTxOutputOverflow(t *testing.T) {
 	if txs := vm.PendingTxs(); len(txs) != 1 {
 		t.Fatalf("PendingTxs(): returned wrong number of transactions - expected: %d; returned: %d", 1, len(txs))
 	} else {
-		txs[0].Accept()
+		txs[0].Accept(context.Background())
 	}
 	if txs := vm.PendingTxs(); len(txs) != 0 {
 		t.Fatalf("PendingTxs(): there should not have been any pending transactions")
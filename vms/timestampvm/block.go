@@ -4,6 +4,7 @@
 package timestampvm
 
 import (
+	"context"
 	"errors"
 	"time"
 
@@ -29,7 +30,7 @@ type Block struct {
 // Verify returns nil iff this block is valid.
 // To be valid, it must be that:
 // b.parent.Timestamp < b.Timestamp <= [local time] + 1 hour
-func (b *Block) Verify() error {
+func (b *Block) Verify(context.Context) error {
 	if accepted, err := b.Block.Verify(); err != nil || accepted {
 		return err
 	}
@@ -4,6 +4,7 @@
 package timestampvm
 
 import (
+	"context"
 	"errors"
 	"net/http"
 
@@ -82,7 +83,7 @@ func (s *Service) GetBlock(_ *http.Request, args *GetBlockArgs, reply *GetBlockR
 		}
 	}
 
-	blockInterface, err := s.vm.GetBlock(ID)
+	blockInterface, err := s.vm.GetBlock(context.Background(), ID)
 	if err != nil {
 		return errDatabase
 	}
@@ -4,6 +4,7 @@
 package timestampvm
 
 import (
+	"context"
 	"errors"
 	"time"
 
@@ -46,7 +47,7 @@ func (vm *VM) Initialize(
 	toEngine chan<- common.Message,
 	_ []*common.Fx,
 ) error {
-	if err := vm.SnowmanVM.Initialize(ctx, db, vm.ParseBlock, toEngine); err != nil {
+	if err := vm.SnowmanVM.Initialize(ctx, db, vm.parseBlock, toEngine); err != nil {
 		ctx.Log.Error("error initializing SnowmanVM: %v", err)
 		return err
 	}
@@ -78,7 +79,7 @@ func (vm *VM) Initialize(
 
 		// Accept the genesis block
 		// Sets [vm.lastAccepted] and [vm.preferred]
-		genesisBlock.Accept()
+		genesisBlock.Accept(context.Background())
 
 		vm.SetDBInitialized()
 
@@ -108,7 +109,7 @@ func (vm *VM) CreateHandlers() map[string]*common.HTTPHandler {
 func (vm *VM) CreateStaticHandlers() map[string]*common.HTTPHandler { return nil }
 
 // BuildBlock returns a block that this vm wants to add to consensus
-func (vm *VM) BuildBlock() (snowman.Block, error) {
+func (vm *VM) BuildBlock(context.Context) (snowman.Block, error) {
 	if len(vm.mempool) == 0 { // There is no block to be built
 		return nil, errNoPendingBlocks
 	}
@@ -141,12 +142,19 @@ func (vm *VM) proposeBlock(data [dataLen]byte) {
 }
 
 // ParseBlock parses [bytes] to a snowman.Block
+func (vm *VM) ParseBlock(_ context.Context, bytes []byte) (snowman.Block, error) {
+	return vm.parseBlock(bytes)
+}
+
+// parseBlock parses [bytes] to a snowman.Block
 // This function is used by the vm's state to unmarshal blocks saved in state
-func (vm *VM) ParseBlock(bytes []byte) (snowman.Block, error) {
+func (vm *VM) parseBlock(bytes []byte) (snowman.Block, error) {
 	block := &Block{}
-	err := vm.codec.Unmarshal(bytes, block)
+	if err := vm.codec.Unmarshal(bytes, block); err != nil {
+		return nil, err
+	}
 	block.Initialize(bytes, &vm.SnowmanVM)
-	return block, err
+	return block, nil
 }
 
 // NewBlock returns a new Block where:
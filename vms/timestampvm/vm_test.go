@@ -4,6 +4,7 @@
 package timestampvm
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
@@ -11,6 +12,8 @@ import (
 	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/snow"
 	"github.com/ava-labs/gecko/snow/engine/common"
+	"github.com/ava-labs/gecko/snow/engine/snowman"
+	"github.com/ava-labs/gecko/snow/engine/snowman/conformance"
 	"github.com/ava-labs/gecko/utils/formatting"
 )
 
@@ -27,10 +30,10 @@ func assertBlock(block *Block, parentID ids.ID, expectedData [dataLen]byte, pass
 	if block.Data != expectedData {
 		return fmt.Errorf("expected data to be %v but was %v", expectedData, block.Data)
 	}
-	if block.Verify() != nil && passesVerify {
+	if block.Verify(context.Background()) != nil && passesVerify {
 		return fmt.Errorf("expected block to pass verification but it fails")
 	}
-	if block.Verify() == nil && !passesVerify {
+	if block.Verify(context.Background()) == nil && !passesVerify {
 		return fmt.Errorf("expected block to fail verification but it passes")
 	}
 	return nil
@@ -59,7 +62,7 @@ func TestGenesis(t *testing.T) {
 
 	// Verify that getBlock returns the genesis block, and the genesis block
 	// is the type we expect
-	genesisSnowmanBlock, err := vm.GetBlock(lastAccepted) // genesisBlock as snowman.Block
+	genesisSnowmanBlock, err := vm.GetBlock(context.Background(), lastAccepted) // genesisBlock as snowman.Block
 	if err != nil {
 		t.Fatalf("couldn't get genesisBlock: %s", err)
 	}
@@ -74,6 +77,17 @@ func TestGenesis(t *testing.T) {
 	}
 }
 
+func TestConformance(t *testing.T) {
+	conformance.Run(t, conformance.Harness{
+		NewVM:   func() snowman.ChainVM { return &VM{} },
+		Genesis: []byte{0, 0, 0, 0, 0},
+		PrepareBlock: func(vm snowman.ChainVM) error {
+			vm.(*VM).proposeBlock([dataLen]byte{0, 0, 0, 0, 1})
+			return nil
+		},
+	})
+}
+
 func TestHappyPath(t *testing.T) {
 	// Initialize the vm
 	db := memdb.New()
@@ -85,7 +99,7 @@ func TestHappyPath(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	genesisBlock, err := vm.GetBlock(vm.LastAccepted())
+	genesisBlock, err := vm.GetBlock(context.Background(), vm.LastAccepted())
 	if err != nil {
 		t.Fatal("could not get genesis block")
 	}
@@ -107,18 +121,18 @@ func TestHappyPath(t *testing.T) {
 
 	// build the block
 	ctx.Lock.Lock()
-	snowmanBlock2, err := vm.BuildBlock()
+	snowmanBlock2, err := vm.BuildBlock(context.Background())
 	if err != nil {
 		t.Fatalf("problem building block: %s", err)
 	}
-	if err := snowmanBlock2.Verify(); err != nil {
+	if err := snowmanBlock2.Verify(context.Background()); err != nil {
 		t.Fatal(err)
 	}
-	snowmanBlock2.Accept() // accept the block
+	snowmanBlock2.Accept(context.Background()) // accept the block
 	vm.SetPreference(snowmanBlock2.ID())
 
 	// Should be the block we just accepted
-	snowmanBlock2, err = vm.GetBlock(vm.LastAccepted())
+	snowmanBlock2, err = vm.GetBlock(context.Background(), vm.LastAccepted())
 	if err != nil {
 		t.Fatal("couldn't get block")
 	}
@@ -146,18 +160,18 @@ func TestHappyPath(t *testing.T) {
 	ctx.Lock.Lock()
 
 	// build the block
-	if block, err := vm.BuildBlock(); err != nil {
+	if block, err := vm.BuildBlock(context.Background()); err != nil {
 		t.Fatalf("problem building block: %s", err)
 	} else {
-		if err := block.Verify(); err != nil {
+		if err := block.Verify(context.Background()); err != nil {
 			t.Fatal(err)
 		}
-		block.Accept() // accept the block
+		block.Accept(context.Background()) // accept the block
 		vm.SetPreference(block.ID())
 	}
 
 	// The block we just accepted
-	snowmanBlock3, err := vm.GetBlock(vm.LastAccepted())
+	snowmanBlock3, err := vm.GetBlock(context.Background(), vm.LastAccepted())
 	if err != nil {
 		t.Fatal("couldn't get block")
 	}
@@ -171,12 +185,12 @@ func TestHappyPath(t *testing.T) {
 	}
 
 	// Next, check the blocks we added are there
-	if block2FromState, err := vm.GetBlock(block2.ID()); err != nil {
+	if block2FromState, err := vm.GetBlock(context.Background(), block2.ID()); err != nil {
 		t.Fatal(err)
 	} else if !block2FromState.ID().Equals(block2.ID()) {
 		t.Fatal("expected IDs to match but they don't")
 	}
-	if block3FromState, err := vm.GetBlock(block3.ID()); err != nil {
+	if block3FromState, err := vm.GetBlock(context.Background(), block3.ID()); err != nil {
 		t.Fatal(err)
 	} else if !block3FromState.ID().Equals(block3.ID()) {
 		t.Fatal("expected IDs to match but they don't")
@@ -22,7 +22,7 @@ import (
 // benchmark an instance of the avm
 func (n *network) benchmarkAVM(chain *platformvm.CreateChainTx) {
 	genesisBytes := chain.GenesisData
-	wallet, err := avmwallet.NewWallet(n.log, n.networkID, chain.ID(), config.AvaTxFee)
+	wallet, err := avmwallet.NewWallet(n.log, n.networkID, chain.ChainID(), config.AvaTxFee)
 	n.log.AssertNoError(err)
 
 	cb58 := formatting.CB58{}
@@ -54,7 +54,7 @@ func (n *network) benchmarkAVM(chain *platformvm.CreateChainTx) {
 
 	n.log.AssertNoError(wallet.GenerateTxs(config.NumTxs, assetID))
 
-	go n.log.RecoverAndPanic(func() { n.IssueAVM(chain.ID(), assetID, wallet) })
+	go n.log.RecoverAndPanic(func() { n.IssueAVM(chain.ChainID(), assetID, wallet) })
 }
 
 // issue transactions to the instance of the avm funded by the provided wallet
@@ -22,7 +22,7 @@ import (
 // benchmark an instance of the sp chain
 func (n *network) benchmarkSPChain(chain *platformvm.CreateChainTx) {
 	genesisBytes := chain.GenesisData
-	wallet := chainwallet.NewWallet(n.log, n.networkID, chain.ID())
+	wallet := chainwallet.NewWallet(n.log, n.networkID, chain.ChainID())
 
 	codec := spchainvm.Codec{}
 	accounts, err := codec.UnmarshalGenesis(genesisBytes)
@@ -45,7 +45,7 @@ func (n *network) benchmarkSPChain(chain *platformvm.CreateChainTx) {
 
 	n.log.AssertNoError(wallet.GenerateTxs(config.NumTxs))
 
-	go n.log.RecoverAndPanic(func() { n.IssueSPChain(chain.ID(), wallet) })
+	go n.log.RecoverAndPanic(func() { n.IssueSPChain(chain.ChainID(), wallet) })
 }
 
 func (n *network) IssueSPChain(chainID ids.ID, wallet *chainwallet.Wallet) {
@@ -22,7 +22,7 @@ import (
 // benchmark an instance of the sp dag
 func (n *network) benchmarkSPDAG(chain *platformvm.CreateChainTx) {
 	genesisBytes := chain.GenesisData
-	wallet := dagwallet.NewWallet(n.networkID, chain.ID(), config.AvaTxFee)
+	wallet := dagwallet.NewWallet(n.networkID, chain.ChainID(), config.AvaTxFee)
 
 	codec := spdagvm.Codec{}
 	tx, err := codec.UnmarshalTx(genesisBytes)
@@ -41,7 +41,7 @@ func (n *network) benchmarkSPDAG(chain *platformvm.CreateChainTx) {
 		wallet.AddUTXO(utxo)
 	}
 
-	go n.log.RecoverAndPanic(func() { n.IssueSPDAG(chain.ID(), wallet) })
+	go n.log.RecoverAndPanic(func() { n.IssueSPDAG(chain.ChainID(), wallet) })
 }
 
 // issue transactions to the instance of the spdag funded by the provided wallet